@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/url"
 	"strings"
 
@@ -49,6 +50,11 @@ var (
 		"address: normal asset amount of zero",
 	)
 
+	// ErrAmountTooLarge is an error returned when we attempt to create or
+	// decode a Taproot Asset address with an amount above the configured
+	// maximum.
+	ErrAmountTooLarge = errors.New("address: amount exceeds maximum")
+
 	// ErrUnsupportedAssetType is an error returned when we attempt to
 	// create a Taproot Asset address for a non-standard asset type.
 	ErrUnsupportedAssetType = errors.New("address: unsupported asset type")
@@ -60,11 +66,39 @@ var (
 	// the local database.
 	ErrScriptKeyNotFound = errors.New("script key not found")
 
+	// ErrInternalKeyNotFound is returned when an internal key is not found
+	// in the local database.
+	ErrInternalKeyNotFound = errors.New("internal key not found")
+
 	// ErrUnknownVersion is returned when encountering an address with an
 	// unrecognised version number.
 	ErrUnknownVersion = errors.New("address: unknown version number")
+
+	// ErrFixedAmount is returned when we attempt to send an amount other
+	// than the requested one to an address that doesn't allow any amount.
+	ErrFixedAmount = errors.New("address: amount is fixed")
+
+	// ErrAmountBelowMinimum is returned when we attempt to send less than
+	// the minimum amount to an address that allows any amount.
+	ErrAmountBelowMinimum = errors.New("address: amount below minimum")
 )
 
+// DefaultMaxAmount is the default maximum amount a Taproot Asset address can
+// request. Larger amounts can't be represented in the virtual transactions
+// used to send to an address.
+const DefaultMaxAmount uint64 = math.MaxInt64
+
+// CheckAmount returns ErrAmountTooLarge if the given address amount exceeds
+// the given maximum.
+func CheckAmount(amt, maxAmount uint64) error {
+	if amt > maxAmount {
+		return fmt.Errorf("%w: %d > %d", ErrAmountTooLarge, amt,
+			maxAmount)
+	}
+
+	return nil
+}
+
 // Version denotes the version of a Taproot Asset address format.
 type Version uint8
 
@@ -110,8 +144,17 @@ type Tap struct {
 	TapscriptSibling *commitment.TapscriptPreimage
 
 	// Amount is the number of asset units being requested by the receiver.
+	// If AllowAnyAmount is set, this is only the suggested amount.
 	Amount uint64
 
+	// AllowAnyAmount indicates that the receiver accepts a transfer of any
+	// amount of at least MinAmount asset units, chosen by the sender.
+	AllowAnyAmount bool
+
+	// MinAmount is the minimum number of asset units the receiver accepts
+	// if AllowAnyAmount is set.
+	MinAmount uint64
+
 	// assetGen is the receiving asset's genesis metadata which directly
 	// maps to its unique ID within the Taproot Asset protocol.
 	assetGen asset.Genesis
@@ -125,6 +168,10 @@ type Tap struct {
 // created.
 type newAddrOptions struct {
 	assetVersion asset.Version
+
+	allowAnyAmount bool
+
+	minAmount uint64
 }
 
 // defaultNewAddrOptions returns a newAddrOptions struct with default values.`
@@ -146,6 +193,17 @@ func WithAssetVersion(v asset.Version) NewAddrOpt {
 	}
 }
 
+// WithAnyAmount is a new address option that allows the sender to choose the
+// amount sent to the address, as long as it is at least the given minimum
+// amount. The amount of the address is then only the suggested amount. If it
+// is zero, the minimum amount (or a single unit) is suggested.
+func WithAnyAmount(minAmount uint64) NewAddrOpt {
+	return func(o *newAddrOptions) {
+		o.allowAnyAmount = true
+		o.minAmount = minAmount
+	}
+}
+
 // New creates an address for receiving a Taproot asset.
 //
 // TODO(ffranr): This function takes many arguments. Add a struct to better
@@ -162,6 +220,24 @@ func New(version Version, genesis asset.Genesis, groupKey *btcec.PublicKey,
 		opt(options)
 	}
 
+	// An address that allows any amount still commits to a suggested
+	// amount, which must satisfy the minimum amount.
+	if options.allowAnyAmount {
+		switch {
+		case genesis.Type == asset.Collectible:
+			return nil, fmt.Errorf("address: collectible asset " +
+				"can't be received with any amount")
+
+		case amt == 0:
+			amt = max(options.minAmount, 1)
+
+		case amt < options.minAmount:
+			return nil, fmt.Errorf("%w: suggested amount %d is "+
+				"below minimum amount %d",
+				ErrAmountBelowMinimum, amt, options.minAmount)
+		}
+	}
+
 	// Check for invalid combinations of asset type and amount.
 	// Collectible assets must have an amount of 1, and Normal assets must
 	// have a non-zero amount. We also reject invalid asset types.
@@ -212,6 +288,8 @@ func New(version Version, genesis asset.Genesis, groupKey *btcec.PublicKey,
 		InternalKey:      internalKey,
 		TapscriptSibling: tapscriptSibling,
 		Amount:           amt,
+		AllowAnyAmount:   options.allowAnyAmount,
+		MinAmount:        options.minAmount,
 		assetGen:         genesis,
 		ProofCourierAddr: proofCourierAddr,
 	}
@@ -230,6 +308,39 @@ func (a *Tap) Copy() *Tap {
 	return &addressCopy
 }
 
+// AcceptsAmount returns nil if the given amount can be sent to the address.
+// An address that doesn't allow any amount only accepts its own amount.
+func (a *Tap) AcceptsAmount(amt uint64) error {
+	switch {
+	case !a.AllowAnyAmount && amt != a.Amount:
+		return fmt.Errorf("%w: address requests %d, not %d",
+			ErrFixedAmount, a.Amount, amt)
+
+	case a.AllowAnyAmount && (amt == 0 || amt < a.MinAmount):
+		return fmt.Errorf("%w: %d < %d", ErrAmountBelowMinimum, amt,
+			max(a.MinAmount, 1))
+	}
+
+	return nil
+}
+
+// ForAmount returns a copy of the address that requests exactly the given
+// amount. The sender of a transfer to an address that allows any amount
+// commits to the amount it chose this way, and the receiver recomputes the
+// output it received from the amount in the proof.
+func (a *Tap) ForAmount(amt uint64) (*Tap, error) {
+	if err := a.AcceptsAmount(amt); err != nil {
+		return nil, err
+	}
+
+	addressCopy := a.Copy()
+	addressCopy.Amount = amt
+	addressCopy.AllowAnyAmount = false
+	addressCopy.MinAmount = 0
+
+	return addressCopy, nil
+}
+
 // Net returns the ChainParams struct matching the Taproot Asset address
 // network.
 func (a *Tap) Net() (*ChainParams, error) {
@@ -340,11 +451,16 @@ func (a *Tap) EncodeRecords() []tlv.Record {
 		))
 	}
 	records = append(records, newAddressAmountRecord(&a.Amount))
-
 	records = append(
 		records, newProofCourierAddrRecord(&a.ProofCourierAddr),
 	)
 
+	if a.AllowAnyAmount {
+		records = append(records, newAddressMinAmountRecord(
+			&a.MinAmount,
+		))
+	}
+
 	return records
 }
 
@@ -361,6 +477,7 @@ func (a *Tap) DecodeRecords() []tlv.Record {
 		newAddressTapscriptSiblingRecord(&a.TapscriptSibling),
 		newAddressAmountRecord(&a.Amount),
 		newProofCourierAddrRecord(&a.ProofCourierAddr),
+		newAddressMinAmountRecord(&a.MinAmount),
 	}
 }
 
@@ -379,7 +496,17 @@ func (a *Tap) Decode(r io.Reader) error {
 	if err != nil {
 		return err
 	}
-	return stream.DecodeP2P(r)
+
+	parsedTypes, err := stream.DecodeWithParsedTypesP2P(r)
+	if err != nil {
+		return err
+	}
+
+	// The minimum amount record is only present for addresses that allow
+	// any amount.
+	_, a.AllowAnyAmount = parsedTypes[addrMinAmountType]
+
+	return nil
 }
 
 // EncodeAddress returns a bech32m string encoding of a Taproot Asset address.
@@ -482,3 +609,15 @@ func DecodeAddress(addr string, net *ChainParams) (*Tap, error) {
 
 	return &a, nil
 }
+
+// DecodeNet returns the chain params of the network the given encoded Taproot
+// Asset address was created for, based on its human-readable part. The rest of
+// the address isn't validated.
+func DecodeNet(addr string) (*ChainParams, error) {
+	oneIndex := strings.LastIndexByte(addr, '1')
+	if oneIndex <= 0 {
+		return nil, ErrInvalidBech32m
+	}
+
+	return Net(strings.ToLower(addr[:oneIndex]))
+}
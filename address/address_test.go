@@ -3,6 +3,7 @@ package address
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
 	"testing"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -236,6 +237,36 @@ func TestNewAddress(t *testing.T) {
 	}
 }
 
+// TestCheckAmount tests that address amounts above the maximum are rejected,
+// even if the address itself encodes and decodes cleanly.
+func TestCheckAmount(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, CheckAmount(DefaultMaxAmount, DefaultMaxAmount))
+	require.ErrorIs(
+		t, CheckAmount(DefaultMaxAmount+1, DefaultMaxAmount),
+		ErrAmountTooLarge,
+	)
+	require.NoError(t, CheckAmount(1000, 1000))
+	require.ErrorIs(t, CheckAmount(1001, 1000), ErrAmountTooLarge)
+
+	maxAmt := uint64(math.MaxUint64)
+	addr, err := randAddress(
+		t, &TestNet3Tap, V0, false, false, &maxAmt, asset.Normal,
+	)
+	require.NoError(t, err)
+	encoded, err := addr.EncodeAddress()
+	require.NoError(t, err)
+
+	decoded, err := DecodeAddress(encoded, &TestNet3Tap)
+	require.NoError(t, err)
+	assertAddressEqual(t, addr, decoded)
+	require.ErrorIs(
+		t, CheckAmount(decoded.Amount, DefaultMaxAmount),
+		ErrAmountTooLarge,
+	)
+}
+
 func TestAddressEncoding(t *testing.T) {
 	t.Parallel()
 
@@ -445,6 +476,134 @@ func TestAddressEncoding(t *testing.T) {
 	test.WriteTestVectors(t, generatedTestVectorName, testVectors)
 }
 
+// TestDecodeNet tests that the network of an encoded address is determined
+// from its human-readable part.
+func TestDecodeNet(t *testing.T) {
+	t.Parallel()
+
+	for _, net := range []*ChainParams{&MainNetTap, &TestNet3Tap} {
+		_, encodedAddr, err := randEncodedAddress(
+			t, net, true, false, asset.Normal,
+		)
+		require.NoError(t, err)
+
+		addrNet, err := DecodeNet(encodedAddr)
+		require.NoError(t, err)
+		require.Equal(t, net.TapHRP, addrNet.TapHRP)
+	}
+
+	_, err := DecodeNet("invalid")
+	require.ErrorIs(t, err, ErrInvalidBech32m)
+
+	_, err = DecodeNet("bc1qunknown")
+	require.ErrorIs(t, err, ErrUnsupportedHRP)
+}
+
+// TestAnyAmountAddress tests that addresses that allow any amount encode their
+// minimum amount and only accept amounts that satisfy it.
+func TestAnyAmountAddress(t *testing.T) {
+	t.Parallel()
+
+	zero := uint64(0)
+	addr, err := randAddress(
+		t, &TestNet3Tap, V0, false, false, &zero, asset.Normal,
+		WithAnyAmount(100),
+	)
+	require.NoError(t, err)
+	require.True(t, addr.AllowAnyAmount)
+	require.EqualValues(t, 100, addr.MinAmount)
+
+	// Without a suggested amount, the minimum amount is suggested.
+	require.EqualValues(t, 100, addr.Amount)
+
+	encoded, err := addr.EncodeAddress()
+	require.NoError(t, err)
+	decoded, err := DecodeAddress(encoded, &TestNet3Tap)
+	require.NoError(t, err)
+	assertAddressEqual(t, addr, decoded)
+	require.True(t, decoded.AllowAnyAmount)
+	require.EqualValues(t, 100, decoded.MinAmount)
+
+	require.NoError(t, decoded.AcceptsAmount(100))
+	require.NoError(t, decoded.AcceptsAmount(5000))
+	require.ErrorIs(t, decoded.AcceptsAmount(99), ErrAmountBelowMinimum)
+
+	fixed, err := decoded.ForAmount(5000)
+	require.NoError(t, err)
+	require.EqualValues(t, 5000, fixed.Amount)
+	require.False(t, fixed.AllowAnyAmount)
+	require.ErrorIs(t, fixed.AcceptsAmount(4999), ErrFixedAmount)
+
+	// A fixed amount address doesn't carry the minimum amount record.
+	encoded, err = fixed.EncodeAddress()
+	require.NoError(t, err)
+	decoded, err = DecodeAddress(encoded, &TestNet3Tap)
+	require.NoError(t, err)
+	require.False(t, decoded.AllowAnyAmount)
+	require.NoError(t, decoded.AcceptsAmount(5000))
+
+	// The suggested amount must satisfy the minimum amount, and
+	// collectibles can't be received with any amount.
+	low := uint64(10)
+	_, err = randAddress(
+		t, &TestNet3Tap, V0, false, false, &low, asset.Normal,
+		WithAnyAmount(100),
+	)
+	require.ErrorIs(t, err, ErrAmountBelowMinimum)
+
+	_, err = randAddress(
+		t, &TestNet3Tap, V0, false, false, nil, asset.Collectible,
+		WithAnyAmount(0),
+	)
+	require.ErrorContains(t, err, "collectible")
+}
+
+// TestAddressRecordsRoundTrip tests that both an ordinary address and an
+// address that allows any amount survive a TLV encode/decode round trip.
+func TestAddressRecordsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	zero := uint64(0)
+	testCases := []struct {
+		name string
+		opts []NewAddrOpt
+		amt  *uint64
+	}{{
+		name: "ordinary address",
+	}, {
+		name: "min amount address",
+		opts: []NewAddrOpt{WithAnyAmount(100)},
+		amt:  &zero,
+	}}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			addr, err := randAddress(
+				t, &TestNet3Tap, V0, true, true, testCase.amt,
+				asset.Normal, testCase.opts...,
+			)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, addr.Encode(&buf))
+
+			var decoded Tap
+			require.NoError(t, decoded.Decode(&buf))
+
+			assertAddressEqual(t, addr, &decoded)
+			require.Equal(
+				t, addr.ProofCourierAddr.String(),
+				decoded.ProofCourierAddr.String(),
+			)
+			require.Equal(
+				t, addr.AllowAnyAmount, decoded.AllowAnyAmount,
+			)
+			require.Equal(t, addr.MinAmount, decoded.MinAmount)
+		})
+	}
+}
+
 // TestBIPTestVectors tests that the BIP test vectors are passing.
 func TestBIPTestVectors(t *testing.T) {
 	t.Parallel()
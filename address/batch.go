@@ -0,0 +1,216 @@
+package address
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// NewAddrParams holds the parameters of a new Taproot Asset address.
+type NewAddrParams struct {
+	// AssetID is the ID of the asset the address receives.
+	AssetID asset.ID
+
+	// Amount is the amount of asset units the address receives.
+	Amount uint64
+
+	// ScriptKey is the pre-derived script key of the address. If this is
+	// nil, a new script key is derived.
+	ScriptKey *asset.ScriptKey
+
+	// InternalKey is the pre-derived internal key of the address. It must
+	// be set if and only if the script key is set.
+	InternalKey *keychain.KeyDescriptor
+
+	// ScriptKeyTapscript is the tapscript tree the derived script key
+	// commits to. It can't be used together with a pre-derived script
+	// key.
+	ScriptKeyTapscript *asset.TapscriptTree
+
+	// TapscriptSibling is the optional tapscript sibling of the asset
+	// commitment of the address.
+	TapscriptSibling *commitment.TapscriptPreimage
+
+	// ProofCourierAddr is the proof courier the sender delivers the proof
+	// through.
+	ProofCourierAddr url.URL
+
+	// AddrOpts are the options the address is created with.
+	AddrOpts []NewAddrOpt
+}
+
+// Validate returns an error if the parameters don't describe a valid
+// combination of keys.
+func (p *NewAddrParams) Validate() error {
+	switch {
+	case p.ScriptKeyTapscript != nil &&
+		(p.ScriptKey != nil || p.InternalKey != nil):
+
+		return fmt.Errorf("cannot specify script key tapscript " +
+			"together with script or internal key")
+
+	case p.ScriptKey != nil && p.InternalKey == nil:
+		return fmt.Errorf("internal key must also be specified if " +
+			"script key is specified")
+
+	case p.ScriptKey == nil && p.InternalKey != nil:
+		return fmt.Errorf("script key must also be specified if " +
+			"internal key is specified")
+
+	case p.ScriptKeyTapscript != nil:
+		if err := p.ScriptKeyTapscript.Validate(); err != nil {
+			return fmt.Errorf("invalid script key tapscript: %w",
+				err)
+		}
+	}
+
+	return nil
+}
+
+// NewAddressFromParams creates a new Taproot Asset address from the given
+// parameters.
+func (b *Book) NewAddressFromParams(ctx context.Context,
+	params NewAddrParams) (*AddrWithKeyInfo, error) {
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case params.ScriptKeyTapscript != nil:
+		return b.NewAddressWithTapscript(
+			ctx, params.AssetID, params.Amount,
+			params.ScriptKeyTapscript, params.TapscriptSibling,
+			params.ProofCourierAddr, params.AddrOpts...,
+		)
+
+	case params.ScriptKey != nil:
+		return b.NewAddressWithKeys(
+			ctx, params.AssetID, params.Amount, *params.ScriptKey,
+			*params.InternalKey, params.TapscriptSibling,
+			params.ProofCourierAddr, params.AddrOpts...,
+		)
+
+	default:
+		return b.NewAddress(
+			ctx, params.AssetID, params.Amount,
+			params.TapscriptSibling, params.ProofCourierAddr,
+			params.AddrOpts...,
+		)
+	}
+}
+
+// NewAddressBatch creates a new Taproot Asset address for each of the given
+// parameters. All addresses are stored in a single database transaction, so
+// if any of them is invalid, none of them are created. The addresses are
+// returned in the order of the parameters.
+func (b *Book) NewAddressBatch(ctx context.Context,
+	params []NewAddrParams) ([]*AddrWithKeyInfo, error) {
+
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no addresses to create")
+	}
+
+	// We validate all parameters first, so we don't derive any keys for a
+	// batch that is going to be rejected anyway.
+	for idx := range params {
+		if err := params[idx].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid address %d: %w", idx,
+				err)
+		}
+	}
+
+	var (
+		addrs = make([]AddrWithKeyInfo, 0, len(params))
+		trees []*asset.TapscriptTree
+	)
+	for idx := range params {
+		p := params[idx]
+
+		addr, err := b.prepareAddress(ctx, p)
+		if err != nil {
+			return nil, fmt.Errorf("address %d: %w", idx, err)
+		}
+		addrs = append(addrs, *addr)
+
+		if p.ScriptKeyTapscript != nil {
+			trees = append(trees, p.ScriptKeyTapscript)
+		}
+	}
+
+	if err := b.cfg.Store.InsertAddrBatch(ctx, addrs, trees); err != nil {
+		return nil, fmt.Errorf("unable to insert addrs: %w", err)
+	}
+
+	result := make([]*AddrWithKeyInfo, len(addrs))
+	for idx := range addrs {
+		result[idx] = &addrs[idx]
+		b.notifySubscribers(result[idx])
+	}
+
+	return result, nil
+}
+
+// prepareAddress derives the keys of a new address and creates it from the
+// given parameters without storing it.
+func (b *Book) prepareAddress(ctx context.Context,
+	params NewAddrParams) (*AddrWithKeyInfo, error) {
+
+	// Before we proceed and make new keys, make sure that we actually know
+	// of this asset ID already.
+	_, err := b.cfg.Store.QueryAssetGroup(ctx, params.AssetID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make address for unknown "+
+			"asset %x: %w", params.AssetID[:], err)
+	}
+
+	scriptKey, internalKey, err := b.newAddrKeys(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive keys: %w", err)
+	}
+
+	return b.buildAddress(
+		ctx, params.AssetID, params.Amount, scriptKey, internalKey,
+		params.TapscriptSibling, params.ProofCourierAddr,
+		params.AddrOpts...,
+	)
+}
+
+// newAddrKeys returns the script and internal key of an address that is
+// created without being stored right away, deriving the ones that weren't
+// specified in the parameters.
+func (b *Book) newAddrKeys(ctx context.Context,
+	params NewAddrParams) (asset.ScriptKey, keychain.KeyDescriptor, error) {
+
+	if params.ScriptKey != nil {
+		return *params.ScriptKey, *params.InternalKey, nil
+	}
+
+	rawScriptKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return asset.ScriptKey{}, keychain.KeyDescriptor{},
+			fmt.Errorf("unable to gen key: %w", err)
+	}
+
+	// Without a tapscript tree, we'll map the raw key to a BIP-0086
+	// tweaked key, so the received asset can be spent with a plain key
+	// spend.
+	scriptKey := asset.NewScriptKeyBip86(rawScriptKeyDesc)
+	if params.ScriptKeyTapscript != nil {
+		scriptKey = params.ScriptKeyTapscript.ScriptKey(
+			rawScriptKeyDesc,
+		)
+	}
+
+	internalKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return asset.ScriptKey{}, keychain.KeyDescriptor{},
+			fmt.Errorf("unable to gen key: %w", err)
+	}
+
+	return scriptKey, internalKeyDesc, nil
+}
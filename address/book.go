@@ -2,6 +2,7 @@ package address
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"sync"
@@ -106,6 +107,54 @@ type Storage interface {
 	// database, so it can be recognized as belonging to the wallet when a
 	// transfer comes in later on.
 	InsertScriptKey(ctx context.Context, scriptKey asset.ScriptKey) error
+
+	// InsertSigningKeys inserts the given internal and script keys in a
+	// single database transaction, so either all or none of them are
+	// stored.
+	InsertSigningKeys(ctx context.Context,
+		internalKeys []keychain.KeyDescriptor,
+		scriptKeys []asset.ScriptKey) error
+
+	// InsertTapscriptTree inserts the leaves of a tapscript tree that a
+	// script key commits to, so an asset sent to that script key can be
+	// spent through one of them.
+	InsertTapscriptTree(ctx context.Context,
+		tree *asset.TapscriptTree) error
+
+	// FetchAllInternalKeys returns the key descriptors of all internal
+	// keys known to the database.
+	FetchAllInternalKeys(ctx context.Context) ([]keychain.KeyDescriptor,
+		error)
+
+	// FetchAllScriptKeys returns all script keys known to the database,
+	// including the key descriptors of their internal keys.
+	FetchAllScriptKeys(ctx context.Context) ([]asset.ScriptKey, error)
+
+	// InsertKeyRange stores a key range that was reserved from the wallet.
+	InsertKeyRange(ctx context.Context, keyRange KeyRange) error
+
+	// FetchKeyRanges returns all key ranges that were reserved from the
+	// wallet, ordered by key family and first key index.
+	FetchKeyRanges(ctx context.Context) ([]KeyRange, error)
+
+	// InsertIdempotentAddr inserts the given address, the tapscript tree
+	// its script key commits to, if any, and the idempotency key it was
+	// created with in a single database transaction. The hash of the
+	// creation parameters is stored together with the key.
+	InsertIdempotentAddr(ctx context.Context, addr AddrWithKeyInfo,
+		tree *asset.TapscriptTree, key string, paramsHash []byte) error
+
+	// FetchAddrIdempotencyKey returns the hash of the creation parameters
+	// and the address that was created with the given idempotency key.
+	// ErrNoAddr is returned if the key is unknown.
+	FetchAddrIdempotencyKey(ctx context.Context, key string) ([]byte,
+		*AddrWithKeyInfo, error)
+
+	// InsertAddrBatch inserts the given addresses together with the
+	// tapscript trees their script keys commit to in a single database
+	// transaction. Either all or none of the addresses are inserted.
+	InsertAddrBatch(ctx context.Context, addrs []AddrWithKeyInfo,
+		trees []*asset.TapscriptTree) error
 }
 
 // KeyRing is used to create script and internal keys for Taproot Asset
@@ -155,6 +204,10 @@ type Book struct {
 	// subscriberMtx guards the subscribers map and access to the
 	// subscriptionID.
 	subscriberMtx sync.Mutex
+
+	// idempotencyMtx serializes the creation of addresses with an
+	// idempotency key.
+	idempotencyMtx sync.Mutex
 }
 
 // A compile-time assertion to make sure Book satisfies the
@@ -205,6 +258,44 @@ func (b *Book) NewAddress(ctx context.Context, assetID asset.ID, amount uint64,
 	)
 }
 
+// NewAddressWithTapscript creates a new Taproot Asset address with a script key
+// that commits to the given tapscript tree, so the received asset can be spent
+// through one of the tree's leaves as well as with the script key's internal
+// key.
+func (b *Book) NewAddressWithTapscript(ctx context.Context, assetID asset.ID,
+	amount uint64, tree *asset.TapscriptTree,
+	tapscriptSibling *commitment.TapscriptPreimage,
+	proofCourierAddr url.URL,
+	addrOpts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
+
+	if err := tree.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid script key tapscript: %w", err)
+	}
+
+	rawScriptKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gen key: %w", err)
+	}
+	scriptKey := tree.ScriptKey(rawScriptKeyDesc)
+
+	internalKeyDesc, err := b.cfg.KeyRing.DeriveNextTaprootAssetKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to gen key: %w", err)
+	}
+
+	// We store the leaves of the tree before creating the address, so
+	// they're known by the time an asset is sent to it.
+	if err := b.cfg.Store.InsertTapscriptTree(ctx, tree); err != nil {
+		return nil, fmt.Errorf("unable to insert tapscript tree: %w",
+			err)
+	}
+
+	return b.NewAddressWithKeys(
+		ctx, assetID, amount, scriptKey, internalKeyDesc,
+		tapscriptSibling, proofCourierAddr, addrOpts...,
+	)
+}
+
 // NewAddressWithKeys creates a new Taproot Asset address based on the input
 // parameters that include pre-derived script and internal keys.
 func (b *Book) NewAddressWithKeys(ctx context.Context, assetID asset.ID,
@@ -214,6 +305,42 @@ func (b *Book) NewAddressWithKeys(ctx context.Context, assetID asset.ID,
 	proofCourierAddr url.URL,
 	addrOpts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
 
+	addr, err := b.buildAddress(
+		ctx, assetID, amount, scriptKey, internalKeyDesc,
+		tapscriptSibling, proofCourierAddr, addrOpts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// We also want to import the two keys, so we can identify them as
+	// belonging to the wallet later on.
+	err = b.cfg.Store.InsertInternalKey(ctx, internalKeyDesc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert internal key: %w", err)
+	}
+	if err := b.cfg.Store.InsertScriptKey(ctx, scriptKey); err != nil {
+		return nil, fmt.Errorf("unable to insert script key: %w", err)
+	}
+
+	if err := b.cfg.Store.InsertAddrs(ctx, *addr); err != nil {
+		return nil, fmt.Errorf("unable to insert addr: %w", err)
+	}
+
+	b.notifySubscribers(addr)
+
+	return addr, nil
+}
+
+// buildAddress creates a new Taproot Asset address from the given parameters
+// without storing it.
+func (b *Book) buildAddress(ctx context.Context, assetID asset.ID,
+	amount uint64, scriptKey asset.ScriptKey,
+	internalKeyDesc keychain.KeyDescriptor,
+	tapscriptSibling *commitment.TapscriptPreimage,
+	proofCourierAddr url.URL,
+	addrOpts ...NewAddrOpt) (*AddrWithKeyInfo, error) {
+
 	// Before we proceed, we'll make sure that the asset group is known to
 	// the local store. Otherwise, we can't make an address as we haven't
 	// bootstrapped it.
@@ -248,36 +375,23 @@ func (b *Book) NewAddressWithKeys(ctx context.Context, assetID asset.ID,
 			" %w", err)
 	}
 
-	// We also want to import the two keys, so we can identify them as
-	// belonging to the wallet later on.
-	err = b.cfg.Store.InsertInternalKey(ctx, internalKeyDesc)
-	if err != nil {
-		return nil, fmt.Errorf("unable to insert internal key: %w", err)
-	}
-	if err := b.cfg.Store.InsertScriptKey(ctx, scriptKey); err != nil {
-		return nil, fmt.Errorf("unable to insert script key: %w", err)
-	}
-
-	addr := AddrWithKeyInfo{
+	return &AddrWithKeyInfo{
 		Tap:              baseAddr,
 		ScriptKeyTweak:   *scriptKey.TweakedScriptKey,
 		InternalKeyDesc:  internalKeyDesc,
 		TaprootOutputKey: *taprootOutputKey,
 		CreationTime:     time.Now(),
-	}
-
-	if err := b.cfg.Store.InsertAddrs(ctx, addr); err != nil {
-		return nil, fmt.Errorf("unable to insert addr: %w", err)
-	}
+	}, nil
+}
 
-	// Inform our subscribers about the new address.
+// notifySubscribers informs our subscribers about the given new address.
+func (b *Book) notifySubscribers(addr *AddrWithKeyInfo) {
 	b.subscriberMtx.Lock()
+	defer b.subscriberMtx.Unlock()
+
 	for _, sub := range b.subscribers {
-		sub.NewItemCreated.ChanIn() <- &addr
+		sub.NewItemCreated.ChanIn() <- addr
 	}
-	b.subscriberMtx.Unlock()
-
-	return &addr, nil
 }
 
 // IsLocalKey returns true if the key is under the control of the wallet and can
@@ -342,6 +456,65 @@ func (b *Book) AddrByTaprootOutput(ctx context.Context,
 	return b.cfg.Store.AddrByTaprootOutput(ctx, key)
 }
 
+// AddrForAmount returns the address that receives exactly the given amount
+// through the given address that allows any amount. The on-chain output of a
+// transfer to such an address commits to the amount chosen by the sender, so
+// a fixed amount address with the same keys is stored for it, which the
+// inbound transfer is then tracked with. Subscribers are notified about the
+// new address, so its output is watched on chain.
+func (b *Book) AddrForAmount(ctx context.Context, addr *AddrWithKeyInfo,
+	amount uint64) (*AddrWithKeyInfo, error) {
+
+	if !addr.AllowAnyAmount {
+		return nil, fmt.Errorf("address doesn't allow any amount")
+	}
+
+	tapAddr, err := addr.ForAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// The address requests the suggested amount, so we can track the
+	// transfer with the address itself.
+	if amount == addr.Amount {
+		return addr, nil
+	}
+
+	taprootOutputKey, err := tapAddr.TaprootOutputKey()
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive Taproot output key: "+
+			"%w", err)
+	}
+
+	// The address might already be known if the same amount was received
+	// before or we're resuming after a restart.
+	knownAddr, err := b.cfg.Store.AddrByTaprootOutput(
+		ctx, taprootOutputKey,
+	)
+	switch {
+	case err == nil:
+		return knownAddr, nil
+
+	case !errors.Is(err, ErrNoAddr):
+		return nil, fmt.Errorf("unable to query address: %w", err)
+	}
+
+	amountAddr := &AddrWithKeyInfo{
+		Tap:              tapAddr,
+		ScriptKeyTweak:   addr.ScriptKeyTweak,
+		InternalKeyDesc:  addr.InternalKeyDesc,
+		TaprootOutputKey: *taprootOutputKey,
+		CreationTime:     time.Now(),
+	}
+	if err := b.cfg.Store.InsertAddrs(ctx, *amountAddr); err != nil {
+		return nil, fmt.Errorf("unable to insert addr: %w", err)
+	}
+
+	b.notifySubscribers(amountAddr)
+
+	return amountAddr, nil
+}
+
 // SetAddrManaged sets an address as being managed by the internal
 // wallet.
 func (b *Book) SetAddrManaged(ctx context.Context, addr *AddrWithKeyInfo,
@@ -389,6 +562,22 @@ func (b *Book) CompleteEvent(ctx context.Context, event *Event,
 	return b.cfg.Store.CompleteEvent(ctx, event, status, anchorPoint)
 }
 
+// SetEventAssetAmt records the number of asset units that were actually
+// received for an address event, as seen in the proof of the transfer.
+func (b *Book) SetEventAssetAmt(ctx context.Context, event *Event,
+	assetAmt uint64) error {
+
+	return b.cfg.Store.SetEventAssetAmt(ctx, event, assetAmt)
+}
+
+// SetEventQuarantined marks an address event as quarantined or releases it
+// from quarantine.
+func (b *Book) SetEventQuarantined(ctx context.Context, event *Event,
+	quarantined bool) error {
+
+	return b.cfg.Store.SetEventQuarantined(ctx, event, quarantined)
+}
+
 // RegisterSubscriber adds a new subscriber for receiving events. The
 // deliverExisting boolean indicates whether already existing items should be
 // sent to the NewItemCreated channel when the subscription is started. An
@@ -48,6 +48,11 @@ type EventQueryParams struct {
 	// StatusTo is the largest status to query for (inclusive). Can be
 	// set to nil to return events of all states.
 	StatusTo *Status
+
+	// ScriptKey is the optional 32-byte x-only serialized script key of
+	// the address to filter by. Must be set to nil to return events for
+	// all script keys.
+	ScriptKey []byte
 }
 
 // Event represents a single incoming asset transfer that was initiated by
@@ -93,6 +98,29 @@ type Event struct {
 	// don't keep a reference to it in memory as the proof itself can be
 	// large. The proof can be fetched by the script key of the address.
 	HasProof bool
+
+	// AssetAmt is the number of asset units that were actually received,
+	// as seen in the proof of the transfer. This can be less than the
+	// amount requested by the address. Zero means no proof was seen yet.
+	AssetAmt uint64
+
+	// Quarantined indicates that the transfer was quarantined, either by
+	// the address reuse policy because it re-used an address that already
+	// received assets or by the partial receive policy because it carries
+	// fewer asset units than requested. A quarantined transfer is only
+	// completed once its proof is imported manually.
+	Quarantined bool
+}
+
+// Shortfall returns the number of asset units that were received less than
+// requested by the address. Zero is returned if no proof was seen yet or the
+// full amount was received.
+func (e *Event) Shortfall() uint64 {
+	if e.AssetAmt == 0 || e.AssetAmt >= e.Addr.Amount {
+		return 0
+	}
+
+	return e.Addr.Amount - e.AssetAmt
 }
 
 // EventStorage is the interface that a component storing address events should
@@ -115,4 +143,15 @@ type EventStorage interface {
 	// with the proof and asset that was imported/created for it.
 	CompleteEvent(ctx context.Context, event *Event, status Status,
 		anchorPoint wire.OutPoint) error
+
+	// SetEventAssetAmt records the number of asset units that were
+	// actually received for an address event, as seen in the proof of the
+	// transfer.
+	SetEventAssetAmt(ctx context.Context, event *Event,
+		assetAmt uint64) error
+
+	// SetEventQuarantined marks an address event as quarantined or
+	// releases it from quarantine.
+	SetEventQuarantined(ctx context.Context, event *Event,
+		quarantined bool) error
 }
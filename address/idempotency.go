@@ -0,0 +1,70 @@
+package address
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrIdempotencyKeyReused is returned if an idempotency key is used to create
+// an address with different parameters than the address it was first used
+// for.
+var ErrIdempotencyKeyReused = errors.New("address: idempotency key already " +
+	"used with different parameters")
+
+// IdempotentAddress returns the address that was created with the given
+// idempotency key if the hash of its creation parameters matches the given
+// one. If the key wasn't used before, a new address is created from the given
+// parameters and stored together with the key in a single database
+// transaction. ErrIdempotencyKeyReused is returned if the key was used with
+// different parameters.
+func (b *Book) IdempotentAddress(ctx context.Context, key string,
+	paramsHash []byte, params NewAddrParams) (*AddrWithKeyInfo, error) {
+
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key must not be empty")
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	// We hold the lock for the whole lookup and creation, otherwise two
+	// concurrent requests with the same key could both create an address.
+	b.idempotencyMtx.Lock()
+	defer b.idempotencyMtx.Unlock()
+
+	knownHash, addr, err := b.cfg.Store.FetchAddrIdempotencyKey(ctx, key)
+	switch {
+	case err == nil:
+		if !bytes.Equal(knownHash, paramsHash) {
+			return nil, fmt.Errorf("%w: %v", ErrIdempotencyKeyReused,
+				key)
+		}
+
+		return addr, nil
+
+	case !errors.Is(err, ErrNoAddr):
+		return nil, fmt.Errorf("unable to fetch idempotency key: %w",
+			err)
+	}
+
+	addr, err = b.prepareAddress(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// The address and the key are stored atomically, so a failure can
+	// neither leave an address without its key, which would create a
+	// second address on retry, nor a key without its address.
+	err = b.cfg.Store.InsertIdempotentAddr(
+		ctx, *addr, params.ScriptKeyTapscript, key, paramsHash,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to insert addr: %w", err)
+	}
+
+	b.notifySubscribers(addr)
+
+	return addr, nil
+}
@@ -0,0 +1,166 @@
+package address
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// MaxKeyRangeSize is the maximum number of keys that can be reserved with a
+// single key range.
+const MaxKeyRangeSize = 10_000
+
+// KeyRange is a range of key indexes of a key family that was reserved from the
+// wallet for a purpose outside of the daemon's own key derivation. All keys of
+// the range were derived from the wallet, so it never hands them out again.
+type KeyRange struct {
+	// Family is the key family the range belongs to.
+	Family keychain.KeyFamily
+
+	// FirstIndex is the first key index of the range.
+	FirstIndex uint32
+
+	// LastIndex is the last key index of the range, inclusive.
+	LastIndex uint32
+
+	// Purpose is a free form description of what the range was reserved
+	// for.
+	Purpose string
+
+	// CreationTime is the time the range was reserved.
+	CreationTime time.Time
+}
+
+// KeyFamilyUsage describes how the keys of a key family are used by the
+// daemon.
+type KeyFamilyUsage struct {
+	// Family is the key family.
+	Family keychain.KeyFamily
+
+	// NextIndex is the lowest key index above all keys the daemon used or
+	// reserved in the family. The wallet might already be past this index
+	// if other users of the wallet derive keys of the same family.
+	NextIndex uint32
+
+	// UsedKeys is the number of keys of the family the daemon derived and
+	// stored, for example as internal or script keys.
+	UsedKeys uint32
+
+	// ReservedRanges are the key ranges that were reserved in the family.
+	ReservedRanges []KeyRange
+}
+
+// ListKeyRanges returns the usage of all key families the daemon derived keys
+// in or reserved key ranges of. The Taproot Asset key family is always
+// included.
+func (b *Book) ListKeyRanges(ctx context.Context) ([]*KeyFamilyUsage, error) {
+	internalKeys, err := b.cfg.Store.FetchAllInternalKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch internal keys: %w", err)
+	}
+
+	keyRanges, err := b.cfg.Store.FetchKeyRanges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch key ranges: %w", err)
+	}
+
+	families := make(map[keychain.KeyFamily]*KeyFamilyUsage)
+	familyUsage := func(family keychain.KeyFamily) *KeyFamilyUsage {
+		usage, ok := families[family]
+		if !ok {
+			usage = &KeyFamilyUsage{
+				Family: family,
+			}
+			families[family] = usage
+		}
+
+		return usage
+	}
+	familyUsage(asset.TaprootAssetsKeyFamily)
+
+	// The same internal key can be stored more than once, for example as
+	// the internal key of an anchor output and of a script key.
+	seenKeys := make(map[keychain.KeyLocator]struct{})
+	for _, keyDesc := range internalKeys {
+		if !isDerivedKey(keyDesc) {
+			continue
+		}
+		if _, ok := seenKeys[keyDesc.KeyLocator]; ok {
+			continue
+		}
+		seenKeys[keyDesc.KeyLocator] = struct{}{}
+
+		usage := familyUsage(keyDesc.Family)
+		usage.UsedKeys++
+		if keyDesc.Index >= usage.NextIndex {
+			usage.NextIndex = keyDesc.Index + 1
+		}
+	}
+
+	for _, keyRange := range keyRanges {
+		usage := familyUsage(keyRange.Family)
+		usage.ReservedRanges = append(usage.ReservedRanges, keyRange)
+		if keyRange.LastIndex >= usage.NextIndex {
+			usage.NextIndex = keyRange.LastIndex + 1
+		}
+	}
+
+	usages := make([]*KeyFamilyUsage, 0, len(families))
+	for _, usage := range families {
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Family < usages[j].Family
+	})
+
+	return usages, nil
+}
+
+// ReserveKeyRange reserves the given number of keys of the given key family
+// for the given purpose by deriving them from the wallet, which then never
+// hands them out again. The keys are derived one after the other, so the range
+// can contain keys that were derived concurrently by other users of the wallet.
+func (b *Book) ReserveKeyRange(ctx context.Context, family keychain.KeyFamily,
+	numKeys uint32, purpose string) (*KeyRange, error) {
+
+	switch {
+	case numKeys == 0:
+		return nil, fmt.Errorf("at least one key must be reserved")
+
+	case numKeys > MaxKeyRangeSize:
+		return nil, fmt.Errorf("cannot reserve more than %d keys at "+
+			"once", MaxKeyRangeSize)
+
+	case purpose == "":
+		return nil, fmt.Errorf("the purpose of the key range must be " +
+			"specified")
+	}
+
+	keyRange := &KeyRange{
+		Family:  family,
+		Purpose: purpose,
+	}
+	for i := uint32(0); i < numKeys; i++ {
+		keyDesc, err := b.cfg.KeyRing.DeriveNextKey(ctx, family)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive key of "+
+				"family %v: %w", family, err)
+		}
+
+		if i == 0 {
+			keyRange.FirstIndex = keyDesc.Index
+		}
+		keyRange.LastIndex = keyDesc.Index
+	}
+	keyRange.CreationTime = time.Now()
+
+	if err := b.cfg.Store.InsertKeyRange(ctx, *keyRange); err != nil {
+		return nil, fmt.Errorf("unable to store key range: %w", err)
+	}
+
+	return keyRange, nil
+}
@@ -40,6 +40,11 @@ const (
 
 	// addrProofCourierType is the TLV type of the proof courier address.
 	addrProofCourierAddrType addressTLVType = 12
+
+	// addrMinAmountType is the TLV type of the minimum amount of an address
+	// that allows any amount. The record is only present for such
+	// addresses.
+	addrMinAmountType addressTLVType = 13
 )
 
 func newAddressVersionRecord(version *Version) tlv.Record {
@@ -106,6 +111,16 @@ func newAddressAmountRecord(amount *uint64) tlv.Record {
 	)
 }
 
+func newAddressMinAmountRecord(minAmount *uint64) tlv.Record {
+	recordSize := func() uint64 {
+		return tlv.VarIntSize(*minAmount)
+	}
+	return tlv.MakeDynamicRecord(
+		addrMinAmountType, minAmount, recordSize,
+		asset.VarIntEncoder, asset.VarIntDecoder,
+	)
+}
+
 func newProofCourierAddrRecord(addr *url.URL) tlv.Record {
 	var addrBytes []byte
 	if addr != nil {
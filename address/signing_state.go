@@ -0,0 +1,266 @@
+package address
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// MaxImportKeyIndexGap is the maximum number of keys the wallet's key
+// derivation of a single key family is advanced by when a signing state is
+// imported. The wallet can only advance its key index by deriving one key
+// after another, so a signing state that is further ahead is rejected before
+// anything is imported.
+const MaxImportKeyIndexGap = 100_000
+
+// ErrNotLocalKey is returned when a key of an imported signing state can't be
+// derived by the wallet, which usually means the signing state was exported by
+// a node with a different lnd seed.
+var ErrNotLocalKey = errors.New("key can't be derived by the wallet")
+
+// SigningState is the inventory of keys a node derived and controls. Together
+// with the proofs of its assets, it allows a node that was rebuilt with the
+// same lnd seed to recognize and spend the assets it previously owned.
+type SigningState struct {
+	// InternalKeys are the internal keys derived by the wallet. These are
+	// used as the internal keys of anchor outputs and of script keys.
+	InternalKeys []keychain.KeyDescriptor
+
+	// ScriptKeys are the script keys derived by the wallet, including
+	// their internal key and tweak.
+	ScriptKeys []asset.ScriptKey
+
+	// KeyIndexes maps each key family to the highest key index used in
+	// it.
+	KeyIndexes map[keychain.KeyFamily]uint32
+}
+
+// trackKeyIndex makes sure the highest key index tracked for the given key's
+// family is at least the key's index.
+func trackKeyIndex(keyIndexes map[keychain.KeyFamily]uint32,
+	loc keychain.KeyLocator) {
+
+	if idx, ok := keyIndexes[loc.Family]; ok && idx >= loc.Index {
+		return
+	}
+
+	keyIndexes[loc.Family] = loc.Index
+}
+
+// isDerivedKey returns true if the key was derived by the wallet. Keys of
+// other parties, for example the ones learned from proofs, are stored without
+// a key locator.
+func isDerivedKey(keyDesc keychain.KeyDescriptor) bool {
+	return keyDesc.Family != 0 || keyDesc.Index != 0
+}
+
+// ExportSigningState returns the inventory of all keys the wallet derived,
+// along with the highest key index used in each key family.
+func (b *Book) ExportSigningState(ctx context.Context) (*SigningState, error) {
+	internalKeys, err := b.cfg.Store.FetchAllInternalKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch internal keys: %w", err)
+	}
+
+	scriptKeys, err := b.cfg.Store.FetchAllScriptKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch script keys: %w", err)
+	}
+
+	state := &SigningState{
+		KeyIndexes: make(map[keychain.KeyFamily]uint32),
+	}
+	for _, keyDesc := range internalKeys {
+		if !isDerivedKey(keyDesc) {
+			continue
+		}
+
+		state.InternalKeys = append(state.InternalKeys, keyDesc)
+		trackKeyIndex(state.KeyIndexes, keyDesc.KeyLocator)
+	}
+	for _, scriptKey := range scriptKeys {
+		if !isDerivedKey(scriptKey.RawKey) {
+			continue
+		}
+
+		state.ScriptKeys = append(state.ScriptKeys, scriptKey)
+		trackKeyIndex(state.KeyIndexes, scriptKey.RawKey.KeyLocator)
+	}
+
+	// Reserved key ranges must not be derived again either.
+	keyRanges, err := b.cfg.Store.FetchKeyRanges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch key ranges: %w", err)
+	}
+	for _, keyRange := range keyRanges {
+		trackKeyIndex(state.KeyIndexes, keychain.KeyLocator{
+			Family: keyRange.Family,
+			Index:  keyRange.LastIndex,
+		})
+	}
+
+	return state, nil
+}
+
+// ImportSigningState imports a signing state that was exported by a node with
+// the same lnd seed. All keys are verified to be derivable by the wallet before
+// any of them is stored. The wallet's key derivation is then advanced past the
+// highest imported key index of each key family, so previously used keys are
+// never derived again.
+func (b *Book) ImportSigningState(ctx context.Context,
+	state *SigningState) error {
+
+	// The key indexes of the signing state are only a lower bound, the
+	// imported keys themselves might have been derived later on.
+	keyIndexes := make(map[keychain.KeyFamily]uint32)
+	for family, idx := range state.KeyIndexes {
+		keyIndexes[family] = idx
+	}
+
+	for _, keyDesc := range state.InternalKeys {
+		if err := b.checkLocalKey(ctx, keyDesc); err != nil {
+			return err
+		}
+
+		trackKeyIndex(keyIndexes, keyDesc.KeyLocator)
+	}
+	scriptKeys := make([]asset.ScriptKey, 0, len(state.ScriptKeys))
+	for _, scriptKey := range state.ScriptKeys {
+		scriptKey, err := tweakScriptKey(scriptKey)
+		if err != nil {
+			return err
+		}
+		if err := b.checkLocalKey(ctx, scriptKey.RawKey); err != nil {
+			return err
+		}
+
+		scriptKeys = append(scriptKeys, scriptKey)
+		trackKeyIndex(keyIndexes, scriptKey.RawKey.KeyLocator)
+	}
+
+	// The wallet can only advance its key index by deriving one key after
+	// another. We learn its current index of each key family first, so we
+	// can reject a signing state that is too far ahead before anything is
+	// imported.
+	nextIndexes := make(map[keychain.KeyFamily]uint32, len(keyIndexes))
+	for family, idx := range keyIndexes {
+		keyDesc, err := b.cfg.KeyRing.DeriveNextKey(ctx, family)
+		if err != nil {
+			return fmt.Errorf("unable to derive key of family %v: "+
+				"%w", family, err)
+		}
+
+		ahead := idx > keyDesc.Index
+		if ahead && idx-keyDesc.Index > MaxImportKeyIndexGap {
+			return fmt.Errorf("key index %d of family %v is more "+
+				"than %d keys ahead of the wallet's key index "+
+				"%d", idx, family, MaxImportKeyIndexGap,
+				keyDesc.Index)
+		}
+
+		nextIndexes[family] = keyDesc.Index + 1
+	}
+
+	// All keys are stored in a single database transaction, so a failed
+	// import doesn't leave a partial key inventory behind. An import that
+	// fails while advancing the key derivation can simply be repeated.
+	err := b.cfg.Store.InsertSigningKeys(
+		ctx, state.InternalKeys, scriptKeys,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to insert signing keys: %w", err)
+	}
+
+	for family, idx := range keyIndexes {
+		err := b.advanceKeyIndex(ctx, family, nextIndexes[family], idx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkLocalKey returns an error if the given key can't be derived by the
+// wallet.
+func (b *Book) checkLocalKey(ctx context.Context,
+	keyDesc keychain.KeyDescriptor) error {
+
+	if keyDesc.PubKey == nil {
+		return fmt.Errorf("key %v/%v has no public key", keyDesc.Family,
+			keyDesc.Index)
+	}
+
+	if !b.cfg.KeyRing.IsLocalKey(ctx, keyDesc) {
+		return fmt.Errorf("%w: %x (family %v, index %v)",
+			ErrNotLocalKey, keyDesc.PubKey.SerializeCompressed(),
+			keyDesc.Family, keyDesc.Index)
+	}
+
+	return nil
+}
+
+// tweakScriptKey re-derives the given script key by applying its tweak to its
+// internal key. An error is returned if the result doesn't match the script
+// key. The returned script key always carries the full tweaked key, even if
+// the given one was only known as an x-only key.
+func tweakScriptKey(scriptKey asset.ScriptKey) (asset.ScriptKey, error) {
+	if scriptKey.PubKey == nil || scriptKey.TweakedScriptKey == nil ||
+		scriptKey.RawKey.PubKey == nil {
+
+		return scriptKey, fmt.Errorf("script key is missing its " +
+			"internal key")
+	}
+
+	var tweakedKey *btcec.PublicKey
+	if len(scriptKey.Tweak) == 0 {
+		tweakedKey = txscript.ComputeTaprootKeyNoScript(
+			scriptKey.RawKey.PubKey,
+		)
+	} else {
+		tweakedKey = txscript.ComputeTaprootOutputKey(
+			scriptKey.RawKey.PubKey, scriptKey.Tweak,
+		)
+	}
+
+	// Script keys are Taproot keys, so we only compare the x coordinate.
+	if !bytes.Equal(
+		schnorr.SerializePubKey(tweakedKey),
+		schnorr.SerializePubKey(scriptKey.PubKey),
+	) {
+
+		return scriptKey, fmt.Errorf("script key %x doesn't match its "+
+			"internal key and tweak",
+			schnorr.SerializePubKey(scriptKey.PubKey))
+	}
+
+	return asset.ScriptKey{
+		PubKey:           tweakedKey,
+		TweakedScriptKey: scriptKey.TweakedScriptKey,
+	}, nil
+}
+
+// advanceKeyIndex derives keys of the given family, starting at the wallet's
+// given next key index, until the wallet's key index is past the given one.
+func (b *Book) advanceKeyIndex(ctx context.Context, family keychain.KeyFamily,
+	nextIdx, idx uint32) error {
+
+	for nextIdx <= idx {
+		keyDesc, err := b.cfg.KeyRing.DeriveNextKey(ctx, family)
+		if err != nil {
+			return fmt.Errorf("unable to advance key index of "+
+				"family %v: %w", family, err)
+		}
+
+		nextIdx = keyDesc.Index + 1
+	}
+
+	return nil
+}
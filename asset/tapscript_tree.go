@@ -0,0 +1,102 @@
+package asset
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+var (
+	// ErrEmptyTapscriptTree is returned when a tapscript tree has neither
+	// leaves nor a root hash.
+	ErrEmptyTapscriptTree = errors.New("tapscript tree has no leaves " +
+		"and no root hash")
+)
+
+// TapscriptTree is a tapscript tree that the script key of an asset commits
+// to, defining spending conditions in addition to the key spend path of the
+// internal key. Either the full tree is known through its leaves, or only its
+// root hash.
+type TapscriptTree struct {
+	// Leaves are the leaves of the tree, in the order they are assembled
+	// into the tree.
+	Leaves []txscript.TapLeaf
+
+	// RootHash is the root hash of the tree, if its leaves aren't known.
+	RootHash *chainhash.Hash
+}
+
+// NewTapscriptTreeFromLeaves creates a tapscript tree from the given scripts,
+// which are used as leaves with the base leaf version.
+func NewTapscriptTreeFromLeaves(scripts [][]byte) *TapscriptTree {
+	leaves := make([]txscript.TapLeaf, len(scripts))
+	for idx := range scripts {
+		leaves[idx] = txscript.NewBaseTapLeaf(scripts[idx])
+	}
+
+	return &TapscriptTree{
+		Leaves: leaves,
+	}
+}
+
+// Validate returns an error if the tapscript tree can't be committed to.
+func (t *TapscriptTree) Validate() error {
+	switch {
+	case len(t.Leaves) == 0 && t.RootHash == nil:
+		return ErrEmptyTapscriptTree
+
+	case len(t.Leaves) > 0 && t.RootHash != nil:
+		return fmt.Errorf("tapscript tree can't have both leaves and " +
+			"a root hash")
+	}
+
+	for idx, leaf := range t.Leaves {
+		if leaf.LeafVersion != txscript.BaseLeafVersion {
+			return fmt.Errorf("leaf %d has unsupported leaf "+
+				"version %d", idx, leaf.LeafVersion)
+		}
+
+		if len(leaf.Script) == 0 {
+			return fmt.Errorf("leaf %d has an empty script", idx)
+		}
+	}
+
+	return nil
+}
+
+// Root returns the root hash of the tapscript tree.
+func (t *TapscriptTree) Root() chainhash.Hash {
+	if t.RootHash != nil {
+		return *t.RootHash
+	}
+
+	tree := txscript.AssembleTaprootScriptTree(t.Leaves...)
+	return tree.RootNode.TapHash()
+}
+
+// ScriptKey returns the script key that commits to the tapscript tree with
+// the given internal key.
+func (t *TapscriptTree) ScriptKey(rawKey keychain.KeyDescriptor) ScriptKey {
+	root := t.Root()
+	tweakedPubKey := txscript.ComputeTaprootOutputKey(
+		rawKey.PubKey, root[:],
+	)
+
+	// Like for BIP-0086 keys, the parity of the key isn't relevant, as
+	// it's only ever serialized in its 32-byte x-only representation.
+	tweakedPubKey, _ = schnorr.ParsePubKey(
+		schnorr.SerializePubKey(tweakedPubKey),
+	)
+
+	return ScriptKey{
+		PubKey: tweakedPubKey,
+		TweakedScriptKey: &TweakedScriptKey{
+			RawKey: rawKey,
+			Tweak:  root[:],
+		},
+	}
+}
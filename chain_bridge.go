@@ -59,6 +59,29 @@ func (l *LndRpcChainBridge) RegisterConfirmationsNtfn(ctx context.Context,
 	}, errChan, nil
 }
 
+// RegisterSpendNtfn registers an intent to be notified once the given outpoint
+// is spent on chain.
+func (l *LndRpcChainBridge) RegisterSpendNtfn(ctx context.Context,
+	outpoint *wire.OutPoint, pkScript []byte,
+	heightHint uint32) (*chainntnfs.SpendEvent, chan error, error) {
+
+	ctx, cancel := context.WithCancel(ctx) // nolint:govet
+	spendChan, errChan, err := l.lnd.ChainNotifier.RegisterSpendNtfn(
+		ctx, outpoint, pkScript, int32(heightHint),
+	)
+	if err != nil {
+		cancel()
+
+		return nil, nil, fmt.Errorf("unable to register for spend: %w",
+			err)
+	}
+
+	return &chainntnfs.SpendEvent{
+		Spend:  spendChan,
+		Cancel: cancel,
+	}, errChan, nil
+}
+
 // RegisterBlockEpochNtfn registers an intent to be notified of each new block
 // connected to the main chain.
 func (l *LndRpcChainBridge) RegisterBlockEpochNtfn(
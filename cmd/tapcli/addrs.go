@@ -32,6 +32,16 @@ const (
 	amtName = "amt"
 
 	assetVersionName = "asset_version"
+
+	tapscriptLeafName = "tapscript_leaf"
+
+	tapscriptRootName = "tapscript_root"
+
+	idempotencyKeyName = "idempotency_key"
+
+	allowAnyAmountName = "allow_any_amount"
+
+	minAmountName = "min_amount"
 )
 
 var newAddrCommand = cli.Command{
@@ -53,6 +63,36 @@ var newAddrCommand = cli.Command{
 			Name:  assetVersionName,
 			Usage: "the asset version of the asset to receive",
 		},
+		cli.StringSliceFlag{
+			Name: tapscriptLeafName,
+			Usage: "a hex encoded script of a leaf of the " +
+				"tapscript tree the script key should commit " +
+				"to, can be specified multiple times",
+		},
+		cli.StringFlag{
+			Name: tapscriptRootName,
+			Usage: "the hex encoded root hash of the tapscript " +
+				"tree the script key should commit to, if " +
+				"its leaves should not be revealed",
+		},
+		cli.StringFlag{
+			Name: idempotencyKeyName,
+			Usage: "an optional key that makes the call " +
+				"idempotent, if an address was already " +
+				"created with the same key and parameters, " +
+				"that address is returned instead of a new one",
+		},
+		cli.BoolFlag{
+			Name: allowAnyAmountName,
+			Usage: "let the sender choose the amount to send, " +
+				"the amt is then only the suggested amount; " +
+				"requires a mailbox based proof courier",
+		},
+		cli.Uint64Flag{
+			Name: minAmountName,
+			Usage: "the minimum amount the address accepts if " +
+				"any amount is allowed",
+		},
 	},
 	Action: newAddr,
 }
@@ -79,10 +119,19 @@ func newAddr(ctx *cli.Context) error {
 		return err
 	}
 
+	scriptKeyTapscript, err := parseScriptKeyTapscript(ctx)
+	if err != nil {
+		return err
+	}
+
 	addr, err := client.NewAddr(ctxc, &taprpc.NewAddrRequest{
-		AssetId:      assetID,
-		Amt:          ctx.Uint64(amtName),
-		AssetVersion: assetVersion,
+		AssetId:            assetID,
+		Amt:                ctx.Uint64(amtName),
+		AssetVersion:       assetVersion,
+		ScriptKeyTapscript: scriptKeyTapscript,
+		IdempotencyKey:     ctx.String(idempotencyKeyName),
+		AllowAnyAmount:     ctx.Bool(allowAnyAmountName),
+		MinAmount:          ctx.Uint64(minAmountName),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to make addr: %w", err)
@@ -92,6 +141,51 @@ func newAddr(ctx *cli.Context) error {
 	return nil
 }
 
+// parseScriptKeyTapscript parses the tapscript tree the script key should
+// commit to from the command line flags. Nil is returned if neither leaves nor
+// a root hash were specified.
+func parseScriptKeyTapscript(
+	ctx *cli.Context) (*taprpc.ScriptKeyTapscript, error) {
+
+	leafScripts := ctx.StringSlice(tapscriptLeafName)
+	rootHash := ctx.String(tapscriptRootName)
+
+	switch {
+	case len(leafScripts) > 0 && rootHash != "":
+		return nil, fmt.Errorf("tapscript leaves and root cannot be " +
+			"both set")
+
+	case len(leafScripts) > 0:
+		tapscript := &taprpc.ScriptKeyTapscript{}
+		for _, leafScript := range leafScripts {
+			script, err := hex.DecodeString(leafScript)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tapscript "+
+					"leaf: %w", err)
+			}
+			tapscript.LeafScripts = append(
+				tapscript.LeafScripts, script,
+			)
+		}
+
+		return tapscript, nil
+
+	case rootHash != "":
+		root, err := hex.DecodeString(rootHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tapscript root: %w",
+				err)
+		}
+
+		return &taprpc.ScriptKeyTapscript{
+			RootHash: root,
+		}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
 const (
 	createdAfterName = "created_after"
 
@@ -3,11 +3,14 @@ package main
 import (
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	taprootassets "github.com/lightninglabs/taproot-assets"
 	"github.com/lightninglabs/taproot-assets/tapcfg"
 	"github.com/lightninglabs/taproot-assets/taprpc"
+	wrpc "github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
 	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
 	"github.com/urfave/cli"
 )
@@ -21,13 +24,34 @@ var assetsCommands = []cli.Command{
 		Subcommands: []cli.Command{
 			mintAssetCommand,
 			listAssetsCommand,
+			subscribeAssetsCommand,
 			listUtxosCommand,
+			onChainFootprintCommand,
 			listGroupsCommand,
+			verifySupplyCommand,
 			listAssetBalancesCommand,
+			rebuildBalancesCommand,
 			sendAssetsCommand,
+			prepareTransferCommand,
+			broadcastTransferCommand,
+			exportPendingTransferCommand,
+			importPendingTransferCommand,
+			bumpTransferFeeCommand,
+			cancelTransferCommand,
 			burnAssetsCommand,
 			listTransfersCommand,
+			listTransfersByScriptKeyCommand,
+			transferGraphCommand,
+			transferMetricsCommand,
+			anchorSweepStatusCommand,
+			sendStatesCommand,
 			fetchMetaCommand,
+			searchMetaCommand,
+			keyDerivationCommand,
+			exportSigningStateCommand,
+			importSigningStateCommand,
+			listKeyRangesCommand,
+			reserveKeyRangeCommand,
 		},
 	},
 }
@@ -39,17 +63,42 @@ var (
 	assetMetaBytesName           = "meta_bytes"
 	assetMetaFilePathName        = "meta_file_path"
 	assetMetaTypeName            = "meta_type"
+	assetSupplyCapName           = "supply_cap"
 	assetEmissionName            = "enable_emission"
 	assetShowWitnessName         = "show_witness"
 	assetShowSpentName           = "show_spent"
 	assetGroupKeyName            = "group_key"
 	assetGroupAnchorName         = "group_anchor"
+	assetGroupAssetIDName        = "group_asset_id"
 	batchKeyName                 = "batch_key"
 	groupByGroupName             = "by_group"
 	assetIDName                  = "asset_id"
 	shortResponseName            = "short"
+	finalizeTimeName             = "finalize_time"
+	minSeedlingsName             = "min_seedlings"
 	assetAmountName              = "amount"
 	burnOverrideConfirmationName = "override_confirmation_destroy_assets"
+	anchorTxidName               = "anchor_txid"
+	maxInputsName                = "max_inputs"
+	feeBumpAnchorName            = "fee_bump_anchor"
+	cpfpName                     = "cpfp"
+	dryRunName                   = "dry_run"
+	inputOutpointName            = "input_outpoint"
+	sendAmountName               = "send_amount"
+	proofCourierAddrName         = "proof_courier_addr"
+	transferLabelName            = "label"
+	labelFilterName              = "label_filter"
+	acquiredAfterName            = "acquired_after"
+	acquiredBeforeName           = "acquired_before"
+	includeLeasedName            = "include_leased"
+	transferPackageName          = "transfer_package"
+	satPerKwName                 = "sat_per_kw"
+	signingStateFileName         = "signing_state_file"
+	metaQueryName                = "query"
+	includeUniverseName          = "include_universe"
+	keyFamilyName                = "key_family"
+	numKeysName                  = "num_keys"
+	purposeName                  = "purpose"
 )
 
 var mintAssetCommand = cli.Command{
@@ -85,14 +134,21 @@ var mintAssetCommand = cli.Command{
 				"and used as the asset meta",
 		},
 		cli.IntFlag{
-			Name:  assetMetaTypeName,
-			Usage: "the type of the meta data for the asset",
+			Name: assetMetaTypeName,
+			Usage: "the type of the meta data for the asset, " +
+				"0 for opaque data and 1 for JSON",
 		},
 		cli.BoolFlag{
 			Name: assetEmissionName,
 			Usage: "if true, then the asset supports on going " +
 				"emission",
 		},
+		cli.Uint64Flag{
+			Name: assetSupplyCapName,
+			Usage: "the maximum total supply that can ever be " +
+				"issued for the new asset group, requires " +
+				"emission to be enabled",
+		},
 		cli.StringFlag{
 			Name: assetGroupKeyName,
 			Usage: "the specific group key to use to mint the " +
@@ -103,6 +159,26 @@ var mintAssetCommand = cli.Command{
 			Usage: "the other asset in this batch that the new " +
 				"asset be grouped with",
 		},
+		cli.StringFlag{
+			Name: assetGroupAssetIDName,
+			Usage: "the ID of a previously issued grouped asset " +
+				"whose group the new asset should be minted " +
+				"into",
+		},
+		cli.StringSliceFlag{
+			Name: tapscriptLeafName,
+			Usage: "a hex encoded script of a leaf of the " +
+				"tapscript tree the script key of the asset " +
+				"should commit to, can be specified multiple " +
+				"times",
+		},
+		cli.StringFlag{
+			Name: tapscriptRootName,
+			Usage: "the hex encoded root hash of the tapscript " +
+				"tree the script key of the asset should " +
+				"commit to, if its leaves should not be " +
+				"revealed",
+		},
 		cli.BoolFlag{
 			Name: shortResponseName,
 			Usage: "if true, then the current assets within the " +
@@ -116,6 +192,7 @@ var mintAssetCommand = cli.Command{
 		listBatchesCommand,
 		finalizeBatchCommand,
 		cancelBatchCommand,
+		rotateGroupKeyCommand,
 	},
 }
 
@@ -137,9 +214,11 @@ func mintAsset(ctx *cli.Context) error {
 	}
 
 	var (
-		groupKey    []byte
-		err         error
-		groupKeyStr = ctx.String(assetGroupKeyName)
+		groupKey        []byte
+		groupAssetID    []byte
+		err             error
+		groupKeyStr     = ctx.String(assetGroupKeyName)
+		groupAssetIDStr = ctx.String(assetGroupAssetIDName)
 	)
 
 	if len(groupKeyStr) != 0 {
@@ -149,6 +228,13 @@ func mintAsset(ctx *cli.Context) error {
 		}
 	}
 
+	if len(groupAssetIDStr) != 0 {
+		groupAssetID, err = hex.DecodeString(groupAssetIDStr)
+		if err != nil {
+			return fmt.Errorf("invalid group asset ID")
+		}
+	}
+
 	// Both the meta bytes and the meta path can be set.
 	var assetMeta *taprpc.AssetMeta
 	switch {
@@ -178,6 +264,20 @@ func mintAsset(ctx *cli.Context) error {
 		}
 	}
 
+	// The supply cap is part of the asset meta, so we'll create an empty
+	// meta if no other meta data was provided.
+	if supplyCap := ctx.Uint64(assetSupplyCapName); supplyCap != 0 {
+		if assetMeta == nil {
+			assetMeta = &taprpc.AssetMeta{}
+		}
+		assetMeta.SupplyCap = supplyCap
+	}
+
+	scriptKeyTapscript, err := parseScriptKeyTapscript(ctx)
+	if err != nil {
+		return err
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getMintClient(ctx)
 	defer cleanUp()
@@ -193,6 +293,8 @@ func mintAsset(ctx *cli.Context) error {
 			AssetVersion: taprpc.AssetVersion(
 				ctx.Uint64(assetVersionName),
 			),
+			ScriptKeyTapscript: scriptKeyTapscript,
+			GroupAssetId:       groupAssetID,
 		},
 		EnableEmission: ctx.Bool(assetEmissionName),
 		ShortResponse:  ctx.Bool(shortResponseName),
@@ -218,6 +320,18 @@ var finalizeBatchCommand = cli.Command{
 				"in order to avoid printing a large amount " +
 				"of data in case of large batches",
 		},
+		cli.Int64Flag{
+			Name: finalizeTimeName,
+			Usage: "if set, the batch is not finalized right " +
+				"away but automatically at the given unix " +
+				"timestamp in seconds",
+		},
+		cli.Uint64Flag{
+			Name: minSeedlingsName,
+			Usage: "if set, the batch is not finalized right " +
+				"away but automatically once it contains " +
+				"the given number of seedlings",
+		},
 	},
 	Action: finalizeBatch,
 }
@@ -229,6 +343,8 @@ func finalizeBatch(ctx *cli.Context) error {
 
 	resp, err := client.FinalizeBatch(ctxc, &mintrpc.FinalizeBatchRequest{
 		ShortResponse: ctx.Bool(shortResponseName),
+		FinalizeTime:  ctx.Int64(finalizeTimeName),
+		MinSeedlings:  uint32(ctx.Uint64(minSeedlingsName)),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to finalize batch: %w", err)
@@ -304,6 +420,56 @@ func listBatches(ctx *cli.Context) error {
 	return nil
 }
 
+var rotateGroupKeyCommand = cli.Command{
+	Name:      "rotategroupkey",
+	ShortName: "r",
+	Usage:     "rotate the issuance authority of an asset group",
+	Description: `
+	Hand the authority to issue further assets into an asset group over to a
+	freshly derived key. The rotation is committed to on chain, and the
+	command only returns once the committing transaction has confirmed.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetGroupKeyName,
+			Usage: "the tweaked group key of the asset group",
+		},
+		cli.Uint64Flag{
+			Name: satPerKwName,
+			Usage: "if set, the fee rate in sat/kw to use " +
+				"for the rotation transaction",
+		},
+	},
+	Action: rotateGroupKey,
+}
+
+func rotateGroupKey(ctx *cli.Context) error {
+	groupKeyStr := ctx.String(assetGroupKeyName)
+	if ctx.NArg() != 0 || groupKeyStr == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	groupKey, err := hex.DecodeString(groupKeyStr)
+	if err != nil {
+		return fmt.Errorf("invalid group key: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getMintClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RotateGroupKey(ctxc, &mintrpc.RotateGroupKeyRequest{
+		GroupKey: groupKey,
+		SatPerKw: uint32(ctx.Uint64(satPerKwName)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to rotate group key: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var listAssetsCommand = cli.Command{
 	Name:        "list",
 	ShortName:   "l",
@@ -318,6 +484,16 @@ var listAssetsCommand = cli.Command{
 			Name:  assetShowSpentName,
 			Usage: "include fully spent assets in the list",
 		},
+		cli.Int64Flag{
+			Name: acquiredAfterName,
+			Usage: "only list assets acquired at or after this " +
+				"unix timestamp",
+		},
+		cli.Int64Flag{
+			Name: acquiredBeforeName,
+			Usage: "only list assets acquired before this unix " +
+				"timestamp",
+		},
 	},
 	Action: listAssets,
 }
@@ -330,8 +506,10 @@ func listAssets(ctx *cli.Context) error {
 	// TODO(roasbeef): need to reverse txid
 
 	resp, err := client.ListAssets(ctxc, &taprpc.ListAssetRequest{
-		WithWitness:  ctx.Bool(assetShowWitnessName),
-		IncludeSpent: ctx.Bool(assetShowSpentName),
+		WithWitness:    ctx.Bool(assetShowWitnessName),
+		IncludeSpent:   ctx.Bool(assetShowSpentName),
+		AcquiredAfter:  ctx.Int64(acquiredAfterName),
+		AcquiredBefore: ctx.Int64(acquiredBeforeName),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to list assets: %w", err)
@@ -340,6 +518,57 @@ func listAssets(ctx *cli.Context) error {
 	return nil
 }
 
+var subscribeAssetsCommand = cli.Command{
+	Name:  "subscribe",
+	Usage: "stream the set of owned assets and updates to it",
+	Description: `
+	Print the current set of owned assets, followed by an update whenever
+	assets are received, sent or change their confirmation status. Runs
+	until interrupted.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  assetShowWitnessName,
+			Usage: "include the asset's witness data",
+		},
+		cli.BoolFlag{
+			Name:  includeLeasedName,
+			Usage: "include assets that are leased",
+		},
+	},
+	Action: subscribeAssets,
+}
+
+func subscribeAssets(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeAssets(
+		ctxc, &taprpc.SubscribeAssetsRequest{
+			WithWitness:   ctx.Bool(assetShowWitnessName),
+			IncludeLeased: ctx.Bool(includeLeasedName),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to subscribe to assets: %w", err)
+	}
+
+	for {
+		update, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to receive asset update: %w",
+				err)
+		}
+
+		printRespJSON(update)
+	}
+}
+
 var listUtxosCommand = cli.Command{
 	Name:        "utxos",
 	ShortName:   "u",
@@ -361,6 +590,31 @@ func listUtxos(ctx *cli.Context) error {
 	return nil
 }
 
+var onChainFootprintCommand = cli.Command{
+	Name:  "footprint",
+	Usage: "list all on-chain utxos controlled by the daemon",
+	Description: "list all on-chain utxos the daemon considers its own, " +
+		"including anchor outputs that only hold spent assets, " +
+		"together with their key derivation and the assets they " +
+		"anchor",
+	Action: onChainFootprint,
+}
+
+func onChainFootprint(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListOnChainFootprint(
+		ctxc, &taprpc.ListOnChainFootprintRequest{},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to list on-chain footprint: %w", err)
+	}
+	printRespJSON(resp)
+	return nil
+}
+
 var listGroupsCommand = cli.Command{
 	Name:        "groups",
 	ShortName:   "g",
@@ -390,8 +644,9 @@ var listAssetBalancesCommand = cli.Command{
 	Action:      listAssetBalances,
 	Flags: []cli.Flag{
 		cli.BoolFlag{
-			Name:  groupByGroupName,
-			Usage: "Group asset balances by group key",
+			Name: groupByGroupName,
+			Usage: "Group asset balances by group key, with a " +
+				"breakdown by asset ID",
 		},
 		cli.StringFlag{
 			Name: assetIDName,
@@ -453,6 +708,89 @@ func listAssetBalances(ctx *cli.Context) error {
 	return nil
 }
 
+var verifySupplyCommand = cli.Command{
+	Name:  "verifysupply",
+	Usage: "verify the supply of an asset group",
+	Description: `
+	Reconcile the supply of an asset group. The issuance proofs of the local
+	universe are checked against the universe root and all known burns are
+	subtracted from the issued amount. The remaining outstanding units are
+	checked against the units held in the outputs known to the universe or
+	the node that no known transfer spends, and against the balance held by
+	the node. All discrepancies found are reported.
+	`,
+	Action: verifySupply,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  groupKeyName,
+			Usage: "the group key of the asset group to verify",
+		},
+	},
+}
+
+func verifySupply(ctx *cli.Context) error {
+	if ctx.String(groupKeyName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	groupKey, err := hex.DecodeString(ctx.String(groupKeyName))
+	if err != nil {
+		return fmt.Errorf("invalid group key: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.VerifySupplyConsistency(
+		ctxc, &taprpc.VerifySupplyConsistencyRequest{
+			GroupKey: groupKey,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to verify supply: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var rebuildBalancesCommand = cli.Command{
+	Name:      "rebuildbalances",
+	ShortName: "rb",
+	Usage:     "rebuild asset balances from the stored proofs",
+	Description: `
+	Re-derive the amounts of all owned, unspent assets from their stored
+	proofs and check the proofs against the current chain. Recorded amounts
+	that differ from the proofs are replaced, and all discrepancies found
+	are reported. Use --dry_run to only report the discrepancies.
+	`,
+	Action: rebuildBalances,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: dryRunName,
+			Usage: "only report discrepancies without changing " +
+				"the recorded assets",
+		},
+	},
+}
+
+func rebuildBalances(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RebuildBalances(ctxc, &taprpc.RebuildBalancesRequest{
+		DryRun: ctx.Bool(dryRunName),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to rebuild asset balances: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var sendAssetsCommand = cli.Command{
 	Name:        "send",
 	ShortName:   "s",
@@ -464,6 +802,64 @@ var sendAssetsCommand = cli.Command{
 			Usage: "addr to send to; can be specified multiple " +
 				"times to send to multiple addresses at once",
 		},
+		cli.Uint64Flag{
+			Name: maxInputsName,
+			Usage: "(optional) the maximum number of asset " +
+				"inputs the send may spend; overrides the " +
+				"daemon's configured maximum and is never " +
+				"relaxed",
+		},
+		cli.BoolFlag{
+			Name: feeBumpAnchorName,
+			Usage: "reserve an additional output in the anchor " +
+				"transaction that can be spent to bump its " +
+				"fee with a child transaction (CPFP)",
+		},
+		cli.Uint64Flag{
+			Name: satPerKwName,
+			Usage: "(optional) the fee rate in sat/kw to fund " +
+				"the anchor transaction with; if not set, " +
+				"the fee rate is estimated",
+		},
+		cli.StringSliceFlag{
+			Name: inputOutpointName,
+			Usage: "(optional) the anchor outpoint (txid:index) " +
+				"of an asset the send may spend; can be " +
+				"specified multiple times to restrict the " +
+				"send to multiple inputs",
+		},
+		cli.Int64SliceFlag{
+			Name: sendAmountName,
+			Usage: "(optional) the amount to send to an addr " +
+				"that allows any amount; can be specified " +
+				"once per addr, in the same order, with 0 " +
+				"sending the amount requested by the addr",
+		},
+		cli.StringFlag{
+			Name: proofCourierAddrName,
+			Usage: "(optional) the proof courier to deliver the " +
+				"proofs through instead of the one of the " +
+				"addrs; must use the same protocol, host " +
+				"and port",
+		},
+		cli.StringFlag{
+			Name: transferLabelName,
+			Usage: "(optional) a human-readable label to store " +
+				"the transfer with",
+		},
+		cli.StringFlag{
+			Name: idempotencyKeyName,
+			Usage: "(optional) a unique key for the send; if " +
+				"a transfer was already requested with the " +
+				"same key, it is returned instead of sending " +
+				"again",
+		},
+		cli.BoolFlag{
+			Name: dryRunName,
+			Usage: "only preview the inputs, outputs and chain " +
+				"fees of the send without signing, " +
+				"broadcasting or locking anything",
+		},
 		// TODO(roasbeef): add arg for file name to write sender proof
 		// blob
 	},
@@ -476,12 +872,30 @@ func sendAssets(ctx *cli.Context) error {
 		return cli.ShowSubcommandHelp(ctx)
 	}
 
+	sendAmounts := ctx.Int64Slice(sendAmountName)
+	amounts := make([]uint64, len(sendAmounts))
+	for idx, amount := range sendAmounts {
+		if amount < 0 {
+			return fmt.Errorf("invalid send amount %d", amount)
+		}
+		amounts[idx] = uint64(amount)
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	resp, err := client.SendAsset(ctxc, &taprpc.SendAssetRequest{
-		TapAddrs: addrs,
+		TapAddrs:             addrs,
+		MaxInputs:            uint32(ctx.Uint64(maxInputsName)),
+		ReserveFeeBumpAnchor: ctx.Bool(feeBumpAnchorName),
+		FeeRate:              uint32(ctx.Uint64(satPerKwName)),
+		DryRun:               ctx.Bool(dryRunName),
+		InputOutpoints:       ctx.StringSlice(inputOutpointName),
+		Amounts:              amounts,
+		ProofCourierAddr:     ctx.String(proofCourierAddrName),
+		Label:                ctx.String(transferLabelName),
+		IdempotencyKey:       ctx.String(idempotencyKeyName),
 	})
 	if err != nil {
 		return fmt.Errorf("unable to send assets: %w", err)
@@ -491,128 +905,405 @@ func sendAssets(ctx *cli.Context) error {
 	return nil
 }
 
-var burnAssetsCommand = cli.Command{
-	Name:  "burn",
-	Usage: "burn a number of asset units",
-	Description: `
-	Burn (destroy, remove from circulation) a number of asset units in a
-	provable way. The returned burn proof is cryptographic evidence that the
-	assets can no longer be spent and that the supply has been reduced by
-	the specified amount.
-
-	To avoid a dangling BTC output, not all assets within a commitment can
-	be burned completely, there always must be a change output (either with
-	a change amount or another asset that resides in the same commitment).
-	`,
+var prepareTransferCommand = cli.Command{
+	Name:  "prepare",
+	Usage: "prepare an asset send without broadcasting it",
+	Description: "Fund, sign and persist the anchor transaction of a " +
+		"send to one or more taproot asset addrs without " +
+		"broadcasting it. The inputs stay locked until the transfer " +
+		"is released with the broadcast command.",
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  assetIDName,
-			Usage: "the asset ID to burn units from",
+		cli.StringSliceFlag{
+			Name: addrName,
+			Usage: "addr to send to; can be specified multiple " +
+				"times to send to multiple addresses at once",
 		},
 		cli.Uint64Flag{
-			Name:  assetAmountName,
-			Usage: "the amount of units to burn/destroy",
+			Name: maxInputsName,
+			Usage: "(optional) the maximum number of asset " +
+				"inputs the send may spend; overrides the " +
+				"daemon's configured maximum and is never " +
+				"relaxed",
 		},
 		cli.BoolFlag{
-			Name: burnOverrideConfirmationName,
-			Usage: "if set, the confirmation prompt will be " +
-				"skipped and the assets are burned/destroyed " +
-				"immediately",
+			Name: feeBumpAnchorName,
+			Usage: "reserve an additional output in the anchor " +
+				"transaction that can be spent to bump its " +
+				"fee with a child transaction (CPFP)",
 		},
 	},
-	Action: burnAssets,
+	Action: prepareTransfer,
 }
 
-func burnAssets(ctx *cli.Context) error {
-	if ctx.NArg() != 0 || ctx.NumFlags() == 0 {
+func prepareTransfer(ctx *cli.Context) error {
+	addrs := ctx.StringSlice(addrName)
+	if ctx.NArg() != 0 || ctx.NumFlags() == 0 || len(addrs) == 0 {
 		return cli.ShowSubcommandHelp(ctx)
 	}
 
-	assetIDHex := ctx.String(assetIDName)
-	assetIDBytes, err := hex.DecodeString(assetIDHex)
-	if err != nil {
-		return fmt.Errorf("invalid asset ID")
-	}
-
-	burnAmount := ctx.Uint64(assetAmountName)
-	if burnAmount == 0 {
-		return fmt.Errorf("invalid burn amount")
-	}
-
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	if !ctx.Bool(burnOverrideConfirmationName) {
-		balance, err := client.ListBalances(
-			ctxc, &taprpc.ListBalancesRequest{
-				GroupBy: &taprpc.ListBalancesRequest_AssetId{
-					AssetId: true,
-				},
-				AssetFilter: assetIDBytes,
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("unable to list current asset "+
-				"balances: %w", err)
-		}
-
-		assetBalance, ok := balance.AssetBalances[assetIDHex]
-		if !ok {
-			return fmt.Errorf("couldn't fetch balance for asset %x",
-				assetIDBytes)
-		}
-
-		msg := fmt.Sprintf("Please confirm destructive action.\n"+
-			"Asset ID: %x\nCurrent available balance: %d\n"+
-			"Amount to burn: %d\n Are you sure you want to "+
-			"irreversibly burn (destroy, remove from circulation) "+
-			"the specified amount of assets?\nPlease answer 'yes' "+
-			"or 'no' and press enter: ", assetIDBytes,
-			assetBalance.Balance, burnAmount)
-
-		if !promptForConfirmation(msg) {
-			return nil
-		}
-	}
-
-	resp, err := client.BurnAsset(ctxc, &taprpc.BurnAssetRequest{
-		Asset: &taprpc.BurnAssetRequest_AssetId{
-			AssetId: assetIDBytes,
-		},
-		AmountToBurn:     burnAmount,
-		ConfirmationText: taprootassets.AssetBurnConfirmationText,
+	resp, err := client.PrepareTransfer(ctxc, &taprpc.PrepareTransferRequest{
+		TapAddrs:             addrs,
+		MaxInputs:            uint32(ctx.Uint64(maxInputsName)),
+		ReserveFeeBumpAnchor: ctx.Bool(feeBumpAnchorName),
 	})
 	if err != nil {
-		return fmt.Errorf("unable to send assets: %w", err)
+		return fmt.Errorf("unable to prepare transfer: %w", err)
 	}
 
 	printRespJSON(resp)
 	return nil
 }
 
-var listTransfersCommand = cli.Command{
-	Name:      "transfers",
-	ShortName: "t",
-	Usage:     "list asset transfers",
-	Description: "list outgoing transfers of all assets or a selected " +
-		"asset",
-	Action: listTransfers,
+var broadcastTransferCommand = cli.Command{
+	Name:  "broadcast",
+	Usage: "broadcast a prepared asset send",
+	Description: "Release a transfer staged with the prepare command, " +
+		"broadcasting its anchor transaction.",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: assetIDName,
-			Usage: "A specific asset ID to list outgoing " +
-				"transfers for",
+			Name: anchorTxidName,
+			Usage: "the anchor transaction hash of the staged " +
+				"transfer",
 		},
 	},
+	Action: broadcastTransfer,
 }
 
-func listTransfers(ctx *cli.Context) error {
+func broadcastTransfer(ctx *cli.Context) error {
+	anchorTxid := ctx.String(anchorTxidName)
+	if ctx.NArg() != 0 || anchorTxid == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &taprpc.ListTransfersRequest{}
+	resp, err := client.BroadcastTransfer(
+		ctxc, &taprpc.BroadcastTransferRequest{
+			AnchorTxid: anchorTxid,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to broadcast transfer: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportPendingTransferCommand = cli.Command{
+	Name:  "exportpending",
+	Usage: "export a pending asset send for another daemon",
+	Description: "Export the state of a pending transfer, so another " +
+		"daemon connected to the same lnd node can take it over " +
+		"with the importpending command.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: anchorTxidName,
+			Usage: "the anchor transaction hash of the pending " +
+				"transfer",
+		},
+	},
+	Action: exportPendingTransfer,
+}
+
+func exportPendingTransfer(ctx *cli.Context) error {
+	anchorTxid := ctx.String(anchorTxidName)
+	if ctx.NArg() != 0 || anchorTxid == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportPendingTransfer(
+		ctxc, &taprpc.ExportPendingTransferRequest{
+			AnchorTxid: anchorTxid,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to export pending transfer: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var importPendingTransferCommand = cli.Command{
+	Name:  "importpending",
+	Usage: "take over a pending asset send of another daemon",
+	Description: "Import a pending transfer exported by another daemon " +
+		"connected to the same lnd node and complete its broadcast " +
+		"and proof delivery.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: transferPackageName,
+			Usage: "the hex encoded transfer package returned by " +
+				"the exportpending command",
+		},
+	},
+	Action: importPendingTransfer,
+}
+
+func importPendingTransfer(ctx *cli.Context) error {
+	transferPackage := ctx.String(transferPackageName)
+	if ctx.NArg() != 0 || transferPackage == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	pkgBytes, err := hex.DecodeString(transferPackage)
+	if err != nil {
+		return fmt.Errorf("invalid transfer package: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportPendingTransfer(
+		ctxc, &taprpc.ImportPendingTransferRequest{
+			TransferPackage: pkgBytes,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to import pending transfer: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var bumpTransferFeeCommand = cli.Command{
+	Name:  "bumpfee",
+	Usage: "bump the fee of a pending asset send",
+	Description: "Replace the unconfirmed anchor transaction of a " +
+		"pending transfer with one that pays a higher fee rate " +
+		"(RBF). The additional fee is paid from the change output " +
+		"of the anchor transaction. With --" + cpfpName + ", the " +
+		"anchor transaction is kept and a child transaction " +
+		"spending its fee bump anchor output is published instead " +
+		"(CPFP).",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: anchorTxidName,
+			Usage: "the anchor transaction hash of the pending " +
+				"transfer",
+		},
+		cli.Uint64Flag{
+			Name: satPerKwName,
+			Usage: "the fee rate in sat/kw the replacement anchor " +
+				"transaction should pay",
+		},
+		cli.BoolFlag{
+			Name: cpfpName,
+			Usage: "bump the fee with a child transaction " +
+				"spending the fee bump anchor output of the " +
+				"transfer",
+		},
+	},
+	Action: bumpTransferFee,
+}
+
+func bumpTransferFee(ctx *cli.Context) error {
+	anchorTxid := ctx.String(anchorTxidName)
+	feeRate := ctx.Uint64(satPerKwName)
+	if ctx.NArg() != 0 || anchorTxid == "" || feeRate == 0 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.BumpTransferFee(
+		ctxc, &taprpc.BumpTransferFeeRequest{
+			AnchorTxid:       anchorTxid,
+			FeeRate:          uint32(feeRate),
+			UseFeeBumpAnchor: ctx.Bool(cpfpName),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to bump transfer fee: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var cancelTransferCommand = cli.Command{
+	Name:  "cancel",
+	Usage: "cancel a pending asset send that doesn't confirm",
+	Description: "Cancel a pending transfer whose anchor transaction " +
+		"hasn't confirmed yet and release its asset inputs, so they " +
+		"can be spent again.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: anchorTxidName,
+			Usage: "the anchor transaction hash of the pending " +
+				"transfer",
+		},
+	},
+	Action: cancelTransfer,
+}
+
+func cancelTransfer(ctx *cli.Context) error {
+	anchorTxid := ctx.String(anchorTxidName)
+	if ctx.NArg() != 0 || anchorTxid == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CancelTransfer(
+		ctxc, &taprpc.CancelTransferRequest{
+			AnchorTxid: anchorTxid,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to cancel transfer: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var burnAssetsCommand = cli.Command{
+	Name:  "burn",
+	Usage: "burn a number of asset units",
+	Description: `
+	Burn (destroy, remove from circulation) a number of asset units in a
+	provable way. The returned burn proof is cryptographic evidence that the
+	assets can no longer be spent and that the supply has been reduced by
+	the specified amount.
+
+	To avoid a dangling BTC output, not all assets within a commitment can
+	be burned completely, there always must be a change output (either with
+	a change amount or another asset that resides in the same commitment).
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID to burn units from",
+		},
+		cli.Uint64Flag{
+			Name:  assetAmountName,
+			Usage: "the amount of units to burn/destroy",
+		},
+		cli.BoolFlag{
+			Name: burnOverrideConfirmationName,
+			Usage: "if set, the confirmation prompt will be " +
+				"skipped and the assets are burned/destroyed " +
+				"immediately",
+		},
+	},
+	Action: burnAssets,
+}
+
+func burnAssets(ctx *cli.Context) error {
+	if ctx.NArg() != 0 || ctx.NumFlags() == 0 {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	assetIDHex := ctx.String(assetIDName)
+	assetIDBytes, err := hex.DecodeString(assetIDHex)
+	if err != nil {
+		return fmt.Errorf("invalid asset ID")
+	}
+
+	burnAmount := ctx.Uint64(assetAmountName)
+	if burnAmount == 0 {
+		return fmt.Errorf("invalid burn amount")
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.Bool(burnOverrideConfirmationName) {
+		balance, err := client.ListBalances(
+			ctxc, &taprpc.ListBalancesRequest{
+				GroupBy: &taprpc.ListBalancesRequest_AssetId{
+					AssetId: true,
+				},
+				AssetFilter: assetIDBytes,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to list current asset "+
+				"balances: %w", err)
+		}
+
+		assetBalance, ok := balance.AssetBalances[assetIDHex]
+		if !ok {
+			return fmt.Errorf("couldn't fetch balance for asset %x",
+				assetIDBytes)
+		}
+
+		msg := fmt.Sprintf("Please confirm destructive action.\n"+
+			"Asset ID: %x\nCurrent available balance: %d\n"+
+			"Amount to burn: %d\n Are you sure you want to "+
+			"irreversibly burn (destroy, remove from circulation) "+
+			"the specified amount of assets?\nPlease answer 'yes' "+
+			"or 'no' and press enter: ", assetIDBytes,
+			assetBalance.Balance, burnAmount)
+
+		if !promptForConfirmation(msg) {
+			return nil
+		}
+	}
+
+	resp, err := client.BurnAsset(ctxc, &taprpc.BurnAssetRequest{
+		Asset: &taprpc.BurnAssetRequest_AssetId{
+			AssetId: assetIDBytes,
+		},
+		AmountToBurn:     burnAmount,
+		ConfirmationText: taprootassets.AssetBurnConfirmationText,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to send assets: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listTransfersCommand = cli.Command{
+	Name:      "transfers",
+	ShortName: "t",
+	Usage:     "list asset transfers",
+	Description: "list outgoing transfers of all assets or a selected " +
+		"asset",
+	Action: listTransfers,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: assetIDName,
+			Usage: "A specific asset ID to list outgoing " +
+				"transfers for",
+		},
+		cli.StringFlag{
+			Name: labelFilterName,
+			Usage: "only list the transfers whose label contains " +
+				"the given string",
+		},
+	},
+}
+
+func listTransfers(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.ListTransfersRequest{
+		LabelFilter: ctx.String(labelFilterName),
+	}
 	resp, err := client.ListTransfers(ctxc, req)
 	if err != nil {
 		return fmt.Errorf("unable to list asset transfers: %w", err)
@@ -622,6 +1313,193 @@ func listTransfers(ctx *cli.Context) error {
 	return nil
 }
 
+var listTransfersByScriptKeyCommand = cli.Command{
+	Name:  "transfersbykey",
+	Usage: "list all transfers involving a script key",
+	Description: `
+	List all inbound and outbound transfers that created, spent or changed
+	an output with the given script key, ordered by the time they were
+	first seen.
+	`,
+	Action: listTransfersByScriptKey,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key to list the transfers for",
+		},
+	},
+}
+
+func listTransfersByScriptKey(ctx *cli.Context) error {
+	if ctx.String(scriptKeyName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	scriptKey, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.ListTransfersByScriptKeyRequest{
+		ScriptKey: scriptKey,
+	}
+	resp, err := client.ListTransfersByScriptKey(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to list transfers: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var transferGraphCommand = cli.Command{
+	Name:  "transfergraph",
+	Usage: "show the flow from the inputs to the outputs of a transfer",
+	Description: `
+	Show an outgoing transfer together with the edges from each of its
+	inputs to the outputs it contributed to, including the amount that
+	went into each output. The inputs of an asset ID are attributed to its
+	outputs in the order both appear in the transfer.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  anchorTxidName,
+			Usage: "the anchor transaction hash of the transfer",
+		},
+	},
+	Action: transferGraph,
+}
+
+func transferGraph(ctx *cli.Context) error {
+	anchorTxid := ctx.String(anchorTxidName)
+	if ctx.NArg() != 0 || anchorTxid == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetTransferGraph(
+		ctxc, &taprpc.GetTransferGraphRequest{
+			AnchorTxid: anchorTxid,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to get transfer graph: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var transferMetricsCommand = cli.Command{
+	Name:  "transfermetrics",
+	Usage: "show transfer latency and throughput metrics",
+	Description: "Show latency percentiles for the confirmation and " +
+		"proof delivery of outgoing transfers, as well as the " +
+		"transfer throughput, within the given time window.",
+	Action: transferMetrics,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  createdAfterName,
+			Usage: "a duration short hand (-1h, -48h, etc)",
+		},
+		cli.StringFlag{
+			Name:  createdBeforeName,
+			Usage: "a duration short hand (-1h, -48h, etc)",
+		},
+	},
+}
+
+func transferMetrics(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.GetTransferMetricsRequest{}
+	if ctx.IsSet(createdAfterName) {
+		startOffset, err := time.ParseDuration(
+			ctx.String(createdAfterName),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to parse start: %w", err)
+		}
+		req.StartTimestamp = time.Now().Add(startOffset).Unix()
+	}
+	if ctx.IsSet(createdBeforeName) {
+		endOffset, err := time.ParseDuration(
+			ctx.String(createdBeforeName),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to parse end: %w", err)
+		}
+		req.EndTimestamp = time.Now().Add(endOffset).Unix()
+	}
+
+	resp, err := client.GetTransferMetrics(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get transfer metrics: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var anchorSweepStatusCommand = cli.Command{
+	Name:  "sweepstatus",
+	Usage: "show the status of the anchor output sweeper",
+	Description: "Show the status of the background sweeper of anchor " +
+		"outputs that only anchor spent assets, such as the " +
+		"tombstones and burns left behind by completed transfers, " +
+		"together with the anchor outputs that are waiting to be " +
+		"swept and the ones that were already swept.",
+	Action: anchorSweepStatus,
+}
+
+func anchorSweepStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.AnchorSweepStatusRequest{}
+	resp, err := client.AnchorSweepStatus(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get anchor sweep status: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var sendStatesCommand = cli.Command{
+	Name:  "sendstates",
+	Usage: "show the states outgoing transfers move through",
+	Description: "Show all states of the state machine outgoing " +
+		"transfers move through, in order, together with the " +
+		"states a transfer can move to from each of them.",
+	Action: sendStates,
+}
+
+func sendStates(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.GetSendStateMachineRequest{}
+	resp, err := client.GetSendStateMachine(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get send state machine: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 const (
 	metaName = "asset_meta"
 )
@@ -686,3 +1564,264 @@ func fetchMeta(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var searchMetaCommand = cli.Command{
+	Name:  "searchmeta",
+	Usage: "search assets by the content of their meta data",
+	Description: `
+	Search the owned assets, and optionally the assets known from the
+	universe, by the content of their meta data. The query consists of one
+	or more terms separated by "&&". A term of the form path=value matches
+	JSON meta data that has the given value at the dot separated key path,
+	a term that only consists of a path matches JSON meta data that has
+	any value at the path. Any other meta data matches if it contains the
+	complete query.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  metaQueryName,
+			Usage: "the query expression, e.g. 'artist.name=satoshi'",
+		},
+		cli.BoolFlag{
+			Name: includeUniverseName,
+			Usage: "also search the assets known from the " +
+				"universe that aren't owned",
+		},
+	},
+	Action: searchMeta,
+}
+
+func searchMeta(ctx *cli.Context) error {
+	if !ctx.IsSet(metaQueryName) {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SearchAssetsByMeta(
+		ctxc, &taprpc.SearchAssetsByMetaRequest{
+			Query:           ctx.String(metaQueryName),
+			IncludeUniverse: ctx.Bool(includeUniverseName),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to search asset meta: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var keyDerivationCommand = cli.Command{
+	Name:  "keyderivation",
+	Usage: "show the key derivation paths of an owned asset",
+	Description: `
+	Show the key family, index and full BIP-0032 derivation path of the
+	script key and the anchor internal key of an owned asset. This can be
+	used by external signers to locate the keys of the asset.
+	`,
+	Action: keyDerivation,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID of the owned asset",
+		},
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key of the owned asset",
+		},
+	},
+}
+
+func keyDerivation(ctx *cli.Context) error {
+	switch {
+	case ctx.String(assetIDName) == "",
+		ctx.String(scriptKeyName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("unable to decode asset ID: %w", err)
+	}
+
+	scriptKey, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	req := &wrpc.GetKeyDerivationRequest{
+		AssetId:   assetID,
+		ScriptKey: scriptKey,
+	}
+	resp, err := client.GetKeyDerivation(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get key derivation: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportSigningStateCommand = cli.Command{
+	Name:  "exportsigningstate",
+	Usage: "export the key inventory of the wallet",
+	Description: `
+	Export all internal and script keys the wallet derived, along with the
+	highest key index used in each key family. The output can be imported
+	with the importsigningstate command into a node that was rebuilt with
+	the same lnd seed, to reclaim control of the keys.
+	`,
+	Action: exportSigningState,
+}
+
+func exportSigningState(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportSigningState(
+		ctxc, &wrpc.ExportSigningStateRequest{},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to export signing state: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var importSigningStateCommand = cli.Command{
+	Name:  "importsigningstate",
+	Usage: "import the key inventory exported by another node",
+	Description: `
+	Import the key inventory exported by the exportsigningstate command of
+	a node with the same lnd seed. All keys must be derivable by the
+	connected lnd node.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: signingStateFileName,
+			Usage: "the path to the JSON output of the " +
+				"exportsigningstate command",
+		},
+	},
+	Action: importSigningState,
+}
+
+func importSigningState(ctx *cli.Context) error {
+	filePath := tapcfg.CleanAndExpandPath(
+		ctx.String(signingStateFileName),
+	)
+	if ctx.NArg() != 0 || filePath == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	stateJSON, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to read signing state file: %w", err)
+	}
+
+	exported := &wrpc.ExportSigningStateResponse{}
+	err = taprpc.ProtoJSONUnmarshalOpts.Unmarshal(stateJSON, exported)
+	if err != nil {
+		return fmt.Errorf("unable to decode signing state: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportSigningState(
+		ctxc, &wrpc.ImportSigningStateRequest{
+			SigningState: exported.SigningState,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to import signing state: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listKeyRangesCommand = cli.Command{
+	Name:  "keyranges",
+	Usage: "list the key families used by the wallet",
+	Description: `
+	List the key families the wallet derived keys in or reserved key ranges
+	of, together with the number of keys used, the next unused key index
+	and the reserved key ranges of each family.
+	`,
+	Action: listKeyRanges,
+}
+
+func listKeyRanges(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListKeyRanges(ctxc, &wrpc.ListKeyRangesRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to list key ranges: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var reserveKeyRangeCommand = cli.Command{
+	Name:  "reservekeys",
+	Usage: "reserve a range of keys of a key family",
+	Description: `
+	Reserve a range of keys of a key family for a purpose outside of tapd
+	by deriving them from the connected lnd node, which then never hands
+	them out again.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  keyFamilyName,
+			Usage: "the key family to reserve the keys of",
+		},
+		cli.Uint64Flag{
+			Name:  numKeysName,
+			Usage: "the number of keys to reserve",
+		},
+		cli.StringFlag{
+			Name: purposeName,
+			Usage: "a description of what the keys are " +
+				"reserved for",
+		},
+	},
+	Action: reserveKeyRange,
+}
+
+func reserveKeyRange(ctx *cli.Context) error {
+	if ctx.NArg() != 0 || !ctx.IsSet(keyFamilyName) ||
+		ctx.Uint64(numKeysName) == 0 || ctx.String(purposeName) == "" {
+
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ReserveKeyRange(ctxc, &wrpc.ReserveKeyRangeRequest{
+		KeyFamily: uint32(ctx.Uint64(keyFamilyName)),
+		NumKeys:   uint32(ctx.Uint64(numKeysName)),
+		Purpose:   ctx.String(purposeName),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to reserve key range: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	"github.com/lightningnetwork/lnd/signal"
@@ -110,6 +111,46 @@ func stopDaemon(ctx *cli.Context) error {
 	return nil
 }
 
+var backupCommand = cli.Command{
+	Name:      "backup",
+	Usage:     "Create a backup of the daemon's database.",
+	ArgsUsage: "backup_file_path",
+	Description: `
+	Create a consistent backup of the database while the daemon keeps
+	running. The backup is written to the given path on the machine running
+	the daemon, which must not exist yet. A relative path is resolved
+	against the current directory. The size and SHA-256 checksum of the
+	backup are returned.
+
+	Proof files stored on disk are not part of the backup.`,
+	Action: backupDatabase,
+}
+
+func backupDatabase(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "backup")
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	filePath, err := filepath.Abs(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to resolve backup path: %w", err)
+	}
+
+	resp, err := client.BackupDatabase(ctxc, &taprpc.BackupDatabaseRequest{
+		FilePath: filePath,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var getInfoCommand = cli.Command{
 	Name:        "getinfo",
 	Usage:       "Get daemon info.",
@@ -131,3 +172,26 @@ func getInfo(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var getConfigCommand = cli.Command{
+	Name:  "getconfig",
+	Usage: "Get the effective daemon config.",
+	Description: "Returns the config the active daemon was started with, " +
+		"with sensitive values redacted.",
+	Action: getConfig,
+}
+
+func getConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.GetConfigRequest{}
+	resp, err := client.GetConfig(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -332,8 +332,10 @@ func main() {
 	app.Commands = []cli.Command{
 		stopCommand,
 		debugLevelCommand,
+		backupCommand,
 		profileSubCommand,
 		getInfoCommand,
+		getConfigCommand,
 	}
 	app.Commands = append(app.Commands, assetsCommands...)
 	app.Commands = append(app.Commands, addrCommands...)
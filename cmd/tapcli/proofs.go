@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	wrpc "github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -22,7 +25,17 @@ var proofCommands = []cli.Command{
 		Subcommands: []cli.Command{
 			verifyProofCommand,
 			decodeProofCommand,
+			checkCompatibilityCommand,
 			exportProofCommand,
+			exportProofsBatchCommand,
+			exportProofStreamCommand,
+			mergeProofsCommand,
+			splitCommitmentCommand,
+			anchorMerkleProofCommand,
+			deliveryReceiptsCommand,
+			pauseDeliveryCommand,
+			resumeDeliveryCommand,
+			deadLettersCommand,
 			proveOwnershipCommand,
 			verifyOwnershipCommand,
 		},
@@ -35,6 +48,9 @@ const (
 	proofAtDepthName      = "proof_at_depth"
 	withPrevWitnessesName = "latest_proof"
 	withMetaRevealName    = "meta_reveal"
+
+	implementationName        = "implementation"
+	implementationVersionName = "implementation_version"
 )
 
 var verifyProofCommand = cli.Command{
@@ -47,6 +63,11 @@ var verifyProofCommand = cli.Command{
 	prove that the creator of the proof can actually also spend the asset.
 	To verify ownership, use the "verifyownership" command with a separate
 	ownership proof.
+
+	A single mint or transfer proof can be verified as well, though the
+	witnesses of a transfer can only be checked as part of the full file.
+	The asset does not need to be owned by the node. If the verification
+	fails, the reason is reported in the failure_reason field.
 `,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -154,6 +175,68 @@ func decodeProof(ctx *cli.Context) error {
 	return nil
 }
 
+var checkCompatibilityCommand = cli.Command{
+	Name:      "checkcompat",
+	ShortName: "cc",
+	Usage:     "check the encoding of a Taproot Asset proof",
+	Description: `
+	Run a strict conformance check of the encoding of a taproot asset proof
+	or proof file, for example one created by another implementation. The
+	proof must be encoded canonically, contain all required records and no
+	unknown even records. The validity of the proof is not checked, use the
+	"verify" command for that.
+`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: proofPathName,
+			Usage: "the path to the proof file on disk; use the " +
+				"dash character (-) to read from stdin instead",
+		},
+		cli.StringFlag{
+			Name:  implementationName,
+			Usage: "the implementation that created the proof",
+		},
+		cli.StringFlag{
+			Name: implementationVersionName,
+			Usage: "the version of the implementation that " +
+				"created the proof",
+		},
+	},
+	Action: checkCompatibility,
+}
+
+func checkCompatibility(ctx *cli.Context) error {
+	if ctx.String(proofPathName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	filePath := lncfg.CleanAndExpandPath(ctx.String(proofPathName))
+	rawFile, err := readFile(filePath)
+	if err != nil {
+		return fmt.Errorf("unable to read proof file: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CheckProofCompatibility(
+		ctxc, &taprpc.CheckProofCompatibilityRequest{
+			RawProof:       rawFile,
+			Implementation: ctx.String(implementationName),
+			ImplementationVersion: ctx.String(
+				implementationVersionName,
+			),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to check proof: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var verifyOwnershipCommand = cli.Command{
 	Name:      "verifyownership",
 	ShortName: "vo",
@@ -163,6 +246,9 @@ var verifyOwnershipCommand = cli.Command{
 	full asset provenance, only the last state transition proof. But that
 	proof contains a signature to prove the asset can be spent by the
 	creator of the proof. This command verifies that signature.
+
+	If the proof was requested with a challenge, the same challenge must
+	be given, otherwise the verification fails.
 	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -171,6 +257,11 @@ var verifyOwnershipCommand = cli.Command{
 				"disk; use the dash character (-) to read " +
 				"from stdin instead",
 		},
+		cli.StringFlag{
+			Name: challengeName,
+			Usage: "(optional) the hex encoded 32-byte challenge " +
+				"the ownership proof was created for",
+		},
 	},
 	Action: verifyOwnershipProof,
 }
@@ -188,6 +279,11 @@ func verifyOwnershipProof(ctx *cli.Context) error {
 			err)
 	}
 
+	challenge, err := hex.DecodeString(ctx.String(challengeName))
+	if err != nil {
+		return fmt.Errorf("unable to decode challenge: %w", err)
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getWalletClient(ctx)
 	defer cleanUp()
@@ -195,6 +291,7 @@ func verifyOwnershipProof(ctx *cli.Context) error {
 	resp, err := client.VerifyAssetOwnership(
 		ctxc, &wrpc.VerifyAssetOwnershipRequest{
 			ProofWithWitness: rawFile,
+			Challenge:        challenge,
 		},
 	)
 	if err != nil {
@@ -207,6 +304,8 @@ func verifyOwnershipProof(ctx *cli.Context) error {
 
 const (
 	scriptKeyName = "script_key"
+
+	challengeName = "challenge"
 )
 
 var exportProofCommand = cli.Command{
@@ -280,6 +379,623 @@ func exportProof(ctx *cli.Context) error {
 	return nil
 }
 
+const (
+	outputDirName = "output_dir"
+)
+
+var exportProofsBatchCommand = cli.Command{
+	Name:      "exportbatch",
+	ShortName: "eb",
+	Usage:     "export the taproot asset proofs of multiple assets",
+	Description: `
+	Export the taproot asset proofs of all owned assets that match the
+	given asset IDs or group keys. If neither is specified, the proofs of
+	all owned assets are exported. If an output directory is specified,
+	each raw proof is written to
+	<output_dir>/<asset_id>/<script_key>_<anchor_txid>_<anchor_index>` +
+		proof.TaprootAssetsFileSuffix + `, otherwise the proofs are
+	printed in the JSON format. The anchor outpoint is part of the file
+	name, as the same script key can hold assets of the same ID in
+	multiple outputs.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: assetIDName,
+			Usage: "the asset ID of the assets to export; can be " +
+				"specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: groupKeyName,
+			Usage: "the group key of the grouped assets to " +
+				"export; can be specified multiple times",
+		},
+		cli.StringFlag{
+			Name: outputDirName,
+			Usage: "(optional) the directory to write the raw " +
+				"proofs to",
+		},
+	},
+	Action: exportProofsBatch,
+}
+
+func exportProofsBatch(ctx *cli.Context) error {
+	req := &taprpc.ExportProofsBatchRequest{}
+	for _, assetIDHex := range ctx.StringSlice(assetIDName) {
+		assetID, err := hex.DecodeString(assetIDHex)
+		if err != nil {
+			return fmt.Errorf("unable to decode asset ID: %w", err)
+		}
+		req.AssetIds = append(req.AssetIds, assetID)
+	}
+	for _, groupKeyHex := range ctx.StringSlice(groupKeyName) {
+		groupKey, err := hex.DecodeString(groupKeyHex)
+		if err != nil {
+			return fmt.Errorf("unable to decode group key: %w", err)
+		}
+		req.GroupKeys = append(req.GroupKeys, groupKey)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.ExportProofsBatch(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to export proof files: %w", err)
+	}
+
+	outputDir := ctx.String(outputDirName)
+	for {
+		resp, err := stream.Recv()
+		switch {
+		case err == io.EOF:
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to export proof files: %w",
+				err)
+		}
+
+		if outputDir == "" {
+			printRespJSON(resp)
+			continue
+		}
+
+		filePath := exportedProofPath(
+			lncfg.CleanAndExpandPath(outputDir), resp,
+		)
+		if err := writeToFile(filePath, resp.RawProofFile); err != nil {
+			return err
+		}
+	}
+}
+
+// exportedProofPath returns the path within the output directory the given
+// exported proof file is written to. Besides the asset ID and script key, the
+// path contains the anchor outpoint of the asset, as the same script key can
+// hold assets of the same ID in multiple outputs.
+func exportedProofPath(outputDir string,
+	proofFile *taprpc.ExportedProofFile) string {
+
+	// The colon of the outpoint isn't allowed in file names on all
+	// platforms.
+	outpoint := strings.ReplaceAll(proofFile.AnchorOutpoint, ":", "_")
+
+	return filepath.Join(
+		outputDir, hex.EncodeToString(proofFile.AssetId),
+		fmt.Sprintf("%x_%s%s", proofFile.ScriptKey, outpoint,
+			proof.TaprootAssetsFileSuffix),
+	)
+}
+
+const (
+	startIndexName = "start_index"
+	endIndexName   = "end_index"
+)
+
+var exportProofStreamCommand = cli.Command{
+	Name:      "exportstream",
+	ShortName: "es",
+	Usage:     "export a taproot asset proof one proof at a time",
+	Description: `
+	Export a taproot asset proof like the "export" command, but receive
+	the proof file one proof at a time and reassemble it locally. This
+	avoids transferring large proof files in a single response. If a
+	range of proof indexes is given, only the proofs within that range
+	are exported, which results in a shorter proof file that starts at
+	the proof with the start index.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID of the asset to export",
+		},
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key of the asset to export",
+		},
+		cli.Uint64Flag{
+			Name: startIndexName,
+			Usage: "(optional) the index of the first proof to " +
+				"export, with 0 being the genesis proof",
+		},
+		cli.Uint64Flag{
+			Name: endIndexName,
+			Usage: "(optional) the index after the last proof to " +
+				"export; if not set, all proofs up to the " +
+				"latest one are exported",
+		},
+		cli.StringFlag{
+			Name: proofPathName,
+			Usage: "(optional) the file to write the raw proof " +
+				"file to instead of printing it in the JSON " +
+				"format",
+		},
+	},
+	Action: exportProofStream,
+}
+
+func exportProofStream(ctx *cli.Context) error {
+	switch {
+	case ctx.String(scriptKeyName) == "",
+		ctx.String(assetIDName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	scriptKeyBytes, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("unable to decode asset ID: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.ExportProofStream(
+		ctxc, &taprpc.ExportProofStreamRequest{
+			AssetId:    assetID,
+			ScriptKey:  scriptKeyBytes,
+			StartIndex: uint32(ctx.Uint64(startIndexName)),
+			EndIndex:   uint32(ctx.Uint64(endIndexName)),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to export proof file: %w", err)
+	}
+
+	// We append the streamed proofs to a new file, which also recreates
+	// the chained hashes of the proofs.
+	var proofFile *proof.File
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to export proof file: %w",
+				err)
+		}
+
+		if proofFile == nil {
+			proofFile = proof.NewEmptyFile(
+				proof.Version(chunk.FileVersion),
+			)
+		}
+
+		if err := proofFile.AppendRawProof(chunk.RawProof); err != nil {
+			return fmt.Errorf("unable to append proof %d: %w",
+				chunk.ProofIndex, err)
+		}
+	}
+
+	if proofFile == nil {
+		return fmt.Errorf("no proofs exported")
+	}
+
+	var buf bytes.Buffer
+	if err := proofFile.Encode(&buf); err != nil {
+		return fmt.Errorf("unable to encode proof file: %w", err)
+	}
+
+	if ctx.String(proofPathName) != "" {
+		filePath := lncfg.CleanAndExpandPath(ctx.String(proofPathName))
+		return writeToFile(filePath, buf.Bytes())
+	}
+
+	printRespJSON(&taprpc.ProofFile{
+		RawProofFile: buf.Bytes(),
+	})
+	return nil
+}
+
+const (
+	prefixProofPathName = "prefix_proof_file"
+	suffixProofPathName = "suffix_proof_file"
+)
+
+var mergeProofsCommand = cli.Command{
+	Name:      "merge",
+	ShortName: "m",
+	Usage:     "merge two taproot asset proof files",
+	Description: `
+	Merge two taproot asset proof files into a single proof file. The first
+	proof of the suffix file must spend the asset output of the last proof
+	of the prefix file, otherwise the files are rejected.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: prefixProofPathName,
+			Usage: "the path to the proof file on disk that " +
+				"contains the beginning of the proof chain",
+		},
+		cli.StringFlag{
+			Name: suffixProofPathName,
+			Usage: "the path to the proof file on disk that " +
+				"continues the proof chain of the prefix",
+		},
+		cli.StringFlag{
+			Name: proofPathName,
+			Usage: "(optional) the file to write the merged raw " +
+				"proof to; use the dash character (-) to " +
+				"write the raw binary proof to stdout " +
+				"instead of the default JSON format",
+		},
+	},
+	Action: mergeProofs,
+}
+
+func mergeProofs(ctx *cli.Context) error {
+	switch {
+	case ctx.String(prefixProofPathName) == "",
+		ctx.String(suffixProofPathName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	prefixPath := lncfg.CleanAndExpandPath(ctx.String(prefixProofPathName))
+	prefixFile, err := readFile(prefixPath)
+	if err != nil {
+		return fmt.Errorf("unable to read prefix proof file: %w", err)
+	}
+
+	suffixPath := lncfg.CleanAndExpandPath(ctx.String(suffixProofPathName))
+	suffixFile, err := readFile(suffixPath)
+	if err != nil {
+		return fmt.Errorf("unable to read suffix proof file: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.MergeProofFilesRequest{
+		PrefixProofFile: prefixFile,
+		SuffixProofFile: suffixFile,
+	}
+	resp, err := client.MergeProofFiles(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to merge proof files: %w", err)
+	}
+
+	if ctx.String(proofPathName) != "" {
+		filePath := lncfg.CleanAndExpandPath(ctx.String(proofPathName))
+		return writeToFile(filePath, resp.RawProofFile)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var splitCommitmentCommand = cli.Command{
+	Name:      "splitcommitment",
+	ShortName: "sc",
+	Usage:     "inspect the split commitment of a transfer output",
+	Description: `
+	Decode the split commitment of the asset in the given transfer output
+	from the stored proof. For a split asset, the inclusion proof of the
+	split in the split commitment tree is shown together with the split
+	root it resolves to. For the root asset of a split, only the split root
+	is shown.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: outpointName,
+			Usage: "the anchor outpoint of the transfer output, " +
+				"in the form txid:index",
+		},
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key of the asset in the output",
+		},
+		cli.StringFlag{
+			Name: assetIDName,
+			Usage: "(optional) the asset ID of the asset in the " +
+				"output",
+		},
+	},
+	Action: getSplitCommitment,
+}
+
+func getSplitCommitment(ctx *cli.Context) error {
+	switch {
+	case ctx.String(outpointName) == "",
+		ctx.String(scriptKeyName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	scriptKeyBytes, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("unable to decode asset ID: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.GetSplitCommitmentRequest{
+		AnchorOutpoint: ctx.String(outpointName),
+		ScriptKey:      scriptKeyBytes,
+		AssetId:        assetID,
+	}
+	resp, err := client.GetSplitCommitment(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get split commitment: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var anchorMerkleProofCommand = cli.Command{
+	Name:      "merkleproof",
+	ShortName: "mp",
+	Usage: "show the merkle proof of the anchor transaction of a " +
+		"transfer output",
+	Description: `
+	Show the merkle proof of the anchor transaction of the given transfer
+	output in its confirming block, as embedded in the stored proof,
+	together with the block header. A verifier that follows the header
+	chain can use them to confirm the inclusion of the anchor transaction
+	independently.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: outpointName,
+			Usage: "the anchor outpoint of the transfer output, " +
+				"in the form txid:index",
+		},
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key of the asset in the output",
+		},
+		cli.StringFlag{
+			Name: assetIDName,
+			Usage: "(optional) the asset ID of the asset in the " +
+				"output",
+		},
+	},
+	Action: getAnchorMerkleProof,
+}
+
+func getAnchorMerkleProof(ctx *cli.Context) error {
+	switch {
+	case ctx.String(outpointName) == "",
+		ctx.String(scriptKeyName) == "":
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	scriptKeyBytes, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	assetID, err := hex.DecodeString(ctx.String(assetIDName))
+	if err != nil {
+		return fmt.Errorf("unable to decode asset ID: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.GetAnchorMerkleProofRequest{
+		AnchorOutpoint: ctx.String(outpointName),
+		ScriptKey:      scriptKeyBytes,
+		AssetId:        assetID,
+	}
+	resp, err := client.GetAnchorMerkleProof(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to get anchor merkle proof: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var deliveryReceiptsCommand = cli.Command{
+	Name:  "receipts",
+	Usage: "list the delivery receipts for proofs sent to a script key",
+	Description: `
+	List the delivery receipts that receivers returned for proofs sent to
+	the given script key through a proof courier. Each receipt is signed by
+	the key that controls the script key.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  scriptKeyName,
+			Usage: "the script key the proofs were sent to",
+		},
+	},
+	Action: listDeliveryReceipts,
+}
+
+func listDeliveryReceipts(ctx *cli.Context) error {
+	if ctx.String(scriptKeyName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	scriptKeyBytes, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &taprpc.ListDeliveryReceiptsRequest{
+		ScriptKey: scriptKeyBytes,
+	}
+	resp, err := client.ListDeliveryReceipts(ctxc, req)
+	if err != nil {
+		return fmt.Errorf("unable to list delivery receipts: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// deliveryFlags are the flags that identify the transfer output of a proof
+// delivery.
+var deliveryFlags = []cli.Flag{
+	cli.StringFlag{
+		Name: outpointName,
+		Usage: "the anchor outpoint of the transfer output, in the " +
+			"form txid:index",
+	},
+	cli.StringFlag{
+		Name:  scriptKeyName,
+		Usage: "the script key of the transfer output",
+	},
+}
+
+// parseDeliveryFlags parses the anchor outpoint and script key of the transfer
+// output of a proof delivery.
+func parseDeliveryFlags(ctx *cli.Context) (string, []byte, error) {
+	scriptKeyBytes, err := hex.DecodeString(ctx.String(scriptKeyName))
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to decode script key: %w",
+			err)
+	}
+
+	return ctx.String(outpointName), scriptKeyBytes, nil
+}
+
+var pauseDeliveryCommand = cli.Command{
+	Name:  "pausedelivery",
+	Usage: "pause the delivery of the proof for a transfer output",
+	Description: `
+	Pause the delivery of the proof for the given transfer output. The
+	proof courier stops its delivery attempts until the delivery is resumed
+	with the "resumedelivery" command. Paused deliveries are resumed when
+	the daemon restarts.
+	`,
+	Flags:  deliveryFlags,
+	Action: pauseDelivery,
+}
+
+func pauseDelivery(ctx *cli.Context) error {
+	if ctx.String(outpointName) == "" || ctx.String(scriptKeyName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	outpoint, scriptKey, err := parseDeliveryFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.PauseProofDelivery(
+		ctxc, &taprpc.PauseProofDeliveryRequest{
+			AnchorOutpoint: outpoint,
+			ScriptKey:      scriptKey,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to pause proof delivery: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var resumeDeliveryCommand = cli.Command{
+	Name:  "resumedelivery",
+	Usage: "resume the paused delivery of the proof for a transfer output",
+	Description: `
+	Resume the paused delivery of the proof for the given transfer output.
+	The proof courier starts over with a fresh set of delivery attempts.
+	`,
+	Flags:  deliveryFlags,
+	Action: resumeDelivery,
+}
+
+func resumeDelivery(ctx *cli.Context) error {
+	if ctx.String(outpointName) == "" || ctx.String(scriptKeyName) == "" {
+		return cli.ShowSubcommandHelp(ctx)
+	}
+
+	outpoint, scriptKey, err := parseDeliveryFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ResumeProofDelivery(
+		ctxc, &taprpc.ResumeProofDeliveryRequest{
+			AnchorOutpoint: outpoint,
+			ScriptKey:      scriptKey,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to resume proof delivery: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var deadLettersCommand = cli.Command{
+	Name:  "deadletters",
+	Usage: "list the inbound proofs that couldn't be imported",
+	Description: `
+	List the inbound proofs that couldn't be imported after all retries.
+	A dead letter stays listed until its proof is imported.
+	`,
+	Action: listDeadLetters,
+}
+
+func listDeadLetters(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListDeadLetterProofs(
+		ctxc, &taprpc.ListDeadLetterProofsRequest{},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to list dead letter proofs: %w", err)
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var proveOwnershipCommand = cli.Command{
 	Name:      "proveownership",
 	ShortName: "po",
@@ -290,6 +1006,10 @@ var proveOwnershipCommand = cli.Command{
 	contains a signature created with the script key of the asset. This
 	signature proves that the asset can be spent by the creator of the
 	ownership proof.
+
+	To prevent the proof from being replayed, the verifier should send a
+	random 32-byte challenge to the prover, which is committed to in the
+	signature. The proof can then only be verified with that challenge.
 `,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -302,6 +1022,11 @@ var proveOwnershipCommand = cli.Command{
 			Usage: "the script key of the asset to prove " +
 				"ownership of",
 		},
+		cli.StringFlag{
+			Name: challengeName,
+			Usage: "(optional) the hex encoded 32-byte challenge " +
+				"chosen by the verifier",
+		},
 		cli.StringFlag{
 			Name: proofPathName,
 			Usage: "(optional) the file to write the ownership " +
@@ -330,6 +1055,11 @@ func proveOwnership(ctx *cli.Context) error {
 		return fmt.Errorf("unable to decode asset ID: %w", err)
 	}
 
+	challenge, err := hex.DecodeString(ctx.String(challengeName))
+	if err != nil {
+		return fmt.Errorf("unable to decode challenge: %w", err)
+	}
+
 	ctxc := getContext()
 	client, cleanUp := getWalletClient(ctx)
 	defer cleanUp()
@@ -338,6 +1068,7 @@ func proveOwnership(ctx *cli.Context) error {
 		ctxc, &wrpc.ProveAssetOwnershipRequest{
 			AssetId:   assetID,
 			ScriptKey: scriptKeyBytes,
+			Challenge: challenge,
 		},
 	)
 	if err != nil {
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExportedProofPath tests that the proof files of assets with the same ID
+// and script key in different outputs are written to different paths.
+func TestExportedProofPath(t *testing.T) {
+	t.Parallel()
+
+	const (
+		txid1 = "6e8b5e1c0f3a4b7d2c9e1f0a8b7c6d5e4f3a2b1c0d9e8f7a6b5c" +
+			"4d3e2f1a0b9c"
+		txid2 = "0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c" +
+			"4d3e2f1a6e8b"
+	)
+
+	assetID := []byte{0x01, 0x02}
+	scriptKey := []byte{0x03, 0x04}
+	newProofFile := func(outpoint string) *taprpc.ExportedProofFile {
+		return &taprpc.ExportedProofFile{
+			AssetId:        assetID,
+			ScriptKey:      scriptKey,
+			AnchorOutpoint: outpoint,
+		}
+	}
+
+	outputDir := t.TempDir()
+	paths := []string{
+		exportedProofPath(outputDir, newProofFile(txid1+":0")),
+		exportedProofPath(outputDir, newProofFile(txid1+":1")),
+		exportedProofPath(outputDir, newProofFile(txid2+":0")),
+	}
+
+	require.Equal(t, filepath.Join(
+		outputDir, "0102",
+		"0304_"+txid1+"_0"+proof.TaprootAssetsFileSuffix,
+	), paths[0])
+
+	// Each proof file must end up in its own file.
+	unique := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		require.Equal(t, filepath.Join(outputDir, "0102"),
+			filepath.Dir(path))
+
+		unique[path] = struct{}{}
+	}
+	require.Len(t, unique, len(paths))
+}
@@ -17,6 +17,8 @@ import (
 
 const (
 	proofTypeName = "proof_type"
+
+	authoritativeName = "authoritative"
 )
 
 func getUniverseClient(ctx *cli.Context) (universerpc.UniverseClient, func()) {
@@ -39,10 +41,12 @@ var universeCommands = []cli.Command{
 		Subcommands: []cli.Command{
 			universeRootsCommand,
 			universeDeleteRootCommand,
+			universePruneCommand,
 			universeLeavesCommand,
 			universeKeysCommand,
 			universeProofCommand,
 			universeSyncCommand,
+			universeDefaultCommand,
 			universeFederationCommand,
 			universeInfoCommand,
 			universeStatsCommand,
@@ -70,6 +74,11 @@ var universeRootsCommand = cli.Command{
 				"either 'issuance' or 'transfer'",
 			Value: universe.ProofTypeIssuance.String(),
 		},
+		cli.BoolFlag{
+			Name: authoritativeName,
+			Usage: "only list the roots of the universes this " +
+				"server is the issuance origin of",
+		},
 	},
 	Action: universeRoots,
 }
@@ -137,6 +146,42 @@ func universeRoots(ctx *cli.Context) error {
 		return err
 	}
 
+	// The authoritative roots are always listed in full, optionally
+	// filtered by the proof type.
+	if ctx.Bool(authoritativeName) {
+		if universeID != nil {
+			return fmt.Errorf("asset_id and group_key can't be " +
+				"combined with --authoritative")
+		}
+
+		var proofType universerpc.ProofType
+		if ctx.IsSet(proofTypeName) {
+			parsedType, err := universe.ParseStrProofType(
+				ctx.String(proofTypeName),
+			)
+			if err != nil {
+				return err
+			}
+
+			proofType, err = tap.MarshalUniProofType(parsedType)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp, err := client.ListAuthoritativeAssets(
+			ctxc, &universerpc.ListAuthoritativeAssetsRequest{
+				ProofType: proofType,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+		return nil
+	}
+
 	// If neither an asset ID or group key is specified, then we'll query
 	// for all the known universe roots.
 	if universeID == nil {
@@ -210,6 +255,70 @@ func deleteUniverseRoot(ctx *cli.Context) error {
 	return nil
 }
 
+const (
+	beforeHeightName = "before_height"
+)
+
+var universePruneCommand = cli.Command{
+	Name:      "prune",
+	ShortName: "p",
+	Usage:     "remove old or unwanted proofs from the universe",
+	Description: `
+	Remove the proofs of a given asset universe, or of all known universes,
+	that were confirmed in a block below the given height. If no height is
+	given, all proofs of the specified universe are removed. The universe
+	roots are updated accordingly, so the pruned proofs are no longer
+	served to syncing peers.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID of the universe to prune",
+		},
+		cli.StringFlag{
+			Name:  groupKeyName,
+			Usage: "the group key of the universe to prune",
+		},
+		cli.StringFlag{
+			Name: proofTypeName,
+			Usage: "the type of proof to prune, either " +
+				"'issuance', 'transfer' or 'unspecified' for " +
+				"both",
+			Value: universe.ProofTypeUnspecified.String(),
+		},
+		cli.Uint64Flag{
+			Name: beforeHeightName,
+			Usage: "if set, only the proofs confirmed in a block " +
+				"below this height are removed",
+		},
+	},
+	Action: pruneUniverse,
+}
+
+func pruneUniverse(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	universeID, err := parseUniverseID(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	req := &universerpc.PruneUniverseRequest{
+		Id:           universeID,
+		BeforeHeight: uint32(ctx.Uint64(beforeHeightName)),
+	}
+
+	resp, err := client.PruneUniverse(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var universeKeysCommand = cli.Command{
 	Name:      "keys",
 	ShortName: "k",
@@ -510,7 +619,8 @@ var universeSyncCommand = cli.Command{
 		cli.StringFlag{
 			Name: universeHostName,
 			Usage: "the host:port or just host of the remote " +
-				"universe",
+				"universe; if not set, the default universe " +
+				"server is used",
 		},
 		cli.StringFlag{
 			Name:  assetIDName,
@@ -522,14 +632,12 @@ var universeSyncCommand = cli.Command{
 		},
 	},
 	Action: universeSync,
+	Subcommands: []cli.Command{
+		universeSyncEstimateCommand,
+	},
 }
 
 func universeSync(ctx *cli.Context) error {
-	switch {
-	case ctx.String(universeHostName) == "":
-		return cli.ShowSubcommandHelp(ctx)
-	}
-
 	universeID, err := parseUniverseID(ctx, false)
 	if err != nil {
 		return err
@@ -560,6 +668,140 @@ func universeSync(ctx *cli.Context) error {
 	return nil
 }
 
+var universeSyncEstimateCommand = cli.Command{
+	Name:      "estimate",
+	ShortName: "e",
+	Usage:     "estimate the duration and data volume of a sync",
+	Description: `
+	Estimate how much data a sync with a remote Universe would transfer and
+	how long it would take, without actually syncing. The estimate is based
+	on the number of leaves missing locally and a small sample of their
+	proofs, which is fetched from the remote Universe.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: universeHostName,
+			Usage: "the host:port or just host of the remote " +
+				"universe; if not set, the default universe " +
+				"server is used",
+		},
+		cli.StringFlag{
+			Name:  assetIDName,
+			Usage: "the asset ID to estimate the sync for",
+		},
+		cli.StringFlag{
+			Name:  groupKeyName,
+			Usage: "the group key to estimate the sync for",
+		},
+	},
+	Action: universeSyncEstimate,
+}
+
+func universeSyncEstimate(ctx *cli.Context) error {
+	universeID, err := parseUniverseID(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	var targets []*universerpc.SyncTarget
+	if universeID != nil {
+		targets = append(targets, &universerpc.SyncTarget{
+			Id: universeID,
+		})
+	}
+
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.EstimateSync(
+		ctxc, &universerpc.EstimateSyncRequest{
+			UniverseHost: ctx.String(universeHostName),
+			SyncTargets:  targets,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var universeDefaultCommand = cli.Command{
+	Name:  "default",
+	Usage: "manage the default Universe server to sync with",
+	Description: `
+	Manage the default Universe server. This server is used for syncs that
+	don't specify a server, and for the periodic sync if there are no
+	Federation servers.
+	`,
+	Subcommands: []cli.Command{
+		universeDefaultSetCommand,
+		universeDefaultGetCommand,
+	},
+}
+
+var universeDefaultSetCommand = cli.Command{
+	Name:      "set",
+	ShortName: "s",
+	Description: `
+	Set the default Universe server. The server must be reachable to be
+	accepted. The default set with this command isn't persisted across
+	restarts, use the universe.default-sync-server option for that.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: universeHostName,
+			Usage: "the address for the universe server, eg: " +
+				"testnet.mydomain.com:10029; if empty, the " +
+				"default is cleared",
+		},
+	},
+	Action: universeDefaultSet,
+}
+
+func universeDefaultSet(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetDefaultUniverse(
+		ctxc, &universerpc.SetDefaultUniverseRequest{
+			UniverseHost: ctx.String(universeHostName),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var universeDefaultGetCommand = cli.Command{
+	Name:        "get",
+	ShortName:   "g",
+	Description: "Show the default Universe server",
+	Action:      universeDefaultGet,
+}
+
+func universeDefaultGet(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetDefaultUniverse(
+		ctxc, &universerpc.GetDefaultUniverseRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var universeFederationCommand = cli.Command{
 	Name:      "federation",
 	ShortName: "f",
@@ -574,6 +816,8 @@ var universeFederationCommand = cli.Command{
 		universeFederationListCommand,
 		universeFederationAddCommand,
 		universeFederationDelCommand,
+		universeFederationBacklogCommand,
+		universeFederationSyncStatusCommand,
 	},
 }
 
@@ -698,6 +942,78 @@ func universeFederationDel(ctx *cli.Context) error {
 	return nil
 }
 
+var universeFederationBacklogCommand = cli.Command{
+	Name:      "backlog",
+	ShortName: "b",
+	Description: `
+	List the proof pushes to each Federation server that failed and are
+	queued to be retried.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: universeHostName,
+			Usage: "only list the backlog of the server with this " +
+				"host",
+		},
+	},
+	Action: universeFederationBacklog,
+}
+
+func universeFederationBacklog(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListFederationPushBacklog(
+		ctxc, &universerpc.ListFederationPushBacklogRequest{
+			ServerHost: ctx.String(universeHostName),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var universeFederationSyncStatusCommand = cli.Command{
+	Name:      "syncstatus",
+	ShortName: "s",
+	Description: `
+	Show the sync status of each Federation server: the last successful
+	sync, the number of universe roots the server has that the local
+	Universe server doesn't and an estimate of how far the local Universe
+	server lags behind it.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: universeHostName,
+			Usage: "only show the sync status of the server with " +
+				"this host",
+		},
+	},
+	Action: universeFederationSyncStatus,
+}
+
+func universeFederationSyncStatus(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getUniverseClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetFederationSyncStatus(
+		ctxc, &universerpc.GetFederationSyncStatusRequest{
+			ServerHost: ctx.String(universeHostName),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var universeInfoCommand = cli.Command{
 	Name:      "info",
 	ShortName: "i",
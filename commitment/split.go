@@ -147,6 +147,12 @@ func NewSplitCommitment(ctx context.Context, inputs []SplitCommitmentInput,
 	totalInputAmount := uint64(0)
 	for idx := range inputs {
 		input := inputs[idx]
+		err := mssmt.CheckSumOverflowUint64(
+			totalInputAmount, input.Asset.Amount,
+		)
+		if err != nil {
+			return nil, ErrInvalidSplitAmount
+		}
 		totalInputAmount += input.Asset.Amount
 	}
 
@@ -14,6 +14,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/signal"
@@ -72,6 +73,14 @@ type DatabaseConfig struct {
 	Multiverse *tapdb.MultiverseStore
 
 	FederationDB *tapdb.UniverseFederationDB
+
+	// SendEvents persists the events of the asset sending process, so
+	// they can be replayed to subscribers.
+	SendEvents *tapdb.SendEventDB
+
+	// Backuper is used to create consistent backups of the database while
+	// the daemon is running.
+	Backuper tapdb.DatabaseBackuper
 }
 
 // Config is the main config of the Taproot Assets server.
@@ -96,12 +105,22 @@ type Config struct {
 
 	AssetCustodian *tapgarden.Custodian
 
+	// GroupKeyRotator is used to rotate the key that authorizes the
+	// issuance of assets into an asset group.
+	GroupKeyRotator *tapgarden.GroupKeyRotator
+
 	ChainBridge tapgarden.ChainBridge
 
 	AddrBook *address.Book
 
 	DefaultProofCourierAddr *url.URL
 
+	// MaxAddrAmount is the maximum amount a Taproot Asset address can
+	// request to be created, decoded or sent to.
+	MaxAddrAmount uint64
+
+	PausedProofDeliveries *proof.PausedDeliveries
+
 	ProofArchive proof.Archiver
 
 	AssetWallet tapfreighter.Wallet
@@ -124,6 +143,26 @@ type Config struct {
 	// This applies to federation syncing as well as RPC insert and query.
 	UniversePublicAccess bool
 
+	// UniverseReadOnly is a flag which, if true, causes the Universe server
+	// to reject any proofs inserted or pushed through RPC. Proofs of
+	// locally minted assets are still added, and all read and sync calls
+	// are served as usual.
+	UniverseReadOnly bool
+
+	// EffectiveConfig is the flattened set of all config options the
+	// daemon was started with, after validation. Sensitive values are
+	// redacted.
+	EffectiveConfig map[string]string
+
+	// SendEventRetention is the duration for which the events of the asset
+	// sending process are persisted for replay. If zero, the events aren't
+	// persisted and can't be replayed.
+	SendEventRetention time.Duration
+
+	// Webhook is the config of the webhook the send and receive asset
+	// events are delivered to. If nil, no webhook is notified.
+	Webhook *webhook.Config
+
 	Prometheus monitoring.PrometheusConfig
 
 	// LogWriter is the root logger that all of the daemon's subloggers are
@@ -20,8 +20,19 @@ type ErrFunc[V any] func(context.Context, V) error
 // the first time a function passed returns a non-nil error.  Returns the first
 // non-nil error (if any).
 func ParSlice[V any](ctx context.Context, s []V, f ErrFunc[V]) error {
+	return ParSliceLimit(ctx, runtime.NumCPU(), s, f)
+}
+
+// ParSliceLimit is identical to ParSlice, but limits the number of active
+// goroutines to the given limit instead of the number of CPUs. A limit that
+// isn't positive means the function is executed for all elements at once.
+func ParSliceLimit[V any](ctx context.Context, limit int, s []V,
+	f ErrFunc[V]) error {
+
 	errGroup, ctx := errgroup.WithContext(ctx)
-	errGroup.SetLimit(runtime.NumCPU())
+	if limit > 0 {
+		errGroup.SetLimit(limit)
+	}
 
 	for _, v := range s {
 		v := v
@@ -32,3 +43,40 @@ func ParSlice[V any](ctx context.Context, s []V, f ErrFunc[V]) error {
 
 	return errGroup.Wait()
 }
+
+// ParMapLimit applies the given function to each element of a slice in
+// parallel, limiting the number of active goroutines to the given limit like
+// ParSliceLimit does. The results are returned in the order of the elements
+// of the slice, independent of the order the goroutines finish in. Returns the
+// first non-nil error (if any).
+func ParMapLimit[V, R any](ctx context.Context, limit int, s []V,
+	f func(context.Context, V) (R, error)) ([]R, error) {
+
+	results := make([]R, len(s))
+	errGroup, ctx := errgroup.WithContext(ctx)
+	if limit > 0 {
+		errGroup.SetLimit(limit)
+	}
+
+	for i, v := range s {
+		i, v := i, v
+		errGroup.Go(func() error {
+			result, err := f(ctx, v)
+			if err != nil {
+				return err
+			}
+
+			// Each goroutine writes to its own index only, so no
+			// further synchronization is needed.
+			results[i] = result
+
+			return nil
+		})
+	}
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -3,7 +3,9 @@ package fn
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -57,3 +59,57 @@ func TestParSlice(t *testing.T) {
 		})
 	}
 }
+
+// TestParMapLimit tests that the results of ParMapLimit are returned in the
+// order of the input slice and that an error is returned if any call fails.
+func TestParMapLimit(t *testing.T) {
+	t.Parallel()
+
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = i
+	}
+
+	var numActive, maxActive atomic.Int32
+	square := func(_ context.Context, v int) (int, error) {
+		active := numActive.Add(1)
+		defer numActive.Add(-1)
+
+		for {
+			prevMax := maxActive.Load()
+			if active <= prevMax ||
+				maxActive.CompareAndSwap(prevMax, active) {
+
+				break
+			}
+		}
+
+		// Make the goroutines finish in a different order than they
+		// were started in.
+		time.Sleep(time.Duration(len(values)-v) * time.Microsecond)
+
+		return v * v, nil
+	}
+
+	const limit = 4
+	results, err := ParMapLimit(context.Background(), limit, values, square)
+	require.NoError(t, err)
+	require.Len(t, results, len(values))
+	for i, result := range results {
+		require.Equal(t, i*i, result)
+	}
+	require.LessOrEqual(t, maxActive.Load(), int32(limit))
+
+	errFail := errors.New("fail")
+	_, err = ParMapLimit(
+		context.Background(), limit, values,
+		func(_ context.Context, v int) (int, error) {
+			if v == 42 {
+				return 0, errFail
+			}
+
+			return v, nil
+		},
+	)
+	require.ErrorIs(t, err, errFail)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 
+	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	wrpc "github.com/lightninglabs/taproot-assets/taprpc/assetwalletrpc"
@@ -142,6 +143,44 @@ func testAddresses(t *harnessTest) {
 		)
 		require.NoError(t.t, err)
 		require.True(t.t, verifyResp.ValidProof)
+
+		// An ownership proof created for a challenge can only be
+		// verified with that exact challenge.
+		challenge := test.RandBytes(32)
+		proveResp, err = secondTapd.ProveAssetOwnership(
+			ctxt, &wrpc.ProveAssetOwnershipRequest{
+				AssetId:   receiverAddr.AssetId,
+				ScriptKey: receiverAddr.ScriptKey,
+				Challenge: challenge,
+			},
+		)
+		require.NoError(t.t, err)
+
+		verifyResp, err = t.tapd.VerifyAssetOwnership(
+			ctxt, &wrpc.VerifyAssetOwnershipRequest{
+				ProofWithWitness: proveResp.ProofWithWitness,
+				Challenge:        challenge,
+			},
+		)
+		require.NoError(t.t, err)
+		require.True(t.t, verifyResp.ValidProof)
+
+		// Replaying the proof without the challenge or with a
+		// different one fails.
+		_, err = t.tapd.VerifyAssetOwnership(
+			ctxt, &wrpc.VerifyAssetOwnershipRequest{
+				ProofWithWitness: proveResp.ProofWithWitness,
+			},
+		)
+		require.ErrorContains(t.t, err, "invalid challenge witness")
+
+		_, err = t.tapd.VerifyAssetOwnership(
+			ctxt, &wrpc.VerifyAssetOwnershipRequest{
+				ProofWithWitness: proveResp.ProofWithWitness,
+				Challenge:        test.RandBytes(32),
+			},
+		)
+		require.ErrorContains(t.t, err, "invalid challenge witness")
 	}
 }
 
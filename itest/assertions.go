@@ -534,6 +534,9 @@ func AssertAddrCreated(t *testing.T, client taprpc.TaprootAssetsClient,
 
 	// Does the decoded address still show everything correctly?
 	AssertAddr(t, expected, decoded)
+	require.Equal(t, actual.Amount, decoded.Amount)
+	require.Equal(t, actual.AllowAnyAmount, decoded.AllowAnyAmount)
+	require.Equal(t, actual.MinAmount, decoded.MinAmount)
 
 	allAddrs, err := client.QueryAddrs(ctxt, &taprpc.QueryAddrRequest{})
 	require.NoError(t, err)
@@ -551,6 +554,42 @@ func AssertAddrCreated(t *testing.T, client taprpc.TaprootAssetsClient,
 
 	// Does the address in the list contain all information we expect?
 	AssertAddr(t, expected, rpcAddr)
+	require.Equal(t, actual.AllowAnyAmount, rpcAddr.AllowAnyAmount)
+	require.Equal(t, actual.MinAmount, rpcAddr.MinAmount)
+}
+
+// AssertBurnTransferOutput makes sure the given burn transfer is listed with a
+// single burn output of the given amount.
+func AssertBurnTransferOutput(t *testing.T, client taprpc.TaprootAssetsClient,
+	burnTransfer *taprpc.AssetTransfer, burnAmt uint64) {
+
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout)
+	defer cancel()
+
+	resp, err := client.ListTransfers(ctxt, &taprpc.ListTransfersRequest{})
+	require.NoError(t, err)
+
+	var (
+		anchorTxHash = burnTransfer.AnchorTxHash
+		transfer     *taprpc.AssetTransfer
+	)
+	for _, candidate := range resp.Transfers {
+		if bytes.Equal(candidate.AnchorTxHash, anchorTxHash) {
+			transfer = candidate
+			break
+		}
+	}
+	require.NotNil(t, transfer)
+
+	var burnOutputs []*taprpc.TransferOutput
+	for _, out := range transfer.Outputs {
+		if out.OutputType == taprpc.OutputType_OUTPUT_TYPE_BURN {
+			burnOutputs = append(burnOutputs, out)
+		}
+	}
+	require.Len(t, burnOutputs, 1)
+	require.Equal(t, burnAmt, burnOutputs[0].Amount)
 }
 
 // AssertAddrEvent makes sure the given address was detected by the given
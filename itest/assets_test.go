@@ -1,6 +1,7 @@
 package itest
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"net/http"
@@ -136,6 +137,176 @@ func testMintAssets(t *harnessTest) {
 	transferAssetProofs(t, t.tapd, secondTapd, allAssets, false)
 }
 
+// testSubscribeAssets tests that the asset subscription streams minted assets
+// and assets that were spent.
+func testSubscribeAssets(t *harnessTest) {
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout*2)
+	defer cancel()
+
+	stream, err := t.tapd.SubscribeAssets(
+		ctxt, &taprpc.SubscribeAssetsRequest{},
+	)
+	require.NoError(t.t, err)
+
+	snapshot, err := stream.Recv()
+	require.NoError(t.t, err)
+	require.True(t.t, snapshot.IsSnapshot)
+
+	// recvUntil reads asset updates until the selector returns true for
+	// one of them.
+	recvUntil := func(selector func(*taprpc.AssetUpdate) bool) {
+		t.t.Helper()
+
+		for {
+			update, err := stream.Recv()
+			require.NoError(t.t, err)
+			require.False(t.t, update.IsSnapshot)
+
+			if selector(update) {
+				return
+			}
+		}
+	}
+	containsAnchor := func(assets []*taprpc.Asset, outpoint string) bool {
+		return fn.Any(assets, func(a *taprpc.Asset) bool {
+			return a.ChainAnchor.AnchorOutpoint == outpoint
+		})
+	}
+
+	// A freshly minted asset is added to the set once its batch is
+	// finalized.
+	rpcAssets := MintAssetsConfirmBatch(
+		t.t, t.lndHarness.Miner.Client, t.tapd, simpleAssets[:1],
+	)
+	mintedAsset := rpcAssets[0]
+	mintOutpoint := mintedAsset.ChainAnchor.AnchorOutpoint
+	recvUntil(func(update *taprpc.AssetUpdate) bool {
+		return containsAnchor(update.Added, mintOutpoint) ||
+			containsAnchor(update.Changed, mintOutpoint)
+	})
+
+	// Spending the asset removes it from the set.
+	assetID := mintedAsset.AssetGenesis.AssetId
+	addr, err := t.tapd.NewAddr(ctxt, &taprpc.NewAddrRequest{
+		AssetId: assetID,
+		Amt:     1000,
+	})
+	require.NoError(t.t, err)
+	AssertAddrCreated(t.t, t.tapd, mintedAsset, addr)
+
+	sendResp := sendAssetsToAddr(t, t.tapd, addr)
+	ConfirmAndAssertOutboundTransfer(
+		t.t, t.lndHarness.Miner.Client, t.tapd, sendResp, assetID,
+		[]uint64{mintedAsset.Amount - 1000, 1000}, 0, 1,
+	)
+	recvUntil(func(update *taprpc.AssetUpdate) bool {
+		return containsAnchor(update.Removed, mintOutpoint)
+	})
+}
+
+// testVerifyProof tests that a node can verify proof files and single proofs
+// of assets it doesn't own and of asset groups it doesn't know.
+func testVerifyProof(t *harnessTest) {
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout)
+	defer cancel()
+
+	minerClient := t.lndHarness.Miner.Client
+	rpcAssets := MintAssetsConfirmBatch(
+		t.t, minerClient, t.tapd, []*mintrpc.MintAssetRequest{
+			issuableAssets[0],
+		},
+	)
+	groupedAsset := rpcAssets[0]
+	genInfo := groupedAsset.AssetGenesis
+
+	// We send some of the grouped asset to ourselves, so we have a proof
+	// file with a transfer proof.
+	const sendAmt = 1000
+	addr, err := t.tapd.NewAddr(ctxt, &taprpc.NewAddrRequest{
+		AssetId: genInfo.AssetId,
+		Amt:     sendAmt,
+	})
+	require.NoError(t.t, err)
+
+	sendResp := sendAssetsToAddr(t, t.tapd, addr)
+	ConfirmAndAssertOutboundTransfer(
+		t.t, minerClient, t.tapd, sendResp, genInfo.AssetId,
+		[]uint64{groupedAsset.Amount - sendAmt, sendAmt}, 0, 1,
+	)
+	AssertNonInteractiveRecvComplete(t.t, t.tapd, 1)
+
+	exportResp, err := t.tapd.ExportProof(ctxt, &taprpc.ExportProofRequest{
+		AssetId:   genInfo.AssetId,
+		ScriptKey: addr.ScriptKey,
+	})
+	require.NoError(t.t, err)
+	rawFile := exportResp.RawProofFile
+
+	proofFile := &proof.File{}
+	require.NoError(t.t, proofFile.Decode(bytes.NewReader(rawFile)))
+	require.Equal(t.t, 2, proofFile.NumProofs())
+
+	// The proofs are verified by a new node that has never seen the asset
+	// or its group.
+	charlie := t.lndHarness.NewNode("charlie", lndDefaultArgs)
+	secondTapd := setupTapdHarness(t.t, t, charlie, t.universeServer)
+	defer shutdownAndAssert(t, charlie, secondTapd)
+
+	verify := func(rawProof []byte) *taprpc.VerifyProofResponse {
+		resp, err := secondTapd.VerifyProof(ctxt, &taprpc.ProofFile{
+			RawProofFile: rawProof,
+		})
+		require.NoError(t.t, err)
+		require.Equal(t.t, genInfo.AssetId, resp.AssetId)
+
+		return resp
+	}
+
+	// The full proof file is valid, since it contains the group anchor.
+	resp := verify(rawFile)
+	require.True(t.t, resp.Valid, resp.FailureReason)
+	require.Empty(t.t, resp.FailureReason)
+	require.EqualValues(t.t, sendAmt, resp.Amount)
+	require.Equal(t.t, addr.ScriptKey, resp.ScriptKey)
+	require.EqualValues(t.t, 2, resp.DecodedProof.NumberOfProofs)
+
+	// The single genesis proof of the group anchor is valid on its own.
+	rawGenesisProof, err := proofFile.RawProofAt(0)
+	require.NoError(t.t, err)
+
+	resp = verify(rawGenesisProof)
+	require.True(t.t, resp.Valid, resp.FailureReason)
+	require.EqualValues(t.t, groupedAsset.Amount, resp.Amount)
+	require.EqualValues(t.t, 1, resp.DecodedProof.NumberOfProofs)
+
+	// A single transfer proof can't be verified without the previous
+	// proofs, but it's still decoded.
+	rawTransferProof, err := proofFile.RawLastProof()
+	require.NoError(t.t, err)
+
+	resp = verify(rawTransferProof)
+	require.False(t.t, resp.Valid)
+	require.NotEmpty(t.t, resp.FailureReason)
+	require.EqualValues(t.t, sendAmt, resp.Amount)
+	require.NotNil(t.t, resp.DecodedProof)
+
+	// A proof file with a tampered transfer proof is invalid.
+	lastProof, err := proofFile.LastProof()
+	require.NoError(t.t, err)
+	lastProof.Asset.Amount++
+	require.NoError(t.t, proofFile.ReplaceLastProof(*lastProof))
+
+	var buf bytes.Buffer
+	require.NoError(t.t, proofFile.Encode(&buf))
+
+	resp = verify(buf.Bytes())
+	require.False(t.t, resp.Valid)
+	require.NotEmpty(t.t, resp.FailureReason)
+	require.EqualValues(t.t, sendAmt+1, resp.Amount)
+}
+
 // transferAssetProofs locates and exports the proof files for all given assets
 // from the source node and imports them into the destination node.
 func transferAssetProofs(t *harnessTest, src, dst *tapdHarness,
@@ -288,6 +459,7 @@ func testMintAssetNameCollisionError(t *harnessTest) {
 	require.Equal(
 		t.t, cancelBatchKey.BatchKey, collideResp.PendingBatch.BatchKey,
 	)
+	require.EqualValues(t.t, 1, cancelBatchKey.NumSeedlings)
 
 	// The only change in the returned batch after cancellation should be
 	// the batch state.
@@ -163,6 +163,10 @@ func testBurnAssets(t *harnessTest) {
 		t.t, t.tapd, simpleAssetGen.AssetId, simpleAsset.Amount-burnAmt,
 	)
 
+	// The burn shows up in the transfer history with a distinct output
+	// type.
+	AssertBurnTransferOutput(t.t, t.tapd, burnResp.BurnTransfer, burnAmt)
+
 	// The burned asset should be pruned from the tree when we next spend
 	// the anchor output it was in (together with the change). So let's test
 	// that we can successfully spend the change output.
@@ -231,6 +231,85 @@ func testReIssuance(t *harnessTest) {
 	AssertNumGroups(t.t, t.tapd, groupCount)
 }
 
+// testReIssuanceGroupAssetID tests that an asset can be reissued into the
+// group of a previously issued asset by referencing that asset's ID instead of
+// the group key.
+func testReIssuanceGroupAssetID(t *harnessTest) {
+	miner := t.lndHarness.Miner.Client
+
+	// We mint a normal asset with emission enabled and a collectible that
+	// isn't part of any group.
+	normalGroupGen := MintAssetsConfirmBatch(
+		t.t, miner, t.tapd,
+		[]*mintrpc.MintAssetRequest{issuableAssets[0]},
+	)
+	ungroupedGen := MintAssetsConfirmBatch(
+		t.t, miner, t.tapd,
+		[]*mintrpc.MintAssetRequest{simpleAssets[1]},
+	)
+	require.Equal(t.t, 1, len(normalGroupGen))
+	require.Equal(t.t, 1, len(ungroupedGen))
+
+	ctxb := context.Background()
+
+	normalGroupKey := normalGroupGen[0].AssetGroup.TweakedGroupKey
+	encodedNormalGroupKey := hex.EncodeToString(normalGroupKey)
+	normalGenInfo := normalGroupGen[0].AssetGenesis
+
+	reissueRequest := CopyRequest(simpleAssets[0])
+	reissueRequest.Asset.GroupAssetId = normalGenInfo.AssetId
+
+	// A request can reference the group either by its key or by one of
+	// its assets, but not both.
+	reissueRequest.Asset.GroupKey = normalGroupKey
+	_, err := t.tapd.MintAsset(ctxb, reissueRequest)
+	require.ErrorContains(
+		t.t, err, "cannot specify a group key and a group asset ID",
+	)
+	reissueRequest.Asset.GroupKey = nil
+
+	// The referenced asset must be a valid asset ID of an asset known to
+	// the node.
+	reissueRequest.Asset.GroupAssetId = normalGenInfo.AssetId[1:]
+	_, err = t.tapd.MintAsset(ctxb, reissueRequest)
+	require.ErrorContains(t.t, err, "group asset ID must be 32 bytes")
+
+	reissueRequest.Asset.GroupAssetId = make([]byte, 32)
+	_, err = t.tapd.MintAsset(ctxb, reissueRequest)
+	require.ErrorContains(t.t, err, "unknown group asset")
+
+	// An asset that was minted without a group can't be reissued.
+	ungroupedGenInfo := ungroupedGen[0].AssetGenesis
+	reissueRequest.Asset.GroupAssetId = ungroupedGenInfo.AssetId
+	_, err = t.tapd.MintAsset(ctxb, reissueRequest)
+	require.ErrorContains(t.t, err, "isn't grouped")
+
+	// Referencing the normal asset reissues into its group.
+	reissueRequest.Asset.GroupAssetId = normalGenInfo.AssetId
+	reissueGen := MintAssetsConfirmBatch(
+		t.t, miner, t.tapd,
+		[]*mintrpc.MintAssetRequest{reissueRequest},
+	)
+	require.Equal(t.t, 1, len(reissueGen))
+	require.Equal(
+		t.t, normalGroupKey, reissueGen[0].AssetGroup.TweakedGroupKey,
+	)
+
+	// The node still knows a single group, which now holds both the
+	// original and the reissued asset.
+	AssertNumGroups(t.t, t.tapd, 1)
+	groups, err := t.tapd.ListGroups(ctxb, &taprpc.ListGroupsRequest{})
+	require.NoError(t.t, err)
+
+	normalGroup := groups.Groups[encodedNormalGroupKey]
+	require.Len(t.t, normalGroup.Assets, 2)
+
+	AssertBalanceByGroup(
+		t.t, t.tapd, encodedNormalGroupKey,
+		normalGroupGen[0].Amount+reissueGen[0].Amount,
+	)
+}
+
 // testReIssuanceAmountOverflow tests that an error is returned when attempting
 // to issue a further quantity of an asset beyond the integer overflow limit.
 func testReIssuanceAmountOverflow(t *harnessTest) {
@@ -436,6 +436,17 @@ func testReattemptFailedAssetSend(t *harnessTest) {
 			case *taprpc.SendAssetEvent_ReceiverProofBackoffWaitEvent:
 				ev := eventTyped.ReceiverProofBackoffWaitEvent
 				t.Logf("Found event ntfs: %v", ev)
+
+				// The event tells how far into the backoff
+				// procedure the delivery is and when the next
+				// attempt is made.
+				require.LessOrEqual(
+					t.t, ev.TriesCounter, ev.NumTries,
+				)
+				require.Equal(
+					t.t, ev.Timestamp+ev.Backoff,
+					ev.NextAttemptTimestamp,
+				)
 				return true
 			}
 
@@ -784,16 +795,24 @@ func testSendMultipleCoins(t *harnessTest) {
 		numParts     = 5
 		unitsPerPart = 1000
 	)
-	addrs := make([]*taprpc.Addr, numParts)
+	addrReqs := make([]*taprpc.NewAddrRequest, numParts)
 	for i := 0; i < numParts; i++ {
-		newAddr, err := t.tapd.NewAddr(ctxb, &taprpc.NewAddrRequest{
+		addrReqs[i] = &taprpc.NewAddrRequest{
 			AssetId: genInfo.AssetId,
 			Amt:     unitsPerPart,
-		})
-		require.NoError(t.t, err)
+		}
+	}
+	batchResp, err := t.tapd.NewAddrBatch(
+		ctxb, &taprpc.NewAddrBatchRequest{
+			Addrs: addrReqs,
+		},
+	)
+	require.NoError(t.t, err)
+	require.Len(t.t, batchResp.Addrs, numParts)
 
+	addrs := batchResp.Addrs
+	for _, newAddr := range addrs {
 		AssertAddrCreated(t.t, t.tapd, rpcAssets[0], newAddr)
-		addrs[i] = newAddr
 	}
 
 	// We created 5 addresses in our first node now, so we can initiate the
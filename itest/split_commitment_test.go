@@ -0,0 +1,120 @@
+package itest
+
+import (
+	"context"
+
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/taprpc/mintrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// testGetSplitCommitment tests that the split commitment of both outputs of a
+// split send can be inspected, and that the split asset's inclusion proof
+// resolves to the split root committed to by the change output.
+func testGetSplitCommitment(t *harnessTest) {
+	const numUnits = 10
+
+	rpcAssets := MintAssetsConfirmBatch(
+		t.t, t.lndHarness.Miner.Client, t.tapd,
+		[]*mintrpc.MintAssetRequest{simpleAssets[0]},
+	)
+	genInfo := rpcAssets[0].AssetGenesis
+	totalUnits := simpleAssets[0].Asset.Amount
+
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout)
+	defer cancel()
+
+	secondTapd := setupTapdHarness(
+		t.t, t, t.lndHarness.Bob, t.universeServer,
+		func(params *tapdHarnessParams) {
+			params.startupSyncNode = t.tapd
+			params.startupSyncNumAssets = len(rpcAssets)
+		},
+	)
+	defer func() {
+		require.NoError(t.t, secondTapd.stop(!*noDelete))
+	}()
+
+	// We send a part of the minted units to the second node, which splits
+	// the minted asset into a change output and the recipient output.
+	bobAddr, err := secondTapd.NewAddr(ctxt, &taprpc.NewAddrRequest{
+		AssetId:      genInfo.AssetId,
+		Amt:          numUnits,
+		AssetVersion: rpcAssets[0].Version,
+	})
+	require.NoError(t.t, err)
+	AssertAddrCreated(t.t, secondTapd, rpcAssets[0], bobAddr)
+
+	sendResp := sendAssetsToAddr(t, t.tapd, bobAddr)
+	ConfirmAndAssertOutboundTransfer(
+		t.t, t.lndHarness.Miner.Client, t.tapd, sendResp,
+		genInfo.AssetId, []uint64{totalUnits - numUnits, numUnits},
+		0, 1,
+	)
+	AssertNonInteractiveRecvComplete(t.t, secondTapd, 1)
+
+	transferOutputs := sendResp.Transfer.Outputs
+	require.Len(t.t, transferOutputs, 2)
+	changeOutput, recipientOutput := transferOutputs[0], transferOutputs[1]
+	require.Equal(
+		t.t, taprpc.OutputType_OUTPUT_TYPE_SPLIT_ROOT,
+		changeOutput.OutputType,
+	)
+
+	// The change output carries the root asset of the split, which commits
+	// to the split root itself.
+	rootResp, err := t.tapd.GetSplitCommitment(
+		ctxt, &taprpc.GetSplitCommitmentRequest{
+			AnchorOutpoint: changeOutput.Anchor.Outpoint,
+			ScriptKey:      changeOutput.ScriptKey,
+			AssetId:        genInfo.AssetId,
+		},
+	)
+	require.NoError(t.t, err)
+	require.True(t.t, rootResp.IsSplitRoot)
+	require.Equal(t.t, totalUnits, rootResp.SplitRootSum)
+	require.Equal(t.t, totalUnits-numUnits, rootResp.RootAssetAmount)
+	require.Equal(
+		t.t, changeOutput.SplitCommitRootHash, rootResp.SplitRootHash,
+	)
+	require.Empty(t.t, rootResp.SplitInclusionProof)
+
+	// The recipient output carries a split asset, which references the
+	// same split root and comes with a valid inclusion proof.
+	splitResp, err := secondTapd.GetSplitCommitment(
+		ctxt, &taprpc.GetSplitCommitmentRequest{
+			AnchorOutpoint: recipientOutput.Anchor.Outpoint,
+			ScriptKey:      recipientOutput.ScriptKey,
+			AssetId:        genInfo.AssetId,
+		},
+	)
+	require.NoError(t.t, err)
+	require.False(t.t, splitResp.IsSplitRoot)
+	require.Equal(t.t, rootResp.SplitRootHash, splitResp.SplitRootHash)
+	require.Equal(t.t, totalUnits, splitResp.SplitRootSum)
+	require.Equal(
+		t.t, rootResp.RootAssetScriptKey, splitResp.RootAssetScriptKey,
+	)
+	require.NotEmpty(t.t, splitResp.SplitLeafKey)
+	require.NotEmpty(t.t, splitResp.SplitInclusionProof)
+	require.True(t.t, splitResp.InclusionProofValid)
+
+	// Both outputs were created in the same anchor transaction, so the
+	// split asset points at the change output as its split root.
+	require.Equal(
+		t.t, rootResp.SplitRootOutputIndex,
+		splitResp.SplitRootOutputIndex,
+	)
+
+	// An asset that wasn't created by a split has no split commitment.
+	mintedAsset := rpcAssets[0]
+	_, err = t.tapd.GetSplitCommitment(
+		ctxt, &taprpc.GetSplitCommitmentRequest{
+			AnchorOutpoint: mintedAsset.ChainAnchor.AnchorOutpoint,
+			ScriptKey:      mintedAsset.ScriptKey,
+			AssetId:        genInfo.AssetId,
+		},
+	)
+	require.ErrorContains(t.t, err, "wasn't created by a split")
+}
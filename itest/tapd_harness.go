@@ -72,9 +72,10 @@ type tapdHarness struct {
 // tapdConfig holds all configuration items that are required to start a tapd
 // server.
 type tapdConfig struct {
-	LndNode   *node.HarnessNode
-	NetParams *chaincfg.Params
-	BaseDir   string
+	LndNode          *node.HarnessNode
+	NetParams        *chaincfg.Params
+	BaseDir          string
+	UniverseReadOnly bool
 }
 
 // newTapdHarness creates a new tapd server harness with the given
@@ -148,6 +149,7 @@ func newTapdHarness(t *testing.T, ht *harnessTest, cfg tapdConfig,
 	// be queryable by other tapd nodes. This applies to federation syncing
 	// as well as RPC insert and query.
 	tapCfg.Universe.PublicAccess = true
+	tapCfg.Universe.ReadOnly = cfg.UniverseReadOnly
 
 	cfgLogger := tapCfg.LogWriter.GenSubLogger("CONF", nil)
 	finalCfg, err := tapcfg.ValidateConfig(tapCfg, cfgLogger)
@@ -336,6 +336,10 @@ type tapdHarnessParams struct {
 	// startupSyncNumAssets is the number of assets that are expected to be
 	// synced from the above node.
 	startupSyncNumAssets int
+
+	// universeReadOnly indicates whether the Universe server of the tapd
+	// node should reject proofs inserted through RPC.
+	universeReadOnly bool
 }
 
 type Option func(*tapdHarnessParams)
@@ -365,8 +369,9 @@ func setupTapdHarness(t *testing.T, ht *harnessTest,
 
 	tapdHarness, err := newTapdHarness(
 		t, ht, tapdConfig{
-			NetParams: harnessNetParams,
-			LndNode:   node,
+			NetParams:        harnessNetParams,
+			LndNode:          node,
+			UniverseReadOnly: params.universeReadOnly,
 		}, selectedProofCourier,
 		params.proofSendBackoffCfg, params.proofReceiverAckTimeout,
 	)
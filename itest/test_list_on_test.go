@@ -11,6 +11,14 @@ var testCases = []*testCase{
 		name: "mint assets",
 		test: testMintAssets,
 	},
+	{
+		name: "subscribe assets",
+		test: testSubscribeAssets,
+	},
+	{
+		name: "verify proof",
+		test: testVerifyProof,
+	},
 	{
 		name: "asset name collision raises mint error",
 		test: testMintAssetNameCollisionError,
@@ -88,10 +96,18 @@ var testCases = []*testCase{
 		name: "collectible send",
 		test: testCollectibleSend,
 	},
+	{
+		name: "get split commitment",
+		test: testGetSplitCommitment,
+	},
 	{
 		name: "re-issuance",
 		test: testReIssuance,
 	},
+	{
+		name: "re-issuance by group asset id",
+		test: testReIssuanceGroupAssetID,
+	},
 	{
 		name: "minting multi asset groups",
 		test: testMintMultiAssetGroups,
@@ -157,6 +173,14 @@ var testCases = []*testCase{
 		name: "universe federation",
 		test: testUniverseFederation,
 	},
+	{
+		name: "universe read-only",
+		test: testUniverseReadOnly,
+	},
+	{
+		name: "universe default server",
+		test: testUniverseDefaultServer,
+	},
 	{
 		name: "get info",
 		test: testGetInfo,
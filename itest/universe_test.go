@@ -383,6 +383,153 @@ func getJSON[T proto.Message](url string) (T, error) {
 	return jsonResp.(T), nil
 }
 
+// testUniverseReadOnly tests that a Universe server in read-only mode rejects
+// proofs inserted through RPC while still serving queries.
+func testUniverseReadOnly(t *harnessTest) {
+	// We'll start a new node with a read-only Universe server, next to the
+	// main node that mints an asset.
+	bob := setupTapdHarness(
+		t.t, t, t.lndHarness.Bob, nil,
+		func(params *tapdHarnessParams) {
+			params.universeReadOnly = true
+		},
+	)
+	defer func() {
+		require.NoError(t.t, bob.stop(!*noDelete))
+	}()
+
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout)
+	defer cancel()
+
+	miner := t.lndHarness.Miner.Client
+	rpcAssets := MintAssetsConfirmBatch(t.t, miner, t.tapd, simpleAssets[:1])
+	require.Len(t.t, rpcAssets, 1)
+
+	outpoint, err := tap.UnmarshalOutpoint(
+		rpcAssets[0].ChainAnchor.AnchorOutpoint,
+	)
+	require.NoError(t.t, err)
+
+	uniKey := &unirpc.UniverseKey{
+		Id: &unirpc.ID{
+			Id: &unirpc.ID_AssetId{
+				AssetId: rpcAssets[0].AssetGenesis.AssetId,
+			},
+		},
+		LeafKey: &unirpc.AssetKey{
+			Outpoint: &unirpc.AssetKey_Op{
+				Op: &unirpc.Outpoint{
+					HashStr: outpoint.Hash.String(),
+					Index:   int32(outpoint.Index),
+				},
+			},
+			ScriptKey: &unirpc.AssetKey_ScriptKeyBytes{
+				ScriptKeyBytes: rpcAssets[0].ScriptKey,
+			},
+		},
+	}
+
+	// We fetch the issuance proof from the main node and try to insert it
+	// into Bob's Universe, which should be rejected.
+	uniProof, err := t.tapd.QueryProof(ctxt, uniKey)
+	require.NoError(t.t, err)
+
+	_, err = bob.InsertProof(ctxt, &unirpc.AssetProof{
+		Key:       uniKey,
+		AssetLeaf: uniProof.AssetLeaf,
+	})
+	require.ErrorContains(t.t, err, "read-only mode")
+
+	// Nothing should have been inserted, but queries are still served.
+	roots, err := bob.AssetRoots(ctxt, &unirpc.AssetRootRequest{})
+	require.NoError(t.t, err)
+	require.Empty(t.t, roots.UniverseRoots)
+
+	// The same proof is accepted by the main node, which isn't in
+	// read-only mode.
+	_, err = t.tapd.InsertProof(ctxt, &unirpc.AssetProof{
+		Key:       uniKey,
+		AssetLeaf: uniProof.AssetLeaf,
+	})
+	require.NoError(t.t, err)
+}
+
+// testUniverseDefaultServer tests that the default Universe server is used for
+// syncs that don't specify a server, and that a default set at runtime is
+// persisted across restarts.
+func testUniverseDefaultServer(t *harnessTest) {
+	miner := t.lndHarness.Miner.Client
+	rpcAssets := MintAssetsConfirmBatch(t.t, miner, t.tapd, simpleAssets)
+
+	bob := setupTapdHarness(
+		t.t, t, t.lndHarness.Bob, nil,
+	)
+	defer func() {
+		require.NoError(t.t, bob.stop(!*noDelete))
+	}()
+
+	ctxb := context.Background()
+	ctxt, cancel := context.WithTimeout(ctxb, defaultWaitTimeout)
+	defer cancel()
+
+	// Without a default server, a sync needs to specify the server.
+	_, err := bob.SyncUniverse(ctxt, &unirpc.SyncRequest{
+		SyncMode: unirpc.UniverseSyncMode_SYNC_ISSUANCE_ONLY,
+	})
+	require.ErrorContains(t.t, err, "no default universe server set")
+
+	// An unreachable server isn't accepted as the default.
+	_, err = bob.SetDefaultUniverse(ctxt, &unirpc.SetDefaultUniverseRequest{
+		UniverseHost: "foobar this is not even a valid address",
+	})
+	require.Error(t.t, err)
+
+	// We now set the main node as Bob's default server, which is then
+	// used for syncs that don't specify a server.
+	_, err = bob.SetDefaultUniverse(ctxt, &unirpc.SetDefaultUniverseRequest{
+		UniverseHost: t.tapd.rpcHost(),
+	})
+	require.NoError(t.t, err)
+
+	defaultResp, err := bob.GetDefaultUniverse(
+		ctxt, &unirpc.GetDefaultUniverseRequest{},
+	)
+	require.NoError(t.t, err)
+	require.Equal(t.t, t.tapd.rpcHost(), defaultResp.UniverseHost)
+
+	syncDiff, err := bob.SyncUniverse(ctxt, &unirpc.SyncRequest{
+		SyncMode: unirpc.UniverseSyncMode_SYNC_ISSUANCE_ONLY,
+	})
+	require.NoError(t.t, err)
+	require.Len(t.t, syncDiff.SyncedUniverses, len(rpcAssets))
+
+	// The default survives a restart of Bob's node.
+	require.NoError(t.t, bob.stop(false))
+	require.NoError(t.t, bob.start(false))
+
+	defaultResp, err = bob.GetDefaultUniverse(
+		ctxt, &unirpc.GetDefaultUniverseRequest{},
+	)
+	require.NoError(t.t, err)
+	require.Equal(t.t, t.tapd.rpcHost(), defaultResp.UniverseHost)
+
+	// Finally, clearing the default is persisted as well.
+	_, err = bob.SetDefaultUniverse(
+		ctxt, &unirpc.SetDefaultUniverseRequest{},
+	)
+	require.NoError(t.t, err)
+
+	require.NoError(t.t, bob.stop(false))
+	require.NoError(t.t, bob.start(false))
+
+	defaultResp, err = bob.GetDefaultUniverse(
+		ctxt, &unirpc.GetDefaultUniverseRequest{},
+	)
+	require.NoError(t.t, err)
+	require.Empty(t.t, defaultResp.UniverseHost)
+}
+
 func testUniverseFederation(t *harnessTest) {
 	// We'll kick off the test by making a new node, without hooking it up to
 	// any existing Universe server.
@@ -10,6 +10,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/signal"
 )
@@ -107,6 +108,7 @@ func SetupLoggers(root *build.RotatingLogWriter, interceptor signal.Interceptor)
 	AddSubLogger(
 		root, monitoring.Subsystem, interceptor, monitoring.UseLogger,
 	)
+	AddSubLogger(root, webhook.Subsystem, interceptor, webhook.UseLogger)
 }
 
 // AddSubLogger is a helper method to conveniently create and register the
@@ -0,0 +1,44 @@
+package taprootassets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightninglabs/lndclient"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// LndRpcMessageSigner is an implementation of the tapgarden.MessageSigner
+// interface backed by an active lnd node.
+type LndRpcMessageSigner struct {
+	lnd *lndclient.LndServices
+}
+
+// NewLndRpcMessageSigner returns a new message signer instance backed by the
+// passed connection to a remote lnd node.
+func NewLndRpcMessageSigner(lnd *lndclient.LndServices) *LndRpcMessageSigner {
+	return &LndRpcMessageSigner{
+		lnd: lnd,
+	}
+}
+
+// SignMessage creates a Schnorr signature over the SHA256 hash of the given
+// message with the key at the given locator.
+func (l *LndRpcMessageSigner) SignMessage(ctx context.Context,
+	keyLoc keychain.KeyLocator, msg []byte) (*schnorr.Signature, error) {
+
+	rawSig, err := l.lnd.Signer.SignMessage(
+		ctx, msg, keyLoc, lndclient.SignSchnorr(nil),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign message: %w", err)
+	}
+
+	return schnorr.ParseSignature(rawSig)
+}
+
+// A compile time assertion to ensure LndRpcMessageSigner meets the
+// tapgarden.MessageSigner interface.
+var _ tapgarden.MessageSigner = (*LndRpcMessageSigner)(nil)
@@ -16,30 +16,70 @@ var (
 			Entity: "daemon",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/BackupDatabase": {{
+			Entity: "daemon",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/GetInfo": {{
 			Entity: "daemon",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/GetConfig": {{
+			Entity: "daemon",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ListAssets": {{
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/SubscribeAssets": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ListUtxos": {{
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/ListOnChainFootprint": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ListGroups": {{
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/VerifySupplyConsistency": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ListBalances": {{
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/RebuildBalances": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/ListTransfers": {{
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/ListTransfersByScriptKey": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/GetTransferGraph": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/GetTransferMetrics": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/AnchorSweepStatus": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/QueryAddrs": {{
 			Entity: "addresses",
 			Action: "read",
@@ -48,6 +88,10 @@ var (
 			Entity: "addresses",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/NewAddrBatch": {{
+			Entity: "addresses",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/DecodeAddr": {{
 			Entity: "addresses",
 			Action: "read",
@@ -56,6 +100,10 @@ var (
 			Entity: "addresses",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/SubscribeReceiveAssetEventNtfns": {{
+			Entity: "addresses",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/VerifyProof": {{
 			Entity: "proofs",
 			Action: "read",
@@ -64,14 +112,78 @@ var (
 			Entity: "proofs",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/CheckProofCompatibility": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/ExportProof": {{
 			Entity: "proofs",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/ExportProofsBatch": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/ExportProofStream": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/MergeProofFiles": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/GetSplitCommitment": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/GetAnchorMerkleProof": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/ListDeliveryReceipts": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/ListDeadLetterProofs": {{
+			Entity: "proofs",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/PauseProofDelivery": {{
+			Entity: "proofs",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/ResumeProofDelivery": {{
+			Entity: "proofs",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/SendAsset": {{
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/PrepareTransfer": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/BroadcastTransfer": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/ExportPendingTransfer": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/taprpc.TaprootAssets/ImportPendingTransfer": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/BumpTransferFee": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/taprpc.TaprootAssets/CancelTransfer": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/taprpc.TaprootAssets/BurnAsset": {{
 			Entity: "assets",
 			Action: "write",
@@ -80,10 +192,18 @@ var (
 			Entity: "assets",
 			Action: "read",
 		}},
+		"/taprpc.TaprootAssets/SearchAssetsByMeta": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/taprpc.TaprootAssets/SubscribeSendAssetEventNtfns": {{
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/taprpc.TaprootAssets/GetSendStateMachine": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/assetwalletrpc.AssetWallet/FundVirtualPsbt": {{
 			Entity: "assets",
 			Action: "write",
@@ -92,10 +212,22 @@ var (
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/assetwalletrpc.AssetWallet/PrepareScriptSpend": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/assetwalletrpc.AssetWallet/AnchorVirtualPsbts": {{
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/assetwalletrpc.AssetWallet/CommitVirtualPsbts": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/PublishAndLogTransfer": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/assetwalletrpc.AssetWallet/NextInternalKey": {{
 			Entity: "assets",
 			Action: "write",
@@ -104,6 +236,10 @@ var (
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/assetwalletrpc.AssetWallet/GetKeyDerivation": {{
+			Entity: "assets",
+			Action: "read",
+		}},
 		"/assetwalletrpc.AssetWallet/ProveAssetOwnership": {{
 			Entity: "assets",
 			Action: "write",
@@ -116,6 +252,30 @@ var (
 			Entity: "assets",
 			Action: "write",
 		}},
+		"/assetwalletrpc.AssetWallet/ListLeases": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/assetwalletrpc.AssetWallet/ReleaseLease": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/ExportSigningState": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/assetwalletrpc.AssetWallet/ImportSigningState": {{
+			Entity: "assets",
+			Action: "write",
+		}},
+		"/assetwalletrpc.AssetWallet/ListKeyRanges": {{
+			Entity: "assets",
+			Action: "read",
+		}},
+		"/assetwalletrpc.AssetWallet/ReserveKeyRange": {{
+			Entity: "assets",
+			Action: "write",
+		}},
 		"/mintrpc.Mint/MintAsset": {{
 			Entity: "mint",
 			Action: "write",
@@ -132,6 +292,10 @@ var (
 			Entity: "mint",
 			Action: "read",
 		}},
+		"/mintrpc.Mint/RotateGroupKey": {{
+			Entity: "mint",
+			Action: "write",
+		}},
 		"/universerpc.Universe/AssetRoots": {{
 			Entity: "universe",
 			Action: "read",
@@ -140,10 +304,18 @@ var (
 			Entity: "universe",
 			Action: "read",
 		}},
+		"/universerpc.Universe/ListAuthoritativeAssets": {{
+			Entity: "universe",
+			Action: "read",
+		}},
 		"/universerpc.Universe/DeleteAssetRoot": {{
 			Entity: "universe",
 			Action: "write",
 		}},
+		"/universerpc.Universe/PruneUniverse": {{
+			Entity: "universe",
+			Action: "prune",
+		}},
 		"/universerpc.Universe/AssetLeafKeys": {{
 			Entity: "universe",
 			Action: "read",
@@ -156,6 +328,10 @@ var (
 			Entity: "universe",
 			Action: "read",
 		}},
+		"/universerpc.Universe/QueryProofs": {{
+			Entity: "universe",
+			Action: "read",
+		}},
 		"/universerpc.Universe/InsertProof": {{
 			Entity: "universe",
 			Action: "write",
@@ -164,10 +340,34 @@ var (
 			Entity: "universe",
 			Action: "write",
 		}},
+		"/universerpc.Universe/EstimateSync": {{
+			Entity: "universe",
+			Action: "read",
+		}},
+		"/universerpc.Universe/SetDefaultUniverse": {{
+			Entity: "universe",
+			Action: "write",
+		}},
+		"/universerpc.Universe/GetDefaultUniverse": {{
+			Entity: "universe",
+			Action: "read",
+		}},
 		"/universerpc.Universe/ListFederationServers": {{
 			Entity: "universe",
 			Action: "read",
 		}},
+		"/universerpc.Universe/ListFederationPushBacklog": {{
+			Entity: "universe",
+			Action: "read",
+		}},
+		"/universerpc.Universe/SubscribeIssuancePushEvents": {{
+			Entity: "universe",
+			Action: "read",
+		}},
+		"/universerpc.Universe/GetFederationSyncStatus": {{
+			Entity: "universe",
+			Action: "read",
+		}},
 		"/universerpc.Universe/AddFederationServer": {{
 			Entity: "universe",
 			Action: "write",
@@ -209,11 +409,12 @@ var (
 	// InsertProof as a valid proof requires an on-chain transaction, so we
 	// gain a layer of DoS defense.
 	defaultMacaroonWhitelist = map[string]struct{}{
-		"/universerpc.Universe/AssetRoots":      {},
-		"/universerpc.Universe/QueryAssetRoots": {},
-		"/universerpc.Universe/AssetLeafKeys":   {},
-		"/universerpc.Universe/AssetLeaves":     {},
-		"/universerpc.Universe/Info":            {},
+		"/universerpc.Universe/AssetRoots":              {},
+		"/universerpc.Universe/QueryAssetRoots":         {},
+		"/universerpc.Universe/ListAuthoritativeAssets": {},
+		"/universerpc.Universe/AssetLeafKeys":           {},
+		"/universerpc.Universe/AssetLeaves":             {},
+		"/universerpc.Universe/Info":                    {},
 	}
 )
 
@@ -232,6 +433,7 @@ func MacaroonWhitelist(allowPublicUniProofCourier bool,
 	// the whitelist.
 	if allowPublicUniProofCourier {
 		whitelist["/universerpc.Universe/QueryProof"] = struct{}{}
+		whitelist["/universerpc.Universe/QueryProofs"] = struct{}{}
 		whitelist["/universerpc.Universe/InsertProof"] = struct{}{}
 	}
 
@@ -146,6 +146,21 @@ type FileArchiver struct {
 	// eventDistributor is an event distributor that will be used to notify
 	// subscribers about new proofs that are added to the archiver.
 	eventDistributor *fn.EventDistributor[Blob]
+
+	// compress indicates whether proof files are stored compressed.
+	compress bool
+}
+
+// FileArchiverOption is a functional option that modifies the file archiver.
+type FileArchiverOption func(*FileArchiver)
+
+// WithFileCompression is a FileArchiverOption that makes the archiver store
+// proof files compressed on disk. Files are always decompressed when they're
+// fetched, so they can be passed on to nodes that don't support compression.
+func WithFileCompression() FileArchiverOption {
+	return func(f *FileArchiver) {
+		f.compress = true
+	}
 }
 
 // NewFileArchiver creates a new file archive rooted at the passed specified
@@ -155,7 +170,9 @@ type FileArchiver struct {
 //
 // TODO(roasbeef): option to memory map these instead? then don't need to lug
 // around large blobs in user space as much
-func NewFileArchiver(dirName string) (*FileArchiver, error) {
+func NewFileArchiver(dirName string,
+	opts ...FileArchiverOption) (*FileArchiver, error) {
+
 	// First, we'll make sure our main proof directory has already been
 	// created.
 	proofPath := filepath.Join(dirName, ProofDirName)
@@ -163,10 +180,15 @@ func NewFileArchiver(dirName string) (*FileArchiver, error) {
 		return nil, fmt.Errorf("unable to create proof dir: %w", err)
 	}
 
-	return &FileArchiver{
+	archiver := &FileArchiver{
 		proofPath:        proofPath,
 		eventDistributor: fn.NewEventDistributor[Blob](),
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(archiver)
+	}
+
+	return archiver, nil
 }
 
 // genProofFilePath generates the full proof file path based on a rootPath and
@@ -213,7 +235,9 @@ func (f *FileArchiver) FetchProof(_ context.Context, id Locator) (Blob, error) {
 		return nil, fmt.Errorf("unable to find proof: %w", err)
 	}
 
-	return proofFile, nil
+	// Files are stored compressed if compression is enabled, or was
+	// enabled when they were stored.
+	return SetFileCompression(proofFile, false)
 }
 
 // FetchProofs fetches all proofs for assets uniquely identified by the passed
@@ -258,6 +282,10 @@ func (f *FileArchiver) FetchProofs(_ context.Context,
 		if err != nil {
 			return nil, fmt.Errorf("unable to read proof: %w", err)
 		}
+		proofFile, err = SetFileCompression(proofFile, false)
+		if err != nil {
+			return nil, err
+		}
 
 		proofs[idx] = &AnnotatedProof{
 			Locator: Locator{
@@ -298,7 +326,12 @@ func (f *FileArchiver) ImportProofs(_ context.Context,
 				"%s does not exist", proofPath)
 		}
 
-		err = os.WriteFile(proofPath, proof.Blob, 0666)
+		storedBlob, err := SetFileCompression(proof.Blob, f.compress)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(proofPath, storedBlob, 0666)
 		if err != nil {
 			return fmt.Errorf("unable to store proof: %v", err)
 		}
@@ -129,3 +129,56 @@ func TestFileArchiver(t *testing.T) {
 		})
 	}
 }
+
+// TestFileArchiverCompression tests that the file archiver stores proof files
+// compressed if configured to, but always hands them out uncompressed.
+func TestFileArchiverCompression(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	fileArchive, err := NewFileArchiver(dir, WithFileCompression())
+	require.NoError(t, err)
+
+	f := genTransitionChain(t, 5)
+	var uncompressed bytes.Buffer
+	require.NoError(t, f.Encode(&uncompressed))
+
+	ctx := context.Background()
+	locator := Locator{
+		AssetID:   randAssetID(t),
+		ScriptKey: *test.RandPubKey(t),
+	}
+	err = fileArchive.ImportProofs(
+		ctx, MockHeaderVerifier, MockGroupVerifier, false,
+		&AnnotatedProof{
+			Locator: locator,
+			Blob:    uncompressed.Bytes(),
+		},
+	)
+	require.NoError(t, err)
+
+	proofPath, err := genProofFilePath(fileArchive.proofPath, locator)
+	require.NoError(t, err)
+	storedBlob, err := os.ReadFile(proofPath)
+	require.NoError(t, err)
+	require.True(t, IsCompressedFile(storedBlob))
+	require.Less(t, len(storedBlob), uncompressed.Len())
+
+	fetchedBlob, err := fileArchive.FetchProof(ctx, locator)
+	require.NoError(t, err)
+	require.Equal(t, Blob(uncompressed.Bytes()), fetchedBlob)
+
+	proofs, err := fileArchive.FetchProofs(ctx, *locator.AssetID)
+	require.NoError(t, err)
+	require.Len(t, proofs, 1)
+	require.Equal(t, Blob(uncompressed.Bytes()), proofs[0].Blob)
+
+	// Files that were stored compressed are still handed out uncompressed
+	// once compression is disabled again.
+	plainArchive, err := NewFileArchiver(dir)
+	require.NoError(t, err)
+
+	fetchedBlob, err = plainArchive.FetchProof(ctx, locator)
+	require.NoError(t, err)
+	require.Equal(t, Blob(uncompressed.Bytes()), fetchedBlob)
+}
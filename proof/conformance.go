@@ -0,0 +1,302 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// ConformanceCheckDecode is the name of the check that a proof or proof
+	// file can be decoded at all.
+	ConformanceCheckDecode = "decode"
+
+	// ConformanceCheckVersion is the name of the check that a proof or
+	// proof file uses a version known to this implementation.
+	ConformanceCheckVersion = "known_version"
+
+	// ConformanceCheckRequiredRecords is the name of the check that a
+	// proof contains all records that are required by the specification.
+	ConformanceCheckRequiredRecords = "required_records"
+
+	// ConformanceCheckUnknownRecords is the name of the check that a proof
+	// doesn't contain any unknown even (critical) records. Unknown odd
+	// records are allowed, but listed in the details of the check.
+	ConformanceCheckUnknownRecords = "unknown_records"
+
+	// ConformanceCheckCanonical is the name of the check that a proof or
+	// proof file is encoded canonically, meaning re-encoding the decoded
+	// proof results in exactly the same bytes.
+	ConformanceCheckCanonical = "canonical_encoding"
+
+	// FileConformanceIndex is the proof index that is used for the checks
+	// of the proof file itself, instead of an individual proof within it.
+	FileConformanceIndex = -1
+)
+
+// requiredProofTypes are the TLV types every proof must contain. These are
+// the even types that are always encoded, all other types are optional.
+var requiredProofTypes = []tlv.Type{
+	VersionType, PrevOutType, BlockHeaderType, AnchorTxType,
+	TxMerkleProofType, AssetLeafType, InclusionProofType, BlockHeightType,
+}
+
+// ConformanceCheck is the result of a single check of a conformance report.
+type ConformanceCheck struct {
+	// Name is the name of the check.
+	Name string
+
+	// ProofIndex is the index of the checked proof within the proof file.
+	// This is zero for a single proof and FileConformanceIndex for the
+	// checks of the proof file itself.
+	ProofIndex int
+
+	// Passed is true if the proof passed the check.
+	Passed bool
+
+	// Details describes why the check failed, or contains additional
+	// information about a passed check.
+	Details string
+}
+
+// ConformanceReport is the result of a strict conformance check of a proof or
+// proof file. In contrast to the proof verification, the conformance check
+// only looks at the encoding of a proof and not at its validity.
+type ConformanceReport struct {
+	// IsFile is true if the checked blob is a proof file.
+	IsFile bool
+
+	// Checks are the results of the individual checks.
+	Checks []ConformanceCheck
+}
+
+// Conformant returns true if all checks of the report passed.
+func (r *ConformanceReport) Conformant() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// addCheck adds the result of a check to the report.
+func (r *ConformanceReport) addCheck(name string, proofIndex int, err error,
+	details string) {
+
+	check := ConformanceCheck{
+		Name:       name,
+		ProofIndex: proofIndex,
+		Passed:     err == nil,
+		Details:    details,
+	}
+	if err != nil {
+		check.Details = err.Error()
+	}
+
+	r.Checks = append(r.Checks, check)
+}
+
+// CheckConformance runs a strict conformance check of the encoding of the
+// given proof or proof file. This is meant to detect encoding differences
+// between implementations, so the report contains the results of all checks
+// instead of stopping at the first failure.
+func CheckConformance(blob Blob) (*ConformanceReport, error) {
+	switch {
+	case IsSingleProof(blob):
+		report := &ConformanceReport{}
+		checkProofConformance(report, 0, blob)
+
+		return report, nil
+
+	case IsProofFile(blob):
+		if err := CheckMaxFileSize(blob); err != nil {
+			return nil, fmt.Errorf("invalid proof file: %w", err)
+		}
+
+		report := &ConformanceReport{
+			IsFile: true,
+		}
+		checkFileConformance(report, blob)
+
+		return report, nil
+
+	default:
+		return nil, fmt.Errorf("blob is neither a proof nor a proof " +
+			"file")
+	}
+}
+
+// checkFileConformance checks the encoding of a proof file and all proofs
+// within it.
+func checkFileConformance(report *ConformanceReport, blob Blob) {
+	var file File
+	err := file.Decode(bytes.NewReader(blob))
+	report.addCheck(ConformanceCheckDecode, FileConformanceIndex, err, "")
+	if err != nil {
+		return
+	}
+
+	var versionErr error
+	if file.IsUnknownVersion() {
+		versionErr = fmt.Errorf("unknown file version %d",
+			file.Version)
+	}
+	report.addCheck(
+		ConformanceCheckVersion, FileConformanceIndex, versionErr, "",
+	)
+
+	var buf bytes.Buffer
+	err = file.Encode(&buf)
+	if err == nil && !bytes.Equal(buf.Bytes(), blob) {
+		err = fmt.Errorf("re-encoded file differs from original, "+
+			"got %d bytes, expected %d bytes", buf.Len(),
+			len(blob))
+	}
+	report.addCheck(
+		ConformanceCheckCanonical, FileConformanceIndex, err, "",
+	)
+
+	for i := 0; i < file.NumProofs(); i++ {
+		rawProof, err := file.RawProofAt(uint32(i))
+		if err != nil {
+			report.addCheck(ConformanceCheckDecode, i, err, "")
+			continue
+		}
+
+		checkProofConformance(report, i, rawProof)
+	}
+}
+
+// checkProofConformance checks the encoding of a single proof.
+func checkProofConformance(report *ConformanceReport, index int,
+	rawProof []byte) {
+
+	if !IsSingleProof(rawProof) {
+		report.addCheck(
+			ConformanceCheckDecode, index,
+			fmt.Errorf("invalid prefix magic bytes"), "",
+		)
+		return
+	}
+
+	// We decode the TLV stream ourselves instead of using Decode, since we
+	// need the set of parsed types to detect unknown records.
+	var p Proof
+	stream, err := tlv.NewStream(p.DecodeRecords()...)
+	if err != nil {
+		report.addCheck(ConformanceCheckDecode, index, err, "")
+		return
+	}
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(
+		bytes.NewReader(rawProof[PrefixMagicBytesLength:]),
+	)
+	report.addCheck(ConformanceCheckDecode, index, err, "")
+	if err != nil {
+		return
+	}
+
+	var versionErr error
+	if p.IsUnknownVersion() {
+		versionErr = fmt.Errorf("unknown proof version %d", p.Version)
+	}
+	report.addCheck(ConformanceCheckVersion, index, versionErr, "")
+
+	var missing []tlv.Type
+	for _, typ := range requiredProofTypes {
+		if _, ok := parsedTypes[typ]; !ok {
+			missing = append(missing, typ)
+		}
+	}
+	var missingErr error
+	if len(missing) > 0 {
+		missingErr = fmt.Errorf("missing required records: %s",
+			formatTypes(missing))
+	}
+	report.addCheck(ConformanceCheckRequiredRecords, index, missingErr, "")
+
+	// The decoder only keeps the value of records it doesn't know. Unknown
+	// even records are critical and must be rejected, unknown odd records
+	// may be ignored.
+	var unknownEven, unknownOdd []tlv.Type
+	for typ, value := range parsedTypes {
+		if value == nil {
+			continue
+		}
+
+		if typ%2 == 0 {
+			unknownEven = append(unknownEven, typ)
+		} else {
+			unknownOdd = append(unknownOdd, typ)
+		}
+	}
+
+	var unknownErr error
+	if len(unknownEven) > 0 {
+		unknownErr = fmt.Errorf("unknown even records: %s",
+			formatTypes(unknownEven))
+	}
+	var unknownDetails string
+	if len(unknownOdd) > 0 {
+		unknownDetails = fmt.Sprintf("ignored unknown odd records: %s",
+			formatTypes(unknownOdd))
+	}
+	report.addCheck(
+		ConformanceCheckUnknownRecords, index, unknownErr,
+		unknownDetails,
+	)
+
+	// A proof without all required records can't be re-encoded.
+	if missingErr != nil {
+		report.addCheck(
+			ConformanceCheckCanonical, index,
+			fmt.Errorf("unable to re-encode proof with missing "+
+				"required records"), "",
+		)
+		return
+	}
+
+	// Unknown odd records are encoded again, just like the decoder keeps
+	// them, while unknown even records are dropped. So a proof with
+	// unknown even records is never canonical from our point of view. We
+	// still run the check, as it also catches unknown records in nested
+	// TLV streams.
+	for typ, value := range parsedTypes {
+		if value == nil || typ%2 == 0 {
+			continue
+		}
+
+		if p.UnknownOddTypes == nil {
+			p.UnknownOddTypes = make(tlv.TypeMap)
+		}
+		p.UnknownOddTypes[typ] = value
+	}
+
+	var buf bytes.Buffer
+	err = p.Encode(&buf)
+	if err == nil && !bytes.Equal(buf.Bytes(), rawProof) {
+		err = fmt.Errorf("re-encoded proof differs from original, "+
+			"got %d bytes, expected %d bytes", buf.Len(),
+			len(rawProof))
+	}
+	report.addCheck(ConformanceCheckCanonical, index, err, "")
+}
+
+// formatTypes formats the given TLV types as a sorted, comma separated list.
+func formatTypes(types []tlv.Type) string {
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+
+	typeStrs := make([]string, len(types))
+	for i, typ := range types {
+		typeStrs[i] = fmt.Sprintf("%d", typ)
+	}
+
+	return strings.Join(typeStrs, ", ")
+}
@@ -0,0 +1,110 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// readTestProofBlob reads a hex encoded proof or proof file from the given
+// test data file.
+func readTestProofBlob(t *testing.T, fileName string) Blob {
+	proofHex, err := os.ReadFile(fileName)
+	require.NoError(t, err)
+
+	proofBytes, err := hex.DecodeString(
+		strings.Trim(string(proofHex), "\n"),
+	)
+	require.NoError(t, err)
+
+	return proofBytes
+}
+
+// assertCheck asserts that the report contains the named check for the proof
+// at the given index, with the expected result.
+func assertCheck(t *testing.T, report *ConformanceReport, name string,
+	index int, passed bool) ConformanceCheck {
+
+	t.Helper()
+
+	for _, check := range report.Checks {
+		if check.Name == name && check.ProofIndex == index {
+			require.Equal(t, passed, check.Passed, check.Details)
+			return check
+		}
+	}
+
+	t.Fatalf("check %v for proof %d not found", name, index)
+	return ConformanceCheck{}
+}
+
+// TestCheckConformance tests that the conformance check of proofs and proof
+// files detects encoding deviations.
+func TestCheckConformance(t *testing.T) {
+	t.Parallel()
+
+	proofBlob := readTestProofBlob(t, proofHexFileName)
+	fileBlob := readTestProofBlob(t, proofFileHexFileName)
+
+	// The proofs we generate ourselves must be conformant.
+	report, err := CheckConformance(proofBlob)
+	require.NoError(t, err)
+	require.False(t, report.IsFile)
+	require.True(t, report.Conformant())
+
+	report, err = CheckConformance(fileBlob)
+	require.NoError(t, err)
+	require.True(t, report.IsFile)
+	require.True(t, report.Conformant())
+	assertCheck(t, report, ConformanceCheckCanonical, FileConformanceIndex,
+		true)
+	assertCheck(t, report, ConformanceCheckCanonical, 0, true)
+
+	// An unknown odd record is allowed and encoded again, so the proof
+	// stays canonical.
+	withRecord := func(recordType, value byte) Blob {
+		blob := bytes.Clone(proofBlob)
+		return append(blob, recordType, 1, value)
+	}
+	report, err = CheckConformance(withRecord(101, 1))
+	require.NoError(t, err)
+	require.True(t, report.Conformant())
+	check := assertCheck(
+		t, report, ConformanceCheckUnknownRecords, 0, true,
+	)
+	require.Contains(t, check.Details, "101")
+	assertCheck(t, report, ConformanceCheckCanonical, 0, true)
+
+	// An unknown even record is critical.
+	report, err = CheckConformance(withRecord(100, 1))
+	require.NoError(t, err)
+	check = assertCheck(
+		t, report, ConformanceCheckUnknownRecords, 0, false,
+	)
+	require.Contains(t, check.Details, "100")
+
+	// A proof that only contains a version is missing all other required
+	// records.
+	versionOnly := append(bytes.Clone(PrefixMagicBytes[:]), 0, 4, 0, 0, 0, 0)
+	report, err = CheckConformance(versionOnly)
+	require.NoError(t, err)
+	assertCheck(t, report, ConformanceCheckDecode, 0, true)
+	check = assertCheck(
+		t, report, ConformanceCheckRequiredRecords, 0, false,
+	)
+	require.Contains(t, check.Details, "2, 4, 6, 8, 10, 12, 22")
+
+	// A truncated proof can't be decoded at all.
+	report, err = CheckConformance(proofBlob[:len(proofBlob)-1])
+	require.NoError(t, err)
+	assertCheck(t, report, ConformanceCheckDecode, 0, false)
+	require.Len(t, report.Checks, 1)
+
+	// Anything that isn't a proof is rejected.
+	_, err = CheckConformance([]byte("not a proof"))
+	require.Error(t, err)
+}
@@ -3,19 +3,24 @@ package proof
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lightning-node-connect/hashmailrpc"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/fn"
 	"github.com/lightninglabs/taproot-assets/taprpc"
 	unirpc "github.com/lightninglabs/taproot-assets/taprpc/universerpc"
+	"github.com/lightningnetwork/lnd/keychain"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -38,6 +43,26 @@ const (
 	// UniverseRpcCourierType is a courier that uses the daemon universe RPC
 	// endpoints to deliver proofs.
 	UniverseRpcCourierType = "universerpc"
+
+	// EmailCourierType is a courier that delivers proofs as encrypted email
+	// attachments.
+	EmailCourierType = "email"
+
+	// courierCompressionParam is the query parameter of a hashmail or
+	// email courier address that advertises the compression schemes of
+	// proof files the receiver can decode.
+	courierCompressionParam = "compression"
+
+	// courierCompressionDeflate is the value of the compression parameter
+	// for proof files that are compressed with DEFLATE.
+	courierCompressionDeflate = "deflate"
+)
+
+var (
+	// ErrNoFallbackCourier is returned when a fallback courier is requested
+	// but no fallback courier address is configured.
+	ErrNoFallbackCourier = errors.New("no fallback proof courier " +
+		"configured")
 )
 
 // CourierHarness interface is an integration testing harness for a proof
@@ -101,12 +126,59 @@ func ParseCourierAddrUrl(addr url.URL) (CourierAddr, error) {
 		return NewHashMailCourierAddr(addr)
 	case UniverseRpcCourierType:
 		return NewUniverseRpcCourierAddr(addr)
+	case EmailCourierType:
+		return NewEmailCourierAddr(addr)
+	case HTTPSCourierType, HTTPCourierType:
+		return NewHTTPCourierAddr(addr)
 	}
 
 	return nil, fmt.Errorf("unknown courier address protocol "+
 		"(consider updating tapd): %v", addr.Scheme)
 }
 
+// IsMailboxCourierAddr returns true if the given courier address delivers
+// proofs through a mailbox. The mailbox of a recipient is only identified by
+// its script key, so the recipient can receive a proof without knowing the
+// outpoint of the transfer.
+func IsMailboxCourierAddr(addr *url.URL) bool {
+	switch addr.Scheme {
+	case HashmailCourierType, EmailCourierType, HTTPSCourierType,
+		HTTPCourierType:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
+// SupportsCompressedFiles returns true if the receiver behind the given courier
+// address advertises that it can decode compressed proof files. Proof files
+// are only delivered compressed to such receivers.
+func SupportsCompressedFiles(addr *url.URL) bool {
+	schemes := strings.Split(addr.Query().Get(courierCompressionParam), ",")
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(strings.TrimSpace(scheme))
+		if scheme == courierCompressionDeflate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithCompressedFiles returns a copy of the given courier address that
+// advertises that the receiver can decode compressed proof files. Only hashmail
+// and email courier addresses can carry the advertisement, since older nodes
+// reject HTTP(S) courier addresses with a query.
+func WithCompressedFiles(addr url.URL) url.URL {
+	query := addr.Query()
+	query.Set(courierCompressionParam, courierCompressionDeflate)
+	addr.RawQuery = query.Encode()
+
+	return addr
+}
+
 // HashMailCourierAddr is a hashmail protocol specific implementation of the
 // CourierAddr interface.
 type HashMailCourierAddr struct {
@@ -137,11 +209,15 @@ func (h *HashMailCourierAddr) NewCourier(_ context.Context, cfg *CourierCfg,
 		map[uint64]*fn.EventReceiver[fn.Event],
 	)
 	return &HashMailCourier{
-		cfg:         &hashMailCfg,
-		recipient:   recipient,
-		mailbox:     hashMailBox,
-		deliveryLog: cfg.DeliveryLog,
-		subscribers: subscribers,
+		cfg:              &hashMailCfg,
+		mode:             cfg.Mode(recipient.ReceiveType),
+		recipient:        recipient,
+		mailbox:          hashMailBox,
+		deliveryLog:      cfg.DeliveryLog,
+		receiptSigner:    cfg.ReceiptSigner,
+		pausedDeliveries: cfg.PausedDeliveries,
+		compressFiles:    cfg.compressFiles(&h.addr),
+		subscribers:      subscribers,
 	}, nil
 }
 
@@ -160,6 +236,11 @@ func NewHashMailCourierAddr(addr url.URL) (*HashMailCourierAddr, error) {
 			"port unspecified")
 	}
 
+	// Only the email courier negotiates an encryption scheme.
+	if err := checkNoEncSchemes(addr); err != nil {
+		return nil, err
+	}
+
 	return &HashMailCourierAddr{
 		addr,
 	}, nil
@@ -202,16 +283,26 @@ func (h *UniverseRpcCourierAddr) NewCourier(_ context.Context,
 
 	client := unirpc.NewUniverseClient(conn)
 
+	gapMailbox, err := newGapMailbox(cfg.GapRecovery)
+	if err != nil {
+		return nil, err
+	}
+
 	// Instantiate the events subscribers map.
 	subscribers := make(
 		map[uint64]*fn.EventReceiver[fn.Event],
 	)
 
 	return &UniverseRpcCourier{
-		recipient:   recipient,
-		client:      client,
-		deliveryLog: cfg.DeliveryLog,
-		subscribers: subscribers,
+		recipient:        recipient,
+		backoffCfg:       cfg.BackoffCfg,
+		mode:             cfg.Mode(recipient.ReceiveType),
+		client:           client,
+		deliveryLog:      cfg.DeliveryLog,
+		pausedDeliveries: cfg.PausedDeliveries,
+		gapRecovery:      cfg.GapRecovery,
+		gapMailbox:       gapMailbox,
+		subscribers:      subscribers,
 	}, nil
 }
 
@@ -224,6 +315,11 @@ func NewUniverseRpcCourierAddr(addr url.URL) (*UniverseRpcCourierAddr, error) {
 			"unspecified")
 	}
 
+	// Only the email courier negotiates an encryption scheme.
+	if err := checkNoEncSchemes(addr); err != nil {
+		return nil, err
+	}
+
 	return &UniverseRpcCourierAddr{
 		addr,
 	}, nil
@@ -242,6 +338,37 @@ func NewCourier(ctx context.Context, addr url.URL, cfg *CourierCfg,
 	return courierAddr.NewCourier(ctx, cfg, recipient)
 }
 
+// NewFallbackCourier instantiates a new courier service handle for the
+// fallback universe of the given config. Proofs are delivered through it if
+// the courier of a receiver failed all delivery attempts, and receivers poll it
+// for proofs they couldn't receive through the courier of their address.
+func NewFallbackCourier(ctx context.Context, cfg *CourierCfg,
+	recipient Recipient) (Courier, error) {
+
+	if cfg.FallbackCourierAddr == nil {
+		return nil, ErrNoFallbackCourier
+	}
+
+	courierAddr, err := NewUniverseRpcCourierAddr(*cfg.FallbackCourierAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	courier, err := courierAddr.NewCourier(ctx, cfg, recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	uniCourier, ok := courier.(*UniverseRpcCourier)
+	if !ok {
+		return nil, fmt.Errorf("unexpected fallback courier type %T",
+			courier)
+	}
+	uniCourier.fallback = true
+
+	return uniCourier, nil
+}
+
 // CourierCfg contains general config parameters applicable to all proof
 // couriers.
 type CourierCfg struct {
@@ -256,6 +383,74 @@ type CourierCfg struct {
 	// DeliveryLog is the log that the courier will use to record the
 	// attempted delivery of proofs to the receiver.
 	DeliveryLog DeliveryLog
+
+	// ReceiptSigner is used to sign a delivery receipt for each proof that
+	// is received. If this is nil, proofs are only acknowledged without a
+	// receipt.
+	ReceiptSigner ReceiptSigner
+
+	// PausedDeliveries keeps track of the proof deliveries that were
+	// paused by the user. If this is nil, deliveries can't be paused.
+	PausedDeliveries *PausedDeliveries
+
+	// EmailCfg is the config of the mail servers used by the email
+	// courier. If this is nil, proofs can't be delivered by email.
+	EmailCfg *EmailCourierCfg
+
+	// SharedKeyDeriver is used by the email courier to derive the key of
+	// the proofs it receives from the ephemeral key of the sender. If this
+	// is nil, proofs can't be received by email.
+	SharedKeyDeriver SharedKeyDeriver
+
+	// HTTPCfg is the config of the HTTP(S) courier. If this is nil, the
+	// default poll interval and request timeout are used.
+	HTTPCfg *HTTPCourierCfg
+
+	// FallbackCourierAddr is the address of the universe proofs are
+	// delivered through if the courier of a receiver failed all delivery
+	// attempts. If this is nil, no fallback courier is used.
+	FallbackCourierAddr *url.URL
+
+	// NonInteractiveMode is the courier mode used to deliver proofs to
+	// receivers that received to a Taproot Asset address.
+	NonInteractiveMode CourierMode
+
+	// InteractiveMode is the courier mode used to deliver proofs to
+	// receivers of an interactive transfer.
+	InteractiveMode CourierMode
+
+	// InteractiveCourierAddr is the proof courier used to deliver proofs
+	// to receivers of an interactive transfer, as those don't announce a
+	// courier of their own. If this is nil, interactive receivers are
+	// expected to obtain their proofs out of band.
+	InteractiveCourierAddr *url.URL
+
+	// GapRecovery is the config for the recovery of proofs that are
+	// missing from the proof chain assembled from a universe courier. If
+	// this is nil, a gap in the proof chain fails the receive.
+	GapRecovery *GapRecoveryCfg
+
+	// CompressFiles indicates whether proof files are compressed when
+	// they're delivered through a hashmail or email courier whose address
+	// advertises that the receiver supports compressed files. All other
+	// receivers get uncompressed files.
+	CompressFiles bool
+}
+
+// compressFiles returns true if proof files delivered through the given
+// mailbox courier address should be compressed.
+func (c *CourierCfg) compressFiles(addr *url.URL) bool {
+	return c.CompressFiles && SupportsCompressedFiles(addr)
+}
+
+// Mode returns the courier mode used to deliver proofs to receivers of the
+// given receive type.
+func (c *CourierCfg) Mode(receiveType ReceiveType) CourierMode {
+	if receiveType == ReceiveTypeInteractive {
+		return c.InteractiveMode
+	}
+
+	return c.NonInteractiveMode
 }
 
 // ProofMailbox represents an abstract store-and-forward mailbox that can be
@@ -271,11 +466,15 @@ type ProofMailbox interface {
 	ReadProof(ctx context.Context, sid streamID) (Blob, error)
 
 	// AckProof sends an ACK from the receiver to the sender that a proof
-	// has been received.
-	AckProof(ctx context.Context, sid streamID) error
+	// has been received. If a delivery receipt is given, it is sent along
+	// with the ACK.
+	AckProof(ctx context.Context, sid streamID,
+		receipt *DeliveryReceipt) error
 
 	// RecvAck waits for the sender to receive the ack from the receiver.
-	RecvAck(ctx context.Context, sid streamID) error
+	// If the receiver sent a delivery receipt along with the ACK, it is
+	// returned.
+	RecvAck(ctx context.Context, sid streamID) (*DeliveryReceipt, error)
 
 	// CleanUp attempts to tear down the mailbox as specified by the passed
 	// sid.
@@ -413,8 +612,10 @@ func (h *HashMailBox) ReadProof(ctx context.Context,
 var ackMsg = []byte("ack")
 
 // AckProof sends an ACK from the receiver to the sender that a proof has been
-// received.
-func (h *HashMailBox) AckProof(ctx context.Context, sid streamID) error {
+// received. If a delivery receipt is given, it is appended to the ACK message.
+func (h *HashMailBox) AckProof(ctx context.Context, sid streamID,
+	receipt *DeliveryReceipt) error {
+
 	writeStream, err := h.client.SendStream(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to create send stream: %w", err)
@@ -424,7 +625,7 @@ func (h *HashMailBox) AckProof(ctx context.Context, sid streamID) error {
 		Desc: &hashmailrpc.CipherBoxDesc{
 			StreamId: sid[:],
 		},
-		Msg: ackMsg,
+		Msg: encodeAckMsg(receipt),
 	})
 	if err != nil {
 		return err
@@ -433,25 +634,55 @@ func (h *HashMailBox) AckProof(ctx context.Context, sid streamID) error {
 	return writeStream.CloseSend()
 }
 
-// RecvAck waits for the sender to receive the ack from the receiver.
-func (h *HashMailBox) RecvAck(ctx context.Context, sid streamID) error {
+// RecvAck waits for the sender to receive the ack from the receiver. If the
+// receiver appended a delivery receipt to the ACK message, it is returned.
+func (h *HashMailBox) RecvAck(ctx context.Context,
+	sid streamID) (*DeliveryReceipt, error) {
+
 	readStream, err := h.client.RecvStream(ctx, &hashmailrpc.CipherBoxDesc{
 		StreamId: sid[:],
 	})
 	if err != nil {
-		return fmt.Errorf("unable to create read stream: %w", err)
+		return nil, fmt.Errorf("unable to create read stream: %w", err)
 	}
 
 	msg, err := readStream.Recv()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if bytes.Equal(msg.Msg, ackMsg) {
-		return nil
+	return decodeAckMsg(msg.Msg)
+}
+
+// encodeAckMsg encodes an ACK message, with the given delivery receipt
+// appended if it is set.
+func encodeAckMsg(receipt *DeliveryReceipt) []byte {
+	if receipt == nil {
+		return ackMsg
 	}
 
-	return fmt.Errorf("expected ack, got %x", msg.Msg)
+	msg := append([]byte(nil), ackMsg...)
+	return append(msg, receipt.Encode()...)
+}
+
+// decodeAckMsg decodes an ACK message, returning the delivery receipt that was
+// appended to it, if any.
+func decodeAckMsg(msg []byte) (*DeliveryReceipt, error) {
+	switch {
+	case bytes.Equal(msg, ackMsg):
+		return nil, nil
+
+	case bytes.HasPrefix(msg, ackMsg):
+		receipt, err := DecodeDeliveryReceipt(msg[len(ackMsg):])
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode delivery "+
+				"receipt: %w", err)
+		}
+
+		return receipt, nil
+	}
+
+	return nil, fmt.Errorf("expected ack, got %x", msg)
 }
 
 // CleanUp atempts to tear down the mailbox as specified by the passed sid.
@@ -511,6 +742,25 @@ type Recipient struct {
 	// Amount is the amount of the asset that is being transferred. This is
 	// used for logging purposes only.
 	Amount uint64
+
+	// AnchorOutPoint is the outpoint of the anchor output the asset is
+	// transferred to. Together with the script key, it identifies the
+	// delivery when it is paused or resumed by the user.
+	AnchorOutPoint wire.OutPoint
+
+	// ReceiveType is the way the recipient received the asset, which
+	// determines the courier mode used to deliver the proof.
+	ReceiveType ReceiveType
+
+	// InternalKey is the internal key of the address the recipient
+	// received the asset to. The email courier encrypts the proof to its
+	// public key, the receiver uses its key locator to decrypt it.
+	InternalKey keychain.KeyDescriptor
+}
+
+// DeliveryID returns the ID of the proof delivery to the recipient.
+func (r *Recipient) DeliveryID() DeliveryID {
+	return NewDeliveryID(r.AnchorOutPoint, r.ScriptKey)
 }
 
 // HashMailCourierCfg is the config for the hashmail proof courier.
@@ -522,6 +772,10 @@ type HashMailCourierCfg struct {
 	// BackoffCfg configures the behaviour of the proof delivery
 	// functionality.
 	BackoffCfg *BackoffCfg
+
+	// SignReceipts indicates whether a signed delivery receipt should be
+	// sent back to the sender for each proof that is received.
+	SignReceipts bool `long:"signreceipts" description:"If set, a delivery receipt signed with the key of the receiving script key is sent back to the sender together with the acknowledgement of each received proof. Senders running a version that doesn't support receipts won't recognize the acknowledgement and will retry the delivery."`
 }
 
 // BackoffCfg configures the behaviour of the proof delivery backoff procedure.
@@ -549,6 +803,9 @@ type HashMailCourier struct {
 	// cfg contains the courier's configuration parameters.
 	cfg *HashMailCourierCfg
 
+	// mode is the courier mode used to deliver the proof.
+	mode CourierMode
+
 	// recipient describes the recipient of the proof.
 	recipient Recipient
 
@@ -558,6 +815,18 @@ type HashMailCourier struct {
 	// attempted delivery of proofs to the receiver.
 	deliveryLog DeliveryLog
 
+	// receiptSigner is used to sign a delivery receipt for each received
+	// proof. If this is nil, no receipts are sent.
+	receiptSigner ReceiptSigner
+
+	// pausedDeliveries keeps track of the proof deliveries that were
+	// paused by the user.
+	pausedDeliveries *PausedDeliveries
+
+	// compressFiles indicates whether proof files are delivered
+	// compressed, because the receiver supports it.
+	compressFiles bool
+
 	// subscribers is a map of components that want to be notified on new
 	// events, keyed by their subscription ID.
 	subscribers map[uint64]*fn.EventReceiver[fn.Event]
@@ -577,43 +846,28 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context,
 	log.Infof("Attempting to deliver receiver proof for send of "+
 		"asset_id=%v, amt=%v", h.recipient.AssetID, h.recipient.Amount)
 
+	// Only receivers that advertise support for compressed proof files
+	// get one, all others would reject it.
+	blob, err := SetFileCompression(proof.Blob, h.compressFiles)
+	if err != nil {
+		return err
+	}
+
 	// Compute the stream IDs for the sender and receiver.
 	senderStreamID := deriveSenderStreamID(h.recipient)
 	receiverStreamID := deriveReceiverStreamID(h.recipient)
 
 	// Query delivery log to ensure a sensible rate of delivery attempts.
-	timestamps, err := h.deliveryLog.QueryProofDeliveryLog(
-		ctx, proof.Locator,
-	)
+	backoff := h.backoff()
+	err = backoff.waitForPastAttempts(ctx, h.deliveryLog, proof.Locator)
 	if err != nil {
-		return fmt.Errorf("unable to retrieve proof delivery "+
-			"logs: %w", err)
-	}
-
-	// Determine whether the historical receiver proof delivery attempts
-	// occurred far enough in the past to warrant a new set of delivery
-	// attempts. Otherwise, wait.
-	//
-	// Only wait if we have a non-zero number of past delivery attempts.
-	timeSinceLastAttempt := timeSinceLastDeliveryAttempt(timestamps)
-	backoffResetWait := h.cfg.BackoffCfg.BackoffResetWait
-	if len(timestamps) > 0 &&
-		timeSinceLastAttempt < backoffResetWait {
-
-		waitDuration := backoffResetWait - timeSinceLastAttempt
-		log.Infof("Waiting %v before attempting to "+
-			"deliver receiver proof to receiver "+
-			"using backoff procedure", waitDuration)
-
-		err := h.wait(ctx, waitDuration)
-		if err != nil {
-			return err
-		}
+		return err
 	}
 
 	// Interact with the hashmail service using a backoff procedure to
 	// ensure that we don't overwhelm the service with delivery attempts.
-	err = h.backoffExec(
+	var receipt *DeliveryReceipt
+	err = backoff.exec(
 		ctx, func() error {
 			err := h.initMailboxes(
 				ctx, senderStreamID, receiverStreamID,
@@ -639,9 +893,7 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context,
 			// TODO(roasbeef): do ecies here
 			log.Infof("Sending receiver proof via sid=%x",
 				senderStreamID)
-			err = h.mailbox.WriteProof(
-				ctx, senderStreamID, proof.Blob,
-			)
+			err = h.mailbox.WriteProof(ctx, senderStreamID, blob)
 			if err != nil {
 				return fmt.Errorf("failed to send proof "+
 					"to asset transfer receiver: %w", err)
@@ -656,7 +908,9 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context,
 				ctx, h.cfg.ReceiverAckTimeout,
 			)
 			defer cancel()
-			err = h.mailbox.RecvAck(ctxTimeout, receiverStreamID)
+			receipt, err = h.mailbox.RecvAck(
+				ctxTimeout, receiverStreamID,
+			)
 			if err != nil {
 				return fmt.Errorf("failed to receive ACK "+
 					"from receiver within timeout: %w", err)
@@ -681,6 +935,30 @@ func (h *HashMailCourier) DeliverProof(ctx context.Context,
 		return fmt.Errorf("failed to cleanup receiver mailbox: %w", err)
 	}
 
+	// If the receiver sent a delivery receipt, we store it after making
+	// sure it was signed by the receiver for the proof we sent. An invalid
+	// receipt doesn't affect the delivery itself, since the proof was
+	// acknowledged anyway.
+	if receipt == nil {
+		return nil
+	}
+
+	scriptKey := h.recipient.ScriptKey
+	err = receipt.Verify(scriptKey, sha256.Sum256(blob))
+	if err != nil {
+		log.Warnf("Ignoring delivery receipt for script key %x: %v",
+			scriptKey.SerializeCompressed(), err)
+		return nil
+	}
+
+	err = h.deliveryLog.StoreProofDeliveryReceipt(
+		ctx, proof.Locator, receipt,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to store proof delivery receipt: %w",
+			err)
+	}
+
 	return nil
 }
 
@@ -747,22 +1025,131 @@ func (e *BackoffExecError) Error() string {
 	return fmt.Sprintf("backoff exec error: %s", e.execErr.Error())
 }
 
-// backoffExec attempts to execute the given `exec` function using a repeating
-// backoff time delayed strategy. The backoff strategy is used to ensure
-// that we don't spam the hashmail service with proof delivery attempts.
-func (h *HashMailCourier) backoffExec(ctx context.Context,
+// publishSubscriberEvent publishes an event to all subscribers.
+func (h *HashMailCourier) publishSubscriberEvent(event fn.Event) {
+	// Lock the subscriber mutex to ensure that we don't modify the
+	// subscriber map while we're iterating over it.
+	h.subscriberMtx.Lock()
+	defer h.subscriberMtx.Unlock()
+
+	for _, sub := range h.subscribers {
+		sub.NewItemCreated.ChanIn() <- event
+	}
+}
+
+// backoff returns the handle that executes the delivery attempts of the
+// courier with its backoff procedure.
+func (h *HashMailCourier) backoff() *backoffHandle {
+	return &backoffHandle{
+		cfg:              h.cfg.BackoffCfg,
+		mode:             h.mode,
+		deliveryID:       h.recipient.DeliveryID(),
+		pausedDeliveries: h.pausedDeliveries,
+		publishEvent:     h.publishSubscriberEvent,
+	}
+}
+
+// backoffHandle executes proof delivery attempts with the backoff procedure
+// configured by a BackoffCfg. All courier implementations deliver proofs
+// through it, so a failed delivery is retried the same way regardless of the
+// transport the courier uses.
+type backoffHandle struct {
+	// cfg configures the backoff procedure. If this is nil, a delivery is
+	// only attempted once.
+	cfg *BackoffCfg
+
+	// mode is the courier mode used to deliver the proof.
+	mode CourierMode
+
+	// deliveryID identifies the delivery when it is paused by the user.
+	deliveryID DeliveryID
+
+	// pausedDeliveries keeps track of the proof deliveries that were
+	// paused by the user.
+	pausedDeliveries *PausedDeliveries
+
+	// publishEvent notifies the subscribers of the courier about an
+	// event of the delivery.
+	publishEvent func(fn.Event)
+}
+
+// waitForPastAttempts determines whether the past delivery attempts of the
+// proof identified by the given locator occurred far enough in the past to
+// warrant a new set of delivery attempts. Otherwise, it waits until the backoff
+// reset wait has passed.
+func (b *backoffHandle) waitForPastAttempts(ctx context.Context,
+	deliveryLog DeliveryLog, loc Locator) error {
+
+	// A synchronous delivery expects the receiver to be online right now,
+	// so it never waits for past attempts.
+	if b.cfg == nil || b.mode != CourierModeAsync {
+		return nil
+	}
+
+	timestamps, err := deliveryLog.QueryProofDeliveryLog(ctx, loc)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve proof delivery "+
+			"logs: %w", err)
+	}
+
+	// Only wait if we have a non-zero number of past delivery attempts.
+	timeSinceLastAttempt := timeSinceLastDeliveryAttempt(timestamps)
+	backoffResetWait := b.cfg.BackoffResetWait
+	if len(timestamps) > 0 && timeSinceLastAttempt < backoffResetWait {
+		waitDuration := backoffResetWait - timeSinceLastAttempt
+		log.Infof("Waiting %v before attempting to "+
+			"deliver receiver proof to receiver "+
+			"using backoff procedure", waitDuration)
+
+		return b.wait(ctx, waitDuration)
+	}
+
+	return nil
+}
+
+// exec attempts to execute the given target function using a repeating
+// backoff time delayed strategy. The backoff strategy is used to ensure that
+// we don't spam the courier service with proof delivery attempts.
+func (b *backoffHandle) exec(ctx context.Context,
 	targetFunc func() error) error {
 
 	var (
-		backoff    = h.cfg.BackoffCfg.InitialBackoff
-		numTries   = h.cfg.BackoffCfg.NumTries
-		maxBackoff = h.cfg.BackoffCfg.MaxBackoff
+		backoff    time.Duration
+		numTries   = 1
+		maxBackoff time.Duration
 
 		// Target function execution error.
 		errExec error = nil
 	)
+	if b.cfg != nil {
+		backoff = b.cfg.InitialBackoff
+		numTries = b.cfg.NumTries
+		maxBackoff = b.cfg.MaxBackoff
+	}
+
+	// A synchronous delivery fails as soon as the receiver doesn't
+	// respond, so there is nothing to back off from.
+	if b.mode == CourierModeSync {
+		numTries = 1
+	}
 
 	for i := 0; i < numTries; i++ {
+		// If the user paused the delivery, we wait until it is resumed.
+		// The receiver is expected to be back by then, so we start
+		// over with a fresh set of tries.
+		paused, err := b.pausedDeliveries.waitUntilResumed(
+			ctx, b.deliveryID, b.publishEvent,
+		)
+		if err != nil {
+			return err
+		}
+		if paused {
+			i = 0
+			if b.cfg != nil {
+				backoff = b.cfg.InitialBackoff
+			}
+		}
+
 		// Execute target function.
 		errExec = targetFunc()
 		if errExec == nil {
@@ -783,15 +1170,15 @@ func (h *HashMailCourier) backoffExec(ctx context.Context,
 		// The target function execution failed. Notify subscribers that
 		// backoff wait is about to commence.
 		transferEvent := NewReceiverProofBackoffWaitEvent(
-			backoff, int64(i+1),
+			backoff, int64(i+1), int64(numTries),
 		)
-		h.publishSubscriberEvent(transferEvent)
+		b.publishEvent(transferEvent)
 
 		log.Debugf("Receiver proof delivery failed with "+
 			"error. Backing off for %s: %v", backoff, errExec)
 
 		// Wait before reattempting execution.
-		err := h.wait(ctx, backoff)
+		err = b.wait(ctx, backoff)
 		if err != nil {
 			return fmt.Errorf("backoff wait: %w", err)
 		}
@@ -811,27 +1198,15 @@ func (h *HashMailCourier) backoffExec(ctx context.Context,
 	return nil
 }
 
-// publishSubscriberEvent publishes an event to all subscribers.
-func (h *HashMailCourier) publishSubscriberEvent(event fn.Event) {
-	// Lock the subscriber mutex to ensure that we don't modify the
-	// subscriber map while we're iterating over it.
-	h.subscriberMtx.Lock()
-	defer h.subscriberMtx.Unlock()
-
-	for _, sub := range h.subscribers {
-		sub.NewItemCreated.ChanIn() <- event
-	}
-}
-
 // wait blocks for a given amount of time.
-func (h *HashMailCourier) wait(ctx context.Context,
+func (b *backoffHandle) wait(ctx context.Context,
 	backoff time.Duration) error {
 
 	select {
 	case <-time.After(backoff):
 		return nil
 	case <-ctx.Done():
-		return fmt.Errorf("hashmail courier context canceled")
+		return fmt.Errorf("proof courier context canceled")
 	}
 }
 
@@ -849,6 +1224,14 @@ type ReceiverProofBackoffWaitEvent struct {
 	// course of the current Backoff procedure to deliver the proof to the
 	// receiver.
 	TriesCounter int64
+
+	// NumTries is the configured number of tries of the current Backoff
+	// procedure.
+	NumTries int64
+
+	// NextAttempt is the time at which the next delivery attempt is made,
+	// once the Backoff duration has passed.
+	NextAttempt time.Time
 }
 
 // Timestamp returns the timestamp of the event.
@@ -857,13 +1240,16 @@ func (e *ReceiverProofBackoffWaitEvent) Timestamp() time.Time {
 }
 
 // NewReceiverProofBackoffWaitEvent creates a new ReceiverProofBackoffWaitEvent.
-func NewReceiverProofBackoffWaitEvent(
-	backoff time.Duration, triesCounter int64) *ReceiverProofBackoffWaitEvent {
+func NewReceiverProofBackoffWaitEvent(backoff time.Duration, triesCounter,
+	numTries int64) *ReceiverProofBackoffWaitEvent {
 
+	now := time.Now().UTC()
 	return &ReceiverProofBackoffWaitEvent{
-		timestamp:    time.Now().UTC(),
+		timestamp:    now,
 		Backoff:      backoff,
 		TriesCounter: triesCounter,
+		NumTries:     numTries,
+		NextAttempt:  now.Add(backoff),
 	}
 }
 
@@ -886,6 +1272,19 @@ func (h *HashMailCourier) ReceiveProof(ctx context.Context,
 		return nil, err
 	}
 
+	// If we're configured to do so, we sign a delivery receipt for the
+	// proof we received, which is sent back together with the ACK.
+	var receipt *DeliveryReceipt
+	if h.receiptSigner != nil {
+		receipt, err = h.receiptSigner.SignDeliveryReceipt(
+			ctx, h.recipient.ScriptKey, sha256.Sum256(proof),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to sign delivery "+
+				"receipt: %w", err)
+		}
+	}
+
 	// Now that we've read the proof, we'll create our mailbox (which might
 	// already exist) to send an ACK back to the sender.
 	receiverStreamID := deriveReceiverStreamID(h.recipient)
@@ -893,7 +1292,16 @@ func (h *HashMailCourier) ReceiveProof(ctx context.Context,
 	if err := h.mailbox.Init(ctx, receiverStreamID); err != nil {
 		return nil, err
 	}
-	if err := h.mailbox.AckProof(ctx, receiverStreamID); err != nil {
+	err = h.mailbox.AckProof(ctx, receiverStreamID, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The receipt covers the proof file as it was sent, but the rest of
+	// the node passes proof files on to other nodes as well. So we hand
+	// them out uncompressed, as not all nodes support compressed files.
+	proof, err = SetFileCompression(proof, false)
+	if err != nil {
 		return nil, err
 	}
 
@@ -925,6 +1333,18 @@ type UniverseRpcCourier struct {
 	// recipient describes the recipient of the proof.
 	recipient Recipient
 
+	// backoffCfg configures the retry of failed proof deliveries.
+	backoffCfg *BackoffCfg
+
+	// mode is the courier mode used to deliver the proof.
+	mode CourierMode
+
+	// fallback indicates that the courier is used as the fallback of
+	// another courier. The past delivery attempts were made through that
+	// other courier, so they don't hold back a delivery, and proofs that
+	// aren't in the universe yet are polled for when receiving.
+	fallback bool
+
 	// client is the RPC client that the courier will use to interact with
 	// the universe RPC server.
 	client unirpc.UniverseClient
@@ -933,6 +1353,18 @@ type UniverseRpcCourier struct {
 	// attempted delivery of proofs to the receiver.
 	deliveryLog DeliveryLog
 
+	// pausedDeliveries keeps track of the proof deliveries that were
+	// paused by the user.
+	pausedDeliveries *PausedDeliveries
+
+	// gapRecovery is the config for the recovery of proofs that are
+	// missing from the universe.
+	gapRecovery *GapRecoveryCfg
+
+	// gapMailbox is the mailbox through which missing proofs are requested
+	// from the sender. If this is nil, gap recovery is disabled.
+	gapMailbox ProofMailbox
+
 	// subscribers is a map of components that want to be notified on new
 	// events, keyed by their subscription ID.
 	subscribers map[uint64]*fn.EventReceiver[fn.Event]
@@ -942,6 +1374,10 @@ type UniverseRpcCourier struct {
 	subscriberMtx sync.Mutex
 }
 
+// A compile-time assertion to ensure that the UniverseRpcCourier meets the
+// GapServer interface.
+var _ GapServer = (*UniverseRpcCourier)(nil)
+
 // DeliverProof attempts to delivery a proof file to the receiver.
 func (c *UniverseRpcCourier) DeliverProof(ctx context.Context,
 	annotatedProof *AnnotatedProof) error {
@@ -953,6 +1389,28 @@ func (c *UniverseRpcCourier) DeliverProof(ctx context.Context,
 		return err
 	}
 
+	// We retry failed insertions with the same backoff procedure as the
+	// other couriers.
+	backoff := c.backoff()
+	if !c.fallback {
+		err = backoff.waitForPastAttempts(
+			ctx, c.deliveryLog, annotatedProof.Locator,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return backoff.exec(ctx, func() error {
+		return c.insertProofs(ctx, proofFile)
+	})
+}
+
+// insertProofs submits each proof of the given proof file to the universe
+// courier service.
+func (c *UniverseRpcCourier) insertProofs(ctx context.Context,
+	proofFile *File) error {
+
 	// Iterate over each proof in the proof file and submit to the courier
 	// service.
 	for i := 0; i < proofFile.NumProofs(); i++ {
@@ -1021,7 +1479,7 @@ func (c *UniverseRpcCourier) DeliverProof(ctx context.Context,
 		}
 	}
 
-	return err
+	return nil
 }
 
 // ReceiveProof attempts to obtain a proof file from the courier service. The
@@ -1029,6 +1487,50 @@ func (c *UniverseRpcCourier) DeliverProof(ctx context.Context,
 func (c *UniverseRpcCourier) ReceiveProof(ctx context.Context,
 	originLocator Locator) (*AnnotatedProof, error) {
 
+	var receivedProof *AnnotatedProof
+	queryProofs := func() error {
+		var err error
+		receivedProof, err = c.queryProofs(ctx, originLocator)
+		return err
+	}
+
+	// The sender only uploads the proof to the fallback universe once
+	// the delivery through the courier of the receiver failed, so we poll
+	// for it with the backoff procedure.
+	var err error
+	if c.fallback {
+		err = c.backoff().exec(ctx, queryProofs)
+	} else {
+		err = queryProofs()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Our proof chain is complete, so the sender can stop answering our
+	// gap requests. If the acknowledgement is lost, the sender stops once
+	// its maximum serve duration passed.
+	if c.gapMailbox != nil {
+		err := acknowledgeProofChain(
+			ctx, c.gapMailbox, &originLocator.ScriptKey,
+			c.gapRecovery.RequestTimeout,
+		)
+		if err != nil {
+			log.Warnf("Unable to acknowledge proof chain for "+
+				"script key %x: %v",
+				originLocator.ScriptKey.SerializeCompressed(),
+				err)
+		}
+	}
+
+	return receivedProof, nil
+}
+
+// queryProofs assembles the proof file of the given locator from the
+// transition proofs found in the universe.
+func (c *UniverseRpcCourier) queryProofs(ctx context.Context,
+	originLocator Locator) (*AnnotatedProof, error) {
+
 	// In order to reconstruct the proof file we must collect all the
 	// transition proofs that make up the main chain of proofs. That is
 	// accomplished by iterating backwards through the main chain of proofs
@@ -1053,18 +1555,39 @@ func (c *UniverseRpcCourier) ReceiveProof(ctx context.Context,
 			LeafKey: assetKey,
 		}
 
+		var transitionProof Proof
 		resp, err := c.client.QueryProof(ctx, &universeKey)
-		if err != nil {
+		switch {
+		// An earlier proof of the chain is missing from the universe.
+		// If gap recovery is enabled, we ask the sender for it.
+		case err != nil && len(revProofs) > 0 && c.gapMailbox != nil:
+			log.Warnf("Proof for script key %x at %v missing from "+
+				"universe: %v",
+				loc.ScriptKey.SerializeCompressed(),
+				loc.OutPoint, err)
+
+			gapProof, gapErr := requestGapProof(
+				ctx, c.gapMailbox, &originLocator.ScriptKey,
+				loc, c.gapRecovery.RequestTimeout,
+			)
+			if gapErr != nil {
+				return nil, fmt.Errorf("unable to recover "+
+					"missing proof: %w (universe: %v)",
+					gapErr, err)
+			}
+			transitionProof = *gapProof
+
+		case err != nil:
 			return nil, err
-		}
 
 		// Decode transition proof from query response.
-		proofBlob := resp.AssetLeaf.IssuanceProof
-		var transitionProof Proof
-		if err := transitionProof.Decode(
-			bytes.NewReader(proofBlob),
-		); err != nil {
-			return nil, err
+		default:
+			proofBlob := resp.AssetLeaf.IssuanceProof
+			if err := transitionProof.Decode(
+				bytes.NewReader(proofBlob),
+			); err != nil {
+				return nil, err
+			}
 		}
 
 		revProofs = append(revProofs, transitionProof)
@@ -1118,6 +1641,28 @@ func (c *UniverseRpcCourier) ReceiveProof(ctx context.Context,
 	}, nil
 }
 
+// ServeProofGaps answers the requests of the receiver for the proofs of the
+// given proof file that it can't find in the universe, until the receiver
+// acknowledges that its proof chain is complete or the context is done. If gap
+// recovery is disabled, it returns immediately.
+func (c *UniverseRpcCourier) ServeProofGaps(ctx context.Context,
+	annotatedProof *AnnotatedProof) error {
+
+	if c.gapMailbox == nil {
+		return nil
+	}
+
+	proofFile := &File{}
+	err := proofFile.Decode(bytes.NewReader(annotatedProof.Blob))
+	if err != nil {
+		return err
+	}
+
+	return serveProofGaps(
+		ctx, c.gapMailbox, c.recipient.ScriptKey, proofFile,
+	)
+}
+
 // SetSubscribers sets the subscribers for the courier. This method is
 // thread-safe.
 func (c *UniverseRpcCourier) SetSubscribers(
@@ -1129,6 +1674,30 @@ func (c *UniverseRpcCourier) SetSubscribers(
 	c.subscribers = subscribers
 }
 
+// backoff returns the handle that executes the delivery attempts of the
+// courier with its backoff procedure.
+func (c *UniverseRpcCourier) backoff() *backoffHandle {
+	return &backoffHandle{
+		cfg:              c.backoffCfg,
+		mode:             c.mode,
+		deliveryID:       c.recipient.DeliveryID(),
+		pausedDeliveries: c.pausedDeliveries,
+		publishEvent:     c.publishSubscriberEvent,
+	}
+}
+
+// publishSubscriberEvent publishes an event to all subscribers.
+func (c *UniverseRpcCourier) publishSubscriberEvent(event fn.Event) {
+	// Lock the subscriber mutex to ensure that we don't modify the
+	// subscriber map while we're iterating over it.
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	for _, sub := range c.subscribers {
+		sub.NewItemCreated.ChanIn() <- event
+	}
+}
+
 // A compile-time assertion to ensure the UniverseRpcCourier meets the
 // proof.Courier interface.
 var _ Courier = (*UniverseRpcCourier)(nil)
@@ -1142,4 +1711,9 @@ type DeliveryLog interface {
 	// QueryProofDeliveryLog returns timestamps which correspond to logged
 	// proof delivery attempts.
 	QueryProofDeliveryLog(context.Context, Locator) ([]time.Time, error)
+
+	// StoreProofDeliveryReceipt stores the delivery receipt the receiver
+	// returned for the proof identified by the given locator.
+	StoreProofDeliveryReceipt(context.Context, Locator,
+		*DeliveryReceipt) error
 }
@@ -0,0 +1,79 @@
+package proof
+
+import "fmt"
+
+// ReceiveType describes how the receiver of a proof receives the asset it
+// belongs to.
+type ReceiveType uint8
+
+const (
+	// ReceiveTypeNonInteractive is a receive to a Taproot Asset address.
+	// The receiver isn't necessarily online at the time of the transfer,
+	// so the proof is picked up from the courier whenever it comes back.
+	ReceiveTypeNonInteractive ReceiveType = iota
+
+	// ReceiveTypeInteractive is a receive that was negotiated with the
+	// receiver through a virtual PSBT, which means the receiver is
+	// expected to be online while the transfer is completed.
+	ReceiveTypeInteractive
+)
+
+// String returns a human-readable string for the receive type.
+func (r ReceiveType) String() string {
+	switch r {
+	case ReceiveTypeNonInteractive:
+		return "noninteractive"
+
+	case ReceiveTypeInteractive:
+		return "interactive"
+
+	default:
+		return fmt.Sprintf("<unknown_receive_type(%d)>", r)
+	}
+}
+
+// CourierMode describes how a proof courier hands a proof over to the
+// receiver.
+type CourierMode uint8
+
+const (
+	// CourierModeAsync tolerates a receiver that isn't online. Failed
+	// delivery attempts are retried with an increasing backoff, and
+	// repeated deliveries of the same proof are rate limited by the
+	// delivery log.
+	CourierModeAsync CourierMode = iota
+
+	// CourierModeSync expects the receiver to acknowledge the proof right
+	// away. The proof is delivered in a single attempt without waiting for
+	// previous attempts to back off, so an unresponsive receiver fails the
+	// delivery within the receiver ACK timeout.
+	CourierModeSync
+)
+
+// String returns a human-readable string for the courier mode.
+func (m CourierMode) String() string {
+	switch m {
+	case CourierModeAsync:
+		return "async"
+
+	case CourierModeSync:
+		return "sync"
+
+	default:
+		return fmt.Sprintf("<unknown_courier_mode(%d)>", m)
+	}
+}
+
+// ParseCourierMode parses a courier mode string.
+func ParseCourierMode(mode string) (CourierMode, error) {
+	switch mode {
+	case "", CourierModeAsync.String():
+		return CourierModeAsync, nil
+
+	case CourierModeSync.String():
+		return CourierModeSync, nil
+
+	default:
+		return 0, fmt.Errorf("unknown courier mode: %v", mode)
+	}
+}
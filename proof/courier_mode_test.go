@@ -0,0 +1,288 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableMailbox is a proof mailbox whose receiver never shows up. It
+// counts the attempts to initialize a mailbox stream.
+type unreachableMailbox struct {
+	ProofMailbox
+
+	attempts int
+}
+
+// Init fails to create the mailbox, as the receiver is unreachable.
+func (m *unreachableMailbox) Init(context.Context, streamID) error {
+	m.attempts++
+
+	return fmt.Errorf("receiver unreachable")
+}
+
+// stubDeliveryLog is a delivery log that optionally reports a recent delivery
+// attempt for every proof.
+type stubDeliveryLog struct {
+	recent bool
+}
+
+// StoreProofDeliveryAttempt logs a proof delivery attempt to disk.
+func (stubDeliveryLog) StoreProofDeliveryAttempt(context.Context,
+	Locator) error {
+
+	return nil
+}
+
+// QueryProofDeliveryLog returns timestamps which correspond to logged proof
+// delivery attempts.
+func (l stubDeliveryLog) QueryProofDeliveryLog(context.Context,
+	Locator) ([]time.Time, error) {
+
+	if !l.recent {
+		return nil, nil
+	}
+
+	return []time.Time{time.Now()}, nil
+}
+
+// StoreProofDeliveryReceipt stores the delivery receipt the receiver returned
+// for the proof identified by the given locator.
+func (stubDeliveryLog) StoreProofDeliveryReceipt(context.Context, Locator,
+	*DeliveryReceipt) error {
+
+	return nil
+}
+
+// TestCourierMode tests that the courier mode is selected by the receive type
+// and that a synchronous delivery neither waits for past attempts nor retries.
+func TestCourierMode(t *testing.T) {
+	t.Parallel()
+
+	mode, err := ParseCourierMode("")
+	require.NoError(t, err)
+	require.Equal(t, CourierModeAsync, mode)
+
+	mode, err = ParseCourierMode(CourierModeSync.String())
+	require.NoError(t, err)
+	require.Equal(t, CourierModeSync, mode)
+
+	_, err = ParseCourierMode("instant")
+	require.ErrorContains(t, err, "unknown courier mode")
+
+	cfg := &CourierCfg{InteractiveMode: CourierModeSync}
+	require.Equal(t, CourierModeAsync, cfg.Mode(ReceiveTypeNonInteractive))
+	require.Equal(t, CourierModeSync, cfg.Mode(ReceiveTypeInteractive))
+
+	newCourier := func(mode CourierMode) (*HashMailCourier,
+		*unreachableMailbox) {
+
+		mailbox := &unreachableMailbox{}
+		return &HashMailCourier{
+			cfg: &HashMailCourierCfg{
+				ReceiverAckTimeout: time.Second,
+				BackoffCfg: &BackoffCfg{
+					BackoffResetWait: time.Hour,
+					NumTries:         3,
+				},
+			},
+			mode: mode,
+			recipient: Recipient{
+				ScriptKey: test.RandPubKey(t),
+			},
+			mailbox:     mailbox,
+			deliveryLog: stubDeliveryLog{recent: true},
+			subscribers: make(
+				map[uint64]*fn.EventReceiver[fn.Event],
+			),
+		}, mailbox
+	}
+	annotatedProof := &AnnotatedProof{
+		Blob: test.RandBytes(100),
+	}
+
+	// An asynchronous delivery waits for the recent attempt to back off
+	// first, which outlasts our context.
+	courier, mailbox := newCourier(CourierModeAsync)
+	ctx, cancel := context.WithTimeout(
+		context.Background(), 50*time.Millisecond,
+	)
+	defer cancel()
+	err = courier.DeliverProof(ctx, annotatedProof)
+	require.ErrorContains(t, err, "context canceled")
+	require.Zero(t, mailbox.attempts)
+
+	// Without the recent attempt, it uses up all of its tries.
+	courier.deliveryLog = stubDeliveryLog{}
+	err = courier.DeliverProof(context.Background(), annotatedProof)
+	require.ErrorContains(t, err, "receiver unreachable")
+	require.Equal(t, 3, mailbox.attempts)
+
+	// A synchronous delivery fails after a single attempt right away.
+	courier, mailbox = newCourier(CourierModeSync)
+	err = courier.DeliverProof(context.Background(), annotatedProof)
+	require.ErrorContains(t, err, "receiver unreachable")
+	require.Equal(t, 1, mailbox.attempts)
+}
+
+// recordingMailbox is a proof mailbox that records the proofs written to it,
+// acknowledges them immediately and hands out a fixed proof to readers.
+type recordingMailbox struct {
+	ProofMailbox
+
+	written []Blob
+
+	toRead Blob
+}
+
+// Init creates a mailbox given the specified stream ID.
+func (m *recordingMailbox) Init(context.Context, streamID) error {
+	return nil
+}
+
+// WriteProof writes the proof to the mailbox specified by the sid.
+func (m *recordingMailbox) WriteProof(_ context.Context, _ streamID,
+	proof Blob) error {
+
+	m.written = append(m.written, proof)
+
+	return nil
+}
+
+// ReadProof reads a proof from the mailbox.
+func (m *recordingMailbox) ReadProof(context.Context, streamID) (Blob,
+	error) {
+
+	return m.toRead, nil
+}
+
+// AckProof sends an ACK from the receiver to the sender.
+func (m *recordingMailbox) AckProof(context.Context, streamID,
+	*DeliveryReceipt) error {
+
+	return nil
+}
+
+// RecvAck waits for the sender to receive the ack from the receiver.
+func (m *recordingMailbox) RecvAck(context.Context,
+	streamID) (*DeliveryReceipt, error) {
+
+	return nil, nil
+}
+
+// CleanUp attempts to tear down the mailbox as specified by the passed sid.
+func (m *recordingMailbox) CleanUp(context.Context, streamID) error {
+	return nil
+}
+
+// TestCourierFileCompression tests that proof files are only delivered
+// compressed to receivers that advertise support for them, and that received
+// files are handed out uncompressed.
+func TestCourierFileCompression(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	f := genTransitionChain(t, 2)
+	var uncompressed bytes.Buffer
+	require.NoError(t, f.Encode(&uncompressed))
+	f.Compressed = true
+	var compressed bytes.Buffer
+	require.NoError(t, f.Encode(&compressed))
+
+	plainAddr, err := url.Parse("hashmail://courier.example.com:443")
+	require.NoError(t, err)
+	require.False(t, SupportsCompressedFiles(plainAddr))
+
+	advertisingAddr := WithCompressedFiles(*plainAddr)
+	require.True(t, SupportsCompressedFiles(&advertisingAddr))
+	require.Equal(
+		t, "hashmail://courier.example.com:443?compression=deflate",
+		advertisingAddr.String(),
+	)
+
+	newCourier := func(addr *url.URL,
+		compress bool) (*HashMailCourier, *recordingMailbox) {
+
+		cfg := &CourierCfg{
+			CompressFiles: compress,
+		}
+		mailbox := &recordingMailbox{}
+		return &HashMailCourier{
+			cfg: &HashMailCourierCfg{
+				ReceiverAckTimeout: time.Second,
+				BackoffCfg: &BackoffCfg{
+					BackoffResetWait: time.Second,
+					NumTries:         1,
+				},
+			},
+			recipient: Recipient{
+				ScriptKey: test.RandPubKey(t),
+			},
+			mailbox:       mailbox,
+			deliveryLog:   stubDeliveryLog{},
+			compressFiles: cfg.compressFiles(addr),
+			subscribers: make(
+				map[uint64]*fn.EventReceiver[fn.Event],
+			),
+		}, mailbox
+	}
+
+	testCases := []struct {
+		name           string
+		addr           *url.URL
+		compress       bool
+		blob           []byte
+		wantCompressed bool
+	}{{
+		name:           "compression disabled",
+		addr:           &advertisingAddr,
+		blob:           uncompressed.Bytes(),
+		wantCompressed: false,
+	}, {
+		name:           "receiver without support",
+		addr:           plainAddr,
+		compress:       true,
+		blob:           compressed.Bytes(),
+		wantCompressed: false,
+	}, {
+		name:           "receiver with support",
+		addr:           &advertisingAddr,
+		compress:       true,
+		blob:           uncompressed.Bytes(),
+		wantCompressed: true,
+	}}
+	for _, tc := range testCases {
+		courier, mailbox := newCourier(tc.addr, tc.compress)
+		err := courier.DeliverProof(ctx, &AnnotatedProof{
+			Blob: tc.blob,
+		})
+		require.NoError(t, err, tc.name)
+
+		require.Len(t, mailbox.written, 1, tc.name)
+		require.Equal(
+			t, tc.wantCompressed,
+			IsCompressedFile(mailbox.written[0]), tc.name,
+		)
+
+		var sent File
+		err = sent.Decode(bytes.NewReader(mailbox.written[0]))
+		require.NoError(t, err, tc.name)
+		require.Equal(t, f.proofs, sent.proofs, tc.name)
+	}
+
+	// A compressed file that is received is handed out uncompressed, so
+	// it can be passed on to nodes that don't support compression.
+	courier, mailbox := newCourier(&advertisingAddr, true)
+	mailbox.toRead = compressed.Bytes()
+	received, err := courier.ReceiveProof(ctx, Locator{})
+	require.NoError(t, err)
+	require.Equal(t, Blob(uncompressed.Bytes()), received.Blob)
+}
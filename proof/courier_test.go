@@ -0,0 +1,66 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackoffWaitEvents tests that a backoff wait event is published for each
+// failed delivery attempt, reporting the number of tries and the time of the
+// next attempt.
+func TestBackoffWaitEvents(t *testing.T) {
+	t.Parallel()
+
+	var events []*ReceiverProofBackoffWaitEvent
+	handle := &backoffHandle{
+		cfg: &BackoffCfg{
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     15 * time.Millisecond,
+			NumTries:       3,
+		},
+		mode: CourierModeAsync,
+		publishEvent: func(event fn.Event) {
+			waitEvent, ok := event.(*ReceiverProofBackoffWaitEvent)
+			require.True(t, ok)
+
+			events = append(events, waitEvent)
+		},
+	}
+
+	var attempts []time.Time
+	err := handle.exec(context.Background(), func() error {
+		attempts = append(attempts, time.Now().UTC())
+		return errors.New("receiver unreachable")
+	})
+	require.ErrorContains(t, err, "receiver unreachable")
+	require.Len(t, attempts, 3)
+
+	// The backoff doubles after each attempt, but never exceeds the
+	// maximum backoff.
+	expectedBackoffs := []time.Duration{
+		10 * time.Millisecond, 15 * time.Millisecond,
+		15 * time.Millisecond,
+	}
+	require.Len(t, events, len(expectedBackoffs))
+	for i, event := range events {
+		require.Equal(t, expectedBackoffs[i], event.Backoff)
+		require.EqualValues(t, i+1, event.TriesCounter)
+		require.EqualValues(t, 3, event.NumTries)
+		require.Equal(
+			t, event.Timestamp().Add(event.Backoff),
+			event.NextAttempt,
+		)
+
+		// The next attempt is only made once the announced time has
+		// passed.
+		if i+1 < len(attempts) {
+			nextAttempt := attempts[i+1]
+			require.False(t, nextAttempt.Before(event.NextAttempt))
+		}
+	}
+}
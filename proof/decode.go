@@ -0,0 +1,182 @@
+package proof
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// proofRecordNames maps the TLV types of the top level proof records to a
+// human readable name, used to report decode errors.
+var proofRecordNames = map[tlv.Type]string{
+	VersionType:           "version",
+	PrevOutType:           "prev out",
+	BlockHeaderType:       "block header",
+	AnchorTxType:          "anchor tx",
+	TxMerkleProofType:     "tx merkle proof",
+	AssetLeafType:         "asset leaf",
+	InclusionProofType:    "inclusion proof",
+	ExclusionProofsType:   "exclusion proofs",
+	SplitRootProofType:    "split root proof",
+	MetaRevealType:        "meta reveal",
+	AdditionalInputsType:  "additional inputs",
+	ChallengeWitnessType:  "challenge witness",
+	BlockHeightType:       "block height",
+	GenesisRevealType:     "genesis reveal",
+	GroupKeyRevealType:    "group key reveal",
+	IssuanceAuthorityType: "issuance authority",
+}
+
+// DecodeError is returned by DecodeRaw if a proof can't be decoded. It locates
+// the TLV record of the proof that couldn't be decoded.
+type DecodeError struct {
+	// RecordType is the TLV type of the record that couldn't be decoded,
+	// or nil if the type itself couldn't be read.
+	RecordType *tlv.Type
+
+	// Offset is the byte offset of the start of the record within the raw
+	// proof, including the prefix magic bytes.
+	Offset int
+
+	// Err is the error the record failed to decode with.
+	Err error
+}
+
+// Error returns a human readable description of the decode error.
+func (e *DecodeError) Error() string {
+	if e.RecordType == nil {
+		return fmt.Sprintf("unable to decode record at byte offset "+
+			"%d: %v", e.Offset, e.Err)
+	}
+
+	typ := *e.RecordType
+	name, ok := proofRecordNames[typ]
+	if !ok {
+		name = "unknown"
+	}
+
+	return fmt.Sprintf("unable to decode %s record (type %d) at byte "+
+		"offset %d: %v", name, typ, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeRaw decodes a single raw proof. If the proof can't be decoded, the
+// returned error is a *DecodeError that locates the offending record, unless
+// the failure can't be attributed to a single record.
+func DecodeRaw(rawProof []byte) (*Proof, error) {
+	var p Proof
+	err := p.Decode(bytes.NewReader(rawProof))
+	if err == nil {
+		return &p, nil
+	}
+
+	if !IsSingleProof(rawProof) {
+		return nil, err
+	}
+
+	if decodeErr := locateDecodeError(rawProof); decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return nil, err
+}
+
+// locateDecodeError walks the TLV stream of the given raw proof record by
+// record and returns the first record that can't be decoded. Nil is returned
+// if all records can be decoded on their own.
+func locateDecodeError(rawProof []byte) *DecodeError {
+	var (
+		p       Proof
+		records = make(map[tlv.Type]tlv.Record)
+		r       = bytes.NewReader(rawProof[PrefixMagicBytesLength:])
+		buf     [8]byte
+		minType tlv.Type
+	)
+	for _, record := range p.DecodeRecords() {
+		records[record.Type()] = record
+	}
+
+	for {
+		offset := len(rawProof) - r.Len()
+
+		t, err := tlv.ReadVarInt(r, &buf)
+		switch {
+		case err == io.EOF:
+			return nil
+
+		case err != nil:
+			return &DecodeError{
+				Offset: offset,
+				Err: fmt.Errorf("invalid record type: %w",
+					err),
+			}
+		}
+		typ := tlv.Type(t)
+
+		// The types of a canonical stream are strictly increasing.
+		if offset > PrefixMagicBytesLength && typ < minType {
+			return &DecodeError{
+				RecordType: &typ,
+				Offset:     offset,
+				Err:        tlv.ErrStreamNotCanonical,
+			}
+		}
+		minType = typ + 1
+
+		length, err := tlv.ReadVarInt(r, &buf)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return &DecodeError{
+				RecordType: &typ,
+				Offset:     offset,
+				Err: fmt.Errorf("invalid record length: %w",
+					err),
+			}
+		}
+
+		if length > uint64(r.Len()) {
+			return &DecodeError{
+				RecordType: &typ,
+				Offset:     offset,
+				Err: fmt.Errorf("record length %d exceeds the "+
+					"remaining %d bytes", length, r.Len()),
+			}
+		}
+
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return &DecodeError{
+				RecordType: &typ,
+				Offset:     offset,
+				Err:        err,
+			}
+		}
+
+		// Unknown records are skipped by the decoder.
+		record, ok := records[typ]
+		if !ok {
+			continue
+		}
+
+		valueReader := bytes.NewReader(value)
+		err = record.Decode(valueReader, length)
+		if err == nil && valueReader.Len() != 0 {
+			err = fmt.Errorf("%d trailing bytes", valueReader.Len())
+		}
+		if err != nil {
+			return &DecodeError{
+				RecordType: &typ,
+				Offset:     offset,
+				Err:        err,
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// rawTestProof builds a raw proof from the given TLV records, which are
+// written as they are, without any validation.
+func rawTestProof(records ...[]byte) []byte {
+	var b bytes.Buffer
+	b.Write(PrefixMagicBytes[:])
+	for _, record := range records {
+		b.Write(record)
+	}
+
+	return b.Bytes()
+}
+
+// rawTestRecord encodes a single TLV record with the given type and value.
+func rawTestRecord(t *testing.T, typ tlv.Type, value []byte) []byte {
+	var (
+		b   bytes.Buffer
+		buf [8]byte
+	)
+	require.NoError(t, tlv.WriteVarInt(&b, uint64(typ), &buf))
+	require.NoError(t, tlv.WriteVarInt(&b, uint64(len(value)), &buf))
+	b.Write(value)
+
+	return b.Bytes()
+}
+
+// TestDecodeRaw tests that a raw proof that can't be decoded is reported with
+// the type and offset of the offending record.
+func TestDecodeRaw(t *testing.T) {
+	t.Parallel()
+
+	proofBlob := readTestProofBlob(t, proofHexFileName)
+
+	// A valid proof is decoded as usual.
+	p, err := DecodeRaw(proofBlob)
+	require.NoError(t, err)
+
+	var expected Proof
+	require.NoError(t, expected.Decode(bytes.NewReader(proofBlob)))
+	require.Equal(t, expected.Asset.ID(), p.Asset.ID())
+
+	versionRecord := rawTestRecord(t, VersionType, make([]byte, 4))
+	versionOffset := PrefixMagicBytesLength
+	nextOffset := versionOffset + len(versionRecord)
+
+	testCases := []struct {
+		name       string
+		rawProof   []byte
+		recordType *tlv.Type
+		offset     int
+		err        error
+	}{{
+		name: "invalid static record length",
+		rawProof: rawTestProof(
+			versionRecord,
+			rawTestRecord(t, BlockHeaderType, make([]byte, 10)),
+		),
+		recordType: ptrTo(BlockHeaderType),
+		offset:     nextOffset,
+	}, {
+		name: "record longer than proof",
+		rawProof: rawTestProof(
+			versionRecord,
+			rawTestRecord(t, AnchorTxType, make([]byte, 10))[:5],
+		),
+		recordType: ptrTo(AnchorTxType),
+		offset:     nextOffset,
+	}, {
+		name: "records out of order",
+		rawProof: rawTestProof(
+			rawTestRecord(t, BlockHeightType, make([]byte, 4)),
+			versionRecord,
+		),
+		recordType: ptrTo(VersionType),
+		offset:     versionOffset + 6,
+		err:        tlv.ErrStreamNotCanonical,
+	}, {
+		name:     "truncated record type",
+		rawProof: rawTestProof(versionRecord, []byte{0xfd}),
+		offset:   nextOffset,
+		err:      io.ErrUnexpectedEOF,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := DecodeRaw(tc.rawProof)
+			require.Error(t, err)
+
+			var decodeErr *DecodeError
+			require.True(t, errors.As(err, &decodeErr), err)
+			require.Equal(t, tc.recordType, decodeErr.RecordType)
+			require.Equal(t, tc.offset, decodeErr.Offset)
+
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+
+	// A proof that is cut off in the middle of a record is reported at
+	// that record.
+	_, err = DecodeRaw(proofBlob[:len(proofBlob)/2])
+	var decodeErr *DecodeError
+	require.True(t, errors.As(err, &decodeErr), err)
+	require.NotNil(t, decodeErr.RecordType)
+	require.Less(t, decodeErr.Offset, len(proofBlob)/2)
+}
+
+// ptrTo returns a pointer to a copy of the given value.
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,164 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/fn"
+)
+
+var (
+	// ErrDeliveryPaused is returned when attempting to pause a proof
+	// delivery that is already paused.
+	ErrDeliveryPaused = errors.New("proof delivery already paused")
+
+	// ErrDeliveryNotPaused is returned when attempting to resume a proof
+	// delivery that isn't paused.
+	ErrDeliveryNotPaused = errors.New("proof delivery not paused")
+)
+
+// DeliveryID identifies the delivery of a proof by the transfer output the
+// proof is delivered for.
+type DeliveryID struct {
+	// AnchorOutPoint is the outpoint of the anchor output of the transfer
+	// output.
+	AnchorOutPoint wire.OutPoint
+
+	// ScriptKey is the script key of the transfer output.
+	ScriptKey asset.SerializedKey
+}
+
+// NewDeliveryID creates the ID of the proof delivery for the transfer output
+// with the given anchor outpoint and script key.
+func NewDeliveryID(anchorOutPoint wire.OutPoint,
+	scriptKey *btcec.PublicKey) DeliveryID {
+
+	return DeliveryID{
+		AnchorOutPoint: anchorOutPoint,
+		ScriptKey:      asset.ToSerialized(scriptKey),
+	}
+}
+
+// String returns a human-readable representation of the delivery ID.
+func (d DeliveryID) String() string {
+	return fmt.Sprintf("%v/%x", d.AnchorOutPoint, d.ScriptKey[:])
+}
+
+// PausedDeliveries keeps track of the proof deliveries that were paused by the
+// user. A courier doesn't attempt to deliver a paused proof until the delivery
+// is resumed. The paused state is only kept in memory, so all deliveries are
+// resumed when the daemon restarts.
+type PausedDeliveries struct {
+	// paused maps the ID of each paused delivery to a channel that is
+	// closed once the delivery is resumed.
+	paused map[DeliveryID]chan struct{}
+
+	mtx sync.Mutex
+}
+
+// NewPausedDeliveries creates a new, empty set of paused deliveries.
+func NewPausedDeliveries() *PausedDeliveries {
+	return &PausedDeliveries{
+		paused: make(map[DeliveryID]chan struct{}),
+	}
+}
+
+// Pause pauses the proof delivery with the given ID. A delivery can be paused
+// before it is started, in which case the courier waits for it to be resumed
+// before the first delivery attempt.
+func (p *PausedDeliveries) Pause(id DeliveryID) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if _, ok := p.paused[id]; ok {
+		return fmt.Errorf("%w: %v", ErrDeliveryPaused, id)
+	}
+
+	p.paused[id] = make(chan struct{})
+
+	return nil
+}
+
+// Resume resumes the paused proof delivery with the given ID.
+func (p *PausedDeliveries) Resume(id DeliveryID) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	resumed, ok := p.paused[id]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrDeliveryNotPaused, id)
+	}
+
+	close(resumed)
+	delete(p.paused, id)
+
+	return nil
+}
+
+// waitUntilResumed blocks for as long as the proof delivery with the given ID
+// is paused. If the delivery is paused, a ReceiverProofDeliveryPausedEvent is
+// published first. The returned boolean indicates whether the delivery was
+// paused. It is safe to call this method on a nil receiver, in which case no
+// delivery is ever paused.
+func (p *PausedDeliveries) waitUntilResumed(ctx context.Context, id DeliveryID,
+	publishEvent func(fn.Event)) (bool, error) {
+
+	if p == nil {
+		return false, nil
+	}
+
+	p.mtx.Lock()
+	resumed, ok := p.paused[id]
+	p.mtx.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	log.Infof("Proof delivery %v is paused, waiting for it to be resumed",
+		id)
+	publishEvent(NewReceiverProofDeliveryPausedEvent(id))
+
+	select {
+	case <-resumed:
+		log.Infof("Proof delivery %v resumed", id)
+		return true, nil
+
+	case <-ctx.Done():
+		return true, fmt.Errorf("proof delivery %v canceled while "+
+			"paused", id)
+	}
+}
+
+// ReceiverProofDeliveryPausedEvent is an event that is sent to a subscriber
+// when a courier holds back the delivery of a proof because the delivery was
+// paused by the user.
+type ReceiverProofDeliveryPausedEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// DeliveryID is the ID of the paused delivery.
+	DeliveryID DeliveryID
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *ReceiverProofDeliveryPausedEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewReceiverProofDeliveryPausedEvent creates a new
+// ReceiverProofDeliveryPausedEvent.
+func NewReceiverProofDeliveryPausedEvent(
+	id DeliveryID) *ReceiverProofDeliveryPausedEvent {
+
+	return &ReceiverProofDeliveryPausedEvent{
+		timestamp:  time.Now().UTC(),
+		DeliveryID: id,
+	}
+}
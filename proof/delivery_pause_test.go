@@ -0,0 +1,83 @@
+package proof
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPausedDeliveries tests that a paused proof delivery blocks the courier
+// until it is resumed.
+func TestPausedDeliveries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	paused := NewPausedDeliveries()
+	id := NewDeliveryID(test.RandOp(t), test.RandPubKey(t))
+	otherID := NewDeliveryID(test.RandOp(t), test.RandPubKey(t))
+
+	var events []fn.Event
+	publish := func(event fn.Event) {
+		events = append(events, event)
+	}
+
+	// A delivery that isn't paused doesn't block and can't be resumed.
+	wasPaused, err := paused.waitUntilResumed(ctx, id, publish)
+	require.NoError(t, err)
+	require.False(t, wasPaused)
+	require.Empty(t, events)
+	require.ErrorIs(t, paused.Resume(id), ErrDeliveryNotPaused)
+
+	// A delivery can only be paused once.
+	require.NoError(t, paused.Pause(id))
+	require.ErrorIs(t, paused.Pause(id), ErrDeliveryPaused)
+
+	// Pausing one delivery doesn't affect any other.
+	wasPaused, err = paused.waitUntilResumed(ctx, otherID, publish)
+	require.NoError(t, err)
+	require.False(t, wasPaused)
+
+	// The paused delivery blocks until it is resumed and publishes a pause
+	// event.
+	done := make(chan error, 1)
+	go func() {
+		_, err := paused.waitUntilResumed(ctx, id, publish)
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("paused delivery didn't block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, paused.Resume(id))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatalf("resumed delivery is still blocked")
+	}
+
+	require.Len(t, events, 1)
+	pauseEvent, ok := events[0].(*ReceiverProofDeliveryPausedEvent)
+	require.True(t, ok)
+	require.Equal(t, id, pauseEvent.DeliveryID)
+
+	// A paused delivery gives up once its context is canceled.
+	require.NoError(t, paused.Pause(id))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = paused.waitUntilResumed(cancelCtx, id, publish)
+	require.Error(t, err)
+
+	// Without a set of paused deliveries, nothing is ever paused.
+	var noPaused *PausedDeliveries
+	wasPaused, err = noPaused.waitUntilResumed(ctx, id, publish)
+	require.NoError(t, err)
+	require.False(t, wasPaused)
+}
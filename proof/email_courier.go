@@ -0,0 +1,1034 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/fn"
+)
+
+const (
+	// emailProofSubject is the subject prefix of an email that carries a
+	// proof.
+	emailProofSubject = "tapd-proof"
+
+	// emailAckSubject is the subject prefix of an email that carries the
+	// ACK of a received proof.
+	emailAckSubject = "tapd-ack"
+
+	// emailAttachmentName is the file name of the attachment that carries
+	// the encrypted proof or ACK.
+	emailAttachmentName = "tapd-message.bin"
+
+	// defaultEmailPollInterval is the interval at which the mailbox is
+	// polled for new messages if no interval is configured.
+	defaultEmailPollInterval = time.Minute
+)
+
+// emailKeyDomain is the domain separation tag used to derive the key that
+// encrypts the attachments of a proof delivery from the ECDH shared secret.
+var emailKeyDomain = []byte("tapd email courier")
+
+// EmailCourierCfg is the config of the mail servers used by the email proof
+// courier. Proofs and ACKs are sent over SMTP and received by polling a POP3
+// mailbox.
+type EmailCourierCfg struct {
+	SMTPHost string `long:"smtphost" description:"The host:port of the SMTP server used to send proofs and acknowledgements. STARTTLS is used if the server supports it."`
+
+	SMTPUser string `long:"smtpuser" description:"The user name to authenticate with at the SMTP server."`
+
+	SMTPPassword string `long:"smtppassword" description:"The password to authenticate with at the SMTP server."`
+
+	FromAddr string `long:"fromaddr" description:"The email address proofs and acknowledgements are sent from. This must be the address of the polled mailbox, as receivers send their acknowledgements to it."`
+
+	POP3Host string `long:"pop3host" description:"The host:port of the POP3 server (TLS) of the mailbox that is polled for inbound proofs and acknowledgements."`
+
+	POP3User string `long:"pop3user" description:"The user name to authenticate with at the POP3 server."`
+
+	POP3Password string `long:"pop3password" description:"The password to authenticate with at the POP3 server."`
+
+	PollInterval time.Duration `long:"pollinterval" description:"The interval at which the mailbox is polled for inbound proofs and acknowledgements."`
+
+	EncSchemes []string `long:"encscheme" description:"An encryption scheme supported for proofs and acknowledgements sent by email, in order of preference. Can be specified multiple times. The schemes are advertised in the email courier address of new addresses. Only applies to the email courier, the other proof couriers don't negotiate an encryption scheme. Valid options: aes256gcm, chacha20poly1305, xchacha20poly1305. Defaults to aes256gcm."`
+}
+
+// Validate returns an error if the config can't be used to deliver or receive
+// proofs.
+func (c *EmailCourierCfg) Validate() error {
+	switch {
+	case c.SMTPHost == "":
+		return fmt.Errorf("email courier SMTP host not set")
+
+	case c.POP3Host == "":
+		return fmt.Errorf("email courier POP3 host not set")
+
+	case c.FromAddr == "":
+		return fmt.Errorf("email courier from address not set")
+
+	case c.PollInterval < 0:
+		return fmt.Errorf("email courier poll interval must not be " +
+			"negative")
+	}
+
+	if _, err := mail.ParseAddress(c.FromAddr); err != nil {
+		return fmt.Errorf("invalid email courier from address: %w",
+			err)
+	}
+
+	if _, err := ParseEmailEncSchemes(c.EncSchemes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SupportedEncSchemes returns the encryption schemes supported by this node,
+// in order of preference.
+func (c *EmailCourierCfg) SupportedEncSchemes() ([]EmailEncScheme, error) {
+	return ParseEmailEncSchemes(c.EncSchemes)
+}
+
+// EmailCourierAddr is an email specific implementation of the CourierAddr
+// interface. The address is of the form email://user@domain, where
+// user@domain is the mailbox of the receiver. The receiver can advertise the
+// encryption schemes it supports with the enc query parameter, e.g.
+// email://user@domain?enc=chacha20poly1305,aes256gcm.
+type EmailCourierAddr struct {
+	addr url.URL
+}
+
+// NewEmailCourierAddr generates a new email courier address from a given URL.
+// This function also performs email specific address validation.
+func NewEmailCourierAddr(addr url.URL) (*EmailCourierAddr, error) {
+	if addr.Scheme != EmailCourierType {
+		return nil, fmt.Errorf("expected email courier protocol: %v",
+			addr.Scheme)
+	}
+
+	if addr.User == nil || addr.User.Username() == "" ||
+		addr.Hostname() == "" {
+
+		return nil, fmt.Errorf("email proof courier URI address " +
+			"must be of the form email://user@domain")
+	}
+
+	mailAddr := addr.User.Username() + "@" + addr.Hostname()
+	if _, err := mail.ParseAddress(mailAddr); err != nil {
+		return nil, fmt.Errorf("invalid email proof courier "+
+			"address: %w", err)
+	}
+
+	return &EmailCourierAddr{
+		addr: addr,
+	}, nil
+}
+
+// Url returns the url.URL representation of the courier address.
+func (e *EmailCourierAddr) Url() *url.URL {
+	return &e.addr
+}
+
+// MailAddr returns the email address of the receiver's mailbox.
+func (e *EmailCourierAddr) MailAddr() string {
+	return e.addr.User.Username() + "@" + e.addr.Hostname()
+}
+
+// AdvertisesEncSchemes returns true if the address explicitly lists the
+// encryption schemes supported by the receiver.
+func (e *EmailCourierAddr) AdvertisesEncSchemes() bool {
+	return e.addr.Query().Has(emailEncSchemeParam)
+}
+
+// EncSchemes returns the encryption schemes advertised by the receiver, in
+// order of preference. Receivers that don't advertise any scheme only support
+// the default scheme. Schemes unknown to this node are returned as well, as
+// they're skipped during the negotiation.
+func (e *EmailCourierAddr) EncSchemes() []EmailEncScheme {
+	var schemes []EmailEncScheme
+	param := e.addr.Query().Get(emailEncSchemeParam)
+	for _, name := range strings.Split(param, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		schemes = append(schemes, EmailEncScheme(name))
+	}
+
+	if len(schemes) == 0 {
+		return []EmailEncScheme{DefaultEmailEncScheme}
+	}
+
+	return schemes
+}
+
+// NegotiateEncScheme returns the encryption scheme used to send messages to
+// the receiver, which is the receiver's most preferred scheme among the ones
+// supported by the given config. ErrNoCommonEncScheme is returned if there is
+// no such scheme. The negotiation only happens between the sender and the
+// receiver of an email delivery, it isn't part of the courier handshake shared
+// with the other couriers.
+func (e *EmailCourierAddr) NegotiateEncScheme(
+	cfg *EmailCourierCfg) (EmailEncScheme, error) {
+
+	supported := []EmailEncScheme{DefaultEmailEncScheme}
+	if cfg != nil {
+		var err error
+		supported, err = cfg.SupportedEncSchemes()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return NegotiateEmailEncScheme(e.EncSchemes(), supported)
+}
+
+// NewCourier generates a new courier service handle. The email courier uses
+// the same delivery flow as the hashmail courier, with an email backed
+// mailbox.
+func (e *EmailCourierAddr) NewCourier(_ context.Context, cfg *CourierCfg,
+	recipient Recipient) (Courier, error) {
+
+	if cfg.EmailCfg == nil {
+		return nil, fmt.Errorf("email proof courier not configured")
+	}
+	if err := cfg.EmailCfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	mailbox, err := NewEmailMailbox(
+		cfg.EmailCfg, NewSMTPPOP3Transport(cfg.EmailCfg),
+		cfg.SharedKeyDeriver, e, recipient,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HashMailCourier{
+		cfg: &HashMailCourierCfg{
+			ReceiverAckTimeout: cfg.ReceiverAckTimeout,
+			BackoffCfg:         cfg.BackoffCfg,
+		},
+		mode:             cfg.Mode(recipient.ReceiveType),
+		recipient:        recipient,
+		mailbox:          mailbox,
+		deliveryLog:      cfg.DeliveryLog,
+		receiptSigner:    cfg.ReceiptSigner,
+		pausedDeliveries: cfg.PausedDeliveries,
+		compressFiles:    cfg.compressFiles(&e.addr),
+		subscribers:      make(map[uint64]*fn.EventReceiver[fn.Event]),
+	}, nil
+}
+
+// MailTransport sends and fetches raw email messages.
+type MailTransport interface {
+	// Send sends the given raw message from the given address to the
+	// given address.
+	Send(ctx context.Context, from, to string, msg []byte) error
+
+	// Fetch fetches all messages of the mailbox and passes each of them to
+	// the given function. Messages for which the function returns true
+	// are deleted from the mailbox.
+	Fetch(ctx context.Context, handle func(msg []byte) bool) error
+}
+
+// EmailMailbox is an implementation of the ProofMailbox interface that sends
+// proofs and ACKs as encrypted email attachments and receives them by polling
+// a mailbox. The attachments are encrypted with ECIES: the sender creates an
+// ephemeral key and derives the encryption key from the ECDH shared secret of
+// that key and the internal key of the recipient's address. The ephemeral
+// public key is sent along with every message, so the receiver can derive the
+// same secret with its private key, while the mail servers in between can't.
+// Proofs are encrypted with the scheme negotiated from the schemes advertised
+// by the receiver, ACKs with the scheme and key of the proof they acknowledge.
+type EmailMailbox struct {
+	cfg *EmailCourierCfg
+
+	transport MailTransport
+
+	// keyDeriver derives the shared secret of received proofs. This is
+	// only needed to receive proofs.
+	keyDeriver SharedKeyDeriver
+
+	// recipient is the recipient of the proof delivery.
+	recipient Recipient
+
+	// receiverAddr is the email address of the receiver's mailbox.
+	receiverAddr string
+
+	// sendScheme is the encryption scheme used to send proofs.
+	sendScheme EmailEncScheme
+
+	// supported are the encryption schemes supported by this node.
+	supported []EmailEncScheme
+
+	// sendKey is the ephemeral public key of the proofs sent through this
+	// mailbox. It is created when the first proof is sent.
+	sendKey *btcec.PublicKey
+
+	// sendCipher is the AEAD cipher derived from the ephemeral key, which
+	// encrypts the proofs and decrypts their ACKs.
+	sendCipher cipher.AEAD
+
+	// replyAddr is the address of the sender of the last proof that was
+	// read. The ACK for the proof is sent to this address.
+	replyAddr string
+
+	// replyScheme is the encryption scheme of the last proof that was
+	// read. The ACK for the proof is encrypted with the same scheme.
+	replyScheme EmailEncScheme
+
+	// replyKey is the ephemeral key of the last proof that was read.
+	replyKey *btcec.PublicKey
+
+	// replyCipher is the AEAD cipher the last proof that was read was
+	// decrypted with. The ACK for the proof is encrypted with it.
+	replyCipher cipher.AEAD
+
+	mtx sync.Mutex
+}
+
+// NewEmailMailbox creates a new email backed mailbox for the proof delivery to
+// the given recipient. ErrNoCommonEncScheme is returned if the receiver
+// doesn't support any of the encryption schemes supported by the config.
+func NewEmailMailbox(cfg *EmailCourierCfg, transport MailTransport,
+	keyDeriver SharedKeyDeriver, courierAddr *EmailCourierAddr,
+	recipient Recipient) (*EmailMailbox, error) {
+
+	sendScheme, err := courierAddr.NegotiateEncScheme(cfg)
+	if err != nil {
+		return nil, err
+	}
+	supported, err := cfg.SupportedEncSchemes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailMailbox{
+		cfg:          cfg,
+		transport:    transport,
+		keyDeriver:   keyDeriver,
+		recipient:    recipient,
+		receiverAddr: courierAddr.MailAddr(),
+		sendScheme:   sendScheme,
+		supported:    supported,
+	}, nil
+}
+
+// Init creates a mailbox given the specified stream ID. Email mailboxes exist
+// independently of the stream, so this is a no-op.
+func (e *EmailMailbox) Init(context.Context, streamID) error {
+	return nil
+}
+
+// WriteProof writes the proof to the mailbox specified by the sid.
+func (e *EmailMailbox) WriteProof(ctx context.Context, sid streamID,
+	proof Blob) error {
+
+	sendKey, sendCipher, err := e.sendKeys()
+	if err != nil {
+		return err
+	}
+
+	return e.send(
+		ctx, e.receiverAddr, emailProofSubject, e.sendScheme, sendKey,
+		sendCipher, sid, proof,
+	)
+}
+
+// sendKeys returns the ephemeral key and the cipher proofs are sent with,
+// creating them from the internal key of the recipient if they don't exist
+// yet.
+func (e *EmailMailbox) sendKeys() (*btcec.PublicKey, cipher.AEAD, error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.sendCipher != nil {
+		return e.sendKey, e.sendCipher, nil
+	}
+
+	receiverKey := e.recipient.InternalKey.PubKey
+	if receiverKey == nil {
+		return nil, nil, fmt.Errorf("internal key of recipient " +
+			"unknown, unable to encrypt proof")
+	}
+
+	sendKey, sharedSecret, err := newEmailSendSecret(receiverKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to derive shared "+
+			"secret: %w", err)
+	}
+	sendCipher, err := newEmailCipher(e.sendScheme, sharedSecret, sendKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e.sendKey, e.sendCipher = sendKey, sendCipher
+
+	return sendKey, sendCipher, nil
+}
+
+// ReadProof reads a proof from the mailbox. This is a blocking method.
+func (e *EmailMailbox) ReadProof(ctx context.Context,
+	sid streamID) (Blob, error) {
+
+	if e.keyDeriver == nil {
+		return nil, fmt.Errorf("no shared key deriver configured, " +
+			"unable to decrypt proof")
+	}
+
+	openCipher := func(msg *emailMessage) (cipher.AEAD, error) {
+		isSupported := func(s EmailEncScheme) bool {
+			return s == msg.encScheme
+		}
+		if !fn.Any(e.supported, isSupported) {
+			return nil, fmt.Errorf("unsupported encryption "+
+				"scheme %v", msg.encScheme)
+		}
+		if msg.ephemeralKey == nil {
+			return nil, fmt.Errorf("no ephemeral key")
+		}
+
+		sharedSecret, err := e.keyDeriver.DeriveSharedKey(
+			ctx, msg.ephemeralKey,
+			&e.recipient.InternalKey.KeyLocator,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive shared "+
+				"secret: %w", err)
+		}
+
+		return newEmailCipher(
+			msg.encScheme, sharedSecret, msg.ephemeralKey,
+		)
+	}
+
+	payload, msg, aead, err := e.receive(
+		ctx, emailProofSubject, sid, openCipher,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mtx.Lock()
+	e.replyAddr = msg.from
+	e.replyScheme = msg.encScheme
+	e.replyKey = msg.ephemeralKey
+	e.replyCipher = aead
+	e.mtx.Unlock()
+
+	return payload, nil
+}
+
+// AckProof sends an ACK from the receiver to the sender that a proof has been
+// received. The ACK is sent to the address the proof was received from.
+func (e *EmailMailbox) AckProof(ctx context.Context, sid streamID,
+	receipt *DeliveryReceipt) error {
+
+	e.mtx.Lock()
+	replyAddr, replyScheme := e.replyAddr, e.replyScheme
+	replyKey, replyCipher := e.replyKey, e.replyCipher
+	e.mtx.Unlock()
+
+	if replyAddr == "" {
+		return fmt.Errorf("no proof received to acknowledge")
+	}
+
+	return e.send(
+		ctx, replyAddr, emailAckSubject, replyScheme, replyKey,
+		replyCipher, sid, encodeAckMsg(receipt),
+	)
+}
+
+// RecvAck waits for the sender to receive the ack from the receiver.
+func (e *EmailMailbox) RecvAck(ctx context.Context,
+	sid streamID) (*DeliveryReceipt, error) {
+
+	e.mtx.Lock()
+	sendKey, sendCipher := e.sendKey, e.sendCipher
+	e.mtx.Unlock()
+
+	if sendCipher == nil {
+		return nil, fmt.Errorf("no proof sent to be acknowledged")
+	}
+
+	// The receiver acknowledges a proof with the key and scheme the proof
+	// was encrypted with.
+	openCipher := func(msg *emailMessage) (cipher.AEAD, error) {
+		if msg.encScheme != e.sendScheme {
+			return nil, fmt.Errorf("unexpected encryption "+
+				"scheme %v", msg.encScheme)
+		}
+		if msg.ephemeralKey == nil ||
+			!msg.ephemeralKey.IsEqual(sendKey) {
+
+			return nil, fmt.Errorf("unexpected ephemeral key")
+		}
+
+		return sendCipher, nil
+	}
+
+	payload, _, _, err := e.receive(
+		ctx, emailAckSubject, sid, openCipher,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAckMsg(payload)
+}
+
+// CleanUp attempts to tear down the mailbox as specified by the passed sid.
+// Received messages are deleted when they're read, so this is a no-op.
+func (e *EmailMailbox) CleanUp(context.Context, streamID) error {
+	return nil
+}
+
+// send encrypts the payload with the given cipher and sends it as an
+// attachment to the given address, along with the scheme and the ephemeral key
+// the cipher was derived from.
+func (e *EmailMailbox) send(ctx context.Context, to, subjectPrefix string,
+	scheme EmailEncScheme, ephemeralKey *btcec.PublicKey, aead cipher.AEAD,
+	sid streamID, payload []byte) error {
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	ciphertext := aead.Seal(nonce, nonce, payload, sid[:])
+
+	msg, err := encodeEmail(
+		e.cfg.FromAddr, to, emailSubject(subjectPrefix, sid), scheme,
+		ephemeralKey, ciphertext,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to encode email: %w", err)
+	}
+
+	return e.transport.Send(ctx, e.cfg.FromAddr, to, msg)
+}
+
+// receive polls the mailbox until a message with the given subject arrives
+// that can be decrypted with the cipher returned by openCipher. The decrypted
+// payload, the message and the cipher are returned.
+func (e *EmailMailbox) receive(ctx context.Context, subjectPrefix string,
+	sid streamID, openCipher func(*emailMessage) (cipher.AEAD, error)) (
+	[]byte, *emailMessage, cipher.AEAD, error) {
+
+	subject := emailSubject(subjectPrefix, sid)
+
+	pollInterval := e.cfg.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultEmailPollInterval
+	}
+
+	for {
+		var (
+			payload  []byte
+			received *emailMessage
+			aead     cipher.AEAD
+		)
+		err := e.transport.Fetch(ctx, func(raw []byte) bool {
+			if payload != nil {
+				return false
+			}
+
+			msg, err := decodeEmail(raw)
+			if err != nil || msg.subject != subject {
+				return false
+			}
+
+			msgCipher, err := openCipher(msg)
+			if err != nil {
+				log.Warnf("Ignoring email from %v: %v",
+					msg.from, err)
+				return false
+			}
+
+			attachment := msg.attachment
+			nonceSize := msgCipher.NonceSize()
+			if len(attachment) < nonceSize {
+				return false
+			}
+			plaintext, err := msgCipher.Open(
+				nil, attachment[:nonceSize],
+				attachment[nonceSize:], sid[:],
+			)
+			if err != nil {
+				log.Warnf("Ignoring email with invalid "+
+					"attachment from %v: %v", msg.from, err)
+				return false
+			}
+
+			payload, received, aead = plaintext, msg, msgCipher
+			return true
+		})
+		if err != nil {
+			log.Warnf("Unable to fetch emails: %v", err)
+		}
+		if payload != nil {
+			return payload, received, aead, nil
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		}
+	}
+}
+
+// emailSubject returns the subject of the email with the given prefix for the
+// given stream ID. We only use a hash of the stream ID, to keep the subject
+// short enough to not be folded.
+func emailSubject(prefix string, sid streamID) string {
+	tag := sha256.Sum256(sid[:])
+	return prefix + " " + hex.EncodeToString(tag[:16])
+}
+
+// encodeEmail encodes a raw email message with the given payload, encrypted
+// with the given scheme and ephemeral key, attached.
+func encodeEmail(from, to, subject string, encScheme EmailEncScheme,
+	ephemeralKey *btcec.PublicKey, payload []byte) ([]byte, error) {
+
+	var (
+		body   bytes.Buffer
+		writer = multipart.NewWriter(&body)
+	)
+
+	textPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=utf-8"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(
+		textPart, "This email carries an encrypted Taproot Assets "+
+			"message for tapd.\r\n",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"application/octet-stream"},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition": {mime.FormatMediaType(
+			"attachment", map[string]string{
+				"filename": emailAttachmentName,
+			},
+		)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// We wrap the base64 encoded payload at 76 characters, as required by
+	// RFC 2045.
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	for len(encoded) > 76 {
+		_, err = io.WriteString(attachment, encoded[:76]+"\r\n")
+		if err != nil {
+			return nil, err
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := io.WriteString(attachment, encoded+"\r\n"); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&msg, "%s: %s\r\n", emailEncSchemeHeader, encScheme)
+	fmt.Fprintf(&msg, "%s: %x\r\n", emailEphemeralKeyHeader,
+		ephemeralKey.SerializeCompressed())
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s\r\n\r\n", mime.FormatMediaType(
+		"multipart/mixed", map[string]string{
+			"boundary": writer.Boundary(),
+		},
+	))
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// emailMessage is a decoded email message of the email proof courier.
+type emailMessage struct {
+	// from is the address of the sender.
+	from string
+
+	// subject is the subject of the email.
+	subject string
+
+	// encScheme is the scheme the attachment is encrypted with.
+	encScheme EmailEncScheme
+
+	// ephemeralKey is the ephemeral key of the sender of the proof the
+	// attachment is encrypted with. This is nil if the message doesn't
+	// carry a valid key.
+	ephemeralKey *btcec.PublicKey
+
+	// attachment is the encrypted payload.
+	attachment []byte
+}
+
+// decodeEmail decodes a raw email message and returns its sender address,
+// subject, encryption scheme, ephemeral key and the payload of its attachment.
+// Messages that don't specify an encryption scheme use the default scheme.
+func decodeEmail(raw []byte) (*emailMessage, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %w", err)
+	}
+	subject := strings.TrimSpace(msg.Header.Get("Subject"))
+
+	encScheme := EmailEncScheme(strings.ToLower(strings.TrimSpace(
+		msg.Header.Get(emailEncSchemeHeader),
+	)))
+	if encScheme == "" {
+		encScheme = DefaultEmailEncScheme
+	}
+
+	var ephemeralKey *btcec.PublicKey
+	keyBytes, err := hex.DecodeString(strings.TrimSpace(
+		msg.Header.Get(emailEphemeralKeyHeader),
+	))
+	if err == nil && len(keyBytes) > 0 {
+		ephemeralKey, _ = btcec.ParsePubKey(keyBytes)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(
+		msg.Header.Get("Content-Type"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("unexpected content type %v",
+			mediaType)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, fmt.Errorf("no attachment found: %w",
+				err)
+		}
+
+		if part.FileName() != emailAttachmentName {
+			continue
+		}
+
+		var payloadReader io.Reader = part
+		encoding := part.Header.Get("Content-Transfer-Encoding")
+		if strings.EqualFold(encoding, "base64") {
+			payloadReader = base64.NewDecoder(
+				base64.StdEncoding, newCRLFStripper(part),
+			)
+		}
+
+		payload, err := io.ReadAll(payloadReader)
+		if err != nil {
+			return nil, err
+		}
+
+		return &emailMessage{
+			from:         from.Address,
+			subject:      subject,
+			encScheme:    encScheme,
+			ephemeralKey: ephemeralKey,
+			attachment:   payload,
+		}, nil
+	}
+}
+
+// crlfStripper is a reader that drops all line breaks, so wrapped base64 can be
+// decoded.
+type crlfStripper struct {
+	r io.Reader
+}
+
+// newCRLFStripper creates a new reader that drops all line breaks of the given
+// reader.
+func newCRLFStripper(r io.Reader) *crlfStripper {
+	return &crlfStripper{r: r}
+}
+
+// Read reads from the underlying reader, dropping all line breaks.
+func (c *crlfStripper) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+
+	out := 0
+	for _, b := range p[:n] {
+		if b == '\r' || b == '\n' {
+			continue
+		}
+
+		p[out] = b
+		out++
+	}
+
+	return out, err
+}
+
+// SMTPPOP3Transport is a MailTransport that sends messages over SMTP and
+// fetches them from a POP3 mailbox over TLS.
+type SMTPPOP3Transport struct {
+	cfg *EmailCourierCfg
+}
+
+// NewSMTPPOP3Transport creates a new mail transport for the given config.
+func NewSMTPPOP3Transport(cfg *EmailCourierCfg) *SMTPPOP3Transport {
+	return &SMTPPOP3Transport{
+		cfg: cfg,
+	}
+}
+
+// Send sends the given raw message from the given address to the given
+// address.
+func (s *SMTPPOP3Transport) Send(_ context.Context, from, to string,
+	msg []byte) error {
+
+	var auth smtp.Auth
+	if s.cfg.SMTPUser != "" {
+		host, _, err := net.SplitHostPort(s.cfg.SMTPHost)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP host: %w", err)
+		}
+
+		auth = smtp.PlainAuth(
+			"", s.cfg.SMTPUser, s.cfg.SMTPPassword, host,
+		)
+	}
+
+	return smtp.SendMail(s.cfg.SMTPHost, auth, from, []string{to}, msg)
+}
+
+// Fetch fetches all messages of the mailbox and passes each of them to the
+// given function. Messages for which the function returns true are deleted
+// from the mailbox.
+func (s *SMTPPOP3Transport) Fetch(ctx context.Context,
+	handle func(msg []byte) bool) error {
+
+	host, _, err := net.SplitHostPort(s.cfg.POP3Host)
+	if err != nil {
+		return fmt.Errorf("invalid POP3 host: %w", err)
+	}
+
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName: host,
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.POP3Host)
+	if err != nil {
+		return fmt.Errorf("unable to connect to POP3 server: %w", err)
+	}
+
+	// Make sure we don't block forever if the context is canceled.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client := newPOP3Client(conn)
+	defer client.Close()
+
+	err = client.Login(s.cfg.POP3User, s.cfg.POP3Password)
+	if err != nil {
+		return err
+	}
+
+	ids, err := client.List()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		msg, err := client.Retr(id)
+		if err != nil {
+			return err
+		}
+
+		if !handle(msg) {
+			continue
+		}
+
+		if err := client.Dele(id); err != nil {
+			return err
+		}
+	}
+
+	// Deleted messages are only removed once the session is ended
+	// properly.
+	return client.Quit()
+}
+
+// pop3Client is a minimal POP3 client, implementing the commands needed to
+// fetch and delete messages as described in RFC 1939.
+type pop3Client struct {
+	text *textproto.Conn
+}
+
+// newPOP3Client creates a new POP3 client using the given connection.
+func newPOP3Client(conn io.ReadWriteCloser) *pop3Client {
+	return &pop3Client{
+		text: textproto.NewConn(conn),
+	}
+}
+
+// readStatus reads a single status line and returns its message, or an error
+// if the status isn't positive.
+func (p *pop3Client) readStatus() (string, error) {
+	line, err := p.text.ReadLine()
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(line, "+OK") {
+		return "", fmt.Errorf("POP3 error: %v", line)
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+}
+
+// cmd sends a command and reads its status line.
+func (p *pop3Client) cmd(format string, args ...any) (string, error) {
+	id, err := p.text.Cmd(format, args...)
+	if err != nil {
+		return "", err
+	}
+
+	p.text.StartResponse(id)
+	defer p.text.EndResponse(id)
+
+	return p.readStatus()
+}
+
+// Login reads the greeting of the server and authenticates with the given
+// credentials.
+func (p *pop3Client) Login(user, password string) error {
+	if _, err := p.readStatus(); err != nil {
+		return fmt.Errorf("invalid POP3 greeting: %w", err)
+	}
+
+	if _, err := p.cmd("USER %s", user); err != nil {
+		return err
+	}
+	if _, err := p.cmd("PASS %s", password); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List returns the IDs of all messages in the mailbox.
+func (p *pop3Client) List() ([]int, error) {
+	id, err := p.text.Cmd("LIST")
+	if err != nil {
+		return nil, err
+	}
+
+	p.text.StartResponse(id)
+	defer p.text.EndResponse(id)
+
+	if _, err := p.readStatus(); err != nil {
+		return nil, err
+	}
+
+	lines, err := p.text.ReadDotLines()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		msgID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid POP3 listing: %v",
+				line)
+		}
+		ids = append(ids, msgID)
+	}
+
+	return ids, nil
+}
+
+// Retr retrieves the message with the given ID.
+func (p *pop3Client) Retr(msgID int) ([]byte, error) {
+	id, err := p.text.Cmd("RETR %d", msgID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.text.StartResponse(id)
+	defer p.text.EndResponse(id)
+
+	if _, err := p.readStatus(); err != nil {
+		return nil, err
+	}
+
+	return p.text.ReadDotBytes()
+}
+
+// Dele marks the message with the given ID as deleted.
+func (p *pop3Client) Dele(msgID int) error {
+	_, err := p.cmd("DELE %d", msgID)
+	return err
+}
+
+// Quit ends the session, which removes all messages marked as deleted.
+func (p *pop3Client) Quit() error {
+	_, err := p.cmd("QUIT")
+	return err
+}
+
+// Close closes the connection to the server.
+func (p *pop3Client) Close() error {
+	return p.text.Close()
+}
+
+// A compile-time assertion to ensure that the EmailMailbox meets the
+// ProofMailbox interface.
+var _ ProofMailbox = (*EmailMailbox)(nil)
+
+// A compile-time assertion to ensure that the SMTPPOP3Transport meets the
+// MailTransport interface.
+var _ MailTransport = (*SMTPPOP3Transport)(nil)
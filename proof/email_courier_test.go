@@ -0,0 +1,409 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSharedKeyDeriver derives shared keys with a single private key.
+type mockSharedKeyDeriver struct {
+	privKey *btcec.PrivateKey
+}
+
+// DeriveSharedKey returns the ECDH shared secret of the given ephemeral key and
+// the private key of the deriver.
+func (m *mockSharedKeyDeriver) DeriveSharedKey(_ context.Context,
+	ephemeralPubKey *btcec.PublicKey, _ *keychain.KeyLocator) ([32]byte,
+	error) {
+
+	ecdh := keychain.PrivKeyECDH{PrivKey: m.privKey}
+	return ecdh.ECDH(ephemeralPubKey)
+}
+
+// newTestEmailRecipient creates a recipient with a random internal key and the
+// shared key deriver of the receiver owning that key.
+func newTestEmailRecipient(t *testing.T) (Recipient, *mockSharedKeyDeriver) {
+	internalKey := test.RandPrivKey(t)
+
+	recipient := Recipient{
+		ScriptKey: test.RandPubKey(t),
+		AssetID:   [32]byte{1},
+		Amount:    100,
+		InternalKey: keychain.KeyDescriptor{
+			PubKey: internalKey.PubKey(),
+		},
+	}
+
+	return recipient, &mockSharedKeyDeriver{privKey: internalKey}
+}
+
+// mockMailTransport is an in-memory mail transport that delivers messages to
+// the mailbox of the receiving address.
+type mockMailTransport struct {
+	sync.Mutex
+
+	mailboxes map[string][][]byte
+}
+
+// newMockMailTransport creates a new in-memory mail transport.
+func newMockMailTransport() *mockMailTransport {
+	return &mockMailTransport{
+		mailboxes: make(map[string][][]byte),
+	}
+}
+
+// forAddr returns a transport that fetches from the mailbox of the given
+// address.
+func (m *mockMailTransport) forAddr(addr string) *mockMailbox {
+	return &mockMailbox{
+		mockMailTransport: m,
+		addr:              addr,
+	}
+}
+
+// mockMailbox is a view of the in-memory mail transport for a single mailbox.
+type mockMailbox struct {
+	*mockMailTransport
+
+	addr string
+}
+
+// Send sends the given raw message from the given address to the given
+// address.
+func (m *mockMailbox) Send(_ context.Context, _, to string,
+	msg []byte) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.mailboxes[to] = append(m.mailboxes[to], msg)
+
+	return nil
+}
+
+// Fetch fetches all messages of the mailbox and passes each of them to the
+// given function.
+func (m *mockMailbox) Fetch(_ context.Context,
+	handle func(msg []byte) bool) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	var remaining [][]byte
+	for _, msg := range m.mailboxes[m.addr] {
+		if !handle(msg) {
+			remaining = append(remaining, msg)
+		}
+	}
+	m.mailboxes[m.addr] = remaining
+
+	return nil
+}
+
+// newTestEmailCourierAddr parses the given email courier address.
+func newTestEmailCourierAddr(t *testing.T, addr string) *EmailCourierAddr {
+	addrURL, err := url.Parse(addr)
+	require.NoError(t, err)
+
+	courierAddr, err := NewEmailCourierAddr(*addrURL)
+	require.NoError(t, err)
+
+	return courierAddr
+}
+
+// TestEmailMailbox tests that a proof and its ACK can be exchanged between a
+// sender and a receiver through email mailboxes, without revealing the proof
+// to the mail servers.
+func TestEmailMailbox(t *testing.T) {
+	t.Parallel()
+
+	const (
+		senderAddr   = "sender@example.com"
+		receiverAddr = "receiver@example.com"
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	recipient, keyDeriver := newTestEmailRecipient(t)
+	transport := newMockMailTransport()
+	courierAddr := newTestEmailCourierAddr(t, "email://"+receiverAddr)
+
+	newMailbox := func(from string,
+		deriver SharedKeyDeriver) *EmailMailbox {
+
+		mailbox, err := NewEmailMailbox(
+			&EmailCourierCfg{
+				FromAddr:     from,
+				PollInterval: 10 * time.Millisecond,
+			}, transport.forAddr(from), deriver, courierAddr,
+			recipient,
+		)
+		require.NoError(t, err)
+
+		return mailbox
+	}
+	sender := newMailbox(senderAddr, nil)
+	receiver := newMailbox(receiverAddr, keyDeriver)
+
+	proofBlob := Blob(test.RandBytes(500))
+	senderSID := deriveSenderStreamID(recipient)
+	receiverSID := deriveReceiverStreamID(recipient)
+
+	require.NoError(t, sender.WriteProof(ctx, senderSID, proofBlob))
+
+	// The mail servers only ever see the encrypted proof.
+	transport.Lock()
+	require.Len(t, transport.mailboxes[receiverAddr], 1)
+	rawMsg := transport.mailboxes[receiverAddr][0]
+	transport.Unlock()
+	require.False(t, bytes.Contains(rawMsg, proofBlob))
+
+	// The key of the proof can't be derived from the public data of the
+	// address alone, so a receiver that knows the address but not the
+	// private internal key can't read the proof.
+	otherReceiver := newMailbox(receiverAddr, &mockSharedKeyDeriver{
+		privKey: test.RandPrivKey(t),
+	})
+	shortCtx, shortCancel := context.WithTimeout(
+		ctx, 50*time.Millisecond,
+	)
+	_, err := otherReceiver.ReadProof(shortCtx, senderSID)
+	shortCancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Neither can a receiver without a shared key deriver.
+	_, err = sender.ReadProof(ctx, senderSID)
+	require.ErrorContains(t, err, "no shared key deriver")
+
+	// The actual receiver reads the proof, which removes it from the
+	// mailbox, and acknowledges it to the sender.
+	receivedProof, err := receiver.ReadProof(ctx, senderSID)
+	require.NoError(t, err)
+	require.Equal(t, proofBlob, receivedProof)
+
+	transport.Lock()
+	require.Empty(t, transport.mailboxes[receiverAddr])
+	transport.Unlock()
+
+	receipt := signTestReceipt(
+		t, test.RandPrivKey(t), nil, sha256.Sum256(proofBlob),
+	)
+	require.NoError(t, receiver.AckProof(ctx, receiverSID, receipt))
+
+	receivedReceipt, err := sender.RecvAck(ctx, receiverSID)
+	require.NoError(t, err)
+	require.Equal(t, receipt, receivedReceipt)
+
+	// Every proof of the delivery is sent with the same ephemeral key, so
+	// a proof that is resent after a restart of the receiver can still be
+	// acknowledged.
+	require.NoError(t, sender.WriteProof(ctx, senderSID, proofBlob))
+	_, err = receiver.ReadProof(ctx, senderSID)
+	require.NoError(t, err)
+
+	// An ACK without a receipt is received as well.
+	require.NoError(t, receiver.AckProof(ctx, receiverSID, nil))
+	receivedReceipt, err = sender.RecvAck(ctx, receiverSID)
+	require.NoError(t, err)
+	require.Nil(t, receivedReceipt)
+}
+
+// TestEmailCourierAddr tests that email courier addresses are parsed and
+// validated correctly.
+func TestEmailCourierAddr(t *testing.T) {
+	t.Parallel()
+
+	addr, err := ParseCourierAddrString("email://proofs@example.com")
+	require.NoError(t, err)
+
+	emailAddr, ok := addr.(*EmailCourierAddr)
+	require.True(t, ok)
+	require.Equal(t, "proofs@example.com", emailAddr.MailAddr())
+
+	// Without a configured email courier, no courier can be created.
+	_, err = emailAddr.NewCourier(
+		context.Background(), &CourierCfg{}, Recipient{
+			ScriptKey: test.RandPubKey(t),
+		},
+	)
+	require.ErrorContains(t, err, "not configured")
+
+	for _, invalid := range []string{
+		"email://example.com", "email://@example.com",
+		"email://proofs@",
+	} {
+		invalidURL, err := url.Parse(invalid)
+		require.NoError(t, err)
+
+		_, err = NewEmailCourierAddr(*invalidURL)
+		require.Error(t, err, invalid)
+	}
+
+	// Only email courier addresses can advertise encryption schemes.
+	for _, other := range []string{
+		"hashmail://courier.example.com:443?enc=aes256gcm",
+		"universerpc://universe.example.com:10029?enc=aes256gcm",
+	} {
+		_, err := ParseCourierAddrString(other)
+		require.ErrorIs(t, err, ErrEncSchemeUnsupported, other)
+	}
+
+	addr, err = ParseCourierAddrString(
+		"email://proofs@example.com?enc=chacha20poly1305",
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t, []EmailEncScheme{EmailEncChaCha20Poly1305},
+		addr.(*EmailCourierAddr).EncSchemes(),
+	)
+}
+
+// TestEmailEncSchemeNegotiation tests that sender and receiver agree on the
+// most preferred encryption scheme they both support, and that a delivery is
+// refused if there is none.
+func TestEmailEncSchemeNegotiation(t *testing.T) {
+	t.Parallel()
+
+	const (
+		senderAddr   = "sender@example.com"
+		receiverAddr = "receiver@example.com"
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	// An address without any advertised scheme only supports the
+	// default scheme, unknown schemes are kept for the negotiation.
+	legacyAddr := newTestEmailCourierAddr(t, "email://"+receiverAddr)
+	require.False(t, legacyAddr.AdvertisesEncSchemes())
+	require.Equal(
+		t, []EmailEncScheme{DefaultEmailEncScheme},
+		legacyAddr.EncSchemes(),
+	)
+
+	courierAddr := newTestEmailCourierAddr(
+		t, "email://"+receiverAddr+"?enc=future,chacha20poly1305,"+
+			"aes256gcm",
+	)
+	require.True(t, courierAddr.AdvertisesEncSchemes())
+	require.Equal(t, []EmailEncScheme{
+		"future", EmailEncChaCha20Poly1305, EmailEncAES256GCM,
+	}, courierAddr.EncSchemes())
+
+	// Advertising our own schemes results in an address that lists them.
+	advertised := WithEmailEncSchemes(*legacyAddr.Url(), []EmailEncScheme{
+		EmailEncXChaCha20Poly1305, EmailEncAES256GCM,
+	})
+	advertisedAddr, err := NewEmailCourierAddr(advertised)
+	require.NoError(t, err)
+	require.Equal(t, []EmailEncScheme{
+		EmailEncXChaCha20Poly1305, EmailEncAES256GCM,
+	}, advertisedAddr.EncSchemes())
+
+	// Unknown schemes are refused in the config.
+	invalidCfg := &EmailCourierCfg{
+		SMTPHost:   "smtp.example.com:587",
+		POP3Host:   "pop3.example.com:995",
+		FromAddr:   senderAddr,
+		EncSchemes: []string{"rot13"},
+	}
+	require.ErrorContains(t, invalidCfg.Validate(), "unknown email")
+
+	recipient, keyDeriver := newTestEmailRecipient(t)
+	transport := newMockMailTransport()
+	newMailbox := func(from string, addr *EmailCourierAddr,
+		schemes ...string) (*EmailMailbox, error) {
+
+		return NewEmailMailbox(
+			&EmailCourierCfg{
+				FromAddr:     from,
+				PollInterval: 10 * time.Millisecond,
+				EncSchemes:   schemes,
+			}, transport.forAddr(from), keyDeriver, addr,
+			recipient,
+		)
+	}
+
+	// A sender that only supports schemes the receiver doesn't advertise
+	// can't deliver to it.
+	_, err = newMailbox(senderAddr, legacyAddr, "xchacha20poly1305")
+	require.ErrorIs(t, err, ErrNoCommonEncScheme)
+
+	// Otherwise the receiver's most preferred common scheme is used.
+	sender, err := newMailbox(
+		senderAddr, courierAddr, "aes256gcm", "chacha20poly1305",
+	)
+	require.NoError(t, err)
+	require.Equal(t, EmailEncChaCha20Poly1305, sender.sendScheme)
+
+	receiver, err := newMailbox(
+		receiverAddr, courierAddr, "chacha20poly1305", "aes256gcm",
+	)
+	require.NoError(t, err)
+
+	proofBlob := Blob(test.RandBytes(500))
+	senderSID := deriveSenderStreamID(recipient)
+	receiverSID := deriveReceiverStreamID(recipient)
+	require.NoError(t, sender.WriteProof(ctx, senderSID, proofBlob))
+
+	transport.Lock()
+	require.Len(t, transport.mailboxes[receiverAddr], 1)
+	rawMsg := transport.mailboxes[receiverAddr][0]
+	msg, err := decodeEmail(rawMsg)
+	transport.Unlock()
+	require.NoError(t, err)
+	require.Equal(t, EmailEncChaCha20Poly1305, msg.encScheme)
+	require.True(t, msg.ephemeralKey.IsEqual(sender.sendKey))
+
+	// The key of the proof commits to the scheme, so a proof can't be
+	// opened if the scheme in its header is changed in transit.
+	tamperedMsg := bytes.Replace(
+		rawMsg, []byte(emailEncSchemeHeader+": chacha20poly1305"),
+		[]byte(emailEncSchemeHeader+": aes256gcm"), 1,
+	)
+	require.NotEqual(t, rawMsg, tamperedMsg)
+
+	transport.Lock()
+	transport.mailboxes[receiverAddr][0] = tamperedMsg
+	transport.Unlock()
+
+	shortCtx, shortCancel := context.WithTimeout(
+		ctx, 50*time.Millisecond,
+	)
+	_, err = receiver.ReadProof(shortCtx, senderSID)
+	shortCancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	transport.Lock()
+	transport.mailboxes[receiverAddr][0] = rawMsg
+	transport.Unlock()
+
+	receivedProof, err := receiver.ReadProof(ctx, senderSID)
+	require.NoError(t, err)
+	require.Equal(t, proofBlob, receivedProof)
+
+	// The ACK is encrypted with the scheme and key of the proof.
+	require.NoError(t, receiver.AckProof(ctx, receiverSID, nil))
+
+	transport.Lock()
+	require.Len(t, transport.mailboxes[senderAddr], 1)
+	msg, err = decodeEmail(transport.mailboxes[senderAddr][0])
+	transport.Unlock()
+	require.NoError(t, err)
+	require.Equal(t, EmailEncChaCha20Poly1305, msg.encScheme)
+	require.True(t, msg.ephemeralKey.IsEqual(sender.sendKey))
+
+	_, err = sender.RecvAck(ctx, receiverSID)
+	require.NoError(t, err)
+}
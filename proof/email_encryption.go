@@ -0,0 +1,215 @@
+package proof
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// EmailEncScheme is the name of an AEAD scheme used to encrypt the attachments
+// of the email proof courier.
+//
+// NOTE: The schemes are only negotiated between the sender and the receiver of
+// an email delivery. The other proof couriers rely on the transport security
+// of their servers and don't negotiate any encryption, so their addresses are
+// rejected if they advertise encryption schemes.
+type EmailEncScheme string
+
+const (
+	// EmailEncAES256GCM encrypts attachments with AES-256 in GCM mode.
+	// This is the scheme used by nodes that don't advertise any scheme.
+	EmailEncAES256GCM EmailEncScheme = "aes256gcm"
+
+	// EmailEncChaCha20Poly1305 encrypts attachments with
+	// ChaCha20-Poly1305 as described in RFC 8439.
+	EmailEncChaCha20Poly1305 EmailEncScheme = "chacha20poly1305"
+
+	// EmailEncXChaCha20Poly1305 encrypts attachments with the extended
+	// nonce variant of ChaCha20-Poly1305.
+	EmailEncXChaCha20Poly1305 EmailEncScheme = "xchacha20poly1305"
+
+	// DefaultEmailEncScheme is the scheme that is assumed if a courier
+	// address or an email doesn't specify one.
+	DefaultEmailEncScheme = EmailEncAES256GCM
+
+	// emailEncSchemeParam is the query parameter of an email courier
+	// address that lists the encryption schemes supported by the
+	// receiver, in order of preference.
+	emailEncSchemeParam = "enc"
+
+	// emailEncSchemeHeader is the email header that carries the scheme
+	// the attachment of an email is encrypted with.
+	emailEncSchemeHeader = "X-Tapd-Encryption"
+
+	// emailEphemeralKeyHeader is the email header that carries the
+	// ephemeral public key of the sender, which the receiver needs to
+	// derive the key the attachment is encrypted with.
+	emailEphemeralKeyHeader = "X-Tapd-Ephemeral-Key"
+)
+
+// SharedKeyDeriver derives ECDH shared secrets with the keys of the local
+// wallet. This is implemented by the signer client of lnd.
+type SharedKeyDeriver interface {
+	// DeriveSharedKey returns the SHA256 hash of the compressed ECDH
+	// shared point of the given ephemeral public key and the private key
+	// at the given key locator.
+	DeriveSharedKey(ctx context.Context, ephemeralPubKey *btcec.PublicKey,
+		keyLocator *keychain.KeyLocator) ([32]byte, error)
+}
+
+var (
+	// ErrNoCommonEncScheme is returned if the receiver of a proof doesn't
+	// support any of the email encryption schemes supported by the sender.
+	ErrNoCommonEncScheme = errors.New("no common email courier " +
+		"encryption scheme")
+
+	// ErrEncSchemeUnsupported is returned if a courier address other than
+	// an email courier address advertises encryption schemes.
+	ErrEncSchemeUnsupported = errors.New("encryption scheme negotiation " +
+		"is only supported by the email courier")
+)
+
+// emailEncCiphers maps all encryption schemes this node is able to use to the
+// constructor of their AEAD cipher, which takes the 32-byte key derived for
+// the scheme.
+var emailEncCiphers = map[EmailEncScheme]func([]byte) (cipher.AEAD, error){
+	EmailEncAES256GCM: func(key []byte) (cipher.AEAD, error) {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		return cipher.NewGCM(block)
+	},
+	EmailEncChaCha20Poly1305:  chacha20poly1305.New,
+	EmailEncXChaCha20Poly1305: chacha20poly1305.NewX,
+}
+
+// checkNoEncSchemes returns ErrEncSchemeUnsupported if the given courier
+// address advertises encryption schemes.
+func checkNoEncSchemes(addr url.URL) error {
+	if addr.Query().Has(emailEncSchemeParam) {
+		return fmt.Errorf("%w: %v courier address advertises "+
+			"encryption schemes", ErrEncSchemeUnsupported,
+			addr.Scheme)
+	}
+
+	return nil
+}
+
+// ParseEmailEncSchemes parses the given list of encryption scheme names. An
+// error is returned if any of them isn't known. An empty list results in the
+// default scheme.
+func ParseEmailEncSchemes(names []string) ([]EmailEncScheme, error) {
+	if len(names) == 0 {
+		return []EmailEncScheme{DefaultEmailEncScheme}, nil
+	}
+
+	schemes := make([]EmailEncScheme, 0, len(names))
+	for _, name := range names {
+		scheme := EmailEncScheme(strings.ToLower(strings.TrimSpace(
+			name,
+		)))
+		if _, ok := emailEncCiphers[scheme]; !ok {
+			return nil, fmt.Errorf("unknown email encryption "+
+				"scheme: %v", name)
+		}
+
+		schemes = append(schemes, scheme)
+	}
+
+	return schemes, nil
+}
+
+// NegotiateEmailEncScheme returns the first of the schemes advertised by the
+// receiver that is also supported locally. ErrNoCommonEncScheme is returned if
+// there is no such scheme.
+func NegotiateEmailEncScheme(advertised,
+	supported []EmailEncScheme) (EmailEncScheme, error) {
+
+	for _, scheme := range advertised {
+		for _, localScheme := range supported {
+			if scheme == localScheme {
+				return scheme, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: receiver supports %v, we support %v",
+		ErrNoCommonEncScheme, advertised, supported)
+}
+
+// WithEmailEncSchemes returns a copy of the given email courier address that
+// advertises the given encryption schemes.
+func WithEmailEncSchemes(addr url.URL, schemes []EmailEncScheme) url.URL {
+	names := make([]string, len(schemes))
+	for idx, scheme := range schemes {
+		names[idx] = string(scheme)
+	}
+
+	query := addr.Query()
+	query.Set(emailEncSchemeParam, strings.Join(names, ","))
+	addr.RawQuery = query.Encode()
+
+	return addr
+}
+
+// newEmailSendSecret creates a fresh ephemeral key and derives the ECDH shared
+// secret with the given key of the receiver, as the receiver does with
+// SharedKeyDeriver. Only the receiver, which holds the private key, can derive
+// the same secret from the ephemeral public key.
+func newEmailSendSecret(receiverKey *btcec.PublicKey) (*btcec.PublicKey,
+	[32]byte, error) {
+
+	ephemeralKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	ecdh := keychain.PrivKeyECDH{PrivKey: ephemeralKey}
+	sharedSecret, err := ecdh.ECDH(receiverKey)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	return ephemeralKey.PubKey(), sharedSecret, nil
+}
+
+// deriveEmailKey derives the key of the given scheme from the ECDH shared
+// secret of the sender's ephemeral key and the receiver's key (ECIES). The key
+// commits to the scheme and the ephemeral key, so an attachment can't be
+// opened under a different scheme or with a different ephemeral key.
+func deriveEmailKey(scheme EmailEncScheme, sharedSecret [32]byte,
+	ephemeralKey *btcec.PublicKey) []byte {
+
+	h := sha256.New()
+	_, _ = h.Write(emailKeyDomain)
+	_, _ = h.Write([]byte(scheme))
+	_, _ = h.Write(sharedSecret[:])
+	_, _ = h.Write(ephemeralKey.SerializeCompressed())
+
+	return h.Sum(nil)
+}
+
+// newEmailCipher creates the AEAD cipher of the given scheme, keyed with the
+// key derived for the scheme from the given shared secret and ephemeral key.
+func newEmailCipher(scheme EmailEncScheme, sharedSecret [32]byte,
+	ephemeralKey *btcec.PublicKey) (cipher.AEAD, error) {
+
+	newCipher, ok := emailEncCiphers[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown email encryption scheme: %v",
+			scheme)
+	}
+
+	return newCipher(deriveEmailKey(scheme, sharedSecret, ephemeralKey))
+}
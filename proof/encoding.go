@@ -478,3 +478,34 @@ func GroupKeyRevealDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
 	}
 	return tlv.NewTypeForEncodingErr(val, "GroupKeyReveal")
 }
+
+func IssuanceAuthorityEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(**IssuanceAuthority); ok {
+		return (*t).Encode(w)
+	}
+	return tlv.NewTypeForEncodingErr(val, "*IssuanceAuthority")
+}
+
+func IssuanceAuthorityDecoder(r io.Reader, val any, buf *[8]byte,
+	l uint64) error {
+
+	if l > FileMaxProofSizeBytes {
+		return tlv.ErrRecordTooLarge
+	}
+
+	if typ, ok := val.(**IssuanceAuthority); ok {
+		var authorityBytes []byte
+		err := tlv.DVarBytes(r, &authorityBytes, buf, l)
+		if err != nil {
+			return err
+		}
+		var authority IssuanceAuthority
+		err = authority.Decode(bytes.NewReader(authorityBytes))
+		if err != nil {
+			return err
+		}
+		*typ = &authority
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "*IssuanceAuthority")
+}
@@ -2,11 +2,13 @@ package proof
 
 import (
 	"bytes"
+	"compress/flate"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -61,6 +63,12 @@ const (
 	// much smaller, assuming they don't all have additional inputs. But we
 	// must cap this value somewhere to avoid OOM attacks.
 	FileMaxSizeBytes = 500 * 1024 * 1024
+
+	// fileFlagCompressed is the bit that is set in the encoded version of
+	// a proof file if the proofs following the file header are compressed.
+	// Any version we know of is far below this value, so the flag doesn't
+	// collide with the version itself.
+	fileFlagCompressed uint32 = 1 << 31
 )
 
 // hashedProof is a struct that contains an encoded proof and its chained
@@ -79,6 +87,11 @@ type File struct {
 	// Version is the version of the proof file.
 	Version Version
 
+	// Compressed indicates whether the proofs of the file are compressed
+	// when encoding the file. This is set when decoding a compressed file,
+	// so a file keeps its compression when being re-encoded.
+	Compressed bool
+
 	// proofs are the proofs contained within the proof file starting from
 	// the genesis proof.
 	proofs []*hashedProof
@@ -124,7 +137,9 @@ func NewFile(v Version, proofs ...Proof) (*File, error) {
 	}, nil
 }
 
-// Encode encodes the proof file into `w` including its checksum.
+// Encode encodes the proof file into `w` including its checksum. If the file
+// is marked as compressed, all proofs following the file header are
+// compressed.
 func (f *File) Encode(w io.Writer) error {
 	num, err := w.Write(FilePrefixMagicBytes[:])
 	if err != nil {
@@ -134,11 +149,35 @@ func (f *File) Encode(w io.Writer) error {
 		return errors.New("failed to write prefix magic bytes")
 	}
 
-	err = binary.Write(w, binary.BigEndian, uint32(f.Version))
+	version := uint32(f.Version)
+	if f.Compressed {
+		version |= fileFlagCompressed
+	}
+	err = binary.Write(w, binary.BigEndian, version)
 	if err != nil {
 		return err
 	}
 
+	if !f.Compressed {
+		return f.encodeProofs(w)
+	}
+
+	compressor, err := flate.NewWriter(w, flate.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := f.encodeProofs(compressor); err != nil {
+		return err
+	}
+
+	// Closing the compressor flushes any pending data, but doesn't close
+	// the underlying writer.
+	return compressor.Close()
+}
+
+// encodeProofs encodes the number of proofs, followed by all proofs of the
+// file and their checksums into `w`.
+func (f *File) encodeProofs(w io.Writer) error {
 	var tlvBuf [8]byte
 	if err := tlv.WriteVarInt(w, uint64(len(f.proofs)), &tlvBuf); err != nil {
 		return err
@@ -172,7 +211,46 @@ func (f *File) Encode(w io.Writer) error {
 	return nil
 }
 
-// Decode decodes a proof file from `r`.
+// IsCompressedFile returns true if the given blob is a proof file whose proofs
+// are compressed. Nodes that don't support compression reject such files, as
+// they don't recognize the flag in their version.
+func IsCompressedFile(blob Blob) bool {
+	const headerLen = PrefixMagicBytesLength + 4
+	if !IsProofFile(blob) || len(blob) < headerLen {
+		return false
+	}
+
+	version := binary.BigEndian.Uint32(
+		blob[PrefixMagicBytesLength:headerLen],
+	)
+	return version&fileFlagCompressed != 0
+}
+
+// SetFileCompression returns the given proof file encoded with or without
+// compression of its proofs. The blob is returned unchanged if it already has
+// the requested encoding or isn't a proof file at all.
+func SetFileCompression(blob Blob, compressed bool) (Blob, error) {
+	if !IsProofFile(blob) || IsCompressedFile(blob) == compressed {
+		return blob, nil
+	}
+
+	var f File
+	if err := f.Decode(bytes.NewReader(blob)); err != nil {
+		return nil, fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	f.Compressed = compressed
+
+	var buf bytes.Buffer
+	if err := f.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("unable to encode proof file: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode decodes a proof file from `r`. Both compressed and uncompressed files
+// are supported.
 func (f *File) Decode(r io.Reader) error {
 	var prefixMagicBytes [PrefixMagicBytesLength]byte
 	num, err := r.Read(prefixMagicBytes[:])
@@ -193,8 +271,25 @@ func (f *File) Decode(r io.Reader) error {
 	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
 		return err
 	}
-	f.Version = Version(version)
+	f.Version = Version(version &^ fileFlagCompressed)
+	f.Compressed = version&fileFlagCompressed != 0
+
+	if !f.Compressed {
+		return f.decodeProofs(r)
+	}
+
+	decompressor := flate.NewReader(r)
+	defer decompressor.Close()
 
+	// The size of the decompressed file is capped the same way as the size
+	// of an uncompressed one, so a small compressed file can't be used for
+	// an OOM attack.
+	return f.decodeProofs(io.LimitReader(decompressor, FileMaxSizeBytes))
+}
+
+// decodeProofs decodes the number of proofs, followed by all proofs of the
+// file and their checksums from `r`.
+func (f *File) decodeProofs(r io.Reader) error {
 	var tlvBuf [8]byte
 	numProofs, err := tlv.ReadVarInt(r, &tlvBuf)
 	if err != nil {
@@ -393,6 +488,47 @@ func (f *File) AppendProof(proof Proof) error {
 	return nil
 }
 
+// AppendRawProof appends an encoded proof to the file and calculates its
+// chained hash. This allows a proof file to be reassembled from the raw proofs
+// of another file without re-encoding them. The raw proof must be a single
+// mint/transfer proof and not a proof file.
+func (f *File) AppendRawProof(rawProof []byte) error {
+	var prevHash [sha256.Size]byte
+	if f.IsUnknownVersion() {
+		return ErrUnknownVersion
+	}
+
+	if len(f.proofs) >= FileMaxNumProofs {
+		return fmt.Errorf("%w: too many proofs in file",
+			ErrProofFileInvalid)
+	}
+	if len(rawProof) > FileMaxProofSizeBytes {
+		return fmt.Errorf("%w: proof in file too large",
+			ErrProofFileInvalid)
+	}
+
+	// We make sure the bytes actually are a proof before adding them, as
+	// they would otherwise only fail once the file is used.
+	var p Proof
+	if err := p.Decode(bytes.NewReader(rawProof)); err != nil {
+		return fmt.Errorf("error decoding proof: %w", err)
+	}
+
+	if !f.IsEmpty() {
+		prevHash = f.proofs[len(f.proofs)-1].hash
+	}
+
+	proofBytes := make([]byte, len(rawProof))
+	copy(proofBytes, rawProof)
+
+	f.proofs = append(f.proofs, &hashedProof{
+		proofBytes: proofBytes,
+		hash:       hashProof(proofBytes, prevHash),
+	})
+
+	return nil
+}
+
 // ReplaceLastProof attempts to replace the last proof in the file with another
 // one, updating its chained hash in the process.
 func (f *File) ReplaceLastProof(proof Proof) error {
@@ -437,6 +573,118 @@ func (f *File) ReplaceProofAt(index uint32, proof Proof) error {
 	return nil
 }
 
+// MergeFiles merges the given prefix and suffix proof files into a single proof
+// file. The first proof of the suffix must spend the asset described by the
+// last proof of the prefix, otherwise the two chains aren't contiguous and an
+// error is returned.
+func MergeFiles(prefix, suffix *File) (*File, error) {
+	if err := prefix.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid prefix file: %w", err)
+	}
+	if err := suffix.IsValid(); err != nil {
+		return nil, fmt.Errorf("invalid suffix file: %w", err)
+	}
+
+	if prefix.Version != suffix.Version {
+		return nil, fmt.Errorf("%w: prefix version %d doesn't match "+
+			"suffix version %d", ErrProofFileInvalid,
+			prefix.Version, suffix.Version)
+	}
+
+	lastPrefixProof, err := prefix.LastProof()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch last prefix proof: %w",
+			err)
+	}
+	firstSuffixProof, err := suffix.ProofAt(0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch first suffix proof: %w",
+			err)
+	}
+
+	err = checkProofContinuity(lastPrefixProof, firstSuffixProof)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proofs of the suffix are appended as is, we only need to
+	// re-compute their chained hashes on top of the prefix.
+	numProofs := len(prefix.proofs) + len(suffix.proofs)
+	merged := &File{
+		Version:    prefix.Version,
+		Compressed: prefix.Compressed,
+		proofs:     make([]*hashedProof, 0, numProofs),
+	}
+	for _, p := range prefix.proofs {
+		merged.proofs = append(merged.proofs, &hashedProof{
+			proofBytes: p.proofBytes,
+			hash:       p.hash,
+		})
+	}
+
+	prevHash := merged.proofs[len(merged.proofs)-1].hash
+	for _, p := range suffix.proofs {
+		linkedProof := &hashedProof{
+			proofBytes: p.proofBytes,
+			hash:       hashProof(p.proofBytes, prevHash),
+		}
+		merged.proofs = append(merged.proofs, linkedProof)
+		prevHash = linkedProof.hash
+	}
+
+	if numProofs > FileMaxNumProofs {
+		return nil, fmt.Errorf("%w: merged file has %d proofs, max is "+
+			"%d", ErrProofFileInvalid, numProofs, FileMaxNumProofs)
+	}
+
+	return merged, nil
+}
+
+// checkProofContinuity makes sure the next proof is a state transition that
+// spends the asset output described by the previous proof.
+func checkProofContinuity(prev, next *Proof) error {
+	prevOutPoint := prev.OutPoint()
+	if next.PrevOut != prevOutPoint {
+		return fmt.Errorf("%w: proof spends %v, expected %v",
+			ErrProofFileInvalid, next.PrevOut, prevOutPoint)
+	}
+
+	nextAsset := &next.Asset
+	if nextAsset.IsGenesisAsset() {
+		return fmt.Errorf("%w: proof is a genesis proof and can't "+
+			"continue a chain", ErrProofFileInvalid)
+	}
+
+	prevID := prev.Asset.Genesis.ID()
+	if nextAsset.Genesis.ID() != prevID {
+		return fmt.Errorf("%w: asset ID %v doesn't match previous "+
+			"asset ID %v", ErrProofFileInvalid,
+			nextAsset.Genesis.ID(), prevID)
+	}
+
+	// For a split, the inputs are referenced by the root asset of the
+	// split commitment.
+	if nextAsset.HasSplitCommitmentWitness() {
+		splitCommitment := nextAsset.PrevWitnesses[0].SplitCommitment
+		nextAsset = &splitCommitment.RootAsset
+	}
+
+	expectedPrevID := asset.PrevID{
+		OutPoint:  prevOutPoint,
+		ID:        prevID,
+		ScriptKey: asset.ToSerialized(prev.Asset.ScriptKey.PubKey),
+	}
+	for _, witness := range nextAsset.PrevWitnesses {
+		if witness.PrevID != nil && *witness.PrevID == expectedPrevID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: proof doesn't spend previous asset with "+
+		"script key %x", ErrProofFileInvalid,
+		expectedPrevID.ScriptKey[:])
+}
+
 // encodeProof encodes the given proof and returns its raw bytes.
 func encodeProof(proof *Proof) ([]byte, error) {
 	var buf bytes.Buffer
@@ -474,6 +722,10 @@ type AssetSnapshot struct {
 	// AnchorBlockHeight is the height of the block hash above.
 	AnchorBlockHeight uint32
 
+	// AnchorBlockTime is the timestamp of the block that anchors the
+	// Bitcoin transaction for this Taproot Asset state transition.
+	AnchorBlockTime time.Time
+
 	// AnchorTxIndex is the transaction index within the above block where
 	// the AnchorTx can be found.
 	AnchorTxIndex uint32
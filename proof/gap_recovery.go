@@ -0,0 +1,345 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+)
+
+var (
+	// ErrGapProofUnknown is returned when the sender doesn't know the proof
+	// the receiver found missing from its proof chain.
+	ErrGapProofUnknown = errors.New("sender doesn't know the missing proof")
+
+	// gapStreamTag is mixed into the stream IDs of the mailbox used for
+	// gap requests, so they don't collide with the proof delivery streams
+	// of the same recipient.
+	gapStreamTag = []byte("proof-gap")
+)
+
+// GapRecoveryCfg is the config for the automatic recovery of proofs that are
+// missing from a proof chain that is assembled from the universe courier.
+type GapRecoveryCfg struct {
+	MailboxAddr string `long:"mailboxaddr" description:"The hashmail server (hashmail://host:port) through which receivers request the proofs that are missing from a proof chain they assemble from a universe courier, and senders answer those requests. Both sides of a transfer need to use the same server. If not set, a gap in a proof chain fails the receive."`
+
+	RequestTimeout time.Duration `long:"requesttimeout" description:"The maximum time a receiver waits for the sender to answer the request for a missing proof."`
+
+	ServeDuration time.Duration `long:"serveduration" description:"The maximum time a sender answers requests for missing proofs after it delivered a proof to a universe courier. The sender stops earlier once the receiver acknowledges that its proof chain is complete. Serving is resumed after a restart."`
+}
+
+// Validate returns an error if the config can't be used to recover missing
+// proofs.
+func (c *GapRecoveryCfg) Validate() error {
+	switch {
+	case c.RequestTimeout <= 0:
+		return fmt.Errorf("gap request timeout must be positive")
+
+	case c.ServeDuration <= 0:
+		return fmt.Errorf("gap serve duration must be positive")
+	}
+
+	_, err := c.mailboxURL()
+	return err
+}
+
+// mailboxURL parses the address of the hashmail server used for gap requests.
+func (c *GapRecoveryCfg) mailboxURL() (*url.URL, error) {
+	addr, err := ParseCourierAddrString(c.MailboxAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gap recovery mailbox "+
+			"address: %w", err)
+	}
+
+	if _, ok := addr.(*HashMailCourierAddr); !ok {
+		return nil, fmt.Errorf("gap recovery mailbox must be a %v "+
+			"address", HashmailCourierType)
+	}
+
+	return addr.Url(), nil
+}
+
+// newGapMailbox creates the mailbox used to exchange gap requests. If gap
+// recovery isn't configured, nil is returned.
+func newGapMailbox(cfg *GapRecoveryCfg) (ProofMailbox, error) {
+	if cfg == nil || cfg.MailboxAddr == "" {
+		return nil, nil
+	}
+
+	mailboxURL, err := cfg.mailboxURL()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHashMailBox(mailboxURL)
+}
+
+// GapServer is implemented by couriers that can answer the requests of a
+// receiver for proofs missing from the proof chain it assembled.
+type GapServer interface {
+	// ServeProofGaps answers the requests of the receiver of the given
+	// proof file for the proofs of its chain, until the receiver
+	// acknowledges that its proof chain is complete or the context is
+	// done.
+	ServeProofGaps(context.Context, *AnnotatedProof) error
+}
+
+// ServeProofGaps answers the requests of the receiver of the given proof file
+// for the proofs of its chain through the mailbox of the given gap recovery
+// config, until the receiver acknowledges that its proof chain is complete or
+// the context is done. It is used to resume serving without a courier after a
+// restart. If gap recovery is disabled, it returns immediately.
+func ServeProofGaps(ctx context.Context, cfg *GapRecoveryCfg,
+	annotatedProof *AnnotatedProof) error {
+
+	mailbox, err := newGapMailbox(cfg)
+	if err != nil {
+		return err
+	}
+	if mailbox == nil {
+		return nil
+	}
+
+	proofFile := &File{}
+	err = proofFile.Decode(bytes.NewReader(annotatedProof.Blob))
+	if err != nil {
+		return err
+	}
+
+	return serveProofGaps(
+		ctx, mailbox, &annotatedProof.ScriptKey, proofFile,
+	)
+}
+
+// deriveGapStreamIDs derives the stream IDs of the gap requests of the receiver
+// with the given script key and of the responses of the sender.
+func deriveGapStreamIDs(scriptKey *btcec.PublicKey) (streamID, streamID) {
+	reqSID := sha512.Sum512(
+		append(scriptKey.SerializeCompressed(), gapStreamTag...),
+	)
+
+	respSID := reqSID
+	respSID[63] ^= 0x01
+
+	return reqSID, respSID
+}
+
+// encodeGapRequest encodes the locator of a missing proof. All of its fields
+// but the group key are required.
+func encodeGapRequest(loc Locator) (Blob, error) {
+	if loc.AssetID == nil || loc.OutPoint == nil {
+		return nil, fmt.Errorf("gap request requires asset ID and " +
+			"outpoint")
+	}
+
+	var buf bytes.Buffer
+	buf.Write(loc.AssetID[:])
+	buf.Write(loc.ScriptKey.SerializeCompressed())
+	buf.Write(loc.OutPoint.Hash[:])
+
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], loc.OutPoint.Index)
+	buf.Write(index[:])
+
+	return buf.Bytes(), nil
+}
+
+// decodeGapRequest decodes the locator of a missing proof.
+func decodeGapRequest(msg Blob) (*Locator, error) {
+	// The outpoint is encoded as its 32-byte hash and a 4-byte index.
+	const reqLen = sha256.Size + btcec.PubKeyBytesLenCompressed +
+		chainhash.HashSize + 4
+	if len(msg) != reqLen {
+		return nil, fmt.Errorf("invalid gap request length %d",
+			len(msg))
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], msg[:sha256.Size])
+	msg = msg[sha256.Size:]
+
+	scriptKey, err := btcec.ParsePubKey(
+		msg[:btcec.PubKeyBytesLenCompressed],
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gap request script key: %w",
+			err)
+	}
+	msg = msg[btcec.PubKeyBytesLenCompressed:]
+
+	var outPoint wire.OutPoint
+	copy(outPoint.Hash[:], msg[:chainhash.HashSize])
+	outPoint.Index = binary.BigEndian.Uint32(msg[chainhash.HashSize:])
+
+	return &Locator{
+		AssetID:   &assetID,
+		ScriptKey: *scriptKey,
+		OutPoint:  &outPoint,
+	}, nil
+}
+
+// matchesLocator returns true if the proof is the one identified by the given
+// locator, which must specify an asset ID and outpoint.
+func matchesLocator(p *Proof, loc *Locator) bool {
+	return p.Asset.ID() == *loc.AssetID &&
+		p.Asset.ScriptKey.PubKey.IsEqual(&loc.ScriptKey) &&
+		p.OutPoint() == *loc.OutPoint
+}
+
+// requestGapProof asks the sender of the proof for the receiver with the given
+// script key for the missing proof identified by the locator, and waits for the
+// sender to answer for at most the given timeout.
+func requestGapProof(ctx context.Context, mailbox ProofMailbox,
+	scriptKey *btcec.PublicKey, missing Locator,
+	timeout time.Duration) (*Proof, error) {
+
+	req, err := encodeGapRequest(missing)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reqSID, respSID := deriveGapStreamIDs(scriptKey)
+	if err := mailbox.Init(ctx, reqSID); err != nil {
+		return nil, err
+	}
+	if err := mailbox.Init(ctx, respSID); err != nil {
+		return nil, err
+	}
+
+	log.Infof("Requesting missing proof for script key %x at %v from "+
+		"sender via sid=%x", missing.ScriptKey.SerializeCompressed(),
+		missing.OutPoint, reqSID)
+
+	if err := mailbox.WriteProof(ctx, reqSID, req); err != nil {
+		return nil, fmt.Errorf("unable to send gap request: %w", err)
+	}
+
+	resp, err := mailbox.ReadProof(ctx, respSID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to receive gap response: %w",
+			err)
+	}
+
+	// An empty response means the sender doesn't have the proof either.
+	if len(resp) == 0 {
+		return nil, ErrGapProofUnknown
+	}
+
+	var gapProof Proof
+	if err := gapProof.Decode(bytes.NewReader(resp)); err != nil {
+		return nil, fmt.Errorf("unable to decode gap proof: %w", err)
+	}
+
+	if !matchesLocator(&gapProof, &missing) {
+		return nil, fmt.Errorf("sender returned proof for different " +
+			"asset output than requested")
+	}
+
+	return &gapProof, nil
+}
+
+// acknowledgeProofChain tells the sender of the proof for the receiver with the
+// given script key that the proof chain is complete, so it stops answering gap
+// requests. The acknowledgement is an empty gap request.
+func acknowledgeProofChain(ctx context.Context, mailbox ProofMailbox,
+	scriptKey *btcec.PublicKey, timeout time.Duration) error {
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reqSID, _ := deriveGapStreamIDs(scriptKey)
+	if err := mailbox.Init(ctx, reqSID); err != nil {
+		return err
+	}
+
+	if err := mailbox.WriteProof(ctx, reqSID, nil); err != nil {
+		return fmt.Errorf("unable to send proof chain "+
+			"acknowledgement: %w", err)
+	}
+
+	return nil
+}
+
+// serveProofGaps answers the gap requests of the receiver with the given script
+// key with the matching proofs of the proof file, until the receiver
+// acknowledges that its proof chain is complete or the context is done.
+func serveProofGaps(ctx context.Context, mailbox ProofMailbox,
+	scriptKey *btcec.PublicKey, file *File) error {
+
+	reqSID, respSID := deriveGapStreamIDs(scriptKey)
+	if err := mailbox.Init(ctx, reqSID); err != nil {
+		return err
+	}
+	if err := mailbox.Init(ctx, respSID); err != nil {
+		return err
+	}
+
+	for {
+		req, err := mailbox.ReadProof(ctx, reqSID)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+
+		case err != nil:
+			return fmt.Errorf("unable to receive gap request: %w",
+				err)
+
+		// An empty request acknowledges that the proof chain of the
+		// receiver is complete.
+		case len(req) == 0:
+			log.Infof("Receiver with script key %x acknowledged "+
+				"its proof chain",
+				scriptKey.SerializeCompressed())
+
+			return nil
+		}
+
+		loc, err := decodeGapRequest(req)
+		if err != nil {
+			log.Warnf("Ignoring invalid gap request: %v", err)
+			continue
+		}
+
+		// We answer with the requested proof, or with an empty message
+		// if it isn't part of the proof chain we delivered.
+		var resp Blob
+		for i := 0; i < file.NumProofs(); i++ {
+			p, err := file.ProofAt(uint32(i))
+			if err != nil {
+				return err
+			}
+
+			if matchesLocator(p, loc) {
+				var buf bytes.Buffer
+				if err := p.Encode(&buf); err != nil {
+					return err
+				}
+				resp = buf.Bytes()
+
+				break
+			}
+		}
+
+		log.Infof("Answering request for proof of script key %x at "+
+			"%v (found=%v)", loc.ScriptKey.SerializeCompressed(),
+			loc.OutPoint, len(resp) > 0)
+
+		if err := mailbox.WriteProof(ctx, respSID, resp); err != nil {
+			return fmt.Errorf("unable to send gap response: %w",
+				err)
+		}
+	}
+}
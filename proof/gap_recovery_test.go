@@ -0,0 +1,141 @@
+package proof
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// memMailbox is an in-memory proof mailbox that delivers the messages written
+// to a stream to its reader in order.
+type memMailbox struct {
+	ProofMailbox
+
+	mtx     sync.Mutex
+	streams map[streamID]chan Blob
+}
+
+// stream returns the channel of the stream with the given ID.
+func (m *memMailbox) stream(sid streamID) chan Blob {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.streams == nil {
+		m.streams = make(map[streamID]chan Blob)
+	}
+	if _, ok := m.streams[sid]; !ok {
+		m.streams[sid] = make(chan Blob, 10)
+	}
+
+	return m.streams[sid]
+}
+
+// Init creates a mailbox given the specified stream ID.
+func (m *memMailbox) Init(_ context.Context, sid streamID) error {
+	m.stream(sid)
+
+	return nil
+}
+
+// WriteProof writes the proof to the mailbox specified by the sid.
+func (m *memMailbox) WriteProof(_ context.Context, sid streamID,
+	proof Blob) error {
+
+	m.stream(sid) <- proof
+
+	return nil
+}
+
+// ReadProof reads a proof from the mailbox. This is a blocking method.
+func (m *memMailbox) ReadProof(ctx context.Context,
+	sid streamID) (Blob, error) {
+
+	select {
+	case msg := <-m.stream(sid):
+		return msg, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TestGapRecovery tests that a receiver can request a proof that is missing
+// from its proof chain from the sender through the mailbox, and that the sender
+// stops serving once the receiver acknowledges its proof chain.
+func TestGapRecovery(t *testing.T) {
+	t.Parallel()
+
+	amt := uint64(1000)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil, asset.V0,
+	)
+	file, err := NewFile(V0, genesisProof)
+	require.NoError(t, err)
+
+	assetID := genesisProof.Asset.ID()
+	outPoint := genesisProof.OutPoint()
+	missing := Locator{
+		AssetID:   &assetID,
+		ScriptKey: *genesisProof.Asset.ScriptKey.PubKey,
+		OutPoint:  &outPoint,
+	}
+
+	req, err := encodeGapRequest(missing)
+	require.NoError(t, err)
+	decoded, err := decodeGapRequest(req)
+	require.NoError(t, err)
+	require.Equal(t, missing, *decoded)
+
+	_, err = encodeGapRequest(Locator{ScriptKey: missing.ScriptKey})
+	require.ErrorContains(t, err, "requires asset ID and outpoint")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mailbox := &memMailbox{}
+	receiverKey := test.RandPubKey(t)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := serveProofGaps(ctx, mailbox, receiverKey, file)
+		require.NoError(t, err)
+	}()
+
+	// The sender answers with the proof that is part of its chain.
+	gapProof, err := requestGapProof(
+		ctx, mailbox, receiverKey, missing, time.Second,
+	)
+	require.NoError(t, err)
+	require.True(t, matchesLocator(gapProof, &missing))
+
+	// A proof the sender doesn't know is answered with an empty message.
+	unknownOutPoint := test.RandOp(t)
+	unknown := missing
+	unknown.OutPoint = &unknownOutPoint
+	_, err = requestGapProof(
+		ctx, mailbox, receiverKey, unknown, time.Second,
+	)
+	require.ErrorIs(t, err, ErrGapProofUnknown)
+
+	// Once the receiver acknowledges its proof chain, the sender stops
+	// serving without its context being cancelled, and further requests
+	// time out.
+	err = acknowledgeProofChain(ctx, mailbox, receiverKey, time.Second)
+	require.NoError(t, err)
+	wg.Wait()
+	require.NoError(t, ctx.Err())
+
+	_, err = requestGapProof(
+		context.Background(), mailbox, receiverKey, missing,
+		10*time.Millisecond,
+	)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,566 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// MaxGroupKeyRotations is the maximum number of rotations a single
+	// issuance authority can carry.
+	MaxGroupKeyRotations = 1024
+
+	rotationGroupKeyType    tlv.Type = 0
+	rotationSequenceType    tlv.Type = 2
+	rotationPrevKeyType     tlv.Type = 4
+	rotationNewKeyType      tlv.Type = 6
+	rotationSignatureType   tlv.Type = 8
+	rotationAnchorTxType    tlv.Type = 10
+	rotationOutputIndexType tlv.Type = 12
+	rotationMerkleProofType tlv.Type = 14
+	rotationBlockHeaderType tlv.Type = 16
+	rotationBlockHeightType tlv.Type = 18
+
+	authorityAnchorIDType      tlv.Type = 0
+	authorityTapscriptRootType tlv.Type = 1
+	authorityRotationsType     tlv.Type = 2
+	authoritySignatureType     tlv.Type = 4
+)
+
+var (
+	// rotationMsgPrefix is the prefix of the message that is signed by the
+	// current issuance authority of an asset group to hand over the
+	// authority to a new key.
+	rotationMsgPrefix = []byte("taproot-assets group key rotation")
+
+	// issuanceMsgPrefix is the prefix of the message that is signed by the
+	// current issuance authority of an asset group to authorize the
+	// issuance of an asset into the group.
+	issuanceMsgPrefix = []byte("taproot-assets group issuance authority")
+
+	// ErrIssuanceAuthorityInvalid is returned if the issuance authority of
+	// a proof can't be verified.
+	ErrIssuanceAuthorityInvalid = errors.New("invalid issuance authority")
+
+	// ErrStaleIssuanceAuthority is returned if an issuance is authorized
+	// by a key that was already rotated out when the asset was issued.
+	ErrStaleIssuanceAuthority = errors.New("issuance authorized by stale " +
+		"group key")
+)
+
+// GroupKeyRotation is an on-chain transition of the key that authorizes the
+// issuance of assets into an asset group. The key that held the authority
+// before the rotation signs over the new key, and the transaction that
+// anchors the rotation commits to the signed message in an OP_RETURN output.
+//
+// The tweaked group key of an asset group can't change, so the group witness
+// of a reissued asset is still created with the original group key. Once a
+// rotation is known, issuance proofs must in addition carry an issuance
+// authority that is signed by the latest key of the rotation chain.
+type GroupKeyRotation struct {
+	// GroupKey is the tweaked group key of the asset group.
+	GroupKey *btcec.PublicKey
+
+	// Sequence is the position of the rotation in the rotation chain of
+	// the group, starting at zero.
+	Sequence uint32
+
+	// PrevKey is the key that held the issuance authority before the
+	// rotation. For the first rotation, this is the raw group key.
+	PrevKey *btcec.PublicKey
+
+	// NewKey is the key that holds the issuance authority after the
+	// rotation.
+	NewKey *btcec.PublicKey
+
+	// Signature is the Schnorr signature over the rotation message,
+	// created with the previous key.
+	Signature *schnorr.Signature
+
+	// AnchorTx is the transaction that anchors the rotation on-chain.
+	AnchorTx wire.MsgTx
+
+	// OutputIndex is the index of the OP_RETURN output of the anchor
+	// transaction that commits to the rotation.
+	OutputIndex uint32
+
+	// TxMerkleProof is the merkle proof for AnchorTx used to prove its
+	// inclusion within BlockHeader.
+	TxMerkleProof TxMerkleProof
+
+	// BlockHeader is the header of the block that confirmed the anchor
+	// transaction.
+	BlockHeader wire.BlockHeader
+
+	// BlockHeight is the height of the block that confirmed the anchor
+	// transaction.
+	BlockHeight uint32
+}
+
+// RotationMessage returns the message that is signed by the previous key to
+// hand over the issuance authority of the given group to the new key.
+func RotationMessage(groupKey, prevKey, newKey *btcec.PublicKey,
+	sequence uint32) []byte {
+
+	msg := make(
+		[]byte, 0, len(rotationMsgPrefix)+
+			3*btcec.PubKeyBytesLenCompressed+4,
+	)
+	msg = append(msg, rotationMsgPrefix...)
+	msg = append(msg, groupKey.SerializeCompressed()...)
+	msg = append(msg, prevKey.SerializeCompressed()...)
+	msg = append(msg, newKey.SerializeCompressed()...)
+	return binary.BigEndian.AppendUint32(msg, sequence)
+}
+
+// RotationScript returns the OP_RETURN script that commits to the rotation of
+// the issuance authority of the given group from the previous to the new key.
+func RotationScript(groupKey, prevKey, newKey *btcec.PublicKey,
+	sequence uint32) ([]byte, error) {
+
+	digest := sha256.Sum256(
+		RotationMessage(groupKey, prevKey, newKey, sequence),
+	)
+	return txscript.NullDataScript(digest[:])
+}
+
+// IssuanceMessage returns the message that is signed by the issuance authority
+// of the given group to authorize the issuance of the asset with the given ID.
+func IssuanceMessage(groupKey *btcec.PublicKey, assetID asset.ID) []byte {
+	msg := make(
+		[]byte, 0, len(issuanceMsgPrefix)+
+			btcec.PubKeyBytesLenCompressed+sha256.Size,
+	)
+	msg = append(msg, issuanceMsgPrefix...)
+	msg = append(msg, groupKey.SerializeCompressed()...)
+	return append(msg, assetID[:]...)
+}
+
+// Verify makes sure the rotation was signed by the previous key and that it is
+// anchored in a transaction that was confirmed in a valid block.
+func (r *GroupKeyRotation) Verify(headerVerifier HeaderVerifier) error {
+	if r.GroupKey == nil || r.PrevKey == nil || r.NewKey == nil ||
+		r.Signature == nil {
+
+		return fmt.Errorf("%w: incomplete rotation",
+			ErrIssuanceAuthorityInvalid)
+	}
+
+	// The message is hashed with a single round of SHA256 before signing,
+	// in the same way lnd's message signer does it.
+	digest := sha256.Sum256(RotationMessage(
+		r.GroupKey, r.PrevKey, r.NewKey, r.Sequence,
+	))
+	if !r.Signature.Verify(digest[:], r.PrevKey) {
+		return fmt.Errorf("%w: invalid rotation signature",
+			ErrIssuanceAuthorityInvalid)
+	}
+
+	if int(r.OutputIndex) >= len(r.AnchorTx.TxOut) {
+		return fmt.Errorf("%w: rotation output index %d out of range",
+			ErrIssuanceAuthorityInvalid, r.OutputIndex)
+	}
+	script, err := RotationScript(
+		r.GroupKey, r.PrevKey, r.NewKey, r.Sequence,
+	)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(r.AnchorTx.TxOut[r.OutputIndex].PkScript, script) {
+		return fmt.Errorf("%w: rotation output doesn't commit to "+
+			"rotation", ErrIssuanceAuthorityInvalid)
+	}
+
+	if !r.TxMerkleProof.Verify(&r.AnchorTx, r.BlockHeader.MerkleRoot) {
+		return ErrInvalidTxMerkleProof
+	}
+
+	err = headerVerifier(r.BlockHeader, r.BlockHeight)
+	if err != nil {
+		return fmt.Errorf("failed to validate rotation block header: "+
+			"%w", err)
+	}
+
+	return nil
+}
+
+// records returns the set of TLV records to encode and decode a rotation.
+func (r *GroupKeyRotation) records(
+	sig *[schnorr.SignatureSize]byte) []tlv.Record {
+
+	txSize := func() uint64 {
+		return uint64(r.AnchorTx.SerializeSize())
+	}
+	merkleProofSize := func() uint64 {
+		var buf bytes.Buffer
+		if err := r.TxMerkleProof.Encode(&buf); err != nil {
+			panic(err)
+		}
+		return uint64(buf.Len())
+	}
+
+	return []tlv.Record{
+		tlv.MakePrimitiveRecord(rotationGroupKeyType, &r.GroupKey),
+		tlv.MakePrimitiveRecord(rotationSequenceType, &r.Sequence),
+		tlv.MakePrimitiveRecord(rotationPrevKeyType, &r.PrevKey),
+		tlv.MakePrimitiveRecord(rotationNewKeyType, &r.NewKey),
+		tlv.MakePrimitiveRecord(rotationSignatureType, sig),
+		tlv.MakeDynamicRecord(
+			rotationAnchorTxType, &r.AnchorTx, txSize, TxEncoder,
+			TxDecoder,
+		),
+		tlv.MakePrimitiveRecord(
+			rotationOutputIndexType, &r.OutputIndex,
+		),
+		tlv.MakeDynamicRecord(
+			rotationMerkleProofType, &r.TxMerkleProof,
+			merkleProofSize, TxMerkleProofEncoder,
+			TxMerkleProofDecoder,
+		),
+		tlv.MakeStaticRecord(
+			rotationBlockHeaderType, &r.BlockHeader,
+			wire.MaxBlockHeaderPayload, BlockHeaderEncoder,
+			BlockHeaderDecoder,
+		),
+		tlv.MakePrimitiveRecord(
+			rotationBlockHeightType, &r.BlockHeight,
+		),
+	}
+}
+
+// Encode encodes the rotation into `w`.
+func (r *GroupKeyRotation) Encode(w io.Writer) error {
+	if r.Signature == nil {
+		return fmt.Errorf("rotation signature missing")
+	}
+
+	var sig [schnorr.SignatureSize]byte
+	copy(sig[:], r.Signature.Serialize())
+
+	stream, err := tlv.NewStream(r.records(&sig)...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the rotation from `r`.
+func (r *GroupKeyRotation) Decode(reader io.Reader) error {
+	var sig [schnorr.SignatureSize]byte
+	stream, err := tlv.NewStream(r.records(&sig)...)
+	if err != nil {
+		return err
+	}
+	if err := stream.Decode(reader); err != nil {
+		return err
+	}
+
+	r.Signature, err = schnorr.ParseSignature(sig[:])
+	return err
+}
+
+// IssuanceAuthority proves that the issuance of an asset into an asset group
+// was authorized by the latest key of the rotation chain of the group.
+type IssuanceAuthority struct {
+	// GroupAnchorID is the asset ID of the group anchor, which is needed
+	// to link the raw group key to the tweaked group key.
+	GroupAnchorID asset.ID
+
+	// GroupTapscriptRoot is the tapscript root the group key is tweaked
+	// with. It is empty if the group key has no tapscript tree.
+	GroupTapscriptRoot []byte
+
+	// Rotations is the chain of rotations that hands the issuance
+	// authority from the raw group key to the key that signed the
+	// issuance.
+	Rotations []*GroupKeyRotation
+
+	// Signature is the Schnorr signature over the issuance message,
+	// created with the new key of the last rotation.
+	Signature *schnorr.Signature
+}
+
+// AuthorityKey returns the key that holds the issuance authority according to
+// the rotation chain.
+func (a *IssuanceAuthority) AuthorityKey() *btcec.PublicKey {
+	if len(a.Rotations) == 0 {
+		return nil
+	}
+
+	return a.Rotations[len(a.Rotations)-1].NewKey
+}
+
+// Verify makes sure the rotation chain starts at the raw key of the given
+// group key, that every rotation is valid and confirmed no later than the
+// issuance, and that the issuance of the asset with the given ID was signed by
+// the latest key of the chain.
+func (a *IssuanceAuthority) Verify(groupKey *btcec.PublicKey, assetID asset.ID,
+	issuanceHeight uint32, headerVerifier HeaderVerifier) error {
+
+	if len(a.Rotations) == 0 {
+		return fmt.Errorf("%w: no rotations",
+			ErrIssuanceAuthorityInvalid)
+	}
+
+	// The first rotation must be signed by the raw group key, which we
+	// can only link to the group key with the group anchor ID.
+	reveal := asset.GroupKeyReveal{
+		RawKey:        asset.ToSerialized(a.Rotations[0].PrevKey),
+		TapscriptRoot: a.GroupTapscriptRoot,
+	}
+	rawGroupKey, err := reveal.GroupPubKey(a.GroupAnchorID)
+	if err != nil {
+		return err
+	}
+	if !rawGroupKey.IsEqual(groupKey) {
+		return fmt.Errorf("%w: rotation chain doesn't start at group "+
+			"key", ErrIssuanceAuthorityInvalid)
+	}
+
+	for idx, rotation := range a.Rotations {
+		if rotation.GroupKey == nil ||
+			!rotation.GroupKey.IsEqual(groupKey) {
+
+			return fmt.Errorf("%w: rotation %d for wrong group",
+				ErrIssuanceAuthorityInvalid, idx)
+		}
+		if rotation.Sequence != uint32(idx) {
+			return fmt.Errorf("%w: rotation %d has sequence %d",
+				ErrIssuanceAuthorityInvalid, idx,
+				rotation.Sequence)
+		}
+		if idx > 0 && !rotation.PrevKey.IsEqual(
+			a.Rotations[idx-1].NewKey,
+		) {
+
+			return fmt.Errorf("%w: rotation %d doesn't continue "+
+				"chain", ErrIssuanceAuthorityInvalid, idx)
+		}
+		if rotation.BlockHeight > issuanceHeight {
+			return fmt.Errorf("%w: rotation %d confirmed after "+
+				"issuance", ErrIssuanceAuthorityInvalid, idx)
+		}
+
+		if err := rotation.Verify(headerVerifier); err != nil {
+			return err
+		}
+	}
+
+	if a.Signature == nil {
+		return fmt.Errorf("%w: issuance signature missing",
+			ErrIssuanceAuthorityInvalid)
+	}
+	digest := sha256.Sum256(IssuanceMessage(groupKey, assetID))
+	if !a.Signature.Verify(digest[:], a.AuthorityKey()) {
+		return fmt.Errorf("%w: invalid issuance signature",
+			ErrIssuanceAuthorityInvalid)
+	}
+
+	return nil
+}
+
+// Encode encodes the issuance authority into `w`.
+func (a *IssuanceAuthority) Encode(w io.Writer) error {
+	if a.Signature == nil {
+		return fmt.Errorf("issuance signature missing")
+	}
+
+	var sig [schnorr.SignatureSize]byte
+	copy(sig[:], a.Signature.Serialize())
+
+	var anchorID [32]byte = a.GroupAnchorID
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(authorityAnchorIDType, &anchorID),
+	}
+	if len(a.GroupTapscriptRoot) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			authorityTapscriptRootType, &a.GroupTapscriptRoot,
+		))
+	}
+	records = append(records, rotationsRecord(&a.Rotations))
+	records = append(records, tlv.MakePrimitiveRecord(
+		authoritySignatureType, &sig,
+	))
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+	return stream.Encode(w)
+}
+
+// Decode decodes the issuance authority from `r`.
+func (a *IssuanceAuthority) Decode(r io.Reader) error {
+	var (
+		anchorID [32]byte
+		sig      [schnorr.SignatureSize]byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(authorityAnchorIDType, &anchorID),
+		tlv.MakePrimitiveRecord(
+			authorityTapscriptRootType, &a.GroupTapscriptRoot,
+		),
+		rotationsRecord(&a.Rotations),
+		tlv.MakePrimitiveRecord(authoritySignatureType, &sig),
+	)
+	if err != nil {
+		return err
+	}
+	if err := stream.Decode(r); err != nil {
+		return err
+	}
+
+	a.GroupAnchorID = anchorID
+	a.Signature, err = schnorr.ParseSignature(sig[:])
+	return err
+}
+
+// rotationsRecord returns the TLV record for a chain of rotations.
+func rotationsRecord(rotations *[]*GroupKeyRotation) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := rotationsEncoder(&buf, rotations, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(buf.Len())
+	}
+	return tlv.MakeDynamicRecord(
+		authorityRotationsType, rotations, sizeFunc, rotationsEncoder,
+		rotationsDecoder,
+	)
+}
+
+func rotationsEncoder(w io.Writer, val any, buf *[8]byte) error {
+	if t, ok := val.(*[]*GroupKeyRotation); ok {
+		numRotations := uint64(len(*t))
+		if err := tlv.WriteVarInt(w, numRotations, buf); err != nil {
+			return err
+		}
+		for _, rotation := range *t {
+			var b bytes.Buffer
+			if err := rotation.Encode(&b); err != nil {
+				return err
+			}
+			rotationBytes := b.Bytes()
+			err := asset.InlineVarBytesEncoder(
+				w, &rotationBytes, buf,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]*GroupKeyRotation")
+}
+
+func rotationsDecoder(r io.Reader, val any, buf *[8]byte, l uint64) error {
+	if typ, ok := val.(*[]*GroupKeyRotation); ok {
+		numRotations, err := tlv.ReadVarInt(r, buf)
+		if err != nil {
+			return err
+		}
+		if numRotations > MaxGroupKeyRotations {
+			return tlv.ErrRecordTooLarge
+		}
+
+		rotations := make([]*GroupKeyRotation, 0, numRotations)
+		for i := uint64(0); i < numRotations; i++ {
+			var rotationBytes []byte
+			err := asset.InlineVarBytesDecoder(
+				r, &rotationBytes, buf, l,
+			)
+			if err != nil {
+				return err
+			}
+
+			var rotation GroupKeyRotation
+			err = rotation.Decode(bytes.NewReader(rotationBytes))
+			if err != nil {
+				return err
+			}
+			rotations = append(rotations, &rotation)
+		}
+		*typ = rotations
+		return nil
+	}
+	return tlv.NewTypeForEncodingErr(val, "[]*GroupKeyRotation")
+}
+
+// GroupRotationLookup is a callback function which returns the known rotations
+// of the issuance authority of the asset group with the given group key,
+// ordered by their sequence.
+type GroupRotationLookup func(ctx context.Context,
+	groupKey *btcec.PublicKey) ([]*GroupKeyRotation, error)
+
+// verifyIssuanceAuthority verifies the issuance authority of a reissuance
+// proof, if there is one. If a rotation lookup is given, the issuance must be
+// authorized by a rotation chain that contains all known rotations that were
+// confirmed no later than the issuance, otherwise the issuance was authorized
+// by a stale key.
+func (p *Proof) verifyIssuanceAuthority(ctx context.Context,
+	headerVerifier HeaderVerifier,
+	rotationLookup GroupRotationLookup) error {
+
+	groupKey := &p.Asset.GroupKey.GroupPubKey
+	authority := p.IssuanceAuthority
+	if authority != nil {
+		err := authority.Verify(
+			groupKey, p.Asset.ID(), p.BlockHeight, headerVerifier,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if rotationLookup == nil {
+		return nil
+	}
+
+	knownRotations, err := rotationLookup(ctx, groupKey)
+	if err != nil {
+		return fmt.Errorf("unable to look up group key rotations: %w",
+			err)
+	}
+
+	// Only the rotations that were confirmed before the issuance are
+	// binding for it.
+	var numBinding int
+	for _, rotation := range knownRotations {
+		if rotation.BlockHeight > p.BlockHeight {
+			break
+		}
+		numBinding++
+	}
+	if numBinding == 0 {
+		return nil
+	}
+
+	if authority == nil || len(authority.Rotations) < numBinding {
+		return ErrStaleIssuanceAuthority
+	}
+	for idx := 0; idx < numBinding; idx++ {
+		known := knownRotations[idx]
+		rotation := authority.Rotations[idx]
+		if !known.NewKey.IsEqual(rotation.NewKey) ||
+			known.AnchorTx.TxHash() != rotation.AnchorTx.TxHash() {
+
+			return ErrStaleIssuanceAuthority
+		}
+	}
+
+	return nil
+}
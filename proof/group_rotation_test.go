@@ -0,0 +1,343 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// rotationTestGroup is an asset group whose issuance authority is rotated in
+// tests.
+type rotationTestGroup struct {
+	anchorID asset.ID
+	groupKey *btcec.PublicKey
+
+	// keys is the set of keys that held the issuance authority, starting
+	// with the raw group key.
+	keys []*btcec.PrivateKey
+
+	rotations []*GroupKeyRotation
+}
+
+func newRotationTestGroup(t *testing.T) *rotationTestGroup {
+	rawKey := test.RandPrivKey(t)
+	anchorID := asset.RandID(t)
+
+	groupKey, err := asset.GroupPubKey(rawKey.PubKey(), anchorID[:], nil)
+	require.NoError(t, err)
+
+	return &rotationTestGroup{
+		anchorID: anchorID,
+		groupKey: groupKey,
+		keys:     []*btcec.PrivateKey{rawKey},
+	}
+}
+
+// rotate hands the issuance authority over to a new key with a rotation that
+// is confirmed at the given height.
+func (g *rotationTestGroup) rotate(t *testing.T,
+	height uint32) *GroupKeyRotation {
+
+	prevKey := g.keys[len(g.keys)-1]
+	newKey := test.RandPrivKey(t)
+	sequence := uint32(len(g.rotations))
+
+	rotation := newTestRotation(
+		t, g.groupKey, prevKey, newKey.PubKey(), sequence, height,
+	)
+
+	g.keys = append(g.keys, newKey)
+	g.rotations = append(g.rotations, rotation)
+
+	return rotation
+}
+
+// authority returns an issuance authority for the given asset ID with the
+// given rotations, signed by the given key.
+func (g *rotationTestGroup) authority(t *testing.T, assetID asset.ID,
+	rotations []*GroupKeyRotation,
+	signer *btcec.PrivateKey) *IssuanceAuthority {
+
+	digest := sha256.Sum256(IssuanceMessage(g.groupKey, assetID))
+	sig, err := schnorr.Sign(signer, digest[:])
+	require.NoError(t, err)
+
+	return &IssuanceAuthority{
+		GroupAnchorID: g.anchorID,
+		Rotations:     rotations,
+		Signature:     sig,
+	}
+}
+
+// newTestRotation creates a rotation signed by the previous key that is
+// anchored in a block at the given height.
+func newTestRotation(t *testing.T, groupKey *btcec.PublicKey,
+	prevKey *btcec.PrivateKey, newKey *btcec.PublicKey, sequence,
+	height uint32) *GroupKeyRotation {
+
+	digest := sha256.Sum256(RotationMessage(
+		groupKey, prevKey.PubKey(), newKey, sequence,
+	))
+	sig, err := schnorr.Sign(prevKey, digest[:])
+	require.NoError(t, err)
+
+	script, err := RotationScript(
+		groupKey, prevKey.PubKey(), newKey, sequence,
+	)
+	require.NoError(t, err)
+
+	anchorTx := &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: test.RandOp(t),
+		}},
+		TxOut: []*wire.TxOut{{
+			PkScript: test.RandBytes(34),
+			Value:    10_000,
+		}, {
+			PkScript: script,
+		}},
+	}
+	merkleTree := blockchain.BuildMerkleTreeStore(
+		[]*btcutil.Tx{btcutil.NewTx(anchorTx)}, false,
+	)
+	blockHeader := wire.NewBlockHeader(
+		0, chaincfg.MainNetParams.GenesisHash,
+		merkleTree[len(merkleTree)-1], 0, 0,
+	)
+	txMerkleProof, err := NewTxMerkleProof([]*wire.MsgTx{anchorTx}, 0)
+	require.NoError(t, err)
+
+	return &GroupKeyRotation{
+		GroupKey:      groupKey,
+		Sequence:      sequence,
+		PrevKey:       prevKey.PubKey(),
+		NewKey:        newKey,
+		Signature:     sig,
+		AnchorTx:      *anchorTx,
+		OutputIndex:   1,
+		TxMerkleProof: *txMerkleProof,
+		BlockHeader:   *blockHeader,
+		BlockHeight:   height,
+	}
+}
+
+// newReissuanceProof returns a reissuance proof of an asset into the given
+// group at the given height.
+func newReissuanceProof(t *testing.T, groupKey *btcec.PublicKey,
+	height uint32) *Proof {
+
+	reissued := asset.RandAsset(t, asset.Normal)
+	reissued.GroupKey = &asset.GroupKey{
+		GroupPubKey: *groupKey,
+	}
+
+	return &Proof{
+		Asset:       *reissued,
+		BlockHeight: height,
+	}
+}
+
+// TestGroupKeyRotationVerification tests that an issuance authority is only
+// valid if its rotation chain starts at the raw group key, every rotation is
+// valid, and the issuance is signed by the latest key of the chain.
+func TestGroupKeyRotationVerification(t *testing.T) {
+	t.Parallel()
+
+	group := newRotationTestGroup(t)
+	group.rotate(t, 100)
+	group.rotate(t, 200)
+
+	assetID := asset.RandID(t)
+	verify := func(authority *IssuanceAuthority) error {
+		return authority.Verify(
+			group.groupKey, assetID, 300, MockHeaderVerifier,
+		)
+	}
+
+	// An issuance signed by the latest key is valid.
+	authority := group.authority(t, assetID, group.rotations, group.keys[2])
+	require.NoError(t, verify(authority))
+
+	// Neither the raw group key nor the key of the first rotation can
+	// authorize issuance after the second rotation.
+	for _, staleKey := range group.keys[:2] {
+		authority := group.authority(
+			t, assetID, group.rotations, staleKey,
+		)
+		require.ErrorIs(
+			t, verify(authority), ErrIssuanceAuthorityInvalid,
+		)
+	}
+
+	// An authority without any rotations is invalid.
+	authority = group.authority(t, assetID, nil, group.keys[0])
+	require.ErrorIs(t, verify(authority), ErrIssuanceAuthorityInvalid)
+
+	// A rotation that isn't signed by the previous key is rejected.
+	forged := newTestRotation(
+		t, group.groupKey, test.RandPrivKey(t), group.keys[1].PubKey(),
+		0, 100,
+	)
+	forged.PrevKey = group.keys[0].PubKey()
+	authority = group.authority(
+		t, assetID, []*GroupKeyRotation{forged}, group.keys[1],
+	)
+	require.ErrorIs(t, verify(authority), ErrIssuanceAuthorityInvalid)
+
+	// A rotation chain that doesn't start at the raw group key is
+	// rejected, even if all signatures are valid.
+	otherKey := test.RandPrivKey(t)
+	foreign := newTestRotation(
+		t, group.groupKey, otherKey, group.keys[1].PubKey(), 0, 100,
+	)
+	authority = group.authority(
+		t, assetID, []*GroupKeyRotation{foreign}, group.keys[1],
+	)
+	require.ErrorIs(t, verify(authority), ErrIssuanceAuthorityInvalid)
+
+	// A rotation that skips a step of the chain is rejected.
+	authority = group.authority(
+		t, assetID, group.rotations[1:], group.keys[2],
+	)
+	require.ErrorIs(t, verify(authority), ErrIssuanceAuthorityInvalid)
+
+	// The anchor transaction must commit to the rotation.
+	uncommitted := *group.rotations[0]
+	uncommitted.OutputIndex = 0
+	authority = group.authority(
+		t, assetID, []*GroupKeyRotation{&uncommitted}, group.keys[1],
+	)
+	require.ErrorIs(t, verify(authority), ErrIssuanceAuthorityInvalid)
+
+	// A rotation can't authorize an issuance that was confirmed before
+	// the rotation.
+	authority = group.authority(t, assetID, group.rotations, group.keys[2])
+	err := authority.Verify(
+		group.groupKey, assetID, 150, MockHeaderVerifier,
+	)
+	require.ErrorIs(t, err, ErrIssuanceAuthorityInvalid)
+}
+
+// TestStaleIssuanceAuthority tests that reissuance proofs are rejected if they
+// were authorized by a key that was already rotated out at the time of the
+// issuance.
+func TestStaleIssuanceAuthority(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	group := newRotationTestGroup(t)
+	group.rotate(t, 100)
+	group.rotate(t, 200)
+
+	lookup := func(_ context.Context,
+		groupKey *btcec.PublicKey) ([]*GroupKeyRotation, error) {
+
+		require.True(t, groupKey.IsEqual(group.groupKey))
+		return group.rotations, nil
+	}
+	verify := func(p *Proof) error {
+		return p.verifyIssuanceAuthority(
+			ctx, MockHeaderVerifier, lookup,
+		)
+	}
+
+	// Before the first rotation, the raw group key alone authorizes
+	// issuance.
+	p := newReissuanceProof(t, group.groupKey, 50)
+	require.NoError(t, verify(p))
+
+	// After the first rotation, an issuance without an issuance authority
+	// is stale.
+	p = newReissuanceProof(t, group.groupKey, 150)
+	require.ErrorIs(t, verify(p), ErrStaleIssuanceAuthority)
+
+	// The key of the first rotation authorizes issuance until the second
+	// rotation is confirmed.
+	p.IssuanceAuthority = group.authority(
+		t, p.Asset.ID(), group.rotations[:1], group.keys[1],
+	)
+	require.NoError(t, verify(p))
+
+	p = newReissuanceProof(t, group.groupKey, 250)
+	p.IssuanceAuthority = group.authority(
+		t, p.Asset.ID(), group.rotations[:1], group.keys[1],
+	)
+	require.ErrorIs(t, verify(p), ErrStaleIssuanceAuthority)
+
+	// A rotation chain that forks off from the known one is stale as well.
+	forkKey := test.RandPrivKey(t)
+	fork := newTestRotation(
+		t, group.groupKey, group.keys[1], forkKey.PubKey(), 1, 180,
+	)
+	p.IssuanceAuthority = group.authority(
+		t, p.Asset.ID(), []*GroupKeyRotation{group.rotations[0], fork},
+		forkKey,
+	)
+	require.ErrorIs(t, verify(p), ErrStaleIssuanceAuthority)
+
+	// The latest key authorizes issuance after the second rotation.
+	p.IssuanceAuthority = group.authority(
+		t, p.Asset.ID(), group.rotations, group.keys[2],
+	)
+	require.NoError(t, verify(p))
+
+	// Without a rotation lookup, only the authority itself is verified.
+	p = newReissuanceProof(t, group.groupKey, 250)
+	require.NoError(t, p.verifyIssuanceAuthority(
+		ctx, MockHeaderVerifier, nil,
+	))
+}
+
+// TestIssuanceAuthorityEncoding tests that a proof with an issuance authority
+// can be encoded and decoded, and that an issuance authority is rejected for
+// proofs that aren't reissuances.
+func TestIssuanceAuthorityEncoding(t *testing.T) {
+	t.Parallel()
+
+	amt := uint64(1000)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil, asset.V0,
+	)
+
+	group := newRotationTestGroup(t)
+	group.rotate(t, 100)
+	group.rotate(t, 200)
+
+	authority := group.authority(
+		t, genesisProof.Asset.ID(), group.rotations, group.keys[2],
+	)
+	authority.GroupTapscriptRoot = test.RandBytes(32)
+	genesisProof.IssuanceAuthority = authority
+
+	var buf bytes.Buffer
+	require.NoError(t, genesisProof.Encode(&buf))
+
+	var decoded Proof
+	require.NoError(t, decoded.Decode(&buf))
+	require.NotNil(t, decoded.IssuanceAuthority)
+
+	var authorityBuf, decodedBuf bytes.Buffer
+	require.NoError(t, authority.Encode(&authorityBuf))
+	require.NoError(t, decoded.IssuanceAuthority.Encode(&decodedBuf))
+	require.Equal(t, authorityBuf.Bytes(), decodedBuf.Bytes())
+
+	// The genesis proof is a group anchor, which can't carry an issuance
+	// authority.
+	_, err := decoded.Verify(
+		context.Background(), nil, MockHeaderVerifier,
+		MockGroupVerifier,
+	)
+	require.ErrorIs(t, err, ErrIssuanceAuthorityInvalid)
+}
@@ -0,0 +1,336 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+)
+
+const (
+	// HTTPSCourierType is a courier that delivers proofs through a
+	// store-and-forward mailbox served over HTTPS.
+	HTTPSCourierType = "https"
+
+	// HTTPCourierType is a courier that delivers proofs through a
+	// store-and-forward mailbox served over plain HTTP. This should only
+	// be used for testing, as the proofs are sent unencrypted.
+	HTTPCourierType = "http"
+
+	// httpMailboxPath is the path below the courier address at which the
+	// messages of a stream are stored.
+	httpMailboxPath = "mailbox"
+
+	// defaultHTTPPollInterval is the interval at which the mailbox is
+	// polled for new messages if no interval is configured.
+	defaultHTTPPollInterval = 5 * time.Second
+
+	// defaultHTTPRequestTimeout is the timeout of a single request to the
+	// mailbox server if no timeout is configured.
+	defaultHTTPRequestTimeout = 30 * time.Second
+
+	// maxHTTPMessageSize is the maximum size of a message we read from the
+	// mailbox server.
+	maxHTTPMessageSize = 1 << 26
+)
+
+// HTTPCourierCfg is the config of the HTTP(S) proof courier.
+type HTTPCourierCfg struct {
+	PollInterval time.Duration `long:"pollinterval" description:"The interval at which the HTTP(S) mailbox is polled for inbound proofs and acknowledgements."`
+
+	RequestTimeout time.Duration `long:"requesttimeout" description:"The timeout of a single request to the HTTP(S) mailbox server."`
+}
+
+// Validate returns an error if the config can't be used to deliver or receive
+// proofs.
+func (c *HTTPCourierCfg) Validate() error {
+	switch {
+	case c.PollInterval < 0:
+		return fmt.Errorf("http courier poll interval must not be " +
+			"negative")
+
+	case c.RequestTimeout < 0:
+		return fmt.Errorf("http courier request timeout must not be " +
+			"negative")
+	}
+
+	return nil
+}
+
+// HTTPCourierAddr is an HTTP(S) specific implementation of the CourierAddr
+// interface. The address is the base URL of a store-and-forward mailbox
+// server, e.g. https://courier.example.com/tapd. The messages of a stream are
+// written to the server with a POST request to <base>/mailbox/<sid>, read with
+// a GET request to the same URL, which returns 404 if no message was written
+// yet, and removed with a DELETE request.
+type HTTPCourierAddr struct {
+	addr url.URL
+}
+
+// NewHTTPCourierAddr generates a new HTTP(S) courier address from a given URL.
+// This function also performs protocol specific address validation.
+func NewHTTPCourierAddr(addr url.URL) (*HTTPCourierAddr, error) {
+	if addr.Scheme != HTTPSCourierType && addr.Scheme != HTTPCourierType {
+		return nil, fmt.Errorf("expected http(s) courier protocol: %v",
+			addr.Scheme)
+	}
+
+	if addr.Hostname() == "" {
+		return nil, fmt.Errorf("http proof courier URI address host " +
+			"unspecified")
+	}
+
+	if addr.RawQuery != "" || addr.Fragment != "" {
+		return nil, fmt.Errorf("http proof courier URI address must " +
+			"not have a query or fragment")
+	}
+
+	return &HTTPCourierAddr{
+		addr: addr,
+	}, nil
+}
+
+// Url returns the url.URL representation of the courier address.
+func (h *HTTPCourierAddr) Url() *url.URL {
+	return &h.addr
+}
+
+// NewCourier generates a new courier service handle. The HTTP(S) courier uses
+// the same delivery flow as the hashmail courier, with an HTTP(S) backed
+// mailbox.
+func (h *HTTPCourierAddr) NewCourier(_ context.Context, cfg *CourierCfg,
+	recipient Recipient) (Courier, error) {
+
+	httpCfg := cfg.HTTPCfg
+	if httpCfg == nil {
+		httpCfg = &HTTPCourierCfg{}
+	}
+	if err := httpCfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &HashMailCourier{
+		cfg: &HashMailCourierCfg{
+			ReceiverAckTimeout: cfg.ReceiverAckTimeout,
+			BackoffCfg:         cfg.BackoffCfg,
+		},
+		mode:             cfg.Mode(recipient.ReceiveType),
+		recipient:        recipient,
+		mailbox:          NewHTTPMailbox(httpCfg, &h.addr, nil),
+		deliveryLog:      cfg.DeliveryLog,
+		receiptSigner:    cfg.ReceiptSigner,
+		pausedDeliveries: cfg.PausedDeliveries,
+		subscribers:      make(map[uint64]*fn.EventReceiver[fn.Event]),
+	}, nil
+}
+
+// HTTPMailbox is an implementation of the ProofMailbox interface backed by a
+// store-and-forward mailbox server that is accessed over HTTP(S).
+type HTTPMailbox struct {
+	cfg *HTTPCourierCfg
+
+	// baseURL is the URL of the mailbox server the stream paths are
+	// appended to.
+	baseURL url.URL
+
+	client *http.Client
+}
+
+// NewHTTPMailbox creates a new HTTP(S) backed mailbox for the server at the
+// given base URL. If the client is nil, a default client with the configured
+// request timeout is used.
+func NewHTTPMailbox(cfg *HTTPCourierCfg, baseURL *url.URL,
+	client *http.Client) *HTTPMailbox {
+
+	if client == nil {
+		timeout := cfg.RequestTimeout
+		if timeout == 0 {
+			timeout = defaultHTTPRequestTimeout
+		}
+
+		client = &http.Client{
+			Timeout: timeout,
+		}
+	}
+
+	return &HTTPMailbox{
+		cfg:     cfg,
+		baseURL: *baseURL,
+		client:  client,
+	}
+}
+
+// Init creates a mailbox given the specified stream ID. The server creates
+// the mailbox of a stream when the first message is written to it, so this is
+// a no-op.
+func (h *HTTPMailbox) Init(context.Context, streamID) error {
+	return nil
+}
+
+// WriteProof writes the proof to the mailbox specified by the sid.
+func (h *HTTPMailbox) WriteProof(ctx context.Context, sid streamID,
+	proof Blob) error {
+
+	return h.write(ctx, sid, proof)
+}
+
+// ReadProof reads a proof from the mailbox. This is a blocking method.
+func (h *HTTPMailbox) ReadProof(ctx context.Context,
+	sid streamID) (Blob, error) {
+
+	return h.read(ctx, sid)
+}
+
+// AckProof sends an ACK from the receiver to the sender that a proof has been
+// received.
+func (h *HTTPMailbox) AckProof(ctx context.Context, sid streamID,
+	receipt *DeliveryReceipt) error {
+
+	return h.write(ctx, sid, encodeAckMsg(receipt))
+}
+
+// RecvAck waits for the sender to receive the ack from the receiver.
+func (h *HTTPMailbox) RecvAck(ctx context.Context,
+	sid streamID) (*DeliveryReceipt, error) {
+
+	msg, err := h.read(ctx, sid)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeAckMsg(msg)
+}
+
+// CleanUp attempts to tear down the mailbox as specified by the passed sid.
+func (h *HTTPMailbox) CleanUp(ctx context.Context, sid streamID) error {
+	resp, err := h.do(ctx, http.MethodDelete, sid, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+
+	default:
+		return fmt.Errorf("unable to clean up http mailbox: %v",
+			resp.Status)
+	}
+}
+
+// write writes the given message to the mailbox of the given stream.
+func (h *HTTPMailbox) write(ctx context.Context, sid streamID,
+	msg []byte) error {
+
+	resp, err := h.do(ctx, http.MethodPost, sid, msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unable to write to http mailbox: %v",
+			resp.Status)
+	}
+
+	return nil
+}
+
+// read polls the mailbox of the given stream until a message was written to
+// it and returns the message.
+func (h *HTTPMailbox) read(ctx context.Context, sid streamID) ([]byte, error) {
+	pollInterval := h.cfg.PollInterval
+	if pollInterval == 0 {
+		pollInterval = defaultHTTPPollInterval
+	}
+
+	for {
+		msg, err := h.fetch(ctx, sid)
+		switch {
+		case err != nil:
+			log.Warnf("Unable to read from http mailbox: %v", err)
+
+		case msg != nil:
+			return msg, nil
+		}
+
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// fetch fetches the message of the given stream from the mailbox. Nil is
+// returned if no message was written to it yet.
+func (h *HTTPMailbox) fetch(ctx context.Context, sid streamID) ([]byte, error) {
+	resp, err := h.do(ctx, http.MethodGet, sid, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		msg, err := io.ReadAll(
+			io.LimitReader(resp.Body, maxHTTPMessageSize),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// An empty message is treated as no message, so a server can
+		// signal that a mailbox exists but is still empty.
+		if len(msg) == 0 {
+			return nil, nil
+		}
+
+		return msg, nil
+
+	case http.StatusNotFound, http.StatusNoContent:
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected http mailbox response: %v",
+			resp.Status)
+	}
+}
+
+// do sends a request with the given method and body to the mailbox URL of the
+// given stream.
+func (h *HTTPMailbox) do(ctx context.Context, method string, sid streamID,
+	body []byte) (*http.Response, error) {
+
+	streamURL := h.baseURL.JoinPath(
+		httpMailboxPath, hex.EncodeToString(sid[:]),
+	)
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, method, streamURL.String(), bodyReader,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	return h.client.Do(req)
+}
+
+// A compile-time assertion to ensure the HTTPMailbox meets the ProofMailbox
+// interface.
+var _ ProofMailbox = (*HTTPMailbox)(nil)
@@ -0,0 +1,187 @@
+package proof
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHTTPMailboxServer is an in-memory store-and-forward mailbox server that
+// speaks the protocol expected by the HTTP(S) courier.
+type mockHTTPMailboxServer struct {
+	sync.Mutex
+
+	mailboxes map[string][]byte
+}
+
+// ServeHTTP handles a single request to the mailbox server.
+func (m *mockHTTPMailboxServer) ServeHTTP(w http.ResponseWriter,
+	r *http.Request) {
+
+	sid, ok := strings.CutPrefix(r.URL.Path, "/base/mailbox/")
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	switch r.Method {
+	case http.MethodPost:
+		msg, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.mailboxes[sid] = msg
+
+	case http.MethodGet:
+		msg, ok := m.mailboxes[sid]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(msg)
+
+	case http.MethodDelete:
+		delete(m.mailboxes, sid)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// TestHTTPMailbox tests that proofs and ACKs are exchanged through an HTTP(S)
+// mailbox server.
+func TestHTTPMailbox(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	server := &mockHTTPMailboxServer{
+		mailboxes: make(map[string][]byte),
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	baseURL, err := url.Parse(httpServer.URL + "/base")
+	require.NoError(t, err)
+
+	cfg := &HTTPCourierCfg{
+		PollInterval: 10 * time.Millisecond,
+	}
+	sender := NewHTTPMailbox(cfg, baseURL, httpServer.Client())
+	receiver := NewHTTPMailbox(cfg, baseURL, httpServer.Client())
+
+	recipient := Recipient{
+		ScriptKey: test.RandPubKey(t),
+		AssetID:   [32]byte{1},
+		Amount:    100,
+	}
+	senderSID := deriveSenderStreamID(recipient)
+	receiverSID := deriveReceiverStreamID(recipient)
+
+	// Reading blocks until a proof was written to the mailbox.
+	shortCtx, shortCancel := context.WithTimeout(
+		ctx, 50*time.Millisecond,
+	)
+	_, err = receiver.ReadProof(shortCtx, senderSID)
+	shortCancel()
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	proofBlob := Blob(test.RandBytes(500))
+	require.NoError(t, sender.Init(ctx, senderSID))
+	require.NoError(t, sender.WriteProof(ctx, senderSID, proofBlob))
+
+	receivedProof, err := receiver.ReadProof(ctx, senderSID)
+	require.NoError(t, err)
+	require.Equal(t, proofBlob, receivedProof)
+
+	receipt := signTestReceipt(
+		t, test.RandPrivKey(t), nil, sha256.Sum256(proofBlob),
+	)
+	require.NoError(t, receiver.AckProof(ctx, receiverSID, receipt))
+
+	receivedReceipt, err := sender.RecvAck(ctx, receiverSID)
+	require.NoError(t, err)
+	require.Equal(t, receipt, receivedReceipt)
+
+	// Cleaning up removes both streams from the server, and cleaning up a
+	// stream that doesn't exist isn't an error.
+	require.NoError(t, sender.CleanUp(ctx, senderSID))
+	require.NoError(t, sender.CleanUp(ctx, receiverSID))
+	require.NoError(t, sender.CleanUp(ctx, receiverSID))
+
+	server.Lock()
+	require.Empty(t, server.mailboxes)
+	server.Unlock()
+
+	// Server errors are surfaced when writing.
+	brokenURL, err := url.Parse(httpServer.URL + "/broken")
+	require.NoError(t, err)
+	broken := NewHTTPMailbox(cfg, brokenURL, httpServer.Client())
+	err = broken.WriteProof(ctx, senderSID, proofBlob)
+	require.ErrorContains(t, err, "400")
+}
+
+// TestHTTPCourierAddr tests that HTTP(S) courier addresses are parsed and
+// validated correctly.
+func TestHTTPCourierAddr(t *testing.T) {
+	t.Parallel()
+
+	for _, valid := range []string{
+		"https://courier.example.com", "http://localhost:8080/tapd",
+	} {
+		addr, err := ParseCourierAddrString(valid)
+		require.NoError(t, err, valid)
+
+		_, ok := addr.(*HTTPCourierAddr)
+		require.True(t, ok)
+		require.Equal(t, valid, addr.Url().String())
+
+		courier, err := addr.NewCourier(
+			context.Background(), &CourierCfg{}, Recipient{
+				ScriptKey: test.RandPubKey(t),
+			},
+		)
+		require.NoError(t, err)
+		require.IsType(t, &HashMailCourier{}, courier)
+	}
+
+	for _, invalid := range []string{
+		"https://", "https://example.com?a=b",
+		"https://example.com#frag",
+	} {
+		invalidURL, err := url.Parse(invalid)
+		require.NoError(t, err)
+
+		_, err = NewHTTPCourierAddr(*invalidURL)
+		require.Error(t, err, invalid)
+	}
+
+	// A negative poll interval is rejected.
+	addr, err := ParseCourierAddrString("https://courier.example.com")
+	require.NoError(t, err)
+	_, err = addr.NewCourier(
+		context.Background(), &CourierCfg{
+			HTTPCfg: &HTTPCourierCfg{
+				PollInterval: -time.Second,
+			},
+		}, Recipient{
+			ScriptKey: test.RandPubKey(t),
+		},
+	)
+	require.ErrorContains(t, err, "negative")
+}
@@ -3,11 +3,17 @@ package proof
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math/bits"
+	"strings"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 // MetaType is the type of the meta data being revealed.
@@ -18,6 +24,10 @@ const (
 	// bytes without any specific interpretation.
 	MetaOpaque MetaType = 0
 
+	// MetaJson signals that the meta data is a JSON document. The meta
+	// data of this type must be valid JSON.
+	MetaJson MetaType = 1
+
 	// MetaDataMaxSizeBytes is the maximum length of the meta data. We limit
 	// this to 1MiB for now. This should be of sufficient size to commit to
 	// any JSON data or even medium resolution images. If there is need to
@@ -35,6 +45,28 @@ var (
 
 	// ErrMetaDataTooLarge signals that the meta data is too large.
 	ErrMetaDataTooLarge = errors.New("meta data too large")
+
+	// ErrMetaTypeUnknown signals that the type of the meta data is not
+	// known.
+	ErrMetaTypeUnknown = errors.New("unknown meta type")
+
+	// ErrInvalidJSON signals that the meta data is declared to be JSON but
+	// doesn't parse as such.
+	ErrInvalidJSON = errors.New("meta data is not valid JSON")
+
+	// ErrJSONSchemaMismatch signals that the JSON meta data doesn't adhere
+	// to the JSON schema it is validated against.
+	ErrJSONSchemaMismatch = errors.New("meta data doesn't match JSON " +
+		"schema")
+
+	// ErrSupplyCapExceeded signals that the total amount issued for an
+	// asset group exceeds the supply cap declared for the group.
+	ErrSupplyCapExceeded = errors.New("supply cap exceeded")
+
+	// ErrSupplyCapNotGroupAnchor signals that an issuance that isn't the
+	// anchor of an asset group declares a supply cap.
+	ErrSupplyCapNotGroupAnchor = errors.New("supply cap can only be " +
+		"declared by a group anchor")
 )
 
 // MetaReveal is an optional TLV type that can be added to the proof of a
@@ -48,6 +80,12 @@ type MetaReveal struct {
 
 	// Data is the committed data being revealed.
 	Data []byte
+
+	// SupplyCap is the optional maximum total amount that can ever be
+	// issued for the asset group anchored by this asset. A value of zero
+	// means there is no cap. Because the cap is part of the meta hash, it
+	// is committed to in the genesis of the group anchor.
+	SupplyCap uint64
 }
 
 // Validate validates the meta reveal.
@@ -57,8 +95,9 @@ func (m *MetaReveal) Validate() error {
 		return nil
 	}
 
-	// If a meta reveal is present, then the data must be non-empty.
-	if len(m.Data) == 0 {
+	// If a meta reveal is present, then the data must be non-empty, unless
+	// the reveal only declares a supply cap.
+	if len(m.Data) == 0 && m.SupplyCap == 0 {
 		return ErrMetaDataMissing
 	}
 
@@ -66,6 +105,47 @@ func (m *MetaReveal) Validate() error {
 		return ErrMetaDataTooLarge
 	}
 
+	// The declared type must match the payload. A reveal that only
+	// declares a supply cap doesn't have any payload to check.
+	switch m.Type {
+	// Opaque data isn't interpreted, so there's nothing to check.
+	case MetaOpaque:
+
+	case MetaJson:
+		if len(m.Data) != 0 && !json.Valid(m.Data) {
+			return ErrInvalidJSON
+		}
+
+	default:
+		return fmt.Errorf("%w: %d", ErrMetaTypeUnknown, m.Type)
+	}
+
+	return nil
+}
+
+// ValidateJSONSchema validates the meta data against the given JSON schema if
+// the meta data is of the JSON type. Meta data of any other type is not
+// checked.
+func (m *MetaReveal) ValidateJSONSchema(schema *gojsonschema.Schema) error {
+	if m == nil || m.Type != MetaJson || len(m.Data) == 0 {
+		return nil
+	}
+
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(m.Data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+
+	if !result.Valid() {
+		violations := make([]string, 0, len(result.Errors()))
+		for _, resultErr := range result.Errors() {
+			violations = append(violations, resultErr.String())
+		}
+
+		return fmt.Errorf("%w: %s", ErrJSONSchemaMismatch,
+			strings.Join(violations, "; "))
+	}
+
 	return nil
 }
 
@@ -80,10 +160,20 @@ func (m *MetaReveal) MetaHash() [asset.MetaHashLen]byte {
 
 // EncodeRecords returns the TLV encode records for the meta reveal.
 func (m *MetaReveal) EncodeRecords() []tlv.Record {
-	return []tlv.Record{
+	records := []tlv.Record{
 		MetaRevealTypeRecord(&m.Type),
 		MetaRevealDataRecord(&m.Data),
 	}
+
+	// The supply cap is only encoded if it's set, so the meta hash of
+	// reveals without a cap stays the same.
+	if m.SupplyCap != 0 {
+		records = append(
+			records, MetaRevealSupplyCapRecord(&m.SupplyCap),
+		)
+	}
+
+	return records
 }
 
 // DecodeRecords returns the TLV decode records for the meta reveal.
@@ -91,6 +181,7 @@ func (m *MetaReveal) DecodeRecords() []tlv.Record {
 	return []tlv.Record{
 		MetaRevealTypeRecord(&m.Type),
 		MetaRevealDataRecord(&m.Data),
+		MetaRevealSupplyCapRecord(&m.SupplyCap),
 	}
 }
 
@@ -111,3 +202,78 @@ func (m *MetaReveal) Decode(r io.Reader) error {
 	}
 	return stream.Decode(r)
 }
+
+// VerifyGroupSupplyCap verifies that the total amount issued by the given set
+// of issuance proofs of a single asset group doesn't exceed the supply cap
+// declared by the group anchor. The cap is only taken from the proof of the
+// group anchor, which is the issuance proof that reveals the group key, as only
+// the anchor's meta is committed to when the group is created. A re-issuance
+// proof that declares a cap is rejected. All issuance proofs of the group,
+// including the one of the anchor, must be passed in for the check to be
+// meaningful. If the anchor doesn't declare a supply cap, then the group is
+// uncapped and no error is returned.
+func VerifyGroupSupplyCap(issuanceProofs []*Proof) error {
+	var (
+		groupKey    *btcec.PublicKey
+		anchorFound bool
+		supplyCap   uint64
+		totalIssued uint64
+	)
+	for idx := range issuanceProofs {
+		p := issuanceProofs[idx]
+
+		if !p.Asset.IsGenesisAsset() {
+			return fmt.Errorf("proof %d is not an issuance proof",
+				idx)
+		}
+		if p.Asset.GroupKey == nil {
+			return fmt.Errorf("proof %d is not for a grouped asset",
+				idx)
+		}
+
+		assetGroupKey := &p.Asset.GroupKey.GroupPubKey
+		switch {
+		case groupKey == nil:
+			groupKey = assetGroupKey
+
+		case !groupKey.IsEqual(assetGroupKey):
+			return fmt.Errorf("proof %d is for a different asset "+
+				"group", idx)
+		}
+
+		var proofCap uint64
+		if p.MetaReveal != nil {
+			proofCap = p.MetaReveal.SupplyCap
+		}
+
+		// Only the group anchor can declare the cap. A re-issuance
+		// could otherwise raise or introduce a cap after the fact.
+		switch {
+		case p.GroupKeyReveal != nil && anchorFound:
+			return fmt.Errorf("proof %d is a second group anchor "+
+				"proof", idx)
+
+		case p.GroupKeyReveal != nil:
+			anchorFound = true
+			supplyCap = proofCap
+
+		case proofCap != 0:
+			return fmt.Errorf("%w: re-issuance proof %d declares "+
+				"a supply cap of %d",
+				ErrSupplyCapNotGroupAnchor, idx, proofCap)
+		}
+
+		amount, carry := bits.Add64(totalIssued, p.Asset.Amount, 0)
+		if carry != 0 {
+			return fmt.Errorf("total issued amount uint64 overflow")
+		}
+		totalIssued = amount
+	}
+
+	if supplyCap != 0 && totalIssued > supplyCap {
+		return fmt.Errorf("%w: issued %d of cap %d",
+			ErrSupplyCapExceeded, totalIssued, supplyCap)
+	}
+
+	return nil
+}
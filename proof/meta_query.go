@@ -0,0 +1,167 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	// metaQueryTermSeparator separates the terms of a meta query, all of
+	// which need to match.
+	metaQueryTermSeparator = "&&"
+
+	// metaQueryPathSeparator separates the keys of the path of a term
+	// into nested JSON objects.
+	metaQueryPathSeparator = "."
+)
+
+// metaQueryTerm is a single term of a meta query.
+type metaQueryTerm struct {
+	// path is the key path of the JSON value the term refers to.
+	path []string
+
+	// value is the value the JSON value at the path needs to have. If
+	// this is nil, any value matches.
+	value *string
+}
+
+// MetaQuery is a query expression that meta data can be matched against. It
+// consists of one or more terms separated by "&&", all of which need to
+// match. A term of the form path=value matches JSON meta data that has the
+// given value at the dot separated key path, e.g. "artist.name=satoshi". A
+// term that only consists of a path matches JSON meta data that has any value
+// at the path. Meta data that isn't a JSON object matches if it contains the
+// complete query as a substring.
+type MetaQuery struct {
+	// raw is the query as it was passed in.
+	raw string
+
+	// terms are the parsed terms of the query.
+	terms []metaQueryTerm
+}
+
+// ParseMetaQuery parses the given query expression.
+func ParseMetaQuery(query string) (*MetaQuery, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("meta query must not be empty")
+	}
+
+	q := &MetaQuery{
+		raw: query,
+	}
+	for _, rawTerm := range strings.Split(query, metaQueryTermSeparator) {
+		rawPath, rawValue, hasValue := strings.Cut(rawTerm, "=")
+
+		rawPath = strings.TrimSpace(rawPath)
+		if rawPath == "" {
+			return nil, fmt.Errorf("invalid meta query term %q: "+
+				"missing key", rawTerm)
+		}
+
+		term := metaQueryTerm{
+			path: strings.Split(rawPath, metaQueryPathSeparator),
+		}
+		for _, key := range term.path {
+			if key == "" {
+				return nil, fmt.Errorf("invalid meta query "+
+					"term %q: empty key", rawTerm)
+			}
+		}
+
+		if hasValue {
+			value := strings.TrimSpace(rawValue)
+			term.value = &value
+		}
+
+		q.terms = append(q.terms, term)
+	}
+
+	return q, nil
+}
+
+// String returns the query as it was passed in.
+func (q *MetaQuery) String() string {
+	return q.raw
+}
+
+// Matches returns true if the given meta data matches the query.
+func (q *MetaQuery) Matches(meta *MetaReveal) bool {
+	if meta == nil || len(meta.Data) == 0 {
+		return false
+	}
+
+	// Meta data that is a JSON object is matched by its fields, any other
+	// meta data is opaque to us, so we can only look for the query in it.
+	fields, ok := decodeJSONObject(meta.Data)
+	if !ok {
+		return bytes.Contains(meta.Data, []byte(q.raw))
+	}
+
+	for _, term := range q.terms {
+		if !term.matches(fields) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches returns true if the given JSON object matches the term.
+func (t *metaQueryTerm) matches(fields map[string]any) bool {
+	var value any = fields
+	for _, key := range t.path {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		value, ok = obj[key]
+		if !ok {
+			return false
+		}
+	}
+
+	if t.value == nil {
+		return true
+	}
+
+	switch v := value.(type) {
+	// Strings are compared without their quotes.
+	case string:
+		return v == *t.value
+
+	// Numbers are compared in their original representation.
+	case json.Number:
+		return v.String() == *t.value
+
+	case bool:
+		return fmt.Sprint(v) == *t.value
+
+	case nil:
+		return *t.value == "null"
+
+	// Objects and arrays never equal a plain value.
+	default:
+		return false
+	}
+}
+
+// decodeJSONObject attempts to decode the given data as a JSON object.
+func decodeJSONObject(data []byte) (map[string]any, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil || fields == nil {
+		return nil, false
+	}
+
+	// Trailing data means this isn't a single JSON object.
+	if decoder.More() {
+		return nil, false
+	}
+
+	return fields, true
+}
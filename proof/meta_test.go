@@ -1,9 +1,14 @@
 package proof
 
 import (
+	"bytes"
+	"math"
 	"testing"
 
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightningnetwork/lnd/tlv"
 	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 func TestValidateMetaReveal(t *testing.T) {
@@ -31,6 +36,13 @@ func TestValidateMetaReveal(t *testing.T) {
 			Data: nil,
 		},
 		expectedErr: ErrMetaDataMissing,
+	}, {
+		name: "only supply cap",
+		reveal: &MetaReveal{
+			Type:      MetaOpaque,
+			SupplyCap: 1000,
+		},
+		expectedErr: nil,
 	}, {
 		name: "too much data",
 		reveal: &MetaReveal{
@@ -38,6 +50,27 @@ func TestValidateMetaReveal(t *testing.T) {
 			Data: make([]byte, MetaDataMaxSizeBytes+1),
 		},
 		expectedErr: ErrMetaDataTooLarge,
+	}, {
+		name: "valid JSON",
+		reveal: &MetaReveal{
+			Type: MetaJson,
+			Data: []byte(`{"name": "itest", "decimals": 2}`),
+		},
+		expectedErr: nil,
+	}, {
+		name: "invalid JSON",
+		reveal: &MetaReveal{
+			Type: MetaJson,
+			Data: []byte("itest-metadata"),
+		},
+		expectedErr: ErrInvalidJSON,
+	}, {
+		name: "unknown type",
+		reveal: &MetaReveal{
+			Type: 99,
+			Data: []byte("data"),
+		},
+		expectedErr: ErrMetaTypeUnknown,
 	}}
 
 	for _, tc := range testCases {
@@ -57,3 +90,326 @@ func TestValidateMetaReveal(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateJSONSchema tests that JSON meta data is validated against a
+// JSON schema, while meta data of other types is not.
+func TestValidateJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"}
+		},
+		"required": ["name"]
+	}`))
+	require.NoError(t, err)
+
+	valid := &MetaReveal{
+		Type: MetaJson,
+		Data: []byte(`{"name": "itest"}`),
+	}
+	require.NoError(t, valid.ValidateJSONSchema(schema))
+
+	mismatch := &MetaReveal{
+		Type: MetaJson,
+		Data: []byte(`{"name": 1}`),
+	}
+	require.ErrorIs(
+		t, mismatch.ValidateJSONSchema(schema), ErrJSONSchemaMismatch,
+	)
+
+	opaque := &MetaReveal{
+		Type: MetaOpaque,
+		Data: []byte("itest-metadata"),
+	}
+	require.NoError(t, opaque.ValidateJSONSchema(schema))
+
+	var nilReveal *MetaReveal
+	require.NoError(t, nilReveal.ValidateJSONSchema(schema))
+}
+
+// TestMetaRevealSupplyCap tests that the supply cap of a meta reveal survives
+// an encoding round trip and that it doesn't change the meta hash of reveals
+// without a cap.
+func TestMetaRevealSupplyCap(t *testing.T) {
+	t.Parallel()
+
+	reveal := &MetaReveal{
+		Type: MetaOpaque,
+		Data: []byte("data"),
+	}
+	uncappedHash := reveal.MetaHash()
+
+	// The meta hash of a reveal without a cap must be the hash of just the
+	// type and data records, like it was before the cap was introduced.
+	require.Len(t, reveal.EncodeRecords(), 2)
+
+	reveal.SupplyCap = 21_000_000
+	require.NotEqual(t, uncappedHash, reveal.MetaHash())
+
+	var b bytes.Buffer
+	require.NoError(t, reveal.Encode(&b))
+
+	encoded := b.Bytes()
+
+	var decoded MetaReveal
+	require.NoError(t, decoded.Decode(bytes.NewReader(encoded)))
+	require.Equal(t, reveal, &decoded)
+	require.Equal(t, reveal.MetaHash(), decoded.MetaHash())
+
+	// A decoder that doesn't know about the supply cap must skip it
+	// instead of failing, which requires the record type to be odd.
+	var legacy MetaReveal
+	legacyStream, err := tlv.NewStream(
+		MetaRevealTypeRecord(&legacy.Type),
+		MetaRevealDataRecord(&legacy.Data),
+	)
+	require.NoError(t, err)
+	require.NoError(t, legacyStream.Decode(bytes.NewReader(encoded)))
+	require.Equal(t, reveal.Data, legacy.Data)
+	require.Zero(t, legacy.SupplyCap)
+}
+
+// TestVerifySupplyCap tests that a single issuance proof may only declare a
+// supply cap if it's the proof of a group anchor that respects the cap.
+func TestVerifySupplyCap(t *testing.T) {
+	t.Parallel()
+
+	anchor := asset.RandAsset(t, asset.Normal)
+	anchor.Amount = 600
+	groupKeyReveal := &asset.GroupKeyReveal{
+		RawKey: asset.ToSerialized(&anchor.GroupKey.GroupPubKey),
+	}
+	newProof := func(a *asset.Asset, reveal *asset.GroupKeyReveal,
+		supplyCap uint64) *Proof {
+
+		return &Proof{
+			Asset: *a,
+			MetaReveal: &MetaReveal{
+				Type:      MetaOpaque,
+				SupplyCap: supplyCap,
+			},
+			GroupKeyReveal: reveal,
+		}
+	}
+
+	// Proofs without a cap are always fine.
+	require.NoError(t, newProof(anchor, nil, 0).verifySupplyCap())
+	require.NoError(t, (&Proof{Asset: *anchor}).verifySupplyCap())
+
+	// A group anchor may declare a cap that covers its own amount.
+	p := newProof(anchor, groupKeyReveal, 600)
+	require.NoError(t, p.verifySupplyCap())
+
+	p = newProof(anchor, groupKeyReveal, 599)
+	require.ErrorIs(t, p.verifySupplyCap(), ErrSupplyCapExceeded)
+
+	// A re-issuance can't declare a cap.
+	p = newProof(anchor, nil, 1000)
+	require.ErrorIs(t, p.verifySupplyCap(), ErrSupplyCapNotGroupAnchor)
+
+	// Neither can an asset that isn't grouped at all.
+	ungrouped := asset.RandAsset(t, asset.Normal)
+	ungrouped.GroupKey = nil
+	p = newProof(ungrouped, nil, 1000)
+	require.ErrorIs(t, p.verifySupplyCap(), ErrSupplyCapNotGroupAnchor)
+}
+
+// TestVerifyGroupSupplyCap tests that the total issuance of an asset group is
+// checked against the supply cap declared by the group anchor proof.
+func TestVerifyGroupSupplyCap(t *testing.T) {
+	t.Parallel()
+
+	anchor := asset.RandAsset(t, asset.Normal)
+	anchor.Amount = 600
+
+	newTranche := func(amt uint64) *asset.Asset {
+		tranche := asset.RandAsset(t, asset.Normal)
+		tranche.GroupKey = anchor.GroupKey
+		tranche.Amount = amt
+		return tranche
+	}
+	groupKeyReveal := &asset.GroupKeyReveal{
+		RawKey: asset.ToSerialized(&anchor.GroupKey.GroupPubKey),
+	}
+
+	anchorProof := &Proof{
+		Asset: *anchor,
+		MetaReveal: &MetaReveal{
+			Type:      MetaOpaque,
+			SupplyCap: 1000,
+		},
+		GroupKeyReveal: groupKeyReveal,
+	}
+
+	// Issuing up to the cap is fine.
+	err := VerifyGroupSupplyCap([]*Proof{
+		anchorProof, {Asset: *newTranche(400)},
+	})
+	require.NoError(t, err)
+
+	// Issuing more than the cap isn't.
+	err = VerifyGroupSupplyCap([]*Proof{
+		anchorProof, {Asset: *newTranche(401)},
+	})
+	require.ErrorIs(t, err, ErrSupplyCapExceeded)
+
+	// A re-issuance can't declare a cap, neither a different one nor the
+	// same one.
+	for _, reissueCap := range []uint64{5000, 1000} {
+		err = VerifyGroupSupplyCap([]*Proof{
+			anchorProof, {
+				Asset: *newTranche(1),
+				MetaReveal: &MetaReveal{
+					Type:      MetaOpaque,
+					SupplyCap: reissueCap,
+				},
+			},
+		})
+		require.ErrorContains(t, err, "declares a supply cap")
+	}
+
+	// A re-issuance can't introduce a cap for an uncapped group either.
+	err = VerifyGroupSupplyCap([]*Proof{
+		{
+			Asset: *newTranche(1),
+			MetaReveal: &MetaReveal{
+				Type:      MetaOpaque,
+				SupplyCap: 1,
+			},
+		}, {Asset: *anchor, GroupKeyReveal: groupKeyReveal},
+	})
+	require.ErrorContains(t, err, "declares a supply cap")
+
+	// There can only be a single group anchor.
+	err = VerifyGroupSupplyCap([]*Proof{anchorProof, anchorProof})
+	require.ErrorContains(t, err, "second group anchor")
+
+	// Proofs of a different group can't be mixed in.
+	err = VerifyGroupSupplyCap([]*Proof{
+		anchorProof, {Asset: *asset.RandAsset(t, asset.Normal)},
+	})
+	require.ErrorContains(t, err, "different asset group")
+
+	// Amounts that add up to more than fits into an uint64 must not wrap
+	// around to a total that is below the cap.
+	err = VerifyGroupSupplyCap([]*Proof{
+		anchorProof, {Asset: *newTranche(math.MaxUint64 - 500)},
+	})
+	require.ErrorContains(t, err, "overflow")
+
+	// The overflow is caught for uncapped groups as well.
+	err = VerifyGroupSupplyCap([]*Proof{
+		{Asset: *anchor, GroupKeyReveal: groupKeyReveal},
+		{Asset: *newTranche(math.MaxUint64)},
+	})
+	require.ErrorContains(t, err, "overflow")
+
+	// Without any declared cap, the group is uncapped.
+	err = VerifyGroupSupplyCap([]*Proof{
+		{Asset: *anchor, GroupKeyReveal: groupKeyReveal},
+		{Asset: *newTranche(1_000_000)},
+	})
+	require.NoError(t, err)
+}
+
+// TestMetaQuery tests that meta data is matched against meta queries
+// correctly.
+func TestMetaQuery(t *testing.T) {
+	t.Parallel()
+
+	jsonMeta := &MetaReveal{
+		Type: MetaOpaque,
+		Data: []byte(`{"name": "pixel", "edition": 7, "rare": true, ` +
+			`"artist": {"name": "satoshi", "tags": ["a", "b"]}}`),
+	}
+	opaqueMeta := &MetaReveal{
+		Type: MetaOpaque,
+		Data: []byte("name=pixel, a collectible"),
+	}
+
+	testCases := []struct {
+		name        string
+		query       string
+		meta        *MetaReveal
+		matches     bool
+		expectedErr string
+	}{{
+		name:    "json string value",
+		query:   "name=pixel",
+		meta:    jsonMeta,
+		matches: true,
+	}, {
+		name:  "json string mismatch",
+		query: "name=other",
+		meta:  jsonMeta,
+	}, {
+		name:    "json number and bool",
+		query:   "edition=7 && rare=true",
+		meta:    jsonMeta,
+		matches: true,
+	}, {
+		name:  "json one term mismatch",
+		query: "edition=7 && rare=false",
+		meta:  jsonMeta,
+	}, {
+		name:    "json nested path",
+		query:   "artist.name=satoshi",
+		meta:    jsonMeta,
+		matches: true,
+	}, {
+		name:    "json key presence",
+		query:   "artist.tags",
+		meta:    jsonMeta,
+		matches: true,
+	}, {
+		name:  "json array never equals value",
+		query: "artist.tags=a",
+		meta:  jsonMeta,
+	}, {
+		name:  "json missing key",
+		query: "artist.country",
+		meta:  jsonMeta,
+	}, {
+		name:    "opaque substring",
+		query:   "name=pixel",
+		meta:    opaqueMeta,
+		matches: true,
+	}, {
+		name:  "opaque no substring",
+		query: "name=other",
+		meta:  opaqueMeta,
+	}, {
+		name:  "nil meta",
+		query: "name",
+		meta:  nil,
+	}, {
+		name:        "empty query",
+		query:       " ",
+		expectedErr: "must not be empty",
+	}, {
+		name:        "missing key",
+		query:       "name=pixel && =7",
+		expectedErr: "missing key",
+	}, {
+		name:        "empty path key",
+		query:       "artist..name",
+		expectedErr: "empty key",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := ParseMetaQuery(tc.query)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.matches, query.Matches(tc.meta))
+		})
+	}
+}
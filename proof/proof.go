@@ -105,6 +105,15 @@ var (
 	// invalid.
 	ErrProofInvalid = errors.New("proof is invalid")
 
+	// ErrMissingChallengeWitness is returned if an ownership proof is
+	// verified that doesn't contain a challenge witness.
+	ErrMissingChallengeWitness = errors.New("missing challenge witness")
+
+	// ErrInvalidChallengeWitness is returned if the challenge witness of an
+	// ownership proof is invalid, for example because it was created for a
+	// different challenge.
+	ErrInvalidChallengeWitness = errors.New("invalid challenge witness")
+
 	// RegtestTestVectorName is the name of the test vector file that is
 	// generated/updated by an actual integration test run on regtest. It is
 	// exported here, so we can use it in the integration tests.
@@ -314,6 +323,18 @@ type Proof struct {
 	// the asset group. This field must be provided for issuance proofs of
 	// grouped assets.
 	GroupKeyReveal *asset.GroupKeyReveal
+
+	// IssuanceAuthority is an optional proof that the reissuance of an
+	// asset into an asset group was authorized by the latest key of the
+	// rotation chain of the group's issuance authority. This field must be
+	// provided for reissuance proofs of groups whose issuance authority
+	// was rotated.
+	IssuanceAuthority *IssuanceAuthority
+
+	// UnknownOddTypes are the unknown odd (non-critical) TLV records the
+	// proof was decoded with. They are ignored, but kept so the proof is
+	// encoded with them again.
+	UnknownOddTypes tlv.TypeMap
 }
 
 // OutPoint returns the outpoint that commits to the asset associated with this
@@ -327,7 +348,7 @@ func (p *Proof) OutPoint() wire.OutPoint {
 
 // EncodeRecords returns the set of known TLV records to encode a Proof.
 func (p *Proof) EncodeRecords() []tlv.Record {
-	records := make([]tlv.Record, 0, 15)
+	records := make([]tlv.Record, 0, 16)
 	records = append(records, VersionRecord(&p.Version))
 	records = append(records, PrevOutRecord(&p.PrevOut))
 	records = append(records, BlockHeaderRecord(&p.BlockHeader))
@@ -367,6 +388,11 @@ func (p *Proof) EncodeRecords() []tlv.Record {
 			&p.GroupKeyReveal,
 		))
 	}
+	if p.IssuanceAuthority != nil {
+		records = append(records, IssuanceAuthorityRecord(
+			&p.IssuanceAuthority,
+		))
+	}
 	return records
 }
 
@@ -388,6 +414,7 @@ func (p *Proof) DecodeRecords() []tlv.Record {
 		BlockHeightRecord(&p.BlockHeight),
 		GenesisRevealRecord(&p.GenesisReveal),
 		GroupKeyRevealRecord(&p.GroupKeyReveal),
+		IssuanceAuthorityRecord(&p.IssuanceAuthority),
 	}
 }
 
@@ -401,7 +428,12 @@ func (p *Proof) Encode(w io.Writer) error {
 		return errors.New("failed to write prefix magic bytes")
 	}
 
-	stream, err := tlv.NewStream(p.EncodeRecords()...)
+	records := append(
+		p.EncodeRecords(), unknownRecords(p.UnknownOddTypes)...,
+	)
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
 	if err != nil {
 		return err
 	}
@@ -433,7 +465,13 @@ func (p *Proof) Decode(r io.Reader) error {
 	// Note, we can't use the DecodeP2P method here, because the additional
 	// inputs records might be larger than 64k each. Instead, we add
 	// individual limits to each record.
-	return stream.Decode(r)
+	unknownOddTypes, err := decodeWithUnknownRecords(stream, r)
+	if err != nil {
+		return err
+	}
+	p.UnknownOddTypes = unknownOddTypes
+
+	return nil
 }
 
 // IsUnknownVersion returns true if a proof has a version that is not recognized
@@ -559,6 +559,34 @@ func TestGenesisProofVerification(t *testing.T) {
 				Data: []byte("shall be lifted nevermore"),
 			},
 		},
+		{
+			name:      "normal asset with a JSON meta reveal",
+			assetType: asset.Normal,
+			amount:    &amount,
+			metaReveal: &MetaReveal{
+				Type: MetaJson,
+				Data: []byte(`{"raven": "nevermore"}`),
+			},
+		},
+		{
+			name:      "normal asset with invalid JSON meta reveal",
+			assetType: asset.Normal,
+			amount:    &amount,
+			metaReveal: &MetaReveal{
+				Type: MetaJson,
+				Data: []byte("quoth the raven"),
+			},
+			expectedErr: ErrInvalidJSON,
+		},
+		{
+			name:      "collectible with an unknown meta type",
+			assetType: asset.Collectible,
+			metaReveal: &MetaReveal{
+				Type: 99,
+				Data: []byte("shall be lifted nevermore"),
+			},
+			expectedErr: ErrMetaTypeUnknown,
+		},
 		{
 			name:      "collectible invalid meta reveal",
 			assetType: asset.Collectible,
@@ -714,6 +742,174 @@ func TestProofFileVerification(t *testing.T) {
 	require.ErrorIs(t, err, ErrUnknownVersion)
 }
 
+// TestProofFileVerificationStrictness ensures that every verification
+// strictness level accepts a valid proof file, and that the stricter levels
+// catch invalid state transitions the lower levels skip.
+func TestProofFileVerificationStrictness(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	f := &File{}
+	err := f.Decode(bytes.NewReader(
+		readTestProofBlob(t, proofFileHexFileName),
+	))
+	require.NoError(t, err)
+	require.Equal(t, 3, f.NumProofs())
+
+	proofs := make([]*Proof, f.NumProofs())
+	for idx := range proofs {
+		proofs[idx], err = f.ProofAt(uint32(idx))
+		require.NoError(t, err)
+	}
+	require.NotEqual(
+		t, proofs[0].BlockHeader.BlockHash(),
+		proofs[2].BlockHeader.BlockHash(),
+	)
+
+	// knownProofs returns a universe verifier that knows the proofs at the
+	// given indexes of the file.
+	knownProofs := func(indexes ...int) UniverseVerifier {
+		var known [][]byte
+		for _, idx := range indexes {
+			var buf bytes.Buffer
+			require.NoError(t, proofs[idx].Encode(&buf))
+			known = append(known, buf.Bytes())
+		}
+
+		return func(_ context.Context, p *Proof) (bool, error) {
+			var buf bytes.Buffer
+			if err := p.Encode(&buf); err != nil {
+				return false, err
+			}
+
+			for _, knownProof := range known {
+				if bytes.Equal(knownProof, buf.Bytes()) {
+					return true, nil
+				}
+			}
+
+			return false, nil
+		}
+	}
+
+	// rejectHeaderOf returns a header verifier that rejects the block
+	// header of the proof at the given index of the file.
+	errHeaderVerifier := fmt.Errorf("invalid block header")
+	rejectHeaderOf := func(idx int) HeaderVerifier {
+		rejected := proofs[idx].BlockHeader.BlockHash()
+		return func(header wire.BlockHeader, _ uint32) error {
+			if header.BlockHash() == rejected {
+				return errHeaderVerifier
+			}
+
+			return nil
+		}
+	}
+
+	// All levels accept the valid proof file.
+	for _, strictness := range []VerificationStrictness{
+		StrictnessVerifyFull, StrictnessVerifyChain,
+		StrictnessTrustUniverse,
+	} {
+		_, err := f.VerifyWithStrictness(
+			ctx, MockHeaderVerifier, MockGroupVerifier, strictness,
+			knownProofs(1),
+		)
+		require.NoError(t, err, strictness.String())
+	}
+
+	// A historic state transition that isn't anchored in the chain is
+	// caught by all levels that check the chain, but is accepted if it is
+	// already part of a trusted universe.
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(0), MockGroupVerifier, StrictnessVerifyFull,
+		knownProofs(1),
+	)
+	require.ErrorIs(t, err, errHeaderVerifier)
+
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(0), MockGroupVerifier,
+		StrictnessVerifyChain, knownProofs(1),
+	)
+	require.ErrorIs(t, err, errHeaderVerifier)
+
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(0), MockGroupVerifier,
+		StrictnessTrustUniverse, knownProofs(1),
+	)
+	require.NoError(t, err)
+
+	// Without a universe that knows the transition, or without a universe
+	// verifier at all, nothing is trusted.
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(0), MockGroupVerifier,
+		StrictnessTrustUniverse, knownProofs(),
+	)
+	require.ErrorIs(t, err, errHeaderVerifier)
+
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(0), MockGroupVerifier,
+		StrictnessTrustUniverse, nil,
+	)
+	require.ErrorIs(t, err, errHeaderVerifier)
+
+	// State transitions after the latest trusted one are still checked.
+	_, err = f.VerifyWithStrictness(
+		ctx, rejectHeaderOf(2), MockGroupVerifier,
+		StrictnessTrustUniverse, knownProofs(1),
+	)
+	require.ErrorIs(t, err, errHeaderVerifier)
+
+	// The asset witnesses of a state transition are only validated by the
+	// full verification. We simulate an invalid witness by presenting a
+	// previous asset the witness of the transition doesn't sign for.
+	genesisSnapshot, err := proofs[0].Verify(
+		ctx, nil, MockHeaderVerifier, MockGroupVerifier,
+	)
+	require.NoError(t, err)
+
+	wrongPrevAsset := genesisSnapshot.Asset.Copy()
+	wrongPrevAsset.ScriptKey = asset.NewScriptKey(test.RandPubKey(t))
+	wrongPrev := &AssetSnapshot{
+		Asset:    wrongPrevAsset,
+		OutPoint: genesisSnapshot.OutPoint,
+	}
+
+	_, err = proofs[1].verify(
+		ctx, wrongPrev, MockHeaderVerifier, MockGroupVerifier, nil,
+		checkChain,
+	)
+	require.NoError(t, err)
+
+	_, err = proofs[1].verify(
+		ctx, wrongPrev, MockHeaderVerifier, MockGroupVerifier, nil,
+		checkFull,
+	)
+	require.Error(t, err)
+}
+
+// TestParseVerificationStrictness tests that verification strictness levels
+// are parsed correctly.
+func TestParseVerificationStrictness(t *testing.T) {
+	t.Parallel()
+
+	for _, strictness := range []VerificationStrictness{
+		StrictnessVerifyFull, StrictnessVerifyChain,
+		StrictnessTrustUniverse,
+	} {
+		parsed, err := ParseVerificationStrictness(strictness.String())
+		require.NoError(t, err)
+		require.Equal(t, strictness, parsed)
+	}
+
+	parsed, err := ParseVerificationStrictness("")
+	require.NoError(t, err)
+	require.Equal(t, StrictnessVerifyFull, parsed)
+
+	_, err = ParseVerificationStrictness("trust_everything")
+	require.Error(t, err)
+}
+
 // TestProofVerification ensures that the proof encoding and decoding works as
 // expected.
 func TestProofVerification(t *testing.T) {
@@ -761,6 +957,41 @@ func TestProofVerification(t *testing.T) {
 	require.ErrorIs(t, err, ErrUnknownVersion)
 }
 
+// TestRevealedGroupVerifier tests that the group keys revealed by the group
+// anchor proofs of a file are accepted without consulting the fallback
+// verifier.
+func TestRevealedGroupVerifier(t *testing.T) {
+	t.Parallel()
+
+	amt := uint64(5000)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil, asset.V0,
+	)
+	require.NotNil(t, genesisProof.GroupKeyReveal)
+	transitionProof := genTransitionProof(t, &genesisProof)
+	groupKey := genesisProof.Asset.GroupKey.GroupPubKey
+
+	errUnknown := fmt.Errorf("unknown group")
+	unknownGroup := func(*btcec.PublicKey) error {
+		return errUnknown
+	}
+
+	// The group anchor proof reveals the group, so it's accepted even
+	// though the fallback doesn't know it.
+	verifier := RevealedGroupVerifier(
+		unknownGroup, &genesisProof, &transitionProof,
+	)
+	require.NoError(t, verifier(&groupKey))
+
+	// Any other group key is checked by the fallback.
+	require.ErrorIs(t, verifier(test.RandPubKey(t)), errUnknown)
+	require.ErrorIs(t, verifier(nil), errUnknown)
+
+	// A transfer proof alone doesn't reveal its group.
+	verifier = RevealedGroupVerifier(unknownGroup, &transitionProof)
+	require.ErrorIs(t, verifier(&groupKey), errUnknown)
+}
+
 // TestOwnershipProofVerification ensures that the ownership proof encoding and
 // decoding as well as the verification works as expected.
 func TestOwnershipProofVerification(t *testing.T) {
@@ -782,6 +1013,79 @@ func TestOwnershipProofVerification(t *testing.T) {
 	)
 	require.NoError(t, err)
 	require.NotNil(t, snapshot)
+
+	// The proof wasn't created for a challenge, so it must only be valid
+	// as an ownership proof without one.
+	snapshot, err = p.VerifyOwnership(
+		context.Background(), nil, MockHeaderVerifier,
+		MockGroupVerifier,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+
+	challenge := [32]byte(test.RandHash())
+	_, err = p.VerifyOwnership(
+		context.Background(), &challenge, MockHeaderVerifier,
+		MockGroupVerifier,
+	)
+	require.ErrorIs(t, err, ErrInvalidChallengeWitness)
+
+	// A proof without a challenge witness is no ownership proof.
+	p.ChallengeWitness = nil
+	_, err = p.VerifyOwnership(
+		context.Background(), nil, MockHeaderVerifier,
+		MockGroupVerifier,
+	)
+	require.ErrorIs(t, err, ErrMissingChallengeWitness)
+}
+
+// TestProofUnknownRecords tests that unknown odd records of a proof are kept
+// and encoded again, while unknown even records are rejected unless lenient
+// decoding is enabled.
+//
+// NOTE: This test must not run in parallel, as it changes the global decoding
+// mode.
+func TestProofUnknownRecords(t *testing.T) {
+	proofBlob := readTestProofBlob(t, proofHexFileName)
+	withRecord := func(recordType, value byte) []byte {
+		blob := bytes.Clone(proofBlob)
+		return append(blob, recordType, 1, value)
+	}
+
+	// An unknown odd record is ignored, but encoded again.
+	oddBlob := withRecord(101, 7)
+	var oddProof Proof
+	require.NoError(t, oddProof.Decode(bytes.NewReader(oddBlob)))
+	require.Len(t, oddProof.UnknownOddTypes, 1)
+	require.Equal(t, []byte{7}, oddProof.UnknownOddTypes[101])
+
+	var buf bytes.Buffer
+	require.NoError(t, oddProof.Encode(&buf))
+	require.Equal(t, oddBlob, buf.Bytes())
+
+	// A proof without unknown records doesn't carry an empty map around.
+	var knownProof Proof
+	require.NoError(t, knownProof.Decode(bytes.NewReader(proofBlob)))
+	require.Nil(t, knownProof.UnknownOddTypes)
+
+	// An unknown even record is critical and results in an error.
+	evenBlob := withRecord(100, 7)
+	var evenProof Proof
+	err := evenProof.Decode(bytes.NewReader(evenBlob))
+	require.ErrorIs(t, err, ErrUnknownCriticalRecord)
+	require.ErrorContains(t, err, "100")
+
+	// In lenient mode, the unknown even record is dropped instead.
+	SetLenientDecoding(true)
+	defer SetLenientDecoding(false)
+
+	evenProof = Proof{}
+	require.NoError(t, evenProof.Decode(bytes.NewReader(evenBlob)))
+	require.Nil(t, evenProof.UnknownOddTypes)
+
+	buf.Reset()
+	require.NoError(t, evenProof.Encode(&buf))
+	require.Equal(t, []byte(proofBlob), buf.Bytes())
 }
 
 // TestProofReplacement ensures that proofs can be replaced in a proof file.
@@ -865,6 +1169,158 @@ func TestProofReplacement(t *testing.T) {
 	require.Equal(t, f2.proofs, f.proofs)
 }
 
+// TestAppendRawProof tests that a proof file can be reassembled from the raw
+// proofs of another file, which is how streamed proof files are put back
+// together.
+func TestAppendRawProof(t *testing.T) {
+	t.Parallel()
+
+	const numProofs = 5
+	proofs := make([]Proof, numProofs)
+	for i := 0; i < numProofs; i++ {
+		amt := uint64(i + 1)
+		proofs[i], _ = genRandomGenesisWithProof(
+			t, asset.Normal, &amt, nil, false, nil, nil, asset.V0,
+		)
+	}
+
+	f, err := NewFile(V0, proofs...)
+	require.NoError(t, err)
+
+	reassembled := NewEmptyFile(f.Version)
+	for i := 0; i < f.NumProofs(); i++ {
+		rawProof, err := f.RawProofAt(uint32(i))
+		require.NoError(t, err)
+
+		require.NoError(t, reassembled.AppendRawProof(rawProof))
+	}
+
+	// The reassembled file must be identical to the original one,
+	// including the chained hashes.
+	var origBuf, reassembledBuf bytes.Buffer
+	require.NoError(t, f.Encode(&origBuf))
+	require.NoError(t, reassembled.Encode(&reassembledBuf))
+	require.Equal(t, origBuf.Bytes(), reassembledBuf.Bytes())
+
+	// A range of proofs results in a shorter but still valid file.
+	suffix := NewEmptyFile(f.Version)
+	for i := 2; i < f.NumProofs(); i++ {
+		rawProof, err := f.RawProofAt(uint32(i))
+		require.NoError(t, err)
+
+		require.NoError(t, suffix.AppendRawProof(rawProof))
+	}
+	require.Equal(t, numProofs-2, suffix.NumProofs())
+
+	lastProof, err := suffix.LastProof()
+	require.NoError(t, err)
+	require.Equal(t, uint64(numProofs), lastProof.Asset.Amount)
+
+	// Bytes that aren't a proof are rejected.
+	err = suffix.AppendRawProof([]byte{0x01, 0x02, 0x03})
+	require.Error(t, err)
+	require.Equal(t, numProofs-2, suffix.NumProofs())
+}
+
+// genTransitionProof creates a proof that spends the asset output of the given
+// previous proof.
+func genTransitionProof(t testing.TB, prev *Proof) Proof {
+	next := *prev
+	next.PrevOut = prev.OutPoint()
+	next.AnchorTx = wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{{
+			PreviousOutPoint: next.PrevOut,
+		}},
+		TxOut: []*wire.TxOut{{
+			PkScript: prev.AnchorTx.TxOut[0].PkScript,
+			Value:    330,
+		}},
+	}
+	next.GenesisReveal = nil
+	next.GroupKeyReveal = nil
+	next.MetaReveal = nil
+
+	newAsset := prev.Asset.Copy()
+	newAsset.ScriptKey = asset.RandScriptKey(t)
+	newAsset.PrevWitnesses = []asset.Witness{{
+		PrevID: &asset.PrevID{
+			OutPoint: next.PrevOut,
+			ID:       prev.Asset.Genesis.ID(),
+			ScriptKey: asset.ToSerialized(
+				prev.Asset.ScriptKey.PubKey,
+			),
+		},
+		TxWitness: wire.TxWitness{[]byte("foo")},
+	}}
+	next.Asset = *newAsset
+
+	return next
+}
+
+// TestMergeFiles ensures that two contiguous proof files can be merged and
+// that non-contiguous files are rejected.
+func TestMergeFiles(t *testing.T) {
+	t.Parallel()
+
+	amt := uint64(5000)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil, asset.V0,
+	)
+
+	transfer1 := genTransitionProof(t, &genesisProof)
+	transfer2 := genTransitionProof(t, &transfer1)
+	transfer3 := genTransitionProof(t, &transfer2)
+
+	prefix, err := NewFile(V0, genesisProof, transfer1)
+	require.NoError(t, err)
+	suffix, err := NewFile(V0, transfer2, transfer3)
+	require.NoError(t, err)
+
+	// The merged file should be identical to a file created from all the
+	// proofs in one go, including the chained hashes.
+	merged, err := MergeFiles(prefix, suffix)
+	require.NoError(t, err)
+
+	expected, err := NewFile(
+		V0, genesisProof, transfer1, transfer2, transfer3,
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected, merged)
+
+	// The input files must not have been modified.
+	require.Equal(t, 2, prefix.NumProofs())
+	require.Equal(t, 2, suffix.NumProofs())
+
+	// A gap in the chain must be rejected.
+	gapSuffix, err := NewFile(V0, transfer3)
+	require.NoError(t, err)
+	_, err = MergeFiles(prefix, gapSuffix)
+	require.ErrorIs(t, err, ErrProofFileInvalid)
+
+	// So must a suffix that starts with a genesis proof.
+	genesisFile, err := NewFile(V0, genesisProof)
+	require.NoError(t, err)
+	_, err = MergeFiles(prefix, genesisFile)
+	require.ErrorIs(t, err, ErrProofFileInvalid)
+
+	// A suffix that spends the correct outpoint but a different asset is
+	// rejected as well.
+	mismatched := transfer2
+	mismatched.Asset = *transfer2.Asset.Copy()
+	mismatched.Asset.PrevWitnesses[0].PrevID.ScriptKey = asset.ToSerialized(
+		test.RandPubKey(t),
+	)
+	mismatchedFile, err := NewFile(V0, mismatched)
+	require.NoError(t, err)
+	_, err = MergeFiles(prefix, mismatchedFile)
+	require.ErrorIs(t, err, ErrProofFileInvalid)
+
+	// Empty files can't be merged.
+	_, err = MergeFiles(prefix, NewEmptyFile(V0))
+	require.ErrorIs(t, err, ErrNoProofAvailable)
+}
+
 func BenchmarkProofEncoding(b *testing.B) {
 	amt := uint64(5000)
 
@@ -903,6 +1359,157 @@ func BenchmarkProofEncoding(b *testing.B) {
 	}
 }
 
+// genTransitionChain creates a proof file with a genesis proof followed by the
+// given number of transfers of the asset.
+func genTransitionChain(t testing.TB, numTransfers int) *File {
+	amt := uint64(5000)
+	genesisProof, _ := genRandomGenesisWithProof(
+		t, asset.Normal, &amt, nil, true, nil, nil, asset.V0,
+	)
+
+	proofs := []Proof{genesisProof}
+	for i := 0; i < numTransfers; i++ {
+		proofs = append(proofs, genTransitionProof(t, &proofs[i]))
+	}
+
+	f, err := NewFile(V0, proofs...)
+	require.NoError(t, err)
+
+	return f
+}
+
+// TestFileCompression tests that compressed proof files are smaller than
+// uncompressed ones, and that both can be decoded.
+func TestFileCompression(t *testing.T) {
+	t.Parallel()
+
+	f := genTransitionChain(t, 20)
+
+	var uncompressed bytes.Buffer
+	require.NoError(t, f.Encode(&uncompressed))
+
+	f.Compressed = true
+	var compressed bytes.Buffer
+	require.NoError(t, f.Encode(&compressed))
+
+	require.True(t, IsProofFile(compressed.Bytes()))
+	require.Less(t, compressed.Len(), uncompressed.Len())
+
+	// Both files must decode into the same proofs, with the compression
+	// flag reflecting the encoding.
+	var decodedCompressed, decodedUncompressed File
+	require.NoError(t, decodedCompressed.Decode(&compressed))
+	require.NoError(t, decodedUncompressed.Decode(&uncompressed))
+
+	require.True(t, decodedCompressed.Compressed)
+	require.False(t, decodedUncompressed.Compressed)
+	require.Equal(t, V0, decodedCompressed.Version)
+	require.Equal(t, f.proofs, decodedCompressed.proofs)
+	require.Equal(t, f.proofs, decodedUncompressed.proofs)
+
+	// An existing uncompressed file still decodes and verifies, also after
+	// a round trip through the compressed encoding.
+	var oldFile File
+	err := oldFile.Decode(bytes.NewReader(
+		readTestProofBlob(t, proofFileHexFileName),
+	))
+	require.NoError(t, err)
+	require.False(t, oldFile.Compressed)
+
+	oldFile.Compressed = true
+	var buf bytes.Buffer
+	require.NoError(t, oldFile.Encode(&buf))
+
+	var roundTripped File
+	require.NoError(t, roundTripped.Decode(&buf))
+	require.True(t, roundTripped.Compressed)
+
+	_, err = roundTripped.Verify(
+		context.Background(), MockHeaderVerifier, MockGroupVerifier,
+	)
+	require.NoError(t, err)
+
+	// A corrupted compressed stream must be rejected.
+	f.Compressed = true
+	var corrupted bytes.Buffer
+	require.NoError(t, f.Encode(&corrupted))
+	corruptedBytes := corrupted.Bytes()
+	corruptedBytes[len(corruptedBytes)/2] ^= 0xff
+
+	var corruptedFile File
+	require.Error(t, corruptedFile.Decode(bytes.NewReader(corruptedBytes)))
+}
+
+// TestSetFileCompression tests that proof files can be converted between the
+// compressed and uncompressed encoding without changing their proofs.
+func TestSetFileCompression(t *testing.T) {
+	t.Parallel()
+
+	f := genTransitionChain(t, 5)
+	var uncompressed bytes.Buffer
+	require.NoError(t, f.Encode(&uncompressed))
+	require.False(t, IsCompressedFile(uncompressed.Bytes()))
+
+	compressed, err := SetFileCompression(uncompressed.Bytes(), true)
+	require.NoError(t, err)
+	require.True(t, IsCompressedFile(compressed))
+
+	// A file that already has the requested encoding is returned as is.
+	same, err := SetFileCompression(compressed, true)
+	require.NoError(t, err)
+	require.Equal(t, compressed, same)
+
+	// Converting back yields the exact original file.
+	roundTripped, err := SetFileCompression(compressed, false)
+	require.NoError(t, err)
+	require.Equal(t, Blob(uncompressed.Bytes()), roundTripped)
+
+	// Blobs that aren't proof files are never touched.
+	singleProof := bytes.Repeat([]byte{0x01}, 100)
+	require.False(t, IsCompressedFile(singleProof))
+	unchanged, err := SetFileCompression(singleProof, true)
+	require.NoError(t, err)
+	require.Equal(t, Blob(singleProof), unchanged)
+}
+
+// BenchmarkProofFileCompression measures the encoding and decoding of a
+// compressed multi-hop proof file and reports its size compared to the
+// uncompressed file.
+func BenchmarkProofFileCompression(b *testing.B) {
+	const numTransfers = 100
+	f := genTransitionChain(b, numTransfers)
+
+	var uncompressed bytes.Buffer
+	require.NoError(b, f.Encode(&uncompressed))
+
+	f.Compressed = true
+	var compressed bytes.Buffer
+	require.NoError(b, f.Encode(&compressed))
+
+	b.ReportMetric(float64(uncompressed.Len()), "uncompressed-bytes")
+	b.ReportMetric(float64(compressed.Len()), "compressed-bytes")
+	b.ReportMetric(
+		float64(compressed.Len())/float64(uncompressed.Len()),
+		"size-ratio",
+	)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	// Only this part is measured.
+	for i := 0; i < b.N; i++ {
+		var buf bytes.Buffer
+		err := f.Encode(&buf)
+		require.NoError(b, err)
+
+		var f2 File
+		err = f2.Decode(&buf)
+		require.NoError(b, err)
+
+		require.Len(b, f2.proofs, numTransfers+1)
+	}
+}
+
 // TestBIPTestVectors tests that the BIP test vectors are passing.
 func TestBIPTestVectors(t *testing.T) {
 	t.Parallel()
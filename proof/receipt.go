@@ -0,0 +1,147 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+var (
+	// receiptMsgPrefix is the prefix of the message that is signed by the
+	// receiver of a proof to create a delivery receipt. The prefix makes
+	// sure a receipt signature can't be mistaken for a signature over any
+	// other message.
+	receiptMsgPrefix = []byte("taproot-assets proof delivery receipt")
+
+	// ErrInvalidReceipt is returned if a delivery receipt doesn't match the
+	// script key it is supposed to be signed by.
+	ErrInvalidReceipt = errors.New("invalid delivery receipt")
+)
+
+// DeliveryReceipt is a receipt that is signed by the receiver of a proof after
+// receiving it through a proof courier. The receipt is signed with the key
+// that controls the script key of the receiving address. That allows the
+// sender to prove that the receiver got the exact proof that was sent.
+type DeliveryReceipt struct {
+	// ProofHash is the SHA256 hash of the raw proof file that was received.
+	ProofHash [sha256.Size]byte
+
+	// RawScriptKey is the internal key of the script key of the receiving
+	// address. The signature is created with this key.
+	RawScriptKey *btcec.PublicKey
+
+	// TapTweak is the optional Taproot tweak that is applied to the raw
+	// script key to arrive at the script key. If this is empty then a
+	// BIP-86 style tweak is applied.
+	TapTweak []byte
+
+	// Signature is the Schnorr signature over the receipt message, created
+	// with the raw script key.
+	Signature *schnorr.Signature
+}
+
+// ReceiptMessage returns the message that is signed to create a delivery
+// receipt for the proof with the given hash.
+func ReceiptMessage(proofHash [sha256.Size]byte) []byte {
+	msg := make([]byte, 0, len(receiptMsgPrefix)+sha256.Size)
+	msg = append(msg, receiptMsgPrefix...)
+	return append(msg, proofHash[:]...)
+}
+
+// Verify makes sure the receipt was signed by the key that controls the given
+// script key and that it commits to the given proof hash.
+func (r *DeliveryReceipt) Verify(scriptKey *btcec.PublicKey,
+	proofHash [sha256.Size]byte) error {
+
+	if r.ProofHash != proofHash {
+		return fmt.Errorf("%w: proof hash mismatch", ErrInvalidReceipt)
+	}
+
+	// The script key is either a BIP-86 key or a key with the tap tweak
+	// applied to it, which ComputeTaprootOutputKey handles for us.
+	tweakedKey := txscript.ComputeTaprootOutputKey(
+		r.RawScriptKey, r.TapTweak,
+	)
+	if !bytes.Equal(
+		schnorr.SerializePubKey(tweakedKey),
+		schnorr.SerializePubKey(scriptKey),
+	) {
+
+		return fmt.Errorf("%w: script key mismatch", ErrInvalidReceipt)
+	}
+
+	// The message is hashed with a single round of SHA256 before signing,
+	// in the same way lnd's message signer does it.
+	digest := sha256.Sum256(ReceiptMessage(r.ProofHash))
+	if !r.Signature.Verify(digest[:], r.RawScriptKey) {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidReceipt)
+	}
+
+	return nil
+}
+
+// Encode serializes the receipt. The encoding is the proof hash, followed by
+// the compressed raw script key, the signature and the (possibly empty) tap
+// tweak.
+func (r *DeliveryReceipt) Encode() []byte {
+	var b bytes.Buffer
+	b.Write(r.ProofHash[:])
+	b.Write(r.RawScriptKey.SerializeCompressed())
+	b.Write(r.Signature.Serialize())
+	b.Write(r.TapTweak)
+
+	return b.Bytes()
+}
+
+// DecodeDeliveryReceipt parses a receipt that was serialized with Encode.
+func DecodeDeliveryReceipt(raw []byte) (*DeliveryReceipt, error) {
+	const minLen = sha256.Size + btcec.PubKeyBytesLenCompressed +
+		schnorr.SignatureSize
+
+	if len(raw) < minLen {
+		return nil, fmt.Errorf("delivery receipt too short: %d bytes",
+			len(raw))
+	}
+
+	var r DeliveryReceipt
+	copy(r.ProofHash[:], raw[:sha256.Size])
+	raw = raw[sha256.Size:]
+
+	rawKey, err := btcec.ParsePubKey(
+		raw[:btcec.PubKeyBytesLenCompressed],
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse raw script key: %w",
+			err)
+	}
+	r.RawScriptKey = rawKey
+	raw = raw[btcec.PubKeyBytesLenCompressed:]
+
+	r.Signature, err = schnorr.ParseSignature(raw[:schnorr.SignatureSize])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse signature: %w", err)
+	}
+	raw = raw[schnorr.SignatureSize:]
+
+	if len(raw) > 0 {
+		r.TapTweak = append([]byte(nil), raw...)
+	}
+
+	return &r, nil
+}
+
+// ReceiptSigner is used by the receiver of a proof to sign a delivery receipt
+// for it.
+type ReceiptSigner interface {
+	// SignDeliveryReceipt creates a delivery receipt for the proof with
+	// the given hash, signed by the key that controls the given script
+	// key.
+	SignDeliveryReceipt(ctx context.Context, scriptKey *btcec.PublicKey,
+		proofHash [sha256.Size]byte) (*DeliveryReceipt, error)
+}
@@ -0,0 +1,91 @@
+package proof
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// signTestReceipt creates a delivery receipt for the given proof hash, signed
+// by the given private key, in the same way lnd's message signer does it.
+func signTestReceipt(t *testing.T, privKey *btcec.PrivateKey, tapTweak []byte,
+	proofHash [sha256.Size]byte) *DeliveryReceipt {
+
+	digest := sha256.Sum256(ReceiptMessage(proofHash))
+	sig, err := schnorr.Sign(privKey, digest[:])
+	require.NoError(t, err)
+
+	return &DeliveryReceipt{
+		ProofHash:    proofHash,
+		RawScriptKey: privKey.PubKey(),
+		TapTweak:     tapTweak,
+		Signature:    sig,
+	}
+}
+
+// TestDeliveryReceipt tests that delivery receipts can be encoded, decoded
+// and verified.
+func TestDeliveryReceipt(t *testing.T) {
+	t.Parallel()
+
+	privKey := test.RandPrivKey(t)
+	proofHash := sha256.Sum256(test.RandBytes(100))
+
+	testCases := []struct {
+		name     string
+		tapTweak []byte
+	}{{
+		name: "bip86 script key",
+	}, {
+		name:     "tweaked script key",
+		tapTweak: test.RandBytes(32),
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			scriptKey := txscript.ComputeTaprootOutputKey(
+				privKey.PubKey(), tc.tapTweak,
+			)
+			receipt := signTestReceipt(
+				t, privKey, tc.tapTweak, proofHash,
+			)
+			require.NoError(t, receipt.Verify(scriptKey, proofHash))
+
+			// The receipt must survive an encoding round trip.
+			decoded, err := DecodeDeliveryReceipt(receipt.Encode())
+			require.NoError(t, err)
+			require.Equal(t, receipt, decoded)
+			require.NoError(t, decoded.Verify(scriptKey, proofHash))
+
+			// A receipt for a different proof must be rejected.
+			otherHash := sha256.Sum256(test.RandBytes(100))
+			err = receipt.Verify(scriptKey, otherHash)
+			require.ErrorIs(t, err, ErrInvalidReceipt)
+
+			// The same goes for a different script key.
+			err = receipt.Verify(test.RandPubKey(t), proofHash)
+			require.ErrorIs(t, err, ErrInvalidReceipt)
+
+			// And for a receipt signed by a different key.
+			forged := signTestReceipt(
+				t, test.RandPrivKey(t), tc.tapTweak, proofHash,
+			)
+			forged.RawScriptKey = privKey.PubKey()
+			err = forged.Verify(scriptKey, proofHash)
+			require.ErrorIs(t, err, ErrInvalidReceipt)
+		})
+	}
+
+	// A truncated receipt can't be decoded.
+	receipt := signTestReceipt(t, privKey, nil, proofHash)
+	_, err := DecodeDeliveryReceipt(receipt.Encode()[:100])
+	require.Error(t, err)
+}
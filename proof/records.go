@@ -11,21 +11,22 @@ import (
 )
 
 const (
-	VersionType          tlv.Type = 0
-	PrevOutType          tlv.Type = 2
-	BlockHeaderType      tlv.Type = 4
-	AnchorTxType         tlv.Type = 6
-	TxMerkleProofType    tlv.Type = 8
-	AssetLeafType        tlv.Type = 10
-	InclusionProofType   tlv.Type = 12
-	ExclusionProofsType  tlv.Type = 13
-	SplitRootProofType   tlv.Type = 15
-	MetaRevealType       tlv.Type = 17
-	AdditionalInputsType tlv.Type = 19
-	ChallengeWitnessType tlv.Type = 21
-	BlockHeightType      tlv.Type = 22
-	GenesisRevealType    tlv.Type = 23
-	GroupKeyRevealType   tlv.Type = 25
+	VersionType           tlv.Type = 0
+	PrevOutType           tlv.Type = 2
+	BlockHeaderType       tlv.Type = 4
+	AnchorTxType          tlv.Type = 6
+	TxMerkleProofType     tlv.Type = 8
+	AssetLeafType         tlv.Type = 10
+	InclusionProofType    tlv.Type = 12
+	ExclusionProofsType   tlv.Type = 13
+	SplitRootProofType    tlv.Type = 15
+	MetaRevealType        tlv.Type = 17
+	AdditionalInputsType  tlv.Type = 19
+	ChallengeWitnessType  tlv.Type = 21
+	BlockHeightType       tlv.Type = 22
+	GenesisRevealType     tlv.Type = 23
+	GroupKeyRevealType    tlv.Type = 25
+	IssuanceAuthorityType tlv.Type = 27
 
 	TaprootProofOutputIndexType     tlv.Type = 0
 	TaprootProofInternalKeyType     tlv.Type = 2
@@ -43,6 +44,11 @@ const (
 
 	MetaRevealEncodingType tlv.Type = 0
 	MetaRevealDataType     tlv.Type = 2
+
+	// MetaRevealSupplyCapType is the type of the optional supply cap
+	// record. It's odd, so decoders that don't know about supply caps
+	// skip it instead of failing to decode the meta reveal.
+	MetaRevealSupplyCapType tlv.Type = 5
 )
 
 func VersionRecord(version *TransitionVersion) tlv.Record {
@@ -305,6 +311,10 @@ func MetaRevealDataRecord(data *[]byte) tlv.Record {
 	)
 }
 
+func MetaRevealSupplyCapRecord(supplyCap *uint64) tlv.Record {
+	return tlv.MakePrimitiveRecord(MetaRevealSupplyCapType, supplyCap)
+}
+
 func GenesisRevealRecord(genesis **asset.Genesis) tlv.Record {
 	recordSize := func() uint64 {
 		var (
@@ -337,3 +347,18 @@ func GroupKeyRevealRecord(reveal **asset.GroupKeyReveal) tlv.Record {
 		GroupKeyRevealDecoder,
 	)
 }
+
+func IssuanceAuthorityRecord(authority **IssuanceAuthority) tlv.Record {
+	sizeFunc := func() uint64 {
+		var buf bytes.Buffer
+		err := IssuanceAuthorityEncoder(&buf, authority, &[8]byte{})
+		if err != nil {
+			panic(err)
+		}
+		return uint64(len(buf.Bytes()))
+	}
+	return tlv.MakeDynamicRecord(
+		IssuanceAuthorityType, authority, sizeFunc,
+		IssuanceAuthorityEncoder, IssuanceAuthorityDecoder,
+	)
+}
@@ -0,0 +1,82 @@
+package proof
+
+import "fmt"
+
+// VerificationStrictness describes how rigorously the state transitions of a
+// proof file are verified when the file is imported.
+type VerificationStrictness uint8
+
+const (
+	// StrictnessVerifyFull verifies every state transition of a proof file
+	// completely. The anchor of each transition is checked against the
+	// chain and the asset witnesses of each transition are validated.
+	StrictnessVerifyFull VerificationStrictness = iota
+
+	// StrictnessVerifyChain checks the anchor of every state transition
+	// against the chain, including the inclusion and exclusion proofs of
+	// each transition. The asset witnesses are only validated for the final
+	// state transition of a file.
+	StrictnessVerifyChain
+
+	// StrictnessTrustUniverse accepts all state transitions of a proof file
+	// up to and including the latest one that is already known to a
+	// trusted universe without checking their anchors against the chain or
+	// validating their asset witnesses. The remaining state transitions are
+	// verified as with StrictnessVerifyChain.
+	StrictnessTrustUniverse
+)
+
+// String returns a human-readable string for the verification strictness.
+func (s VerificationStrictness) String() string {
+	switch s {
+	case StrictnessVerifyFull:
+		return "verifyfull"
+
+	case StrictnessVerifyChain:
+		return "verifychain"
+
+	case StrictnessTrustUniverse:
+		return "trustuniverse"
+
+	default:
+		return fmt.Sprintf("<unknown_strictness(%d)>", s)
+	}
+}
+
+// ParseVerificationStrictness parses a verification strictness string.
+func ParseVerificationStrictness(
+	strictness string) (VerificationStrictness, error) {
+
+	switch strictness {
+	case "", StrictnessVerifyFull.String():
+		return StrictnessVerifyFull, nil
+
+	case StrictnessVerifyChain.String():
+		return StrictnessVerifyChain, nil
+
+	case StrictnessTrustUniverse.String():
+		return StrictnessTrustUniverse, nil
+
+	default:
+		return 0, fmt.Errorf("unknown verification strictness: %v",
+			strictness)
+	}
+}
+
+// proofCheck describes which checks are applied to a single state transition
+// of a proof file.
+type proofCheck uint8
+
+const (
+	// checkFull applies all checks to the state transition.
+	checkFull proofCheck = iota
+
+	// checkChain applies all checks except the validation of the asset
+	// witnesses.
+	checkChain
+
+	// checkTrusted skips both the block header lookup and the validation
+	// of the asset witnesses, as the state transition is already known to
+	// a trusted universe. The remaining checks only need local data.
+	checkTrusted
+)
@@ -116,6 +116,21 @@ func (p TxMerkleProof) Verify(tx *wire.MsgTx, merkleRoot chainhash.Hash) bool {
 	return current == merkleRoot
 }
 
+// Index returns the position of the proven transaction within its block, as
+// encoded in the direction bits of the proof.
+func (p TxMerkleProof) Index() uint32 {
+	var idx uint32
+	for i, left := range p.Bits {
+		// A set bit means the transaction's branch is the left child
+		// at this level, which doesn't contribute to its position.
+		if !left {
+			idx |= 1 << i
+		}
+	}
+
+	return idx
+}
+
 // Encode encodes a TxMerkleProof into `w`.
 func (p TxMerkleProof) Encode(w io.Writer) error {
 	numNodes := uint64(len(p.Nodes))
@@ -0,0 +1,89 @@
+package proof
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// ErrUnknownCriticalRecord is returned when a proof contains an unknown even
+// TLV record. Following the "it's OK to be odd" rule, even records are critical
+// and must be understood by the decoder, while unknown odd records can safely
+// be ignored.
+var ErrUnknownCriticalRecord = errors.New("proof contains unknown critical " +
+	"record")
+
+// lenientDecoding is set if proofs with unknown critical records should still
+// be decoded. This is only meant for debugging.
+var lenientDecoding atomic.Bool
+
+// SetLenientDecoding sets whether proofs that contain unknown critical (even)
+// TLV records are decoded anyway, with those records being dropped. This is
+// only meant for debugging, as it means proofs that might be invalid under
+// rules this implementation doesn't know about are accepted.
+func SetLenientDecoding(lenient bool) {
+	lenientDecoding.Store(lenient)
+}
+
+// decodeWithUnknownRecords decodes the given TLV stream and returns the
+// records that weren't known to the stream. An unknown even record results in
+// ErrUnknownCriticalRecord, unless lenient decoding is enabled. Only the
+// unknown odd records are returned, so they can be encoded again. If there are
+// none, nil is returned.
+func decodeWithUnknownRecords(stream *tlv.Stream,
+	r io.Reader) (tlv.TypeMap, error) {
+
+	parsedTypes, err := stream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The decoder only keeps the value of records it doesn't know.
+	var (
+		unknownEven []tlv.Type
+		unknownOdd  tlv.TypeMap
+	)
+	for typ, value := range parsedTypes {
+		if value == nil {
+			continue
+		}
+
+		if typ%2 == 0 {
+			unknownEven = append(unknownEven, typ)
+			continue
+		}
+
+		if unknownOdd == nil {
+			unknownOdd = make(tlv.TypeMap)
+		}
+		unknownOdd[typ] = value
+	}
+
+	if len(unknownEven) > 0 {
+		if !lenientDecoding.Load() {
+			return nil, fmt.Errorf("%w: %s",
+				ErrUnknownCriticalRecord,
+				formatTypes(unknownEven))
+		}
+
+		log.Warnf("Ignoring unknown critical proof records %s",
+			formatTypes(unknownEven))
+	}
+
+	return unknownOdd, nil
+}
+
+// unknownRecords returns the TLV records that encode the given unknown
+// records with their original values.
+func unknownRecords(types tlv.TypeMap) []tlv.Record {
+	records := make([]tlv.Record, 0, len(types))
+	for typ, value := range types {
+		value := value
+		records = append(records, tlv.MakePrimitiveRecord(typ, &value))
+	}
+
+	return records
+}
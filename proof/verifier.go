@@ -31,6 +31,20 @@ type Verifier interface {
 // BaseVerifier implements a simple verifier that loads the entire proof file
 // into memory and then verifies it all at once.
 type BaseVerifier struct {
+	// Strictness is the verification strictness applied to the proof
+	// files. The zero value fully verifies every state transition.
+	Strictness VerificationStrictness
+
+	// UniverseVerifier is used to find out whether a proof is already
+	// known to a trusted universe. It is only used with
+	// StrictnessTrustUniverse. If it is nil, no proof is trusted.
+	UniverseVerifier UniverseVerifier
+
+	// RotationLookup is used to look up the known rotations of the
+	// issuance authority of asset groups, so reissuance proofs signed by
+	// a stale key can be rejected. If it is nil, only the issuance
+	// authority carried by a proof itself is verified.
+	RotationLookup GroupRotationLookup
 }
 
 // Verify takes the passed serialized proof file, and returns a nil
@@ -46,7 +60,10 @@ func (b *BaseVerifier) Verify(ctx context.Context, blobReader io.Reader,
 		return nil, fmt.Errorf("unable to parse proof: %w", err)
 	}
 
-	return proofFile.Verify(ctx, headerVerifier, groupVerifier)
+	return proofFile.verifyWithStrictness(
+		ctx, headerVerifier, groupVerifier, b.Strictness,
+		b.UniverseVerifier, b.RotationLookup,
+	)
 }
 
 // verifyTaprootProof attempts to verify a TaprootProof for inclusion or
@@ -249,14 +266,14 @@ func (p *Proof) verifyAssetStateTransition(ctx context.Context,
 
 // verifyChallengeWitness verifies the challenge witness by constructing a
 // well-defined 1-in-1-out packet and verifying the witness is valid for that
-// virtual transaction.
-func (p *Proof) verifyChallengeWitness() (bool, error) {
+// virtual transaction. If a challenge is given, the witness must commit to it.
+func (p *Proof) verifyChallengeWitness(challenge *[32]byte) (bool, error) {
 	// The challenge witness packet always has one input and one output,
 	// independent of how the asset was created. The chain params are only
 	// needed when encoding/decoding a vPkt, so it doesn't matter what
 	// network we choose as we only need the packet to get the witness.
 	vPkt := tappsbt.OwnershipProofPacket(
-		p.Asset.Copy(), &address.MainNetTap,
+		p.Asset.Copy(), challenge, &address.MainNetTap,
 	)
 	vIn := vPkt.Inputs[0]
 	vOut := vPkt.Outputs[0]
@@ -298,7 +315,6 @@ func (p *Proof) verifyGenesisReveal() error {
 		return ErrGenesisRevealPrevOutMismatch
 	}
 
-	// TODO(roasbeef): enforce practical limit on size of meta reveal
 	// If this asset has an empty meta reveal, then the meta hash must be
 	// empty. Otherwise, the meta hash must match the meta reveal.
 	var proofMeta [asset.MetaHashLen]byte
@@ -307,6 +323,12 @@ func (p *Proof) verifyGenesisReveal() error {
 	}
 
 	if p.MetaReveal != nil {
+		// The revealed meta data must be within the size limit and
+		// match its declared type.
+		if err := p.MetaReveal.Validate(); err != nil {
+			return fmt.Errorf("invalid meta reveal: %w", err)
+		}
+
 		proofMeta = p.MetaReveal.MetaHash()
 	}
 
@@ -363,6 +385,26 @@ func (p *Proof) verifyGroupKeyReveal() error {
 	return nil
 }
 
+// verifySupplyCap verifies that a supply cap is only declared by the proof of a
+// group anchor and that the amount issued by the anchor doesn't exceed it.
+func (p *Proof) verifySupplyCap() error {
+	if p.MetaReveal == nil || p.MetaReveal.SupplyCap == 0 {
+		return nil
+	}
+
+	if p.Asset.GroupKey == nil || p.GroupKeyReveal == nil {
+		return ErrSupplyCapNotGroupAnchor
+	}
+
+	if p.Asset.Amount > p.MetaReveal.SupplyCap {
+		return fmt.Errorf("%w: anchor issued %d of cap %d",
+			ErrSupplyCapExceeded, p.Asset.Amount,
+			p.MetaReveal.SupplyCap)
+	}
+
+	return nil
+}
+
 // HeaderVerifier is a callback function which returns an error if the given
 // block header is invalid (usually: not present on chain).
 type HeaderVerifier func(blockHeader wire.BlockHeader, blockHeight uint32) error
@@ -372,6 +414,42 @@ type HeaderVerifier func(blockHeader wire.BlockHeader, blockHeight uint32) error
 // issuance proof for the group anchor has not been imported or synced.
 type GroupVerifier func(groupKey *btcec.PublicKey) error
 
+// RevealedGroupVerifier returns a group verifier that accepts the group keys
+// revealed by the group anchor proofs among the given proofs, and defers to
+// the fallback verifier for any other group key. The group key reveal of a
+// genesis proof is checked against the group key of its asset during
+// verification. So a proof file that passes verification proves the existence
+// of the groups it reveals, even if they were never imported by this node.
+func RevealedGroupVerifier(fallback GroupVerifier,
+	proofs ...*Proof) GroupVerifier {
+
+	revealed := make(map[asset.SerializedKey]struct{})
+	for _, p := range proofs {
+		if p.GroupKeyReveal == nil || p.Asset.GroupKey == nil {
+			continue
+		}
+
+		groupKey := p.Asset.GroupKey.GroupPubKey
+		revealed[asset.ToSerialized(&groupKey)] = struct{}{}
+	}
+
+	return func(groupKey *btcec.PublicKey) error {
+		if groupKey != nil {
+			_, ok := revealed[asset.ToSerialized(groupKey)]
+			if ok {
+				return nil
+			}
+		}
+
+		return fallback(groupKey)
+	}
+}
+
+// UniverseVerifier is a callback function which returns true if the given
+// proof is already known to a trusted universe, in which case the state
+// transition it represents doesn't need to be verified again.
+type UniverseVerifier func(ctx context.Context, p *Proof) (bool, error)
+
 // GroupAnchorVerifier is a callback function which returns an error if the
 // given genesis is not the asset genesis of the group anchor. This callback
 // should return an error for any reissuance into an existing group.
@@ -393,6 +471,62 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 	headerVerifier HeaderVerifier,
 	groupVerifier GroupVerifier) (*AssetSnapshot, error) {
 
+	return p.verify(
+		ctx, prev, headerVerifier, groupVerifier, nil, checkFull,
+	)
+}
+
+// VerifyWithRotations verifies the proof as described in Verify. In addition,
+// a reissuance that was authorized by a group key that was already rotated out
+// according to the given rotation lookup is rejected.
+func (p *Proof) VerifyWithRotations(ctx context.Context, prev *AssetSnapshot,
+	headerVerifier HeaderVerifier, groupVerifier GroupVerifier,
+	rotationLookup GroupRotationLookup) (*AssetSnapshot, error) {
+
+	return p.verify(
+		ctx, prev, headerVerifier, groupVerifier, rotationLookup,
+		checkFull,
+	)
+}
+
+// VerifyOwnership verifies an ownership proof, which is a single proof with a
+// challenge witness, against the challenge the verifier gave to the prover.
+// All other checks of Verify are applied as well. If the challenge is nil, the
+// witness must not commit to any challenge. A proof created for one challenge
+// is rejected for any other challenge, so it can't be replayed to a different
+// verifier.
+func (p *Proof) VerifyOwnership(ctx context.Context, challenge *[32]byte,
+	headerVerifier HeaderVerifier,
+	groupVerifier GroupVerifier) (*AssetSnapshot, error) {
+
+	if p.ChallengeWitness == nil {
+		return nil, ErrMissingChallengeWitness
+	}
+
+	// We first apply all checks except for the witness validation, as we
+	// validate the challenge witness ourselves below.
+	snapshot, err := p.verify(
+		ctx, nil, headerVerifier, groupVerifier, nil, checkChain,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := p.verifyChallengeWitness(challenge); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChallengeWitness,
+			err)
+	}
+
+	return snapshot, nil
+}
+
+// verify verifies the proof as described in Verify, but only applies the
+// checks selected by the given proof check level.
+func (p *Proof) verify(ctx context.Context, prev *AssetSnapshot,
+	headerVerifier HeaderVerifier, groupVerifier GroupVerifier,
+	rotationLookup GroupRotationLookup,
+	check proofCheck) (*AssetSnapshot, error) {
+
 	// 0. Check only for the proof version.
 	if p.IsUnknownVersion() {
 		return nil, ErrUnknownVersion
@@ -413,11 +547,14 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 		return nil, commitment.ErrInvalidTaprootProof // TODO
 	}
 
-	// Cross-check block header with a bitcoin node.
-	err := headerVerifier(p.BlockHeader, p.BlockHeight)
-	if err != nil {
-		return nil, fmt.Errorf("failed to validate proof block "+
-			"header: %w", err)
+	// Cross-check block header with a bitcoin node, unless the transition
+	// is already known to a trusted universe.
+	if check != checkTrusted {
+		err := headerVerifier(p.BlockHeader, p.BlockHeight)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate proof "+
+				"block header: %w", err)
+		}
 	}
 
 	if !p.TxMerkleProof.Verify(&p.AnchorTx, p.BlockHeader.MerkleRoot) {
@@ -475,8 +612,15 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 	// assets have a group key, and should therefore not have a group key
 	// reveal. The group key reveal must be present for group anchors, and
 	// the group key must be present for any reissuance into an asset group.
+	// An issuance authority is only valid for reissuances.
 	hasGroupKeyReveal := p.GroupKeyReveal != nil
 	hasGroupKey := p.Asset.GroupKey != nil
+	isReissuance := isGenesisAsset && hasGroupKey && !hasGroupKeyReveal
+	if p.IssuanceAuthority != nil && !isReissuance {
+		return nil, fmt.Errorf("%w: issuance authority for non "+
+			"reissuance", ErrIssuanceAuthorityInvalid)
+	}
+
 	switch {
 	case !isGenesisAsset && hasGroupKeyReveal:
 		return nil, ErrNonGenesisAssetWithGroupKeyReveal
@@ -491,12 +635,31 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 			return nil, err
 		}
 
+		// If the issuance authority of the group was rotated, the
+		// reissuance must be authorized by the latest key.
+		err := p.verifyIssuanceAuthority(
+			ctx, headerVerifier, rotationLookup,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 	case isGenesisAsset && hasGroupKey && hasGroupKeyReveal:
 		if err := p.verifyGroupKeyReveal(); err != nil {
 			return nil, err
 		}
 	}
 
+	// A supply cap can only be declared by a group anchor, and the anchor
+	// itself must respect it. Whether the re-issuances into the group stay
+	// within the cap can only be checked against the full issuance history
+	// of the group, which is done by the universe.
+	if isGenesisAsset {
+		if err := p.verifySupplyCap(); err != nil {
+			return nil, err
+		}
+	}
+
 	// 7. Verify group key for asset transfers. Any asset with a group key
 	// must carry a group key that has already been imported and verified.
 	if !isGenesisAsset && hasGroupKey {
@@ -507,11 +670,15 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 
 	// 8. Either a set of asset inputs with valid witnesses is included that
 	// satisfy the resulting state transition or a challenge witness is
-	// provided as part of an ownership proof.
+	// provided as part of an ownership proof. The witnesses are only
+	// validated if the strictness of the verification demands it.
 	var splitAsset bool
 	switch {
+	case check != checkFull:
+		splitAsset = p.Asset.HasSplitCommitmentWitness()
+
 	case prev == nil && p.ChallengeWitness != nil:
-		splitAsset, err = p.verifyChallengeWitness()
+		splitAsset, err = p.verifyChallengeWitness(nil)
 
 	default:
 		splitAsset, err = p.verifyAssetStateTransition(
@@ -537,6 +704,7 @@ func (p *Proof) Verify(ctx context.Context, prev *AssetSnapshot,
 		},
 		AnchorBlockHash:   p.BlockHeader.BlockHash(),
 		AnchorBlockHeight: p.BlockHeight,
+		AnchorBlockTime:   p.BlockHeader.Timestamp,
 		AnchorTx:          &p.AnchorTx,
 		OutputIndex:       p.InclusionProof.OutputIndex,
 		InternalKey:       p.InclusionProof.InternalKey,
@@ -559,6 +727,47 @@ func (f *File) Verify(ctx context.Context, headerVerifier HeaderVerifier,
 
 	*AssetSnapshot, error) {
 
+	return f.VerifyWithStrictness(
+		ctx, headerVerifier, groupVerifier, StrictnessVerifyFull, nil,
+	)
+}
+
+// VerifyWithRotations attempts to verify a full proof file starting from the
+// asset's genesis. In addition to the checks of Verify, reissuances that were
+// authorized by a group key that was already rotated out according to the
+// given rotation lookup are rejected.
+func (f *File) VerifyWithRotations(ctx context.Context,
+	headerVerifier HeaderVerifier, groupVerifier GroupVerifier,
+	rotationLookup GroupRotationLookup) (*AssetSnapshot, error) {
+
+	return f.verifyWithStrictness(
+		ctx, headerVerifier, groupVerifier, StrictnessVerifyFull, nil,
+		rotationLookup,
+	)
+}
+
+// VerifyWithStrictness attempts to verify a full proof file starting from the
+// asset's genesis, applying the given verification strictness. The universe
+// verifier is only used with StrictnessTrustUniverse and may be nil.
+func (f *File) VerifyWithStrictness(ctx context.Context,
+	headerVerifier HeaderVerifier, groupVerifier GroupVerifier,
+	strictness VerificationStrictness,
+	universeVerifier UniverseVerifier) (*AssetSnapshot, error) {
+
+	return f.verifyWithStrictness(
+		ctx, headerVerifier, groupVerifier, strictness,
+		universeVerifier, nil,
+	)
+}
+
+// verifyWithStrictness verifies a full proof file as described in
+// VerifyWithStrictness. The rotation lookup is used to reject reissuances that
+// were authorized by a stale group key and may be nil.
+func (f *File) verifyWithStrictness(ctx context.Context,
+	headerVerifier HeaderVerifier, groupVerifier GroupVerifier,
+	strictness VerificationStrictness, universeVerifier UniverseVerifier,
+	rotationLookup GroupRotationLookup) (*AssetSnapshot, error) {
+
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -571,6 +780,29 @@ func (f *File) Verify(ctx context.Context, headerVerifier HeaderVerifier,
 		return nil, ErrUnknownVersion
 	}
 
+	// If we trust the universe, we look for the latest state transition
+	// that is already known to it. That transition and all transitions
+	// leading up to it don't need to be verified again.
+	trustedIdx := -1
+	if strictness == StrictnessTrustUniverse && universeVerifier != nil {
+		for idx := len(f.proofs) - 1; idx >= 0; idx-- {
+			decodedProof, err := f.ProofAt(uint32(idx))
+			if err != nil {
+				return nil, err
+			}
+
+			known, err := universeVerifier(ctx, decodedProof)
+			if err != nil {
+				return nil, fmt.Errorf("unable to look up "+
+					"proof in universe: %w", err)
+			}
+			if known {
+				trustedIdx = idx
+				break
+			}
+		}
+	}
+
 	var prev *AssetSnapshot
 	for idx := range f.proofs {
 		select {
@@ -584,8 +816,20 @@ func (f *File) Verify(ctx context.Context, headerVerifier HeaderVerifier,
 			return nil, err
 		}
 
-		result, err := decodedProof.Verify(
+		check := checkFull
+		switch {
+		case idx <= trustedIdx:
+			check = checkTrusted
+
+		case idx < len(f.proofs)-1 &&
+			strictness != StrictnessVerifyFull:
+
+			check = checkChain
+		}
+
+		result, err := decodedProof.verify(
 			ctx, prev, headerVerifier, groupVerifier,
+			rotationLookup, check,
 		)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,80 @@
+package taprootassets
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/lightninglabs/lndclient"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+// ScriptKeyFetcher is used to look up the key descriptor of a local script
+// key.
+type ScriptKeyFetcher interface {
+	// FetchScriptKey attempts to fetch the full tweaked script key struct
+	// (including the key descriptor) for the given tweaked script key.
+	FetchScriptKey(ctx context.Context,
+		tweakedScriptKey *btcec.PublicKey) (*asset.TweakedScriptKey,
+		error)
+}
+
+// LndRpcReceiptSigner is an implementation of the proof.ReceiptSigner
+// interface backed by an active lnd node.
+type LndRpcReceiptSigner struct {
+	lnd        *lndclient.LndServices
+	scriptKeys ScriptKeyFetcher
+}
+
+// NewLndRpcReceiptSigner returns a new receipt signer instance backed by the
+// passed connection to a remote lnd node.
+func NewLndRpcReceiptSigner(lnd *lndclient.LndServices,
+	scriptKeys ScriptKeyFetcher) *LndRpcReceiptSigner {
+
+	return &LndRpcReceiptSigner{
+		lnd:        lnd,
+		scriptKeys: scriptKeys,
+	}
+}
+
+// SignDeliveryReceipt creates a delivery receipt for the proof with the given
+// hash, signed by the internal key of the given script key.
+func (l *LndRpcReceiptSigner) SignDeliveryReceipt(ctx context.Context,
+	scriptKey *btcec.PublicKey,
+	proofHash [sha256.Size]byte) (*proof.DeliveryReceipt, error) {
+
+	tweakedKey, err := l.scriptKeys.FetchScriptKey(ctx, scriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch script key: %w", err)
+	}
+
+	// We sign with the raw internal key, since lnd doesn't apply a BIP-86
+	// tweak when signing messages. The receipt contains the tweak so the
+	// verifier can link the internal key to the script key.
+	rawSig, err := l.lnd.Signer.SignMessage(
+		ctx, proof.ReceiptMessage(proofHash),
+		tweakedKey.RawKey.KeyLocator, lndclient.SignSchnorr(nil),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign receipt: %w", err)
+	}
+
+	sig, err := schnorr.ParseSignature(rawSig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse signature: %w", err)
+	}
+
+	return &proof.DeliveryReceipt{
+		ProofHash:    proofHash,
+		RawScriptKey: tweakedKey.RawKey.PubKey,
+		TapTweak:     tweakedKey.Tweak,
+		Signature:    sig,
+	}, nil
+}
+
+// A compile time assertion to ensure LndRpcReceiptSigner meets the
+// proof.ReceiptSigner interface.
+var _ proof.ReceiptSigner = (*LndRpcReceiptSigner)(nil)
@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -17,7 +20,10 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -40,10 +46,13 @@ import (
 	unirpc "github.com/lightninglabs/taproot-assets/taprpc/universerpc"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/signal"
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -91,6 +100,10 @@ type rpcServer struct {
 
 	cfg *Config
 
+	// sendEventLog forwards the persisted events of the asset sending
+	// process and replays the ones a subscriber missed.
+	sendEventLog *sendEventLog
+
 	blockTimestampCache *lru.Cache[uint32, cacheableTimestamp]
 
 	quit chan struct{}
@@ -99,12 +112,13 @@ type rpcServer struct {
 
 // newRPCServer creates a new RPC sever from the set of input dependencies.
 func newRPCServer(interceptor signal.Interceptor,
-	interceptorChain *rpcperms.InterceptorChain,
-	cfg *Config) (*rpcServer, error) {
+	interceptorChain *rpcperms.InterceptorChain, cfg *Config,
+	sendEventLog *sendEventLog) (*rpcServer, error) {
 
 	return &rpcServer{
 		interceptor:      interceptor,
 		interceptorChain: interceptorChain,
+		sendEventLog:     sendEventLog,
 		blockTimestampCache: lru.NewCache[uint32, cacheableTimestamp](
 			maxNumBlocksInCache,
 		),
@@ -282,6 +296,36 @@ func (r *rpcServer) DebugLevel(ctx context.Context,
 	return &taprpc.DebugLevelResponse{}, nil
 }
 
+// BackupDatabase creates a consistent backup of the database while the daemon
+// keeps running, and writes it to the given path.
+func (r *rpcServer) BackupDatabase(ctx context.Context,
+	req *taprpc.BackupDatabaseRequest) (*taprpc.BackupDatabaseResponse,
+	error) {
+
+	// The path is interpreted on the machine running the daemon, so we
+	// don't want it to depend on the daemon's working directory.
+	if !filepath.IsAbs(req.FilePath) {
+		return nil, fmt.Errorf("backup file path must be absolute")
+	}
+
+	rpcsLog.Infof("[BackupDatabase]: backing up database to %v",
+		req.FilePath)
+
+	info, err := r.cfg.Backuper.Backup(ctx, req.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	rpcsLog.Infof("[BackupDatabase]: backed up database to %v, size=%d, "+
+		"sha256=%x", info.Path, info.Size, info.SHA256[:])
+
+	return &taprpc.BackupDatabaseResponse{
+		FilePath:  info.Path,
+		SizeBytes: info.Size,
+		Sha256:    hex.EncodeToString(info.SHA256[:]),
+	}, nil
+}
+
 // GetInfo returns general information relating to the active daemon. For
 // example: its version, network, and lnd version.
 func (r *rpcServer) GetInfo(context.Context,
@@ -294,6 +338,27 @@ func (r *rpcServer) GetInfo(context.Context,
 	}, nil
 }
 
+// GetConfig returns the effective configuration the daemon was started with.
+func (r *rpcServer) GetConfig(ctx context.Context,
+	_ *taprpc.GetConfigRequest) (*taprpc.GetConfigResponse, error) {
+
+	uniServers, err := r.cfg.FederationDB.UniverseServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch federation servers: "+
+			"%w", err)
+	}
+
+	return &taprpc.GetConfigResponse{
+		Network: r.cfg.ChainParams.Name,
+		FederationServers: fn.Map(
+			uniServers, func(s universe.ServerAddr) string {
+				return s.HostStr()
+			},
+		),
+		Config: r.cfg.EffectiveConfig,
+	}, nil
+}
+
 // MintAsset attempts to mint the set of assets (async by default to ensure
 // proper batching) specified in the request.
 func (r *rpcServer) MintAsset(ctx context.Context,
@@ -308,7 +373,24 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 		return nil, fmt.Errorf("invalid asset name: %w", err)
 	}
 
-	specificGroupKey := len(req.Asset.GroupKey) != 0
+	// A previously issued asset can be referenced instead of its group
+	// key, in which case we look up the group key of that asset.
+	groupKeyBytes := req.Asset.GroupKey
+	if len(req.Asset.GroupAssetId) != 0 {
+		if len(groupKeyBytes) != 0 {
+			return nil, fmt.Errorf("cannot specify a group key " +
+				"and a group asset ID")
+		}
+
+		groupKeyBytes, err = r.groupKeyOfAsset(
+			ctx, req.Asset.GroupAssetId,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	specificGroupKey := len(groupKeyBytes) != 0
 	specificGroupAnchor := len(req.Asset.GroupAnchor) != 0
 
 	// Using a specific group key or anchor implies disabling emission.
@@ -346,7 +428,7 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 				"and a group anchor")
 		}
 
-		groupTweakedKey, err := btcec.ParsePubKey(req.Asset.GroupKey)
+		groupTweakedKey, err := btcec.ParsePubKey(groupKeyBytes)
 		if err != nil {
 			return nil, fmt.Errorf("invalid group key: %w", err)
 		}
@@ -372,6 +454,16 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 		seedling.GroupAnchor = &req.Asset.GroupAnchor
 	}
 
+	if req.Asset.ScriptKeyTapscript != nil {
+		seedling.ScriptKeyTapscript, err = UnmarshalScriptKeyTapscript(
+			req.Asset.ScriptKeyTapscript,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script key tapscript: "+
+				"%w", err)
+		}
+	}
+
 	if req.Asset.AssetMeta != nil {
 		// Ensure that the meta field is within bounds.
 		switch {
@@ -385,8 +477,9 @@ func (r *rpcServer) MintAsset(ctx context.Context,
 		}
 
 		seedling.Meta = &proof.MetaReveal{
-			Type: proof.MetaType(req.Asset.AssetMeta.Type),
-			Data: req.Asset.AssetMeta.Data,
+			Type:      proof.MetaType(req.Asset.AssetMeta.Type),
+			Data:      req.Asset.AssetMeta.Data,
+			SupplyCap: req.Asset.AssetMeta.SupplyCap,
 		}
 
 		// If the asset meta field was specified, then the data inside
@@ -431,9 +524,37 @@ func (r *rpcServer) FinalizeBatch(_ context.Context,
 	req *mintrpc.FinalizeBatchRequest) (*mintrpc.FinalizeBatchResponse,
 	error) {
 
-	batch, err := r.cfg.AssetMinter.FinalizeBatch()
-	if err != nil {
-		return nil, fmt.Errorf("unable to finalize batch: %w", err)
+	var (
+		batch *tapgarden.MintingBatch
+		err   error
+	)
+	switch {
+	// If a finalize time or minimum number of seedlings is given, we only
+	// schedule the finalization of the pending batch.
+	case req.FinalizeTime != 0 || req.MinSeedlings != 0:
+		if req.FinalizeTime < 0 {
+			return nil, fmt.Errorf("finalize time must be positive")
+		}
+
+		schedule := tapgarden.FinalizeSchedule{
+			MinSeedlings: int(req.MinSeedlings),
+		}
+		if req.FinalizeTime != 0 {
+			schedule.FinalizeTime = time.Unix(req.FinalizeTime, 0)
+		}
+
+		batch, err = r.cfg.AssetMinter.ScheduleFinalize(schedule)
+		if err != nil {
+			return nil, fmt.Errorf("unable to schedule batch "+
+				"finalization: %w", err)
+		}
+
+	default:
+		batch, err = r.cfg.AssetMinter.FinalizeBatch()
+		if err != nil {
+			return nil, fmt.Errorf("unable to finalize batch: %w",
+				err)
+		}
 	}
 
 	// If there was no batch to finalize, return an empty response.
@@ -456,18 +577,19 @@ func (r *rpcServer) CancelBatch(_ context.Context,
 	_ *mintrpc.CancelBatchRequest) (*mintrpc.CancelBatchResponse,
 	error) {
 
-	batchKey, err := r.cfg.AssetMinter.CancelBatch()
+	cancelled, err := r.cfg.AssetMinter.CancelBatch()
 	if err != nil {
 		return nil, fmt.Errorf("unable to cancel batch: %w", err)
 	}
 
 	// If there was no batch to cancel, return an empty response.
-	if batchKey == nil {
+	if cancelled == nil || cancelled.BatchKey == nil {
 		return &mintrpc.CancelBatchResponse{}, nil
 	}
 
 	return &mintrpc.CancelBatchResponse{
-		BatchKey: batchKey.SerializeCompressed(),
+		BatchKey:     cancelled.BatchKey.SerializeCompressed(),
+		NumSeedlings: uint32(cancelled.NumSeedlings),
 	}, nil
 }
 
@@ -525,6 +647,64 @@ func (r *rpcServer) ListBatches(_ context.Context,
 	}, nil
 }
 
+// groupKeyOfAsset returns the serialized group key of the previously issued
+// asset with the given ID.
+func (r *rpcServer) groupKeyOfAsset(ctx context.Context,
+	rawAssetID []byte) ([]byte, error) {
+
+	if len(rawAssetID) != sha256.Size {
+		return nil, fmt.Errorf("group asset ID must be 32 bytes")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], rawAssetID)
+
+	assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(ctx, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("unknown group asset %v: %w", assetID,
+			err)
+	}
+
+	if assetGroup.GroupKey == nil {
+		return nil, fmt.Errorf("group asset %v isn't grouped", assetID)
+	}
+
+	return assetGroup.GroupPubKey.SerializeCompressed(), nil
+}
+
+// RotateGroupKey hands the issuance authority of an asset group over to a
+// freshly derived key. The rotation is committed to on chain and only returns
+// once it has been confirmed.
+func (r *rpcServer) RotateGroupKey(ctx context.Context,
+	req *mintrpc.RotateGroupKeyRequest) (*mintrpc.RotateGroupKeyResponse,
+	error) {
+
+	groupKey, err := btcec.ParsePubKey(req.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group key: %w", err)
+	}
+
+	rotation, err := r.cfg.GroupKeyRotator.RotateGroupKey(
+		ctx, groupKey, chainfee.SatPerKWeight(req.SatPerKw),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to rotate group key: %w", err)
+	}
+
+	anchorOutpoint := wire.OutPoint{
+		Hash:  rotation.AnchorTx.TxHash(),
+		Index: rotation.OutputIndex,
+	}
+
+	return &mintrpc.RotateGroupKeyResponse{
+		Sequence:       rotation.Sequence,
+		PrevKey:        rotation.PrevKey.SerializeCompressed(),
+		NewKey:         rotation.NewKey.SerializeCompressed(),
+		AnchorOutpoint: anchorOutpoint.String(),
+		BlockHeight:    rotation.BlockHeight,
+	}, nil
+}
+
 // checkBalanceOverflow ensures that the new asset amount will not overflow
 // the max allowed asset (or asset group) balance.
 func (r *rpcServer) checkBalanceOverflow(ctx context.Context,
@@ -595,10 +775,30 @@ func (r *rpcServer) ListAssets(ctx context.Context,
 	case req.IncludeSpent && req.IncludeLeased:
 		return nil, fmt.Errorf("cannot specify both include_spent " +
 			"and include_leased")
+
+	case req.AcquiredAfter < 0 || req.AcquiredBefore < 0:
+		return nil, fmt.Errorf("acquisition time filters must not be " +
+			"negative")
+
+	case req.AcquiredBefore != 0 && req.AcquiredAfter >= req.AcquiredBefore:
+		return nil, fmt.Errorf("acquired_after must be before " +
+			"acquired_before")
+	}
+
+	var filters *tapdb.AssetQueryFilters
+	if req.AcquiredAfter != 0 || req.AcquiredBefore != 0 {
+		filters = &tapdb.AssetQueryFilters{}
+		if req.AcquiredAfter != 0 {
+			filters.MinAcquiredAt = time.Unix(req.AcquiredAfter, 0)
+		}
+		if req.AcquiredBefore != 0 {
+			filters.MaxAcquiredAt = time.Unix(req.AcquiredBefore, 0)
+		}
 	}
 
 	rpcAssets, err := r.fetchRpcAssets(
 		ctx, req.WithWitness, req.IncludeSpent, req.IncludeLeased,
+		filters,
 	)
 	if err != nil {
 		return nil, err
@@ -610,10 +810,11 @@ func (r *rpcServer) ListAssets(ctx context.Context,
 }
 
 func (r *rpcServer) fetchRpcAssets(ctx context.Context, withWitness,
-	includeSpent, includeLeased bool) ([]*taprpc.Asset, error) {
+	includeSpent, includeLeased bool,
+	filters *tapdb.AssetQueryFilters) ([]*taprpc.Asset, error) {
 
 	assets, err := r.cfg.AssetStore.FetchAllAssets(
-		ctx, includeSpent, includeLeased, nil,
+		ctx, includeSpent, includeLeased, filters,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read chain assets: %w", err)
@@ -631,6 +832,239 @@ func (r *rpcServer) fetchRpcAssets(ctx context.Context, withWitness,
 	return rpcAssets, nil
 }
 
+// SubscribeAssets sends the current set of assets owned by the daemon and then
+// streams incremental updates to that set. The set is read again whenever a
+// proof is imported, the chain porter reports progress on a transfer or a
+// minting batch is finalized, which covers received, sent, minted and newly
+// confirmed assets. Unless leased assets are included, the set is also read
+// again once the earliest lease expires.
+func (r *rpcServer) SubscribeAssets(req *taprpc.SubscribeAssetsRequest,
+	stream taprpc.TaprootAssets_SubscribeAssetsServer) error {
+
+	// We register for the events before reading the initial snapshot, so
+	// we don't miss any change that happens in between.
+	proofEvents := fn.NewEventReceiver[proof.Blob](fn.DefaultQueueSize)
+	defer proofEvents.Stop()
+
+	err := r.cfg.AssetStore.RegisterSubscriber(proofEvents, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to register proof event "+
+			"subscription: %w", err)
+	}
+	defer func() {
+		_ = r.cfg.AssetStore.RemoveSubscriber(proofEvents)
+	}()
+
+	sendEvents := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	defer sendEvents.Stop()
+
+	err = r.cfg.ChainPorter.RegisterSubscriber(sendEvents, false, false)
+	if err != nil {
+		return fmt.Errorf("failed to register send event "+
+			"subscription: %w", err)
+	}
+	defer func() {
+		_ = r.cfg.ChainPorter.RemoveSubscriber(sendEvents)
+	}()
+
+	mintEvents := fn.NewEventReceiver[tapgarden.BatchKey](
+		fn.DefaultQueueSize,
+	)
+	defer mintEvents.Stop()
+
+	err = r.cfg.AssetMinter.RegisterSubscriber(mintEvents)
+	if err != nil {
+		return fmt.Errorf("failed to register mint event "+
+			"subscription: %w", err)
+	}
+	defer func() {
+		_ = r.cfg.AssetMinter.RemoveSubscriber(mintEvents)
+	}()
+
+	// We always fetch the leased assets as well, so we know when the
+	// next lease expires and the asset becomes part of the set again.
+	ctx := stream.Context()
+	fetchAssets := func() ([]*taprpc.Asset, time.Time, error) {
+		allAssets, err := r.fetchRpcAssets(
+			ctx, req.WithWitness, false, true, nil,
+		)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+
+		assets, nextExpiry := filterLeasedAssets(
+			allAssets, req.IncludeLeased, time.Now(),
+		)
+
+		return assets, nextExpiry, nil
+	}
+
+	assets, nextExpiry, err := fetchAssets()
+	if err != nil {
+		return err
+	}
+
+	err = stream.Send(&taprpc.AssetUpdate{
+		Timestamp:  time.Now().UnixMicro(),
+		IsSnapshot: true,
+		Added:      assets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send asset snapshot: %w", err)
+	}
+
+	for {
+		var (
+			leaseTimer  *time.Timer
+			leaseExpiry <-chan time.Time
+		)
+		if !nextExpiry.IsZero() {
+			leaseTimer = time.NewTimer(time.Until(nextExpiry))
+			leaseExpiry = leaseTimer.C
+		}
+
+		select {
+		case <-proofEvents.NewItemCreated.ChanOut():
+		case <-sendEvents.NewItemCreated.ChanOut():
+		case <-mintEvents.NewItemCreated.ChanOut():
+		case <-leaseExpiry:
+
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+
+			return ctx.Err()
+
+		case <-r.quit:
+			return nil
+		}
+
+		if leaseTimer != nil {
+			leaseTimer.Stop()
+		}
+
+		// A single change usually results in multiple events, so we
+		// consume all pending events before reading the set again.
+	drain:
+		for {
+			select {
+			case <-proofEvents.NewItemCreated.ChanOut():
+			case <-sendEvents.NewItemCreated.ChanOut():
+			case <-mintEvents.NewItemCreated.ChanOut():
+			default:
+				break drain
+			}
+		}
+
+		var newAssets []*taprpc.Asset
+		newAssets, nextExpiry, err = fetchAssets()
+		if err != nil {
+			return err
+		}
+
+		update := diffRpcAssets(assets, newAssets)
+		assets = newAssets
+		if update == nil {
+			continue
+		}
+
+		if err := stream.Send(update); err != nil {
+			return fmt.Errorf("failed to send asset update: %w",
+				err)
+		}
+	}
+}
+
+// filterLeasedAssets removes the assets that are leased at the given time from
+// the set, unless leased assets should be included. The time at which the
+// earliest of the removed leases expires is returned as well, or the zero time
+// if no asset was removed.
+func filterLeasedAssets(assets []*taprpc.Asset, includeLeased bool,
+	now time.Time) ([]*taprpc.Asset, time.Time) {
+
+	if includeLeased {
+		return assets, time.Time{}
+	}
+
+	var (
+		unleased   = make([]*taprpc.Asset, 0, len(assets))
+		nextExpiry time.Time
+	)
+	for _, a := range assets {
+		leaseExpiry := time.Unix(a.LeaseExpiry, 0)
+		if len(a.LeaseOwner) == 0 || !leaseExpiry.After(now) {
+			unleased = append(unleased, a)
+			continue
+		}
+
+		if nextExpiry.IsZero() || leaseExpiry.Before(nextExpiry) {
+			nextExpiry = leaseExpiry
+		}
+	}
+
+	return unleased, nextExpiry
+}
+
+// rpcAssetKey returns the key that identifies an asset within the set of owned
+// assets, which consists of its anchor outpoint, asset ID and script key.
+func rpcAssetKey(a *taprpc.Asset) string {
+	var (
+		anchorOutpoint string
+		assetID        []byte
+	)
+	if a.ChainAnchor != nil {
+		anchorOutpoint = a.ChainAnchor.AnchorOutpoint
+	}
+	if a.AssetGenesis != nil {
+		assetID = a.AssetGenesis.AssetId
+	}
+
+	return fmt.Sprintf("%s:%x:%x", anchorOutpoint, assetID, a.ScriptKey)
+}
+
+// diffRpcAssets returns the update that turns the old set of assets into the
+// new one. Nil is returned if both sets are equal.
+func diffRpcAssets(oldAssets, newAssets []*taprpc.Asset) *taprpc.AssetUpdate {
+	oldByKey := make(map[string]*taprpc.Asset, len(oldAssets))
+	for _, a := range oldAssets {
+		oldByKey[rpcAssetKey(a)] = a
+	}
+
+	var (
+		update  taprpc.AssetUpdate
+		newKeys = make(map[string]struct{}, len(newAssets))
+	)
+	for _, a := range newAssets {
+		key := rpcAssetKey(a)
+		newKeys[key] = struct{}{}
+
+		oldAsset, ok := oldByKey[key]
+		switch {
+		case !ok:
+			update.Added = append(update.Added, a)
+
+		case !proto.Equal(oldAsset, a):
+			update.Changed = append(update.Changed, a)
+		}
+	}
+	for _, a := range oldAssets {
+		if _, ok := newKeys[rpcAssetKey(a)]; !ok {
+			update.Removed = append(update.Removed, a)
+		}
+	}
+
+	numChanges := len(update.Added) + len(update.Removed) +
+		len(update.Changed)
+	if numChanges == 0 {
+		return nil
+	}
+
+	update.Timestamp = time.Now().UnixMicro()
+
+	return &update
+}
+
 func (r *rpcServer) marshalChainAsset(ctx context.Context, a *tapdb.ChainAsset,
 	withWitness bool) (*taprpc.Asset, error) {
 
@@ -668,6 +1102,10 @@ func (r *rpcServer) marshalChainAsset(ctx context.Context, a *tapdb.ChainAsset,
 		rpcAsset.LeaseExpiry = a.AnchorLeaseExpiry.UTC().Unix()
 	}
 
+	if !a.AcquiredAt.IsZero() {
+		rpcAsset.AcquiredAt = a.AcquiredAt.Unix()
+	}
+
 	return rpcAsset, nil
 }
 
@@ -687,23 +1125,28 @@ func (r *rpcServer) listBalancesByAsset(ctx context.Context,
 		balance := balance
 
 		assetIDStr := hex.EncodeToString(balance.ID[:])
-
-		resp.AssetBalances[assetIDStr] = &taprpc.AssetBalance{
-			AssetGenesis: &taprpc.GenesisInfo{
-				Version:      int32(balance.Version),
-				GenesisPoint: balance.GenesisPoint.String(),
-				Name:         balance.Tag,
-				MetaHash:     balance.MetaHash[:],
-				AssetId:      balance.ID[:],
-			},
-			AssetType: taprpc.AssetType(balance.Type),
-			Balance:   balance.Balance,
-		}
+		resp.AssetBalances[assetIDStr] = marshalAssetBalance(balance)
 	}
 
 	return resp, nil
 }
 
+// marshalAssetBalance turns the balance of a single asset ID into its RPC
+// counterpart.
+func marshalAssetBalance(balance tapdb.AssetBalance) *taprpc.AssetBalance {
+	return &taprpc.AssetBalance{
+		AssetGenesis: &taprpc.GenesisInfo{
+			Version:      int32(balance.Version),
+			GenesisPoint: balance.GenesisPoint.String(),
+			Name:         balance.Tag,
+			MetaHash:     fn.ByteSlice(balance.MetaHash),
+			AssetId:      fn.ByteSlice(balance.ID),
+		},
+		AssetType: taprpc.AssetType(balance.Type),
+		Balance:   balance.Balance,
+	}
+}
+
 func (r *rpcServer) listBalancesByGroupKey(ctx context.Context,
 	groupKey *btcec.PublicKey) (*taprpc.ListBalancesResponse, error) {
 
@@ -728,11 +1171,48 @@ func (r *rpcServer) listBalancesByGroupKey(ctx context.Context,
 			groupKey = balance.GroupKey.SerializeCompressed()
 		}
 
+		// The balance of a group is broken down by the individual
+		// asset IDs (tranches) of the group.
+		assetBalances := make(
+			map[string]*taprpc.AssetBalance,
+			len(balance.AssetBalances),
+		)
+		for assetID, assetBalance := range balance.AssetBalances {
+			assetIDStr := hex.EncodeToString(assetID[:])
+			assetBalances[assetIDStr] = marshalAssetBalance(
+				assetBalance,
+			)
+		}
+
 		groupKeyString := hex.EncodeToString(groupKey)
 		resp.AssetGroupBalances[groupKeyString] = &taprpc.AssetGroupBalance{
-			GroupKey: groupKey,
-			Balance:  balance.Balance,
+			GroupKey:      groupKey,
+			Balance:       balance.Balance,
+			AssetBalances: assetBalances,
+		}
+	}
+
+	// Assets without a group key don't show up in any group, so we report
+	// them individually, unless we're asked for a specific group.
+	if groupKey != nil {
+		return resp, nil
+	}
+
+	assetBalances, err := r.cfg.AssetStore.QueryBalancesByAsset(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list balances: %w", err)
+	}
+
+	resp.AssetBalances = make(map[string]*taprpc.AssetBalance)
+	for assetID, assetBalance := range assetBalances {
+		if assetBalance.GroupKey != nil {
+			continue
 		}
+
+		assetIDStr := hex.EncodeToString(assetID[:])
+		resp.AssetBalances[assetIDStr] = marshalAssetBalance(
+			assetBalance,
+		)
 	}
 
 	return resp, nil
@@ -743,7 +1223,9 @@ func (r *rpcServer) listBalancesByGroupKey(ctx context.Context,
 func (r *rpcServer) ListUtxos(ctx context.Context,
 	req *taprpc.ListUtxosRequest) (*taprpc.ListUtxosResponse, error) {
 
-	rpcAssets, err := r.fetchRpcAssets(ctx, false, false, req.IncludeLeased)
+	rpcAssets, err := r.fetchRpcAssets(
+		ctx, false, false, req.IncludeLeased, nil,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -790,58 +1272,214 @@ func (r *rpcServer) ListUtxos(ctx context.Context,
 	}, nil
 }
 
-// ListGroups lists known groups and the assets held in each group.
-func (r *rpcServer) ListGroups(ctx context.Context,
-	_ *taprpc.ListGroupsRequest) (*taprpc.ListGroupsResponse, error) {
+// ListOnChainFootprint lists all on-chain UTXOs the daemon considers its own,
+// together with their key derivation and the assets they anchor.
+func (r *rpcServer) ListOnChainFootprint(ctx context.Context,
+	_ *taprpc.ListOnChainFootprintRequest) (
+	*taprpc.ListOnChainFootprintResponse, error) {
 
-	readableAssets, err := r.cfg.AssetStore.FetchGroupedAssets(ctx)
+	utxos, err := r.cfg.AssetStore.OnChainFootprint(ctx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to fetch on-chain footprint: %w",
+			err)
 	}
 
-	groupsWithAssets := make(map[string]*taprpc.GroupedAssets)
-
-	// Populate the map of group keys to assets in that group.
-	for _, a := range readableAssets {
-		groupKey := hex.EncodeToString(a.GroupKey.SerializeCompressed())
-
-		assetVersion, err := taprpc.MarshalAssetVersion(
-			a.Version,
-		)
-		if err != nil {
-			return nil, err
+	resp := &taprpc.ListOnChainFootprintResponse{
+		Utxos: make([]*taprpc.FootprintUtxo, len(utxos)),
+	}
+	for idx, utxo := range utxos {
+		rpcUtxo := &taprpc.FootprintUtxo{
+			Outpoint:         utxo.OutPoint.String(),
+			AmtSats:          int64(utxo.OutputValue),
+			InternalKey:      marshalKeyDescriptor(utxo.InternalKey),
+			TaprootAssetRoot: utxo.TaprootAssetRoot,
+			MerkleRoot:       utxo.MerkleRoot,
+			BlockHeight:      utxo.BlockHeight,
+			LeaseOwner:       utxo.LeaseOwner,
+			FeeBumpAnchor:    utxo.FeeBumpAnchor,
+			Assets: make(
+				[]*taprpc.FootprintAsset, len(utxo.Assets),
+			),
 		}
-
-		asset := &taprpc.AssetHumanReadable{
-			Id:               a.ID[:],
-			Version:          assetVersion,
-			Amount:           a.Amount,
-			LockTime:         int32(a.LockTime),
-			RelativeLockTime: int32(a.RelativeLockTime),
-			Tag:              a.Tag,
-			MetaHash:         a.MetaHash[:],
-			Type:             taprpc.AssetType(a.Type),
+		if !utxo.LeaseExpiry.IsZero() {
+			rpcUtxo.LeaseExpiry = utxo.LeaseExpiry.Unix()
 		}
 
-		_, ok := groupsWithAssets[groupKey]
-		if !ok {
-			groupsWithAssets[groupKey] = &taprpc.GroupedAssets{
-				Assets: []*taprpc.AssetHumanReadable{},
+		for assetIdx, a := range utxo.Assets {
+			rpcUtxo.Assets[assetIdx] = &taprpc.FootprintAsset{
+				AssetId:   fn.ByteSlice(a.ID),
+				Amount:    a.Amount,
+				ScriptKey: a.ScriptKey.SerializeCompressed(),
+				Spent:     a.Spent,
 			}
 		}
 
-		groupsWithAssets[groupKey].Assets = append(
-			groupsWithAssets[groupKey].Assets, asset,
-		)
+		resp.Utxos[idx] = rpcUtxo
+		resp.TotalAmtSats += rpcUtxo.AmtSats
 	}
 
-	return &taprpc.ListGroupsResponse{Groups: groupsWithAssets}, nil
+	return resp, nil
 }
 
-// ListBalances lists the asset balances owned by the daemon.
-func (r *rpcServer) ListBalances(ctx context.Context,
+// VerifySupplyConsistency reconciles the supply of an asset group by comparing
+// its issuance proofs with the issuance universe root, subtracting all known
+// burns and checking the outstanding units against the units held in the
+// unspent outputs known to the universe and the local node.
+func (r *rpcServer) VerifySupplyConsistency(ctx context.Context,
+	req *taprpc.VerifySupplyConsistencyRequest) (
+	*taprpc.VerifySupplyConsistencyResponse, error) {
+
+	groupKey, err := btcec.ParsePubKey(req.GroupKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group key: %w", err)
+	}
+
+	issuanceID := universe.Identifier{
+		GroupKey:  groupKey,
+		ProofType: universe.ProofTypeIssuance,
+	}
+	issuanceRoot, err := r.cfg.BaseUniverse.RootNode(ctx, issuanceID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch issuance universe "+
+			"root: %w", err)
+	}
+	issuanceLeaves, err := r.cfg.BaseUniverse.MintingLeaves(
+		ctx, issuanceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch issuance proofs: %w",
+			err)
+	}
+
+	// Burns can be known to the transfer universe of the group, if they
+	// were pushed there, or to the local node, if it burned them itself.
+	// The transfers also tell which outputs of the group were spent.
+	var burns []universe.SupplyBurn
+	transferLeaves, err := r.cfg.BaseUniverse.MintingLeaves(
+		ctx, universe.Identifier{
+			GroupKey:  groupKey,
+			ProofType: universe.ProofTypeTransfer,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch transfer proofs: %w",
+			err)
+	}
+	for _, leaf := range transferLeaves {
+		if leaf.Proof == nil || !leaf.Proof.Asset.IsBurn() {
+			continue
+		}
+
+		burns = append(burns, universe.SupplyBurn{
+			OutPoint: leaf.Proof.OutPoint(),
+			Asset:    &leaf.Proof.Asset,
+		})
+	}
+
+	constraints := tapfreighter.CommitmentConstraints{
+		GroupKey: groupKey,
+	}
+	localAssets, err := r.cfg.AssetStore.FetchAllAssets(
+		ctx, true, true, &tapdb.AssetQueryFilters{
+			CommitmentConstraints: constraints,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch local assets: %w", err)
+	}
+	var localOutputs []universe.SupplyOutput
+	for _, localAsset := range localAssets {
+		output := universe.SupplyOutput{
+			OutPoint: localAsset.AnchorOutpoint,
+			Asset:    localAsset.Asset,
+		}
+
+		switch {
+		case localAsset.IsBurn():
+			burns = append(burns, output)
+
+		case !localAsset.IsSpent:
+			localOutputs = append(localOutputs, output)
+		}
+	}
+
+	report := universe.ReconcileSupply(
+		groupKey, issuanceRoot.NodeSum(), issuanceLeaves,
+		transferLeaves, burns, localOutputs,
+	)
+
+	return &taprpc.VerifySupplyConsistencyResponse{
+		GroupKey:          groupKey.SerializeCompressed(),
+		IssuanceRootSum:   report.IssuanceRootSum,
+		IssuedAmt:         report.IssuedAmt,
+		NumIssuances:      report.NumIssuances,
+		BurnedAmt:         report.BurnedAmt,
+		NumBurns:          report.NumBurns,
+		LocalBalance:      report.LocalBalance,
+		OutstandingAmt:    report.OutstandingAmt(),
+		UnspentAmt:        report.UnspentAmt,
+		NumUnspentOutputs: report.NumUnspentOutputs,
+		Consistent:        report.Consistent(),
+		Discrepancies:     report.Discrepancies,
+	}, nil
+}
+
+// ListGroups lists known groups and the assets held in each group.
+func (r *rpcServer) ListGroups(ctx context.Context,
+	_ *taprpc.ListGroupsRequest) (*taprpc.ListGroupsResponse, error) {
+
+	readableAssets, err := r.cfg.AssetStore.FetchGroupedAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groupsWithAssets := make(map[string]*taprpc.GroupedAssets)
+
+	// Populate the map of group keys to assets in that group.
+	for _, a := range readableAssets {
+		groupKey := hex.EncodeToString(a.GroupKey.SerializeCompressed())
+
+		assetVersion, err := taprpc.MarshalAssetVersion(
+			a.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		asset := &taprpc.AssetHumanReadable{
+			Id:               a.ID[:],
+			Version:          assetVersion,
+			Amount:           a.Amount,
+			LockTime:         int32(a.LockTime),
+			RelativeLockTime: int32(a.RelativeLockTime),
+			Tag:              a.Tag,
+			MetaHash:         a.MetaHash[:],
+			Type:             taprpc.AssetType(a.Type),
+		}
+
+		_, ok := groupsWithAssets[groupKey]
+		if !ok {
+			groupsWithAssets[groupKey] = &taprpc.GroupedAssets{
+				Assets: []*taprpc.AssetHumanReadable{},
+			}
+		}
+
+		groupsWithAssets[groupKey].Assets = append(
+			groupsWithAssets[groupKey].Assets, asset,
+		)
+	}
+
+	return &taprpc.ListGroupsResponse{Groups: groupsWithAssets}, nil
+}
+
+// ListBalances lists the asset balances owned by the daemon.
+func (r *rpcServer) ListBalances(ctx context.Context,
 	req *taprpc.ListBalancesRequest) (*taprpc.ListBalancesResponse, error) {
 
+	// The balances are only returned to the caller, so they can be read
+	// from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
 	switch groupBy := req.GroupBy.(type) {
 	case *taprpc.ListBalancesRequest_AssetId:
 		if !groupBy.AssetId {
@@ -882,12 +1520,74 @@ func (r *rpcServer) ListBalances(ctx context.Context,
 	}
 }
 
-// ListTransfers lists all asset transfers managed by this deamon.
+// RebuildBalances re-derives the amounts of all owned, unspent assets from
+// their stored proofs and repairs the recorded amounts that differ.
+func (r *rpcServer) RebuildBalances(ctx context.Context,
+	req *taprpc.RebuildBalancesRequest) (*taprpc.RebuildBalancesResponse,
+	error) {
+
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
+	report, err := r.cfg.AssetStore.RebuildBalances(
+		ctx, headerVerifier, req.DryRun,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to rebuild balances: %w", err)
+	}
+
+	resp := &taprpc.RebuildBalancesResponse{
+		NumAssets: uint32(report.NumAssets),
+	}
+	for idx := range report.AssetDiscrepancies {
+		d := report.AssetDiscrepancies[idx]
+		scriptKey := d.ScriptKey.SerializeCompressed()
+		resp.Discrepancies = append(
+			resp.Discrepancies, &taprpc.AssetBalanceDiscrepancy{
+				Type:           d.Type.String(),
+				AssetId:        fn.ByteSlice(d.AssetID),
+				ScriptKey:      scriptKey,
+				AnchorOutpoint: d.AnchorPoint.String(),
+				IndexedAmount:  d.IndexedAmount,
+				ProofAmount:    d.ProofAmount,
+				Repaired:       d.Repaired,
+				Details:        d.Details,
+			},
+		)
+	}
+	for idx := range report.BalanceDiscrepancies {
+		d := report.BalanceDiscrepancies[idx]
+		resp.BalanceChanges = append(
+			resp.BalanceChanges, &taprpc.BalanceChange{
+				AssetId:    fn.ByteSlice(d.AssetID),
+				OldBalance: d.OldBalance,
+				NewBalance: d.NewBalance,
+			},
+		)
+	}
+
+	return resp, nil
+}
+
+// ListTransfers lists all asset transfers managed by this deamon, optionally
+// only the ones whose label contains the given filter.
 func (r *rpcServer) ListTransfers(ctx context.Context,
-	_ *taprpc.ListTransfersRequest) (*taprpc.ListTransfersResponse,
+	req *taprpc.ListTransfersRequest) (*taprpc.ListTransfersResponse,
 	error) {
 
-	parcels, err := r.cfg.AssetStore.QueryParcels(ctx, false)
+	// The transfers are only returned to the caller, so they can be read
+	// from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
+	var (
+		parcels []*tapfreighter.OutboundParcel
+		err     error
+	)
+	if req.LabelFilter != "" {
+		parcels, err = r.cfg.AssetStore.QueryParcelsByLabel(
+			ctx, req.LabelFilter,
+		)
+	} else {
+		parcels, err = r.cfg.AssetStore.QueryParcels(ctx, false)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to query parcels: %w", err)
 	}
@@ -907,785 +1607,2544 @@ func (r *rpcServer) ListTransfers(ctx context.Context,
 	return resp, nil
 }
 
-// QueryAddrs queries the set of Taproot Asset addresses stored in the database.
-func (r *rpcServer) QueryAddrs(ctx context.Context,
-	req *taprpc.QueryAddrRequest) (*taprpc.QueryAddrResponse, error) {
+// ListTransfersByScriptKey lists all inbound and outbound asset transfers that
+// involve the given script key, ordered by the time they were first seen. Next
+// to the outbound transfers and the inbound transfers to addresses, the assets
+// that were received with the script key without an address are listed as
+// inbound transfers as well.
+func (r *rpcServer) ListTransfersByScriptKey(ctx context.Context,
+	req *taprpc.ListTransfersByScriptKeyRequest) (
+	*taprpc.ListTransfersByScriptKeyResponse, error) {
 
-	query := address.QueryParams{
-		Limit:  req.Limit,
-		Offset: req.Offset,
-	}
+	// The transfers are only returned to the caller, so they can be read
+	// from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
 
-	// The unix time of 0 (1970-01-01) is not the same as an empty Time
-	// struct (0000-00-00). For our query to succeed, we need to set the
-	// time values the way the address book expects them.
-	if req.CreatedBefore > 0 {
-		query.CreatedBefore = time.Unix(req.CreatedBefore, 0)
+	scriptKey, err := parseUserKey(req.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script key: %w", err)
 	}
-	if req.CreatedAfter > 0 {
-		query.CreatedAfter = time.Unix(req.CreatedAfter, 0)
+
+	// We compare all keys in their x-only form, as that's what ends up in
+	// the asset leaf and the parity of the key is irrelevant.
+	xOnlyKey := schnorr.SerializePubKey(scriptKey)
+
+	// A transfer entry is tracked together with its timestamp so we can
+	// merge the inbound and outbound transfers into a single history.
+	type transferEntry struct {
+		timestamp int64
+		transfer  *taprpc.ScriptKeyTransfer
 	}
+	var entries []transferEntry
 
-	rpcsLog.Debugf("[QueryAddrs]: addr query params: %v",
-		spew.Sdump(query))
+	// The outpoints of all transfers we list as outbound or inbound
+	// transfers to an address, so we don't list the assets they created
+	// as received a second time.
+	knownOutpoints := make(map[wire.OutPoint]struct{})
 
-	dbAddrs, err := r.cfg.AddrBook.ListAddrs(ctx, query)
+	// We start with the outbound transfers, which either spend an input
+	// or create an output with the script key.
+	parcels, err := r.cfg.AssetStore.QueryParcelsByScriptKey(ctx, scriptKey)
 	if err != nil {
-		return nil, fmt.Errorf("unable to query addrs: %w", err)
+		return nil, fmt.Errorf("failed to query parcels: %w", err)
 	}
 
-	// TODO(roasbeef): just stop storing the hrp in the addr?
-	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
-
-	addrs := make([]*taprpc.Addr, len(dbAddrs))
-	for i, dbAddr := range dbAddrs {
-		dbAddr.ChainParams = &tapParams
+	for idx := range parcels {
+		parcel := parcels[idx]
+		for _, out := range parcel.Outputs {
+			knownOutpoints[out.Anchor.OutPoint] = struct{}{}
+		}
 
-		addrs[i], err = marshalAddr(dbAddr.Tap, r.cfg.TapAddrBook)
+		rpcParcel, err := marshalOutboundParcel(parcel)
 		if err != nil {
-			return nil, fmt.Errorf("unable to marshal addr: %w",
+			return nil, fmt.Errorf("failed to marshal parcel: %w",
 				err)
 		}
+
+		entries = append(entries, transferEntry{
+			timestamp: parcel.TransferTime.Unix(),
+			transfer: &taprpc.ScriptKeyTransfer{
+				Transfer: &taprpc.ScriptKeyTransfer_Outbound{
+					Outbound: rpcParcel,
+				},
+			},
+		})
 	}
 
-	rpcsLog.Debugf("[QueryAddrs]: returning %v addrs", len(addrs))
+	// The inbound transfers are the events of all addresses that use the
+	// script key.
+	events, err := r.cfg.AddrBook.QueryEvents(
+		ctx, address.EventQueryParams{
+			ScriptKey: xOnlyKey,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error querying events: %w", err)
+	}
 
-	return &taprpc.QueryAddrResponse{
-		Addrs: addrs,
-	}, nil
-}
+	for _, event := range events {
+		knownOutpoints[event.Outpoint] = struct{}{}
 
-// NewAddr makes a new address from the set of request params.
-func (r *rpcServer) NewAddr(ctx context.Context,
-	req *taprpc.NewAddrRequest) (*taprpc.Addr, error) {
+		rpcEvent, err := marshalAddrEvent(event, r.cfg.TapAddrBook)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling event: %w",
+				err)
+		}
 
-	var err error
+		entries = append(entries, transferEntry{
+			timestamp: event.CreationTime.Unix(),
+			transfer: &taprpc.ScriptKeyTransfer{
+				Transfer: &taprpc.ScriptKeyTransfer_Inbound{
+					Inbound: rpcEvent,
+				},
+			},
+		})
+	}
 
-	// Parse the proof courier address if one was provided, otherwise use
-	// the default specified in the config.
-	courierAddr := r.cfg.DefaultProofCourierAddr
-	if req.ProofCourierAddr != "" {
-		addr, err := proof.ParseCourierAddrString(
-			req.ProofCourierAddr,
-		)
+	// Finally, any asset locked to the script key that wasn't created by
+	// one of the transfers above and wasn't minted by us was received
+	// without an address.
+	assets, err := r.cfg.AssetStore.FetchAssetsByScriptKey(ctx, scriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching assets: %w", err)
+	}
+
+	for _, a := range assets {
+		if _, ok := knownOutpoints[a.AnchorOutpoint]; ok {
+			continue
+		}
+		if a.IsGenesisAsset() {
+			continue
+		}
+
+		rpcAsset, err := r.marshalChainAsset(ctx, a, false)
 		if err != nil {
-			return nil, fmt.Errorf("invalid proof courier "+
-				"address: %w", err)
+			return nil, fmt.Errorf("error marshaling asset: %w",
+				err)
 		}
 
-		// At this point, we do not intend on creating a proof courier
-		// service instance. We are only interested in parsing and
-		// validating the address. We therefore convert the address into
-		// an url.URL type for storage in the address book.
-		courierAddr = addr.Url()
+		entries = append(entries, transferEntry{
+			timestamp: a.AcquiredAt.Unix(),
+			transfer: &taprpc.ScriptKeyTransfer{
+				Transfer: &taprpc.ScriptKeyTransfer_Received{
+					Received: rpcAsset,
+				},
+			},
+		})
 	}
 
-	// Check that the proof courier address is set. This should never
-	// happen, but we check anyway to avoid panics (possibly caused by
-	// future erroneous config changes).
-	if courierAddr == nil {
-		return nil, fmt.Errorf("no proof courier address provided")
-	}
-	proofCourierAddr := *courierAddr
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].timestamp < entries[j].timestamp
+	})
 
-	if len(req.AssetId) != 32 {
-		return nil, fmt.Errorf("invalid asset id length")
+	resp := &taprpc.ListTransfersByScriptKeyResponse{
+		Transfers: make([]*taprpc.ScriptKeyTransfer, len(entries)),
+	}
+	for idx := range entries {
+		resp.Transfers[idx] = entries[idx].transfer
 	}
 
-	var assetID asset.ID
-	copy(assetID[:], req.AssetId)
+	return resp, nil
+}
 
-	rpcsLog.Infof("[NewAddr]: making new addr: asset_id=%x, amt=%v",
-		assetID[:], req.Amt)
+// GetTransferGraph returns an outbound transfer together with the flow of
+// asset amounts from its inputs to the outputs they contributed to.
+func (r *rpcServer) GetTransferGraph(ctx context.Context,
+	req *taprpc.GetTransferGraphRequest) (*taprpc.GetTransferGraphResponse,
+	error) {
+
+	// The graph is only returned to the caller, so the transfers can be
+	// read from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
 
-	err = r.checkBalanceOverflow(ctx, &assetID, nil, req.Amt)
+	anchorTXID, err := chainhash.NewHashFromStr(req.AnchorTxid)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid anchor txid: %w", err)
 	}
 
-	// Was there a tapscript sibling preimage specified?
-	tapscriptSibling, _, err := commitment.MaybeDecodeTapscriptPreimage(
-		req.TapscriptSibling,
-	)
+	parcels, err := r.cfg.AssetStore.QueryParcels(ctx, false)
 	if err != nil {
-		return nil, fmt.Errorf("invalid tapscript sibling: %w", err)
+		return nil, fmt.Errorf("failed to query parcels: %w", err)
 	}
 
-	assetVersion, err := taprpc.UnmarshalAssetVersion(req.AssetVersion)
+	var parcel *tapfreighter.OutboundParcel
+	for idx := range parcels {
+		if parcels[idx].AnchorTx.TxHash() == *anchorTXID {
+			parcel = parcels[idx]
+			break
+		}
+	}
+	if parcel == nil {
+		return nil, fmt.Errorf("no transfer found for anchor txid %v",
+			anchorTXID)
+	}
+
+	edges, err := tapfreighter.TransferGraph(parcel)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to build transfer graph: %w",
+			err)
 	}
 
-	var addr *address.AddrWithKeyInfo
-	switch {
-	// No key was specified, we'll let the address book derive them.
-	case req.ScriptKey == nil && req.InternalKey == nil:
-		// Now that we have all the params, we'll try to add a new
-		// address to the addr book.
-		addr, err = r.cfg.AddrBook.NewAddress(
-			ctx, assetID, req.Amt, tapscriptSibling,
-			proofCourierAddr,
-			address.WithAssetVersion(assetVersion),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to make new addr: %w",
-				err)
+	rpcParcel, err := marshalOutboundParcel(parcel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal parcel: %w", err)
+	}
+
+	resp := &taprpc.GetTransferGraphResponse{
+		Transfer: rpcParcel,
+		Edges:    make([]*taprpc.TransferEdge, len(edges)),
+	}
+	for idx, edge := range edges {
+		resp.Edges[idx] = &taprpc.TransferEdge{
+			InputIndex:  uint32(edge.InputIndex),
+			OutputIndex: uint32(edge.OutputIndex),
+			Amount:      edge.Amount,
 		}
+	}
 
-	// Only the script key was specified.
-	case req.ScriptKey != nil && req.InternalKey == nil:
-		return nil, fmt.Errorf("internal key must also be specified " +
-			"if script key is specified")
+	return resp, nil
+}
 
-	// Only the internal key was specified.
-	case req.ScriptKey == nil && req.InternalKey != nil:
-		return nil, fmt.Errorf("script key must also be specified " +
-			"if internal key is specified")
+// GetTransferMetrics returns latency percentiles and throughput figures for
+// the outbound asset transfers initiated within the requested time window.
+func (r *rpcServer) GetTransferMetrics(ctx context.Context,
+	req *taprpc.GetTransferMetricsRequest) (*taprpc.GetTransferMetricsResponse,
+	error) {
 
-	// Both the script and internal keys were specified.
-	default:
-		scriptKey, err := UnmarshalScriptKey(req.ScriptKey)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode script key: "+
-				"%w", err)
-		}
+	// The metrics are only returned to the caller, so the transfers can
+	// be read from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
 
-		rpcsLog.Debugf("Decoded script key %x (internal %x, tweak %x)",
-			scriptKey.PubKey.SerializeCompressed(),
-			scriptKey.RawKey.PubKey.SerializeCompressed(),
-			scriptKey.Tweak[:])
+	var start time.Time
+	if req.StartTimestamp > 0 {
+		start = time.Unix(req.StartTimestamp, 0)
+	}
 
-		internalKey, err := UnmarshalKeyDescriptor(req.InternalKey)
-		if err != nil {
-			return nil, fmt.Errorf("unable to decode internal "+
-				"key: %w", err)
-		}
+	end := time.Now()
+	if req.EndTimestamp > 0 {
+		end = time.Unix(req.EndTimestamp, 0)
+	}
 
-		// Now that we have all the params, we'll try to add a new
-		// address to the addr book.
-		addr, err = r.cfg.AddrBook.NewAddressWithKeys(
-			ctx, assetID, req.Amt, *scriptKey, internalKey,
-			tapscriptSibling, proofCourierAddr,
-			address.WithAssetVersion(assetVersion),
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to make new addr: %w",
-				err)
-		}
+	if !start.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end timestamp must not be before " +
+			"start timestamp")
 	}
 
-	// With our addr obtained, we'll marshal it as an RPC message then send
-	// off the response.
-	rpcAddr, err := marshalAddr(addr.Tap, r.cfg.TapAddrBook)
+	parcels, err := r.cfg.AssetStore.QueryParcels(ctx, false)
 	if err != nil {
-		return nil, fmt.Errorf("unable to marshal addr: %w", err)
+		return nil, fmt.Errorf("failed to query parcels: %w", err)
 	}
 
-	return rpcAddr, nil
+	metrics := tapfreighter.CalcTransferMetrics(parcels, start, end)
+
+	// If there were no transfers at all, we report the requested window
+	// as is.
+	var startUnix int64
+	if !metrics.Start.IsZero() {
+		startUnix = metrics.Start.Unix()
+	}
+
+	return &taprpc.GetTransferMetricsResponse{
+		StartTimestamp:   startUnix,
+		EndTimestamp:     metrics.End.Unix(),
+		NumTransfers:     uint32(metrics.NumTransfers),
+		NumCompleted:     uint32(metrics.NumCompleted),
+		CompletedPerHour: metrics.CompletedPerHour,
+		ConfirmationLatency: marshalLatencyPercentiles(
+			metrics.ConfirmationLatency,
+		),
+		DeliveryLatency: marshalLatencyPercentiles(
+			metrics.DeliveryLatency,
+		),
+	}, nil
 }
 
-// DecodeAddr decode a Taproot Asset address into a partial asset message that
-// represents the asset it wants to receive.
-func (r *rpcServer) DecodeAddr(_ context.Context,
-	req *taprpc.DecodeAddrRequest) (*taprpc.Addr, error) {
+// AnchorSweepStatus returns the status of the background sweeper of anchor
+// outputs that only anchor spent assets, together with the anchor outputs that
+// are waiting to be swept and the ones that were already swept.
+func (r *rpcServer) AnchorSweepStatus(ctx context.Context,
+	_ *taprpc.AnchorSweepStatusRequest) (*taprpc.AnchorSweepStatusResponse,
+	error) {
 
-	if len(req.Addr) == 0 {
-		return nil, fmt.Errorf("must specify an addr")
+	status, err := r.cfg.ChainPorter.AnchorSweepStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch anchor sweep status: "+
+			"%w", err)
 	}
 
-	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+	resp := &taprpc.AnchorSweepStatusResponse{
+		Enabled: status.Policy != nil,
+		Backlog: make([]*taprpc.SweepableAnchor, len(status.Backlog)),
+		Sweeps:  make([]*taprpc.AnchorSweep, len(status.Sweeps)),
+	}
+	if status.Policy != nil {
+		resp.MaxOutputValueSats = int64(status.Policy.MaxOutputValue)
+		resp.MaxFeeRateSatPerVbyte = uint64(
+			status.Policy.MaxFeeRate.FeePerKVByte() / 1000,
+		)
+		resp.FeeBudgetPercent = status.Policy.FeeBudget
+	}
+	if !status.LastAttempt.IsZero() {
+		resp.LastAttempt = status.LastAttempt.Unix()
+	}
+	if status.LastError != nil {
+		resp.LastError = status.LastError.Error()
+	}
 
-	addr, err := address.DecodeAddress(req.Addr, &tapParams)
-	if err != nil {
-		return nil, fmt.Errorf("unable to decode addr: %w", err)
+	for idx, anchor := range status.Backlog {
+		resp.Backlog[idx] = &taprpc.SweepableAnchor{
+			Outpoint: anchor.OutPoint.String(),
+			AmtSats:  int64(anchor.Value),
+		}
+		resp.BacklogSats += int64(anchor.Value)
 	}
 
-	rpcAddr, err := marshalAddr(addr, r.cfg.TapAddrBook)
-	if err != nil {
-		return nil, fmt.Errorf("unable to marshal addr: %w", err)
+	for idx, sweep := range status.Sweeps {
+		resp.Sweeps[idx] = &taprpc.AnchorSweep{
+			Outpoint:  sweep.OutPoint.String(),
+			AmtSats:   int64(sweep.Value),
+			SweepTxid: sweep.SweepTXID.String(),
+			SweptAt:   sweep.SweptAt.Unix(),
+		}
 	}
 
-	return rpcAddr, nil
+	return resp, nil
 }
 
-// VerifyProof attempts to verify a given proof file that claims to be anchored
-// at the specified genesis point.
-func (r *rpcServer) VerifyProof(ctx context.Context,
-	req *taprpc.ProofFile) (*taprpc.VerifyProofResponse, error) {
+// marshalLatencyPercentiles converts a set of latency percentiles to its RPC
+// counterpart.
+func marshalLatencyPercentiles(
+	p tapfreighter.LatencyPercentiles) *taprpc.LatencyPercentiles {
 
-	if !proof.IsProofFile(req.RawProofFile) {
-		return nil, fmt.Errorf("invalid raw proof, expect file, not " +
-			"single encoded mint or transition proof")
+	return &taprpc.LatencyPercentiles{
+		NumSamples: uint32(p.NumSamples),
+		P50Ms:      p.P50.Milliseconds(),
+		P90Ms:      p.P90.Milliseconds(),
+		P99Ms:      p.P99.Milliseconds(),
 	}
+}
 
-	if err := proof.CheckMaxFileSize(req.RawProofFile); err != nil {
-		return nil, fmt.Errorf("invalid proof file: %w", err)
-	}
+// QueryAddrs queries the set of Taproot Asset addresses stored in the database.
+func (r *rpcServer) QueryAddrs(ctx context.Context,
+	req *taprpc.QueryAddrRequest) (*taprpc.QueryAddrResponse, error) {
 
-	var proofFile proof.File
-	err := proofFile.Decode(bytes.NewReader(req.RawProofFile))
-	if err != nil {
-		return nil, fmt.Errorf("unable to decode proof file: %w", err)
+	query := address.QueryParams{
+		Limit:  req.Limit,
+		Offset: req.Offset,
 	}
 
-	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
-	groupVerifier := tapgarden.GenGroupVerifier(ctx, r.cfg.MintingStore)
-	_, err = proofFile.Verify(ctx, headerVerifier, groupVerifier)
-	if err != nil {
-		// We don't want to fail the RPC request because of a proof
-		// verification error, but we do want to log it for easier
-		// debugging.
-		rpcsLog.Errorf("Proof verification failed with err: %v", err)
+	// The unix time of 0 (1970-01-01) is not the same as an empty Time
+	// struct (0000-00-00). For our query to succeed, we need to set the
+	// time values the way the address book expects them.
+	if req.CreatedBefore > 0 {
+		query.CreatedBefore = time.Unix(req.CreatedBefore, 0)
+	}
+	if req.CreatedAfter > 0 {
+		query.CreatedAfter = time.Unix(req.CreatedAfter, 0)
 	}
-	valid := err == nil
 
-	p, err := proofFile.LastProof()
+	rpcsLog.Debugf("[QueryAddrs]: addr query params: %v",
+		spew.Sdump(query))
+
+	dbAddrs, err := r.cfg.AddrBook.ListAddrs(ctx, query)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to query addrs: %w", err)
 	}
-	decodedProof, err := r.marshalProof(ctx, p, false, false)
-	if err != nil {
-		return nil, fmt.Errorf("unable to marshal proof: %w", err)
+
+	// TODO(roasbeef): just stop storing the hrp in the addr?
+	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+
+	addrs := make([]*taprpc.Addr, len(dbAddrs))
+	for i, dbAddr := range dbAddrs {
+		dbAddr.ChainParams = &tapParams
+
+		addrs[i], err = marshalAddr(dbAddr.Tap, r.cfg.TapAddrBook)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal addr: %w",
+				err)
+		}
 	}
 
-	decodedProof.ProofAtDepth = 0
-	decodedProof.NumberOfProofs = uint32(proofFile.NumProofs())
+	rpcsLog.Debugf("[QueryAddrs]: returning %v addrs", len(addrs))
 
-	return &taprpc.VerifyProofResponse{
-		Valid:        valid,
-		DecodedProof: decodedProof,
+	return &taprpc.QueryAddrResponse{
+		Addrs: addrs,
 	}, nil
 }
 
-// DecodeProof attempts to decode a given proof file that claims to be anchored
-// at the specified genesis point.
-func (r *rpcServer) DecodeProof(ctx context.Context,
-	req *taprpc.DecodeProofRequest) (*taprpc.DecodeProofResponse, error) {
+// NewAddr makes a new address from the set of request params.
+func (r *rpcServer) NewAddr(ctx context.Context,
+	req *taprpc.NewAddrRequest) (*taprpc.Addr, error) {
 
-	var (
-		proofReader = bytes.NewReader(req.RawProof)
-		rpcProof    *taprpc.DecodedProof
-	)
+	params, err := r.unmarshalNewAddrParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[NewAddr]: making new addr: asset_id=%x, amt=%v",
+		params.AssetID[:], req.Amt)
+
+	var addr *address.AddrWithKeyInfo
 	switch {
-	case proof.IsSingleProof(req.RawProof):
-		var p proof.Proof
-		err := p.Decode(proofReader)
+	// No idempotency key was specified, so we always create a new address.
+	case req.IdempotencyKey == "":
+		addr, err = r.cfg.AddrBook.NewAddressFromParams(ctx, *params)
+
+	// An idempotency key was specified, so we return the address that was
+	// already created with the same key and parameters, if there is one.
+	default:
+		var paramsHash []byte
+		paramsHash, err = newAddrParamsHash(req)
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode proof: %w",
-				err)
+			return nil, err
 		}
 
-		rpcProof, err = r.marshalProof(
-			ctx, &p, req.WithPrevWitnesses, req.WithMetaReveal,
+		addr, err = r.cfg.AddrBook.IdempotentAddress(
+			ctx, req.IdempotencyKey, paramsHash, *params,
 		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to marshal proof: %w",
-				err)
-		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to make new addr: %w", err)
+	}
 
-		rpcProof.NumberOfProofs = 1
+	// With our addr obtained, we'll marshal it as an RPC message then send
+	// off the response.
+	rpcAddr, err := marshalAddr(addr.Tap, r.cfg.TapAddrBook)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal addr: %w", err)
+	}
 
-	case proof.IsProofFile(req.RawProof):
-		if err := proof.CheckMaxFileSize(req.RawProof); err != nil {
-			return nil, fmt.Errorf("invalid proof file: %w", err)
-		}
+	return rpcAddr, nil
+}
 
-		var proofFile proof.File
-		if err := proofFile.Decode(proofReader); err != nil {
-			return nil, fmt.Errorf("unable to decode proof file: "+
-				"%w", err)
-		}
+// NewAddrBatch makes a new address for each of the given sets of request
+// params. The addresses are created atomically, so if any of the requests is
+// invalid, none of the addresses are created.
+func (r *rpcServer) NewAddrBatch(ctx context.Context,
+	req *taprpc.NewAddrBatchRequest) (*taprpc.NewAddrBatchResponse, error) {
 
-		latestProofIndex := uint32(proofFile.NumProofs() - 1)
-		if req.ProofAtDepth > latestProofIndex {
-			return nil, fmt.Errorf("invalid depth %d is greater "+
-				"than latest proof index of %d",
-				req.ProofAtDepth, latestProofIndex)
+	if len(req.Addrs) == 0 {
+		return nil, fmt.Errorf("at least one address must be " +
+			"specified")
+	}
+
+	params := make([]address.NewAddrParams, len(req.Addrs))
+	for idx, addrReq := range req.Addrs {
+		if addrReq.IdempotencyKey != "" {
+			return nil, fmt.Errorf("address %d: idempotency keys "+
+				"are not supported in a batch", idx)
 		}
 
-		// Default to latest proof.
-		index := latestProofIndex - req.ProofAtDepth
-		p, err := proofFile.ProofAt(index)
+		addrParams, err := r.unmarshalNewAddrParams(ctx, addrReq)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("address %d: %w", idx, err)
 		}
+		params[idx] = *addrParams
+	}
 
-		rpcProof, err = r.marshalProof(
-			ctx, p, req.WithPrevWitnesses,
-			req.WithMetaReveal,
-		)
+	rpcsLog.Infof("[NewAddrBatch]: making %d new addrs", len(params))
+
+	addrs, err := r.cfg.AddrBook.NewAddressBatch(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make new addrs: %w", err)
+	}
+
+	resp := &taprpc.NewAddrBatchResponse{
+		Addrs: make([]*taprpc.Addr, len(addrs)),
+	}
+	for idx, addr := range addrs {
+		resp.Addrs[idx], err = marshalAddr(addr.Tap, r.cfg.TapAddrBook)
 		if err != nil {
-			return nil, fmt.Errorf("unable to marshal proof: %w",
+			return nil, fmt.Errorf("unable to marshal addr: %w",
 				err)
 		}
-
-		rpcProof.ProofAtDepth = req.ProofAtDepth
-		rpcProof.NumberOfProofs = uint32(proofFile.NumProofs())
-
-	default:
-		return nil, fmt.Errorf("invalid raw proof, could not " +
-			"identify decoding format")
 	}
 
-	return &taprpc.DecodeProofResponse{
-		DecodedProof: rpcProof,
-	}, nil
+	return resp, nil
 }
 
-// marshalProof turns a transition proof into an RPC DecodedProof.
-func (r *rpcServer) marshalProof(ctx context.Context, p *proof.Proof,
-	withPrevWitnesses, withMetaReveal bool) (*taprpc.DecodedProof, error) {
+// unmarshalNewAddrParams validates the given address request and converts it
+// into the params the address book creates the address from.
+func (r *rpcServer) unmarshalNewAddrParams(ctx context.Context,
+	req *taprpc.NewAddrRequest) (*address.NewAddrParams, error) {
 
-	var (
-		rpcMeta        *taprpc.AssetMeta
-		rpcGenesis     = p.GenesisReveal
-		rpcGroupKey    = p.GroupKeyReveal
-		anchorOutpoint = wire.OutPoint{
-			Hash:  p.AnchorTx.TxHash(),
-			Index: p.InclusionProof.OutputIndex,
+	// Parse the proof courier address if one was provided, otherwise use
+	// the default specified in the config.
+	courierAddr := r.cfg.DefaultProofCourierAddr
+	if req.ProofCourierAddr != "" {
+		addr, err := proof.ParseCourierAddrString(
+			req.ProofCourierAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof courier "+
+				"address: %w", err)
 		}
-		txMerkleProof  = p.TxMerkleProof
-		inclusionProof = p.InclusionProof
-		splitRootProof = p.SplitRootProof
-	)
 
-	var txMerkleProofBuf bytes.Buffer
-	if err := txMerkleProof.Encode(&txMerkleProofBuf); err != nil {
-		return nil, fmt.Errorf("unable to encode serialized Bitcoin "+
-			"merkle proof: %w", err)
+		// At this point, we do not intend on creating a proof courier
+		// service instance. We are only interested in parsing and
+		// validating the address. We therefore convert the address into
+		// an url.URL type for storage in the address book.
+		courierAddr = addr.Url()
 	}
 
-	var inclusionProofBuf bytes.Buffer
-	if err := inclusionProof.Encode(&inclusionProofBuf); err != nil {
-		return nil, fmt.Errorf("unable to encode inclusion proof: %w",
-			err)
+	// Check that the proof courier address is set. This should never
+	// happen, but we check anyway to avoid panics (possibly caused by
+	// future erroneous config changes).
+	if courierAddr == nil {
+		return nil, fmt.Errorf("no proof courier address provided")
 	}
 
-	if inclusionProof.CommitmentProof == nil {
-		return nil, fmt.Errorf("inclusion proof is missing " +
-			"commitment proof")
+	if len(req.AssetId) != 32 {
+		return nil, fmt.Errorf("invalid asset id length")
 	}
-	tsSibling, tsHash, err := commitment.MaybeEncodeTapscriptPreimage(
-		inclusionProof.CommitmentProof.TapSiblingPreimage,
-	)
+
+	params := &address.NewAddrParams{
+		Amount:           req.Amt,
+		ProofCourierAddr: *courierAddr,
+	}
+	copy(params.AssetID[:], req.AssetId)
+
+	err := address.CheckAmount(req.Amt, r.cfg.MaxAddrAmount)
 	if err != nil {
-		return nil, fmt.Errorf("error encoding tapscript sibling: %w",
-			err)
+		return nil, err
 	}
 
-	tapProof, err := inclusionProof.CommitmentProof.DeriveByAssetInclusion(
-		&p.Asset,
+	err = r.checkBalanceOverflow(ctx, &params.AssetID, nil, req.Amt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Was there a tapscript sibling preimage specified?
+	tapscriptSibling, _, err := commitment.MaybeDecodeTapscriptPreimage(
+		req.TapscriptSibling,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error deriving inclusion proof: %w",
-			err)
+		return nil, fmt.Errorf("invalid tapscript sibling: %w", err)
 	}
-	merkleRoot := tapProof.TapscriptRoot(tsHash)
+	params.TapscriptSibling = tapscriptSibling
 
-	var exclusionProofs [][]byte
-	for _, exclusionProof := range p.ExclusionProofs {
-		var exclusionProofBuf bytes.Buffer
-		err := exclusionProof.Encode(&exclusionProofBuf)
+	assetVersion, err := taprpc.UnmarshalAssetVersion(req.AssetVersion)
+	if err != nil {
+		return nil, err
+	}
+	params.AddrOpts = []address.NewAddrOpt{
+		address.WithAssetVersion(assetVersion),
+	}
+
+	switch {
+	case req.AllowAnyAmount:
+		err := address.CheckAmount(req.MinAmount, r.cfg.MaxAddrAmount)
 		if err != nil {
-			return nil, fmt.Errorf("unable to encode exclusion "+
-				"proofs: %w", err)
+			return nil, err
 		}
-		exclusionProofs = append(
-			exclusionProofs, exclusionProofBuf.Bytes(),
+
+		// The receiver can't locate a transfer of an unknown amount on
+		// chain, so it waits for the proof in the mailbox of the
+		// courier instead, which is only keyed by the script key.
+		if !proof.IsMailboxCourierAddr(courierAddr) {
+			return nil, fmt.Errorf("an address that allows any "+
+				"amount requires a mailbox based proof "+
+				"courier, not %v", courierAddr.Scheme)
+		}
+
+		params.AddrOpts = append(
+			params.AddrOpts, address.WithAnyAmount(req.MinAmount),
 		)
+
+	case req.MinAmount != 0:
+		return nil, fmt.Errorf("min amount can only be set if any " +
+			"amount is allowed")
 	}
 
-	var splitRootProofBuf bytes.Buffer
-	if splitRootProof != nil {
-		err := splitRootProof.Encode(&splitRootProofBuf)
+	// A tapscript tree was specified for the script key, we'll let the
+	// address book derive the keys and commit to the tree.
+	if req.ScriptKeyTapscript != nil {
+		params.ScriptKeyTapscript, err = UnmarshalScriptKeyTapscript(
+			req.ScriptKeyTapscript,
+		)
 		if err != nil {
-			return nil, fmt.Errorf("unable to encode split root "+
-				"proof: %w", err)
+			return nil, fmt.Errorf("invalid script key "+
+				"tapscript: %w", err)
 		}
 	}
 
-	rpcAsset, err := r.marshalChainAsset(ctx, &tapdb.ChainAsset{
-		Asset:                  &p.Asset,
-		AnchorTx:               &p.AnchorTx,
-		AnchorTxid:             p.AnchorTx.TxHash(),
-		AnchorBlockHash:        p.BlockHeader.BlockHash(),
-		AnchorBlockHeight:      p.BlockHeight,
-		AnchorOutpoint:         anchorOutpoint,
-		AnchorInternalKey:      p.InclusionProof.InternalKey,
-		AnchorMerkleRoot:       merkleRoot[:],
-		AnchorTapscriptSibling: tsSibling,
-	}, withPrevWitnesses)
-	if err != nil {
-		return nil, err
-	}
-
-	if withMetaReveal {
-		metaHash := rpcAsset.AssetGenesis.MetaHash
-		if len(metaHash) == 0 {
-			return nil, fmt.Errorf("asset does not contain meta " +
-				"data")
+	if req.ScriptKey != nil {
+		params.ScriptKey, err = UnmarshalScriptKey(req.ScriptKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode script key: "+
+				"%w", err)
 		}
 
-		rpcMeta, err = r.FetchAssetMeta(
-			ctx, &taprpc.FetchAssetMetaRequest{
-				Asset: &taprpc.FetchAssetMetaRequest_MetaHash{
-					MetaHash: metaHash,
-				},
-			},
-		)
+		rpcsLog.Debugf("Decoded script key %x (internal %x, tweak %x)",
+			params.ScriptKey.PubKey.SerializeCompressed(),
+			params.ScriptKey.RawKey.PubKey.SerializeCompressed(),
+			params.ScriptKey.Tweak[:])
+	}
+
+	if req.InternalKey != nil {
+		internalKey, err := UnmarshalKeyDescriptor(req.InternalKey)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("unable to decode internal "+
+				"key: %w", err)
 		}
+		params.InternalKey = &internalKey
 	}
 
-	decodedAssetID := p.Asset.ID()
-	var genesisReveal *taprpc.GenesisReveal
-	if rpcGenesis != nil {
-		genesisReveal = &taprpc.GenesisReveal{
-			GenesisBaseReveal: &taprpc.GenesisInfo{
-				GenesisPoint: rpcGenesis.FirstPrevOut.String(),
-				Name:         rpcGenesis.Tag,
-				MetaHash:     rpcGenesis.MetaHash[:],
-				AssetId:      decodedAssetID[:],
-				OutputIndex:  rpcGenesis.OutputIndex,
-			},
-			AssetType: taprpc.AssetType(p.Asset.Type),
-		}
+	// Make sure the keys were specified in a valid combination. If none
+	// were specified, the address book derives them.
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
 
-	var GroupKeyReveal taprpc.GroupKeyReveal
-	if rpcGroupKey != nil {
-		GroupKeyReveal = taprpc.GroupKeyReveal{
-			RawGroupKey:   rpcGroupKey.RawKey[:],
-			TapscriptRoot: rpcGroupKey.TapscriptRoot,
-		}
+	return params, nil
+}
+
+// newAddrParamsHash returns a hash of all parameters of the given address
+// request, except for its idempotency key.
+func newAddrParamsHash(req *taprpc.NewAddrRequest) ([]byte, error) {
+	params := proto.Clone(req).(*taprpc.NewAddrRequest)
+	params.IdempotencyKey = ""
+
+	paramBytes, err := proto.MarshalOptions{
+		Deterministic: true,
+	}.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode address params: %w",
+			err)
 	}
 
-	return &taprpc.DecodedProof{
-		Asset:               rpcAsset,
-		MetaReveal:          rpcMeta,
-		TxMerkleProof:       txMerkleProofBuf.Bytes(),
-		InclusionProof:      inclusionProofBuf.Bytes(),
-		ExclusionProofs:     exclusionProofs,
-		SplitRootProof:      splitRootProofBuf.Bytes(),
-		NumAdditionalInputs: uint32(len(p.AdditionalInputs)),
-		ChallengeWitness:    p.ChallengeWitness,
-		IsBurn:              p.Asset.IsBurn(),
-		GenesisReveal:       genesisReveal,
-		GroupKeyReveal:      &GroupKeyReveal,
-	}, nil
+	paramsHash := sha256.Sum256(paramBytes)
+	return paramsHash[:], nil
 }
 
-// ExportProof exports the latest raw proof file anchored at the specified
-// script_key.
-func (r *rpcServer) ExportProof(ctx context.Context,
-	req *taprpc.ExportProofRequest) (*taprpc.ProofFile, error) {
+// DecodeAddr decode a Taproot Asset address into a partial asset message that
+// represents the asset it wants to receive.
+func (r *rpcServer) DecodeAddr(_ context.Context,
+	req *taprpc.DecodeAddrRequest) (*taprpc.Addr, error) {
 
-	if len(req.ScriptKey) == 0 {
-		return nil, fmt.Errorf("a valid script key must be specified")
+	if len(req.Addr) == 0 {
+		return nil, fmt.Errorf("must specify an addr")
 	}
 
-	scriptKey, err := parseUserKey(req.ScriptKey)
+	addr, err := r.decodeTapAddr(req.Addr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid script key: %w", err)
+		return nil, fmt.Errorf("unable to decode addr: %w", err)
 	}
 
-	if len(req.AssetId) != 32 {
-		return nil, fmt.Errorf("asset ID must be 32 bytes")
+	err = address.CheckAmount(addr.Amount, r.cfg.MaxAddrAmount)
+	if err != nil {
+		return nil, err
 	}
 
-	var assetID asset.ID
-	copy(assetID[:], req.AssetId)
-
-	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, proof.Locator{
-		AssetID:   &assetID,
-		ScriptKey: *scriptKey,
-	})
+	rpcAddr, err := marshalAddr(addr, r.cfg.TapAddrBook)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to marshal addr: %w", err)
 	}
 
-	return &taprpc.ProofFile{
-		RawProofFile: proofBlob,
-	}, nil
+	return rpcAddr, nil
 }
 
-// ImportProof attempts to import a proof file into the daemon. If successful, a
-// new asset will be inserted on disk, spendable using the specified target
-// script key, and internal key.
-func (r *rpcServer) ImportProof(ctx context.Context,
-	req *tapdevrpc.ImportProofRequest) (*tapdevrpc.ImportProofResponse,
-	error) {
+// decodeTapAddr decodes the given Taproot Asset address for the network of the
+// daemon. If the address is for a different network, the error names the
+// network the address was created for.
+func (r *rpcServer) decodeTapAddr(encoded string) (*address.Tap, error) {
+	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
 
-	// We'll perform some basic input validation before we move forward.
-	if len(req.ProofFile) == 0 {
-		return nil, fmt.Errorf("proof file must be specified")
+	addr, err := address.DecodeAddress(encoded, &tapParams)
+	if errors.Is(err, address.ErrMismatchedHRP) {
+		addrNet, netErr := address.DecodeNet(encoded)
+		if netErr == nil {
+			return nil, fmt.Errorf("%w: address is for %v, but "+
+				"the daemon runs on %v", err, addrNet.Name,
+				tapParams.Name)
+		}
 	}
-
-	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
-	groupVerifier := tapgarden.GenGroupVerifier(ctx, r.cfg.MintingStore)
-
-	// Now that we know the proof file is at least present, we'll attempt
-	// to import it into the main archive.
-	err := r.cfg.ProofArchive.ImportProofs(
-		ctx, headerVerifier, groupVerifier, false,
-		&proof.AnnotatedProof{Blob: req.ProofFile},
-	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &tapdevrpc.ImportProofResponse{}, nil
+	return addr, nil
 }
 
-// AddrReceives lists all receives for incoming asset transfers for addresses
-// that were created previously.
-func (r *rpcServer) AddrReceives(ctx context.Context,
-	req *taprpc.AddrReceivesRequest) (*taprpc.AddrReceivesResponse,
-	error) {
-
-	var sqlQuery address.EventQueryParams
+// VerifyProof attempts to verify a given proof file or single proof. The asset
+// the proof is for does not need to be owned by this node, and neither does
+// its asset group need to be known, as long as the group anchor is part of the
+// proof.
+func (r *rpcServer) VerifyProof(ctx context.Context,
+	req *taprpc.ProofFile) (*taprpc.VerifyProofResponse, error) {
 
-	if len(req.FilterAddr) > 0 {
-		tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
+	localGroupVerifier := tapgarden.GenGroupVerifier(
+		ctx, r.cfg.MintingStore,
+	)
 
-		addr, err := address.DecodeAddress(req.FilterAddr, &tapParams)
+	var (
+		lastProof *proof.Proof
+		numProofs = 1
+		verifyErr error
+	)
+	switch {
+	case proof.IsSingleProof(req.RawProofFile):
+		p, err := proof.DecodeRaw(req.RawProofFile)
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode addr: %w", err)
+			return nil, fmt.Errorf("unable to decode proof: %w",
+				err)
 		}
 
-		// Now that we've decoded the address, we'll check to make sure
-		// that we can fetch the genesis for this address. Otherwise,
-		// that means we don't know anything about what it should look
-		// like on chain (the genesis is required to derive the taproot
-		// output key).
-		assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(
-			ctx, addr.AssetID,
+		// Without the previous proof, only genesis and ownership
+		// proofs can have their witnesses validated.
+		groupVerifier := proof.RevealedGroupVerifier(
+			localGroupVerifier, p,
+		)
+		_, verifyErr = p.VerifyWithRotations(
+			ctx, nil, headerVerifier, groupVerifier,
+			r.cfg.MintingStore.GroupKeyRotations,
 		)
+		lastProof = p
+
+	case proof.IsProofFile(req.RawProofFile):
+		err := proof.CheckMaxFileSize(req.RawProofFile)
 		if err != nil {
-			return nil, fmt.Errorf("unknown asset=%x: %w",
-				addr.AssetID[:], err)
+			return nil, fmt.Errorf("invalid proof file: %w", err)
 		}
 
-		rpcsLog.Tracef("Listing receives for group: %v",
-			spew.Sdump(assetGroup))
-
-		addr.AttachGenesis(*assetGroup.Genesis)
-
-		taprootOutputKey, err := addr.TaprootOutputKey()
+		var proofFile proof.File
+		err = proofFile.Decode(bytes.NewReader(req.RawProofFile))
 		if err != nil {
-			return nil, fmt.Errorf("error deriving Taproot key: %w",
-				err)
+			return nil, fmt.Errorf("unable to decode proof file: "+
+				"%w", err)
 		}
 
-		sqlQuery.AddrTaprootOutputKey = schnorr.SerializePubKey(
-			taprootOutputKey,
+		// The groups anchored by the file itself don't need to be
+		// known to this node.
+		fileProofs := make([]*proof.Proof, proofFile.NumProofs())
+		for idx := range fileProofs {
+			fileProofs[idx], err = proofFile.ProofAt(uint32(idx))
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode "+
+					"proof %d: %w", idx, err)
+			}
+		}
+		groupVerifier := proof.RevealedGroupVerifier(
+			localGroupVerifier, fileProofs...,
 		)
-	}
 
-	if req.FilterStatus != taprpc.AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN {
-		status, err := unmarshalAddrEventStatus(req.FilterStatus)
+		_, verifyErr = proofFile.VerifyWithRotations(
+			ctx, headerVerifier, groupVerifier,
+			r.cfg.MintingStore.GroupKeyRotations,
+		)
+
+		lastProof, err = proofFile.LastProof()
 		if err != nil {
-			return nil, fmt.Errorf("error parsing status: %w", err)
+			return nil, err
 		}
+		numProofs = proofFile.NumProofs()
 
-		sqlQuery.StatusFrom = &status
-		sqlQuery.StatusTo = &status
+	default:
+		return nil, fmt.Errorf("invalid raw proof, could not " +
+			"identify decoding format")
 	}
 
-	events, err := r.cfg.AddrBook.QueryEvents(ctx, sqlQuery)
-	if err != nil {
-		return nil, fmt.Errorf("error querying events: %w", err)
+	// We don't want to fail the RPC request because of a proof
+	// verification error, but we do want to log it for easier debugging
+	// and report it to the caller.
+	var failureReason string
+	if verifyErr != nil {
+		rpcsLog.Errorf("Proof verification failed with err: %v",
+			verifyErr)
+		failureReason = verifyErr.Error()
 	}
 
-	resp := &taprpc.AddrReceivesResponse{
-		Events: make([]*taprpc.AddrEvent, len(events)),
+	decodedProof, err := r.marshalProof(ctx, lastProof, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal proof: %w", err)
 	}
 
-	for idx, event := range events {
-		resp.Events[idx], err = marshalAddrEvent(
-			event, r.cfg.TapAddrBook,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("error marshaling event: %w",
-				err)
-		}
-	}
+	decodedProof.ProofAtDepth = 0
+	decodedProof.NumberOfProofs = uint32(numProofs)
 
-	return resp, nil
+	assetID := lastProof.Asset.ID()
+	return &taprpc.VerifyProofResponse{
+		Valid:         verifyErr == nil,
+		DecodedProof:  decodedProof,
+		FailureReason: failureReason,
+		AssetId:       assetID[:],
+		Amount:        lastProof.Asset.Amount,
+		ScriptKey: lastProof.Asset.ScriptKey.PubKey.
+			SerializeCompressed(),
+	}, nil
 }
 
-// FundVirtualPsbt selects inputs from the available asset commitments to fund
-// a virtual transaction matching the template.
-func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
-	req *wrpc.FundVirtualPsbtRequest) (*wrpc.FundVirtualPsbtResponse,
-	error) {
+// DecodeProof attempts to decode a given proof file that claims to be anchored
+// at the specified genesis point.
+func (r *rpcServer) DecodeProof(ctx context.Context,
+	req *taprpc.DecodeProofRequest) (*taprpc.DecodeProofResponse, error) {
 
-	var fundedVPkt *tapfreighter.FundedVPacket
+	var (
+		proofReader = bytes.NewReader(req.RawProof)
+		rpcProof    *taprpc.DecodedProof
+	)
 	switch {
-	case req.GetPsbt() != nil:
-		vPkt, err := tappsbt.NewFromRawBytes(
-			bytes.NewReader(req.GetPsbt()), false,
-		)
+	case proof.IsSingleProof(req.RawProof):
+		p, err := proof.DecodeRaw(req.RawProof)
 		if err != nil {
-			return nil, fmt.Errorf("unable to decode psbt: %w", err)
+			return nil, fmt.Errorf("unable to decode proof: %w",
+				err)
 		}
 
-		// Extract the recipient information from the packet. This
+		rpcProof, err = r.marshalProof(
+			ctx, p, req.WithPrevWitnesses, req.WithMetaReveal,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal proof: %w",
+				err)
+		}
+
+		rpcProof.NumberOfProofs = 1
+
+	case proof.IsProofFile(req.RawProof):
+		if err := proof.CheckMaxFileSize(req.RawProof); err != nil {
+			return nil, fmt.Errorf("invalid proof file: %w", err)
+		}
+
+		var proofFile proof.File
+		if err := proofFile.Decode(proofReader); err != nil {
+			return nil, fmt.Errorf("unable to decode proof file: "+
+				"%w", err)
+		}
+
+		latestProofIndex := uint32(proofFile.NumProofs() - 1)
+		if req.ProofAtDepth > latestProofIndex {
+			return nil, fmt.Errorf("invalid depth %d is greater "+
+				"than latest proof index of %d",
+				req.ProofAtDepth, latestProofIndex)
+		}
+
+		// Default to latest proof.
+		index := latestProofIndex - req.ProofAtDepth
+		rawProof, err := proofFile.RawProofAt(index)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := proof.DecodeRaw(rawProof)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode proof at "+
+				"index %d: %w", index, err)
+		}
+
+		rpcProof, err = r.marshalProof(
+			ctx, p, req.WithPrevWitnesses,
+			req.WithMetaReveal,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal proof: %w",
+				err)
+		}
+
+		rpcProof.ProofAtDepth = req.ProofAtDepth
+		rpcProof.NumberOfProofs = uint32(proofFile.NumProofs())
+
+	default:
+		return nil, fmt.Errorf("invalid raw proof, could not " +
+			"identify decoding format")
+	}
+
+	return &taprpc.DecodeProofResponse{
+		DecodedProof: rpcProof,
+	}, nil
+}
+
+// marshalProof turns a transition proof into an RPC DecodedProof.
+func (r *rpcServer) marshalProof(ctx context.Context, p *proof.Proof,
+	withPrevWitnesses, withMetaReveal bool) (*taprpc.DecodedProof, error) {
+
+	var (
+		rpcMeta        *taprpc.AssetMeta
+		rpcGenesis     = p.GenesisReveal
+		rpcGroupKey    = p.GroupKeyReveal
+		anchorOutpoint = wire.OutPoint{
+			Hash:  p.AnchorTx.TxHash(),
+			Index: p.InclusionProof.OutputIndex,
+		}
+		txMerkleProof  = p.TxMerkleProof
+		inclusionProof = p.InclusionProof
+		splitRootProof = p.SplitRootProof
+	)
+
+	var txMerkleProofBuf bytes.Buffer
+	if err := txMerkleProof.Encode(&txMerkleProofBuf); err != nil {
+		return nil, fmt.Errorf("unable to encode serialized Bitcoin "+
+			"merkle proof: %w", err)
+	}
+
+	var inclusionProofBuf bytes.Buffer
+	if err := inclusionProof.Encode(&inclusionProofBuf); err != nil {
+		return nil, fmt.Errorf("unable to encode inclusion proof: %w",
+			err)
+	}
+
+	if inclusionProof.CommitmentProof == nil {
+		return nil, fmt.Errorf("inclusion proof is missing " +
+			"commitment proof")
+	}
+	tsSibling, tsHash, err := commitment.MaybeEncodeTapscriptPreimage(
+		inclusionProof.CommitmentProof.TapSiblingPreimage,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding tapscript sibling: %w",
+			err)
+	}
+
+	tapProof, err := inclusionProof.CommitmentProof.DeriveByAssetInclusion(
+		&p.Asset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving inclusion proof: %w",
+			err)
+	}
+	merkleRoot := tapProof.TapscriptRoot(tsHash)
+
+	var exclusionProofs [][]byte
+	for _, exclusionProof := range p.ExclusionProofs {
+		var exclusionProofBuf bytes.Buffer
+		err := exclusionProof.Encode(&exclusionProofBuf)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode exclusion "+
+				"proofs: %w", err)
+		}
+		exclusionProofs = append(
+			exclusionProofs, exclusionProofBuf.Bytes(),
+		)
+	}
+
+	var splitRootProofBuf bytes.Buffer
+	if splitRootProof != nil {
+		err := splitRootProof.Encode(&splitRootProofBuf)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode split root "+
+				"proof: %w", err)
+		}
+	}
+
+	rpcAsset, err := r.marshalChainAsset(ctx, &tapdb.ChainAsset{
+		Asset:                  &p.Asset,
+		AnchorTx:               &p.AnchorTx,
+		AnchorTxid:             p.AnchorTx.TxHash(),
+		AnchorBlockHash:        p.BlockHeader.BlockHash(),
+		AnchorBlockHeight:      p.BlockHeight,
+		AnchorOutpoint:         anchorOutpoint,
+		AnchorInternalKey:      p.InclusionProof.InternalKey,
+		AnchorMerkleRoot:       merkleRoot[:],
+		AnchorTapscriptSibling: tsSibling,
+	}, withPrevWitnesses)
+	if err != nil {
+		return nil, err
+	}
+
+	if withMetaReveal {
+		metaHash := rpcAsset.AssetGenesis.MetaHash
+		if len(metaHash) == 0 {
+			return nil, fmt.Errorf("asset does not contain meta " +
+				"data")
+		}
+
+		rpcMeta, err = r.FetchAssetMeta(
+			ctx, &taprpc.FetchAssetMetaRequest{
+				Asset: &taprpc.FetchAssetMetaRequest_MetaHash{
+					MetaHash: metaHash,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decodedAssetID := p.Asset.ID()
+	var genesisReveal *taprpc.GenesisReveal
+	if rpcGenesis != nil {
+		genesisReveal = &taprpc.GenesisReveal{
+			GenesisBaseReveal: &taprpc.GenesisInfo{
+				GenesisPoint: rpcGenesis.FirstPrevOut.String(),
+				Name:         rpcGenesis.Tag,
+				MetaHash:     rpcGenesis.MetaHash[:],
+				AssetId:      decodedAssetID[:],
+				OutputIndex:  rpcGenesis.OutputIndex,
+			},
+			AssetType: taprpc.AssetType(p.Asset.Type),
+		}
+	}
+
+	var GroupKeyReveal taprpc.GroupKeyReveal
+	if rpcGroupKey != nil {
+		GroupKeyReveal = taprpc.GroupKeyReveal{
+			RawGroupKey:   rpcGroupKey.RawKey[:],
+			TapscriptRoot: rpcGroupKey.TapscriptRoot,
+		}
+	}
+
+	return &taprpc.DecodedProof{
+		Asset:               rpcAsset,
+		MetaReveal:          rpcMeta,
+		TxMerkleProof:       txMerkleProofBuf.Bytes(),
+		InclusionProof:      inclusionProofBuf.Bytes(),
+		ExclusionProofs:     exclusionProofs,
+		SplitRootProof:      splitRootProofBuf.Bytes(),
+		NumAdditionalInputs: uint32(len(p.AdditionalInputs)),
+		ChallengeWitness:    p.ChallengeWitness,
+		IsBurn:              p.Asset.IsBurn(),
+		GenesisReveal:       genesisReveal,
+		GroupKeyReveal:      &GroupKeyReveal,
+	}, nil
+}
+
+// CheckProofCompatibility runs a strict conformance check of the encoding of
+// the given proof or proof file and returns the results of all checks.
+func (r *rpcServer) CheckProofCompatibility(_ context.Context,
+	req *taprpc.CheckProofCompatibilityRequest) (
+	*taprpc.CheckProofCompatibilityResponse, error) {
+
+	report, err := proof.CheckConformance(req.RawProof)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("Checked proof compatibility of %v %v: compatible=%v",
+		req.Implementation, req.ImplementationVersion,
+		report.Conformant())
+
+	checks := make([]*taprpc.ProofConformanceCheck, len(report.Checks))
+	for idx, check := range report.Checks {
+		checks[idx] = &taprpc.ProofConformanceCheck{
+			Name:       check.Name,
+			ProofIndex: int32(check.ProofIndex),
+			Passed:     check.Passed,
+			Details:    check.Details,
+		}
+	}
+
+	return &taprpc.CheckProofCompatibilityResponse{
+		Compatible:            report.Conformant(),
+		IsFile:                report.IsFile,
+		Checks:                checks,
+		Implementation:        req.Implementation,
+		ImplementationVersion: req.ImplementationVersion,
+		CheckerVersion:        Version(),
+	}, nil
+}
+
+// ExportProof exports the latest raw proof file anchored at the specified
+// script_key.
+func (r *rpcServer) ExportProof(ctx context.Context,
+	req *taprpc.ExportProofRequest) (*taprpc.ProofFile, error) {
+
+	if len(req.ScriptKey) == 0 {
+		return nil, fmt.Errorf("a valid script key must be specified")
+	}
+
+	scriptKey, err := parseUserKey(req.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script key: %w", err)
+	}
+
+	if len(req.AssetId) != 32 {
+		return nil, fmt.Errorf("asset ID must be 32 bytes")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], req.AssetId)
+
+	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: *scriptKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &taprpc.ProofFile{
+		RawProofFile: proofBlob,
+	}, nil
+}
+
+// ExportProofsBatch exports the latest raw proof files of all owned assets that
+// match the given filter. If no filter is specified, the proof files of all
+// owned assets are exported.
+func (r *rpcServer) ExportProofsBatch(req *taprpc.ExportProofsBatchRequest,
+	stream taprpc.TaprootAssets_ExportProofsBatchServer) error {
+
+	ctx := stream.Context()
+
+	assetIDs := make(map[asset.ID]struct{}, len(req.AssetIds))
+	for _, rawAssetID := range req.AssetIds {
+		if len(rawAssetID) != sha256.Size {
+			return fmt.Errorf("asset ID must be 32 bytes")
+		}
+
+		var assetID asset.ID
+		copy(assetID[:], rawAssetID)
+		assetIDs[assetID] = struct{}{}
+	}
+
+	groupKeys := make(map[asset.SerializedKey]struct{}, len(req.GroupKeys))
+	for _, rawGroupKey := range req.GroupKeys {
+		groupKey, err := parseUserKey(rawGroupKey)
+		if err != nil {
+			return fmt.Errorf("invalid group key: %w", err)
+		}
+
+		groupKeys[asset.ToSerialized(groupKey)] = struct{}{}
+	}
+
+	exportAll := len(assetIDs) == 0 && len(groupKeys) == 0
+
+	// We export the proofs of all assets we own, including the ones that
+	// are currently leased for a pending transfer.
+	assets, err := r.cfg.AssetStore.FetchAllAssets(ctx, false, true, nil)
+	if err != nil {
+		return fmt.Errorf("unable to read chain assets: %w", err)
+	}
+
+	for _, a := range assets {
+		assetID := a.ID()
+
+		_, matchesID := assetIDs[assetID]
+
+		var matchesGroup bool
+		if a.GroupKey != nil {
+			_, matchesGroup = groupKeys[asset.ToSerialized(
+				&a.GroupKey.GroupPubKey,
+			)]
+		}
+
+		if !exportAll && !matchesID && !matchesGroup {
+			continue
+		}
+
+		scriptKey := a.ScriptKey.PubKey
+		anchorPoint := a.AnchorOutpoint
+		proofBlob, err := r.cfg.ProofArchive.FetchProof(
+			ctx, proof.Locator{
+				AssetID:   &assetID,
+				ScriptKey: *scriptKey,
+				OutPoint:  &anchorPoint,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch proof for asset "+
+				"%v at %v: %w", assetID, anchorPoint, err)
+		}
+
+		err = stream.Send(&taprpc.ExportedProofFile{
+			AssetId:        assetID[:],
+			ScriptKey:      scriptKey.SerializeCompressed(),
+			AnchorOutpoint: anchorPoint.String(),
+			RawProofFile:   proofBlob,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExportProofStream exports the latest proof file anchored at the specified
+// script key one proof at a time. If a range of proof indexes is given, only
+// the proofs within that range are exported.
+func (r *rpcServer) ExportProofStream(req *taprpc.ExportProofStreamRequest,
+	stream taprpc.TaprootAssets_ExportProofStreamServer) error {
+
+	ctx := stream.Context()
+
+	if len(req.ScriptKey) == 0 {
+		return fmt.Errorf("a valid script key must be specified")
+	}
+
+	scriptKey, err := parseUserKey(req.ScriptKey)
+	if err != nil {
+		return fmt.Errorf("invalid script key: %w", err)
+	}
+
+	if len(req.AssetId) != sha256.Size {
+		return fmt.Errorf("asset ID must be 32 bytes")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], req.AssetId)
+
+	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: *scriptKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	var proofFile proof.File
+	if err := proofFile.Decode(bytes.NewReader(proofBlob)); err != nil {
+		return fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	numProofs := uint32(proofFile.NumProofs())
+	endIndex := req.EndIndex
+	if endIndex == 0 {
+		endIndex = numProofs
+	}
+
+	switch {
+	case endIndex > numProofs:
+		return fmt.Errorf("end index %d exceeds the number of proofs "+
+			"%d", endIndex, numProofs)
+
+	case req.StartIndex >= endIndex:
+		return fmt.Errorf("start index %d must be lower than end "+
+			"index %d", req.StartIndex, endIndex)
+	}
+
+	for idx := req.StartIndex; idx < endIndex; idx++ {
+		rawProof, err := proofFile.RawProofAt(idx)
+		if err != nil {
+			return fmt.Errorf("unable to fetch proof %d: %w", idx,
+				err)
+		}
+
+		err = stream.Send(&taprpc.ProofFileChunk{
+			FileVersion: uint32(proofFile.Version),
+			NumProofs:   numProofs,
+			ProofIndex:  idx,
+			RawProof:    rawProof,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MergeProofFiles merges two proof files into a single proof file. The first
+// proof of the suffix file must spend the asset output of the last proof of the
+// prefix file, otherwise the request is rejected.
+func (r *rpcServer) MergeProofFiles(_ context.Context,
+	req *taprpc.MergeProofFilesRequest) (*taprpc.ProofFile, error) {
+
+	decodeFile := func(name string, rawFile []byte) (*proof.File, error) {
+		if !proof.IsProofFile(rawFile) {
+			return nil, fmt.Errorf("invalid %s proof, expect "+
+				"file, not single encoded mint or transition "+
+				"proof", name)
+		}
+
+		if err := proof.CheckMaxFileSize(rawFile); err != nil {
+			return nil, fmt.Errorf("invalid %s proof file: %w",
+				name, err)
+		}
+
+		var proofFile proof.File
+		err := proofFile.Decode(bytes.NewReader(rawFile))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode %s proof "+
+				"file: %w", name, err)
+		}
+
+		return &proofFile, nil
+	}
+
+	prefix, err := decodeFile("prefix", req.PrefixProofFile)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := decodeFile("suffix", req.SuffixProofFile)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := proof.MergeFiles(prefix, suffix)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge proof files: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := merged.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("unable to encode merged proof file: %w",
+			err)
+	}
+
+	genesisProof, err := merged.ProofAt(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &taprpc.ProofFile{
+		RawProofFile: buf.Bytes(),
+		GenesisPoint: genesisProof.Asset.Genesis.FirstPrevOut.String(),
+	}, nil
+}
+
+// fetchOutputProof fetches the last proof of the asset in the transfer output
+// identified by the given anchor outpoint, script key and optional asset ID.
+func (r *rpcServer) fetchOutputProof(ctx context.Context,
+	anchorOutpoint string, scriptKeyBytes,
+	assetIDBytes []byte) (*proof.Proof, error) {
+
+	anchorOutPoint, err := UnmarshalOutpoint(anchorOutpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anchor outpoint: %w", err)
+	}
+
+	if len(scriptKeyBytes) == 0 {
+		return nil, fmt.Errorf("a valid script key must be specified")
+	}
+
+	scriptKey, err := parseUserKey(scriptKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script key: %w", err)
+	}
+
+	locator := proof.Locator{
+		ScriptKey: *scriptKey,
+		OutPoint:  anchorOutPoint,
+	}
+	if len(assetIDBytes) > 0 {
+		if len(assetIDBytes) != 32 {
+			return nil, fmt.Errorf("asset ID must be 32 bytes")
+		}
+
+		var assetID asset.ID
+		copy(assetID[:], assetIDBytes)
+		locator.AssetID = &assetID
+	}
+
+	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch proof: %w", err)
+	}
+
+	var proofFile proof.File
+	err = proofFile.Decode(bytes.NewReader(proofBlob))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode proof file: %w", err)
+	}
+
+	return proofFile.LastProof()
+}
+
+// GetSplitCommitment decodes the split commitment of the asset in the given
+// transfer output from the stored proof.
+func (r *rpcServer) GetSplitCommitment(ctx context.Context,
+	req *taprpc.GetSplitCommitmentRequest) (
+	*taprpc.GetSplitCommitmentResponse, error) {
+
+	p, err := r.fetchOutputProof(
+		ctx, req.AnchorOutpoint, req.ScriptKey, req.AssetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The root asset of a split carries the split commitment root itself,
+	// while a split asset references the root asset in its only witness.
+	transferAsset := &p.Asset
+	rootAsset := transferAsset
+	rootOutputIndex := p.InclusionProof.OutputIndex
+	isSplit := transferAsset.HasSplitCommitmentWitness()
+	if isSplit {
+		if p.SplitRootProof == nil {
+			return nil, fmt.Errorf("proof of split asset is " +
+				"missing split root proof")
+		}
+
+		splitWitness := transferAsset.PrevWitnesses[0]
+		rootAsset = &splitWitness.SplitCommitment.RootAsset
+		rootOutputIndex = p.SplitRootProof.OutputIndex
+	}
+
+	splitRoot := rootAsset.SplitCommitmentRoot
+	if splitRoot == nil {
+		return nil, fmt.Errorf("asset in transfer output %v wasn't "+
+			"created by a split", req.AnchorOutpoint)
+	}
+
+	splitRootHash := splitRoot.NodeHash()
+	resp := &taprpc.GetSplitCommitmentResponse{
+		IsSplitRoot:          !isSplit,
+		SplitRootHash:        splitRootHash[:],
+		SplitRootSum:         splitRoot.NodeSum(),
+		SplitRootOutputIndex: rootOutputIndex,
+		RootAssetScriptKey: schnorr.SerializePubKey(
+			rootAsset.ScriptKey.PubKey,
+		),
+		RootAssetAmount: rootAsset.Amount,
+	}
+
+	if !isSplit {
+		return resp, nil
+	}
+
+	// For a split asset, we also return the inclusion proof of its leaf in
+	// the split commitment tree and check that it resolves to the root.
+	splitCommitment := transferAsset.PrevWitnesses[0].SplitCommitment
+	locatorKey := (&commitment.SplitLocator{
+		OutputIndex: p.InclusionProof.OutputIndex,
+		AssetID:     transferAsset.Genesis.ID(),
+		ScriptKey:   asset.ToSerialized(transferAsset.ScriptKey.PubKey),
+		Amount:      transferAsset.Amount,
+	}).Hash()
+
+	splitNoWitness := transferAsset.Copy()
+	splitNoWitness.PrevWitnesses[0].SplitCommitment = nil
+	splitLeaf, err := splitNoWitness.Leaf()
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute split leaf: %w", err)
+	}
+
+	var proofBuf bytes.Buffer
+	err = splitCommitment.Proof.Compress().Encode(&proofBuf)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode split inclusion "+
+			"proof: %w", err)
+	}
+
+	resp.SplitLeafKey = locatorKey[:]
+	resp.SplitInclusionProof = proofBuf.Bytes()
+	resp.InclusionProofValid = mssmt.VerifyMerkleProof(
+		locatorKey, splitLeaf, &splitCommitment.Proof, splitRoot,
+	)
+
+	return resp, nil
+}
+
+// GetAnchorMerkleProof returns the merkle proof of the anchor transaction of
+// the given transfer output in its confirming block, together with the block
+// header.
+func (r *rpcServer) GetAnchorMerkleProof(ctx context.Context,
+	req *taprpc.GetAnchorMerkleProofRequest) (
+	*taprpc.GetAnchorMerkleProofResponse, error) {
+
+	p, err := r.fetchOutputProof(
+		ctx, req.AnchorOutpoint, req.ScriptKey, req.AssetId,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var txBuf bytes.Buffer
+	if err := p.AnchorTx.Serialize(&txBuf); err != nil {
+		return nil, fmt.Errorf("unable to serialize anchor tx: %w",
+			err)
+	}
+
+	var headerBuf bytes.Buffer
+	if err := p.BlockHeader.Serialize(&headerBuf); err != nil {
+		return nil, fmt.Errorf("unable to serialize block header: %w",
+			err)
+	}
+
+	var proofBuf bytes.Buffer
+	if err := p.TxMerkleProof.Encode(&proofBuf); err != nil {
+		return nil, fmt.Errorf("unable to encode merkle proof: %w",
+			err)
+	}
+
+	branch := make([][]byte, len(p.TxMerkleProof.Nodes))
+	for idx := range p.TxMerkleProof.Nodes {
+		branch[idx] = fn.CopySlice(p.TxMerkleProof.Nodes[idx][:])
+	}
+
+	return &taprpc.GetAnchorMerkleProofResponse{
+		AnchorTxid:   p.AnchorTx.TxHash().String(),
+		RawAnchorTx:  txBuf.Bytes(),
+		BlockHeader:  headerBuf.Bytes(),
+		BlockHash:    p.BlockHeader.BlockHash().String(),
+		BlockHeight:  p.BlockHeight,
+		TxIndex:      p.TxMerkleProof.Index(),
+		MerkleBranch: branch,
+		MerkleProof:  proofBuf.Bytes(),
+		MerkleProofValid: p.TxMerkleProof.Verify(
+			&p.AnchorTx, p.BlockHeader.MerkleRoot,
+		),
+	}, nil
+}
+
+// ListDeliveryReceipts lists the delivery receipts that receivers returned for
+// proofs sent to the given script key through a proof courier.
+func (r *rpcServer) ListDeliveryReceipts(ctx context.Context,
+	req *taprpc.ListDeliveryReceiptsRequest) (
+	*taprpc.ListDeliveryReceiptsResponse, error) {
+
+	scriptKey, err := parseUserKey(req.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script key: %w", err)
+	}
+
+	receipts, err := r.cfg.AssetStore.FetchProofDeliveryReceipts(
+		ctx, scriptKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcReceipts := make([]*taprpc.DeliveryReceipt, len(receipts))
+	for idx, receipt := range receipts {
+		loc, signed := receipt.Locator, receipt.Receipt
+		rawKey := signed.RawScriptKey
+		rpcReceipt := &taprpc.DeliveryReceipt{
+			ScriptKey:              scriptKey.SerializeCompressed(),
+			ProofHash:              signed.ProofHash[:],
+			RawScriptKey:           rawKey.SerializeCompressed(),
+			TapTweak:               signed.TapTweak,
+			Signature:              signed.Signature.Serialize(),
+			ReceiveTimeUnixSeconds: receipt.ReceiveTime.Unix(),
+		}
+		if loc.AssetID != nil {
+			rpcReceipt.AssetId = loc.AssetID[:]
+		}
+		if loc.OutPoint != nil {
+			rpcReceipt.AnchorOutpoint = loc.OutPoint.String()
+		}
+
+		rpcReceipts[idx] = rpcReceipt
+	}
+
+	return &taprpc.ListDeliveryReceiptsResponse{
+		Receipts: rpcReceipts,
+	}, nil
+}
+
+// unmarshalDeliveryID parses the ID of a proof delivery from the anchor
+// outpoint and script key of the transfer output the proof is delivered for.
+func unmarshalDeliveryID(anchorOutpoint string,
+	scriptKeyBytes []byte) (proof.DeliveryID, error) {
+
+	outPoint, err := UnmarshalOutpoint(anchorOutpoint)
+	if err != nil {
+		return proof.DeliveryID{}, fmt.Errorf("invalid anchor "+
+			"outpoint: %w", err)
+	}
+
+	// We need the full compressed key here, since the parity of the script
+	// key is part of the delivery ID.
+	scriptKey, err := btcec.ParsePubKey(scriptKeyBytes)
+	if err != nil {
+		return proof.DeliveryID{}, fmt.Errorf("invalid script key: %w",
+			err)
+	}
+
+	return proof.NewDeliveryID(*outPoint, scriptKey), nil
+}
+
+// PauseProofDelivery pauses the delivery of the proof for the given transfer
+// output until it is resumed.
+func (r *rpcServer) PauseProofDelivery(_ context.Context,
+	req *taprpc.PauseProofDeliveryRequest) (
+	*taprpc.PauseProofDeliveryResponse, error) {
+
+	id, err := unmarshalDeliveryID(req.AnchorOutpoint, req.ScriptKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.cfg.PausedProofDeliveries.Pause(id); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Paused proof delivery %v", id)
+
+	return &taprpc.PauseProofDeliveryResponse{}, nil
+}
+
+// ResumeProofDelivery resumes the paused delivery of the proof for the given
+// transfer output.
+func (r *rpcServer) ResumeProofDelivery(_ context.Context,
+	req *taprpc.ResumeProofDeliveryRequest) (
+	*taprpc.ResumeProofDeliveryResponse, error) {
+
+	id, err := unmarshalDeliveryID(req.AnchorOutpoint, req.ScriptKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.cfg.PausedProofDeliveries.Resume(id); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Resumed proof delivery %v", id)
+
+	return &taprpc.ResumeProofDeliveryResponse{}, nil
+}
+
+// ListDeadLetterProofs lists the inbound proofs that couldn't be imported
+// after all retries.
+func (r *rpcServer) ListDeadLetterProofs(_ context.Context,
+	_ *taprpc.ListDeadLetterProofsRequest) (
+	*taprpc.ListDeadLetterProofsResponse, error) {
+
+	deadLetters := r.cfg.AssetCustodian.DeadLetters()
+
+	rpcDeadLetters := make([]*taprpc.DeadLetterProof, len(deadLetters))
+	for idx, deadLetter := range deadLetters {
+		rpcDeadLetter := &taprpc.DeadLetterProof{
+			Outpoint:        deadLetter.OutPoint.String(),
+			Attempts:        deadLetter.Attempts,
+			TimeUnixSeconds: deadLetter.Time.Unix(),
+		}
+		if deadLetter.Err != nil {
+			rpcDeadLetter.Error = deadLetter.Err.Error()
+		}
+		if deadLetter.Proof != nil {
+			loc := deadLetter.Proof.Locator
+			if loc.AssetID != nil {
+				rpcDeadLetter.AssetId = loc.AssetID[:]
+			}
+			rpcDeadLetter.ScriptKey =
+				loc.ScriptKey.SerializeCompressed()
+			rpcDeadLetter.RawProofFile = deadLetter.Proof.Blob
+		}
+
+		rpcDeadLetters[idx] = rpcDeadLetter
+	}
+
+	return &taprpc.ListDeadLetterProofsResponse{
+		DeadLetters: rpcDeadLetters,
+	}, nil
+}
+
+// ImportProof attempts to import a proof file into the daemon. If successful, a
+// new asset will be inserted on disk, spendable using the specified target
+// script key, and internal key.
+func (r *rpcServer) ImportProof(ctx context.Context,
+	req *tapdevrpc.ImportProofRequest) (*tapdevrpc.ImportProofResponse,
+	error) {
+
+	// We'll perform some basic input validation before we move forward.
+	if len(req.ProofFile) == 0 {
+		return nil, fmt.Errorf("proof file must be specified")
+	}
+
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
+	groupVerifier := tapgarden.GenGroupVerifier(ctx, r.cfg.MintingStore)
+
+	// Now that we know the proof file is at least present, we'll attempt
+	// to import it into the main archive.
+	err := r.cfg.ProofArchive.ImportProofs(
+		ctx, headerVerifier, groupVerifier, false,
+		&proof.AnnotatedProof{Blob: req.ProofFile},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tapdevrpc.ImportProofResponse{}, nil
+}
+
+// AddrReceives lists all receives for incoming asset transfers for addresses
+// that were created previously.
+func (r *rpcServer) AddrReceives(ctx context.Context,
+	req *taprpc.AddrReceivesRequest) (*taprpc.AddrReceivesResponse,
+	error) {
+
+	var sqlQuery address.EventQueryParams
+
+	if len(req.FilterAddr) > 0 {
+		tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+
+		addr, err := address.DecodeAddress(req.FilterAddr, &tapParams)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode addr: %w", err)
+		}
+
+		// Now that we've decoded the address, we'll check to make sure
+		// that we can fetch the genesis for this address. Otherwise,
+		// that means we don't know anything about what it should look
+		// like on chain (the genesis is required to derive the taproot
+		// output key).
+		assetGroup, err := r.cfg.TapAddrBook.QueryAssetGroup(
+			ctx, addr.AssetID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unknown asset=%x: %w",
+				addr.AssetID[:], err)
+		}
+
+		rpcsLog.Tracef("Listing receives for group: %v",
+			spew.Sdump(assetGroup))
+
+		addr.AttachGenesis(*assetGroup.Genesis)
+
+		taprootOutputKey, err := addr.TaprootOutputKey()
+		if err != nil {
+			return nil, fmt.Errorf("error deriving Taproot key: %w",
+				err)
+		}
+
+		sqlQuery.AddrTaprootOutputKey = schnorr.SerializePubKey(
+			taprootOutputKey,
+		)
+	}
+
+	if req.FilterStatus != taprpc.AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN {
+		status, err := unmarshalAddrEventStatus(req.FilterStatus)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing status: %w", err)
+		}
+
+		sqlQuery.StatusFrom = &status
+		sqlQuery.StatusTo = &status
+	}
+
+	events, err := r.cfg.AddrBook.QueryEvents(ctx, sqlQuery)
+	if err != nil {
+		return nil, fmt.Errorf("error querying events: %w", err)
+	}
+
+	resp := &taprpc.AddrReceivesResponse{
+		Events: make([]*taprpc.AddrEvent, len(events)),
+	}
+
+	for idx, event := range events {
+		resp.Events[idx], err = marshalAddrEvent(
+			event, r.cfg.TapAddrBook,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling event: %w",
+				err)
+		}
+	}
+
+	return resp, nil
+}
+
+// FundVirtualPsbt selects inputs from the available asset commitments to fund
+// a virtual transaction matching the template.
+func (r *rpcServer) FundVirtualPsbt(ctx context.Context,
+	req *wrpc.FundVirtualPsbtRequest) (*wrpc.FundVirtualPsbtResponse,
+	error) {
+
+	// The tranche preference of the request overrides the configured one.
+	// It only applies to the inputs of a PSBT template, as the raw template
+	// pays addresses, which always spend the tranche of their asset ID.
+	var fundingOpts []tapfreighter.FundingOption
+	switch {
+	case req.TranchePreference != "":
+		tranche, err := tapfreighter.ParseTrancheSelection(
+			req.TranchePreference, req.TrancheGenesisPoint,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tranche preference: %w",
+				err)
+		}
+
+		fundingOpts = append(
+			fundingOpts, tapfreighter.WithTrancheSelection(tranche),
+		)
+
+	case req.TrancheGenesisPoint != "":
+		return nil, fmt.Errorf("tranche genesis point can only be " +
+			"set with a tranche preference")
+	}
+
+	var fundedVPkt *tapfreighter.FundedVPacket
+	switch {
+	case req.GetPsbt() != nil:
+		vPkt, err := tappsbt.NewFromRawBytes(
+			bytes.NewReader(req.GetPsbt()), false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode psbt: %w", err)
+		}
+
+		// Extract the recipient information from the packet. This
 		// basically assembles the asset ID we want to send to and the
 		// sum of all output amounts.
 		desc, err := tapscript.DescribeRecipients(
 			ctx, vPkt, r.cfg.TapAddrBook,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("unable to describe packet "+
-				"recipients: %w", err)
+			return nil, fmt.Errorf("unable to describe packet "+
+				"recipients: %w", err)
+		}
+
+		fundedVPkt, err = r.cfg.AssetWallet.FundPacket(
+			ctx, desc, vPkt, fundingOpts...,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error funding packet: %w", err)
+		}
+
+	case req.GetRaw() != nil:
+		raw := req.GetRaw()
+		if len(raw.Inputs) > 0 {
+			return nil, fmt.Errorf("template inputs not yet " +
+				"supported")
+		}
+		if len(raw.Recipients) > 1 {
+			return nil, fmt.Errorf("only one recipient supported")
+		}
+
+		var (
+			tapParams = address.ParamsForChain(
+				r.cfg.ChainParams.Name,
+			)
+			addr *address.Tap
+			err  error
+		)
+		for a := range raw.Recipients {
+			addr, err = address.DecodeAddress(a, &tapParams)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode "+
+					"addr: %w", err)
+			}
+
+			err = address.CheckAmount(
+				addr.Amount, r.cfg.MaxAddrAmount,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if addr == nil {
+			return nil, fmt.Errorf("no recipients specified")
+		}
+
+		fundedVPkt, _, err = r.cfg.AssetWallet.FundAddressSend(
+			ctx, 0, nil, addr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error funding address send: "+
+				"%w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("either PSBT or raw template must be " +
+			"specified")
+	}
+
+	var b bytes.Buffer
+	if err := fundedVPkt.VPacket.Serialize(&b); err != nil {
+		return nil, fmt.Errorf("error serializing packet: %w", err)
+	}
+
+	return &wrpc.FundVirtualPsbtResponse{
+		FundedPsbt:        b.Bytes(),
+		ChangeOutputIndex: 0,
+	}, nil
+}
+
+// SignVirtualPsbt signs the inputs of a virtual transaction and prepares the
+// commitments of the inputs and outputs.
+func (r *rpcServer) SignVirtualPsbt(_ context.Context,
+	req *wrpc.SignVirtualPsbtRequest) (*wrpc.SignVirtualPsbtResponse,
+	error) {
+
+	if req.FundedPsbt == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(req.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	signedInputs, err := r.cfg.AssetWallet.SignVirtualPacket(vPkt)
+	if err != nil {
+		return nil, fmt.Errorf("error signing packet: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := vPkt.Serialize(&b); err != nil {
+		return nil, fmt.Errorf("error serializing packet: %w", err)
+	}
+
+	return &wrpc.SignVirtualPsbtResponse{
+		SignedPsbt:   b.Bytes(),
+		SignedInputs: signedInputs,
+	}, nil
+}
+
+// PrepareScriptSpend prepares an input of a funded virtual transaction to be
+// spent through the script path of any leaf of the tapscript tree its script
+// key commits to, optionally with a witness supplied by the caller.
+func (r *rpcServer) PrepareScriptSpend(_ context.Context,
+	req *wrpc.PrepareScriptSpendRequest) (*wrpc.PrepareScriptSpendResponse,
+	error) {
+
+	if req.FundedPsbt == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+	if len(req.LeafScript) == 0 {
+		return nil, fmt.Errorf("leaf script must be set")
+	}
+
+	vPkt, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(req.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	if int(req.InputIndex) >= len(vPkt.Inputs) {
+		return nil, fmt.Errorf("invalid input index %d for packet "+
+			"with %d inputs", req.InputIndex, len(vPkt.Inputs))
+	}
+	vIn := vPkt.Inputs[req.InputIndex]
+
+	leafVersion := txscript.BaseLeafVersion
+	if req.LeafVersion != 0 {
+		if req.LeafVersion > math.MaxUint8 {
+			return nil, fmt.Errorf("invalid leaf version %d",
+				req.LeafVersion)
+		}
+		leafVersion = txscript.TapscriptLeafVersion(req.LeafVersion)
+	}
+	leaf := txscript.NewTapLeaf(leafVersion, req.LeafScript)
+
+	err = tapscript.PrepareLeafSpend(vIn, leaf, req.ControlBlock)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing script spend: %w", err)
+	}
+
+	// A previously set witness belongs to a different leaf, so we always
+	// reset it.
+	vIn.FinalScriptWitness = nil
+	if len(req.Witness) > 0 {
+		err = tapscript.SetScriptSpendWitness(vIn, req.Witness)
+		if err != nil {
+			return nil, fmt.Errorf("error setting witness: %w", err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := vPkt.Serialize(&b); err != nil {
+		return nil, fmt.Errorf("error serializing packet: %w", err)
+	}
+
+	return &wrpc.PrepareScriptSpendResponse{
+		PreparedPsbt: b.Bytes(),
+	}, nil
+}
+
+// AnchorVirtualPsbts merges and then commits multiple virtual transactions in
+// a single BTC level anchor transaction.
+//
+// TODO(guggero): Actually implement accepting and merging multiple
+// transactions.
+func (r *rpcServer) AnchorVirtualPsbts(ctx context.Context,
+	req *wrpc.AnchorVirtualPsbtsRequest) (*taprpc.SendAssetResponse,
+	error) {
+
+	vPacket, inputCommitments, err := r.decodeSignedVirtualPsbts(
+		ctx, req.VirtualPsbts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.cfg.ChainPorter.RequestShipment(
+		tapfreighter.NewPreSignedParcel(vPacket, inputCommitments),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting delivery: %w", err)
+	}
+
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
+	}
+
+	return &taprpc.SendAssetResponse{
+		Transfer: parcel,
+	}, nil
+}
+
+// decodeSignedVirtualPsbts decodes the given signed virtual PSBTs and fetches
+// the commitments of the inputs they spend. Only a single virtual PSBT with a
+// single input is currently supported.
+func (r *rpcServer) decodeSignedVirtualPsbts(ctx context.Context,
+	rawPsbts [][]byte) (*tappsbt.VPacket, tappsbt.InputCommitments,
+	error) {
+
+	if len(rawPsbts) == 0 {
+		return nil, nil, fmt.Errorf("no virtual PSBTs specified")
+	}
+
+	if len(rawPsbts) > 1 {
+		return nil, nil, fmt.Errorf("only one virtual PSBT supported")
+	}
+
+	vPacket, err := tappsbt.NewFromRawBytes(
+		bytes.NewReader(rawPsbts[0]), false,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding packet: %w", err)
+	}
+
+	if len(vPacket.Inputs) != 1 {
+		return nil, nil, fmt.Errorf("only one input is currently " +
+			"supported")
+	}
+
+	inputAsset := vPacket.Inputs[0].Asset()
+	prevID := vPacket.Inputs[0].PrevID
+	inputCommitment, err := r.cfg.AssetStore.FetchCommitment(
+		ctx, inputAsset.ID(), prevID.OutPoint, inputAsset.GroupKey,
+		&inputAsset.ScriptKey, true,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error fetching input "+
+			"commitment: %w", err)
+	}
+
+	rpcsLog.Debugf("Selected commitment for anchor point %v",
+		inputCommitment.AnchorPoint)
+
+	return vPacket, tappsbt.InputCommitments{
+		0: inputCommitment.Commitment,
+	}, nil
+}
+
+// CommitVirtualPsbts creates the BTC level anchor transaction that commits to
+// the given signed virtual transaction and funds it with the wallet, but
+// doesn't sign it. The returned anchor PSBT can then be signed externally and
+// handed to PublishAndLogTransfer.
+func (r *rpcServer) CommitVirtualPsbts(ctx context.Context,
+	req *wrpc.CommitVirtualPsbtsRequest) (*wrpc.CommitVirtualPsbtsResponse,
+	error) {
+
+	vPacket, inputCommitments, err := r.decodeSignedVirtualPsbts(
+		ctx, req.VirtualPsbts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate := chainfee.SatPerKWeight(req.FeeRate)
+	if feeRate == 0 {
+		feeRate, err = r.cfg.ChainBridge.EstimateFee(
+			ctx, tapscript.SendConfTarget,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee: %w",
+				err)
 		}
+	}
 
-		fundedVPkt, err = r.cfg.AssetWallet.FundPacket(
-			ctx, desc, vPkt,
+	passiveAssets, err := r.cfg.AssetWallet.SignPassiveAssets(
+		vPacket, inputCommitments,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign passive assets: %w", err)
+	}
+
+	passivePackets := make([]*tappsbt.VPacket, len(passiveAssets))
+	for idx := range passiveAssets {
+		passivePackets[idx] = passiveAssets[idx].VPacket
+	}
+
+	anchorTx, err := r.cfg.AssetWallet.CommitVirtualTransactions(
+		ctx, &tapfreighter.AnchorVTxnsParams{
+			FeeRate: feeRate,
+			VPkts:   []*tappsbt.VPacket{vPacket},
+			InputCommitments: []tappsbt.InputCommitments{
+				inputCommitments,
+			},
+			PassiveAssetsVPkts: passivePackets,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to commit virtual "+
+			"transactions: %w", err)
+	}
+
+	var anchorBuf bytes.Buffer
+	if err := anchorTx.FundedPsbt.Pkt.Serialize(&anchorBuf); err != nil {
+		return nil, fmt.Errorf("error serializing anchor packet: %w",
+			err)
+	}
+
+	resp := &wrpc.CommitVirtualPsbtsResponse{
+		AnchorPsbt:        anchorBuf.Bytes(),
+		ChangeOutputIndex: anchorTx.FundedPsbt.ChangeOutputIndex,
+	}
+
+	// The virtual packet now commits to the anchor outputs, so we return
+	// the updated version.
+	var vPktBuf bytes.Buffer
+	if err := vPacket.Serialize(&vPktBuf); err != nil {
+		return nil, fmt.Errorf("error serializing packet: %w", err)
+	}
+	resp.VirtualPsbts = [][]byte{vPktBuf.Bytes()}
+
+	for _, passivePacket := range passivePackets {
+		var b bytes.Buffer
+		if err := passivePacket.Serialize(&b); err != nil {
+			return nil, fmt.Errorf("error serializing passive "+
+				"packet: %w", err)
+		}
+		resp.PassiveAssetPsbts = append(
+			resp.PassiveAssetPsbts, b.Bytes(),
+		)
+	}
+
+	for _, utxo := range anchorTx.FundedPsbt.LockedUTXOs {
+		resp.LndLockedUtxos = append(
+			resp.LndLockedUtxos, utxo.String(),
+		)
+	}
+
+	return resp, nil
+}
+
+// PublishAndLogTransfer completes a transfer with an anchor transaction that
+// was created by CommitVirtualPsbts and signed externally. The transfer is
+// logged, broadcast and its proofs are delivered like for any other transfer.
+func (r *rpcServer) PublishAndLogTransfer(ctx context.Context,
+	req *wrpc.PublishAndLogRequest) (*taprpc.SendAssetResponse, error) {
+
+	vPacket, inputCommitments, err := r.decodeSignedVirtualPsbts(
+		ctx, req.VirtualPsbts,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorPkt, err := psbt.NewFromRawBytes(
+		bytes.NewReader(req.AnchorPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding anchor packet: %w", err)
+	}
+
+	numPassive := len(req.PassiveAssetPsbts)
+	passiveAssets := make([]*tapfreighter.PassiveAssetReAnchor, numPassive)
+	for idx := range req.PassiveAssetPsbts {
+		passivePacket, err := tappsbt.NewFromRawBytes(
+			bytes.NewReader(req.PassiveAssetPsbts[idx]), false,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error funding packet: %w", err)
+			return nil, fmt.Errorf("error decoding passive "+
+				"packet %d: %w", idx, err)
 		}
 
-	case req.GetRaw() != nil:
-		raw := req.GetRaw()
-		if len(raw.Inputs) > 0 {
-			return nil, fmt.Errorf("template inputs not yet " +
-				"supported")
+		passiveAssets[idx], err = tapfreighter.NewPassiveAssetReAnchor(
+			passivePacket,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid passive packet %d: %w",
+				idx, err)
 		}
-		if len(raw.Recipients) > 1 {
-			return nil, fmt.Errorf("only one recipient supported")
+	}
+
+	lockedUTXOs := make([]wire.OutPoint, len(req.LndLockedUtxos))
+	for idx := range req.LndLockedUtxos {
+		utxo, err := wire.NewOutPointFromString(
+			req.LndLockedUtxos[idx],
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locked UTXO %d: %w",
+				idx, err)
 		}
+		lockedUTXOs[idx] = *utxo
+	}
 
-		var (
-			tapParams = address.ParamsForChain(
-				r.cfg.ChainParams.Name,
-			)
-			addr *address.Tap
-			err  error
+	resp, err := r.cfg.ChainPorter.RequestShipment(
+		tapfreighter.NewPreAnchoredParcel(
+			vPacket, inputCommitments, passiveAssets, anchorPkt,
+			req.ChangeOutputIndex, lockedUTXOs, req.Label,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting delivery: %w", err)
+	}
+
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
+	}
+
+	return &taprpc.SendAssetResponse{
+		Transfer: parcel,
+	}, nil
+}
+
+// NextInternalKey derives the next internal key for the given key family and
+// stores it as an internal key in the database to make sure it is identified
+// as a local key later on when importing proofs. While an internal key can
+// also be used as the internal key of a script key, it is recommended to use
+// the NextScriptKey RPC instead, to make sure the tweaked Taproot output key
+// is also recognized as a local key.
+func (r *rpcServer) NextInternalKey(ctx context.Context,
+	req *wrpc.NextInternalKeyRequest) (*wrpc.NextInternalKeyResponse,
+	error) {
+
+	// Due to how we detect local keys, we need to make sure that the key
+	// family is not zero.
+	if req.KeyFamily == 0 {
+		return nil, fmt.Errorf("key family must be set to a non-zero " +
+			"value")
+	}
+
+	keyDesc, err := r.cfg.AddrBook.NextInternalKey(ctx, keychain.KeyFamily(
+		req.KeyFamily,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting internal key: %w", err)
+	}
+
+	return &wrpc.NextInternalKeyResponse{
+		InternalKey: marshalKeyDescriptor(keyDesc),
+	}, nil
+}
+
+// NextScriptKey derives the next script key (and its corresponding internal
+// key) and stores them both in the database to make sure they are identified
+// as local keys later on when importing proofs.
+func (r *rpcServer) NextScriptKey(ctx context.Context,
+	req *wrpc.NextScriptKeyRequest) (*wrpc.NextScriptKeyResponse,
+	error) {
+
+	// Due to how we detect local keys, we need to make sure that the key
+	// family is not zero.
+	if req.KeyFamily == 0 {
+		return nil, fmt.Errorf("key family must be set to a non-zero " +
+			"value")
+	}
+
+	scriptKey, err := r.cfg.AddrBook.NextScriptKey(ctx, keychain.KeyFamily(
+		req.KeyFamily,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error inserting internal key: %w", err)
+	}
+
+	return &wrpc.NextScriptKeyResponse{
+		ScriptKey: marshalScriptKey(scriptKey),
+	}, nil
+}
+
+// GetKeyDerivation returns the key derivation information of the script key
+// and the anchor internal key of an owned asset.
+func (r *rpcServer) GetKeyDerivation(ctx context.Context,
+	req *wrpc.GetKeyDerivationRequest) (*wrpc.GetKeyDerivationResponse,
+	error) {
+
+	if len(req.AssetId) != sha256.Size {
+		return nil, fmt.Errorf("asset ID must be 32 bytes")
+	}
+
+	var assetID asset.ID
+	copy(assetID[:], req.AssetId)
+
+	scriptKey, err := parseUserKey(req.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script key: %w", err)
+	}
+
+	constraints := tapfreighter.CommitmentConstraints{
+		AssetID: &assetID,
+	}
+	assets, err := r.cfg.AssetStore.FetchAllAssets(
+		ctx, false, true, &tapdb.AssetQueryFilters{
+			CommitmentConstraints: constraints,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read chain assets: %w", err)
+	}
+
+	// The script key may be given in the Schnorr format, so we compare
+	// the x-only keys.
+	chainAsset, err := fn.First(assets, func(a *tapdb.ChainAsset) bool {
+		return bytes.Equal(
+			schnorr.SerializePubKey(a.ScriptKey.PubKey),
+			schnorr.SerializePubKey(scriptKey),
 		)
-		for a := range raw.Recipients {
-			addr, err = address.DecodeAddress(a, &tapParams)
-			if err != nil {
-				return nil, fmt.Errorf("unable to decode "+
-					"addr: %w", err)
-			}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no owned asset with asset ID %v and "+
+			"script key %x found", assetID,
+			scriptKey.SerializeCompressed())
+	}
+
+	if chainAsset.ScriptKey.TweakedScriptKey == nil {
+		return nil, fmt.Errorf("script key %x is not a local key",
+			scriptKey.SerializeCompressed())
+	}
+	tweakedScriptKey := chainAsset.ScriptKey.TweakedScriptKey
+
+	anchorKeyLoc, err := r.cfg.TapAddrBook.FetchInternalKeyLocator(
+		ctx, chainAsset.AnchorInternalKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch anchor internal key: "+
+			"%w", err)
+	}
+	anchorKeyDesc := keychain.KeyDescriptor{
+		PubKey:     chainAsset.AnchorInternalKey,
+		KeyLocator: anchorKeyLoc,
+	}
+
+	tapParams := address.ParamsForChain(r.cfg.ChainParams.Name)
+	marshalDerivation := func(
+		desc keychain.KeyDescriptor) *wrpc.KeyDerivation {
+
+		derivation, _ := tappsbt.Bip32DerivationFromKeyDesc(
+			desc, tapParams.HDCoinType,
+		)
+
+		return &wrpc.KeyDerivation{
+			KeyDesc:        marshalKeyDescriptor(desc),
+			DerivationPath: formatBip32Path(derivation.Bip32Path),
 		}
+	}
 
-		if addr == nil {
-			return nil, fmt.Errorf("no recipients specified")
+	return &wrpc.GetKeyDerivationResponse{
+		ScriptKeyDerivation: marshalDerivation(tweakedScriptKey.RawKey),
+		ScriptKeyTapTweak:   tweakedScriptKey.Tweak,
+		AnchorInternalKeyDerivation: marshalDerivation(
+			anchorKeyDesc,
+		),
+		AnchorOutpoint: chainAsset.AnchorOutpoint.String(),
+	}, nil
+}
+
+// formatBip32Path formats the given BIP-0032 derivation path in its string
+// form, for example m/1017'/0'/6'/0/1.
+func formatBip32Path(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, element := range path {
+		if element >= hdkeychain.HardenedKeyStart {
+			fmt.Fprintf(
+				&b, "/%d'", element-hdkeychain.HardenedKeyStart,
+			)
+			continue
 		}
 
-		fundedVPkt, _, err = r.cfg.AssetWallet.FundAddressSend(
-			ctx, addr,
-		)
+		fmt.Fprintf(&b, "/%d", element)
+	}
+
+	return b.String()
+}
+
+// marshalAddr turns an address into its RPC counterpart.
+func marshalAddr(addr *address.Tap,
+	db address.Storage) (*taprpc.Addr, error) {
+
+	addrStr, err := addr.EncodeAddress()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode addr: %w", err)
+	}
+
+	// We can only derive the taproot output if we already know the genesis
+	// for this asset, as that's required to make the template asset that
+	// will be committed to in the tapscript tree.
+	var taprootOutputKey []byte
+	assetGroup, err := db.QueryAssetGroup(
+		context.Background(), addr.AssetID,
+	)
+	if err == nil {
+		addr.AttachGenesis(*assetGroup.Genesis)
+
+		outputKey, err := addr.TaprootOutputKey()
 		if err != nil {
-			return nil, fmt.Errorf("error funding address send: "+
-				"%w", err)
+			return nil, fmt.Errorf("error deriving Taproot "+
+				"output key: %w", err)
 		}
 
-	default:
-		return nil, fmt.Errorf("either PSBT or raw template must be " +
-			"specified")
+		taprootOutputKey = schnorr.SerializePubKey(outputKey)
+	}
+
+	siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
+		addr.TapscriptSibling,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding tapscript sibling: %w",
+			err)
+	}
+
+	assetVersion, err := taprpc.MarshalAssetVersion(addr.AssetVersion)
+	if err != nil {
+		return nil, err
 	}
 
-	var b bytes.Buffer
-	if err := fundedVPkt.VPacket.Serialize(&b); err != nil {
-		return nil, fmt.Errorf("error serializing packet: %w", err)
+	id := addr.AssetID
+	rpcAddr := &taprpc.Addr{
+		AssetVersion:     assetVersion,
+		Encoded:          addrStr,
+		AssetId:          id[:],
+		Amount:           addr.Amount,
+		ScriptKey:        addr.ScriptKey.SerializeCompressed(),
+		InternalKey:      addr.InternalKey.SerializeCompressed(),
+		TapscriptSibling: siblingBytes,
+		TaprootOutputKey: taprootOutputKey,
+		AssetType:        taprpc.AssetType(addr.AssetType()),
+		ProofCourierAddr: addr.ProofCourierAddr.String(),
+		AllowAnyAmount:   addr.AllowAnyAmount,
+		MinAmount:        addr.MinAmount,
 	}
 
-	return &wrpc.FundVirtualPsbtResponse{
-		FundedPsbt:        b.Bytes(),
-		ChangeOutputIndex: 0,
-	}, nil
-}
+	if addr.GroupKey != nil {
+		rpcAddr.GroupKey = addr.GroupKey.SerializeCompressed()
+	}
 
-// SignVirtualPsbt signs the inputs of a virtual transaction and prepares the
-// commitments of the inputs and outputs.
-func (r *rpcServer) SignVirtualPsbt(_ context.Context,
-	req *wrpc.SignVirtualPsbtRequest) (*wrpc.SignVirtualPsbtResponse,
-	error) {
+	return rpcAddr, nil
+}
 
-	if req.FundedPsbt == nil {
-		return nil, fmt.Errorf("request cannot be nil")
-	}
+// marshalAddrEvent turns an address event into its RPC counterpart.
+func marshalAddrEvent(event *address.Event,
+	db address.Storage) (*taprpc.AddrEvent, error) {
 
-	vPkt, err := tappsbt.NewFromRawBytes(
-		bytes.NewReader(req.FundedPsbt), false,
-	)
+	rpcAddr, err := marshalAddr(event.Addr.Tap, db)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding packet: %w", err)
+		return nil, fmt.Errorf("error marshaling addr: %w", err)
 	}
 
-	signedInputs, err := r.cfg.AssetWallet.SignVirtualPacket(vPkt)
+	rpcStatus, err := marshalAddrEventStatus(event.Status)
 	if err != nil {
-		return nil, fmt.Errorf("error signing packet: %w", err)
-	}
-
-	var b bytes.Buffer
-	if err := vPkt.Serialize(&b); err != nil {
-		return nil, fmt.Errorf("error serializing packet: %w", err)
+		return nil, fmt.Errorf("error marshaling status: %w", err)
 	}
 
-	return &wrpc.SignVirtualPsbtResponse{
-		SignedPsbt:   b.Bytes(),
-		SignedInputs: signedInputs,
+	return &taprpc.AddrEvent{
+		CreationTimeUnixSeconds: uint64(event.CreationTime.Unix()),
+		Addr:                    rpcAddr,
+		Status:                  rpcStatus,
+		Outpoint:                event.Outpoint.String(),
+		UtxoAmtSat:              uint64(event.Amt),
+		ConfirmationHeight:      event.ConfirmationHeight,
+		HasProof:                event.HasProof,
+		AssetAmt:                event.AssetAmt,
+		AssetShortfall:          event.Shortfall(),
+		Quarantined:             event.Quarantined,
 	}, nil
 }
 
-// AnchorVirtualPsbts merges and then commits multiple virtual transactions in
-// a single BTC level anchor transaction.
-//
-// TODO(guggero): Actually implement accepting and merging multiple
-// transactions.
-func (r *rpcServer) AnchorVirtualPsbts(ctx context.Context,
-	req *wrpc.AnchorVirtualPsbtsRequest) (*taprpc.SendAssetResponse,
-	error) {
+// unmarshalAddrEventStatus parses the RPC address event status into the native
+// counterpart.
+func unmarshalAddrEventStatus(
+	rpcStatus taprpc.AddrEventStatus) (address.Status, error) {
+
+	switch rpcStatus {
+	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_DETECTED:
+		return address.StatusTransactionDetected, nil
+
+	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_CONFIRMED:
+		return address.StatusTransactionConfirmed, nil
+
+	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_PROOF_RECEIVED:
+		return address.StatusProofReceived, nil
+
+	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_COMPLETED:
+		return address.StatusCompleted, nil
 
-	if len(req.VirtualPsbts) == 0 {
-		return nil, fmt.Errorf("no virtual PSBTs specified")
+	default:
+		return 0, fmt.Errorf("unknown address event status <%d>",
+			rpcStatus)
 	}
+}
+
+// marshalAddrEventStatus turns the address event status into the RPC
+// counterpart.
+func marshalAddrEventStatus(status address.Status) (taprpc.AddrEventStatus,
+	error) {
+
+	switch status {
+	case address.StatusTransactionDetected:
+		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_DETECTED,
+			nil
+
+	case address.StatusTransactionConfirmed:
+		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_CONFIRMED,
+			nil
+
+	case address.StatusProofReceived:
+		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_PROOF_RECEIVED,
+			nil
+
+	case address.StatusCompleted:
+		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_COMPLETED, nil
 
-	if len(req.VirtualPsbts) > 1 {
-		return nil, fmt.Errorf("only one virtual PSBT supported")
+	default:
+		return 0, fmt.Errorf("unknown address event status <%d>",
+			status)
 	}
+}
 
-	vPacket, err := tappsbt.NewFromRawBytes(
-		bytes.NewReader(req.VirtualPsbts[0]), false,
-	)
+// SendAsset uses one or multiple passed Taproot Asset address(es) to attempt to
+// complete an asset send. The method returns information w.r.t the on chain
+// send, as well as the proof file information the receiver needs to fully
+// receive the asset.
+func (r *rpcServer) SendAsset(_ context.Context,
+	req *taprpc.SendAssetRequest) (*taprpc.SendAssetResponse, error) {
+
+	tapAddrs, err := r.decodeSendAddrs(req.TapAddrs, req.Amounts)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding packet: %w", err)
+		return nil, err
 	}
 
-	if len(vPacket.Inputs) != 1 {
-		return nil, fmt.Errorf("only one input is currently supported")
-	}
+	inputs := make([]wire.OutPoint, len(req.InputOutpoints))
+	for idx, opStr := range req.InputOutpoints {
+		op, err := UnmarshalOutpoint(opStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input outpoint %v: %w",
+				opStr, err)
+		}
 
-	inputAsset := vPacket.Inputs[0].Asset()
-	prevID := vPacket.Inputs[0].PrevID
-	inputCommitment, err := r.cfg.AssetStore.FetchCommitment(
-		ctx, inputAsset.ID(), prevID.OutPoint, inputAsset.GroupKey,
-		&inputAsset.ScriptKey, true,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error fetching input commitment: %w",
-			err)
+		inputs[idx] = *op
 	}
 
-	rpcsLog.Debugf("Selected commitment for anchor point %v, requesting "+
-		"delivery", inputCommitment.AnchorPoint)
+	// The proof courier of the addresses can be overridden for this
+	// transfer.
+	var courierAddr *url.URL
+	if req.ProofCourierAddr != "" {
+		addr, err := proof.ParseCourierAddrString(
+			req.ProofCourierAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof courier "+
+				"address: %w", err)
+		}
 
-	resp, err := r.cfg.ChainPorter.RequestShipment(
-		tapfreighter.NewPreSignedParcel(
-			vPacket, tappsbt.InputCommitments{
-				0: inputCommitment.Commitment,
-			},
-		),
+		courierAddr = addr.Url()
+	}
+
+	addrParcel := tapfreighter.NewAddressParcel(
+		req.MaxInputs, inputs, req.ReserveFeeBumpAnchor,
+		chainfee.SatPerKWeight(req.FeeRate), courierAddr, req.Label,
+		req.IdempotencyKey, tapAddrs...,
 	)
+
+	// In dry-run mode we only preview the send without committing to
+	// anything.
+	if req.DryRun {
+		estimate, err := r.cfg.ChainPorter.EstimateShipment(addrParcel)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcEstimate, err := marshalSendEstimate(estimate)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling send "+
+				"estimate: %w", err)
+		}
+
+		return &taprpc.SendAssetResponse{
+			CoinRelaxations: marshalCoinRelaxations(
+				estimate.CoinRelaxations,
+			),
+			Estimate: rpcEstimate,
+		}, nil
+	}
+
+	resp, err := r.cfg.ChainPorter.RequestShipment(addrParcel)
 	if err != nil {
-		return nil, fmt.Errorf("error requesting delivery: %w", err)
+		return nil, err
 	}
 
 	parcel, err := marshalOutboundParcel(resp)
@@ -1695,258 +4154,323 @@ func (r *rpcServer) AnchorVirtualPsbts(ctx context.Context,
 	}
 
 	return &taprpc.SendAssetResponse{
-		Transfer: parcel,
+		Transfer:        parcel,
+		CoinRelaxations: marshalCoinRelaxations(resp.CoinRelaxations),
 	}, nil
 }
 
-// NextInternalKey derives the next internal key for the given key family and
-// stores it as an internal key in the database to make sure it is identified
-// as a local key later on when importing proofs. While an internal key can
-// also be used as the internal key of a script key, it is recommended to use
-// the NextScriptKey RPC instead, to make sure the tweaked Taproot output key
-// is also recognized as a local key.
-func (r *rpcServer) NextInternalKey(ctx context.Context,
-	req *wrpc.NextInternalKeyRequest) (*wrpc.NextInternalKeyResponse,
-	error) {
+// marshalCoinRelaxations turns the coin selection relaxations of a transfer
+// into their RPC counterpart.
+func marshalCoinRelaxations(
+	relaxations []tapfreighter.CoinRelaxation) []*taprpc.CoinRelaxation {
 
-	// Due to how we detect local keys, we need to make sure that the key
-	// family is not zero.
-	if req.KeyFamily == 0 {
-		return nil, fmt.Errorf("key family must be set to a non-zero " +
-			"value")
+	rpcRelaxations := make([]*taprpc.CoinRelaxation, len(relaxations))
+	for idx, relaxation := range relaxations {
+		rpcRelaxations[idx] = &taprpc.CoinRelaxation{
+			Constraint: relaxation.Step.String(),
+			Required:   relaxation.Required,
+			RelaxedTo:  relaxation.RelaxedTo,
+		}
 	}
 
-	keyDesc, err := r.cfg.AddrBook.NextInternalKey(ctx, keychain.KeyFamily(
-		req.KeyFamily,
-	))
-	if err != nil {
-		return nil, fmt.Errorf("error inserting internal key: %w", err)
+	return rpcRelaxations
+}
+
+// marshalSendEstimate turns the preview of a send into its RPC counterpart.
+func marshalSendEstimate(
+	estimate *tapfreighter.SendEstimate) (*taprpc.SendAssetEstimate,
+	error) {
+
+	var (
+		rpcInputs  []*taprpc.TransferInput
+		rpcOutputs []*taprpc.SendAssetEstimateOutput
+	)
+	for _, vPkt := range estimate.VPackets {
+		for _, vIn := range vPkt.Inputs {
+			var amount uint64
+			if vIn.Asset() != nil {
+				amount = vIn.Asset().Amount
+			}
+
+			rpcInputs = append(rpcInputs, &taprpc.TransferInput{
+				AnchorPoint: vIn.PrevID.OutPoint.String(),
+				AssetId:     fn.ByteSlice(vIn.PrevID.ID),
+				ScriptKey:   vIn.PrevID.ScriptKey[:],
+				Amount:      amount,
+			})
+		}
+
+		// All outputs of a virtual packet are of the same asset ID as
+		// its inputs.
+		assetID := vPkt.Inputs[0].PrevID.ID
+		for _, vOut := range vPkt.Outputs {
+			rpcOutputType, err := marshalOutputType(vOut.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			var scriptKey []byte
+			if vOut.ScriptKey.PubKey != nil {
+				scriptKey = vOut.ScriptKey.PubKey.
+					SerializeCompressed()
+			}
+
+			rpcOutput := &taprpc.SendAssetEstimateOutput{
+				AnchorOutputIndex: vOut.AnchorOutputIndex,
+				ScriptKey:         scriptKey,
+				Amount:            vOut.Amount,
+				OutputType:        rpcOutputType,
+				IsChange:          vOut.Type.IsSplitRoot(),
+				AssetId:           fn.ByteSlice(assetID),
+			}
+			rpcOutputs = append(rpcOutputs, rpcOutput)
+		}
 	}
 
-	return &wrpc.NextInternalKeyResponse{
-		InternalKey: marshalKeyDescriptor(keyDesc),
+	return &taprpc.SendAssetEstimate{
+		Inputs:           rpcInputs,
+		Outputs:          rpcOutputs,
+		NumPassiveAssets: uint32(estimate.NumPassiveAssets),
+		FeeRate:          uint32(estimate.FeeRate),
+		ChainFees:        int64(estimate.ChainFees),
 	}, nil
 }
 
-// NextScriptKey derives the next script key (and its corresponding internal
-// key) and stores them both in the database to make sure they are identified
-// as local keys later on when importing proofs.
-func (r *rpcServer) NextScriptKey(ctx context.Context,
-	req *wrpc.NextScriptKeyRequest) (*wrpc.NextScriptKeyResponse,
-	error) {
+// decodeSendAddrs decodes the given Taproot Asset addresses that should be
+// used as the recipients of a single transfer. If an amount is given for an
+// address, the address is converted to one that requests that amount, which
+// is only possible for addresses that allow any amount.
+func (r *rpcServer) decodeSendAddrs(addrStrings []string,
+	amounts []uint64) ([]*address.Tap, error) {
 
-	// Due to how we detect local keys, we need to make sure that the key
-	// family is not zero.
-	if req.KeyFamily == 0 {
-		return nil, fmt.Errorf("key family must be set to a non-zero " +
-			"value")
+	if len(addrStrings) == 0 {
+		return nil, fmt.Errorf("at least one addr is required")
 	}
 
-	scriptKey, err := r.cfg.AddrBook.NextScriptKey(ctx, keychain.KeyFamily(
-		req.KeyFamily,
-	))
-	if err != nil {
-		return nil, fmt.Errorf("error inserting internal key: %w", err)
+	if len(amounts) > len(addrStrings) {
+		return nil, fmt.Errorf("got %d amounts for %d addrs",
+			len(amounts), len(addrStrings))
+	}
+
+	var (
+		tapAddrs = make([]*address.Tap, len(addrStrings))
+		err      error
+	)
+	for idx := range addrStrings {
+		if addrStrings[idx] == "" {
+			return nil, fmt.Errorf("addr %d must be specified", idx)
+		}
+
+		tapAddrs[idx], err = r.decodeTapAddr(addrStrings[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		if idx < len(amounts) && amounts[idx] != 0 {
+			tapAddrs[idx], err = tapAddrs[idx].ForAmount(
+				amounts[idx],
+			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid amount for "+
+					"addr %d: %w", idx, err)
+			}
+		}
+
+		err = address.CheckAmount(
+			tapAddrs[idx].Amount, r.cfg.MaxAddrAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &wrpc.NextScriptKeyResponse{
-		ScriptKey: marshalScriptKey(scriptKey),
-	}, nil
+	return tapAddrs, nil
 }
 
-// marshalAddr turns an address into its RPC counterpart.
-func marshalAddr(addr *address.Tap,
-	db address.Storage) (*taprpc.Addr, error) {
+// PrepareTransfer funds, signs and persists the anchor transaction of a
+// transfer to the given addresses without broadcasting it.
+func (r *rpcServer) PrepareTransfer(_ context.Context,
+	req *taprpc.PrepareTransferRequest) (*taprpc.PrepareTransferResponse,
+	error) {
 
-	addrStr, err := addr.EncodeAddress()
+	tapAddrs, err := r.decodeSendAddrs(req.TapAddrs, nil)
 	if err != nil {
-		return nil, fmt.Errorf("unable to encode addr: %w", err)
+		return nil, err
 	}
 
-	// We can only derive the taproot output if we already know the genesis
-	// for this asset, as that's required to make the template asset that
-	// will be committed to in the tapscript tree.
-	var taprootOutputKey []byte
-	assetGroup, err := db.QueryAssetGroup(
-		context.Background(), addr.AssetID,
+	resp, err := r.cfg.ChainPorter.RequestShipment(
+		tapfreighter.NewStagedParcel(
+			tapfreighter.NewAddressParcel(
+				req.MaxInputs, nil, req.ReserveFeeBumpAnchor,
+				0, nil, "", "", tapAddrs...,
+			),
+		),
 	)
-	if err == nil {
-		addr.AttachGenesis(*assetGroup.Genesis)
-
-		outputKey, err := addr.TaprootOutputKey()
-		if err != nil {
-			return nil, fmt.Errorf("error deriving Taproot "+
-				"output key: %w", err)
-		}
-
-		taprootOutputKey = schnorr.SerializePubKey(outputKey)
+	if err != nil {
+		return nil, err
 	}
 
-	siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
-		addr.TapscriptSibling,
-	)
+	parcel, err := marshalOutboundParcel(resp)
 	if err != nil {
-		return nil, fmt.Errorf("error encoding tapscript sibling: %w",
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
 			err)
 	}
 
-	assetVersion, err := taprpc.MarshalAssetVersion(addr.AssetVersion)
+	return &taprpc.PrepareTransferResponse{
+		Transfer:   parcel,
+		AnchorTxid: resp.AnchorTx.TxHash().String(),
+		CoinRelaxations: marshalCoinRelaxations(
+			resp.CoinRelaxations,
+		),
+	}, nil
+}
+
+// BroadcastTransfer releases a transfer that was staged with PrepareTransfer
+// for broadcast.
+func (r *rpcServer) BroadcastTransfer(_ context.Context,
+	req *taprpc.BroadcastTransferRequest) (*taprpc.BroadcastTransferResponse,
+	error) {
+
+	anchorTXID, err := chainhash.NewHashFromStr(req.AnchorTxid)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid anchor txid: %w", err)
 	}
 
-	id := addr.AssetID
-	rpcAddr := &taprpc.Addr{
-		AssetVersion:     assetVersion,
-		Encoded:          addrStr,
-		AssetId:          id[:],
-		Amount:           addr.Amount,
-		ScriptKey:        addr.ScriptKey.SerializeCompressed(),
-		InternalKey:      addr.InternalKey.SerializeCompressed(),
-		TapscriptSibling: siblingBytes,
-		TaprootOutputKey: taprootOutputKey,
-		AssetType:        taprpc.AssetType(addr.AssetType()),
-		ProofCourierAddr: addr.ProofCourierAddr.String(),
+	resp, err := r.cfg.ChainPorter.ReleaseStagedParcel(*anchorTXID)
+	if err != nil {
+		return nil, err
 	}
 
-	if addr.GroupKey != nil {
-		rpcAddr.GroupKey = addr.GroupKey.SerializeCompressed()
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
 	}
 
-	return rpcAddr, nil
+	return &taprpc.BroadcastTransferResponse{
+		Transfer: parcel,
+	}, nil
 }
 
-// marshalAddrEvent turns an address event into its RPC counterpart.
-func marshalAddrEvent(event *address.Event,
-	db address.Storage) (*taprpc.AddrEvent, error) {
+// ExportPendingTransfer exports the state of a pending transfer, so another
+// daemon connected to the same lnd node can take it over.
+func (r *rpcServer) ExportPendingTransfer(_ context.Context,
+	req *taprpc.ExportPendingTransferRequest) (
+	*taprpc.ExportPendingTransferResponse, error) {
 
-	rpcAddr, err := marshalAddr(event.Addr.Tap, db)
+	anchorTXID, err := chainhash.NewHashFromStr(req.AnchorTxid)
 	if err != nil {
-		return nil, fmt.Errorf("error marshaling addr: %w", err)
+		return nil, fmt.Errorf("invalid anchor txid: %w", err)
 	}
 
-	rpcStatus, err := marshalAddrEventStatus(event.Status)
+	handoff, err := r.cfg.ChainPorter.ExportPendingParcel(*anchorTXID)
 	if err != nil {
-		return nil, fmt.Errorf("error marshaling status: %w", err)
+		return nil, err
 	}
 
-	return &taprpc.AddrEvent{
-		CreationTimeUnixSeconds: uint64(event.CreationTime.Unix()),
-		Addr:                    rpcAddr,
-		Status:                  rpcStatus,
-		Outpoint:                event.Outpoint.String(),
-		UtxoAmtSat:              uint64(event.Amt),
-		ConfirmationHeight:      event.ConfirmationHeight,
-		HasProof:                event.HasProof,
+	var buf bytes.Buffer
+	if err := handoff.Encode(&buf); err != nil {
+		return nil, fmt.Errorf("unable to encode pending transfer: %w",
+			err)
+	}
+
+	return &taprpc.ExportPendingTransferResponse{
+		TransferPackage: buf.Bytes(),
 	}, nil
 }
 
-// unmarshalAddrEventStatus parses the RPC address event status into the native
-// counterpart.
-func unmarshalAddrEventStatus(
-	rpcStatus taprpc.AddrEventStatus) (address.Status, error) {
-
-	switch rpcStatus {
-	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_DETECTED:
-		return address.StatusTransactionDetected, nil
+// ImportPendingTransfer takes over a pending transfer exported by another
+// daemon connected to the same lnd node.
+func (r *rpcServer) ImportPendingTransfer(_ context.Context,
+	req *taprpc.ImportPendingTransferRequest) (
+	*taprpc.ImportPendingTransferResponse, error) {
 
-	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_CONFIRMED:
-		return address.StatusTransactionConfirmed, nil
+	if len(req.TransferPackage) == 0 {
+		return nil, fmt.Errorf("transfer package must be specified")
+	}
 
-	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_PROOF_RECEIVED:
-		return address.StatusProofReceived, nil
+	var handoff tapfreighter.TransferHandoff
+	err := handoff.Decode(bytes.NewReader(req.TransferPackage))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode pending transfer: %w",
+			err)
+	}
 
-	case taprpc.AddrEventStatus_ADDR_EVENT_STATUS_COMPLETED:
-		return address.StatusCompleted, nil
+	resp, err := r.cfg.ChainPorter.ImportPendingParcel(&handoff)
+	if err != nil {
+		return nil, err
+	}
 
-	default:
-		return 0, fmt.Errorf("unknown address event status <%d>",
-			rpcStatus)
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
 	}
+
+	return &taprpc.ImportPendingTransferResponse{
+		Transfer: parcel,
+	}, nil
 }
 
-// marshalAddrEventStatus turns the address event status into the RPC
-// counterpart.
-func marshalAddrEventStatus(status address.Status) (taprpc.AddrEventStatus,
+// BumpTransferFee replaces the unconfirmed anchor transaction of a pending
+// transfer with one that pays a higher fee rate.
+func (r *rpcServer) BumpTransferFee(_ context.Context,
+	req *taprpc.BumpTransferFeeRequest) (*taprpc.BumpTransferFeeResponse,
 	error) {
 
-	switch status {
-	case address.StatusTransactionDetected:
-		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_DETECTED,
-			nil
-
-	case address.StatusTransactionConfirmed:
-		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_TRANSACTION_CONFIRMED,
-			nil
+	anchorTXID, err := chainhash.NewHashFromStr(req.AnchorTxid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anchor txid: %w", err)
+	}
 
-	case address.StatusProofReceived:
-		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_PROOF_RECEIVED,
-			nil
+	if req.FeeRate == 0 {
+		return nil, fmt.Errorf("fee rate must be specified")
+	}
 
-	case address.StatusCompleted:
-		return taprpc.AddrEventStatus_ADDR_EVENT_STATUS_COMPLETED, nil
+	feeRate := chainfee.SatPerKWeight(req.FeeRate)
 
-	default:
-		return 0, fmt.Errorf("unknown address event status <%d>",
-			status)
+	var resp *tapfreighter.OutboundParcel
+	if req.UseFeeBumpAnchor {
+		resp, err = r.cfg.ChainPorter.BumpParcelFeeCPFP(
+			*anchorTXID, feeRate,
+		)
+	} else {
+		resp, err = r.cfg.ChainPorter.BumpParcelFee(
+			*anchorTXID, feeRate,
+		)
+	}
+	if err != nil {
+		return nil, err
 	}
-}
 
-// SendAsset uses one or multiple passed Taproot Asset address(es) to attempt to
-// complete an asset send. The method returns information w.r.t the on chain
-// send, as well as the proof file information the receiver needs to fully
-// receive the asset.
-func (r *rpcServer) SendAsset(_ context.Context,
-	req *taprpc.SendAssetRequest) (*taprpc.SendAssetResponse, error) {
+	parcel, err := marshalOutboundParcel(resp)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling outbound parcel: %w",
+			err)
+	}
 
-	if len(req.TapAddrs) == 0 {
-		return nil, fmt.Errorf("at least one addr is required")
+	rpcResp := &taprpc.BumpTransferFeeResponse{
+		Transfer: parcel,
+	}
+	if resp.FeeBumpChildTXID != nil {
+		rpcResp.ChildTxid = resp.FeeBumpChildTXID.String()
 	}
 
-	var (
-		tapParams = address.ParamsForChain(r.cfg.ChainParams.Name)
-		tapAddrs  = make([]*address.Tap, len(req.TapAddrs))
-		err       error
-	)
-	for idx := range req.TapAddrs {
-		if req.TapAddrs[idx] == "" {
-			return nil, fmt.Errorf("addr %d must be specified", idx)
-		}
+	return rpcResp, nil
+}
 
-		tapAddrs[idx], err = address.DecodeAddress(
-			req.TapAddrs[idx], &tapParams,
-		)
-		if err != nil {
-			return nil, err
-		}
+// CancelTransfer cancels a pending transfer whose anchor transaction has not
+// yet confirmed and releases its asset inputs.
+func (r *rpcServer) CancelTransfer(_ context.Context,
+	req *taprpc.CancelTransferRequest) (*taprpc.CancelTransferResponse,
+	error) {
 
-		// Ensure all addrs are of the same asset ID. Within a single
-		// transfer (=a single virtual packet), we expect only to have
-		// inputs and outputs of the same asset ID. Multiple assets can
-		// be moved in a single BTC level anchor output, but the
-		// expectation is that they would be in separate virtual
-		// packets, one for each asset ID. They would then be merged
-		// into the same anchor output in the wallet's
-		// AnchorVirtualTransactions call.
-		//
-		// TODO(guggero): Support creating multiple virtual packets, one
-		// for each asset ID when the user wants to send multiple asset
-		// IDs at the same time without going through the PSBT flow.
-		//
-		// TODO(guggero): Revisit after we have a way to send fungible
-		// assets with different IDs to an address (non-interactive).
-		if idx > 0 {
-			if tapAddrs[idx].AssetID != tapAddrs[0].AssetID {
-				return nil, fmt.Errorf("all addrs must be of "+
-					"the same asset ID %v",
-					tapAddrs[0].AssetID)
-			}
-		}
+	anchorTXID, err := chainhash.NewHashFromStr(req.AnchorTxid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid anchor txid: %w", err)
 	}
 
-	resp, err := r.cfg.ChainPorter.RequestShipment(
-		tapfreighter.NewAddressParcel(tapAddrs...),
-	)
+	resp, err := r.cfg.ChainPorter.CancelPendingParcel(*anchorTXID)
 	if err != nil {
 		return nil, err
 	}
@@ -1957,7 +4481,7 @@ func (r *rpcServer) SendAsset(_ context.Context,
 			err)
 	}
 
-	return &taprpc.SendAssetResponse{
+	return &taprpc.CancelTransferResponse{
 		Transfer: parcel,
 	}, nil
 }
@@ -2102,6 +4626,15 @@ func marshalOutboundParcel(
 			return nil, err
 		}
 
+		// Burns are created as regular outputs, so we tell them apart
+		// by their script key, which provably can't be spent.
+		isBurn := len(out.WitnessData) > 0 && asset.IsBurnKey(
+			scriptPubKey, out.WitnessData[0],
+		)
+		if isBurn {
+			rpcOutType = taprpc.OutputType_OUTPUT_TYPE_BURN
+		}
+
 		assetVersion, err := taprpc.MarshalAssetVersion(
 			out.AssetVersion,
 		)
@@ -2109,15 +4642,22 @@ func marshalOutboundParcel(
 			return nil, err
 		}
 
+		proofDelivery, err := marshalProofDelivery(out.ProofDelivery)
+		if err != nil {
+			return nil, err
+		}
+
 		rpcOutputs[idx] = &taprpc.TransferOutput{
 			Anchor:              rpcAnchor,
 			ScriptKey:           scriptPubKey.SerializeCompressed(),
 			ScriptKeyIsLocal:    out.ScriptKeyLocal,
+			AssetId:             fn.ByteSlice(out.AssetID),
 			Amount:              out.Amount,
 			NewProofBlob:        out.ProofSuffix,
 			SplitCommitRootHash: splitCommitRoot,
 			OutputType:          rpcOutType,
 			AssetVersion:        assetVersion,
+			ProofDelivery:       proofDelivery,
 		}
 	}
 
@@ -2129,6 +4669,8 @@ func marshalOutboundParcel(
 		AnchorTxChainFees:  parcel.ChainFees,
 		Inputs:             rpcInputs,
 		Outputs:            rpcOutputs,
+		Staged:             parcel.Staged,
+		Label:              parcel.Label,
 	}, nil
 }
 
@@ -2149,45 +4691,225 @@ func marshalOutputType(outputType tappsbt.VOutputType) (taprpc.OutputType,
 	case tappsbt.TypePassiveSplitRoot:
 		return taprpc.OutputType_OUTPUT_TYPE_PASSIVE_SPLIT_ROOT, nil
 
-	case tappsbt.TypeSimplePassiveAssets:
-		return taprpc.OutputType_OUTPUT_TYPE_SIMPLE_PASSIVE_ASSETS, nil
+	case tappsbt.TypeSimplePassiveAssets:
+		return taprpc.OutputType_OUTPUT_TYPE_SIMPLE_PASSIVE_ASSETS, nil
+
+	default:
+		return 0, fmt.Errorf("unknown output type: %d", outputType)
+	}
+}
+
+// marshalProofDelivery turns the proof delivery record of a transfer output
+// into the RPC counterpart.
+func marshalProofDelivery(
+	delivery tapfreighter.ProofDelivery) (*taprpc.ProofDelivery, error) {
+
+	var status taprpc.ProofDeliveryStatus
+	switch delivery.Status {
+	case tapfreighter.ProofDeliveryStatusNone:
+		status = taprpc.ProofDeliveryStatus_PROOF_DELIVERY_STATUS_NONE
+
+	case tapfreighter.ProofDeliveryStatusDelivered:
+		status = taprpc.
+			ProofDeliveryStatus_PROOF_DELIVERY_STATUS_DELIVERED
+
+	case tapfreighter.ProofDeliveryStatusFailed:
+		status = taprpc.
+			ProofDeliveryStatus_PROOF_DELIVERY_STATUS_FAILED
+
+	case tapfreighter.ProofDeliveryStatusSkipped:
+		status = taprpc.
+			ProofDeliveryStatus_PROOF_DELIVERY_STATUS_SKIPPED
+
+	case tapfreighter.ProofDeliveryStatusPending:
+		status = taprpc.
+			ProofDeliveryStatus_PROOF_DELIVERY_STATUS_PENDING
+
+	default:
+		return nil, fmt.Errorf("unknown proof delivery status: %v",
+			delivery.Status)
+	}
+
+	rpcDelivery := &taprpc.ProofDelivery{
+		Status:          status,
+		NumAttempts:     delivery.NumAttempts,
+		ReceiptReceived: delivery.ReceiptReceived,
+	}
+	if len(delivery.CourierAddr) > 0 {
+		courierAddr, err := url.Parse(string(delivery.CourierAddr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof courier "+
+				"address: %w", err)
+		}
+
+		rpcDelivery.CourierType = courierAddr.Scheme
+		rpcDelivery.CourierAddr = courierAddr.String()
+	}
+
+	return rpcDelivery, nil
+}
+
+// GetSendStateMachine returns the definition of the state machine that
+// outbound transfers move through.
+func (r *rpcServer) GetSendStateMachine(_ context.Context,
+	_ *taprpc.GetSendStateMachineRequest) (
+	*taprpc.GetSendStateMachineResponse, error) {
+
+	states := tapfreighter.SendStates()
+	rpcStates := make([]*taprpc.SendStateDefinition, len(states))
+	for idx, state := range states {
+		successors := state.Successors()
+		rpcSuccessors := make([]string, len(successors))
+		for succIdx, successor := range successors {
+			rpcSuccessors[succIdx] = successor.String()
+		}
+
+		rpcStates[idx] = &taprpc.SendStateDefinition{
+			Name:       state.String(),
+			Initial:    state.IsInitial(),
+			Successors: rpcSuccessors,
+		}
+	}
+
+	return &taprpc.GetSendStateMachineResponse{
+		States: rpcStates,
+	}, nil
+}
+
+// SubscribeSendAssetEventNtfns registers a subscription to the event
+// notification stream which relates to the asset sending process. If requested,
+// the persisted events the client missed are replayed before the live events
+// are streamed.
+func (r *rpcServer) SubscribeSendAssetEventNtfns(
+	req *taprpc.SubscribeSendAssetEventNtfnsRequest,
+	ntfnStream taprpc.TaprootAssets_SubscribeSendAssetEventNtfnsServer) error {
+
+	if req.StartTimestamp < 0 {
+		return fmt.Errorf("start timestamp must not be negative")
+	}
+
+	// Create a new event subscriber and register it with the send event
+	// log. We will then read events from the subscriber. We subscribe
+	// before replaying the persisted events, so no event is missed in
+	// between.
+	eventSubscriber := fn.NewEventReceiver[*taprpc.SendAssetEvent](
+		fn.DefaultQueueSize,
+	)
+	r.sendEventLog.RegisterSubscriber(eventSubscriber)
+	defer func() {
+		_ = r.sendEventLog.RemoveSubscriber(eventSubscriber)
+	}()
+
+	// Replay the persisted events the client asked for. An event that
+	// was persisted while we replayed might also be received from the
+	// live subscription, so we skip the events we already sent.
+	lastEventID := req.AfterEventId
+	if req.StartTimestamp != 0 || req.AfterEventId != 0 {
+		var err error
+		lastEventID, err = r.sendEventLog.replayEvents(
+			ntfnStream.Context(), req.AfterEventId,
+			time.UnixMicro(req.StartTimestamp), ntfnStream.Send,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to replay send events: %w",
+				err)
+		}
+	}
+
+	// Loop and read from the send event subscription and forward to the
+	// RPC stream.
+	for {
+		select {
+		// Handle receiving a new event from the ChainPorter.
+		// The event was already mapped to the RPC event type and
+		// is sent over the stream.
+		case rpcEvent := <-eventSubscriber.NewItemCreated.ChanOut():
+			// Events that weren't persisted don't have an ID and
+			// can't have been replayed.
+			if rpcEvent.EventId != 0 &&
+				rpcEvent.EventId <= lastEventID {
+
+				continue
+			}
+
+			err := ntfnStream.Send(rpcEvent)
+			if err != nil {
+				return fmt.Errorf("failed to RPC stream send "+
+					"event: %w", err)
+			}
+
+		// Handle the case where the RPC stream is closed by the
+		// client.
+		case <-ntfnStream.Context().Done():
+			// Don't return an error if a normal context
+			// cancellation has occurred.
+			isCanceledContext := errors.Is(
+				ntfnStream.Context().Err(), context.Canceled,
+			)
+			if isCanceledContext {
+				return nil
+			}
+
+			return ntfnStream.Context().Err()
 
-	default:
-		return 0, fmt.Errorf("unknown output type: %d", outputType)
+		// Handle the case where the RPC server is shutting down.
+		case <-r.quit:
+			return nil
+		}
 	}
 }
 
-// SubscribeSendAssetEventNtfns registers a subscription to the event
-// notification stream which relates to the asset sending process.
-func (r *rpcServer) SubscribeSendAssetEventNtfns(
-	_ *taprpc.SubscribeSendAssetEventNtfnsRequest,
-	ntfnStream taprpc.TaprootAssets_SubscribeSendAssetEventNtfnsServer) error {
+// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+// notification stream which relates to the process of receiving assets with an
+// address.
+func (r *rpcServer) SubscribeReceiveAssetEventNtfns(
+	req *taprpc.SubscribeReceiveAssetEventNtfnsRequest,
+	ntfnStream taprpc.TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error {
+
+	var filterScriptKey *btcec.PublicKey
+	if len(req.FilterScriptKey) > 0 {
+		var err error
+		filterScriptKey, err = parseUserKey(req.FilterScriptKey)
+		if err != nil {
+			return fmt.Errorf("invalid filter script key: %w", err)
+		}
+	}
 
-	// Create a new event subscriber and pass a copy to the chain porter.
-	// We will then read events from the subscriber.
+	// Create a new event subscriber and pass a copy to the custodian. We
+	// will then read events from the subscriber.
 	eventSubscriber := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
 	defer eventSubscriber.Stop()
 
-	err := r.cfg.ChainPorter.RegisterSubscriber(eventSubscriber, false, false)
+	err := r.cfg.AssetCustodian.RegisterSubscriber(
+		eventSubscriber, false, false,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to register event notifications "+
 			"subscription: %w", err)
 	}
+	defer func() {
+		_ = r.cfg.AssetCustodian.RemoveSubscriber(eventSubscriber)
+	}()
 
-	// Loop and read from the ChainPorter event subscription and forward to
-	// the RPC stream.
 	for {
 		select {
-		// Handle receiving a new event from the ChainPorter.
-		// The event will be mapped to the RPC event type and
-		// sent over the stream.
 		case event := <-eventSubscriber.NewItemCreated.ChanOut():
+			receiveEvent, ok := event.(*tapgarden.AssetReceiveEvent)
+			if !ok {
+				continue
+			}
 
-			rpcEvent, err := marshallSendAssetEvent(event)
+			scriptKey := &receiveEvent.ScriptKey
+			if filterScriptKey != nil &&
+				!filterScriptKey.IsEqual(scriptKey) {
+
+				continue
+			}
+
+			rpcEvent, err := marshalReceiveAssetEvent(receiveEvent)
 			if err != nil {
-				return fmt.Errorf("failed to marshall "+
-					"ChainPorter event into RPC event: "+
-					"%w", err)
+				return fmt.Errorf("failed to marshal receive "+
+					"event: %w", err)
 			}
 
 			err = ntfnStream.Send(rpcEvent)
@@ -2217,6 +4939,65 @@ func (r *rpcServer) SubscribeSendAssetEventNtfns(
 	}
 }
 
+// marshalReceiveAssetEvent maps a custodian receive event to its RPC
+// counterpart.
+func marshalReceiveAssetEvent(
+	event *tapgarden.AssetReceiveEvent) (*taprpc.ReceiveAssetEvent, error) {
+
+	status, err := marshalReceiveStatus(event.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	var errStr string
+	if event.Err != nil {
+		errStr = event.Err.Error()
+	}
+
+	return &taprpc.ReceiveAssetEvent{
+		Timestamp: event.Timestamp().UnixMicro(),
+		ScriptKey: event.ScriptKey.SerializeCompressed(),
+		AssetId:   event.AssetID[:],
+		Outpoint:  event.OutPoint.String(),
+		Status:    status,
+		Error:     errStr,
+	}, nil
+}
+
+// marshalReceiveStatus maps a custodian receive status to its RPC counterpart.
+func marshalReceiveStatus(
+	status tapgarden.ReceiveStatus) (taprpc.ReceiveAssetStatus, error) {
+
+	switch status {
+	case tapgarden.ReceiveStatusDetected:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_DETECTED,
+			nil
+
+	case tapgarden.ReceiveStatusConfirmed:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_CONFIRMED,
+			nil
+
+	case tapgarden.ReceiveStatusProofReceived:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_PROOF_RECEIVED,
+			nil
+
+	case tapgarden.ReceiveStatusProofVerified:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_PROOF_VERIFIED,
+			nil
+
+	case tapgarden.ReceiveStatusCompleted:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_COMPLETED,
+			nil
+
+	case tapgarden.ReceiveStatusFailed:
+		return taprpc.ReceiveAssetStatus_RECEIVE_ASSET_STATUS_FAILED,
+			nil
+
+	default:
+		return 0, fmt.Errorf("unknown receive status: %v", status)
+	}
+}
+
 // marshallSendAssetEvent maps a ChainPorter event to its RPC counterpart.
 func marshallSendAssetEvent(
 	eventInterface fn.Event) (*taprpc.SendAssetEvent, error) {
@@ -2227,6 +5008,7 @@ func marshallSendAssetEvent(
 			ExecuteSendStateEvent: &taprpc.ExecuteSendStateEvent{
 				Timestamp: event.Timestamp().UnixMicro(),
 				SendState: event.SendState.String(),
+				Label:     event.Label,
 			},
 		}
 		return &taprpc.SendAssetEvent{
@@ -2234,15 +5016,135 @@ func marshallSendAssetEvent(
 		}, nil
 
 	case *proof.ReceiverProofBackoffWaitEvent:
+		rpcWaitEvent := &taprpc.ReceiverProofBackoffWaitEvent{
+			Timestamp:            event.Timestamp().UnixMicro(),
+			Backoff:              event.Backoff.Microseconds(),
+			TriesCounter:         event.TriesCounter,
+			NumTries:             event.NumTries,
+			NextAttemptTimestamp: event.NextAttempt.UnixMicro(),
+		}
 		eventRpc := taprpc.SendAssetEvent_ReceiverProofBackoffWaitEvent{
-			ReceiverProofBackoffWaitEvent: &taprpc.ReceiverProofBackoffWaitEvent{
+			ReceiverProofBackoffWaitEvent: rpcWaitEvent,
+		}
+		return &taprpc.SendAssetEvent{
+			Event: &eventRpc,
+		}, nil
+
+	case *proof.ReceiverProofDeliveryPausedEvent:
+		id := event.DeliveryID
+		eventRpc := &taprpc.SendAssetEvent_ReceiverProofDeliveryPausedEvent{
+			ReceiverProofDeliveryPausedEvent: &taprpc.ReceiverProofDeliveryPausedEvent{
+				Timestamp:      event.Timestamp().UnixMicro(),
+				AnchorOutpoint: id.AnchorOutPoint.String(),
+				ScriptKey:      id.ScriptKey[:],
+			},
+		}
+		return &taprpc.SendAssetEvent{
+			Event: eventRpc,
+		}, nil
+
+	case *tapfreighter.ReceiverProofDeliveredEvent:
+		rpcDeliveredEvent := &taprpc.ReceiverProofDeliveredEvent{
+			Timestamp:      event.Timestamp().UnixMicro(),
+			AnchorOutpoint: event.AnchorOutPoint.String(),
+			ScriptKey:      event.ScriptKey.SerializeCompressed(),
+			CourierAddr:    event.CourierAddr,
+			Fallback:       event.Fallback,
+		}
+		if event.Err != nil {
+			rpcDeliveredEvent.Error = event.Err.Error()
+		}
+
+		return &taprpc.SendAssetEvent{
+			Event: &taprpc.SendAssetEvent_ReceiverProofDeliveredEvent{
+				ReceiverProofDeliveredEvent: rpcDeliveredEvent,
+			},
+		}, nil
+
+	case *tapfreighter.TransferCompleteEvent:
+		eventRpc := &taprpc.SendAssetEvent_TransferCompleteEvent{
+			TransferCompleteEvent: &taprpc.TransferCompleteEvent{
 				Timestamp:    event.Timestamp().UnixMicro(),
-				Backoff:      event.Backoff.Microseconds(),
-				TriesCounter: event.TriesCounter,
+				AnchorTxid:   event.AnchorTXID.String(),
+				NumReceivers: uint32(event.NumReceivers),
+				NumDelivered: uint32(event.NumDelivered),
 			},
 		}
 		return &taprpc.SendAssetEvent{
-			Event: &eventRpc,
+			Event: eventRpc,
+		}, nil
+
+	case *tapfreighter.TransferReOrgEvent:
+		rpcReOrgEvent := &taprpc.TransferReOrgEvent{
+			Timestamp:  event.Timestamp().UnixMicro(),
+			AnchorTxid: event.AnchorTXID.String(),
+			Policy:     event.Policy.String(),
+		}
+		if event.Err != nil {
+			rpcReOrgEvent.Error = event.Err.Error()
+		}
+
+		return &taprpc.SendAssetEvent{
+			Event: &taprpc.SendAssetEvent_TransferReorgEvent{
+				TransferReorgEvent: rpcReOrgEvent,
+			},
+		}, nil
+
+	case *tapfreighter.TransferAbandonedEvent:
+		eventRpc := &taprpc.SendAssetEvent_TransferAbandonedEvent{
+			TransferAbandonedEvent: &taprpc.TransferAbandonedEvent{
+				Timestamp:       event.Timestamp().UnixMicro(),
+				AnchorTxid:      event.AnchorTXID.String(),
+				ConflictingTxid: event.ConflictingTXID.String(),
+				WillRetry:       event.WillRetry,
+			},
+		}
+		return &taprpc.SendAssetEvent{
+			Event: eventRpc,
+		}, nil
+
+	case *tapfreighter.TransferRetryEvent:
+		rpcRetryEvent := &taprpc.TransferRetryEvent{
+			Timestamp:     event.Timestamp().UnixMicro(),
+			AbandonedTxid: event.AbandonedTXID.String(),
+		}
+		if event.Err != nil {
+			rpcRetryEvent.Error = event.Err.Error()
+		} else {
+			newTXID := event.NewAnchorTXID.String()
+			rpcRetryEvent.NewAnchorTxid = newTXID
+		}
+
+		return &taprpc.SendAssetEvent{
+			Event: &taprpc.SendAssetEvent_TransferRetryEvent{
+				TransferRetryEvent: rpcRetryEvent,
+			},
+		}, nil
+
+	case *tapfreighter.TransferFeeBumpedEvent:
+		eventRpc := &taprpc.SendAssetEvent_TransferFeeBumpedEvent{
+			TransferFeeBumpedEvent: &taprpc.TransferFeeBumpedEvent{
+				Timestamp:     event.Timestamp().UnixMicro(),
+				OldAnchorTxid: event.OldAnchorTXID.String(),
+				NewAnchorTxid: event.NewAnchorTXID.String(),
+				FeeRate:       uint32(event.FeeRate),
+				ChainFees:     event.ChainFees,
+				Escalation:    event.Escalation,
+			},
+		}
+		return &taprpc.SendAssetEvent{
+			Event: eventRpc,
+		}, nil
+
+	case *tapfreighter.TransferCancelledEvent:
+		eventRpc := &taprpc.SendAssetEvent_TransferCancelledEvent{
+			TransferCancelledEvent: &taprpc.TransferCancelledEvent{
+				Timestamp:  event.Timestamp().UnixMicro(),
+				AnchorTxid: event.AnchorTXID.String(),
+			},
+		}
+		return &taprpc.SendAssetEvent{
+			Event: eventRpc,
 		}, nil
 
 	default:
@@ -2250,6 +5152,40 @@ func marshallSendAssetEvent(
 	}
 }
 
+// webhookSources returns the sources of the events that are delivered to the
+// configured webhook. These are the same events that are streamed by the send
+// and receive event subscriptions.
+func webhookSources(cfg *Config) []webhook.EventSource {
+	return []webhook.EventSource{{
+		Type:      "send",
+		Publisher: cfg.ChainPorter,
+		Marshal: func(event fn.Event) (proto.Message, error) {
+			rpcEvent, err := marshallSendAssetEvent(event)
+			if err != nil {
+				return nil, err
+			}
+
+			return rpcEvent, nil
+		},
+	}, {
+		Type:      "receive",
+		Publisher: cfg.AssetCustodian,
+		Marshal: func(event fn.Event) (proto.Message, error) {
+			receiveEvent, ok := event.(*tapgarden.AssetReceiveEvent)
+			if !ok {
+				return nil, nil
+			}
+
+			rpcEvent, err := marshalReceiveAssetEvent(receiveEvent)
+			if err != nil {
+				return nil, err
+			}
+
+			return rpcEvent, nil
+		},
+	}}
+}
+
 // marshalMintingBatch marshals a minting batch into the RPC counterpart.
 func marshalMintingBatch(batch *tapgarden.MintingBatch,
 	skipSeedlings bool) (*mintrpc.MintingBatch, error) {
@@ -2264,6 +5200,24 @@ func marshalMintingBatch(batch *tapgarden.MintingBatch,
 		State:    rpcBatchState,
 	}
 
+	// Once the batch is committed, the funded genesis packet is known,
+	// which also means we know the genesis TXID.
+	if batch.GenesisPacket != nil && batch.GenesisPacket.Pkt != nil {
+		genesisTx := batch.GenesisPacket.Pkt.UnsignedTx
+		rpcBatch.BatchTxid = genesisTx.TxHash().String()
+	}
+
+	if batch.FinalizeSchedule != nil {
+		schedule := batch.FinalizeSchedule
+		rpcSchedule := &mintrpc.BatchFinalizeSchedule{
+			MinSeedlings: uint32(schedule.MinSeedlings),
+		}
+		if !schedule.FinalizeTime.IsZero() {
+			rpcSchedule.FinalizeTime = schedule.FinalizeTime.Unix()
+		}
+		rpcBatch.FinalizeSchedule = rpcSchedule
+	}
+
 	// If we don't need to include the seedlings, we can return here.
 	if skipSeedlings {
 		return rpcBatch, nil
@@ -2313,12 +5267,12 @@ func marshalSeedlings(
 
 		var seedlingMeta *taprpc.AssetMeta
 		if seedling.Meta != nil {
+			meta := seedling.Meta
 			seedlingMeta = &taprpc.AssetMeta{
-				MetaHash: fn.ByteSlice(
-					seedling.Meta.MetaHash(),
-				),
-				Data: seedling.Meta.Data,
-				Type: taprpc.AssetMetaType(seedling.Meta.Type),
+				MetaHash:  fn.ByteSlice(meta.MetaHash()),
+				Data:      meta.Data,
+				Type:      taprpc.AssetMetaType(meta.Type),
+				SupplyCap: meta.SupplyCap,
 			}
 		}
 
@@ -2337,6 +5291,9 @@ func marshalSeedlings(
 			Amount:       seedling.Amount,
 			GroupKey:     groupKeyBytes,
 			GroupAnchor:  groupAnchor,
+			ScriptKeyTapscript: marshalScriptKeyTapscript(
+				seedling.ScriptKeyTapscript,
+			),
 		})
 	}
 
@@ -2355,9 +5312,10 @@ func marshalSprouts(sprouts []*asset.Asset,
 		if metas != nil {
 			if m, ok := metas[scriptKey]; ok && m != nil {
 				assetMeta = &taprpc.AssetMeta{
-					MetaHash: fn.ByteSlice(m.MetaHash()),
-					Data:     m.Data,
-					Type:     taprpc.AssetMetaType(m.Type),
+					MetaHash:  fn.ByteSlice(m.MetaHash()),
+					Data:      m.Data,
+					Type:      taprpc.AssetMetaType(m.Type),
+					SupplyCap: m.SupplyCap,
 				}
 			}
 		}
@@ -2465,6 +5423,63 @@ func marshalScriptKey(scriptKey asset.ScriptKey) *taprpc.ScriptKey {
 	return rpcScriptKey
 }
 
+// UnmarshalScriptKeyTapscript parses the RPC tapscript tree of a script key
+// into the native counterpart.
+func UnmarshalScriptKeyTapscript(
+	rpcTree *taprpc.ScriptKeyTapscript) (*asset.TapscriptTree, error) {
+
+	var tree *asset.TapscriptTree
+	switch {
+	case len(rpcTree.LeafScripts) > 0 && len(rpcTree.RootHash) > 0:
+		return nil, fmt.Errorf("cannot specify both leaf scripts and " +
+			"a root hash")
+
+	case len(rpcTree.LeafScripts) > 0:
+		tree = asset.NewTapscriptTreeFromLeaves(rpcTree.LeafScripts)
+
+	case len(rpcTree.RootHash) > 0:
+		rootHash, err := chainhash.NewHash(rpcTree.RootHash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid root hash: %w", err)
+		}
+		tree = &asset.TapscriptTree{RootHash: rootHash}
+
+	default:
+		return nil, asset.ErrEmptyTapscriptTree
+	}
+
+	if err := tree.Validate(); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
+
+// marshalScriptKeyTapscript marshals the native tapscript tree of a script key
+// into the RPC counterpart.
+func marshalScriptKeyTapscript(
+	tree *asset.TapscriptTree) *taprpc.ScriptKeyTapscript {
+
+	if tree == nil {
+		return nil
+	}
+
+	if tree.RootHash != nil {
+		return &taprpc.ScriptKeyTapscript{
+			RootHash: tree.RootHash[:],
+		}
+	}
+
+	leafScripts := make([][]byte, len(tree.Leaves))
+	for idx, leaf := range tree.Leaves {
+		leafScripts[idx] = leaf.Script
+	}
+
+	return &taprpc.ScriptKeyTapscript{
+		LeafScripts: leafScripts,
+	}
+}
+
 // parseUserKey parses a user-provided script or group key, which can be in
 // either the Schnorr or Compressed format.
 func parseUserKey(scriptKey []byte) (*btcec.PublicKey, error) {
@@ -2607,12 +5622,77 @@ func (r *rpcServer) FetchAssetMeta(ctx context.Context,
 
 	metaHash := assetMeta.MetaHash()
 	return &taprpc.AssetMeta{
-		Data:     assetMeta.Data,
-		Type:     taprpc.AssetMetaType(assetMeta.Type),
-		MetaHash: metaHash[:],
+		Data:      assetMeta.Data,
+		Type:      taprpc.AssetMetaType(assetMeta.Type),
+		MetaHash:  metaHash[:],
+		SupplyCap: assetMeta.SupplyCap,
 	}, nil
 }
 
+// SearchAssetsByMeta searches the assets known to the daemon by the content of
+// their meta data.
+func (r *rpcServer) SearchAssetsByMeta(ctx context.Context,
+	req *taprpc.SearchAssetsByMetaRequest) (*taprpc.SearchAssetsByMetaResponse,
+	error) {
+
+	// The matches are only returned to the caller, so the balances can be
+	// read from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
+	query, err := proof.ParseMetaQuery(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	balances, err := r.cfg.AssetStore.QueryBalancesByAsset(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list balances: %w", err)
+	}
+
+	geneses, err := r.cfg.AssetStore.FetchGenesesWithMeta(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch asset meta: %w", err)
+	}
+
+	resp := &taprpc.SearchAssetsByMetaResponse{}
+	for idx := range geneses {
+		gen := geneses[idx]
+
+		// Unless asked otherwise, we only search the assets we own.
+		assetID := gen.Genesis.ID()
+		balance, owned := balances[assetID]
+		if !owned && !req.IncludeUniverse {
+			continue
+		}
+
+		if !query.Matches(&gen.Meta) {
+			continue
+		}
+
+		metaHash := gen.Meta.MetaHash()
+		resp.Assets = append(resp.Assets, &taprpc.AssetMetaMatch{
+			AssetGenesis: &taprpc.GenesisInfo{
+				Version:      balance.Version,
+				GenesisPoint: gen.Genesis.FirstPrevOut.String(),
+				Name:         gen.Genesis.Tag,
+				MetaHash:     gen.Genesis.MetaHash[:],
+				AssetId:      assetID[:],
+				OutputIndex:  gen.Genesis.OutputIndex,
+			},
+			AssetType: taprpc.AssetType(gen.Genesis.Type),
+			Balance:   balance.Balance,
+			Meta: &taprpc.AssetMeta{
+				Data:      gen.Meta.Data,
+				Type:      taprpc.AssetMetaType(gen.Meta.Type),
+				MetaHash:  metaHash[:],
+				SupplyCap: gen.Meta.SupplyCap,
+			},
+		})
+	}
+
+	return resp, nil
+}
+
 // MarshalUniProofType marshals the universe proof type into the RPC
 // counterpart.
 func MarshalUniProofType(
@@ -2688,6 +5768,8 @@ func marshalUniverseRoot(node universe.BaseRoot) (*unirpc.UniverseRoot, error) {
 		MssmtRoot:        mssmtRoot,
 		AssetName:        node.AssetName,
 		AmountsByAssetId: rpcGroupedAssets,
+		IssuanceOrigin:   node.IssuanceOrigin,
+		RootVersion:      uint32(node.Version),
 	}, nil
 }
 
@@ -2696,6 +5778,10 @@ func marshalUniverseRoot(node universe.BaseRoot) (*unirpc.UniverseRoot, error) {
 func (r *rpcServer) AssetRoots(ctx context.Context,
 	_ *unirpc.AssetRootRequest) (*unirpc.AssetRootResponse, error) {
 
+	// The roots are only returned to the caller, so they can be read
+	// from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
 	// First, we'll retrieve the full set of known asset Universe roots.
 	assetRoots, err := r.cfg.BaseUniverse.RootNodes(ctx)
 	if err != nil {
@@ -2732,6 +5818,62 @@ func (r *rpcServer) AssetRoots(ctx context.Context,
 	return resp, nil
 }
 
+// ListAuthoritativeAssets lists the universe roots this server is the
+// issuance origin of, meaning the assets of the universe were minted by this
+// server.
+func (r *rpcServer) ListAuthoritativeAssets(ctx context.Context,
+	req *unirpc.ListAuthoritativeAssetsRequest) (
+	*unirpc.ListAuthoritativeAssetsResponse, error) {
+
+	// This only reads universe roots for the caller, which may be served
+	// by a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
+	proofType, err := UnmarshalUniProofType(req.ProofType)
+	if err != nil {
+		return nil, err
+	}
+
+	assetRoots, err := r.cfg.BaseUniverse.RootNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// We apply the same sync export filter as for the full list of roots,
+	// so this call doesn't reveal any roots that aren't otherwise public.
+	syncConfigs, err := r.cfg.UniverseFederation.QuerySyncConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &unirpc.ListAuthoritativeAssetsResponse{
+		UniverseRoots: make(map[string]*unirpc.UniverseRoot),
+	}
+	for _, assetRoot := range assetRoots {
+		if !assetRoot.IssuanceOrigin {
+			continue
+		}
+
+		if proofType != universe.ProofTypeUnspecified &&
+			assetRoot.ID.ProofType != proofType {
+
+			continue
+		}
+
+		if !syncConfigs.IsSyncExportEnabled(assetRoot.ID) {
+			continue
+		}
+
+		idStr := assetRoot.ID.String()
+		resp.UniverseRoots[idStr], err = marshalUniverseRoot(assetRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
 // UnmarshalUniProofType parses the RPC universe proof type into the native
 // counterpart.
 func UnmarshalUniProofType(rpcType unirpc.ProofType) (universe.ProofType,
@@ -2946,7 +6088,73 @@ func (r *rpcServer) DeleteAssetRoot(ctx context.Context,
 		return nil, err
 	}
 
-	return &unirpc.DeleteRootResponse{}, nil
+	return &unirpc.DeleteRootResponse{}, nil
+}
+
+// PruneUniverse removes the proofs of a given universe, or of all known
+// universes, that were confirmed in a block below the given height.
+func (r *rpcServer) PruneUniverse(ctx context.Context,
+	req *unirpc.PruneUniverseRequest) (*unirpc.PruneUniverseResponse,
+	error) {
+
+	// Pruning all proofs of all universes would wipe the whole universe
+	// server, so we require a height if no universe is specified.
+	if req.Id == nil && req.BeforeHeight == 0 {
+		return nil, fmt.Errorf("either a universe ID or a block " +
+			"height must be specified")
+	}
+
+	var universeIDs []universe.Identifier
+	switch {
+	case req.Id != nil:
+		universeID, err := UnmarshalUniID(req.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the universe proof type is unspecified, we'll prune both
+		// the issuance and transfer universes.
+		if universeID.ProofType != universe.ProofTypeUnspecified {
+			universeIDs = append(universeIDs, universeID)
+			break
+		}
+
+		issuanceID, transferID := universeID, universeID
+		issuanceID.ProofType = universe.ProofTypeIssuance
+		transferID.ProofType = universe.ProofTypeTransfer
+		universeIDs = append(universeIDs, issuanceID, transferID)
+
+	default:
+		roots, err := r.cfg.BaseUniverse.RootNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch universe "+
+				"roots: %w", err)
+		}
+
+		for _, root := range roots {
+			universeIDs = append(universeIDs, root.ID)
+		}
+	}
+
+	rpcsLog.Infof("Pruning %d universe(s) (before_height=%d)",
+		len(universeIDs), req.BeforeHeight)
+
+	var numPruned uint64
+	for _, universeID := range universeIDs {
+		n, err := r.cfg.BaseUniverse.PruneLeaves(
+			ctx, universeID, req.BeforeHeight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to prune universe %v: "+
+				"%w", universeID.StringForLog(), err)
+		}
+
+		numPruned += n
+	}
+
+	return &unirpc.PruneUniverseResponse{
+		NumPruned: numPruned,
+	}, nil
 }
 
 func marshalLeafKey(leafKey universe.LeafKey) *unirpc.AssetKey {
@@ -2978,6 +6186,10 @@ func (r *rpcServer) AssetLeafKeys(ctx context.Context,
 	// TODO(roasbeef): tell above if was tring or not, then would set
 	// below diff
 
+	// The keys are only returned to the caller, so they can be read from
+	// a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
 	leafKeys, err := r.cfg.BaseUniverse.UniverseLeafKeys(ctx, universeID)
 	if err != nil {
 		return nil, err
@@ -3037,6 +6249,10 @@ func (r *rpcServer) AssetLeaves(ctx context.Context,
 		return nil, err
 	}
 
+	// The leaves are only returned to the caller, so they can be read
+	// from a read replica.
+	ctx = tapdb.WithReplicaReads(ctx)
+
 	assetLeaves, err := r.cfg.BaseUniverse.MintingLeaves(ctx, universeID)
 	if err != nil {
 		return nil, err
@@ -3313,6 +6529,80 @@ func (r *rpcServer) QueryProof(ctx context.Context,
 	return r.marshalIssuanceProof(ctx, req, proof)
 }
 
+// QueryProofs attempts to query for the issuance or transfer proofs of a set
+// of leaves of a single universe at once.
+func (r *rpcServer) QueryProofs(ctx context.Context,
+	req *unirpc.QueryProofsRequest) (*unirpc.QueryProofsResponse, error) {
+
+	universeID, err := UnmarshalUniID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if universeID.ProofType == universe.ProofTypeUnspecified {
+		return nil, fmt.Errorf("proof type must be specified")
+	}
+
+	switch {
+	case len(req.LeafKeys) == 0:
+		return nil, fmt.Errorf("at least one leaf key must be " +
+			"specified")
+
+	case len(req.LeafKeys) > universe.MaxQueryProofsKeys:
+		return nil, fmt.Errorf("too many leaf keys: %d, max is %d",
+			len(req.LeafKeys), universe.MaxQueryProofsKeys)
+	}
+
+	leafKeys := make([]universe.LeafKey, 0, len(req.LeafKeys))
+	for _, rpcKey := range req.LeafKeys {
+		leafKey, err := unmarshalLeafKey(rpcKey)
+		if err != nil {
+			return nil, err
+		}
+
+		leafKeys = append(leafKeys, leafKey)
+	}
+
+	rpcsLog.Debugf("[QueryProofs]: fetching %d proofs at (universeID=%v)",
+		len(leafKeys), universeID)
+
+	// Make sure proof export is enabled for the given universe.
+	syncConfigs, err := r.cfg.UniverseFederation.QuerySyncConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !syncConfigs.IsSyncExportEnabled(universeID) {
+		return nil, fmt.Errorf("proof export is disabled for the " +
+			"given universe")
+	}
+
+	proofs, err := r.cfg.BaseUniverse.FetchIssuanceProofs(
+		ctx, universeID, leafKeys,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &unirpc.QueryProofsResponse{
+		Proofs: make([]*unirpc.AssetProofResponse, 0, len(proofs)),
+	}
+	for i, proof := range proofs {
+		rpcProof, err := r.marshalIssuanceProof(
+			ctx, &unirpc.UniverseKey{
+				Id:      req.Id,
+				LeafKey: req.LeafKeys[i],
+			}, proof,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Proofs = append(resp.Proofs, rpcProof)
+	}
+
+	return resp, nil
+}
+
 // unmarshalAssetLeaf unmarshals an asset leaf from the RPC form.
 func unmarshalAssetLeaf(leaf *unirpc.AssetLeaf) (*universe.Leaf, error) {
 	// We'll just pull the asset details from the serialized issuance proof
@@ -3344,6 +6634,13 @@ func unmarshalAssetLeaf(leaf *unirpc.AssetLeaf) (*universe.Leaf, error) {
 func (r *rpcServer) InsertProof(ctx context.Context,
 	req *unirpc.AssetProof) (*unirpc.AssetProofResponse, error) {
 
+	// A read-only universe only ever learns about new proofs through
+	// local minting, never from remote parties pushing them.
+	if r.cfg.UniverseReadOnly {
+		return nil, fmt.Errorf("proof insert is disabled, universe " +
+			"server is in read-only mode")
+	}
+
 	if req.Key == nil {
 		return nil, fmt.Errorf("key cannot be nil")
 	}
@@ -3475,108 +6772,500 @@ func (r *rpcServer) marshalUniverseDiff(ctx context.Context,
 				"root: %w", err)
 		}
 
-		leaves := make([]*unirpc.AssetLeaf, len(diff.NewLeafProofs))
-		for i, leaf := range diff.NewLeafProofs {
-			leaves[i], err = r.marshalAssetLeaf(ctx, leaf)
-			if err != nil {
-				return err
-			}
+		leaves := make([]*unirpc.AssetLeaf, len(diff.NewLeafProofs))
+		for i, leaf := range diff.NewLeafProofs {
+			leaves[i], err = r.marshalAssetLeaf(ctx, leaf)
+			if err != nil {
+				return err
+			}
+		}
+
+		resp.SyncedUniverses = append(
+			resp.SyncedUniverses, &unirpc.SyncedUniverse{
+				OldAssetRoot:   oldUniRoot,
+				NewAssetRoot:   newUniRoot,
+				NewAssetLeaves: leaves,
+			},
+		)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// SyncUniverse takes host information for a remote Universe server, then
+// attempts to synchronize either only the set of specified asset_ids, or all
+// assets if none are specified. The sync process will attempt to query for the
+// latest known root for each asset, performing tree based reconciliation to
+// arrive at a new shared root.
+func (r *rpcServer) SyncUniverse(ctx context.Context,
+	req *unirpc.SyncRequest) (*unirpc.SyncResponse, error) {
+
+	// TODO(roasbeef): have another layer, only allow single outstanding
+	// sync request per host?
+
+	syncMode, err := unmarshalUniverseSyncType(req.SyncMode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sync type: %w", err)
+	}
+	syncTargets, err := unmarshalSyncTargets(req.SyncTargets)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sync targets: %w", err)
+	}
+
+	uniAddr, err := r.syncServerAddr(req.UniverseHost)
+	if err != nil {
+		return nil, err
+	}
+
+	syncConfigs, err := r.federationSyncConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO(roasbeef): add layer of indirection in front of?
+	//  * just interface interaction
+	// TODO(ffranr): Sync via the FederationEnvoy rather than syncer.
+	universeDiff, err := r.cfg.UniverseSyncer.SyncUniverse(
+		ctx, uniAddr, syncMode, syncConfigs, syncTargets...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sync universe: %w", err)
+	}
+
+	return r.marshalUniverseDiff(ctx, universeDiff)
+}
+
+// EstimateSync estimates how much data a sync with a remote Universe server
+// would transfer and how long it would take, without actually syncing.
+func (r *rpcServer) EstimateSync(ctx context.Context,
+	req *unirpc.EstimateSyncRequest) (*unirpc.EstimateSyncResponse, error) {
+
+	syncMode, err := unmarshalUniverseSyncType(req.SyncMode)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sync type: %w", err)
+	}
+	syncTargets, err := unmarshalSyncTargets(req.SyncTargets)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse sync targets: %w", err)
+	}
+
+	uniAddr, err := r.syncServerAddr(req.UniverseHost)
+	if err != nil {
+		return nil, err
+	}
+
+	syncConfigs, err := r.federationSyncConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := r.cfg.UniverseSyncer.EstimateSync(
+		ctx, uniAddr, syncMode, syncConfigs, syncTargets...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to estimate universe sync: %w",
+			err)
+	}
+
+	return &unirpc.EstimateSyncResponse{
+		NumRoots:         estimate.NumRoots,
+		NumLeaves:        estimate.NumLeaves,
+		BatchSize:        estimate.BatchSize,
+		NumBatches:       estimate.NumBatches,
+		NumSampledLeaves: estimate.NumSamples,
+		EstimatedBytes:   estimate.TotalBytes,
+		EstimatedDurationSeconds: int64(
+			estimate.Duration.Seconds(),
+		),
+	}, nil
+}
+
+// syncServerAddr returns the address of the Universe server to sync with. If
+// no host is specified, we fall back to the default Universe server.
+func (r *rpcServer) syncServerAddr(host string) (universe.ServerAddr, error) {
+	if host != "" {
+		return universe.NewServerAddrFromStr(host), nil
+	}
+
+	defaultServer := r.cfg.UniverseFederation.DefaultServer()
+	if defaultServer == nil {
+		return universe.ServerAddr{}, fmt.Errorf("no universe host " +
+			"specified and no default universe server set")
+	}
+
+	return *defaultServer, nil
+}
+
+// federationSyncConfigs obtains the general and universe specific federation
+// sync configs.
+func (r *rpcServer) federationSyncConfigs(
+	ctx context.Context) (universe.SyncConfigs, error) {
+
+	queryFedSyncConfigs := r.cfg.FederationDB.QueryFederationSyncConfigs
+	globalConfigs, uniSyncConfigs, err := queryFedSyncConfigs(ctx)
+	if err != nil {
+		return universe.SyncConfigs{}, fmt.Errorf("unable to query "+
+			"federation sync config(s): %w", err)
+	}
+
+	return universe.SyncConfigs{
+		GlobalSyncConfigs: globalConfigs,
+		UniSyncConfigs:    uniSyncConfigs,
+	}, nil
+}
+
+// SetDefaultUniverse sets the Universe server that is used as the sync target
+// if a sync request doesn't specify one. The default is persisted and takes
+// precedence over the configured default server.
+func (r *rpcServer) SetDefaultUniverse(ctx context.Context,
+	req *unirpc.SetDefaultUniverseRequest,
+) (*unirpc.SetDefaultUniverseResponse, error) {
+
+	// An empty host clears the default.
+	if req.UniverseHost == "" {
+		err := r.cfg.UniverseFederation.SetDefaultServer(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return &unirpc.SetDefaultUniverseResponse{}, nil
+	}
+
+	// Before we accept the server as the default, we check that we can
+	// actually connect to it and that it isn't ourselves.
+	server := universe.NewServerAddrFromStr(req.UniverseHost)
+	err := CheckFederationServer(
+		r.cfg.RuntimeID, universe.DefaultTimeout, server,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to use %v as default universe "+
+			"server: %w", req.UniverseHost, err)
+	}
+
+	err = r.cfg.UniverseFederation.SetDefaultServer(ctx, &server)
+	if err != nil {
+		return nil, err
+	}
+
+	return &unirpc.SetDefaultUniverseResponse{}, nil
+}
+
+// GetDefaultUniverse returns the Universe server that is used as the sync
+// target if a sync request doesn't specify one.
+func (r *rpcServer) GetDefaultUniverse(_ context.Context,
+	_ *unirpc.GetDefaultUniverseRequest,
+) (*unirpc.GetDefaultUniverseResponse, error) {
+
+	var host string
+	if server := r.cfg.UniverseFederation.DefaultServer(); server != nil {
+		host = server.HostStr()
+	}
+
+	return &unirpc.GetDefaultUniverseResponse{
+		UniverseHost: host,
+	}, nil
+}
+
+func marshalUniverseServer(server universe.ServerAddr,
+) *unirpc.UniverseFederationServer {
+
+	return &unirpc.UniverseFederationServer{
+		Host: server.HostStr(),
+		Id:   int32(server.ID),
+	}
+}
+
+// ListFederationServers lists the set of servers that make up the federation
+// of the local Universe server. This servers are used to push out new proofs,
+// and also periodically call sync new proofs from the remote server. The
+// time of the last successful sync and the error of the last failed sync are
+// reported for each server.
+func (r *rpcServer) ListFederationServers(ctx context.Context,
+	_ *unirpc.ListFederationServersRequest,
+) (*unirpc.ListFederationServersResponse, error) {
+
+	uniServers, err := r.cfg.FederationDB.UniverseServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	syncTimes, err := r.cfg.FederationDB.LastSyncTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query last sync times: %w",
+			err)
+	}
+	syncErrors, err := r.cfg.FederationDB.LastSyncErrors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query last sync errors: %w",
+			err)
+	}
+
+	servers := make([]*unirpc.UniverseFederationServer, 0, len(uniServers))
+	for _, uniServer := range uniServers {
+		server := marshalUniverseServer(uniServer)
+
+		lastSync, ok := syncTimes[uniServer.HostStr()]
+		if ok {
+			server.LastSyncTimestamp = lastSync.Unix()
+		}
+
+		if syncErr, ok := syncErrors[uniServer.HostStr()]; ok {
+			server.LastSyncError = syncErr
+		}
+
+		servers = append(servers, server)
+	}
+
+	return &unirpc.ListFederationServersResponse{
+		Servers: servers,
+	}, nil
+}
+
+func unmarshalUniverseServer(
+	server *unirpc.UniverseFederationServer) universe.ServerAddr {
+
+	return universe.NewServerAddr(int64(server.Id), server.Host)
+}
+
+// ListFederationPushBacklog lists the failed proof pushes to each member of
+// the federation that are queued to be retried.
+func (r *rpcServer) ListFederationPushBacklog(ctx context.Context,
+	req *unirpc.ListFederationPushBacklogRequest,
+) (*unirpc.ListFederationPushBacklogResponse, error) {
+
+	uniServers, err := r.cfg.FederationDB.UniverseServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingPushes, err := r.cfg.UniverseFederation.PendingPushes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	backlogs := make(map[int64]*unirpc.FederationPushBacklog)
+	regBacklogs := make(map[string]*unirpc.FederationPushBacklog)
+	resp := &unirpc.ListFederationPushBacklogResponse{}
+	for _, server := range uniServers {
+		if req.ServerHost != "" && server.HostStr() != req.ServerHost {
+			continue
+		}
+
+		backlog := &unirpc.FederationPushBacklog{
+			Server: marshalUniverseServer(server),
+		}
+		backlogs[server.ID] = backlog
+		resp.Backlogs = append(resp.Backlogs, backlog)
+	}
+
+	for _, push := range pendingPushes {
+		var backlog *unirpc.FederationPushBacklog
+		switch {
+		// The registration pushes to the default Universe server are
+		// listed in a backlog of their own, as the server isn't
+		// necessarily a federation member.
+		case push.Registration:
+			host := push.Server.HostStr()
+			if req.ServerHost != "" && host != req.ServerHost {
+				continue
+			}
+
+			backlog = regBacklogs[host]
+			if backlog == nil {
+				server := marshalUniverseServer(push.Server)
+				backlog = &unirpc.FederationPushBacklog{
+					Server:       server,
+					Registration: true,
+				}
+				regBacklogs[host] = backlog
+				resp.Backlogs = append(resp.Backlogs, backlog)
+			}
+
+		default:
+			backlog = backlogs[push.Server.ID]
+		}
+		if backlog == nil {
+			continue
+		}
+
+		uniID, err := MarshalUniID(push.UniverseID)
+		if err != nil {
+			return nil, err
 		}
 
-		resp.SyncedUniverses = append(
-			resp.SyncedUniverses, &unirpc.SyncedUniverse{
-				OldAssetRoot:   oldUniRoot,
-				NewAssetRoot:   newUniRoot,
-				NewAssetLeaves: leaves,
+		backlog.PendingPushes = append(
+			backlog.PendingPushes, &unirpc.PendingFederationPush{
+				Key: &unirpc.UniverseKey{
+					Id:      uniID,
+					LeafKey: marshalLeafKey(push.LeafKey),
+				},
+				NumAttempts:          push.NumAttempts,
+				NextAttemptTimestamp: push.NextAttempt.Unix(),
+				LastError:            push.LastError,
+				CreationTimestamp:    push.CreationTime.Unix(),
 			},
 		)
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	return resp, nil
 }
 
-// SyncUniverse takes host information for a remote Universe server, then
-// attempts to synchronize either only the set of specified asset_ids, or all
-// assets if none are specified. The sync process will attempt to query for the
-// latest known root for each asset, performing tree based reconciliation to
-// arrive at a new shared root.
-func (r *rpcServer) SyncUniverse(ctx context.Context,
-	req *unirpc.SyncRequest) (*unirpc.SyncResponse, error) {
+// SubscribeIssuancePushEvents subscribes to the results of pushing the issuance
+// proofs of minted assets to the federation and the default Universe server.
+func (r *rpcServer) SubscribeIssuancePushEvents(
+	_ *unirpc.SubscribeIssuancePushEventsRequest,
+	ntfnStream unirpc.Universe_SubscribeIssuancePushEventsServer) error {
 
-	// TODO(roasbeef): have another layer, only allow single outstanding
-	// sync request per host?
+	eventSubscriber := fn.NewEventReceiver[*universe.IssuancePushEvent](
+		fn.DefaultQueueSize,
+	)
+	r.cfg.UniverseFederation.RegisterPushSubscriber(eventSubscriber)
+	defer func() {
+		_ = r.cfg.UniverseFederation.RemovePushSubscriber(
+			eventSubscriber,
+		)
+	}()
 
-	syncMode, err := unmarshalUniverseSyncType(req.SyncMode)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse sync type: %w", err)
+	for {
+		select {
+		case event := <-eventSubscriber.NewItemCreated.ChanOut():
+			uniID, err := MarshalUniID(event.ID)
+			if err != nil {
+				return err
+			}
+
+			rpcEvent := &unirpc.IssuancePushEvent{
+				Timestamp:  event.Timestamp().UnixMicro(),
+				ServerHost: event.Server.HostStr(),
+				Key: &unirpc.UniverseKey{
+					Id:      uniID,
+					LeafKey: marshalLeafKey(event.Key),
+				},
+				Retry: event.Retry,
+			}
+			if event.Error != nil {
+				rpcEvent.Error = event.Error.Error()
+			}
+
+			err = ntfnStream.Send(rpcEvent)
+			if err != nil {
+				return fmt.Errorf("failed to RPC stream send "+
+					"event: %w", err)
+			}
+
+		// Handle the case where the RPC stream is closed by the
+		// client.
+		case <-ntfnStream.Context().Done():
+			// Don't return an error if a normal context
+			// cancellation has occurred.
+			isCanceledContext := errors.Is(
+				ntfnStream.Context().Err(), context.Canceled,
+			)
+			if isCanceledContext {
+				return nil
+			}
+
+			return ntfnStream.Context().Err()
+
+		// Handle the case where the RPC server is shutting down.
+		case <-r.quit:
+			return nil
+		}
 	}
-	syncTargets, err := unmarshalSyncTargets(req.SyncTargets)
+}
+
+// GetFederationSyncStatus reports, for each member of the federation, the last
+// successful sync, the number of universe roots the member has that the local
+// Universe server doesn't and an estimate of how far the local Universe server
+// lags behind the member.
+func (r *rpcServer) GetFederationSyncStatus(ctx context.Context,
+	req *unirpc.GetFederationSyncStatusRequest,
+) (*unirpc.GetFederationSyncStatusResponse, error) {
+
+	uniServers, err := r.cfg.FederationDB.UniverseServers(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse sync targets: %w", err)
+		return nil, err
 	}
 
-	uniAddr := universe.NewServerAddrFromStr(req.UniverseHost)
-
-	// Obtain the general and universe specific federation sync configs.
-	queryFedSyncConfigs := r.cfg.FederationDB.QueryFederationSyncConfigs
-	globalConfigs, uniSyncConfigs, err := queryFedSyncConfigs(ctx)
+	syncTimes, err := r.cfg.FederationDB.LastSyncTimes(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to query federation sync "+
-			"config(s): %w", err)
+		return nil, fmt.Errorf("unable to query last sync times: %w",
+			err)
 	}
 
-	syncConfigs := universe.SyncConfigs{
-		GlobalSyncConfigs: globalConfigs,
-		UniSyncConfigs:    uniSyncConfigs,
+	syncConfigs, err := r.cfg.UniverseFederation.QuerySyncConfigs(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO(roasbeef): add layer of indirection in front of?
-	//  * just interface interaction
-	// TODO(ffranr): Sync via the FederationEnvoy rather than syncer.
-	universeDiff, err := r.cfg.UniverseSyncer.SyncUniverse(
-		ctx, uniAddr, syncMode, syncConfigs, syncTargets...,
-	)
+	localRoots, err := r.cfg.BaseUniverse.RootNodes(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to sync universe: %w", err)
+		return nil, fmt.Errorf("unable to fetch local universe "+
+			"roots: %w", err)
 	}
 
-	return r.marshalUniverseDiff(ctx, universeDiff)
-}
+	maxPushes, inFlightPushes := r.cfg.UniverseFederation.PushConcurrency()
+	resp := &unirpc.GetFederationSyncStatusResponse{
+		MaxConcurrentPushes: uint32(maxPushes),
+		InFlightPushes:      uint32(inFlightPushes),
+	}
+	for _, server := range uniServers {
+		if req.ServerHost != "" && server.HostStr() != req.ServerHost {
+			continue
+		}
 
-func marshalUniverseServer(server universe.ServerAddr,
-) *unirpc.UniverseFederationServer {
+		lastSync := syncTimes[server.HostStr()]
+		status := &unirpc.FederationSyncStatus{
+			Server:            marshalUniverseServer(server),
+			LastSyncTimestamp: lastSync.Unix(),
+		}
+		resp.Statuses = append(resp.Statuses, status)
 
-	return &unirpc.UniverseFederationServer{
-		Host: server.HostStr(),
-		Id:   int32(server.ID),
+		// A member that can't be reached is reported with the error
+		// instead of failing the whole call, as that's exactly the
+		// kind of member this call should help to spot.
+		remoteRoots, err := r.fetchRemoteRoots(ctx, server)
+		if err != nil {
+			status.Error = err.Error()
+			continue
+		}
+
+		numMissing, numDiverged := universe.CountRootDiff(
+			localRoots, remoteRoots, *syncConfigs,
+		)
+		status.NumMissingRoots = numMissing
+		status.NumDivergedRoots = numDiverged
+
+		if numMissing > 0 || numDiverged > 0 {
+			lag := time.Since(lastSync)
+			status.EstimatedLagSeconds = int64(lag.Seconds())
+		}
 	}
+
+	return resp, nil
 }
 
-// ListFederationServers lists the set of servers that make up the federation
-// of the local Universe server. This servers are used to push out new proofs,
-// and also periodically call sync new proofs from the remote server.
-func (r *rpcServer) ListFederationServers(ctx context.Context,
-	_ *unirpc.ListFederationServersRequest,
-) (*unirpc.ListFederationServersResponse, error) {
+// fetchRemoteRoots fetches all universe roots of the given remote Universe
+// server.
+func (r *rpcServer) fetchRemoteRoots(ctx context.Context,
+	server universe.ServerAddr) ([]universe.BaseRoot, error) {
 
-	uniServers, err := r.cfg.FederationDB.UniverseServers(ctx)
+	diffEngine, err := NewRpcUniverseDiff(server)
 	if err != nil {
 		return nil, err
 	}
 
-	return &unirpc.ListFederationServersResponse{
-		Servers: fn.Map(uniServers, marshalUniverseServer),
-	}, nil
-}
-
-func unmarshalUniverseServer(
-	server *unirpc.UniverseFederationServer) universe.ServerAddr {
+	remoteRoots, err := diffEngine.RootNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch remote universe "+
+			"roots: %w", err)
+	}
 
-	return universe.NewServerAddr(int64(server.Id), server.Host)
+	return remoteRoots, nil
 }
 
 // AddFederationServer adds a new server to the federation of the local
@@ -3733,10 +7422,26 @@ func (r *rpcServer) QueryFederationSyncConfig(ctx context.Context,
 	}, nil
 }
 
+// parseOwnershipChallenge parses the optional challenge of an ownership proof.
+// If no challenge is given, nil is returned.
+func parseOwnershipChallenge(challenge []byte) (*[32]byte, error) {
+	switch len(challenge) {
+	case 0:
+		return nil, nil
+
+	case 32:
+		challengeBytes := fn.ToArray[[32]byte](challenge)
+		return &challengeBytes, nil
+
+	default:
+		return nil, fmt.Errorf("challenge must be 32 bytes")
+	}
+}
+
 // ProveAssetOwnership creates an ownership proof embedded in an asset
 // transition proof. That ownership proof is a signed virtual transaction
 // spending the asset with a valid witness to prove the prover owns the keys
-// that can spend the asset.
+// that can spend the asset. If a challenge is given, the witness commits to it.
 func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 	req *wrpc.ProveAssetOwnershipRequest) (*wrpc.ProveAssetOwnershipResponse,
 	error) {
@@ -3754,6 +7459,11 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 		return nil, fmt.Errorf("asset ID must be 32 bytes")
 	}
 
+	challenge, err := parseOwnershipChallenge(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
 	assetID := fn.ToArray[asset.ID](req.AssetId)
 	proofBlob, err := r.cfg.ProofArchive.FetchProof(ctx, proof.Locator{
 		AssetID:   &assetID,
@@ -3788,7 +7498,7 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 	}
 
 	challengeWitness, err := r.cfg.AssetWallet.SignOwnershipProof(
-		inputCommitment.Asset.Copy(),
+		inputCommitment.Asset.Copy(), challenge,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error signing ownership proof: %w", err)
@@ -3813,6 +7523,7 @@ func (r *rpcServer) ProveAssetOwnership(ctx context.Context,
 
 // VerifyAssetOwnership verifies the asset ownership proof embedded in the
 // given transition proof of an asset and returns true if the proof is valid.
+// If a challenge is given, the proof must have been created for it.
 func (r *rpcServer) VerifyAssetOwnership(ctx context.Context,
 	req *wrpc.VerifyAssetOwnershipRequest) (*wrpc.VerifyAssetOwnershipResponse,
 	error) {
@@ -3821,15 +7532,22 @@ func (r *rpcServer) VerifyAssetOwnership(ctx context.Context,
 		return nil, fmt.Errorf("a valid proof must be specified")
 	}
 
+	challenge, err := parseOwnershipChallenge(req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
 	p := &proof.Proof{}
-	err := p.Decode(bytes.NewReader(req.ProofWithWitness))
+	err = p.Decode(bytes.NewReader(req.ProofWithWitness))
 	if err != nil {
 		return nil, fmt.Errorf("cannot decode proof file: %w", err)
 	}
 
 	headerVerifier := tapgarden.GenHeaderVerifier(ctx, r.cfg.ChainBridge)
 	groupVerifier := tapgarden.GenGroupVerifier(ctx, r.cfg.MintingStore)
-	_, err = p.Verify(ctx, nil, headerVerifier, groupVerifier)
+	_, err = p.VerifyOwnership(
+		ctx, challenge, headerVerifier, groupVerifier,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("error verifying proof: %w", err)
 	}
@@ -3854,6 +7572,9 @@ func (r *rpcServer) UniverseStats(ctx context.Context,
 		NumTotalGroups: int64(universeStats.NumTotalGroups),
 		NumTotalSyncs:  int64(universeStats.NumTotalSyncs),
 		NumTotalProofs: int64(universeStats.NumTotalProofs),
+
+		NumRecentSyncs:  int64(universeStats.NumRecentSyncs),
+		NumRecentProofs: int64(universeStats.NumRecentProofs),
 	}, nil
 }
 
@@ -4016,26 +7737,260 @@ func (r *rpcServer) RemoveUTXOLease(ctx context.Context,
 	req *wrpc.RemoveUTXOLeaseRequest) (*wrpc.RemoveUTXOLeaseResponse,
 	error) {
 
-	if req.Outpoint == nil {
-		return nil, fmt.Errorf("outpoint must be specified")
+	outPoint, err := unmarshalWalletOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, err
 	}
 
-	hash, err := chainhash.NewHash(req.Outpoint.Txid)
+	err = r.cfg.CoinSelect.ReleaseCoins(ctx, outPoint)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing txid: %w", err)
+		return nil, err
 	}
 
-	outPoint := wire.OutPoint{
-		Hash:  *hash,
-		Index: req.Outpoint.OutputIndex,
+	return &wrpc.RemoveUTXOLeaseResponse{}, nil
+}
+
+// ListLeases lists the leases/locks/reservations of managed UTXOs that haven't
+// expired yet.
+func (r *rpcServer) ListLeases(ctx context.Context,
+	_ *wrpc.ListLeasesRequest) (*wrpc.ListLeasesResponse, error) {
+
+	leases, err := r.cfg.CoinSelect.ListLeases(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	err = r.cfg.CoinSelect.ReleaseCoins(ctx, outPoint)
+	rpcLeases := make([]*wrpc.UTXOLease, len(leases))
+	for idx := range leases {
+		lease := leases[idx]
+		rpcLeases[idx] = &wrpc.UTXOLease{
+			Outpoint: &wrpc.OutPoint{
+				Txid:        lease.OutPoint.Hash[:],
+				OutputIndex: lease.OutPoint.Index,
+			},
+			AmtSats:     int64(lease.AmtSats),
+			LeaseOwner:  lease.LeaseOwner[:],
+			LeaseExpiry: lease.Expiry.Unix(),
+		}
+
+		if lease.TransferAnchorTxID != nil {
+			rpcLeases[idx].TransferAnchorTxHash =
+				lease.TransferAnchorTxID[:]
+		}
+	}
+
+	return &wrpc.ListLeasesResponse{
+		Leases: rpcLeases,
+	}, nil
+}
+
+// ReleaseLease releases the lease/lock/reservation of the given managed UTXO
+// if it is held by the given lease owner.
+func (r *rpcServer) ReleaseLease(ctx context.Context,
+	req *wrpc.ReleaseLeaseRequest) (*wrpc.ReleaseLeaseResponse, error) {
+
+	outPoint, err := unmarshalWalletOutPoint(req.Outpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	return &wrpc.RemoveUTXOLeaseResponse{}, nil
+	if len(req.LeaseOwner) != 32 {
+		return nil, fmt.Errorf("lease owner must be 32 bytes")
+	}
+
+	var leaseOwner [32]byte
+	copy(leaseOwner[:], req.LeaseOwner)
+
+	err = r.cfg.CoinSelect.ReleaseLease(ctx, leaseOwner, outPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wrpc.ReleaseLeaseResponse{}, nil
+}
+
+// unmarshalWalletOutPoint parses the given wallet RPC outpoint.
+func unmarshalWalletOutPoint(op *wrpc.OutPoint) (wire.OutPoint, error) {
+	if op == nil {
+		return wire.OutPoint{}, fmt.Errorf("outpoint must be specified")
+	}
+
+	hash, err := chainhash.NewHash(op.Txid)
+	if err != nil {
+		return wire.OutPoint{}, fmt.Errorf("error parsing txid: %w",
+			err)
+	}
+
+	return wire.OutPoint{
+		Hash:  *hash,
+		Index: op.OutputIndex,
+	}, nil
+}
+
+// ExportSigningState exports the inventory of all internal and script keys the
+// wallet derived, along with the highest key index used in each key family.
+func (r *rpcServer) ExportSigningState(ctx context.Context,
+	_ *wrpc.ExportSigningStateRequest) (*wrpc.ExportSigningStateResponse,
+	error) {
+
+	state, err := r.cfg.AddrBook.ExportSigningState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to export signing state: %w", err)
+	}
+
+	rpcState := &wrpc.SigningState{
+		InternalKeys: make(
+			[]*taprpc.KeyDescriptor, len(state.InternalKeys),
+		),
+		ScriptKeys: make([]*taprpc.ScriptKey, len(state.ScriptKeys)),
+	}
+	for idx := range state.InternalKeys {
+		rpcState.InternalKeys[idx] = marshalKeyDescriptor(
+			state.InternalKeys[idx],
+		)
+	}
+	for idx := range state.ScriptKeys {
+		rpcState.ScriptKeys[idx] = marshalScriptKey(
+			state.ScriptKeys[idx],
+		)
+	}
+	for family, keyIndex := range state.KeyIndexes {
+		rpcState.KeyIndexes = append(
+			rpcState.KeyIndexes, &wrpc.KeyFamilyIndex{
+				KeyFamily: uint32(family),
+				KeyIndex:  keyIndex,
+			},
+		)
+	}
+
+	// We sort the key indexes by family to get a stable output.
+	sort.Slice(rpcState.KeyIndexes, func(i, j int) bool {
+		return rpcState.KeyIndexes[i].KeyFamily <
+			rpcState.KeyIndexes[j].KeyFamily
+	})
+
+	return &wrpc.ExportSigningStateResponse{
+		SigningState: rpcState,
+	}, nil
+}
+
+// ImportSigningState imports a signing state that was exported by a node with
+// the same lnd seed and advances the key derivation of lnd past the highest
+// imported key index of each key family.
+func (r *rpcServer) ImportSigningState(ctx context.Context,
+	req *wrpc.ImportSigningStateRequest) (*wrpc.ImportSigningStateResponse,
+	error) {
+
+	rpcState := req.SigningState
+	if rpcState == nil {
+		return nil, fmt.Errorf("signing state must be specified")
+	}
+
+	state := &address.SigningState{
+		InternalKeys: make(
+			[]keychain.KeyDescriptor, len(rpcState.InternalKeys),
+		),
+		ScriptKeys: make([]asset.ScriptKey, len(rpcState.ScriptKeys)),
+		KeyIndexes: make(map[keychain.KeyFamily]uint32),
+	}
+	for idx, rpcKey := range rpcState.InternalKeys {
+		keyDesc, err := UnmarshalKeyDescriptor(rpcKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid internal key %d: %w",
+				idx, err)
+		}
+
+		state.InternalKeys[idx] = keyDesc
+	}
+	for idx, rpcKey := range rpcState.ScriptKeys {
+		scriptKey, err := UnmarshalScriptKey(rpcKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid script key %d: %w",
+				idx, err)
+		}
+
+		state.ScriptKeys[idx] = *scriptKey
+	}
+	for _, keyIndex := range rpcState.KeyIndexes {
+		family := keychain.KeyFamily(keyIndex.KeyFamily)
+		state.KeyIndexes[family] = keyIndex.KeyIndex
+	}
+
+	err := r.cfg.AddrBook.ImportSigningState(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import signing state: %w", err)
+	}
+
+	return &wrpc.ImportSigningStateResponse{
+		NumInternalKeys: uint32(len(state.InternalKeys)),
+		NumScriptKeys:   uint32(len(state.ScriptKeys)),
+	}, nil
+}
+
+// marshalKeyRange turns a reserved key range into its RPC counterpart.
+func marshalKeyRange(keyRange address.KeyRange) *wrpc.KeyRange {
+	return &wrpc.KeyRange{
+		KeyFamily:        uint32(keyRange.Family),
+		FirstIndex:       keyRange.FirstIndex,
+		LastIndex:        keyRange.LastIndex,
+		Purpose:          keyRange.Purpose,
+		CreationTimeUnix: keyRange.CreationTime.Unix(),
+	}
+}
+
+// ListKeyRanges lists the key families the daemon derived keys in or reserved
+// key ranges of, together with their usage.
+func (r *rpcServer) ListKeyRanges(ctx context.Context,
+	_ *wrpc.ListKeyRangesRequest) (*wrpc.ListKeyRangesResponse, error) {
+
+	usages, err := r.cfg.AddrBook.ListKeyRanges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list key ranges: %w", err)
+	}
+
+	rpcUsages := make([]*wrpc.KeyFamilyUsage, len(usages))
+	for idx, usage := range usages {
+		rpcRanges := make(
+			[]*wrpc.KeyRange, len(usage.ReservedRanges),
+		)
+		for rangeIdx, keyRange := range usage.ReservedRanges {
+			rpcRanges[rangeIdx] = marshalKeyRange(keyRange)
+		}
+
+		rpcUsages[idx] = &wrpc.KeyFamilyUsage{
+			KeyFamily:      uint32(usage.Family),
+			NextIndex:      usage.NextIndex,
+			UsedKeys:       usage.UsedKeys,
+			ReservedRanges: rpcRanges,
+		}
+	}
+
+	return &wrpc.ListKeyRangesResponse{
+		KeyFamilies: rpcUsages,
+	}, nil
+}
+
+// ReserveKeyRange reserves a range of keys of a key family for a purpose
+// outside of the daemon.
+func (r *rpcServer) ReserveKeyRange(ctx context.Context,
+	req *wrpc.ReserveKeyRangeRequest) (*wrpc.ReserveKeyRangeResponse,
+	error) {
+
+	keyRange, err := r.cfg.AddrBook.ReserveKeyRange(
+		ctx, keychain.KeyFamily(req.KeyFamily), req.NumKeys,
+		req.Purpose,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reserve key range: %w", err)
+	}
+
+	rpcsLog.Infof("Reserved keys %d to %d of key family %d for purpose "+
+		"%q", keyRange.FirstIndex, keyRange.LastIndex, req.KeyFamily,
+		req.Purpose)
+
+	return &wrpc.ReserveKeyRangeResponse{
+		KeyRange: marshalKeyRange(*keyRange),
+	}, nil
 }
 
 // MarshalAssetFedSyncCfg returns an RPC ready asset specific federation sync
@@ -0,0 +1,241 @@
+package taprootassets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/tapdb"
+	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// sendEventPruneInterval is the interval at which the persisted send
+	// events that are older than the retention period are deleted.
+	sendEventPruneInterval = time.Hour
+
+	// sendEventDBTimeout is the timeout of a single database operation of
+	// the send event log.
+	sendEventDBTimeout = 30 * time.Second
+)
+
+// sendEventLog persists the events of the asset sending process and forwards
+// them, along with the ID they were persisted under, to the RPC subscribers.
+// This allows subscribers that reconnect to replay the events they missed.
+type sendEventLog struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	porter tapfreighter.Porter
+
+	db *tapdb.SendEventDB
+
+	// retention is the duration for which the events are persisted. If
+	// zero, the events are only forwarded but not persisted.
+	retention time.Duration
+
+	// receiver is the subscription to the events of the chain porter.
+	receiver *fn.EventReceiver[fn.Event]
+
+	*fn.EventDistributor[*taprpc.SendAssetEvent]
+
+	*fn.ContextGuard
+}
+
+// newSendEventLog creates a new send event log for the events of the given
+// chain porter.
+func newSendEventLog(porter tapfreighter.Porter, db *tapdb.SendEventDB,
+	retention time.Duration) *sendEventLog {
+
+	distributor := fn.NewEventDistributor[*taprpc.SendAssetEvent]()
+
+	return &sendEventLog{
+		porter:           porter,
+		db:               db,
+		retention:        retention,
+		EventDistributor: distributor,
+		ContextGuard: &fn.ContextGuard{
+			DefaultTimeout: sendEventDBTimeout,
+			Quit:           make(chan struct{}),
+		},
+	}
+}
+
+// Start subscribes to the events of the chain porter. It must be called before
+// the chain porter is started, so no event is missed.
+func (l *sendEventLog) Start() error {
+	var startErr error
+	l.startOnce.Do(func() {
+		srvrLog.Infof("Starting send event log, retention=%v",
+			l.retention)
+
+		l.receiver = fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+		err := l.porter.RegisterSubscriber(l.receiver, false, false)
+		if err != nil {
+			startErr = fmt.Errorf("unable to subscribe to send "+
+				"events: %w", err)
+			return
+		}
+
+		l.Wg.Add(1)
+		go l.logEvents()
+	})
+
+	return startErr
+}
+
+// Stop stops persisting and forwarding events.
+func (l *sendEventLog) Stop() error {
+	l.stopOnce.Do(func() {
+		srvrLog.Info("Stopping send event log")
+
+		close(l.Quit)
+		l.Wg.Wait()
+
+		// The chain porter removes all of its subscribers when it
+		// stops, so the subscription might already be gone.
+		if l.receiver != nil {
+			_ = l.porter.RemoveSubscriber(l.receiver)
+		}
+	})
+
+	return nil
+}
+
+// logEvents persists the events of the chain porter, forwards them to the
+// subscribers and periodically prunes the events that are older than the
+// retention period.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (l *sendEventLog) logEvents() {
+	defer l.Wg.Done()
+
+	l.pruneEvents()
+
+	pruneTicker := time.NewTicker(sendEventPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case event := <-l.receiver.NewItemCreated.ChanOut():
+			rpcEvent, err := marshallSendAssetEvent(event)
+			if err != nil {
+				srvrLog.Errorf("Unable to marshal send event "+
+					"%T: %v", event, err)
+				continue
+			}
+
+			// An event that can't be persisted is still forwarded
+			// to the live subscribers, it just can't be replayed.
+			if l.retention > 0 {
+				err := l.persistEvent(
+					event.Timestamp(), rpcEvent,
+				)
+				if err != nil {
+					srvrLog.Errorf("Unable to persist "+
+						"send event: %v", err)
+				}
+			}
+
+			l.NotifySubscribers(rpcEvent)
+
+		case <-pruneTicker.C:
+			l.pruneEvents()
+
+		case <-l.Quit:
+			return
+		}
+	}
+}
+
+// persistEvent persists the given event and sets the ID it was persisted
+// under.
+func (l *sendEventLog) persistEvent(timestamp time.Time,
+	rpcEvent *taprpc.SendAssetEvent) error {
+
+	eventData, err := proto.Marshal(rpcEvent)
+	if err != nil {
+		return fmt.Errorf("unable to encode event: %w", err)
+	}
+
+	ctx, cancel := l.WithCtxQuit()
+	defer cancel()
+
+	eventID, err := l.db.LogSendEvent(ctx, timestamp, eventData)
+	if err != nil {
+		return err
+	}
+
+	rpcEvent.EventId = eventID
+
+	return nil
+}
+
+// pruneEvents deletes the persisted events that are older than the retention
+// period.
+func (l *sendEventLog) pruneEvents() {
+	if l.retention == 0 {
+		return
+	}
+
+	ctx, cancel := l.WithCtxQuit()
+	defer cancel()
+
+	numDeleted, err := l.db.PruneSendEvents(ctx, l.retention)
+	if err != nil {
+		srvrLog.Errorf("Unable to prune send events: %v", err)
+		return
+	}
+
+	if numDeleted > 0 {
+		srvrLog.Debugf("Pruned %d send events older than %v",
+			numDeleted, l.retention)
+	}
+}
+
+// replayEvents sends the persisted events with an ID greater than afterID that
+// were emitted at or after the given time to the given send function, in the
+// order they were emitted in. Events older than the retention period aren't
+// replayed. The ID of the last sent event is returned, or afterID if no event
+// was sent.
+func (l *sendEventLog) replayEvents(ctx context.Context, afterID uint64,
+	since time.Time,
+	send func(*taprpc.SendAssetEvent) error) (uint64, error) {
+
+	if l.retention == 0 {
+		return 0, fmt.Errorf("send event replay is disabled, " +
+			"send-event-retention is zero")
+	}
+
+	retentionStart := time.Now().Add(-l.retention)
+	if since.Before(retentionStart) {
+		since = retentionStart
+	}
+
+	events, err := l.db.QuerySendEvents(ctx, afterID, since)
+	if err != nil {
+		return 0, err
+	}
+
+	lastID := afterID
+	for _, event := range events {
+		var rpcEvent taprpc.SendAssetEvent
+		if err := proto.Unmarshal(event.Data, &rpcEvent); err != nil {
+			return 0, fmt.Errorf("unable to decode send event "+
+				"%d: %w", event.ID, err)
+		}
+		rpcEvent.EventId = event.ID
+
+		if err := send(&rpcEvent); err != nil {
+			return 0, err
+		}
+
+		lastID = event.ID
+	}
+
+	return lastID, nil
+}
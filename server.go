@@ -15,6 +15,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/perms"
 	"github.com/lightninglabs/taproot-assets/rpcperms"
 	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -36,6 +37,14 @@ type Server struct {
 	*rpcServer
 	macaroonService *lndclient.MacaroonService
 
+	// sendEventLog persists the events of the asset sending process and
+	// forwards them to the RPC subscribers.
+	sendEventLog *sendEventLog
+
+	// webhookNotifier delivers the send and receive asset events to the
+	// configured webhook. It is nil if no webhook is configured.
+	webhookNotifier *webhook.Notifier
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -126,8 +135,12 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 	// Initialize, and register our implementation of the gRPC interface
 	// exported by the rpcServer.
 	var err error
+	s.sendEventLog = newSendEventLog(
+		s.cfg.ChainPorter, s.cfg.SendEvents, s.cfg.SendEventRetention,
+	)
 	s.rpcServer, err = newRPCServer(
 		s.cfg.SignalInterceptor, interceptorChain, s.cfg,
+		s.sendEventLog,
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create rpc server: %v", err)
@@ -147,6 +160,20 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 		return fmt.Errorf("unable to start re-org watcher: %v", err)
 	}
 
+	// Leases of transfers that were interrupted before they were logged
+	// aren't renewed by anyone, so we release the ones that expired while
+	// we were offline before resuming the pending transfers.
+	err = s.cfg.CoinSelect.DeleteExpiredLeases(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to delete expired leases: %v", err)
+	}
+
+	// The send event log needs to subscribe to the chain porter before it
+	// starts, so the events of resumed transfers are persisted too.
+	if err := s.sendEventLog.Start(); err != nil {
+		return fmt.Errorf("unable to start send event log: %v", err)
+	}
+
 	if err := s.cfg.ChainPorter.Start(); err != nil {
 		return fmt.Errorf("unable to start chain porter: %v", err)
 	}
@@ -164,6 +191,17 @@ func (s *Server) initialize(interceptorChain *rpcperms.InterceptorChain) error {
 		}
 	}
 
+	if s.cfg.Webhook != nil {
+		s.webhookNotifier = webhook.NewNotifier(&webhook.NotifierConfig{
+			Cfg:     s.cfg.Webhook,
+			Sources: webhookSources(s.cfg),
+		})
+		if err := s.webhookNotifier.Start(); err != nil {
+			return fmt.Errorf("unable to start webhook notifier: "+
+				"%v", err)
+		}
+	}
+
 	// Now we have created all dependencies necessary to populate and
 	// start the RPC server.
 	if err := s.rpcServer.Start(); err != nil {
@@ -551,6 +589,13 @@ func (s *Server) Stop() error {
 	if err := s.rpcServer.Stop(); err != nil {
 		return err
 	}
+
+	if s.webhookNotifier != nil {
+		if err := s.webhookNotifier.Stop(); err != nil {
+			return err
+		}
+	}
+
 	if err := s.cfg.AssetMinter.Stop(); err != nil {
 		return err
 	}
@@ -566,6 +611,10 @@ func (s *Server) Stop() error {
 		return err
 	}
 
+	if err := s.sendEventLog.Stop(); err != nil {
+		return err
+	}
+
 	if err := s.cfg.UniverseFederation.Start(); err != nil {
 		return err
 	}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -20,9 +21,14 @@ import (
 	"github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/lndclient"
 	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/monitoring"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb"
+	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/cert"
 	"github.com/lightningnetwork/lnd/lncfg"
@@ -102,6 +108,59 @@ const (
 	// use for waiting for a receiver to acknowledge a proof transfer.
 	defaultProofTransferReceiverAckTimeout = time.Hour * 6
 
+	// defaultEmailCourierPollInterval is the default interval at which the
+	// mailbox of the email proof courier is polled.
+	defaultEmailCourierPollInterval = time.Minute
+
+	// defaultHTTPCourierPollInterval is the default interval at which the
+	// mailbox of the HTTP(S) proof courier is polled.
+	defaultHTTPCourierPollInterval = 5 * time.Second
+
+	// defaultHTTPCourierRequestTimeout is the default timeout of a single
+	// request to the mailbox server of the HTTP(S) proof courier.
+	defaultHTTPCourierRequestTimeout = 30 * time.Second
+
+	// defaultProofGapRequestTimeout is the default time a receiver waits
+	// for the sender to answer the request for a missing proof.
+	defaultProofGapRequestTimeout = time.Minute
+
+	// defaultProofGapServeDuration is the default maximum time a sender
+	// answers requests for missing proofs after delivering a proof, if the
+	// receiver doesn't acknowledge its proof chain before.
+	defaultProofGapServeDuration = 7 * 24 * time.Hour
+
+	// defaultProofImportMaxAttempts is the default number of attempts to
+	// import an inbound proof that fails because of a transient issue.
+	defaultProofImportMaxAttempts = 10
+
+	// defaultProofImportInitialBackoff is the default time to wait before
+	// the first retry of a failed proof import.
+	defaultProofImportInitialBackoff = 10 * time.Second
+
+	// defaultProofImportMaxBackoff is the default maximum time to wait
+	// between two retries of a failed proof import.
+	defaultProofImportMaxBackoff = 10 * time.Minute
+
+	// defaultSendEventRetention is the default duration for which the
+	// events of the asset sending process are persisted for replay.
+	defaultSendEventRetention = 7 * 24 * time.Hour
+
+	// defaultWebhookRequestTimeout is the default timeout of a single
+	// webhook request.
+	defaultWebhookRequestTimeout = 30 * time.Second
+
+	// defaultWebhookMaxAttempts is the default number of attempts to
+	// deliver an event to the webhook.
+	defaultWebhookMaxAttempts = 10
+
+	// defaultWebhookInitialBackoff is the default time to wait before the
+	// first retry of a failed webhook delivery.
+	defaultWebhookInitialBackoff = 5 * time.Second
+
+	// defaultWebhookMaxBackoff is the default maximum time to wait between
+	// two retries of a failed webhook delivery.
+	defaultWebhookMaxBackoff = 10 * time.Minute
+
 	// defaultUniverseSyncInterval is the default interval that we'll use
 	// to sync Universe state with the federation.
 	defaultUniverseSyncInterval = time.Minute * 10
@@ -110,9 +169,72 @@ const (
 	// sync in a single batch.
 	defaultUniverseSyncBatchSize = 200
 
+	// defaultUniversePushRetryInitialBackoff is the default time we'll
+	// wait before retrying a failed proof push to a federation server.
+	defaultUniversePushRetryInitialBackoff = time.Minute
+
+	// defaultUniversePushRetryMaxBackoff is the default maximum time we'll
+	// wait between two attempts to push a proof to a federation server.
+	defaultUniversePushRetryMaxBackoff = time.Hour * 6
+
+	// defaultUniverseMaxPendingPushes is the default number of failed proof
+	// pushes we'll queue for retry per federation server.
+	defaultUniverseMaxPendingPushes = 10_000
+
+	// defaultUniverseMaxConcurrentPushes is the default number of proof
+	// pushes to federation servers that are in flight at the same time.
+	defaultUniverseMaxConcurrentPushes = 16
+
+	// defaultUniverseSyncTimeout is the default maximum time a sync with a
+	// single federation server may take.
+	defaultUniverseSyncTimeout = 30 * time.Minute
+
 	// defaultReOrgSafeDepth is the default number of confirmations we'll
 	// wait for before considering a transaction safely buried in the chain.
 	defaultReOrgSafeDepth = 6
+
+	// defaultFeeEscalationAfter is the default duration a transfer needs
+	// to be unconfirmed before the fee rate of its anchor transaction is
+	// escalated.
+	defaultFeeEscalationAfter = time.Hour
+
+	// defaultFeeEscalationInterval is the default interval at which the
+	// fee rate of unconfirmed anchor transactions is escalated.
+	defaultFeeEscalationInterval = time.Hour
+
+	// defaultFeeEscalationStep is the default fee rate in sat/vB the fee
+	// rate of an unconfirmed anchor transaction is increased by with
+	// every escalation.
+	defaultFeeEscalationStep = 1
+
+	// defaultFeeEscalationMaxFeeRate is the default maximum fee rate in
+	// sat/vB an unconfirmed anchor transaction is escalated to.
+	defaultFeeEscalationMaxFeeRate = 50
+
+	// defaultAnchorSweepInterval is the default interval at which anchor
+	// outputs that only anchor spent assets are swept.
+	defaultAnchorSweepInterval = time.Hour
+
+	// defaultAnchorSweepMaxOutputValue is the default value in satoshis
+	// an anchor output has at most to be swept.
+	defaultAnchorSweepMaxOutputValue = 1000
+
+	// defaultAnchorSweepMaxFeeRate is the default fee rate in sat/vB above
+	// which no sweep is attempted.
+	defaultAnchorSweepMaxFeeRate = 5
+
+	// defaultAnchorSweepConfTarget is the default confirmation target the
+	// fee rate of a sweep transaction is estimated for.
+	defaultAnchorSweepConfTarget = 144
+
+	// defaultAnchorSweepFeeBudget is the default percentage of the swept
+	// value that can be spent on the fees of a sweep transaction.
+	defaultAnchorSweepFeeBudget = 50
+
+	// defaultProofDeliveryQuorum is the default percentage of receivers
+	// the proofs of a transfer need to be delivered to before the transfer
+	// is complete, if the quorum delivery completion is used.
+	defaultProofDeliveryQuorum = 51
 )
 
 var (
@@ -138,6 +260,10 @@ var (
 
 	defaultSqliteDatabaseFileName = "tapd.db"
 
+	// defaultWebhookDeadLetterFileName is the name of the file the events
+	// that couldn't be delivered to the webhook are written to.
+	defaultWebhookDeadLetterFileName = "webhook_dead_letters.log"
+
 	// defaultLndMacaroon is the default macaroon file we use if the old,
 	// deprecated --lnd.macaroondir config option is used.
 	defaultLndMacaroon = "admin.macaroon"
@@ -251,6 +377,76 @@ type UniverseConfig struct {
 	FederationServers []string `long:"federationserver" description:"The host:port of a Universe server peer with. These servers will be added as the default set of federation servers. Can be specified multiple times."`
 
 	PublicAccess bool `long:"public-access" description:"If true, and the Universe server is on a public interface, valid proof from remote parties will be accepted, and proofs will be queryable by remote parties. This applies to federation syncing as well as RPC insert and query."`
+
+	PushRetryInitialBackoff time.Duration `long:"push-retry-initial-backoff" description:"The time to wait before retrying a failed proof push to a federation server for the first time. The wait time is doubled after each failed attempt."`
+
+	PushRetryMaxBackoff time.Duration `long:"push-retry-max-backoff" description:"The maximum time to wait between two attempts to push a proof to a federation server."`
+
+	MaxPendingPushes int `long:"max-pending-pushes" description:"The maximum number of failed proof pushes to queue for retry per federation server. Set to 0 to disable retrying failed pushes."`
+
+	MaxConcurrentPushes int `long:"max-concurrent-pushes" description:"The maximum number of proof pushes to federation servers that are in flight at the same time, across all servers."`
+
+	SyncTimeout time.Duration `long:"sync-timeout" description:"The maximum time a sync with a single federation server may take. A sync that takes longer is aborted, so a server that hangs doesn't hold up the sync with the other servers."`
+
+	SyncWorkers int `long:"sync-workers" description:"The maximum number of universes that are synced in parallel with a remote Universe server. The leaf proofs of each universe are fetched by up to that many workers as well. Set to 0 to use the number of CPUs."`
+
+	ReadOnly bool `long:"read-only" description:"If true, the Universe server rejects all proofs inserted or pushed through RPC while still serving queries and sync requests. Issuance proofs of locally minted assets are still added."`
+
+	DefaultSyncServer string `long:"default-sync-server" description:"The host:port of the Universe server to sync with if a sync request doesn't specify a server. This server is also used for the periodic sync if there are no federation servers. Can be changed at runtime, a server set at runtime is persisted and takes precedence over this option."`
+
+	AutoRegisterMints bool `long:"auto-register-mints" description:"If true, the issuance proofs of locally minted assets are pushed to the default-sync-server as well once the minting batch confirms. The default sync server doesn't become a federation server for this, failed pushes to it are queued for retry separately."`
+}
+
+// CoinSelectConfig is the config that houses the values that influence how
+// asset inputs are selected for a transfer.
+type CoinSelectConfig struct {
+	TranchePreference string `long:"tranchepreference" description:"The order in which the tranches of a grouped asset are considered when selecting inputs for an interactive transfer or burn of that group, oldest and newest refer to the genesis block height of a tranche. All inputs of a transfer are selected from the first tranche in this order that can cover the amount. If none can on its own, the transfer is rejected with the amount each tranche contributes, so it can be split into one transfer per tranche. If none, only inputs of the asset ID of the transfer are selected. Sends to addresses always spend the tranche of the address's asset ID. Can be overridden per request with the tranche_preference field of FundVirtualPsbt." choice:"none" choice:"oldest" choice:"newest" choice:"genesispoint"`
+
+	TrancheGenesisPoint string `long:"tranchegenesispoint" description:"The genesis point (txid:index) of the tranche to prefer, only used if tranchepreference is set to genesispoint."`
+
+	MaxInputs uint32 `long:"maxinputs" description:"The maximum number of asset inputs a single transfer may spend. A transfer that can only be satisfied by spending more inputs fails, and the assets should be consolidated first. Can be overridden per send. A value of 0 means no limit."`
+
+	MinConfs uint32 `long:"minconfs" description:"The number of confirmations the anchor transaction of an asset needs before the asset can be selected as an input of a transfer. A value of 0 means unconfirmed assets can be spent as well."`
+
+	AssetMinConfs []string `long:"assetminconfs" description:"Overrides minconfs for a single asset or all assets of a group, in the format <asset_id|group_key>:<confs>. An override for an asset ID takes precedence over one for its group key. Can be specified multiple times."`
+
+	RelaxSteps []string `long:"relaxstep" description:"A constraint that is relaxed if a transfer can't be funded otherwise, in the order the steps are applied. Each step is applied on top of the previous ones until the transfer can be funded, the relaxations are reported with the transfer. 'confirmations' lowers the confirmations required by minconfs and assetminconfs to relaxminconfs, 'maxinputs' raises maxinputs to relaxmaxinputs, a maximum given with a send is never relaxed. Can be specified multiple times. If not set, coin selection is strict." choice:"confirmations" choice:"maxinputs"`
+
+	RelaxMinConfs uint32 `long:"relaxminconfs" description:"The number of confirmations an asset needs at least once the confirmations are relaxed."`
+
+	RelaxMaxInputs uint32 `long:"relaxmaxinputs" description:"The maximum number of asset inputs a transfer may spend once the maximum number of inputs is relaxed. A value of 0 means no limit."`
+
+	ZeroChangePolicy string `long:"zerochangepolicy" description:"How to handle a change output that ends up with a zero amount because a transfer spends its inputs exactly. 'tombstone' keeps it as an un-spendable tombstone output, 'omit' removes it if all recipients are interactive. Transfers to addresses always require a tombstone." choice:"tombstone" choice:"omit"`
+}
+
+// FeeEscalationConfig is the config that houses the values of the background
+// fee escalation of unconfirmed transfers.
+type FeeEscalationConfig struct {
+	Enable bool `long:"enable" description:"If set, the fee rate of the anchor transaction of a transfer that doesn't confirm is periodically escalated by replacing the transaction (RBF)."`
+
+	After time.Duration `long:"after" description:"How long a transfer needs to be unconfirmed before the fee rate of its anchor transaction is escalated for the first time."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which the fee rate of unconfirmed anchor transactions is escalated."`
+
+	Step uint64 `long:"step" description:"The fee rate in sat/vB the fee rate of an unconfirmed anchor transaction is increased by with every escalation."`
+
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The maximum fee rate in sat/vB an unconfirmed anchor transaction is escalated to."`
+}
+
+// AnchorSweepConfig is the config that houses the values of the background
+// sweeper of the anchor outputs that only anchor spent assets.
+type AnchorSweepConfig struct {
+	Enable bool `long:"enable" description:"If set, anchor outputs that only anchor spent assets, such as the tombstones and burns left behind by completed transfers, are periodically swept back into the lnd wallet."`
+
+	Interval time.Duration `long:"interval" description:"The interval at which sweepable anchor outputs are looked for and swept."`
+
+	MaxOutputValue uint64 `long:"maxoutputvalue" description:"The value in satoshis an anchor output has at most to be swept."`
+
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The fee rate in sat/vB above which no sweep is attempted, so anchor outputs are only swept during low-fee periods."`
+
+	ConfTarget uint32 `long:"conftarget" description:"The confirmation target the fee rate of a sweep transaction is estimated for."`
+
+	FeeBudget uint32 `long:"feebudget" description:"The percentage of the value of the swept anchor outputs that can be spent on the fees of a sweep transaction at most."`
 }
 
 // Config is the main config for the tapd cli command.
@@ -274,9 +470,56 @@ type Config struct {
 
 	ReOrgSafeDepth int32 `long:"reorgsafedepth" description:"The number of confirmations we'll wait for before considering a transaction safely buried in the chain."`
 
+	AutoRetryAbandonedTransfers bool `long:"auto-retry-abandoned-transfers" description:"If set, a transfer to one or more addresses whose anchor transaction can no longer confirm because one of its inputs was double spent is automatically re-attempted with a new anchor transaction and freshly selected coins. This may result in a second transfer to the same addresses if the double spend is re-organized out of the chain."`
+
+	TransferReOrgPolicy string `long:"transfer-reorg-policy" description:"How to handle a confirmed transfer whose anchor transaction is re-organized out of the best chain before it reaches reorgsafedepth confirmations. 'rebroadcast' publishes the anchor transaction again, 'abandon' rolls the transfer back and frees its inputs once one of them is double spent by another transaction, and 'quarantine' leases the transfer's outputs until they are reviewed and released manually. A transfer that moved passive assets is quarantined instead of abandoned." choice:"rebroadcast" choice:"abandon" choice:"quarantine"`
+
+	ProofVerification string `long:"proof-verification" description:"How rigorously imported proofs are verified. 'verifyfull' checks the anchor of every state transition against the chain and validates all asset witnesses, 'verifychain' checks the anchor of every state transition but only validates the asset witnesses of the final state transition, and 'trustuniverse' additionally skips all state transitions up to the latest one that is already part of the local universe." choice:"verifyfull" choice:"verifychain" choice:"trustuniverse"`
+
+	CompressProofFiles bool `long:"compress-proof-files" description:"If set, proof files are stored compressed on disk, and are delivered compressed to receivers whose mailbox proof courier address advertises support for them. The courier address of new Taproot Asset addresses advertises that this node supports compressed proof files. Proof files are always passed on uncompressed to all other nodes, as older versions reject compressed files."`
+
+	LenientProofDecoding bool `long:"lenient-proof-decoding" description:"If set, proofs that contain unknown critical (even) TLV records are decoded anyway, with those records being dropped, instead of being rejected. Unknown odd records are always ignored. This should only be used for debugging, as it accepts proofs that might be invalid under rules this version doesn't know about."`
+
+	MetaJSONSchema string `long:"meta-json-schema" description:"Path to a JSON schema file. If set, the meta data of assets minted with the JSON meta type must adhere to this schema, otherwise it only needs to be valid JSON."`
+
+	FeeBumpAnchor bool `long:"fee-bump-anchor" description:"If set, the anchor transaction of every transfer reserves an additional small wallet owned output that can be spent by a child transaction to bump its fee (CPFP), even if the transfer has no change output."`
+
+	SendIdempotencyKeyRetention time.Duration `long:"send-idempotency-key-retention" description:"A duration (1h, 24h, etc) for which the idempotency key of a send request is remembered. A repeated send request with the same key within this duration returns the existing transfer instead of creating a new one."`
+
+	SendEventRetention time.Duration `long:"send-event-retention" description:"A duration (24h, 168h, etc) for which the events of the asset sending process are persisted, so that clients that subscribe to them again can replay the events they missed. Older events are deleted periodically. A value of 0 disables persisting and replaying send events."`
+
+	AddrReusePolicy string `long:"addr-reuse-policy" description:"How to handle an inbound transfer to a Taproot Asset address that already received assets in a different on-chain output. 'accept' takes custody of it as a separate UTXO, 'reject' ignores it and 'quarantine' tracks it but only completes it once its proof is imported manually." choice:"accept" choice:"reject" choice:"quarantine"`
+
+	PartialReceivePolicy string `long:"partial-receive-policy" description:"How to handle an inbound transfer to a Taproot Asset address that carries fewer asset units than the address requested. 'accept' takes custody of it, 'reject' doesn't import its proof and 'quarantine' only completes it once its proof is imported manually. The received amount is recorded on the address event in all cases." choice:"accept" choice:"reject" choice:"quarantine"`
+
+	MaxProofChainDepth uint32 `long:"max-proof-chain-depth" description:"The maximum number of state transitions the proof of an inbound transfer may contain. Proofs with a longer chain are neither verified nor imported but moved to the dead letters, which protects against artificially long proof chains. A value of 0 means no limit."`
+
+	MaxAddrAmount uint64 `long:"max-addr-amount" description:"The maximum amount of asset units a Taproot Asset address can request. Addresses with a larger amount are rejected when they are created, decoded or sent to."`
+
+	AnchorOutputOrder string `long:"anchor-output-order" description:"The order of the asset carrying outputs of the anchor transaction of a send to addresses. 'none' puts the change output first, followed by the recipients in the order of their addresses. 'bip69' sorts them by the rules of BIP-0069, using the internal key of each output in place of its final output key, which commits to the output index. The BTC change output is always the last output. Anchor output indexes requested through the virtual PSBT RPCs are always kept." choice:"none" choice:"bip69"`
+
+	ProofDeliveryCompletion string `long:"proof-delivery-completion" description:"When a transfer to multiple receivers is marked as complete. 'all' waits until the proofs were delivered to all receivers, 'quorum' only waits until they were delivered to the percentage of receivers set with proof-delivery-quorum. The proofs of the remaining receivers continue to be delivered in the background, also after a restart." choice:"all" choice:"quorum"`
+
+	ProofDeliveryQuorum uint32 `long:"proof-delivery-quorum" description:"The percentage of receivers the proofs of a transfer need to be delivered to before the transfer is marked as complete, if proof-delivery-completion is set to 'quorum'."`
+
+	NonInteractiveCourierMode string `long:"noninteractive-courier-mode" description:"How proofs are delivered to receivers of a Taproot Asset address. 'async' tolerates receivers that are offline by retrying failed deliveries with an increasing backoff, 'sync' delivers the proof in a single attempt that fails if the receiver doesn't acknowledge it within the receiver ACK timeout." choice:"async" choice:"sync"`
+
+	InteractiveProofCourierAddr string `long:"interactive-proof-courier-addr" description:"The proof courier used to deliver proofs to the receivers of interactive transfers, which don't announce a courier of their own. If not set, interactive receivers need to obtain their proofs out of band."`
+
+	FallbackProofCourierAddr string `long:"fallback-proof-courier-addr" description:"The universe RPC courier (universerpc://host:port) proofs are uploaded to if the proof courier of a receiver failed all delivery attempts. Receivers that can't fetch their proofs from the courier of their address poll the same universe for them. If not set, failed deliveries are retried through the courier of the receiver only."`
+
+	InteractiveCourierMode string `long:"interactive-courier-mode" description:"How proofs are delivered to the receivers of interactive transfers through the interactive-proof-courier-addr. 'sync' expects the receiver to be online and acknowledge the proof in a single attempt, 'async' retries failed deliveries with an increasing backoff." choice:"async" choice:"sync"`
+
 	// The following options are used to configure the proof courier.
 	DefaultProofCourierAddr string                    `long:"proofcourieraddr" description:"Default proof courier service address."`
 	HashMailCourier         *proof.HashMailCourierCfg `group:"proofcourier" namespace:"hashmailcourier"`
+	EmailCourier            *proof.EmailCourierCfg    `group:"emailcourier" namespace:"emailcourier"`
+	HTTPCourier             *proof.HTTPCourierCfg     `group:"httpcourier" namespace:"httpcourier"`
+	ProofGapRecovery        *proof.GapRecoveryCfg     `group:"proofgaprecovery" namespace:"proofgaprecovery"`
+
+	ProofImportRetry *tapgarden.ProofImportRetryCfg `group:"proofimportretry" namespace:"proofimportretry"`
+
+	Webhook *webhook.Config `group:"webhook" namespace:"webhook"`
 
 	ChainConf *ChainConfig
 	RpcConf   *RpcConfig
@@ -289,6 +532,12 @@ type Config struct {
 
 	Universe *UniverseConfig `group:"universe" namespace:"universe"`
 
+	CoinSelect *CoinSelectConfig `group:"coinselect" namespace:"coinselect"`
+
+	FeeEscalation *FeeEscalationConfig `group:"feeescalation" namespace:"feeescalation"`
+
+	AnchorSweep *AnchorSweepConfig `group:"anchorsweep" namespace:"anchorsweep"`
+
 	Prometheus monitoring.PrometheusConfig `group:"prometheus" namespace:"prometheus"`
 
 	// LogWriter is the root logger that all of the daemon's subloggers are
@@ -338,18 +587,39 @@ func DefaultConfig() Config {
 		},
 		DatabaseBackend: DatabaseBackendSqlite,
 		Sqlite: &tapdb.SqliteConfig{
-			DatabaseFileName: defaultSqliteDatabasePath,
+			DatabaseFileName:   defaultSqliteDatabasePath,
+			MaxOpenConnections: tapdb.DefaultSqliteMaxConns,
+			MaxIdleConnections: tapdb.DefaultSqliteMaxConns,
+			ConnMaxLifetime:    tapdb.DefaultConnMaxLifetime,
+			JournalMode:        tapdb.DefaultSqliteJournalMode,
+			BusyTimeout:        tapdb.DefaultSqliteBusyTimeout,
 		},
 		Postgres: &tapdb.PostgresConfig{
 			Host:               "localhost",
 			Port:               5432,
 			MaxOpenConnections: 10,
+			MaxIdleConnections: 10,
+			ConnMaxLifetime:    tapdb.DefaultConnMaxLifetime,
 		},
 		LogWriter:               build.NewRotatingLogWriter(),
 		Prometheus:              monitoring.DefaultPrometheusConfig(),
 		BatchMintingInterval:    defaultBatchMintingInterval,
 		ReOrgSafeDepth:          defaultReOrgSafeDepth,
+		AddrReusePolicy:         tapgarden.AddrReuseAccept.String(),
+		PartialReceivePolicy:    tapgarden.PartialReceiveAccept.String(),
+		MaxAddrAmount:           address.DefaultMaxAmount,
+		AnchorOutputOrder:       tapfreighter.AnchorOutputOrderNone.String(),
+		ProofDeliveryCompletion: tapfreighter.DeliveryCompletionAll.String(),
+		ProofDeliveryQuorum:     defaultProofDeliveryQuorum,
 		DefaultProofCourierAddr: defaultProofCourierAddr,
+		TransferReOrgPolicy: tapfreighter.
+			ReOrgPolicyRebroadcast.String(),
+		SendIdempotencyKeyRetention: tapfreighter.
+			DefaultIdempotencyKeyRetention,
+		SendEventRetention:        defaultSendEventRetention,
+		ProofVerification:         proof.StrictnessVerifyFull.String(),
+		NonInteractiveCourierMode: proof.CourierModeAsync.String(),
+		InteractiveCourierMode:    proof.CourierModeSync.String(),
 		HashMailCourier: &proof.HashMailCourierCfg{
 			ReceiverAckTimeout: defaultProofTransferReceiverAckTimeout,
 			BackoffCfg: &proof.BackoffCfg{
@@ -359,8 +629,52 @@ func DefaultConfig() Config {
 				MaxBackoff:       defaultProofTransferMaxBackoff,
 			},
 		},
+		EmailCourier: &proof.EmailCourierCfg{
+			PollInterval: defaultEmailCourierPollInterval,
+		},
+		HTTPCourier: &proof.HTTPCourierCfg{
+			PollInterval:   defaultHTTPCourierPollInterval,
+			RequestTimeout: defaultHTTPCourierRequestTimeout,
+		},
+		ProofGapRecovery: &proof.GapRecoveryCfg{
+			RequestTimeout: defaultProofGapRequestTimeout,
+			ServeDuration:  defaultProofGapServeDuration,
+		},
+		ProofImportRetry: &tapgarden.ProofImportRetryCfg{
+			MaxAttempts:    defaultProofImportMaxAttempts,
+			InitialBackoff: defaultProofImportInitialBackoff,
+			MaxBackoff:     defaultProofImportMaxBackoff,
+		},
+		Webhook: &webhook.Config{
+			RequestTimeout: defaultWebhookRequestTimeout,
+			MaxAttempts:    defaultWebhookMaxAttempts,
+			InitialBackoff: defaultWebhookInitialBackoff,
+			MaxBackoff:     defaultWebhookMaxBackoff,
+		},
 		Universe: &UniverseConfig{
-			SyncInterval: defaultUniverseSyncInterval,
+			SyncInterval:            defaultUniverseSyncInterval,
+			PushRetryInitialBackoff: defaultUniversePushRetryInitialBackoff,
+			PushRetryMaxBackoff:     defaultUniversePushRetryMaxBackoff,
+			MaxPendingPushes:        defaultUniverseMaxPendingPushes,
+			MaxConcurrentPushes:     defaultUniverseMaxConcurrentPushes,
+			SyncTimeout:             defaultUniverseSyncTimeout,
+		},
+		CoinSelect: &CoinSelectConfig{
+			TranchePreference: tapfreighter.TranchePreferNone.String(),
+			ZeroChangePolicy:  tapfreighter.ZeroChangeTombstone.String(),
+		},
+		FeeEscalation: &FeeEscalationConfig{
+			After:      defaultFeeEscalationAfter,
+			Interval:   defaultFeeEscalationInterval,
+			Step:       defaultFeeEscalationStep,
+			MaxFeeRate: defaultFeeEscalationMaxFeeRate,
+		},
+		AnchorSweep: &AnchorSweepConfig{
+			Interval:       defaultAnchorSweepInterval,
+			MaxOutputValue: defaultAnchorSweepMaxOutputValue,
+			MaxFeeRate:     defaultAnchorSweepMaxFeeRate,
+			ConfTarget:     defaultAnchorSweepConfTarget,
+			FeeBudget:      defaultAnchorSweepFeeBudget,
 		},
 	}
 }
@@ -544,7 +858,11 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 	cfg.RpcConf.TLSCertPath = CleanAndExpandPath(cfg.RpcConf.TLSCertPath)
 	cfg.RpcConf.TLSKeyPath = CleanAndExpandPath(cfg.RpcConf.TLSKeyPath)
 	cfg.LogDir = CleanAndExpandPath(cfg.LogDir)
+	cfg.MetaJSONSchema = CleanAndExpandPath(cfg.MetaJSONSchema)
 	cfg.RpcConf.MacaroonPath = CleanAndExpandPath(cfg.RpcConf.MacaroonPath)
+	cfg.Webhook.DeadLetterFile = CleanAndExpandPath(
+		cfg.Webhook.DeadLetterFile,
+	)
 
 	// Multiple networks can't be selected simultaneously.  Count number of
 	// network flags passed; assign active network params
@@ -612,6 +930,133 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 		}
 	}
 
+	// Failed universe pushes can only be retried with a positive backoff.
+	if cfg.Universe.MaxPendingPushes > 0 &&
+		(cfg.Universe.PushRetryInitialBackoff <= 0 ||
+			cfg.Universe.PushRetryMaxBackoff <
+				cfg.Universe.PushRetryInitialBackoff) {
+
+		return nil, mkErr("universe push retry backoff must be " +
+			"positive and the max backoff must not be smaller " +
+			"than the initial backoff")
+	}
+
+	if cfg.SendEventRetention < 0 {
+		return nil, mkErr("send-event-retention must not be negative")
+	}
+
+	// At least one push to the federation must be allowed at a time.
+	if cfg.Universe.MaxConcurrentPushes <= 0 {
+		return nil, mkErr("universe.max-concurrent-pushes must be " +
+			"positive")
+	}
+
+	if cfg.Universe.SyncTimeout <= 0 {
+		return nil, mkErr("universe.sync-timeout must be positive")
+	}
+
+	if cfg.Universe.SyncWorkers < 0 {
+		return nil, mkErr("universe.sync-workers must not be negative")
+	}
+
+	if cfg.Universe.DefaultSyncServer != "" {
+		err := universe.ValidateServerAddrStr(
+			cfg.Universe.DefaultSyncServer,
+		)
+		if err != nil {
+			return nil, mkErr("invalid universe."+
+				"default-sync-server: %v", err)
+		}
+	}
+
+	// Minted assets can only be registered automatically if there is a
+	// default server to register them with.
+	if cfg.Universe.AutoRegisterMints &&
+		cfg.Universe.DefaultSyncServer == "" {
+
+		return nil, mkErr("universe.auto-register-mints requires " +
+			"universe.default-sync-server to be set")
+	}
+
+	// The fee rate of unconfirmed transfers can only be escalated with a
+	// positive interval, step and cap.
+	escalationCfg := cfg.FeeEscalation
+	if escalationCfg != nil && escalationCfg.Enable &&
+		(escalationCfg.After < 0 || escalationCfg.Interval <= 0 ||
+			escalationCfg.Step == 0 ||
+			escalationCfg.MaxFeeRate == 0) {
+
+		return nil, mkErr("fee escalation interval, step and max fee " +
+			"rate must be positive and the delay must not be " +
+			"negative")
+	}
+
+	// Anchor outputs can only be swept with a positive interval, value,
+	// fee rate and confirmation target, and a fee budget that is a
+	// percentage of the swept value.
+	sweepCfg := cfg.AnchorSweep
+	if sweepCfg != nil && sweepCfg.Enable &&
+		(sweepCfg.Interval <= 0 || sweepCfg.MaxOutputValue == 0 ||
+			sweepCfg.MaxFeeRate == 0 || sweepCfg.ConfTarget == 0 ||
+			sweepCfg.FeeBudget == 0 || sweepCfg.FeeBudget > 100) {
+
+		return nil, mkErr("anchor sweep interval, max output value, " +
+			"max fee rate and conf target must be positive and " +
+			"the fee budget must be between 1 and 100")
+	}
+
+	// A delivery quorum is a percentage of the receivers of a transfer.
+	if cfg.ProofDeliveryQuorum == 0 || cfg.ProofDeliveryQuorum > 100 {
+		return nil, mkErr("proof-delivery-quorum must be between 1 " +
+			"and 100")
+	}
+
+	// Addresses with a larger amount than the default maximum can't be
+	// sent to anyway.
+	if cfg.MaxAddrAmount == 0 ||
+		cfg.MaxAddrAmount > address.DefaultMaxAmount {
+
+		return nil, mkErr("max-addr-amount must be between 1 and %d",
+			address.DefaultMaxAmount)
+	}
+
+	// The email courier is only used if its SMTP server is configured, in
+	// which case the rest of its config needs to be complete as well.
+	if cfg.EmailCourier != nil && cfg.EmailCourier.SMTPHost != "" {
+		if err := cfg.EmailCourier.Validate(); err != nil {
+			return nil, mkErr("invalid email courier config: %v",
+				err)
+		}
+	}
+
+	if cfg.HTTPCourier != nil {
+		if err := cfg.HTTPCourier.Validate(); err != nil {
+			return nil, mkErr("invalid http courier config: %v",
+				err)
+		}
+	}
+
+	// Gap recovery is only enabled if its mailbox is configured.
+	gapRecovery := cfg.ProofGapRecovery
+	if gapRecovery != nil && gapRecovery.MailboxAddr != "" {
+		if err := gapRecovery.Validate(); err != nil {
+			return nil, mkErr("invalid proof gap recovery config: "+
+				"%v", err)
+		}
+	}
+
+	if err := cfg.ProofImportRetry.Validate(); err != nil {
+		return nil, mkErr("invalid proof import retry config: %v", err)
+	}
+
+	// The webhook is only enabled if its URL is configured.
+	webhookEnabled := cfg.Webhook != nil && cfg.Webhook.URL != ""
+	if webhookEnabled {
+		if err := cfg.Webhook.Validate(); err != nil {
+			return nil, mkErr("invalid webhook config: %v", err)
+		}
+	}
+
 	// We'll now construct the network directory which will be where we
 	// store all the data specific to this chain/network.
 	cfg.networkDir = filepath.Join(
@@ -635,6 +1080,14 @@ func ValidateConfig(cfg Config, cfgLogger btclog.Logger) (*Config, error) {
 		)
 	}
 
+	// Undeliverable webhook events are written to the network directory,
+	// unless a custom dead letter file was specified.
+	if webhookEnabled && cfg.Webhook.DeadLetterFile == "" {
+		cfg.Webhook.DeadLetterFile = filepath.Join(
+			cfg.networkDir, defaultWebhookDeadLetterFileName,
+		)
+	}
+
 	// Make sure only one of the macaroon options is used.
 	switch {
 	case cfg.Lnd.MacaroonPath != defaultLndMacaroonPath &&
@@ -994,6 +1447,82 @@ func getCertificateConfig(cfg *Config, cfgLogger btclog.Logger) (*tls.Config,
 	return tlsCfg, restCreds, nil
 }
 
+// ConfigToFlatMap converts the given config into a flat map of all its options,
+// keyed by their long option name prefixed with the namespaces of the groups
+// they're in (for example "universe.public-access"). Values of sensitive
+// options, such as passwords, are redacted.
+func ConfigToFlatMap(cfg *Config) map[string]string {
+	result := make(map[string]string)
+	flattenConfig(reflect.ValueOf(cfg).Elem(), "", result)
+
+	return result
+}
+
+// flattenConfig recursively adds all options found in the given config struct
+// value to the result map.
+func flattenConfig(value reflect.Value, namespace string,
+	result map[string]string) {
+
+	// The suffixes of option names that hold sensitive values that should
+	// never be exposed.
+	sensitiveSuffixes := []string{"pass", "password", "dsn", "secret"}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		longName := field.Tag.Get("long")
+
+		// A struct without an option name is a group of options, which
+		// we descend into.
+		if longName == "" {
+			if fieldValue.Kind() != reflect.Struct {
+				continue
+			}
+
+			groupNamespace := namespace
+			if ns := field.Tag.Get("namespace"); ns != "" {
+				groupNamespace = namespace + ns + "."
+			}
+			flattenConfig(fieldValue, groupNamespace, result)
+
+			continue
+		}
+
+		key := namespace + longName
+		optionValue := fmt.Sprintf("%v", fieldValue.Interface())
+		if fieldValue.Kind() == reflect.Slice {
+			items := make([]string, fieldValue.Len())
+			for j := range items {
+				items[j] = fmt.Sprintf(
+					"%v", fieldValue.Index(j).Interface(),
+				)
+			}
+			optionValue = strings.Join(items, ",")
+		}
+
+		for _, suffix := range sensitiveSuffixes {
+			if strings.HasSuffix(key, suffix) && optionValue != "" {
+				optionValue = "[redacted]"
+				break
+			}
+		}
+
+		result[key] = optionValue
+	}
+}
+
 // fileExists reports whether the named file or directory exists.
 // This function is taken from https://github.com/btcsuite/btcd
 func fileExists(name string) bool {
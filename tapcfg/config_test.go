@@ -0,0 +1,44 @@
+package tapcfg
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfigToFlatMap tests that the config is flattened into namespaced
+// option names and that sensitive values are redacted.
+func TestConfigToFlatMap(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultConfig()
+	cfg.DatabaseBackend = DatabaseBackendPostgres
+	cfg.Postgres.Password = "super-secret"
+	cfg.Webhook.Secret = "webhook-secret"
+	cfg.Universe.ReadOnly = true
+	cfg.Universe.FederationServers = []string{"foo:10029", "bar:10029"}
+
+	flatMap := ConfigToFlatMap(&cfg)
+
+	require.Equal(t, DatabaseBackendPostgres, flatMap["databasebackend"])
+	require.Equal(t, "[redacted]", flatMap["postgres.password"])
+	require.Equal(t, "[redacted]", flatMap["webhook.secret"])
+	require.Equal(t, "true", flatMap["universe.read-only"])
+	require.Equal(
+		t, "foo:10029,bar:10029", flatMap["universe.federationserver"],
+	)
+	require.Equal(t, "none", flatMap["coinselect.tranchepreference"])
+	require.Equal(
+		t, strconv.Itoa(cfg.HashMailCourier.BackoffCfg.NumTries),
+		flatMap["hashmailcourier.numtries"],
+	)
+
+	// Options without a group namespace aren't prefixed.
+	require.Equal(t, cfg.RpcConf.TLSCertPath, flatMap["tlscertpath"])
+
+	// An empty password isn't redacted, so it's obvious it isn't set.
+	cfg.Postgres.Password = ""
+	flatMap = ConfigToFlatMap(&cfg)
+	require.Empty(t, flatMap["postgres.password"])
+}
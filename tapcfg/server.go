@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"fmt"
 	prand "math/rand"
+	"net/url"
+	"os"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btclog"
 	"github.com/lightninglabs/lndclient"
 	tap "github.com/lightninglabs/taproot-assets"
@@ -19,16 +22,20 @@ import (
 	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightninglabs/taproot-assets/webhook"
 	"github.com/lightningnetwork/lnd"
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/signal"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/xeipuuv/gojsonschema"
 )
 
 // databaseBackend is an interface that contains all methods our different
 // database backends implement.
 type databaseBackend interface {
 	tapdb.BatchedQuerier
+	tapdb.DatabaseBackuper
 	WithTx(tx *sql.Tx) *sqlc.Queries
 }
 
@@ -139,6 +146,7 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		},
 		HeaderVerifier: headerVerifier,
 		GroupVerifier:  groupVerifier,
+		RotationLookup: assetMintingStore.GroupKeyRotations,
 		Multiverse:     multiverse,
 		UniverseStats:  universeStats,
 	}
@@ -152,13 +160,69 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		federationStore, defaultClock,
 	)
 
-	proofFileStore, err := proof.NewFileArchiver(cfg.networkDir)
+	sendEventStore := tapdb.NewTransactionExecutor(db,
+		func(tx *sql.Tx) tapdb.SendEventStore {
+			return db.WithTx(tx)
+		},
+	)
+	sendEventDB := tapdb.NewSendEventDB(sendEventStore, defaultClock)
+
+	deadLetterStore := tapdb.NewTransactionExecutor(db,
+		func(tx *sql.Tx) tapdb.ProofDeadLetterStore {
+			return db.WithTx(tx)
+		},
+	)
+	deadLetterDB := tapdb.NewDeadLetterDB(deadLetterStore)
+
+	var archiverOpts []proof.FileArchiverOption
+	if cfg.CompressProofFiles {
+		archiverOpts = append(
+			archiverOpts, proof.WithFileCompression(),
+		)
+	}
+	proofFileStore, err := proof.NewFileArchiver(
+		cfg.networkDir, archiverOpts...,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to open disk archive: %v", err)
 	}
+	if cfg.LenientProofDecoding {
+		cfgLogger.Warnf("Lenient proof decoding enabled, proofs with " +
+			"unknown critical records will be accepted")
+	}
+	proof.SetLenientDecoding(cfg.LenientProofDecoding)
+
+	var metaSchema *gojsonschema.Schema
+	if cfg.MetaJSONSchema != "" {
+		schemaBytes, err := os.ReadFile(cfg.MetaJSONSchema)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read meta JSON "+
+				"schema: %w", err)
+		}
+
+		metaSchema, err = gojsonschema.NewSchema(
+			gojsonschema.NewBytesLoader(schemaBytes),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid meta JSON schema: %w",
+				err)
+		}
+	}
+
+	proofStrictness, err := proof.ParseVerificationStrictness(
+		cfg.ProofVerification,
+	)
+	if err != nil {
+		return nil, err
+	}
+	proofVerifier := &proof.BaseVerifier{
+		Strictness:       proofStrictness,
+		UniverseVerifier: tapgarden.GenUniverseVerifier(multiverse),
+		RotationLookup:   assetMintingStore.GroupKeyRotations,
+	}
 	proofArchive := proof.NewMultiArchiver(
-		&proof.BaseVerifier{}, tapdb.DefaultStoreTimeout,
-		assetStore, proofFileStore,
+		proofVerifier, tapdb.DefaultStoreTimeout, assetStore,
+		proofFileStore,
 	)
 
 	federationMembers := cfg.Universe.FederationServers
@@ -225,16 +289,140 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		}
 	}
 
+	// An email courier address advertises the encryption schemes we
+	// support, unless it explicitly lists its own. The other courier
+	// addresses don't advertise any schemes, as only the email courier
+	// negotiates one.
+	emailAddr, ok := proofCourierAddr.(*proof.EmailCourierAddr)
+	emailCfg := cfg.EmailCourier
+	if ok && emailCfg != nil && len(emailCfg.EncSchemes) > 0 &&
+		!emailAddr.AdvertisesEncSchemes() {
+
+		encSchemes, err := emailCfg.SupportedEncSchemes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid email courier "+
+				"encryption schemes: %v", err)
+		}
+
+		proofCourierAddr, err = proof.NewEmailCourierAddr(
+			proof.WithEmailEncSchemes(*emailAddr.Url(), encSchemes),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse default proof "+
+				"courier address: %v", err)
+		}
+	}
+
+	// If we compress proof files, the mailbox courier address of our
+	// addresses advertises that we support receiving them as well. HTTP(S)
+	// courier addresses can't carry the advertisement, as they're rejected
+	// with a query by older nodes.
+	courierURL := proofCourierAddr.Url()
+	isHashmail := courierURL.Scheme == proof.HashmailCourierType
+	isEmail := courierURL.Scheme == proof.EmailCourierType
+	if cfg.CompressProofFiles && (isHashmail || isEmail) &&
+		!proof.SupportsCompressedFiles(courierURL) {
+
+		proofCourierAddr, err = proof.ParseCourierAddrUrl(
+			proof.WithCompressedFiles(*courierURL),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse default proof "+
+				"courier address: %v", err)
+		}
+	}
+
+	// Interactive receivers don't announce a proof courier, so they're only
+	// served by the one configured for them.
+	var interactiveCourierAddr *url.URL
+	if cfg.InteractiveProofCourierAddr != "" {
+		courierAddr, err := proof.ParseCourierAddrString(
+			cfg.InteractiveProofCourierAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse interactive "+
+				"proof courier address: %v", err)
+		}
+		interactiveCourierAddr = courierAddr.Url()
+	}
+
+	// The fallback courier takes over deliveries that failed through the
+	// courier of the receiver. It needs to be a universe, as receivers pull
+	// their proofs from it during sync.
+	var fallbackCourierAddr *url.URL
+	if cfg.FallbackProofCourierAddr != "" {
+		courierAddr, err := proof.ParseCourierAddrString(
+			cfg.FallbackProofCourierAddr,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse fallback "+
+				"proof courier address: %v", err)
+		}
+		fallbackCourierAddr = courierAddr.Url()
+
+		if fallbackCourierAddr.Scheme != proof.UniverseRpcCourierType {
+			return nil, fmt.Errorf("fallback proof courier must "+
+				"be a %s address", proof.UniverseRpcCourierType)
+		}
+	}
+
+	nonInteractiveMode, err := proof.ParseCourierMode(
+		cfg.NonInteractiveCourierMode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse non-interactive "+
+			"courier mode: %w", err)
+	}
+	interactiveMode, err := proof.ParseCourierMode(
+		cfg.InteractiveCourierMode,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse interactive courier "+
+			"mode: %w", err)
+	}
+
 	// TODO(ffranr): This logic is leftover for integration tests which
 	//  do not yet enable a proof courier. Remove once all integration tests
 	//  support a proof courier.
+	pausedDeliveries := proof.NewPausedDeliveries()
 	var proofCourierCfg *proof.CourierCfg
 	if cfg.HashMailCourier != nil {
 		proofCourierCfg = &proof.CourierCfg{
 			ReceiverAckTimeout: cfg.HashMailCourier.ReceiverAckTimeout,
 			BackoffCfg:         cfg.HashMailCourier.BackoffCfg,
 			DeliveryLog:        assetStore,
+			PausedDeliveries:   pausedDeliveries,
+			NonInteractiveMode: nonInteractiveMode,
+			InteractiveMode:    interactiveMode,
+
+			InteractiveCourierAddr: interactiveCourierAddr,
+			FallbackCourierAddr:    fallbackCourierAddr,
+			CompressFiles:          cfg.CompressProofFiles,
 		}
+
+		if cfg.HashMailCourier.SignReceipts {
+			receiptSigner := tap.NewLndRpcReceiptSigner(
+				lndServices, tapdbAddrBook,
+			)
+			proofCourierCfg.ReceiptSigner = receiptSigner
+		}
+
+		// Missing proofs can only be recovered through the configured
+		// mailbox.
+		if cfg.ProofGapRecovery != nil &&
+			cfg.ProofGapRecovery.MailboxAddr != "" {
+
+			proofCourierCfg.GapRecovery = cfg.ProofGapRecovery
+		}
+
+		// Proofs to email:// courier addresses can only be delivered
+		// if the mail servers are configured.
+		if cfg.EmailCourier != nil && cfg.EmailCourier.SMTPHost != "" {
+			proofCourierCfg.EmailCfg = cfg.EmailCourier
+			proofCourierCfg.SharedKeyDeriver = lndServices.Signer
+		}
+
+		proofCourierCfg.HTTPCfg = cfg.HTTPCourier
 	}
 
 	reOrgWatcher := tapgarden.NewReOrgWatcher(&tapgarden.ReOrgWatcherConfig{
@@ -272,6 +460,7 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		NewRemoteDiffEngine: tap.NewRpcUniverseDiff,
 		LocalRegistrar:      baseUni,
 		SyncBatchSize:       defaultUniverseSyncBatchSize,
+		SyncWorkers:         cfg.Universe.SyncWorkers,
 	})
 
 	runtimeID := prand.Int63() // nolint:gosec
@@ -280,9 +469,17 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 			FederationDB:            federationDB,
 			UniverseSyncer:          universeSyncer,
 			LocalRegistrar:          baseUni,
+			LocalDiffEngine:         baseUni,
 			SyncInterval:            cfg.Universe.SyncInterval,
+			PushRetryInitialBackoff: cfg.Universe.PushRetryInitialBackoff,
+			PushRetryMaxBackoff:     cfg.Universe.PushRetryMaxBackoff,
+			MaxPendingPushes:        cfg.Universe.MaxPendingPushes,
+			MaxConcurrentPushes:     cfg.Universe.MaxConcurrentPushes,
+			ServerSyncTimeout:       cfg.Universe.SyncTimeout,
 			NewRemoteRegistrar:      tap.NewRpcUniverseRegistrar,
 			StaticFederationMembers: federationMembers,
+			DefaultSyncServer:       cfg.Universe.DefaultSyncServer,
+			AutoRegisterMints:       cfg.Universe.AutoRegisterMints,
 			ServerChecker: func(addr universe.ServerAddr) error {
 				return tap.CheckFederationServer(
 					runtimeID, universe.DefaultTimeout,
@@ -293,19 +490,152 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		},
 	)
 
+	trancheSelection, err := tapfreighter.ParseTrancheSelection(
+		cfg.CoinSelect.TranchePreference,
+		cfg.CoinSelect.TrancheGenesisPoint,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse tranche selection: %w",
+			err)
+	}
+
+	confPolicy, err := tapfreighter.ParseConfirmationPolicy(
+		cfg.CoinSelect.MinConfs, cfg.CoinSelect.AssetMinConfs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse confirmation policy: "+
+			"%w", err)
+	}
+
+	relaxPolicy, err := tapfreighter.ParseCoinRelaxationPolicy(
+		cfg.CoinSelect.RelaxSteps, cfg.CoinSelect.RelaxMinConfs,
+		cfg.CoinSelect.RelaxMaxInputs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse coin relaxation "+
+			"policy: %w", err)
+	}
+
+	zeroChangePolicy, err := tapfreighter.ParseZeroChangePolicy(
+		cfg.CoinSelect.ZeroChangePolicy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse zero change policy: %w",
+			err)
+	}
+
+	anchorOutputOrder, err := tapfreighter.ParseAnchorOutputOrder(
+		cfg.AnchorOutputOrder,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse anchor output order: "+
+			"%w", err)
+	}
+
+	addrReusePolicy, err := tapgarden.ParseAddrReusePolicy(
+		cfg.AddrReusePolicy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse address reuse policy: "+
+			"%w", err)
+	}
+
+	partialReceivePolicy, err := tapgarden.ParsePartialReceivePolicy(
+		cfg.PartialReceivePolicy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse partial receive "+
+			"policy: %w", err)
+	}
+
+	// The fee rate of unconfirmed anchor transactions is only escalated
+	// automatically if explicitly enabled.
+	var feeEscalation *tapfreighter.FeeEscalationPolicy
+	if cfg.FeeEscalation.Enable {
+		feeEscalation = &tapfreighter.FeeEscalationPolicy{
+			After:    cfg.FeeEscalation.After,
+			Interval: cfg.FeeEscalation.Interval,
+			Step: chainfee.SatPerKVByte(
+				cfg.FeeEscalation.Step * 1000,
+			).FeePerKWeight(),
+			MaxFeeRate: chainfee.SatPerKVByte(
+				cfg.FeeEscalation.MaxFeeRate * 1000,
+			).FeePerKWeight(),
+		}
+	}
+
+	// Anchor outputs that only anchor spent assets are only swept back
+	// into the wallet if explicitly enabled.
+	var anchorSweep *tapfreighter.AnchorSweepPolicy
+	if cfg.AnchorSweep.Enable {
+		anchorSweep = &tapfreighter.AnchorSweepPolicy{
+			Interval: cfg.AnchorSweep.Interval,
+			MaxOutputValue: btcutil.Amount(
+				cfg.AnchorSweep.MaxOutputValue,
+			),
+			MaxFeeRate: chainfee.SatPerKVByte(
+				cfg.AnchorSweep.MaxFeeRate * 1000,
+			).FeePerKWeight(),
+			ConfTarget: cfg.AnchorSweep.ConfTarget,
+			FeeBudget:  cfg.AnchorSweep.FeeBudget,
+		}
+	}
+
+	completion, err := tapfreighter.ParseDeliveryCompletion(
+		cfg.ProofDeliveryCompletion,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse proof delivery "+
+			"completion: %w", err)
+	}
+	deliveryCompletion := &tapfreighter.DeliveryCompletionPolicy{
+		Completion:    completion,
+		QuorumPercent: cfg.ProofDeliveryQuorum,
+	}
+
+	reOrgPolicy, err := tapfreighter.ParseReOrgPolicy(
+		cfg.TransferReOrgPolicy,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse transfer reorg "+
+			"policy: %w", err)
+	}
+
 	virtualTxSigner := tap.NewLndRpcVirtualTxSigner(lndServices)
-	coinSelect := tapfreighter.NewCoinSelect(assetStore)
+	coinSelect := tapfreighter.NewCoinSelect(
+		assetStore, confPolicy, relaxPolicy, chainBridge,
+	)
 	assetWallet := tapfreighter.NewAssetWallet(&tapfreighter.WalletConfig{
-		CoinSelector: coinSelect,
-		AssetProofs:  proofArchive,
-		AddrBook:     tapdbAddrBook,
-		KeyRing:      keyRing,
-		Signer:       virtualTxSigner,
-		TxValidator:  &tap.ValidatorV0{},
-		Wallet:       walletAnchor,
-		ChainParams:  &tapChainParams,
+		CoinSelector:       coinSelect,
+		AssetProofs:        proofArchive,
+		AddrBook:           tapdbAddrBook,
+		KeyRing:            keyRing,
+		Signer:             virtualTxSigner,
+		TxValidator:        &tap.ValidatorV0{},
+		Wallet:             walletAnchor,
+		ChainParams:        &tapChainParams,
+		TrancheSelection:   trancheSelection,
+		MaxInputs:          cfg.CoinSelect.MaxInputs,
+		ZeroChangePolicy:   zeroChangePolicy,
+		AnchorOutputSorter: anchorOutputOrder.Sorter(),
 	})
 
+	groupKeyRotator := tapgarden.NewGroupKeyRotator(
+		&tapgarden.GroupKeyRotatorConfig{
+			Wallet:      walletAnchor,
+			ChainBridge: chainBridge,
+			Log:         assetMintingStore,
+			KeyRing:     keyRing,
+			Signer:      tap.NewLndRpcMessageSigner(lndServices),
+		},
+	)
+
+	// The webhook is only notified if its URL is configured.
+	var webhookCfg *webhook.Config
+	if cfg.Webhook != nil && cfg.Webhook.URL != "" {
+		webhookCfg = cfg.Webhook
+	}
+
 	return &tap.Config{
 		DebugLevel:   cfg.DebugLevel,
 		RuntimeID:    runtimeID,
@@ -325,10 +655,14 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 				Universe:              universeFederation,
 				ProofWatcher:          reOrgWatcher,
 				UniversePushBatchSize: defaultUniverseSyncBatchSize,
+				IssuanceAuthorizer:    groupKeyRotator,
 			},
-			BatchTicker:  ticker.NewForce(cfg.BatchMintingInterval),
-			ProofUpdates: proofArchive,
-			ErrChan:      mainErrChan,
+			BatchTicker: ticker.NewForce(
+				cfg.BatchMintingInterval,
+			),
+			ProofUpdates:   proofArchive,
+			MetaJSONSchema: metaSchema,
+			ErrChan:        mainErrChan,
 		}),
 		AssetCustodian: tapgarden.NewCustodian(
 			&tapgarden.CustodianConfig{
@@ -338,17 +672,25 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 				GroupVerifier: tapgarden.GenGroupVerifier(
 					context.Background(), assetMintingStore,
 				),
-				AddrBook:        addrBook,
-				ProofArchive:    proofArchive,
-				ProofNotifier:   assetStore,
-				ErrChan:         mainErrChan,
-				ProofCourierCfg: proofCourierCfg,
-				ProofWatcher:    reOrgWatcher,
+				AddrBook:             addrBook,
+				ProofArchive:         proofArchive,
+				ProofNotifier:        assetStore,
+				ErrChan:              mainErrChan,
+				ProofCourierCfg:      proofCourierCfg,
+				ProofWatcher:         reOrgWatcher,
+				AddrReusePolicy:      addrReusePolicy,
+				PartialReceivePolicy: partialReceivePolicy,
+				ProofImportRetry:     cfg.ProofImportRetry,
+				DeadLetterStore:      deadLetterDB,
+				MaxProofChainDepth:   cfg.MaxProofChainDepth,
 			},
 		),
+		GroupKeyRotator:         groupKeyRotator,
 		ChainBridge:             chainBridge,
 		AddrBook:                addrBook,
 		DefaultProofCourierAddr: proofCourierAddr.Url(),
+		MaxAddrAmount:           cfg.MaxAddrAmount,
+		PausedProofDeliveries:   pausedDeliveries,
 		ProofArchive:            proofArchive,
 		AssetWallet:             assetWallet,
 		CoinSelect:              coinSelect,
@@ -367,7 +709,18 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 				AssetProofs:     proofFileStore,
 				ProofCourierCfg: proofCourierCfg,
 				ProofWatcher:    reOrgWatcher,
-				ErrChan:         mainErrChan,
+				AutoRetryAbandoned: cfg.
+					AutoRetryAbandonedTransfers,
+				FeeEscalation:      feeEscalation,
+				AnchorSweep:        anchorSweep,
+				ChainParams:        &tapChainParams,
+				DeliveryCompletion: deliveryCompletion,
+				ReOrgPolicy:        reOrgPolicy,
+				ReOrgSafeDepth:     uint32(cfg.ReOrgSafeDepth),
+				FeeBumpAnchor:      cfg.FeeBumpAnchor,
+				IdempotencyKeyRetention: cfg.
+					SendIdempotencyKeyRetention,
+				ErrChan: mainErrChan,
 			},
 		),
 		BaseUniverse:         baseUni,
@@ -375,6 +728,10 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 		UniverseFederation:   universeFederation,
 		UniverseStats:        universeStats,
 		UniversePublicAccess: cfg.Universe.PublicAccess,
+		UniverseReadOnly:     cfg.Universe.ReadOnly,
+		EffectiveConfig:      ConfigToFlatMap(cfg),
+		SendEventRetention:   cfg.SendEventRetention,
+		Webhook:              webhookCfg,
 		LogWriter:            cfg.LogWriter,
 		DatabaseConfig: &tap.DatabaseConfig{
 			RootKeyStore: tapdb.NewRootKeyStore(rksDB),
@@ -383,6 +740,8 @@ func genServerConfig(cfg *Config, cfgLogger btclog.Logger,
 			TapAddrBook:  tapdbAddrBook,
 			Multiverse:   multiverse,
 			FederationDB: federationDB,
+			SendEvents:   sendEventDB,
+			Backuper:     db,
 		},
 		Prometheus: cfg.Prometheus,
 	}, nil
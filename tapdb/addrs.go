@@ -14,6 +14,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
@@ -48,6 +49,14 @@ type (
 	// updating an existing one.
 	UpsertAddrEvent = sqlc.UpsertAddrEventParams
 
+	// SetAddrEventAssetAmt is a type alias for the params needed to record
+	// the received asset amount of an address event.
+	SetAddrEventAssetAmt = sqlc.SetAddrEventAssetAmtParams
+
+	// SetAddrEventQuarantined is a type alias for the params needed to
+	// mark an address event as quarantined.
+	SetAddrEventQuarantined = sqlc.SetAddrEventQuarantinedParams
+
 	// AddrEvent is a type alias for fetching an address event row.
 	AddrEvent = sqlc.FetchAddrEventRow
 
@@ -64,6 +73,21 @@ type (
 
 	// ScriptKey is a type alias for fetching the script key information.
 	ScriptKey = sqlc.FetchScriptKeyByTweakedKeyRow
+
+	// ScriptKeyWithInternal is a type alias for fetching a script key
+	// together with its internal key.
+	ScriptKeyWithInternal = sqlc.FetchAllScriptKeysRow
+
+	// NewKeyRange is a type alias for inserting a reserved key range.
+	NewKeyRange = sqlc.InsertKeyRangeParams
+
+	// NewAddrIdempotencyKey is a type alias for inserting the idempotency
+	// key of an address.
+	NewAddrIdempotencyKey = sqlc.InsertAddrIdempotencyKeyParams
+
+	// AddrIdempotencyKey is a type alias for fetching the idempotency key
+	// of an address.
+	AddrIdempotencyKey = sqlc.FetchAddrIdempotencyKeyRow
 )
 
 // AddrBook is an interface that represents the storage backed needed to create
@@ -103,6 +127,16 @@ type AddrBook interface {
 	// wallet.
 	SetAddrManaged(ctx context.Context, arg AddrManaged) error
 
+	// SetAddrEventAssetAmt records the received asset amount of an
+	// address event.
+	SetAddrEventAssetAmt(ctx context.Context,
+		arg SetAddrEventAssetAmt) error
+
+	// SetAddrEventQuarantined marks an address event as quarantined or
+	// releases it from quarantine.
+	SetAddrEventQuarantined(ctx context.Context,
+		arg SetAddrEventQuarantined) error
+
 	// UpsertManagedUTXO inserts a new or updates an existing managed UTXO
 	// to disk and returns the primary key.
 	UpsertManagedUTXO(ctx context.Context, arg RawManagedUTXO) (int64,
@@ -139,6 +173,36 @@ type AddrBook interface {
 	// corresponding internal key from the database.
 	FetchScriptKeyByTweakedKey(ctx context.Context,
 		tweakedScriptKey []byte) (ScriptKey, error)
+
+	// FetchInternalKeyLocator fetches the key locator of an internal key.
+	FetchInternalKeyLocator(ctx context.Context,
+		rawKey []byte) (sqlc.FetchInternalKeyLocatorRow, error)
+
+	// AllInternalKeys returns all internal keys known to the database.
+	AllInternalKeys(ctx context.Context) ([]sqlc.InternalKey, error)
+
+	// FetchAllScriptKeys returns all script keys known to the database,
+	// together with their internal keys.
+	FetchAllScriptKeys(ctx context.Context) ([]ScriptKeyWithInternal,
+		error)
+
+	// InsertKeyRange inserts a key range that was reserved from the
+	// wallet.
+	InsertKeyRange(ctx context.Context, arg NewKeyRange) error
+
+	// FetchKeyRanges returns all key ranges that were reserved from the
+	// wallet.
+	FetchKeyRanges(ctx context.Context) ([]sqlc.KeyRange, error)
+
+	// InsertAddrIdempotencyKey inserts the idempotency key an address was
+	// created with.
+	InsertAddrIdempotencyKey(ctx context.Context,
+		arg NewAddrIdempotencyKey) error
+
+	// FetchAddrIdempotencyKey fetches the parameter hash and the taproot
+	// output key of the address created with an idempotency key.
+	FetchAddrIdempotencyKey(ctx context.Context,
+		idempotencyKey string) (AddrIdempotencyKey, error)
 }
 
 // AddrBookTxOptions defines the set of db txn options the AddrBook
@@ -208,89 +272,125 @@ func (t *TapAddressBook) InsertAddrs(ctx context.Context,
 
 	var writeTxOpts AddrBookTxOptions
 	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
-		// For each of the addresses listed, we'll insert the two new
-		// internal keys, then use those returned primary key IDs to
-		// returned to insert the address itself.
-		for idx := range addrs {
-			// The asset genesis should already be known at this
-			// point, so we'll just fetch it so we can obtain the
-			// genAssetID.
-			addr := addrs[idx]
-			assetGen, err := db.FetchGenesisByAssetID(
-				ctx, addr.AssetID[:],
-			)
-			if err != nil {
-				return err
-			}
+		return insertAddrs(ctx, db, addrs...)
+	})
+}
 
-			genAssetID := assetGen.GenAssetID
+// InsertAddrBatch inserts the given addresses together with the tapscript
+// trees their script keys commit to in a single database transaction. Either
+// all or none of the addresses are inserted.
+func (t *TapAddressBook) InsertAddrBatch(ctx context.Context,
+	addrs []address.AddrWithKeyInfo, trees []*asset.TapscriptTree) error {
 
-			rawScriptKeyID, err := insertInternalKey(
-				ctx, db, addr.ScriptKeyTweak.RawKey,
-			)
-			if err != nil {
-				return fmt.Errorf("unable to insert internal "+
-					"script key: %w", err)
-			}
-			scriptKeyID, err := db.UpsertScriptKey(ctx, NewScriptKey{
-				InternalKeyID:    rawScriptKeyID,
-				TweakedScriptKey: addr.ScriptKey.SerializeCompressed(),
-				Tweak:            addr.ScriptKeyTweak.Tweak,
-			})
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		// We store the leaves of the trees first, so they're known by
+		// the time an asset is sent to one of the addresses.
+		for _, tree := range trees {
+			_, err := upsertTapscriptTree(ctx, db, tree)
 			if err != nil {
-				return fmt.Errorf("unable to insert script "+
-					"key: %w", err)
+				return fmt.Errorf("unable to insert tapscript "+
+					"tree: %w", err)
 			}
+		}
 
-			taprootKeyID, err := insertInternalKey(
-				ctx, db, addr.InternalKeyDesc,
-			)
-			if err != nil {
-				return fmt.Errorf("unable to insert internal "+
-					"taproot key: %w", err)
-			}
+		return insertAddrs(ctx, db, addrs...)
+	})
+}
 
-			siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
-				addr.TapscriptSibling,
-			)
-			if err != nil {
-				return fmt.Errorf("unable to encode tapscript "+
-					"sibling: %w", err)
-			}
+// insertAddrs inserts the given addresses using the given database
+// transaction.
+func insertAddrs(ctx context.Context, db AddrBook,
+	addrs ...address.AddrWithKeyInfo) error {
 
-			var groupKeyBytes []byte
-			if addr.GroupKey != nil {
-				groupKeyBytes = addr.GroupKey.SerializeCompressed()
-			}
+	// For each of the addresses listed, we'll insert the two new internal
+	// keys, then use those returned primary key IDs to returned to insert
+	// the address itself.
+	for idx := range addrs {
+		// The asset genesis should already be known at this
+		// point, so we'll just fetch it so we can obtain the
+		// genAssetID.
+		addr := addrs[idx]
+		assetGen, err := db.FetchGenesisByAssetID(
+			ctx, addr.AssetID[:],
+		)
+		if err != nil {
+			return err
+		}
 
-			proofCourierAddrBytes := []byte(
-				addr.Tap.ProofCourierAddr.String(),
-			)
+		genAssetID := assetGen.GenAssetID
 
-			_, err = db.InsertAddr(ctx, NewAddr{
-				Version:          int16(addr.Version),
-				AssetVersion:     int16(addr.AssetVersion),
-				GenesisAssetID:   genAssetID,
-				GroupKey:         groupKeyBytes,
-				ScriptKeyID:      scriptKeyID,
-				TaprootKeyID:     taprootKeyID,
-				TapscriptSibling: siblingBytes,
-				TaprootOutputKey: schnorr.SerializePubKey(
-					&addr.TaprootOutputKey,
-				),
-				Amount:           int64(addr.Amount),
-				AssetType:        int16(assetGen.AssetType),
-				CreationTime:     addr.CreationTime.UTC(),
-				ProofCourierAddr: proofCourierAddrBytes,
-			})
-			if err != nil {
-				return fmt.Errorf("unable to insert addr: %w",
-					err)
-			}
+		rawScriptKeyID, err := insertInternalKey(
+			ctx, db, addr.ScriptKeyTweak.RawKey,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert internal "+
+				"script key: %w", err)
+		}
+		scriptKeyID, err := db.UpsertScriptKey(ctx, NewScriptKey{
+			InternalKeyID:    rawScriptKeyID,
+			TweakedScriptKey: addr.ScriptKey.SerializeCompressed(),
+			Tweak:            addr.ScriptKeyTweak.Tweak,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert script "+
+				"key: %w", err)
 		}
 
-		return nil
-	})
+		taprootKeyID, err := insertInternalKey(
+			ctx, db, addr.InternalKeyDesc,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert internal "+
+				"taproot key: %w", err)
+		}
+
+		siblingBytes, _, err := commitment.MaybeEncodeTapscriptPreimage(
+			addr.TapscriptSibling,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to encode tapscript "+
+				"sibling: %w", err)
+		}
+
+		var groupKeyBytes []byte
+		if addr.GroupKey != nil {
+			groupKeyBytes = addr.GroupKey.SerializeCompressed()
+		}
+
+		proofCourierAddrBytes := []byte(
+			addr.Tap.ProofCourierAddr.String(),
+		)
+
+		var minAmount sql.NullInt64
+		if addr.AllowAnyAmount {
+			minAmount = sqlInt64(addr.MinAmount)
+		}
+
+		_, err = db.InsertAddr(ctx, NewAddr{
+			Version:          int16(addr.Version),
+			AssetVersion:     int16(addr.AssetVersion),
+			GenesisAssetID:   genAssetID,
+			GroupKey:         groupKeyBytes,
+			ScriptKeyID:      scriptKeyID,
+			TaprootKeyID:     taprootKeyID,
+			TapscriptSibling: siblingBytes,
+			TaprootOutputKey: schnorr.SerializePubKey(
+				&addr.TaprootOutputKey,
+			),
+			Amount:           int64(addr.Amount),
+			AssetType:        int16(assetGen.AssetType),
+			CreationTime:     addr.CreationTime.UTC(),
+			ProofCourierAddr: proofCourierAddrBytes,
+			MinAmount:        minAmount,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert addr: %w",
+				err)
+		}
+	}
+
+	return nil
 }
 
 // QueryAddrs attempts to query for the set of addresses on disk given the
@@ -438,9 +538,9 @@ func (t *TapAddressBook) QueryAddrs(ctx context.Context,
 				groupKey, groupWitness,
 				*scriptKey, *internalKey, uint64(addr.Amount),
 				tapscriptSibling, t.params, *proofCourierAddr,
-				address.WithAssetVersion(
-					asset.Version(addr.AssetVersion),
-				),
+				addrOptsFromDB(
+					addr.AssetVersion, addr.MinAmount,
+				)...,
 			)
 			if err != nil {
 				return fmt.Errorf("unable to make addr: %w", err)
@@ -489,6 +589,23 @@ func (t *TapAddressBook) AddrByTaprootOutput(ctx context.Context,
 	return addr, nil
 }
 
+// addrOptsFromDB returns the options to re-create an address with from the
+// given asset version and minimum amount columns of the address.
+func addrOptsFromDB(assetVersion int16,
+	minAmount sql.NullInt64) []address.NewAddrOpt {
+
+	opts := []address.NewAddrOpt{
+		address.WithAssetVersion(asset.Version(assetVersion)),
+	}
+	if minAmount.Valid {
+		opts = append(opts, address.WithAnyAmount(
+			uint64(minAmount.Int64),
+		))
+	}
+
+	return opts
+}
+
 // fetchAddr fetches a single address identified by its taproot output key from
 // the database and populates all its fields.
 func fetchAddr(ctx context.Context, db AddrBook, params *address.ChainParams,
@@ -588,7 +705,7 @@ func fetchAddr(ctx context.Context, db AddrBook, params *address.ChainParams,
 		address.Version(dbAddr.Version), genesis, groupKey,
 		groupWitness, *scriptKey, *internalKey, uint64(dbAddr.Amount),
 		tapscriptSibling, params, *proofCourierAddr,
-		address.WithAssetVersion(asset.Version(dbAddr.AssetVersion)),
+		addrOptsFromDB(dbAddr.AssetVersion, dbAddr.MinAmount)...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to make addr: %w", err)
@@ -670,6 +787,79 @@ func (t *TapAddressBook) InsertScriptKey(ctx context.Context,
 	})
 }
 
+// InsertSigningKeys inserts the given internal and script keys in a single
+// database transaction, so either all or none of them are stored.
+func (t *TapAddressBook) InsertSigningKeys(ctx context.Context,
+	internalKeys []keychain.KeyDescriptor,
+	scriptKeys []asset.ScriptKey) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(q AddrBook) error {
+		for _, keyDesc := range internalKeys {
+			_, err := insertInternalKey(ctx, q, keyDesc)
+			if err != nil {
+				return fmt.Errorf("error inserting internal "+
+					"key: %w", err)
+			}
+		}
+
+		for _, scriptKey := range scriptKeys {
+			internalKeyID, err := insertInternalKey(
+				ctx, q, scriptKey.RawKey,
+			)
+			if err != nil {
+				return fmt.Errorf("error inserting internal "+
+					"key: %w", err)
+			}
+
+			_, err = q.UpsertScriptKey(ctx, NewScriptKey{
+				InternalKeyID: internalKeyID,
+				TweakedScriptKey: scriptKey.PubKey.
+					SerializeCompressed(),
+				Tweak: scriptKey.Tweak,
+			})
+			if err != nil {
+				return fmt.Errorf("error inserting script "+
+					"key: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// InsertTapscriptTree inserts the leaves of a tapscript tree that a script key
+// commits to, so an asset sent to that script key can later be spent through
+// one of them.
+func (t *TapAddressBook) InsertTapscriptTree(ctx context.Context,
+	tree *asset.TapscriptTree) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(q AddrBook) error {
+		_, err := upsertTapscriptTree(ctx, q, tree)
+		return err
+	})
+}
+
+// FetchTapscriptLeaves fetches the leaves of the tapscript tree with the given
+// root hash. No leaves are returned if the tree isn't known.
+func (t *TapAddressBook) FetchTapscriptLeaves(ctx context.Context,
+	rootHash []byte) ([]txscript.TapLeaf, error) {
+
+	var leaves []txscript.TapLeaf
+	readOpts := NewAddrBookReadTx()
+	err := t.db.ExecTx(ctx, &readOpts, func(q AddrBook) error {
+		var err error
+		leaves, err = fetchTapscriptLeaves(ctx, q, rootHash)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return leaves, nil
+}
+
 // GetOrCreateEvent creates a new address event for the given status, address
 // and transaction. If an event for that address and transaction already exists,
 // then the status and transaction information is updated instead.
@@ -791,6 +981,9 @@ func (t *TapAddressBook) QueryAddrEvents(
 	if len(params.AddrTaprootOutputKey) > 0 {
 		sqlQuery.AddrTaprootKey = params.AddrTaprootOutputKey
 	}
+	if len(params.ScriptKey) > 0 {
+		sqlQuery.ScriptKey = params.ScriptKey
+	}
 	if params.StatusFrom != nil {
 		sqlQuery.StatusFrom = int16(*params.StatusFrom)
 	}
@@ -878,6 +1071,8 @@ func fetchEvent(ctx context.Context, db AddrBook, eventID int64,
 		InternalKey:        internalKey,
 		ConfirmationHeight: uint32(dbEvent.ConfirmationHeight.Int32),
 		HasProof:           dbEvent.AssetProofID.Valid,
+		AssetAmt:           extractSqlInt64[uint64](dbEvent.AssetAmt),
+		Quarantined:        dbEvent.Quarantined,
 	}, nil
 }
 
@@ -889,6 +1084,13 @@ func (t *TapAddressBook) CompleteEvent(ctx context.Context,
 
 	scriptKeyBytes := event.Addr.ScriptKey.SerializeCompressed()
 
+	// The received amount is only known if it was recorded on the event,
+	// otherwise the amount stored previously is kept.
+	var assetAmt sql.NullInt64
+	if event.AssetAmt != 0 {
+		assetAmt = sqlInt64(event.AssetAmt)
+	}
+
 	var writeTxOpts AddrBookTxOptions
 	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
 		proofData, err := db.FetchAssetProof(ctx, scriptKeyBytes)
@@ -905,11 +1107,40 @@ func (t *TapAddressBook) CompleteEvent(ctx context.Context,
 			ChainTxnOutputIndex: int32(anchorPoint.Index),
 			AssetProofID:        sqlInt64(proofData.ProofID),
 			AssetID:             sqlInt64(proofData.AssetID),
+			AssetAmt:            assetAmt,
 		})
 		return err
 	})
 }
 
+// SetEventAssetAmt records the number of asset units that were actually
+// received for an address event, as seen in the proof of the transfer.
+func (t *TapAddressBook) SetEventAssetAmt(ctx context.Context,
+	event *address.Event, assetAmt uint64) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		return db.SetAddrEventAssetAmt(ctx, SetAddrEventAssetAmt{
+			ID:       event.ID,
+			AssetAmt: sqlInt64(assetAmt),
+		})
+	})
+}
+
+// SetEventQuarantined marks an address event as quarantined, meaning its
+// inbound transfer is only completed once the proof is imported manually.
+func (t *TapAddressBook) SetEventQuarantined(ctx context.Context,
+	event *address.Event, quarantined bool) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		return db.SetAddrEventQuarantined(ctx, SetAddrEventQuarantined{
+			ID:          event.ID,
+			Quarantined: quarantined,
+		})
+	})
+}
+
 // QueryAssetGroup attempts to fetch an asset group by its asset ID. If the
 // asset group cannot be found, then ErrAssetGroupUnknown is returned.
 func (t *TapAddressBook) QueryAssetGroup(ctx context.Context,
@@ -1073,7 +1304,274 @@ func (t *TapAddressBook) FetchScriptKey(ctx context.Context,
 	return scriptKey, nil
 }
 
+// FetchInternalKeyLocator attempts to fetch the key locator information for the
+// given raw internal key. If the key cannot be found, then
+// ErrInternalKeyNotFound is returned.
+func (t *TapAddressBook) FetchInternalKeyLocator(ctx context.Context,
+	rawKey *btcec.PublicKey) (keychain.KeyLocator, error) {
+
+	var (
+		readOpts = NewAddrBookReadTx()
+		keyLoc   keychain.KeyLocator
+	)
+	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		dbKey, err := db.FetchInternalKeyLocator(
+			ctx, rawKey.SerializeCompressed(),
+		)
+		if err != nil {
+			return err
+		}
+
+		keyLoc = keychain.KeyLocator{
+			Family: keychain.KeyFamily(dbKey.KeyFamily),
+			Index:  uint32(dbKey.KeyIndex),
+		}
+
+		return nil
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return keyLoc, address.ErrInternalKeyNotFound
+
+	case err != nil:
+		return keyLoc, err
+	}
+
+	return keyLoc, nil
+}
+
 // A set of compile-time assertions to ensure that TapAddressBook meets the
 // address.Storage and address.EventStorage interface.
 var _ address.Storage = (*TapAddressBook)(nil)
 var _ address.EventStorage = (*TapAddressBook)(nil)
+
+// FetchAllInternalKeys returns the key descriptors of all internal keys known
+// to the database. This includes the internal keys of script keys.
+func (t *TapAddressBook) FetchAllInternalKeys(
+	ctx context.Context) ([]keychain.KeyDescriptor, error) {
+
+	var (
+		readOpts = NewAddrBookReadTx()
+		keys     []keychain.KeyDescriptor
+	)
+	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		dbKeys, err := db.AllInternalKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		keys = make([]keychain.KeyDescriptor, 0, len(dbKeys))
+		for _, dbKey := range dbKeys {
+			keyDesc, err := parseKeyDesc(
+				dbKey.RawKey, dbKey.KeyFamily, dbKey.KeyIndex,
+			)
+			if err != nil {
+				return err
+			}
+
+			keys = append(keys, keyDesc)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FetchAllScriptKeys returns all script keys known to the database, including
+// the key descriptors of their internal keys and their tweaks.
+func (t *TapAddressBook) FetchAllScriptKeys(
+	ctx context.Context) ([]asset.ScriptKey, error) {
+
+	var (
+		readOpts   = NewAddrBookReadTx()
+		scriptKeys []asset.ScriptKey
+	)
+	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		dbKeys, err := db.FetchAllScriptKeys(ctx)
+		if err != nil {
+			return err
+		}
+
+		scriptKeys = make([]asset.ScriptKey, 0, len(dbKeys))
+		for _, dbKey := range dbKeys {
+			tweakedKey, err := btcec.ParsePubKey(
+				dbKey.TweakedScriptKey,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to parse script "+
+					"key: %w", err)
+			}
+
+			rawKey, err := parseKeyDesc(
+				dbKey.RawKey, dbKey.KeyFamily, dbKey.KeyIndex,
+			)
+			if err != nil {
+				return err
+			}
+
+			scriptKeys = append(scriptKeys, asset.ScriptKey{
+				PubKey: tweakedKey,
+				TweakedScriptKey: &asset.TweakedScriptKey{
+					RawKey: rawKey,
+					Tweak:  dbKey.Tweak,
+				},
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptKeys, nil
+}
+
+// InsertKeyRange stores a key range that was reserved from the wallet.
+func (t *TapAddressBook) InsertKeyRange(ctx context.Context,
+	keyRange address.KeyRange) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		return db.InsertKeyRange(ctx, NewKeyRange{
+			KeyFamily:    int32(keyRange.Family),
+			FirstIndex:   int32(keyRange.FirstIndex),
+			LastIndex:    int32(keyRange.LastIndex),
+			Purpose:      keyRange.Purpose,
+			CreationTime: keyRange.CreationTime.UTC(),
+		})
+	})
+}
+
+// FetchKeyRanges returns all key ranges that were reserved from the wallet,
+// ordered by key family and first key index.
+func (t *TapAddressBook) FetchKeyRanges(
+	ctx context.Context) ([]address.KeyRange, error) {
+
+	var (
+		readOpts  = NewAddrBookReadTx()
+		keyRanges []address.KeyRange
+	)
+	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		dbRanges, err := db.FetchKeyRanges(ctx)
+		if err != nil {
+			return err
+		}
+
+		keyRanges = make([]address.KeyRange, len(dbRanges))
+		for idx, dbRange := range dbRanges {
+			keyRanges[idx] = address.KeyRange{
+				Family: keychain.KeyFamily(
+					dbRange.KeyFamily,
+				),
+				FirstIndex:   uint32(dbRange.FirstIndex),
+				LastIndex:    uint32(dbRange.LastIndex),
+				Purpose:      dbRange.Purpose,
+				CreationTime: dbRange.CreationTime,
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keyRanges, nil
+}
+
+// parseKeyDesc parses the key descriptor of an internal key as it is stored in
+// the database.
+func parseKeyDesc(rawKey []byte, family, index int32) (keychain.KeyDescriptor,
+	error) {
+
+	pubKey, err := btcec.ParsePubKey(rawKey)
+	if err != nil {
+		return keychain.KeyDescriptor{}, fmt.Errorf("unable to parse "+
+			"internal key: %w", err)
+	}
+
+	return keychain.KeyDescriptor{
+		PubKey: pubKey,
+		KeyLocator: keychain.KeyLocator{
+			Family: keychain.KeyFamily(family),
+			Index:  uint32(index),
+		},
+	}, nil
+}
+
+// InsertIdempotentAddr inserts the given address, the tapscript tree its
+// script key commits to, if any, and the idempotency key it was created with
+// in a single database transaction. The hash of the creation parameters is
+// stored together with the key.
+func (t *TapAddressBook) InsertIdempotentAddr(ctx context.Context,
+	addr address.AddrWithKeyInfo, tree *asset.TapscriptTree, key string,
+	paramsHash []byte) error {
+
+	var writeTxOpts AddrBookTxOptions
+	return t.db.ExecTx(ctx, &writeTxOpts, func(db AddrBook) error {
+		if tree != nil {
+			_, err := upsertTapscriptTree(ctx, db, tree)
+			if err != nil {
+				return fmt.Errorf("unable to insert tapscript "+
+					"tree: %w", err)
+			}
+		}
+
+		if err := insertAddrs(ctx, db, addr); err != nil {
+			return err
+		}
+
+		return db.InsertAddrIdempotencyKey(ctx, NewAddrIdempotencyKey{
+			IdempotencyKey: key,
+			ParamsHash:     paramsHash,
+			TaprootOutputKey: schnorr.SerializePubKey(
+				&addr.TaprootOutputKey,
+			),
+		})
+	})
+}
+
+// FetchAddrIdempotencyKey returns the hash of the creation parameters and the
+// address that was created with the given idempotency key. address.ErrNoAddr
+// is returned if the key is unknown.
+func (t *TapAddressBook) FetchAddrIdempotencyKey(ctx context.Context,
+	key string) ([]byte, *address.AddrWithKeyInfo, error) {
+
+	var (
+		readOpts   = NewAddrBookReadTx()
+		paramsHash []byte
+		addr       *address.AddrWithKeyInfo
+	)
+	err := t.db.ExecTx(ctx, &readOpts, func(db AddrBook) error {
+		dbKey, err := db.FetchAddrIdempotencyKey(ctx, key)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return address.ErrNoAddr
+
+		case err != nil:
+			return err
+		}
+
+		taprootOutputKey, err := schnorr.ParsePubKey(
+			dbKey.TaprootOutputKey,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode taproot output "+
+				"key: %w", err)
+		}
+
+		paramsHash = dbKey.ParamsHash
+		addr, err = fetchAddr(ctx, db, t.params, taprootOutputKey)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return paramsHash, addr, nil
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"math/rand"
+	"net/url"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/internal/test"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/stretchr/testify/require"
 )
@@ -194,6 +198,46 @@ func TestAddressInsertion(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, scriptKey.RawKey.PubKey)
 		require.False(t, scriptKey.RawKey.IsEmpty())
+
+		// The same goes for the key locator of the internal key.
+		keyLoc, err := addrBook.FetchInternalKeyLocator(
+			ctx, addr.InternalKeyDesc.PubKey,
+		)
+		require.NoError(t, err)
+		require.Equal(t, addr.InternalKeyDesc.KeyLocator, keyLoc)
+	}
+
+	// An unknown internal key should result in a distinct error.
+	_, err = addrBook.FetchInternalKeyLocator(ctx, test.RandPubKey(t))
+	require.ErrorIs(t, err, address.ErrInternalKeyNotFound)
+
+	// The complete key inventory should contain the script and internal
+	// keys of all addresses.
+	allScriptKeys, err := addrBook.FetchAllScriptKeys(ctx)
+	require.NoError(t, err)
+	allInternalKeys, err := addrBook.FetchAllInternalKeys(ctx)
+	require.NoError(t, err)
+
+	scriptKeySet := make(map[asset.SerializedKey]struct{})
+	for _, scriptKey := range allScriptKeys {
+		scriptKeySet[asset.ToSerialized(scriptKey.PubKey)] = struct{}{}
+	}
+	internalKeySet := make(map[asset.SerializedKey]keychain.KeyLocator)
+	for _, keyDesc := range allInternalKeys {
+		internalKeySet[asset.ToSerialized(keyDesc.PubKey)] =
+			keyDesc.KeyLocator
+	}
+	for _, addr := range addrs {
+		require.Contains(
+			t, scriptKeySet, asset.ToSerialized(&addr.ScriptKey),
+		)
+
+		internalKey := asset.ToSerialized(addr.InternalKeyDesc.PubKey)
+		require.Contains(t, internalKeySet, internalKey)
+		require.Equal(
+			t, addr.InternalKeyDesc.KeyLocator,
+			internalKeySet[internalKey],
+		)
 	}
 
 	// All addresses should be unmanaged at this point.
@@ -467,6 +511,29 @@ func TestAddrEventCreation(t *testing.T) {
 
 		assertEqualAddrEvent(t, *events[idx], *actual)
 	}
+
+	// Finally, we record a partial receive on each event and make sure the
+	// received amount survives a further status update. Collectible
+	// addresses always request a single unit, so they can't be partially
+	// received.
+	for idx := range events {
+		addrAmt := events[idx].Addr.Amount
+		if addrAmt < 2 {
+			continue
+		}
+
+		partialAmt := addrAmt / 2
+		err := addrBook.SetEventAssetAmt(ctx, events[idx], partialAmt)
+		require.NoError(t, err)
+
+		actual, err := addrBook.GetOrCreateEvent(
+			ctx, address.StatusTransactionConfirmed,
+			events[idx].Addr, txns[idx], events[idx].Outpoint.Index,
+		)
+		require.NoError(t, err)
+		require.EqualValues(t, partialAmt, actual.AssetAmt)
+		require.EqualValues(t, addrAmt-partialAmt, actual.Shortfall())
+	}
 }
 
 // TestAddressEventQuery tests that we're able to properly retrieve rows based
@@ -522,6 +589,7 @@ func TestAddressEventQuery(t *testing.T) {
 		name string
 
 		addrTaprootKey []byte
+		scriptKey      []byte
 		stateFrom      *address.Status
 		stateTo        *address.Status
 
@@ -579,6 +647,27 @@ func TestAddressEventQuery(t *testing.T) {
 			numAddrs: 1,
 			firstID:  5,
 		},
+
+		// Unknown script key.
+		{
+			name: "unknown script key",
+
+			scriptKey: schnorr.SerializePubKey(
+				test.RandPubKey(t),
+			),
+			numAddrs: 0,
+		},
+
+		// Correct script key, which is matched in its x-only form.
+		{
+			name: "correct script key",
+
+			scriptKey: schnorr.SerializePubKey(
+				&addrs[3].ScriptKey,
+			),
+			numAddrs: 1,
+			firstID:  4,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -586,6 +675,7 @@ func TestAddressEventQuery(t *testing.T) {
 			dbAddrs, err := addrBook.QueryAddrEvents(
 				ctx, address.EventQueryParams{
 					AddrTaprootOutputKey: test.addrTaprootKey,
+					ScriptKey:            test.scriptKey,
 					StatusFrom:           test.stateFrom,
 					StatusTo:             test.stateTo,
 				},
@@ -601,3 +691,513 @@ func TestAddressEventQuery(t *testing.T) {
 		})
 	}
 }
+
+// familyKeyRing is a key ring that hands out increasing key indexes per key
+// family.
+type familyKeyRing struct {
+	t *testing.T
+
+	nextIndexes map[keychain.KeyFamily]uint32
+
+	// foreignKey is a key that isn't considered local, if set.
+	foreignKey *btcec.PublicKey
+}
+
+// DeriveNextTaprootAssetKey derives the next key of the Taproot Asset key
+// family.
+func (f *familyKeyRing) DeriveNextTaprootAssetKey(
+	ctx context.Context) (keychain.KeyDescriptor, error) {
+
+	return f.DeriveNextKey(ctx, asset.TaprootAssetsKeyFamily)
+}
+
+// DeriveNextKey derives the next key of the given key family.
+func (f *familyKeyRing) DeriveNextKey(_ context.Context,
+	family keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	idx := f.nextIndexes[family]
+	f.nextIndexes[family] = idx + 1
+
+	return keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(f.t),
+		KeyLocator: keychain.KeyLocator{
+			Family: family,
+			Index:  idx,
+		},
+	}, nil
+}
+
+// IsLocalKey returns true for all keys apart from the foreign key.
+func (f *familyKeyRing) IsLocalKey(_ context.Context,
+	keyDesc keychain.KeyDescriptor) bool {
+
+	return f.foreignKey == nil || !keyDesc.PubKey.IsEqual(f.foreignKey)
+}
+
+// TestKeyRanges tests that key ranges can be reserved and that the usage of
+// each key family is reported correctly.
+func TestKeyRanges(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	addrBook, _ := newAddrBook(t, testClock)
+	ctx := context.Background()
+
+	book := address.NewBook(address.BookConfig{
+		Store:        addrBook,
+		StoreTimeout: DefaultStoreTimeout,
+		Chain:        *chainParams,
+		KeyRing: &familyKeyRing{
+			t: t,
+			nextIndexes: map[keychain.KeyFamily]uint32{
+				asset.TaprootAssetsKeyFamily: 3,
+			},
+		},
+	})
+
+	// We start out with a few keys the daemon already used. The same key
+	// stored twice only counts once.
+	tapKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: asset.TaprootAssetsKeyFamily,
+			Index:  5,
+		},
+	}
+	otherKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: 300,
+			Index:  2,
+		},
+	}
+	require.NoError(t, addrBook.InsertInternalKey(ctx, tapKey))
+	require.NoError(t, addrBook.InsertInternalKey(ctx, tapKey))
+	require.NoError(t, addrBook.InsertInternalKey(ctx, otherKey))
+
+	// Invalid reservations are rejected.
+	_, err := book.ReserveKeyRange(ctx, 400, 0, "channels")
+	require.ErrorContains(t, err, "at least one key")
+	_, err = book.ReserveKeyRange(ctx, 400, 1, "")
+	require.ErrorContains(t, err, "purpose")
+
+	firstRange, err := book.ReserveKeyRange(ctx, 400, 3, "channels")
+	require.NoError(t, err)
+	require.EqualValues(t, 400, firstRange.Family)
+	require.EqualValues(t, 0, firstRange.FirstIndex)
+	require.EqualValues(t, 2, firstRange.LastIndex)
+
+	secondRange, err := book.ReserveKeyRange(
+		ctx, asset.TaprootAssetsKeyFamily, 4, "external signer",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, secondRange.FirstIndex)
+	require.EqualValues(t, 6, secondRange.LastIndex)
+
+	dbRanges, err := addrBook.FetchKeyRanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbRanges, 2)
+	require.EqualValues(t, asset.TaprootAssetsKeyFamily, dbRanges[0].Family)
+	require.Equal(t, "external signer", dbRanges[0].Purpose)
+	require.Equal(t, "channels", dbRanges[1].Purpose)
+	require.Equal(
+		t, secondRange.CreationTime.Unix(),
+		dbRanges[0].CreationTime.Unix(),
+	)
+
+	usages, err := book.ListKeyRanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, usages, 3)
+
+	require.EqualValues(t, asset.TaprootAssetsKeyFamily, usages[0].Family)
+	require.EqualValues(t, 1, usages[0].UsedKeys)
+	require.EqualValues(t, 7, usages[0].NextIndex)
+	require.Len(t, usages[0].ReservedRanges, 1)
+
+	require.EqualValues(t, 300, usages[1].Family)
+	require.EqualValues(t, 1, usages[1].UsedKeys)
+	require.EqualValues(t, 3, usages[1].NextIndex)
+	require.Empty(t, usages[1].ReservedRanges)
+
+	require.EqualValues(t, 400, usages[2].Family)
+	require.EqualValues(t, 0, usages[2].UsedKeys)
+	require.EqualValues(t, 3, usages[2].NextIndex)
+	require.Len(t, usages[2].ReservedRanges, 1)
+
+	// Reserved ranges are part of the exported signing state, so they are
+	// never derived again after an import.
+	state, err := book.ExportSigningState(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, state.KeyIndexes[400])
+	require.EqualValues(
+		t, 6, state.KeyIndexes[asset.TaprootAssetsKeyFamily],
+	)
+}
+
+// TestImportSigningState tests that an imported signing state is only stored
+// if all of its keys are local and the wallet's key derivation isn't too far
+// behind, and that the key derivation is advanced past the imported keys.
+func TestImportSigningState(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	addrBook, _ := newAddrBook(t, testClock)
+	ctx := context.Background()
+
+	keyRing := &familyKeyRing{
+		t: t,
+		nextIndexes: map[keychain.KeyFamily]uint32{
+			asset.TaprootAssetsKeyFamily: 2,
+		},
+	}
+	book := address.NewBook(address.BookConfig{
+		Store:        addrBook,
+		StoreTimeout: DefaultStoreTimeout,
+		Chain:        *chainParams,
+		KeyRing:      keyRing,
+	})
+
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: 300,
+			Index:  12,
+		},
+	}
+	rawScriptKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: asset.TaprootAssetsKeyFamily,
+			Index:  4,
+		},
+	}
+	scriptKey := asset.NewScriptKeyBip86(rawScriptKey)
+	state := &address.SigningState{
+		InternalKeys: []keychain.KeyDescriptor{internalKey},
+		ScriptKeys:   []asset.ScriptKey{scriptKey},
+		KeyIndexes: map[keychain.KeyFamily]uint32{
+			300:                          9,
+			asset.TaprootAssetsKeyFamily: 7,
+		},
+	}
+
+	assertNumKeys := func(numInternal, numScript int) {
+		internalKeys, err := addrBook.FetchAllInternalKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, internalKeys, numInternal)
+
+		scriptKeys, err := addrBook.FetchAllScriptKeys(ctx)
+		require.NoError(t, err)
+		require.Len(t, scriptKeys, numScript)
+	}
+
+	// A signing state with a key the wallet can't derive is rejected
+	// before anything is imported.
+	keyRing.foreignKey = internalKey.PubKey
+	err := book.ImportSigningState(ctx, state)
+	require.ErrorIs(t, err, address.ErrNotLocalKey)
+	assertNumKeys(0, 0)
+	require.Empty(t, keyRing.nextIndexes[300])
+	keyRing.foreignKey = nil
+
+	// A signing state that is too far ahead of the wallet's key
+	// derivation is rejected as well.
+	tooFarAhead := *state
+	tooFarAhead.ScriptKeys = nil
+	tooFarAhead.KeyIndexes = map[keychain.KeyFamily]uint32{
+		300: address.MaxImportKeyIndexGap + 1,
+	}
+	err = book.ImportSigningState(ctx, &tooFarAhead)
+	require.ErrorContains(t, err, "keys ahead")
+	assertNumKeys(0, 0)
+
+	// A valid signing state is imported and the key derivation of each
+	// family is advanced past the highest imported key index. The key
+	// derived to learn the wallet's key index of family 300 above is
+	// skipped as well.
+	require.NoError(t, book.ImportSigningState(ctx, state))
+	assertNumKeys(2, 1)
+	require.EqualValues(t, 13, keyRing.nextIndexes[300])
+	require.EqualValues(
+		t, 8, keyRing.nextIndexes[asset.TaprootAssetsKeyFamily],
+	)
+
+	// Importing the same signing state again doesn't store any keys twice
+	// and only derives one key of each family.
+	require.NoError(t, book.ImportSigningState(ctx, state))
+	assertNumKeys(2, 1)
+	require.EqualValues(t, 14, keyRing.nextIndexes[300])
+	require.EqualValues(
+		t, 9, keyRing.nextIndexes[asset.TaprootAssetsKeyFamily],
+	)
+}
+
+// TestAddrIdempotencyKeys tests that an address created with an idempotency
+// key is returned again for the same key and parameters.
+func TestAddrIdempotencyKeys(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	addrBook, _ := newAddrBook(t, testClock)
+	ctx := context.Background()
+
+	book := address.NewBook(address.BookConfig{
+		Store:        addrBook,
+		StoreTimeout: DefaultStoreTimeout,
+		Chain:        *chainParams,
+		KeyRing: &familyKeyRing{
+			t:           t,
+			nextIndexes: make(map[keychain.KeyFamily]uint32),
+		},
+	})
+
+	proofCourierAddr := address.RandProofCourierAddr(t)
+	knownAddr, assetGen, assetGroup := randNormalAddr(t, proofCourierAddr)
+	var writeTxOpts AddrBookTxOptions
+	err := addrBook.db.ExecTx(
+		ctx, &writeTxOpts,
+		insertFullAssetGen(ctx, assetGen, assetGroup),
+	)
+	require.NoError(t, err)
+
+	params := address.NewAddrParams{
+		AssetID:          knownAddr.AssetID,
+		Amount:           1,
+		ProofCourierAddr: proofCourierAddr,
+	}
+	assertNumAddrs := func(num int) {
+		dbAddrs, err := addrBook.QueryAddrs(ctx, address.QueryParams{})
+		require.NoError(t, err)
+		require.Len(t, dbAddrs, num)
+	}
+
+	// An unknown key is reported as such.
+	_, _, err = addrBook.FetchAddrIdempotencyKey(ctx, "unknown")
+	require.ErrorIs(t, err, address.ErrNoAddr)
+
+	paramsHash := test.RandBytes(32)
+	firstAddr, err := book.IdempotentAddress(
+		ctx, "first", paramsHash, params,
+	)
+	require.NoError(t, err)
+	assertNumAddrs(1)
+
+	// Using the same key with the same parameters returns the address
+	// that was already created.
+	sameAddr, err := book.IdempotentAddress(
+		ctx, "first", paramsHash, params,
+	)
+	require.NoError(t, err)
+	assertNumAddrs(1)
+	assertEqualAddr(t, *firstAddr, *sameAddr)
+
+	// Using the same key with different parameters is rejected.
+	_, err = book.IdempotentAddress(
+		ctx, "first", test.RandBytes(32), params,
+	)
+	require.ErrorIs(t, err, address.ErrIdempotencyKeyReused)
+	assertNumAddrs(1)
+
+	// A different key results in a new address, even with the same
+	// parameters.
+	secondAddr, err := book.IdempotentAddress(
+		ctx, "second", paramsHash, params,
+	)
+	require.NoError(t, err)
+	assertNumAddrs(2)
+	require.NotEqual(
+		t, firstAddr.TaprootOutputKey.SerializeCompressed(),
+		secondAddr.TaprootOutputKey.SerializeCompressed(),
+	)
+
+	// The address and its key are inserted atomically. An address whose
+	// key is already taken isn't stored.
+	newAddr, _, _ := address.RandAddr(t, chainParams, proofCourierAddr)
+	newAddr.AssetID = knownAddr.AssetID
+	err = addrBook.InsertIdempotentAddr(
+		ctx, *newAddr, nil, "first", paramsHash,
+	)
+	require.Error(t, err)
+
+	_, err = addrBook.AddrByTaprootOutput(ctx, &newAddr.TaprootOutputKey)
+	require.ErrorIs(t, err, address.ErrNoAddr)
+
+	// And a key whose address can't be stored isn't stored either.
+	unknownAddr, _, _ := address.RandAddr(t, chainParams, proofCourierAddr)
+	err = addrBook.InsertIdempotentAddr(
+		ctx, *unknownAddr, nil, "third", paramsHash,
+	)
+	require.Error(t, err)
+
+	_, _, err = addrBook.FetchAddrIdempotencyKey(ctx, "third")
+	require.ErrorIs(t, err, address.ErrNoAddr)
+	assertNumAddrs(2)
+}
+
+// TestNewAddressBatch tests that a batch of addresses is created atomically.
+func TestNewAddressBatch(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	addrBook, _ := newAddrBook(t, testClock)
+	ctx := context.Background()
+
+	book := address.NewBook(address.BookConfig{
+		Store:        addrBook,
+		StoreTimeout: DefaultStoreTimeout,
+		Chain:        *chainParams,
+		KeyRing: &familyKeyRing{
+			t:           t,
+			nextIndexes: make(map[keychain.KeyFamily]uint32),
+		},
+	})
+
+	// We need an asset the addresses can receive. As the addresses request
+	// different amounts, it can't be a collectible.
+	proofCourierAddr := address.RandProofCourierAddr(t)
+	knownAddr, assetGen, assetGroup := randNormalAddr(t, proofCourierAddr)
+	var writeTxOpts AddrBookTxOptions
+	err := addrBook.db.ExecTx(
+		ctx, &writeTxOpts,
+		insertFullAssetGen(ctx, assetGen, assetGroup),
+	)
+	require.NoError(t, err)
+
+	params := []address.NewAddrParams{{
+		AssetID:          knownAddr.AssetID,
+		Amount:           1,
+		ProofCourierAddr: proofCourierAddr,
+	}, {
+		AssetID:          knownAddr.AssetID,
+		Amount:           2,
+		ProofCourierAddr: proofCourierAddr,
+	}}
+	addrs, err := book.NewAddressBatch(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, addrs, 2)
+	require.EqualValues(t, 1, addrs[0].Amount)
+	require.EqualValues(t, 2, addrs[1].Amount)
+
+	dbAddrs, err := addrBook.QueryAddrs(ctx, address.QueryParams{})
+	require.NoError(t, err)
+	require.Len(t, dbAddrs, 2)
+
+	// A batch with an address for an unknown asset is rejected as a
+	// whole.
+	params = append(params, address.NewAddrParams{
+		AssetID:          asset.RandID(t),
+		Amount:           3,
+		ProofCourierAddr: proofCourierAddr,
+	})
+	_, err = book.NewAddressBatch(ctx, params)
+	require.Error(t, err)
+
+	dbAddrs, err = addrBook.QueryAddrs(ctx, address.QueryParams{})
+	require.NoError(t, err)
+	require.Len(t, dbAddrs, 2)
+
+	// The same goes for a batch that fails while being inserted, in which
+	// case the whole database transaction is rolled back.
+	unknownAddr, _, _ := address.RandAddr(t, chainParams, proofCourierAddr)
+	newAddr, _, _ := address.RandAddr(t, chainParams, proofCourierAddr)
+	newAddr.AssetID = knownAddr.AssetID
+	err = addrBook.InsertAddrBatch(ctx, []address.AddrWithKeyInfo{
+		*newAddr, *unknownAddr,
+	}, nil)
+	require.Error(t, err)
+
+	_, err = addrBook.AddrByTaprootOutput(ctx, &newAddr.TaprootOutputKey)
+	require.ErrorIs(t, err, address.ErrNoAddr)
+}
+
+// TestAnyAmountAddress tests that addresses that allow any amount are stored
+// with their minimum amount and that the fixed amount address of a received
+// amount is only stored once.
+func TestAnyAmountAddress(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	addrBook, _ := newAddrBook(t, testClock)
+	ctx := context.Background()
+
+	book := address.NewBook(address.BookConfig{
+		Store:        addrBook,
+		StoreTimeout: DefaultStoreTimeout,
+		Chain:        *chainParams,
+		KeyRing: &familyKeyRing{
+			t:           t,
+			nextIndexes: make(map[keychain.KeyFamily]uint32),
+		},
+	})
+
+	proofCourierAddr := address.RandProofCourierAddr(t)
+	knownAddr, assetGen, assetGroup := randNormalAddr(t, proofCourierAddr)
+	var writeTxOpts AddrBookTxOptions
+	err := addrBook.db.ExecTx(
+		ctx, &writeTxOpts,
+		insertFullAssetGen(ctx, assetGen, assetGroup),
+	)
+	require.NoError(t, err)
+
+	anyAddr, err := book.NewAddress(
+		ctx, knownAddr.AssetID, 0, nil, proofCourierAddr,
+		address.WithAnyAmount(10),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, anyAddr.Amount)
+
+	dbAddr, err := addrBook.AddrByTaprootOutput(
+		ctx, &anyAddr.TaprootOutputKey,
+	)
+	require.NoError(t, err)
+	require.True(t, dbAddr.AllowAnyAmount)
+	require.EqualValues(t, 10, dbAddr.MinAmount)
+
+	// The suggested amount is tracked with the address itself.
+	sameAddr, err := book.AddrForAmount(ctx, dbAddr, 10)
+	require.NoError(t, err)
+	require.Equal(t, dbAddr.TaprootOutputKey, sameAddr.TaprootOutputKey)
+
+	_, err = book.AddrForAmount(ctx, dbAddr, 9)
+	require.ErrorIs(t, err, address.ErrAmountBelowMinimum)
+
+	// Any other amount is tracked with a fixed amount address with the
+	// same keys, which is stored only once.
+	amountAddr, err := book.AddrForAmount(ctx, dbAddr, 25)
+	require.NoError(t, err)
+	require.EqualValues(t, 25, amountAddr.Amount)
+	require.False(t, amountAddr.AllowAnyAmount)
+	require.Equal(t, dbAddr.ScriptKey, amountAddr.ScriptKey)
+	require.NotEqual(
+		t, dbAddr.TaprootOutputKey, amountAddr.TaprootOutputKey,
+	)
+
+	knownAmountAddr, err := book.AddrForAmount(ctx, dbAddr, 25)
+	require.NoError(t, err)
+	require.Equal(
+		t, amountAddr.TaprootOutputKey,
+		knownAmountAddr.TaprootOutputKey,
+	)
+
+	dbAddrs, err := addrBook.QueryAddrs(ctx, address.QueryParams{})
+	require.NoError(t, err)
+	require.Len(t, dbAddrs, 2)
+
+	_, err = book.AddrForAmount(ctx, amountAddr, 30)
+	require.Error(t, err)
+}
+
+// randNormalAddr creates a random address for an asset of the normal type, so
+// it can request any non-zero amount.
+func randNormalAddr(t *testing.T, proofCourierAddr url.URL) (
+	*address.AddrWithKeyInfo, *asset.Genesis, *asset.GroupKey) {
+
+	for {
+		addr, assetGen, assetGroup := address.RandAddr(
+			t, chainParams, proofCourierAddr,
+		)
+		if assetGen.Type == asset.Normal {
+			return addr, assetGen, assetGroup
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/stretchr/testify/require"
 )
@@ -70,3 +71,84 @@ func TestAssetMetaUpsert(t *testing.T) {
 
 	require.Equal(t, zeroMetaID, zeroMetaID2)
 }
+
+// TestAssetMetaSupplyCap tests that the supply cap of a meta reveal is stored
+// and returned, so the meta hash can be re-created from disk.
+func TestAssetMetaSupplyCap(t *testing.T) {
+	t.Parallel()
+
+	_, assetStore, db := newAssetStore(t)
+
+	assetMeta := &proof.MetaReveal{
+		Type:      proof.MetaOpaque,
+		Data:      []byte("capped"),
+		SupplyCap: 21_000_000,
+	}
+
+	ctx := context.Background()
+	_, err := maybeUpsertAssetMeta(ctx, db, nil, assetMeta)
+	require.NoError(t, err)
+
+	fetchedMeta, err := assetStore.FetchAssetMetaByHash(
+		ctx, assetMeta.MetaHash(),
+	)
+	require.NoError(t, err)
+	require.Equal(t, assetMeta, fetchedMeta)
+	require.Equal(t, assetMeta.MetaHash(), fetchedMeta.MetaHash())
+}
+
+// TestFetchGenesesWithMeta tests that the geneses of all assets that reveal
+// their meta data are returned together with the meta data.
+func TestFetchGenesesWithMeta(t *testing.T) {
+	t.Parallel()
+
+	_, assetStore, db := newAssetStore(t)
+	ctx := context.Background()
+
+	// We'll insert two assets that reveal their meta data and one that
+	// only commits to the meta hash.
+	insertGenesis := func(meta *proof.MetaReveal,
+		reveal bool) asset.Genesis {
+
+		gen := asset.RandGenesis(t, asset.Normal)
+		gen.MetaHash = meta.MetaHash()
+
+		if reveal {
+			_, err := maybeUpsertAssetMeta(ctx, db, &gen, meta)
+			require.NoError(t, err)
+		}
+
+		genesisPointID, err := upsertGenesisPoint(
+			ctx, db, gen.FirstPrevOut,
+		)
+		require.NoError(t, err)
+
+		_, err = upsertGenesis(ctx, db, genesisPointID, gen)
+		require.NoError(t, err)
+
+		return gen
+	}
+
+	jsonMeta := &proof.MetaReveal{
+		Type: proof.MetaOpaque,
+		Data: []byte(`{"name": "pixel"}`),
+	}
+	cappedMeta := &proof.MetaReveal{
+		Type:      proof.MetaOpaque,
+		Data:      []byte("capped"),
+		SupplyCap: 1000,
+	}
+	jsonGen := insertGenesis(jsonMeta, true)
+	cappedGen := insertGenesis(cappedMeta, true)
+	insertGenesis(&proof.MetaReveal{Data: []byte("hidden")}, false)
+
+	geneses, err := assetStore.FetchGenesesWithMeta(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []AssetGenesisMeta{{
+		Genesis: jsonGen,
+		Meta:    *jsonMeta,
+	}, {
+		Genesis: cappedGen,
+		Meta:    *cappedMeta,
+	}}, geneses)
+}
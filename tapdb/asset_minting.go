@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil/psbt"
@@ -25,6 +26,10 @@ type (
 	// BatchStateUpdate holds the arguments to update the state of a batch.
 	BatchStateUpdate = sqlc.UpdateMintingBatchStateParams
 
+	// BatchFinalizeSchedule holds the arguments to update the finalization
+	// schedule of a batch.
+	BatchFinalizeSchedule = sqlc.UpdateMintingBatchFinalizeScheduleParams
+
 	// InternalKey holds the arguments to update an internal key.
 	InternalKey = sqlc.UpsertInternalKeyParams
 
@@ -92,6 +97,10 @@ type (
 	// ChainTxConf is used to mark a chain tx as being confirmed.
 	ChainTxConf = sqlc.ConfirmChainTxParams
 
+	// BatchAssetsAcquiredAt is used to set the acquisition time of the
+	// assets minted in a batch.
+	BatchAssetsAcquiredAt = sqlc.SetBatchAssetsAcquiredAtParams
+
 	// GenesisAsset is used to insert the base information of an asset into
 	// the DB.
 	GenesisAsset = sqlc.UpsertGenesisAssetParams
@@ -116,6 +125,23 @@ type (
 	// NewAssetMeta wraps the params needed to insert a new asset meta on
 	// disk.
 	NewAssetMeta = sqlc.UpsertAssetMetaParams
+
+	// NewGroupKeyRotation wraps the params needed to insert a rotation of
+	// the issuance authority of an asset group on disk.
+	NewGroupKeyRotation = sqlc.InsertGroupKeyRotationParams
+
+	// GroupKeyRotationsFrom wraps the params needed to delete the
+	// rotations of an asset group starting at a sequence.
+	GroupKeyRotationsFrom = sqlc.DeleteGroupKeyRotationsParams
+
+	// GroupKeyRotationRow is a rotation of the issuance authority of an
+	// asset group, along with the key locator of the new key if it's a
+	// local key.
+	GroupKeyRotationRow = sqlc.FetchGroupKeyRotationsRow
+
+	// NewTapscriptLeaf wraps the params needed to insert a leaf of a
+	// tapscript tree a script key commits to on disk.
+	NewTapscriptLeaf = sqlc.UpsertTapscriptLeafParams
 )
 
 // PendingAssetStore is a sub-set of the main sqlc.Querier interface that
@@ -137,6 +163,11 @@ type PendingAssetStore interface {
 	UpdateMintingBatchState(ctx context.Context,
 		arg BatchStateUpdate) error
 
+	// UpdateMintingBatchFinalizeSchedule updates the finalization
+	// schedule of an existing minting batch.
+	UpdateMintingBatchFinalizeSchedule(ctx context.Context,
+		arg BatchFinalizeSchedule) error
+
 	// InsertAssetSeedling inserts a new asset seedling (base description)
 	// into the database.
 	InsertAssetSeedling(ctx context.Context, arg AssetSeedlingShell) error
@@ -197,6 +228,11 @@ type PendingAssetStore interface {
 	// ConfirmChainTx confirms an existing chain tx.
 	ConfirmChainTx(ctx context.Context, arg ChainTxConf) error
 
+	// SetBatchAssetsAcquiredAt sets the acquisition time of all assets
+	// minted in a batch.
+	SetBatchAssetsAcquiredAt(ctx context.Context,
+		arg BatchAssetsAcquiredAt) error
+
 	// FetchAssetsForBatch fetches all the assets created by a particular
 	// batch.
 	FetchAssetsForBatch(ctx context.Context, rawKey []byte) ([]AssetSprout,
@@ -324,6 +360,20 @@ func (a *AssetMintingStore) CommitMintingBatch(ctx context.Context,
 				EmissionEnabled: seedling.EnableEmission,
 			}
 
+			// If the script key of the asset commits to a tapscript
+			// tree, we store its leaves so the asset can be spent
+			// through them.
+			if seedling.ScriptKeyTapscript != nil {
+				root, err := upsertTapscriptTree(
+					ctx, q, seedling.ScriptKeyTapscript,
+				)
+				if err != nil {
+					return err
+				}
+
+				dbSeedling.ScriptKeyTapscriptRoot = root
+			}
+
 			// If this seedling is being issued to an existing
 			// group, we need to reference the genesis that
 			// was first used to create the group.
@@ -398,6 +448,20 @@ func (a *AssetMintingStore) AddSeedlingsToBatch(ctx context.Context,
 				EmissionEnabled: seedling.EnableEmission,
 			}
 
+			// If the script key of the asset commits to a tapscript
+			// tree, we store its leaves so the asset can be spent
+			// through them.
+			if seedling.ScriptKeyTapscript != nil {
+				root, err := upsertTapscriptTree(
+					ctx, q, seedling.ScriptKeyTapscript,
+				)
+				if err != nil {
+					return err
+				}
+
+				dbSeedling.ScriptKeyTapscriptRoot = root
+			}
+
 			// If this seedling is being issued to an existing
 			// group, we need to reference the genesis that
 			// was first used to create the group.
@@ -510,12 +574,23 @@ func fetchAssetSeedlings(ctx context.Context, q PendingAssetStore,
 			seedling.GroupAnchor = &seedlingAnchor.AssetName
 		}
 
-		if len(dbSeedling.MetaDataBlob) != 0 {
+		if len(dbSeedling.ScriptKeyTapscriptRoot) != 0 {
+			seedling.ScriptKeyTapscript, err = fetchTapscriptTree(
+				ctx, q, dbSeedling.ScriptKeyTapscriptRoot,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		supplyCap := extractSqlInt64[uint64](dbSeedling.MetaSupplyCap)
+		if len(dbSeedling.MetaDataBlob) != 0 || supplyCap != 0 {
 			seedling.Meta = &proof.MetaReveal{
 				Data: dbSeedling.MetaDataBlob,
 				Type: proof.MetaType(
 					dbSeedling.MetaDataType.Int16,
 				),
+				SupplyCap: supplyCap,
 			}
 		}
 
@@ -559,6 +634,20 @@ func fetchAssetSprouts(ctx context.Context, q PendingAssetStore,
 			},
 		}
 
+		// A script key with a tweak commits to the tapscript tree of
+		// its seedling, all other script keys are BIP-0086 keys.
+		tweakedScriptKey := asset.NewScriptKeyBip86(scriptKey)
+		if len(sprout.Tweak) != 0 {
+			root, err := chainhash.NewHash(sprout.Tweak)
+			if err != nil {
+				return nil, fmt.Errorf("invalid script key "+
+					"tweak: %w", err)
+			}
+
+			tree := &asset.TapscriptTree{RootHash: root}
+			tweakedScriptKey = tree.ScriptKey(scriptKey)
+		}
+
 		// Not all assets have a key group, so we only need to
 		// populate this information for those that signalled the
 		// requirement of on going emission.
@@ -638,7 +727,7 @@ func fetchAssetSprouts(ctx context.Context, q PendingAssetStore,
 
 		assetSprout, err := asset.New(
 			assetGenesis, amount, lockTime, relativeLocktime,
-			asset.NewScriptKeyBip86(scriptKey), groupKey,
+			tweakedScriptKey, groupKey,
 			asset.WithAssetVersion(asset.Version(sprout.Version)),
 		)
 		if err != nil {
@@ -685,6 +774,9 @@ func fetchAssetMetas(ctx context.Context, db PendingAssetStore,
 		assetMetas[scriptKey] = &proof.MetaReveal{
 			Data: assetMeta.MetaDataBlob,
 			Type: proof.MetaType(assetMeta.MetaDataType.Int16),
+			SupplyCap: extractSqlInt64[uint64](
+				assetMeta.MetaSupplyCap,
+			),
 		}
 	}
 
@@ -845,6 +937,22 @@ func marshalMintingBatch(ctx context.Context, q PendingAssetStore,
 
 	batch.UpdateState(batchState)
 
+	// Only the pending batch can still be finalized according to its
+	// schedule.
+	hasSchedule := dbBatch.FinalizeTimeUnix.Valid ||
+		dbBatch.FinalizeMinSeedlings.Valid
+	if batchState == tapgarden.BatchStatePending && hasSchedule {
+		batch.FinalizeSchedule = &tapgarden.FinalizeSchedule{
+			MinSeedlings: extractSqlInt32[int](
+				dbBatch.FinalizeMinSeedlings,
+			),
+		}
+		if dbBatch.FinalizeTimeUnix.Valid {
+			batch.FinalizeSchedule.FinalizeTime =
+				dbBatch.FinalizeTimeUnix.Time.UTC()
+		}
+	}
+
 	if dbBatch.MintingTxPsbt != nil {
 		genesisPkt, err := psbt.NewFromRawBytes(
 			bytes.NewReader(dbBatch.MintingTxPsbt), false,
@@ -912,6 +1020,30 @@ func (a *AssetMintingStore) UpdateBatchState(ctx context.Context,
 	})
 }
 
+// UpdateBatchFinalizeSchedule updates the finalization schedule of a batch
+// based on the batch key. A nil schedule removes the schedule of the batch.
+func (a *AssetMintingStore) UpdateBatchFinalizeSchedule(ctx context.Context,
+	batchKey *btcec.PublicKey,
+	schedule *tapgarden.FinalizeSchedule) error {
+
+	update := BatchFinalizeSchedule{
+		RawKey: batchKey.SerializeCompressed(),
+	}
+	if schedule != nil {
+		update.FinalizeTimeUnix = sqlTime(schedule.FinalizeTime)
+		if schedule.MinSeedlings > 0 {
+			update.FinalizeMinSeedlings = sqlInt32(
+				schedule.MinSeedlings,
+			)
+		}
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		return q.UpdateMintingBatchFinalizeSchedule(ctx, update)
+	})
+}
+
 // encodeOutpoint encodes the outpoint point in Bitcoin wire format, returning
 // the final result.
 func encodeOutpoint(outPoint wire.OutPoint) ([]byte, error) {
@@ -1116,7 +1248,7 @@ func (a *AssetMintingStore) CommitSignedGenesisTx(ctx context.Context,
 // disk.
 func (a *AssetMintingStore) MarkBatchConfirmed(ctx context.Context,
 	batchKey *btcec.PublicKey, blockHash *chainhash.Hash,
-	blockHeight uint32, txIndex uint32,
+	blockHeight uint32, txIndex uint32, blockTime time.Time,
 	mintingProofs proof.AssetBlobs) error {
 
 	rawBatchKey := batchKey.SerializeCompressed()
@@ -1144,6 +1276,17 @@ func (a *AssetMintingStore) MarkBatchConfirmed(ctx context.Context,
 			return fmt.Errorf("unable to confirm chain tx: %w", err)
 		}
 
+		// The assets of the batch are acquired with the block that
+		// confirmed the genesis transaction.
+		err = q.SetBatchAssetsAcquiredAt(ctx, BatchAssetsAcquiredAt{
+			RawKey:     rawBatchKey,
+			AcquiredAt: sqlTime(blockTime),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set asset acquisition "+
+				"time: %w", err)
+		}
+
 		// As a final act, we'll now insert the proof files for each of
 		// the assets that were fully confirmed with this block.
 		for scriptKey, proofBlob := range mintingProofs {
@@ -1206,6 +1349,102 @@ func (a *AssetMintingStore) FetchGroupByGroupKey(ctx context.Context,
 	return dbGroup, nil
 }
 
+// FetchGroupAuthority fetches the asset group with a matching tweaked key,
+// along with the current issuance authority of the group.
+func (a *AssetMintingStore) FetchGroupAuthority(ctx context.Context,
+	groupKey *btcec.PublicKey) (*tapgarden.GroupAuthority, error) {
+
+	var authority tapgarden.GroupAuthority
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q PendingAssetStore) error {
+		group, err := fetchGroupByGroupKey(ctx, q, groupKey)
+		if err != nil {
+			return err
+		}
+		authority.Group = group
+
+		rotations, lastKey, err := fetchGroupKeyRotations(
+			ctx, q, groupKey,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to fetch group key "+
+				"rotations: %w", err)
+		}
+		authority.Rotations = rotations
+
+		// Without any rotations, the raw group key still holds the
+		// issuance authority.
+		authority.AuthorityKey = group.RawKey
+		if len(rotations) > 0 {
+			authority.AuthorityKey = lastKey
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return &authority, nil
+}
+
+// AddGroupKeyRotation adds a confirmed rotation of the issuance authority of
+// an asset group, which hands the authority to the given local key. An error
+// is returned if the rotation doesn't continue the known rotation chain of the
+// group, or if a competing rotation that was confirmed earlier is known.
+func (a *AssetMintingStore) AddGroupKeyRotation(ctx context.Context,
+	rotation *proof.GroupKeyRotation, newKey keychain.KeyDescriptor) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q PendingAssetStore) error {
+		newKeyID, err := q.UpsertInternalKey(ctx, InternalKey{
+			RawKey:    newKey.PubKey.SerializeCompressed(),
+			KeyFamily: int32(newKey.Family),
+			KeyIndex:  int32(newKey.Index),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to insert internal key: %w",
+				err)
+		}
+
+		inserted, err := insertGroupKeyRotation(
+			ctx, q, rotation, sqlInt64(newKeyID),
+		)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return fmt.Errorf("rotation %d of group %x isn't "+
+				"part of the rotation chain of the group",
+				rotation.Sequence,
+				rotation.GroupKey.SerializeCompressed())
+		}
+
+		return nil
+	})
+}
+
+// GroupKeyRotations returns the known rotations of the issuance authority of
+// the asset group with the given tweaked key, ordered by their sequence.
+func (a *AssetMintingStore) GroupKeyRotations(ctx context.Context,
+	groupKey *btcec.PublicKey) ([]*proof.GroupKeyRotation, error) {
+
+	var rotations []*proof.GroupKeyRotation
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q PendingAssetStore) error {
+		var err error
+		rotations, _, err = fetchGroupKeyRotations(ctx, q, groupKey)
+		return err
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return rotations, nil
+}
+
 // A compile-time assertion to ensure that AssetMintingStore meets the
 // tapgarden.MintingStore interface.
 var _ tapgarden.MintingStore = (*AssetMintingStore)(nil)
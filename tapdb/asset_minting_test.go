@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -727,9 +728,10 @@ func TestCommitBatchChainActions(t *testing.T) {
 	fakeBlockHash := chainhash.Hash(sha256.Sum256([]byte("fake")))
 	blockHeight := uint32(20)
 	txIndex := uint32(5)
+	blockTime := time.Unix(1_700_000_000, 0).UTC()
 	require.NoError(t, assetStore.MarkBatchConfirmed(
 		ctx, randAssetCtx.batchKey, &fakeBlockHash, blockHeight,
-		txIndex, assetProofs,
+		txIndex, blockTime, assetProofs,
 	))
 
 	// We'll now fetch the chain transaction again, to confirm that all the
@@ -750,6 +752,28 @@ func TestCommitBatchChainActions(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, numSeedlings, len(assets))
 
+	// The minted assets were acquired with the block that confirmed the
+	// batch, so they're only returned by a matching acquisition filter.
+	for _, a := range assets {
+		require.Equal(t, blockTime, a.AcquiredAt)
+	}
+	acquiredAssets, err := confAssets.FetchAllAssets(
+		ctx, false, false, &AssetQueryFilters{
+			MinAcquiredAt: blockTime,
+			MaxAcquiredAt: blockTime.Add(time.Second),
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, acquiredAssets, numSeedlings)
+
+	acquiredAssets, err = confAssets.FetchAllAssets(
+		ctx, false, false, &AssetQueryFilters{
+			MinAcquiredAt: blockTime.Add(time.Second),
+		},
+	)
+	require.NoError(t, err)
+	require.Empty(t, acquiredAssets)
+
 	// Count the number of assets with a group key. Each grouped asset
 	// should have a grouped genesis witness.
 	groupCount := fn.Count(assets, func(a *ChainAsset) bool {
@@ -821,6 +845,14 @@ func TestCommitBatchChainActions(t *testing.T) {
 		require.True(t, ok)
 
 		require.Equal(t, newAsset.Amount, assetBalance.Balance)
+
+		if newAsset.GroupKey == nil {
+			require.Nil(t, assetBalance.GroupKey)
+			continue
+		}
+		require.True(t, newAsset.GroupKey.GroupPubKey.IsEqual(
+			assetBalance.GroupKey,
+		))
 	}
 
 	// We'll also now ensure that if we group by key group, then we're
@@ -849,6 +881,18 @@ func TestCommitBatchChainActions(t *testing.T) {
 		assetBalance, ok := assetBalancesByGroup[groupKey]
 		require.True(t, ok)
 
+		// The group balance is broken down by the asset IDs of the
+		// group, which must add up to the group's total.
+		idBalance, ok := assetBalance.AssetBalances[newAsset.ID()]
+		require.True(t, ok)
+		require.Equal(t, newAsset.Amount, idBalance.Balance)
+
+		var totalBalance uint64
+		for _, idBalance := range assetBalance.AssetBalances {
+			totalBalance += idBalance.Balance
+		}
+		require.Equal(t, assetBalance.Balance, totalBalance)
+
 		// One asset was minted into an existing group, so the value
 		// of the group genesis asset must be deducted from the group
 		// balance before comparing to the minted asset.
@@ -1145,3 +1189,298 @@ func init() {
 	logWriter.RegisterSubLogger(Subsystem, logger)
 	UseLogger(logger)
 }
+
+// randGroupKeyRotation returns a rotation of the issuance authority of the
+// given group that hands the authority from the previous to the new key.
+func randGroupKeyRotation(t *testing.T, groupKey *btcec.PublicKey,
+	prevKey *btcec.PrivateKey, newKey *btcec.PublicKey,
+	sequence uint32) *proof.GroupKeyRotation {
+
+	msg := proof.RotationMessage(
+		groupKey, prevKey.PubKey(), newKey, sequence,
+	)
+	digest := sha256.Sum256(msg)
+	sig, err := schnorr.Sign(prevKey, digest[:])
+	require.NoError(t, err)
+
+	script, err := proof.RotationScript(
+		groupKey, prevKey.PubKey(), newKey, sequence,
+	)
+	require.NoError(t, err)
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+	anchorTx.AddTxOut(&wire.TxOut{PkScript: script})
+
+	txMerkleProof, err := proof.NewTxMerkleProof(
+		[]*wire.MsgTx{anchorTx}, 0,
+	)
+	require.NoError(t, err)
+
+	return &proof.GroupKeyRotation{
+		GroupKey:      groupKey,
+		Sequence:      sequence,
+		PrevKey:       prevKey.PubKey(),
+		NewKey:        newKey,
+		Signature:     sig,
+		AnchorTx:      *anchorTx,
+		TxMerkleProof: *txMerkleProof,
+		BlockHeight:   100 + sequence,
+	}
+}
+
+// TestGroupKeyRotations tests that rotations of the issuance authority of an
+// asset group can be stored, and that the authority key follows the latest
+// rotation.
+func TestGroupKeyRotations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	privDesc, groupPriv := randKeyDesc(t)
+	gen := asset.RandGenesis(t, asset.Normal)
+	_, _, group := storeGroupGenesis(
+		t, ctx, gen, nil, assetStore, privDesc, groupPriv,
+	)
+	groupKey := &group.GroupKey.GroupPubKey
+
+	// Without any rotations, the raw group key holds the issuance
+	// authority.
+	authority, err := assetStore.FetchGroupAuthority(ctx, groupKey)
+	require.NoError(t, err)
+	require.Empty(t, authority.Rotations)
+	require.Equal(t, privDesc, authority.AuthorityKey)
+
+	// We'll now rotate the authority twice, each time to a new local key.
+	newDesc1, newPriv1 := randKeyDesc(t)
+	rotation1 := randGroupKeyRotation(
+		t, groupKey, groupPriv, newDesc1.PubKey, 0,
+	)
+	require.NoError(t, assetStore.AddGroupKeyRotation(
+		ctx, rotation1, newDesc1,
+	))
+
+	newDesc2, _ := randKeyDesc(t)
+	rotation2 := randGroupKeyRotation(
+		t, groupKey, newPriv1, newDesc2.PubKey, 1,
+	)
+	require.NoError(t, assetStore.AddGroupKeyRotation(
+		ctx, rotation2, newDesc2,
+	))
+
+	// Adding the same rotation again is a no-op.
+	require.NoError(t, assetStore.AddGroupKeyRotation(
+		ctx, rotation2, newDesc2,
+	))
+
+	// The authority key should now be the key of the latest rotation.
+	authority, err = assetStore.FetchGroupAuthority(ctx, groupKey)
+	require.NoError(t, err)
+	require.Len(t, authority.Rotations, 2)
+	require.Equal(t, newDesc2, authority.AuthorityKey)
+
+	// The rotations should be returned in sequence order, and be identical
+	// to the ones we stored.
+	rotations, err := assetStore.GroupKeyRotations(ctx, groupKey)
+	require.NoError(t, err)
+	require.Len(t, rotations, 2)
+	for i, expected := range []*proof.GroupKeyRotation{
+		rotation1, rotation2,
+	} {
+		var expectedBuf, rotationBuf bytes.Buffer
+		require.NoError(t, expected.Encode(&expectedBuf))
+		require.NoError(t, rotations[i].Encode(&rotationBuf))
+		require.Equal(t, expectedBuf.Bytes(), rotationBuf.Bytes())
+	}
+
+	// A group without any rotations returns an empty set.
+	rotations, err = assetStore.GroupKeyRotations(
+		ctx, test.RandPubKey(t),
+	)
+	require.NoError(t, err)
+	require.Empty(t, rotations)
+}
+
+// TestCompetingGroupKeyRotations tests that competing rotations of the
+// issuance authority of an asset group are resolved by their confirmation
+// height, regardless of the order in which they are learned.
+func TestCompetingGroupKeyRotations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	privDesc, groupPriv := randKeyDesc(t)
+	gen := asset.RandGenesis(t, asset.Normal)
+	_, _, group := storeGroupGenesis(
+		t, ctx, gen, nil, assetStore, privDesc, groupPriv,
+	)
+	groupKey := &group.GroupKey.GroupPubKey
+
+	// The raw group key hands the authority to two different keys. We
+	// first learn about the rotation that was confirmed later, and about
+	// a rotation that continues it.
+	lateDesc, latePriv := randKeyDesc(t)
+	late := randGroupKeyRotation(t, groupKey, groupPriv, lateDesc.PubKey, 0)
+	late.BlockHeight = 200
+	require.NoError(t, assetStore.AddGroupKeyRotation(ctx, late, lateDesc))
+
+	nextDesc, _ := randKeyDesc(t)
+	next := randGroupKeyRotation(t, groupKey, latePriv, nextDesc.PubKey, 1)
+	next.BlockHeight = 210
+	require.NoError(t, assetStore.AddGroupKeyRotation(ctx, next, nextDesc))
+
+	// A rotation that isn't signed by the current authority key doesn't
+	// continue the chain.
+	strayDesc, _ := randKeyDesc(t)
+	stray := randGroupKeyRotation(
+		t, groupKey, test.RandPrivKey(t), strayDesc.PubKey, 2,
+	)
+	err := assetStore.AddGroupKeyRotation(ctx, stray, strayDesc)
+	require.ErrorContains(t, err, "isn't part of the rotation chain")
+
+	// The rotation that was confirmed first wins, which removes the
+	// competing rotation and the one that continued it.
+	earlyDesc, _ := randKeyDesc(t)
+	early := randGroupKeyRotation(
+		t, groupKey, groupPriv, earlyDesc.PubKey, 0,
+	)
+	early.BlockHeight = 150
+	require.NoError(t, assetStore.AddGroupKeyRotation(
+		ctx, early, earlyDesc,
+	))
+
+	authority, err := assetStore.FetchGroupAuthority(ctx, groupKey)
+	require.NoError(t, err)
+	require.Len(t, authority.Rotations, 1)
+	require.Equal(t, earlyDesc, authority.AuthorityKey)
+
+	// Learning about the losing rotation again doesn't change anything.
+	err = assetStore.AddGroupKeyRotation(ctx, late, lateDesc)
+	require.ErrorContains(t, err, "isn't part of the rotation chain")
+
+	rotations, err := assetStore.GroupKeyRotations(ctx, groupKey)
+	require.NoError(t, err)
+	require.Len(t, rotations, 1)
+	require.Equal(
+		t, early.AnchorTx.TxHash(), rotations[0].AnchorTx.TxHash(),
+	)
+}
+
+// TestSeedlingScriptKeyTapscript tests that the tapscript tree the script key
+// of a seedling commits to is stored with the seedling, and that its leaves can
+// be fetched by the root hash of the tree.
+func TestSeedlingScriptKeyTapscript(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	// We give one seedling a tree with known leaves and another one a tree
+	// that is only known by its root hash.
+	mintingBatch := tapgarden.RandSeedlingMintingBatch(t, 2)
+	seedlings := maps.Values(mintingBatch.Seedlings)
+
+	leafTree := asset.NewTapscriptTreeFromLeaves([][]byte{
+		test.RandBytes(32), test.RandBytes(33),
+	})
+	seedlings[0].ScriptKeyTapscript = leafTree
+
+	rootHash := test.RandHash()
+	seedlings[1].ScriptKeyTapscript = &asset.TapscriptTree{
+		RootHash: &rootHash,
+	}
+
+	err := assetStore.CommitMintingBatch(ctx, mintingBatch)
+	require.NoError(t, err)
+
+	dbBatch, err := assetStore.FetchMintingBatch(
+		ctx, mintingBatch.BatchKey.PubKey,
+	)
+	require.NoError(t, err)
+	assertBatchEqual(t, mintingBatch, dbBatch)
+
+	// The leaves of the tree are also available through the address book,
+	// so the wallet can spend through them later on.
+	addrBook, _ := newAddrBook(t, clock.NewDefaultClock())
+	require.NoError(t, addrBook.InsertTapscriptTree(ctx, leafTree))
+
+	root := leafTree.Root()
+	leaves, err := addrBook.FetchTapscriptLeaves(ctx, root[:])
+	require.NoError(t, err)
+	require.Equal(t, leafTree.Leaves, leaves)
+
+	// Inserting the same tree again is a no-op.
+	require.NoError(t, addrBook.InsertTapscriptTree(ctx, leafTree))
+	leaves, err = addrBook.FetchTapscriptLeaves(ctx, root[:])
+	require.NoError(t, err)
+	require.Equal(t, leafTree.Leaves, leaves)
+
+	// An unknown tree has no leaves.
+	leaves, err = addrBook.FetchTapscriptLeaves(ctx, rootHash[:])
+	require.NoError(t, err)
+	require.Empty(t, leaves)
+}
+
+// TestBatchFinalizeSchedule tests that the finalization schedule of a pending
+// batch is stored with the batch and can be removed again.
+func TestBatchFinalizeSchedule(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assetStore, _, _ := newAssetStore(t)
+
+	mintingBatch := tapgarden.RandSeedlingMintingBatch(t, 2)
+	err := assetStore.CommitMintingBatch(ctx, mintingBatch)
+	require.NoError(t, err)
+
+	batchKey := mintingBatch.BatchKey.PubKey
+	fetchSchedule := func() *tapgarden.FinalizeSchedule {
+		dbBatch, err := assetStore.FetchMintingBatch(ctx, batchKey)
+		require.NoError(t, err)
+
+		return dbBatch.FinalizeSchedule
+	}
+
+	// A new batch isn't scheduled to be finalized.
+	require.Nil(t, fetchSchedule())
+
+	// Schedules based on time, on the number of seedlings or on both are
+	// stored as given.
+	finalizeTime := time.Unix(time.Now().Unix()+3600, 0).UTC()
+	schedules := []*tapgarden.FinalizeSchedule{{
+		FinalizeTime: finalizeTime,
+	}, {
+		MinSeedlings: 3,
+	}, {
+		FinalizeTime: finalizeTime,
+		MinSeedlings: 3,
+	}}
+	for _, schedule := range schedules {
+		err := assetStore.UpdateBatchFinalizeSchedule(
+			ctx, batchKey, schedule,
+		)
+		require.NoError(t, err)
+		require.Equal(t, schedule, fetchSchedule())
+	}
+
+	// The schedule only applies to the pending batch, so it isn't
+	// reported once the batch was frozen.
+	err = assetStore.UpdateBatchState(
+		ctx, batchKey, tapgarden.BatchStateFrozen,
+	)
+	require.NoError(t, err)
+	require.Nil(t, fetchSchedule())
+
+	// A nil schedule removes the schedule of the batch.
+	err = assetStore.UpdateBatchState(
+		ctx, batchKey, tapgarden.BatchStatePending,
+	)
+	require.NoError(t, err)
+	require.Equal(t, schedules[2], fetchSchedule())
+
+	err = assetStore.UpdateBatchFinalizeSchedule(ctx, batchKey, nil)
+	require.NoError(t, err)
+	require.Nil(t, fetchSchedule())
+}
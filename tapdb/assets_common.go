@@ -9,6 +9,8 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/proof"
@@ -77,6 +79,30 @@ type UpsertAssetStore interface {
 	// updated asset's database ID is returned.
 	SetAssetSpent(ctx context.Context, arg SetAssetSpentParams) (int64,
 		error)
+
+	// InsertGroupKeyRotation inserts a rotation of the issuance authority
+	// of an asset group.
+	InsertGroupKeyRotation(ctx context.Context,
+		arg NewGroupKeyRotation) error
+
+	// DeleteGroupKeyRotations deletes the rotations of the issuance
+	// authority of an asset group starting at the given sequence.
+	DeleteGroupKeyRotations(ctx context.Context,
+		arg GroupKeyRotationsFrom) error
+
+	// FetchGroupKeyRotations fetches the rotations of the issuance
+	// authority of an asset group, ordered by their sequence.
+	FetchGroupKeyRotations(ctx context.Context,
+		groupKey []byte) ([]GroupKeyRotationRow, error)
+
+	// UpsertTapscriptLeaf inserts a leaf of a tapscript tree, unless the
+	// leaf is already known.
+	UpsertTapscriptLeaf(ctx context.Context, arg NewTapscriptLeaf) error
+
+	// FetchTapscriptLeaves fetches the leaves of the tapscript tree with
+	// the given root hash, in the order they're assembled into the tree.
+	FetchTapscriptLeaves(ctx context.Context,
+		rootHash []byte) ([]sqlc.FetchTapscriptLeavesRow, error)
 }
 
 // upsertGenesis imports a new genesis point into the database or returns the
@@ -535,9 +561,10 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 	assetGen *asset.Genesis, metaReveal *proof.MetaReveal) (int64, error) {
 
 	var (
-		metaHash [32]byte
-		metaBlob []byte
-		metaType sql.NullInt16
+		metaHash  [32]byte
+		metaBlob  []byte
+		metaType  sql.NullInt16
+		supplyCap sql.NullInt64
 
 		err error
 	)
@@ -552,6 +579,9 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 			Int16: int16(metaReveal.Type),
 			Valid: true,
 		}
+		if metaReveal.SupplyCap != 0 {
+			supplyCap = sqlInt64(metaReveal.SupplyCap)
+		}
 
 	// Otherwise, we'll just be inserting only the meta hash. At a later
 	// time, the reveal/blob can also be inserted.
@@ -564,9 +594,10 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 	}
 
 	assetMetaID, err := db.UpsertAssetMeta(ctx, NewAssetMeta{
-		MetaDataHash: metaHash[:],
-		MetaDataBlob: metaBlob,
-		MetaDataType: metaType,
+		MetaDataHash:  metaHash[:],
+		MetaDataBlob:  metaBlob,
+		MetaDataType:  metaType,
+		MetaSupplyCap: supplyCap,
 	})
 	if err != nil {
 		return assetMetaID, err
@@ -574,3 +605,202 @@ func maybeUpsertAssetMeta(ctx context.Context, db UpsertAssetStore,
 
 	return assetMetaID, nil
 }
+
+// insertGroupKeyRotation inserts a rotation of the issuance authority of an
+// asset group. The new key ID is only set if the new key is a local key.
+//
+// Competing rotations with the same sequence are resolved by their
+// confirmation: the rotation that confirmed first wins, and the lower anchor
+// txid breaks ties within a block. If the new rotation wins, the stored
+// rotations that continue the chain of the losing one are removed. The
+// returned boolean is false if the rotation isn't part of the stored rotation
+// chain of the group, either because it lost or because it doesn't continue
+// the chain.
+func insertGroupKeyRotation(ctx context.Context, q UpsertAssetStore,
+	rotation *proof.GroupKeyRotation, newKeyID sql.NullInt64) (bool,
+	error) {
+
+	knownRotations, _, err := fetchGroupKeyRotations(
+		ctx, q, rotation.GroupKey,
+	)
+	if err != nil {
+		return false, fmt.Errorf("unable to fetch rotations: %w", err)
+	}
+
+	sequence := int(rotation.Sequence)
+	switch {
+	// The rotation chain can't have any gaps.
+	case sequence > len(knownRotations):
+		return false, nil
+
+	// The rotation must be signed by the key that held the issuance
+	// authority after the previous rotation of the chain.
+	case sequence > 0 &&
+		!knownRotations[sequence-1].NewKey.IsEqual(rotation.PrevKey):
+
+		return false, nil
+
+	case sequence < len(knownRotations):
+		known := knownRotations[sequence]
+		if !rotationConfirmedFirst(rotation, known) {
+			knownTXID := known.AnchorTx.TxHash()
+			return knownTXID == rotation.AnchorTx.TxHash(), nil
+		}
+
+		err := q.DeleteGroupKeyRotations(ctx, GroupKeyRotationsFrom{
+			GroupKey: rotation.GroupKey.SerializeCompressed(),
+			Sequence: int32(sequence),
+		})
+		if err != nil {
+			return false, fmt.Errorf("unable to delete superseded "+
+				"rotations: %w", err)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := rotation.Encode(&b); err != nil {
+		return false, fmt.Errorf("unable to encode rotation: %w", err)
+	}
+
+	err = q.InsertGroupKeyRotation(ctx, NewGroupKeyRotation{
+		GroupKey:    rotation.GroupKey.SerializeCompressed(),
+		Sequence:    int32(rotation.Sequence),
+		BlockHeight: int32(rotation.BlockHeight),
+		Rotation:    b.Bytes(),
+		NewKeyID:    newKeyID,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// rotationConfirmedFirst returns true if the first rotation was confirmed
+// before the second one. Rotations confirmed in the same block are ordered by
+// their anchor txid.
+func rotationConfirmedFirst(first, second *proof.GroupKeyRotation) bool {
+	if first.BlockHeight != second.BlockHeight {
+		return first.BlockHeight < second.BlockHeight
+	}
+
+	firstTXID := first.AnchorTx.TxHash()
+	secondTXID := second.AnchorTx.TxHash()
+	return bytes.Compare(firstTXID[:], secondTXID[:]) < 0
+}
+
+// fetchGroupKeyRotations fetches the rotations of the issuance authority of
+// the asset group with the given tweaked key, ordered by their sequence. The
+// key descriptor of the new key of the last rotation is returned as well. Its
+// key locator is only set if the new key is a local key.
+func fetchGroupKeyRotations(ctx context.Context, q UpsertAssetStore,
+	groupKey *btcec.PublicKey) ([]*proof.GroupKeyRotation,
+	keychain.KeyDescriptor, error) {
+
+	var lastKey keychain.KeyDescriptor
+
+	dbRotations, err := q.FetchGroupKeyRotations(
+		ctx, groupKey.SerializeCompressed(),
+	)
+	if err != nil {
+		return nil, lastKey, err
+	}
+
+	rotations := make([]*proof.GroupKeyRotation, 0, len(dbRotations))
+	for _, dbRotation := range dbRotations {
+		var rotation proof.GroupKeyRotation
+		err := rotation.Decode(bytes.NewReader(dbRotation.Rotation))
+		if err != nil {
+			return nil, lastKey, fmt.Errorf("unable to decode "+
+				"rotation: %w", err)
+		}
+		rotations = append(rotations, &rotation)
+
+		lastKey = keychain.KeyDescriptor{
+			PubKey: rotation.NewKey,
+		}
+		if dbRotation.NewKeyFamily.Valid {
+			lastKey.KeyLocator = keychain.KeyLocator{
+				Family: keychain.KeyFamily(
+					dbRotation.NewKeyFamily.Int32,
+				),
+				Index: uint32(dbRotation.NewKeyIndex.Int32),
+			}
+		}
+	}
+
+	return rotations, lastKey, nil
+}
+
+// upsertTapscriptTree stores the leaves of the given tapscript tree, if they're
+// known, so an asset with a script key that commits to the tree can later be
+// spent through one of them. The root hash of the tree is returned.
+func upsertTapscriptTree(ctx context.Context, q UpsertAssetStore,
+	tree *asset.TapscriptTree) ([]byte, error) {
+
+	root := tree.Root()
+	for idx, leaf := range tree.Leaves {
+		err := q.UpsertTapscriptLeaf(ctx, NewTapscriptLeaf{
+			RootHash:    root[:],
+			LeafIndex:   int32(idx),
+			LeafVersion: int16(leaf.LeafVersion),
+			Script:      leaf.Script,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to insert tapscript "+
+				"leaf: %w", err)
+		}
+	}
+
+	return root[:], nil
+}
+
+// fetchTapscriptTree fetches the tapscript tree with the given root hash. If
+// the leaves of the tree aren't known, the tree only carries its root hash.
+func fetchTapscriptTree(ctx context.Context, q UpsertAssetStore,
+	rootHash []byte) (*asset.TapscriptTree, error) {
+
+	leaves, err := fetchTapscriptLeaves(ctx, q, rootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(leaves) > 0 {
+		return &asset.TapscriptTree{
+			Leaves: leaves,
+		}, nil
+	}
+
+	root, err := chainhash.NewHash(rootHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tapscript root: %w", err)
+	}
+
+	return &asset.TapscriptTree{
+		RootHash: root,
+	}, nil
+}
+
+// fetchTapscriptLeaves fetches the leaves of the tapscript tree with the given
+// root hash. No leaves are returned if the tree isn't known.
+func fetchTapscriptLeaves(ctx context.Context, q UpsertAssetStore,
+	rootHash []byte) ([]txscript.TapLeaf, error) {
+
+	dbLeaves, err := q.FetchTapscriptLeaves(ctx, rootHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch tapscript leaves: %w",
+			err)
+	}
+
+	leaves := make([]txscript.TapLeaf, 0, len(dbLeaves))
+	for _, dbLeaf := range dbLeaves {
+		leaves = append(leaves, txscript.TapLeaf{
+			LeafVersion: txscript.TapscriptLeafVersion(
+				dbLeaf.LeafVersion,
+			),
+			Script: dbLeaf.Script,
+		})
+	}
+
+	return leaves, nil
+}
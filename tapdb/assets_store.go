@@ -3,6 +3,7 @@ package tapdb
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,8 +11,11 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
@@ -20,7 +24,9 @@ import (
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/keychain"
 )
@@ -80,6 +86,13 @@ type (
 	// UpdateUTXOLease wraps the params needed to lease a managed UTXO.
 	UpdateUTXOLease = sqlc.UpdateUTXOLeaseParams
 
+	// DeleteUTXOLeaseByOwner wraps the params needed to release the lease
+	// of a managed UTXO held by a specific lease owner.
+	DeleteUTXOLeaseByOwner = sqlc.DeleteUTXOLeaseByOwnerParams
+
+	// UTXOLeaseRow wraps a managed UTXO lease listing row.
+	UTXOLeaseRow = sqlc.QueryUTXOLeasesRow
+
 	// ApplyPendingOutput is used to update the script key and amount of an
 	// existing asset.
 	ApplyPendingOutput = sqlc.ApplyPendingOutputParams
@@ -87,6 +100,53 @@ type (
 	// AnchorTxConf identifies an unconfirmed anchor tx to confirm.
 	AnchorTxConf = sqlc.ConfirmChainAnchorTxParams
 
+	// AssetUnspent wraps the params needed to mark a spent asset as
+	// unspent again.
+	AssetUnspent = sqlc.SetAssetUnspentParams
+
+	// AnchoredAsset identifies the asset with a given script key that is
+	// anchored at a given managed UTXO.
+	AnchoredAsset = sqlc.DeleteAnchoredAssetParams
+
+	// AnchoredAssetProof identifies the proof of the asset with a given
+	// script key that is anchored at a given managed UTXO.
+	AnchoredAssetProof = sqlc.DeleteAnchoredAssetProofParams
+
+	// AnchorTxReplacement wraps the params needed to replace an
+	// unconfirmed anchor tx.
+	AnchorTxReplacement = sqlc.ReplaceChainAnchorTxParams
+
+	// NewTransferAnchorTx wraps the params needed to insert a new version
+	// of the anchor tx of a transfer.
+	NewTransferAnchorTx = sqlc.InsertTransferAnchorTxParams
+
+	// TransferAnchorTx is a stored version of the anchor tx of a transfer.
+	TransferAnchorTx = sqlc.TransferAnchorTx
+
+	// TransferAnchorTxsAfter identifies the versions of the anchor tx of a
+	// transfer that were stored after a given version.
+	TransferAnchorTxsAfter = sqlc.DeleteTransferAnchorTxsAfterParams
+
+	// NewTransferFeeBumpAnchor wraps the params needed to insert the fee
+	// bump anchor output of a transfer.
+	NewTransferFeeBumpAnchor = sqlc.InsertTransferFeeBumpAnchorParams
+
+	// TransferFeeBumpAnchorRow is the fee bump anchor output of a
+	// transfer, together with its internal key.
+	TransferFeeBumpAnchorRow = sqlc.FetchTransferFeeBumpAnchorRow
+
+	// TransferFeeBumpChild wraps the params needed to record the child
+	// transaction spending the fee bump anchor output of a transfer.
+	TransferFeeBumpChild = sqlc.SetTransferFeeBumpChildParams
+
+	// FeeBumpAnchorFootprintRow is an unspent fee bump anchor output of a
+	// transfer.
+	FeeBumpAnchorFootprintRow = sqlc.FetchFeeBumpAnchorFootprintRow
+
+	// ManagedUTXOOutpoint wraps the params needed to update the outpoint
+	// of a managed UTXO.
+	ManagedUTXOOutpoint = sqlc.UpdateManagedUTXOOutpointParams
+
 	// NewAssetTransfer wraps the params needed to insert a new asset
 	// transfer.
 	NewAssetTransfer = sqlc.InsertAssetTransferParams
@@ -101,6 +161,58 @@ type (
 	// AssetTransferRow wraps a single transfer row.
 	AssetTransferRow = sqlc.QueryAssetTransfersRow
 
+	// TransferCompletionTimes wraps the params needed to record the
+	// confirmation and delivery completion times of a transfer.
+	TransferCompletionTimes = sqlc.SetTransferCompletionTimesParams
+
+	// ProofDeliveryOutcome wraps the params needed to record the outcome
+	// of the proof delivery of a transfer output.
+	ProofDeliveryOutcome = sqlc.SetProofDeliveryOutcomeParams
+
+	// NewProofGapServe wraps the params needed to record a proof whose
+	// receiver might request the proofs missing from its proof chain.
+	NewProofGapServe = sqlc.InsertProofGapServeParams
+
+	// ProofGapServeKey wraps the params needed to identify the proof of a
+	// gap serve.
+	ProofGapServeKey = sqlc.DeleteProofGapServeParams
+
+	// TransferReOrgWatch wraps the params needed to insert or update the
+	// re-org watch of a confirmed transfer.
+	TransferReOrgWatch = sqlc.UpsertTransferReOrgWatchParams
+
+	// NewTransferReOrgWatchInput wraps the params needed to insert an input
+	// of the anchor transaction of a watched transfer.
+	NewTransferReOrgWatchInput = sqlc.InsertTransferReOrgWatchInputParams
+
+	// TransferReOrgWatchRow is the re-org watch of a confirmed transfer,
+	// together with the ID of its anchor transaction.
+	TransferReOrgWatchRow = sqlc.QueryTransferReOrgWatchesRow
+
+	// TransferReOrgWatchInput is an input of the anchor transaction of a
+	// watched transfer.
+	TransferReOrgWatchInput = sqlc.FetchTransferReOrgWatchInputsRow
+
+	// SweepableAnchorQuery wraps the params needed to query the managed
+	// UTXOs that can be swept back into the wallet.
+	SweepableAnchorQuery = sqlc.FetchSweepableAnchorsParams
+
+	// SweepableAnchorRow is a managed UTXO that can be swept back into
+	// the wallet.
+	SweepableAnchorRow = sqlc.FetchSweepableAnchorsRow
+
+	// OnChainFootprintRow is an unspent managed UTXO, together with one of
+	// the assets it anchors, if any.
+	OnChainFootprintRow = sqlc.FetchOnChainFootprintRow
+
+	// NewAnchorSweep wraps the params needed to record the sweep of a
+	// managed UTXO.
+	NewAnchorSweep = sqlc.InsertAnchorSweepParams
+
+	// AnchorSweepRow is a managed UTXO that was swept back into the
+	// wallet.
+	AnchorSweepRow = sqlc.QueryAnchorSweepsRow
+
 	// TransferInput tracks the inputs to an asset transfer.
 	TransferInput = sqlc.AssetTransferInput
 
@@ -130,6 +242,18 @@ type (
 
 	// ReAnchorParams wraps the params needed to re-anchor a passive asset.
 	ReAnchorParams = sqlc.ReAnchorPassiveAssetsParams
+
+	// UnspentAssetProof is an unspent asset along with its anchor outpoint
+	// and proof file, if one is stored.
+	UnspentAssetProof = sqlc.QueryUnspentAssetProofsRow
+
+	// AssetAmountUpdate wraps the params needed to update the amount of an
+	// asset.
+	AssetAmountUpdate = sqlc.UpdateAssetAmountParams
+
+	// AssetAcquiredAt wraps the params needed to set the acquisition time
+	// of an asset.
+	AssetAcquiredAt = sqlc.SetAssetAcquiredAtParams
 )
 
 // ActiveAssetsStore is a sub-set of the main sqlc.Querier interface that
@@ -172,6 +296,19 @@ type ActiveAssetsStore interface {
 	FetchAssetProofsByAssetID(ctx context.Context,
 		assetID []byte) ([]AssetProofByIDRow, error)
 
+	// QueryUnspentAssetProofs fetches all unspent assets along with their
+	// anchor outpoint and proof file.
+	QueryUnspentAssetProofs(ctx context.Context) ([]UnspentAssetProof,
+		error)
+
+	// UpdateAssetAmount updates the amount of the asset with the given
+	// primary key.
+	UpdateAssetAmount(ctx context.Context, arg AssetAmountUpdate) error
+
+	// SetAssetAcquiredAt sets the acquisition time of the asset with the
+	// given primary key.
+	SetAssetAcquiredAt(ctx context.Context, arg AssetAcquiredAt) error
+
 	// UpsertChainTx inserts a new or updates an existing chain tx into the
 	// DB.
 	UpsertChainTx(ctx context.Context, arg ChainTxParams) (int64, error)
@@ -222,13 +359,66 @@ type ActiveAssetsStore interface {
 	// the passed serialized outpoint.
 	DeleteUTXOLease(ctx context.Context, outpoint []byte) error
 
+	// UpdateManagedUTXOOutpoint updates the outpoint of the managed UTXO
+	// with the given ID.
+	UpdateManagedUTXOOutpoint(ctx context.Context,
+		arg ManagedUTXOOutpoint) error
+
 	// DeleteExpiredUTXOLeases deletes all expired UTXO leases.
 	DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime) error
 
+	// DeleteUTXOLeaseByOwner deletes the lease on a managed UTXO if it is
+	// held by the given lease owner.
+	DeleteUTXOLeaseByOwner(ctx context.Context,
+		arg DeleteUTXOLeaseByOwner) (int64, error)
+
+	// QueryUTXOLeases returns all managed UTXO leases that expire at or
+	// after the given time.
+	QueryUTXOLeases(ctx context.Context,
+		now sql.NullTime) ([]UTXOLeaseRow, error)
+
+	// FetchSweepableAnchors fetches the confirmed managed UTXOs that only
+	// anchor spent assets and weren't spent or swept yet.
+	FetchSweepableAnchors(ctx context.Context,
+		arg SweepableAnchorQuery) ([]SweepableAnchorRow, error)
+
+	// FetchOnChainFootprint fetches all managed UTXOs that weren't spent
+	// or swept yet, with one row for each asset they anchor.
+	FetchOnChainFootprint(ctx context.Context) ([]OnChainFootprintRow,
+		error)
+
+	// FetchFeeBumpAnchorFootprint fetches all fee bump anchor outputs of
+	// transfers that weren't spent by a child transaction yet.
+	FetchFeeBumpAnchorFootprint(ctx context.Context) (
+		[]FeeBumpAnchorFootprintRow, error)
+
+	// InsertAnchorSweep records the sweep of a managed UTXO.
+	InsertAnchorSweep(ctx context.Context, arg NewAnchorSweep) error
+
+	// QueryAnchorSweeps returns all managed UTXOs that were swept.
+	QueryAnchorSweeps(ctx context.Context) ([]AnchorSweepRow, error)
+
 	// ConfirmChainAnchorTx marks a new anchor transaction that was
 	// previously unconfirmed as confirmed.
 	ConfirmChainAnchorTx(ctx context.Context, arg AnchorTxConf) error
 
+	// UnconfirmChainAnchorTx removes the confirmation information of an
+	// anchor transaction that was re-organized out of the chain.
+	UnconfirmChainAnchorTx(ctx context.Context, txid []byte) error
+
+	// SetAssetUnspent marks the spent asset with the given script key and
+	// asset ID that is anchored at the given outpoint as unspent again.
+	SetAssetUnspent(ctx context.Context, arg AssetUnspent) error
+
+	// DeleteAnchoredAssetProof deletes the proof of the asset with the
+	// given script key that is anchored at the given managed UTXO.
+	DeleteAnchoredAssetProof(ctx context.Context,
+		arg AnchoredAssetProof) error
+
+	// DeleteAnchoredAsset deletes the asset with the given script key that
+	// is anchored at the given managed UTXO.
+	DeleteAnchoredAsset(ctx context.Context, arg AnchoredAsset) error
+
 	// InsertAssetTransfer inserts a new asset transfer into the DB.
 	InsertAssetTransfer(ctx context.Context,
 		arg NewAssetTransfer) (int64, error)
@@ -256,6 +446,62 @@ type ActiveAssetsStore interface {
 		query sqlc.QueryAssetTransfersParams) ([]AssetTransferRow,
 		error)
 
+	// SetTransferCompletionTimes records the confirmation and delivery
+	// completion times of the transfer with the given anchor txid.
+	SetTransferCompletionTimes(ctx context.Context,
+		arg TransferCompletionTimes) error
+
+	// SetProofDeliveryOutcome records the outcome of the proof delivery
+	// of the transfer output with the given anchor outpoint and script
+	// key.
+	SetProofDeliveryOutcome(ctx context.Context,
+		arg ProofDeliveryOutcome) error
+
+	// UnstageAssetTransfer clears the staged flag of the transfer with the
+	// given anchor txid, returning the number of affected rows.
+	UnstageAssetTransfer(ctx context.Context, anchorTxid []byte) (int64,
+		error)
+
+	// CancelAssetTransfer marks the unconfirmed transfer with the given
+	// anchor txid as cancelled, returning the number of affected rows.
+	CancelAssetTransfer(ctx context.Context, anchorTxid []byte) (int64,
+		error)
+
+	// ReplaceChainAnchorTx replaces an unconfirmed anchor tx, returning
+	// the number of affected rows.
+	ReplaceChainAnchorTx(ctx context.Context,
+		arg AnchorTxReplacement) (int64, error)
+
+	// InsertTransferAnchorTx inserts a new version of the anchor tx of a
+	// transfer.
+	InsertTransferAnchorTx(ctx context.Context,
+		arg NewTransferAnchorTx) error
+
+	// FetchTransferAnchorTxs fetches all stored versions of the anchor tx
+	// of a transfer, oldest first.
+	FetchTransferAnchorTxs(ctx context.Context,
+		transferID int64) ([]TransferAnchorTx, error)
+
+	// DeleteTransferAnchorTxsAfter deletes all versions of the anchor tx
+	// of a transfer that were stored after the given one.
+	DeleteTransferAnchorTxsAfter(ctx context.Context,
+		arg TransferAnchorTxsAfter) error
+
+	// InsertTransferFeeBumpAnchor inserts the fee bump anchor output of a
+	// transfer.
+	InsertTransferFeeBumpAnchor(ctx context.Context,
+		arg NewTransferFeeBumpAnchor) error
+
+	// FetchTransferFeeBumpAnchor fetches the fee bump anchor output of a
+	// transfer.
+	FetchTransferFeeBumpAnchor(ctx context.Context,
+		transferID int64) (TransferFeeBumpAnchorRow, error)
+
+	// SetTransferFeeBumpChild records the child transaction spending the
+	// fee bump anchor output of a transfer.
+	SetTransferFeeBumpChild(ctx context.Context,
+		arg TransferFeeBumpChild) error
+
 	// DeleteAssetWitnesses deletes the witnesses on disk associated with a
 	// given asset ID.
 	DeleteAssetWitnesses(ctx context.Context, assetID int64) error
@@ -270,6 +516,61 @@ type ActiveAssetsStore interface {
 	QueryReceiverProofTransferAttempt(ctx context.Context,
 		proofLocatorHash []byte) ([]time.Time, error)
 
+	// UpsertProofDeliveryReceipt inserts a new or updates an existing
+	// proof delivery receipt.
+	UpsertProofDeliveryReceipt(ctx context.Context,
+		arg NewProofDeliveryReceipt) error
+
+	// QueryProofDeliveryReceipts returns all proof delivery receipts for
+	// the given script key.
+	QueryProofDeliveryReceipts(ctx context.Context,
+		scriptKey []byte) ([]sqlc.ProofDeliveryReceipt, error)
+
+	// QueryPendingProofDeliveryTransfers returns the anchor TXIDs of the
+	// confirmed transfers that have at least one output with the given
+	// proof delivery status.
+	QueryPendingProofDeliveryTransfers(ctx context.Context,
+		status sql.NullInt16) ([][]byte, error)
+
+	// InsertProofGapServe records a delivered proof whose receiver might
+	// request the proofs missing from its proof chain.
+	InsertProofGapServe(ctx context.Context, arg NewProofGapServe) error
+
+	// QueryProofGapServes returns all recorded proof gap serves.
+	QueryProofGapServes(ctx context.Context) ([]sqlc.ProofGapServe, error)
+
+	// DeleteProofGapServe deletes the proof gap serve of the given proof.
+	DeleteProofGapServe(ctx context.Context, arg ProofGapServeKey) error
+
+	// UpsertTransferReOrgWatch inserts or updates the re-org watch of a
+	// confirmed transfer.
+	UpsertTransferReOrgWatch(ctx context.Context,
+		arg TransferReOrgWatch) error
+
+	// InsertTransferReOrgWatchInput inserts an input of the anchor
+	// transaction of a watched transfer.
+	InsertTransferReOrgWatchInput(ctx context.Context,
+		arg NewTransferReOrgWatchInput) error
+
+	// QueryTransferReOrgWatches returns the re-org watches of all watched
+	// transfers.
+	QueryTransferReOrgWatches(
+		ctx context.Context) ([]TransferReOrgWatchRow, error)
+
+	// FetchTransferReOrgWatchInputs returns the inputs of the anchor
+	// transaction of the watched transfer with the given ID.
+	FetchTransferReOrgWatchInputs(ctx context.Context,
+		transferID int64) ([]TransferReOrgWatchInput, error)
+
+	// DeleteTransferReOrgWatchInputs deletes the inputs of the anchor
+	// transaction of the watched transfer with the given ID.
+	DeleteTransferReOrgWatchInputs(ctx context.Context,
+		transferID int64) error
+
+	// DeleteTransferReOrgWatch deletes the re-org watch of the transfer
+	// with the given ID.
+	DeleteTransferReOrgWatch(ctx context.Context, transferID int64) error
+
 	// InsertPassiveAsset inserts a new row which includes the data
 	// necessary to re-anchor a passive asset.
 	InsertPassiveAsset(ctx context.Context, arg NewPassiveAsset) error
@@ -292,10 +593,32 @@ type ActiveAssetsStore interface {
 	// FetchAssetMetaForAsset fetches the asset meta for a given asset.
 	FetchAssetMetaForAsset(ctx context.Context,
 		assetID []byte) (sqlc.FetchAssetMetaForAssetRow, error)
+
+	// FetchGenesesWithMeta fetches the genesis of all assets that have
+	// meta data, together with the meta data.
+	FetchGenesesWithMeta(
+		ctx context.Context) ([]sqlc.FetchGenesesWithMetaRow, error)
 }
 
 type InsertRecvProofTxAttemptParams = sqlc.InsertReceiverProofTransferAttemptParams
 
+// NewProofDeliveryReceipt wraps the params needed to insert a new proof
+// delivery receipt.
+type NewProofDeliveryReceipt = sqlc.UpsertProofDeliveryReceiptParams
+
+// ProofDeliveryReceipt is a delivery receipt the receiver of a proof returned,
+// together with the locator of the proof it was returned for.
+type ProofDeliveryReceipt struct {
+	// Locator identifies the proof the receipt was returned for.
+	Locator proof.Locator
+
+	// Receipt is the signed delivery receipt.
+	Receipt *proof.DeliveryReceipt
+
+	// ReceiveTime is the time the receipt was received.
+	ReceiveTime time.Time
+}
+
 // AssetBalance holds a balance query result for a particular asset or all
 // assets tracked by this daemon.
 type AssetBalance struct {
@@ -307,6 +630,10 @@ type AssetBalance struct {
 	Type         asset.Type
 	GenesisPoint wire.OutPoint
 	OutputIndex  uint32
+
+	// GroupKey is the tweaked group key of the asset, or nil if the asset
+	// isn't part of a group.
+	GroupKey *btcec.PublicKey
 }
 
 // AssetGroupBalance holds abalance query result for a particular asset group
@@ -314,6 +641,10 @@ type AssetBalance struct {
 type AssetGroupBalance struct {
 	GroupKey *btcec.PublicKey
 	Balance  uint64
+
+	// AssetBalances is the breakdown of the group's balance by the IDs of
+	// the individual assets in the group.
+	AssetBalances map[asset.ID]AssetBalance
 }
 
 // BatchedAssetStore combines the AssetStore interface with the BatchedTx
@@ -367,6 +698,10 @@ type ChainAsset struct {
 	// tx.
 	AnchorBlockHeight uint32
 
+	// GenesisBlockHeight is the height of the block that mined the genesis
+	// transaction of the asset, or zero if it isn't known.
+	GenesisBlockHeight uint32
+
 	// AnchorOutpoint is the outpoint that commits to the asset.
 	AnchorOutpoint wire.OutPoint
 
@@ -397,6 +732,12 @@ type ChainAsset struct {
 	// the time is in the past, then the lease is not valid and the UTXO is
 	// available for coin selection.
 	AnchorLeaseExpiry *time.Time
+
+	// AcquiredAt is the time the asset was minted or received by this
+	// node, which is the time of the block that confirmed its anchor
+	// transaction. This is the zero time for assets that were stored
+	// before acquisition times were recorded.
+	AcquiredAt time.Time
 }
 
 // ManagedUTXO holds information about a given UTXO we manage.
@@ -425,6 +766,62 @@ type ManagedUTXO struct {
 	TapscriptSibling []byte
 }
 
+// FootprintAsset is an asset that is anchored in a UTXO of the daemon.
+type FootprintAsset struct {
+	// ID is the ID of the asset.
+	ID asset.ID
+
+	// Amount is the number of units of the asset.
+	Amount uint64
+
+	// ScriptKey is the tweaked script key of the asset.
+	ScriptKey *btcec.PublicKey
+
+	// Spent indicates whether the asset was spent, which is the case for
+	// the tombstones and burns left behind by transfers.
+	Spent bool
+}
+
+// FootprintUTXO is a UTXO the daemon considers its own and that wasn't spent by
+// a transfer or swept yet.
+type FootprintUTXO struct {
+	// OutPoint is the outpoint of the UTXO.
+	OutPoint wire.OutPoint
+
+	// OutputValue is the satoshi output value of the UTXO.
+	OutputValue btcutil.Amount
+
+	// InternalKey is the internal key of the output.
+	InternalKey keychain.KeyDescriptor
+
+	// TaprootAssetRoot is the Taproot Asset commitment root hash committed
+	// to by the output.
+	TaprootAssetRoot []byte
+
+	// MerkleRoot is the Taproot merkle root hash committed to by the
+	// output.
+	MerkleRoot []byte
+
+	// BlockHeight is the height of the block the UTXO was confirmed in,
+	// or zero if it isn't confirmed yet.
+	BlockHeight uint32
+
+	// LeaseOwner is the owner of the lease on the UTXO, if it is leased.
+	LeaseOwner []byte
+
+	// LeaseExpiry is the time the lease on the UTXO expires, if it is
+	// leased.
+	LeaseExpiry time.Time
+
+	// Assets are the assets anchored in the UTXO.
+	Assets []FootprintAsset
+
+	// FeeBumpAnchor indicates that the UTXO is the output of a transfer's
+	// anchor transaction that is reserved for bumping its fee with a
+	// child transaction. Such a UTXO never anchors any assets.
+	FeeBumpAnchor bool
+}
+
 // AssetHumanReadable is a subset of the base asset struct that only includes
 // human-readable asset fields.
 type AssetHumanReadable struct {
@@ -752,17 +1149,25 @@ func (a *AssetStore) dbAssetsToChainAssets(dbAssets []ConfirmedAsset,
 				"internal key: %w", err)
 		}
 
+		genesisHeight := extractSqlInt32[uint32](
+			sprout.GenesisBlockHeight,
+		)
+
 		chainAssets[i] = &ChainAsset{
 			Asset:                  assetSprout,
 			IsSpent:                sprout.Spent,
 			AnchorTx:               anchorTx,
 			AnchorTxid:             anchorTx.TxHash(),
 			AnchorBlockHash:        anchorBlockHash,
+			GenesisBlockHeight:     genesisHeight,
 			AnchorOutpoint:         anchorOutpoint,
 			AnchorInternalKey:      anchorInternalKey,
 			AnchorMerkleRoot:       sprout.AnchorMerkleRoot,
 			AnchorTapscriptSibling: sprout.AnchorTapscriptSibling,
 		}
+		if sprout.AcquiredAt.Valid {
+			chainAssets[i].AcquiredAt = sprout.AcquiredAt.Time.UTC()
+		}
 
 		// We only set the lease info if the lease is actually still
 		// valid and hasn't expired.
@@ -806,6 +1211,8 @@ func (a *AssetStore) constraintsToDbFilter(
 			assetID := query.AssetID[:]
 			assetFilter.AssetIDFilter = assetID
 		}
+		assetFilter.MinAcquiredAt = sqlTime(query.MinAcquiredAt)
+		assetFilter.MaxAcquiredAt = sqlTime(query.MaxAcquiredAt)
 		if query.GroupKey != nil {
 			groupKey := query.GroupKey.SerializeCompressed()
 			assetFilter.KeyGroupFilter = groupKey
@@ -886,10 +1293,20 @@ type AssetQueryFilters struct {
 	// MinAnchorHeight is the minimum block height the asset's anchor tx
 	// must have been confirmed at.
 	MinAnchorHeight int32
+
+	// MinAcquiredAt is the earliest acquisition time of the returned
+	// assets, inclusive.
+	MinAcquiredAt time.Time
+
+	// MaxAcquiredAt is the latest acquisition time of the returned assets,
+	// exclusive.
+	MaxAcquiredAt time.Time
 }
 
 // QueryBalancesByAsset queries the balances for assets or alternatively
-// for a selected one that matches the passed asset ID filter.
+// for a selected one that matches the passed asset ID filter. If the context
+// allows it, the query may be served by a read replica, so recent changes to
+// the balances might be missing.
 func (a *AssetStore) QueryBalancesByAsset(ctx context.Context,
 	assetID *asset.ID) (map[asset.ID]AssetBalance, error) {
 
@@ -900,7 +1317,10 @@ func (a *AssetStore) QueryBalancesByAsset(ctx context.Context,
 
 	balances := make(map[asset.ID]AssetBalance)
 
-	readOpts := NewAssetStoreReadTx()
+	// The balances are also used to check the supply of new mints, which
+	// needs to see the latest state. So we only use a read replica if the
+	// caller allows it.
+	readOpts := NewContextReadTx(ctx)
 	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
 		dbBalances, err := q.QueryAssetBalancesByAsset(ctx, assetFilter)
 		if err != nil {
@@ -909,29 +1329,12 @@ func (a *AssetStore) QueryBalancesByAsset(ctx context.Context,
 		}
 
 		for _, assetBalance := range dbBalances {
-			var assetID asset.ID
-			copy(assetID[:], assetBalance.AssetID[:])
-
-			assetIDBalance := AssetBalance{
-				Version:     assetBalance.Version,
-				Balance:     uint64(assetBalance.Balance),
-				Tag:         assetBalance.AssetTag,
-				Type:        asset.Type(assetBalance.AssetType),
-				OutputIndex: uint32(assetBalance.OutputIndex),
-			}
-
-			err = readOutPoint(
-				bytes.NewReader(assetBalance.GenesisPoint),
-				0, 0, &assetIDBalance.GenesisPoint,
-			)
+			assetIDBalance, err := parseAssetBalance(assetBalance)
 			if err != nil {
 				return err
 			}
 
-			copy(assetIDBalance.ID[:], assetBalance.AssetID)
-			copy(assetIDBalance.MetaHash[:], assetBalance.MetaHash)
-
-			balances[assetID] = assetIDBalance
+			balances[assetIDBalance.ID] = *assetIDBalance
 		}
 
 		return err
@@ -943,8 +1346,45 @@ func (a *AssetStore) QueryBalancesByAsset(ctx context.Context,
 	return balances, nil
 }
 
+// parseAssetBalance parses the balance of a single asset ID from the given
+// database row.
+func parseAssetBalance(dbBalance RawAssetBalance) (*AssetBalance, error) {
+	assetBalance := &AssetBalance{
+		Version:     dbBalance.Version,
+		Balance:     uint64(dbBalance.Balance),
+		Tag:         dbBalance.AssetTag,
+		Type:        asset.Type(dbBalance.AssetType),
+		OutputIndex: uint32(dbBalance.OutputIndex),
+	}
+
+	err := readOutPoint(
+		bytes.NewReader(dbBalance.GenesisPoint), 0, 0,
+		&assetBalance.GenesisPoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	copy(assetBalance.ID[:], dbBalance.AssetID)
+	copy(assetBalance.MetaHash[:], dbBalance.MetaHash)
+
+	if dbBalance.TweakedGroupKey != nil {
+		assetBalance.GroupKey, err = btcec.ParsePubKey(
+			dbBalance.TweakedGroupKey,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse group key: %w",
+				err)
+		}
+	}
+
+	return assetBalance, nil
+}
+
 // QueryAssetBalancesByGroup queries the asset balances for asset groups or
-// alternatively for a selected one that matches the passed filter.
+// alternatively for a selected one that matches the passed filter. If the
+// context allows it, the query may be served by a read replica, so recent
+// changes to the balances might be missing.
 func (a *AssetStore) QueryAssetBalancesByGroup(ctx context.Context,
 	groupKey *btcec.PublicKey) (map[asset.SerializedKey]AssetGroupBalance,
 	error) {
@@ -957,7 +1397,10 @@ func (a *AssetStore) QueryAssetBalancesByGroup(ctx context.Context,
 
 	balances := make(map[asset.SerializedKey]AssetGroupBalance)
 
-	readOpts := NewAssetStoreReadTx()
+	// The balances are also used to check the supply of new mints, which
+	// needs to see the latest state. So we only use a read replica if the
+	// caller allows it.
+	readOpts := NewContextReadTx(ctx)
 	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
 		dbBalances, err := q.QueryAssetBalancesByGroup(ctx, groupFilter)
 		if err != nil {
@@ -980,10 +1423,49 @@ func (a *AssetStore) QueryAssetBalancesByGroup(ctx context.Context,
 			balances[serializedKey] = AssetGroupBalance{
 				GroupKey: groupKey,
 				Balance:  uint64(groupBalance.Balance),
+				AssetBalances: make(
+					map[asset.ID]AssetBalance,
+				),
 			}
 		}
 
-		return err
+		// A group can span multiple asset IDs, so we also break down
+		// the balance of each group by the individual asset IDs.
+		dbAssetBalances, err := q.QueryAssetBalancesByAsset(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("unable to query asset "+
+				"balances by asset: %w", err)
+		}
+
+		for _, dbAssetBalance := range dbAssetBalances {
+			if dbAssetBalance.TweakedGroupKey == nil {
+				continue
+			}
+
+			assetBalance, err := parseAssetBalance(dbAssetBalance)
+			if err != nil {
+				return err
+			}
+
+			serializedKey := asset.ToSerialized(
+				assetBalance.GroupKey,
+			)
+			groupBalance, ok := balances[serializedKey]
+			if !ok {
+				continue
+			}
+
+			// The same asset ID can be reported in multiple rows
+			// if its assets are of different versions.
+			idBalances := groupBalance.AssetBalances
+			idBalance, ok := idBalances[assetBalance.ID]
+			if ok {
+				assetBalance.Balance += idBalance.Balance
+			}
+			idBalances[assetBalance.ID] = *assetBalance
+		}
+
+		return nil
 	})
 	if dbErr != nil {
 		return nil, dbErr
@@ -1084,6 +1566,56 @@ func (a *AssetStore) FetchAllAssets(ctx context.Context, includeSpent,
 	return a.dbAssetsToChainAssets(dbAssets, assetWitnesses)
 }
 
+// FetchAssetsByScriptKey fetches all assets, including spent and leased ones,
+// that are locked to the given script key. The script key is matched in its
+// x-only form, so assets locked to either parity of the key are returned. If
+// the context allows it, the query may be served by a read replica.
+func (a *AssetStore) FetchAssetsByScriptKey(ctx context.Context,
+	scriptKey *btcec.PublicKey) ([]*ChainAsset, error) {
+
+	xOnlyKey := schnorr.SerializePubKey(scriptKey)
+	parityKeys := [][]byte{
+		append([]byte{btcec.PubKeyFormatCompressedEven}, xOnlyKey...),
+		append([]byte{btcec.PubKeyFormatCompressedOdd}, xOnlyKey...),
+	}
+
+	var (
+		dbAssets  []ConfirmedAsset
+		witnesses assetWitnesses
+	)
+	readOpts := NewContextReadTx(ctx)
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbAssets = nil
+		witnesses = make(assetWitnesses)
+		for _, key := range parityKeys {
+			keyAssets, keyWitnesses, err := fetchAssetsWithWitness(
+				ctx, q, QueryAssetFilters{
+					TweakedScriptKey: key,
+					Now: sql.NullTime{
+						Time:  a.clock.Now().UTC(),
+						Valid: true,
+					},
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			dbAssets = append(dbAssets, keyAssets...)
+			for assetID, assetWitness := range keyWitnesses {
+				witnesses[assetID] = assetWitness
+			}
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return a.dbAssetsToChainAssets(dbAssets, witnesses)
+}
+
 // FetchManagedUTXOs fetches all UTXOs we manage.
 func (a *AssetStore) FetchManagedUTXOs(ctx context.Context) (
 	[]*ManagedUTXO, error) {
@@ -1449,6 +1981,29 @@ func (a *AssetStore) importAssetFromProof(ctx context.Context,
 		return fmt.Errorf("error inserting asset with genesis: %w", err)
 	}
 
+	// We also link the genesis point to its genesis transaction, so the
+	// genesis height of assets we didn't mint ourselves is known as well.
+	genesisProof, err := anchorGenesisFromProofFile(ctx, db, proof.Blob)
+	if err != nil {
+		return fmt.Errorf("unable to anchor genesis point: %w", err)
+	}
+
+	// If the asset was reissued into a group whose issuance authority was
+	// rotated, we learn about the rotations as well. That allows us to
+	// reject later reissuances that are authorized by a stale key.
+	if genesisProof != nil && genesisProof.IssuanceAuthority != nil {
+		authority := genesisProof.IssuanceAuthority
+		for _, rotation := range authority.Rotations {
+			_, err := insertGroupKeyRotation(
+				ctx, db, rotation, sql.NullInt64{},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to insert group key "+
+					"rotation: %w", err)
+			}
+		}
+	}
+
 	// Now that we have the asset inserted, we'll also insert all the
 	// witness data associated with the asset in a new row.
 	err = a.insertAssetWitnesses(
@@ -1458,6 +2013,22 @@ func (a *AssetStore) importAssetFromProof(ctx context.Context,
 		return fmt.Errorf("unable to insert asset witness: %w", err)
 	}
 
+	// The asset was acquired with the block that confirmed its anchor
+	// transaction. If the proof doesn't tell us when that was, we fall back
+	// to the time of the import.
+	acquiredAt := proof.AnchorBlockTime
+	if acquiredAt.IsZero() {
+		acquiredAt = a.clock.Now()
+	}
+	err = db.SetAssetAcquiredAt(ctx, AssetAcquiredAt{
+		AcquiredAt:      sqlTime(acquiredAt),
+		AssetPrimaryKey: assetIDs[0],
+	})
+	if err != nil {
+		return fmt.Errorf("unable to set asset acquisition time: %w",
+			err)
+	}
+
 	// As a final step, we'll insert the proof file we used to generate all
 	// the above information.
 	scriptKeyBytes := newAsset.ScriptKey.PubKey.SerializeCompressed()
@@ -1467,6 +2038,63 @@ func (a *AssetStore) importAssetFromProof(ctx context.Context,
 	})
 }
 
+// anchorGenesisFromProofFile links the genesis point of the asset of the given
+// proof file to the genesis transaction, which is the anchor transaction of the
+// first proof in the file. The genesis proof is returned. Blobs that aren't
+// proof files are ignored, in which case nil is returned.
+func anchorGenesisFromProofFile(ctx context.Context, db ActiveAssetsStore,
+	blob proof.Blob) (*proof.Proof, error) {
+
+	if !proof.IsProofFile(blob) {
+		return nil, nil
+	}
+
+	var proofFile proof.File
+	if err := proofFile.Decode(bytes.NewReader(blob)); err != nil {
+		return nil, fmt.Errorf("unable to decode proof file: %w", err)
+	}
+	genesisProof, err := proofFile.ProofAt(0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch genesis proof: %w", err)
+	}
+
+	var txBuf bytes.Buffer
+	if err := genesisProof.AnchorTx.Serialize(&txBuf); err != nil {
+		return nil, fmt.Errorf("unable to serialize genesis tx: %w",
+			err)
+	}
+
+	genTXID := genesisProof.AnchorTx.TxHash()
+	genBlockHash := genesisProof.BlockHeader.BlockHash()
+	chainTXID, err := db.UpsertChainTx(ctx, ChainTxParams{
+		Txid:        genTXID[:],
+		RawTx:       txBuf.Bytes(),
+		BlockHeight: sqlInt32(genesisProof.BlockHeight),
+		BlockHash:   genBlockHash[:],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to upsert genesis tx: %w", err)
+	}
+
+	genesisPoint, err := encodeOutpoint(
+		genesisProof.Asset.Genesis.FirstPrevOut,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode genesis point: %w",
+			err)
+	}
+
+	err = db.AnchorGenesisPoint(ctx, GenesisPointAnchor{
+		PrevOut:    genesisPoint,
+		AnchorTxID: sqlInt64(chainTXID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return genesisProof, nil
+}
+
 // upsertAssetProof updates the proof of an asset in the database, overwriting
 // the previous proof if it exists. This includes updating the chain tx, as the
 // only thing in a proof that can change is the block information.
@@ -1668,7 +2296,39 @@ func (a *AssetStore) ListEligibleCoins(ctx context.Context,
 	assetFilter.Spent = sqlBool(false)
 	assetFilter.Leased = sqlBool(false)
 
-	return a.queryCommitments(ctx, assetFilter)
+	commitments, err := a.queryCommitments(ctx, assetFilter)
+	if err != nil || len(constraints.SharedOutpoints) == 0 {
+		return commitments, err
+	}
+
+	// The shared outpoints were leased for another virtual packet of the
+	// same transfer, so their commitments are eligible as well.
+	assetFilter.Leased = nil
+	allCommitments, err := a.queryCommitments(ctx, assetFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	commitmentID := func(c *tapfreighter.AnchoredCommitment) asset.PrevID {
+		return asset.PrevID{
+			OutPoint:  c.AnchorPoint,
+			ID:        c.Asset.ID(),
+			ScriptKey: asset.ToSerialized(c.Asset.ScriptKey.PubKey),
+		}
+	}
+	listed := fn.NewSet(fn.Map(commitments, commitmentID)...)
+	shared := fn.NewSet(constraints.SharedOutpoints...)
+	for _, c := range allCommitments {
+		if !shared.Contains(c.AnchorPoint) ||
+			listed.Contains(commitmentID(c)) {
+
+			continue
+		}
+
+		commitments = append(commitments, c)
+	}
+
+	return commitments, nil
 }
 
 // LeaseCoins leases/locks/reserves coins for the given lease owner until the
@@ -1747,29 +2407,112 @@ func (a *AssetStore) DeleteExpiredLeases(ctx context.Context) error {
 	})
 }
 
-// queryCommitments queries the database for commitments matching the passed
-// filter.
-func (a *AssetStore) queryCommitments(ctx context.Context,
-	assetFilter QueryAssetFilters) ([]*tapfreighter.AnchoredCommitment,
-	error) {
+// ListLeases returns all leases that haven't expired yet.
+func (a *AssetStore) ListLeases(
+	ctx context.Context) ([]tapfreighter.UTXOLease, error) {
 
-	var (
-		matchingAssets      []*ChainAsset
-		chainAnchorToAssets = make(map[wire.OutPoint][]*ChainAsset)
-		anchorPoints        = make(map[wire.OutPoint]AnchorPoint)
-		err                 error
-	)
+	var leases []tapfreighter.UTXOLease
 
 	readOpts := NewAssetStoreReadTx()
 	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
-		// Now that we have the set of filters we need we'll query the
-		// DB for the set of assets that matches them.
-		matchingAssets, err = a.queryChainAssets(ctx, q, assetFilter)
+		dbLeases, err := q.QueryUTXOLeases(ctx, sql.NullTime{
+			Time:  a.clock.Now().UTC(),
+			Valid: true,
+		})
 		if err != nil {
 			return err
 		}
 
-		if len(matchingAssets) == 0 {
+		leases = make([]tapfreighter.UTXOLease, 0, len(dbLeases))
+		for _, dbLease := range dbLeases {
+			lease := tapfreighter.UTXOLease{
+				AmtSats: btcutil.Amount(dbLease.AmtSats),
+				Expiry:  dbLease.LeaseExpiry.Time.UTC(),
+			}
+			err := readOutPoint(
+				bytes.NewReader(dbLease.Outpoint), 0, 0,
+				&lease.OutPoint,
+			)
+			if err != nil {
+				return err
+			}
+			copy(lease.LeaseOwner[:], dbLease.LeaseOwner)
+
+			anchorTxid := dbLease.TransferAnchorTxid
+			if len(anchorTxid) > 0 {
+				txid, err := chainhash.NewHash(anchorTxid)
+				if err != nil {
+					return err
+				}
+				lease.TransferAnchorTxID = txid
+			}
+
+			leases = append(leases, lease)
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to list leases: %w", dbErr)
+	}
+
+	return leases, nil
+}
+
+// ReleaseLease releases the lease of the given UTXO if it is held by the given
+// lease owner. If there is no such lease, tapfreighter.ErrLeaseNotFound is
+// returned.
+func (a *AssetStore) ReleaseLease(ctx context.Context, leaseOwner [32]byte,
+	utxoOutpoint wire.OutPoint) error {
+
+	outpoint, err := encodeOutpoint(utxoOutpoint)
+	if err != nil {
+		return err
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		numReleased, err := q.DeleteUTXOLeaseByOwner(
+			ctx, DeleteUTXOLeaseByOwner{
+				Outpoint:   outpoint,
+				LeaseOwner: leaseOwner[:],
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to release lease: %w", err)
+		}
+
+		if numReleased == 0 {
+			return tapfreighter.ErrLeaseNotFound
+		}
+
+		return nil
+	})
+}
+
+// queryCommitments queries the database for commitments matching the passed
+// filter.
+func (a *AssetStore) queryCommitments(ctx context.Context,
+	assetFilter QueryAssetFilters) ([]*tapfreighter.AnchoredCommitment,
+	error) {
+
+	var (
+		matchingAssets      []*ChainAsset
+		chainAnchorToAssets = make(map[wire.OutPoint][]*ChainAsset)
+		anchorPoints        = make(map[wire.OutPoint]AnchorPoint)
+		err                 error
+	)
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		// Now that we have the set of filters we need we'll query the
+		// DB for the set of assets that matches them.
+		matchingAssets, err = a.queryChainAssets(ctx, q, assetFilter)
+		if err != nil {
+			return err
+		}
+
+		if len(matchingAssets) == 0 {
 			return tapfreighter.ErrMatchingAssetsNotFound
 		}
 
@@ -1876,6 +2619,8 @@ func (a *AssetStore) queryCommitments(ctx context.Context,
 			return nil, err
 		}
 
+		tapCommitment := anchorPointToCommitment[anchorPoint]
+
 		selectedAssets[i] = &tapfreighter.AnchoredCommitment{
 			AnchorPoint:       anchorPoint,
 			AnchorOutputValue: btcutil.Amount(anchorUTXO.AmtSats),
@@ -1888,9 +2633,11 @@ func (a *AssetStore) queryCommitments(ctx context.Context,
 					),
 				},
 			},
-			TapscriptSibling: tapscriptSibling,
-			Asset:            matchingAsset.Asset,
-			Commitment:       anchorPointToCommitment[anchorPoint],
+			TapscriptSibling:   tapscriptSibling,
+			GenesisBlockHeight: matchingAsset.GenesisBlockHeight,
+			AnchorBlockHeight:  matchingAsset.AnchorBlockHeight,
+			Asset:              matchingAsset.Asset,
+			Commitment:         tapCommitment,
 		}
 	}
 
@@ -1933,16 +2680,44 @@ func (a *AssetStore) LogPendingParcel(ctx context.Context,
 		// The transfer itself is just a shell which the inputs and
 		// outputs will reference. We'll insert this next, so we can
 		// use its ID.
+		var claimID []byte
+		if spend.ClaimID != nil {
+			claimID = spend.ClaimID[:]
+		}
 		transferID, err := q.InsertAssetTransfer(ctx, NewAssetTransfer{
 			HeightHint:       int32(spend.AnchorTxHeightHint),
 			AnchorTxid:       newAnchorTXID[:],
 			TransferTimeUnix: spend.TransferTime,
+			Staged:           spend.Staged,
+			ClaimID:          claimID,
+			Label:            spend.Label,
+			IdempotencyKey:   sqlStr(spend.IdempotencyKey),
 		})
 		if err != nil {
 			return fmt.Errorf("unable to insert asset transfer: "+
 				"%w", err)
 		}
 
+		// The funded anchor PSBT is stored with the transfer, so its
+		// anchor transaction can still be replaced after a restart.
+		err = storeTransferAnchorTx(
+			ctx, q, transferID, spend.AnchorTx, spend.ChainFees,
+			spend.AnchorPsbt, false,
+		)
+		if err != nil {
+			return err
+		}
+
+		if spend.FeeBumpAnchor != nil {
+			err = insertTransferFeeBumpAnchor(
+				ctx, q, transferID, spend.AnchorTx,
+				spend.FeeBumpAnchor,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Next, we'll insert the inputs to this transfer.
 		for idx := range spend.Inputs {
 			err := insertAssetTransferInput(
@@ -1971,6 +2746,225 @@ func (a *AssetStore) LogPendingParcel(ctx context.Context,
 	})
 }
 
+// storeTransferAnchorTx stores the given version of the anchor transaction of
+// the transfer with the given ID. If the version was stored before, the
+// transfer goes back to it and all versions stored after it are deleted
+// instead. Versions without a funded PSBT can't be replaced and aren't stored.
+func storeTransferAnchorTx(ctx context.Context, q ActiveAssetsStore,
+	transferID int64, anchorTx *wire.MsgTx, chainFees int64,
+	fundedPsbt *tapgarden.FundedPsbt, escalation bool) error {
+
+	versions, err := q.FetchTransferAnchorTxs(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch anchor txs: %w", err)
+	}
+
+	txid := anchorTx.TxHash()
+	for _, version := range versions {
+		if !bytes.Equal(version.Txid, txid[:]) {
+			continue
+		}
+
+		err := q.DeleteTransferAnchorTxsAfter(
+			ctx, TransferAnchorTxsAfter{
+				TransferID: transferID,
+				AnchorTxID: version.ID,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to delete anchor txs: %w",
+				err)
+		}
+
+		return nil
+	}
+
+	if fundedPsbt == nil || fundedPsbt.Pkt == nil {
+		return nil
+	}
+
+	var txBuf, psbtBuf bytes.Buffer
+	if err := anchorTx.Serialize(&txBuf); err != nil {
+		return fmt.Errorf("unable to serialize anchor tx: %w", err)
+	}
+	if err := fundedPsbt.Pkt.Serialize(&psbtBuf); err != nil {
+		return fmt.Errorf("unable to encode psbt: %w", err)
+	}
+
+	err = q.InsertTransferAnchorTx(ctx, NewTransferAnchorTx{
+		TransferID:        transferID,
+		Txid:              txid[:],
+		RawTx:             txBuf.Bytes(),
+		ChainFees:         chainFees,
+		FundedPsbt:        psbtBuf.Bytes(),
+		ChangeOutputIndex: fundedPsbt.ChangeOutputIndex,
+		FeeEscalation:     escalation,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert anchor tx: %w", err)
+	}
+
+	return nil
+}
+
+// fetchTransferAnchorTxs fetches the stored versions of the anchor transaction
+// of the transfer with the given ID. The funded PSBT of the current version is
+// set on the transfer, together with the versions it replaced and the number of
+// automatic fee escalations that led to it. All versions share the fee bump
+// anchor output of the transfer. Without any stored versions, the funded PSBT
+// stays unknown.
+func fetchTransferAnchorTxs(ctx context.Context, q ActiveAssetsStore,
+	transferID int64, transfer *tapfreighter.OutboundParcel) error {
+
+	versions, err := q.FetchTransferAnchorTxs(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch anchor txs: %w", err)
+	}
+
+	currentTXID := transfer.AnchorTx.TxHash()
+	for _, version := range versions {
+		if version.FeeEscalation {
+			transfer.FeeEscalations++
+		}
+
+		pkt, err := psbt.NewFromRawBytes(
+			bytes.NewReader(version.FundedPsbt), false,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode psbt: %w", err)
+		}
+		fundedPsbt := &tapgarden.FundedPsbt{
+			Pkt:               pkt,
+			ChangeOutputIndex: version.ChangeOutputIndex,
+			ChainFees:         version.ChainFees,
+		}
+
+		if bytes.Equal(version.Txid, currentTXID[:]) {
+			transfer.AnchorPsbt = fundedPsbt
+			continue
+		}
+
+		// All other versions were replaced, but can still confirm
+		// instead of the current one.
+		finalTx := wire.NewMsgTx(2)
+		err = finalTx.Deserialize(bytes.NewReader(version.RawTx))
+		if err != nil {
+			return fmt.Errorf("unable to deserialize anchor tx: %w",
+				err)
+		}
+		transfer.ReplacedAnchorTxs = append(
+			transfer.ReplacedAnchorTxs,
+			&tapfreighter.AnchorTransaction{
+				FundedPsbt:    fundedPsbt,
+				FinalTx:       finalTx,
+				ChainFees:     version.ChainFees,
+				FeeBumpAnchor: transfer.FeeBumpAnchor,
+			},
+		)
+	}
+
+	return nil
+}
+
+// insertTransferFeeBumpAnchor inserts the given fee bump anchor output of the
+// anchor transaction of the transfer with the given ID.
+func insertTransferFeeBumpAnchor(ctx context.Context, q ActiveAssetsStore,
+	transferID int64, anchorTx *wire.MsgTx,
+	feeBumpAnchor *tapfreighter.FeeBumpAnchor) error {
+
+	idx := feeBumpAnchor.OutputIndex
+	if int(idx) >= len(anchorTx.TxOut) {
+		return fmt.Errorf("fee bump anchor output %d out of range", idx)
+	}
+
+	internalKey := feeBumpAnchor.InternalKey
+	internalKeyID, err := q.UpsertInternalKey(ctx, InternalKey{
+		RawKey:    internalKey.PubKey.SerializeCompressed(),
+		KeyFamily: int32(internalKey.Family),
+		KeyIndex:  int32(internalKey.Index),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upsert internal key: %w", err)
+	}
+
+	err = q.InsertTransferFeeBumpAnchor(ctx, NewTransferFeeBumpAnchor{
+		TransferID:    transferID,
+		OutputIndex:   int32(idx),
+		AmtSats:       anchorTx.TxOut[idx].Value,
+		InternalKeyID: internalKeyID,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert fee bump anchor: %w", err)
+	}
+
+	return nil
+}
+
+// fetchTransferFeeBumpAnchor fetches the fee bump anchor output of the
+// transfer with the given ID and sets it on the transfer, together with the
+// child transaction that spends it, if any. Transfers without a fee bump anchor
+// output are left untouched.
+func fetchTransferFeeBumpAnchor(ctx context.Context, q ActiveAssetsStore,
+	transferID int64, transfer *tapfreighter.OutboundParcel) error {
+
+	dbAnchor, err := q.FetchTransferFeeBumpAnchor(ctx, transferID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+
+	case err != nil:
+		return fmt.Errorf("unable to fetch fee bump anchor: %w", err)
+	}
+
+	feeBumpAnchor, err := parseFeeBumpAnchor(
+		dbAnchor.OutputIndex, dbAnchor.RawKey, dbAnchor.KeyFamily,
+		dbAnchor.KeyIndex,
+	)
+	if err != nil {
+		return err
+	}
+	transfer.FeeBumpAnchor = feeBumpAnchor
+
+	if len(dbAnchor.ChildTxid) > 0 {
+		childTXID, err := chainhash.NewHash(dbAnchor.ChildTxid)
+		if err != nil {
+			return err
+		}
+		transfer.FeeBumpChildTXID = childTXID
+	}
+
+	return nil
+}
+
+// parseFeeBumpAnchor parses the columns of a stored fee bump anchor output.
+// The pk script is derived from the BIP-0086 internal key.
+func parseFeeBumpAnchor(outputIndex int32, rawKey []byte, keyFamily,
+	keyIndex int32) (*tapfreighter.FeeBumpAnchor, error) {
+
+	internalKey, err := btcec.ParsePubKey(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode internal key: %w", err)
+	}
+
+	outputKey := txscript.ComputeTaprootKeyNoScript(internalKey)
+	pkScript, err := tapscript.PayToTaprootScript(outputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tapfreighter.FeeBumpAnchor{
+		OutputIndex: uint32(outputIndex),
+		InternalKey: keychain.KeyDescriptor{
+			PubKey: internalKey,
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamily(keyFamily),
+				Index:  uint32(keyIndex),
+			},
+		},
+		PkScript: pkScript,
+	}, nil
+}
+
 // insertAssetTransferInput inserts a new asset transfer input into the DB.
 func insertAssetTransferInput(ctx context.Context, q ActiveAssetsStore,
 	transferID int64, input tapfreighter.TransferInput,
@@ -2087,10 +3081,23 @@ func insertAssetTransferOutput(ctx context.Context, q ActiveAssetsStore,
 
 	// Is this the output that will be used to re-anchor the passive asset?
 	if output.Anchor.NumPassiveAssets > 0 {
+		// A transfer of multiple assets can re-anchor passive assets
+		// in more than one output, so we only store the ones that are
+		// anchored in this output.
+		anchorIndex := output.Anchor.OutPoint.Index
+		outputPassiveAssets := fn.Filter(
+			passiveAssets,
+			func(p *tapfreighter.PassiveAssetReAnchor) bool {
+				passiveOut := p.VPacket.Outputs[0]
+				return passiveOut.AnchorOutputIndex ==
+					anchorIndex
+			},
+		)
+
 		// And now that we know the ID of that new anchor TX, we can
 		// store the passive assets, referencing that new UTXO.
 		err = logPendingPassiveAssets(
-			ctx, q, transferID, newUtxoID, passiveAssets,
+			ctx, q, transferID, newUtxoID, outputPassiveAssets,
 		)
 		if err != nil {
 			return fmt.Errorf("unable to log passive assets: %w",
@@ -2148,6 +3155,7 @@ func insertAssetTransferOutput(ctx context.Context, q ActiveAssetsStore,
 		NumPassiveAssets:    int32(output.Anchor.NumPassiveAssets),
 		OutputType:          int16(output.Type),
 		ProofCourierAddr:    output.ProofCourierAddr,
+		AssetID:             fn.ByteSlice(output.AssetID),
 	}
 
 	// There might not have been a split, so we can't rely on the split root
@@ -2229,6 +3237,11 @@ func fetchAssetTransferOutputs(ctx context.Context, q ActiveAssetsStore,
 				err)
 		}
 
+		// Outputs created before the asset ID was stored don't have
+		// one, so it is left empty for those.
+		var assetID asset.ID
+		copy(assetID[:], dbOut.AssetID)
+
 		outputs[idx] = tapfreighter.TransferOutput{
 			Anchor: tapfreighter.Anchor{
 				Value: btcutil.Amount(
@@ -2265,6 +3278,7 @@ func fetchAssetTransferOutputs(ctx context.Context, q ActiveAssetsStore,
 				},
 			},
 			ScriptKeyLocal: dbOut.ScriptKeyLocal,
+			AssetID:        assetID,
 			WitnessData:    witnessData,
 			SplitCommitmentRoot: mssmt.NewComputedNode(
 				splitRootHash,
@@ -2273,6 +3287,16 @@ func fetchAssetTransferOutputs(ctx context.Context, q ActiveAssetsStore,
 			ProofSuffix:      dbOut.ProofSuffix,
 			Type:             tappsbt.VOutputType(dbOut.OutputType),
 			ProofCourierAddr: dbOut.ProofCourierAddr,
+			ProofDelivery: tapfreighter.ProofDelivery{
+				Status: tapfreighter.ProofDeliveryStatus(
+					dbOut.ProofDeliveryStatus.Int16,
+				),
+				CourierAddr: dbOut.ProofDeliveryCourierAddr,
+				NumAttempts: uint32(
+					dbOut.ProofDeliveryAttempts.Int32,
+				),
+				ReceiptReceived: dbOut.ProofDeliveryReceipt,
+			},
 		}
 
 		err = readOutPoint(
@@ -2410,6 +3434,114 @@ func (a *AssetStore) QueryProofDeliveryLog(ctx context.Context,
 	return timestamps, err
 }
 
+// StoreProofDeliveryReceipt stores the delivery receipt the receiver returned
+// for the proof identified by the given locator.
+func (a *AssetStore) StoreProofDeliveryReceipt(ctx context.Context,
+	locator proof.Locator, receipt *proof.DeliveryReceipt) error {
+
+	var (
+		assetID  []byte
+		outpoint []byte
+		err      error
+	)
+	if locator.AssetID != nil {
+		assetID = locator.AssetID[:]
+	}
+	if locator.OutPoint != nil {
+		outpoint, err = encodeOutpoint(*locator.OutPoint)
+		if err != nil {
+			return fmt.Errorf("unable to encode outpoint: %w", err)
+		}
+	}
+
+	dbReceipt := NewProofDeliveryReceipt{
+		ScriptKey:    locator.ScriptKey.SerializeCompressed(),
+		AssetID:      assetID,
+		Outpoint:     outpoint,
+		ProofHash:    receipt.ProofHash[:],
+		RawScriptKey: receipt.RawScriptKey.SerializeCompressed(),
+		TapTweak:     receipt.TapTweak,
+		Signature:    receipt.Signature.Serialize(),
+		ReceiveTime:  a.clock.Now().UTC(),
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.UpsertProofDeliveryReceipt(ctx, dbReceipt)
+	})
+}
+
+// FetchProofDeliveryReceipts returns all delivery receipts that were returned
+// by the receiver of proofs sent to the given script key, most recent first.
+func (a *AssetStore) FetchProofDeliveryReceipts(ctx context.Context,
+	scriptKey *btcec.PublicKey) ([]*ProofDeliveryReceipt, error) {
+
+	var dbReceipts []sqlc.ProofDeliveryReceipt
+	readOpts := NewAssetStoreReadTx()
+	err := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		var err error
+		dbReceipts, err = q.QueryProofDeliveryReceipts(
+			ctx, scriptKey.SerializeCompressed(),
+		)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query proof delivery "+
+			"receipts: %w", err)
+	}
+
+	receipts := make([]*ProofDeliveryReceipt, 0, len(dbReceipts))
+	for _, dbReceipt := range dbReceipts {
+		locator := proof.Locator{
+			ScriptKey: *scriptKey,
+		}
+		if len(dbReceipt.AssetID) > 0 {
+			var assetID asset.ID
+			copy(assetID[:], dbReceipt.AssetID)
+			locator.AssetID = &assetID
+		}
+		if len(dbReceipt.Outpoint) > 0 {
+			var op wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(dbReceipt.Outpoint), 0, 0, &op,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode "+
+					"outpoint: %w", err)
+			}
+			locator.OutPoint = &op
+		}
+
+		var proofHash [sha256.Size]byte
+		copy(proofHash[:], dbReceipt.ProofHash)
+
+		rawKey, err := btcec.ParsePubKey(dbReceipt.RawScriptKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse raw script "+
+				"key: %w", err)
+		}
+
+		sig, err := schnorr.ParseSignature(dbReceipt.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse signature: %w",
+				err)
+		}
+
+		receipts = append(receipts, &ProofDeliveryReceipt{
+			Locator: locator,
+			Receipt: &proof.DeliveryReceipt{
+				ProofHash:    proofHash,
+				RawScriptKey: rawKey,
+				TapTweak:     dbReceipt.TapTweak,
+				Signature:    sig,
+			},
+			ReceiveTime: dbReceipt.ReceiveTime.UTC(),
+		})
+	}
+
+	return receipts, nil
+}
+
 // ConfirmParcelDelivery marks a spend event on disk as confirmed. This updates
 // the on-chain reference information on disk to point to this new spend.
 func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
@@ -2441,8 +3573,10 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 
 		// We'll keep around the IDs of the assets that we set to being
 		// spent. We'll need one of them as our template to create the
-		// new assets.
+		// new assets. A transfer can move multiple asset IDs, so we
+		// also remember one spent asset for each asset ID.
 		spentAssetIDs := make([]int64, len(inputs))
+		templateIDs := make(map[asset.ID]int64, len(inputs))
 		for idx := range inputs {
 			spentAssetIDs[idx], err = q.SetAssetSpent(
 				ctx, SetAssetSpentParams{
@@ -2454,6 +3588,10 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 				return fmt.Errorf("unable to set asset spent: "+
 					"%w", err)
 			}
+
+			var inputAssetID asset.ID
+			copy(inputAssetID[:], inputs[idx].AssetID)
+			templateIDs[inputAssetID] = spentAssetIDs[idx]
 		}
 
 		// Now is the time to fetch our outputs and create new assets
@@ -2463,6 +3601,13 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 			return fmt.Errorf("unable to fetch transfer outputs: "+
 				"%w", err)
 		}
+
+		// The new assets are acquired with the block that confirms the
+		// transfer, or now if we don't know when that was.
+		acquiredAt := sqlTime(conf.ConfirmationTime)
+		if !acquiredAt.Valid {
+			acquiredAt = sqlTime(a.clock.Now())
+		}
 		for idx := range outputs {
 			out := outputs[idx]
 
@@ -2507,16 +3652,29 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 				continue
 			}
 
-			// Since we define that a transfer can only move assets
-			// within the same asset ID, we can take any of the
-			// inputs as a template for the new asset, since the
+			// We can take any of the inputs of the output's asset
+			// ID as a template for the new asset, since the
 			// genesis and group key will be the same. We'll
-			// overwrite all other fields.
+			// overwrite all other fields. Outputs that were stored
+			// without an asset ID belong to transfers that only
+			// moved a single asset ID.
 			//
 			// TODO(guggero): This will need an update once we want
 			// to support full lock_time and relative_lock_time
 			// support.
 			templateID := spentAssetIDs[0]
+			if len(out.AssetID) != 0 {
+				var outAssetID asset.ID
+				copy(outAssetID[:], out.AssetID)
+
+				var ok bool
+				templateID, ok = templateIDs[outAssetID]
+				if !ok {
+					return fmt.Errorf("no input for "+
+						"output asset ID %x",
+						outAssetID[:])
+				}
+			}
 			params := ApplyPendingOutput{
 				ScriptKeyID: out.ScriptKeyID,
 				AnchorUtxoID: sqlInt64(
@@ -2528,6 +3686,7 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 				SpentAssetID:             templateID,
 				Spent:                    isTombstone || isBurn,
 				AssetVersion:             out.AssetVersion,
+				AcquiredAt:               acquiredAt,
 			}
 			newAssetID, err := q.ApplyPendingOutput(ctx, params)
 			if err != nil {
@@ -2592,6 +3751,36 @@ func (a *AssetStore) ConfirmParcelDelivery(ctx context.Context,
 			return err
 		}
 
+		// We also record when the transfer was confirmed and completed,
+		// so we can report on transfer latencies later on.
+		confTime := sql.NullTime{
+			Time:  conf.ConfirmationTime.UTC(),
+			Valid: !conf.ConfirmationTime.IsZero(),
+		}
+		err = q.SetTransferCompletionTimes(ctx, TransferCompletionTimes{
+			AnchorTxid:           conf.AnchorTXID[:],
+			ConfirmationTimeUnix: confTime,
+			DeliveryCompleteTimeUnix: sql.NullTime{
+				Time:  a.clock.Now().UTC(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set transfer completion "+
+				"times: %w", err)
+		}
+
+		// The re-org watch of the anchor transaction is stored together
+		// with the confirmation, so it is resumed after a restart.
+		if conf.ReOrgWatch != nil {
+			err = insertReOrgWatch(
+				ctx, q, assetTransfer.ID, conf.ReOrgWatch,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Keep the old proofs as a reference for when we list past
 		// transfers.
 
@@ -2715,22 +3904,1186 @@ func (a *AssetStore) reAnchorPassiveAssets(ctx context.Context,
 func (a *AssetStore) PendingParcels(
 	ctx context.Context) ([]*tapfreighter.OutboundParcel, error) {
 
-	return a.QueryParcels(ctx, true)
+	// The pending parcels are resumed by the porter, which needs to see
+	// its own writes, so we always query the primary database.
+	readOpts := NewAssetStoreReadTx()
+	return a.queryParcels(ctx, TransferQuery{
+		UnconfOnly: true,
+	}, &readOpts)
 }
 
-// QueryParcels returns the set of confirmed or unconfirmed parcels.
-func (a *AssetStore) QueryParcels(ctx context.Context,
-	pending bool) ([]*tapfreighter.OutboundParcel, error) {
+// QueryParcelByIdempotencyKey returns the most recent parcel that wasn't
+// cancelled and was requested with the given idempotency key at or after the
+// given time. Nil is returned if there is no such parcel.
+func (a *AssetStore) QueryParcelByIdempotencyKey(ctx context.Context,
+	key string, since time.Time) (*tapfreighter.OutboundParcel, error) {
 
-	var transfers []*tapfreighter.OutboundParcel
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key must be set")
+	}
+
+	// Retried send requests must see the transfer stored by the previous
+	// attempt, so we always query the primary database.
+	readOpts := NewAssetStoreReadTx()
+	parcels, err := a.queryParcels(ctx, TransferQuery{
+		IdempotencyKey: sqlStr(key),
+		MinTransferTime: sql.NullTime{
+			Time:  since.UTC(),
+			Valid: true,
+		},
+	}, &readOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parcels are ordered by their transfer time, so we return the
+	// last one that wasn't cancelled.
+	for idx := len(parcels) - 1; idx >= 0; idx-- {
+		if !parcels[idx].Cancelled {
+			return parcels[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// UnstageParcel clears the staged flag of the pending parcel with the given
+// anchor transaction ID, releasing it for broadcast.
+func (a *AssetStore) UnstageParcel(ctx context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		numRows, err := q.UnstageAssetTransfer(ctx, anchorTXID[:])
+		if err != nil {
+			return fmt.Errorf("unable to unstage transfer: %w", err)
+		}
+
+		if numRows == 0 {
+			return fmt.Errorf("no staged transfer found for anchor "+
+				"txid %v", anchorTXID)
+		}
+
+		return nil
+	})
+}
+
+// CancelParcel marks the unconfirmed parcel with the given anchor transaction
+// ID as cancelled and releases the leases on its inputs, so the inputs are
+// available for coin selection again.
+func (a *AssetStore) CancelParcel(ctx context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		numRows, err := q.CancelAssetTransfer(ctx, anchorTXID[:])
+		if err != nil {
+			return fmt.Errorf("unable to cancel transfer: %w", err)
+		}
+
+		transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+			AnchorTxHash: anchorTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query asset transfers: %w",
+				err)
+		}
+
+		// If no transfer was cancelled, we find out why, so the caller
+		// can tell a confirmed transfer apart from an unknown one.
+		switch {
+		case len(transfers) == 0:
+			return fmt.Errorf("no transfer found for anchor txid "+
+				"%v", anchorTXID)
+
+		case numRows == 0 && transfers[0].Cancelled:
+			return fmt.Errorf("transfer with anchor txid %v was "+
+				"already cancelled", anchorTXID)
+
+		case numRows == 0:
+			return fmt.Errorf("%w: anchor txid %v",
+				tapfreighter.ErrTransferConfirmed, anchorTXID)
+		}
+
+		inputs, err := q.FetchTransferInputs(ctx, transfers[0].ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch transfer inputs: %w",
+				err)
+		}
+		for _, input := range inputs {
+			err := q.DeleteUTXOLease(ctx, input.AnchorPoint)
+			if err != nil {
+				return fmt.Errorf("unable to release input "+
+					"lease: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceParcelAnchorTx replaces the unconfirmed anchor transaction of the
+// parcel with the given anchor transaction ID. The replacement must create the
+// same outputs at the same indexes, so only the outpoints of the parcel's
+// anchor outputs need to be updated. The replaced version is kept together with
+// the new one, unless the parcel goes back to a version it was replaced with
+// before, in which case the versions created after that one are deleted.
+func (a *AssetStore) ReplaceParcelAnchorTx(ctx context.Context,
+	oldTXID chainhash.Hash, anchorTx *tapfreighter.AnchorTransaction,
+	escalation bool) error {
+
+	newTx := anchorTx.FinalTx
+	chainFees := anchorTx.ChainFees
+
+	var txBuf bytes.Buffer
+	if err := newTx.Serialize(&txBuf); err != nil {
+		return fmt.Errorf("unable to serialize anchor tx: %w", err)
+	}
+	newTXID := newTx.TxHash()
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+			AnchorTxHash: oldTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query asset transfers: %w",
+				err)
+		}
+
+		switch {
+		case len(transfers) == 0:
+			return fmt.Errorf("no transfer found for anchor txid "+
+				"%v", oldTXID)
+
+		case transfers[0].Cancelled:
+			return fmt.Errorf("transfer with anchor txid %v was "+
+				"cancelled", oldTXID)
+		}
+
+		numRows, err := q.ReplaceChainAnchorTx(ctx, AnchorTxReplacement{
+			NewTxid:   newTXID[:],
+			RawTx:     txBuf.Bytes(),
+			ChainFees: chainFees,
+			OldTxid:   oldTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to replace anchor tx: %w",
+				err)
+		}
+		if numRows == 0 {
+			return fmt.Errorf("%w: anchor txid %v",
+				tapfreighter.ErrTransferConfirmed, oldTXID)
+		}
+
+		outputs, err := q.FetchTransferOutputs(ctx, transfers[0].ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch transfer outputs: "+
+				"%w", err)
+		}
+
+		// Multiple outputs can share the same anchor output, so we
+		// only update each of them once.
+		updated := make(map[int64]struct{}, len(outputs))
+		for _, output := range outputs {
+			if _, ok := updated[output.AnchorUtxoID]; ok {
+				continue
+			}
+
+			var anchorPoint wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(output.AnchorOutpoint), 0, 0,
+				&anchorPoint,
+			)
+			if err != nil {
+				return err
+			}
+			anchorPoint.Hash = newTXID
+
+			outpointBytes, err := encodeOutpoint(anchorPoint)
+			if err != nil {
+				return err
+			}
+			err = q.UpdateManagedUTXOOutpoint(
+				ctx, ManagedUTXOOutpoint{
+					Outpoint: outpointBytes,
+					UtxoID:   output.AnchorUtxoID,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to update anchor "+
+					"outpoint: %w", err)
+			}
+
+			updated[output.AnchorUtxoID] = struct{}{}
+		}
+
+		// A child transaction that spent the fee bump anchor output of
+		// the replaced version doesn't spend the one of the new
+		// version.
+		err = q.SetTransferFeeBumpChild(ctx, TransferFeeBumpChild{
+			TransferID: transfers[0].ID,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to reset fee bump child: %w",
+				err)
+		}
+
+		return storeTransferAnchorTx(
+			ctx, q, transfers[0].ID, newTx, chainFees,
+			anchorTx.FundedPsbt, escalation,
+		)
+	})
+}
+
+// LogFeeBumpChild records the given child transaction as spending the fee bump
+// anchor output of the unconfirmed anchor transaction with the given ID. A nil
+// child transaction ID removes the record again.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) LogFeeBumpChild(ctx context.Context,
+	anchorTXID chainhash.Hash, childTXID *chainhash.Hash) error {
+
+	var childTxid []byte
+	if childTXID != nil {
+		childTxid = childTXID[:]
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+			AnchorTxHash: anchorTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query asset transfers: %w",
+				err)
+		}
+
+		switch {
+		case len(transfers) == 0:
+			return fmt.Errorf("no transfer found for anchor txid "+
+				"%v", anchorTXID)
+
+		case transfers[0].Cancelled:
+			return fmt.Errorf("transfer with anchor txid %v was "+
+				"cancelled", anchorTXID)
+		}
+
+		// Only a child of an unconfirmed anchor transaction speeds
+		// up its confirmation. Removing the record is always allowed.
+		chainTx, err := q.FetchChainTx(ctx, anchorTXID[:])
+		if err != nil {
+			return fmt.Errorf("unable to fetch anchor tx: %w", err)
+		}
+		if childTXID != nil && chainTx.BlockHeight.Valid {
+			return fmt.Errorf("%w: anchor txid %v",
+				tapfreighter.ErrTransferConfirmed, anchorTXID)
+		}
+
+		_, err = q.FetchTransferFeeBumpAnchor(ctx, transfers[0].ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch fee bump anchor: "+
+				"%w", err)
+		}
+
+		err = q.SetTransferFeeBumpChild(ctx, TransferFeeBumpChild{
+			ChildTxid:  childTxid,
+			TransferID: transfers[0].ID,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to set fee bump child: %w",
+				err)
+		}
+
+		return nil
+	})
+}
+
+// AbandonParcel rolls back the confirmation of the parcel with the given anchor
+// transaction ID after its anchor transaction was re-organized out of the
+// chain. The input assets of the parcel are marked as unspent again, the assets
+// it created are removed and the parcel is marked as cancelled. A parcel that
+// re-anchored passive assets can't be rolled back, as the previous anchor of
+// the passive assets isn't known anymore.
+func (a *AssetStore) AbandonParcel(ctx context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+			AnchorTxHash: anchorTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query asset transfers: %w",
+				err)
+		}
+		if len(transfers) == 0 {
+			return fmt.Errorf("no transfer found for anchor txid "+
+				"%v", anchorTXID)
+		}
+		transfer := transfers[0]
+
+		passiveAssets, err := q.QueryPassiveAssets(ctx, transfer.ID)
+		if err != nil {
+			return fmt.Errorf("unable to query passive assets: %w",
+				err)
+		}
+		if len(passiveAssets) > 0 {
+			return fmt.Errorf("%w: transfer with anchor txid %v "+
+				"re-anchored %d passive assets",
+				tapfreighter.ErrTransferRollbackUnsupported,
+				anchorTXID, len(passiveAssets))
+		}
+
+		// The input assets become spendable again, so we also release
+		// the leases we acquired on them when broadcasting the
+		// transfer.
+		inputs, err := q.FetchTransferInputs(ctx, transfer.ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch transfer inputs: %w",
+				err)
+		}
+		for _, input := range inputs {
+			err := q.SetAssetUnspent(ctx, AssetUnspent{
+				ScriptKey:   input.ScriptKey,
+				GenAssetID:  input.AssetID,
+				AnchorPoint: input.AnchorPoint,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to set asset "+
+					"unspent: %w", err)
+			}
+
+			err = q.DeleteUTXOLease(ctx, input.AnchorPoint)
+			if err != nil {
+				return fmt.Errorf("unable to release input "+
+					"lease: %w", err)
+			}
+		}
+
+		// The assets created for our own outputs when the transfer
+		// confirmed are removed again, together with their proofs.
+		outputs, err := q.FetchTransferOutputs(ctx, transfer.ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch transfer outputs: "+
+				"%w", err)
+		}
+		for _, out := range outputs {
+			anchorUtxoID := sqlInt64(out.AnchorUtxoID)
+			err := q.DeleteAnchoredAssetProof(
+				ctx, AnchoredAssetProof{
+					ScriptKeyID:  out.ScriptKeyID,
+					AnchorUtxoID: anchorUtxoID,
+				},
+			)
+			if err != nil {
+				return fmt.Errorf("unable to delete output "+
+					"proof: %w", err)
+			}
+
+			err = q.DeleteAnchoredAsset(ctx, AnchoredAsset{
+				ScriptKeyID:  out.ScriptKeyID,
+				AnchorUtxoID: anchorUtxoID,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to delete output "+
+					"asset: %w", err)
+			}
+		}
+
+		// Only an unconfirmed transfer can be cancelled, so we need to
+		// remove the confirmation of its anchor transaction first.
+		err = q.UnconfirmChainAnchorTx(ctx, anchorTXID[:])
+		if err != nil {
+			return fmt.Errorf("unable to unconfirm anchor tx: %w",
+				err)
+		}
+
+		numRows, err := q.CancelAssetTransfer(ctx, anchorTXID[:])
+		if err != nil {
+			return fmt.Errorf("unable to cancel transfer: %w", err)
+		}
+		if numRows == 0 {
+			return fmt.Errorf("transfer with anchor txid %v was "+
+				"already cancelled", anchorTXID)
+		}
+
+		return deleteReOrgWatch(ctx, q, transfer.ID)
+	})
+}
+
+// QuarantineParcel leases the anchor outputs of the parcel with the given
+// anchor transaction ID under the quarantine lease owner, so the assets it
+// created can't be spent until the leases are released manually.
+func (a *AssetStore) QuarantineParcel(ctx context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+			AnchorTxHash: anchorTXID[:],
+		})
+		if err != nil {
+			return fmt.Errorf("unable to query asset transfers: %w",
+				err)
+		}
+		if len(transfers) == 0 {
+			return fmt.Errorf("no transfer found for anchor txid "+
+				"%v", anchorTXID)
+		}
+
+		outputs, err := q.FetchTransferOutputs(ctx, transfers[0].ID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch transfer outputs: "+
+				"%w", err)
+		}
+		leaseOwner := tapfreighter.QuarantineLeaseIdentifier
+		for _, out := range outputs {
+			err := q.UpdateUTXOLease(ctx, UpdateUTXOLease{
+				LeaseOwner: leaseOwner[:],
+				LeaseExpiry: sql.NullTime{
+					Time:  MaxValidSQLTime,
+					Valid: true,
+				},
+				Outpoint: out.AnchorOutpoint,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to lease output: %w",
+					err)
+			}
+		}
+
+		return deleteReOrgWatch(ctx, q, transfers[0].ID)
+	})
+}
+
+// insertReOrgWatch stores the given re-org watch of the transfer with the
+// given ID.
+func insertReOrgWatch(ctx context.Context, q ActiveAssetsStore,
+	transferID int64, watch *tapfreighter.ReOrgWatch) error {
+
+	err := q.UpsertTransferReOrgWatch(ctx, TransferReOrgWatch{
+		TransferID:     transferID,
+		ConfHeight:     int32(watch.ConfHeight),
+		AbandonPending: watch.AbandonPending,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to insert re-org watch: %w", err)
+	}
+
+	for _, input := range watch.Inputs {
+		outpoint, err := encodeOutpoint(input.OutPoint)
+		if err != nil {
+			return err
+		}
+
+		err = q.InsertTransferReOrgWatchInput(
+			ctx, NewTransferReOrgWatchInput{
+				TransferID: transferID,
+				Outpoint:   outpoint,
+				PkScript:   input.PkScript,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert re-org watch "+
+				"input: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteReOrgWatch deletes the re-org watch of the transfer with the given ID,
+// if there is one.
+func deleteReOrgWatch(ctx context.Context, q ActiveAssetsStore,
+	transferID int64) error {
+
+	err := q.DeleteTransferReOrgWatchInputs(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("unable to delete re-org watch inputs: %w",
+			err)
+	}
+
+	err = q.DeleteTransferReOrgWatch(ctx, transferID)
+	if err != nil {
+		return fmt.Errorf("unable to delete re-org watch: %w", err)
+	}
+
+	return nil
+}
+
+// queryTransferID returns the ID of the transfer with the given anchor
+// transaction ID.
+func queryTransferID(ctx context.Context, q ActiveAssetsStore,
+	anchorTXID chainhash.Hash) (int64, error) {
+
+	transfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
+		AnchorTxHash: anchorTXID[:],
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to query asset transfers: %w", err)
+	}
+	if len(transfers) == 0 {
+		return 0, fmt.Errorf("no transfer found for anchor txid %v",
+			anchorTXID)
+	}
+
+	return transfers[0].ID, nil
+}
+
+// ReOrgWatches returns the parcels whose anchor transactions are still watched
+// for re-orgs.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) ReOrgWatches(
+	ctx context.Context) ([]*tapfreighter.ReOrgWatch, error) {
+
+	var (
+		txids   [][]byte
+		watches []*tapfreighter.ReOrgWatch
+	)
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		rows, err := q.QueryTransferReOrgWatches(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to query re-org watches: %w",
+				err)
+		}
+
+		txids = make([][]byte, 0, len(rows))
+		watches = make([]*tapfreighter.ReOrgWatch, 0, len(rows))
+		for _, row := range rows {
+			dbInputs, err := q.FetchTransferReOrgWatchInputs(
+				ctx, row.TransferID,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to fetch re-org "+
+					"watch inputs: %w", err)
+			}
+
+			inputs := make(
+				[]tapfreighter.AnchorInput, len(dbInputs),
+			)
+			for idx, dbInput := range dbInputs {
+				err := readOutPoint(
+					bytes.NewReader(dbInput.Outpoint), 0, 0,
+					&inputs[idx].OutPoint,
+				)
+				if err != nil {
+					return err
+				}
+				inputs[idx].PkScript = dbInput.PkScript
+			}
+
+			txids = append(txids, row.Txid)
+			watches = append(watches, &tapfreighter.ReOrgWatch{
+				ConfHeight:     uint32(row.ConfHeight),
+				Inputs:         inputs,
+				AbandonPending: row.AbandonPending,
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	for idx, txid := range txids {
+		parcels, err := a.queryParcels(ctx, TransferQuery{
+			AnchorTxHash: txid,
+		}, &readOpts)
+		if err != nil {
+			return nil, err
+		}
+		if len(parcels) == 0 {
+			return nil, fmt.Errorf("no transfer found for anchor "+
+				"txid %x", txid)
+		}
+
+		watches[idx].Parcel = parcels[0]
+	}
+
+	return watches, nil
+}
+
+// UpdateReOrgWatch updates the confirmation height and the pending abandon
+// flag of the re-org watch of the parcel with the given anchor transaction ID.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) UpdateReOrgWatch(ctx context.Context,
+	anchorTXID chainhash.Hash, confHeight uint32,
+	abandonPending bool) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transferID, err := queryTransferID(ctx, q, anchorTXID)
+		if err != nil {
+			return err
+		}
+
+		return q.UpsertTransferReOrgWatch(ctx, TransferReOrgWatch{
+			TransferID:     transferID,
+			ConfHeight:     int32(confHeight),
+			AbandonPending: abandonPending,
+		})
+	})
+}
+
+// RemoveReOrgWatch removes the re-org watch of the parcel with the given anchor
+// transaction ID.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) RemoveReOrgWatch(ctx context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		transferID, err := queryTransferID(ctx, q, anchorTXID)
+		if err != nil {
+			return err
+		}
+
+		return deleteReOrgWatch(ctx, q, transferID)
+	})
+}
+
+// LogProofDelivery records the outcome of the delivery of the proof of the
+// transfer output with the given anchor outpoint and script key.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) LogProofDelivery(ctx context.Context,
+	anchorPoint wire.OutPoint, scriptKey *btcec.PublicKey,
+	delivery tapfreighter.ProofDelivery) error {
+
+	anchorOutpoint, err := encodeOutpoint(anchorPoint)
+	if err != nil {
+		return err
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.SetProofDeliveryOutcome(ctx, ProofDeliveryOutcome{
+			Status:         sqlInt16(delivery.Status),
+			CourierAddr:    delivery.CourierAddr,
+			NumAttempts:    sqlInt32(delivery.NumAttempts),
+			AnchorOutpoint: anchorOutpoint,
+			ScriptKey:      scriptKey.SerializeCompressed(),
+		})
+	})
+}
+
+// PendingProofDeliveries returns the confirmed parcels that have at least one
+// output with a pending proof delivery.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) PendingProofDeliveries(
+	ctx context.Context) ([]*tapfreighter.OutboundParcel, error) {
+
+	// The pending deliveries are resumed by the porter, which needs to see
+	// its own writes, so we always query the primary database.
+	var txids [][]byte
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		var err error
+		txids, err = q.QueryPendingProofDeliveryTransfers(
+			ctx, sqlInt16(tapfreighter.ProofDeliveryStatusPending),
+		)
+		return err
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	var parcels []*tapfreighter.OutboundParcel
+	for _, txid := range txids {
+		txParcels, err := a.queryParcels(ctx, TransferQuery{
+			AnchorTxHash: txid,
+		}, &readOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		parcels = append(parcels, txParcels...)
+	}
+
+	return parcels, nil
+}
+
+// LogProofGapServe records that we answer the requests of the receiver of the
+// given proof for the proofs missing from its proof chain, so serving is
+// resumed after a restart. Recording the same proof again keeps its original
+// start time.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) LogProofGapServe(ctx context.Context,
+	serve tapfreighter.ProofGapServe) error {
+
+	key, err := proofGapServeKey(serve.Locator)
+	if err != nil {
+		return err
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.InsertProofGapServe(ctx, NewProofGapServe{
+			AssetID:   key.AssetID,
+			ScriptKey: key.ScriptKey,
+			Outpoint:  key.Outpoint,
+			StartTime: serve.StartTime.UTC(),
+		})
+	})
+}
+
+// ProofGapServes returns the proofs whose receivers might still request the
+// proofs missing from their proof chain.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) ProofGapServes(
+	ctx context.Context) ([]tapfreighter.ProofGapServe, error) {
+
+	var serves []tapfreighter.ProofGapServe
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		rows, err := q.QueryProofGapServes(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to query proof gap serves: "+
+				"%w", err)
+		}
+
+		serves = make([]tapfreighter.ProofGapServe, 0, len(rows))
+		for _, row := range rows {
+			var assetID asset.ID
+			copy(assetID[:], row.AssetID)
+
+			scriptKey, err := btcec.ParsePubKey(row.ScriptKey)
+			if err != nil {
+				return err
+			}
+
+			var outPoint wire.OutPoint
+			err = readOutPoint(
+				bytes.NewReader(row.Outpoint), 0, 0, &outPoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			serves = append(serves, tapfreighter.ProofGapServe{
+				Locator: proof.Locator{
+					AssetID:   &assetID,
+					ScriptKey: *scriptKey,
+					OutPoint:  &outPoint,
+				},
+				StartTime: row.StartTime.UTC(),
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return serves, nil
+}
+
+// RemoveProofGapServe removes the proof gap serve of the proof with the given
+// locator, once its receiver acknowledged its proof chain or the maximum serve
+// duration passed.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) RemoveProofGapServe(ctx context.Context,
+	locator proof.Locator) error {
+
+	key, err := proofGapServeKey(locator)
+	if err != nil {
+		return err
+	}
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		return q.DeleteProofGapServe(ctx, key)
+	})
+}
+
+// proofGapServeKey encodes the locator of the proof of a gap serve, which must
+// specify an asset ID and outpoint.
+func proofGapServeKey(locator proof.Locator) (ProofGapServeKey, error) {
+	if locator.AssetID == nil || locator.OutPoint == nil {
+		return ProofGapServeKey{}, fmt.Errorf("proof gap serve " +
+			"requires asset ID and outpoint")
+	}
+
+	outPoint, err := encodeOutpoint(*locator.OutPoint)
+	if err != nil {
+		return ProofGapServeKey{}, err
+	}
+
+	return ProofGapServeKey{
+		AssetID:   locator.AssetID[:],
+		ScriptKey: locator.ScriptKey.SerializeCompressed(),
+		Outpoint:  outPoint,
+	}, nil
+}
+
+// SweepableAnchors returns the confirmed anchor outputs of the node with a
+// value of at most the given value that only anchor spent assets and weren't
+// spent or swept yet.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) SweepableAnchors(ctx context.Context,
+	maxValue btcutil.Amount) ([]*tapfreighter.SweepableAnchor, error) {
+
+	var anchors []*tapfreighter.SweepableAnchor
 
 	readOpts := NewAssetStoreReadTx()
 	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
-		// If we want every unconfirmed transfer, then we only pass in
-		// the UnconfOnly field.
-		dbTransfers, err := q.QueryAssetTransfers(ctx, TransferQuery{
-			UnconfOnly: pending,
-		})
+		dbAnchors, err := q.FetchSweepableAnchors(
+			ctx, SweepableAnchorQuery{
+				MaxAmtSats: int64(maxValue),
+				Now:        sqlTime(a.clock.Now().UTC()),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		anchors = make(
+			[]*tapfreighter.SweepableAnchor, 0, len(dbAnchors),
+		)
+		for _, dbAnchor := range dbAnchors {
+			var outPoint wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(dbAnchor.Outpoint), 0, 0,
+				&outPoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			internalKey, err := btcec.ParsePubKey(dbAnchor.RawKey)
+			if err != nil {
+				return err
+			}
+
+			anchors = append(anchors, &tapfreighter.SweepableAnchor{
+				OutPoint: outPoint,
+				Value:    btcutil.Amount(dbAnchor.AmtSats),
+				InternalKey: keychain.KeyDescriptor{
+					PubKey: internalKey,
+					KeyLocator: keychain.KeyLocator{
+						Family: keychain.KeyFamily(
+							dbAnchor.KeyFamily,
+						),
+						Index: uint32(
+							dbAnchor.KeyIndex,
+						),
+					},
+				},
+				MerkleRoot: dbAnchor.MerkleRoot,
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return anchors, nil
+}
+
+// OnChainFootprint returns all UTXOs the daemon considers its own, together
+// with the assets they anchor. UTXOs that were spent by a transfer or swept
+// back into the wallet are not included.
+func (a *AssetStore) OnChainFootprint(
+	ctx context.Context) ([]*FootprintUTXO, error) {
+
+	var utxos []*FootprintUTXO
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbRows, err := q.FetchOnChainFootprint(ctx)
+		if err != nil {
+			return err
+		}
+
+		// The rows are ordered by UTXO, with one row for each asset
+		// the UTXO anchors, so we only need to look at the last UTXO
+		// to group them.
+		var (
+			utxo       *FootprintUTXO
+			lastUtxoID int64
+		)
+		for _, dbRow := range dbRows {
+			if utxo == nil || dbRow.UtxoID != lastUtxoID {
+				utxo, err = parseFootprintUTXO(dbRow)
+				if err != nil {
+					return err
+				}
+
+				lastUtxoID = dbRow.UtxoID
+				utxos = append(utxos, utxo)
+			}
+
+			// A UTXO without any assets is still returned, but
+			// only as a single row without asset columns.
+			if !dbRow.AssetAmount.Valid {
+				continue
+			}
+
+			scriptKey, err := btcec.ParsePubKey(
+				dbRow.TweakedScriptKey,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to decode script "+
+					"key: %w", err)
+			}
+
+			var assetID asset.ID
+			copy(assetID[:], dbRow.GenesisAssetID)
+
+			utxo.Assets = append(utxo.Assets, FootprintAsset{
+				ID:        assetID,
+				Amount:    uint64(dbRow.AssetAmount.Int64),
+				ScriptKey: scriptKey,
+				Spent:     dbRow.AssetSpent.Bool,
+			})
+		}
+
+		// The fee bump anchor outputs of transfers are ours as well,
+		// until a child transaction spends them.
+		feeBumpRows, err := q.FetchFeeBumpAnchorFootprint(ctx)
+		if err != nil {
+			return err
+		}
+		for _, dbRow := range feeBumpRows {
+			utxo, err := parseFeeBumpAnchorFootprint(dbRow)
+			if err != nil {
+				return err
+			}
+
+			utxos = append(utxos, utxo)
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return utxos, nil
+}
+
+// parseFootprintUTXO parses the UTXO columns of a row of the on-chain
+// footprint.
+func parseFootprintUTXO(dbRow OnChainFootprintRow) (*FootprintUTXO, error) {
+	var outPoint wire.OutPoint
+	err := readOutPoint(bytes.NewReader(dbRow.Outpoint), 0, 0, &outPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	internalKey, err := btcec.ParsePubKey(dbRow.RawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	utxo := &FootprintUTXO{
+		OutPoint:    outPoint,
+		OutputValue: btcutil.Amount(dbRow.AmtSats),
+		InternalKey: keychain.KeyDescriptor{
+			PubKey: internalKey,
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamily(dbRow.KeyFamily),
+				Index:  uint32(dbRow.KeyIndex),
+			},
+		},
+		TaprootAssetRoot: dbRow.TaprootAssetRoot,
+		MerkleRoot:       dbRow.MerkleRoot,
+		LeaseOwner:       dbRow.LeaseOwner,
+	}
+	if dbRow.BlockHeight.Valid {
+		utxo.BlockHeight = uint32(dbRow.BlockHeight.Int32)
+	}
+	if dbRow.LeaseExpiry.Valid {
+		utxo.LeaseExpiry = dbRow.LeaseExpiry.Time.UTC()
+	}
+
+	return utxo, nil
+}
+
+// parseFeeBumpAnchorFootprint parses a row of the unspent fee bump anchor
+// outputs of transfers.
+func parseFeeBumpAnchorFootprint(
+	dbRow FeeBumpAnchorFootprintRow) (*FootprintUTXO, error) {
+
+	anchorTXID, err := chainhash.NewHash(dbRow.Txid)
+	if err != nil {
+		return nil, err
+	}
+
+	feeBumpAnchor, err := parseFeeBumpAnchor(
+		dbRow.OutputIndex, dbRow.RawKey, dbRow.KeyFamily,
+		dbRow.KeyIndex,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	utxo := &FootprintUTXO{
+		OutPoint: wire.OutPoint{
+			Hash:  *anchorTXID,
+			Index: feeBumpAnchor.OutputIndex,
+		},
+		OutputValue:   btcutil.Amount(dbRow.AmtSats),
+		InternalKey:   feeBumpAnchor.InternalKey,
+		FeeBumpAnchor: true,
+	}
+	if dbRow.BlockHeight.Valid {
+		utxo.BlockHeight = uint32(dbRow.BlockHeight.Int32)
+	}
+
+	return utxo, nil
+}
+
+// LogAnchorSweep records that the anchor outputs with the given outpoints were
+// swept by the transaction with the given ID at the given time.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) LogAnchorSweep(ctx context.Context,
+	sweepTXID chainhash.Hash, anchorPoints []wire.OutPoint,
+	sweptAt time.Time) error {
+
+	var writeTxOpts AssetStoreTxOptions
+	return a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		for _, anchorPoint := range anchorPoints {
+			outpoint, err := encodeOutpoint(anchorPoint)
+			if err != nil {
+				return err
+			}
+
+			utxo, err := q.FetchManagedUTXO(ctx, UtxoQuery{
+				Outpoint: outpoint,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to fetch anchor "+
+					"output %v: %w", anchorPoint, err)
+			}
+
+			err = q.InsertAnchorSweep(ctx, NewAnchorSweep{
+				UtxoID:    utxo.UtxoID,
+				SweepTxid: sweepTXID[:],
+				SweptAt:   sweptAt.UTC(),
+			})
+			if err != nil {
+				return fmt.Errorf("unable to record sweep of "+
+					"anchor output %v: %w", anchorPoint,
+					err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// AnchorSweeps returns all anchor outputs that were swept.
+//
+// NOTE: This implements the tapfreighter.ExportLog interface.
+func (a *AssetStore) AnchorSweeps(
+	ctx context.Context) ([]*tapfreighter.AnchorSweep, error) {
+
+	var sweeps []*tapfreighter.AnchorSweep
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbSweeps, err := q.QueryAnchorSweeps(ctx)
+		if err != nil {
+			return err
+		}
+
+		sweeps = make([]*tapfreighter.AnchorSweep, 0, len(dbSweeps))
+		for _, dbSweep := range dbSweeps {
+			var outPoint wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(dbSweep.Outpoint), 0, 0,
+				&outPoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			sweepTXID, err := chainhash.NewHash(dbSweep.SweepTxid)
+			if err != nil {
+				return err
+			}
+
+			sweeps = append(sweeps, &tapfreighter.AnchorSweep{
+				OutPoint:  outPoint,
+				Value:     btcutil.Amount(dbSweep.AmtSats),
+				SweepTXID: *sweepTXID,
+				SweptAt:   dbSweep.SweptAt.UTC(),
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return sweeps, nil
+}
+
+// QueryParcels returns the set of confirmed or unconfirmed parcels. If the
+// context allows it, the query may be served by a read replica, so recent
+// transfers might be missing.
+func (a *AssetStore) QueryParcels(ctx context.Context,
+	pending bool) ([]*tapfreighter.OutboundParcel, error) {
+
+	// If we want every unconfirmed transfer, then we only pass in the
+	// UnconfOnly field.
+	readOpts := NewContextReadTx(ctx)
+	return a.queryParcels(ctx, TransferQuery{
+		UnconfOnly: pending,
+	}, &readOpts)
+}
+
+// QueryParcelsByScriptKey returns the set of parcels that spent an input or
+// created an output with the given script key, which is matched in its x-only
+// form. If the context allows it, the query may be served by a read replica,
+// so recent transfers might be missing.
+func (a *AssetStore) QueryParcelsByScriptKey(ctx context.Context,
+	scriptKey *btcec.PublicKey) ([]*tapfreighter.OutboundParcel, error) {
+
+	readOpts := NewContextReadTx(ctx)
+	return a.queryParcels(ctx, TransferQuery{
+		ScriptKey: schnorr.SerializePubKey(scriptKey),
+	}, &readOpts)
+}
+
+// QueryParcelsByLabel returns the set of parcels whose label contains the given
+// substring, ignoring case. If the context allows it, the query may be served
+// by a read replica, so recent transfers might be missing.
+func (a *AssetStore) QueryParcelsByLabel(ctx context.Context,
+	labelSubstr string) ([]*tapfreighter.OutboundParcel, error) {
+
+	readOpts := NewContextReadTx(ctx)
+	return a.queryParcels(ctx, TransferQuery{
+		LabelSubstr: sqlLikeSubstr(labelSubstr),
+	}, &readOpts)
+}
+
+// queryParcels returns the set of parcels that match the given query, using a
+// read transaction with the given options.
+func (a *AssetStore) queryParcels(ctx context.Context, query TransferQuery,
+	readOpts TxOptions) ([]*tapfreighter.OutboundParcel, error) {
+
+	var transfers []*tapfreighter.OutboundParcel
+
+	dbErr := a.db.ExecTx(ctx, readOpts, func(q ActiveAssetsStore) error {
+		dbTransfers, err := q.QueryAssetTransfers(ctx, query)
 		if err != nil {
 			return err
 		}
@@ -2752,6 +5105,18 @@ func (a *AssetStore) QueryParcels(ctx context.Context,
 					"outputs: %w", err)
 			}
 
+			// Transfers that were stored before the asset ID of
+			// their outputs was recorded only ever moved a single
+			// asset ID, the one of their inputs.
+			for outIdx := range outputs {
+				out := &outputs[outIdx]
+				if out.AssetID == (asset.ID{}) &&
+					len(inputs) > 0 {
+
+					out.AssetID = inputs[0].PrevID.ID
+				}
+			}
+
 			// We know that the anchor transaction is the same for
 			// each output, we can just fetch the first.
 			if len(outputs) == 0 {
@@ -2779,9 +5144,38 @@ func (a *AssetStore) QueryParcels(ctx context.Context,
 				AnchorTxHeightHint: uint32(dbT.HeightHint),
 				TransferTime:       dbT.TransferTimeUnix.UTC(),
 				ChainFees:          dbAnchorTx.ChainFees,
+				Staged:             dbT.Staged,
+				Cancelled:          dbT.Cancelled,
+				Label:              dbT.Label,
+				IdempotencyKey:     dbT.IdempotencyKey.String,
 				Inputs:             inputs,
 				Outputs:            outputs,
 			}
+			if dbT.ConfirmationTimeUnix.Valid {
+				transfer.ConfirmationTime =
+					dbT.ConfirmationTimeUnix.Time.UTC()
+			}
+			if dbT.DeliveryCompleteTimeUnix.Valid {
+				transfer.DeliveryCompleteTime =
+					dbT.DeliveryCompleteTimeUnix.Time.UTC()
+			}
+			if len(dbT.ClaimID) == 32 {
+				var claimID [32]byte
+				copy(claimID[:], dbT.ClaimID)
+				transfer.ClaimID = &claimID
+			}
+
+			err = fetchTransferFeeBumpAnchor(
+				ctx, q, dbT.ID, transfer,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = fetchTransferAnchorTxs(ctx, q, dbT.ID, transfer)
+			if err != nil {
+				return err
+			}
 			transfers = append(transfers, transfer)
 		}
 
@@ -2814,6 +5208,9 @@ func (a *AssetStore) FetchAssetMetaForAsset(ctx context.Context,
 		assetMeta = &proof.MetaReveal{
 			Data: dbMeta.MetaDataBlob,
 			Type: proof.MetaType(dbMeta.MetaDataType.Int16),
+			SupplyCap: extractSqlInt64[uint64](
+				dbMeta.MetaSupplyCap,
+			),
 		}
 
 		return nil
@@ -2844,6 +5241,9 @@ func (a *AssetStore) FetchAssetMetaByHash(ctx context.Context,
 		assetMeta = &proof.MetaReveal{
 			Data: dbMeta.MetaDataBlob,
 			Type: proof.MetaType(dbMeta.MetaDataType.Int16),
+			SupplyCap: extractSqlInt64[uint64](
+				dbMeta.MetaSupplyCap,
+			),
 		}
 
 		return nil
@@ -2858,6 +5258,75 @@ func (a *AssetStore) FetchAssetMetaByHash(ctx context.Context,
 	return assetMeta, nil
 }
 
+// AssetGenesisMeta is the genesis of an asset together with the meta data it
+// reveals.
+type AssetGenesisMeta struct {
+	// Genesis is the genesis of the asset.
+	Genesis asset.Genesis
+
+	// Meta is the meta data of the asset.
+	Meta proof.MetaReveal
+}
+
+// FetchGenesesWithMeta returns the genesis and meta data of all assets known
+// to the daemon that have meta data. This includes assets that were only
+// synced from a universe and aren't owned by the daemon.
+func (a *AssetStore) FetchGenesesWithMeta(
+	ctx context.Context) ([]AssetGenesisMeta, error) {
+
+	var geneses []AssetGenesisMeta
+
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		dbGeneses, err := q.FetchGenesesWithMeta(ctx)
+		if err != nil {
+			return err
+		}
+
+		geneses = make([]AssetGenesisMeta, 0, len(dbGeneses))
+		for _, dbGen := range dbGeneses {
+			var genesisPrevOut wire.OutPoint
+			err := readOutPoint(
+				bytes.NewReader(dbGen.PrevOut), 0, 0,
+				&genesisPrevOut,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to read outpoint: "+
+					"%w", err)
+			}
+
+			var metaHash [asset.MetaHashLen]byte
+			copy(metaHash[:], dbGen.MetaDataHash)
+
+			geneses = append(geneses, AssetGenesisMeta{
+				Genesis: asset.Genesis{
+					FirstPrevOut: genesisPrevOut,
+					Tag:          dbGen.AssetTag,
+					MetaHash:     metaHash,
+					OutputIndex:  uint32(dbGen.OutputIndex),
+					Type:         asset.Type(dbGen.AssetType),
+				},
+				Meta: proof.MetaReveal{
+					Data: dbGen.MetaDataBlob,
+					Type: proof.MetaType(
+						dbGen.MetaDataType.Int16,
+					),
+					SupplyCap: extractSqlInt64[uint64](
+						dbGen.MetaSupplyCap,
+					),
+				},
+			})
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	return geneses, nil
+}
+
 // A compile-time constraint to ensure that AssetStore meets the
 // proof.NotifyArchiver interface.
 var _ proof.NotifyArchiver = (*AssetStore)(nil)
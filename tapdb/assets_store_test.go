@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"database/sql"
+	"fmt"
 	"math/rand"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -21,7 +26,9 @@ import (
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
 	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/stretchr/testify/require"
@@ -277,7 +284,14 @@ func TestImportAssetProof(t *testing.T) {
 		Hash:  anchorTx.TxHash(),
 		Index: 0,
 	}
-	initialBlob := bytes.Repeat([]byte{0x0}, 100)
+
+	// The initial proof is a real proof file, so its genesis proof anchors
+	// the genesis point of the asset on import.
+	genesisProof := randProof(t)
+	genesisProof.Asset = *testAsset.Copy()
+	genesisProof.BlockHeight = test.RandInt[uint32]()
+	initialBlob, err := proof.EncodeAsProofFile(genesisProof)
+	require.NoError(t, err)
 	updatedBlob := bytes.Repeat([]byte{0x77}, 100)
 	testProof := &proof.AnnotatedProof{
 		Locator: proof.Locator{
@@ -291,6 +305,7 @@ func TestImportAssetProof(t *testing.T) {
 			AnchorBlockHash:   blockHash,
 			AnchorBlockHeight: test.RandInt[uint32](),
 			AnchorTxIndex:     test.RandInt[uint32](),
+			AnchorBlockTime:   time.Unix(1_700_000_000, 0).UTC(),
 			AnchorTx:          anchorTx,
 			OutputIndex:       0,
 			InternalKey:       test.RandPubKey(t),
@@ -365,13 +380,16 @@ func TestImportAssetProof(t *testing.T) {
 	)
 	require.Equal(t, testProof.AnchorTx.TxHash(), dbAsset.AnchorTx.TxHash())
 
+	// The asset was acquired with the block that anchors it.
+	require.Equal(t, testProof.AnchorBlockTime, dbAsset.AcquiredAt)
+
 	// We should also be able to fetch the proof we just inserted using the
 	// script key of the new asset.
 	currentBlob, err := assetStore.FetchProof(ctxb, proof.Locator{
 		ScriptKey: *testAsset.ScriptKey.PubKey,
 	})
 	require.NoError(t, err)
-	require.Equal(t, initialBlob, []byte(currentBlob))
+	require.Equal(t, initialBlob, currentBlob)
 
 	// We should also be able to fetch the created asset above based on
 	// either the asset ID, or key group via the main coin selection
@@ -388,6 +406,10 @@ func TestImportAssetProof(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, selectedAssets, 1)
 	assertAssetEqual(t, testAsset, selectedAssets[0].Asset)
+	require.Equal(
+		t, genesisProof.BlockHeight,
+		selectedAssets[0].GenesisBlockHeight,
+	)
 
 	// We'll now attempt to overwrite the proof with one that has different
 	// block information (simulating a re-org).
@@ -908,6 +930,81 @@ func TestUTXOLeases(t *testing.T) {
 	}
 }
 
+// TestListReleaseLeases tests that active leases can be listed and only be
+// released by their lease owner.
+func TestListReleaseLeases(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	const numAssets = 2
+	assetGen := newAssetGenerator(t, numAssets, 2)
+	assetGen.genAssets(t, assetsStore, []assetDesc{
+		{
+			assetGen:    assetGen.assetGens[0],
+			anchorPoint: assetGen.anchorPoints[0],
+
+			amt: 16,
+		},
+		{
+			assetGen:    assetGen.assetGens[1],
+			anchorPoint: assetGen.anchorPoints[1],
+
+			amt: 10,
+		},
+	})
+
+	// Without any leases, there's nothing to list.
+	leases, err := assetsStore.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Empty(t, leases)
+
+	// We lease the first anchor point for an hour and the second one with
+	// an expiry in the past, which means it shouldn't be listed.
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	leaseExpiry := time.Now().Add(time.Hour)
+	err = assetsStore.LeaseCoins(
+		ctx, leaseOwner, leaseExpiry, assetGen.anchorPoints[0],
+	)
+	require.NoError(t, err)
+	err = assetsStore.LeaseCoins(
+		ctx, leaseOwner, time.Now().Add(-time.Hour),
+		assetGen.anchorPoints[1],
+	)
+	require.NoError(t, err)
+
+	leases, err = assetsStore.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+	require.Equal(t, assetGen.anchorPoints[0], leases[0].OutPoint)
+	require.Equal(t, leaseOwner, leases[0].LeaseOwner)
+	require.Equal(t, leaseExpiry.Unix(), leases[0].Expiry.Unix())
+	require.Nil(t, leases[0].TransferAnchorTxID)
+
+	// A lease can't be released by a different owner.
+	otherOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	err = assetsStore.ReleaseLease(
+		ctx, otherOwner, assetGen.anchorPoints[0],
+	)
+	require.ErrorIs(t, err, tapfreighter.ErrLeaseNotFound)
+
+	err = assetsStore.ReleaseLease(
+		ctx, leaseOwner, assetGen.anchorPoints[0],
+	)
+	require.NoError(t, err)
+
+	leases, err = assetsStore.ListLeases(ctx)
+	require.NoError(t, err)
+	require.Empty(t, leases)
+
+	// Releasing it again fails, as there's no lease anymore.
+	err = assetsStore.ReleaseLease(
+		ctx, leaseOwner, assetGen.anchorPoints[0],
+	)
+	require.ErrorIs(t, err, tapfreighter.ErrLeaseNotFound)
+}
+
 // TestSelectCommitment tests that the coin selection logic can properly select
 // assets from a canned set that meet the specified set of constraints.
 func TestSelectCommitment(t *testing.T) {
@@ -1225,10 +1322,14 @@ func TestAssetExportLog(t *testing.T) {
 	// used to commit a new spend on disk.
 	inputAsset := allAssets[0]
 	anchorTxHash := newAnchorTx.TxHash()
+	var claimID [32]byte
+	test.RandRead(t, claimID[:])
 	spendDelta := &tapfreighter.OutboundParcel{
 		AnchorTx:           newAnchorTx,
 		AnchorTxHeightHint: heightHint,
 		ChainFees:          chainFees,
+		Staged:             true,
+		ClaimID:            &claimID,
 		// We'll actually modify only one of the assets. This simulates
 		// us create a split of the asset to send to another party.
 		Inputs: []tapfreighter.TransferInput{{
@@ -1389,18 +1490,30 @@ func TestAssetExportLog(t *testing.T) {
 	require.Equal(t, 1, len(parcels))
 	require.Equal(t, spendDelta, parcels[0])
 
+	// The parcel was logged as staged, so we should be able to release it
+	// exactly once.
+	require.NoError(t, assetsStore.UnstageParcel(ctx, anchorTxHash))
+	require.Error(t, assetsStore.UnstageParcel(ctx, anchorTxHash))
+
+	parcels, err = assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(parcels))
+	require.False(t, parcels[0].Staged)
+
 	// With the asset delta committed and verified, we'll now mark the
 	// delta as being confirmed on chain.
 	fakeBlockHash := chainhash.Hash(sha256.Sum256([]byte("fake")))
 	blockHeight := int32(100)
 	txIndex := int32(10)
+	confTime := time.Unix(1_700_000_000, 0).UTC()
 	err = assetsStore.ConfirmParcelDelivery(
 		ctx, &tapfreighter.AssetConfirmEvent{
-			AnchorTXID:  firstOutputAnchor.OutPoint.Hash,
-			TxIndex:     txIndex,
-			BlockHeight: blockHeight,
-			BlockHash:   fakeBlockHash,
-			FinalProofs: proofs,
+			AnchorTXID:       firstOutputAnchor.OutPoint.Hash,
+			TxIndex:          txIndex,
+			BlockHeight:      blockHeight,
+			BlockHash:        fakeBlockHash,
+			ConfirmationTime: confTime,
+			FinalProofs:      proofs,
 		},
 	)
 	require.NoError(t, err)
@@ -1500,149 +1613,1644 @@ func TestAssetExportLog(t *testing.T) {
 	parcels, err = assetsStore.PendingParcels(ctx)
 	require.NoError(t, err)
 	require.Equal(t, 0, len(parcels))
+
+	// The confirmed parcel should have its confirmation and delivery
+	// completion times recorded.
+	parcels, err = assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(parcels))
+	require.Equal(t, confTime.Unix(), parcels[0].ConfirmationTime.Unix())
+	require.False(t, parcels[0].DeliveryCompleteTime.IsZero())
+
+	// A confirmed parcel can't be cancelled or replaced anymore.
+	err = assetsStore.CancelParcel(ctx, anchorTxHash)
+	require.ErrorIs(t, err, tapfreighter.ErrTransferConfirmed)
+
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, anchorTxHash, &tapfreighter.AnchorTransaction{
+			FinalTx: wire.NewMsgTx(2),
+		}, false,
+	)
+	require.ErrorIs(t, err, tapfreighter.ErrTransferConfirmed)
 }
 
-// TestAssetGroupSigUpsert tests that if you try to insert another asset
-// group sig with the same asset_gen_id, then only one is actually created.
-func TestAssetGroupSigUpsert(t *testing.T) {
-	t.Parallel()
+// logTestParcel logs a pending parcel with the given label that spends the
+// given input asset and creates a single output to a local script key.
+func logTestParcel(t *testing.T, assetsStore *AssetStore,
+	inputAsset *ChainAsset, inputPoint wire.OutPoint,
+	label string) *tapfreighter.OutboundParcel {
 
-	_, _, db := newAssetStore(t)
 	ctx := context.Background()
 
-	internalKey := test.RandPubKey(t)
+	parcel := newTestParcel(t, inputAsset, inputPoint, label)
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
 
-	// First, we'll insert all the required rows we need to satisfy the
-	// foreign key constraints needed to insert a new genesis sig.
-	keyID, err := db.UpsertInternalKey(ctx, InternalKey{
-		RawKey: internalKey.SerializeCompressed(),
+	return parcel
+}
+
+// newTestParcel creates a parcel with the given label that spends the given
+// input asset and creates a single output to a local script key.
+func newTestParcel(t *testing.T, inputAsset *ChainAsset,
+	inputPoint wire.OutPoint, label string) *tapfreighter.OutboundParcel {
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: inputPoint,
+		SignatureScript:  []byte{},
+	})
+	anchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x01}, 34),
+		Value:    1000,
+	})
+	anchorTxHash := anchorTx.TxHash()
+
+	parcel := &tapfreighter.OutboundParcel{
+		AnchorTx:           anchorTx,
+		AnchorTxHeightHint: 1450,
+		Label:              label,
+		Inputs: []tapfreighter.TransferInput{{
+			PrevID: asset.PrevID{
+				OutPoint: inputPoint,
+				ID:       inputAsset.ID(),
+				ScriptKey: asset.ToSerialized(
+					inputAsset.ScriptKey.PubKey,
+				),
+			},
+			Amount: inputAsset.Amount,
+		}},
+		Outputs: []tapfreighter.TransferOutput{{
+			Anchor: tapfreighter.Anchor{
+				Value: 1000,
+				OutPoint: wire.OutPoint{
+					Hash: anchorTxHash,
+				},
+				InternalKey: keychain.KeyDescriptor{
+					PubKey: test.RandPubKey(t),
+				},
+				TaprootAssetRoot: bytes.Repeat([]byte{0x1}, 32),
+				MerkleRoot:       bytes.Repeat([]byte{0x1}, 32),
+			},
+			ScriptKey: asset.NewScriptKeyBip86(
+				keychain.KeyDescriptor{
+					PubKey: test.RandPubKey(t),
+				},
+			),
+			ScriptKeyLocal: true,
+			Amount:         inputAsset.Amount,
+			WitnessData: []asset.Witness{{
+				PrevID:    &asset.PrevID{},
+				TxWitness: [][]byte{{0x01}},
+			}},
+			AssetVersion: asset.V0,
+			ProofSuffix:  bytes.Repeat([]byte{0x01}, 100),
+		}},
+	}
+
+	return parcel
+}
+
+// TestConcurrentParcelsSqlite tests that several transfers can be logged
+// concurrently to a SQLite database without running into lock contention.
+func TestConcurrentParcelsSqlite(t *testing.T) {
+	t.Parallel()
+
+	const numParcels = 10
+
+	db, err := NewSqliteStore(&SqliteConfig{
+		DatabaseFileName:   filepath.Join(t.TempDir(), "tmp.db"),
+		MaxOpenConnections: numParcels,
+		JournalMode:        DefaultSqliteJournalMode,
+		BusyTimeout:        DefaultSqliteBusyTimeout,
 	})
 	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
 
-	genesisPointID, err := upsertGenesisPoint(ctx, db, test.RandOp(t))
+	assetsDB := NewTransactionExecutor(
+		db, func(tx *sql.Tx) ActiveAssetsStore {
+			return db.WithTx(tx)
+		},
+	)
+	assetsStore := NewAssetStore(assetsDB, clock.NewTestClock(time.Now()))
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, numParcels, 1)
+	descs := make([]assetDesc, numParcels)
+	for i := range descs {
+		descs[i] = assetDesc{
+			assetGen:    assetGen.assetGens[i],
+			anchorPoint: assetGen.anchorPoints[i],
+			amt:         16,
+		}
+	}
+	assetGen.genAssets(t, assetsStore, descs)
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
 	require.NoError(t, err)
+	require.Len(t, allAssets, numParcels)
 
-	genAssetID, err := upsertGenesis(
-		ctx, db, genesisPointID, asset.RandGenesis(t, asset.Normal),
+	parcels := make([]*tapfreighter.OutboundParcel, numParcels)
+	for i, inputAsset := range allAssets {
+		parcels[i] = newTestParcel(
+			t, inputAsset, inputAsset.AnchorOutpoint,
+			fmt.Sprintf("parcel %d", i),
+		)
+	}
+
+	// All transfers are logged at once, none of them may fail because the
+	// database is locked by another one.
+	err = fn.ParSliceLimit(
+		ctx, numParcels, parcels,
+		func(ctx context.Context,
+			parcel *tapfreighter.OutboundParcel) error {
+
+			leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+			return assetsStore.LogPendingParcel(
+				ctx, parcel, leaseOwner,
+				time.Now().Add(time.Hour),
+			)
+		},
 	)
 	require.NoError(t, err)
 
-	groupID, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
-		TweakedGroupKey: internalKey.SerializeCompressed(),
-		InternalKeyID:   keyID,
-		GenesisPointID:  genesisPointID,
-	})
+	pendingParcels, err := assetsStore.PendingParcels(ctx)
 	require.NoError(t, err)
+	require.Len(t, pendingParcels, numParcels)
+}
 
-	// With all the other items inserted, we'll now insert an asset group
-	// sig.
-	groupSigID, err := db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
-		WitnessStack: []byte{0x01},
-		GenAssetID:   genAssetID,
-		GroupKeyID:   groupID,
-	})
+// TestParcelLabel tests that the label of a parcel is stored with it and that
+// parcels can be queried by a substring of their label.
+func TestParcelLabel(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
 	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
 
-	// If we insert the very same sig, then we should get the same group sig
-	// ID back.
-	groupSigID2, err := db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
-		WitnessStack: []byte{0x01},
-		GenAssetID:   genAssetID,
-		GroupKeyID:   groupID,
-	})
+	const label = "Payroll_March 100%"
+	parcel := logTestParcel(
+		t, assetsStore, allAssets[0], assetGen.anchorPoints[0], label,
+	)
+
+	parcels, err := assetsStore.QueryParcels(ctx, false)
 	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.Equal(t, label, parcels[0].Label)
+
+	// Any substring of the label selects the parcel, regardless of its
+	// case. Wildcard characters only match themselves.
+	filters := []string{label, "payroll", "MARCH", "l_m", "100%"}
+	for _, filter := range filters {
+		parcels, err = assetsStore.QueryParcelsByLabel(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, parcels, 1, filter)
+		require.Equal(
+			t, parcel.AnchorTx.TxHash(),
+			parcels[0].AnchorTx.TxHash(),
+		)
+	}
 
-	require.Equal(t, groupSigID, groupSigID2)
+	for _, filter := range []string{"refund", "l%m", "payroll_m_", `\`} {
+		parcels, err = assetsStore.QueryParcelsByLabel(ctx, filter)
+		require.NoError(t, err)
+		require.Empty(t, parcels, filter)
+	}
 }
 
-// TestAssetGroupKeyUpsert tests that if you try to insert another asset group
-// key with the same tweaked_group_key, then only one is actually created.
-func TestAssetGroupKeyUpsert(t *testing.T) {
+// TestParcelsByScriptKey tests that parcels and assets can be queried by a
+// script key they spend or create, which is matched in its x-only form.
+func TestParcelsByScriptKey(t *testing.T) {
 	t.Parallel()
 
-	_, _, db := newAssetStore(t)
+	_, assetsStore, _ := newAssetStore(t)
 	ctx := context.Background()
 
-	internalKey := test.RandPubKey(t)
-	groupKey := internalKey.SerializeCompressed()
-	keyIndex := test.RandInt[int32]()
-	scriptRoot := test.RandBytes(32)
-	witness := test.RandBytes(64)
+	const numAssets = 2
+	assetGen := newAssetGenerator(t, numAssets, 1)
+	descs := make([]assetDesc, numAssets)
+	for i := range descs {
+		descs[i] = assetDesc{
+			assetGen:    assetGen.assetGens[i],
+			anchorPoint: assetGen.anchorPoints[i],
+			amt:         16,
+		}
+	}
+	assetGen.genAssets(t, assetsStore, descs)
 
-	// First, we'll insert all the required rows we need to satisfy the
-	// foreign key constraints needed to insert a new genesis sig.
-	keyID, err := db.UpsertInternalKey(ctx, InternalKey{
-		RawKey:    groupKey,
-		KeyFamily: asset.TaprootAssetsKeyFamily,
-		KeyIndex:  keyIndex,
-	})
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
 	require.NoError(t, err)
+	require.Len(t, allAssets, numAssets)
 
-	genesisPointID, err := upsertGenesisPoint(ctx, db, test.RandOp(t))
-	require.NoError(t, err)
+	parcels := make([]*tapfreighter.OutboundParcel, numAssets)
+	for i, inputAsset := range allAssets {
+		parcels[i] = logTestParcel(
+			t, assetsStore, inputAsset, inputAsset.AnchorOutpoint,
+			fmt.Sprintf("parcel %d", i),
+		)
+	}
 
-	// Now we'll insert an asset group key. We use a non-empty tapscript
-	// root to test that we can fetch the tapscript root after the upsert,
-	// even though we only store empty tapscript roots currently.
-	groupID, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
-		TweakedGroupKey: groupKey,
-		TapscriptRoot:   scriptRoot,
-		InternalKeyID:   keyID,
-		GenesisPointID:  genesisPointID,
-	})
-	require.NoError(t, err)
+	// evenKey returns the key with the same x coordinate as the given key
+	// and an even y coordinate, so the parity of the stored key doesn't
+	// matter.
+	evenKey := func(key *btcec.PublicKey) *btcec.PublicKey {
+		evenKey, err := schnorr.ParsePubKey(
+			schnorr.SerializePubKey(key),
+		)
+		require.NoError(t, err)
 
-	// If we insert the very same group key, then we should get the same
-	// group ID back.
-	groupID2, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
-		TweakedGroupKey: groupKey,
-		TapscriptRoot:   scriptRoot,
-		InternalKeyID:   keyID,
-		GenesisPointID:  genesisPointID,
-	})
-	require.NoError(t, err)
+		return evenKey
+	}
+	requireParcel := func(key *btcec.PublicKey,
+		parcel *tapfreighter.OutboundParcel) {
 
-	require.Equal(t, groupID, groupID2)
+		dbParcels, err := assetsStore.QueryParcelsByScriptKey(
+			ctx, evenKey(key),
+		)
+		require.NoError(t, err)
+		require.Len(t, dbParcels, 1)
+		require.Equal(
+			t, parcel.AnchorTx.TxHash(),
+			dbParcels[0].AnchorTx.TxHash(),
+		)
+	}
 
-	// Insert a genesis and group sig to fill out the group key view.
-	genAssetID, err := upsertGenesis(
-		ctx, db, genesisPointID, asset.RandGenesis(t, asset.Normal),
-	)
-	require.NoError(t, err)
+	// Each parcel is selected by the script key of its input as well as
+	// the one of its output.
+	for i, inputAsset := range allAssets {
+		outputKey := parcels[i].Outputs[0].ScriptKey.PubKey
+		requireParcel(inputAsset.ScriptKey.PubKey, parcels[i])
+		requireParcel(outputKey, parcels[i])
+	}
 
-	_, err = db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
-		WitnessStack: witness,
-		GenAssetID:   genAssetID,
-		GroupKeyID:   groupID,
-	})
+	// An unknown script key doesn't select any parcel.
+	dbParcels, err := assetsStore.QueryParcelsByScriptKey(
+		ctx, test.RandPubKey(t),
+	)
 	require.NoError(t, err)
+	require.Empty(t, dbParcels)
 
-	// If we fetch the group key, it should match the inserted fields.
-	groupInfo, err := db.FetchGroupByGroupKey(ctx, groupKey[:])
-	require.NoError(t, err)
+	// The assets are selected by their script key in the same way.
+	for _, inputAsset := range allAssets {
+		dbAssets, err := assetsStore.FetchAssetsByScriptKey(
+			ctx, evenKey(inputAsset.ScriptKey.PubKey),
+		)
+		require.NoError(t, err)
+		require.Len(t, dbAssets, 1)
+		require.Equal(
+			t, inputAsset.AnchorOutpoint,
+			dbAssets[0].AnchorOutpoint,
+		)
+	}
 
-	require.Equal(t, genAssetID, groupInfo.GenAssetID)
-	require.Equal(t, groupKey, groupInfo.RawKey)
-	require.EqualValues(
-		t, asset.TaprootAssetsKeyFamily, groupInfo.KeyFamily,
+	dbAssets, err := assetsStore.FetchAssetsByScriptKey(
+		ctx, test.RandPubKey(t),
 	)
-	require.Equal(t, keyIndex, groupInfo.KeyIndex)
-	require.Equal(t, scriptRoot, groupInfo.TapscriptRoot)
-	require.Equal(t, witness, groupInfo.WitnessStack)
+	require.NoError(t, err)
+	require.Empty(t, dbAssets)
 }
 
-// TestFetchGroupedAssets tests that the FetchGroupedAssets query corectly
-// excludes assets with nil group keys, groups assets with matching group
-// keys, and returns other asset fields accurately.
-func TestFetchGroupedAssets(t *testing.T) {
+// TestParcelIdempotencyKey tests that a parcel can be queried by the
+// idempotency key it was requested with, as long as it was requested within the
+// given time window and wasn't cancelled.
+func TestParcelIdempotencyKey(t *testing.T) {
 	t.Parallel()
 
 	_, assetsStore, _ := newAssetStore(t)
 	ctx := context.Background()
 
-	// Make four assets and create only two group keys. We want one asset
-	// with no group, one group with only one asset, and two assets to be
-	// in the same group.
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	const key = "send-1234"
+	transferTime := time.Now().UTC().Truncate(time.Second)
+	parcel := newTestParcel(t, allAssets[0], assetGen.anchorPoints[0], "")
+	parcel.IdempotencyKey = key
+	parcel.TransferTime = transferTime
+
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+	anchorTxHash := parcel.AnchorTx.TxHash()
+
+	// The parcel is found by its key if it was requested within the time
+	// window.
+	dbParcel, err := assetsStore.QueryParcelByIdempotencyKey(
+		ctx, key, transferTime.Add(-time.Hour),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, dbParcel)
+	require.Equal(t, anchorTxHash, dbParcel.AnchorTx.TxHash())
+	require.Equal(t, key, dbParcel.IdempotencyKey)
+
+	dbParcel, err = assetsStore.QueryParcelByIdempotencyKey(
+		ctx, key, transferTime.Add(time.Hour),
+	)
+	require.NoError(t, err)
+	require.Nil(t, dbParcel)
+
+	dbParcel, err = assetsStore.QueryParcelByIdempotencyKey(
+		ctx, "send-5678", transferTime.Add(-time.Hour),
+	)
+	require.NoError(t, err)
+	require.Nil(t, dbParcel)
+
+	// An empty key never selects a parcel.
+	_, err = assetsStore.QueryParcelByIdempotencyKey(
+		ctx, "", transferTime.Add(-time.Hour),
+	)
+	require.ErrorContains(t, err, "must be set")
+
+	// Once the parcel is cancelled, the key can be used for a new one.
+	require.NoError(t, assetsStore.CancelParcel(ctx, anchorTxHash))
+
+	dbParcel, err = assetsStore.QueryParcelByIdempotencyKey(
+		ctx, key, transferTime.Add(-time.Hour),
+	)
+	require.NoError(t, err)
+	require.Nil(t, dbParcel)
+}
+
+// TestCancelParcel tests that a pending parcel can be cancelled, which releases
+// its inputs and stops it from being resumed.
+func TestCancelParcel(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	inputAsset := allAssets[0]
+
+	parcel := logTestParcel(
+		t, assetsStore, inputAsset, assetGen.anchorPoints[0], "",
+	)
+	anchorTxHash := parcel.AnchorTx.TxHash()
+
+	// The input of the pending parcel is leased, so it isn't available for
+	// coin selection.
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Empty(t, allAssets)
+
+	// Only parcels that exist can be cancelled.
+	err = assetsStore.CancelParcel(ctx, chainhash.Hash{})
+	require.ErrorContains(t, err, "no transfer found")
+
+	// Once the parcel is cancelled, its input is released and it is no
+	// longer pending, but it is still part of the transfer history.
+	require.NoError(t, assetsStore.CancelParcel(ctx, anchorTxHash))
+
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	pendingParcels, err := assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pendingParcels)
+
+	parcels, err := assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.True(t, parcels[0].Cancelled)
+
+	// A parcel can only be cancelled once.
+	err = assetsStore.CancelParcel(ctx, anchorTxHash)
+	require.ErrorContains(t, err, "already cancelled")
+}
+
+// TestProofDeliveryOutcome tests that the outcome of a proof delivery is
+// recorded for a transfer output and returned with the transfer history.
+func TestProofDeliveryOutcome(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	parcel := logTestParcel(
+		t, assetsStore, allAssets[0], assetGen.anchorPoints[0], "",
+	)
+	out := parcel.Outputs[0]
+
+	// Before any delivery was attempted, there is no outcome recorded.
+	parcels, err := assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.Equal(
+		t, tapfreighter.ProofDelivery{},
+		parcels[0].Outputs[0].ProofDelivery,
+	)
+
+	delivery := tapfreighter.ProofDelivery{
+		Status:      tapfreighter.ProofDeliveryStatusDelivered,
+		CourierAddr: []byte("hashmail://localhost:10029"),
+		NumAttempts: 2,
+	}
+	err = assetsStore.LogProofDelivery(
+		ctx, out.Anchor.OutPoint, out.ScriptKey.PubKey, delivery,
+	)
+	require.NoError(t, err)
+
+	// Once the receiver returned a delivery receipt, it shows up as well.
+	digest := sha256.Sum256(test.RandBytes(32))
+	sig, err := schnorr.Sign(test.RandPrivKey(t), digest[:])
+	require.NoError(t, err)
+
+	assetID := allAssets[0].ID()
+	err = assetsStore.StoreProofDeliveryReceipt(ctx, proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: *out.ScriptKey.PubKey,
+		OutPoint:  &out.Anchor.OutPoint,
+	}, &proof.DeliveryReceipt{
+		ProofHash:    sha256.Sum256(test.RandBytes(100)),
+		RawScriptKey: test.RandPubKey(t),
+		TapTweak:     test.RandBytes(32),
+		Signature:    sig,
+	})
+	require.NoError(t, err)
+
+	parcels, err = assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+
+	delivery.ReceiptReceived = true
+	require.Equal(t, delivery, parcels[0].Outputs[0].ProofDelivery)
+}
+
+// TestPendingProofDeliveries tests that only the confirmed parcels with an
+// output whose proof delivery is pending are returned for resumption.
+func TestPendingProofDeliveries(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	parcel := logTestParcel(
+		t, assetsStore, allAssets[0], assetGen.anchorPoints[0], "",
+	)
+	out := parcel.Outputs[0]
+
+	err = assetsStore.LogProofDelivery(
+		ctx, out.Anchor.OutPoint, out.ScriptKey.PubKey,
+		tapfreighter.ProofDelivery{
+			Status: tapfreighter.ProofDeliveryStatusPending,
+		},
+	)
+	require.NoError(t, err)
+
+	// As long as the transfer isn't confirmed, its deliveries are resumed
+	// together with the transfer itself.
+	parcels, err := assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Empty(t, parcels)
+
+	anchorTxHash := parcel.AnchorTx.TxHash()
+	finalProofs := map[asset.SerializedKey]*proof.AnnotatedProof{
+		asset.ToSerialized(out.ScriptKey.PubKey): {
+			Blob: bytes.Repeat([]byte{0x1}, 100),
+		},
+	}
+	err = assetsStore.ConfirmParcelDelivery(
+		ctx, &tapfreighter.AssetConfirmEvent{
+			AnchorTXID:  anchorTxHash,
+			BlockHash:   chainhash.Hash{1},
+			BlockHeight: 1500,
+			TxIndex:     1,
+			FinalProofs: finalProofs,
+		},
+	)
+	require.NoError(t, err)
+
+	parcels, err = assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.Equal(t, anchorTxHash, parcels[0].AnchorTx.TxHash())
+	require.Equal(
+		t, tapfreighter.ProofDeliveryStatusPending,
+		parcels[0].Outputs[0].ProofDelivery.Status,
+	)
+
+	// Once the delivery finished, the parcel is no longer returned.
+	err = assetsStore.LogProofDelivery(
+		ctx, out.Anchor.OutPoint, out.ScriptKey.PubKey,
+		tapfreighter.ProofDelivery{
+			Status: tapfreighter.ProofDeliveryStatusDelivered,
+		},
+	)
+	require.NoError(t, err)
+
+	parcels, err = assetsStore.PendingProofDeliveries(ctx)
+	require.NoError(t, err)
+	require.Empty(t, parcels)
+}
+
+// TestProofGapServes tests that proof gap serves are recorded once, keep their
+// original start time and can be removed.
+func TestProofGapServes(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetID := asset.RandID(t)
+	outPoint := test.RandOp(t)
+	serve := tapfreighter.ProofGapServe{
+		Locator: proof.Locator{
+			AssetID:   &assetID,
+			ScriptKey: *test.RandPubKey(t),
+			OutPoint:  &outPoint,
+		},
+		StartTime: time.Unix(1000, 0).UTC(),
+	}
+	require.NoError(t, assetsStore.LogProofGapServe(ctx, serve))
+
+	// Recording the same proof again doesn't restart the serve.
+	restarted := serve
+	restarted.StartTime = time.Unix(2000, 0).UTC()
+	require.NoError(t, assetsStore.LogProofGapServe(ctx, restarted))
+
+	serves, err := assetsStore.ProofGapServes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []tapfreighter.ProofGapServe{serve}, serves)
+
+	// A locator without an outpoint can't identify a serve.
+	invalid := serve
+	invalid.Locator.OutPoint = nil
+	err = assetsStore.LogProofGapServe(ctx, invalid)
+	require.ErrorContains(t, err, "requires asset ID and outpoint")
+
+	require.NoError(t, assetsStore.RemoveProofGapServe(ctx, serve.Locator))
+
+	serves, err = assetsStore.ProofGapServes(ctx)
+	require.NoError(t, err)
+	require.Empty(t, serves)
+}
+
+// TestMultiAssetSharedAnchorTransfer tests that two assets anchored in the
+// same output can be selected for and sent in a single transfer.
+func TestMultiAssetSharedAnchorTransfer(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 2, 1)
+	anchorPoint := assetGen.anchorPoints[0]
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: anchorPoint,
+		amt:         16,
+	}, {
+		assetGen:    assetGen.assetGens[1],
+		anchorPoint: anchorPoint,
+		amt:         32,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 2)
+	assetA, assetB := allAssets[0], allAssets[1]
+
+	// The first asset was selected for the first virtual packet, which
+	// leased the anchor output.
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	err = assetsStore.LeaseCoins(
+		ctx, leaseOwner, time.Now().Add(time.Hour), anchorPoint,
+	)
+	require.NoError(t, err)
+
+	// The second asset can then only be selected if the anchor output is
+	// shared with the first packet.
+	idB := assetB.ID()
+	constraints := tapfreighter.CommitmentConstraints{
+		AssetID: &idB,
+		MinAmt:  1,
+	}
+	coins, err := assetsStore.ListEligibleCoins(ctx, constraints)
+	require.NoError(t, err)
+	require.Empty(t, coins)
+
+	constraints.SharedOutpoints = []wire.OutPoint{anchorPoint}
+	coins, err = assetsStore.ListEligibleCoins(ctx, constraints)
+	require.NoError(t, err)
+	require.Len(t, coins, 1)
+	require.Equal(t, idB, coins[0].Asset.ID())
+	require.Equal(t, anchorPoint, coins[0].AnchorPoint)
+
+	// We now send both assets back to ourselves, each to its own anchor
+	// output of the same anchor transaction.
+	parcel := newTestParcel(t, assetA, anchorPoint, "")
+	parcel.Outputs[0].AssetID = assetA.ID()
+
+	anchorTxHash := parcel.AnchorTx.TxHash()
+	outB := parcel.Outputs[0]
+	outB.AssetID = idB
+	outB.Amount = assetB.Amount
+	outB.Anchor.OutPoint = wire.OutPoint{Hash: anchorTxHash, Index: 1}
+	outB.Anchor.InternalKey = keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	}
+	outB.ScriptKey = asset.NewScriptKeyBip86(keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	})
+	parcel.Outputs = append(parcel.Outputs, outB)
+	parcel.Inputs = append(parcel.Inputs, tapfreighter.TransferInput{
+		PrevID: asset.PrevID{
+			OutPoint:  anchorPoint,
+			ID:        idB,
+			ScriptKey: asset.ToSerialized(assetB.ScriptKey.PubKey),
+		},
+		Amount: assetB.Amount,
+	})
+	parcel.AnchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x02}, 34),
+		Value:    1000,
+	})
+
+	// Adding the output changed the anchor transaction, so we need to
+	// update the outpoints of the outputs.
+	anchorTxHash = parcel.AnchorTx.TxHash()
+	for idx := range parcel.Outputs {
+		parcel.Outputs[idx].Anchor.OutPoint.Hash = anchorTxHash
+	}
+
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+
+	finalProofs := make(map[asset.SerializedKey]*proof.AnnotatedProof)
+	for _, out := range parcel.Outputs {
+		scriptKey := asset.ToSerialized(out.ScriptKey.PubKey)
+		finalProofs[scriptKey] = &proof.AnnotatedProof{
+			Blob: bytes.Repeat([]byte{0x1}, 100),
+		}
+	}
+	err = assetsStore.ConfirmParcelDelivery(
+		ctx, &tapfreighter.AssetConfirmEvent{
+			AnchorTXID:  anchorTxHash,
+			BlockHash:   chainhash.Hash{1},
+			BlockHeight: 1500,
+			TxIndex:     1,
+			FinalProofs: finalProofs,
+		},
+	)
+	require.NoError(t, err)
+
+	// Both inputs are spent now, and each asset was re-created in its own
+	// new anchor output.
+	unspentAssets, err := assetsStore.FetchAllAssets(ctx, false, true, nil)
+	require.NoError(t, err)
+	require.Len(t, unspentAssets, 2)
+
+	for _, out := range parcel.Outputs {
+		var found bool
+		for _, a := range unspentAssets {
+			if a.ID() != out.AssetID {
+				continue
+			}
+
+			found = true
+			require.Equal(t, out.Anchor.OutPoint, a.AnchorOutpoint)
+			require.Equal(t, out.Amount, a.Amount)
+		}
+		require.True(t, found)
+	}
+}
+
+// TestSweepableAnchors tests that only confirmed anchor outputs that anchor
+// nothing but spent assets are returned as sweepable, and that they no longer
+// are once their sweep was recorded.
+func TestSweepableAnchors(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	inputAsset := allAssets[0]
+
+	// The anchor output of an unspent asset can't be swept.
+	anchors, err := assetsStore.SweepableAnchors(ctx, 100_000)
+	require.NoError(t, err)
+	require.Empty(t, anchors)
+
+	// We send the full amount of the asset away, which leaves a tombstone
+	// behind in our change output.
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: assetGen.anchorPoints[0],
+		SignatureScript:  []byte{},
+	})
+	anchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x01}, 34),
+		Value:    1000,
+	})
+	anchorTxHash := anchorTx.TxHash()
+
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: test.RandInt[keychain.KeyFamily](),
+			Index:  uint32(test.RandInt[int32]()),
+		},
+	}
+	changeAnchor := wire.OutPoint{Hash: anchorTxHash}
+	parcel := &tapfreighter.OutboundParcel{
+		AnchorTx:           anchorTx,
+		AnchorTxHeightHint: 1450,
+		Inputs: []tapfreighter.TransferInput{{
+			PrevID: asset.PrevID{
+				OutPoint: assetGen.anchorPoints[0],
+				ID:       inputAsset.ID(),
+				ScriptKey: asset.ToSerialized(
+					inputAsset.ScriptKey.PubKey,
+				),
+			},
+			Amount: inputAsset.Amount,
+		}},
+		Outputs: []tapfreighter.TransferOutput{{
+			Anchor: tapfreighter.Anchor{
+				Value:            1000,
+				OutPoint:         changeAnchor,
+				InternalKey:      internalKey,
+				TaprootAssetRoot: bytes.Repeat([]byte{0x1}, 32),
+				MerkleRoot:       bytes.Repeat([]byte{0x2}, 32),
+			},
+			ScriptKey: asset.NUMSScriptKey,
+			WitnessData: []asset.Witness{{
+				PrevID:    &asset.PrevID{},
+				TxWitness: [][]byte{{0x01}},
+			}},
+			AssetVersion: asset.V0,
+			ProofSuffix:  bytes.Repeat([]byte{0x01}, 100),
+		}},
+	}
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+
+	// As long as the transfer isn't confirmed, the tombstone isn't spent
+	// and its anchor output can't be swept.
+	anchors, err = assetsStore.SweepableAnchors(ctx, 100_000)
+	require.NoError(t, err)
+	require.Empty(t, anchors)
+
+	finalProofs := map[asset.SerializedKey]*proof.AnnotatedProof{
+		asset.ToSerialized(asset.NUMSPubKey): {
+			Blob: bytes.Repeat([]byte{0x1}, 100),
+		},
+	}
+	err = assetsStore.ConfirmParcelDelivery(
+		ctx, &tapfreighter.AssetConfirmEvent{
+			AnchorTXID:  anchorTxHash,
+			BlockHash:   chainhash.Hash{1},
+			BlockHeight: 1500,
+			TxIndex:     1,
+			FinalProofs: finalProofs,
+		},
+	)
+	require.NoError(t, err)
+
+	// Now the change output only anchors the spent tombstone and can be
+	// swept, while the spent input anchor can't. Outputs that are worth
+	// more than the maximum value aren't returned.
+	anchors, err = assetsStore.SweepableAnchors(ctx, 999)
+	require.NoError(t, err)
+	require.Empty(t, anchors)
+
+	anchors, err = assetsStore.SweepableAnchors(ctx, 1000)
+	require.NoError(t, err)
+	require.Equal(t, []*tapfreighter.SweepableAnchor{{
+		OutPoint:    changeAnchor,
+		Value:       1000,
+		InternalKey: internalKey,
+		MerkleRoot:  bytes.Repeat([]byte{0x2}, 32),
+	}}, anchors)
+
+	// Once the sweep is recorded, the output is no longer sweepable.
+	sweepTXID := chainhash.Hash{2}
+	sweptAt := time.Unix(time.Now().Unix(), 0).UTC()
+	err = assetsStore.LogAnchorSweep(
+		ctx, sweepTXID, []wire.OutPoint{changeAnchor}, sweptAt,
+	)
+	require.NoError(t, err)
+
+	anchors, err = assetsStore.SweepableAnchors(ctx, 1000)
+	require.NoError(t, err)
+	require.Empty(t, anchors)
+
+	sweeps, err := assetsStore.AnchorSweeps(ctx)
+	require.NoError(t, err)
+	require.Len(t, sweeps, 1)
+	require.Equal(t, &tapfreighter.AnchorSweep{
+		OutPoint:  changeAnchor,
+		Value:     1000,
+		SweepTXID: sweepTXID,
+		SweptAt:   sweptAt,
+	}, sweeps[0])
+
+	// An output can only be swept once.
+	err = assetsStore.LogAnchorSweep(
+		ctx, sweepTXID, []wire.OutPoint{changeAnchor}, sweptAt,
+	)
+	require.Error(t, err)
+}
+
+// TestOnChainFootprint tests that the on-chain footprint contains all managed
+// UTXOs that weren't spent or swept, together with the assets they anchor.
+func TestOnChainFootprint(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	inputAsset := allAssets[0]
+
+	// At first, the footprint only consists of the anchor output of the
+	// minted asset.
+	utxos, err := assetsStore.OnChainFootprint(ctx)
+	require.NoError(t, err)
+	require.Len(t, utxos, 1)
+	require.Equal(t, assetGen.anchorPoints[0], utxos[0].OutPoint)
+	require.Equal(t, []FootprintAsset{{
+		ID:        inputAsset.ID(),
+		Amount:    16,
+		ScriptKey: inputAsset.ScriptKey.PubKey,
+	}}, utxos[0].Assets)
+
+	// We send the full amount of the asset away, which leaves a tombstone
+	// behind in our change output.
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: assetGen.anchorPoints[0],
+		SignatureScript:  []byte{},
+	})
+	anchorTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x01}, 34),
+		Value:    1000,
+	})
+	anchorTxHash := anchorTx.TxHash()
+
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: test.RandInt[keychain.KeyFamily](),
+			Index:  uint32(test.RandInt[int32]()),
+		},
+	}
+	changeAnchor := wire.OutPoint{Hash: anchorTxHash}
+	parcel := &tapfreighter.OutboundParcel{
+		AnchorTx:           anchorTx,
+		AnchorTxHeightHint: 1450,
+		Inputs: []tapfreighter.TransferInput{{
+			PrevID: asset.PrevID{
+				OutPoint: assetGen.anchorPoints[0],
+				ID:       inputAsset.ID(),
+				ScriptKey: asset.ToSerialized(
+					inputAsset.ScriptKey.PubKey,
+				),
+			},
+			Amount: inputAsset.Amount,
+		}},
+		Outputs: []tapfreighter.TransferOutput{{
+			Anchor: tapfreighter.Anchor{
+				Value:            1000,
+				OutPoint:         changeAnchor,
+				InternalKey:      internalKey,
+				TaprootAssetRoot: bytes.Repeat([]byte{0x1}, 32),
+				MerkleRoot:       bytes.Repeat([]byte{0x2}, 32),
+			},
+			ScriptKey: asset.NUMSScriptKey,
+			WitnessData: []asset.Witness{{
+				PrevID:    &asset.PrevID{},
+				TxWitness: [][]byte{{0x01}},
+			}},
+			AssetVersion: asset.V0,
+			ProofSuffix:  bytes.Repeat([]byte{0x01}, 100),
+		}},
+	}
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+
+	// The spent input anchor is replaced by the unconfirmed change output,
+	// which doesn't anchor any assets until the transfer confirms.
+	utxos, err = assetsStore.OnChainFootprint(ctx)
+	require.NoError(t, err)
+	require.Len(t, utxos, 1)
+	require.Equal(t, changeAnchor, utxos[0].OutPoint)
+	require.EqualValues(t, 1000, utxos[0].OutputValue)
+	require.Equal(t, internalKey, utxos[0].InternalKey)
+	require.Zero(t, utxos[0].BlockHeight)
+	require.Empty(t, utxos[0].Assets)
+
+	finalProofs := map[asset.SerializedKey]*proof.AnnotatedProof{
+		asset.ToSerialized(asset.NUMSPubKey): {
+			Blob: bytes.Repeat([]byte{0x1}, 100),
+		},
+	}
+	err = assetsStore.ConfirmParcelDelivery(
+		ctx, &tapfreighter.AssetConfirmEvent{
+			AnchorTXID:  anchorTxHash,
+			BlockHash:   chainhash.Hash{1},
+			BlockHeight: 1500,
+			TxIndex:     1,
+			FinalProofs: finalProofs,
+		},
+	)
+	require.NoError(t, err)
+
+	// Once confirmed, the change output anchors the spent tombstone.
+	utxos, err = assetsStore.OnChainFootprint(ctx)
+	require.NoError(t, err)
+	require.Len(t, utxos, 1)
+	require.EqualValues(t, 1500, utxos[0].BlockHeight)
+	require.Len(t, utxos[0].Assets, 1)
+	require.True(t, utxos[0].Assets[0].Spent)
+	require.Equal(t, asset.NUMSPubKey, utxos[0].Assets[0].ScriptKey)
+
+	// After the change output was swept, nothing is left.
+	err = assetsStore.LogAnchorSweep(
+		ctx, chainhash.Hash{2}, []wire.OutPoint{changeAnchor},
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	utxos, err = assetsStore.OnChainFootprint(ctx)
+	require.NoError(t, err)
+	require.Empty(t, utxos)
+}
+
+// TestReplaceParcelAnchorTx tests that the anchor transaction of a pending
+// parcel can be replaced and that the parcel's outputs follow it.
+func TestReplaceParcelAnchorTx(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	// The funded anchor PSBT is logged together with the parcel.
+	parcel := newTestParcel(
+		t, allAssets[0], assetGen.anchorPoints[0], "",
+	)
+	parcel.AnchorPsbt = newTestFundedPsbt(t, parcel.AnchorTx, 0)
+	parcel.ChainFees = 50
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+	oldTxHash := parcel.AnchorTx.TxHash()
+	original := &tapfreighter.AnchorTransaction{
+		FundedPsbt: parcel.AnchorPsbt,
+		FinalTx:    parcel.AnchorTx,
+		ChainFees:  parcel.ChainFees,
+	}
+
+	pendingParcels, err := assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+	assertFundedPsbt(t, parcel.AnchorPsbt, pendingParcels[0].AnchorPsbt)
+	require.Zero(t, pendingParcels[0].FeeEscalations)
+
+	// The replacement spends the same input and creates the same output,
+	// but pays a different amount to the change output.
+	newTx := parcel.AnchorTx.Copy()
+	newTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x02}, 34),
+		Value:    500,
+	})
+	newTxHash := newTx.TxHash()
+	replacement := &tapfreighter.AnchorTransaction{
+		FundedPsbt: newTestFundedPsbt(t, newTx, 1),
+		FinalTx:    newTx,
+		ChainFees:  100,
+	}
+
+	// Only parcels that exist can be replaced.
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, chainhash.Hash{}, replacement, true,
+	)
+	require.ErrorContains(t, err, "no transfer found")
+
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, oldTxHash, replacement, true,
+	)
+	require.NoError(t, err)
+
+	// The pending parcel now references the replacement, including the
+	// outpoint of its output, its funded PSBT and the fee escalation.
+	pendingParcels, err = assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+
+	pendingParcel := pendingParcels[0]
+	require.Equal(t, newTxHash, pendingParcel.AnchorTx.TxHash())
+	require.EqualValues(t, 100, pendingParcel.ChainFees)
+	require.Len(t, pendingParcel.Outputs, 1)
+	require.Equal(
+		t, wire.OutPoint{Hash: newTxHash},
+		pendingParcel.Outputs[0].Anchor.OutPoint,
+	)
+	assertFundedPsbt(t, replacement.FundedPsbt, pendingParcel.AnchorPsbt)
+	require.EqualValues(t, 1, pendingParcel.FeeEscalations)
+
+	// The original is kept as a replaced version, so it's still watched
+	// after a restart.
+	require.Len(t, pendingParcel.ReplacedAnchorTxs, 1)
+	replaced := pendingParcel.ReplacedAnchorTxs[0]
+	require.Equal(t, oldTxHash, replaced.FinalTx.TxHash())
+	require.EqualValues(t, 50, replaced.ChainFees)
+	assertFundedPsbt(t, parcel.AnchorPsbt, replaced.FundedPsbt)
+
+	// The original anchor transaction is gone, so it can't be replaced
+	// again.
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, oldTxHash, replacement, false,
+	)
+	require.ErrorContains(t, err, "no transfer found")
+
+	// Going back to the original discards the replacement and its fee
+	// escalation.
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, newTxHash, original, false,
+	)
+	require.NoError(t, err)
+
+	pendingParcels, err = assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+	require.Equal(t, oldTxHash, pendingParcels[0].AnchorTx.TxHash())
+	assertFundedPsbt(t, parcel.AnchorPsbt, pendingParcels[0].AnchorPsbt)
+	require.Zero(t, pendingParcels[0].FeeEscalations)
+	require.Empty(t, pendingParcels[0].ReplacedAnchorTxs)
+
+	// A cancelled parcel can't be replaced anymore.
+	require.NoError(t, assetsStore.CancelParcel(ctx, oldTxHash))
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, oldTxHash, replacement, false,
+	)
+	require.ErrorContains(t, err, "was cancelled")
+}
+
+// TestTransferFeeBumpAnchor tests that the fee bump anchor output of a transfer
+// is stored with it, is part of the on-chain footprint until a child
+// transaction spends it, and that a replacement of the anchor transaction
+// makes it spendable again.
+func TestTransferFeeBumpAnchor(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: asset.TaprootAssetsKeyFamily,
+			Index:  uint32(test.RandInt[int32]()),
+		},
+	}
+	pkScript, err := tapscript.PayToTaprootScript(
+		txscript.ComputeTaprootKeyNoScript(internalKey.PubKey),
+	)
+	require.NoError(t, err)
+	feeBumpAnchor := &tapfreighter.FeeBumpAnchor{
+		OutputIndex: 1,
+		InternalKey: internalKey,
+		PkScript:    pkScript,
+	}
+
+	parcel := newTestParcel(
+		t, allAssets[0], assetGen.anchorPoints[0], "",
+	)
+	parcel.AnchorTx.AddTxOut(&wire.TxOut{
+		PkScript: pkScript,
+		Value:    tapfreighter.FeeBumpAnchorValue,
+	})
+	anchorTxHash := parcel.AnchorTx.TxHash()
+	parcel.Outputs[0].Anchor.OutPoint.Hash = anchorTxHash
+	parcel.AnchorPsbt = newTestFundedPsbt(t, parcel.AnchorTx, -1)
+	parcel.FeeBumpAnchor = feeBumpAnchor
+	leaseOwner := fn.ToArray[[32]byte](test.RandBytes(32))
+	require.NoError(t, assetsStore.LogPendingParcel(
+		ctx, parcel, leaseOwner, time.Now().Add(time.Hour),
+	))
+
+	// The fee bump anchor is restored with the pending parcel.
+	pendingParcels, err := assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+	require.Equal(t, feeBumpAnchor, pendingParcels[0].FeeBumpAnchor)
+	require.Nil(t, pendingParcels[0].FeeBumpChildTXID)
+
+	feeBumpAnchorPoint := wire.OutPoint{Hash: anchorTxHash, Index: 1}
+	assertFeeBumpFootprint := func(expected *wire.OutPoint) {
+		t.Helper()
+
+		utxos, err := assetsStore.OnChainFootprint(ctx)
+		require.NoError(t, err)
+
+		var feeBumpUtxos []*FootprintUTXO
+		for _, utxo := range utxos {
+			if utxo.FeeBumpAnchor {
+				feeBumpUtxos = append(feeBumpUtxos, utxo)
+			}
+		}
+
+		if expected == nil {
+			require.Empty(t, feeBumpUtxos)
+			return
+		}
+
+		require.Len(t, feeBumpUtxos, 1)
+		require.Equal(t, *expected, feeBumpUtxos[0].OutPoint)
+		require.EqualValues(
+			t, tapfreighter.FeeBumpAnchorValue,
+			feeBumpUtxos[0].OutputValue,
+		)
+		require.Equal(t, internalKey, feeBumpUtxos[0].InternalKey)
+		require.Empty(t, feeBumpUtxos[0].Assets)
+	}
+	assertFeeBumpFootprint(&feeBumpAnchorPoint)
+
+	// Once a child transaction spends the output, it's no longer part of
+	// the footprint.
+	childTXID := chainhash.Hash{0x01}
+	err = assetsStore.LogFeeBumpChild(ctx, chainhash.Hash{}, &childTXID)
+	require.ErrorContains(t, err, "no transfer found")
+
+	require.NoError(t, assetsStore.LogFeeBumpChild(
+		ctx, anchorTxHash, &childTXID,
+	))
+	assertFeeBumpFootprint(nil)
+
+	pendingParcels, err = assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+	require.Equal(t, &childTXID, pendingParcels[0].FeeBumpChildTXID)
+
+	// The child doesn't spend the output of a replacement, so the output
+	// of the replacement is part of the footprint again. The replaced
+	// version keeps the fee bump anchor.
+	newTx := parcel.AnchorTx.Copy()
+	newTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{0x02}, 34),
+		Value:    500,
+	})
+	newTxHash := newTx.TxHash()
+	err = assetsStore.ReplaceParcelAnchorTx(
+		ctx, anchorTxHash, &tapfreighter.AnchorTransaction{
+			FundedPsbt:    newTestFundedPsbt(t, newTx, 2),
+			FinalTx:       newTx,
+			ChainFees:     100,
+			FeeBumpAnchor: feeBumpAnchor,
+		}, false,
+	)
+	require.NoError(t, err)
+	assertFeeBumpFootprint(&wire.OutPoint{Hash: newTxHash, Index: 1})
+
+	pendingParcels, err = assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingParcels, 1)
+	require.Nil(t, pendingParcels[0].FeeBumpChildTXID)
+	require.Len(t, pendingParcels[0].ReplacedAnchorTxs, 1)
+	require.Equal(
+		t, feeBumpAnchor,
+		pendingParcels[0].ReplacedAnchorTxs[0].FeeBumpAnchor,
+	)
+
+	// Removing the record of a child makes the output spendable again.
+	require.NoError(t, assetsStore.LogFeeBumpChild(
+		ctx, newTxHash, &childTXID,
+	))
+	assertFeeBumpFootprint(nil)
+	require.NoError(t, assetsStore.LogFeeBumpChild(ctx, newTxHash, nil))
+	assertFeeBumpFootprint(&wire.OutPoint{Hash: newTxHash, Index: 1})
+
+	// The output of a cancelled transfer isn't ours to spend anymore.
+	require.NoError(t, assetsStore.CancelParcel(ctx, newTxHash))
+	assertFeeBumpFootprint(nil)
+
+	err = assetsStore.LogFeeBumpChild(ctx, newTxHash, &childTXID)
+	require.ErrorContains(t, err, "was cancelled")
+}
+
+// newTestFundedPsbt creates a funded PSBT for the given unsigned anchor
+// transaction.
+func newTestFundedPsbt(t *testing.T, tx *wire.MsgTx,
+	changeIndex int32) *tapgarden.FundedPsbt {
+
+	pkt, err := psbt.NewFromUnsignedTx(tx.Copy())
+	require.NoError(t, err)
+
+	return &tapgarden.FundedPsbt{
+		Pkt:               pkt,
+		ChangeOutputIndex: changeIndex,
+	}
+}
+
+// assertFundedPsbt asserts that the given funded PSBT was stored and restored
+// correctly.
+func assertFundedPsbt(t *testing.T, expected,
+	actual *tapgarden.FundedPsbt) {
+
+	require.NotNil(t, actual)
+	require.Equal(
+		t, expected.Pkt.UnsignedTx.TxHash(),
+		actual.Pkt.UnsignedTx.TxHash(),
+	)
+	require.Equal(t, expected.ChangeOutputIndex, actual.ChangeOutputIndex)
+}
+
+// TestAbandonParcel tests that the confirmation of a parcel can be rolled back
+// after its anchor transaction was re-organized out of the chain, which makes
+// its input spendable again and removes the asset it created, and that a
+// parcel can be quarantined before that.
+func TestAbandonParcel(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	assetGen := newAssetGenerator(t, 1, 1)
+	assetGen.genAssets(t, assetsStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         16,
+	}})
+
+	allAssets, err := assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	inputKey := asset.ToSerialized(allAssets[0].ScriptKey.PubKey)
+
+	parcel := logTestParcel(
+		t, assetsStore, allAssets[0], assetGen.anchorPoints[0], "",
+	)
+	anchorTxHash := parcel.AnchorTx.TxHash()
+	outputKey := asset.ToSerialized(parcel.Outputs[0].ScriptKey.PubKey)
+
+	finalProofs := map[asset.SerializedKey]*proof.AnnotatedProof{
+		outputKey: {
+			Blob: bytes.Repeat([]byte{0x1}, 100),
+		},
+	}
+	watchInput := tapfreighter.AnchorInput{
+		OutPoint: test.RandOp(t),
+		PkScript: []byte{0x51, 0x20},
+	}
+	err = assetsStore.ConfirmParcelDelivery(
+		ctx, &tapfreighter.AssetConfirmEvent{
+			AnchorTXID:  anchorTxHash,
+			BlockHash:   chainhash.Hash{1},
+			BlockHeight: 1500,
+			TxIndex:     1,
+			FinalProofs: finalProofs,
+			ReOrgWatch: &tapfreighter.ReOrgWatch{
+				ConfHeight: 1500,
+				Inputs: []tapfreighter.AnchorInput{
+					watchInput,
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// The re-org watch was stored together with the confirmation.
+	watches, err := assetsStore.ReOrgWatches(ctx)
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	require.Equal(t, anchorTxHash, watches[0].Parcel.AnchorTx.TxHash())
+	require.EqualValues(t, 1500, watches[0].ConfHeight)
+	require.False(t, watches[0].AbandonPending)
+	require.Equal(
+		t, []tapfreighter.AnchorInput{watchInput}, watches[0].Inputs,
+	)
+
+	err = assetsStore.UpdateReOrgWatch(ctx, anchorTxHash, 1501, true)
+	require.NoError(t, err)
+
+	watches, err = assetsStore.ReOrgWatches(ctx)
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	require.EqualValues(t, 1501, watches[0].ConfHeight)
+	require.True(t, watches[0].AbandonPending)
+
+	// The input asset was spent and the output asset was created.
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, true, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	require.Equal(
+		t, outputKey, asset.ToSerialized(allAssets[0].ScriptKey.PubKey),
+	)
+
+	// Quarantining the parcel leases its output, so the output asset is
+	// no longer available for coin selection.
+	require.NoError(t, assetsStore.QuarantineParcel(ctx, anchorTxHash))
+
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Empty(t, allAssets)
+
+	// The quarantined parcel is no longer watched for re-orgs.
+	watches, err = assetsStore.ReOrgWatches(ctx)
+	require.NoError(t, err)
+	require.Empty(t, watches)
+
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, true, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	require.Equal(
+		t, tapfreighter.QuarantineLeaseIdentifier,
+		allAssets[0].AnchorLeaseOwner,
+	)
+
+	// Only parcels that exist can be abandoned.
+	err = assetsStore.AbandonParcel(ctx, chainhash.Hash{})
+	require.ErrorContains(t, err, "no transfer found")
+
+	// A watch that is removed is no longer returned.
+	err = assetsStore.UpdateReOrgWatch(ctx, anchorTxHash, 1500, false)
+	require.NoError(t, err)
+	require.NoError(t, assetsStore.RemoveReOrgWatch(ctx, anchorTxHash))
+
+	watches, err = assetsStore.ReOrgWatches(ctx)
+	require.NoError(t, err)
+	require.Empty(t, watches)
+
+	// Once the parcel is abandoned, the output asset is gone and the
+	// input asset is available for coin selection again. Its re-org watch
+	// is removed as well.
+	err = assetsStore.UpdateReOrgWatch(ctx, anchorTxHash, 1500, true)
+	require.NoError(t, err)
+	require.NoError(t, assetsStore.AbandonParcel(ctx, anchorTxHash))
+
+	watches, err = assetsStore.ReOrgWatches(ctx)
+	require.NoError(t, err)
+	require.Empty(t, watches)
+
+	allAssets, err = assetsStore.FetchAllAssets(ctx, true, true, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+
+	allAssets, err = assetsStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, allAssets, 1)
+	require.Equal(
+		t, inputKey, asset.ToSerialized(allAssets[0].ScriptKey.PubKey),
+	)
+
+	// The abandoned parcel is cancelled and won't be resumed.
+	pendingParcels, err := assetsStore.PendingParcels(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pendingParcels)
+
+	parcels, err := assetsStore.QueryParcels(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, parcels, 1)
+	require.True(t, parcels[0].Cancelled)
+
+	// A parcel can only be abandoned once.
+	err = assetsStore.AbandonParcel(ctx, anchorTxHash)
+	require.ErrorContains(t, err, "already cancelled")
+}
+
+// TestAssetGroupSigUpsert tests that if you try to insert another asset
+// group sig with the same asset_gen_id, then only one is actually created.
+func TestAssetGroupSigUpsert(t *testing.T) {
+	t.Parallel()
+
+	_, _, db := newAssetStore(t)
+	ctx := context.Background()
+
+	internalKey := test.RandPubKey(t)
+
+	// First, we'll insert all the required rows we need to satisfy the
+	// foreign key constraints needed to insert a new genesis sig.
+	keyID, err := db.UpsertInternalKey(ctx, InternalKey{
+		RawKey: internalKey.SerializeCompressed(),
+	})
+	require.NoError(t, err)
+
+	genesisPointID, err := upsertGenesisPoint(ctx, db, test.RandOp(t))
+	require.NoError(t, err)
+
+	genAssetID, err := upsertGenesis(
+		ctx, db, genesisPointID, asset.RandGenesis(t, asset.Normal),
+	)
+	require.NoError(t, err)
+
+	groupID, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
+		TweakedGroupKey: internalKey.SerializeCompressed(),
+		InternalKeyID:   keyID,
+		GenesisPointID:  genesisPointID,
+	})
+	require.NoError(t, err)
+
+	// With all the other items inserted, we'll now insert an asset group
+	// sig.
+	groupSigID, err := db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
+		WitnessStack: []byte{0x01},
+		GenAssetID:   genAssetID,
+		GroupKeyID:   groupID,
+	})
+	require.NoError(t, err)
+
+	// If we insert the very same sig, then we should get the same group sig
+	// ID back.
+	groupSigID2, err := db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
+		WitnessStack: []byte{0x01},
+		GenAssetID:   genAssetID,
+		GroupKeyID:   groupID,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, groupSigID, groupSigID2)
+}
+
+// TestAssetGroupKeyUpsert tests that if you try to insert another asset group
+// key with the same tweaked_group_key, then only one is actually created.
+func TestAssetGroupKeyUpsert(t *testing.T) {
+	t.Parallel()
+
+	_, _, db := newAssetStore(t)
+	ctx := context.Background()
+
+	internalKey := test.RandPubKey(t)
+	groupKey := internalKey.SerializeCompressed()
+	keyIndex := test.RandInt[int32]()
+	scriptRoot := test.RandBytes(32)
+	witness := test.RandBytes(64)
+
+	// First, we'll insert all the required rows we need to satisfy the
+	// foreign key constraints needed to insert a new genesis sig.
+	keyID, err := db.UpsertInternalKey(ctx, InternalKey{
+		RawKey:    groupKey,
+		KeyFamily: asset.TaprootAssetsKeyFamily,
+		KeyIndex:  keyIndex,
+	})
+	require.NoError(t, err)
+
+	genesisPointID, err := upsertGenesisPoint(ctx, db, test.RandOp(t))
+	require.NoError(t, err)
+
+	// Now we'll insert an asset group key. We use a non-empty tapscript
+	// root to test that we can fetch the tapscript root after the upsert,
+	// even though we only store empty tapscript roots currently.
+	groupID, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
+		TweakedGroupKey: groupKey,
+		TapscriptRoot:   scriptRoot,
+		InternalKeyID:   keyID,
+		GenesisPointID:  genesisPointID,
+	})
+	require.NoError(t, err)
+
+	// If we insert the very same group key, then we should get the same
+	// group ID back.
+	groupID2, err := db.UpsertAssetGroupKey(ctx, AssetGroupKey{
+		TweakedGroupKey: groupKey,
+		TapscriptRoot:   scriptRoot,
+		InternalKeyID:   keyID,
+		GenesisPointID:  genesisPointID,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, groupID, groupID2)
+
+	// Insert a genesis and group sig to fill out the group key view.
+	genAssetID, err := upsertGenesis(
+		ctx, db, genesisPointID, asset.RandGenesis(t, asset.Normal),
+	)
+	require.NoError(t, err)
+
+	_, err = db.UpsertAssetGroupWitness(ctx, AssetGroupWitness{
+		WitnessStack: witness,
+		GenAssetID:   genAssetID,
+		GroupKeyID:   groupID,
+	})
+	require.NoError(t, err)
+
+	// If we fetch the group key, it should match the inserted fields.
+	groupInfo, err := db.FetchGroupByGroupKey(ctx, groupKey[:])
+	require.NoError(t, err)
+
+	require.Equal(t, genAssetID, groupInfo.GenAssetID)
+	require.Equal(t, groupKey, groupInfo.RawKey)
+	require.EqualValues(
+		t, asset.TaprootAssetsKeyFamily, groupInfo.KeyFamily,
+	)
+	require.Equal(t, keyIndex, groupInfo.KeyIndex)
+	require.Equal(t, scriptRoot, groupInfo.TapscriptRoot)
+	require.Equal(t, witness, groupInfo.WitnessStack)
+}
+
+// TestFetchGroupedAssets tests that the FetchGroupedAssets query corectly
+// excludes assets with nil group keys, groups assets with matching group
+// keys, and returns other asset fields accurately.
+func TestFetchGroupedAssets(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	// Make four assets and create only two group keys. We want one asset
+	// with no group, one group with only one asset, and two assets to be
+	// in the same group.
 	const numAssets = 4
 	assetGen := newAssetGenerator(t, numAssets, 2)
 
@@ -1729,3 +3337,56 @@ func TestFetchGroupedAssets(t *testing.T) {
 	equalityCheck(allAssets[2].Asset, groupedAssets[1])
 	equalityCheck(allAssets[3].Asset, groupedAssets[2])
 }
+
+// TestProofDeliveryReceipts tests that proof delivery receipts can be stored
+// and fetched by their script key.
+func TestProofDeliveryReceipts(t *testing.T) {
+	t.Parallel()
+
+	_, assetsStore, _ := newAssetStore(t)
+	ctx := context.Background()
+
+	scriptKey := test.RandPubKey(t)
+	assetID := asset.RandID(t)
+	outPoint := test.RandOp(t)
+	locator := proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: *scriptKey,
+		OutPoint:  &outPoint,
+	}
+
+	digest := sha256.Sum256(test.RandBytes(32))
+	sig, err := schnorr.Sign(test.RandPrivKey(t), digest[:])
+	require.NoError(t, err)
+
+	receipt := &proof.DeliveryReceipt{
+		ProofHash:    sha256.Sum256(test.RandBytes(100)),
+		RawScriptKey: test.RandPubKey(t),
+		TapTweak:     test.RandBytes(32),
+		Signature:    sig,
+	}
+
+	// Storing the same receipt twice must not result in a duplicate.
+	for i := 0; i < 2; i++ {
+		err := assetsStore.StoreProofDeliveryReceipt(
+			ctx, locator, receipt,
+		)
+		require.NoError(t, err)
+	}
+
+	receipts, err := assetsStore.FetchProofDeliveryReceipts(ctx, scriptKey)
+	require.NoError(t, err)
+	require.Len(t, receipts, 1)
+
+	require.Equal(t, locator.AssetID, receipts[0].Locator.AssetID)
+	require.Equal(t, locator.OutPoint, receipts[0].Locator.OutPoint)
+	require.True(t, scriptKey.IsEqual(&receipts[0].Locator.ScriptKey))
+	require.Equal(t, receipt, receipts[0].Receipt)
+
+	// There are no receipts for a different script key.
+	receipts, err = assetsStore.FetchProofDeliveryReceipts(
+		ctx, test.RandPubKey(t),
+	)
+	require.NoError(t, err)
+	require.Empty(t, receipts)
+}
@@ -0,0 +1,181 @@
+package tapdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	// pgDumpBinary is the name of the Postgres tool used to create backups
+	// of a Postgres database.
+	pgDumpBinary = "pg_dump"
+
+	// backupFileMode is the file mode of the backup files. A backup holds
+	// the full state of the daemon, so only the owner can access it.
+	backupFileMode = 0600
+)
+
+// BackupInfo describes a backup of the database that was written to disk.
+type BackupInfo struct {
+	// Path is the path of the backup file.
+	Path string
+
+	// Size is the size of the backup file in bytes.
+	Size uint64
+
+	// SHA256 is the SHA-256 checksum of the backup file.
+	SHA256 [sha256.Size]byte
+}
+
+// DatabaseBackuper is implemented by database backends that are able to create
+// a consistent backup of the database while it's in use.
+type DatabaseBackuper interface {
+	// Backup writes a consistent snapshot of the database to the given
+	// file path, which must not exist yet. The backup file is only
+	// readable and writable by its owner.
+	Backup(ctx context.Context, destPath string) (*BackupInfo, error)
+}
+
+// Backup writes a consistent snapshot of the SQLite database to the given file
+// path, which must not exist yet. The result is a regular SQLite database file.
+//
+// NOTE: This implements the DatabaseBackuper interface.
+func (s *SqliteStore) Backup(ctx context.Context,
+	destPath string) (*BackupInfo, error) {
+
+	if err := createBackupFile(destPath); err != nil {
+		return nil, err
+	}
+
+	// VACUUM INTO copies the database within a single read transaction,
+	// so the copy contains every transaction that was committed before it
+	// started, and none that are committed while it runs. In WAL mode
+	// this doesn't block any writers, so in-flight transfers carry on as
+	// usual.
+	// The backup file we created is still empty, which VACUUM INTO
+	// accepts as its target.
+	_, err := s.DB.ExecContext(ctx, "VACUUM INTO ?", destPath)
+	if err != nil {
+		_ = os.Remove(destPath)
+
+		return nil, fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	return newBackupInfo(destPath)
+}
+
+// Backup writes a consistent snapshot of the Postgres database to the given
+// file path, which must not exist yet. The snapshot is created by running
+// pg_dump, which must be installed on the machine running the daemon, and is
+// written in its custom archive format. It can be restored with pg_restore.
+//
+// NOTE: This implements the DatabaseBackuper interface.
+func (s *PostgresStore) Backup(ctx context.Context,
+	destPath string) (*BackupInfo, error) {
+
+	if err := createBackupFile(destPath); err != nil {
+		return nil, err
+	}
+
+	sslMode := "disable"
+	if s.cfg.RequireSSL {
+		sslMode = "require"
+	}
+
+	// pg_dump exports all data within a single repeatable read
+	// transaction, so the dump is consistent even while the database is
+	// being written to. We pass the connection details through the
+	// environment, so the password doesn't show up in the process list.
+	// pg_dump truncates the backup file we created, which keeps its mode.
+	cmd := exec.CommandContext(
+		ctx, pgDumpBinary, "--format=custom", "--no-password",
+		"--file="+destPath,
+	)
+	cmd.Env = append(
+		os.Environ(),
+		"PGHOST="+s.cfg.Host,
+		"PGPORT="+strconv.Itoa(s.cfg.Port),
+		"PGUSER="+s.cfg.User,
+		"PGPASSWORD="+s.cfg.Password,
+		"PGDATABASE="+s.cfg.DBName,
+		"PGSSLMODE="+sslMode,
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Make sure we don't leave a partial backup behind.
+		_ = os.Remove(destPath)
+
+		return nil, fmt.Errorf("unable to back up database using "+
+			"%s: %w: %s", pgDumpBinary, err,
+			bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return newBackupInfo(destPath)
+}
+
+// createBackupFile creates an empty backup file at the given path that is
+// only accessible by its owner, along with its parent directory if needed.
+// The file is created before any data is written to it, so the backup is never
+// readable by anyone else, not even while it's being written. The file must
+// not exist yet.
+func createBackupFile(destPath string) error {
+	if destPath == "" {
+		return fmt.Errorf("backup file path must be set")
+	}
+
+	err := os.MkdirAll(filepath.Dir(destPath), 0700)
+	if err != nil {
+		return fmt.Errorf("unable to create backup directory: %w", err)
+	}
+
+	backupFile, err := os.OpenFile(
+		destPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, backupFileMode,
+	)
+	switch {
+	case os.IsExist(err):
+		return fmt.Errorf("backup file %s already exists", destPath)
+
+	case err != nil:
+		return fmt.Errorf("unable to create backup file: %w", err)
+	}
+
+	return backupFile.Close()
+}
+
+// newBackupInfo computes the size and checksum of the backup file at the given
+// path.
+func newBackupInfo(path string) (*BackupInfo, error) {
+	backupFile, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open backup file: %w", err)
+	}
+	defer backupFile.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, backupFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup file: %w", err)
+	}
+
+	info := &BackupInfo{
+		Path: path,
+		Size: uint64(size),
+	}
+	copy(info.SHA256[:], hasher.Sum(nil))
+
+	return info, nil
+}
+
+// A compile time check to ensure that both database backends implement the
+// DatabaseBackuper interface.
+var _ DatabaseBackuper = (*SqliteStore)(nil)
+var _ DatabaseBackuper = (*PostgresStore)(nil)
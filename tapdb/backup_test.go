@@ -0,0 +1,66 @@
+package tapdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSqliteBackup tests that a backup of a SQLite database contains the data
+// of the database, and that its size and checksum are reported correctly.
+func TestSqliteBackup(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestSqliteDB(t)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	testHash := bytes.Repeat([]byte{1}, 32)
+	_, err := db.Exec(`
+		INSERT INTO assets_meta (meta_data_hash) VALUES ($1);
+	`, testHash)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "backups", "tapd.db")
+	info, err := db.Backup(ctx, backupPath)
+	require.NoError(t, err)
+
+	backupBytes, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	require.Equal(t, backupPath, info.Path)
+	require.EqualValues(t, len(backupBytes), info.Size)
+	require.Equal(t, sha256.Sum256(backupBytes), info.SHA256)
+
+	// Only the owner has access to the backup.
+	fileInfo, err := os.Stat(backupPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(backupFileMode), fileInfo.Mode().Perm())
+
+	// An existing backup is never overwritten.
+	_, err = db.Backup(ctx, backupPath)
+	require.ErrorContains(t, err, "already exists")
+
+	// The backup is a regular database that contains our data.
+	backupDB, err := NewSqliteStore(&SqliteConfig{
+		DatabaseFileName: backupPath,
+		SkipMigrations:   true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, backupDB.Close())
+	})
+
+	var storedHash []byte
+	err = backupDB.QueryRow(`
+		SELECT meta_data_hash FROM assets_meta;
+	`).Scan(&storedHash)
+	require.NoError(t, err)
+	require.Equal(t, testHash, storedHash)
+}
@@ -0,0 +1,383 @@
+package tapdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+// BalanceDiscrepancyType is the type of discrepancy between an owned asset as
+// it is recorded in the database and as it is described by its proof.
+type BalanceDiscrepancyType uint8
+
+const (
+	// DiscrepancyAmountMismatch indicates that the recorded amount of an
+	// asset differs from the amount in its proof. This discrepancy is
+	// repaired by a rebuild.
+	DiscrepancyAmountMismatch BalanceDiscrepancyType = iota
+
+	// DiscrepancyMissingProof indicates that there is no proof stored for
+	// an asset.
+	DiscrepancyMissingProof
+
+	// DiscrepancyInvalidProof indicates that the stored proof of an asset
+	// can't be decoded.
+	DiscrepancyInvalidProof
+
+	// DiscrepancyAnchorMismatch indicates that the proof of an asset
+	// anchors it in a different output than the one that is recorded, or
+	// describes a different asset.
+	DiscrepancyAnchorMismatch
+
+	// DiscrepancyNotInChain indicates that the block the proof of an asset
+	// anchors it in isn't part of the current chain.
+	DiscrepancyNotInChain
+)
+
+// String returns a human-readable string for the discrepancy type.
+func (d BalanceDiscrepancyType) String() string {
+	switch d {
+	case DiscrepancyAmountMismatch:
+		return "amount_mismatch"
+
+	case DiscrepancyMissingProof:
+		return "missing_proof"
+
+	case DiscrepancyInvalidProof:
+		return "invalid_proof"
+
+	case DiscrepancyAnchorMismatch:
+		return "anchor_mismatch"
+
+	case DiscrepancyNotInChain:
+		return "not_in_chain"
+
+	default:
+		return fmt.Sprintf("<unknown_discrepancy(%d)>", d)
+	}
+}
+
+// AssetDiscrepancy describes a discrepancy between a single owned asset as it
+// is recorded in the database and as it is described by its proof.
+type AssetDiscrepancy struct {
+	// Type is the type of the discrepancy.
+	Type BalanceDiscrepancyType
+
+	// AssetID is the ID of the asset.
+	AssetID asset.ID
+
+	// ScriptKey is the script key of the asset.
+	ScriptKey *btcec.PublicKey
+
+	// AnchorPoint is the recorded anchor outpoint of the asset.
+	AnchorPoint wire.OutPoint
+
+	// IndexedAmount is the amount of the asset that is recorded in the
+	// database.
+	IndexedAmount uint64
+
+	// ProofAmount is the amount of the asset according to its proof. This
+	// is only set if the proof could be decoded.
+	ProofAmount uint64
+
+	// Repaired is true if the recorded asset was updated to match its
+	// proof.
+	Repaired bool
+
+	// Details describes the discrepancy.
+	Details string
+}
+
+// BalanceDiscrepancy describes a difference between the balance of an asset ID
+// before and after a rebuild.
+type BalanceDiscrepancy struct {
+	// AssetID is the ID of the asset.
+	AssetID asset.ID
+
+	// OldBalance is the balance before the rebuild.
+	OldBalance uint64
+
+	// NewBalance is the balance after the rebuild.
+	NewBalance uint64
+}
+
+// BalanceRebuildReport is the result of rebuilding the asset balances from the
+// stored proofs.
+type BalanceRebuildReport struct {
+	// NumAssets is the number of owned, unspent assets that were checked.
+	NumAssets int
+
+	// AssetDiscrepancies are the discrepancies found for individual
+	// assets.
+	AssetDiscrepancies []AssetDiscrepancy
+
+	// BalanceDiscrepancies are the asset IDs whose balance changed by the
+	// rebuild.
+	BalanceDiscrepancies []BalanceDiscrepancy
+}
+
+// RebuildBalances re-derives the amounts of all owned, unspent assets from
+// their stored proofs and checks the anchor of each proof against the current
+// chain with the given header verifier. Recorded amounts that differ from the
+// proof are replaced in a single database transaction, unless dryRun is set.
+// Assets whose proof is missing, can't be decoded or doesn't match the
+// recorded anchor are reported, but are left unchanged, since their proof
+// can't be used to repair them.
+func (a *AssetStore) RebuildBalances(ctx context.Context,
+	headerVerifier proof.HeaderVerifier,
+	dryRun bool) (*BalanceRebuildReport, error) {
+
+	// We first read all unspent assets and their proofs. The proofs are
+	// checked against the chain outside a database transaction, as that
+	// might take a while.
+	var rows []UnspentAssetProof
+	readOpts := NewAssetStoreReadTx()
+	dbErr := a.db.ExecTx(ctx, &readOpts, func(q ActiveAssetsStore) error {
+		var err error
+		rows, err = q.QueryUnspentAssetProofs(ctx)
+		return err
+	})
+	if dbErr != nil {
+		return nil, fmt.Errorf("unable to query unspent assets: %w",
+			dbErr)
+	}
+
+	report := &BalanceRebuildReport{
+		NumAssets: len(rows),
+	}
+
+	var (
+		oldBalances = make(map[asset.ID]uint64)
+		newBalances = make(map[asset.ID]uint64)
+		repairs     = make(map[int64]int)
+	)
+	for _, row := range rows {
+		var assetID asset.ID
+		copy(assetID[:], row.AssetID)
+
+		discrepancy, err := checkAssetProof(row, headerVerifier)
+		if err != nil {
+			return nil, err
+		}
+
+		oldBalances[assetID] += uint64(row.Amount)
+		if discrepancy == nil ||
+			discrepancy.Type != DiscrepancyAmountMismatch {
+
+			newBalances[assetID] += uint64(row.Amount)
+		} else {
+			newBalances[assetID] += discrepancy.ProofAmount
+			repairs[row.AssetPrimaryKey] = len(
+				report.AssetDiscrepancies,
+			)
+		}
+
+		if discrepancy != nil {
+			report.AssetDiscrepancies = append(
+				report.AssetDiscrepancies, *discrepancy,
+			)
+		}
+	}
+
+	if !dryRun && len(repairs) > 0 {
+		err := a.repairAssetAmounts(ctx, rows, repairs, report)
+		if err != nil {
+			return nil, err
+		}
+
+		// Assets that changed since they were checked weren't
+		// repaired, so they still count with their recorded amount.
+		for _, idx := range repairs {
+			d := report.AssetDiscrepancies[idx]
+			if d.Repaired {
+				continue
+			}
+
+			newBalances[d.AssetID] -= d.ProofAmount
+			newBalances[d.AssetID] += d.IndexedAmount
+		}
+	}
+
+	for assetID, oldBalance := range oldBalances {
+		if newBalances[assetID] == oldBalance {
+			continue
+		}
+
+		report.BalanceDiscrepancies = append(
+			report.BalanceDiscrepancies, BalanceDiscrepancy{
+				AssetID:    assetID,
+				OldBalance: oldBalance,
+				NewBalance: newBalances[assetID],
+			},
+		)
+	}
+
+	sort.Slice(report.BalanceDiscrepancies, func(i, j int) bool {
+		return bytes.Compare(
+			report.BalanceDiscrepancies[i].AssetID[:],
+			report.BalanceDiscrepancies[j].AssetID[:],
+		) < 0
+	})
+
+	return report, nil
+}
+
+// repairAssetAmounts replaces the recorded amounts of the assets with the
+// given primary keys with the amounts of their proofs. The repairs map the
+// primary key of an asset to the index of its discrepancy in the report. An
+// asset is only updated if it is still unspent and its amount didn't change
+// since it was checked.
+func (a *AssetStore) repairAssetAmounts(ctx context.Context,
+	checked []UnspentAssetProof, repairs map[int64]int,
+	report *BalanceRebuildReport) error {
+
+	checkedAmounts := make(map[int64]int64, len(checked))
+	for _, row := range checked {
+		checkedAmounts[row.AssetPrimaryKey] = row.Amount
+	}
+
+	var repaired []int
+	var writeTxOpts AssetStoreTxOptions
+	dbErr := a.db.ExecTx(ctx, &writeTxOpts, func(q ActiveAssetsStore) error {
+		// The transaction might be retried, so we reset the repaired
+		// assets here.
+		repaired = nil
+
+		rows, err := q.QueryUnspentAssetProofs(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to query unspent assets: %w",
+				err)
+		}
+
+		for _, row := range rows {
+			idx, ok := repairs[row.AssetPrimaryKey]
+			if !ok || checkedAmounts[row.AssetPrimaryKey] !=
+				row.Amount {
+
+				continue
+			}
+
+			d := report.AssetDiscrepancies[idx]
+			err := q.UpdateAssetAmount(ctx, AssetAmountUpdate{
+				Amount:          int64(d.ProofAmount),
+				AssetPrimaryKey: row.AssetPrimaryKey,
+			})
+			if err != nil {
+				return fmt.Errorf("unable to update asset "+
+					"amount: %w", err)
+			}
+
+			repaired = append(repaired, idx)
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return dbErr
+	}
+
+	for _, idx := range repaired {
+		report.AssetDiscrepancies[idx].Repaired = true
+	}
+
+	return nil
+}
+
+// checkAssetProof compares an unspent asset as it is recorded in the database
+// with its stored proof. Nil is returned if no discrepancy was found.
+func checkAssetProof(row UnspentAssetProof,
+	headerVerifier proof.HeaderVerifier) (*AssetDiscrepancy, error) {
+
+	scriptKey, err := btcec.ParsePubKey(row.ScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse script key: %w", err)
+	}
+
+	discrepancy := &AssetDiscrepancy{
+		ScriptKey:     scriptKey,
+		IndexedAmount: uint64(row.Amount),
+	}
+	copy(discrepancy.AssetID[:], row.AssetID)
+
+	err = readOutPoint(
+		bytes.NewReader(row.AnchorOutpoint), 0, 0,
+		&discrepancy.AnchorPoint,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode anchor outpoint: %w",
+			err)
+	}
+
+	if len(row.ProofFile) == 0 {
+		discrepancy.Type = DiscrepancyMissingProof
+		discrepancy.Details = "no proof stored for asset"
+		return discrepancy, nil
+	}
+
+	var proofFile proof.File
+	err = proofFile.Decode(bytes.NewReader(row.ProofFile))
+	if err != nil {
+		discrepancy.Type = DiscrepancyInvalidProof
+		discrepancy.Details = fmt.Sprintf("unable to decode proof "+
+			"file: %v", err)
+		return discrepancy, nil
+	}
+	lastProof, err := proofFile.LastProof()
+	if err != nil {
+		discrepancy.Type = DiscrepancyInvalidProof
+		discrepancy.Details = fmt.Sprintf("unable to fetch last "+
+			"proof: %v", err)
+		return discrepancy, nil
+	}
+
+	proofAsset := lastProof.Asset
+	discrepancy.ProofAmount = proofAsset.Amount
+
+	switch {
+	case proofAsset.ID() != discrepancy.AssetID:
+		discrepancy.Type = DiscrepancyAnchorMismatch
+		discrepancy.Details = fmt.Sprintf("proof is for asset ID %v",
+			proofAsset.ID())
+		return discrepancy, nil
+
+	case !proofAsset.ScriptKey.PubKey.IsEqual(scriptKey):
+		discrepancy.Type = DiscrepancyAnchorMismatch
+		discrepancy.Details = fmt.Sprintf("proof is for script key "+
+			"%x", proofAsset.ScriptKey.PubKey.SerializeCompressed())
+		return discrepancy, nil
+
+	case lastProof.OutPoint() != discrepancy.AnchorPoint:
+		discrepancy.Type = DiscrepancyAnchorMismatch
+		discrepancy.Details = fmt.Sprintf("proof anchors asset in "+
+			"output %v", lastProof.OutPoint())
+		return discrepancy, nil
+	}
+
+	if headerVerifier != nil {
+		err := headerVerifier(
+			lastProof.BlockHeader, lastProof.BlockHeight,
+		)
+		if err != nil {
+			discrepancy.Type = DiscrepancyNotInChain
+			discrepancy.Details = fmt.Sprintf("anchor block not "+
+				"in chain: %v", err)
+			return discrepancy, nil
+		}
+	}
+
+	if proofAsset.Amount != discrepancy.IndexedAmount {
+		discrepancy.Type = DiscrepancyAmountMismatch
+		discrepancy.Details = fmt.Sprintf("recorded amount %d, proof "+
+			"amount %d", discrepancy.IndexedAmount,
+			proofAsset.Amount)
+		return discrepancy, nil
+	}
+
+	return nil, nil
+}
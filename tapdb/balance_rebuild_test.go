@@ -0,0 +1,173 @@
+package tapdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRebuildBalances tests that the amounts of owned assets are rebuilt from
+// their stored proofs and that all discrepancies are reported.
+func TestRebuildBalances(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	_, assetStore, db := newAssetStore(t)
+
+	// We create one asset that matches its proof, one that has a different
+	// amount than its proof, one with an invalid proof and a spent asset.
+	// The asset generator stores invalid proofs for all of them, which we
+	// replace below.
+	assetGen := newAssetGenerator(t, 4, 0)
+	assetGen.genAssets(t, assetStore, []assetDesc{{
+		assetGen:    assetGen.assetGens[0],
+		anchorPoint: assetGen.anchorPoints[0],
+		amt:         10,
+	}, {
+		assetGen:    assetGen.assetGens[1],
+		anchorPoint: assetGen.anchorPoints[1],
+		amt:         20,
+	}, {
+		assetGen:    assetGen.assetGens[2],
+		anchorPoint: assetGen.anchorPoints[2],
+		amt:         30,
+	}, {
+		assetGen:    assetGen.assetGens[3],
+		anchorPoint: assetGen.anchorPoints[3],
+		amt:         40,
+		spent:       true,
+	}})
+
+	assets, err := assetStore.FetchAllAssets(ctx, false, false, nil)
+	require.NoError(t, err)
+	require.Len(t, assets, 3)
+
+	byAmount := make(map[uint64]*ChainAsset)
+	for _, a := range assets {
+		byAmount[a.Amount] = a
+	}
+	validAsset, mismatchAsset, invalidAsset := byAmount[10], byAmount[20],
+		byAmount[30]
+
+	// storeProof stores a proof for the given asset that anchors it in its
+	// recorded anchor output with the given amount.
+	storeProof := func(chainAsset *ChainAsset, amt uint64) {
+		p := randProof(t)
+		p.Asset = *chainAsset.Asset.Copy()
+		p.Asset.Amount = amt
+		p.AnchorTx = *chainAsset.AnchorTx.Copy()
+		p.InclusionProof.OutputIndex = chainAsset.AnchorOutpoint.Index
+
+		blob, err := proof.EncodeAsProofFile(p)
+		require.NoError(t, err)
+
+		scriptKey := chainAsset.ScriptKey.PubKey.SerializeCompressed()
+		err = db.UpsertAssetProof(ctx, sqlc.UpsertAssetProofParams{
+			ProofFile:        blob,
+			TweakedScriptKey: scriptKey,
+		})
+		require.NoError(t, err)
+	}
+	storeProof(validAsset, 10)
+	storeProof(mismatchAsset, 25)
+
+	assertBalance := func(chainAsset *ChainAsset, expected uint64) {
+		id := chainAsset.ID()
+		balances, err := assetStore.QueryBalancesByAsset(ctx, &id)
+		require.NoError(t, err)
+		require.Equal(t, expected, balances[id].Balance)
+	}
+
+	discrepancyTypes := func(
+		report *BalanceRebuildReport) map[asset.ID]AssetDiscrepancy {
+
+		result := make(map[asset.ID]AssetDiscrepancy)
+		for _, d := range report.AssetDiscrepancies {
+			result[d.AssetID] = d
+		}
+
+		return result
+	}
+
+	// A dry run reports the discrepancies, but doesn't repair anything.
+	report, err := assetStore.RebuildBalances(
+		ctx, proof.MockHeaderVerifier, true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, report.NumAssets)
+
+	discrepancies := discrepancyTypes(report)
+	require.Len(t, discrepancies, 2)
+
+	mismatch := discrepancies[mismatchAsset.ID()]
+	require.Equal(t, DiscrepancyAmountMismatch, mismatch.Type)
+	require.EqualValues(t, 20, mismatch.IndexedAmount)
+	require.EqualValues(t, 25, mismatch.ProofAmount)
+	require.False(t, mismatch.Repaired)
+
+	invalid := discrepancies[invalidAsset.ID()]
+	require.Equal(t, DiscrepancyInvalidProof, invalid.Type)
+	require.Equal(t, invalidAsset.AnchorOutpoint, invalid.AnchorPoint)
+
+	require.Equal(t, []BalanceDiscrepancy{{
+		AssetID:    mismatchAsset.ID(),
+		OldBalance: 20,
+		NewBalance: 25,
+	}}, report.BalanceDiscrepancies)
+	assertBalance(mismatchAsset, 20)
+
+	// If the anchor block of a proof isn't in the chain, the proof can't be
+	// used to repair the asset.
+	errNotInChain := errors.New("block not in chain")
+	report, err = assetStore.RebuildBalances(
+		ctx, func(wire.BlockHeader, uint32) error {
+			return errNotInChain
+		}, false,
+	)
+	require.NoError(t, err)
+
+	discrepancies = discrepancyTypes(report)
+	require.Len(t, discrepancies, 3)
+	require.Equal(
+		t, DiscrepancyNotInChain, discrepancies[validAsset.ID()].Type,
+	)
+	require.Equal(
+		t, DiscrepancyNotInChain,
+		discrepancies[mismatchAsset.ID()].Type,
+	)
+	require.Empty(t, report.BalanceDiscrepancies)
+	assertBalance(mismatchAsset, 20)
+
+	// A real run repairs the amount mismatch, but leaves the asset with the
+	// invalid proof unchanged.
+	report, err = assetStore.RebuildBalances(
+		ctx, proof.MockHeaderVerifier, false,
+	)
+	require.NoError(t, err)
+
+	discrepancies = discrepancyTypes(report)
+	require.True(t, discrepancies[mismatchAsset.ID()].Repaired)
+	require.False(t, discrepancies[invalidAsset.ID()].Repaired)
+	require.Len(t, report.BalanceDiscrepancies, 1)
+
+	assertBalance(validAsset, 10)
+	assertBalance(mismatchAsset, 25)
+	assertBalance(invalidAsset, 30)
+
+	// Rebuilding again finds only the invalid proof.
+	report, err = assetStore.RebuildBalances(
+		ctx, proof.MockHeaderVerifier, false,
+	)
+	require.NoError(t, err)
+	require.Len(t, report.AssetDiscrepancies, 1)
+	require.Equal(
+		t, DiscrepancyInvalidProof, report.AssetDiscrepancies[0].Type,
+	)
+	require.Empty(t, report.BalanceDiscrepancies)
+}
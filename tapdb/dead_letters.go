@@ -0,0 +1,212 @@
+package tapdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+)
+
+type (
+	// NewProofDeadLetter is used to insert or update a dead letter proof.
+	NewProofDeadLetter = sqlc.UpsertProofDeadLetterParams
+)
+
+// ProofDeadLetterStore is the set of queries needed to persist the inbound
+// proofs that couldn't be imported.
+type ProofDeadLetterStore interface {
+	// UpsertProofDeadLetter inserts a new dead letter proof or replaces
+	// the one of the same outpoint.
+	UpsertProofDeadLetter(ctx context.Context, arg NewProofDeadLetter) error
+
+	// QueryProofDeadLetters returns all dead letter proofs in the order
+	// they were given up on.
+	QueryProofDeadLetters(ctx context.Context) ([]sqlc.ProofDeadLetter,
+		error)
+
+	// DeleteProofDeadLetter deletes the dead letter proof of the given
+	// outpoint.
+	DeleteProofDeadLetter(ctx context.Context, outpoint []byte) (int64,
+		error)
+}
+
+// ProofDeadLetterTxOptions is the database tx object for the dead letter
+// store.
+type ProofDeadLetterTxOptions struct {
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+func (p *ProofDeadLetterTxOptions) ReadOnly() bool {
+	return p.readOnly
+}
+
+// NewProofDeadLetterReadTx returns a new read tx for the dead letter store.
+func NewProofDeadLetterReadTx() ProofDeadLetterTxOptions {
+	return ProofDeadLetterTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedProofDeadLetterStore allows for batched DB transactions for the dead
+// letter store.
+type BatchedProofDeadLetterStore interface {
+	ProofDeadLetterStore
+
+	BatchedTx[ProofDeadLetterStore]
+}
+
+// DeadLetterDB persists the inbound proofs that couldn't be imported, so they
+// survive a restart and can be listed until they're imported manually.
+type DeadLetterDB struct {
+	db BatchedProofDeadLetterStore
+}
+
+// NewDeadLetterDB creates a new dead letter DB.
+func NewDeadLetterDB(db BatchedProofDeadLetterStore) *DeadLetterDB {
+	return &DeadLetterDB{
+		db: db,
+	}
+}
+
+// StoreDeadLetter stores the given dead letter, replacing an existing one for
+// the same outpoint.
+func (d *DeadLetterDB) StoreDeadLetter(ctx context.Context,
+	deadLetter *tapgarden.DeadLetterProof) error {
+
+	if deadLetter.Proof == nil {
+		return fmt.Errorf("dead letter has no proof")
+	}
+
+	outpoint, err := encodeOutpoint(deadLetter.OutPoint)
+	if err != nil {
+		return fmt.Errorf("unable to encode outpoint: %w", err)
+	}
+
+	var assetID []byte
+	if deadLetter.Proof.AssetID != nil {
+		assetID = fn.ByteSlice(*deadLetter.Proof.AssetID)
+	}
+	scriptKey := deadLetter.Proof.ScriptKey.SerializeCompressed()
+
+	errMsg := ""
+	if deadLetter.Err != nil {
+		errMsg = deadLetter.Err.Error()
+	}
+
+	var writeTx ProofDeadLetterTxOptions
+	return d.db.ExecTx(ctx, &writeTx, func(db ProofDeadLetterStore) error {
+		return db.UpsertProofDeadLetter(ctx, NewProofDeadLetter{
+			Outpoint:  outpoint,
+			AssetID:   assetID,
+			ScriptKey: scriptKey,
+			ProofFile: deadLetter.Proof.Blob,
+			Attempts:  int32(deadLetter.Attempts),
+			ErrorMsg:  errMsg,
+			CreatedAt: deadLetter.Time.UTC(),
+		})
+	})
+}
+
+// FetchDeadLetters returns all stored dead letters.
+func (d *DeadLetterDB) FetchDeadLetters(
+	ctx context.Context) ([]*tapgarden.DeadLetterProof, error) {
+
+	var deadLetters []*tapgarden.DeadLetterProof
+
+	readTx := NewProofDeadLetterReadTx()
+	err := d.db.ExecTx(ctx, &readTx, func(db ProofDeadLetterStore) error {
+		dbDeadLetters, err := db.QueryProofDeadLetters(ctx)
+		if err != nil {
+			return err
+		}
+
+		deadLetters = make(
+			[]*tapgarden.DeadLetterProof, 0, len(dbDeadLetters),
+		)
+		for _, dbDeadLetter := range dbDeadLetters {
+			deadLetter, err := parseDeadLetter(dbDeadLetter)
+			if err != nil {
+				return err
+			}
+
+			deadLetters = append(deadLetters, deadLetter)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query dead letters: %w", err)
+	}
+
+	return deadLetters, nil
+}
+
+// DeleteDeadLetter deletes the dead letter of the given outpoint, if there is
+// one.
+func (d *DeadLetterDB) DeleteDeadLetter(ctx context.Context,
+	op wire.OutPoint) error {
+
+	outpoint, err := encodeOutpoint(op)
+	if err != nil {
+		return fmt.Errorf("unable to encode outpoint: %w", err)
+	}
+
+	var writeTx ProofDeadLetterTxOptions
+	return d.db.ExecTx(ctx, &writeTx, func(db ProofDeadLetterStore) error {
+		_, err := db.DeleteProofDeadLetter(ctx, outpoint)
+		return err
+	})
+}
+
+// parseDeadLetter turns a dead letter database row into its native
+// counterpart.
+func parseDeadLetter(
+	dbDeadLetter sqlc.ProofDeadLetter) (*tapgarden.DeadLetterProof, error) {
+
+	var op wire.OutPoint
+	err := readOutPoint(bytes.NewReader(dbDeadLetter.Outpoint), 0, 0, &op)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode outpoint: %w", err)
+	}
+
+	locator := proof.Locator{
+		OutPoint: &op,
+	}
+	if len(dbDeadLetter.AssetID) > 0 {
+		var assetID asset.ID
+		copy(assetID[:], dbDeadLetter.AssetID)
+		locator.AssetID = &assetID
+	}
+	if len(dbDeadLetter.ScriptKey) > 0 {
+		scriptKey, err := btcec.ParsePubKey(dbDeadLetter.ScriptKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse script key: "+
+				"%w", err)
+		}
+		locator.ScriptKey = *scriptKey
+	}
+
+	return &tapgarden.DeadLetterProof{
+		OutPoint: op,
+		Proof: &proof.AnnotatedProof{
+			Locator: locator,
+			Blob:    dbDeadLetter.ProofFile,
+		},
+		Attempts: uint32(dbDeadLetter.Attempts),
+		Err:      errors.New(dbDeadLetter.ErrorMsg),
+		Time:     dbDeadLetter.CreatedAt.UTC(),
+	}, nil
+}
+
+// A compile time assertion to ensure DeadLetterDB meets the
+// tapgarden.DeadLetterStore interface.
+var _ tapgarden.DeadLetterStore = (*DeadLetterDB)(nil)
@@ -0,0 +1,99 @@
+package tapdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeadLetterDB(t *testing.T) *DeadLetterDB {
+	db := NewTestDB(t)
+
+	dbTxer := NewTransactionExecutor(db,
+		func(tx *sql.Tx) ProofDeadLetterStore {
+			return db.WithTx(tx)
+		},
+	)
+
+	return NewDeadLetterDB(dbTxer)
+}
+
+func randDeadLetter(t *testing.T,
+	timestamp time.Time) *tapgarden.DeadLetterProof {
+
+	op := test.RandOp(t)
+	assetID := asset.RandID(t)
+
+	return &tapgarden.DeadLetterProof{
+		OutPoint: op,
+		Proof: &proof.AnnotatedProof{
+			Locator: proof.Locator{
+				AssetID:   &assetID,
+				ScriptKey: *test.RandPubKey(t),
+				OutPoint:  &op,
+			},
+			Blob: test.RandBytes(100),
+		},
+		Attempts: uint32(test.RandIntn(10) + 1),
+		Err:      errors.New("proof not valid"),
+		Time:     timestamp,
+	}
+}
+
+// TestDeadLetterStore tests that dead letters are persisted, replaced by a
+// newer dead letter for the same outpoint, returned oldest first and deleted.
+func TestDeadLetterStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	deadLetterDB := newTestDeadLetterDB(t)
+
+	// Without any stored dead letters, nothing is returned.
+	deadLetters, err := deadLetterDB.FetchDeadLetters(ctx)
+	require.NoError(t, err)
+	require.Empty(t, deadLetters)
+
+	// Deleting an unknown dead letter isn't an error.
+	require.NoError(t, deadLetterDB.DeleteDeadLetter(ctx, test.RandOp(t)))
+
+	// We'll store two dead letters, the second one older than the first.
+	startTime := time.Unix(1700000000, 0).UTC()
+	first := randDeadLetter(t, startTime)
+	second := randDeadLetter(t, startTime.Add(-time.Hour))
+	require.NoError(t, deadLetterDB.StoreDeadLetter(ctx, first))
+	require.NoError(t, deadLetterDB.StoreDeadLetter(ctx, second))
+
+	deadLetters, err = deadLetterDB.FetchDeadLetters(ctx)
+	require.NoError(t, err)
+	require.Equal(
+		t, []*tapgarden.DeadLetterProof{second, first}, deadLetters,
+	)
+
+	// Storing a dead letter for an existing outpoint replaces it.
+	updated := *first
+	updated.Attempts++
+	updated.Err = errors.New("header not found")
+	updated.Time = startTime.Add(-2 * time.Hour)
+	require.NoError(t, deadLetterDB.StoreDeadLetter(ctx, &updated))
+
+	deadLetters, err = deadLetterDB.FetchDeadLetters(ctx)
+	require.NoError(t, err)
+	require.Equal(
+		t, []*tapgarden.DeadLetterProof{&updated, second}, deadLetters,
+	)
+
+	// Once deleted, a dead letter is no longer returned.
+	require.NoError(t, deadLetterDB.DeleteDeadLetter(ctx, second.OutPoint))
+
+	deadLetters, err = deadLetterDB.FetchDeadLetters(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []*tapgarden.DeadLetterProof{&updated}, deadLetters)
+}
@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"math"
 	prand "math/rand"
+	"sync"
 	"time"
 
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/lightningnetwork/lnd/clock"
 )
 
 var (
@@ -32,6 +34,15 @@ const (
 
 	// DefaultMaxRetryDelay is the default maximum delay between retries.
 	DefaultMaxRetryDelay = time.Second * 3
+
+	// replicaInitialBackoff is the time we stop using the read replica for
+	// after it failed for the first time. The backoff is doubled with each
+	// consecutive failure until we reach replicaMaxBackoff.
+	replicaInitialBackoff = time.Second * 10
+
+	// replicaMaxBackoff is the maximum time we stop using the read replica
+	// for after it failed.
+	replicaMaxBackoff = time.Minute * 5
 )
 
 // TxOptions represents a set of options one can use to control what type of
@@ -41,6 +52,72 @@ type TxOptions interface {
 	ReadOnly() bool
 }
 
+// ReplicaTxOptions is an optional extension of the TxOptions interface. Read
+// only transactions whose options allow it may be started on a read replica of
+// the database, if one is configured. As a replica can lag behind the primary
+// database, this should only be used for queries that don't need to observe
+// the effects of a write that just happened, such as serving RPC queries.
+type ReplicaTxOptions interface {
+	TxOptions
+
+	// AllowReplica returns true if the transaction may be started on a
+	// read replica of the database.
+	AllowReplica() bool
+}
+
+// ReplicaReadTxOptions is the set of options for a read only transaction that
+// may be served by a read replica of the database.
+type ReplicaReadTxOptions struct {
+	// primaryOnly is true if the transaction must be served by the
+	// primary database.
+	primaryOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the TxOptions interface.
+func (r *ReplicaReadTxOptions) ReadOnly() bool {
+	return true
+}
+
+// AllowReplica returns true if the transaction may be started on a read
+// replica of the database.
+//
+// NOTE: This implements the ReplicaTxOptions interface.
+func (r *ReplicaReadTxOptions) AllowReplica() bool {
+	return !r.primaryOnly
+}
+
+// NewReplicaReadTx creates a new read transaction option set that allows the
+// transaction to be served by a read replica.
+func NewReplicaReadTx() ReplicaReadTxOptions {
+	return ReplicaReadTxOptions{}
+}
+
+// replicaReadsKey is the context key that marks a context as allowing its
+// reads to be served by a read replica.
+type replicaReadsKey struct{}
+
+// WithReplicaReads returns a child context that allows the balance, transfer
+// and universe reads done with it to be served by a read replica of the
+// database, if one is configured. Internal logic like the universe sync or the
+// balance checks of new mints must see its own writes, so this must only be
+// used by read only RPC handlers that return the data to the caller.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// NewContextReadTx creates a new read transaction option set that only allows
+// the transaction to be served by a read replica if the given context was
+// created with WithReplicaReads.
+func NewContextReadTx(ctx context.Context) ReplicaReadTxOptions {
+	allowReplica, _ := ctx.Value(replicaReadsKey{}).(bool)
+
+	return ReplicaReadTxOptions{
+		primaryOnly: !allowReplica,
+	}
+}
+
 // BatchedTx is a generic interface that represents the ability to execute
 // several operations to a given storage interface in a single atomic
 // transaction. Typically, Q here will be some subset of the main sqlc.Querier
@@ -286,12 +363,127 @@ type BaseDB struct {
 	*sql.DB
 
 	*sqlc.Queries
+
+	// ReadReplica is an optional connection to a read replica of the
+	// database. If set, read only transactions that allow it are started
+	// on the replica instead of the primary database.
+	ReadReplica *sql.DB
+
+	// replicaBreaker keeps us from trying to use the read replica while
+	// it's failing.
+	replicaBreaker replicaBreaker
+}
+
+// replicaBreaker is a circuit breaker for the read replica of the database.
+// Once a transaction couldn't be started on the replica, all transactions are
+// served by the primary database until a backoff period expired. This keeps
+// every query from waiting for the connect timeout of an unreachable replica.
+type replicaBreaker struct {
+	// clock is used to determine whether the backoff period expired. The
+	// system clock is used if it isn't set.
+	clock clock.Clock
+
+	mtx sync.Mutex
+
+	// backoff is the current backoff period. It is zero as long as the
+	// replica didn't fail.
+	backoff time.Duration
+
+	// retryTime is the time after which the replica is used again.
+	retryTime time.Time
+}
+
+// now returns the current time of the breaker's clock.
+func (b *replicaBreaker) now() time.Time {
+	if b.clock == nil {
+		return time.Now()
+	}
+
+	return b.clock.Now()
+}
+
+// available returns true if the read replica should be used.
+func (b *replicaBreaker) available() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return !b.now().Before(b.retryTime)
+}
+
+// success resets the breaker after a transaction was started on the replica.
+func (b *replicaBreaker) success() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.backoff = 0
+	b.retryTime = time.Time{}
+}
+
+// failure opens the breaker after a transaction couldn't be started on the
+// replica, doubling the backoff period of the previous failure. It returns the
+// new backoff period, or zero if the breaker was already opened by a
+// concurrent failure.
+func (b *replicaBreaker) failure() time.Duration {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	now := b.now()
+	if now.Before(b.retryTime) {
+		return 0
+	}
+
+	switch {
+	case b.backoff == 0:
+		b.backoff = replicaInitialBackoff
+
+	case b.backoff*2 > replicaMaxBackoff:
+		b.backoff = replicaMaxBackoff
+
+	default:
+		b.backoff *= 2
+	}
+	b.retryTime = now.Add(b.backoff)
+
+	return b.backoff
 }
 
 // BeginTx wraps the normal sql specific BeginTx method with the TxOptions
 // interface. This interface is then mapped to the concrete sql tx options
 // struct.
 func (s *BaseDB) BeginTx(ctx context.Context, opts TxOptions) (*sql.Tx, error) {
+	replicaOpts, ok := opts.(ReplicaTxOptions)
+	if ok && s.ReadReplica != nil && replicaOpts.ReadOnly() &&
+		replicaOpts.AllowReplica() && s.replicaBreaker.available() {
+
+		// A hot standby doesn't support serializable transactions, so
+		// we use snapshot isolation instead, which is equivalent for
+		// read only transactions.
+		tx, err := s.ReadReplica.BeginTx(ctx, &sql.TxOptions{
+			ReadOnly:  true,
+			Isolation: sql.LevelRepeatableRead,
+		})
+		if err == nil {
+			s.replicaBreaker.success()
+			return tx, nil
+		}
+
+		// A cancelled query isn't the fault of the replica.
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		// If the replica isn't reachable, we fall back to the primary
+		// database, which can serve the same queries. We stop trying
+		// the replica for a while, so the following queries don't
+		// need to wait for it to fail as well.
+		backoff := s.replicaBreaker.failure()
+		if backoff > 0 {
+			log.Warnf("Unable to start read replica transaction, "+
+				"using primary database for %v: %v", backoff,
+				err)
+		}
+	}
+
 	sqlOptions := sql.TxOptions{
 		ReadOnly:  opts.ReadOnly(),
 		Isolation: sql.LevelSerializable,
@@ -303,3 +495,26 @@ func (s *BaseDB) BeginTx(ctx context.Context, opts TxOptions) (*sql.Tx, error) {
 func (s *BaseDB) Backend() sqlc.BackendType {
 	return s.Queries.Backend()
 }
+
+// configureConnPool applies the given connection pool settings to the
+// database. If the max number of open connections isn't set, the given default
+// is used. The number of idle connections defaults to the number of open
+// connections, so a new connection doesn't need to be established for each
+// query.
+func configureConnPool(db *sql.DB, maxOpen, maxIdle int,
+	maxLifetime time.Duration, defaultMaxOpen int) {
+
+	if maxOpen <= 0 {
+		maxOpen = defaultMaxOpen
+	}
+	if maxIdle <= 0 {
+		maxIdle = maxOpen
+	}
+	if maxLifetime <= 0 {
+		maxLifetime = DefaultConnMaxLifetime
+	}
+
+	db.SetMaxOpenConns(maxOpen)
+	db.SetMaxIdleConns(maxIdle)
+	db.SetConnMaxLifetime(maxLifetime)
+}
@@ -1,10 +1,12 @@
 package tapdb
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
@@ -135,9 +137,11 @@ func (b *MultiverseStore) RootNode(ctx context.Context,
 func (b *MultiverseStore) RootNodes(
 	ctx context.Context) ([]universe.BaseRoot, error) {
 
+	// The roots are served to syncing peers, so if the context allows it,
+	// the query can be served by a read replica.
 	var (
 		uniRoots []universe.BaseRoot
-		readTx   = NewBaseMultiverseReadTx()
+		readTx   = NewContextReadTx(ctx)
 	)
 
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseMultiverseStore) error {
@@ -205,8 +209,9 @@ func (b *MultiverseStore) RootNodes(
 				Node: mssmt.NewComputedBranch(
 					nodeHash, uint64(dbRoot.RootSum),
 				),
-				AssetName:     dbRoot.AssetName,
-				GroupedAssets: groupedAssets,
+				AssetName:      dbRoot.AssetName,
+				GroupedAssets:  groupedAssets,
+				IssuanceOrigin: dbRoot.IssuanceOrigin,
 			}
 
 			uniRoots = append(uniRoots, uniRoot)
@@ -453,3 +458,171 @@ func (b *MultiverseStore) RegisterBatchIssuance(ctx context.Context,
 
 	return nil
 }
+
+// PruneProofLeaves removes the proof leaves of the given universe that were
+// confirmed in a block below the given height. If the height is zero, then all
+// leaves of the universe are removed. The universe root and the multiverse
+// tree are updated within the same transaction, and a universe that no longer
+// has any leaves is deleted entirely. The proof events of the removed leaves
+// are subtracted from the universe stats. The number of removed leaves is
+// returned.
+func (b *MultiverseStore) PruneProofLeaves(ctx context.Context,
+	id universe.Identifier, beforeHeight uint32) (uint64, error) {
+
+	multiverseNS, err := namespaceForProof(id.ProofType)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		writeTx   BaseMultiverseOptions
+		numPruned uint64
+	)
+	dbErr := b.db.ExecTx(ctx, &writeTx, func(db BaseMultiverseStore) error {
+		numPruned = 0
+
+		namespace := id.String()
+		universeTree := mssmt.NewCompactedTree(
+			newTreeStoreWrapperTx(db, namespace),
+		)
+
+		universeKeys, err := db.FetchUniverseKeys(ctx, namespace)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range universeKeys {
+			scriptKeyPub, err := schnorr.ParsePubKey(
+				key.ScriptKeyBytes,
+			)
+			if err != nil {
+				return err
+			}
+			scriptKey := asset.NewScriptKey(scriptKeyPub)
+
+			var genPoint wire.OutPoint
+			err = readOutPoint(
+				bytes.NewReader(key.MintingPoint), 0, 0,
+				&genPoint,
+			)
+			if err != nil {
+				return err
+			}
+
+			leafKey := universe.LeafKey{
+				OutPoint:  genPoint,
+				ScriptKey: &scriptKey,
+			}
+			smtKey := leafKey.UniverseKey()
+
+			// If a height was given, then we only remove the leaf
+			// if its proof was confirmed below that height.
+			if beforeHeight != 0 {
+				leafNode, err := universeTree.Get(ctx, smtKey)
+				if err != nil {
+					return err
+				}
+
+				var leafProof proof.Proof
+				err = leafProof.Decode(bytes.NewReader(
+					leafNode.Value,
+				))
+				if err != nil {
+					return fmt.Errorf("unable to decode "+
+						"proof: %w", err)
+				}
+
+				if leafProof.BlockHeight >= beforeHeight {
+					continue
+				}
+			}
+
+			_, err = universeTree.Delete(ctx, smtKey)
+			if err != nil {
+				return err
+			}
+
+			err = db.DeleteUniverseLeaf(ctx, DeleteUniverseLeafKey{
+				Namespace:   namespace,
+				LeafNodeKey: smtKey[:],
+			})
+			if err != nil {
+				return err
+			}
+
+			numPruned++
+		}
+
+		if numPruned == 0 {
+			return nil
+		}
+
+		// With the leaves removed, the multiverse leaf of this
+		// universe needs to commit to the new universe root. If the
+		// universe is now empty, then we remove it altogether.
+		multiverseTree := mssmt.NewCompactedTree(
+			newTreeStoreWrapperTx(db, multiverseNS),
+		)
+		leafNodeKey := id.Bytes()
+
+		universeRoot, err := universeTree.Root(ctx)
+		if err != nil {
+			return err
+		}
+
+		if mssmt.IsEqualNode(universeRoot, mssmt.EmptyTree[0]) {
+			_, err = multiverseTree.Delete(ctx, leafNodeKey)
+			if err != nil {
+				return err
+			}
+
+			return deleteUniverseTree(ctx, db, namespace)
+		}
+
+		// The universe events aren't linked to a leaf, so we remove
+		// the oldest proof events of the universe, one per pruned
+		// leaf, and subtract them from the running stats counter. A
+		// universe that is removed altogether has all its events
+		// subtracted above.
+		numEvents, err := db.DeleteUniverseProofEvents(
+			ctx, UniverseProofEvents{
+				NamespaceRoot: namespace,
+				NumEvents:     int32(numPruned),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete universe proof "+
+				"events: %w", err)
+		}
+
+		err = db.DecrementUniverseStatsCounter(
+			ctx, UniverseStatsCounterDelta{
+				NumEvents: numEvents,
+				EventType: newProofEventType,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update universe stats "+
+				"counters: %w", err)
+		}
+
+		universeRootHash := universeRoot.NodeHash()
+		assetGroupSum := universeRoot.NodeSum()
+
+		if id.ProofType == universe.ProofTypeIssuance {
+			assetGroupSum = 1
+		}
+
+		leafNode := mssmt.NewLeafNode(
+			universeRootHash[:], assetGroupSum,
+		)
+		_, err = multiverseTree.Insert(ctx, leafNodeKey, leafNode)
+
+		return err
+	})
+	if dbErr != nil {
+		return 0, dbErr
+	}
+
+	return numPruned, nil
+}
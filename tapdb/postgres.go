@@ -14,6 +14,11 @@ import (
 
 const (
 	dsnTemplate = "postgres://%v:%v@%v:%d/%v?sslmode=%v"
+
+	// readReplicaConnectTimeout is the timeout in seconds for connecting
+	// to the read replica. We keep it short, so queries quickly fall back
+	// to the primary database if the replica is unreachable.
+	readReplicaConnectTimeout = 5
 )
 
 var (
@@ -27,18 +32,43 @@ var (
 
 // PostgresConfig holds the postgres database configuration.
 type PostgresConfig struct {
-	SkipMigrations     bool   `long:"skipmigrations" description:"Skip applying migrations on startup."`
-	Host               string `long:"host" description:"Database server hostname."`
-	Port               int    `long:"port" description:"Database server port."`
-	User               string `long:"user" description:"Database user."`
-	Password           string `long:"password" description:"Database user's password."`
-	DBName             string `long:"dbname" description:"Database name to use."`
-	MaxOpenConnections int    `long:"maxconnections" description:"Max open connections to keep alive to the database server."`
-	RequireSSL         bool   `long:"requiressl" description:"Whether to require using SSL (mode: require) when connecting to the server."`
+	SkipMigrations     bool          `long:"skipmigrations" description:"Skip applying migrations on startup."`
+	Host               string        `long:"host" description:"Database server hostname."`
+	Port               int           `long:"port" description:"Database server port."`
+	User               string        `long:"user" description:"Database user."`
+	Password           string        `long:"password" description:"Database user's password."`
+	DBName             string        `long:"dbname" description:"Database name to use."`
+	MaxOpenConnections int           `long:"maxconnections" description:"Max open connections to keep alive to the database server."`
+	MaxIdleConnections int           `long:"maxidleconnections" description:"Max idle connections to keep open to the database server. Defaults to the number of max open connections."`
+	ConnMaxLifetime    time.Duration `long:"connmaxlifetime" description:"The maximum amount of time a connection to the database server may be reused."`
+	RequireSSL         bool          `long:"requiressl" description:"Whether to require using SSL (mode: require) when connecting to the server."`
+	ReadReplicaHost    string        `long:"readreplicahost" description:"Optional hostname of a read replica of the database. If set, RPC queries for balances, transfers and universe data are served by the replica, using the same user, password and database name as the primary. The replica may lag behind the primary, so recent writes might not be visible right away. Queries fall back to the primary if the replica is unreachable, which is then retried with an increasing backoff of up to 5 minutes."`
+	ReadReplicaPort    int           `long:"readreplicaport" description:"Read replica server port. Defaults to the port of the primary database."`
 }
 
 // DSN returns the dns to connect to the database.
 func (s *PostgresConfig) DSN(hidePassword bool) string {
+	return s.dsn(s.Host, s.Port, hidePassword)
+}
+
+// ReadReplicaDSN returns the dsn to connect to the read replica of the
+// database.
+func (s *PostgresConfig) ReadReplicaDSN(hidePassword bool) string {
+	port := s.Port
+	if s.ReadReplicaPort > 0 {
+		port = s.ReadReplicaPort
+	}
+
+	return fmt.Sprintf("%s&connect_timeout=%d",
+		s.dsn(s.ReadReplicaHost, port, hidePassword),
+		readReplicaConnectTimeout)
+}
+
+// dsn returns the dsn to connect to the database server at the given host and
+// port.
+func (s *PostgresConfig) dsn(host string, port int,
+	hidePassword bool) string {
+
 	var sslMode = "disable"
 	if s.RequireSSL {
 		sslMode = "require"
@@ -50,7 +80,7 @@ func (s *PostgresConfig) DSN(hidePassword bool) string {
 		password = "****"
 	}
 
-	return fmt.Sprintf(dsnTemplate, s.User, password, s.Host, s.Port,
+	return fmt.Sprintf(dsnTemplate, s.User, password, host, port,
 		s.DBName, sslMode)
 }
 
@@ -72,14 +102,10 @@ func NewPostgresStore(cfg *PostgresConfig) (*PostgresStore, error) {
 		return nil, err
 	}
 
-	maxConns := defaultMaxConns
-	if cfg.MaxOpenConnections > 0 {
-		maxConns = cfg.MaxOpenConnections
-	}
-
-	rawDb.SetMaxOpenConns(maxConns)
-	rawDb.SetMaxIdleConns(maxConns)
-	rawDb.SetConnMaxLifetime(connIdleLifetime)
+	configureConnPool(
+		rawDb, cfg.MaxOpenConnections, cfg.MaxIdleConnections,
+		cfg.ConnMaxLifetime, defaultMaxConns,
+	)
 
 	if !cfg.SkipMigrations {
 		// Now that the database is open, populate the database with
@@ -110,13 +136,34 @@ func NewPostgresStore(cfg *PostgresConfig) (*PostgresStore, error) {
 		}
 	}
 
+	// If a read replica is configured, we open a separate connection
+	// pool for it. Opening the pool doesn't connect to the replica yet, so
+	// an unreachable replica won't prevent us from starting up.
+	var readReplica *sql.DB
+	if cfg.ReadReplicaHost != "" {
+		log.Infof("Using SQL read replica '%s'",
+			cfg.ReadReplicaDSN(true))
+
+		readReplica, err = sql.Open("pgx", cfg.ReadReplicaDSN(false))
+		if err != nil {
+			return nil, err
+		}
+
+		configureConnPool(
+			readReplica, cfg.MaxOpenConnections,
+			cfg.MaxIdleConnections, cfg.ConnMaxLifetime,
+			defaultMaxConns,
+		)
+	}
+
 	queries := sqlc.NewPostgres(rawDb)
 
 	return &PostgresStore{
 		cfg: cfg,
 		BaseDB: &BaseDB{
-			DB:      rawDb,
-			Queries: queries,
+			DB:          rawDb,
+			Queries:     queries,
+			ReadReplica: readReplica,
 		},
 	}, nil
 }
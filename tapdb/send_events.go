@@ -0,0 +1,176 @@
+package tapdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
+	"github.com/lightningnetwork/lnd/clock"
+)
+
+type (
+	// NewSendEvent is used to insert a new send event.
+	NewSendEvent = sqlc.InsertSendEventParams
+
+	// SendEventQuery is used to query the stored send events.
+	SendEventQuery = sqlc.QuerySendEventsParams
+)
+
+// SendEventStore is the set of queries needed to persist the events of the
+// asset sending process.
+type SendEventStore interface {
+	// InsertSendEvent inserts a new send event and returns its ID.
+	InsertSendEvent(ctx context.Context, arg NewSendEvent) (int64, error)
+
+	// QuerySendEvents returns the send events with an ID greater than the
+	// given one that were emitted at or after the given time, in the order
+	// they were emitted in.
+	QuerySendEvents(ctx context.Context,
+		arg SendEventQuery) ([]sqlc.SendEvent, error)
+
+	// DeleteSendEventsBefore deletes all send events that were emitted
+	// before the given time, except for the most recent one.
+	DeleteSendEventsBefore(ctx context.Context,
+		cutoffTime time.Time) (int64, error)
+}
+
+// SendEventTxOptions is the database tx object for the send event store.
+type SendEventTxOptions struct {
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+func (s *SendEventTxOptions) ReadOnly() bool {
+	return s.readOnly
+}
+
+// NewSendEventReadTx returns a new read tx for the send event store.
+func NewSendEventReadTx() SendEventTxOptions {
+	return SendEventTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSendEventStore allows for batched DB transactions for the send event
+// store.
+type BatchedSendEventStore interface {
+	SendEventStore
+
+	BatchedTx[SendEventStore]
+}
+
+// SendEvent is a persisted event of the asset sending process.
+type SendEvent struct {
+	// ID is the ID of the event. The IDs of the events increase
+	// monotonically in the order the events were emitted in.
+	ID uint64
+
+	// Timestamp is the time the event was emitted at.
+	Timestamp time.Time
+
+	// Data is the serialized event.
+	Data []byte
+}
+
+// SendEventDB persists the events of the asset sending process, so they can be
+// replayed to subscribers that missed them.
+type SendEventDB struct {
+	db BatchedSendEventStore
+
+	clock clock.Clock
+}
+
+// NewSendEventDB creates a new send event DB.
+func NewSendEventDB(db BatchedSendEventStore,
+	clock clock.Clock) *SendEventDB {
+
+	return &SendEventDB{
+		db:    db,
+		clock: clock,
+	}
+}
+
+// LogSendEvent persists a send event that was emitted at the given time and
+// returns the ID that was assigned to it.
+func (s *SendEventDB) LogSendEvent(ctx context.Context, timestamp time.Time,
+	data []byte) (uint64, error) {
+
+	var (
+		writeTx SendEventTxOptions
+		eventID int64
+	)
+	err := s.db.ExecTx(ctx, &writeTx, func(db SendEventStore) error {
+		var err error
+		eventID, err = db.InsertSendEvent(ctx, NewSendEvent{
+			EventTime: timestamp.UTC(),
+			EventData: data,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to insert send event: %w", err)
+	}
+
+	return uint64(eventID), nil
+}
+
+// QuerySendEvents returns the persisted send events with an ID greater than
+// afterID that were emitted at or after the given time, in the order they were
+// emitted in.
+func (s *SendEventDB) QuerySendEvents(ctx context.Context, afterID uint64,
+	since time.Time) ([]SendEvent, error) {
+
+	var events []SendEvent
+
+	readTx := NewSendEventReadTx()
+	err := s.db.ExecTx(ctx, &readTx, func(db SendEventStore) error {
+		dbEvents, err := db.QuerySendEvents(ctx, SendEventQuery{
+			AfterEventID: int64(afterID),
+			MinEventTime: since.UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		events = fn.Map(dbEvents, func(e sqlc.SendEvent) SendEvent {
+			return SendEvent{
+				ID:        uint64(e.EventID),
+				Timestamp: e.EventTime.UTC(),
+				Data:      e.EventData,
+			}
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to query send events: %w", err)
+	}
+
+	return events, nil
+}
+
+// PruneSendEvents deletes the send events that are older than the given
+// retention period and returns the number of deleted events. The most recent
+// event is never deleted, so that the IDs of new events keep increasing.
+func (s *SendEventDB) PruneSendEvents(ctx context.Context,
+	retention time.Duration) (int64, error) {
+
+	cutoffTime := s.clock.Now().Add(-retention).UTC()
+
+	var (
+		writeTx    SendEventTxOptions
+		numDeleted int64
+	)
+	err := s.db.ExecTx(ctx, &writeTx, func(db SendEventStore) error {
+		var err error
+		numDeleted, err = db.DeleteSendEventsBefore(ctx, cutoffTime)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to prune send events: %w", err)
+	}
+
+	return numDeleted, nil
+}
@@ -0,0 +1,102 @@
+package tapdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSendEventDB(t *testing.T, clock clock.Clock) *SendEventDB {
+	db := NewTestDB(t)
+
+	dbTxer := NewTransactionExecutor(db,
+		func(tx *sql.Tx) SendEventStore {
+			return db.WithTx(tx)
+		},
+	)
+
+	return NewSendEventDB(dbTxer, clock)
+}
+
+// TestSendEventLog tests that send events are stored with monotonically
+// increasing IDs, can be queried by ID and time and are pruned once they are
+// older than the retention period.
+func TestSendEventLog(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	startTime := time.Unix(1700000000, 0)
+	testClock := clock.NewTestClock(startTime)
+	eventDB := newTestSendEventDB(t, testClock)
+
+	// Without any stored events, the query returns nothing.
+	events, err := eventDB.QuerySendEvents(ctx, 0, time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// We'll now store a series of events, one minute apart.
+	const numEvents = 5
+	eventIDs := make([]uint64, 0, numEvents)
+	for i := 0; i < numEvents; i++ {
+		eventTime := startTime.Add(time.Duration(i) * time.Minute)
+		eventID, err := eventDB.LogSendEvent(
+			ctx, eventTime, []byte(fmt.Sprintf("event-%d", i)),
+		)
+		require.NoError(t, err)
+
+		if len(eventIDs) > 0 {
+			require.Greater(t, eventID, eventIDs[len(eventIDs)-1])
+		}
+		eventIDs = append(eventIDs, eventID)
+	}
+
+	// All events are returned in the order they were stored in.
+	events, err = eventDB.QuerySendEvents(ctx, 0, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, numEvents)
+	for i, event := range events {
+		require.Equal(t, eventIDs[i], event.ID)
+		require.Equal(t, []byte(fmt.Sprintf("event-%d", i)), event.Data)
+		require.True(t, startTime.Add(
+			time.Duration(i)*time.Minute,
+		).Equal(event.Timestamp))
+	}
+
+	// Only the events after the given ID are returned.
+	events, err = eventDB.QuerySendEvents(ctx, eventIDs[2], time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, eventIDs[3], events[0].ID)
+
+	// Only the events emitted at or after the given time are returned.
+	events, err = eventDB.QuerySendEvents(
+		ctx, 0, startTime.Add(time.Minute),
+	)
+	require.NoError(t, err)
+	require.Len(t, events, numEvents-1)
+	require.Equal(t, eventIDs[1], events[0].ID)
+
+	// We now advance the clock, so all events are older than the retention
+	// period. Pruning deletes all events except for the most recent one.
+	testClock.SetTime(startTime.Add(time.Hour))
+	numDeleted, err := eventDB.PruneSendEvents(ctx, time.Minute)
+	require.NoError(t, err)
+	require.EqualValues(t, numEvents-1, numDeleted)
+
+	events, err = eventDB.QuerySendEvents(ctx, 0, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, eventIDs[numEvents-1], events[0].ID)
+
+	// New events continue after the IDs of the pruned events.
+	eventID, err := eventDB.LogSendEvent(
+		ctx, testClock.Now(), []byte("new-event"),
+	)
+	require.NoError(t, err)
+	require.Greater(t, eventID, eventIDs[numEvents-1])
+}
@@ -15,7 +15,7 @@ const fetchAddrByTaprootOutputKey = `-- name: FetchAddrByTaprootOutputKey :one
 SELECT
     version, asset_version, genesis_asset_id, group_key, tapscript_sibling,
     taproot_output_key, amount, asset_type, creation_time, managed_from,
-    proof_courier_addr,
+    proof_courier_addr, min_amount,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key as raw_script_key,
@@ -46,6 +46,7 @@ type FetchAddrByTaprootOutputKeyRow struct {
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
 	ProofCourierAddr []byte
+	MinAmount        sql.NullInt64
 	TweakedScriptKey []byte
 	ScriptKeyTweak   []byte
 	RawScriptKey     []byte
@@ -71,6 +72,7 @@ func (q *Queries) FetchAddrByTaprootOutputKey(ctx context.Context, taprootOutput
 		&i.CreationTime,
 		&i.ManagedFrom,
 		&i.ProofCourierAddr,
+		&i.MinAmount,
 		&i.TweakedScriptKey,
 		&i.ScriptKeyTweak,
 		&i.RawScriptKey,
@@ -85,7 +87,7 @@ func (q *Queries) FetchAddrByTaprootOutputKey(ctx context.Context, taprootOutput
 
 const fetchAddrEvent = `-- name: FetchAddrEvent :one
 SELECT
-    creation_time, status, asset_proof_id, asset_id,
+    creation_time, status, asset_proof_id, asset_id, asset_amt, quarantined,
     chain_txns.txid as txid,
     chain_txns.block_height as confirmation_height,
     chain_txn_output_index as output_index,
@@ -107,6 +109,8 @@ type FetchAddrEventRow struct {
 	Status             int16
 	AssetProofID       sql.NullInt64
 	AssetID            sql.NullInt64
+	AssetAmt           sql.NullInt64
+	Quarantined        bool
 	Txid               []byte
 	ConfirmationHeight sql.NullInt32
 	OutputIndex        int32
@@ -123,6 +127,8 @@ func (q *Queries) FetchAddrEvent(ctx context.Context, id int64) (FetchAddrEventR
 		&i.Status,
 		&i.AssetProofID,
 		&i.AssetID,
+		&i.AssetAmt,
+		&i.Quarantined,
 		&i.Txid,
 		&i.ConfirmationHeight,
 		&i.OutputIndex,
@@ -133,11 +139,31 @@ func (q *Queries) FetchAddrEvent(ctx context.Context, id int64) (FetchAddrEventR
 	return i, err
 }
 
+const fetchAddrIdempotencyKey = `-- name: FetchAddrIdempotencyKey :one
+SELECT idempotency_keys.params_hash, addrs.taproot_output_key
+FROM addr_idempotency_keys idempotency_keys
+JOIN addrs
+    ON idempotency_keys.addr_id = addrs.id
+WHERE idempotency_keys.idempotency_key = $1
+`
+
+type FetchAddrIdempotencyKeyRow struct {
+	ParamsHash       []byte
+	TaprootOutputKey []byte
+}
+
+func (q *Queries) FetchAddrIdempotencyKey(ctx context.Context, idempotencyKey string) (FetchAddrIdempotencyKeyRow, error) {
+	row := q.db.QueryRowContext(ctx, fetchAddrIdempotencyKey, idempotencyKey)
+	var i FetchAddrIdempotencyKeyRow
+	err := row.Scan(&i.ParamsHash, &i.TaprootOutputKey)
+	return i, err
+}
+
 const fetchAddrs = `-- name: FetchAddrs :many
 SELECT 
     version, asset_version, genesis_asset_id, group_key, tapscript_sibling,
     taproot_output_key, amount, asset_type, creation_time, managed_from,
-    proof_courier_addr,
+    proof_courier_addr, min_amount,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key AS raw_script_key,
@@ -181,6 +207,7 @@ type FetchAddrsRow struct {
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
 	ProofCourierAddr []byte
+	MinAmount        sql.NullInt64
 	TweakedScriptKey []byte
 	ScriptKeyTweak   []byte
 	RawScriptKey     []byte
@@ -218,6 +245,7 @@ func (q *Queries) FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]Fetch
 			&i.CreationTime,
 			&i.ManagedFrom,
 			&i.ProofCourierAddr,
+			&i.MinAmount,
 			&i.TweakedScriptKey,
 			&i.ScriptKeyTweak,
 			&i.RawScriptKey,
@@ -240,12 +268,48 @@ func (q *Queries) FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]Fetch
 	return items, nil
 }
 
+const fetchKeyRanges = `-- name: FetchKeyRanges :many
+SELECT id, key_family, first_index, last_index, purpose, creation_time
+FROM key_ranges
+ORDER BY key_family, first_index
+`
+
+func (q *Queries) FetchKeyRanges(ctx context.Context) ([]KeyRange, error) {
+	rows, err := q.db.QueryContext(ctx, fetchKeyRanges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []KeyRange
+	for rows.Next() {
+		var i KeyRange
+		if err := rows.Scan(
+			&i.ID,
+			&i.KeyFamily,
+			&i.FirstIndex,
+			&i.LastIndex,
+			&i.Purpose,
+			&i.CreationTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAddr = `-- name: InsertAddr :one
 INSERT INTO addrs (
     version, asset_version, genesis_asset_id, group_key, script_key_id,
     taproot_key_id, tapscript_sibling, taproot_output_key, amount, asset_type,
-    creation_time, proof_courier_addr
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id
+    creation_time, proof_courier_addr, min_amount
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id
 `
 
 type InsertAddrParams struct {
@@ -261,6 +325,7 @@ type InsertAddrParams struct {
 	AssetType        int16
 	CreationTime     time.Time
 	ProofCourierAddr []byte
+	MinAmount        sql.NullInt64
 }
 
 func (q *Queries) InsertAddr(ctx context.Context, arg InsertAddrParams) (int64, error) {
@@ -277,12 +342,60 @@ func (q *Queries) InsertAddr(ctx context.Context, arg InsertAddrParams) (int64,
 		arg.AssetType,
 		arg.CreationTime,
 		arg.ProofCourierAddr,
+		arg.MinAmount,
 	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
+const insertAddrIdempotencyKey = `-- name: InsertAddrIdempotencyKey :exec
+INSERT INTO addr_idempotency_keys (
+    idempotency_key, params_hash, addr_id
+) VALUES (
+    $1, $2,
+    (SELECT id FROM addrs WHERE taproot_output_key = $3)
+)
+`
+
+type InsertAddrIdempotencyKeyParams struct {
+	IdempotencyKey   string
+	ParamsHash       []byte
+	TaprootOutputKey []byte
+}
+
+func (q *Queries) InsertAddrIdempotencyKey(ctx context.Context, arg InsertAddrIdempotencyKeyParams) error {
+	_, err := q.db.ExecContext(ctx, insertAddrIdempotencyKey, arg.IdempotencyKey, arg.ParamsHash, arg.TaprootOutputKey)
+	return err
+}
+
+const insertKeyRange = `-- name: InsertKeyRange :exec
+INSERT INTO key_ranges (
+    key_family, first_index, last_index, purpose, creation_time
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+`
+
+type InsertKeyRangeParams struct {
+	KeyFamily    int32
+	FirstIndex   int32
+	LastIndex    int32
+	Purpose      string
+	CreationTime time.Time
+}
+
+func (q *Queries) InsertKeyRange(ctx context.Context, arg InsertKeyRangeParams) error {
+	_, err := q.db.ExecContext(ctx, insertKeyRange,
+		arg.KeyFamily,
+		arg.FirstIndex,
+		arg.LastIndex,
+		arg.Purpose,
+		arg.CreationTime,
+	)
+	return err
+}
+
 const queryEventIDs = `-- name: QueryEventIDs :many
 SELECT
     addr_events.id as event_id, addrs.taproot_output_key as taproot_output_key
@@ -292,6 +405,12 @@ JOIN addrs
 WHERE addr_events.status >= $1 
   AND addr_events.status <= $2
   AND COALESCE($3, addrs.taproot_output_key) = addrs.taproot_output_key
+  AND ($4 IS NULL OR EXISTS (
+      SELECT 1
+      FROM script_keys
+      WHERE script_keys.script_key_id = addrs.script_key_id
+        AND substr(script_keys.tweaked_script_key, 2) = $4
+  ))
 ORDER by addr_events.creation_time
 `
 
@@ -299,6 +418,7 @@ type QueryEventIDsParams struct {
 	StatusFrom     int16
 	StatusTo       int16
 	AddrTaprootKey []byte
+	ScriptKey      []byte
 }
 
 type QueryEventIDsRow struct {
@@ -307,7 +427,12 @@ type QueryEventIDsRow struct {
 }
 
 func (q *Queries) QueryEventIDs(ctx context.Context, arg QueryEventIDsParams) ([]QueryEventIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryEventIDs, arg.StatusFrom, arg.StatusTo, arg.AddrTaprootKey)
+	rows, err := q.db.QueryContext(ctx, queryEventIDs,
+		arg.StatusFrom,
+		arg.StatusTo,
+		arg.AddrTaprootKey,
+		arg.ScriptKey,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -350,6 +475,38 @@ func (q *Queries) SetAddrManaged(ctx context.Context, arg SetAddrManagedParams)
 	return err
 }
 
+const setAddrEventAssetAmt = `-- name: SetAddrEventAssetAmt :exec
+UPDATE addr_events
+SET asset_amt = $2
+WHERE id = $1
+`
+
+type SetAddrEventAssetAmtParams struct {
+	ID       int64
+	AssetAmt sql.NullInt64
+}
+
+func (q *Queries) SetAddrEventAssetAmt(ctx context.Context, arg SetAddrEventAssetAmtParams) error {
+	_, err := q.db.ExecContext(ctx, setAddrEventAssetAmt, arg.ID, arg.AssetAmt)
+	return err
+}
+
+const setAddrEventQuarantined = `-- name: SetAddrEventQuarantined :exec
+UPDATE addr_events
+SET quarantined = $2
+WHERE id = $1
+`
+
+type SetAddrEventQuarantinedParams struct {
+	ID          int64
+	Quarantined bool
+}
+
+func (q *Queries) SetAddrEventQuarantined(ctx context.Context, arg SetAddrEventQuarantinedParams) error {
+	_, err := q.db.ExecContext(ctx, setAddrEventQuarantined, arg.ID, arg.Quarantined)
+	return err
+}
+
 const upsertAddrEvent = `-- name: UpsertAddrEvent :one
 WITH target_addr(addr_id) AS (
     SELECT id
@@ -362,15 +519,17 @@ WITH target_addr(addr_id) AS (
 )
 INSERT INTO addr_events (
     creation_time, addr_id, status, chain_txn_id, chain_txn_output_index,
-    managed_utxo_id, asset_proof_id, asset_id
+    managed_utxo_id, asset_proof_id, asset_id, asset_amt
 ) VALUES (
     $3, (SELECT addr_id FROM target_addr), $4,
-    (SELECT txn_id FROM target_chain_txn), $5, $6, $7, $8
+    (SELECT txn_id FROM target_chain_txn), $5, $6, $7, $8,
+    $9
 )
 ON CONFLICT (addr_id, chain_txn_id, chain_txn_output_index)
     DO UPDATE SET status = EXCLUDED.status,
                   asset_proof_id = COALESCE(EXCLUDED.asset_proof_id, addr_events.asset_proof_id),
-                  asset_id = COALESCE(EXCLUDED.asset_id, addr_events.asset_id)
+                  asset_id = COALESCE(EXCLUDED.asset_id, addr_events.asset_id),
+                  asset_amt = COALESCE(EXCLUDED.asset_amt, addr_events.asset_amt)
 RETURNING id
 `
 
@@ -383,6 +542,7 @@ type UpsertAddrEventParams struct {
 	ManagedUtxoID       int64
 	AssetProofID        sql.NullInt64
 	AssetID             sql.NullInt64
+	AssetAmt            sql.NullInt64
 }
 
 func (q *Queries) UpsertAddrEvent(ctx context.Context, arg UpsertAddrEventParams) (int64, error) {
@@ -395,6 +555,7 @@ func (q *Queries) UpsertAddrEvent(ctx context.Context, arg UpsertAddrEventParams
 		arg.ManagedUtxoID,
 		arg.AssetProofID,
 		arg.AssetID,
+		arg.AssetAmt,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -12,7 +12,7 @@ import (
 )
 
 const allAssets = `-- name: AllAssets :many
-SELECT asset_id, genesis_id, version, script_key_id, asset_group_witness_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent 
+SELECT asset_id, genesis_id, version, script_key_id, asset_group_witness_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, acquired_at 
 FROM assets
 `
 
@@ -39,6 +39,7 @@ func (q *Queries) AllAssets(ctx context.Context) ([]Asset, error) {
 			&i.SplitCommitmentRootValue,
 			&i.AnchorUtxoID,
 			&i.Spent,
+			&i.AcquiredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -87,24 +88,26 @@ func (q *Queries) AllInternalKeys(ctx context.Context) ([]InternalKey, error) {
 }
 
 const allMintingBatches = `-- name: AllMintingBatches :many
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index 
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, finalize_time_unix, finalize_min_seedlings, key_id, raw_key, key_family, key_index 
 FROM asset_minting_batches
 JOIN internal_keys 
 ON asset_minting_batches.batch_id = internal_keys.key_id
 `
 
 type AllMintingBatchesRow struct {
-	BatchID           int64
-	BatchState        int16
-	MintingTxPsbt     []byte
-	ChangeOutputIndex sql.NullInt32
-	GenesisID         sql.NullInt64
-	HeightHint        int32
-	CreationTimeUnix  time.Time
-	KeyID             int64
-	RawKey            []byte
-	KeyFamily         int32
-	KeyIndex          int32
+	BatchID              int64
+	BatchState           int16
+	MintingTxPsbt        []byte
+	ChangeOutputIndex    sql.NullInt32
+	GenesisID            sql.NullInt64
+	HeightHint           int32
+	CreationTimeUnix     time.Time
+	FinalizeTimeUnix     sql.NullTime
+	FinalizeMinSeedlings sql.NullInt32
+	KeyID                int64
+	RawKey               []byte
+	KeyFamily            int32
+	KeyIndex             int32
 }
 
 func (q *Queries) AllMintingBatches(ctx context.Context) ([]AllMintingBatchesRow, error) {
@@ -124,6 +127,8 @@ func (q *Queries) AllMintingBatches(ctx context.Context) ([]AllMintingBatchesRow
 			&i.GenesisID,
 			&i.HeightHint,
 			&i.CreationTimeUnix,
+			&i.FinalizeTimeUnix,
+			&i.FinalizeMinSeedlings,
 			&i.KeyID,
 			&i.RawKey,
 			&i.KeyFamily,
@@ -413,6 +418,41 @@ func (q *Queries) ConfirmChainTx(ctx context.Context, arg ConfirmChainTxParams)
 	return err
 }
 
+const deleteAnchoredAsset = `-- name: DeleteAnchoredAsset :exec
+DELETE FROM assets
+WHERE script_key_id = $1 AND anchor_utxo_id = $2
+`
+
+type DeleteAnchoredAssetParams struct {
+	ScriptKeyID  int64
+	AnchorUtxoID sql.NullInt64
+}
+
+func (q *Queries) DeleteAnchoredAsset(ctx context.Context, arg DeleteAnchoredAssetParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAnchoredAsset, arg.ScriptKeyID, arg.AnchorUtxoID)
+	return err
+}
+
+const deleteAnchoredAssetProof = `-- name: DeleteAnchoredAssetProof :exec
+DELETE FROM asset_proofs
+WHERE asset_id IN (
+    SELECT asset_id
+    FROM assets
+    WHERE script_key_id = $1
+      AND anchor_utxo_id = $2
+)
+`
+
+type DeleteAnchoredAssetProofParams struct {
+	ScriptKeyID  int64
+	AnchorUtxoID sql.NullInt64
+}
+
+func (q *Queries) DeleteAnchoredAssetProof(ctx context.Context, arg DeleteAnchoredAssetProofParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAnchoredAssetProof, arg.ScriptKeyID, arg.AnchorUtxoID)
+	return err
+}
+
 const deleteExpiredUTXOLeases = `-- name: DeleteExpiredUTXOLeases :exec
 UPDATE managed_utxos
 SET lease_owner = NULL, lease_expiry = NULL
@@ -426,6 +466,21 @@ func (q *Queries) DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime)
 	return err
 }
 
+const deleteGroupKeyRotations = `-- name: DeleteGroupKeyRotations :exec
+DELETE FROM group_key_rotations
+WHERE group_key = $1 AND sequence >= $2
+`
+
+type DeleteGroupKeyRotationsParams struct {
+	GroupKey []byte
+	Sequence int32
+}
+
+func (q *Queries) DeleteGroupKeyRotations(ctx context.Context, arg DeleteGroupKeyRotationsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupKeyRotations, arg.GroupKey, arg.Sequence)
+	return err
+}
+
 const deleteManagedUTXO = `-- name: DeleteManagedUTXO :exec
 DELETE FROM managed_utxos
 WHERE outpoint = $1
@@ -447,46 +502,122 @@ func (q *Queries) DeleteUTXOLease(ctx context.Context, outpoint []byte) error {
 	return err
 }
 
+const deleteUTXOLeaseByOwner = `-- name: DeleteUTXOLeaseByOwner :execrows
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE outpoint = $1 AND lease_owner = $2
+`
+
+type DeleteUTXOLeaseByOwnerParams struct {
+	Outpoint   []byte
+	LeaseOwner []byte
+}
+
+func (q *Queries) DeleteUTXOLeaseByOwner(ctx context.Context, arg DeleteUTXOLeaseByOwnerParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUTXOLeaseByOwner, arg.Outpoint, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const fetchAllScriptKeys = `-- name: FetchAllScriptKeys :many
+SELECT tweaked_script_key, tweak, raw_key, key_family, key_index
+FROM script_keys
+JOIN internal_keys
+  ON script_keys.internal_key_id = internal_keys.key_id
+ORDER BY script_key_id
+`
+
+type FetchAllScriptKeysRow struct {
+	TweakedScriptKey []byte
+	Tweak            []byte
+	RawKey           []byte
+	KeyFamily        int32
+	KeyIndex         int32
+}
+
+func (q *Queries) FetchAllScriptKeys(ctx context.Context) ([]FetchAllScriptKeysRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchAllScriptKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchAllScriptKeysRow
+	for rows.Next() {
+		var i FetchAllScriptKeysRow
+		if err := rows.Scan(
+			&i.TweakedScriptKey,
+			&i.Tweak,
+			&i.RawKey,
+			&i.KeyFamily,
+			&i.KeyIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchAssetMeta = `-- name: FetchAssetMeta :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM assets_meta
 WHERE meta_id = $1
 `
 
 type FetchAssetMetaRow struct {
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaDataHash  []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	MetaSupplyCap sql.NullInt64
 }
 
 func (q *Queries) FetchAssetMeta(ctx context.Context, metaID int64) (FetchAssetMetaRow, error) {
 	row := q.db.QueryRowContext(ctx, fetchAssetMeta, metaID)
 	var i FetchAssetMetaRow
-	err := row.Scan(&i.MetaDataHash, &i.MetaDataBlob, &i.MetaDataType)
+	err := row.Scan(
+		&i.MetaDataHash,
+		&i.MetaDataBlob,
+		&i.MetaDataType,
+		&i.MetaSupplyCap,
+	)
 	return i, err
 }
 
 const fetchAssetMetaByHash = `-- name: FetchAssetMetaByHash :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM assets_meta
 WHERE meta_data_hash = $1
 `
 
 type FetchAssetMetaByHashRow struct {
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaDataHash  []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	MetaSupplyCap sql.NullInt64
 }
 
 func (q *Queries) FetchAssetMetaByHash(ctx context.Context, metaDataHash []byte) (FetchAssetMetaByHashRow, error) {
 	row := q.db.QueryRowContext(ctx, fetchAssetMetaByHash, metaDataHash)
 	var i FetchAssetMetaByHashRow
-	err := row.Scan(&i.MetaDataHash, &i.MetaDataBlob, &i.MetaDataType)
+	err := row.Scan(
+		&i.MetaDataHash,
+		&i.MetaDataBlob,
+		&i.MetaDataType,
+		&i.MetaSupplyCap,
+	)
 	return i, err
 }
 
 const fetchAssetMetaForAsset = `-- name: FetchAssetMetaForAsset :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM genesis_assets assets
 JOIN assets_meta
     ON assets.meta_data_id = assets_meta.meta_id
@@ -494,15 +625,21 @@ WHERE assets.asset_id = $1
 `
 
 type FetchAssetMetaForAssetRow struct {
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaDataHash  []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	MetaSupplyCap sql.NullInt64
 }
 
 func (q *Queries) FetchAssetMetaForAsset(ctx context.Context, assetID []byte) (FetchAssetMetaForAssetRow, error) {
 	row := q.db.QueryRowContext(ctx, fetchAssetMetaForAsset, assetID)
 	var i FetchAssetMetaForAssetRow
-	err := row.Scan(&i.MetaDataHash, &i.MetaDataBlob, &i.MetaDataType)
+	err := row.Scan(
+		&i.MetaDataHash,
+		&i.MetaDataBlob,
+		&i.MetaDataType,
+		&i.MetaSupplyCap,
+	)
 	return i, err
 }
 
@@ -677,7 +814,7 @@ func (q *Queries) FetchAssetWitnesses(ctx context.Context, assetID sql.NullInt64
 }
 
 const fetchAssetsByAnchorTx = `-- name: FetchAssetsByAnchorTx :many
-SELECT asset_id, genesis_id, version, script_key_id, asset_group_witness_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent
+SELECT asset_id, genesis_id, version, script_key_id, asset_group_witness_id, script_version, amount, lock_time, relative_lock_time, split_commitment_root_hash, split_commitment_root_value, anchor_utxo_id, spent, acquired_at
 FROM assets
 WHERE anchor_utxo_id = $1
 `
@@ -705,6 +842,7 @@ func (q *Queries) FetchAssetsByAnchorTx(ctx context.Context, anchorUtxoID sql.Nu
 			&i.SplitCommitmentRootValue,
 			&i.AnchorUtxoID,
 			&i.Spent,
+			&i.AcquiredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -887,6 +1025,123 @@ func (q *Queries) FetchChainTx(ctx context.Context, txid []byte) (ChainTxn, erro
 	return i, err
 }
 
+const fetchGenesesWithMeta = `-- name: FetchGenesesWithMeta :many
+SELECT
+    asset_id, asset_tag, output_index, asset_type,
+    genesis_points.prev_out prev_out, assets_meta.meta_data_hash,
+    assets_meta.meta_data_type, assets_meta.meta_data_blob,
+    assets_meta.meta_supply_cap
+FROM genesis_assets
+JOIN assets_meta
+    ON genesis_assets.meta_data_id = assets_meta.meta_id
+JOIN genesis_points
+    ON genesis_assets.genesis_point_id = genesis_points.genesis_id
+WHERE assets_meta.meta_data_blob IS NOT NULL
+ORDER BY gen_asset_id
+`
+
+type FetchGenesesWithMetaRow struct {
+	AssetID       []byte
+	AssetTag      string
+	OutputIndex   int32
+	AssetType     int16
+	PrevOut       []byte
+	MetaDataHash  []byte
+	MetaDataType  sql.NullInt16
+	MetaDataBlob  []byte
+	MetaSupplyCap sql.NullInt64
+}
+
+func (q *Queries) FetchGenesesWithMeta(ctx context.Context) ([]FetchGenesesWithMetaRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchGenesesWithMeta)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchGenesesWithMetaRow
+	for rows.Next() {
+		var i FetchGenesesWithMetaRow
+		if err := rows.Scan(
+			&i.AssetID,
+			&i.AssetTag,
+			&i.OutputIndex,
+			&i.AssetType,
+			&i.PrevOut,
+			&i.MetaDataHash,
+			&i.MetaDataType,
+			&i.MetaDataBlob,
+			&i.MetaSupplyCap,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchFeeBumpAnchorFootprint = `-- name: FetchFeeBumpAnchorFootprint :many
+SELECT txns.txid, txns.block_height, anchors.output_index, anchors.amt_sats,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM transfer_fee_bump_anchors anchors
+JOIN asset_transfers transfers
+    ON anchors.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+JOIN internal_keys keys
+    ON anchors.internal_key_id = keys.key_id
+WHERE anchors.child_txid IS NULL AND transfers.cancelled = FALSE
+ORDER BY anchors.id
+`
+
+type FetchFeeBumpAnchorFootprintRow struct {
+	Txid        []byte
+	BlockHeight sql.NullInt32
+	OutputIndex int32
+	AmtSats     int64
+	RawKey      []byte
+	KeyFamily   int32
+	KeyIndex    int32
+}
+
+// Fee bump anchors that were spent by a child transaction or belong to a
+// cancelled transfer are no longer part of the on-chain footprint.
+func (q *Queries) FetchFeeBumpAnchorFootprint(ctx context.Context) ([]FetchFeeBumpAnchorFootprintRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchFeeBumpAnchorFootprint)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchFeeBumpAnchorFootprintRow
+	for rows.Next() {
+		var i FetchFeeBumpAnchorFootprintRow
+		if err := rows.Scan(
+			&i.Txid,
+			&i.BlockHeight,
+			&i.OutputIndex,
+			&i.AmtSats,
+			&i.RawKey,
+			&i.KeyFamily,
+			&i.KeyIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchGenesisByAssetID = `-- name: FetchGenesisByAssetID :one
 SELECT gen_asset_id, asset_id, asset_tag, meta_hash, output_index, asset_type, prev_out, block_height 
 FROM genesis_info_view
@@ -1077,6 +1332,53 @@ func (q *Queries) FetchGroupByGroupKey(ctx context.Context, groupKey []byte) (Fe
 	return i, err
 }
 
+const fetchGroupKeyRotations = `-- name: FetchGroupKeyRotations :many
+SELECT rotations.sequence, rotations.rotation, keys.raw_key AS new_raw_key,
+    keys.key_family AS new_key_family, keys.key_index AS new_key_index
+FROM group_key_rotations rotations
+LEFT JOIN internal_keys keys
+    ON rotations.new_key_id = keys.key_id
+WHERE rotations.group_key = $1
+ORDER BY rotations.sequence
+`
+
+type FetchGroupKeyRotationsRow struct {
+	Sequence     int32
+	Rotation     []byte
+	NewRawKey    []byte
+	NewKeyFamily sql.NullInt32
+	NewKeyIndex  sql.NullInt32
+}
+
+func (q *Queries) FetchGroupKeyRotations(ctx context.Context, groupKey []byte) ([]FetchGroupKeyRotationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchGroupKeyRotations, groupKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchGroupKeyRotationsRow
+	for rows.Next() {
+		var i FetchGroupKeyRotationsRow
+		if err := rows.Scan(
+			&i.Sequence,
+			&i.Rotation,
+			&i.NewRawKey,
+			&i.NewKeyFamily,
+			&i.NewKeyIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchGroupedAssets = `-- name: FetchGroupedAssets :many
 SELECT
     assets.asset_id AS asset_primary_key,
@@ -1144,6 +1446,24 @@ func (q *Queries) FetchGroupedAssets(ctx context.Context) ([]FetchGroupedAssetsR
 	return items, nil
 }
 
+const fetchInternalKeyLocator = `-- name: FetchInternalKeyLocator :one
+SELECT key_family, key_index
+FROM internal_keys
+WHERE raw_key = $1
+`
+
+type FetchInternalKeyLocatorRow struct {
+	KeyFamily int32
+	KeyIndex  int32
+}
+
+func (q *Queries) FetchInternalKeyLocator(ctx context.Context, rawKey []byte) (FetchInternalKeyLocatorRow, error) {
+	row := q.db.QueryRowContext(ctx, fetchInternalKeyLocator, rawKey)
+	var i FetchInternalKeyLocatorRow
+	err := row.Scan(&i.KeyFamily, &i.KeyIndex)
+	return i, err
+}
+
 const fetchManagedUTXO = `-- name: FetchManagedUTXO :one
 SELECT utxo_id, outpoint, amt_sats, internal_key_id, taproot_asset_root, tapscript_sibling, merkle_root, txn_id, lease_owner, lease_expiry, key_id, raw_key, key_family, key_index
 FROM managed_utxos utxos
@@ -1273,7 +1593,7 @@ WITH target_batch AS (
         ON batches.batch_id = keys.key_id
     WHERE keys.raw_key = $1
 )
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, finalize_time_unix, finalize_min_seedlings, key_id, raw_key, key_family, key_index
 FROM asset_minting_batches batches
 JOIN internal_keys keys
     ON batches.batch_id = keys.key_id
@@ -1281,17 +1601,19 @@ WHERE batch_id in (SELECT batch_id FROM target_batch)
 `
 
 type FetchMintingBatchRow struct {
-	BatchID           int64
-	BatchState        int16
-	MintingTxPsbt     []byte
-	ChangeOutputIndex sql.NullInt32
-	GenesisID         sql.NullInt64
-	HeightHint        int32
-	CreationTimeUnix  time.Time
-	KeyID             int64
-	RawKey            []byte
-	KeyFamily         int32
-	KeyIndex          int32
+	BatchID              int64
+	BatchState           int16
+	MintingTxPsbt        []byte
+	ChangeOutputIndex    sql.NullInt32
+	GenesisID            sql.NullInt64
+	HeightHint           int32
+	CreationTimeUnix     time.Time
+	FinalizeTimeUnix     sql.NullTime
+	FinalizeMinSeedlings sql.NullInt32
+	KeyID                int64
+	RawKey               []byte
+	KeyFamily            int32
+	KeyIndex             int32
 }
 
 func (q *Queries) FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMintingBatchRow, error) {
@@ -1305,6 +1627,8 @@ func (q *Queries) FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMi
 		&i.GenesisID,
 		&i.HeightHint,
 		&i.CreationTimeUnix,
+		&i.FinalizeTimeUnix,
+		&i.FinalizeMinSeedlings,
 		&i.KeyID,
 		&i.RawKey,
 		&i.KeyFamily,
@@ -1314,7 +1638,7 @@ func (q *Queries) FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMi
 }
 
 const fetchMintingBatchesByInverseState = `-- name: FetchMintingBatchesByInverseState :many
-SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, key_id, raw_key, key_family, key_index
+SELECT batch_id, batch_state, minting_tx_psbt, change_output_index, genesis_id, height_hint, creation_time_unix, finalize_time_unix, finalize_min_seedlings, key_id, raw_key, key_family, key_index
 FROM asset_minting_batches batches
 JOIN internal_keys keys
     ON batches.batch_id = keys.key_id
@@ -1322,17 +1646,19 @@ WHERE batches.batch_state != $1
 `
 
 type FetchMintingBatchesByInverseStateRow struct {
-	BatchID           int64
-	BatchState        int16
-	MintingTxPsbt     []byte
-	ChangeOutputIndex sql.NullInt32
-	GenesisID         sql.NullInt64
-	HeightHint        int32
-	CreationTimeUnix  time.Time
-	KeyID             int64
-	RawKey            []byte
-	KeyFamily         int32
-	KeyIndex          int32
+	BatchID              int64
+	BatchState           int16
+	MintingTxPsbt        []byte
+	ChangeOutputIndex    sql.NullInt32
+	GenesisID            sql.NullInt64
+	HeightHint           int32
+	CreationTimeUnix     time.Time
+	FinalizeTimeUnix     sql.NullTime
+	FinalizeMinSeedlings sql.NullInt32
+	KeyID                int64
+	RawKey               []byte
+	KeyFamily            int32
+	KeyIndex             int32
 }
 
 func (q *Queries) FetchMintingBatchesByInverseState(ctx context.Context, batchState int16) ([]FetchMintingBatchesByInverseStateRow, error) {
@@ -1352,6 +1678,8 @@ func (q *Queries) FetchMintingBatchesByInverseState(ctx context.Context, batchSt
 			&i.GenesisID,
 			&i.HeightHint,
 			&i.CreationTimeUnix,
+			&i.FinalizeTimeUnix,
+			&i.FinalizeMinSeedlings,
 			&i.KeyID,
 			&i.RawKey,
 			&i.KeyFamily,
@@ -1370,6 +1698,96 @@ func (q *Queries) FetchMintingBatchesByInverseState(ctx context.Context, batchSt
 	return items, nil
 }
 
+const fetchOnChainFootprint = `-- name: FetchOnChainFootprint :many
+SELECT
+    utxos.utxo_id, utxos.outpoint, utxos.amt_sats, utxos.taproot_asset_root,
+    utxos.merkle_root, utxos.lease_owner, utxos.lease_expiry, keys.raw_key,
+    keys.key_family, keys.key_index, txns.block_height,
+    genesis.asset_id AS genesis_asset_id, assets.amount AS asset_amount,
+    script_keys.tweaked_script_key, assets.spent AS asset_spent
+FROM managed_utxos utxos
+JOIN internal_keys keys
+    ON utxos.internal_key_id = keys.key_id
+JOIN chain_txns txns
+    ON utxos.txn_id = txns.txn_id
+LEFT JOIN assets
+    ON assets.anchor_utxo_id = utxos.utxo_id
+LEFT JOIN genesis_assets genesis
+    ON assets.genesis_id = genesis.gen_asset_id
+LEFT JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+-- Outputs that were already spent by a transfer or a sweep are no longer part
+-- of the on-chain footprint.
+WHERE NOT EXISTS (
+          SELECT 1
+          FROM asset_transfer_inputs inputs
+          WHERE inputs.anchor_point = utxos.outpoint
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM anchor_sweeps sweeps
+          WHERE sweeps.utxo_id = utxos.utxo_id
+      )
+ORDER BY utxos.utxo_id, assets.asset_id
+`
+
+type FetchOnChainFootprintRow struct {
+	UtxoID           int64
+	Outpoint         []byte
+	AmtSats          int64
+	TaprootAssetRoot []byte
+	MerkleRoot       []byte
+	LeaseOwner       []byte
+	LeaseExpiry      sql.NullTime
+	RawKey           []byte
+	KeyFamily        int32
+	KeyIndex         int32
+	BlockHeight      sql.NullInt32
+	GenesisAssetID   []byte
+	AssetAmount      sql.NullInt64
+	TweakedScriptKey []byte
+	AssetSpent       sql.NullBool
+}
+
+func (q *Queries) FetchOnChainFootprint(ctx context.Context) ([]FetchOnChainFootprintRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchOnChainFootprint)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchOnChainFootprintRow
+	for rows.Next() {
+		var i FetchOnChainFootprintRow
+		if err := rows.Scan(
+			&i.UtxoID,
+			&i.Outpoint,
+			&i.AmtSats,
+			&i.TaprootAssetRoot,
+			&i.MerkleRoot,
+			&i.LeaseOwner,
+			&i.LeaseExpiry,
+			&i.RawKey,
+			&i.KeyFamily,
+			&i.KeyIndex,
+			&i.BlockHeight,
+			&i.GenesisAssetID,
+			&i.AssetAmount,
+			&i.TweakedScriptKey,
+			&i.AssetSpent,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const fetchScriptKeyByTweakedKey = `-- name: FetchScriptKeyByTweakedKey :one
 SELECT tweak, raw_key, key_family, key_index
 FROM script_keys
@@ -1411,7 +1829,7 @@ func (q *Queries) FetchScriptKeyIDByTweakedKey(ctx context.Context, tweakedScrip
 }
 
 const fetchSeedlingByID = `-- name: FetchSeedlingByID :one
-SELECT seedling_id, asset_name, asset_version, asset_type, asset_supply, asset_meta_id, emission_enabled, batch_id, group_genesis_id, group_anchor_id
+SELECT seedling_id, asset_name, asset_version, asset_type, asset_supply, asset_meta_id, emission_enabled, batch_id, group_genesis_id, group_anchor_id, script_key_tapscript_root
 FROM asset_seedlings
 WHERE seedling_id = $1
 `
@@ -1430,6 +1848,7 @@ func (q *Queries) FetchSeedlingByID(ctx context.Context, seedlingID int64) (Asse
 		&i.BatchID,
 		&i.GroupGenesisID,
 		&i.GroupAnchorID,
+		&i.ScriptKeyTapscriptRoot,
 	)
 	return i, err
 }
@@ -1474,8 +1893,9 @@ WITH target_batch(batch_id) AS (
 )
 SELECT seedling_id, asset_name, asset_type, asset_version, asset_supply, 
     assets_meta.meta_data_hash, assets_meta.meta_data_type, 
-    assets_meta.meta_data_blob, emission_enabled, batch_id, 
-    group_genesis_id, group_anchor_id
+    assets_meta.meta_data_blob, assets_meta.meta_supply_cap,
+    emission_enabled, batch_id, 
+    group_genesis_id, group_anchor_id, script_key_tapscript_root
 FROM asset_seedlings 
 LEFT JOIN assets_meta
     ON asset_seedlings.asset_meta_id = assets_meta.meta_id
@@ -1483,18 +1903,20 @@ WHERE asset_seedlings.batch_id in (SELECT batch_id FROM target_batch)
 `
 
 type FetchSeedlingsForBatchRow struct {
-	SeedlingID      int64
-	AssetName       string
-	AssetType       int16
-	AssetVersion    int16
-	AssetSupply     int64
-	MetaDataHash    []byte
-	MetaDataType    sql.NullInt16
-	MetaDataBlob    []byte
-	EmissionEnabled bool
-	BatchID         int64
-	GroupGenesisID  sql.NullInt64
-	GroupAnchorID   sql.NullInt64
+	SeedlingID             int64
+	AssetName              string
+	AssetType              int16
+	AssetVersion           int16
+	AssetSupply            int64
+	MetaDataHash           []byte
+	MetaDataType           sql.NullInt16
+	MetaDataBlob           []byte
+	MetaSupplyCap          sql.NullInt64
+	EmissionEnabled        bool
+	BatchID                int64
+	GroupGenesisID         sql.NullInt64
+	GroupAnchorID          sql.NullInt64
+	ScriptKeyTapscriptRoot []byte
 }
 
 func (q *Queries) FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]FetchSeedlingsForBatchRow, error) {
@@ -1515,10 +1937,12 @@ func (q *Queries) FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]
 			&i.MetaDataHash,
 			&i.MetaDataType,
 			&i.MetaDataBlob,
+			&i.MetaSupplyCap,
 			&i.EmissionEnabled,
 			&i.BatchID,
 			&i.GroupGenesisID,
 			&i.GroupAnchorID,
+			&i.ScriptKeyTapscriptRoot,
 		); err != nil {
 			return nil, err
 		}
@@ -1533,6 +1957,129 @@ func (q *Queries) FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]
 	return items, nil
 }
 
+const fetchSweepableAnchors = `-- name: FetchSweepableAnchors :many
+SELECT
+    utxos.utxo_id, utxos.outpoint, utxos.amt_sats, utxos.merkle_root,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM managed_utxos utxos
+JOIN internal_keys keys
+    ON utxos.internal_key_id = keys.key_id
+JOIN chain_txns txns
+    ON utxos.txn_id = txns.txn_id
+WHERE utxos.amt_sats <= $1 AND
+      txns.block_height IS NOT NULL AND
+      (utxos.lease_owner IS NULL OR
+       utxos.lease_expiry IS NULL OR
+       utxos.lease_expiry <= $2) AND
+      -- Only outputs that anchor nothing but spent assets, such as
+      -- tombstones and burns, can be swept.
+      EXISTS (
+          SELECT 1
+          FROM assets
+          WHERE assets.anchor_utxo_id = utxos.utxo_id
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM assets
+          WHERE assets.anchor_utxo_id = utxos.utxo_id AND
+                assets.spent = FALSE
+      ) AND
+      -- Outputs that were already spent by a transfer or a sweep are left
+      -- alone.
+      NOT EXISTS (
+          SELECT 1
+          FROM asset_transfer_inputs inputs
+          WHERE inputs.anchor_point = utxos.outpoint
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM anchor_sweeps sweeps
+          WHERE sweeps.utxo_id = utxos.utxo_id
+      )
+ORDER BY utxos.utxo_id
+`
+
+type FetchSweepableAnchorsParams struct {
+	MaxAmtSats int64
+	Now        sql.NullTime
+}
+
+type FetchSweepableAnchorsRow struct {
+	UtxoID     int64
+	Outpoint   []byte
+	AmtSats    int64
+	MerkleRoot []byte
+	RawKey     []byte
+	KeyFamily  int32
+	KeyIndex   int32
+}
+
+func (q *Queries) FetchSweepableAnchors(ctx context.Context, arg FetchSweepableAnchorsParams) ([]FetchSweepableAnchorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchSweepableAnchors, arg.MaxAmtSats, arg.Now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchSweepableAnchorsRow
+	for rows.Next() {
+		var i FetchSweepableAnchorsRow
+		if err := rows.Scan(
+			&i.UtxoID,
+			&i.Outpoint,
+			&i.AmtSats,
+			&i.MerkleRoot,
+			&i.RawKey,
+			&i.KeyFamily,
+			&i.KeyIndex,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchTapscriptLeaves = `-- name: FetchTapscriptLeaves :many
+SELECT leaf_version, script
+FROM tapscript_leaves
+WHERE root_hash = $1
+ORDER BY leaf_index
+`
+
+type FetchTapscriptLeavesRow struct {
+	LeafVersion int16
+	Script      []byte
+}
+
+func (q *Queries) FetchTapscriptLeaves(ctx context.Context, rootHash []byte) ([]FetchTapscriptLeavesRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchTapscriptLeaves, rootHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchTapscriptLeavesRow
+	for rows.Next() {
+		var i FetchTapscriptLeavesRow
+		if err := rows.Scan(&i.LeafVersion, &i.Script); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const genesisAssets = `-- name: GenesisAssets :many
 SELECT gen_asset_id, asset_id, asset_tag, meta_data_id, output_index, asset_type, genesis_point_id 
 FROM genesis_assets
@@ -1597,26 +2144,48 @@ func (q *Queries) GenesisPoints(ctx context.Context) ([]GenesisPoint, error) {
 	return items, nil
 }
 
+const insertAnchorSweep = `-- name: InsertAnchorSweep :exec
+INSERT INTO anchor_sweeps (
+    utxo_id, sweep_txid, swept_at
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type InsertAnchorSweepParams struct {
+	UtxoID    int64
+	SweepTxid []byte
+	SweptAt   time.Time
+}
+
+func (q *Queries) InsertAnchorSweep(ctx context.Context, arg InsertAnchorSweepParams) error {
+	_, err := q.db.ExecContext(ctx, insertAnchorSweep, arg.UtxoID, arg.SweepTxid, arg.SweptAt)
+	return err
+}
+
 const insertAssetSeedling = `-- name: InsertAssetSeedling :exec
 INSERT INTO asset_seedlings (
     asset_name, asset_type, asset_version, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    script_key_tapscript_root
 ) VALUES (
    $1, $2, $3, $4, $5, $6, $7,
-   $8, $9
+   $8, $9,
+   $10
 )
 `
 
 type InsertAssetSeedlingParams struct {
-	AssetName       string
-	AssetType       int16
-	AssetVersion    int16
-	AssetSupply     int64
-	AssetMetaID     int64
-	EmissionEnabled bool
-	BatchID         int64
-	GroupGenesisID  sql.NullInt64
-	GroupAnchorID   sql.NullInt64
+	AssetName              string
+	AssetType              int16
+	AssetVersion           int16
+	AssetSupply            int64
+	AssetMetaID            int64
+	EmissionEnabled        bool
+	BatchID                int64
+	GroupGenesisID         sql.NullInt64
+	GroupAnchorID          sql.NullInt64
+	ScriptKeyTapscriptRoot []byte
 }
 
 func (q *Queries) InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedlingParams) error {
@@ -1630,6 +2199,7 @@ func (q *Queries) InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedli
 		arg.BatchID,
 		arg.GroupGenesisID,
 		arg.GroupAnchorID,
+		arg.ScriptKeyTapscriptRoot,
 	)
 	return err
 }
@@ -1647,24 +2217,27 @@ WITH target_key_id AS (
 )
 INSERT INTO asset_seedlings(
     asset_name, asset_type, asset_version, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    script_key_tapscript_root
 ) VALUES (
     $2, $3, $4, $5, $6, $7,
     (SELECT key_id FROM target_key_id),
-    $8, $9
+    $8, $9,
+    $10
 )
 `
 
 type InsertAssetSeedlingIntoBatchParams struct {
-	RawKey          []byte
-	AssetName       string
-	AssetType       int16
-	AssetVersion    int16
-	AssetSupply     int64
-	AssetMetaID     int64
-	EmissionEnabled bool
-	GroupGenesisID  sql.NullInt64
-	GroupAnchorID   sql.NullInt64
+	RawKey                 []byte
+	AssetName              string
+	AssetType              int16
+	AssetVersion           int16
+	AssetSupply            int64
+	AssetMetaID            int64
+	EmissionEnabled        bool
+	GroupGenesisID         sql.NullInt64
+	GroupAnchorID          sql.NullInt64
+	ScriptKeyTapscriptRoot []byte
 }
 
 func (q *Queries) InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAssetSeedlingIntoBatchParams) error {
@@ -1678,6 +2251,7 @@ func (q *Queries) InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAs
 		arg.EmissionEnabled,
 		arg.GroupGenesisID,
 		arg.GroupAnchorID,
+		arg.ScriptKeyTapscriptRoot,
 	)
 	return err
 }
@@ -1712,6 +2286,33 @@ func (q *Queries) InsertAssetWitness(ctx context.Context, arg InsertAssetWitness
 	return err
 }
 
+const insertGroupKeyRotation = `-- name: InsertGroupKeyRotation :exec
+INSERT INTO group_key_rotations (
+    group_key, sequence, block_height, rotation, new_key_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+`
+
+type InsertGroupKeyRotationParams struct {
+	GroupKey    []byte
+	Sequence    int32
+	BlockHeight int32
+	Rotation    []byte
+	NewKeyID    sql.NullInt64
+}
+
+func (q *Queries) InsertGroupKeyRotation(ctx context.Context, arg InsertGroupKeyRotationParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupKeyRotation,
+		arg.GroupKey,
+		arg.Sequence,
+		arg.BlockHeight,
+		arg.Rotation,
+		arg.NewKeyID,
+	)
+	return err
+}
+
 const insertNewAsset = `-- name: InsertNewAsset :one
 INSERT INTO assets (
     genesis_id, version, script_key_id, asset_group_witness_id, script_version, 
@@ -1769,12 +2370,56 @@ func (q *Queries) NewMintingBatch(ctx context.Context, arg NewMintingBatchParams
 	return err
 }
 
+const queryAnchorSweeps = `-- name: QueryAnchorSweeps :many
+SELECT utxos.outpoint, utxos.amt_sats, sweeps.sweep_txid, sweeps.swept_at
+FROM anchor_sweeps sweeps
+JOIN managed_utxos utxos
+    ON sweeps.utxo_id = utxos.utxo_id
+ORDER BY sweeps.id
+`
+
+type QueryAnchorSweepsRow struct {
+	Outpoint  []byte
+	AmtSats   int64
+	SweepTxid []byte
+	SweptAt   time.Time
+}
+
+func (q *Queries) QueryAnchorSweeps(ctx context.Context) ([]QueryAnchorSweepsRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryAnchorSweeps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryAnchorSweepsRow
+	for rows.Next() {
+		var i QueryAnchorSweepsRow
+		if err := rows.Scan(
+			&i.Outpoint,
+			&i.AmtSats,
+			&i.SweepTxid,
+			&i.SweptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryAssetBalancesByAsset = `-- name: QueryAssetBalancesByAsset :many
 SELECT
     genesis_info_view.asset_id, version, SUM(amount) balance,
     genesis_info_view.asset_tag, genesis_info_view.meta_hash,
     genesis_info_view.asset_type, genesis_info_view.output_index,
-    genesis_info_view.prev_out AS genesis_point
+    genesis_info_view.prev_out AS genesis_point,
+    key_group_info_view.tweaked_group_key
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
@@ -1786,18 +2431,19 @@ WHERE spent = FALSE
 GROUP BY assets.genesis_id, genesis_info_view.asset_id,
          version, genesis_info_view.asset_tag, genesis_info_view.meta_hash,
          genesis_info_view.asset_type, genesis_info_view.output_index,
-         genesis_info_view.prev_out
+         genesis_info_view.prev_out, key_group_info_view.tweaked_group_key
 `
 
 type QueryAssetBalancesByAssetRow struct {
-	AssetID      []byte
-	Version      int32
-	Balance      int64
-	AssetTag     string
-	MetaHash     []byte
-	AssetType    int16
-	OutputIndex  int32
-	GenesisPoint []byte
+	AssetID         []byte
+	Version         int32
+	Balance         int64
+	AssetTag        string
+	MetaHash        []byte
+	AssetType       int16
+	OutputIndex     int32
+	GenesisPoint    []byte
+	TweakedGroupKey []byte
 }
 
 // We use a LEFT JOIN here as not every asset has a group key, so this'll
@@ -1822,6 +2468,7 @@ func (q *Queries) QueryAssetBalancesByAsset(ctx context.Context, assetIDFilter [
 			&i.AssetType,
 			&i.OutputIndex,
 			&i.GenesisPoint,
+			&i.TweakedGroupKey,
 		); err != nil {
 			return nil, err
 		}
@@ -1897,6 +2544,7 @@ SELECT
     genesis_info_view.output_index AS genesis_output_index,
     genesis_info_view.asset_type,
     genesis_info_view.prev_out AS genesis_prev_out,
+    genesis_info_view.block_height AS genesis_block_height,
     txns.raw_tx AS anchor_tx,
     txns.txid AS anchor_txid,
     txns.block_hash AS anchor_block_hash,
@@ -1908,7 +2556,8 @@ SELECT
     utxos.lease_owner AS anchor_lease_owner,
     utxos.lease_expiry AS anchor_lease_expiry,
     utxo_internal_keys.raw_key AS anchor_internal_key,
-    split_commitment_root_hash, split_commitment_root_value
+    split_commitment_root_hash, split_commitment_root_value,
+    assets.acquired_at
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
@@ -1944,8 +2593,13 @@ WHERE (
     assets.amount >= COALESCE($7, assets.amount) AND
     assets.spent = COALESCE($8, assets.spent) AND
     (key_group_info_view.tweaked_group_key = $9 OR
-      $9 IS NULL)
+      $9 IS NULL) AND
+    (assets.acquired_at >= $10 OR
+      $10 IS NULL) AND
+    (assets.acquired_at < $11 OR
+      $11 IS NULL)
 )
+ORDER BY assets.genesis_id, assets.asset_id
 `
 
 type QueryAssetsParams struct {
@@ -1958,6 +2612,8 @@ type QueryAssetsParams struct {
 	MinAmt           sql.NullInt64
 	Spent            sql.NullBool
 	KeyGroupFilter   []byte
+	MinAcquiredAt    sql.NullTime
+	MaxAcquiredAt    sql.NullTime
 }
 
 type QueryAssetsRow struct {
@@ -1986,6 +2642,7 @@ type QueryAssetsRow struct {
 	GenesisOutputIndex       int32
 	AssetType                int16
 	GenesisPrevOut           []byte
+	GenesisBlockHeight       sql.NullInt32
 	AnchorTx                 []byte
 	AnchorTxid               []byte
 	AnchorBlockHash          []byte
@@ -1999,6 +2656,7 @@ type QueryAssetsRow struct {
 	AnchorInternalKey        []byte
 	SplitCommitmentRootHash  []byte
 	SplitCommitmentRootValue sql.NullInt64
+	AcquiredAt               sql.NullTime
 }
 
 // We use a LEFT JOIN here as not every asset has a group key, so this'll
@@ -2009,6 +2667,9 @@ type QueryAssetsRow struct {
 // channel balances, and also coin selection. We use the sqlc.narg feature to
 // make the entire statement evaluate to true, if none of these extra args are
 // specified.
+// We order by the genesis first, so assets of the same tranche are grouped
+// together and tranches with the same genesis height are always returned in
+// the same order.
 func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]QueryAssetsRow, error) {
 	rows, err := q.db.QueryContext(ctx, queryAssets,
 		arg.AssetIDFilter,
@@ -2020,6 +2681,8 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 		arg.MinAmt,
 		arg.Spent,
 		arg.KeyGroupFilter,
+		arg.MinAcquiredAt,
+		arg.MaxAcquiredAt,
 	)
 	if err != nil {
 		return nil, err
@@ -2054,6 +2717,7 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 			&i.GenesisOutputIndex,
 			&i.AssetType,
 			&i.GenesisPrevOut,
+			&i.GenesisBlockHeight,
 			&i.AnchorTx,
 			&i.AnchorTxid,
 			&i.AnchorBlockHash,
@@ -2067,6 +2731,7 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 			&i.AnchorInternalKey,
 			&i.SplitCommitmentRootHash,
 			&i.SplitCommitmentRootValue,
+			&i.AcquiredAt,
 		); err != nil {
 			return nil, err
 		}
@@ -2081,6 +2746,166 @@ func (q *Queries) QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]Que
 	return items, nil
 }
 
+const queryUTXOLeases = `-- name: QueryUTXOLeases :many
+SELECT utxos.outpoint, utxos.amt_sats, utxos.lease_owner, utxos.lease_expiry,
+    (
+        -- A leased UTXO that is spent by a transfer that is still pending
+        -- is locked by that transfer.
+        SELECT txns.txid
+        FROM asset_transfer_inputs inputs
+        JOIN asset_transfers transfers
+            ON inputs.transfer_id = transfers.id
+        JOIN chain_txns txns
+            ON transfers.anchor_txn_id = txns.txn_id
+        WHERE inputs.anchor_point = utxos.outpoint
+        ORDER BY transfers.id DESC
+        LIMIT 1
+    ) AS transfer_anchor_txid
+FROM managed_utxos utxos
+WHERE utxos.lease_owner IS NOT NULL AND
+      utxos.lease_expiry IS NOT NULL AND
+      utxos.lease_expiry >= $1
+ORDER BY utxos.lease_expiry
+`
+
+type QueryUTXOLeasesRow struct {
+	Outpoint           []byte
+	AmtSats            int64
+	LeaseOwner         []byte
+	LeaseExpiry        sql.NullTime
+	TransferAnchorTxid []byte
+}
+
+func (q *Queries) QueryUTXOLeases(ctx context.Context, now sql.NullTime) ([]QueryUTXOLeasesRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryUTXOLeases, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryUTXOLeasesRow
+	for rows.Next() {
+		var i QueryUTXOLeasesRow
+		if err := rows.Scan(
+			&i.Outpoint,
+			&i.AmtSats,
+			&i.LeaseOwner,
+			&i.LeaseExpiry,
+			&i.TransferAnchorTxid,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryUnspentAssetProofs = `-- name: QueryUnspentAssetProofs :many
+SELECT
+    assets.asset_id AS asset_primary_key, genesis_assets.asset_id,
+    assets.amount, script_keys.tweaked_script_key AS script_key,
+    managed_utxos.outpoint AS anchor_outpoint, asset_proofs.proof_file
+FROM assets
+JOIN genesis_assets
+    ON assets.genesis_id = genesis_assets.gen_asset_id
+JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+JOIN managed_utxos
+    ON assets.anchor_utxo_id = managed_utxos.utxo_id
+-- We use a LEFT JOIN here, as we also want to know about unspent assets that
+-- are missing their proof.
+LEFT JOIN asset_proofs
+    ON asset_proofs.asset_id = assets.asset_id
+WHERE assets.spent = FALSE
+ORDER BY assets.asset_id
+`
+
+type QueryUnspentAssetProofsRow struct {
+	AssetPrimaryKey int64
+	AssetID         []byte
+	Amount          int64
+	ScriptKey       []byte
+	AnchorOutpoint  []byte
+	ProofFile       []byte
+}
+
+func (q *Queries) QueryUnspentAssetProofs(ctx context.Context) ([]QueryUnspentAssetProofsRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryUnspentAssetProofs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryUnspentAssetProofsRow
+	for rows.Next() {
+		var i QueryUnspentAssetProofsRow
+		if err := rows.Scan(
+			&i.AssetPrimaryKey,
+			&i.AssetID,
+			&i.Amount,
+			&i.ScriptKey,
+			&i.AnchorOutpoint,
+			&i.ProofFile,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replaceChainAnchorTx = `-- name: ReplaceChainAnchorTx :execrows
+UPDATE chain_txns
+SET txid = $1, raw_tx = $2, chain_fees = $3
+WHERE txid = $4 AND block_hash IS NULL
+`
+
+type ReplaceChainAnchorTxParams struct {
+	NewTxid   []byte
+	RawTx     []byte
+	ChainFees int64
+	OldTxid   []byte
+}
+
+func (q *Queries) ReplaceChainAnchorTx(ctx context.Context, arg ReplaceChainAnchorTxParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, replaceChainAnchorTx,
+		arg.NewTxid,
+		arg.RawTx,
+		arg.ChainFees,
+		arg.OldTxid,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setAssetAcquiredAt = `-- name: SetAssetAcquiredAt :exec
+UPDATE assets
+SET acquired_at = $1
+WHERE asset_id = $2
+`
+
+type SetAssetAcquiredAtParams struct {
+	AcquiredAt      sql.NullTime
+	AssetPrimaryKey int64
+}
+
+func (q *Queries) SetAssetAcquiredAt(ctx context.Context, arg SetAssetAcquiredAtParams) error {
+	_, err := q.db.ExecContext(ctx, setAssetAcquiredAt, arg.AcquiredAt, arg.AssetPrimaryKey)
+	return err
+}
+
 const setAssetSpent = `-- name: SetAssetSpent :one
 WITH target_asset(asset_id) AS (
     SELECT assets.asset_id
@@ -2113,6 +2938,91 @@ func (q *Queries) SetAssetSpent(ctx context.Context, arg SetAssetSpentParams) (i
 	return asset_id, err
 }
 
+const setAssetUnspent = `-- name: SetAssetUnspent :exec
+WITH target_asset(asset_id) AS (
+    SELECT assets.asset_id
+    FROM assets
+    JOIN script_keys
+      ON assets.script_key_id = script_keys.script_key_id
+    JOIN genesis_assets
+      ON assets.genesis_id = genesis_assets.gen_asset_id
+    JOIN managed_utxos utxos
+      ON assets.anchor_utxo_id = utxos.utxo_id
+    WHERE script_keys.tweaked_script_key = $1
+     AND genesis_assets.asset_id = $2
+     AND utxos.outpoint = $3
+     AND assets.spent = TRUE
+)
+UPDATE assets
+SET spent = FALSE
+WHERE asset_id IN (SELECT asset_id FROM target_asset)
+`
+
+type SetAssetUnspentParams struct {
+	ScriptKey   []byte
+	GenAssetID  []byte
+	AnchorPoint []byte
+}
+
+func (q *Queries) SetAssetUnspent(ctx context.Context, arg SetAssetUnspentParams) error {
+	_, err := q.db.ExecContext(ctx, setAssetUnspent, arg.ScriptKey, arg.GenAssetID, arg.AnchorPoint)
+	return err
+}
+
+const setBatchAssetsAcquiredAt = `-- name: SetBatchAssetsAcquiredAt :exec
+WITH target_utxos(utxo_id) AS (
+    SELECT utxos.utxo_id
+    FROM genesis_points points
+    JOIN asset_minting_batches batches
+        ON batches.genesis_id = points.genesis_id
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    JOIN managed_utxos utxos
+        ON utxos.txn_id = points.anchor_tx_id
+    WHERE keys.raw_key = $1
+)
+UPDATE assets
+SET acquired_at = $2
+WHERE anchor_utxo_id IN (SELECT utxo_id FROM target_utxos)
+`
+
+type SetBatchAssetsAcquiredAtParams struct {
+	RawKey     []byte
+	AcquiredAt sql.NullTime
+}
+
+func (q *Queries) SetBatchAssetsAcquiredAt(ctx context.Context, arg SetBatchAssetsAcquiredAtParams) error {
+	_, err := q.db.ExecContext(ctx, setBatchAssetsAcquiredAt, arg.RawKey, arg.AcquiredAt)
+	return err
+}
+
+const unconfirmChainAnchorTx = `-- name: UnconfirmChainAnchorTx :exec
+UPDATE chain_txns
+SET block_height = NULL, block_hash = NULL, tx_index = NULL
+WHERE txid = $1
+`
+
+func (q *Queries) UnconfirmChainAnchorTx(ctx context.Context, txid []byte) error {
+	_, err := q.db.ExecContext(ctx, unconfirmChainAnchorTx, txid)
+	return err
+}
+
+const updateAssetAmount = `-- name: UpdateAssetAmount :exec
+UPDATE assets
+SET amount = $1
+WHERE asset_id = $2
+`
+
+type UpdateAssetAmountParams struct {
+	Amount          int64
+	AssetPrimaryKey int64
+}
+
+func (q *Queries) UpdateAssetAmount(ctx context.Context, arg UpdateAssetAmountParams) error {
+	_, err := q.db.ExecContext(ctx, updateAssetAmount, arg.Amount, arg.AssetPrimaryKey)
+	return err
+}
+
 const updateBatchGenesisTx = `-- name: UpdateBatchGenesisTx :exec
 WITH target_batch AS (
     SELECT batch_id
@@ -2136,6 +3046,46 @@ func (q *Queries) UpdateBatchGenesisTx(ctx context.Context, arg UpdateBatchGenes
 	return err
 }
 
+const updateManagedUTXOOutpoint = `-- name: UpdateManagedUTXOOutpoint :exec
+UPDATE managed_utxos
+SET outpoint = $1
+WHERE utxo_id = $2
+`
+
+type UpdateManagedUTXOOutpointParams struct {
+	Outpoint []byte
+	UtxoID   int64
+}
+
+func (q *Queries) UpdateManagedUTXOOutpoint(ctx context.Context, arg UpdateManagedUTXOOutpointParams) error {
+	_, err := q.db.ExecContext(ctx, updateManagedUTXOOutpoint, arg.Outpoint, arg.UtxoID)
+	return err
+}
+
+const updateMintingBatchFinalizeSchedule = `-- name: UpdateMintingBatchFinalizeSchedule :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+UPDATE asset_minting_batches
+SET finalize_time_unix = $2, finalize_min_seedlings = $3
+WHERE batch_id IN (SELECT batch_id FROM target_batch)
+`
+
+type UpdateMintingBatchFinalizeScheduleParams struct {
+	RawKey               []byte
+	FinalizeTimeUnix     sql.NullTime
+	FinalizeMinSeedlings sql.NullInt32
+}
+
+func (q *Queries) UpdateMintingBatchFinalizeSchedule(ctx context.Context, arg UpdateMintingBatchFinalizeScheduleParams) error {
+	_, err := q.db.ExecContext(ctx, updateMintingBatchFinalizeSchedule, arg.RawKey, arg.FinalizeTimeUnix, arg.FinalizeMinSeedlings)
+	return err
+}
+
 const updateMintingBatchState = `-- name: UpdateMintingBatchState :exec
 WITH target_batch AS (
     -- This CTE is used to fetch the ID of a batch, based on the serialized
@@ -2237,27 +3187,34 @@ func (q *Queries) UpsertAssetGroupWitness(ctx context.Context, arg UpsertAssetGr
 
 const upsertAssetMeta = `-- name: UpsertAssetMeta :one
 INSERT INTO assets_meta (
-    meta_data_hash, meta_data_blob, meta_data_type
+    meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 ) VALUES (
-    $1, $2, $3 
+    $1, $2, $3, $4
 ) ON CONFLICT (meta_data_hash)
     -- In this case, we may be inserting the data+type for an existing blob. So
     -- we'll set both of those values. At this layer we assume the meta hash
     -- has been validated elsewhere.
     DO UPDATE SET meta_data_blob = COALESCE(EXCLUDED.meta_data_blob, assets_meta.meta_data_blob), 
-                  meta_data_type = COALESCE(EXCLUDED.meta_data_type, assets_meta.meta_data_type)
+                  meta_data_type = COALESCE(EXCLUDED.meta_data_type, assets_meta.meta_data_type),
+                  meta_supply_cap = COALESCE(EXCLUDED.meta_supply_cap, assets_meta.meta_supply_cap)
         
 RETURNING meta_id
 `
 
 type UpsertAssetMetaParams struct {
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaDataHash  []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	MetaSupplyCap sql.NullInt64
 }
 
 func (q *Queries) UpsertAssetMeta(ctx context.Context, arg UpsertAssetMetaParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, upsertAssetMeta, arg.MetaDataHash, arg.MetaDataBlob, arg.MetaDataType)
+	row := q.db.QueryRowContext(ctx, upsertAssetMeta,
+		arg.MetaDataHash,
+		arg.MetaDataBlob,
+		arg.MetaDataType,
+		arg.MetaSupplyCap,
+	)
 	var meta_id int64
 	err := row.Scan(&meta_id)
 	return meta_id, err
@@ -2484,3 +3441,31 @@ func (q *Queries) UpsertScriptKey(ctx context.Context, arg UpsertScriptKeyParams
 	err := row.Scan(&script_key_id)
 	return script_key_id, err
 }
+
+const upsertTapscriptLeaf = `-- name: UpsertTapscriptLeaf :exec
+INSERT INTO tapscript_leaves (
+    root_hash, leaf_index, leaf_version, script
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (root_hash, leaf_index)
+    -- The leaves of a tree are identified by its root hash, so an existing
+    -- leaf is always the same.
+    DO NOTHING
+`
+
+type UpsertTapscriptLeafParams struct {
+	RootHash    []byte
+	LeafIndex   int32
+	LeafVersion int16
+	Script      []byte
+}
+
+func (q *Queries) UpsertTapscriptLeaf(ctx context.Context, arg UpsertTapscriptLeafParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTapscriptLeaf,
+		arg.RootHash,
+		arg.LeafIndex,
+		arg.LeafVersion,
+		arg.Script,
+	)
+	return err
+}
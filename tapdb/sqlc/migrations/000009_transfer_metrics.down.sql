@@ -0,0 +1,2 @@
+ALTER TABLE asset_transfers DROP COLUMN delivery_complete_time_unix;
+ALTER TABLE asset_transfers DROP COLUMN confirmation_time_unix;
@@ -0,0 +1,8 @@
+-- confirmation_time_unix is the timestamp of the block that confirmed the
+-- anchor transaction of the transfer. This value is NULL for transfers that
+-- are not yet confirmed or were confirmed before this column was added.
+ALTER TABLE asset_transfers ADD COLUMN confirmation_time_unix TIMESTAMP;
+
+-- delivery_complete_time_unix is the time at which the transfer was marked as
+-- complete, meaning that all proofs were delivered to their receivers.
+ALTER TABLE asset_transfers ADD COLUMN delivery_complete_time_unix TIMESTAMP;
@@ -0,0 +1,3 @@
+-- staged indicates that the anchor transaction of the transfer was fully
+-- signed and logged, but should not be broadcast until explicitly released.
+ALTER TABLE asset_transfers ADD COLUMN staged BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS federation_push_queue_next_attempt_idx;
+DROP TABLE IF EXISTS federation_push_queue;
@@ -0,0 +1,38 @@
+-- federation_push_queue holds the proof pushes to federation members that
+-- failed and are waiting to be retried. Only the key of the proof leaf is
+-- stored, the leaf itself is fetched from the local universe on retry.
+CREATE TABLE IF NOT EXISTS federation_push_queue (
+    id BIGINT PRIMARY KEY,
+
+    -- server_id references the federation member the proof should be pushed
+    -- to. All pending pushes are removed together with the server.
+    server_id BIGINT NOT NULL REFERENCES universe_servers(id) ON DELETE CASCADE,
+
+    -- asset_id is the asset ID of the target universe, or all zeroes if the
+    -- universe is identified by its group key.
+    asset_id BLOB NOT NULL CHECK(LENGTH(asset_id) = 32),
+
+    -- group_key is the 33-byte compressed group key of the target universe.
+    group_key BLOB CHECK(LENGTH(group_key) = 33),
+
+    proof_type TEXT NOT NULL CHECK(proof_type IN ('issuance', 'transfer')),
+
+    -- leaf_outpoint and leaf_script_key make up the key of the leaf within
+    -- the target universe.
+    leaf_outpoint BLOB NOT NULL,
+
+    leaf_script_key BLOB NOT NULL CHECK(LENGTH(leaf_script_key) = 32),
+
+    num_attempts INTEGER NOT NULL,
+
+    next_attempt_time TIMESTAMP NOT NULL,
+
+    last_error TEXT NOT NULL,
+
+    creation_time TIMESTAMP NOT NULL,
+
+    UNIQUE(server_id, asset_id, proof_type, leaf_outpoint, leaf_script_key)
+);
+
+CREATE INDEX IF NOT EXISTS federation_push_queue_next_attempt_idx
+    ON federation_push_queue(next_attempt_time);
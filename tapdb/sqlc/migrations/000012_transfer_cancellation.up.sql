@@ -0,0 +1,3 @@
+-- cancelled indicates that the unconfirmed transfer was cancelled. The inputs
+-- of a cancelled transfer are released and the transfer is no longer resumed.
+ALTER TABLE asset_transfers ADD COLUMN cancelled BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS proof_delivery_receipts_script_key_idx;
+DROP TABLE IF EXISTS proof_delivery_receipts;
@@ -0,0 +1,37 @@
+-- proof_delivery_receipts stores the delivery receipts that receivers of
+-- proofs sent through a proof courier signed with the key of the receiving
+-- script key.
+CREATE TABLE IF NOT EXISTS proof_delivery_receipts (
+    id BIGINT PRIMARY KEY,
+
+    -- script_key is the 33-byte compressed script key the proof was sent
+    -- to.
+    script_key BLOB NOT NULL CHECK(LENGTH(script_key) = 33),
+
+    -- asset_id is the asset ID of the asset the proof was sent for.
+    asset_id BLOB CHECK(LENGTH(asset_id) = 32),
+
+    -- outpoint is the anchor outpoint of the asset the proof was sent for.
+    outpoint BLOB,
+
+    -- proof_hash is the SHA256 hash of the raw proof file the receipt was
+    -- signed for.
+    proof_hash BLOB NOT NULL CHECK(LENGTH(proof_hash) = 32),
+
+    -- raw_script_key is the 33-byte compressed internal key of the script
+    -- key, which created the signature.
+    raw_script_key BLOB NOT NULL CHECK(LENGTH(raw_script_key) = 33),
+
+    -- tap_tweak is the optional Taproot tweak that is applied to the raw
+    -- script key to arrive at the script key.
+    tap_tweak BLOB,
+
+    signature BLOB NOT NULL CHECK(LENGTH(signature) = 64),
+
+    receive_time TIMESTAMP NOT NULL,
+
+    UNIQUE(script_key, proof_hash)
+);
+
+CREATE INDEX IF NOT EXISTS proof_delivery_receipts_script_key_idx
+    ON proof_delivery_receipts(script_key);
@@ -0,0 +1 @@
+ALTER TABLE assets_meta DROP COLUMN meta_supply_cap;
@@ -0,0 +1,4 @@
+-- meta_supply_cap is the optional supply cap declared in the meta reveal of
+-- a group anchor asset. It is part of the meta reveal, so we need to store it
+-- to be able to re-create the meta hash.
+ALTER TABLE assets_meta ADD COLUMN meta_supply_cap BIGINT;
@@ -0,0 +1,6 @@
+-- acquired_at is the time an asset UTXO was acquired by this node. For minted
+-- assets this is the time of the block that confirmed the genesis transaction,
+-- for imported and transferred assets the time of the block that confirmed
+-- the anchor transaction. Assets created before this column was added have no
+-- known acquisition time.
+ALTER TABLE assets ADD COLUMN acquired_at TIMESTAMP;
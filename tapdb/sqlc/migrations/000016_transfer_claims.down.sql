@@ -0,0 +1 @@
+ALTER TABLE asset_transfers DROP COLUMN claim_id;
@@ -0,0 +1,5 @@
+-- claim_id is the ID this node leases the wallet inputs of the anchor
+-- transaction of a transfer with after it took over the pending transfer from
+-- another node. It is NULL for transfers that were created by this node, whose
+-- wallet inputs are leased with the default ID of the wallet.
+ALTER TABLE asset_transfers ADD COLUMN claim_id BLOB;
@@ -0,0 +1,3 @@
+ALTER TABLE asset_transfer_outputs DROP COLUMN proof_delivery_attempts;
+ALTER TABLE asset_transfer_outputs DROP COLUMN proof_delivery_courier_addr;
+ALTER TABLE asset_transfer_outputs DROP COLUMN proof_delivery_status;
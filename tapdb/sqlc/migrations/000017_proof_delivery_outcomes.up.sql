@@ -0,0 +1,13 @@
+-- proof_delivery_status is the outcome of the delivery of the output's proof
+-- to its receiver. This value is NULL for outputs that don't require a proof
+-- delivery, whose delivery didn't finish yet or that were delivered before
+-- this column was added.
+ALTER TABLE asset_transfer_outputs ADD COLUMN proof_delivery_status SMALLINT;
+
+-- proof_delivery_courier_addr is the address of the proof courier that
+-- delivered the output's proof. This value is NULL if no courier was used.
+ALTER TABLE asset_transfer_outputs ADD COLUMN proof_delivery_courier_addr BLOB;
+
+-- proof_delivery_attempts is the number of attempts the proof courier made to
+-- deliver the output's proof.
+ALTER TABLE asset_transfer_outputs ADD COLUMN proof_delivery_attempts INTEGER;
@@ -0,0 +1,15 @@
+-- anchor_sweeps records the managed UTXOs that only anchor spent assets (such
+-- as tombstones and burns) and were swept back into the backing wallet, so
+-- they aren't swept again.
+CREATE TABLE IF NOT EXISTS anchor_sweeps (
+    id BIGINT PRIMARY KEY,
+
+    -- utxo_id references the managed UTXO that was swept.
+    utxo_id BIGINT NOT NULL UNIQUE REFERENCES managed_utxos(utxo_id),
+
+    -- sweep_txid is the ID of the transaction that swept the UTXO.
+    sweep_txid BLOB NOT NULL CHECK(length(sweep_txid) = 32),
+
+    -- swept_at is the time the sweep transaction was published.
+    swept_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,25 @@
+-- group_key_rotations records the on-chain rotations of the key that
+-- authorizes the issuance of assets into an asset group.
+CREATE TABLE IF NOT EXISTS group_key_rotations (
+    id BIGINT PRIMARY KEY,
+
+    -- group_key is the tweaked group key of the asset group.
+    group_key BLOB NOT NULL REFERENCES asset_groups(tweaked_group_key),
+
+    -- sequence is the position of the rotation in the rotation chain of
+    -- the group, starting at zero.
+    sequence INTEGER NOT NULL CHECK(sequence >= 0),
+
+    -- block_height is the height of the block that confirmed the rotation.
+    block_height INTEGER NOT NULL,
+
+    -- rotation is the serialized rotation, including its on-chain anchor.
+    rotation BLOB NOT NULL,
+
+    -- new_key_id references the internal key that holds the issuance
+    -- authority after the rotation. It is only set if the key is a local
+    -- key.
+    new_key_id BIGINT REFERENCES internal_keys(key_id),
+
+    UNIQUE(group_key, sequence)
+);
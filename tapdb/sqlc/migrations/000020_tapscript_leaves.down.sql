@@ -0,0 +1,3 @@
+ALTER TABLE asset_seedlings DROP COLUMN script_key_tapscript_root;
+
+DROP TABLE IF EXISTS tapscript_leaves;
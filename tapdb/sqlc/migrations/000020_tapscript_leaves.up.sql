@@ -0,0 +1,27 @@
+-- tapscript_leaves stores the leaves of the tapscript trees that the script
+-- keys of assets commit to, so the assets can be spent through one of their
+-- leaves. The leaves are identified by the root hash of their tree, which is
+-- the tweak of the script keys that commit to the tree.
+CREATE TABLE IF NOT EXISTS tapscript_leaves (
+    leaf_id BIGINT PRIMARY KEY,
+
+    -- root_hash is the root hash of the tree the leaf belongs to.
+    root_hash BLOB NOT NULL CHECK(length(root_hash) = 32),
+
+    -- leaf_index is the position of the leaf in the list of leaves the
+    -- tree is assembled from.
+    leaf_index INTEGER NOT NULL CHECK(leaf_index >= 0),
+
+    -- leaf_version is the tapscript leaf version of the leaf.
+    leaf_version SMALLINT NOT NULL,
+
+    -- script is the script of the leaf.
+    script BLOB NOT NULL,
+
+    UNIQUE(root_hash, leaf_index)
+);
+
+-- script_key_tapscript_root is the root hash of the tapscript tree the script
+-- key of the asset minted from the seedling should commit to. If NULL, a
+-- BIP-0086 script key is used.
+ALTER TABLE asset_seedlings ADD COLUMN script_key_tapscript_root BLOB;
@@ -0,0 +1,4 @@
+-- asset_amt is the number of asset units that were actually received in the
+-- inbound transfer of the event, as seen in its proof. This can be less than
+-- the amount the address requested. If NULL, no proof was seen yet.
+ALTER TABLE addr_events ADD COLUMN asset_amt BIGINT;
@@ -0,0 +1,22 @@
+-- key_ranges records the ranges of key indexes that were reserved from the
+-- wallet for a purpose outside of the daemon's own key derivation. The keys of
+-- a range are never derived again by the wallet.
+CREATE TABLE IF NOT EXISTS key_ranges (
+    id BIGINT PRIMARY KEY,
+
+    -- key_family is the key family (BIP-0043 account) the range belongs to.
+    key_family INTEGER NOT NULL,
+
+    -- first_index is the first key index of the range.
+    first_index INTEGER NOT NULL CHECK(first_index >= 0),
+
+    -- last_index is the last key index of the range, inclusive.
+    last_index INTEGER NOT NULL CHECK(last_index >= first_index),
+
+    -- purpose is a free form description of what the range was reserved
+    -- for.
+    purpose TEXT NOT NULL,
+
+    -- creation_time is the time the range was reserved.
+    creation_time TIMESTAMP NOT NULL
+);
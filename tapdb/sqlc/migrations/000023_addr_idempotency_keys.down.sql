@@ -0,0 +1 @@
+DROP TABLE IF EXISTS addr_idempotency_keys;
@@ -0,0 +1,15 @@
+-- addr_idempotency_keys maps the idempotency keys supplied by clients when
+-- creating an address to the address that was created, so a retried request
+-- returns the same address instead of deriving a new one.
+CREATE TABLE IF NOT EXISTS addr_idempotency_keys (
+    -- idempotency_key is the key supplied by the client.
+    idempotency_key TEXT PRIMARY KEY,
+
+    -- params_hash is the hash of the parameters of the request that created
+    -- the address. A request with the same key but different parameters is
+    -- rejected.
+    params_hash BLOB NOT NULL CHECK(length(params_hash) = 32),
+
+    -- addr_id references the address that was created.
+    addr_id BIGINT NOT NULL REFERENCES addrs(id)
+);
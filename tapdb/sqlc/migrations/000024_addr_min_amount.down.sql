@@ -0,0 +1 @@
+ALTER TABLE addrs DROP COLUMN min_amount;
@@ -0,0 +1,4 @@
+-- min_amount is the minimum number of asset units an address that allows the
+-- sender to choose the amount accepts. If NULL, the address only accepts its
+-- own amount.
+ALTER TABLE addrs ADD COLUMN min_amount BIGINT;
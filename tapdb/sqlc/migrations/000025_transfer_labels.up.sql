@@ -0,0 +1,3 @@
+-- label is the optional human-readable label the user attached to an outbound
+-- transfer when requesting it. It is empty for transfers without a label.
+ALTER TABLE asset_transfers ADD COLUMN label TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- asset_id is the ID of the asset that was transferred to the output. A single
+-- transfer can move multiple assets in the same anchor transaction, with the
+-- outputs of each asset ID belonging to their own virtual transaction. This
+-- value is NULL for outputs that were created before this column was added,
+-- which are always of the asset ID of the transfer's first input.
+ALTER TABLE asset_transfer_outputs ADD COLUMN asset_id BLOB;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS universe_events_event_timestamp_idx;
+DROP TABLE IF EXISTS universe_stats_counters;
@@ -0,0 +1,17 @@
+-- universe_stats_counters keeps a running count of the universe events of each
+-- type, so the aggregate universe stats can be queried without counting all
+-- events on each call.
+CREATE TABLE IF NOT EXISTS universe_stats_counters (
+    event_type VARCHAR NOT NULL PRIMARY KEY CHECK (event_type IN ('SYNC', 'NEW_PROOF', 'NEW_ROOT')),
+
+    num_events BIGINT NOT NULL CHECK (num_events >= 0)
+);
+
+-- We start counting from the events that are already stored.
+INSERT INTO universe_stats_counters (event_type, num_events)
+SELECT event_type, COUNT(*)
+FROM universe_events
+GROUP BY event_type;
+
+-- The recent universe events are counted by their timestamp.
+CREATE INDEX IF NOT EXISTS universe_events_event_timestamp_idx ON universe_events(event_timestamp);
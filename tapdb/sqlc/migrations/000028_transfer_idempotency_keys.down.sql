@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS asset_transfers_idempotency_key_idx;
+ALTER TABLE asset_transfers DROP COLUMN idempotency_key;
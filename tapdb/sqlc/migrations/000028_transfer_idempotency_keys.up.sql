@@ -0,0 +1,6 @@
+-- idempotency_key is the optional client-supplied key of the send request that
+-- created an outbound transfer. A repeated send request with the same key
+-- returns the stored transfer instead of creating a new one.
+ALTER TABLE asset_transfers ADD COLUMN idempotency_key TEXT;
+
+CREATE INDEX IF NOT EXISTS asset_transfers_idempotency_key_idx ON asset_transfers(idempotency_key);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS send_events_event_time_idx;
+DROP TABLE IF EXISTS send_events;
@@ -0,0 +1,14 @@
+-- send_events stores the events of the asset sending process, so subscribers
+-- that reconnect can replay the events they missed. The event ID increases
+-- monotonically and marks the position of an event in the event stream.
+CREATE TABLE IF NOT EXISTS send_events (
+    event_id BIGINT PRIMARY KEY,
+
+    -- event_time is the time the event was emitted at.
+    event_time TIMESTAMP NOT NULL,
+
+    -- event_data is the serialized RPC send asset event.
+    event_data BLOB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS send_events_event_time_idx ON send_events(event_time);
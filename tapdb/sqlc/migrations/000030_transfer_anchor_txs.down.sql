@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS transfer_anchor_txs_transfer_id_idx;
+DROP TABLE IF EXISTS transfer_anchor_txs;
@@ -0,0 +1,35 @@
+-- transfer_anchor_txs stores the versions of the anchor transaction of a
+-- pending transfer together with the funded PSBT they were signed from, so the
+-- anchor transaction can still be replaced with one paying a higher fee rate
+-- after a restart. Versions that were replaced are kept, as each of them can
+-- still confirm instead of the current version, which is the one the chain
+-- transaction of the transfer refers to.
+CREATE TABLE IF NOT EXISTS transfer_anchor_txs (
+    id BIGINT PRIMARY KEY,
+
+    -- transfer_id references the transfer the anchor transaction belongs to.
+    transfer_id BIGINT NOT NULL REFERENCES asset_transfers(id),
+
+    -- txid is the ID of this version of the anchor transaction.
+    txid BLOB NOT NULL UNIQUE CHECK(length(txid) = 32),
+
+    -- raw_tx is the fully signed version of the anchor transaction.
+    raw_tx BLOB NOT NULL,
+
+    -- chain_fees is the amount in sats paid in fees by this version.
+    chain_fees BIGINT NOT NULL,
+
+    -- funded_psbt is the serialized funded PSBT of this version before it
+    -- was signed.
+    funded_psbt BLOB NOT NULL,
+
+    -- change_output_index is the index of the wallet's change output.
+    change_output_index INTEGER NOT NULL,
+
+    -- fee_escalation indicates that this version was created by an
+    -- automatic fee escalation.
+    fee_escalation BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS transfer_anchor_txs_transfer_id_idx
+    ON transfer_anchor_txs(transfer_id);
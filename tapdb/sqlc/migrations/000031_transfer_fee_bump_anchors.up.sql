@@ -0,0 +1,26 @@
+-- transfer_fee_bump_anchors records the outputs of transfer anchor
+-- transactions that are reserved for bumping their fee with a child
+-- transaction (CPFP). These outputs don't anchor any assets.
+CREATE TABLE IF NOT EXISTS transfer_fee_bump_anchors (
+    id BIGINT PRIMARY KEY,
+
+    -- transfer_id references the transfer whose anchor transaction has the
+    -- output. All versions of the anchor transaction have the output at the
+    -- same index.
+    transfer_id BIGINT NOT NULL UNIQUE REFERENCES asset_transfers(id),
+
+    -- output_index is the index of the output within the anchor
+    -- transaction.
+    output_index INTEGER NOT NULL,
+
+    -- amt_sats is the value of the output.
+    amt_sats BIGINT NOT NULL,
+
+    -- internal_key_id references the BIP-0086 internal key of the output.
+    internal_key_id BIGINT NOT NULL REFERENCES internal_keys(key_id),
+
+    -- child_txid is the ID of the child transaction that spends the output
+    -- to bump the fee of the current anchor transaction, if one was
+    -- published.
+    child_txid BLOB CHECK(length(child_txid) = 32)
+);
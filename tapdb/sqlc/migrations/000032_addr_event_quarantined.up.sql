@@ -0,0 +1,5 @@
+-- quarantined is set if the inbound transfer of the event re-used an address
+-- that already received assets and was quarantined by the address reuse
+-- policy. Such a transfer is only completed once its proof is imported
+-- manually.
+ALTER TABLE addr_events ADD COLUMN quarantined BOOLEAN NOT NULL DEFAULT FALSE;
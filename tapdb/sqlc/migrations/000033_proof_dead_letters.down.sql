@@ -0,0 +1 @@
+DROP TABLE IF EXISTS proof_dead_letters;
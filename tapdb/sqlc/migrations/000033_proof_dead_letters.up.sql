@@ -0,0 +1,29 @@
+-- proof_dead_letters stores the inbound proofs that couldn't be imported, so
+-- they survive a restart and can be listed until they're imported manually.
+-- There is at most one dead letter for the on-chain output of an inbound
+-- transfer.
+CREATE TABLE IF NOT EXISTS proof_dead_letters (
+    id BIGINT PRIMARY KEY,
+
+    -- outpoint is the on-chain output of the inbound transfer the proof is
+    -- for, in Bitcoin wire format.
+    outpoint BLOB UNIQUE NOT NULL,
+
+    -- asset_id is the ID of the asset the proof is for, if known.
+    asset_id BLOB,
+
+    -- script_key is the script key of the asset the proof is for, if known.
+    script_key BLOB,
+
+    -- proof_file is the raw proof file that couldn't be imported.
+    proof_file BLOB NOT NULL,
+
+    -- attempts is the number of times the import was attempted.
+    attempts INTEGER NOT NULL,
+
+    -- error_msg is the error of the last import attempt.
+    error_msg TEXT NOT NULL,
+
+    -- created_at is the time the proof was given up on.
+    created_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS registration_push_queue_next_attempt_idx;
+DROP TABLE IF EXISTS registration_push_queue;
@@ -0,0 +1,39 @@
+-- registration_push_queue holds the failed pushes of the issuance proofs of
+-- minted assets to the default universe server. The default server isn't
+-- necessarily a federation member, so the pushes are keyed by its host rather
+-- than by a reference to the universe_servers table.
+CREATE TABLE IF NOT EXISTS registration_push_queue (
+    id BIGINT PRIMARY KEY,
+
+    -- server_host is the host:port of the universe server the proof should
+    -- be pushed to.
+    server_host TEXT NOT NULL,
+
+    -- asset_id is the asset ID of the target universe, or all zeroes if the
+    -- universe is identified by its group key.
+    asset_id BLOB NOT NULL CHECK(LENGTH(asset_id) = 32),
+
+    -- group_key is the 33-byte compressed group key of the target universe.
+    group_key BLOB CHECK(LENGTH(group_key) = 33),
+
+    proof_type TEXT NOT NULL CHECK(proof_type IN ('issuance', 'transfer')),
+
+    -- leaf_outpoint and leaf_script_key make up the key of the leaf within
+    -- the target universe.
+    leaf_outpoint BLOB NOT NULL,
+
+    leaf_script_key BLOB NOT NULL CHECK(LENGTH(leaf_script_key) = 32),
+
+    num_attempts INTEGER NOT NULL,
+
+    next_attempt_time TIMESTAMP NOT NULL,
+
+    last_error TEXT NOT NULL,
+
+    creation_time TIMESTAMP NOT NULL,
+
+    UNIQUE(server_host, asset_id, proof_type, leaf_outpoint, leaf_script_key)
+);
+
+CREATE INDEX IF NOT EXISTS registration_push_queue_next_attempt_idx
+    ON registration_push_queue(next_attempt_time);
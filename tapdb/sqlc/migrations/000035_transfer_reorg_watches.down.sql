@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS transfer_reorg_watch_inputs;
+DROP TABLE IF EXISTS transfer_reorg_watches;
@@ -0,0 +1,31 @@
+-- transfer_reorg_watches holds the confirmed transfers whose anchor
+-- transaction is still watched for re-orgs, so the watch survives a restart.
+-- A watch is removed once the anchor transaction is buried under the re-org
+-- safe depth or the re-org policy was applied to the transfer.
+CREATE TABLE IF NOT EXISTS transfer_reorg_watches (
+    transfer_id BIGINT PRIMARY KEY REFERENCES asset_transfers(id),
+
+    -- conf_height is the height at which the anchor transaction confirmed.
+    conf_height INTEGER NOT NULL,
+
+    -- abandon_pending is set once the anchor transaction was re-organized
+    -- out of the chain under the abandon policy. The transfer is only
+    -- abandoned once one of its inputs is spent by another transaction.
+    abandon_pending BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- transfer_reorg_watch_inputs holds the inputs of the anchor transaction of a
+-- watched transfer, which are watched for double spends once the transfer is
+-- pending to be abandoned.
+CREATE TABLE IF NOT EXISTS transfer_reorg_watch_inputs (
+    transfer_id BIGINT NOT NULL
+        REFERENCES transfer_reorg_watches(transfer_id),
+
+    -- outpoint is the outpoint spent by the input, in Bitcoin wire format.
+    outpoint BLOB NOT NULL,
+
+    -- pk_script is the script of the output spent by the input.
+    pk_script BLOB NOT NULL,
+
+    UNIQUE(transfer_id, outpoint)
+);
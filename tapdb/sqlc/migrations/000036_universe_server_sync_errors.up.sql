@@ -0,0 +1,3 @@
+-- last_sync_error is the error of the last sync attempt with the server, if it
+-- failed. It is cleared once a sync with the server succeeds again.
+ALTER TABLE universe_servers ADD COLUMN last_sync_error TEXT;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS federation_default_server;
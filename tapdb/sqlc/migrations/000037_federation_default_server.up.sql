@@ -0,0 +1,9 @@
+-- federation_default_server holds the Universe server that was set as the
+-- default sync target at runtime. The table holds at most a single row. An
+-- empty server_host means the default was cleared at runtime. As long as there
+-- is no row, the default server from the config is used.
+CREATE TABLE IF NOT EXISTS federation_default_server (
+    id SMALLINT PRIMARY KEY CHECK(id = 1),
+
+    server_host TEXT NOT NULL
+);
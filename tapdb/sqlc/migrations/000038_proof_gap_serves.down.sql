@@ -0,0 +1 @@
+DROP TABLE IF EXISTS proof_gap_serves;
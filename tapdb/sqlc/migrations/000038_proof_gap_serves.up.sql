@@ -0,0 +1,20 @@
+-- proof_gap_serves holds the proofs delivered to a universe courier whose
+-- receivers might still request the proofs missing from their proof chain, so
+-- answering those requests is resumed after a restart. A serve is removed once
+-- the receiver acknowledged its complete proof chain or the maximum serve
+-- duration passed.
+CREATE TABLE IF NOT EXISTS proof_gap_serves (
+    id BIGINT PRIMARY KEY,
+
+    -- asset_id, script_key and outpoint locate the delivered proof in the
+    -- proof archive. The outpoint is the anchor output of the asset, in
+    -- Bitcoin wire format.
+    asset_id BLOB NOT NULL CHECK(length(asset_id) = 32),
+    script_key BLOB NOT NULL CHECK(length(script_key) = 33),
+    outpoint BLOB NOT NULL,
+
+    -- start_time is the time the proof was delivered.
+    start_time TIMESTAMP NOT NULL,
+
+    UNIQUE(asset_id, script_key, outpoint)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE asset_minting_batches DROP COLUMN finalize_min_seedlings;
+ALTER TABLE asset_minting_batches DROP COLUMN finalize_time_unix;
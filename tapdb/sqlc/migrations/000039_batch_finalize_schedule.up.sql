@@ -0,0 +1,7 @@
+-- finalize_time_unix and finalize_min_seedlings hold the optional schedule
+-- according to which a pending minting batch is finalized automatically. The
+-- batch is finalized at the given time or once it holds the given number of
+-- seedlings, whichever comes first. Both are NULL if the batch isn't scheduled
+-- to be finalized.
+ALTER TABLE asset_minting_batches ADD COLUMN finalize_time_unix TIMESTAMP;
+ALTER TABLE asset_minting_batches ADD COLUMN finalize_min_seedlings INTEGER;
@@ -24,6 +24,13 @@ type Addr struct {
 	CreationTime     time.Time
 	ManagedFrom      sql.NullTime
 	ProofCourierAddr []byte
+	MinAmount        sql.NullInt64
+}
+
+type AddrIdempotencyKey struct {
+	IdempotencyKey string
+	ParamsHash     []byte
+	AddrID         int64
 }
 
 type AddrEvent struct {
@@ -36,6 +43,15 @@ type AddrEvent struct {
 	ManagedUtxoID       int64
 	AssetProofID        sql.NullInt64
 	AssetID             sql.NullInt64
+	AssetAmt            sql.NullInt64
+	Quarantined         bool
+}
+
+type AnchorSweep struct {
+	ID        int64
+	UtxoID    int64
+	SweepTxid []byte
+	SweptAt   time.Time
 }
 
 type Asset struct {
@@ -52,6 +68,7 @@ type Asset struct {
 	SplitCommitmentRootValue sql.NullInt64
 	AnchorUtxoID             sql.NullInt64
 	Spent                    bool
+	AcquiredAt               sql.NullTime
 }
 
 type AssetGroup struct {
@@ -70,13 +87,15 @@ type AssetGroupWitness struct {
 }
 
 type AssetMintingBatch struct {
-	BatchID           int64
-	BatchState        int16
-	MintingTxPsbt     []byte
-	ChangeOutputIndex sql.NullInt32
-	GenesisID         sql.NullInt64
-	HeightHint        int32
-	CreationTimeUnix  time.Time
+	BatchID              int64
+	BatchState           int16
+	MintingTxPsbt        []byte
+	ChangeOutputIndex    sql.NullInt32
+	GenesisID            sql.NullInt64
+	HeightHint           int32
+	CreationTimeUnix     time.Time
+	FinalizeTimeUnix     sql.NullTime
+	FinalizeMinSeedlings sql.NullInt32
 }
 
 type AssetProof struct {
@@ -86,23 +105,31 @@ type AssetProof struct {
 }
 
 type AssetSeedling struct {
-	SeedlingID      int64
-	AssetName       string
-	AssetVersion    int16
-	AssetType       int16
-	AssetSupply     int64
-	AssetMetaID     int64
-	EmissionEnabled bool
-	BatchID         int64
-	GroupGenesisID  sql.NullInt64
-	GroupAnchorID   sql.NullInt64
+	SeedlingID             int64
+	AssetName              string
+	AssetVersion           int16
+	AssetType              int16
+	AssetSupply            int64
+	AssetMetaID            int64
+	EmissionEnabled        bool
+	BatchID                int64
+	GroupGenesisID         sql.NullInt64
+	GroupAnchorID          sql.NullInt64
+	ScriptKeyTapscriptRoot []byte
 }
 
 type AssetTransfer struct {
-	ID               int64
-	HeightHint       int32
-	AnchorTxnID      int64
-	TransferTimeUnix time.Time
+	ID                       int64
+	HeightHint               int32
+	AnchorTxnID              int64
+	TransferTimeUnix         time.Time
+	ConfirmationTimeUnix     sql.NullTime
+	DeliveryCompleteTimeUnix sql.NullTime
+	Staged                   bool
+	Cancelled                bool
+	ClaimID                  []byte
+	Label                    string
+	IdempotencyKey           sql.NullString
 }
 
 type AssetTransferInput struct {
@@ -129,6 +156,10 @@ type AssetTransferOutput struct {
 	NumPassiveAssets         int32
 	OutputType               int16
 	ProofCourierAddr         []byte
+	ProofDeliveryStatus      sql.NullInt16
+	ProofDeliveryCourierAddr []byte
+	ProofDeliveryAttempts    sql.NullInt32
+	AssetID                  []byte
 }
 
 type AssetWitness struct {
@@ -142,10 +173,11 @@ type AssetWitness struct {
 }
 
 type AssetsMetum struct {
-	MetaID       int64
-	MetaDataHash []byte
-	MetaDataBlob []byte
-	MetaDataType sql.NullInt16
+	MetaID        int64
+	MetaDataHash  []byte
+	MetaDataBlob  []byte
+	MetaDataType  sql.NullInt16
+	MetaSupplyCap sql.NullInt64
 }
 
 type ChainTxn struct {
@@ -158,12 +190,31 @@ type ChainTxn struct {
 	TxIndex     sql.NullInt32
 }
 
+type FederationDefaultServer struct {
+	ID         int16
+	ServerHost string
+}
+
 type FederationGlobalSyncConfig struct {
 	ProofType       string
 	AllowSyncInsert bool
 	AllowSyncExport bool
 }
 
+type FederationPushQueue struct {
+	ID              int64
+	ServerID        int64
+	AssetID         []byte
+	GroupKey        []byte
+	ProofType       string
+	LeafOutpoint    []byte
+	LeafScriptKey   []byte
+	NumAttempts     int32
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
 type FederationUniSyncConfig struct {
 	AssetID         []byte
 	GroupKey        []byte
@@ -199,6 +250,15 @@ type GenesisPoint struct {
 	AnchorTxID sql.NullInt64
 }
 
+type GroupKeyRotation struct {
+	ID          int64
+	GroupKey    []byte
+	Sequence    int32
+	BlockHeight int32
+	Rotation    []byte
+	NewKeyID    sql.NullInt64
+}
+
 type InternalKey struct {
 	KeyID     int64
 	RawKey    []byte
@@ -218,6 +278,15 @@ type KeyGroupInfoView struct {
 	XOnlyGroupKey   []byte
 }
 
+type KeyRange struct {
+	ID           int64
+	KeyFamily    int32
+	FirstIndex   int32
+	LastIndex    int32
+	Purpose      string
+	CreationTime time.Time
+}
+
 type Macaroon struct {
 	ID      []byte
 	RootKey []byte
@@ -262,11 +331,56 @@ type PassiveAsset struct {
 	NewProof        []byte
 }
 
+type ProofDeadLetter struct {
+	ID        int64
+	Outpoint  []byte
+	AssetID   []byte
+	ScriptKey []byte
+	ProofFile []byte
+	Attempts  int32
+	ErrorMsg  string
+	CreatedAt time.Time
+}
+
+type ProofDeliveryReceipt struct {
+	ID           int64
+	ScriptKey    []byte
+	AssetID      []byte
+	Outpoint     []byte
+	ProofHash    []byte
+	RawScriptKey []byte
+	TapTweak     []byte
+	Signature    []byte
+	ReceiveTime  time.Time
+}
+
+type ProofGapServe struct {
+	ID        int64
+	AssetID   []byte
+	ScriptKey []byte
+	Outpoint  []byte
+	StartTime time.Time
+}
+
 type ReceiverProofTransferAttempt struct {
 	ProofLocatorHash []byte
 	TimeUnix         time.Time
 }
 
+type RegistrationPushQueue struct {
+	ID              int64
+	ServerHost      string
+	AssetID         []byte
+	GroupKey        []byte
+	ProofType       string
+	LeafOutpoint    []byte
+	LeafScriptKey   []byte
+	NumAttempts     int32
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
 type ScriptKey struct {
 	ScriptKeyID      int64
 	InternalKeyID    int64
@@ -274,6 +388,52 @@ type ScriptKey struct {
 	Tweak            []byte
 }
 
+type SendEvent struct {
+	EventID   int64
+	EventTime time.Time
+	EventData []byte
+}
+
+type TapscriptLeafe struct {
+	LeafID      int64
+	RootHash    []byte
+	LeafIndex   int32
+	LeafVersion int16
+	Script      []byte
+}
+
+type TransferAnchorTx struct {
+	ID                int64
+	TransferID        int64
+	Txid              []byte
+	RawTx             []byte
+	ChainFees         int64
+	FundedPsbt        []byte
+	ChangeOutputIndex int32
+	FeeEscalation     bool
+}
+
+type TransferFeeBumpAnchor struct {
+	ID            int64
+	TransferID    int64
+	OutputIndex   int32
+	AmtSats       int64
+	InternalKeyID int64
+	ChildTxid     []byte
+}
+
+type TransferReorgWatch struct {
+	TransferID     int64
+	ConfHeight     int32
+	AbandonPending bool
+}
+
+type TransferReorgWatchInput struct {
+	TransferID int64
+	Outpoint   []byte
+	PkScript   []byte
+}
+
 type UniverseEvent struct {
 	EventID        int64
 	EventType      string
@@ -301,9 +461,10 @@ type UniverseRoot struct {
 }
 
 type UniverseServer struct {
-	ID           int64
-	ServerHost   string
-	LastSyncTime time.Time
+	ID            int64
+	ServerHost    string
+	LastSyncTime  time.Time
+	LastSyncError sql.NullString
 }
 
 type UniverseStat struct {
@@ -312,3 +473,8 @@ type UniverseStat struct {
 	AssetID          []byte
 	GroupKey         []byte
 }
+
+type UniverseStatsCounter struct {
+	EventType string
+	NumEvents int64
+}
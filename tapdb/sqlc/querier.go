@@ -20,23 +20,45 @@ type Querier interface {
 	AssetsByGenesisPoint(ctx context.Context, prevOut []byte) ([]AssetsByGenesisPointRow, error)
 	AssetsInBatch(ctx context.Context, rawKey []byte) ([]AssetsInBatchRow, error)
 	BindMintingBatchWithTx(ctx context.Context, arg BindMintingBatchWithTxParams) error
+	CancelAssetTransfer(ctx context.Context, anchorTxid []byte) (int64, error)
 	ConfirmChainAnchorTx(ctx context.Context, arg ConfirmChainAnchorTxParams) error
 	ConfirmChainTx(ctx context.Context, arg ConfirmChainTxParams) error
+	CountFederationPushQueueEntries(ctx context.Context, serverID int64) (int64, error)
+	CountRegistrationPushQueueEntries(ctx context.Context, serverHost string) (int64, error)
+	DecrementUniverseStatsCounter(ctx context.Context, arg DecrementUniverseStatsCounterParams) error
+	DecrementUniverseStatsCounters(ctx context.Context, namespaceRoot string) error
 	DeleteAllNodes(ctx context.Context, namespace string) (int64, error)
+	DeleteAnchoredAsset(ctx context.Context, arg DeleteAnchoredAssetParams) error
+	DeleteAnchoredAssetProof(ctx context.Context, arg DeleteAnchoredAssetProofParams) error
 	DeleteAssetWitnesses(ctx context.Context, assetID int64) error
 	DeleteExpiredUTXOLeases(ctx context.Context, now sql.NullTime) error
+	DeleteGroupKeyRotations(ctx context.Context, arg DeleteGroupKeyRotationsParams) error
+	DeleteFederationPushQueueEntry(ctx context.Context, id int64) error
 	DeleteManagedUTXO(ctx context.Context, outpoint []byte) error
 	DeleteNode(ctx context.Context, arg DeleteNodeParams) (int64, error)
+	DeleteProofDeadLetter(ctx context.Context, outpoint []byte) (int64, error)
+	DeleteProofGapServe(ctx context.Context, arg DeleteProofGapServeParams) error
+	DeleteRegistrationPushQueueEntry(ctx context.Context, id int64) error
 	DeleteRoot(ctx context.Context, namespace string) (int64, error)
+	DeleteSendEventsBefore(ctx context.Context, cutoffTime time.Time) (int64, error)
+	DeleteTransferAnchorTxsAfter(ctx context.Context, arg DeleteTransferAnchorTxsAfterParams) error
+	DeleteTransferReOrgWatch(ctx context.Context, transferID int64) error
+	DeleteTransferReOrgWatchInputs(ctx context.Context, transferID int64) error
 	DeleteUTXOLease(ctx context.Context, outpoint []byte) error
+	DeleteUTXOLeaseByOwner(ctx context.Context, arg DeleteUTXOLeaseByOwnerParams) (int64, error)
 	DeleteUniverseEvents(ctx context.Context, namespaceRoot string) error
+	DeleteUniverseLeaf(ctx context.Context, arg DeleteUniverseLeafParams) error
 	DeleteUniverseLeaves(ctx context.Context, namespace string) error
+	DeleteUniverseProofEvents(ctx context.Context, arg DeleteUniverseProofEventsParams) (int64, error)
 	DeleteUniverseRoot(ctx context.Context, namespaceRoot string) error
 	DeleteUniverseServer(ctx context.Context, arg DeleteUniverseServerParams) error
+	FederationPushQueueEntryExists(ctx context.Context, arg FederationPushQueueEntryExistsParams) (bool, error)
 	FetchAddrByTaprootOutputKey(ctx context.Context, taprootOutputKey []byte) (FetchAddrByTaprootOutputKeyRow, error)
 	FetchAddrEvent(ctx context.Context, id int64) (FetchAddrEventRow, error)
+	FetchAddrIdempotencyKey(ctx context.Context, idempotencyKey string) (FetchAddrIdempotencyKeyRow, error)
 	FetchAddrs(ctx context.Context, arg FetchAddrsParams) ([]FetchAddrsRow, error)
 	FetchAllNodes(ctx context.Context) ([]MssmtNode, error)
+	FetchAllScriptKeys(ctx context.Context) ([]FetchAllScriptKeysRow, error)
 	FetchAssetMeta(ctx context.Context, metaID int64) (FetchAssetMetaRow, error)
 	FetchAssetMetaByHash(ctx context.Context, metaDataHash []byte) (FetchAssetMetaByHashRow, error)
 	FetchAssetMetaForAsset(ctx context.Context, assetID []byte) (FetchAssetMetaForAssetRow, error)
@@ -53,6 +75,8 @@ type Querier interface {
 	FetchChainTx(ctx context.Context, txid []byte) (ChainTxn, error)
 	FetchChildren(ctx context.Context, arg FetchChildrenParams) ([]FetchChildrenRow, error)
 	FetchChildrenSelfJoin(ctx context.Context, arg FetchChildrenSelfJoinParams) ([]FetchChildrenSelfJoinRow, error)
+	FetchFeeBumpAnchorFootprint(ctx context.Context) ([]FetchFeeBumpAnchorFootprintRow, error)
+	FetchGenesesWithMeta(ctx context.Context) ([]FetchGenesesWithMetaRow, error)
 	FetchGenesisByAssetID(ctx context.Context, assetID []byte) (GenesisInfoView, error)
 	FetchGenesisByID(ctx context.Context, genAssetID int64) (FetchGenesisByIDRow, error)
 	FetchGenesisID(ctx context.Context, arg FetchGenesisIDParams) (int64, error)
@@ -60,25 +84,37 @@ type Querier interface {
 	FetchGroupByGenesis(ctx context.Context, genesisID int64) (FetchGroupByGenesisRow, error)
 	// Sort and limit to return the genesis ID for initial genesis of the group.
 	FetchGroupByGroupKey(ctx context.Context, groupKey []byte) (FetchGroupByGroupKeyRow, error)
+	FetchGroupKeyRotations(ctx context.Context, groupKey []byte) ([]FetchGroupKeyRotationsRow, error)
 	FetchGroupedAssets(ctx context.Context) ([]FetchGroupedAssetsRow, error)
+	FetchInternalKeyLocator(ctx context.Context, rawKey []byte) (FetchInternalKeyLocatorRow, error)
+	FetchKeyRanges(ctx context.Context) ([]KeyRange, error)
 	FetchManagedUTXO(ctx context.Context, arg FetchManagedUTXOParams) (FetchManagedUTXORow, error)
 	FetchManagedUTXOs(ctx context.Context) ([]FetchManagedUTXOsRow, error)
 	FetchMintingBatch(ctx context.Context, rawKey []byte) (FetchMintingBatchRow, error)
 	FetchMintingBatchesByInverseState(ctx context.Context, batchState int16) ([]FetchMintingBatchesByInverseStateRow, error)
+	FetchOnChainFootprint(ctx context.Context) ([]FetchOnChainFootprintRow, error)
 	FetchRootNode(ctx context.Context, namespace string) (MssmtNode, error)
 	FetchScriptKeyByTweakedKey(ctx context.Context, tweakedScriptKey []byte) (FetchScriptKeyByTweakedKeyRow, error)
 	FetchScriptKeyIDByTweakedKey(ctx context.Context, tweakedScriptKey []byte) (int64, error)
 	FetchSeedlingByID(ctx context.Context, seedlingID int64) (AssetSeedling, error)
 	FetchSeedlingID(ctx context.Context, arg FetchSeedlingIDParams) (int64, error)
 	FetchSeedlingsForBatch(ctx context.Context, rawKey []byte) ([]FetchSeedlingsForBatchRow, error)
+	FetchSweepableAnchors(ctx context.Context, arg FetchSweepableAnchorsParams) ([]FetchSweepableAnchorsRow, error)
+	FetchTapscriptLeaves(ctx context.Context, rootHash []byte) ([]FetchTapscriptLeavesRow, error)
+	FetchTransferAnchorTxs(ctx context.Context, transferID int64) ([]TransferAnchorTx, error)
+	FetchTransferFeeBumpAnchor(ctx context.Context, transferID int64) (FetchTransferFeeBumpAnchorRow, error)
 	FetchTransferInputs(ctx context.Context, transferID int64) ([]FetchTransferInputsRow, error)
 	FetchTransferOutputs(ctx context.Context, transferID int64) ([]FetchTransferOutputsRow, error)
+	FetchTransferReOrgWatchInputs(ctx context.Context, transferID int64) ([]FetchTransferReOrgWatchInputsRow, error)
 	FetchUniverseKeys(ctx context.Context, namespace string) ([]FetchUniverseKeysRow, error)
 	FetchUniverseRoot(ctx context.Context, namespace string) (FetchUniverseRootRow, error)
 	GenesisAssets(ctx context.Context) ([]GenesisAsset, error)
 	GenesisPoints(ctx context.Context) ([]GenesisPoint, error)
 	GetRootKey(ctx context.Context, id []byte) (Macaroon, error)
+	IncrementUniverseStatsCounter(ctx context.Context, eventType string) error
 	InsertAddr(ctx context.Context, arg InsertAddrParams) (int64, error)
+	InsertAddrIdempotencyKey(ctx context.Context, arg InsertAddrIdempotencyKeyParams) error
+	InsertAnchorSweep(ctx context.Context, arg InsertAnchorSweepParams) error
 	InsertAssetSeedling(ctx context.Context, arg InsertAssetSeedlingParams) error
 	InsertAssetSeedlingIntoBatch(ctx context.Context, arg InsertAssetSeedlingIntoBatchParams) error
 	InsertAssetTransfer(ctx context.Context, arg InsertAssetTransferParams) (int64, error)
@@ -87,27 +123,36 @@ type Querier interface {
 	InsertAssetWitness(ctx context.Context, arg InsertAssetWitnessParams) error
 	InsertBranch(ctx context.Context, arg InsertBranchParams) error
 	InsertCompactedLeaf(ctx context.Context, arg InsertCompactedLeafParams) error
+	InsertGroupKeyRotation(ctx context.Context, arg InsertGroupKeyRotationParams) error
+	InsertKeyRange(ctx context.Context, arg InsertKeyRangeParams) error
 	InsertLeaf(ctx context.Context, arg InsertLeafParams) error
 	InsertNewAsset(ctx context.Context, arg InsertNewAssetParams) (int64, error)
 	InsertNewProofEvent(ctx context.Context, arg InsertNewProofEventParams) error
 	InsertNewSyncEvent(ctx context.Context, arg InsertNewSyncEventParams) error
 	InsertPassiveAsset(ctx context.Context, arg InsertPassiveAssetParams) error
+	InsertProofGapServe(ctx context.Context, arg InsertProofGapServeParams) error
 	InsertReceiverProofTransferAttempt(ctx context.Context, arg InsertReceiverProofTransferAttemptParams) error
 	InsertRootKey(ctx context.Context, arg InsertRootKeyParams) error
+	InsertSendEvent(ctx context.Context, arg InsertSendEventParams) (int64, error)
+	InsertTransferAnchorTx(ctx context.Context, arg InsertTransferAnchorTxParams) error
+	InsertTransferFeeBumpAnchor(ctx context.Context, arg InsertTransferFeeBumpAnchorParams) error
+	InsertTransferReOrgWatchInput(ctx context.Context, arg InsertTransferReOrgWatchInputParams) error
 	InsertUniverseServer(ctx context.Context, arg InsertUniverseServerParams) error
 	ListUniverseServers(ctx context.Context) ([]UniverseServer, error)
 	LogServerSync(ctx context.Context, arg LogServerSyncParams) error
+	LogServerSyncError(ctx context.Context, arg LogServerSyncErrorParams) error
 	NewMintingBatch(ctx context.Context, arg NewMintingBatchParams) error
 	// We use a LEFT JOIN here as not every asset has a group key, so this'll
 	// generate rows that have NULL values for the group key fields if an asset
 	// doesn't have a group key. See the comment in fetchAssetSprouts for a work
 	// around that needs to be used with this query until a sqlc bug is fixed.
+	QueryAnchorSweeps(ctx context.Context) ([]QueryAnchorSweepsRow, error)
 	QueryAssetBalancesByAsset(ctx context.Context, assetIDFilter []byte) ([]QueryAssetBalancesByAssetRow, error)
 	QueryAssetBalancesByGroup(ctx context.Context, keyGroupFilter []byte) ([]QueryAssetBalancesByGroupRow, error)
 	QueryAssetStatsPerDayPostgres(ctx context.Context, arg QueryAssetStatsPerDayPostgresParams) ([]QueryAssetStatsPerDayPostgresRow, error)
 	QueryAssetStatsPerDaySqlite(ctx context.Context, arg QueryAssetStatsPerDaySqliteParams) ([]QueryAssetStatsPerDaySqliteRow, error)
 	// We'll use this clause to filter out for only transfers that are
-	// unconfirmed. But only if the unconf_only field is set.
+	// unconfirmed and weren't cancelled. But only if the unconf_only field is set.
 	// Here we have another optional query clause to select a given transfer
 	// based on the anchor_tx_hash, but only if it's specified.
 	QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfersParams) ([]QueryAssetTransfersRow, error)
@@ -119,24 +164,56 @@ type Querier interface {
 	// channel balances, and also coin selection. We use the sqlc.narg feature to
 	// make the entire statement evaluate to true, if none of these extra args are
 	// specified.
+	// We order by the genesis first, so assets of the same tranche are grouped
+	// together and tranches with the same genesis height are always returned in
+	// the same order.
 	QueryAssets(ctx context.Context, arg QueryAssetsParams) ([]QueryAssetsRow, error)
 	QueryEventIDs(ctx context.Context, arg QueryEventIDsParams) ([]QueryEventIDsRow, error)
+	QueryFederationDefaultServer(ctx context.Context) (string, error)
 	QueryFederationGlobalSyncConfigs(ctx context.Context) ([]FederationGlobalSyncConfig, error)
+	QueryFederationPushQueue(ctx context.Context, dueBefore sql.NullTime) ([]QueryFederationPushQueueRow, error)
 	QueryFederationUniSyncConfigs(ctx context.Context) ([]FederationUniSyncConfig, error)
 	QueryPassiveAssets(ctx context.Context, transferID int64) ([]QueryPassiveAssetsRow, error)
+	QueryPendingProofDeliveryTransfers(ctx context.Context, status sql.NullInt16) ([][]byte, error)
+	QueryProofDeadLetters(ctx context.Context) ([]ProofDeadLetter, error)
+	QueryProofDeliveryReceipts(ctx context.Context, scriptKey []byte) ([]ProofDeliveryReceipt, error)
+	QueryProofGapServes(ctx context.Context) ([]ProofGapServe, error)
 	QueryReceiverProofTransferAttempt(ctx context.Context, proofLocatorHash []byte) ([]time.Time, error)
+	QueryRecentUniverseEvents(ctx context.Context, sinceTimestamp int64) (QueryRecentUniverseEventsRow, error)
+	QueryRegistrationPushQueue(ctx context.Context, dueBefore sql.NullTime) ([]RegistrationPushQueue, error)
+	QuerySendEvents(ctx context.Context, arg QuerySendEventsParams) ([]SendEvent, error)
+	QueryTransferReOrgWatches(ctx context.Context) ([]QueryTransferReOrgWatchesRow, error)
+	QueryUTXOLeases(ctx context.Context, now sql.NullTime) ([]QueryUTXOLeasesRow, error)
 	// TODO(roasbeef): use the universe id instead for the grouping? so namespace
 	// root, simplifies queries
 	QueryUniverseAssetStats(ctx context.Context, arg QueryUniverseAssetStatsParams) ([]QueryUniverseAssetStatsRow, error)
 	QueryUniverseLeaves(ctx context.Context, arg QueryUniverseLeavesParams) ([]QueryUniverseLeavesRow, error)
 	QueryUniverseStats(ctx context.Context) (QueryUniverseStatsRow, error)
+	QueryUnspentAssetProofs(ctx context.Context) ([]QueryUnspentAssetProofsRow, error)
 	ReAnchorPassiveAssets(ctx context.Context, arg ReAnchorPassiveAssetsParams) error
+	RegistrationPushQueueEntryExists(ctx context.Context, arg RegistrationPushQueueEntryExistsParams) (bool, error)
+	ReplaceChainAnchorTx(ctx context.Context, arg ReplaceChainAnchorTxParams) (int64, error)
+	SetAddrEventAssetAmt(ctx context.Context, arg SetAddrEventAssetAmtParams) error
+	SetAddrEventQuarantined(ctx context.Context, arg SetAddrEventQuarantinedParams) error
 	SetAddrManaged(ctx context.Context, arg SetAddrManagedParams) error
+	SetAssetAcquiredAt(ctx context.Context, arg SetAssetAcquiredAtParams) error
 	SetAssetSpent(ctx context.Context, arg SetAssetSpentParams) (int64, error)
+	SetAssetUnspent(ctx context.Context, arg SetAssetUnspentParams) error
+	SetBatchAssetsAcquiredAt(ctx context.Context, arg SetBatchAssetsAcquiredAtParams) error
+	SetProofDeliveryOutcome(ctx context.Context, arg SetProofDeliveryOutcomeParams) error
+	SetTransferCompletionTimes(ctx context.Context, arg SetTransferCompletionTimesParams) error
+	SetTransferFeeBumpChild(ctx context.Context, arg SetTransferFeeBumpChildParams) error
+	UnconfirmChainAnchorTx(ctx context.Context, txid []byte) error
 	UniverseLeaves(ctx context.Context) ([]UniverseLeafe, error)
 	UniverseRoots(ctx context.Context) ([]UniverseRootsRow, error)
+	UnstageAssetTransfer(ctx context.Context, anchorTxid []byte) (int64, error)
+	UpdateAssetAmount(ctx context.Context, arg UpdateAssetAmountParams) error
 	UpdateBatchGenesisTx(ctx context.Context, arg UpdateBatchGenesisTxParams) error
+	UpdateFederationPushQueueEntry(ctx context.Context, arg UpdateFederationPushQueueEntryParams) error
+	UpdateManagedUTXOOutpoint(ctx context.Context, arg UpdateManagedUTXOOutpointParams) error
+	UpdateMintingBatchFinalizeSchedule(ctx context.Context, arg UpdateMintingBatchFinalizeScheduleParams) error
 	UpdateMintingBatchState(ctx context.Context, arg UpdateMintingBatchStateParams) error
+	UpdateRegistrationPushQueueEntry(ctx context.Context, arg UpdateRegistrationPushQueueEntryParams) error
 	UpdateUTXOLease(ctx context.Context, arg UpdateUTXOLeaseParams) error
 	UpsertAddrEvent(ctx context.Context, arg UpsertAddrEventParams) (int64, error)
 	UpsertAssetGroupKey(ctx context.Context, arg UpsertAssetGroupKeyParams) (int64, error)
@@ -144,14 +221,21 @@ type Querier interface {
 	UpsertAssetMeta(ctx context.Context, arg UpsertAssetMetaParams) (int64, error)
 	UpsertAssetProof(ctx context.Context, arg UpsertAssetProofParams) error
 	UpsertChainTx(ctx context.Context, arg UpsertChainTxParams) (int64, error)
+	UpsertFederationDefaultServer(ctx context.Context, serverHost string) error
 	UpsertFederationGlobalSyncConfig(ctx context.Context, arg UpsertFederationGlobalSyncConfigParams) error
+	UpsertFederationPushQueueEntry(ctx context.Context, arg UpsertFederationPushQueueEntryParams) error
 	UpsertFederationUniSyncConfig(ctx context.Context, arg UpsertFederationUniSyncConfigParams) error
 	UpsertGenesisAsset(ctx context.Context, arg UpsertGenesisAssetParams) (int64, error)
 	UpsertGenesisPoint(ctx context.Context, prevOut []byte) (int64, error)
 	UpsertInternalKey(ctx context.Context, arg UpsertInternalKeyParams) (int64, error)
 	UpsertManagedUTXO(ctx context.Context, arg UpsertManagedUTXOParams) (int64, error)
+	UpsertProofDeadLetter(ctx context.Context, arg UpsertProofDeadLetterParams) error
+	UpsertProofDeliveryReceipt(ctx context.Context, arg UpsertProofDeliveryReceiptParams) error
+	UpsertRegistrationPushQueueEntry(ctx context.Context, arg UpsertRegistrationPushQueueEntryParams) error
 	UpsertRootNode(ctx context.Context, arg UpsertRootNodeParams) error
 	UpsertScriptKey(ctx context.Context, arg UpsertScriptKeyParams) (int64, error)
+	UpsertTapscriptLeaf(ctx context.Context, arg UpsertTapscriptLeafParams) error
+	UpsertTransferReOrgWatch(ctx context.Context, arg UpsertTransferReOrgWatchParams) error
 	UpsertUniverseLeaf(ctx context.Context, arg UpsertUniverseLeafParams) error
 	UpsertUniverseRoot(ctx context.Context, arg UpsertUniverseRootParams) (int64, error)
 }
@@ -2,14 +2,14 @@
 INSERT INTO addrs (
     version, asset_version, genesis_asset_id, group_key, script_key_id,
     taproot_key_id, tapscript_sibling, taproot_output_key, amount, asset_type,
-    creation_time, proof_courier_addr
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id;
+    creation_time, proof_courier_addr, min_amount
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id;
 
 -- name: FetchAddrs :many
 SELECT 
     version, asset_version, genesis_asset_id, group_key, tapscript_sibling,
     taproot_output_key, amount, asset_type, creation_time, managed_from,
-    proof_courier_addr,
+    proof_courier_addr, min_amount,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key AS raw_script_key,
@@ -36,7 +36,7 @@ LIMIT @num_limit OFFSET @num_offset;
 SELECT
     version, asset_version, genesis_asset_id, group_key, tapscript_sibling,
     taproot_output_key, amount, asset_type, creation_time, managed_from,
-    proof_courier_addr,
+    proof_courier_addr, min_amount,
     script_keys.tweaked_script_key,
     script_keys.tweak AS script_key_tweak,
     raw_script_keys.raw_key as raw_script_key,
@@ -64,6 +64,16 @@ UPDATE addrs
 SET managed_from = $2
 WHERE id = (SELECT addr_id FROM target_addr);
 
+-- name: SetAddrEventAssetAmt :exec
+UPDATE addr_events
+SET asset_amt = $2
+WHERE id = $1;
+
+-- name: SetAddrEventQuarantined :exec
+UPDATE addr_events
+SET quarantined = $2
+WHERE id = $1;
+
 -- name: UpsertAddrEvent :one
 WITH target_addr(addr_id) AS (
     SELECT id
@@ -76,20 +86,22 @@ WITH target_addr(addr_id) AS (
 )
 INSERT INTO addr_events (
     creation_time, addr_id, status, chain_txn_id, chain_txn_output_index,
-    managed_utxo_id, asset_proof_id, asset_id
+    managed_utxo_id, asset_proof_id, asset_id, asset_amt
 ) VALUES (
     $3, (SELECT addr_id FROM target_addr), $4,
-    (SELECT txn_id FROM target_chain_txn), $5, $6, $7, $8
+    (SELECT txn_id FROM target_chain_txn), $5, $6, $7, $8,
+    sqlc.narg('asset_amt')
 )
 ON CONFLICT (addr_id, chain_txn_id, chain_txn_output_index)
     DO UPDATE SET status = EXCLUDED.status,
                   asset_proof_id = COALESCE(EXCLUDED.asset_proof_id, addr_events.asset_proof_id),
-                  asset_id = COALESCE(EXCLUDED.asset_id, addr_events.asset_id)
+                  asset_id = COALESCE(EXCLUDED.asset_id, addr_events.asset_id),
+                  asset_amt = COALESCE(EXCLUDED.asset_amt, addr_events.asset_amt)
 RETURNING id;
 
 -- name: FetchAddrEvent :one
 SELECT
-    creation_time, status, asset_proof_id, asset_id,
+    creation_time, status, asset_proof_id, asset_id, asset_amt, quarantined,
     chain_txns.txid as txid,
     chain_txns.block_height as confirmation_height,
     chain_txn_output_index as output_index,
@@ -114,4 +126,37 @@ JOIN addrs
 WHERE addr_events.status >= @status_from 
   AND addr_events.status <= @status_to
   AND COALESCE(@addr_taproot_key, addrs.taproot_output_key) = addrs.taproot_output_key
+  AND (sqlc.narg('script_key') IS NULL OR EXISTS (
+      SELECT 1
+      FROM script_keys
+      WHERE script_keys.script_key_id = addrs.script_key_id
+        AND substr(script_keys.tweaked_script_key, 2) = sqlc.narg('script_key')
+  ))
 ORDER by addr_events.creation_time;
+
+-- name: InsertKeyRange :exec
+INSERT INTO key_ranges (
+    key_family, first_index, last_index, purpose, creation_time
+) VALUES (
+    @key_family, @first_index, @last_index, @purpose, @creation_time
+);
+
+-- name: FetchKeyRanges :many
+SELECT *
+FROM key_ranges
+ORDER BY key_family, first_index;
+
+-- name: InsertAddrIdempotencyKey :exec
+INSERT INTO addr_idempotency_keys (
+    idempotency_key, params_hash, addr_id
+) VALUES (
+    @idempotency_key, @params_hash,
+    (SELECT id FROM addrs WHERE taproot_output_key = @taproot_output_key)
+);
+
+-- name: FetchAddrIdempotencyKey :one
+SELECT idempotency_keys.params_hash, addrs.taproot_output_key
+FROM addr_idempotency_keys idempotency_keys
+JOIN addrs
+    ON idempotency_keys.addr_id = addrs.id
+WHERE idempotency_keys.idempotency_key = @idempotency_key;
@@ -54,13 +54,27 @@ UPDATE asset_minting_batches
 SET batch_state = $2
 WHERE batch_id in (SELECT batch_id FROM target_batch);
 
+-- name: UpdateMintingBatchFinalizeSchedule :exec
+WITH target_batch AS (
+    SELECT batch_id
+    FROM asset_minting_batches batches
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    WHERE keys.raw_key = $1
+)
+UPDATE asset_minting_batches
+SET finalize_time_unix = $2, finalize_min_seedlings = $3
+WHERE batch_id IN (SELECT batch_id FROM target_batch);
+
 -- name: InsertAssetSeedling :exec
 INSERT INTO asset_seedlings (
     asset_name, asset_type, asset_version, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    script_key_tapscript_root
 ) VALUES (
    $1, $2, $3, $4, $5, $6, $7,
-   sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id')
+   sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id'),
+   sqlc.narg('script_key_tapscript_root')
 );
 
 -- name: FetchSeedlingID :one
@@ -108,11 +122,13 @@ WITH target_key_id AS (
 )
 INSERT INTO asset_seedlings(
     asset_name, asset_type, asset_version, asset_supply, asset_meta_id,
-    emission_enabled, batch_id, group_genesis_id, group_anchor_id
+    emission_enabled, batch_id, group_genesis_id, group_anchor_id,
+    script_key_tapscript_root
 ) VALUES (
     $2, $3, $4, $5, $6, $7,
     (SELECT key_id FROM target_key_id),
-    sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id')
+    sqlc.narg('group_genesis_id'), sqlc.narg('group_anchor_id'),
+    sqlc.narg('script_key_tapscript_root')
 );
 
 -- name: FetchSeedlingsForBatch :many
@@ -125,8 +141,9 @@ WITH target_batch(batch_id) AS (
 )
 SELECT seedling_id, asset_name, asset_type, asset_version, asset_supply, 
     assets_meta.meta_data_hash, assets_meta.meta_data_type, 
-    assets_meta.meta_data_blob, emission_enabled, batch_id, 
-    group_genesis_id, group_anchor_id
+    assets_meta.meta_data_blob, assets_meta.meta_supply_cap,
+    emission_enabled, batch_id, 
+    group_genesis_id, group_anchor_id, script_key_tapscript_root
 FROM asset_seedlings 
 LEFT JOIN assets_meta
     ON asset_seedlings.asset_meta_id = assets_meta.meta_id
@@ -255,6 +272,27 @@ JOIN script_keys
 JOIN internal_keys
     ON script_keys.internal_key_id = internal_keys.key_id;
 
+-- name: SetAssetAcquiredAt :exec
+UPDATE assets
+SET acquired_at = @acquired_at
+WHERE asset_id = @asset_primary_key;
+
+-- name: SetBatchAssetsAcquiredAt :exec
+WITH target_utxos(utxo_id) AS (
+    SELECT utxos.utxo_id
+    FROM genesis_points points
+    JOIN asset_minting_batches batches
+        ON batches.genesis_id = points.genesis_id
+    JOIN internal_keys keys
+        ON batches.batch_id = keys.key_id
+    JOIN managed_utxos utxos
+        ON utxos.txn_id = points.anchor_tx_id
+    WHERE keys.raw_key = @raw_key
+)
+UPDATE assets
+SET acquired_at = @acquired_at
+WHERE anchor_utxo_id IN (SELECT utxo_id FROM target_utxos);
+
 -- name: SetAssetSpent :one
 WITH target_asset(asset_id) AS (
     SELECT assets.asset_id
@@ -274,12 +312,45 @@ SET spent = TRUE
 WHERE asset_id = (SELECT asset_id FROM target_asset)
 RETURNING assets.asset_id;
 
+-- name: SetAssetUnspent :exec
+WITH target_asset(asset_id) AS (
+    SELECT assets.asset_id
+    FROM assets
+    JOIN script_keys
+      ON assets.script_key_id = script_keys.script_key_id
+    JOIN genesis_assets
+      ON assets.genesis_id = genesis_assets.gen_asset_id
+    JOIN managed_utxos utxos
+      ON assets.anchor_utxo_id = utxos.utxo_id
+    WHERE script_keys.tweaked_script_key = @script_key
+     AND genesis_assets.asset_id = @gen_asset_id
+     AND utxos.outpoint = @anchor_point
+     AND assets.spent = TRUE
+)
+UPDATE assets
+SET spent = FALSE
+WHERE asset_id IN (SELECT asset_id FROM target_asset);
+
+-- name: DeleteAnchoredAssetProof :exec
+DELETE FROM asset_proofs
+WHERE asset_id IN (
+    SELECT asset_id
+    FROM assets
+    WHERE script_key_id = @script_key_id
+      AND anchor_utxo_id = @anchor_utxo_id
+);
+
+-- name: DeleteAnchoredAsset :exec
+DELETE FROM assets
+WHERE script_key_id = @script_key_id AND anchor_utxo_id = @anchor_utxo_id;
+
 -- name: QueryAssetBalancesByAsset :many
 SELECT
     genesis_info_view.asset_id, version, SUM(amount) balance,
     genesis_info_view.asset_tag, genesis_info_view.meta_hash,
     genesis_info_view.asset_type, genesis_info_view.output_index,
-    genesis_info_view.prev_out AS genesis_point
+    genesis_info_view.prev_out AS genesis_point,
+    key_group_info_view.tweaked_group_key
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
@@ -295,7 +366,7 @@ WHERE spent = FALSE
 GROUP BY assets.genesis_id, genesis_info_view.asset_id,
          version, genesis_info_view.asset_tag, genesis_info_view.meta_hash,
          genesis_info_view.asset_type, genesis_info_view.output_index,
-         genesis_info_view.prev_out;
+         genesis_info_view.prev_out, key_group_info_view.tweaked_group_key;
 
 -- name: QueryAssetBalancesByGroup :many
 SELECT
@@ -375,6 +446,7 @@ SELECT
     genesis_info_view.output_index AS genesis_output_index,
     genesis_info_view.asset_type,
     genesis_info_view.prev_out AS genesis_prev_out,
+    genesis_info_view.block_height AS genesis_block_height,
     txns.raw_tx AS anchor_tx,
     txns.txid AS anchor_txid,
     txns.block_hash AS anchor_block_hash,
@@ -386,7 +458,8 @@ SELECT
     utxos.lease_owner AS anchor_lease_owner,
     utxos.lease_expiry AS anchor_lease_expiry,
     utxo_internal_keys.raw_key AS anchor_internal_key,
-    split_commitment_root_hash, split_commitment_root_value
+    split_commitment_root_hash, split_commitment_root_value,
+    assets.acquired_at
 FROM assets
 JOIN genesis_info_view
     ON assets.genesis_id = genesis_info_view.gen_asset_id AND
@@ -430,8 +503,16 @@ WHERE (
     assets.amount >= COALESCE(sqlc.narg('min_amt'), assets.amount) AND
     assets.spent = COALESCE(sqlc.narg('spent'), assets.spent) AND
     (key_group_info_view.tweaked_group_key = sqlc.narg('key_group_filter') OR
-      sqlc.narg('key_group_filter') IS NULL)
-);
+      sqlc.narg('key_group_filter') IS NULL) AND
+    (assets.acquired_at >= sqlc.narg('min_acquired_at') OR
+      sqlc.narg('min_acquired_at') IS NULL) AND
+    (assets.acquired_at < sqlc.narg('max_acquired_at') OR
+      sqlc.narg('max_acquired_at') IS NULL)
+)
+-- We order by the genesis first, so assets of the same tranche are grouped
+-- together and tranches with the same genesis height are always returned in
+-- the same order.
+ORDER BY assets.genesis_id, assets.asset_id;
 
 -- name: AllAssets :many
 SELECT * 
@@ -609,6 +690,20 @@ JOIN genesis_points
   ON genesis_assets.genesis_point_id = genesis_points.genesis_id
 WHERE gen_asset_id = $1;
 
+-- name: FetchGenesesWithMeta :many
+SELECT
+    asset_id, asset_tag, output_index, asset_type,
+    genesis_points.prev_out prev_out, assets_meta.meta_data_hash,
+    assets_meta.meta_data_type, assets_meta.meta_data_blob,
+    assets_meta.meta_supply_cap
+FROM genesis_assets
+JOIN assets_meta
+    ON genesis_assets.meta_data_id = assets_meta.meta_id
+JOIN genesis_points
+    ON genesis_assets.genesis_point_id = genesis_points.genesis_id
+WHERE assets_meta.meta_data_blob IS NOT NULL
+ORDER BY gen_asset_id;
+
 -- name: ConfirmChainTx :exec
 WITH target_txn(txn_id) AS (
     SELECT anchor_tx_id
@@ -710,6 +805,11 @@ WHERE (
 DELETE FROM managed_utxos
 WHERE outpoint = $1;
 
+-- name: UpdateManagedUTXOOutpoint :exec
+UPDATE managed_utxos
+SET outpoint = @outpoint
+WHERE utxo_id = @utxo_id;
+
 -- name: UpdateUTXOLease :exec
 UPDATE managed_utxos
 SET lease_owner = @lease_owner, lease_expiry = @lease_expiry
@@ -727,11 +827,47 @@ WHERE lease_owner IS NOT NULL AND
       lease_expiry IS NOT NULL AND
       lease_expiry < @now;
 
+-- name: DeleteUTXOLeaseByOwner :execrows
+UPDATE managed_utxos
+SET lease_owner = NULL, lease_expiry = NULL
+WHERE outpoint = @outpoint AND lease_owner = @lease_owner;
+
+-- name: QueryUTXOLeases :many
+SELECT utxos.outpoint, utxos.amt_sats, utxos.lease_owner, utxos.lease_expiry,
+    (
+        -- A leased UTXO that is spent by a transfer that is still pending
+        -- is locked by that transfer.
+        SELECT txns.txid
+        FROM asset_transfer_inputs inputs
+        JOIN asset_transfers transfers
+            ON inputs.transfer_id = transfers.id
+        JOIN chain_txns txns
+            ON transfers.anchor_txn_id = txns.txn_id
+        WHERE inputs.anchor_point = utxos.outpoint
+        ORDER BY transfers.id DESC
+        LIMIT 1
+    ) AS transfer_anchor_txid
+FROM managed_utxos utxos
+WHERE utxos.lease_owner IS NOT NULL AND
+      utxos.lease_expiry IS NOT NULL AND
+      utxos.lease_expiry >= @now
+ORDER BY utxos.lease_expiry;
+
+-- name: ReplaceChainAnchorTx :execrows
+UPDATE chain_txns
+SET txid = @new_txid, raw_tx = @raw_tx, chain_fees = @chain_fees
+WHERE txid = @old_txid AND block_hash IS NULL;
+
 -- name: ConfirmChainAnchorTx :exec
 UPDATE chain_txns
 SET block_height = $2, block_hash = $3, tx_index = $4
 WHERE txid = $1;
 
+-- name: UnconfirmChainAnchorTx :exec
+UPDATE chain_txns
+SET block_height = NULL, block_hash = NULL, tx_index = NULL
+WHERE txid = $1;
+
 -- name: UpsertScriptKey :one
 INSERT INTO script_keys (
     internal_key_id, tweaked_script_key, tweak
@@ -755,6 +891,34 @@ JOIN internal_keys
   ON script_keys.internal_key_id = internal_keys.key_id
 WHERE script_keys.tweaked_script_key = $1;
 
+-- name: FetchAllScriptKeys :many
+SELECT tweaked_script_key, tweak, raw_key, key_family, key_index
+FROM script_keys
+JOIN internal_keys
+  ON script_keys.internal_key_id = internal_keys.key_id
+ORDER BY script_key_id;
+
+-- name: UpsertTapscriptLeaf :exec
+INSERT INTO tapscript_leaves (
+    root_hash, leaf_index, leaf_version, script
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (root_hash, leaf_index)
+    -- The leaves of a tree are identified by its root hash, so an existing
+    -- leaf is always the same.
+    DO NOTHING;
+
+-- name: FetchTapscriptLeaves :many
+SELECT leaf_version, script
+FROM tapscript_leaves
+WHERE root_hash = $1
+ORDER BY leaf_index;
+
+-- name: FetchInternalKeyLocator :one
+SELECT key_family, key_index
+FROM internal_keys
+WHERE raw_key = $1;
+
 -- name: FetchGenesisByAssetID :one
 SELECT * 
 FROM genesis_info_view
@@ -762,31 +926,178 @@ WHERE asset_id = $1;
 
 -- name: UpsertAssetMeta :one
 INSERT INTO assets_meta (
-    meta_data_hash, meta_data_blob, meta_data_type
+    meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 ) VALUES (
-    $1, $2, $3 
+    $1, $2, $3, $4
 ) ON CONFLICT (meta_data_hash)
     -- In this case, we may be inserting the data+type for an existing blob. So
     -- we'll set both of those values. At this layer we assume the meta hash
     -- has been validated elsewhere.
     DO UPDATE SET meta_data_blob = COALESCE(EXCLUDED.meta_data_blob, assets_meta.meta_data_blob), 
-                  meta_data_type = COALESCE(EXCLUDED.meta_data_type, assets_meta.meta_data_type)
+                  meta_data_type = COALESCE(EXCLUDED.meta_data_type, assets_meta.meta_data_type),
+                  meta_supply_cap = COALESCE(EXCLUDED.meta_supply_cap, assets_meta.meta_supply_cap)
         
 RETURNING meta_id;
 
 -- name: FetchAssetMeta :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM assets_meta
 WHERE meta_id = $1;
 
 -- name: FetchAssetMetaByHash :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM assets_meta
 WHERE meta_data_hash = $1;
 
 -- name: FetchAssetMetaForAsset :one
-SELECT meta_data_hash, meta_data_blob, meta_data_type
+SELECT meta_data_hash, meta_data_blob, meta_data_type, meta_supply_cap
 FROM genesis_assets assets
 JOIN assets_meta
     ON assets.meta_data_id = assets_meta.meta_id
 WHERE assets.asset_id = $1;
+
+-- name: QueryUnspentAssetProofs :many
+SELECT
+    assets.asset_id AS asset_primary_key, genesis_assets.asset_id,
+    assets.amount, script_keys.tweaked_script_key AS script_key,
+    managed_utxos.outpoint AS anchor_outpoint, asset_proofs.proof_file
+FROM assets
+JOIN genesis_assets
+    ON assets.genesis_id = genesis_assets.gen_asset_id
+JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+JOIN managed_utxos
+    ON assets.anchor_utxo_id = managed_utxos.utxo_id
+-- We use a LEFT JOIN here, as we also want to know about unspent assets that
+-- are missing their proof.
+LEFT JOIN asset_proofs
+    ON asset_proofs.asset_id = assets.asset_id
+WHERE assets.spent = FALSE
+ORDER BY assets.asset_id;
+
+-- name: UpdateAssetAmount :exec
+UPDATE assets
+SET amount = @amount
+WHERE asset_id = @asset_primary_key;
+
+-- name: FetchSweepableAnchors :many
+SELECT
+    utxos.utxo_id, utxos.outpoint, utxos.amt_sats, utxos.merkle_root,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM managed_utxos utxos
+JOIN internal_keys keys
+    ON utxos.internal_key_id = keys.key_id
+JOIN chain_txns txns
+    ON utxos.txn_id = txns.txn_id
+WHERE utxos.amt_sats <= @max_amt_sats AND
+      txns.block_height IS NOT NULL AND
+      (utxos.lease_owner IS NULL OR
+       utxos.lease_expiry IS NULL OR
+       utxos.lease_expiry <= @now) AND
+      -- Only outputs that anchor nothing but spent assets, such as
+      -- tombstones and burns, can be swept.
+      EXISTS (
+          SELECT 1
+          FROM assets
+          WHERE assets.anchor_utxo_id = utxos.utxo_id
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM assets
+          WHERE assets.anchor_utxo_id = utxos.utxo_id AND
+                assets.spent = FALSE
+      ) AND
+      -- Outputs that were already spent by a transfer or a sweep are left
+      -- alone.
+      NOT EXISTS (
+          SELECT 1
+          FROM asset_transfer_inputs inputs
+          WHERE inputs.anchor_point = utxos.outpoint
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM anchor_sweeps sweeps
+          WHERE sweeps.utxo_id = utxos.utxo_id
+      )
+ORDER BY utxos.utxo_id;
+
+-- name: FetchOnChainFootprint :many
+SELECT
+    utxos.utxo_id, utxos.outpoint, utxos.amt_sats, utxos.taproot_asset_root,
+    utxos.merkle_root, utxos.lease_owner, utxos.lease_expiry, keys.raw_key,
+    keys.key_family, keys.key_index, txns.block_height,
+    genesis.asset_id AS genesis_asset_id, assets.amount AS asset_amount,
+    script_keys.tweaked_script_key, assets.spent AS asset_spent
+FROM managed_utxos utxos
+JOIN internal_keys keys
+    ON utxos.internal_key_id = keys.key_id
+JOIN chain_txns txns
+    ON utxos.txn_id = txns.txn_id
+LEFT JOIN assets
+    ON assets.anchor_utxo_id = utxos.utxo_id
+LEFT JOIN genesis_assets genesis
+    ON assets.genesis_id = genesis.gen_asset_id
+LEFT JOIN script_keys
+    ON assets.script_key_id = script_keys.script_key_id
+-- Outputs that were already spent by a transfer or a sweep are no longer part
+-- of the on-chain footprint.
+WHERE NOT EXISTS (
+          SELECT 1
+          FROM asset_transfer_inputs inputs
+          WHERE inputs.anchor_point = utxos.outpoint
+      ) AND
+      NOT EXISTS (
+          SELECT 1
+          FROM anchor_sweeps sweeps
+          WHERE sweeps.utxo_id = utxos.utxo_id
+      )
+ORDER BY utxos.utxo_id, assets.asset_id;
+
+-- name: FetchFeeBumpAnchorFootprint :many
+SELECT txns.txid, txns.block_height, anchors.output_index, anchors.amt_sats,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM transfer_fee_bump_anchors anchors
+JOIN asset_transfers transfers
+    ON anchors.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+JOIN internal_keys keys
+    ON anchors.internal_key_id = keys.key_id
+-- Fee bump anchors that were spent by a child transaction or belong to a
+-- cancelled transfer are no longer part of the on-chain footprint.
+WHERE anchors.child_txid IS NULL AND transfers.cancelled = FALSE
+ORDER BY anchors.id;
+
+-- name: InsertAnchorSweep :exec
+INSERT INTO anchor_sweeps (
+    utxo_id, sweep_txid, swept_at
+) VALUES (
+    @utxo_id, @sweep_txid, @swept_at
+);
+
+-- name: QueryAnchorSweeps :many
+SELECT utxos.outpoint, utxos.amt_sats, sweeps.sweep_txid, sweeps.swept_at
+FROM anchor_sweeps sweeps
+JOIN managed_utxos utxos
+    ON sweeps.utxo_id = utxos.utxo_id
+ORDER BY sweeps.id;
+
+-- name: InsertGroupKeyRotation :exec
+INSERT INTO group_key_rotations (
+    group_key, sequence, block_height, rotation, new_key_id
+) VALUES (
+    @group_key, @sequence, @block_height, @rotation, @new_key_id
+);
+
+-- name: DeleteGroupKeyRotations :exec
+DELETE FROM group_key_rotations
+WHERE group_key = @group_key AND sequence >= @sequence;
+
+-- name: FetchGroupKeyRotations :many
+SELECT rotations.sequence, rotations.rotation, keys.raw_key AS new_raw_key,
+    keys.key_family AS new_key_family, keys.key_index AS new_key_index
+FROM group_key_rotations rotations
+LEFT JOIN internal_keys keys
+    ON rotations.new_key_id = keys.key_id
+WHERE rotations.group_key = @group_key
+ORDER BY rotations.sequence;
@@ -0,0 +1,24 @@
+-- name: UpsertProofDeadLetter :exec
+INSERT INTO proof_dead_letters (
+    outpoint, asset_id, script_key, proof_file, attempts, error_msg,
+    created_at
+) VALUES (
+    @outpoint, @asset_id, @script_key, @proof_file, @attempts, @error_msg,
+    @created_at
+)
+ON CONFLICT (outpoint)
+    DO UPDATE SET asset_id = EXCLUDED.asset_id,
+                  script_key = EXCLUDED.script_key,
+                  proof_file = EXCLUDED.proof_file,
+                  attempts = EXCLUDED.attempts,
+                  error_msg = EXCLUDED.error_msg,
+                  created_at = EXCLUDED.created_at;
+
+-- name: QueryProofDeadLetters :many
+SELECT *
+FROM proof_dead_letters
+ORDER BY created_at, id;
+
+-- name: DeleteProofDeadLetter :execrows
+DELETE FROM proof_dead_letters
+WHERE outpoint = @outpoint;
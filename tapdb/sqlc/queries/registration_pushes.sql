@@ -0,0 +1,50 @@
+-- name: UpsertRegistrationPushQueueEntry :exec
+INSERT INTO registration_push_queue (
+    server_host, asset_id, group_key, proof_type, leaf_outpoint,
+    leaf_script_key, num_attempts, next_attempt_time, last_error,
+    creation_time
+) VALUES (
+    @server_host, @asset_id, @group_key, @proof_type, @leaf_outpoint,
+    @leaf_script_key, @num_attempts, @next_attempt_time, @last_error,
+    @creation_time
+)
+ON CONFLICT (server_host, asset_id, proof_type, leaf_outpoint, leaf_script_key)
+    -- If the same proof is pushed again, we'll just update the retry
+    -- information but keep the original creation time.
+    DO UPDATE SET num_attempts = EXCLUDED.num_attempts,
+        next_attempt_time = EXCLUDED.next_attempt_time,
+        last_error = EXCLUDED.last_error;
+
+-- name: CountRegistrationPushQueueEntries :one
+SELECT COUNT(*)
+FROM registration_push_queue
+WHERE server_host = @server_host;
+
+-- name: RegistrationPushQueueEntryExists :one
+SELECT EXISTS (
+    SELECT 1
+    FROM registration_push_queue
+    WHERE server_host = @server_host
+        AND asset_id = @asset_id
+        AND proof_type = @proof_type
+        AND leaf_outpoint = @leaf_outpoint
+        AND leaf_script_key = @leaf_script_key
+);
+
+-- name: QueryRegistrationPushQueue :many
+SELECT *
+FROM registration_push_queue
+WHERE (next_attempt_time <= sqlc.narg('due_before') OR
+    sqlc.narg('due_before') IS NULL)
+ORDER BY next_attempt_time, id;
+
+-- name: DeleteRegistrationPushQueueEntry :exec
+DELETE FROM registration_push_queue
+WHERE id = @id;
+
+-- name: UpdateRegistrationPushQueueEntry :exec
+UPDATE registration_push_queue
+SET num_attempts = @num_attempts,
+    next_attempt_time = @next_attempt_time,
+    last_error = @last_error
+WHERE id = @id;
@@ -0,0 +1,20 @@
+-- name: InsertSendEvent :one
+INSERT INTO send_events (
+    event_time, event_data
+) VALUES (
+    @event_time, @event_data
+) RETURNING event_id;
+
+-- name: QuerySendEvents :many
+SELECT *
+FROM send_events
+WHERE event_id > @after_event_id
+    AND event_time >= @min_event_time
+ORDER BY event_id;
+
+-- name: DeleteSendEventsBefore :execrows
+DELETE FROM send_events
+WHERE event_time < @cutoff_time
+    -- The newest event is always kept, so the IDs of new events continue
+    -- from it instead of being re-used once all older events were pruned.
+    AND event_id < (SELECT MAX(event_id) FROM send_events);
@@ -5,9 +5,11 @@ WITH target_txn(txn_id) AS (
     WHERE txid = @anchor_txid
 )
 INSERT INTO asset_transfers (
-    height_hint, anchor_txn_id, transfer_time_unix
+    height_hint, anchor_txn_id, transfer_time_unix, staged, claim_id, label,
+    idempotency_key
 ) VALUES (
-    @height_hint, (SELECT txn_id FROM target_txn), @transfer_time_unix
+    @height_hint, (SELECT txn_id FROM target_txn), @transfer_time_unix,
+    @staged, @claim_id, @label, @idempotency_key
 ) RETURNING id;
 
 -- name: InsertAssetTransferInput :exec
@@ -22,28 +24,130 @@ INSERT INTO asset_transfer_outputs (
     transfer_id, anchor_utxo, script_key, script_key_local,
     amount, serialized_witnesses, split_commitment_root_hash,
     split_commitment_root_value, proof_suffix, num_passive_assets,
-    output_type, proof_courier_addr, asset_version
+    output_type, proof_courier_addr, asset_version, asset_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 );
 
 -- name: QueryAssetTransfers :many
 SELECT
-    id, height_hint, txns.txid, transfer_time_unix
+    id, height_hint, txns.txid, transfer_time_unix, confirmation_time_unix,
+    delivery_complete_time_unix, staged, cancelled, claim_id, label,
+    idempotency_key
 FROM asset_transfers transfers
 JOIN chain_txns txns
     ON transfers.anchor_txn_id = txns.txn_id
 -- We'll use this clause to filter out for only transfers that are
--- unconfirmed. But only if the unconf_only field is set.
+-- unconfirmed and weren't cancelled. But only if the unconf_only field is set.
 WHERE (@unconf_only = false OR @unconf_only IS NULL OR
-    (CASE WHEN txns.block_hash IS NULL THEN true ELSE false END) = @unconf_only)
+    ((CASE WHEN txns.block_hash IS NULL THEN true ELSE false END) = @unconf_only
+        AND transfers.cancelled = false))
 
 -- Here we have another optional query clause to select a given transfer
 -- based on the anchor_tx_hash, but only if it's specified.
 AND (txns.txid = sqlc.narg('anchor_tx_hash') OR
     sqlc.narg('anchor_tx_hash') IS NULL)
+
+-- Finally, we can select only the transfers whose label contains the given
+-- substring, ignoring case. Wildcards in the substring must be escaped with a
+-- backslash to be matched literally.
+AND (LOWER(transfers.label) LIKE
+    '%' || LOWER(sqlc.narg('label_substr')) || '%' ESCAPE '\' OR
+    sqlc.narg('label_substr') IS NULL)
+
+-- We can also select the transfers created with the given idempotency key,
+-- optionally only those created at or after the given time.
+AND (transfers.idempotency_key = sqlc.narg('idempotency_key') OR
+    sqlc.narg('idempotency_key') IS NULL)
+AND (transfers.transfer_time_unix >= sqlc.narg('min_transfer_time') OR
+    sqlc.narg('min_transfer_time') IS NULL)
+
+-- And we can select only the transfers that spent an input or created an
+-- output with the given script key, compared in its 32-byte x-only form.
+AND (sqlc.narg('script_key') IS NULL OR EXISTS (
+    SELECT 1
+    FROM asset_transfer_inputs inputs
+    WHERE inputs.transfer_id = transfers.id
+        AND substr(inputs.script_key, 2) = sqlc.narg('script_key')
+) OR EXISTS (
+    SELECT 1
+    FROM asset_transfer_outputs outputs
+    JOIN script_keys
+        ON outputs.script_key = script_keys.script_key_id
+    WHERE outputs.transfer_id = transfers.id
+        AND substr(script_keys.tweaked_script_key, 2) = sqlc.narg('script_key')
+))
 ORDER BY transfer_time_unix;
 
+-- name: SetTransferCompletionTimes :exec
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = @anchor_txid
+)
+UPDATE asset_transfers
+SET confirmation_time_unix = @confirmation_time_unix,
+    delivery_complete_time_unix = @delivery_complete_time_unix
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn);
+
+-- name: UnstageAssetTransfer :execrows
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = @anchor_txid
+)
+UPDATE asset_transfers
+SET staged = FALSE
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn) AND staged = TRUE;
+
+-- name: CancelAssetTransfer :execrows
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = @anchor_txid AND block_hash IS NULL
+)
+UPDATE asset_transfers
+SET cancelled = TRUE
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn) AND cancelled = FALSE;
+
+-- name: InsertTransferAnchorTx :exec
+INSERT INTO transfer_anchor_txs (
+    transfer_id, txid, raw_tx, chain_fees, funded_psbt, change_output_index,
+    fee_escalation
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+);
+
+-- name: FetchTransferAnchorTxs :many
+SELECT *
+FROM transfer_anchor_txs
+WHERE transfer_id = $1
+ORDER BY id;
+
+-- name: InsertTransferFeeBumpAnchor :exec
+INSERT INTO transfer_fee_bump_anchors (
+    transfer_id, output_index, amt_sats, internal_key_id
+) VALUES (
+    @transfer_id, @output_index, @amt_sats, @internal_key_id
+);
+
+-- name: FetchTransferFeeBumpAnchor :one
+SELECT anchors.output_index, anchors.amt_sats, anchors.child_txid,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM transfer_fee_bump_anchors anchors
+JOIN internal_keys keys
+    ON anchors.internal_key_id = keys.key_id
+WHERE anchors.transfer_id = @transfer_id;
+
+-- name: SetTransferFeeBumpChild :exec
+UPDATE transfer_fee_bump_anchors
+SET child_txid = @child_txid
+WHERE transfer_id = @transfer_id;
+
+-- name: DeleteTransferAnchorTxsAfter :exec
+DELETE FROM transfer_anchor_txs
+WHERE transfer_id = @transfer_id AND id > @anchor_tx_id;
+
 -- name: FetchTransferInputs :many
 SELECT input_id, anchor_point, asset_id, script_key, amount
 FROM asset_transfer_inputs inputs
@@ -54,7 +158,13 @@ ORDER BY input_id;
 SELECT
     output_id, proof_suffix, amount, serialized_witnesses, script_key_local,
     split_commitment_root_hash, split_commitment_root_value, num_passive_assets,
-    output_type, proof_courier_addr, asset_version,
+    output_type, proof_courier_addr, asset_version, proof_delivery_status,
+    proof_delivery_courier_addr, proof_delivery_attempts, asset_id,
+    EXISTS (
+        SELECT 1
+        FROM proof_delivery_receipts receipts
+        WHERE receipts.script_key = script_keys.tweaked_script_key
+    ) AS proof_delivery_receipt,
     utxos.utxo_id AS anchor_utxo_id,
     utxos.outpoint AS anchor_outpoint,
     utxos.amt_sats AS anchor_value,
@@ -82,6 +192,34 @@ JOIN internal_keys utxo_internal_keys
 WHERE transfer_id = $1
 ORDER BY output_id;
 
+-- name: SetProofDeliveryOutcome :exec
+WITH target_utxo(utxo_id) AS (
+    SELECT utxo_id
+    FROM managed_utxos
+    WHERE outpoint = @anchor_outpoint
+), target_script_key(script_key_id) AS (
+    SELECT script_key_id
+    FROM script_keys
+    WHERE tweaked_script_key = @script_key
+)
+UPDATE asset_transfer_outputs
+SET proof_delivery_status = @status,
+    proof_delivery_courier_addr = @courier_addr,
+    proof_delivery_attempts = @num_attempts
+WHERE anchor_utxo = (SELECT utxo_id FROM target_utxo)
+    AND script_key = (SELECT script_key_id FROM target_script_key);
+
+-- name: QueryPendingProofDeliveryTransfers :many
+SELECT DISTINCT txns.txid
+FROM asset_transfer_outputs outputs
+JOIN asset_transfers transfers
+    ON outputs.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+WHERE outputs.proof_delivery_status = @status
+    AND txns.block_hash IS NOT NULL
+    AND transfers.cancelled = false;
+
 -- name: ApplyPendingOutput :one
 WITH spent_asset AS (
     SELECT genesis_id, asset_group_witness_id, script_version, lock_time,
@@ -92,7 +230,8 @@ WITH spent_asset AS (
 INSERT INTO assets (
     genesis_id, version, asset_group_witness_id, script_version, lock_time,
     relative_lock_time, script_key_id, anchor_utxo_id, amount,
-    split_commitment_root_hash, split_commitment_root_value, spent
+    split_commitment_root_hash, split_commitment_root_value, spent,
+    acquired_at
 ) VALUES (
     (SELECT genesis_id FROM spent_asset),
     @asset_version,
@@ -101,7 +240,7 @@ INSERT INTO assets (
     (SELECT lock_time FROM spent_asset),
     (SELECT relative_lock_time FROM spent_asset),
     @script_key_id, @anchor_utxo_id, @amount, @split_commitment_root_hash,
-    @split_commitment_root_value, @spent
+    @split_commitment_root_value, @spent, @acquired_at
 )
 RETURNING asset_id;
 
@@ -159,3 +298,82 @@ FROM passive_assets as passive
     JOIN genesis_assets
         ON assets.genesis_id = genesis_assets.gen_asset_id
 WHERE passive.transfer_id = @transfer_id;
+
+-- name: UpsertProofDeliveryReceipt :exec
+INSERT INTO proof_delivery_receipts (
+    script_key, asset_id, outpoint, proof_hash, raw_script_key, tap_tweak,
+    signature, receive_time
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (script_key, proof_hash)
+    -- The receipt for the same proof doesn't change, so we only update the
+    -- time we last received it.
+    DO UPDATE SET receive_time = EXCLUDED.receive_time;
+
+-- name: QueryProofDeliveryReceipts :many
+SELECT *
+FROM proof_delivery_receipts
+WHERE script_key = $1
+ORDER BY receive_time DESC;
+
+-- name: UpsertTransferReOrgWatch :exec
+INSERT INTO transfer_reorg_watches (
+    transfer_id, conf_height, abandon_pending
+) VALUES (
+    @transfer_id, @conf_height, @abandon_pending
+)
+ON CONFLICT (transfer_id)
+    DO UPDATE SET conf_height = EXCLUDED.conf_height,
+        abandon_pending = EXCLUDED.abandon_pending;
+
+-- name: InsertTransferReOrgWatchInput :exec
+INSERT INTO transfer_reorg_watch_inputs (
+    transfer_id, outpoint, pk_script
+) VALUES (
+    @transfer_id, @outpoint, @pk_script
+)
+ON CONFLICT (transfer_id, outpoint) DO NOTHING;
+
+-- name: QueryTransferReOrgWatches :many
+SELECT watches.transfer_id, txns.txid, watches.conf_height,
+       watches.abandon_pending
+FROM transfer_reorg_watches watches
+JOIN asset_transfers transfers
+    ON watches.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+ORDER BY watches.transfer_id;
+
+-- name: FetchTransferReOrgWatchInputs :many
+SELECT outpoint, pk_script
+FROM transfer_reorg_watch_inputs
+WHERE transfer_id = @transfer_id
+ORDER BY outpoint;
+
+-- name: DeleteTransferReOrgWatchInputs :exec
+DELETE FROM transfer_reorg_watch_inputs
+WHERE transfer_id = @transfer_id;
+
+-- name: DeleteTransferReOrgWatch :exec
+DELETE FROM transfer_reorg_watches
+WHERE transfer_id = @transfer_id;
+
+-- name: InsertProofGapServe :exec
+INSERT INTO proof_gap_serves (
+    asset_id, script_key, outpoint, start_time
+) VALUES (
+    @asset_id, @script_key, @outpoint, @start_time
+)
+ON CONFLICT (asset_id, script_key, outpoint) DO NOTHING;
+
+-- name: QueryProofGapServes :many
+SELECT *
+FROM proof_gap_serves
+ORDER BY start_time;
+
+-- name: DeleteProofGapServe :exec
+DELETE FROM proof_gap_serves
+WHERE asset_id = @asset_id
+    AND script_key = @script_key
+    AND outpoint = @outpoint;
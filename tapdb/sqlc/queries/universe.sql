@@ -32,6 +32,38 @@ WITH root_id AS (
 DELETE FROM universe_events
 WHERE universe_root_id = (SELECT id from root_id);
 
+-- name: DecrementUniverseStatsCounters :exec
+WITH root_id AS (
+    SELECT id
+    FROM universe_roots
+    WHERE namespace_root = @namespace_root
+)
+UPDATE universe_stats_counters
+SET num_events = num_events - (
+    SELECT COUNT(*)
+    FROM universe_events events
+    WHERE events.universe_root_id = (SELECT id FROM root_id)
+        AND events.event_type = universe_stats_counters.event_type
+);
+
+-- name: DeleteUniverseProofEvents :execrows
+DELETE FROM universe_events
+WHERE event_id IN (
+    SELECT events.event_id
+    FROM universe_events events
+    JOIN universe_roots roots
+        ON events.universe_root_id = roots.id
+    WHERE roots.namespace_root = @namespace_root
+        AND events.event_type = 'NEW_PROOF'
+    ORDER BY events.event_time, events.event_id
+    LIMIT @num_events
+);
+
+-- name: DecrementUniverseStatsCounter :exec
+UPDATE universe_stats_counters
+SET num_events = num_events - @num_events
+WHERE event_type = @event_type;
+
 -- name: DeleteUniverseRoot :exec
 DELETE FROM universe_roots
 WHERE namespace_root = @namespace_root;
@@ -53,6 +85,10 @@ INSERT INTO universe_leaves (
 DELETE FROM universe_leaves
 WHERE leaf_node_namespace = @namespace;
 
+-- name: DeleteUniverseLeaf :exec
+DELETE FROM universe_leaves
+WHERE leaf_node_namespace = @namespace AND leaf_node_key = @leaf_node_key;
+
 -- name: QueryUniverseLeaves :many
 SELECT leaves.script_key_bytes, gen.gen_asset_id, nodes.value genesis_proof, 
        nodes.sum sum_amt, gen.asset_id
@@ -81,7 +117,12 @@ SELECT * FROM universe_leaves;
 -- name: UniverseRoots :many
 SELECT universe_roots.asset_id, group_key, proof_type,
        mssmt_roots.root_hash root_hash, mssmt_nodes.sum root_sum,
-       genesis_assets.asset_tag asset_name
+       genesis_assets.asset_tag asset_name,
+       EXISTS (
+           SELECT 1
+           FROM asset_minting_batches batches
+           WHERE batches.genesis_id = genesis_assets.genesis_point_id
+       ) AS issuance_origin
 FROM universe_roots
 JOIN mssmt_roots
     ON universe_roots.namespace_root = mssmt_roots.namespace
@@ -107,6 +148,11 @@ UPDATE universe_servers
 SET last_sync_time = @new_sync_time
 WHERE server_host = @target_server;
 
+-- name: LogServerSyncError :exec
+UPDATE universe_servers
+SET last_sync_error = sqlc.narg('sync_error')
+WHERE server_host = @target_server;
+
 -- name: ListUniverseServers :many
 SELECT * FROM universe_servers;
 
@@ -168,10 +214,22 @@ INSERT INTO universe_events (
     @event_time, @event_timestamp
 );
 
+-- name: IncrementUniverseStatsCounter :exec
+INSERT INTO universe_stats_counters (
+    event_type, num_events
+) VALUES (
+    @event_type, 1
+) ON CONFLICT (event_type)
+    DO UPDATE SET num_events = universe_stats_counters.num_events + 1;
+
 -- name: QueryUniverseStats :one
 WITH stats AS (
-    SELECT total_asset_syncs, total_asset_proofs
-    FROM universe_stats
+    SELECT
+        CASE WHEN event_type = 'SYNC' THEN num_events ELSE 0 END
+            AS total_asset_syncs,
+        CASE WHEN event_type = 'NEW_PROOF' THEN num_events ELSE 0 END
+            AS total_asset_proofs
+    FROM universe_stats_counters
 ), group_ids AS (
     SELECT id
     FROM universe_roots
@@ -209,6 +267,14 @@ SELECT SUM(total_syncs) AS total_syncs,
        SUM(total_num_assets) AS total_num_assets
 FROM aggregated;
 
+-- name: QueryRecentUniverseEvents :one
+SELECT
+    COUNT(CASE WHEN event_type = 'SYNC' THEN 1 ELSE NULL END) AS num_syncs,
+    COUNT(CASE WHEN event_type = 'NEW_PROOF' THEN 1 ELSE NULL END)
+        AS num_proofs
+FROM universe_events
+WHERE event_timestamp >= @since_timestamp;
+
 -- TODO(roasbeef): use the universe id instead for the grouping? so namespace
 -- root, simplifies queries
 
@@ -329,6 +395,17 @@ ON CONFLICT(proof_type)
 SELECT proof_type, allow_sync_insert, allow_sync_export
 FROM federation_global_sync_config;
 
+-- name: UpsertFederationDefaultServer :exec
+INSERT INTO federation_default_server (id, server_host)
+VALUES (1, @server_host)
+ON CONFLICT(id)
+    DO UPDATE SET server_host = @server_host;
+
+-- name: QueryFederationDefaultServer :one
+SELECT server_host
+FROM federation_default_server
+WHERE id = 1;
+
 -- name: UpsertFederationUniSyncConfig :exec
 INSERT INTO federation_uni_sync_config  (
     asset_id, group_key, proof_type, allow_sync_insert, allow_sync_export
@@ -343,4 +420,59 @@ ON CONFLICT(asset_id, group_key, proof_type)
 
 -- name: QueryFederationUniSyncConfigs :many
 SELECT asset_id, group_key, proof_type, allow_sync_insert, allow_sync_export
-FROM federation_uni_sync_config;
\ No newline at end of file
+FROM federation_uni_sync_config;
+-- name: UpsertFederationPushQueueEntry :exec
+INSERT INTO federation_push_queue (
+    server_id, asset_id, group_key, proof_type, leaf_outpoint,
+    leaf_script_key, num_attempts, next_attempt_time, last_error,
+    creation_time
+) VALUES (
+    @server_id, @asset_id, @group_key, @proof_type, @leaf_outpoint,
+    @leaf_script_key, @num_attempts, @next_attempt_time, @last_error,
+    @creation_time
+)
+ON CONFLICT (server_id, asset_id, proof_type, leaf_outpoint, leaf_script_key)
+    -- If the same proof is pushed again, we'll just update the retry
+    -- information but keep the original creation time.
+    DO UPDATE SET num_attempts = EXCLUDED.num_attempts,
+        next_attempt_time = EXCLUDED.next_attempt_time,
+        last_error = EXCLUDED.last_error;
+
+-- name: CountFederationPushQueueEntries :one
+SELECT COUNT(*)
+FROM federation_push_queue
+WHERE server_id = @server_id;
+
+-- name: FederationPushQueueEntryExists :one
+SELECT EXISTS (
+    SELECT 1
+    FROM federation_push_queue
+    WHERE server_id = @server_id
+        AND asset_id = @asset_id
+        AND proof_type = @proof_type
+        AND leaf_outpoint = @leaf_outpoint
+        AND leaf_script_key = @leaf_script_key
+);
+
+-- name: QueryFederationPushQueue :many
+SELECT queue.id, queue.server_id, servers.server_host, queue.asset_id,
+    queue.group_key, queue.proof_type, queue.leaf_outpoint,
+    queue.leaf_script_key, queue.num_attempts, queue.next_attempt_time,
+    queue.last_error, queue.creation_time
+FROM federation_push_queue queue
+JOIN universe_servers servers
+    ON queue.server_id = servers.id
+WHERE (queue.next_attempt_time <= sqlc.narg('due_before') OR
+    sqlc.narg('due_before') IS NULL)
+ORDER BY queue.next_attempt_time, queue.id;
+
+-- name: DeleteFederationPushQueueEntry :exec
+DELETE FROM federation_push_queue
+WHERE id = @id;
+
+-- name: UpdateFederationPushQueueEntry :exec
+UPDATE federation_push_queue
+SET num_attempts = @num_attempts,
+    next_attempt_time = @next_attempt_time,
+    last_error = @last_error
+WHERE id = @id;
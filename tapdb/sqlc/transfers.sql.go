@@ -21,7 +21,8 @@ WITH spent_asset AS (
 INSERT INTO assets (
     genesis_id, version, asset_group_witness_id, script_version, lock_time,
     relative_lock_time, script_key_id, anchor_utxo_id, amount,
-    split_commitment_root_hash, split_commitment_root_value, spent
+    split_commitment_root_hash, split_commitment_root_value, spent,
+    acquired_at
 ) VALUES (
     (SELECT genesis_id FROM spent_asset),
     $1,
@@ -30,7 +31,7 @@ INSERT INTO assets (
     (SELECT lock_time FROM spent_asset),
     (SELECT relative_lock_time FROM spent_asset),
     $2, $3, $4, $5,
-    $6, $7
+    $6, $7, $9
 )
 RETURNING asset_id
 `
@@ -44,6 +45,7 @@ type ApplyPendingOutputParams struct {
 	SplitCommitmentRootValue sql.NullInt64
 	Spent                    bool
 	SpentAssetID             int64
+	AcquiredAt               sql.NullTime
 }
 
 func (q *Queries) ApplyPendingOutput(ctx context.Context, arg ApplyPendingOutputParams) (int64, error) {
@@ -56,12 +58,32 @@ func (q *Queries) ApplyPendingOutput(ctx context.Context, arg ApplyPendingOutput
 		arg.SplitCommitmentRootValue,
 		arg.Spent,
 		arg.SpentAssetID,
+		arg.AcquiredAt,
 	)
 	var asset_id int64
 	err := row.Scan(&asset_id)
 	return asset_id, err
 }
 
+const cancelAssetTransfer = `-- name: CancelAssetTransfer :execrows
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = $1 AND block_hash IS NULL
+)
+UPDATE asset_transfers
+SET cancelled = TRUE
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn) AND cancelled = FALSE
+`
+
+func (q *Queries) CancelAssetTransfer(ctx context.Context, anchorTxid []byte) (int64, error) {
+	result, err := q.db.ExecContext(ctx, cancelAssetTransfer, anchorTxid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteAssetWitnesses = `-- name: DeleteAssetWitnesses :exec
 DELETE FROM asset_witnesses
 WHERE asset_id = $1
@@ -72,6 +94,130 @@ func (q *Queries) DeleteAssetWitnesses(ctx context.Context, assetID int64) error
 	return err
 }
 
+const deleteProofGapServe = `-- name: DeleteProofGapServe :exec
+DELETE FROM proof_gap_serves
+WHERE asset_id = $1
+    AND script_key = $2
+    AND outpoint = $3
+`
+
+type DeleteProofGapServeParams struct {
+	AssetID   []byte
+	ScriptKey []byte
+	Outpoint  []byte
+}
+
+func (q *Queries) DeleteProofGapServe(ctx context.Context, arg DeleteProofGapServeParams) error {
+	_, err := q.db.ExecContext(ctx, deleteProofGapServe, arg.AssetID, arg.ScriptKey, arg.Outpoint)
+	return err
+}
+
+const deleteTransferAnchorTxsAfter = `-- name: DeleteTransferAnchorTxsAfter :exec
+DELETE FROM transfer_anchor_txs
+WHERE transfer_id = $1 AND id > $2
+`
+
+type DeleteTransferAnchorTxsAfterParams struct {
+	TransferID int64
+	AnchorTxID int64
+}
+
+func (q *Queries) DeleteTransferAnchorTxsAfter(ctx context.Context, arg DeleteTransferAnchorTxsAfterParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTransferAnchorTxsAfter, arg.TransferID, arg.AnchorTxID)
+	return err
+}
+
+const deleteTransferReOrgWatch = `-- name: DeleteTransferReOrgWatch :exec
+DELETE FROM transfer_reorg_watches
+WHERE transfer_id = $1
+`
+
+func (q *Queries) DeleteTransferReOrgWatch(ctx context.Context, transferID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTransferReOrgWatch, transferID)
+	return err
+}
+
+const deleteTransferReOrgWatchInputs = `-- name: DeleteTransferReOrgWatchInputs :exec
+DELETE FROM transfer_reorg_watch_inputs
+WHERE transfer_id = $1
+`
+
+func (q *Queries) DeleteTransferReOrgWatchInputs(ctx context.Context, transferID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTransferReOrgWatchInputs, transferID)
+	return err
+}
+
+const fetchTransferAnchorTxs = `-- name: FetchTransferAnchorTxs :many
+SELECT id, transfer_id, txid, raw_tx, chain_fees, funded_psbt, change_output_index, fee_escalation
+FROM transfer_anchor_txs
+WHERE transfer_id = $1
+ORDER BY id
+`
+
+func (q *Queries) FetchTransferAnchorTxs(ctx context.Context, transferID int64) ([]TransferAnchorTx, error) {
+	rows, err := q.db.QueryContext(ctx, fetchTransferAnchorTxs, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TransferAnchorTx
+	for rows.Next() {
+		var i TransferAnchorTx
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransferID,
+			&i.Txid,
+			&i.RawTx,
+			&i.ChainFees,
+			&i.FundedPsbt,
+			&i.ChangeOutputIndex,
+			&i.FeeEscalation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const fetchTransferFeeBumpAnchor = `-- name: FetchTransferFeeBumpAnchor :one
+SELECT anchors.output_index, anchors.amt_sats, anchors.child_txid,
+    keys.raw_key, keys.key_family, keys.key_index
+FROM transfer_fee_bump_anchors anchors
+JOIN internal_keys keys
+    ON anchors.internal_key_id = keys.key_id
+WHERE anchors.transfer_id = $1
+`
+
+type FetchTransferFeeBumpAnchorRow struct {
+	OutputIndex int32
+	AmtSats     int64
+	ChildTxid   []byte
+	RawKey      []byte
+	KeyFamily   int32
+	KeyIndex    int32
+}
+
+func (q *Queries) FetchTransferFeeBumpAnchor(ctx context.Context, transferID int64) (FetchTransferFeeBumpAnchorRow, error) {
+	row := q.db.QueryRowContext(ctx, fetchTransferFeeBumpAnchor, transferID)
+	var i FetchTransferFeeBumpAnchorRow
+	err := row.Scan(
+		&i.OutputIndex,
+		&i.AmtSats,
+		&i.ChildTxid,
+		&i.RawKey,
+		&i.KeyFamily,
+		&i.KeyIndex,
+	)
+	return i, err
+}
+
 const fetchTransferInputs = `-- name: FetchTransferInputs :many
 SELECT input_id, anchor_point, asset_id, script_key, amount
 FROM asset_transfer_inputs inputs
@@ -120,7 +266,13 @@ const fetchTransferOutputs = `-- name: FetchTransferOutputs :many
 SELECT
     output_id, proof_suffix, amount, serialized_witnesses, script_key_local,
     split_commitment_root_hash, split_commitment_root_value, num_passive_assets,
-    output_type, proof_courier_addr, asset_version,
+    output_type, proof_courier_addr, asset_version, proof_delivery_status,
+    proof_delivery_courier_addr, proof_delivery_attempts, asset_id,
+    EXISTS (
+        SELECT 1
+        FROM proof_delivery_receipts receipts
+        WHERE receipts.script_key = script_keys.tweaked_script_key
+    ) AS proof_delivery_receipt,
     utxos.utxo_id AS anchor_utxo_id,
     utxos.outpoint AS anchor_outpoint,
     utxos.amt_sats AS anchor_value,
@@ -161,6 +313,11 @@ type FetchTransferOutputsRow struct {
 	OutputType               int16
 	ProofCourierAddr         []byte
 	AssetVersion             int32
+	ProofDeliveryStatus      sql.NullInt16
+	ProofDeliveryCourierAddr []byte
+	ProofDeliveryAttempts    sql.NullInt32
+	AssetID                  []byte
+	ProofDeliveryReceipt     bool
 	AnchorUtxoID             int64
 	AnchorOutpoint           []byte
 	AnchorValue              int64
@@ -199,6 +356,11 @@ func (q *Queries) FetchTransferOutputs(ctx context.Context, transferID int64) ([
 			&i.OutputType,
 			&i.ProofCourierAddr,
 			&i.AssetVersion,
+			&i.ProofDeliveryStatus,
+			&i.ProofDeliveryCourierAddr,
+			&i.ProofDeliveryAttempts,
+			&i.AssetID,
+			&i.ProofDeliveryReceipt,
 			&i.AnchorUtxoID,
 			&i.AnchorOutpoint,
 			&i.AnchorValue,
@@ -228,27 +390,76 @@ func (q *Queries) FetchTransferOutputs(ctx context.Context, transferID int64) ([
 	return items, nil
 }
 
+const fetchTransferReOrgWatchInputs = `-- name: FetchTransferReOrgWatchInputs :many
+SELECT outpoint, pk_script
+FROM transfer_reorg_watch_inputs
+WHERE transfer_id = $1
+ORDER BY outpoint
+`
+
+type FetchTransferReOrgWatchInputsRow struct {
+	Outpoint []byte
+	PkScript []byte
+}
+
+func (q *Queries) FetchTransferReOrgWatchInputs(ctx context.Context, transferID int64) ([]FetchTransferReOrgWatchInputsRow, error) {
+	rows, err := q.db.QueryContext(ctx, fetchTransferReOrgWatchInputs, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FetchTransferReOrgWatchInputsRow
+	for rows.Next() {
+		var i FetchTransferReOrgWatchInputsRow
+		if err := rows.Scan(&i.Outpoint, &i.PkScript); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAssetTransfer = `-- name: InsertAssetTransfer :one
 WITH target_txn(txn_id) AS (
     SELECT txn_id
     FROM chain_txns
-    WHERE txid = $3
+    WHERE txid = $7
 )
 INSERT INTO asset_transfers (
-    height_hint, anchor_txn_id, transfer_time_unix
+    height_hint, anchor_txn_id, transfer_time_unix, staged, claim_id, label,
+    idempotency_key
 ) VALUES (
-    $1, (SELECT txn_id FROM target_txn), $2
+    $1, (SELECT txn_id FROM target_txn), $2,
+    $3, $4, $5, $6
 ) RETURNING id
 `
 
 type InsertAssetTransferParams struct {
 	HeightHint       int32
 	TransferTimeUnix time.Time
+	Staged           bool
+	ClaimID          []byte
+	Label            string
+	IdempotencyKey   sql.NullString
 	AnchorTxid       []byte
 }
 
 func (q *Queries) InsertAssetTransfer(ctx context.Context, arg InsertAssetTransferParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, insertAssetTransfer, arg.HeightHint, arg.TransferTimeUnix, arg.AnchorTxid)
+	row := q.db.QueryRowContext(ctx, insertAssetTransfer,
+		arg.HeightHint,
+		arg.TransferTimeUnix,
+		arg.Staged,
+		arg.ClaimID,
+		arg.Label,
+		arg.IdempotencyKey,
+		arg.AnchorTxid,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
@@ -286,9 +497,9 @@ INSERT INTO asset_transfer_outputs (
     transfer_id, anchor_utxo, script_key, script_key_local,
     amount, serialized_witnesses, split_commitment_root_hash,
     split_commitment_root_value, proof_suffix, num_passive_assets,
-    output_type, proof_courier_addr, asset_version
+    output_type, proof_courier_addr, asset_version, asset_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 )
 `
 
@@ -306,6 +517,7 @@ type InsertAssetTransferOutputParams struct {
 	OutputType               int16
 	ProofCourierAddr         []byte
 	AssetVersion             int32
+	AssetID                  []byte
 }
 
 func (q *Queries) InsertAssetTransferOutput(ctx context.Context, arg InsertAssetTransferOutputParams) error {
@@ -323,6 +535,7 @@ func (q *Queries) InsertAssetTransferOutput(ctx context.Context, arg InsertAsset
 		arg.OutputType,
 		arg.ProofCourierAddr,
 		arg.AssetVersion,
+		arg.AssetID,
 	)
 	return err
 }
@@ -375,6 +588,27 @@ func (q *Queries) InsertPassiveAsset(ctx context.Context, arg InsertPassiveAsset
 	return err
 }
 
+const insertProofGapServe = `-- name: InsertProofGapServe :exec
+INSERT INTO proof_gap_serves (
+    asset_id, script_key, outpoint, start_time
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (asset_id, script_key, outpoint) DO NOTHING
+`
+
+type InsertProofGapServeParams struct {
+	AssetID   []byte
+	ScriptKey []byte
+	Outpoint  []byte
+	StartTime time.Time
+}
+
+func (q *Queries) InsertProofGapServe(ctx context.Context, arg InsertProofGapServeParams) error {
+	_, err := q.db.ExecContext(ctx, insertProofGapServe, arg.AssetID, arg.ScriptKey, arg.Outpoint, arg.StartTime)
+	return err
+}
+
 const insertReceiverProofTransferAttempt = `-- name: InsertReceiverProofTransferAttempt :exec
 INSERT INTO receiver_proof_transfer_attempts (
     proof_locator_hash, time_unix
@@ -393,38 +627,165 @@ func (q *Queries) InsertReceiverProofTransferAttempt(ctx context.Context, arg In
 	return err
 }
 
+const insertTransferAnchorTx = `-- name: InsertTransferAnchorTx :exec
+INSERT INTO transfer_anchor_txs (
+    transfer_id, txid, raw_tx, chain_fees, funded_psbt, change_output_index,
+    fee_escalation
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+`
+
+type InsertTransferAnchorTxParams struct {
+	TransferID        int64
+	Txid              []byte
+	RawTx             []byte
+	ChainFees         int64
+	FundedPsbt        []byte
+	ChangeOutputIndex int32
+	FeeEscalation     bool
+}
+
+func (q *Queries) InsertTransferAnchorTx(ctx context.Context, arg InsertTransferAnchorTxParams) error {
+	_, err := q.db.ExecContext(ctx, insertTransferAnchorTx,
+		arg.TransferID,
+		arg.Txid,
+		arg.RawTx,
+		arg.ChainFees,
+		arg.FundedPsbt,
+		arg.ChangeOutputIndex,
+		arg.FeeEscalation,
+	)
+	return err
+}
+
+const insertTransferFeeBumpAnchor = `-- name: InsertTransferFeeBumpAnchor :exec
+INSERT INTO transfer_fee_bump_anchors (
+    transfer_id, output_index, amt_sats, internal_key_id
+) VALUES (
+    $1, $2, $3, $4
+)
+`
+
+type InsertTransferFeeBumpAnchorParams struct {
+	TransferID    int64
+	OutputIndex   int32
+	AmtSats       int64
+	InternalKeyID int64
+}
+
+func (q *Queries) InsertTransferFeeBumpAnchor(ctx context.Context, arg InsertTransferFeeBumpAnchorParams) error {
+	_, err := q.db.ExecContext(ctx, insertTransferFeeBumpAnchor,
+		arg.TransferID,
+		arg.OutputIndex,
+		arg.AmtSats,
+		arg.InternalKeyID,
+	)
+	return err
+}
+
+const insertTransferReOrgWatchInput = `-- name: InsertTransferReOrgWatchInput :exec
+INSERT INTO transfer_reorg_watch_inputs (
+    transfer_id, outpoint, pk_script
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (transfer_id, outpoint) DO NOTHING
+`
+
+type InsertTransferReOrgWatchInputParams struct {
+	TransferID int64
+	Outpoint   []byte
+	PkScript   []byte
+}
+
+func (q *Queries) InsertTransferReOrgWatchInput(ctx context.Context, arg InsertTransferReOrgWatchInputParams) error {
+	_, err := q.db.ExecContext(ctx, insertTransferReOrgWatchInput, arg.TransferID, arg.Outpoint, arg.PkScript)
+	return err
+}
+
 const queryAssetTransfers = `-- name: QueryAssetTransfers :many
 SELECT
-    id, height_hint, txns.txid, transfer_time_unix
+    id, height_hint, txns.txid, transfer_time_unix, confirmation_time_unix,
+    delivery_complete_time_unix, staged, cancelled, claim_id, label,
+    idempotency_key
 FROM asset_transfers transfers
 JOIN chain_txns txns
     ON transfers.anchor_txn_id = txns.txn_id
 WHERE ($1 = false OR $1 IS NULL OR
-    (CASE WHEN txns.block_hash IS NULL THEN true ELSE false END) = $1)
+    ((CASE WHEN txns.block_hash IS NULL THEN true ELSE false END) = $1
+        AND transfers.cancelled = false))
 
 AND (txns.txid = $2 OR
     $2 IS NULL)
+
+AND (LOWER(transfers.label) LIKE
+    '%' || LOWER($3) || '%' ESCAPE '\' OR
+    $3 IS NULL)
+
+AND (transfers.idempotency_key = $4 OR
+    $4 IS NULL)
+AND (transfers.transfer_time_unix >= $5 OR
+    $5 IS NULL)
+
+AND ($6 IS NULL OR EXISTS (
+    SELECT 1
+    FROM asset_transfer_inputs inputs
+    WHERE inputs.transfer_id = transfers.id
+        AND substr(inputs.script_key, 2) = $6
+) OR EXISTS (
+    SELECT 1
+    FROM asset_transfer_outputs outputs
+    JOIN script_keys
+        ON outputs.script_key = script_keys.script_key_id
+    WHERE outputs.transfer_id = transfers.id
+        AND substr(script_keys.tweaked_script_key, 2) = $6
+))
 ORDER BY transfer_time_unix
 `
 
 type QueryAssetTransfersParams struct {
-	UnconfOnly   interface{}
-	AnchorTxHash []byte
+	UnconfOnly      interface{}
+	AnchorTxHash    []byte
+	LabelSubstr     sql.NullString
+	IdempotencyKey  sql.NullString
+	MinTransferTime sql.NullTime
+	ScriptKey       []byte
 }
 
 type QueryAssetTransfersRow struct {
-	ID               int64
-	HeightHint       int32
-	Txid             []byte
-	TransferTimeUnix time.Time
+	ID                       int64
+	HeightHint               int32
+	Txid                     []byte
+	TransferTimeUnix         time.Time
+	ConfirmationTimeUnix     sql.NullTime
+	DeliveryCompleteTimeUnix sql.NullTime
+	Staged                   bool
+	Cancelled                bool
+	ClaimID                  []byte
+	Label                    string
+	IdempotencyKey           sql.NullString
 }
 
 // We'll use this clause to filter out for only transfers that are
-// unconfirmed. But only if the unconf_only field is set.
+// unconfirmed and weren't cancelled. But only if the unconf_only field is set.
 // Here we have another optional query clause to select a given transfer
 // based on the anchor_tx_hash, but only if it's specified.
+// Finally, we can select only the transfers whose label contains the given
+// substring.
+// We can also select the transfers created with the given idempotency key,
+// optionally only those created at or after the given time.
+// And we can select only the transfers that spent an input or created an
+// output with the given script key, compared in its 32-byte x-only form.
 func (q *Queries) QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfersParams) ([]QueryAssetTransfersRow, error) {
-	rows, err := q.db.QueryContext(ctx, queryAssetTransfers, arg.UnconfOnly, arg.AnchorTxHash)
+	rows, err := q.db.QueryContext(ctx, queryAssetTransfers,
+		arg.UnconfOnly,
+		arg.AnchorTxHash,
+		arg.LabelSubstr,
+		arg.IdempotencyKey,
+		arg.MinTransferTime,
+		arg.ScriptKey,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -437,6 +798,13 @@ func (q *Queries) QueryAssetTransfers(ctx context.Context, arg QueryAssetTransfe
 			&i.HeightHint,
 			&i.Txid,
 			&i.TransferTimeUnix,
+			&i.ConfirmationTimeUnix,
+			&i.DeliveryCompleteTimeUnix,
+			&i.Staged,
+			&i.Cancelled,
+			&i.ClaimID,
+			&i.Label,
+			&i.IdempotencyKey,
 		); err != nil {
 			return nil, err
 		}
@@ -504,6 +872,116 @@ func (q *Queries) QueryPassiveAssets(ctx context.Context, transferID int64) ([]Q
 	return items, nil
 }
 
+const queryPendingProofDeliveryTransfers = `-- name: QueryPendingProofDeliveryTransfers :many
+SELECT DISTINCT txns.txid
+FROM asset_transfer_outputs outputs
+JOIN asset_transfers transfers
+    ON outputs.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+WHERE outputs.proof_delivery_status = $1
+    AND txns.block_hash IS NOT NULL
+    AND transfers.cancelled = false
+`
+
+func (q *Queries) QueryPendingProofDeliveryTransfers(ctx context.Context, status sql.NullInt16) ([][]byte, error) {
+	rows, err := q.db.QueryContext(ctx, queryPendingProofDeliveryTransfers, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var txid []byte
+		if err := rows.Scan(&txid); err != nil {
+			return nil, err
+		}
+		items = append(items, txid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryProofDeliveryReceipts = `-- name: QueryProofDeliveryReceipts :many
+SELECT id, script_key, asset_id, outpoint, proof_hash, raw_script_key, tap_tweak, signature, receive_time
+FROM proof_delivery_receipts
+WHERE script_key = $1
+ORDER BY receive_time DESC
+`
+
+func (q *Queries) QueryProofDeliveryReceipts(ctx context.Context, scriptKey []byte) ([]ProofDeliveryReceipt, error) {
+	rows, err := q.db.QueryContext(ctx, queryProofDeliveryReceipts, scriptKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProofDeliveryReceipt
+	for rows.Next() {
+		var i ProofDeliveryReceipt
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScriptKey,
+			&i.AssetID,
+			&i.Outpoint,
+			&i.ProofHash,
+			&i.RawScriptKey,
+			&i.TapTweak,
+			&i.Signature,
+			&i.ReceiveTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryProofGapServes = `-- name: QueryProofGapServes :many
+SELECT id, asset_id, script_key, outpoint, start_time
+FROM proof_gap_serves
+ORDER BY start_time
+`
+
+func (q *Queries) QueryProofGapServes(ctx context.Context) ([]ProofGapServe, error) {
+	rows, err := q.db.QueryContext(ctx, queryProofGapServes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProofGapServe
+	for rows.Next() {
+		var i ProofGapServe
+		if err := rows.Scan(
+			&i.ID,
+			&i.AssetID,
+			&i.ScriptKey,
+			&i.Outpoint,
+			&i.StartTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryReceiverProofTransferAttempt = `-- name: QueryReceiverProofTransferAttempt :many
 SELECT time_unix
 FROM receiver_proof_transfer_attempts
@@ -534,6 +1012,52 @@ func (q *Queries) QueryReceiverProofTransferAttempt(ctx context.Context, proofLo
 	return items, nil
 }
 
+const queryTransferReOrgWatches = `-- name: QueryTransferReOrgWatches :many
+SELECT watches.transfer_id, txns.txid, watches.conf_height,
+       watches.abandon_pending
+FROM transfer_reorg_watches watches
+JOIN asset_transfers transfers
+    ON watches.transfer_id = transfers.id
+JOIN chain_txns txns
+    ON transfers.anchor_txn_id = txns.txn_id
+ORDER BY watches.transfer_id
+`
+
+type QueryTransferReOrgWatchesRow struct {
+	TransferID     int64
+	Txid           []byte
+	ConfHeight     int32
+	AbandonPending bool
+}
+
+func (q *Queries) QueryTransferReOrgWatches(ctx context.Context) ([]QueryTransferReOrgWatchesRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryTransferReOrgWatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryTransferReOrgWatchesRow
+	for rows.Next() {
+		var i QueryTransferReOrgWatchesRow
+		if err := rows.Scan(
+			&i.TransferID,
+			&i.Txid,
+			&i.ConfHeight,
+			&i.AbandonPending,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const reAnchorPassiveAssets = `-- name: ReAnchorPassiveAssets :exec
 UPDATE assets
 SET anchor_utxo_id = $1
@@ -549,3 +1073,158 @@ func (q *Queries) ReAnchorPassiveAssets(ctx context.Context, arg ReAnchorPassive
 	_, err := q.db.ExecContext(ctx, reAnchorPassiveAssets, arg.NewAnchorUtxoID, arg.AssetID)
 	return err
 }
+
+const setTransferCompletionTimes = `-- name: SetTransferCompletionTimes :exec
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = $3
+)
+UPDATE asset_transfers
+SET confirmation_time_unix = $1,
+    delivery_complete_time_unix = $2
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn)
+`
+
+type SetTransferCompletionTimesParams struct {
+	ConfirmationTimeUnix     sql.NullTime
+	DeliveryCompleteTimeUnix sql.NullTime
+	AnchorTxid               []byte
+}
+
+func (q *Queries) SetTransferCompletionTimes(ctx context.Context, arg SetTransferCompletionTimesParams) error {
+	_, err := q.db.ExecContext(ctx, setTransferCompletionTimes, arg.ConfirmationTimeUnix, arg.DeliveryCompleteTimeUnix, arg.AnchorTxid)
+	return err
+}
+
+const setTransferFeeBumpChild = `-- name: SetTransferFeeBumpChild :exec
+UPDATE transfer_fee_bump_anchors
+SET child_txid = $1
+WHERE transfer_id = $2
+`
+
+type SetTransferFeeBumpChildParams struct {
+	ChildTxid  []byte
+	TransferID int64
+}
+
+func (q *Queries) SetTransferFeeBumpChild(ctx context.Context, arg SetTransferFeeBumpChildParams) error {
+	_, err := q.db.ExecContext(ctx, setTransferFeeBumpChild, arg.ChildTxid, arg.TransferID)
+	return err
+}
+
+const setProofDeliveryOutcome = `-- name: SetProofDeliveryOutcome :exec
+WITH target_utxo(utxo_id) AS (
+    SELECT utxo_id
+    FROM managed_utxos
+    WHERE outpoint = $4
+), target_script_key(script_key_id) AS (
+    SELECT script_key_id
+    FROM script_keys
+    WHERE tweaked_script_key = $5
+)
+UPDATE asset_transfer_outputs
+SET proof_delivery_status = $1,
+    proof_delivery_courier_addr = $2,
+    proof_delivery_attempts = $3
+WHERE anchor_utxo = (SELECT utxo_id FROM target_utxo)
+    AND script_key = (SELECT script_key_id FROM target_script_key)
+`
+
+type SetProofDeliveryOutcomeParams struct {
+	Status         sql.NullInt16
+	CourierAddr    []byte
+	NumAttempts    sql.NullInt32
+	AnchorOutpoint []byte
+	ScriptKey      []byte
+}
+
+func (q *Queries) SetProofDeliveryOutcome(ctx context.Context, arg SetProofDeliveryOutcomeParams) error {
+	_, err := q.db.ExecContext(ctx, setProofDeliveryOutcome,
+		arg.Status,
+		arg.CourierAddr,
+		arg.NumAttempts,
+		arg.AnchorOutpoint,
+		arg.ScriptKey,
+	)
+	return err
+}
+
+const unstageAssetTransfer = `-- name: UnstageAssetTransfer :execrows
+WITH target_txn(txn_id) AS (
+    SELECT txn_id
+    FROM chain_txns
+    WHERE txid = $1
+)
+UPDATE asset_transfers
+SET staged = FALSE
+WHERE anchor_txn_id = (SELECT txn_id FROM target_txn) AND staged = TRUE
+`
+
+func (q *Queries) UnstageAssetTransfer(ctx context.Context, anchorTxid []byte) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unstageAssetTransfer, anchorTxid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertProofDeliveryReceipt = `-- name: UpsertProofDeliveryReceipt :exec
+INSERT INTO proof_delivery_receipts (
+    script_key, asset_id, outpoint, proof_hash, raw_script_key, tap_tweak,
+    signature, receive_time
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (script_key, proof_hash)
+    -- The receipt for the same proof doesn't change, so we only update the
+    -- time we last received it.
+    DO UPDATE SET receive_time = EXCLUDED.receive_time
+`
+
+type UpsertProofDeliveryReceiptParams struct {
+	ScriptKey    []byte
+	AssetID      []byte
+	Outpoint     []byte
+	ProofHash    []byte
+	RawScriptKey []byte
+	TapTweak     []byte
+	Signature    []byte
+	ReceiveTime  time.Time
+}
+
+func (q *Queries) UpsertProofDeliveryReceipt(ctx context.Context, arg UpsertProofDeliveryReceiptParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProofDeliveryReceipt,
+		arg.ScriptKey,
+		arg.AssetID,
+		arg.Outpoint,
+		arg.ProofHash,
+		arg.RawScriptKey,
+		arg.TapTweak,
+		arg.Signature,
+		arg.ReceiveTime,
+	)
+	return err
+}
+
+const upsertTransferReOrgWatch = `-- name: UpsertTransferReOrgWatch :exec
+INSERT INTO transfer_reorg_watches (
+    transfer_id, conf_height, abandon_pending
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (transfer_id)
+    DO UPDATE SET conf_height = EXCLUDED.conf_height,
+        abandon_pending = EXCLUDED.abandon_pending
+`
+
+type UpsertTransferReOrgWatchParams struct {
+	TransferID     int64
+	ConfHeight     int32
+	AbandonPending bool
+}
+
+func (q *Queries) UpsertTransferReOrgWatch(ctx context.Context, arg UpsertTransferReOrgWatchParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTransferReOrgWatch, arg.TransferID, arg.ConfHeight, arg.AbandonPending)
+	return err
+}
@@ -11,6 +11,65 @@ import (
 	"time"
 )
 
+const countFederationPushQueueEntries = `-- name: CountFederationPushQueueEntries :one
+SELECT COUNT(*)
+FROM federation_push_queue
+WHERE server_id = $1
+`
+
+func (q *Queries) CountFederationPushQueueEntries(ctx context.Context, serverID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFederationPushQueueEntries, serverID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const decrementUniverseStatsCounter = `-- name: DecrementUniverseStatsCounter :exec
+UPDATE universe_stats_counters
+SET num_events = num_events - $1
+WHERE event_type = $2
+`
+
+type DecrementUniverseStatsCounterParams struct {
+	NumEvents int64
+	EventType string
+}
+
+func (q *Queries) DecrementUniverseStatsCounter(ctx context.Context, arg DecrementUniverseStatsCounterParams) error {
+	_, err := q.db.ExecContext(ctx, decrementUniverseStatsCounter, arg.NumEvents, arg.EventType)
+	return err
+}
+
+const decrementUniverseStatsCounters = `-- name: DecrementUniverseStatsCounters :exec
+WITH root_id AS (
+    SELECT id
+    FROM universe_roots
+    WHERE namespace_root = $1
+)
+UPDATE universe_stats_counters
+SET num_events = num_events - (
+    SELECT COUNT(*)
+    FROM universe_events events
+    WHERE events.universe_root_id = (SELECT id FROM root_id)
+        AND events.event_type = universe_stats_counters.event_type
+)
+`
+
+func (q *Queries) DecrementUniverseStatsCounters(ctx context.Context, namespaceRoot string) error {
+	_, err := q.db.ExecContext(ctx, decrementUniverseStatsCounters, namespaceRoot)
+	return err
+}
+
+const deleteFederationPushQueueEntry = `-- name: DeleteFederationPushQueueEntry :exec
+DELETE FROM federation_push_queue
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFederationPushQueueEntry(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteFederationPushQueueEntry, id)
+	return err
+}
+
 const deleteUniverseEvents = `-- name: DeleteUniverseEvents :exec
 WITH root_id AS (
     SELECT id
@@ -26,6 +85,21 @@ func (q *Queries) DeleteUniverseEvents(ctx context.Context, namespaceRoot string
 	return err
 }
 
+const deleteUniverseLeaf = `-- name: DeleteUniverseLeaf :exec
+DELETE FROM universe_leaves
+WHERE leaf_node_namespace = $1 AND leaf_node_key = $2
+`
+
+type DeleteUniverseLeafParams struct {
+	Namespace   string
+	LeafNodeKey []byte
+}
+
+func (q *Queries) DeleteUniverseLeaf(ctx context.Context, arg DeleteUniverseLeafParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUniverseLeaf, arg.Namespace, arg.LeafNodeKey)
+	return err
+}
+
 const deleteUniverseLeaves = `-- name: DeleteUniverseLeaves :exec
 DELETE FROM universe_leaves
 WHERE leaf_node_namespace = $1
@@ -36,6 +110,33 @@ func (q *Queries) DeleteUniverseLeaves(ctx context.Context, namespace string) er
 	return err
 }
 
+const deleteUniverseProofEvents = `-- name: DeleteUniverseProofEvents :execrows
+DELETE FROM universe_events
+WHERE event_id IN (
+    SELECT events.event_id
+    FROM universe_events events
+    JOIN universe_roots roots
+        ON events.universe_root_id = roots.id
+    WHERE roots.namespace_root = $1
+        AND events.event_type = 'NEW_PROOF'
+    ORDER BY events.event_time, events.event_id
+    LIMIT $2
+)
+`
+
+type DeleteUniverseProofEventsParams struct {
+	NamespaceRoot string
+	NumEvents     int32
+}
+
+func (q *Queries) DeleteUniverseProofEvents(ctx context.Context, arg DeleteUniverseProofEventsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUniverseProofEvents, arg.NamespaceRoot, arg.NumEvents)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteUniverseRoot = `-- name: DeleteUniverseRoot :exec
 DELETE FROM universe_roots
 WHERE namespace_root = $1
@@ -61,6 +162,39 @@ func (q *Queries) DeleteUniverseServer(ctx context.Context, arg DeleteUniverseSe
 	return err
 }
 
+const federationPushQueueEntryExists = `-- name: FederationPushQueueEntryExists :one
+SELECT EXISTS (
+    SELECT 1
+    FROM federation_push_queue
+    WHERE server_id = $1
+        AND asset_id = $2
+        AND proof_type = $3
+        AND leaf_outpoint = $4
+        AND leaf_script_key = $5
+)
+`
+
+type FederationPushQueueEntryExistsParams struct {
+	ServerID      int64
+	AssetID       []byte
+	ProofType     string
+	LeafOutpoint  []byte
+	LeafScriptKey []byte
+}
+
+func (q *Queries) FederationPushQueueEntryExists(ctx context.Context, arg FederationPushQueueEntryExistsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, federationPushQueueEntryExists,
+		arg.ServerID,
+		arg.AssetID,
+		arg.ProofType,
+		arg.LeafOutpoint,
+		arg.LeafScriptKey,
+	)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const fetchUniverseKeys = `-- name: FetchUniverseKeys :many
 SELECT leaves.minting_point, leaves.script_key_bytes
 FROM universe_leaves leaves
@@ -133,6 +267,20 @@ func (q *Queries) FetchUniverseRoot(ctx context.Context, namespace string) (Fetc
 	return i, err
 }
 
+const incrementUniverseStatsCounter = `-- name: IncrementUniverseStatsCounter :exec
+INSERT INTO universe_stats_counters (
+    event_type, num_events
+) VALUES (
+    $1, 1
+) ON CONFLICT (event_type)
+    DO UPDATE SET num_events = universe_stats_counters.num_events + 1
+`
+
+func (q *Queries) IncrementUniverseStatsCounter(ctx context.Context, eventType string) error {
+	_, err := q.db.ExecContext(ctx, incrementUniverseStatsCounter, eventType)
+	return err
+}
+
 const insertNewProofEvent = `-- name: InsertNewProofEvent :exec
 WITH group_key_root_id AS (
     SELECT id
@@ -250,7 +398,7 @@ func (q *Queries) InsertUniverseServer(ctx context.Context, arg InsertUniverseSe
 }
 
 const listUniverseServers = `-- name: ListUniverseServers :many
-SELECT id, server_host, last_sync_time FROM universe_servers
+SELECT id, server_host, last_sync_time, last_sync_error FROM universe_servers
 `
 
 func (q *Queries) ListUniverseServers(ctx context.Context) ([]UniverseServer, error) {
@@ -262,7 +410,12 @@ func (q *Queries) ListUniverseServers(ctx context.Context) ([]UniverseServer, er
 	var items []UniverseServer
 	for rows.Next() {
 		var i UniverseServer
-		if err := rows.Scan(&i.ID, &i.ServerHost, &i.LastSyncTime); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServerHost,
+			&i.LastSyncTime,
+			&i.LastSyncError,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -292,6 +445,22 @@ func (q *Queries) LogServerSync(ctx context.Context, arg LogServerSyncParams) er
 	return err
 }
 
+const logServerSyncError = `-- name: LogServerSyncError :exec
+UPDATE universe_servers
+SET last_sync_error = $1
+WHERE server_host = $2
+`
+
+type LogServerSyncErrorParams struct {
+	SyncError    sql.NullString
+	TargetServer string
+}
+
+func (q *Queries) LogServerSyncError(ctx context.Context, arg LogServerSyncErrorParams) error {
+	_, err := q.db.ExecContext(ctx, logServerSyncError, arg.SyncError, arg.TargetServer)
+	return err
+}
+
 const queryAssetStatsPerDayPostgres = `-- name: QueryAssetStatsPerDayPostgres :many
 SELECT
     to_char(to_timestamp(event_timestamp), 'YYYY-MM-DD') AS day,
@@ -384,6 +553,19 @@ func (q *Queries) QueryAssetStatsPerDaySqlite(ctx context.Context, arg QueryAsse
 	return items, nil
 }
 
+const queryFederationDefaultServer = `-- name: QueryFederationDefaultServer :one
+SELECT server_host
+FROM federation_default_server
+WHERE id = 1
+`
+
+func (q *Queries) QueryFederationDefaultServer(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, queryFederationDefaultServer)
+	var server_host string
+	err := row.Scan(&server_host)
+	return server_host, err
+}
+
 const queryFederationGlobalSyncConfigs = `-- name: QueryFederationGlobalSyncConfigs :many
 SELECT proof_type, allow_sync_insert, allow_sync_export
 FROM federation_global_sync_config
@@ -412,6 +594,70 @@ func (q *Queries) QueryFederationGlobalSyncConfigs(ctx context.Context) ([]Feder
 	return items, nil
 }
 
+const queryFederationPushQueue = `-- name: QueryFederationPushQueue :many
+SELECT queue.id, queue.server_id, servers.server_host, queue.asset_id,
+    queue.group_key, queue.proof_type, queue.leaf_outpoint,
+    queue.leaf_script_key, queue.num_attempts, queue.next_attempt_time,
+    queue.last_error, queue.creation_time
+FROM federation_push_queue queue
+JOIN universe_servers servers
+    ON queue.server_id = servers.id
+WHERE (queue.next_attempt_time <= $1 OR
+    $1 IS NULL)
+ORDER BY queue.next_attempt_time, queue.id
+`
+
+type QueryFederationPushQueueRow struct {
+	ID              int64
+	ServerID        int64
+	ServerHost      string
+	AssetID         []byte
+	GroupKey        []byte
+	ProofType       string
+	LeafOutpoint    []byte
+	LeafScriptKey   []byte
+	NumAttempts     int32
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
+func (q *Queries) QueryFederationPushQueue(ctx context.Context, dueBefore sql.NullTime) ([]QueryFederationPushQueueRow, error) {
+	rows, err := q.db.QueryContext(ctx, queryFederationPushQueue, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QueryFederationPushQueueRow
+	for rows.Next() {
+		var i QueryFederationPushQueueRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ServerID,
+			&i.ServerHost,
+			&i.AssetID,
+			&i.GroupKey,
+			&i.ProofType,
+			&i.LeafOutpoint,
+			&i.LeafScriptKey,
+			&i.NumAttempts,
+			&i.NextAttemptTime,
+			&i.LastError,
+			&i.CreationTime,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryFederationUniSyncConfigs = `-- name: QueryFederationUniSyncConfigs :many
 SELECT asset_id, group_key, proof_type, allow_sync_insert, allow_sync_export
 FROM federation_uni_sync_config
@@ -446,6 +692,27 @@ func (q *Queries) QueryFederationUniSyncConfigs(ctx context.Context) ([]Federati
 	return items, nil
 }
 
+const queryRecentUniverseEvents = `-- name: QueryRecentUniverseEvents :one
+SELECT
+    COUNT(CASE WHEN event_type = 'SYNC' THEN 1 ELSE NULL END) AS num_syncs,
+    COUNT(CASE WHEN event_type = 'NEW_PROOF' THEN 1 ELSE NULL END)
+        AS num_proofs
+FROM universe_events
+WHERE event_timestamp >= $1
+`
+
+type QueryRecentUniverseEventsRow struct {
+	NumSyncs  int64
+	NumProofs int64
+}
+
+func (q *Queries) QueryRecentUniverseEvents(ctx context.Context, sinceTimestamp int64) (QueryRecentUniverseEventsRow, error) {
+	row := q.db.QueryRowContext(ctx, queryRecentUniverseEvents, sinceTimestamp)
+	var i QueryRecentUniverseEventsRow
+	err := row.Scan(&i.NumSyncs, &i.NumProofs)
+	return i, err
+}
+
 const queryUniverseAssetStats = `-- name: QueryUniverseAssetStats :many
 
 WITH asset_supply AS (
@@ -659,8 +926,12 @@ func (q *Queries) QueryUniverseLeaves(ctx context.Context, arg QueryUniverseLeav
 
 const queryUniverseStats = `-- name: QueryUniverseStats :one
 WITH stats AS (
-    SELECT total_asset_syncs, total_asset_proofs
-    FROM universe_stats
+    SELECT
+        CASE WHEN event_type = 'SYNC' THEN num_events ELSE 0 END
+            AS total_asset_syncs,
+        CASE WHEN event_type = 'NEW_PROOF' THEN num_events ELSE 0 END
+            AS total_asset_proofs
+    FROM universe_stats_counters
 ), group_ids AS (
     SELECT id
     FROM universe_roots
@@ -756,7 +1027,12 @@ func (q *Queries) UniverseLeaves(ctx context.Context) ([]UniverseLeafe, error) {
 const universeRoots = `-- name: UniverseRoots :many
 SELECT universe_roots.asset_id, group_key, proof_type,
        mssmt_roots.root_hash root_hash, mssmt_nodes.sum root_sum,
-       genesis_assets.asset_tag asset_name
+       genesis_assets.asset_tag asset_name,
+       EXISTS (
+           SELECT 1
+           FROM asset_minting_batches batches
+           WHERE batches.genesis_id = genesis_assets.genesis_point_id
+       ) AS issuance_origin
 FROM universe_roots
 JOIN mssmt_roots
     ON universe_roots.namespace_root = mssmt_roots.namespace
@@ -768,12 +1044,13 @@ JOIN genesis_assets
 `
 
 type UniverseRootsRow struct {
-	AssetID   []byte
-	GroupKey  []byte
-	ProofType string
-	RootHash  []byte
-	RootSum   int64
-	AssetName string
+	AssetID        []byte
+	GroupKey       []byte
+	ProofType      string
+	RootHash       []byte
+	RootSum        int64
+	AssetName      string
+	IssuanceOrigin bool
 }
 
 func (q *Queries) UniverseRoots(ctx context.Context) ([]UniverseRootsRow, error) {
@@ -792,6 +1069,7 @@ func (q *Queries) UniverseRoots(ctx context.Context) ([]UniverseRootsRow, error)
 			&i.RootHash,
 			&i.RootSum,
 			&i.AssetName,
+			&i.IssuanceOrigin,
 		); err != nil {
 			return nil, err
 		}
@@ -806,6 +1084,43 @@ func (q *Queries) UniverseRoots(ctx context.Context) ([]UniverseRootsRow, error)
 	return items, nil
 }
 
+const updateFederationPushQueueEntry = `-- name: UpdateFederationPushQueueEntry :exec
+UPDATE federation_push_queue
+SET num_attempts = $1,
+    next_attempt_time = $2,
+    last_error = $3
+WHERE id = $4
+`
+
+type UpdateFederationPushQueueEntryParams struct {
+	NumAttempts     int32
+	NextAttemptTime time.Time
+	LastError       string
+	ID              int64
+}
+
+func (q *Queries) UpdateFederationPushQueueEntry(ctx context.Context, arg UpdateFederationPushQueueEntryParams) error {
+	_, err := q.db.ExecContext(ctx, updateFederationPushQueueEntry,
+		arg.NumAttempts,
+		arg.NextAttemptTime,
+		arg.LastError,
+		arg.ID,
+	)
+	return err
+}
+
+const upsertFederationDefaultServer = `-- name: UpsertFederationDefaultServer :exec
+INSERT INTO federation_default_server (id, server_host)
+VALUES (1, $1)
+ON CONFLICT(id)
+    DO UPDATE SET server_host = $1
+`
+
+func (q *Queries) UpsertFederationDefaultServer(ctx context.Context, serverHost string) error {
+	_, err := q.db.ExecContext(ctx, upsertFederationDefaultServer, serverHost)
+	return err
+}
+
 const upsertFederationGlobalSyncConfig = `-- name: UpsertFederationGlobalSyncConfig :exec
 INSERT INTO federation_global_sync_config (
     proof_type, allow_sync_insert, allow_sync_export
@@ -828,6 +1143,53 @@ func (q *Queries) UpsertFederationGlobalSyncConfig(ctx context.Context, arg Upse
 	return err
 }
 
+const upsertFederationPushQueueEntry = `-- name: UpsertFederationPushQueueEntry :exec
+INSERT INTO federation_push_queue (
+    server_id, asset_id, group_key, proof_type, leaf_outpoint,
+    leaf_script_key, num_attempts, next_attempt_time, last_error,
+    creation_time
+) VALUES (
+    $1, $2, $3, $4, $5,
+    $6, $7, $8, $9,
+    $10
+)
+ON CONFLICT (server_id, asset_id, proof_type, leaf_outpoint, leaf_script_key)
+    -- If the same proof is pushed again, we'll just update the retry
+    -- information but keep the original creation time.
+    DO UPDATE SET num_attempts = EXCLUDED.num_attempts,
+        next_attempt_time = EXCLUDED.next_attempt_time,
+        last_error = EXCLUDED.last_error
+`
+
+type UpsertFederationPushQueueEntryParams struct {
+	ServerID        int64
+	AssetID         []byte
+	GroupKey        []byte
+	ProofType       string
+	LeafOutpoint    []byte
+	LeafScriptKey   []byte
+	NumAttempts     int32
+	NextAttemptTime time.Time
+	LastError       string
+	CreationTime    time.Time
+}
+
+func (q *Queries) UpsertFederationPushQueueEntry(ctx context.Context, arg UpsertFederationPushQueueEntryParams) error {
+	_, err := q.db.ExecContext(ctx, upsertFederationPushQueueEntry,
+		arg.ServerID,
+		arg.AssetID,
+		arg.GroupKey,
+		arg.ProofType,
+		arg.LeafOutpoint,
+		arg.LeafScriptKey,
+		arg.NumAttempts,
+		arg.NextAttemptTime,
+		arg.LastError,
+		arg.CreationTime,
+	)
+	return err
+}
+
 const upsertFederationUniSyncConfig = `-- name: UpsertFederationUniSyncConfig :exec
 INSERT INTO federation_uni_sync_config  (
     asset_id, group_key, proof_type, allow_sync_insert, allow_sync_export
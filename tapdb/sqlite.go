@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,10 +33,42 @@ const (
 	// time.
 	defaultMaxConns = 25
 
+	// DefaultSqliteMaxConns is the default number of permitted active
+	// connections to a SQLite database. As SQLite only allows a single
+	// writer at a time, we keep this lower than for Postgres, so writers
+	// queue up in the connection pool instead of running into the busy
+	// timeout of the database.
+	DefaultSqliteMaxConns = 10
+
 	// connIdleLifetime is the amount of time a connection can be idle.
 	connIdleLifetime = 5 * time.Minute
+
+	// DefaultConnMaxLifetime is the default maximum amount of time a
+	// database connection may be reused.
+	DefaultConnMaxLifetime = connIdleLifetime
+)
+
+const (
+	// DefaultSqliteJournalMode is the default journaling mode of a SQLite
+	// database. In WAL mode, readers don't block writers and a writer
+	// doesn't block readers, which greatly reduces lock contention.
+	DefaultSqliteJournalMode = "WAL"
+
+	// DefaultSqliteBusyTimeout is the default amount of time a SQLite
+	// connection waits for a lock to be released before failing.
+	DefaultSqliteBusyTimeout = 5 * time.Second
 )
 
+// sqliteJournalModes is the set of journaling modes SQLite supports.
+var sqliteJournalModes = map[string]struct{}{
+	"DELETE":   {},
+	"TRUNCATE": {},
+	"PERSIST":  {},
+	"MEMORY":   {},
+	"WAL":      {},
+	"OFF":      {},
+}
+
 // SqliteConfig holds all the config arguments needed to interact with our
 // sqlite DB.
 type SqliteConfig struct {
@@ -45,6 +79,25 @@ type SqliteConfig struct {
 	// DatabaseFileName is the full file path where the database file can be
 	// found.
 	DatabaseFileName string `long:"dbfile" description:"The full path to the database."`
+
+	// MaxOpenConnections is the maximum number of open connections to the
+	// database.
+	MaxOpenConnections int `long:"maxconnections" description:"Max open connections to the database. Writers that exceed it wait for a free connection instead of running into the busy timeout of the database."`
+
+	// MaxIdleConnections is the maximum number of idle connections that
+	// are kept open.
+	MaxIdleConnections int `long:"maxidleconnections" description:"Max idle connections to keep open. Defaults to the number of max open connections."`
+
+	// ConnMaxLifetime is the maximum amount of time a connection may be
+	// reused.
+	ConnMaxLifetime time.Duration `long:"connmaxlifetime" description:"The maximum amount of time a connection may be reused."`
+
+	// JournalMode is the journaling mode of the database.
+	JournalMode string `long:"journalmode" description:"The journaling mode of the database. In WAL mode, reads don't block writes and vice versa. One of: DELETE, TRUNCATE, PERSIST, MEMORY, WAL, OFF."`
+
+	// BusyTimeout is the amount of time a connection waits for a lock to
+	// be released before failing.
+	BusyTimeout time.Duration `long:"busytimeout" description:"The maximum amount of time to wait for a database lock to be released before failing with a 'database is locked' error."`
 }
 
 // SqliteStore is a sqlite3 based database for the Taproot Asset daemon.
@@ -57,6 +110,20 @@ type SqliteStore struct {
 // NewSqliteStore attempts to open a new sqlite database based on the passed
 // config.
 func NewSqliteStore(cfg *SqliteConfig) (*SqliteStore, error) {
+	journalMode := DefaultSqliteJournalMode
+	if cfg.JournalMode != "" {
+		journalMode = strings.ToUpper(cfg.JournalMode)
+	}
+	if _, ok := sqliteJournalModes[journalMode]; !ok {
+		return nil, fmt.Errorf("unknown journal mode: %v",
+			cfg.JournalMode)
+	}
+
+	busyTimeout := DefaultSqliteBusyTimeout
+	if cfg.BusyTimeout > 0 {
+		busyTimeout = cfg.BusyTimeout
+	}
+
 	// The set of pragma options are accepted using query options. For now
 	// we only want to ensure that foreign key constraints are properly
 	// enforced.
@@ -70,11 +137,13 @@ func NewSqliteStore(cfg *SqliteConfig) (*SqliteStore, error) {
 		},
 		{
 			name:  "journal_mode",
-			value: "WAL",
+			value: journalMode,
 		},
 		{
-			name:  "busy_timeout",
-			value: "5000",
+			name: "busy_timeout",
+			value: strconv.FormatInt(
+				busyTimeout.Milliseconds(), 10,
+			),
 		},
 		{
 			// With the WAL mode, this ensures that we also do an
@@ -113,9 +182,10 @@ func NewSqliteStore(cfg *SqliteConfig) (*SqliteStore, error) {
 		return nil, err
 	}
 
-	db.SetMaxOpenConns(defaultMaxConns)
-	db.SetMaxIdleConns(defaultMaxConns)
-	db.SetConnMaxLifetime(connIdleLifetime)
+	configureConnPool(
+		db, cfg.MaxOpenConnections, cfg.MaxIdleConnections,
+		cfg.ConnMaxLifetime, DefaultSqliteMaxConns,
+	)
 
 	if !cfg.SkipMigrations {
 		// Now that the database is open, populate the database with
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +19,10 @@ var (
 	// MaxValidSQLTime is the maximum valid time that can be rendered as a
 	// time string and can be used for comparisons in SQL.
 	MaxValidSQLTime = time.Date(9999, 12, 31, 23, 59, 59, 999999, time.UTC)
+
+	// likeEscaper escapes the characters that have a special meaning
+	// within a LIKE pattern.
+	likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
 )
 
 // sqlInt64 turns a numerical integer type into the NullInt64 that sql/sqlc
@@ -78,6 +83,26 @@ func sqlStr(s string) sql.NullString {
 	}
 }
 
+// sqlLikeSubstr turns a substring into the NullString that matches it
+// literally when used within a LIKE pattern, by escaping the wildcard and
+// escape characters with a backslash. The empty string is mapped to NULL.
+func sqlLikeSubstr(s string) sql.NullString {
+	return sqlStr(likeEscaper.Replace(s))
+}
+
+// sqlTime turns a time into the NullTime that sql/sqlc uses when a timestamp
+// can be permitted to be NULL. The zero time is mapped to NULL.
+func sqlTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  t.UTC(),
+		Valid: true,
+	}
+}
+
 // extractSqlInt64 turns a NullInt64 into a numerical type. This can be useful
 // when reading directly from the database, as this function handles extracting
 // the inner value from the "option"-like struct.
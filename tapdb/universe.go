@@ -38,6 +38,17 @@ type (
 
 	// UniverseLeaf is a universe leaf.
 	UniverseLeaf = sqlc.QueryUniverseLeavesRow
+
+	// DeleteUniverseLeafKey is used to delete a single universe leaf.
+	DeleteUniverseLeafKey = sqlc.DeleteUniverseLeafParams
+
+	// UniverseProofEvents is used to delete the oldest proof events of a
+	// universe.
+	UniverseProofEvents = sqlc.DeleteUniverseProofEventsParams
+
+	// UniverseStatsCounterDelta is used to decrement a single universe
+	// stats counter.
+	UniverseStatsCounterDelta = sqlc.DecrementUniverseStatsCounterParams
 )
 
 // BaseUniverseStore is the main interface for the Taproot Asset universe store.
@@ -62,12 +73,33 @@ type BaseUniverseStore interface {
 	// universe tree.
 	DeleteUniverseLeaves(ctx context.Context, namespace string) error
 
+	// DeleteUniverseLeaf is used to delete a single leaf that resides in
+	// a universe tree.
+	DeleteUniverseLeaf(ctx context.Context,
+		arg DeleteUniverseLeafKey) error
+
 	// DeleteUniverseRoot is used to delete the root of a universe tree.
 	DeleteUniverseRoot(ctx context.Context, namespace string) error
 
 	// DeleteUniverseEvents is used to delete a universe sync event.
 	DeleteUniverseEvents(ctx context.Context, namespace string) error
 
+	// DecrementUniverseStatsCounters subtracts the events of the given
+	// universe from the running universe stats counters.
+	DecrementUniverseStatsCounters(ctx context.Context,
+		namespace string) error
+
+	// DeleteUniverseProofEvents deletes up to the given number of the
+	// oldest proof events of a universe, returning the number of deleted
+	// events.
+	DeleteUniverseProofEvents(ctx context.Context,
+		arg UniverseProofEvents) (int64, error)
+
+	// DecrementUniverseStatsCounter subtracts the given number of events
+	// from the running stats counter of a single event type.
+	DecrementUniverseStatsCounter(ctx context.Context,
+		arg UniverseStatsCounterDelta) error
+
 	// FetchUniverseRoot fetches the root of a universe based on the
 	// namespace key, which is a function of the asset ID and the group
 	// key.
@@ -610,13 +642,15 @@ func universeFetchProofLeaf(ctx context.Context,
 	return proofs, nil
 }
 
-// MintingKeys returns all the keys inserted in the universe.
+// MintingKeys returns all the keys inserted in the universe. If the context
+// allows it, the query may be served by a read replica, so recently inserted
+// keys might be missing.
 func (b *BaseUniverseTree) MintingKeys(ctx context.Context,
 ) ([]universe.LeafKey, error) {
 
 	var leafKeys []universe.LeafKey
 
-	readTx := NewBaseUniverseReadTx()
+	readTx := NewContextReadTx(ctx)
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
 		universeKeys, err := db.FetchUniverseKeys(ctx, b.smtNamespace)
 		if err != nil {
@@ -656,13 +690,15 @@ func (b *BaseUniverseTree) MintingKeys(ctx context.Context,
 	return leafKeys, nil
 }
 
-// MintingLeaves returns all the minting leaves inserted into the universe.
+// MintingLeaves returns all the minting leaves inserted into the universe. If
+// the context allows it, the query may be served by a read replica, so recently
+// inserted leaves might be missing.
 func (b *BaseUniverseTree) MintingLeaves(
 	ctx context.Context) ([]universe.Leaf, error) {
 
 	var leaves []universe.Leaf
 
-	readTx := NewBaseUniverseReadTx()
+	readTx := NewContextReadTx(ctx)
 	dbErr := b.db.ExecTx(ctx, &readTx, func(db BaseUniverseStore) error {
 		// First, we'll query the set of Universe leaves we have
 		// directly to determine which ones we care about. We only
@@ -728,51 +764,70 @@ func (b *BaseUniverseTree) DeleteUniverse(ctx context.Context) (string, error) {
 	var writeTx BaseUniverseStoreOptions
 
 	dbErr := b.db.ExecTx(ctx, &writeTx, func(db BaseUniverseStore) error {
-		// Instantiate a compact tree so we can delete the MS-SMT
-		// backing the universe.
-		universeTree := mssmt.NewCompactedTree(
-			newTreeStoreWrapperTx(db, b.smtNamespace),
-		)
+		return deleteUniverseTree(ctx, db, b.smtNamespace)
+	})
 
-		// Delete all MS-SMT nodes backing the universe tree.
-		err := universeTree.DeleteAllNodes(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to delete universe MS-SMT"+
-				"nodes: %w", err)
-		}
+	return b.smtNamespace, dbErr
+}
 
-		// Delete all leaves in the universe table.
-		err = db.DeleteUniverseLeaves(ctx, b.smtNamespace)
-		if err != nil {
-			return fmt.Errorf("failed to delete universe leaves: %w",
-				err)
-		}
+// deleteUniverseTree deletes all leaves, MS-SMT nodes, events and the root of
+// the universe tree with the given namespace.
+//
+// NOTE: This function accepts a db transaction, as it's used when making
+// broader DB updates.
+func deleteUniverseTree(ctx context.Context, db BaseUniverseStore,
+	namespace string) error {
 
-		// Delete the root node of the MS-SMT backing the universe.
-		err = universeTree.DeleteRoot(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to delete universe MS-SMT"+
-				"tree root: %w", err)
-		}
+	// Instantiate a compact tree so we can delete the MS-SMT backing the
+	// universe.
+	universeTree := mssmt.NewCompactedTree(
+		newTreeStoreWrapperTx(db, namespace),
+	)
 
-		// Delete any events related to this universe.
-		err = db.DeleteUniverseEvents(ctx, b.smtNamespace)
-		if err != nil {
-			return fmt.Errorf("failed to delete universe events: "+
-				"%w", err)
-		}
+	// Delete all MS-SMT nodes backing the universe tree.
+	err := universeTree.DeleteAllNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete universe MS-SMT"+
+			"nodes: %w", err)
+	}
 
-		// Delete the universe root from the universe table.
-		err = db.DeleteUniverseRoot(ctx, b.smtNamespace)
-		if err != nil {
-			return fmt.Errorf("failed to delete universe root: %w",
-				err)
-		}
+	// Delete all leaves in the universe table.
+	err = db.DeleteUniverseLeaves(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to delete universe leaves: %w",
+			err)
+	}
 
-		return nil
-	})
+	// Delete the root node of the MS-SMT backing the universe.
+	err = universeTree.DeleteRoot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete universe MS-SMT"+
+			"tree root: %w", err)
+	}
 
-	return b.smtNamespace, dbErr
+	// Remove the events of this universe from the running stats
+	// counters before the events themselves are deleted.
+	err = db.DecrementUniverseStatsCounters(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to update universe stats "+
+			"counters: %w", err)
+	}
+
+	// Delete any events related to this universe.
+	err = db.DeleteUniverseEvents(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to delete universe events: "+
+			"%w", err)
+	}
+
+	// Delete the universe root from the universe table.
+	err = db.DeleteUniverseRoot(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to delete universe root: %w",
+			err)
+	}
+
+	return nil
 }
 
 var _ universe.BaseBackend = (*BaseUniverseTree)(nil)
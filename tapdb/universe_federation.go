@@ -1,13 +1,16 @@
 package tapdb
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/fn"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
@@ -37,6 +40,34 @@ type (
 	// FedUniSyncConfigs is the universe specific federation sync config
 	// returned from a query.
 	FedUniSyncConfigs = sqlc.FederationUniSyncConfig
+
+	// NewFedPushQueueEntry is used to queue a failed proof push for retry.
+	NewFedPushQueueEntry = sqlc.UpsertFederationPushQueueEntryParams
+
+	// FedPushQueueUpdate is used to update the retry information of a
+	// queued proof push.
+	FedPushQueueUpdate = sqlc.UpdateFederationPushQueueEntryParams
+
+	// FedPushQueueEntryKey identifies a queued proof push.
+	FedPushQueueEntryKey = sqlc.FederationPushQueueEntryExistsParams
+
+	// FedPushQueueEntry is a queued proof push returned from a query.
+	FedPushQueueEntry = sqlc.QueryFederationPushQueueRow
+
+	// NewRegPushQueueEntry is used to queue a failed registration push for
+	// retry.
+	NewRegPushQueueEntry = sqlc.UpsertRegistrationPushQueueEntryParams
+
+	// RegPushQueueUpdate is used to update the retry information of a
+	// queued registration push.
+	RegPushQueueUpdate = sqlc.UpdateRegistrationPushQueueEntryParams
+
+	// RegPushQueueEntryKey identifies a queued registration push.
+	RegPushQueueEntryKey = sqlc.RegistrationPushQueueEntryExistsParams
+
+	// RegPushQueueEntry is a queued registration push returned from a
+	// query.
+	RegPushQueueEntry = sqlc.RegistrationPushQueue
 )
 
 var (
@@ -94,8 +125,80 @@ type UniverseServerStore interface {
 	// LogServerSync marks that a server was just synced in the DB.
 	LogServerSync(ctx context.Context, arg sqlc.LogServerSyncParams) error
 
+	// LogServerSyncError sets or clears the error of the last sync with a
+	// server.
+	LogServerSyncError(ctx context.Context,
+		arg sqlc.LogServerSyncErrorParams) error
+
 	// ListUniverseServers returns the total set of all universe servers.
 	ListUniverseServers(ctx context.Context) ([]sqlc.UniverseServer, error)
+
+	// UpsertFederationDefaultServer sets the host of the default Universe
+	// server.
+	UpsertFederationDefaultServer(ctx context.Context,
+		serverHost string) error
+
+	// QueryFederationDefaultServer returns the host of the default
+	// Universe server.
+	QueryFederationDefaultServer(ctx context.Context) (string, error)
+
+	// UpsertFederationPushQueueEntry queues a failed proof push for
+	// retry, or updates the retry information of an already queued push.
+	UpsertFederationPushQueueEntry(ctx context.Context,
+		arg NewFedPushQueueEntry) error
+
+	// CountFederationPushQueueEntries returns the number of queued pushes
+	// for the given server.
+	CountFederationPushQueueEntries(ctx context.Context,
+		serverID int64) (int64, error)
+
+	// FederationPushQueueEntryExists returns true if a push of the same
+	// proof leaf to the same server is already queued.
+	FederationPushQueueEntryExists(ctx context.Context,
+		arg FedPushQueueEntryKey) (bool, error)
+
+	// QueryFederationPushQueue returns the queued pushes that are due
+	// before the given time, or all of them if the time isn't set.
+	QueryFederationPushQueue(ctx context.Context,
+		dueBefore sql.NullTime) ([]FedPushQueueEntry, error)
+
+	// UpdateFederationPushQueueEntry updates the retry information of a
+	// queued push.
+	UpdateFederationPushQueueEntry(ctx context.Context,
+		arg FedPushQueueUpdate) error
+
+	// DeleteFederationPushQueueEntry removes a queued push.
+	DeleteFederationPushQueueEntry(ctx context.Context, id int64) error
+
+	// UpsertRegistrationPushQueueEntry queues a failed registration push
+	// for retry, or updates the retry information of an already queued
+	// push.
+	UpsertRegistrationPushQueueEntry(ctx context.Context,
+		arg NewRegPushQueueEntry) error
+
+	// CountRegistrationPushQueueEntries returns the number of queued
+	// registration pushes for the given server host.
+	CountRegistrationPushQueueEntries(ctx context.Context,
+		serverHost string) (int64, error)
+
+	// RegistrationPushQueueEntryExists returns true if a registration push
+	// of the same proof leaf to the same server is already queued.
+	RegistrationPushQueueEntryExists(ctx context.Context,
+		arg RegPushQueueEntryKey) (bool, error)
+
+	// QueryRegistrationPushQueue returns the queued registration pushes
+	// that are due before the given time, or all of them if the time isn't
+	// set.
+	QueryRegistrationPushQueue(ctx context.Context,
+		dueBefore sql.NullTime) ([]RegPushQueueEntry, error)
+
+	// UpdateRegistrationPushQueueEntry updates the retry information of a
+	// queued registration push.
+	UpdateRegistrationPushQueueEntry(ctx context.Context,
+		arg RegPushQueueUpdate) error
+
+	// DeleteRegistrationPushQueueEntry removes a queued registration push.
+	DeleteRegistrationPushQueueEntry(ctx context.Context, id int64) error
 }
 
 // UniverseFederationOptions is the database tx object for the universe server store.
@@ -237,6 +340,116 @@ func (u *UniverseFederationDB) LogNewSyncs(ctx context.Context,
 	})
 }
 
+// LastSyncTimes returns the time of the last successful sync with each server
+// in the federation, keyed by the host string of the server. For servers we
+// never synced with, this is the time they were added to the federation.
+func (u *UniverseFederationDB) LastSyncTimes(
+	ctx context.Context) (map[string]time.Time, error) {
+
+	syncTimes := make(map[string]time.Time)
+
+	readTx := NewUniverseFederationReadTx()
+	dbErr := u.db.ExecTx(ctx, &readTx, func(db UniverseServerStore) error {
+		servers, err := db.ListUniverseServers(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range servers {
+			syncTimes[s.ServerHost] = s.LastSyncTime.UTC()
+		}
+
+		return nil
+	})
+
+	return syncTimes, dbErr
+}
+
+// LogSyncError logs the error of the last sync attempt with a server. A nil
+// error clears the logged error after a successful sync.
+func (u *UniverseFederationDB) LogSyncError(ctx context.Context,
+	addr universe.ServerAddr, syncErr error) error {
+
+	var errStr sql.NullString
+	if syncErr != nil {
+		errStr = sqlStr(syncErr.Error())
+	}
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		return db.LogServerSyncError(ctx, sqlc.LogServerSyncErrorParams{
+			SyncError:    errStr,
+			TargetServer: addr.HostStr(),
+		})
+	})
+}
+
+// LastSyncErrors returns the error of the last sync attempt with each server
+// that failed to sync, keyed by the host string of the server. Servers whose
+// last sync succeeded aren't part of the returned map.
+func (u *UniverseFederationDB) LastSyncErrors(
+	ctx context.Context) (map[string]string, error) {
+
+	syncErrors := make(map[string]string)
+
+	readTx := NewUniverseFederationReadTx()
+	dbErr := u.db.ExecTx(ctx, &readTx, func(db UniverseServerStore) error {
+		servers, err := db.ListUniverseServers(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range servers {
+			if !s.LastSyncError.Valid {
+				continue
+			}
+
+			syncErrors[s.ServerHost] = s.LastSyncError.String
+		}
+
+		return nil
+	})
+
+	return syncErrors, dbErr
+}
+
+// SetDefaultServer persists the host of the Universe server that is used as
+// the sync target if none is specified. An empty host records that the default
+// was cleared.
+func (u *UniverseFederationDB) SetDefaultServer(ctx context.Context,
+	host string) error {
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		return db.UpsertFederationDefaultServer(ctx, host)
+	})
+}
+
+// DefaultServer returns the host of the Universe server that was last set as
+// the default sync target. An empty host means the default was cleared.
+// universe.ErrNoDefaultServer is returned if the default was never set.
+func (u *UniverseFederationDB) DefaultServer(
+	ctx context.Context) (string, error) {
+
+	var host string
+
+	readTx := NewUniverseFederationReadTx()
+	dbErr := u.db.ExecTx(ctx, &readTx, func(db UniverseServerStore) error {
+		var err error
+		host, err = db.QueryFederationDefaultServer(ctx)
+		return err
+	})
+	switch {
+	case errors.Is(dbErr, sql.ErrNoRows):
+		return "", universe.ErrNoDefaultServer
+
+	case dbErr != nil:
+		return "", dbErr
+	}
+
+	return host, nil
+}
+
 // UpsertFederationSyncConfig upserts both the global and universe specific
 // federation sync configs.
 func (u *UniverseFederationDB) UpsertFederationSyncConfig(
@@ -408,8 +621,296 @@ func (u *UniverseFederationDB) QueryFederationSyncConfigs(
 	return globalConfigs, uniConfigs, nil
 }
 
+// QueuePendingPush adds the given push to the retry queue of its target
+// server. If the push is already queued, only its retry information is
+// updated. Registration pushes are queued by the host of their target server,
+// as the server isn't necessarily a federation member.
+func (u *UniverseFederationDB) QueuePendingPush(ctx context.Context,
+	push *universe.PendingPush, maxQueueSize int) error {
+
+	uniID := push.UniverseID
+
+	var groupKey []byte
+	if uniID.GroupKey != nil {
+		groupKey = uniID.GroupKey.SerializeCompressed()
+	}
+
+	outpoint, err := encodeOutpoint(push.LeafKey.OutPoint)
+	if err != nil {
+		return err
+	}
+
+	if push.LeafKey.ScriptKey == nil {
+		return fmt.Errorf("leaf key script key must be set")
+	}
+	scriptKey := schnorr.SerializePubKey(push.LeafKey.ScriptKey.PubKey)
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		if push.Registration {
+			return queueRegistrationPush(
+				ctx, db, push, groupKey, outpoint, scriptKey,
+				maxQueueSize, u.clock.Now().UTC(),
+			)
+		}
+
+		// An update of the retry information of an already queued push
+		// doesn't grow the queue, so the limit only applies to new
+		// entries.
+		queued, err := db.FederationPushQueueEntryExists(
+			ctx, FedPushQueueEntryKey{
+				ServerID:      push.Server.ID,
+				AssetID:       uniID.AssetID[:],
+				ProofType:     uniID.ProofType.String(),
+				LeafOutpoint:  outpoint,
+				LeafScriptKey: scriptKey,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		numQueued, err := db.CountFederationPushQueueEntries(
+			ctx, push.Server.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !queued && numQueued >= int64(maxQueueSize) {
+			return universe.ErrPushQueueFull
+		}
+
+		return db.UpsertFederationPushQueueEntry(
+			ctx, NewFedPushQueueEntry{
+				ServerID:        push.Server.ID,
+				AssetID:         uniID.AssetID[:],
+				GroupKey:        groupKey,
+				ProofType:       uniID.ProofType.String(),
+				LeafOutpoint:    outpoint,
+				LeafScriptKey:   scriptKey,
+				NumAttempts:     int32(push.NumAttempts),
+				NextAttemptTime: push.NextAttempt.UTC(),
+				LastError:       push.LastError,
+				CreationTime:    u.clock.Now().UTC(),
+			},
+		)
+	})
+}
+
+// queueRegistrationPush adds the given registration push to the retry queue
+// of the host of its target server.
+func queueRegistrationPush(ctx context.Context, db UniverseServerStore,
+	push *universe.PendingPush, groupKey, outpoint, scriptKey []byte,
+	maxQueueSize int, now time.Time) error {
+
+	uniID := push.UniverseID
+	serverHost := push.Server.HostStr()
+
+	queued, err := db.RegistrationPushQueueEntryExists(
+		ctx, RegPushQueueEntryKey{
+			ServerHost:    serverHost,
+			AssetID:       uniID.AssetID[:],
+			ProofType:     uniID.ProofType.String(),
+			LeafOutpoint:  outpoint,
+			LeafScriptKey: scriptKey,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	numQueued, err := db.CountRegistrationPushQueueEntries(ctx, serverHost)
+	if err != nil {
+		return err
+	}
+
+	if !queued && numQueued >= int64(maxQueueSize) {
+		return universe.ErrPushQueueFull
+	}
+
+	return db.UpsertRegistrationPushQueueEntry(ctx, NewRegPushQueueEntry{
+		ServerHost:      serverHost,
+		AssetID:         uniID.AssetID[:],
+		GroupKey:        groupKey,
+		ProofType:       uniID.ProofType.String(),
+		LeafOutpoint:    outpoint,
+		LeafScriptKey:   scriptKey,
+		NumAttempts:     int32(push.NumAttempts),
+		NextAttemptTime: push.NextAttempt.UTC(),
+		LastError:       push.LastError,
+		CreationTime:    now,
+	})
+}
+
+// PendingPushes returns the queued pushes that are due to be retried at the
+// given time, including the registration pushes. If the time is zero, all
+// queued pushes are returned.
+func (u *UniverseFederationDB) PendingPushes(ctx context.Context,
+	dueBefore time.Time) ([]*universe.PendingPush, error) {
+
+	var pushes []*universe.PendingPush
+
+	readTx := NewUniverseFederationReadTx()
+	dbErr := u.db.ExecTx(ctx, &readTx, func(db UniverseServerStore) error {
+		due := sql.NullTime{
+			Time:  dueBefore.UTC(),
+			Valid: !dueBefore.IsZero(),
+		}
+		dbPushes, err := db.QueryFederationPushQueue(ctx, due)
+		if err != nil {
+			return err
+		}
+		dbRegPushes, err := db.QueryRegistrationPushQueue(ctx, due)
+		if err != nil {
+			return err
+		}
+
+		pushes = make(
+			[]*universe.PendingPush, 0,
+			len(dbPushes)+len(dbRegPushes),
+		)
+		for _, dbPush := range dbPushes {
+			push, err := parsePendingPush(dbPush)
+			if err != nil {
+				return err
+			}
+
+			pushes = append(pushes, push)
+		}
+
+		// A registration push has the same columns as a federation
+		// push, except that its server is only known by its host.
+		for _, dbRegPush := range dbRegPushes {
+			push, err := parsePendingPush(FedPushQueueEntry{
+				ID:              dbRegPush.ID,
+				ServerHost:      dbRegPush.ServerHost,
+				AssetID:         dbRegPush.AssetID,
+				GroupKey:        dbRegPush.GroupKey,
+				ProofType:       dbRegPush.ProofType,
+				LeafOutpoint:    dbRegPush.LeafOutpoint,
+				LeafScriptKey:   dbRegPush.LeafScriptKey,
+				NumAttempts:     dbRegPush.NumAttempts,
+				NextAttemptTime: dbRegPush.NextAttemptTime,
+				LastError:       dbRegPush.LastError,
+				CreationTime:    dbRegPush.CreationTime,
+			})
+			if err != nil {
+				return err
+			}
+			push.Registration = true
+
+			pushes = append(pushes, push)
+		}
+
+		return nil
+	})
+	if dbErr != nil {
+		return nil, dbErr
+	}
+
+	// Both queues are ordered by their next attempt, so we keep that
+	// order for the combined list.
+	sort.SliceStable(pushes, func(i, j int) bool {
+		return pushes[i].NextAttempt.Before(pushes[j].NextAttempt)
+	})
+
+	return pushes, nil
+}
+
+// parsePendingPush parses a queued push from its database representation.
+func parsePendingPush(dbPush FedPushQueueEntry) (*universe.PendingPush,
+	error) {
+
+	proofType, err := universe.ParseStrProofType(dbPush.ProofType)
+	if err != nil {
+		return nil, err
+	}
+
+	uniID := universe.Identifier{
+		ProofType: proofType,
+	}
+	copy(uniID.AssetID[:], dbPush.AssetID)
+
+	if dbPush.GroupKey != nil {
+		uniID.GroupKey, err = btcec.ParsePubKey(dbPush.GroupKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse group key: %w",
+				err)
+		}
+	}
+
+	var leafKey universe.LeafKey
+	err = readOutPoint(
+		bytes.NewReader(dbPush.LeafOutpoint), 0, 0, &leafKey.OutPoint,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read outpoint: %w", err)
+	}
+
+	scriptPub, err := schnorr.ParsePubKey(dbPush.LeafScriptKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse script key: %w", err)
+	}
+	scriptKey := asset.NewScriptKey(scriptPub)
+	leafKey.ScriptKey = &scriptKey
+
+	return &universe.PendingPush{
+		ID: dbPush.ID,
+		Server: universe.NewServerAddr(
+			dbPush.ServerID, dbPush.ServerHost,
+		),
+		UniverseID:   uniID,
+		LeafKey:      leafKey,
+		NumAttempts:  uint32(dbPush.NumAttempts),
+		NextAttempt:  dbPush.NextAttemptTime.UTC(),
+		LastError:    dbPush.LastError,
+		CreationTime: dbPush.CreationTime.UTC(),
+	}, nil
+}
+
+// UpdatePendingPush updates the retry information of the given queued push.
+func (u *UniverseFederationDB) UpdatePendingPush(ctx context.Context,
+	push *universe.PendingPush) error {
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		if push.Registration {
+			update := RegPushQueueUpdate{
+				ID:              push.ID,
+				NumAttempts:     int32(push.NumAttempts),
+				NextAttemptTime: push.NextAttempt.UTC(),
+				LastError:       push.LastError,
+			}
+			return db.UpdateRegistrationPushQueueEntry(ctx, update)
+		}
+
+		return db.UpdateFederationPushQueueEntry(ctx, FedPushQueueUpdate{
+			ID:              push.ID,
+			NumAttempts:     int32(push.NumAttempts),
+			NextAttemptTime: push.NextAttempt.UTC(),
+			LastError:       push.LastError,
+		})
+	})
+}
+
+// RemovePendingPush removes the given queued push.
+func (u *UniverseFederationDB) RemovePendingPush(ctx context.Context,
+	push *universe.PendingPush) error {
+
+	var writeTx UniverseFederationOptions
+	return u.db.ExecTx(ctx, &writeTx, func(db UniverseServerStore) error {
+		if push.Registration {
+			return db.DeleteRegistrationPushQueueEntry(ctx, push.ID)
+		}
+
+		return db.DeleteFederationPushQueueEntry(ctx, push.ID)
+	})
+}
+
 // Check at compile time that we implement the correct interfaces.
 var (
 	_ universe.FederationLog          = (*UniverseFederationDB)(nil)
 	_ universe.FederationSyncConfigDB = (*UniverseFederationDB)(nil)
+	_ universe.FederationPushLog      = (*UniverseFederationDB)(nil)
 )
@@ -94,4 +94,290 @@ func TestUniverseFederationCRUD(t *testing.T) {
 	addrToUpdate := addrs[rand.Int()%len(dbAddrs)]
 	err = fedDB.LogNewSyncs(ctx, addrToUpdate)
 	require.NoError(t, err)
+
+	// The last sync time of a server we just synced with should reflect
+	// the current time.
+	testClock.SetTime(testClock.Now().Add(time.Hour))
+	syncedAddr := dbAddrs[0]
+	err = fedDB.LogNewSyncs(ctx, syncedAddr)
+	require.NoError(t, err)
+
+	syncTimes, err := fedDB.LastSyncTimes(ctx)
+	require.NoError(t, err)
+	require.Len(t, syncTimes, len(dbAddrs))
+	require.Equal(
+		t, testClock.Now().UTC().Unix(),
+		syncTimes[syncedAddr.HostStr()].Unix(),
+	)
+
+	// The error of a failed sync is logged for the server, and cleared
+	// again once a sync with the server succeeds.
+	syncErrors, err := fedDB.LastSyncErrors(ctx)
+	require.NoError(t, err)
+	require.Empty(t, syncErrors)
+
+	err = fedDB.LogSyncError(ctx, syncedAddr, fmt.Errorf("sync failed"))
+	require.NoError(t, err)
+
+	syncErrors, err = fedDB.LastSyncErrors(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		syncedAddr.HostStr(): "sync failed",
+	}, syncErrors)
+
+	err = fedDB.LogSyncError(ctx, syncedAddr, nil)
+	require.NoError(t, err)
+
+	syncErrors, err = fedDB.LastSyncErrors(ctx)
+	require.NoError(t, err)
+	require.Empty(t, syncErrors)
+}
+
+// TestFederationDefaultServer tests that the default Universe server set at
+// runtime is persisted, including the fact that it was cleared.
+func TestFederationDefaultServer(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	fedDB, _ := newTestFederationDb(t, testClock)
+
+	ctx := context.Background()
+
+	// As long as the default was never set, we get a distinct error.
+	_, err := fedDB.DefaultServer(ctx)
+	require.ErrorIs(t, err, universe.ErrNoDefaultServer)
+
+	// Setting the default twice overwrites the first one.
+	require.NoError(t, fedDB.SetDefaultServer(ctx, "localhost:10001"))
+	require.NoError(t, fedDB.SetDefaultServer(ctx, "localhost:10002"))
+
+	host, err := fedDB.DefaultServer(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:10002", host)
+
+	// A cleared default is returned as an empty host.
+	require.NoError(t, fedDB.SetDefaultServer(ctx, ""))
+
+	host, err = fedDB.DefaultServer(ctx)
+	require.NoError(t, err)
+	require.Empty(t, host)
+}
+
+// TestFederationPushQueue tests that failed proof pushes can be queued,
+// updated and removed, and that the queue of each server is bounded.
+func TestFederationPushQueue(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	fedDB, _ := newTestFederationDb(t, testClock)
+
+	ctx := context.Background()
+
+	// We'll start by adding two servers we can queue pushes for.
+	addrs := []universe.ServerAddr{
+		universe.NewServerAddr(1, "localhost:10001"),
+		universe.NewServerAddr(2, "localhost:10002"),
+	}
+	require.NoError(t, fedDB.AddServers(ctx, addrs...))
+
+	const maxQueueSize = 2
+	now := testClock.Now()
+	newPush := func(addr universe.ServerAddr,
+		nextAttempt time.Time) *universe.PendingPush {
+
+		return &universe.PendingPush{
+			Server:      addr,
+			UniverseID:  randUniverseID(t, false),
+			LeafKey:     randLeafKey(t),
+			NumAttempts: 1,
+			NextAttempt: nextAttempt,
+			LastError:   "connection refused",
+		}
+	}
+
+	// We'll queue one push that is due now and one that is due later for
+	// the first server.
+	duePush := newPush(addrs[0], now)
+	laterPush := newPush(addrs[0], now.Add(time.Hour))
+	require.NoError(t, fedDB.QueuePendingPush(ctx, duePush, maxQueueSize))
+	require.NoError(t, fedDB.QueuePendingPush(ctx, laterPush, maxQueueSize))
+
+	// The queue of the first server is now full, but the second server
+	// can still queue pushes.
+	err := fedDB.QueuePendingPush(
+		ctx, newPush(addrs[0], now), maxQueueSize,
+	)
+	require.ErrorIs(t, err, universe.ErrPushQueueFull)
+
+	// Queueing a push that is already queued only updates its retry
+	// information, so it's accepted even if the queue is full.
+	laterPush.NumAttempts = 2
+	require.NoError(t, fedDB.QueuePendingPush(ctx, laterPush, maxQueueSize))
+
+	otherPush := newPush(addrs[1], now)
+	require.NoError(t, fedDB.QueuePendingPush(ctx, otherPush, maxQueueSize))
+
+	// Only the pushes that are due now should be returned when we query
+	// for the current time.
+	duePushes, err := fedDB.PendingPushes(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, duePushes, 2)
+
+	assertPush := func(expected, actual *universe.PendingPush) {
+		t.Helper()
+
+		require.Equal(t, expected.Server.ID, actual.Server.ID)
+		require.Equal(
+			t, expected.Server.HostStr(), actual.Server.HostStr(),
+		)
+		require.Equal(
+			t, expected.UniverseID.String(),
+			actual.UniverseID.String(),
+		)
+		require.Equal(
+			t, expected.LeafKey.UniverseKey(),
+			actual.LeafKey.UniverseKey(),
+		)
+		require.Equal(t, expected.NumAttempts, actual.NumAttempts)
+		require.Equal(
+			t, expected.NextAttempt.Unix(),
+			actual.NextAttempt.Unix(),
+		)
+		require.Equal(t, expected.LastError, actual.LastError)
+	}
+	assertPush(duePush, duePushes[0])
+	assertPush(otherPush, duePushes[1])
+
+	// A zero time returns all pushes.
+	allPushes, err := fedDB.PendingPushes(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, allPushes, 3)
+
+	// We'll now update the retry information of the due push, which should
+	// no longer be due afterward.
+	updatedPush := duePushes[0]
+	updatedPush.NumAttempts = 2
+	updatedPush.NextAttempt = now.Add(2 * time.Hour)
+	updatedPush.LastError = "timeout"
+	require.NoError(t, fedDB.UpdatePendingPush(ctx, updatedPush))
+
+	duePushes, err = fedDB.PendingPushes(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, duePushes, 1)
+	assertPush(otherPush, duePushes[0])
+
+	allPushes, err = fedDB.PendingPushes(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, allPushes, 3)
+	assertPush(updatedPush, allPushes[2])
+
+	// Removing a push frees up space in the queue of its server.
+	require.NoError(t, fedDB.RemovePendingPush(ctx, updatedPush))
+	require.NoError(t, fedDB.QueuePendingPush(
+		ctx, newPush(addrs[0], now), maxQueueSize,
+	))
+
+	// Finally, removing a server also removes its queued pushes.
+	require.NoError(t, fedDB.RemoveServers(ctx, addrs[0]))
+	allPushes, err = fedDB.PendingPushes(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, allPushes, 1)
+	assertPush(otherPush, allPushes[0])
+}
+
+// TestRegistrationPushQueue tests that failed registration pushes are queued
+// for servers that aren't federation members, and that they're returned
+// together with the federation pushes.
+func TestRegistrationPushQueue(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Now())
+	fedDB, _ := newTestFederationDb(t, testClock)
+
+	ctx := context.Background()
+
+	member := universe.NewServerAddr(1, "localhost:10001")
+	require.NoError(t, fedDB.AddServers(ctx, member))
+
+	const maxQueueSize = 1
+	now := testClock.Now()
+	regServer := universe.NewServerAddrFromStr("localhost:10002")
+	regPush := &universe.PendingPush{
+		Server:       regServer,
+		Registration: true,
+		UniverseID:   randUniverseID(t, false),
+		LeafKey:      randLeafKey(t),
+		NumAttempts:  1,
+		NextAttempt:  now.Add(time.Minute),
+		LastError:    "connection refused",
+	}
+	require.NoError(t, fedDB.QueuePendingPush(ctx, regPush, maxQueueSize))
+
+	// The registration server doesn't become a federation member.
+	servers, err := fedDB.UniverseServers(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+
+	// The queue of the registration server is bounded as well, while the
+	// queue of the member is independent of it.
+	otherRegPush := *regPush
+	otherRegPush.LeafKey = randLeafKey(t)
+	err = fedDB.QueuePendingPush(ctx, &otherRegPush, maxQueueSize)
+	require.ErrorIs(t, err, universe.ErrPushQueueFull)
+
+	memberPush := &universe.PendingPush{
+		Server:      member,
+		UniverseID:  regPush.UniverseID,
+		LeafKey:     regPush.LeafKey,
+		NumAttempts: 1,
+		NextAttempt: now,
+		LastError:   "timeout",
+	}
+	require.NoError(t, fedDB.QueuePendingPush(
+		ctx, memberPush, maxQueueSize,
+	))
+
+	// Both pushes are returned, ordered by their next attempt.
+	allPushes, err := fedDB.PendingPushes(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, allPushes, 2)
+	require.False(t, allPushes[0].Registration)
+	require.Equal(t, member.ID, allPushes[0].Server.ID)
+
+	dbRegPush := allPushes[1]
+	require.True(t, dbRegPush.Registration)
+	require.Equal(t, regServer.HostStr(), dbRegPush.Server.HostStr())
+	require.Equal(
+		t, regPush.LeafKey.UniverseKey(),
+		dbRegPush.LeafKey.UniverseKey(),
+	)
+	require.Equal(
+		t, regPush.UniverseID.String(), dbRegPush.UniverseID.String(),
+	)
+
+	// Only the member push is due now.
+	duePushes, err := fedDB.PendingPushes(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, duePushes, 1)
+	require.False(t, duePushes[0].Registration)
+
+	// Updates and removals of the registration push don't affect the
+	// member push, even though the IDs of both might be the same.
+	dbRegPush.NumAttempts = 2
+	dbRegPush.NextAttempt = now.Add(-time.Minute)
+	require.NoError(t, fedDB.UpdatePendingPush(ctx, dbRegPush))
+
+	duePushes, err = fedDB.PendingPushes(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, duePushes, 2)
+	require.True(t, duePushes[0].Registration)
+	require.EqualValues(t, 2, duePushes[0].NumAttempts)
+	require.EqualValues(t, 1, duePushes[1].NumAttempts)
+
+	require.NoError(t, fedDB.RemovePendingPush(ctx, dbRegPush))
+
+	allPushes, err = fedDB.PendingPushes(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, allPushes, 1)
+	require.False(t, allPushes[0].Registration)
 }
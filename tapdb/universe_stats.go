@@ -16,6 +16,16 @@ import (
 	"github.com/lightningnetwork/lnd/clock"
 )
 
+const (
+	// syncEventType is the event type of a universe sync event, as stored
+	// in the universe_events and universe_stats_counters tables.
+	syncEventType = "SYNC"
+
+	// newProofEventType is the event type of a new proof event, as stored
+	// in the universe_events and universe_stats_counters tables.
+	newProofEventType = "NEW_PROOF"
+)
+
 type (
 	// NewProofEvent is used to create a new event that logs insertion of a
 	// new proof.
@@ -35,6 +45,10 @@ type (
 	// Universe.
 	AggregateStats = sqlc.QueryUniverseStatsRow
 
+	// RecentUniverseEvents is used to return the number of universe events
+	// logged within a recent time window.
+	RecentUniverseEvents = sqlc.QueryRecentUniverseEventsRow
+
 	// AssetStatsPerDay is the assets stats record for a given day.
 	AssetStatsPerDay = sqlc.QueryAssetStatsPerDaySqliteRow
 
@@ -60,9 +74,19 @@ type UniverseStatsStore interface {
 	// InsertNewSyncEvent inserts a new sync event into the database.
 	InsertNewSyncEvent(ctx context.Context, arg NewSyncEvent) error
 
+	// IncrementUniverseStatsCounter increments the running counter of the
+	// given event type by one.
+	IncrementUniverseStatsCounter(ctx context.Context,
+		eventType string) error
+
 	// QueryUniverseStats returns the aggregated stats for the entire
 	QueryUniverseStats(ctx context.Context) (AggregateStats, error)
 
+	// QueryRecentUniverseEvents returns the number of sync and new proof
+	// events logged since the given unix timestamp.
+	QueryRecentUniverseEvents(ctx context.Context,
+		sinceTimestamp int64) (RecentUniverseEvents, error)
+
 	// QueryUniverseAssetStats returns the stats for a given asset within a
 	// universe/
 	QueryUniverseAssetStats(ctx context.Context,
@@ -135,13 +159,18 @@ func (u *UniverseStats) LogSyncEvent(ctx context.Context,
 			groupKeyXOnly = schnorr.SerializePubKey(uniID.GroupKey)
 		}
 
-		return db.InsertNewSyncEvent(ctx, NewSyncEvent{
+		err := db.InsertNewSyncEvent(ctx, NewSyncEvent{
 			EventTime:      u.clock.Now().UTC(),
 			EventTimestamp: u.clock.Now().UTC().Unix(),
 			AssetID:        uniID.AssetID[:],
 			GroupKeyXOnly:  groupKeyXOnly,
 			ProofType:      uniID.ProofType.String(),
 		})
+		if err != nil {
+			return err
+		}
+
+		return db.IncrementUniverseStatsCounter(ctx, syncEventType)
 	})
 }
 
@@ -171,6 +200,13 @@ func (u *UniverseStats) LogSyncEvents(ctx context.Context,
 			if err != nil {
 				return err
 			}
+
+			err = db.IncrementUniverseStatsCounter(
+				ctx, syncEventType,
+			)
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -188,13 +224,20 @@ func (u *UniverseStats) LogNewProofEvent(ctx context.Context,
 			groupKeyXOnly = schnorr.SerializePubKey(uniID.GroupKey)
 		}
 
-		return db.InsertNewProofEvent(ctx, NewProofEvent{
+		err := db.InsertNewProofEvent(ctx, NewProofEvent{
 			EventTime:      u.clock.Now().UTC(),
 			EventTimestamp: u.clock.Now().UTC().Unix(),
 			AssetID:        uniID.AssetID[:],
 			GroupKeyXOnly:  groupKeyXOnly,
 			ProofType:      uniID.ProofType.String(),
 		})
+		if err != nil {
+			return err
+		}
+
+		return db.IncrementUniverseStatsCounter(
+			ctx, newProofEventType,
+		)
 	})
 }
 
@@ -223,6 +266,13 @@ func (u *UniverseStats) LogNewProofEvents(ctx context.Context,
 			if err != nil {
 				return err
 			}
+
+			err = db.IncrementUniverseStatsCounter(
+				ctx, newProofEventType,
+			)
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -271,6 +321,20 @@ func (u *UniverseStats) AggregateSyncStats(
 			return err
 		}
 
+		// The recent activity counts are only computed over the
+		// indexed event timestamps of the last window, so this
+		// doesn't require a scan of the full event log either.
+		since := u.clock.Now().Add(-universe.RecentStatsWindow)
+		recent, err := db.QueryRecentUniverseEvents(
+			ctx, since.UTC().Unix(),
+		)
+		if err != nil {
+			return err
+		}
+
+		stats.NumRecentSyncs = uint64(recent.NumSyncs)
+		stats.NumRecentProofs = uint64(recent.NumProofs)
+
 		return nil
 	})
 	if err != nil {
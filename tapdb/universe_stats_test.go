@@ -148,11 +148,14 @@ func TestUniverseStatsEvents(t *testing.T) {
 
 	// We'll now query for the set of aggregate Universe stats. It should
 	// show 3 assets, and one new proof for each of those assets.
+	// As the clock was moved forward a day after each proof, only the last
+	// proof falls within the recent stats window.
 	sh.assertUniverseStatsEqual(t, universe.AggregateStats{
-		NumTotalAssets: numTranches,
-		NumTotalGroups: numGroups,
-		NumTotalProofs: numTranches,
-		NumTotalSyncs:  0,
+		NumTotalAssets:  numTranches,
+		NumTotalGroups:  numGroups,
+		NumTotalProofs:  numTranches,
+		NumTotalSyncs:   0,
+		NumRecentProofs: 1,
 	})
 
 	// Next, we'll simulate a new sync event for a random asset. If we
@@ -163,10 +166,12 @@ func TestUniverseStatsEvents(t *testing.T) {
 	sh.logSyncEventByIndex(assetToSync)
 
 	sh.assertUniverseStatsEqual(t, universe.AggregateStats{
-		NumTotalAssets: numTranches,
-		NumTotalGroups: numGroups,
-		NumTotalProofs: numTranches,
-		NumTotalSyncs:  1,
+		NumTotalAssets:  numTranches,
+		NumTotalGroups:  numGroups,
+		NumTotalProofs:  numTranches,
+		NumTotalSyncs:   1,
+		NumRecentSyncs:  1,
+		NumRecentProofs: 1,
 	})
 
 	// We'll now query for the set of Universe events. There should be 4
@@ -224,11 +229,19 @@ func TestUniverseStatsEvents(t *testing.T) {
 	if sh.universeLeaves[assetToSync].Leaf.GroupKey != nil {
 		numGroups--
 	}
+
+	// The only recent proof is removed as well if it belonged to the
+	// deleted universe.
+	var numRecentProofs uint64 = 1
+	if assetToSync == numTranches-1 {
+		numRecentProofs = 0
+	}
 	sh.assertUniverseStatsEqual(t, universe.AggregateStats{
-		NumTotalAssets: numTranches - 1,
-		NumTotalGroups: numGroups,
-		NumTotalProofs: numTranches - 1,
-		NumTotalSyncs:  0,
+		NumTotalAssets:  numTranches - 1,
+		NumTotalGroups:  numGroups,
+		NumTotalProofs:  numTranches - 1,
+		NumTotalSyncs:   0,
+		NumRecentProofs: numRecentProofs,
 	})
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightninglabs/taproot-assets/tapdb/sqlc"
 	"github.com/lightninglabs/taproot-assets/universe"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -84,6 +85,10 @@ func newTestUniverse(t *testing.T,
 func newTestMultiverse(t *testing.T) (*MultiverseStore, sqlc.Querier) {
 	db := NewTestDB(t)
 
+	return newTestMultiverseWithDb(db.BaseDB)
+}
+
+func newTestMultiverseWithDb(db *BaseDB) (*MultiverseStore, sqlc.Querier) {
 	dbTxer := NewTransactionExecutor(db,
 		func(tx *sql.Tx) BaseMultiverseStore {
 			return db.WithTx(tx)
@@ -490,6 +495,84 @@ func TestUniverseTreeIsolation(t *testing.T) {
 	require.True(t, mssmt.IsEqualNode(rootNodes[0].Node, groupRoot))
 }
 
+// TestMultiverseIssuanceOrigin tests that universe roots of assets minted by
+// the local node are marked as the issuance origin.
+func TestMultiverseIssuanceOrigin(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db := NewTestDB(t)
+
+	mintedID := randUniverseID(t, false)
+	mintedUniverse, _ := newTestUniverseWithDb(db.BaseDB, mintedID)
+	mirroredID := randUniverseID(t, false)
+	mirroredUniverse, _ := newTestUniverseWithDb(db.BaseDB, mirroredID)
+
+	mintedGen := asset.RandGenesis(t, asset.Normal)
+	_, err := insertRandLeaf(t, ctx, mintedUniverse, &mintedGen)
+	require.NoError(t, err)
+	mirroredGen := asset.RandGenesis(t, asset.Normal)
+	_, err = insertRandLeaf(t, ctx, mirroredUniverse, &mirroredGen)
+	require.NoError(t, err)
+
+	multiverseDB := NewTransactionExecutor(db,
+		func(tx *sql.Tx) BaseMultiverseStore {
+			return db.WithTx(tx)
+		},
+	)
+	multiverse := NewMultiverseStore(multiverseDB)
+
+	// issuanceOrigins maps the asset ID of each root to its issuance
+	// origin flag.
+	issuanceOrigins := func() map[asset.ID]bool {
+		rootNodes, err := multiverse.RootNodes(ctx)
+		require.NoError(t, err)
+		require.Len(t, rootNodes, 2)
+
+		origins := make(map[asset.ID]bool, len(rootNodes))
+		for _, rootNode := range rootNodes {
+			origins[rootNode.ID.AssetID] = rootNode.IssuanceOrigin
+		}
+
+		return origins
+	}
+
+	// Neither of the assets was minted by us, so both roots are mirrored.
+	origins := issuanceOrigins()
+	require.False(t, origins[mintedGen.ID()])
+	require.False(t, origins[mirroredGen.ID()])
+
+	// We now add a minting batch for the genesis point of the first asset,
+	// which makes us the issuance origin of its universe.
+	genesisPoint, err := encodeOutpoint(mintedGen.FirstPrevOut)
+	require.NoError(t, err)
+	genesisPointID, err := db.UpsertGenesisPoint(ctx, genesisPoint)
+	require.NoError(t, err)
+
+	batchKey := test.RandPubKey(t).SerializeCompressed()
+	batchID, err := db.UpsertInternalKey(ctx, sqlc.UpsertInternalKeyParams{
+		RawKey: batchKey,
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.NewMintingBatch(ctx, sqlc.NewMintingBatchParams{
+		BatchID:          batchID,
+		CreationTimeUnix: time.Now(),
+	}))
+	err = db.BindMintingBatchWithTx(ctx, sqlc.BindMintingBatchWithTxParams{
+		RawKey: batchKey,
+		GenesisID: sql.NullInt64{
+			Int64: genesisPointID,
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	origins = issuanceOrigins()
+	require.True(t, origins[mintedGen.ID()])
+	require.False(t, origins[mirroredGen.ID()])
+}
+
 // TestUniverseLeafQuery tests that we're able to properly query for the set of
 // leaves in a Universe based on either the outpoint or the script key.
 func TestUniverseLeafQuery(t *testing.T) {
@@ -869,3 +952,117 @@ func TestMultiverseRootSum(t *testing.T) {
 		})
 	}
 }
+
+// TestMultiversePruneProofLeaves tests that we're able to prune the leaves of a
+// universe by block height, and that the universe and multiverse roots are
+// updated accordingly. The proof events of the pruned leaves should no longer
+// be counted in the universe stats.
+func TestMultiversePruneProofLeaves(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := NewTestDB(t)
+	multiverse, _ := newTestMultiverseWithDb(db.BaseDB)
+	statsDB, _ := newUniverseStatsWithDB(
+		db.BaseDB, clock.NewTestClock(time.Now()),
+	)
+
+	// assertNumProofs asserts the number of proofs counted in the
+	// universe stats.
+	assertNumProofs := func(numProofs uint64) {
+		t.Helper()
+
+		stats, err := statsDB.AggregateSyncStats(ctx)
+		require.NoError(t, err)
+		require.Equal(t, numProofs, stats.NumTotalProofs)
+	}
+
+	// The proof events are logged against the group key of the universe.
+	id := randUniverseID(
+		t, true, withProofType(universe.ProofTypeIssuance),
+	)
+	assetGen := asset.RandGenesis(t, asset.Normal)
+
+	// We'll insert three leaves that were confirmed at increasing block
+	// heights.
+	heights := []uint32{100, 200, 300}
+	leaves := make([]leafWithKey, len(heights))
+	for i, height := range heights {
+		leaf := randMintingLeaf(t, assetGen, id.GroupKey)
+		leaf.Proof.BlockHeight = height
+		leafKey := randLeafKey(t)
+
+		_, err := multiverse.UpsertProofLeaf(
+			ctx, id, leafKey, &leaf, nil,
+		)
+		require.NoError(t, err)
+		require.NoError(t, statsDB.LogNewProofEvent(ctx, id, leafKey))
+
+		leaves[i] = leafWithKey{
+			LeafKey: leafKey,
+			Leaf:    leaf,
+		}
+	}
+
+	// If we prune below the lowest height, nothing should be removed.
+	assertNumProofs(3)
+	numPruned, err := multiverse.PruneProofLeaves(ctx, id, heights[0])
+	require.NoError(t, err)
+	require.Zero(t, numPruned)
+	assertNumProofs(3)
+
+	// Pruning below the last height should remove the first two leaves.
+	numPruned, err = multiverse.PruneProofLeaves(ctx, id, heights[2])
+	require.NoError(t, err)
+	require.EqualValues(t, 2, numPruned)
+	assertNumProofs(1)
+
+	for _, leaf := range leaves[:2] {
+		_, err := multiverse.FetchProofLeaf(ctx, id, leaf.LeafKey)
+		require.ErrorIs(t, err, universe.ErrNoUniverseProofFound)
+	}
+
+	// The universe root should now only commit to the remaining leaf,
+	// which should also be reflected in the multiverse tree.
+	uniRoots, err := multiverse.RootNodes(ctx)
+	require.NoError(t, err)
+	require.Len(t, uniRoots, 1)
+	require.EqualValues(t, leaves[2].Amt, uniRoots[0].Node.NodeSum())
+
+	uniProofs, err := multiverse.FetchProofLeaf(ctx, id, leaves[2].LeafKey)
+	require.NoError(t, err)
+	require.Len(t, uniProofs, 1)
+
+	uniProof := uniProofs[0]
+	validRoot, err := uniProof.VerifyRoot(uniRoots[0].Node)
+	require.NoError(t, err)
+	require.True(t, validRoot)
+
+	uniRootHash := uniProof.UniverseRoot.NodeHash()
+	multiverseRoot := uniProof.MultiverseInclusionProof.Root(
+		id.Bytes(), mssmt.NewLeafNode(uniRootHash[:], 1),
+	)
+	require.True(t, mssmt.IsEqualNode(
+		multiverseRoot, uniProof.MultiverseRoot,
+	))
+
+	// Without a height, all remaining leaves are removed, which should
+	// remove the universe altogether.
+	numPruned, err = multiverse.PruneProofLeaves(ctx, id, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, numPruned)
+	assertNumProofs(0)
+
+	uniRoots, err = multiverse.RootNodes(ctx)
+	require.NoError(t, err)
+	require.Empty(t, uniRoots)
+
+	rootNode, err := multiverse.RootNode(ctx, id.ProofType)
+	require.NoError(t, err)
+	require.True(t, mssmt.IsEqualNode(rootNode.Node, mssmt.EmptyTree[0]))
+
+	// Pruning an empty universe is a no-op.
+	numPruned, err = multiverse.PruneProofLeaves(ctx, id, 0)
+	require.NoError(t, err)
+	require.Zero(t, numPruned)
+}
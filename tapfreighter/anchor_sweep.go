@@ -0,0 +1,354 @@
+package tapfreighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// ErrSweepOverBudget is returned when sweeping a set of anchor outputs would
+// spend more on fees than the fee budget of the anchor sweep policy allows.
+var ErrSweepOverBudget = errors.New("sweep fee exceeds fee budget")
+
+// AnchorSweepPolicy describes how the anchor outputs that only anchor spent
+// assets, such as the tombstones and burns left behind by completed transfers,
+// are swept back into the wallet.
+type AnchorSweepPolicy struct {
+	// Interval is the interval at which sweepable anchor outputs are
+	// looked for and swept.
+	Interval time.Duration
+
+	// MaxOutputValue is the value an anchor output has at most to be
+	// swept.
+	MaxOutputValue btcutil.Amount
+
+	// MaxFeeRate is the fee rate above which no sweep is attempted. This
+	// restricts sweeps to low-fee periods.
+	MaxFeeRate chainfee.SatPerKWeight
+
+	// ConfTarget is the confirmation target the fee rate of a sweep
+	// transaction is estimated for.
+	ConfTarget uint32
+
+	// FeeBudget is the percentage of the value of the swept anchor outputs
+	// that can be spent on the fees of a sweep transaction at most.
+	FeeBudget uint32
+}
+
+// SweepableAnchor is a confirmed anchor output of the node that only anchors
+// spent assets and can therefore be swept back into the wallet.
+type SweepableAnchor struct {
+	// OutPoint is the outpoint of the anchor output.
+	OutPoint wire.OutPoint
+
+	// Value is the value of the anchor output.
+	Value btcutil.Amount
+
+	// InternalKey is the internal key of the anchor output.
+	InternalKey keychain.KeyDescriptor
+
+	// MerkleRoot is the root of the tapscript tree the internal key of the
+	// anchor output is tweaked with.
+	MerkleRoot []byte
+}
+
+// AnchorSweep is an anchor output that was swept back into the wallet.
+type AnchorSweep struct {
+	// OutPoint is the outpoint of the swept anchor output.
+	OutPoint wire.OutPoint
+
+	// Value is the value of the swept anchor output.
+	Value btcutil.Amount
+
+	// SweepTXID is the ID of the transaction that swept the anchor output.
+	SweepTXID chainhash.Hash
+
+	// SweptAt is the time the sweep transaction was published.
+	SweptAt time.Time
+}
+
+// AnchorSweepStatus is the status of the background sweeper of anchor outputs.
+type AnchorSweepStatus struct {
+	// Policy is the policy of the sweeper, or nil if sweeping anchor
+	// outputs isn't enabled.
+	Policy *AnchorSweepPolicy
+
+	// LastAttempt is the time of the last sweep attempt. This is the zero
+	// time if no sweep was attempted yet.
+	LastAttempt time.Time
+
+	// LastError is the error the last sweep attempt failed with, if any.
+	LastError error
+
+	// Backlog is the set of anchor outputs that are currently waiting to
+	// be swept.
+	Backlog []*SweepableAnchor
+
+	// Sweeps is the set of anchor outputs that were already swept.
+	Sweeps []*AnchorSweep
+}
+
+// sweepFee returns the fee of the transaction that sweeps the given number of
+// anchor outputs to a single P2TR output of the wallet at the given fee rate.
+func sweepFee(numInputs int, feeRate chainfee.SatPerKWeight) btcutil.Amount {
+	var weightEstimator input.TxWeightEstimator
+	for i := 0; i < numInputs; i++ {
+		weightEstimator.AddTaprootKeySpendInput(txscript.SigHashDefault)
+	}
+	weightEstimator.AddP2TROutput()
+
+	return feeRate.FeeForWeight(int64(weightEstimator.Weight()))
+}
+
+// newSweepPacket creates the PSBT that sweeps the given anchor outputs to the
+// given pkScript of the wallet at the given fee rate. ErrSweepOverBudget is
+// returned if the fee exceeds the fee budget of the policy.
+func (a *AnchorSweepPolicy) newSweepPacket(anchors []*SweepableAnchor,
+	pkScript []byte, feeRate chainfee.SatPerKWeight,
+	coinType uint32) (*psbt.Packet, error) {
+
+	if len(anchors) == 0 {
+		return nil, fmt.Errorf("no anchor outputs to sweep")
+	}
+
+	var (
+		tx        = wire.NewMsgTx(2)
+		pInputs   = make([]psbt.PInput, 0, len(anchors))
+		sweptAmt  btcutil.Amount
+		numInputs = len(anchors)
+	)
+	for _, anchor := range anchors {
+		outputKey := txscript.ComputeTaprootOutputKey(
+			anchor.InternalKey.PubKey, anchor.MerkleRoot,
+		)
+		anchorPkScript, err := tapscript.PayToTaprootScript(outputKey)
+		if err != nil {
+			return nil, err
+		}
+
+		_, trDerivation := tappsbt.Bip32DerivationFromKeyDesc(
+			anchor.InternalKey, coinType,
+		)
+
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: anchor.OutPoint})
+		pInputs = append(pInputs, psbt.PInput{
+			WitnessUtxo: &wire.TxOut{
+				Value:    int64(anchor.Value),
+				PkScript: anchorPkScript,
+			},
+			SighashType: txscript.SigHashDefault,
+			TaprootBip32Derivation: []*psbt.TaprootBip32Derivation{
+				trDerivation,
+			},
+			TaprootInternalKey: trDerivation.XOnlyPubKey,
+			TaprootMerkleRoot:  anchor.MerkleRoot,
+		})
+
+		sweptAmt += anchor.Value
+	}
+
+	fee := sweepFee(numInputs, feeRate)
+	if fee*100 > sweptAmt*btcutil.Amount(a.FeeBudget) {
+		return nil, fmt.Errorf("%w: fee of %v for sweeping %v",
+			ErrSweepOverBudget, fee, sweptAmt)
+	}
+
+	outputValue := sweptAmt - fee
+	dustLimit := lnwallet.DustLimitForSize(len(pkScript))
+	if outputValue < dustLimit {
+		return nil, fmt.Errorf("%w: sweep output of %v is dust",
+			ErrSweepOverBudget, outputValue)
+	}
+
+	tx.AddTxOut(&wire.TxOut{
+		Value:    int64(outputValue),
+		PkScript: pkScript,
+	})
+
+	pkt, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	pkt.Inputs = pInputs
+
+	return pkt, nil
+}
+
+// sweepAnchors periodically sweeps the anchor outputs that only anchor spent
+// assets back into the wallet according to the anchor sweep policy.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ChainPorter) sweepAnchors() {
+	defer p.Wg.Done()
+
+	ticker := time.NewTicker(p.cfg.AnchorSweep.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := p.WithCtxQuitNoTimeout()
+			err := p.sweepPendingAnchors(ctx, time.Now())
+			cancel()
+
+			if err != nil {
+				log.Warnf("Unable to sweep anchor outputs: %v",
+					err)
+			}
+
+		case <-p.Quit:
+			return
+		}
+	}
+}
+
+// sweepPendingAnchors sweeps all anchor outputs that are currently sweepable
+// in a single transaction, if the current fee rate is low enough and the fee
+// is within the fee budget. The outcome is recorded as the last sweep attempt.
+func (p *ChainPorter) sweepPendingAnchors(ctx context.Context,
+	now time.Time) error {
+
+	err := p.trySweepAnchors(ctx, now)
+
+	p.sweepMtx.Lock()
+	p.lastSweepAttempt = now
+	p.lastSweepErr = err
+	p.sweepMtx.Unlock()
+
+	return err
+}
+
+// trySweepAnchors attempts to sweep all anchor outputs that are currently
+// sweepable in a single transaction.
+func (p *ChainPorter) trySweepAnchors(ctx context.Context,
+	now time.Time) error {
+
+	policy := p.cfg.AnchorSweep
+
+	anchors, err := p.cfg.ExportLog.SweepableAnchors(
+		ctx, policy.MaxOutputValue,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to fetch sweepable anchor outputs: "+
+			"%w", err)
+	}
+	if len(anchors) == 0 {
+		return nil
+	}
+
+	feeRate, err := p.cfg.ChainBridge.EstimateFee(ctx, policy.ConfTarget)
+	if err != nil {
+		return fmt.Errorf("unable to estimate fee rate: %w", err)
+	}
+	if feeRate > policy.MaxFeeRate {
+		log.Debugf("Not sweeping %d anchor outputs, fee rate %v above "+
+			"maximum sweep fee rate %v", len(anchors), feeRate,
+			policy.MaxFeeRate)
+		return nil
+	}
+
+	sweepAddr, err := p.cfg.Wallet.NextAddr(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to derive sweep address: %w", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(sweepAddr)
+	if err != nil {
+		return err
+	}
+
+	sweepPkt, err := policy.newSweepPacket(
+		anchors, pkScript, feeRate, p.cfg.ChainParams.HDCoinType,
+	)
+	if err != nil {
+		return err
+	}
+
+	signedPkt, err := p.cfg.Wallet.SignPsbt(ctx, sweepPkt)
+	if err != nil {
+		return fmt.Errorf("unable to sign sweep transaction: %w", err)
+	}
+	if err := psbt.MaybeFinalizeAll(signedPkt); err != nil {
+		return fmt.Errorf("unable to finalize sweep transaction: %w",
+			err)
+	}
+	sweepTx, err := psbt.Extract(signedPkt)
+	if err != nil {
+		return fmt.Errorf("unable to extract sweep transaction: %w",
+			err)
+	}
+	err = blockchain.CheckTransactionSanity(btcutil.NewTx(sweepTx))
+	if err != nil {
+		return fmt.Errorf("sweep transaction is invalid: %w", err)
+	}
+
+	sweepTXID := sweepTx.TxHash()
+	log.Infof("Sweeping %d anchor outputs with sweep_txid=%v at fee "+
+		"rate %v", len(anchors), sweepTXID, feeRate)
+
+	err = p.cfg.ChainBridge.PublishTransaction(ctx, sweepTx)
+	if err != nil {
+		return fmt.Errorf("unable to publish sweep transaction: %w",
+			err)
+	}
+
+	anchorPoints := make([]wire.OutPoint, len(anchors))
+	for idx := range anchors {
+		anchorPoints[idx] = anchors[idx].OutPoint
+	}
+
+	return p.cfg.ExportLog.LogAnchorSweep(
+		ctx, sweepTXID, anchorPoints, now,
+	)
+}
+
+// AnchorSweepStatus returns the status of the background sweeper of anchor
+// outputs, including the anchor outputs that are waiting to be swept and the
+// ones that were already swept.
+func (p *ChainPorter) AnchorSweepStatus(
+	ctx context.Context) (*AnchorSweepStatus, error) {
+
+	policy := p.cfg.AnchorSweep
+
+	p.sweepMtx.Lock()
+	status := &AnchorSweepStatus{
+		Policy:      policy,
+		LastAttempt: p.lastSweepAttempt,
+		LastError:   p.lastSweepErr,
+	}
+	p.sweepMtx.Unlock()
+
+	sweeps, err := p.cfg.ExportLog.AnchorSweeps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch anchor sweeps: %w", err)
+	}
+	status.Sweeps = sweeps
+
+	// Without a policy, we don't know which anchor outputs would be swept.
+	if policy == nil {
+		return status, nil
+	}
+
+	status.Backlog, err = p.cfg.ExportLog.SweepableAnchors(
+		ctx, policy.MaxOutputValue,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch sweepable anchor "+
+			"outputs: %w", err)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,193 @@
+package tapfreighter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+type sweepExportLog struct {
+	ExportLog
+
+	anchors []*SweepableAnchor
+	swept   map[wire.OutPoint]chainhash.Hash
+}
+
+func (l *sweepExportLog) SweepableAnchors(_ context.Context,
+	maxValue btcutil.Amount) ([]*SweepableAnchor, error) {
+
+	var anchors []*SweepableAnchor
+	for _, anchor := range l.anchors {
+		if _, ok := l.swept[anchor.OutPoint]; ok {
+			continue
+		}
+		if anchor.Value <= maxValue {
+			anchors = append(anchors, anchor)
+		}
+	}
+
+	return anchors, nil
+}
+
+func (l *sweepExportLog) LogAnchorSweep(_ context.Context,
+	sweepTXID chainhash.Hash, anchorPoints []wire.OutPoint,
+	_ time.Time) error {
+
+	for _, anchorPoint := range anchorPoints {
+		l.swept[anchorPoint] = sweepTXID
+	}
+
+	return nil
+}
+
+type sweepChainBridge struct {
+	ChainBridge
+
+	feeRate   chainfee.SatPerKWeight
+	published []*wire.MsgTx
+}
+
+func (b *sweepChainBridge) EstimateFee(context.Context,
+	uint32) (chainfee.SatPerKWeight, error) {
+
+	return b.feeRate, nil
+}
+
+func (b *sweepChainBridge) PublishTransaction(_ context.Context,
+	tx *wire.MsgTx) error {
+
+	b.published = append(b.published, tx)
+	return nil
+}
+
+type sweepWallet struct {
+	WalletAnchor
+
+	addr btcutil.Address
+}
+
+func (w *sweepWallet) NextAddr(context.Context) (btcutil.Address, error) {
+	return w.addr, nil
+}
+
+func (w *sweepWallet) SignPsbt(_ context.Context,
+	pkt *psbt.Packet) (*psbt.Packet, error) {
+
+	for idx := range pkt.Inputs {
+		pkt.Inputs[idx].TaprootKeySpendSig = make([]byte, 64)
+	}
+
+	return pkt, nil
+}
+
+// TestSweepPendingAnchors tests that sweepable anchor outputs are only swept
+// if the fee rate is low enough and the fee is within the fee budget.
+func TestSweepPendingAnchors(t *testing.T) {
+	t.Parallel()
+
+	newAnchor := func(value btcutil.Amount) *SweepableAnchor {
+		return &SweepableAnchor{
+			OutPoint: test.RandOp(t),
+			Value:    value,
+			InternalKey: keychain.KeyDescriptor{
+				PubKey: test.RandPubKey(t),
+				KeyLocator: keychain.KeyLocator{
+					Family: 212,
+					Index:  7,
+				},
+			},
+			MerkleRoot: test.RandBytes(32),
+		}
+	}
+
+	sweepAddr, err := btcutil.NewAddressTaproot(
+		test.RandBytes(32), address.RegressionNetTap.Params,
+	)
+	require.NoError(t, err)
+
+	var (
+		dustAnchor1 = newAnchor(1000)
+		dustAnchor2 = newAnchor(1000)
+		bigAnchor   = newAnchor(50_000)
+	)
+	exportLog := &sweepExportLog{
+		anchors: []*SweepableAnchor{
+			dustAnchor1, dustAnchor2, bigAnchor,
+		},
+		swept: make(map[wire.OutPoint]chainhash.Hash),
+	}
+	chainBridge := &sweepChainBridge{
+		feeRate: 5000,
+	}
+	policy := &AnchorSweepPolicy{
+		Interval:       time.Hour,
+		MaxOutputValue: 1000,
+		MaxFeeRate:     1000,
+		ConfTarget:     144,
+		FeeBudget:      25,
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ExportLog:   exportLog,
+		ChainBridge: chainBridge,
+		Wallet:      &sweepWallet{addr: sweepAddr},
+		AnchorSweep: policy,
+		ChainParams: &address.RegressionNetTap,
+	})
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// The fee rate is above the maximum fee rate, so nothing is swept.
+	require.NoError(t, porter.sweepPendingAnchors(ctx, now))
+	require.Empty(t, chainBridge.published)
+
+	// At a lower fee rate, the fee of sweeping the two dust anchor
+	// outputs still exceeds the fee budget.
+	chainBridge.feeRate = 900
+	err = porter.sweepPendingAnchors(ctx, now)
+	require.ErrorIs(t, err, ErrSweepOverBudget)
+	require.Empty(t, chainBridge.published)
+
+	// Once the fee rate drops further, both dust anchor outputs are swept
+	// in a single transaction, while the big one is left alone.
+	chainBridge.feeRate = 253
+	require.NoError(t, porter.sweepPendingAnchors(ctx, now))
+	require.Len(t, chainBridge.published, 1)
+
+	sweepTx := chainBridge.published[0]
+	require.Len(t, sweepTx.TxIn, 2)
+	require.Equal(
+		t, dustAnchor1.OutPoint, sweepTx.TxIn[0].PreviousOutPoint,
+	)
+	require.Equal(
+		t, dustAnchor2.OutPoint, sweepTx.TxIn[1].PreviousOutPoint,
+	)
+	require.Len(t, sweepTx.TxOut, 1)
+
+	pkScript, err := txscript.PayToAddrScript(sweepAddr)
+	require.NoError(t, err)
+	require.Equal(t, pkScript, sweepTx.TxOut[0].PkScript)
+
+	fee := sweepFee(2, chainBridge.feeRate)
+	require.EqualValues(t, 2000-fee, sweepTx.TxOut[0].Value)
+
+	require.Equal(t, map[wire.OutPoint]chainhash.Hash{
+		dustAnchor1.OutPoint: sweepTx.TxHash(),
+		dustAnchor2.OutPoint: sweepTx.TxHash(),
+	}, exportLog.swept)
+
+	// With the backlog cleared, there is nothing left to sweep.
+	require.NoError(t, porter.sweepPendingAnchors(ctx, now))
+	require.Len(t, chainBridge.published, 1)
+}
@@ -3,6 +3,7 @@ package tapfreighter
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,8 +12,10 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/fn"
 	"github.com/lightninglabs/taproot-assets/proof"
@@ -20,8 +23,14 @@ import (
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
+// DefaultIdempotencyKeyRetention is the default duration after which the
+// idempotency key of a send request no longer prevents a new transfer with the
+// same key.
+const DefaultIdempotencyKeyRetention = 24 * time.Hour
+
 // ChainPorterConfig is the main config for the chain porter.
 type ChainPorterConfig struct {
 	// Signer implements the Taproot Asset level signing we need to sign a
@@ -67,6 +76,50 @@ type ChainPorterConfig struct {
 	// to be confirmed safely with a minimum number of confirmations.
 	ProofWatcher proof.Watcher
 
+	// AutoRetryAbandoned indicates whether a transfer to a set of addresses
+	// should automatically be re-attempted with a new anchor transaction
+	// and fresh coins if the original anchor transaction can no longer
+	// confirm because one of its inputs was double spent.
+	AutoRetryAbandoned bool
+
+	// FeeEscalation is the policy the fee rate of the anchor transactions
+	// of pending transfers that don't confirm is escalated with. If nil,
+	// fee rates are only bumped on request.
+	FeeEscalation *FeeEscalationPolicy
+
+	// AnchorSweep is the policy the anchor outputs that only anchor spent
+	// assets are swept back into the wallet with. If nil, such outputs
+	// aren't swept.
+	AnchorSweep *AnchorSweepPolicy
+
+	// ChainParams are the parameters of the chain we operate on.
+	ChainParams *address.ChainParams
+
+	// DeliveryCompletion is the policy that determines how many receiver
+	// proofs of a transfer need to be delivered before the transfer is
+	// marked as complete. If nil, the proofs need to be delivered to all
+	// receivers.
+	DeliveryCompletion *DeliveryCompletionPolicy
+
+	// ReOrgPolicy is the policy applied to a confirmed transfer whose
+	// anchor transaction is re-organized out of the best chain.
+	ReOrgPolicy ReOrgPolicy
+
+	// ReOrgSafeDepth is the number of confirmations after which the anchor
+	// transaction of a confirmed transfer is no longer watched for
+	// re-orgs. If zero, confirmed transfers aren't watched at all.
+	ReOrgSafeDepth uint32
+
+	// FeeBumpAnchor indicates that every anchor transaction should reserve
+	// an additional wallet owned output that can be spent by a child
+	// transaction to bump its fee (CPFP).
+	FeeBumpAnchor bool
+
+	// IdempotencyKeyRetention is the duration after which the idempotency
+	// key of a send request no longer prevents a new transfer with the
+	// same key. If zero, DefaultIdempotencyKeyRetention is used.
+	IdempotencyKeyRetention time.Duration
+
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
 	ErrChan chan<- error
@@ -93,6 +146,37 @@ type ChainPorter struct {
 	// subscriptionID.
 	subscriberMtx sync.Mutex
 
+	// feeBumpHandlers holds the handlers for fee bump requests of the
+	// pending transfers that are waiting for their anchor transaction to
+	// confirm, keyed by their anchor transaction ID.
+	feeBumpHandlers map[chainhash.Hash]*feeBumpHandler
+
+	// feeBumpMtx guards the feeBumpHandlers map.
+	feeBumpMtx sync.Mutex
+
+	// cancelHandlers holds the handlers for cancel requests of the pending
+	// transfers that are waiting for their anchor transaction to confirm,
+	// keyed by their anchor transaction ID.
+	cancelHandlers map[chainhash.Hash]*cancelHandler
+
+	// cancelMtx guards the cancelHandlers map.
+	cancelMtx sync.Mutex
+
+	// lastSweepAttempt is the time of the last attempt to sweep anchor
+	// outputs and lastSweepErr the error it failed with, if any.
+	lastSweepAttempt time.Time
+	lastSweepErr     error
+
+	// sweepMtx guards lastSweepAttempt and lastSweepErr.
+	sweepMtx sync.Mutex
+
+	// idempotencyKeys is the set of idempotency keys of the send requests
+	// that are currently being processed.
+	idempotencyKeys fn.Set[string]
+
+	// idempotencyMtx guards the idempotencyKeys set.
+	idempotencyMtx sync.Mutex
+
 	*fn.ContextGuard
 }
 
@@ -106,6 +190,13 @@ func NewChainPorter(cfg *ChainPorterConfig) *ChainPorter {
 		cfg:         cfg,
 		exportReqs:  make(chan Parcel),
 		subscribers: subscribers,
+		feeBumpHandlers: make(
+			map[chainhash.Hash]*feeBumpHandler,
+		),
+		cancelHandlers: make(
+			map[chainhash.Hash]*cancelHandler,
+		),
+		idempotencyKeys: fn.NewSet[string](),
 		ContextGuard: &fn.ContextGuard{
 			DefaultTimeout: tapgarden.DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -139,6 +230,32 @@ func (p *ChainPorter) Start() error {
 		// by converting the outbound parcels into pending parcels.
 		for idx := range outboundParcels {
 			outboundParcel := outboundParcels[idx]
+
+			// Parcels that were taken over by another node are
+			// left to that node.
+			claimed, err := p.claimedElsewhere(ctx, outboundParcel)
+			if err != nil {
+				startErr = err
+				return
+			}
+			if claimed {
+				log.Infof("Not resuming transfer with "+
+					"anchor_txid=%v claimed by another "+
+					"node",
+					outboundParcel.AnchorTx.TxHash())
+				continue
+			}
+
+			// Staged parcels are only broadcast once they're
+			// explicitly released, their inputs stay leased until
+			// then.
+			if outboundParcel.Staged {
+				log.Infof("Not resuming staged transfer with "+
+					"anchor_txid=%v",
+					outboundParcel.AnchorTx.TxHash())
+				continue
+			}
+
 			log.Infof("Attempting to resume delivery for "+
 				"anchor_txid=%v",
 				outboundParcel.AnchorTx.TxHash().String())
@@ -148,6 +265,41 @@ func (p *ChainPorter) Start() error {
 			// the channel and attempt to deliver them.
 			p.exportReqs <- NewPendingParcel(outboundParcel)
 		}
+
+		// Proof deliveries that were still in progress when their
+		// transfer was completed are resumed in the background.
+		if err := p.resumeProofDeliveries(ctx); err != nil {
+			startErr = err
+			return
+		}
+
+		// Receivers that didn't acknowledge their proof chain before
+		// we were shut down might still request missing proofs.
+		if err := p.resumeProofGapServes(ctx); err != nil {
+			startErr = err
+			return
+		}
+
+		// Confirmed transfers that weren't buried deep enough yet are
+		// watched for re-orgs again.
+		if err := p.resumeReOrgWatches(ctx); err != nil {
+			startErr = err
+			return
+		}
+
+		// If configured, the fee rate of transfers that don't confirm
+		// is escalated in the background.
+		if p.cfg.FeeEscalation != nil {
+			p.Wg.Add(1)
+			go p.escalateFees()
+		}
+
+		// If configured, anchor outputs that only anchor spent assets
+		// are swept back into the wallet in the background.
+		if p.cfg.AnchorSweep != nil {
+			p.Wg.Add(1)
+			go p.sweepAnchors()
+		}
 	})
 
 	return startErr
@@ -185,6 +337,30 @@ func (p *ChainPorter) RequestShipment(req Parcel) (*OutboundParcel, error) {
 		return nil, fmt.Errorf("failed to validate parcel: %w", err)
 	}
 
+	// Make sure we'll be able to deliver the proofs to the receivers
+	// before we lock any coins.
+	if addrParcel, ok := req.(*AddressParcel); ok {
+		err := addrParcel.checkCouriers(p.cfg.ProofCourierCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the same send was already requested, we return the
+		// existing transfer instead of starting a new one. Otherwise
+		// we hold on to the key until the transfer is stored.
+		if addrParcel.idempotencyKey != "" {
+			key := addrParcel.idempotencyKey
+			existing, err := p.reserveIdempotencyKey(addrParcel)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				return existing, nil
+			}
+			defer p.releaseIdempotencyKey(key)
+		}
+	}
+
 	if !fn.SendOrQuit(p.exportReqs, req, p.Quit) {
 		return nil, fmt.Errorf("ChainPorter shutting down")
 	}
@@ -201,109 +377,1347 @@ func (p *ChainPorter) RequestShipment(req Parcel) (*OutboundParcel, error) {
 	}
 }
 
-// assetsPorter is the main goroutine of the ChainPorter. This takes in incoming
-// requests, and attempt to complete a transfer. A response is sent back to the
-// caller if a transfer can be completed. Otherwise, an error is returned.
-func (p *ChainPorter) assetsPorter() {
-	defer p.Wg.Done()
+// reserveIdempotencyKey reserves the idempotency key of the given send
+// request. If a transfer was already requested with the key within the
+// retention window, it is returned instead and the key isn't reserved. An
+// error is returned if another request with the same key is still being
+// processed, or if the existing transfer doesn't pay the destination addresses
+// of the request.
+func (p *ChainPorter) reserveIdempotencyKey(
+	req *AddressParcel) (*OutboundParcel, error) {
 
-	for {
-		select {
-		case req := <-p.exportReqs:
-			// The request either has a destination address we want
-			// to send to, or a send package is already initialized.
-			sendPkg := req.pkg()
+	key := req.idempotencyKey
 
-			// Advance the state machine for this package as far as
-			// possible in its own goroutine. The status will be
-			// reported through the different channels of the send
-			// package.
-			go p.advanceState(sendPkg, req.kit())
+	p.idempotencyMtx.Lock()
+	defer p.idempotencyMtx.Unlock()
 
-		case <-p.Quit:
-			return
+	if p.idempotencyKeys.Contains(key) {
+		return nil, fmt.Errorf("send with idempotency key %q is "+
+			"already in progress", key)
+	}
+
+	retention := p.cfg.IdempotencyKeyRetention
+	if retention == 0 {
+		retention = DefaultIdempotencyKeyRetention
+	}
+
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+	existing, err := p.cfg.ExportLog.QueryParcelByIdempotencyKey(
+		ctx, key, time.Now().Add(-retention),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query transfer by "+
+			"idempotency key: %w", err)
+	}
+	if existing != nil {
+		// A key can only be re-used for the same request, otherwise
+		// the client would be told that a different send succeeded.
+		if err := req.matchesTransfer(existing); err != nil {
+			return nil, fmt.Errorf("idempotency key %q was used "+
+				"for a different send: %w", key, err)
 		}
+
+		log.Infof("Returning existing transfer_txid=%v for send with "+
+			"idempotency key %q", existing.AnchorTx.TxHash(), key)
+
+		return existing, nil
 	}
+
+	p.idempotencyKeys.Add(key)
+
+	return nil, nil
 }
 
-// advanceState advances the state machine.
-//
-// NOTE: This method MUST be called as a goroutine.
-func (p *ChainPorter) advanceState(pkg *sendPackage, kit *parcelKit) {
-	// Continue state transitions whilst state complete has not yet
-	// been reached.
-	for pkg.SendState < SendStateComplete {
-		log.Infof("ChainPorter executing state: %v",
-			pkg.SendState)
+// releaseIdempotencyKey releases the given idempotency key after its send
+// request was processed.
+func (p *ChainPorter) releaseIdempotencyKey(key string) {
+	p.idempotencyMtx.Lock()
+	defer p.idempotencyMtx.Unlock()
 
-		// Before we attempt a state transition, make sure that
-		// we aren't trying to shut down.
-		select {
-		case <-p.Quit:
-			return
+	p.idempotencyKeys.Remove(key)
+}
 
-		default:
+// EstimateShipment previews the send of the given address parcel. The assets to
+// spend are selected and the virtual transaction is funded as for a real send,
+// but nothing is signed, stored or broadcast and the selected inputs aren't
+// leased. The chain fee of the anchor transaction is estimated at the parcel's
+// fee rate, or the estimated fee rate if it doesn't specify one.
+func (p *ChainPorter) EstimateShipment(
+	req *AddressParcel) (*SendEstimate, error) {
+
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate parcel: %w", err)
+	}
+
+	if err := req.checkCouriers(p.cfg.ProofCourierCfg); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+
+	feeRate := req.feeRate
+	if feeRate == 0 {
+		feeRate, err = p.cfg.ChainBridge.EstimateFee(
+			ctx, tapscript.SendConfTarget,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee: %w",
+				err)
 		}
+	}
 
-		updatedPkg, err := p.stateStep(*pkg)
+	// A fee bump anchor is reserved if the daemon is configured to always
+	// do so, or if it was requested for this transfer.
+	feeBumpAnchor := p.cfg.FeeBumpAnchor || req.feeBumpAnchor
+
+	return p.cfg.AssetWallet.EstimateAddressSend(
+		ctx, req.maxInputs, req.inputs, feeRate, feeBumpAnchor,
+		req.sendAddrs()...,
+	)
+}
+
+// ReleaseStagedParcel releases the staged parcel with the given anchor
+// transaction ID for broadcast. The parcel then continues its delivery through
+// the same path a parcel resumed after a restart takes.
+func (p *ChainPorter) ReleaseStagedParcel(
+	anchorTXID chainhash.Hash) (*OutboundParcel, error) {
+
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+	outboundParcels, err := p.cfg.ExportLog.PendingParcels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch pending parcels: %w",
+			err)
+	}
+
+	var stagedParcel *OutboundParcel
+	for idx := range outboundParcels {
+		outboundParcel := outboundParcels[idx]
+		if outboundParcel.AnchorTx.TxHash() != anchorTXID {
+			continue
+		}
+
+		if !outboundParcel.Staged {
+			return nil, fmt.Errorf("transfer with anchor txid %v "+
+				"is not staged", anchorTXID)
+		}
+
+		stagedParcel = outboundParcel
+		break
+	}
+	if stagedParcel == nil {
+		return nil, fmt.Errorf("no pending transfer found for anchor "+
+			"txid %v", anchorTXID)
+	}
+
+	claimed, err := p.claimedElsewhere(ctx, stagedParcel)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, fmt.Errorf("%w: anchor txid %v",
+			ErrTransferClaimed, anchorTXID)
+	}
+
+	// We clear the staged flag on disk first, so the transfer is resumed
+	// in case we restart before it confirms.
+	err = p.cfg.ExportLog.UnstageParcel(ctx, anchorTXID)
+	if err != nil {
+		return nil, err
+	}
+	stagedParcel.Staged = false
+
+	// The wallet inputs were leased for the lifetime of the staged
+	// transfer, which ends now that it is broadcast. The claim on the
+	// inputs of a transfer we took over is kept, so the node we took it
+	// over from keeps leaving it alone.
+	if stagedParcel.ClaimID == nil {
+		p.releaseWalletInputs(ctx, stagedParcel)
+	}
+
+	log.Infof("Releasing staged transfer with anchor_txid=%v",
+		anchorTXID)
+
+	return p.RequestShipment(NewPendingParcel(stagedParcel))
+}
+
+// ExportPendingParcel exports the pending parcel with the given anchor
+// transaction ID together with the proofs of its inputs, so another node that
+// is connected to the same lnd node can take over its broadcast and proof
+// delivery. This node keeps the parcel, but refuses to act on it once the other
+// node claimed it.
+func (p *ChainPorter) ExportPendingParcel(
+	anchorTXID chainhash.Hash) (*TransferHandoff, error) {
+
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+
+	parcel, err := p.pendingParcel(ctx, anchorTXID)
+	if err != nil {
+		return nil, err
+	}
+
+	// We can only hand off what we know about after a restart, which
+	// doesn't include the data needed to re-anchor passive assets.
+	for _, out := range parcel.Outputs {
+		if out.Anchor.NumPassiveAssets > 0 {
+			return nil, fmt.Errorf("transfer with anchor txid %v "+
+				"re-anchors passive assets and can't be "+
+				"exported", anchorTXID)
+		}
+	}
+
+	claimed, err := p.claimedElsewhere(ctx, parcel)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, fmt.Errorf("%w: anchor txid %v",
+			ErrTransferClaimed, anchorTXID)
+	}
+
+	inputProofs := make([]proof.Blob, 0, len(parcel.Inputs))
+	for _, input := range parcel.Inputs {
+		locator, err := inputProofLocator(input)
 		if err != nil {
-			kit.errChan <- err
-			log.Errorf("Error evaluating state (%v): %v",
-				pkg.SendState, err)
-			return
+			return nil, err
 		}
 
-		pkg = updatedPkg
+		blob, err := p.cfg.AssetProofs.FetchProof(ctx, locator)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch input proof: "+
+				"%w", err)
+		}
+		inputProofs = append(inputProofs, blob)
 	}
+
+	log.Infof("Exporting pending transfer with anchor_txid=%v",
+		anchorTXID)
+
+	return &TransferHandoff{
+		Parcel:      parcel,
+		InputProofs: inputProofs,
+	}, nil
 }
 
-// waitForTransferTxConf waits for the confirmation of the final transaction
-// within the delta. Once confirmed, the parcel will be marked as delivered on
-// chain, with the goroutine cleaning up its state.
-func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
-	outboundPkg := pkg.OutboundPkg
+// ImportPendingParcel takes over a pending parcel that was exported by another
+// node connected to the same lnd node. The wallet inputs of the anchor
+// transaction are claimed with a new claim ID first, which fails if another
+// node already claimed them. This makes sure only one node acts on the parcel.
+// The parcel is then logged and, unless it is staged, its delivery is resumed.
+func (p *ChainPorter) ImportPendingParcel(
+	handoff *TransferHandoff) (*OutboundParcel, error) {
 
-	txHash := outboundPkg.AnchorTx.TxHash()
-	log.Infof("Waiting for confirmation of transfer_txid=%v", txHash)
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
 
-	confCtx, confCancel := p.WithCtxQuitNoTimeout()
-	confNtfn, errChan, err := p.cfg.ChainBridge.RegisterConfirmationsNtfn(
-		confCtx, &txHash, outboundPkg.AnchorTx.TxOut[0].PkScript, 1,
-		outboundPkg.AnchorTxHeightHint, true, nil,
+	parcel := handoff.Parcel
+	anchorTXID := parcel.AnchorTx.TxHash()
+
+	_, err := p.pendingParcel(ctx, anchorTXID)
+	if err == nil {
+		return nil, fmt.Errorf("transfer with anchor txid %v is "+
+			"already known", anchorTXID)
+	}
+
+	// The leases on the wallet inputs are what we claim the parcel with,
+	// so there need to be some.
+	claimInputs := walletInputs(parcel)
+	if len(claimInputs) == 0 {
+		return nil, fmt.Errorf("transfer with anchor txid %v has no "+
+			"wallet inputs to claim", anchorTXID)
+	}
+
+	// The input assets need to be known before we can log the parcel, so
+	// we import the proofs of those we don't know yet.
+	headerVerifier := tapgarden.GenHeaderVerifier(ctx, p.cfg.ChainBridge)
+	for idx, input := range parcel.Inputs {
+		locator, err := inputProofLocator(input)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = p.cfg.AssetProofs.FetchProof(ctx, locator)
+		switch {
+		case err == nil:
+			continue
+
+		case !errors.Is(err, proof.ErrProofNotFound):
+			return nil, fmt.Errorf("unable to fetch input proof: "+
+				"%w", err)
+		}
+
+		err = p.cfg.AssetProofs.ImportProofs(
+			ctx, headerVerifier, p.cfg.GroupVerifier, false,
+			&proof.AnnotatedProof{
+				Locator: locator,
+				Blob:    handoff.InputProofs[idx],
+			},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to import input proof: "+
+				"%w", err)
+		}
+	}
+
+	var claimID [32]byte
+	if _, err := rand.Read(claimID[:]); err != nil {
+		return nil, err
+	}
+	err = p.cfg.Wallet.ClaimOutputs(
+		ctx, claimID, claimInputs, defaultBroadcastCoinLeaseDuration,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to register for package tx conf: %w",
+		return nil, fmt.Errorf("%w: unable to claim anchor txid %v: %v",
+			ErrTransferClaimed, anchorTXID, err)
+	}
+	parcel.ClaimID = &claimID
+
+	err = p.cfg.ExportLog.LogPendingParcel(
+		ctx, parcel, defaultWalletLeaseIdentifier,
+		time.Now().Add(defaultBroadcastCoinLeaseDuration),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to log imported transfer: %w",
 			err)
 	}
 
-	// Launch a goroutine that'll notify us when the transaction confirms.
-	defer confCancel()
+	log.Infof("Imported pending transfer with anchor_txid=%v",
+		anchorTXID)
 
-	var confEvent *chainntnfs.TxConfirmation
-	select {
-	case confEvent = <-confNtfn.Confirmed:
-		log.Debugf("Got chain confirmation: %v", confEvent.Tx.TxHash())
-		pkg.TransferTxConfEvent = confEvent
-		pkg.SendState = SendStateStoreProofs
+	if parcel.Staged {
+		return parcel, nil
+	}
 
-	case err := <-errChan:
-		return fmt.Errorf("error whilst waiting for package tx "+
-			"confirmation: %w", err)
+	return p.RequestShipment(NewPendingParcel(parcel))
+}
+
+// pendingParcel returns the pending parcel with the given anchor transaction
+// ID.
+func (p *ChainPorter) pendingParcel(ctx context.Context,
+	anchorTXID chainhash.Hash) (*OutboundParcel, error) {
+
+	outboundParcels, err := p.cfg.ExportLog.PendingParcels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch pending parcels: %w",
+			err)
+	}
+
+	for idx := range outboundParcels {
+		if outboundParcels[idx].AnchorTx.TxHash() == anchorTXID {
+			return outboundParcels[idx], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no pending transfer found for anchor txid %v",
+		anchorTXID)
+}
+
+// inputProofLocator returns the locator of the proof of the given input.
+func inputProofLocator(input TransferInput) (proof.Locator, error) {
+	scriptKey, err := btcec.ParsePubKey(input.ScriptKey[:])
+	if err != nil {
+		return proof.Locator{}, fmt.Errorf("error parsing script key: "+
+			"%w", err)
+	}
+
+	id := input.ID
+	outPoint := input.OutPoint
+
+	return proof.Locator{
+		AssetID:   &id,
+		ScriptKey: *scriptKey,
+		OutPoint:  &outPoint,
+	}, nil
+}
+
+// claimedElsewhere returns true if any wallet input of the given parcel's
+// anchor transaction is leased with a claim ID other than the parcel's own,
+// which means another node took over the parcel. Once the anchor transaction
+// confirms, its inputs and therefore the claims are gone, but by then the only
+// thing left to do is delivering the proofs, which both nodes may safely do.
+func (p *ChainPorter) claimedElsewhere(ctx context.Context,
+	parcel *OutboundParcel) (bool, error) {
+
+	claims, err := p.cfg.Wallet.OutputClaims(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to list output claims: %w",
+			err)
+	}
+
+	for _, op := range walletInputs(parcel) {
+		claimID, ok := claims[op]
+		if !ok {
+			continue
+		}
+
+		if parcel.ClaimID == nil || *parcel.ClaimID != claimID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// walletInputs returns the inputs of the given parcel's anchor transaction that
+// don't anchor any assets.
+func walletInputs(parcel *OutboundParcel) []wire.OutPoint {
+	assetInputs := fn.NewSet[wire.OutPoint]()
+	for _, in := range parcel.Inputs {
+		assetInputs.Add(in.OutPoint)
+	}
+
+	var inputs []wire.OutPoint
+	for _, txIn := range parcel.AnchorTx.TxIn {
+		op := txIn.PreviousOutPoint
+		if !assetInputs.Contains(op) {
+			inputs = append(inputs, op)
+		}
+	}
+
+	return inputs
+}
+
+// leaseWalletInputs leases the inputs of the given parcel's anchor transaction
+// that don't anchor any assets from the wallet for the given duration.
+func (p *ChainPorter) leaseWalletInputs(ctx context.Context,
+	parcel *OutboundParcel, duration time.Duration) error {
+
+	for _, op := range walletInputs(parcel) {
+		err := p.cfg.Wallet.LeaseOutput(ctx, op, duration)
+		if err != nil {
+			return fmt.Errorf("unable to lease wallet input %v: %w",
+				op, err)
+		}
+	}
+
+	return nil
+}
+
+// releaseWalletInputs releases the leases the wallet holds on the inputs of the
+// given parcel's anchor transaction that don't anchor any assets.
+func (p *ChainPorter) releaseWalletInputs(ctx context.Context,
+	parcel *OutboundParcel) {
+
+	for _, op := range walletInputs(parcel) {
+		if err := p.cfg.Wallet.ReleaseOutput(ctx, op); err != nil {
+			log.Warnf("Unable to release wallet input %v of "+
+				"transfer_txid=%v: %v", op,
+				parcel.AnchorTx.TxHash(), err)
+		}
+	}
+}
+
+// feeBumpReq is a request to replace the anchor transaction of a pending
+// transfer with one that pays a higher fee rate.
+type feeBumpReq struct {
+	feeRate chainfee.SatPerKWeight
+
+	// escalation is true if the fee bump is an automatic fee escalation.
+	escalation bool
+
+	respChan chan *OutboundParcel
+
+	errChan chan error
+}
+
+// feeBumpHandler passes fee bump requests to the goroutine that waits for the
+// anchor transaction of a pending transfer to confirm. The done channel is
+// closed once the goroutine no longer accepts requests.
+type feeBumpHandler struct {
+	feeBumpReqs chan *feeBumpReq
+
+	done chan struct{}
+}
+
+// BumpParcelFee replaces the unconfirmed anchor transaction of the pending
+// parcel with the given anchor transaction ID with one that pays the given fee
+// rate. The replacement spends the same inputs and creates the same outputs,
+// with the additional fee being deducted from the wallet's change output. The
+// parcel then continues its delivery with the replacement. This is only
+// possible while we're waiting for the anchor transaction to confirm and as
+// long as the funded anchor PSBT is known, which isn't the case for parcels
+// that were handed off to this node by another one.
+func (p *ChainPorter) BumpParcelFee(anchorTXID chainhash.Hash,
+	feeRate chainfee.SatPerKWeight) (*OutboundParcel, error) {
+
+	return p.requestFeeBump(anchorTXID, feeRate, false)
+}
+
+// BumpParcelFeeCPFP bumps the fee of the unconfirmed anchor transaction of the
+// pending parcel with the given anchor transaction ID to the given fee rate by
+// publishing a child transaction that spends its fee bump anchor output. As
+// opposed to BumpParcelFee, this neither requires a change output nor the
+// funded anchor PSBT, and the parcel keeps waiting for the same anchor
+// transaction. The fee bump anchor output of each version of the anchor
+// transaction can only be spent once. The returned parcel references the
+// published child transaction.
+func (p *ChainPorter) BumpParcelFeeCPFP(anchorTXID chainhash.Hash,
+	feeRate chainfee.SatPerKWeight) (*OutboundParcel, error) {
+
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+
+	parcel, err := p.pendingParcel(ctx, anchorTXID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case parcel.Staged:
+		return nil, fmt.Errorf("transfer with anchor txid %v is "+
+			"staged and wasn't broadcast yet", anchorTXID)
+
+	case parcel.FeeBumpAnchor == nil:
+		return nil, fmt.Errorf("transfer with anchor txid %v has no "+
+			"fee bump anchor output", anchorTXID)
+
+	case parcel.FeeBumpChildTXID != nil:
+		return nil, fmt.Errorf("fee bump anchor output of transfer "+
+			"with anchor txid %v is already spent by child txid %v",
+			anchorTXID, parcel.FeeBumpChildTXID)
+	}
+
+	childTx, err := p.cfg.AssetWallet.CreateFeeBumpChild(
+		ctx, parcel.AnchorTx, parcel.ChainFees, parcel.FeeBumpAnchor,
+		feeRate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create child tx: %w", err)
+	}
+	childTXID := childTx.TxHash()
+
+	// We record the child before publishing it, so the fee bump anchor
+	// output is never reported as unspent while the child is in flight.
+	err = p.cfg.ExportLog.LogFeeBumpChild(ctx, anchorTXID, &childTXID)
+	if err != nil {
+		p.releaseFeeBumpChildInputs(
+			ctx, childTx, anchorTXID, parcel.FeeBumpAnchor,
+		)
+
+		return nil, fmt.Errorf("unable to log child tx: %w", err)
+	}
+
+	log.Infof("Bumping fee of transfer_txid=%v to %v with child_txid=%v",
+		anchorTXID, feeRate, childTXID)
+
+	err = p.cfg.ChainBridge.PublishTransaction(ctx, childTx)
+	if err != nil {
+		logErr := p.cfg.ExportLog.LogFeeBumpChild(ctx, anchorTXID, nil)
+		if logErr != nil {
+			log.Errorf("Unable to remove child tx of "+
+				"transfer_txid=%v: %v", anchorTXID, logErr)
+		}
+		p.releaseFeeBumpChildInputs(
+			ctx, childTx, anchorTXID, parcel.FeeBumpAnchor,
+		)
+
+		return nil, fmt.Errorf("unable to publish child tx: %w", err)
+	}
+
+	parcel.FeeBumpChildTXID = &childTXID
+
+	return parcel, nil
+}
+
+// releaseFeeBumpChildInputs releases the leases the wallet holds on the inputs
+// of the given child transaction, except for the fee bump anchor output of the
+// given anchor transaction, which the wallet doesn't know about.
+func (p *ChainPorter) releaseFeeBumpChildInputs(ctx context.Context,
+	childTx *wire.MsgTx, anchorTXID chainhash.Hash,
+	feeBumpAnchor *FeeBumpAnchor) {
+
+	anchorPoint := wire.OutPoint{
+		Hash:  anchorTXID,
+		Index: feeBumpAnchor.OutputIndex,
+	}
+	for _, txIn := range childTx.TxIn {
+		op := txIn.PreviousOutPoint
+		if op == anchorPoint {
+			continue
+		}
+
+		if err := p.cfg.Wallet.ReleaseOutput(ctx, op); err != nil {
+			log.Warnf("Unable to release wallet input %v of "+
+				"child_txid=%v: %v", op, childTx.TxHash(), err)
+		}
+	}
+}
+
+// requestFeeBump passes a request to replace the anchor transaction of the
+// pending parcel with the given anchor transaction ID with one that pays the
+// given fee rate to the goroutine waiting for it to confirm.
+func (p *ChainPorter) requestFeeBump(anchorTXID chainhash.Hash,
+	feeRate chainfee.SatPerKWeight, escalation bool) (*OutboundParcel,
+	error) {
+
+	handler, ok := p.feeBumpHandler(anchorTXID)
+	if !ok {
+		return nil, fmt.Errorf("transfer with anchor txid %v isn't "+
+			"waiting for a replaceable anchor tx to confirm",
+			anchorTXID)
+	}
+
+	req := &feeBumpReq{
+		feeRate:    feeRate,
+		escalation: escalation,
+		respChan:   make(chan *OutboundParcel, 1),
+		errChan:    make(chan error, 1),
+	}
+	select {
+	case handler.feeBumpReqs <- req:
+	case <-handler.done:
+		return nil, fmt.Errorf("transfer with anchor txid %v is no "+
+			"longer waiting for confirmation", anchorTXID)
+	case <-p.Quit:
+		return nil, fmt.Errorf("ChainPorter shutting down")
+	}
+
+	select {
+	case err := <-req.errChan:
+		return nil, err
+
+	case resp := <-req.respChan:
+		return resp, nil
+
+	case <-p.Quit:
+		return nil, fmt.Errorf("ChainPorter shutting down")
+	}
+}
+
+// feeBumpHandler returns the handler for fee bump requests of the pending
+// transfer with the given anchor transaction ID, if it is waiting for its
+// anchor transaction to confirm.
+func (p *ChainPorter) feeBumpHandler(
+	anchorTXID chainhash.Hash) (*feeBumpHandler, bool) {
+
+	p.feeBumpMtx.Lock()
+	defer p.feeBumpMtx.Unlock()
+
+	handler, ok := p.feeBumpHandlers[anchorTXID]
+	return handler, ok
+}
+
+// registerFeeBumpHandler registers a handler for fee bump requests of the
+// pending transfer with the given anchor transaction ID. The returned function
+// must be called once no more requests are accepted.
+func (p *ChainPorter) registerFeeBumpHandler(
+	anchorTXID chainhash.Hash) (*feeBumpHandler, func()) {
+
+	handler := &feeBumpHandler{
+		feeBumpReqs: make(chan *feeBumpReq),
+		done:        make(chan struct{}),
+	}
+
+	p.feeBumpMtx.Lock()
+	p.feeBumpHandlers[anchorTXID] = handler
+	p.feeBumpMtx.Unlock()
+
+	return handler, func() {
+		p.feeBumpMtx.Lock()
+		delete(p.feeBumpHandlers, anchorTXID)
+		p.feeBumpMtx.Unlock()
+
+		close(handler.done)
+	}
+}
+
+// cancelReq is a request to cancel a pending transfer whose anchor transaction
+// didn't confirm yet.
+type cancelReq struct {
+	respChan chan *OutboundParcel
+
+	errChan chan error
+}
+
+// cancelHandler passes cancel requests to the goroutine that waits for the
+// anchor transaction of a pending transfer to confirm. The done channel is
+// closed once the goroutine no longer accepts requests.
+type cancelHandler struct {
+	cancelReqs chan *cancelReq
+
+	done chan struct{}
+}
+
+// CancelPendingParcel cancels the pending parcel with the given anchor
+// transaction ID, for example because its anchor transaction pays too little
+// in fees to ever confirm. The parcel is moved to its terminal cancelled state,
+// which stops the porter from waiting for its anchor transaction, and its asset
+// and wallet inputs are released so they can be selected again. An error
+// wrapping ErrTransferConfirmed is returned if the anchor transaction already
+// confirmed.
+//
+// NOTE: Cancelling a transfer doesn't evict its anchor transaction from the
+// mempool. If it still confirms before its inputs are spent otherwise, the
+// transfer needs to be recovered manually.
+func (p *ChainPorter) CancelPendingParcel(
+	anchorTXID chainhash.Hash) (*OutboundParcel, error) {
+
+	// If we're waiting for the anchor transaction to confirm, the
+	// goroutine doing so needs to stop as well, so we let it cancel the
+	// transfer.
+	handler, ok := p.cancelHandler(anchorTXID)
+	if ok {
+		req := &cancelReq{
+			respChan: make(chan *OutboundParcel, 1),
+			errChan:  make(chan error, 1),
+		}
+		select {
+		case handler.cancelReqs <- req:
+			select {
+			case err := <-req.errChan:
+				return nil, err
+
+			case resp := <-req.respChan:
+				return resp, nil
+
+			case <-p.Quit:
+				return nil, fmt.Errorf("ChainPorter shutting " +
+					"down")
+			}
+
+		// The anchor transaction might have confirmed or was replaced
+		// in the meantime, so we can't cancel the transfer directly.
+		case <-handler.done:
+			return nil, fmt.Errorf("transfer with anchor txid %v "+
+				"is no longer waiting for confirmation",
+				anchorTXID)
+
+		case <-p.Quit:
+			return nil, fmt.Errorf("ChainPorter shutting down")
+		}
+	}
+
+	// Otherwise, the transfer is either staged or its delivery isn't
+	// active, in which case we can cancel it directly.
+	ctx, cancel := p.WithCtxQuit()
+	defer cancel()
+
+	parcel, err := p.pendingParcel(ctx, anchorTXID)
+	if err != nil {
+		// A transfer that isn't pending either confirmed, was
+		// cancelled already or is unknown. The export log refuses to
+		// cancel any of them and tells us which one it is.
+		cancelErr := p.cfg.ExportLog.CancelParcel(ctx, anchorTXID)
+		if cancelErr != nil {
+			return nil, cancelErr
+		}
+
+		return nil, err
+	}
+
+	if err := p.cancelParcel(ctx, parcel); err != nil {
+		return nil, err
+	}
+
+	return parcel, nil
+}
+
+// cancelParcel marks the given pending parcel as cancelled, which releases the
+// leases on its asset inputs, and releases the wallet inputs of its anchor
+// transaction. Subscribers are notified about the cancellation.
+func (p *ChainPorter) cancelParcel(ctx context.Context,
+	parcel *OutboundParcel) error {
+
+	anchorTXID := parcel.AnchorTx.TxHash()
+
+	// A transfer that was taken over by another node is left to that
+	// node.
+	claimed, err := p.claimedElsewhere(ctx, parcel)
+	if err != nil {
+		return err
+	}
+	if claimed {
+		return fmt.Errorf("%w: anchor txid %v", ErrTransferClaimed,
+			anchorTXID)
+	}
+
+	if err := p.cfg.ExportLog.CancelParcel(ctx, anchorTXID); err != nil {
+		return err
+	}
+	p.releaseWalletInputs(ctx, parcel)
+
+	log.Infof("Cancelled transfer with anchor_txid=%v", anchorTXID)
+
+	p.publishSubscriberEvent(NewTransferCancelledEvent(anchorTXID))
+
+	return nil
+}
+
+// cancelHandler returns the handler for cancel requests of the pending transfer
+// with the given anchor transaction ID, if it is waiting for its anchor
+// transaction to confirm.
+func (p *ChainPorter) cancelHandler(
+	anchorTXID chainhash.Hash) (*cancelHandler, bool) {
+
+	p.cancelMtx.Lock()
+	defer p.cancelMtx.Unlock()
+
+	handler, ok := p.cancelHandlers[anchorTXID]
+	return handler, ok
+}
+
+// registerCancelHandler registers a handler for cancel requests of the pending
+// transfer with the given anchor transaction ID. The returned function must be
+// called once no more requests are accepted.
+func (p *ChainPorter) registerCancelHandler(
+	anchorTXID chainhash.Hash) (*cancelHandler, func()) {
+
+	handler := &cancelHandler{
+		cancelReqs: make(chan *cancelReq),
+		done:       make(chan struct{}),
+	}
+
+	p.cancelMtx.Lock()
+	p.cancelHandlers[anchorTXID] = handler
+	p.cancelMtx.Unlock()
+
+	return handler, func() {
+		p.cancelMtx.Lock()
+		delete(p.cancelHandlers, anchorTXID)
+		p.cancelMtx.Unlock()
+
+		close(handler.done)
+	}
+}
+
+// bumpAnchorTxFee replaces the anchor transaction of the package with one that
+// pays the given fee rate. The replacement is stored and broadcast, and the
+// package is updated to reference it, so the delivery of the proofs continues
+// against the replacement once it confirms. If escalation is set, the fee bump
+// is counted as an automatic fee escalation of the package.
+func (p *ChainPorter) bumpAnchorTxFee(pkg *sendPackage,
+	feeRate chainfee.SatPerKWeight, escalation bool) (*OutboundParcel,
+	error) {
+
+	ctx, cancel := p.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	oldParcel := pkg.OutboundPkg
+	oldTXID := oldParcel.AnchorTx.TxHash()
+
+	claimed, err := p.claimedElsewhere(ctx, oldParcel)
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, fmt.Errorf("%w: anchor txid %v",
+			ErrTransferClaimed, oldTXID)
+	}
+
+	anchorTx, err := p.cfg.AssetWallet.BumpAnchorTxFee(
+		ctx, pkg.AnchorTx, feeRate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create replacement anchor "+
+			"tx: %w", err)
+	}
+	newTXID := anchorTx.FinalTx.TxHash()
+
+	log.Infof("Replacing transfer_txid=%v with txid=%v paying %d sats "+
+		"in fees", oldTXID, newTXID, anchorTx.ChainFees)
+
+	// We store the replacement before broadcasting it, so we resume the
+	// transfer with the right anchor transaction after a restart. If the
+	// replacement isn't accepted, we go back to the original.
+	err = p.cfg.ExportLog.ReplaceParcelAnchorTx(
+		ctx, oldTXID, anchorTx, escalation,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to store replacement anchor "+
+			"tx: %w", err)
+	}
+
+	err = p.cfg.ChainBridge.PublishTransaction(ctx, anchorTx.FinalTx)
+	if err != nil {
+		revertErr := p.cfg.ExportLog.ReplaceParcelAnchorTx(
+			ctx, newTXID, pkg.AnchorTx, false,
+		)
+		if revertErr != nil {
+			log.Errorf("Unable to restore anchor tx of "+
+				"transfer_txid=%v: %v", oldTXID, revertErr)
+		}
+
+		return nil, fmt.Errorf("unable to publish replacement anchor "+
+			"tx: %w", err)
+	}
+
+	// The replaced version can still confirm instead of the replacement,
+	// so we keep watching it.
+	numReplaced := len(oldParcel.ReplacedAnchorTxs)
+	replacedTxs := make([]*AnchorTransaction, 0, numReplaced+1)
+	replacedTxs = append(replacedTxs, oldParcel.ReplacedAnchorTxs...)
+	replacedTxs = append(replacedTxs, pkg.AnchorTx)
+
+	newParcel := switchAnchorTx(pkg, anchorTx)
+	newParcel.ReplacedAnchorTxs = replacedTxs
+
+	var escalationNum uint32
+	if escalation {
+		newParcel.FeeEscalations++
+		escalationNum = newParcel.FeeEscalations
+	}
+
+	p.publishSubscriberEvent(NewTransferFeeBumpedEvent(
+		oldTXID, newTXID, feeRate, anchorTx.ChainFees, escalationNum,
+	))
+
+	return newParcel, nil
+}
+
+// restoreAnchorTx is called once a replaced version of the package's anchor
+// transaction confirmed instead of the current one. The confirmed version is
+// stored as the anchor transaction of the transfer again, and the package is
+// updated to reference it.
+func (p *ChainPorter) restoreAnchorTx(pkg *sendPackage,
+	anchorTx *AnchorTransaction) error {
+
+	ctx, cancel := p.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	currentTXID := pkg.OutboundPkg.AnchorTx.TxHash()
+	confirmedTXID := anchorTx.FinalTx.TxHash()
+
+	log.Infof("Replaced anchor txid=%v of transfer_txid=%v confirmed, "+
+		"restoring it", confirmedTXID, currentTXID)
+
+	err := p.cfg.ExportLog.ReplaceParcelAnchorTx(
+		ctx, currentTXID, anchorTx, false,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to restore confirmed anchor tx: %w",
+			err)
+	}
+
+	restoredParcel := switchAnchorTx(pkg, anchorTx)
+	restoredParcel.ReplacedAnchorTxs = nil
+
+	return nil
+}
+
+// switchAnchorTx updates the package to reference the given version of its
+// anchor transaction and returns the updated outbound parcel.
+func switchAnchorTx(pkg *sendPackage,
+	anchorTx *AnchorTransaction) *OutboundParcel {
+
+	oldParcel := pkg.OutboundPkg
+	newTXID := anchorTx.FinalTx.TxHash()
+
+	// All versions of the anchor transaction have their outputs at the
+	// same indexes, so only the outpoints change.
+	newParcel := *oldParcel
+	newParcel.AnchorTx = anchorTx.FinalTx
+	newParcel.ChainFees = anchorTx.ChainFees
+	newParcel.AnchorPsbt = anchorTx.FundedPsbt
+
+	// A child transaction that bumped the fee of the previous version
+	// doesn't spend the fee bump anchor output of this one.
+	newParcel.FeeBumpChildTXID = nil
+	newParcel.Outputs = make([]TransferOutput, len(oldParcel.Outputs))
+	copy(newParcel.Outputs, oldParcel.Outputs)
+	for idx := range newParcel.Outputs {
+		newParcel.Outputs[idx].Anchor.OutPoint.Hash = newTXID
+	}
+
+	pkg.OutboundPkg = &newParcel
+	pkg.AnchorTx = anchorTx
+
+	return &newParcel
+}
+
+// assetsPorter is the main goroutine of the ChainPorter. This takes in incoming
+// requests, and attempt to complete a transfer. A response is sent back to the
+// caller if a transfer can be completed. Otherwise, an error is returned.
+func (p *ChainPorter) assetsPorter() {
+	defer p.Wg.Done()
+
+	for {
+		select {
+		case req := <-p.exportReqs:
+			// The request either has a destination address we want
+			// to send to, or a send package is already initialized.
+			sendPkg := req.pkg()
+
+			// Advance the state machine for this package as far as
+			// possible in its own goroutine. The status will be
+			// reported through the different channels of the send
+			// package.
+			go p.advanceState(sendPkg, req.kit())
+
+		case <-p.Quit:
+			return
+		}
+	}
+}
+
+// advanceState advances the state machine.
+//
+// NOTE: This method MUST be called as a goroutine.
+func (p *ChainPorter) advanceState(pkg *sendPackage, kit *parcelKit) {
+	// Continue state transitions whilst a terminal state has not yet
+	// been reached.
+	for !pkg.SendState.IsTerminal() {
+		log.Infof("ChainPorter executing state: %v",
+			pkg.SendState)
+
+		// Before we attempt a state transition, make sure that
+		// we aren't trying to shut down.
+		select {
+		case <-p.Quit:
+			return
+
+		default:
+		}
+
+		updatedPkg, err := p.stateStep(*pkg)
+		if err != nil {
+			kit.errChan <- err
+			log.Errorf("Error evaluating state (%v): %v",
+				pkg.SendState, err)
+			return
+		}
+
+		pkg = updatedPkg
+	}
+}
+
+// waitForTransferTxConf waits for the confirmation of the final transaction
+// within the delta. Once confirmed, the parcel will be marked as delivered on
+// chain, with the goroutine cleaning up its state.
+func (p *ChainPorter) waitForTransferTxConf(pkg *sendPackage) error {
+	outboundPkg := pkg.OutboundPkg
+
+	txHash := outboundPkg.AnchorTx.TxHash()
+	log.Infof("Waiting for confirmation of transfer_txid=%v", txHash)
+
+	confCtx, confCancel := p.WithCtxQuitNoTimeout()
+	confNtfn, errChan, err := p.cfg.ChainBridge.RegisterConfirmationsNtfn(
+		confCtx, &txHash, outboundPkg.AnchorTx.TxOut[0].PkScript, 1,
+		outboundPkg.AnchorTxHeightHint, true, nil,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register for package tx conf: %w",
+			err)
+	}
+
+	// Launch a goroutine that'll notify us when the transaction confirms.
+	defer confCancel()
+
+	// While we wait, the anchor transaction can be replaced with one that
+	// pays a higher fee rate. That requires the funded anchor PSBT, which
+	// isn't known for parcels that were handed off to us by another node.
+	var feeBumpReqs <-chan *feeBumpReq
+	if pkg.AnchorTx != nil && pkg.AnchorTx.FundedPsbt != nil {
+		handler, unregister := p.registerFeeBumpHandler(txHash)
+		defer unregister()
+
+		feeBumpReqs = handler.feeBumpReqs
+	}
+
+	// Until the anchor transaction confirms, the transfer can be
+	// cancelled.
+	cancelHandler, unregisterCancel := p.registerCancelHandler(txHash)
+	defer unregisterCancel()
+
+	// A version of the anchor transaction that was replaced by a fee bump
+	// can still confirm instead of the current one.
+	replacedConfs, replacedErrs, err := p.watchReplacedAnchorTxs(
+		confCtx, pkg,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register for replaced package tx "+
+			"conf: %w", err)
+	}
+
+	// The anchor transaction will never confirm if one of its inputs is
+	// spent by a different transaction, so we watch for that as well.
+	doubleSpendChan, err := p.watchAnchorInputs(confCtx, pkg)
+	if err != nil {
+		return fmt.Errorf("unable to watch anchor tx inputs: %w", err)
+	}
+
+	var confEvent *chainntnfs.TxConfirmation
+	select {
+	case confEvent = <-confNtfn.Confirmed:
+		log.Debugf("Got chain confirmation: %v", confEvent.Tx.TxHash())
+
+	case replaced := <-replacedConfs:
+		log.Debugf("Got chain confirmation of replaced anchor tx: %v",
+			replaced.event.Tx.TxHash())
+
+		err := p.restoreAnchorTx(pkg, replaced.anchorTx)
+		if err != nil {
+			return err
+		}
+		confEvent = replaced.event
+
+	case spend := <-doubleSpendChan:
+		return p.abandonTransfer(pkg, spend)
+
+	// If the anchor transaction is replaced, we stay in the same state
+	// and wait for the confirmation of the replacement instead.
+	case req := <-feeBumpReqs:
+		newParcel, err := p.bumpAnchorTxFee(
+			pkg, req.feeRate, req.escalation,
+		)
+		if err != nil {
+			req.errChan <- err
+			return nil
+		}
+
+		req.respChan <- newParcel
+		return nil
+
+	// A cancelled transfer moves to its terminal state. If it can't be
+	// cancelled, we keep waiting for the confirmation instead.
+	case req := <-cancelHandler.cancelReqs:
+		err := p.cancelParcel(confCtx, outboundPkg)
+		if err != nil {
+			req.errChan <- err
+			return nil
+		}
+
+		pkg.SendState = SendStateCancelled
+		req.respChan <- outboundPkg
+		return nil
+
+	case err := <-errChan:
+		return fmt.Errorf("error whilst waiting for package tx "+
+			"confirmation: %w", err)
+
+	case err := <-replacedErrs:
+		return fmt.Errorf("error whilst waiting for replaced package "+
+			"tx confirmation: %w", err)
+
+	case <-confCtx.Done():
+		log.Debugf("Skipping TX confirmation, context done")
+
+	case <-p.Quit:
+		log.Debugf("Skipping TX confirmation, exiting")
+		return nil
+	}
+
+	if confEvent == nil {
+		return fmt.Errorf("got empty package tx confirmation event " +
+			"in batch")
+	}
+
+	pkg.TransferTxConfEvent = confEvent
+	pkg.SendState = SendStateStoreProofs
+
+	return nil
+}
+
+// replacedAnchorTxConf is the confirmation of a version of an anchor
+// transaction that was replaced by a fee bump.
+type replacedAnchorTxConf struct {
+	// anchorTx is the replaced version that confirmed.
+	anchorTx *AnchorTransaction
+
+	// event is the confirmation of the replaced version.
+	event *chainntnfs.TxConfirmation
+}
+
+// watchReplacedAnchorTxs registers for the confirmation of all versions of the
+// package's anchor transaction that were replaced by fee bumps. The returned
+// channels are sent upon once one of them confirms or if watching one of them
+// fails.
+func (p *ChainPorter) watchReplacedAnchorTxs(ctx context.Context,
+	pkg *sendPackage) (<-chan *replacedAnchorTxConf, <-chan error, error) {
+
+	replacedTxs := pkg.OutboundPkg.ReplacedAnchorTxs
+	numReplaced := len(replacedTxs)
+	confChan := make(chan *replacedAnchorTxConf, numReplaced)
+	errChan := make(chan error, numReplaced)
+
+	bridge := p.cfg.ChainBridge
+	for _, replaced := range replacedTxs {
+		replaced := replaced
+
+		txHash := replaced.FinalTx.TxHash()
+		pkScript := replaced.FinalTx.TxOut[0].PkScript
+		ntfn, ntfnErrs, err := bridge.RegisterConfirmationsNtfn(
+			ctx, &txHash, pkScript, 1,
+			pkg.OutboundPkg.AnchorTxHeightHint, true, nil,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		go func() {
+			select {
+			case conf, ok := <-ntfn.Confirmed:
+				if !ok {
+					return
+				}
+
+				confChan <- &replacedAnchorTxConf{
+					anchorTx: replaced,
+					event:    conf,
+				}
+
+			case err := <-ntfnErrs:
+				errChan <- err
+
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return confChan, errChan, nil
+}
+
+// watchAnchorInputs registers for spend notifications of all inputs of the
+// package's anchor transaction. The returned channel is sent upon if one of the
+// inputs is spent by a transaction other than the current or a replaced version
+// of the anchor transaction. Inputs can only be watched if the funded anchor
+// PSBT is known, which isn't the case for parcels that were handed off to this
+// node by another one.
+func (p *ChainPorter) watchAnchorInputs(ctx context.Context,
+	pkg *sendPackage) (<-chan *chainntnfs.SpendDetail, error) {
+
+	doubleSpendChan := make(chan *chainntnfs.SpendDetail, 1)
+
+	anchorTx := pkg.AnchorTx
+	if anchorTx == nil || anchorTx.FundedPsbt == nil ||
+		anchorTx.FundedPsbt.Pkt == nil {
+
+		return doubleSpendChan, nil
+	}
+
+	finalTx := pkg.OutboundPkg.AnchorTx
+	ownTxIDs := pkg.anchorTxIDs()
+	pktInputs := anchorTx.FundedPsbt.Pkt.Inputs
+	for idx := range finalTx.TxIn {
+		if idx >= len(pktInputs) || pktInputs[idx].WitnessUtxo == nil {
+			continue
+		}
+
+		outpoint := finalTx.TxIn[idx].PreviousOutPoint
+		spendEvent, errChan, err := p.cfg.ChainBridge.RegisterSpendNtfn(
+			ctx, &outpoint, pktInputs[idx].WitnessUtxo.PkScript,
+			pkg.OutboundPkg.AnchorTxHeightHint,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		go func() {
+			defer spendEvent.Cancel()
+
+			select {
+			case spend, ok := <-spendEvent.Spend:
+				if !ok {
+					return
+				}
+
+				// Spends by any version of the anchor
+				// transaction are expected.
+				if ownTxIDs.Contains(*spend.SpenderTxHash) {
+					return
+				}
+
+				select {
+				case doubleSpendChan <- spend:
+				default:
+				}
+
+			case err := <-errChan:
+				log.Warnf("Error watching anchor input %v: %v",
+					outpoint, err)
+
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return doubleSpendChan, nil
+}
+
+// abandonTransfer is called once an input of the package's anchor transaction
+// was double spent by a confirmed transaction, meaning the anchor transaction
+// can never confirm. The transfer is cancelled, which releases its inputs and
+// makes sure it isn't resumed after a restart. If auto-retry is enabled and
+// the transfer was made to a set of addresses, a new transfer to the same
+// addresses is initiated.
+func (p *ChainPorter) abandonTransfer(pkg *sendPackage,
+	spend *chainntnfs.SpendDetail) error {
+
+	anchorTXID := pkg.OutboundPkg.AnchorTx.TxHash()
+	log.Warnf("Anchor input %v of transfer_txid=%v was double spent by "+
+		"txid=%v, transfer can no longer confirm",
+		spend.SpentOutPoint, anchorTXID, spend.SpenderTxHash)
+
+	// The inputs of the transfer need to be released before we can retry
+	// it with freshly selected coins. If we can't mark the transfer as
+	// cancelled, we don't retry it, as we'd otherwise resume it after a
+	// restart as well.
+	ctx, cancel := p.WithCtxQuitNoTimeout()
+	defer cancel()
+	err := p.cfg.ExportLog.CancelParcel(ctx, anchorTXID)
+	if err != nil {
+		return fmt.Errorf("%w: unable to cancel transfer with anchor "+
+			"tx %v: %v", ErrTransferAbandoned, anchorTXID, err)
+	}
+	p.releaseWalletInputs(ctx, pkg.OutboundPkg)
+
+	// Only transfers to addresses can be re-created from scratch, as all
+	// other transfers require the involvement of an external party. A
+	// transfer that was restricted to explicitly chosen inputs isn't
+	// re-created either, as the new transfer would spend other coins.
+	addrParcel, isAddrParcel := pkg.Parcel.(*AddressParcel)
+	retry := p.cfg.AutoRetryAbandoned && isAddrParcel &&
+		len(addrParcel.inputs) == 0
+
+	p.publishSubscriberEvent(NewTransferAbandonedEvent(
+		anchorTXID, *spend.SpenderTxHash, retry,
+	))
+
+	if retry {
+		p.Wg.Add(1)
+		go p.retryAbandonedTransfer(anchorTXID, addrParcel)
+	}
+
+	return fmt.Errorf("%w: input %v of anchor tx %v spent by tx %v",
+		ErrTransferAbandoned, spend.SpentOutPoint, anchorTXID,
+		spend.SpenderTxHash)
+}
+
+// retryAbandonedTransfer attempts a new transfer to the addresses of the given
+// abandoned parcel with freshly selected coins, replacing the abandoned
+// transfer with the given anchor transaction ID.
+//
+// NOTE: This method MUST be called as a goroutine.
+func (p *ChainPorter) retryAbandonedTransfer(abandonedTXID chainhash.Hash,
+	abandoned *AddressParcel) {
+
+	defer p.Wg.Done()
 
-	case <-confCtx.Done():
-		log.Debugf("Skipping TX confirmation, context done")
+	log.Infof("Re-attempting abandoned transfer_txid=%v with a new "+
+		"anchor transaction", abandonedTXID)
 
-	case <-p.Quit:
-		log.Debugf("Skipping TX confirmation, exiting")
-		return nil
+	retryEvent := &TransferRetryEvent{
+		timestamp:     time.Now().UTC(),
+		AbandonedTXID: abandonedTXID,
 	}
 
-	if confEvent == nil {
-		return fmt.Errorf("got empty package tx confirmation event " +
-			"in batch")
+	newParcel, err := p.RequestShipment(NewAddressParcel(
+		abandoned.maxInputs, nil, abandoned.feeBumpAnchor,
+		abandoned.feeRate, abandoned.proofCourierAddr, abandoned.label,
+		abandoned.idempotencyKey, abandoned.destAddrs...,
+	))
+	if err != nil {
+		log.Errorf("Unable to re-attempt abandoned transfer_txid=%v: "+
+			"%v", abandonedTXID, err)
+		retryEvent.Err = err
+	} else {
+		retryEvent.NewAnchorTXID = newParcel.AnchorTx.TxHash()
 	}
 
-	return nil
+	p.publishSubscriberEvent(retryEvent)
 }
 
 // storeProofs writes the updated sender and receiver proof files to the proof
@@ -385,7 +1799,6 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 		map[asset.SerializedKey]*proof.AnnotatedProof,
 		len(parcel.Outputs),
 	)
-	firstInput := parcel.Inputs[0]
 	for idx := range parcel.Outputs {
 		out := parcel.Outputs[idx]
 
@@ -417,6 +1830,15 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 				"%d: %w", idx, err)
 		}
 
+		// A transfer can move multiple asset IDs, so only the inputs
+		// of the output's asset ID are part of its proof.
+		outInputs := outputInputs(parcel, out)
+		if len(outInputs) == 0 {
+			return fmt.Errorf("no inputs for output %d with asset "+
+				"ID %v", idx, out.AssetID)
+		}
+		firstInput := outInputs[0]
+
 		// The suffix is complete, so we need to fetch the input proof
 		// in order to append the suffix to it.
 		inputProofFile, err := p.fetchInputProof(ctx, firstInput)
@@ -426,9 +1848,9 @@ func (p *ChainPorter) storeProofs(sendPkg *sendPackage) error {
 
 		// Are there more inputs? Then this is a merge, and we need to
 		// add those additional files to the suffix as well.
-		for idx := 1; idx < len(parcel.Inputs); idx++ {
+		for idx := 1; idx < len(outInputs); idx++ {
 			additionalInputProofFile, err := p.fetchInputProof(
-				ctx, parcel.Inputs[idx],
+				ctx, outInputs[idx],
 			)
 			if err != nil {
 				return fmt.Errorf("error fetching input "+
@@ -588,13 +2010,450 @@ func (p *ChainPorter) updateAssetProofFile(ctx context.Context, assetID asset.ID
 }
 
 // transferReceiverProof retrieves the sender and receiver proofs from the
-// archive and then transfers the receiver's proof to the receiver. Upon
-// successful transfer, the asset parcel delivery is marked as complete.
+// archive and then transfers the receiver's proof to the receiver. Once the
+// proofs were delivered to as many receivers as the delivery completion policy
+// requires, the asset parcel delivery is marked as complete. The proofs of the
+// remaining receivers continue to be delivered in the background.
 func (p *ChainPorter) transferReceiverProof(pkg *sendPackage) error {
 	ctx, cancel := p.WithCtxQuitNoTimeout()
 	defer cancel()
 
-	deliver := func(ctx context.Context, out TransferOutput) error {
+	deliver := func(ctx context.Context,
+		out TransferOutput) (*deliveryCourier, error) {
+
+		key := out.ScriptKey.PubKey
+
+		// We just look for the full proof in the list of final proofs
+		// by matching the content of the proof suffix.
+		var receiverProof *proof.AnnotatedProof
+		for idx := range pkg.FinalProofs {
+			finalFile := pkg.FinalProofs[idx]
+			if finalFile.ScriptKey.IsEqual(out.ScriptKey.PubKey) {
+				receiverProof = finalFile
+				break
+			}
+		}
+		if receiverProof == nil {
+			return nil, fmt.Errorf("no proof found for output "+
+				"with script key %x",
+				key.SerializeCompressed())
+		}
+
+		return p.deliverReceiverProof(ctx, out, receiverProof)
+	}
+
+	// If we have a proof courier instance active, then we'll launch several
+	// goroutines to deliver the proof(s) to the receiver(s).
+	var (
+		receivers    []TransferOutput
+		numDelivered int
+	)
+	if p.cfg.ProofCourierCfg != nil {
+		var err error
+		receivers, err = proofReceivers(pkg.OutboundPkg.Outputs)
+		if err != nil {
+			return err
+		}
+
+		// If the transfer can be completed before all proofs were
+		// delivered, we persist that the deliveries are pending first.
+		// Each delivery records its outcome once it finished, so the
+		// ones that are still pending after a restart are resumed.
+		threshold := p.cfg.DeliveryCompletion.threshold(len(receivers))
+		if threshold < len(receivers) {
+			err = p.markProofDeliveriesPending(ctx, receivers)
+			if err != nil {
+				return err
+			}
+		}
+
+		numDelivered, err = p.deliverReceiverProofs(
+			ctx, receivers, deliver,
+		)
+		if err != nil {
+			return fmt.Errorf("error delivering proof(s): %w", err)
+		}
+	}
+
+	log.Infof("Marking parcel (txid=%v) as confirmed!",
+		pkg.OutboundPkg.AnchorTx.TxHash())
+
+	// Load passive asset proof files from archive.
+	passiveAssetProofFiles := map[[32]byte]proof.Blob{}
+	for idx := range pkg.OutboundPkg.PassiveAssets {
+		passiveAsset := pkg.OutboundPkg.PassiveAssets[idx]
+		proofLocator := proof.Locator{
+			AssetID:   &passiveAsset.GenesisID,
+			ScriptKey: *passiveAsset.ScriptKey.PubKey,
+		}
+		proofFileBlob, err := p.cfg.AssetProofs.FetchProof(
+			ctx, proofLocator,
+		)
+		if err != nil {
+			return fmt.Errorf("error fetching passive asset "+
+				"proof file: %w", err)
+		}
+
+		// Hash proof locator.
+		hash, err := proofLocator.Hash()
+		if err != nil {
+			return fmt.Errorf("error hashing proof locator: %w",
+				err)
+		}
+
+		passiveAssetProofFiles[hash] = proofFileBlob
+	}
+
+	// The block that confirmed the anchor transaction gives us the
+	// confirmation time we record for the transfer metrics.
+	var confTime time.Time
+	if pkg.TransferTxConfEvent.Block != nil {
+		confTime = pkg.TransferTxConfEvent.Block.Header.Timestamp
+	}
+
+	// The confirmed anchor transaction can still be re-organized out of
+	// the best chain until it's buried deep enough, so we keep watching
+	// it. The watch is stored together with the confirmation, so it's
+	// resumed after a restart.
+	var reOrgWatch *ReOrgWatch
+	if p.cfg.ReOrgSafeDepth > 0 {
+		reOrgWatch = newReOrgWatch(pkg)
+	}
+
+	// At this point we have the confirmation signal, so we can mark the
+	// parcel delivery as completed in the database.
+	err := p.cfg.ExportLog.ConfirmParcelDelivery(ctx, &AssetConfirmEvent{
+		AnchorTXID:             pkg.OutboundPkg.AnchorTx.TxHash(),
+		BlockHash:              *pkg.TransferTxConfEvent.BlockHash,
+		BlockHeight:            int32(pkg.TransferTxConfEvent.BlockHeight),
+		TxIndex:                int32(pkg.TransferTxConfEvent.TxIndex),
+		ConfirmationTime:       confTime,
+		FinalProofs:            pkg.FinalProofs,
+		PassiveAssetProofFiles: passiveAssetProofFiles,
+		ReOrgWatch:             reOrgWatch,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to log parcel delivery "+
+			"confirmation: %w", err)
+	}
+
+	if reOrgWatch != nil {
+		p.Wg.Add(1)
+		go p.watchTransferReOrg(reOrgWatch)
+	}
+
+	p.publishSubscriberEvent(NewTransferCompleteEvent(
+		pkg.OutboundPkg.AnchorTx.TxHash(), len(receivers),
+		numDelivered,
+	))
+
+	pkg.SendState = SendStateComplete
+	return nil
+}
+
+// deliverReceiverProof delivers the given proof of the given transfer output
+// to its receiver through the receiver's proof courier, falling back to the
+// fallback courier if configured. The outcome is recorded with the transfer
+// output. The courier that was used is returned, if any.
+func (p *ChainPorter) deliverReceiverProof(ctx context.Context,
+	out TransferOutput,
+	receiverProof *proof.AnnotatedProof) (*deliveryCourier, error) {
+
+	key := out.ScriptKey.PubKey
+
+	log.Debugf("Attempting to deliver proof for script key %x",
+		key.SerializeCompressed())
+
+	// Only outputs to a Tap address carry the proof courier
+	// address of the receiver. Interactive receivers are served by
+	// the courier configured for them, if any.
+	var (
+		proofCourierAddr proof.CourierAddr
+		receiveType      = proof.ReceiveTypeNonInteractive
+		err              error
+	)
+	switch {
+	case len(out.ProofCourierAddr) > 0:
+		proofCourierAddr, err = proof.ParseCourierAddrString(
+			string(out.ProofCourierAddr),
+		)
+
+	case p.cfg.ProofCourierCfg.InteractiveCourierAddr != nil:
+		receiveType = proof.ReceiveTypeInteractive
+		proofCourierAddr, err = proof.ParseCourierAddrUrl(
+			*p.cfg.ProofCourierCfg.InteractiveCourierAddr,
+		)
+
+	default:
+		log.Debugf("Not delivering proof for interactive "+
+			"output script key %x, no courier configured",
+			key.SerializeCompressed())
+
+		p.logProofDelivery(ctx, out, receiverProof.Locator,
+			ProofDelivery{
+				Status: ProofDeliveryStatusSkipped,
+			})
+
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proof "+
+			"courier address: %w", err)
+	}
+
+	// Initiate proof courier service handle from the selected
+	// proof courier address.
+	recipient := proof.Recipient{
+		ScriptKey:      key,
+		AssetID:        *receiverProof.AssetID,
+		Amount:         out.Amount,
+		AnchorOutPoint: out.Anchor.OutPoint,
+		ReceiveType:    receiveType,
+		InternalKey:    out.Anchor.InternalKey,
+	}
+	courier, err := proofCourierAddr.NewCourier(
+		ctx, p.cfg.ProofCourierCfg, recipient,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initiate proof "+
+			"courier service handle: %w", err)
+	}
+
+	// Update courier events subscribers before attempting to
+	// deliver proof.
+	p.subscriberMtx.Lock()
+	courier.SetSubscribers(p.subscribers)
+	p.subscriberMtx.Unlock()
+
+	// Deliver proof to proof courier service.
+	usedCourier := &deliveryCourier{
+		addr: proofCourierAddr.Url().String(),
+	}
+	err = courier.DeliverProof(ctx, receiverProof)
+
+	// If the courier failed all its delivery attempts, we upload
+	// the proof to the fallback universe instead, from which the
+	// receiver pulls it.
+	if p.useFallbackCourier(ctx, proofCourierAddr, err) {
+		log.Warnf("Proof delivery for script key %x failed, "+
+			"delivering through fallback courier: %v",
+			key.SerializeCompressed(), err)
+
+		courier, err = proof.NewFallbackCourier(
+			ctx, p.cfg.ProofCourierCfg, recipient,
+		)
+		if err != nil {
+			return usedCourier, fmt.Errorf("unable to "+
+				"initiate fallback proof courier: %w",
+				err)
+		}
+
+		p.subscriberMtx.Lock()
+		courier.SetSubscribers(p.subscribers)
+		p.subscriberMtx.Unlock()
+
+		usedCourier = &deliveryCourier{
+			addr: p.cfg.ProofCourierCfg.
+				FallbackCourierAddr.String(),
+			fallback: true,
+		}
+		err = courier.DeliverProof(ctx, receiverProof)
+	}
+
+	// We keep a record of the outcome, unless the delivery was
+	// interrupted by a shutdown and will be resumed on restart.
+	if ctx.Err() == nil {
+		delivery := ProofDelivery{
+			Status:      ProofDeliveryStatusDelivered,
+			CourierAddr: []byte(usedCourier.addr),
+		}
+		if err != nil {
+			delivery.Status = ProofDeliveryStatusFailed
+		}
+
+		p.logProofDelivery(
+			ctx, out, receiverProof.Locator, delivery,
+		)
+	}
+	if err != nil {
+		return usedCourier, fmt.Errorf("failed to deliver "+
+			"proof via courier service: %w", err)
+	}
+
+	// A receiver that assembles its proof chain from the universe
+	// might ask us for the proofs that are missing from it. We record
+	// the serve, so it is resumed if we're shut down before the receiver
+	// acknowledged its proof chain.
+	gapServer, ok := courier.(proof.GapServer)
+	if ok && p.cfg.ProofCourierCfg.GapRecovery != nil {
+		serve := ProofGapServe{
+			Locator:   receiverProof.Locator,
+			StartTime: time.Now(),
+		}
+		err := p.cfg.ExportLog.LogProofGapServe(ctx, serve)
+		if err != nil {
+			log.Warnf("Unable to record proof gap serve for "+
+				"script key %x: %v", key.SerializeCompressed(),
+				err)
+		}
+
+		p.serveProofGaps(serve, func(ctx context.Context) error {
+			return gapServer.ServeProofGaps(ctx, receiverProof)
+		})
+	}
+
+	// If the proof courier returned a backoff error, then
+	// we'll just return nil here so that we can retry
+	// later.
+	var backoffExecErr *proof.BackoffExecError
+	if errors.As(err, &backoffExecErr) {
+		return usedCourier, nil
+	}
+	if err != nil {
+		return usedCourier, fmt.Errorf("error delivering "+
+			"proof: %w", err)
+	}
+
+	return usedCourier, nil
+}
+
+// serveProofGaps answers the requests of a receiver for the proofs missing
+// from the proof chain of the proof of the given serve in the background,
+// until the receiver acknowledges that its proof chain is complete or the
+// maximum serve duration of the gap recovery config passed since the proof
+// was delivered. The serve is removed once it is finished, so only serves that
+// were interrupted are resumed on restart.
+func (p *ChainPorter) serveProofGaps(serve ProofGapServe,
+	serveFn func(context.Context) error) {
+
+	gapCfg := p.cfg.ProofCourierCfg.GapRecovery
+	deadline := serve.StartTime.Add(gapCfg.ServeDuration)
+	scriptKey := serve.Locator.ScriptKey.SerializeCompressed()
+
+	p.Wg.Add(1)
+	go func() {
+		defer p.Wg.Done()
+
+		ctx, cancel := p.WithCtxQuitNoTimeout()
+		defer cancel()
+
+		serveCtx, cancelServe := context.WithDeadline(ctx, deadline)
+		defer cancelServe()
+
+		err := serveFn(serveCtx)
+		switch {
+		// We're shutting down, so the serve is resumed on restart.
+		case ctx.Err() != nil:
+			return
+
+		// Serving failed before the maximum serve duration passed, so
+		// we keep the serve to retry it on restart.
+		case err != nil && serveCtx.Err() == nil:
+			log.Warnf("Unable to serve proof gap requests for "+
+				"script key %x: %v", scriptKey, err)
+
+			return
+		}
+
+		err = p.cfg.ExportLog.RemoveProofGapServe(ctx, serve.Locator)
+		if err != nil {
+			log.Warnf("Unable to remove proof gap serve for "+
+				"script key %x: %v", scriptKey, err)
+		}
+	}()
+}
+
+// resumeProofGapServes resumes answering the requests of the receivers for the
+// proofs missing from their proof chains, for the serves that were interrupted
+// by a shutdown before the receiver acknowledged its proof chain. The proofs
+// are fetched from the proof archive and served in the background.
+func (p *ChainPorter) resumeProofGapServes(ctx context.Context) error {
+	if p.cfg.ProofCourierCfg == nil ||
+		p.cfg.ProofCourierCfg.GapRecovery == nil {
+
+		return nil
+	}
+	gapCfg := p.cfg.ProofCourierCfg.GapRecovery
+
+	serves, err := p.cfg.ExportLog.ProofGapServes(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch proof gap serves: %w", err)
+	}
+
+	for _, serve := range serves {
+		scriptKey := serve.Locator.ScriptKey.SerializeCompressed()
+
+		// The maximum serve duration passed while we were shut down.
+		deadline := serve.StartTime.Add(gapCfg.ServeDuration)
+		if !time.Now().Before(deadline) {
+			err := p.cfg.ExportLog.RemoveProofGapServe(
+				ctx, serve.Locator,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to remove proof gap "+
+					"serve: %w", err)
+			}
+
+			continue
+		}
+
+		blob, err := p.cfg.AssetProofs.FetchProof(ctx, serve.Locator)
+		if err != nil {
+			log.Warnf("Unable to fetch proof to resume proof gap "+
+				"serve for script key %x: %v", scriptKey, err)
+
+			continue
+		}
+		receiverProof := &proof.AnnotatedProof{
+			Locator: serve.Locator,
+			Blob:    blob,
+		}
+
+		log.Infof("Resuming proof gap serve for script key %x",
+			scriptKey)
+
+		p.serveProofGaps(serve, func(ctx context.Context) error {
+			return proof.ServeProofGaps(ctx, gapCfg, receiverProof)
+		})
+	}
+
+	return nil
+}
+
+// logProofDelivery records the outcome of the delivery of the proof of the
+// given transfer output. The number of delivery attempts is taken from the
+// delivery log of the proof courier. Failing to record the outcome doesn't
+// affect the delivery itself, so errors are only logged.
+func (p *ChainPorter) logProofDelivery(ctx context.Context, out TransferOutput,
+	locator proof.Locator, delivery ProofDelivery) {
+
+	deliveryLog := p.cfg.ProofCourierCfg.DeliveryLog
+	if delivery.Status != ProofDeliveryStatusSkipped && deliveryLog != nil {
+		attempts, err := deliveryLog.QueryProofDeliveryLog(
+			ctx, locator,
+		)
+		if err != nil {
+			log.Warnf("Unable to query proof delivery log: %v", err)
+		}
+		delivery.NumAttempts = uint32(len(attempts))
+	}
+
+	err := p.cfg.ExportLog.LogProofDelivery(
+		ctx, out.Anchor.OutPoint, out.ScriptKey.PubKey, delivery,
+	)
+	if err != nil {
+		log.Warnf("Unable to record proof delivery outcome for "+
+			"script key %x: %v",
+			out.ScriptKey.PubKey.SerializeCompressed(), err)
+	}
+}
+
+// proofReceivers returns the outputs of a transfer the receiver proofs need to
+// be delivered for. Outputs that go to our own node/wallet and un-spendable
+// outputs don't require a proof delivery.
+func proofReceivers(outputs []TransferOutput) ([]TransferOutput, error) {
+	var receivers []TransferOutput
+	for _, out := range outputs {
 		key := out.ScriptKey.PubKey
 
 		// If this is an output that is going to our own node/wallet,
@@ -602,146 +2461,199 @@ func (p *ChainPorter) transferReceiverProof(pkg *sendPackage) error {
 		if out.ScriptKey.TweakedScriptKey != nil && out.ScriptKeyLocal {
 			log.Debugf("Not transferring proof for local output "+
 				"script key %x", key.SerializeCompressed())
-			return nil
+			continue
 		}
 
-		unSpendable, err := out.ScriptKey.IsUnSpendable()
-		if err != nil {
-			return fmt.Errorf("error checking if script key is "+
-				"unspendable: %w", err)
-		}
-		if unSpendable {
-			log.Debugf("Not transferring proof for un-spendable "+
-				"output script key %x",
-				key.SerializeCompressed())
-			return nil
-		}
+		unSpendable, err := out.ScriptKey.IsUnSpendable()
+		if err != nil {
+			return nil, fmt.Errorf("error checking if script key "+
+				"is unspendable: %w", err)
+		}
+		if unSpendable {
+			log.Debugf("Not transferring proof for un-spendable "+
+				"output script key %x",
+				key.SerializeCompressed())
+			continue
+		}
+
+		receivers = append(receivers, out)
+	}
+
+	return receivers, nil
+}
+
+// deliveryCourier describes the proof courier a receiver proof was delivered
+// through.
+type deliveryCourier struct {
+	// addr is the address of the courier.
+	addr string
+
+	// fallback indicates that the proof was delivered through the fallback
+	// courier, as the courier of the receiver failed all delivery attempts.
+	fallback bool
+}
+
+// useFallbackCourier returns true if the proof delivery through the given
+// courier failed with the given error in a way that warrants a delivery through
+// the fallback courier.
+func (p *ChainPorter) useFallbackCourier(ctx context.Context,
+	courierAddr proof.CourierAddr, deliveryErr error) bool {
+
+	fallbackAddr := p.cfg.ProofCourierCfg.FallbackCourierAddr
+	if fallbackAddr == nil || ctx.Err() != nil {
+		return false
+	}
+
+	// Only a delivery that failed all its attempts falls back. A delivery
+	// through the fallback universe itself has nothing to fall back to.
+	var backoffExecErr *proof.BackoffExecError
+	if !errors.As(deliveryErr, &backoffExecErr) {
+		return false
+	}
+
+	return courierAddr.Url().String() != fallbackAddr.String()
+}
+
+// deliverReceiverProofs delivers the receiver proofs to the given receivers in
+// parallel and waits until as many of them were delivered as the delivery
+// completion policy requires. The number of successful deliveries at that
+// point is returned. Deliveries that are still in progress continue in the
+// background. A ReceiverProofDeliveredEvent is published for every receiver
+// once its delivery finished.
+func (p *ChainPorter) deliverReceiverProofs(ctx context.Context,
+	receivers []TransferOutput, deliver func(context.Context,
+		TransferOutput) (*deliveryCourier, error)) (int, error) {
+
+	results := p.startReceiverProofDeliveries(receivers, deliver)
+
+	threshold := p.cfg.DeliveryCompletion.threshold(len(receivers))
+	return awaitDeliveries(ctx, results, len(receivers), threshold)
+}
+
+// startReceiverProofDeliveries delivers the receiver proofs to the given
+// receivers in parallel in the background. The result of every delivery is
+// sent on the returned channel, which is buffered, so nobody needs to read the
+// results of the deliveries that finish after the transfer was completed. A
+// ReceiverProofDeliveredEvent is published for every receiver once its
+// delivery finished.
+func (p *ChainPorter) startReceiverProofDeliveries(receivers []TransferOutput,
+	deliver func(context.Context, TransferOutput) (*deliveryCourier,
+		error)) <-chan error {
+
+	results := make(chan error, len(receivers))
+	for idx := range receivers {
+		out := receivers[idx]
+
+		p.Wg.Add(1)
+		go func() {
+			defer p.Wg.Done()
+
+			// Each delivery gets its own context, as it might outlive
+			// the wait for the completion threshold.
+			ctx, cancel := p.WithCtxQuitNoTimeout()
+			defer cancel()
 
-		// We just look for the full proof in the list of final proofs
-		// by matching the content of the proof suffix.
-		var receiverProof *proof.AnnotatedProof
-		for idx := range pkg.FinalProofs {
-			finalFile := pkg.FinalProofs[idx]
-			if finalFile.ScriptKey.IsEqual(out.ScriptKey.PubKey) {
-				receiverProof = finalFile
-				break
+			usedCourier, err := deliver(ctx, out)
+			if err != nil {
+				log.Errorf("Unable to deliver proof for script "+
+					"key %x: %v", out.ScriptKey.PubKey.
+					SerializeCompressed(), err)
 			}
-		}
-		if receiverProof == nil {
-			return fmt.Errorf("no proof found for output with "+
-				"script key %x", key.SerializeCompressed())
-		}
 
-		log.Debugf("Attempting to deliver proof for script key %x",
-			key.SerializeCompressed())
+			deliveredEvent := NewReceiverProofDeliveredEvent(
+				out.Anchor.OutPoint, out.ScriptKey.PubKey, err,
+			)
+			if usedCourier != nil {
+				deliveredEvent.CourierAddr = usedCourier.addr
+				deliveredEvent.Fallback = usedCourier.fallback
+			}
+			p.publishSubscriberEvent(deliveredEvent)
 
-		proofCourierAddr, err := proof.ParseCourierAddrString(
-			string(out.ProofCourierAddr),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to parse proof courier "+
-				"address: %w", err)
-		}
+			results <- err
+		}()
+	}
 
-		// Initiate proof courier service handle from the proof
-		// courier address found in the Tap address.
-		recipient := proof.Recipient{
-			ScriptKey: key,
-			AssetID:   *receiverProof.AssetID,
-			Amount:    out.Amount,
-		}
-		courier, err := proofCourierAddr.NewCourier(
-			ctx, p.cfg.ProofCourierCfg, recipient,
-		)
-		if err != nil {
-			return fmt.Errorf("unable to initiate proof courier "+
-				"service handle: %w", err)
-		}
+	return results
+}
 
-		// Update courier events subscribers before attempting to
-		// deliver proof.
-		p.subscriberMtx.Lock()
-		courier.SetSubscribers(p.subscribers)
-		p.subscriberMtx.Unlock()
+// markProofDeliveriesPending records the proof deliveries to the given
+// receivers as pending.
+func (p *ChainPorter) markProofDeliveriesPending(ctx context.Context,
+	receivers []TransferOutput) error {
 
-		// Deliver proof to proof courier service.
-		err = courier.DeliverProof(ctx, receiverProof)
+	for _, out := range receivers {
+		err := p.cfg.ExportLog.LogProofDelivery(
+			ctx, out.Anchor.OutPoint, out.ScriptKey.PubKey,
+			ProofDelivery{
+				Status: ProofDeliveryStatusPending,
+			},
+		)
 		if err != nil {
-			return fmt.Errorf("failed to deliver proof via "+
-				"courier service: %w", err)
+			return fmt.Errorf("unable to mark proof delivery for "+
+				"script key %x as pending: %w",
+				out.ScriptKey.PubKey.SerializeCompressed(), err)
 		}
+	}
 
-		// If the proof courier returned a backoff error, then
-		// we'll just return nil here so that we can retry
-		// later.
-		var backoffExecErr *proof.BackoffExecError
-		if errors.As(err, &backoffExecErr) {
-			return nil
-		}
-		if err != nil {
-			return fmt.Errorf("error delivering proof: %w", err)
-		}
+	return nil
+}
 
+// resumeProofDeliveries resumes the proof deliveries that were still pending
+// when their transfer was completed according to the delivery completion
+// policy and didn't finish before the daemon was shut down. The proofs are
+// fetched from the proof archive and delivered in the background.
+func (p *ChainPorter) resumeProofDeliveries(ctx context.Context) error {
+	if p.cfg.ProofCourierCfg == nil {
 		return nil
 	}
 
-	// If we have a proof courier instance active, then we'll launch several
-	// goroutines to deliver the proof(s) to the receiver(s).
-	if p.cfg.ProofCourierCfg != nil {
-		ctx, cancel := p.WithCtxQuitNoTimeout()
-		defer cancel()
-
-		err := fn.ParSlice(ctx, pkg.OutboundPkg.Outputs, deliver)
-		if err != nil {
-			return fmt.Errorf("error delivering proof(s): %w", err)
-		}
+	parcels, err := p.cfg.ExportLog.PendingProofDeliveries(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch pending proof deliveries: "+
+			"%w", err)
 	}
 
-	log.Infof("Marking parcel (txid=%v) as confirmed!",
-		pkg.OutboundPkg.AnchorTx.TxHash())
+	deliver := func(ctx context.Context,
+		out TransferOutput) (*deliveryCourier, error) {
 
-	// Load passive asset proof files from archive.
-	passiveAssetProofFiles := map[[32]byte]proof.Blob{}
-	for idx := range pkg.OutboundPkg.PassiveAssets {
-		passiveAsset := pkg.OutboundPkg.PassiveAssets[idx]
-		proofLocator := proof.Locator{
-			AssetID:   &passiveAsset.GenesisID,
-			ScriptKey: *passiveAsset.ScriptKey.PubKey,
+		locator := proof.Locator{
+			AssetID:   fn.Ptr(out.AssetID),
+			ScriptKey: *out.ScriptKey.PubKey,
+			OutPoint:  fn.Ptr(out.Anchor.OutPoint),
 		}
-		proofFileBlob, err := p.cfg.AssetProofs.FetchProof(
-			ctx, proofLocator,
-		)
+		blob, err := p.cfg.AssetProofs.FetchProof(ctx, locator)
 		if err != nil {
-			return fmt.Errorf("error fetching passive asset "+
-				"proof file: %w", err)
+			return nil, fmt.Errorf("unable to fetch proof: %w", err)
 		}
 
-		// Hash proof locator.
-		hash, err := proofLocator.Hash()
-		if err != nil {
-			return fmt.Errorf("error hashing proof locator: %w",
-				err)
+		return p.deliverReceiverProof(ctx, out, &proof.AnnotatedProof{
+			Locator: locator,
+			Blob:    blob,
+		})
+	}
+
+	for _, parcel := range parcels {
+		var receivers []TransferOutput
+		for _, out := range parcel.Outputs {
+			if out.ProofDelivery.Status ==
+				ProofDeliveryStatusPending {
+
+				receivers = append(receivers, out)
+			}
+		}
+		if len(receivers) == 0 {
+			continue
 		}
 
-		passiveAssetProofFiles[hash] = proofFileBlob
-	}
+		log.Infof("Resuming %d pending proof deliveries of "+
+			"transfer_txid=%v", len(receivers),
+			parcel.AnchorTx.TxHash())
 
-	// At this point we have the confirmation signal, so we can mark the
-	// parcel delivery as completed in the database.
-	err := p.cfg.ExportLog.ConfirmParcelDelivery(ctx, &AssetConfirmEvent{
-		AnchorTXID:             pkg.OutboundPkg.AnchorTx.TxHash(),
-		BlockHash:              *pkg.TransferTxConfEvent.BlockHash,
-		BlockHeight:            int32(pkg.TransferTxConfEvent.BlockHeight),
-		TxIndex:                int32(pkg.TransferTxConfEvent.TxIndex),
-		FinalProofs:            pkg.FinalProofs,
-		PassiveAssetProofFiles: passiveAssetProofFiles,
-	})
-	if err != nil {
-		return fmt.Errorf("unable to log parcel delivery "+
-			"confirmation: %w", err)
+		// The transfer is complete already, so nobody waits for the
+		// results.
+		p.startReceiverProofDeliveries(receivers, deliver)
 	}
 
-	pkg.SendState = SendStateComplete
 	return nil
 }
 
@@ -808,12 +2720,29 @@ func createDummyOutput() *wire.TxOut {
 	return &newOutput
 }
 
+// outputInputs returns the inputs of the parcel that are of the same asset ID
+// as the given output. Outputs without an asset ID were created by a transfer
+// of a single asset ID, so all inputs are returned for them.
+func outputInputs(parcel *OutboundParcel,
+	out TransferOutput) []TransferInput {
+
+	if out.AssetID == (asset.ID{}) {
+		return parcel.Inputs
+	}
+
+	return fn.Filter(parcel.Inputs, func(in TransferInput) bool {
+		return in.PrevID.ID == out.AssetID
+	})
+}
+
 // stateStep attempts to step through the state machine to complete a Taproot
 // Asset transfer.
 func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 	// Notify subscribers that the state machine is about to execute a
 	// state.
-	stateEvent := NewExecuteSendStateEvent(currentPkg.SendState)
+	stateEvent := NewExecuteSendStateEvent(
+		currentPkg.SendState, currentPkg.Label,
+	)
 	p.publishSubscriberEvent(stateEvent)
 
 	switch currentPkg.SendState {
@@ -832,18 +2761,34 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 			return nil, fmt.Errorf("unable to cast parcel to " +
 				"address parcel")
 		}
-		fundSendRes, outputIdxToAddr, err :=
-			p.cfg.AssetWallet.FundAddressSend(
-				ctx, addrParcel.destAddrs...,
+
+		// Addresses of different asset IDs are funded with separate
+		// virtual packets that are all anchored in the same anchor
+		// transaction.
+		fundedVPkts, outputIdxToAddrs, err :=
+			p.cfg.AssetWallet.FundMultiAssetSend(
+				ctx, addrParcel.maxInputs, addrParcel.inputs,
+				addrParcel.sendAddrs()...,
 			)
 		if err != nil {
 			return nil, fmt.Errorf("unable to fund address send: "+
 				"%w", err)
 		}
 
-		currentPkg.VirtualPacket = fundSendRes.VPacket
-		currentPkg.InputCommitments = fundSendRes.InputCommitments
-		currentPkg.OutputIdxToAddr = outputIdxToAddr
+		for _, fundedVPkt := range fundedVPkts {
+			currentPkg.VirtualPackets = append(
+				currentPkg.VirtualPackets, fundedVPkt.VPacket,
+			)
+			currentPkg.InputCommitments = append(
+				currentPkg.InputCommitments,
+				fundedVPkt.InputCommitments,
+			)
+			currentPkg.CoinRelaxations = append(
+				currentPkg.CoinRelaxations,
+				fundedVPkt.CoinRelaxations...,
+			)
+		}
+		currentPkg.OutputIdxToAddr = outputIdxToAddrs
 
 		currentPkg.SendState = SendStateVirtualSign
 
@@ -852,18 +2797,20 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 	// At this point, we have everything we need to sign our _virtual_
 	// transaction on the Taproot Asset layer.
 	case SendStateVirtualSign:
-		vPacket := currentPkg.VirtualPacket
-		receiverScriptKey := vPacket.Outputs[1].ScriptKey.PubKey
-		log.Infof("Generating Taproot Asset witnesses for send to: %x",
-			receiverScriptKey.SerializeCompressed())
-
-		// Now we'll use the signer to sign all the inputs for the new
-		// Taproot Asset leaves. The witness data for each input will be
-		// assigned for us.
-		_, err := p.cfg.AssetWallet.SignVirtualPacket(vPacket)
-		if err != nil {
-			return nil, fmt.Errorf("unable to sign and commit "+
-				"virtual packet: %w", err)
+		for _, vPacket := range currentPkg.VirtualPackets {
+			receiverScriptKey := vPacket.Outputs[1].ScriptKey.PubKey
+			log.Infof("Generating Taproot Asset witnesses for "+
+				"send to: %x",
+				receiverScriptKey.SerializeCompressed())
+
+			// Now we'll use the signer to sign all the inputs for
+			// the new Taproot Asset leaves. The witness data for
+			// each input will be assigned for us.
+			_, err := p.cfg.AssetWallet.SignVirtualPacket(vPacket)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign and "+
+					"commit virtual packet: %w", err)
+			}
 		}
 
 		currentPkg.SendState = SendStateAnchorSign
@@ -874,21 +2821,38 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 	// make our initial skeleton PSBT packet to send off to the wallet for
 	// funding and signing.
 	case SendStateAnchorSign:
+		// An anchor transaction that was signed externally only needs
+		// to be verified and finalized.
+		preAnchored, ok := currentPkg.Parcel.(*PreAnchoredParcel)
+		if ok {
+			return p.completeExternalAnchor(currentPkg, preAnchored)
+		}
+
 		ctx, cancel := p.WithCtxQuitNoTimeout()
 		defer cancel()
 
-		// Submit the template PSBT to the wallet for funding.
+		// Submit the template PSBT to the wallet for funding. If the
+		// parcel specifies a fee rate, we use it instead of estimating
+		// one.
 		//
 		// TODO(roasbeef): unlock the input UTXOs of things fail
-		feeRate, err := p.cfg.ChainBridge.EstimateFee(
-			ctx, tapscript.SendConfTarget,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to estimate fee: %w",
-				err)
+		addrParcel, isAddrParcel := currentPkg.Parcel.(*AddressParcel)
+		feeRate := chainfee.SatPerKWeight(0)
+		if isAddrParcel {
+			feeRate = addrParcel.feeRate
+		}
+		if feeRate == 0 {
+			var err error
+			feeRate, err = p.cfg.ChainBridge.EstimateFee(
+				ctx, tapscript.SendConfTarget,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to estimate "+
+					"fee: %w", err)
+			}
 		}
 
-		vPacket := currentPkg.VirtualPacket
+		vPacket := currentPkg.VirtualPackets[0]
 		firstRecipient, err := vPacket.FirstNonSplitRootOutput()
 		if err != nil {
 			return nil, fmt.Errorf("unable to get first "+
@@ -898,15 +2862,24 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		log.Infof("Constructing new Taproot Asset commitments for "+
 			"send to: %x", receiverScriptKey.SerializeCompressed())
 
-		// Gather passive assets virtual packets and sign them.
+		// Gather passive assets virtual packets and sign them. Each
+		// virtual packet re-anchors the passive assets of its own
+		// inputs.
 		wallet := p.cfg.AssetWallet
 
-		currentPkg.PassiveAssets, err = wallet.SignPassiveAssets(
-			vPacket, currentPkg.InputCommitments,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("unable to sign passive "+
-				"assets: %w", err)
+		currentPkg.PassiveAssets = nil
+		for idx, vPkt := range currentPkg.VirtualPackets {
+			passiveAssets, err := wallet.SignPassiveAssets(
+				vPkt, currentPkg.InputCommitments[idx],
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to sign "+
+					"passive assets: %w", err)
+			}
+
+			currentPkg.PassiveAssets = append(
+				currentPkg.PassiveAssets, passiveAssets...,
+			)
 		}
 
 		var passiveVPackets []*tappsbt.VPacket
@@ -916,12 +2889,20 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 			)
 		}
 
+		// A fee bump anchor is reserved if the daemon is configured to
+		// always do so, or if it was requested for this transfer.
+		feeBumpAnchor := p.cfg.FeeBumpAnchor
+		if isAddrParcel && addrParcel.feeBumpAnchor {
+			feeBumpAnchor = true
+		}
+
 		anchorTx, err := wallet.AnchorVirtualTransactions(
 			ctx, &AnchorVTxnsParams{
 				FeeRate:            feeRate,
-				VPkts:              []*tappsbt.VPacket{vPacket},
+				VPkts:              currentPkg.VirtualPackets,
 				InputCommitments:   currentPkg.InputCommitments,
 				PassiveAssetsVPkts: passiveVPackets,
+				FeeBumpAnchor:      feeBumpAnchor,
 			},
 		)
 		if err != nil {
@@ -979,6 +2960,19 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		ctx, cancel = p.CtxBlocking()
 		defer cancel()
 
+		// The wallet only leased the inputs it funded the anchor
+		// transaction with for a short time, but a staged transfer
+		// might only be released much later. We lease them for as long
+		// as the asset inputs, until the transfer is released.
+		if currentPkg.Staged {
+			err := p.leaseWalletInputs(
+				ctx, parcel, defaultBroadcastCoinLeaseDuration,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		log.Infof("Committing pending parcel to disk")
 
 		err = p.cfg.ExportLog.LogPendingParcel(
@@ -990,6 +2984,18 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 				"disk: %v", err)
 		}
 
+		// A staged package stops here. It is picked up again at the
+		// broadcast state once it is released.
+		if currentPkg.Staged {
+			log.Infof("Staged transfer with anchor_txid=%v, not "+
+				"broadcasting", parcel.AnchorTx.TxHash())
+
+			currentPkg.deliverTxBroadcastResp()
+			currentPkg.SendState = SendStateComplete
+
+			return &currentPkg, nil
+		}
+
 		// We've logged the state transition to disk, so now we can
 		// move onto the broadcast phase.
 		currentPkg.SendState = SendStateBroadcast
@@ -1002,7 +3008,19 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 		ctx, cancel := p.WithCtxQuitNoTimeout()
 		defer cancel()
 
-		err := p.importLocalAddresses(ctx, currentPkg.OutboundPkg)
+		// Another node might have taken over the transfer since we
+		// logged it, in which case we leave the broadcast to it.
+		claimed, err := p.claimedElsewhere(ctx, currentPkg.OutboundPkg)
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, fmt.Errorf("%w: anchor txid %v",
+				ErrTransferClaimed,
+				currentPkg.OutboundPkg.AnchorTx.TxHash())
+		}
+
+		err = p.importLocalAddresses(ctx, currentPkg.OutboundPkg)
 		if err != nil {
 			return nil, fmt.Errorf("unable to import local "+
 				"addresses: %w", err)
@@ -1012,11 +3030,21 @@ func (p *ChainPorter) stateStep(currentPkg sendPackage) (*sendPackage, error) {
 			currentPkg.OutboundPkg.AnchorTx.TxHash())
 
 		// With the public key imported, we can now broadcast to the
-		// network.
+		// network. If the anchor transaction was replaced by a fee
+		// bump, one of the replaced versions might have confirmed in
+		// the meantime, so the current version can't be published
+		// anymore. We then wait for any of the versions to confirm.
 		err = p.cfg.ChainBridge.PublishTransaction(
 			ctx, currentPkg.OutboundPkg.AnchorTx,
 		)
-		if err != nil {
+		replacedTxs := currentPkg.OutboundPkg.ReplacedAnchorTxs
+		switch {
+		case err != nil && len(replacedTxs) > 0:
+			log.Warnf("Unable to publish transfer tx, txid=%v, "+
+				"waiting for replaced versions to confirm: %v",
+				currentPkg.OutboundPkg.AnchorTx.TxHash(), err)
+
+		case err != nil:
 			return nil, err
 		}
 
@@ -1120,6 +3148,36 @@ func (p *ChainPorter) publishSubscriberEvent(event fn.Event) {
 // fn.EventPublisher interface.
 var _ fn.EventPublisher[fn.Event, bool] = (*ChainPorter)(nil)
 
+// completeExternalAnchor verifies that the externally signed anchor
+// transaction of the given parcel commits to the virtual transactions of the
+// package and finalizes it, so the transfer can be logged.
+func (p *ChainPorter) completeExternalAnchor(currentPkg sendPackage,
+	parcel *PreAnchoredParcel) (*sendPackage, error) {
+
+	var passiveVPackets []*tappsbt.VPacket
+	for _, passiveAsset := range currentPkg.PassiveAssets {
+		passiveVPackets = append(passiveVPackets, passiveAsset.VPacket)
+	}
+
+	anchorTx, err := p.cfg.AssetWallet.CompleteAnchorTx(
+		&AnchorVTxnsParams{
+			VPkts:              currentPkg.VirtualPackets,
+			InputCommitments:   currentPkg.InputCommitments,
+			PassiveAssetsVPkts: passiveVPackets,
+		}, parcel.anchorPkt, parcel.changeOutputIndex,
+		parcel.lockedUTXOs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to complete external anchor "+
+			"transaction: %w", err)
+	}
+
+	currentPkg.AnchorTx = anchorTx
+	currentPkg.SendState = SendStateLogCommit
+
+	return &currentPkg, nil
+}
+
 // ExecuteSendStateEvent is an event which is sent to the ChainPorter's event
 // subscribers before a state is executed.
 type ExecuteSendStateEvent struct {
@@ -1128,6 +3186,9 @@ type ExecuteSendStateEvent struct {
 
 	// SendState is the state that is about to be executed.
 	SendState SendState
+
+	// Label is the label of the transfer the state is executed for.
+	Label string
 }
 
 // Timestamp returns the timestamp of the event.
@@ -1136,9 +3197,228 @@ func (e *ExecuteSendStateEvent) Timestamp() time.Time {
 }
 
 // NewExecuteSendStateEvent creates a new ExecuteSendStateEvent.
-func NewExecuteSendStateEvent(state SendState) *ExecuteSendStateEvent {
+func NewExecuteSendStateEvent(state SendState,
+	label string) *ExecuteSendStateEvent {
+
 	return &ExecuteSendStateEvent{
 		timestamp: time.Now().UTC(),
 		SendState: state,
+		Label:     label,
+	}
+}
+
+// ReceiverProofDeliveredEvent is an event which is sent to the ChainPorter's
+// event subscribers once the delivery of a proof to one of the receivers of a
+// transfer finished.
+type ReceiverProofDeliveredEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AnchorOutPoint is the anchor outpoint of the transfer output the
+	// proof was delivered for.
+	AnchorOutPoint wire.OutPoint
+
+	// ScriptKey is the script key of the transfer output the proof was
+	// delivered for.
+	ScriptKey *btcec.PublicKey
+
+	// CourierAddr is the address of the proof courier the delivery was
+	// attempted through. This is empty if the delivery was skipped.
+	CourierAddr string
+
+	// Fallback indicates that the proof was delivered through the
+	// fallback courier, as the courier of the receiver failed all
+	// delivery attempts.
+	Fallback bool
+
+	// Err is the error that prevented the delivery of the proof, if any.
+	Err error
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *ReceiverProofDeliveredEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewReceiverProofDeliveredEvent creates a new ReceiverProofDeliveredEvent.
+func NewReceiverProofDeliveredEvent(anchorOutPoint wire.OutPoint,
+	scriptKey *btcec.PublicKey, err error) *ReceiverProofDeliveredEvent {
+
+	return &ReceiverProofDeliveredEvent{
+		timestamp:      time.Now().UTC(),
+		AnchorOutPoint: anchorOutPoint,
+		ScriptKey:      scriptKey,
+		Err:            err,
+	}
+}
+
+// TransferCompleteEvent is an event which is sent to the ChainPorter's event
+// subscribers once a transfer is complete according to the delivery completion
+// policy.
+type TransferCompleteEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AnchorTXID is the ID of the anchor transaction of the transfer.
+	AnchorTXID chainhash.Hash
+
+	// NumReceivers is the number of receivers the proofs need to be
+	// delivered to.
+	NumReceivers int
+
+	// NumDelivered is the number of receivers the proofs were delivered to
+	// at the time the transfer was completed.
+	NumDelivered int
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferCompleteEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferCompleteEvent creates a new TransferCompleteEvent.
+func NewTransferCompleteEvent(anchorTXID chainhash.Hash, numReceivers,
+	numDelivered int) *TransferCompleteEvent {
+
+	return &TransferCompleteEvent{
+		timestamp:    time.Now().UTC(),
+		AnchorTXID:   anchorTXID,
+		NumReceivers: numReceivers,
+		NumDelivered: numDelivered,
+	}
+}
+
+// TransferAbandonedEvent is an event which is sent to the ChainPorter's event
+// subscribers when the anchor transaction of a transfer can no longer confirm
+// because one of its inputs was double spent by another transaction. This is
+// different from a fee bump, as the transfer can't be rescued with the same
+// inputs.
+type TransferAbandonedEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AnchorTXID is the ID of the abandoned anchor transaction.
+	AnchorTXID chainhash.Hash
+
+	// ConflictingTXID is the ID of the transaction that double spent one of
+	// the inputs of the abandoned anchor transaction.
+	ConflictingTXID chainhash.Hash
+
+	// WillRetry indicates whether a new transfer with fresh coins will be
+	// attempted. A TransferRetryEvent will be sent once that attempt
+	// completed.
+	WillRetry bool
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferAbandonedEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferAbandonedEvent creates a new TransferAbandonedEvent.
+func NewTransferAbandonedEvent(anchorTXID, conflictingTXID chainhash.Hash,
+	willRetry bool) *TransferAbandonedEvent {
+
+	return &TransferAbandonedEvent{
+		timestamp:       time.Now().UTC(),
+		AnchorTXID:      anchorTXID,
+		ConflictingTXID: conflictingTXID,
+		WillRetry:       willRetry,
+	}
+}
+
+// TransferCancelledEvent is an event which is sent to the ChainPorter's event
+// subscribers when a pending transfer was cancelled on request, which released
+// its inputs.
+type TransferCancelledEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AnchorTXID is the ID of the anchor transaction of the cancelled
+	// transfer.
+	AnchorTXID chainhash.Hash
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferCancelledEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferCancelledEvent creates a new TransferCancelledEvent.
+func NewTransferCancelledEvent(
+	anchorTXID chainhash.Hash) *TransferCancelledEvent {
+
+	return &TransferCancelledEvent{
+		timestamp:  time.Now().UTC(),
+		AnchorTXID: anchorTXID,
+	}
+}
+
+// TransferFeeBumpedEvent is an event which is sent to the ChainPorter's event
+// subscribers when the unconfirmed anchor transaction of a transfer was
+// replaced with one that pays a higher fee rate.
+type TransferFeeBumpedEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// OldAnchorTXID is the ID of the anchor transaction that was replaced.
+	OldAnchorTXID chainhash.Hash
+
+	// NewAnchorTXID is the ID of the replacement anchor transaction.
+	NewAnchorTXID chainhash.Hash
+
+	// FeeRate is the fee rate the replacement was created for.
+	FeeRate chainfee.SatPerKWeight
+
+	// ChainFees is the total amount of sats paid in chain fees by the
+	// replacement.
+	ChainFees int64
+
+	// Escalation is the number of the automatic fee escalation of the
+	// transfer the replacement was created for, or zero if the fee bump
+	// was requested manually.
+	Escalation uint32
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferFeeBumpedEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferFeeBumpedEvent creates a new TransferFeeBumpedEvent.
+func NewTransferFeeBumpedEvent(oldAnchorTXID, newAnchorTXID chainhash.Hash,
+	feeRate chainfee.SatPerKWeight, chainFees int64,
+	escalation uint32) *TransferFeeBumpedEvent {
+
+	return &TransferFeeBumpedEvent{
+		timestamp:     time.Now().UTC(),
+		OldAnchorTXID: oldAnchorTXID,
+		NewAnchorTXID: newAnchorTXID,
+		FeeRate:       feeRate,
+		ChainFees:     chainFees,
+		Escalation:    escalation,
 	}
 }
+
+// TransferRetryEvent is an event which is sent to the ChainPorter's event
+// subscribers once an abandoned transfer was re-attempted with a new anchor
+// transaction and freshly selected coins.
+type TransferRetryEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AbandonedTXID is the ID of the abandoned anchor transaction.
+	AbandonedTXID chainhash.Hash
+
+	// NewAnchorTXID is the ID of the new anchor transaction. This is only
+	// set if the new transfer was broadcast successfully.
+	NewAnchorTXID chainhash.Hash
+
+	// Err is the error that prevented the new transfer, if any.
+	Err error
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferRetryEvent) Timestamp() time.Time {
+	return e.timestamp
+}
@@ -1,11 +1,36 @@
 package tapfreighter
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunChainPorter(t *testing.T) {
@@ -20,3 +45,1720 @@ func init() {
 	logWriter.RegisterSubLogger(Subsystem, logger)
 	UseLogger(logger)
 }
+
+// releaseWallet is a wallet anchor that records the outputs it leases and
+// releases, and reports a fixed set of output claims.
+type releaseWallet struct {
+	WalletAnchor
+
+	leased map[wire.OutPoint]time.Duration
+
+	released []wire.OutPoint
+
+	claims map[wire.OutPoint][32]byte
+}
+
+func (w *releaseWallet) LeaseOutput(_ context.Context, op wire.OutPoint,
+	duration time.Duration) error {
+
+	w.leased[op] = duration
+	return nil
+}
+
+func (w *releaseWallet) ReleaseOutput(_ context.Context,
+	op wire.OutPoint) error {
+
+	w.released = append(w.released, op)
+	return nil
+}
+
+func (w *releaseWallet) OutputClaims(
+	context.Context) (map[wire.OutPoint][32]byte, error) {
+
+	return w.claims, nil
+}
+
+// stagedExportLog is an export log that holds a set of pending parcels and
+// records the parcels it unstages.
+type stagedExportLog struct {
+	ExportLog
+
+	parcels []*OutboundParcel
+
+	unstaged []chainhash.Hash
+}
+
+func (l *stagedExportLog) PendingParcels(
+	context.Context) ([]*OutboundParcel, error) {
+
+	return l.parcels, nil
+}
+
+func (l *stagedExportLog) UnstageParcel(_ context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	l.unstaged = append(l.unstaged, anchorTXID)
+	return nil
+}
+
+// TestStagedParcelWalletInputs tests that only the wallet inputs of a staged
+// parcel are leased for the lifetime of the transfer, and that they're
+// released once the parcel is released for broadcast.
+func TestStagedParcelWalletInputs(t *testing.T) {
+	t.Parallel()
+
+	assetInput, walletInput := test.RandOp(t), test.RandOp(t)
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: assetInput})
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: walletInput})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+	anchorTXID := anchorTx.TxHash()
+
+	parcel := &OutboundParcel{
+		AnchorTx: anchorTx,
+		Staged:   true,
+		Inputs: []TransferInput{{
+			PrevID: asset.PrevID{OutPoint: assetInput},
+		}},
+	}
+	exportLog := &stagedExportLog{
+		parcels: []*OutboundParcel{parcel},
+	}
+	wallet := &releaseWallet{
+		leased: make(map[wire.OutPoint]time.Duration),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		Wallet:    wallet,
+		ExportLog: exportLog,
+	})
+
+	err := porter.leaseWalletInputs(
+		context.Background(), parcel, defaultBroadcastCoinLeaseDuration,
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[wire.OutPoint]time.Duration{
+		walletInput: defaultBroadcastCoinLeaseDuration,
+	}, wallet.leased)
+	require.Empty(t, wallet.released)
+
+	// The porter isn't running, so handing the released parcel over for
+	// broadcast fails. It is unstaged and its wallet input released before
+	// that however.
+	close(porter.Quit)
+	_, err = porter.ReleaseStagedParcel(anchorTXID)
+	require.ErrorContains(t, err, "shutting down")
+	require.Equal(t, []chainhash.Hash{anchorTXID}, exportLog.unstaged)
+	require.Equal(t, []wire.OutPoint{walletInput}, wallet.released)
+}
+
+// cancelExportLog is an export log that holds a set of pending parcels and
+// reports the parcels it cancels.
+type cancelExportLog struct {
+	ExportLog
+
+	parcels   []*OutboundParcel
+	cancelled chan chainhash.Hash
+}
+
+func (l *cancelExportLog) PendingParcels(
+	context.Context) ([]*OutboundParcel, error) {
+
+	return l.parcels, nil
+}
+
+func (l *cancelExportLog) CancelParcel(_ context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	l.cancelled <- anchorTXID
+	return nil
+}
+
+// TestAbandonTransfer tests that an abandoned transfer is cancelled and its
+// inputs are released before it is retried.
+func TestAbandonTransfer(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	assetInput, walletInput := test.RandOp(t), test.RandOp(t)
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: assetInput})
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: walletInput})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+	anchorTXID := anchorTx.TxHash()
+
+	exportLog := &cancelExportLog{
+		cancelled: make(chan chainhash.Hash, 1),
+	}
+	wallet := &releaseWallet{}
+	porter := NewChainPorter(&ChainPorterConfig{
+		Wallet:             wallet,
+		ExportLog:          exportLog,
+		AutoRetryAbandoned: true,
+	})
+	events := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	require.NoError(t, porter.RegisterSubscriber(events, false, false))
+
+	pkg := &sendPackage{
+		SendState: SendStateWaitTxConf,
+		Parcel:    NewAddressParcel(0, nil, false, 0, nil, "", ""),
+		OutboundPkg: &OutboundParcel{
+			AnchorTx: anchorTx,
+			Inputs: []TransferInput{{
+				PrevID: asset.PrevID{OutPoint: assetInput},
+			}},
+		},
+	}
+	conflictingTXID := chainhash.Hash{1}
+	err := porter.abandonTransfer(pkg, &chainntnfs.SpendDetail{
+		SpentOutPoint: &walletInput,
+		SpenderTxHash: &conflictingTXID,
+	})
+	require.ErrorIs(t, err, ErrTransferAbandoned)
+
+	// The transfer was cancelled and its wallet input released before the
+	// retry was attempted.
+	require.Equal(t, anchorTXID, <-exportLog.cancelled)
+	require.Equal(t, []wire.OutPoint{walletInput}, wallet.released)
+
+	nextEvent := func() fn.Event {
+		select {
+		case e := <-events.NewItemCreated.ChanOut():
+			return e
+		case <-time.After(timeout):
+			t.Fatalf("event not received")
+			return nil
+		}
+	}
+
+	abandonedEvent, ok := nextEvent().(*TransferAbandonedEvent)
+	require.True(t, ok)
+	require.Equal(t, anchorTXID, abandonedEvent.AnchorTXID)
+	require.Equal(t, conflictingTXID, abandonedEvent.ConflictingTXID)
+	require.True(t, abandonedEvent.WillRetry)
+
+	// The parcel has no addresses to retry the transfer with, so the
+	// retry fails.
+	retryEvent, ok := nextEvent().(*TransferRetryEvent)
+	require.True(t, ok)
+	require.Equal(t, anchorTXID, retryEvent.AbandonedTXID)
+	require.Error(t, retryEvent.Err)
+
+	porter.Wg.Wait()
+}
+
+// TestCancelPendingParcel tests that a pending parcel that isn't waiting for
+// its anchor transaction to confirm is cancelled directly, which releases its
+// inputs and notifies the subscribers, unless another node claimed it.
+func TestCancelPendingParcel(t *testing.T) {
+	t.Parallel()
+
+	assetInput, walletInput := test.RandOp(t), test.RandOp(t)
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: assetInput})
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: walletInput})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+	anchorTXID := anchorTx.TxHash()
+
+	parcel := &OutboundParcel{
+		AnchorTx: anchorTx,
+		Inputs: []TransferInput{{
+			PrevID: asset.PrevID{OutPoint: assetInput},
+		}},
+	}
+	exportLog := &cancelExportLog{
+		parcels:   []*OutboundParcel{parcel},
+		cancelled: make(chan chainhash.Hash, 1),
+	}
+	wallet := &releaseWallet{
+		claims: map[wire.OutPoint][32]byte{
+			walletInput: {1},
+		},
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		Wallet:    wallet,
+		ExportLog: exportLog,
+	})
+	events := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	require.NoError(t, porter.RegisterSubscriber(events, false, false))
+
+	// A transfer that was taken over by another node can't be cancelled.
+	_, err := porter.CancelPendingParcel(anchorTXID)
+	require.ErrorIs(t, err, ErrTransferClaimed)
+	require.Empty(t, exportLog.cancelled)
+	require.Empty(t, wallet.released)
+
+	// Once the claim is gone, the transfer is cancelled and its wallet
+	// input is released.
+	wallet.claims = nil
+	cancelled, err := porter.CancelPendingParcel(anchorTXID)
+	require.NoError(t, err)
+	require.Equal(t, parcel, cancelled)
+	require.Equal(t, anchorTXID, <-exportLog.cancelled)
+	require.Equal(t, []wire.OutPoint{walletInput}, wallet.released)
+
+	select {
+	case e := <-events.NewItemCreated.ChanOut():
+		cancelledEvent, ok := e.(*TransferCancelledEvent)
+		require.True(t, ok)
+		require.Equal(t, anchorTXID, cancelledEvent.AnchorTXID)
+
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event not received")
+	}
+}
+
+// versionsChainBridge is a chain bridge that lets a test confirm any of a set
+// of transactions.
+type versionsChainBridge struct {
+	ChainBridge
+
+	mtx       sync.Mutex
+	confs     map[chainhash.Hash]chan *chainntnfs.TxConfirmation
+	published []chainhash.Hash
+
+	// publishErr, if set, is returned for every published transaction.
+	publishErr error
+}
+
+func (b *versionsChainBridge) confChan(
+	txid chainhash.Hash) chan *chainntnfs.TxConfirmation {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	confs, ok := b.confs[txid]
+	if !ok {
+		confs = make(chan *chainntnfs.TxConfirmation, 1)
+		b.confs[txid] = confs
+	}
+
+	return confs
+}
+
+func (b *versionsChainBridge) RegisterConfirmationsNtfn(_ context.Context,
+	txid *chainhash.Hash, _ []byte, _, _ uint32, _ bool,
+	_ chan struct{}) (*chainntnfs.ConfirmationEvent, chan error, error) {
+
+	return &chainntnfs.ConfirmationEvent{
+		Confirmed: b.confChan(*txid),
+		Cancel:    func() {},
+	}, make(chan error), nil
+}
+
+func (b *versionsChainBridge) PublishTransaction(_ context.Context,
+	tx *wire.MsgTx) error {
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.publishErr != nil {
+		return b.publishErr
+	}
+
+	b.published = append(b.published, tx.TxHash())
+	return nil
+}
+
+// publishedTxs returns the IDs of the transactions published so far.
+func (b *versionsChainBridge) publishedTxs() []chainhash.Hash {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return append([]chainhash.Hash(nil), b.published...)
+}
+
+// replaceExportLog is an export log that holds a set of pending parcels and
+// records the anchor transaction replacements.
+type replaceExportLog struct {
+	ExportLog
+
+	parcels []*OutboundParcel
+
+	replaced map[chainhash.Hash]chainhash.Hash
+
+	escalations fn.Set[chainhash.Hash]
+}
+
+func (l *replaceExportLog) PendingParcels(
+	context.Context) ([]*OutboundParcel, error) {
+
+	return l.parcels, nil
+}
+
+func (l *replaceExportLog) ReplaceParcelAnchorTx(_ context.Context,
+	oldTXID chainhash.Hash, anchorTx *AnchorTransaction,
+	escalation bool) error {
+
+	newTXID := anchorTx.FinalTx.TxHash()
+	l.replaced[oldTXID] = newTXID
+	if escalation {
+		l.escalations.Add(newTXID)
+	}
+
+	return nil
+}
+
+// bumpWallet is an asset wallet that replaces an anchor transaction by paying
+// the additional fee from its first output.
+type bumpWallet struct {
+	Wallet
+}
+
+func (w *bumpWallet) BumpAnchorTxFee(_ context.Context,
+	anchorTx *AnchorTransaction,
+	feeRate chainfee.SatPerKWeight) (*AnchorTransaction, error) {
+
+	finalTx := anchorTx.FinalTx.Copy()
+	finalTx.TxOut[0].Value -= 100
+
+	return &AnchorTransaction{
+		FundedPsbt:    anchorTx.FundedPsbt,
+		FinalTx:       finalTx,
+		TargetFeeRate: feeRate,
+		ChainFees:     anchorTx.ChainFees + 100,
+	}, nil
+}
+
+// TestWaitForReplacedAnchorTxConf tests that a transfer completes if a version
+// of its anchor transaction that was replaced by a fee bump confirms instead
+// of the current version.
+func TestWaitForReplacedAnchorTxConf(t *testing.T) {
+	t.Parallel()
+
+	newAnchorTx := func(value int64) *wire.MsgTx {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Index: 1},
+		})
+		tx.AddTxOut(&wire.TxOut{Value: value, PkScript: []byte{0x51}})
+		return tx
+	}
+	original := &AnchorTransaction{
+		FinalTx:   newAnchorTx(1000),
+		ChainFees: 100,
+	}
+	replacement := &AnchorTransaction{
+		FinalTx:   newAnchorTx(900),
+		ChainFees: 200,
+	}
+	originalTXID := original.FinalTx.TxHash()
+	replacementTXID := replacement.FinalTx.TxHash()
+
+	bridge := &versionsChainBridge{
+		confs: make(map[chainhash.Hash]chan *chainntnfs.TxConfirmation),
+	}
+	exportLog := &replaceExportLog{
+		replaced:    make(map[chainhash.Hash]chainhash.Hash),
+		escalations: fn.NewSet[chainhash.Hash](),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ChainBridge: bridge,
+		ExportLog:   exportLog,
+	})
+
+	pkg := &sendPackage{
+		SendState: SendStateWaitTxConf,
+		AnchorTx:  replacement,
+		OutboundPkg: &OutboundParcel{
+			AnchorTx:  replacement.FinalTx,
+			ChainFees: replacement.ChainFees,
+			Outputs: []TransferOutput{{
+				Anchor: Anchor{
+					OutPoint: wire.OutPoint{
+						Hash: replacementTXID,
+					},
+				},
+			}},
+			ReplacedAnchorTxs: []*AnchorTransaction{original},
+		},
+	}
+	require.True(t, pkg.anchorTxIDs().Contains(originalTXID))
+	require.True(t, pkg.anchorTxIDs().Contains(replacementTXID))
+
+	// The original anchor transaction confirms instead of its replacement.
+	confEvent := &chainntnfs.TxConfirmation{
+		Tx:          original.FinalTx,
+		BlockHeight: 100,
+	}
+	bridge.confChan(originalTXID) <- confEvent
+
+	require.NoError(t, porter.waitForTransferTxConf(pkg))
+
+	// The transfer continues with the original anchor transaction, which
+	// is stored as its anchor transaction again.
+	require.Equal(t, SendStateStoreProofs, pkg.SendState)
+	require.Equal(t, confEvent, pkg.TransferTxConfEvent)
+	require.Equal(t, original, pkg.AnchorTx)
+	require.Empty(t, pkg.OutboundPkg.ReplacedAnchorTxs)
+	require.Equal(t, originalTXID, pkg.OutboundPkg.AnchorTx.TxHash())
+	require.Equal(t, original.ChainFees, pkg.OutboundPkg.ChainFees)
+	require.Equal(
+		t, originalTXID,
+		pkg.OutboundPkg.Outputs[0].Anchor.OutPoint.Hash,
+	)
+	require.Equal(t, originalTXID, exportLog.replaced[replacementTXID])
+
+	close(porter.Quit)
+	porter.Wg.Wait()
+}
+
+// newReplaceableAnchorTx returns a version of an anchor transaction with the
+// given output value, together with the funded PSBT required to replace it.
+func newReplaceableAnchorTx(t *testing.T, prevOut wire.OutPoint, value,
+	chainFees int64) *AnchorTransaction {
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{PreviousOutPoint: prevOut})
+	tx.AddTxOut(&wire.TxOut{Value: value, PkScript: []byte{0x51}})
+
+	pkt, err := psbt.NewFromUnsignedTx(tx.Copy())
+	require.NoError(t, err)
+
+	return &AnchorTransaction{
+		FundedPsbt: &tapgarden.FundedPsbt{Pkt: pkt},
+		FinalTx:    tx,
+		ChainFees:  chainFees,
+	}
+}
+
+// TestBumpParcelFee tests that the anchor transaction of a transfer that is
+// waiting for its confirmation is replaced on request, that the replacement is
+// stored and published, and that the transfer completes with the original
+// anchor transaction if that one confirms instead of the replacement.
+func TestBumpParcelFee(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	original := newReplaceableAnchorTx(t, test.RandOp(t), 10_000, 200)
+	originalTXID := original.FinalTx.TxHash()
+
+	bridge := &versionsChainBridge{
+		confs: make(map[chainhash.Hash]chan *chainntnfs.TxConfirmation),
+	}
+	exportLog := &replaceExportLog{
+		replaced:    make(map[chainhash.Hash]chainhash.Hash),
+		escalations: fn.NewSet[chainhash.Hash](),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ChainBridge: bridge,
+		Wallet:      &releaseWallet{},
+		AssetWallet: &bumpWallet{},
+		ExportLog:   exportLog,
+	})
+	events := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	require.NoError(t, porter.RegisterSubscriber(events, false, false))
+
+	pkg := &sendPackage{
+		SendState: SendStateWaitTxConf,
+		AnchorTx:  original,
+		OutboundPkg: &OutboundParcel{
+			AnchorTx:   original.FinalTx,
+			ChainFees:  original.ChainFees,
+			AnchorPsbt: original.FundedPsbt,
+			Outputs: []TransferOutput{{
+				Anchor: Anchor{
+					OutPoint: wire.OutPoint{
+						Hash: originalTXID,
+					},
+				},
+			}},
+		},
+	}
+
+	// As long as no transfer is waiting for the anchor transaction to
+	// confirm, there is nothing to bump.
+	_, err := porter.BumpParcelFee(originalTXID, 5000)
+	require.ErrorContains(t, err, "isn't waiting")
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- porter.waitForTransferTxConf(pkg)
+	}()
+	require.Eventually(t, func() bool {
+		_, ok := porter.feeBumpHandler(originalTXID)
+		return ok
+	}, timeout, 10*time.Millisecond)
+
+	bumped, err := porter.BumpParcelFee(originalTXID, 5000)
+	require.NoError(t, err)
+
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+
+	case <-time.After(timeout):
+		t.Fatalf("fee bump not handled")
+	}
+
+	// The replacement was stored and published, and the original is kept
+	// as a replaced version of the anchor transaction.
+	newTXID := bumped.AnchorTx.TxHash()
+	require.NotEqual(t, originalTXID, newTXID)
+	require.Equal(t, bumped, pkg.OutboundPkg)
+	require.Equal(t, SendStateWaitTxConf, pkg.SendState)
+	require.Equal(t, original.ChainFees+100, bumped.ChainFees)
+	require.Equal(t, newTXID, bumped.Outputs[0].Anchor.OutPoint.Hash)
+	require.Equal(
+		t, []*AnchorTransaction{original}, bumped.ReplacedAnchorTxs,
+	)
+	require.Zero(t, bumped.FeeEscalations)
+	require.Equal(t, newTXID, exportLog.replaced[originalTXID])
+	require.False(t, exportLog.escalations.Contains(newTXID))
+	require.Equal(t, []chainhash.Hash{newTXID}, bridge.publishedTxs())
+
+	select {
+	case e := <-events.NewItemCreated.ChanOut():
+		bumpEvent, ok := e.(*TransferFeeBumpedEvent)
+		require.True(t, ok)
+		require.Equal(t, originalTXID, bumpEvent.OldAnchorTXID)
+		require.Equal(t, newTXID, bumpEvent.NewAnchorTXID)
+		require.Zero(t, bumpEvent.Escalation)
+
+	case <-time.After(timeout):
+		t.Fatalf("event not received")
+	}
+
+	// The original anchor transaction confirms instead of the
+	// replacement, so the transfer continues with the original.
+	confEvent := &chainntnfs.TxConfirmation{
+		Tx:          original.FinalTx,
+		BlockHeight: 100,
+	}
+	bridge.confChan(originalTXID) <- confEvent
+
+	require.NoError(t, porter.waitForTransferTxConf(pkg))
+	require.Equal(t, SendStateStoreProofs, pkg.SendState)
+	require.Equal(t, confEvent, pkg.TransferTxConfEvent)
+	require.Equal(t, originalTXID, pkg.OutboundPkg.AnchorTx.TxHash())
+	require.Empty(t, pkg.OutboundPkg.ReplacedAnchorTxs)
+	require.Equal(t, originalTXID, exportLog.replaced[newTXID])
+
+	close(porter.Quit)
+	porter.Wg.Wait()
+}
+
+// TestResumedTransferReplacedAnchorTx tests that the versions of the anchor
+// transaction of a transfer that were replaced by fee bumps are still watched
+// after a restart. If a replaced version confirmed while the node was down,
+// the current version can't be published anymore, and the transfer completes
+// with the replaced version.
+func TestResumedTransferReplacedAnchorTx(t *testing.T) {
+	t.Parallel()
+
+	prevOut := test.RandOp(t)
+	original := newReplaceableAnchorTx(t, prevOut, 10_000, 200)
+	replacement := newReplaceableAnchorTx(t, prevOut, 9_900, 300)
+	originalTXID := original.FinalTx.TxHash()
+	replacementTXID := replacement.FinalTx.TxHash()
+
+	// The parcel is loaded as it was stored, with the replacement as its
+	// current anchor transaction.
+	parcel := &OutboundParcel{
+		AnchorTx:          replacement.FinalTx,
+		ChainFees:         replacement.ChainFees,
+		AnchorPsbt:        replacement.FundedPsbt,
+		ReplacedAnchorTxs: []*AnchorTransaction{original},
+		Outputs: []TransferOutput{{
+			Anchor: Anchor{
+				OutPoint: wire.OutPoint{
+					Hash: replacementTXID,
+				},
+			},
+		}},
+	}
+
+	bridge := &versionsChainBridge{
+		confs: make(map[chainhash.Hash]chan *chainntnfs.TxConfirmation),
+	}
+	bridge.publishErr = errors.New("txn-mempool-conflict")
+	exportLog := &replaceExportLog{
+		parcels:     []*OutboundParcel{parcel},
+		replaced:    make(map[chainhash.Hash]chainhash.Hash),
+		escalations: fn.NewSet[chainhash.Hash](),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ChainBridge: bridge,
+		Wallet:      &releaseWallet{},
+		AssetWallet: &bumpWallet{},
+		ExportLog:   exportLog,
+	})
+
+	pkg := NewPendingParcel(parcel).pkg()
+	require.Equal(t, SendStateBroadcast, pkg.SendState)
+	require.True(t, pkg.anchorTxIDs().Contains(originalTXID))
+
+	// The original confirmed while we were down, so the replacement can't
+	// be published. We wait for any version to confirm instead.
+	pkg, err := porter.stateStep(*pkg)
+	require.NoError(t, err)
+	require.Equal(t, SendStateWaitTxConf, pkg.SendState)
+
+	confEvent := &chainntnfs.TxConfirmation{
+		Tx:          original.FinalTx,
+		BlockHeight: 100,
+	}
+	bridge.confChan(originalTXID) <- confEvent
+
+	pkg, err = porter.stateStep(*pkg)
+	require.NoError(t, err)
+	require.Equal(t, SendStateStoreProofs, pkg.SendState)
+	require.Equal(t, confEvent, pkg.TransferTxConfEvent)
+	require.Equal(t, originalTXID, pkg.OutboundPkg.AnchorTx.TxHash())
+	require.Empty(t, pkg.OutboundPkg.ReplacedAnchorTxs)
+	require.Equal(t, originalTXID, exportLog.replaced[replacementTXID])
+
+	// Without any replaced versions, a transfer whose anchor transaction
+	// can't be published fails as before.
+	parcel.ReplacedAnchorTxs = nil
+	_, err = porter.stateStep(*NewPendingParcel(parcel).pkg())
+	require.ErrorContains(t, err, "txn-mempool-conflict")
+
+	close(porter.Quit)
+	porter.Wg.Wait()
+}
+
+// reOrgRegistration is a confirmation notification registered with the
+// reOrgChainBridge.
+type reOrgRegistration struct {
+	heightHint uint32
+
+	reOrgChan chan struct{}
+
+	confirmed chan *chainntnfs.TxConfirmation
+}
+
+// reOrgChainBridge is a chain bridge that lets a test re-organize the anchor
+// transaction of a confirmed transfer out of the chain and spend its inputs.
+type reOrgChainBridge struct {
+	ChainBridge
+
+	registrations chan reOrgRegistration
+
+	spends chan chan *chainntnfs.SpendDetail
+
+	published chan chainhash.Hash
+}
+
+func (b *reOrgChainBridge) RegisterConfirmationsNtfn(_ context.Context,
+	_ *chainhash.Hash, _ []byte, _, heightHint uint32, _ bool,
+	reOrgChan chan struct{}) (*chainntnfs.ConfirmationEvent, chan error,
+	error) {
+
+	confirmed := make(chan *chainntnfs.TxConfirmation, 1)
+	b.registrations <- reOrgRegistration{
+		heightHint: heightHint,
+		reOrgChan:  reOrgChan,
+		confirmed:  confirmed,
+	}
+
+	return &chainntnfs.ConfirmationEvent{
+		Confirmed: confirmed,
+		Cancel:    func() {},
+	}, make(chan error), nil
+}
+
+func (b *reOrgChainBridge) RegisterSpendNtfn(_ context.Context,
+	_ *wire.OutPoint, _ []byte, _ uint32) (*chainntnfs.SpendEvent,
+	chan error, error) {
+
+	spend := make(chan *chainntnfs.SpendDetail, 1)
+	b.spends <- spend
+
+	return &chainntnfs.SpendEvent{
+		Spend:  spend,
+		Cancel: func() {},
+	}, make(chan error), nil
+}
+
+func (b *reOrgChainBridge) PublishTransaction(_ context.Context,
+	tx *wire.MsgTx) error {
+
+	b.published <- tx.TxHash()
+	return nil
+}
+
+// reOrgWatchUpdate is an update of a re-org watch recorded by the
+// reOrgExportLog.
+type reOrgWatchUpdate struct {
+	confHeight uint32
+
+	abandonPending bool
+}
+
+// reOrgExportLog is an export log that records the parcels it abandons and
+// quarantines and the updates of their re-org watches.
+type reOrgExportLog struct {
+	ExportLog
+
+	abandonErr error
+
+	watches []*ReOrgWatch
+
+	abandoned chan chainhash.Hash
+
+	quarantined chan chainhash.Hash
+
+	updated chan reOrgWatchUpdate
+
+	removed chan chainhash.Hash
+}
+
+func newReOrgExportLog(abandonErr error) *reOrgExportLog {
+	return &reOrgExportLog{
+		abandonErr:  abandonErr,
+		abandoned:   make(chan chainhash.Hash, 1),
+		quarantined: make(chan chainhash.Hash, 1),
+		updated:     make(chan reOrgWatchUpdate, 1),
+		removed:     make(chan chainhash.Hash, 1),
+	}
+}
+
+func (l *reOrgExportLog) AbandonParcel(_ context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	if l.abandonErr != nil {
+		return l.abandonErr
+	}
+
+	l.abandoned <- anchorTXID
+	return nil
+}
+
+func (l *reOrgExportLog) QuarantineParcel(_ context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	l.quarantined <- anchorTXID
+	return nil
+}
+
+func (l *reOrgExportLog) ReOrgWatches(
+	context.Context) ([]*ReOrgWatch, error) {
+
+	return l.watches, nil
+}
+
+func (l *reOrgExportLog) UpdateReOrgWatch(_ context.Context, _ chainhash.Hash,
+	confHeight uint32, abandonPending bool) error {
+
+	l.updated <- reOrgWatchUpdate{
+		confHeight:     confHeight,
+		abandonPending: abandonPending,
+	}
+	return nil
+}
+
+func (l *reOrgExportLog) RemoveReOrgWatch(_ context.Context,
+	anchorTXID chainhash.Hash) error {
+
+	l.removed <- anchorTXID
+	return nil
+}
+
+// newReOrgTestTx creates an anchor transaction spending the given wallet input
+// and the re-org watch of a parcel confirmed at height 100 with it.
+func newReOrgTestTx(walletInput wire.OutPoint,
+	withInputs bool) (*wire.MsgTx, *ReOrgWatch) {
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: walletInput,
+	})
+	anchorTx.AddTxOut(&wire.TxOut{
+		Value: 1000, PkScript: []byte{0x51},
+	})
+
+	watch := &ReOrgWatch{
+		Parcel: &OutboundParcel{
+			AnchorTx:           anchorTx,
+			AnchorTxHeightHint: 90,
+		},
+		ConfHeight: 100,
+	}
+	if withInputs {
+		watch.Inputs = []AnchorInput{{
+			OutPoint: walletInput,
+			PkScript: []byte{0x51},
+		}}
+	}
+
+	return anchorTx, watch
+}
+
+// TestTransferReOrgPolicy tests that the configured re-org policy is applied
+// once the anchor transaction of a confirmed transfer is re-organized out of
+// the chain.
+func TestTransferReOrgPolicy(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	testCases := []struct {
+		name         string
+		policy       ReOrgPolicy
+		noInputs     bool
+		abandonErr   error
+		appliedAs    ReOrgPolicy
+		keepWatching bool
+	}{{
+		name:         "rebroadcast",
+		policy:       ReOrgPolicyRebroadcast,
+		appliedAs:    ReOrgPolicyRebroadcast,
+		keepWatching: true,
+	}, {
+		name:      "abandon",
+		policy:    ReOrgPolicyAbandon,
+		appliedAs: ReOrgPolicyAbandon,
+	}, {
+		name:       "abandon passive assets",
+		policy:     ReOrgPolicyAbandon,
+		abandonErr: ErrTransferRollbackUnsupported,
+		appliedAs:  ReOrgPolicyQuarantine,
+	}, {
+		name:      "abandon unknown inputs",
+		policy:    ReOrgPolicyAbandon,
+		noInputs:  true,
+		appliedAs: ReOrgPolicyQuarantine,
+	}, {
+		name:      "quarantine",
+		policy:    ReOrgPolicyQuarantine,
+		appliedAs: ReOrgPolicyQuarantine,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			walletInput := test.RandOp(t)
+			anchorTx, watch := newReOrgTestTx(
+				walletInput, !tc.noInputs,
+			)
+			anchorTXID := anchorTx.TxHash()
+
+			bridge := &reOrgChainBridge{
+				registrations: make(chan reOrgRegistration, 1),
+				spends: make(
+					chan chan *chainntnfs.SpendDetail, 1,
+				),
+				published: make(chan chainhash.Hash, 1),
+			}
+			exportLog := newReOrgExportLog(tc.abandonErr)
+			wallet := &releaseWallet{}
+			porter := NewChainPorter(&ChainPorterConfig{
+				Wallet:         wallet,
+				ExportLog:      exportLog,
+				ChainBridge:    bridge,
+				ReOrgPolicy:    tc.policy,
+				ReOrgSafeDepth: 6,
+			})
+			events := fn.NewEventReceiver[fn.Event](
+				fn.DefaultQueueSize,
+			)
+			err := porter.RegisterSubscriber(events, false, false)
+			require.NoError(t, err)
+
+			porter.Wg.Add(1)
+			go porter.watchTransferReOrg(watch)
+
+			var reg reOrgRegistration
+			select {
+			case reg = <-bridge.registrations:
+			case <-time.After(timeout):
+				t.Fatalf("transfer not watched for re-orgs")
+			}
+			require.EqualValues(t, 100, reg.heightHint)
+
+			// The anchor transaction is re-organized out of the
+			// chain, which triggers the policy.
+			reg.reOrgChan <- struct{}{}
+
+			// A transfer is only abandoned once one of its inputs
+			// is double spent, which is persisted as pending
+			// first.
+			if tc.policy == ReOrgPolicyAbandon && !tc.noInputs {
+				update := <-exportLog.updated
+				require.True(t, update.abandonPending)
+
+				var spendChan chan *chainntnfs.SpendDetail
+				select {
+				case spendChan = <-bridge.spends:
+				case <-time.After(timeout):
+					t.Fatalf("inputs not watched")
+				}
+
+				spendChan <- &chainntnfs.SpendDetail{
+					SpentOutPoint:  &walletInput,
+					SpenderTxHash:  fn.Ptr(test.RandHash()),
+					SpendingHeight: 101,
+				}
+			}
+
+			var reOrgEvent *TransferReOrgEvent
+			select {
+			case e := <-events.NewItemCreated.ChanOut():
+				var ok bool
+				reOrgEvent, ok = e.(*TransferReOrgEvent)
+				require.True(t, ok)
+
+			case <-time.After(timeout):
+				t.Fatalf("re-org event not received")
+			}
+			require.Equal(t, anchorTXID, reOrgEvent.AnchorTXID)
+			require.Equal(t, tc.appliedAs, reOrgEvent.Policy)
+			require.NoError(t, reOrgEvent.Err)
+
+			switch tc.appliedAs {
+			case ReOrgPolicyRebroadcast:
+				require.Equal(t, anchorTXID, <-bridge.published)
+
+			case ReOrgPolicyAbandon:
+				require.Equal(
+					t, anchorTXID, <-exportLog.abandoned,
+				)
+				require.Equal(
+					t, []wire.OutPoint{walletInput},
+					wallet.released,
+				)
+
+			case ReOrgPolicyQuarantine:
+				require.Equal(
+					t, anchorTXID, <-exportLog.quarantined,
+				)
+				require.Empty(t, wallet.released)
+			}
+
+			// A re-broadcast transfer is still watched, so another
+			// re-org is handled as well. Otherwise, the watcher is
+			// done.
+			if tc.keepWatching {
+				reg.reOrgChan <- struct{}{}
+				require.Equal(t, anchorTXID, <-bridge.published)
+			} else {
+				porter.Wg.Wait()
+			}
+
+			close(porter.Quit)
+			porter.Wg.Wait()
+		})
+	}
+}
+
+// TestTransferReOrgWatchResume tests that a persisted re-org watch is resumed
+// after a restart, that a transfer pending to be abandoned is watched for
+// re-orgs again if its anchor transaction confirms again, and that the watch
+// is removed once the anchor transaction reached the safe depth.
+func TestTransferReOrgWatchResume(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	walletInput := test.RandOp(t)
+	anchorTx, watch := newReOrgTestTx(walletInput, true)
+	anchorTXID := anchorTx.TxHash()
+
+	// The daemon was shut down while the transfer was pending to be
+	// abandoned.
+	watch.AbandonPending = true
+
+	bridge := &reOrgChainBridge{
+		registrations: make(chan reOrgRegistration, 1),
+		spends:        make(chan chan *chainntnfs.SpendDetail, 1),
+	}
+	exportLog := newReOrgExportLog(nil)
+	exportLog.watches = []*ReOrgWatch{watch}
+	porter := NewChainPorter(&ChainPorterConfig{
+		Wallet:         &releaseWallet{},
+		ExportLog:      exportLog,
+		ChainBridge:    bridge,
+		ReOrgPolicy:    ReOrgPolicyAbandon,
+		ReOrgSafeDepth: 6,
+	})
+
+	ctx := context.Background()
+	require.NoError(t, porter.resumeReOrgWatches(ctx))
+
+	// The pending abandon is resumed by watching the inputs of the anchor
+	// transaction right away.
+	var spendChan chan *chainntnfs.SpendDetail
+	select {
+	case spendChan = <-bridge.spends:
+	case <-time.After(timeout):
+		t.Fatalf("inputs not watched")
+	}
+
+	// The anchor transaction itself confirms again, so the transfer isn't
+	// abandoned but watched for re-orgs from its new height.
+	spendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint:  &walletInput,
+		SpenderTxHash:  &anchorTXID,
+		SpendingHeight: 105,
+	}
+
+	update := <-exportLog.updated
+	require.False(t, update.abandonPending)
+	require.EqualValues(t, 105, update.confHeight)
+
+	var reg reOrgRegistration
+	select {
+	case reg = <-bridge.registrations:
+	case <-time.After(timeout):
+		t.Fatalf("transfer not watched for re-orgs")
+	}
+	require.EqualValues(t, 105, reg.heightHint)
+
+	// Once the anchor transaction reached the safe depth, the watch is
+	// removed.
+	reg.confirmed <- &chainntnfs.TxConfirmation{}
+	require.Equal(t, anchorTXID, <-exportLog.removed)
+
+	porter.Wg.Wait()
+	require.Empty(t, exportLog.abandoned)
+
+	close(porter.Quit)
+}
+
+// TestSendStateMachine makes sure the send state machine definition covers all
+// send states and that every state can be reached and leads to completion.
+func TestSendStateMachine(t *testing.T) {
+	t.Parallel()
+
+	states := SendStates()
+	require.Len(t, states, int(SendStateCancelled)+1)
+	require.Equal(t, SendStateVirtualCommitmentSelect, states[0])
+	require.Equal(t, SendStateCancelled, states[len(states)-1])
+	require.Empty(t, SendStateComplete.Successors())
+	require.Empty(t, SendStateCancelled.Successors())
+
+	reachable := fn.NewSet[SendState]()
+	for _, state := range states {
+		require.NotContains(t, state.String(), "unknown")
+
+		if state.IsInitial() {
+			reachable.Add(state)
+		}
+
+		// Apart from the terminal states, each state needs to lead to a
+		// later state, so a transfer can't go around in circles.
+		if !state.IsTerminal() {
+			require.NotEmpty(t, state.Successors(), state)
+		}
+		for _, successor := range state.Successors() {
+			require.Greater(t, successor, state)
+			reachable.Add(successor)
+		}
+	}
+
+	for _, state := range states {
+		require.True(t, reachable.Contains(state), state)
+	}
+}
+
+// TestAddressParcelFeeRate tests that an address parcel with a fee rate below
+// the minimum relay fee rate is rejected.
+func TestAddressParcelFeeRate(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+
+	// A zero fee rate means the fee rate is estimated.
+	parcel := NewAddressParcel(0, nil, false, 0, nil, "", "", addr.Tap)
+	require.NoError(t, parcel.Validate())
+
+	parcel = NewAddressParcel(
+		0, nil, false, chainfee.FeePerKwFloor, nil, "", "", addr.Tap,
+	)
+	require.NoError(t, parcel.Validate())
+
+	parcel = NewAddressParcel(
+		0, nil, false, chainfee.FeePerKwFloor-1, nil, "", "", addr.Tap,
+	)
+	require.ErrorContains(t, parcel.Validate(), "minimum relay fee")
+}
+
+// TestAddressParcelProofCourier tests that an address parcel only overrides
+// the proof courier of its addresses with a courier the receivers share.
+func TestAddressParcelProofCourier(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+
+	parseURL := func(addr string) *url.URL {
+		courierURL, err := url.ParseRequestURI(addr)
+		require.NoError(t, err)
+
+		return courierURL
+	}
+
+	// Without an override, the proof courier of the address is used.
+	parcel := NewAddressParcel(0, nil, false, 0, nil, "", "", addr.Tap)
+	require.NoError(t, parcel.Validate())
+	require.Equal(t, addr.Tap, parcel.sendAddrs()[0])
+
+	// The courier of the address can be replaced by a URL that points to
+	// the same courier service. The address itself is left untouched.
+	override := parseURL("hashmail://rand.hashmail.proof.courier:443/x")
+	parcel = NewAddressParcel(0, nil, false, 0, override, "", "", addr.Tap)
+	require.NoError(t, parcel.Validate())
+
+	sendAddr := parcel.sendAddrs()[0]
+	require.Equal(t, *override, sendAddr.ProofCourierAddr)
+	require.Equal(t, addr.TaprootOutputKey, sendAddr.TaprootOutputKey)
+	require.Equal(
+		t, address.RandProofCourierAddr(t), addr.ProofCourierAddr,
+	)
+
+	// The receiver doesn't listen on any other courier for the proof,
+	// whether it uses a different protocol, host or port.
+	for _, other := range []string{
+		"universerpc://rand.hashmail.proof.courier:443",
+		"hashmail://other.proof.courier:443",
+		"hashmail://rand.hashmail.proof.courier:10029",
+	} {
+		override = parseURL(other)
+		parcel = NewAddressParcel(
+			0, nil, false, 0, override, "", "", addr.Tap,
+		)
+		require.ErrorContains(t, parcel.Validate(), "isn't shared")
+	}
+
+	// An override that isn't a courier at all is rejected.
+	override = parseURL("ftp://other.proof.courier:21")
+	parcel = NewAddressParcel(0, nil, false, 0, override, "", "", addr.Tap)
+	require.ErrorContains(t, parcel.Validate(), "invalid override")
+}
+
+// TestAddressParcelLabel tests that an address parcel with a label that exceeds
+// the maximum length is rejected.
+func TestAddressParcelLabel(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+
+	label := strings.Repeat("a", MaxTransferLabelLen)
+	parcel := NewAddressParcel(0, nil, false, 0, nil, label, "", addr.Tap)
+	require.NoError(t, parcel.Validate())
+	require.Equal(t, label, parcel.pkg().Label)
+
+	label += "a"
+	parcel = NewAddressParcel(0, nil, false, 0, nil, label, "", addr.Tap)
+	require.ErrorContains(t, parcel.Validate(), "exceeds the maximum")
+}
+
+// idempotencyExportLog is an export log that holds a set of parcels keyed by
+// the idempotency key they were requested with.
+type idempotencyExportLog struct {
+	ExportLog
+
+	parcels map[string]*OutboundParcel
+
+	since time.Time
+}
+
+func (l *idempotencyExportLog) QueryParcelByIdempotencyKey(
+	_ context.Context, key string, since time.Time) (*OutboundParcel,
+	error) {
+
+	l.since = since
+	return l.parcels[key], nil
+}
+
+// TestIdempotencyKey tests that a send request with an idempotency key returns
+// the transfer that was already requested with the same key, and that only a
+// single request with the same key can be in progress at a time.
+func TestIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+
+	key := strings.Repeat("a", MaxIdempotencyKeyLen)
+	parcel := NewAddressParcel(0, nil, false, 0, nil, "", key, addr.Tap)
+	require.NoError(t, parcel.Validate())
+	require.Equal(t, key, parcel.pkg().IdempotencyKey)
+
+	parcel = NewAddressParcel(0, nil, false, 0, nil, "", key+"a", addr.Tap)
+	require.ErrorContains(t, parcel.Validate(), "exceeds the maximum")
+
+	// The existing transfer pays the address and sends the change back to
+	// ourselves.
+	existing := &OutboundParcel{
+		AnchorTx: wire.NewMsgTx(2),
+		Outputs: []TransferOutput{{
+			ScriptKey: asset.NewScriptKey(&addr.ScriptKey),
+			AssetID:   addr.AssetID,
+			Amount:    addr.Amount,
+		}, {
+			ScriptKey:      asset.RandScriptKey(t),
+			ScriptKeyLocal: true,
+			AssetID:        addr.AssetID,
+			Amount:         10,
+		}},
+	}
+	exportLog := &idempotencyExportLog{
+		parcels: map[string]*OutboundParcel{
+			"existing": existing,
+		},
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ExportLog: exportLog,
+	})
+	newParcel := func(key string, addrs ...*address.Tap) *AddressParcel {
+		return NewAddressParcel(
+			0, nil, false, 0, nil, "", key, addrs...,
+		)
+	}
+
+	// A key that was already used returns the existing transfer, looking
+	// back as far as the default retention.
+	start := time.Now()
+	found, err := porter.reserveIdempotencyKey(
+		newParcel("existing", addr.Tap),
+	)
+	require.NoError(t, err)
+	require.Equal(t, existing, found)
+	require.WithinRange(
+		t, exportLog.since,
+		start.Add(-DefaultIdempotencyKeyRetention),
+		time.Now().Add(-DefaultIdempotencyKeyRetention),
+	)
+
+	// A new key is reserved until it's released again.
+	found, err = porter.reserveIdempotencyKey(newParcel("new", addr.Tap))
+	require.NoError(t, err)
+	require.Nil(t, found)
+
+	_, err = porter.reserveIdempotencyKey(newParcel("new", addr.Tap))
+	require.ErrorContains(t, err, "already in progress")
+
+	porter.releaseIdempotencyKey("new")
+	found, err = porter.reserveIdempotencyKey(newParcel("new", addr.Tap))
+	require.NoError(t, err)
+	require.Nil(t, found)
+}
+
+// TestIdempotencyKeyMismatch tests that an idempotency key can't be re-used for
+// a send request that pays different addresses or amounts than the transfer
+// that was created with the key.
+func TestIdempotencyKeyMismatch(t *testing.T) {
+	t.Parallel()
+
+	addr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+	otherAddr, _, _ := address.RandAddr(
+		t, &address.RegressionNetTap, address.RandProofCourierAddr(t),
+	)
+
+	existing := &OutboundParcel{
+		AnchorTx: wire.NewMsgTx(2),
+		Outputs: []TransferOutput{{
+			ScriptKey: asset.NewScriptKey(&addr.ScriptKey),
+			AssetID:   addr.AssetID,
+			Amount:    addr.Amount,
+		}},
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ExportLog: &idempotencyExportLog{
+			parcels: map[string]*OutboundParcel{
+				"existing": existing,
+			},
+		},
+	})
+
+	otherAmount := addr.Tap.Copy()
+	otherAmount.Amount++
+
+	testCases := []struct {
+		name  string
+		addrs []*address.Tap
+	}{{
+		name:  "different address",
+		addrs: []*address.Tap{otherAddr.Tap},
+	}, {
+		name:  "different amount",
+		addrs: []*address.Tap{otherAmount},
+	}, {
+		name:  "additional address",
+		addrs: []*address.Tap{addr.Tap, otherAddr.Tap},
+	}}
+
+	for _, testCase := range testCases {
+		parcel := NewAddressParcel(
+			0, nil, false, 0, nil, "", "existing",
+			testCase.addrs...,
+		)
+		_, err := porter.reserveIdempotencyKey(parcel)
+		require.ErrorContains(
+			t, err, "used for a different send", testCase.name,
+		)
+	}
+
+	// The mismatching requests didn't reserve the key, so the same
+	// request can still be retried.
+	parcel := NewAddressParcel(
+		0, nil, false, 0, nil, "", "existing", addr.Tap,
+	)
+	found, err := porter.reserveIdempotencyKey(parcel)
+	require.NoError(t, err)
+	require.Equal(t, existing, found)
+}
+
+// TestPreAnchoredParcel tests that a pre-anchored parcel requires an anchor
+// packet and resumes the send state machine at the anchor signing state.
+func TestPreAnchoredParcel(t *testing.T) {
+	t.Parallel()
+
+	vPkt := &tappsbt.VPacket{}
+	parcel := NewPreAnchoredParcel(vPkt, nil, nil, nil, -1, nil, "")
+	require.ErrorContains(t, parcel.Validate(), "missing anchor")
+
+	label := strings.Repeat("a", MaxTransferLabelLen+1)
+	parcel = NewPreAnchoredParcel(
+		vPkt, nil, nil, &psbt.Packet{}, -1, nil, label,
+	)
+	require.ErrorContains(t, parcel.Validate(), "exceeds the maximum")
+
+	parcel = NewPreAnchoredParcel(
+		vPkt, nil, nil, &psbt.Packet{}, -1, nil, "external",
+	)
+	require.NoError(t, parcel.Validate())
+
+	pkg := parcel.pkg()
+	require.Equal(t, SendStateAnchorSign, pkg.SendState)
+	require.Equal(t, "external", pkg.Label)
+	require.Same(t, vPkt, pkg.VirtualPackets[0])
+}
+
+// TestUseFallbackCourier tests that only deliveries that failed all their
+// attempts through a courier other than the fallback courier fall back.
+func TestUseFallbackCourier(t *testing.T) {
+	t.Parallel()
+
+	parseAddr := func(addr string) proof.CourierAddr {
+		courierAddr, err := proof.ParseCourierAddrString(addr)
+		require.NoError(t, err)
+
+		return courierAddr
+	}
+	primary := parseAddr("hashmail://mailbox.example.com:443")
+	fallback := parseAddr("universerpc://universe.example.com:10029")
+
+	courierCfg := &proof.CourierCfg{}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ProofCourierCfg: courierCfg,
+	})
+
+	ctx := context.Background()
+	exhaustedErr := fmt.Errorf("delivery failed: %w",
+		&proof.BackoffExecError{})
+
+	// Without a fallback courier, there's nothing to fall back to.
+	require.False(t, porter.useFallbackCourier(ctx, primary, exhaustedErr))
+
+	courierCfg.FallbackCourierAddr = fallback.Url()
+	require.True(t, porter.useFallbackCourier(ctx, primary, exhaustedErr))
+
+	// Successful deliveries and errors that aren't caused by exhausted
+	// delivery attempts don't fall back.
+	require.False(t, porter.useFallbackCourier(ctx, primary, nil))
+	require.False(t, porter.useFallbackCourier(
+		ctx, primary, errors.New("invalid proof"),
+	))
+
+	// A delivery through the fallback courier itself doesn't fall back.
+	require.False(t, porter.useFallbackCourier(
+		ctx, fallback, exhaustedErr,
+	))
+
+	// Neither does a delivery that was interrupted by a shutdown.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.False(t, porter.useFallbackCourier(
+		cancelledCtx, primary, exhaustedErr,
+	))
+}
+
+type deliveryExportLog struct {
+	ExportLog
+
+	parcels    []*OutboundParcel
+	deliveries chan ProofDelivery
+}
+
+func (l *deliveryExportLog) PendingProofDeliveries(
+	context.Context) ([]*OutboundParcel, error) {
+
+	return l.parcels, nil
+}
+
+func (l *deliveryExportLog) LogProofDelivery(_ context.Context,
+	_ wire.OutPoint, _ *btcec.PublicKey, delivery ProofDelivery) error {
+
+	l.deliveries <- delivery
+	return nil
+}
+
+type fetchArchiver struct {
+	proof.Archiver
+
+	fetched chan proof.Locator
+}
+
+func (a *fetchArchiver) FetchProof(_ context.Context,
+	locator proof.Locator) (proof.Blob, error) {
+
+	a.fetched <- locator
+	return proof.Blob{0x01}, nil
+}
+
+// TestResumeProofDeliveries tests that only the proof deliveries that are
+// still pending are resumed, using the proofs from the proof archive.
+func TestResumeProofDeliveries(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	newOutput := func(status ProofDeliveryStatus) TransferOutput {
+		return TransferOutput{
+			Anchor: Anchor{
+				OutPoint: test.RandOp(t),
+			},
+			ScriptKey: asset.NewScriptKey(test.RandPubKey(t)),
+			AssetID:   asset.RandID(t),
+			ProofDelivery: ProofDelivery{
+				Status: status,
+			},
+		}
+	}
+	pending := newOutput(ProofDeliveryStatusPending)
+
+	exportLog := &deliveryExportLog{
+		parcels: []*OutboundParcel{{
+			AnchorTx: wire.NewMsgTx(2),
+			Outputs: []TransferOutput{
+				newOutput(ProofDeliveryStatusDelivered),
+				pending,
+				newOutput(ProofDeliveryStatusFailed),
+			},
+		}},
+		deliveries: make(chan ProofDelivery, 3),
+	}
+	archiver := &fetchArchiver{
+		fetched: make(chan proof.Locator, 3),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ExportLog:       exportLog,
+		AssetProofs:     archiver,
+		ProofCourierCfg: &proof.CourierCfg{},
+	})
+
+	require.NoError(t, porter.resumeProofDeliveries(context.Background()))
+
+	// The proof of the pending output is fetched from the archive. As
+	// there's no courier configured for the interactive receiver, the
+	// delivery is skipped.
+	select {
+	case locator := <-archiver.fetched:
+		require.Equal(t, pending.AssetID, *locator.AssetID)
+		require.Equal(t, *pending.ScriptKey.PubKey, locator.ScriptKey)
+		require.Equal(t, pending.Anchor.OutPoint, *locator.OutPoint)
+
+	case <-time.After(timeout):
+		t.Fatalf("proof not fetched")
+	}
+
+	select {
+	case delivery := <-exportLog.deliveries:
+		require.Equal(t, ProofDeliveryStatusSkipped, delivery.Status)
+
+	case <-time.After(timeout):
+		t.Fatalf("delivery outcome not recorded")
+	}
+
+	porter.Wg.Wait()
+	require.Empty(t, archiver.fetched)
+	require.Empty(t, exportLog.deliveries)
+
+	// Without a proof courier config, nothing is resumed.
+	porter = NewChainPorter(&ChainPorterConfig{
+		ExportLog:   exportLog,
+		AssetProofs: archiver,
+	})
+	require.NoError(t, porter.resumeProofDeliveries(context.Background()))
+	porter.Wg.Wait()
+	require.Empty(t, archiver.fetched)
+}
+
+// countingKeyRing is a key ring that counts the keys derived from it and
+// treats all keys as local.
+type countingKeyRing struct {
+	KeyRing
+
+	numDerived int
+}
+
+func (k *countingKeyRing) DeriveNextKey(context.Context,
+	keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	privKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+
+	k.numDerived++
+	return keychain.KeyDescriptor{PubKey: privKey.PubKey()}, nil
+}
+
+func (k *countingKeyRing) IsLocalKey(context.Context,
+	keychain.KeyDescriptor) bool {
+
+	return true
+}
+
+// unknownKeyAddrBook is an address book that doesn't know any script keys.
+type unknownKeyAddrBook struct {
+	AddrBook
+}
+
+func (b *unknownKeyAddrBook) FetchScriptKey(context.Context,
+	*btcec.PublicKey) (*asset.TweakedScriptKey, error) {
+
+	return nil, address.ErrScriptKeyNotFound
+}
+
+// staticArchiver is a proof archive that returns the same proof for all
+// locators.
+type staticArchiver struct {
+	proof.Archiver
+
+	blob proof.Blob
+}
+
+func (a *staticArchiver) FetchProof(context.Context,
+	proof.Locator) (proof.Blob, error) {
+
+	return a.blob, nil
+}
+
+// TestEstimateShipment tests that previewing the send of an address parcel
+// funds the transfer like a real send and estimates the chain fee of its
+// anchor transaction, but neither leases the inputs nor derives keys from the
+// wallet.
+func TestEstimateShipment(t *testing.T) {
+	t.Parallel()
+
+	const (
+		inputAmt = 100
+		sendAmt  = 30
+	)
+
+	// We hold a single asset, anchored in a local output.
+	inputAsset := asset.RandAsset(t, asset.Normal)
+	inputAsset.Version = asset.V0
+	inputAsset.GroupKey = nil
+	inputAsset.Amount = inputAmt
+
+	tapCommitment, err := commitment.FromAssets(inputAsset)
+	require.NoError(t, err)
+	coin := &AnchoredCommitment{
+		AnchorPoint:       test.RandOp(t),
+		AnchorOutputValue: 1000,
+		InternalKey: keychain.KeyDescriptor{
+			PubKey: test.RandPubKey(t),
+		},
+		Commitment: tapCommitment,
+		Asset:      inputAsset,
+	}
+
+	proofFile, err := proof.NewFile(proof.V0, proof.Proof{
+		Asset: *inputAsset,
+		InclusionProof: proof.TaprootProof{
+			InternalKey: coin.InternalKey.PubKey,
+		},
+	})
+	require.NoError(t, err)
+	var proofBuf bytes.Buffer
+	require.NoError(t, proofFile.Encode(&proofBuf))
+
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{coin},
+	}
+	keyRing := &countingKeyRing{}
+	wallet := NewAssetWallet(&WalletConfig{
+		CoinSelector: NewCoinSelect(coinLister, nil, nil, nil),
+		AssetProofs:  &staticArchiver{blob: proofBuf.Bytes()},
+		AddrBook:     &unknownKeyAddrBook{},
+		KeyRing:      keyRing,
+		ChainParams:  &address.RegressionNetTap,
+		MaxInputs:    1,
+	})
+	porter := NewChainPorter(&ChainPorterConfig{
+		AssetWallet: wallet,
+	})
+
+	addr, err := address.New(
+		address.V0, inputAsset.Genesis, nil, nil, *test.RandPubKey(t),
+		*test.RandPubKey(t), sendAmt, nil, &address.RegressionNetTap,
+		address.RandProofCourierAddr(t),
+	)
+	require.NoError(t, err)
+
+	feeRate := chainfee.SatPerKWeight(2_500)
+	estimate, err := porter.EstimateShipment(NewAddressParcel(
+		0, nil, false, feeRate, nil, "", "", addr,
+	))
+	require.NoError(t, err)
+
+	// The transfer pays the address and sends the rest back to a change
+	// output.
+	require.Len(t, estimate.VPackets, 1)
+	vPkt := estimate.VPackets[0]
+	require.Len(t, vPkt.Inputs, 1)
+	require.Equal(t, coin.AnchorPoint, vPkt.Inputs[0].PrevID.OutPoint)
+	require.Len(t, vPkt.Outputs, 2)
+
+	changeOut, err := vPkt.SplitRootOutput()
+	require.NoError(t, err)
+	require.EqualValues(t, inputAmt-sendAmt, changeOut.Amount)
+	require.NotEqual(t, asset.NUMSScriptKey, changeOut.ScriptKey)
+	require.Zero(t, estimate.NumPassiveAssets)
+
+	// The anchor transaction spends the asset anchor and creates an anchor
+	// output for the address and the change, plus the wallet's funding
+	// input and change output.
+	var weightEstimator input.TxWeightEstimator
+	for i := 0; i < 2; i++ {
+		weightEstimator.AddTaprootKeySpendInput(
+			txscript.SigHashDefault,
+		)
+	}
+	for i := 0; i < 3; i++ {
+		weightEstimator.AddP2TROutput()
+	}
+	require.Equal(t, feeRate, estimate.FeeRate)
+	require.Equal(
+		t, feeRate.FeeForWeight(int64(weightEstimator.Weight())),
+		estimate.ChainFees,
+	)
+
+	// Nothing was leased and the key index of the wallet wasn't advanced.
+	require.Empty(t, coinLister.leased)
+	require.Zero(t, keyRing.numDerived)
+
+	// A real send of the same parcel leases the input and derives the
+	// keys of the change output and its anchor.
+	_, _, err = wallet.FundAddressSend(context.Background(), 0, nil, addr)
+	require.NoError(t, err)
+	require.Equal(t, []wire.OutPoint{coin.AnchorPoint}, coinLister.leased)
+	require.Equal(t, 2, keyRing.numDerived)
+}
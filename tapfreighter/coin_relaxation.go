@@ -0,0 +1,126 @@
+package tapfreighter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RelaxationStep is a constraint of the coin selection that can be relaxed if
+// a transfer can't be funded otherwise.
+type RelaxationStep uint8
+
+const (
+	// RelaxConfirmations lowers the number of confirmations an asset needs
+	// before it can be selected.
+	RelaxConfirmations RelaxationStep = iota
+
+	// RelaxMaxInputs raises the maximum number of inputs a transfer may
+	// spend.
+	RelaxMaxInputs
+)
+
+// String returns the name of the relaxation step.
+func (r RelaxationStep) String() string {
+	switch r {
+	case RelaxConfirmations:
+		return "confirmations"
+
+	case RelaxMaxInputs:
+		return "maxinputs"
+
+	default:
+		return fmt.Sprintf("<unknown(%d)>", r)
+	}
+}
+
+// CoinRelaxationPolicy describes how the constraints of the coin selection are
+// relaxed if a transfer can't be funded with the strict constraints. The steps
+// are applied one after the other, each on top of the previous ones, until the
+// transfer can be funded. A nil policy means coin selection is strict.
+type CoinRelaxationPolicy struct {
+	// Steps are the constraints that are relaxed, in order.
+	Steps []RelaxationStep
+
+	// MinConfs is the number of confirmations an asset needs at least
+	// once the confirmations are relaxed.
+	MinConfs uint32
+
+	// MaxInputs is the maximum number of inputs a transfer may spend once
+	// the maximum number of inputs is relaxed. A value of zero means no
+	// limit.
+	MaxInputs uint32
+}
+
+// ParseCoinRelaxationPolicy creates a coin relaxation policy from the given
+// list of step names. A nil policy is returned if no steps are given.
+func ParseCoinRelaxationPolicy(steps []string, minConfs,
+	maxInputs uint32) (*CoinRelaxationPolicy, error) {
+
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	policy := &CoinRelaxationPolicy{
+		MinConfs:  minConfs,
+		MaxInputs: maxInputs,
+	}
+	seen := make(map[RelaxationStep]struct{})
+	for _, name := range steps {
+		var step RelaxationStep
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case RelaxConfirmations.String():
+			step = RelaxConfirmations
+
+		case RelaxMaxInputs.String():
+			step = RelaxMaxInputs
+
+		default:
+			return nil, fmt.Errorf("unknown coin selection "+
+				"relaxation step %q, expected one of %v or %v",
+				name, RelaxConfirmations, RelaxMaxInputs)
+		}
+
+		if _, ok := seen[step]; ok {
+			return nil, fmt.Errorf("duplicate coin selection "+
+				"relaxation step %v", step)
+		}
+		seen[step] = struct{}{}
+
+		policy.Steps = append(policy.Steps, step)
+	}
+
+	return policy, nil
+}
+
+// CoinRelaxation describes a constraint that was relaxed to fund a transfer.
+type CoinRelaxation struct {
+	// Step is the constraint that was relaxed.
+	Step RelaxationStep
+
+	// Required is the value of the constraint before it was relaxed. For
+	// the confirmations, this is the highest number of confirmations
+	// required by the confirmation policy.
+	Required uint32
+
+	// RelaxedTo is the value of the constraint after it was relaxed. A
+	// maximum number of inputs of zero means no limit.
+	RelaxedTo uint32
+}
+
+// String returns a human-readable description of the relaxation.
+func (c CoinRelaxation) String() string {
+	return fmt.Sprintf("%v relaxed from %d to %d", c.Step, c.Required,
+		c.RelaxedTo)
+}
+
+// relaxedConstraints are the constraints of a coin selection attempt that can
+// be relaxed.
+type relaxedConstraints struct {
+	// minConfs is an upper bound for the number of confirmations required
+	// by the confirmation policy. If this is nil, the policy applies as
+	// is.
+	minConfs *uint32
+
+	// maxInputs is the maximum number of inputs that may be selected.
+	maxInputs uint32
+}
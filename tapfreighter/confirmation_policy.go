@@ -0,0 +1,141 @@
+package tapfreighter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightninglabs/taproot-assets/asset"
+)
+
+// ConfirmationPolicy describes how many confirmations the anchor transaction
+// of an asset needs before the asset can be selected as the input of a
+// transfer. The number can be overridden per asset ID or per asset group, an
+// override of an asset ID takes precedence over one of its group.
+type ConfirmationPolicy struct {
+	// DefaultConfs is the number of confirmations required for assets
+	// without an override. Zero means unconfirmed assets can be spent.
+	DefaultConfs uint32
+
+	// AssetConfs overrides the number of confirmations for single assets.
+	AssetConfs map[asset.ID]uint32
+
+	// GroupConfs overrides the number of confirmations for all assets of
+	// an asset group, keyed by the tweaked group key.
+	GroupConfs map[asset.SerializedKey]uint32
+}
+
+// ParseConfirmationPolicy creates a confirmation policy from the default
+// number of confirmations and a list of overrides in the format
+// <asset_id|group_key>:<confs>.
+func ParseConfirmationPolicy(defaultConfs uint32,
+	overrides []string) (*ConfirmationPolicy, error) {
+
+	policy := &ConfirmationPolicy{
+		DefaultConfs: defaultConfs,
+		AssetConfs:   make(map[asset.ID]uint32),
+		GroupConfs:   make(map[asset.SerializedKey]uint32),
+	}
+
+	for _, override := range overrides {
+		keyStr, confsStr, ok := strings.Cut(override, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid confirmation override "+
+				"%q, expected <asset_id|group_key>:<confs>",
+				override)
+		}
+
+		confs, err := strconv.ParseUint(confsStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number of "+
+				"confirmations in override %q: %w", override,
+				err)
+		}
+
+		keyBytes, err := hex.DecodeString(keyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid asset ID or group key "+
+				"in override %q: %w", override, err)
+		}
+
+		switch len(keyBytes) {
+		case len(asset.ID{}):
+			var id asset.ID
+			copy(id[:], keyBytes)
+			policy.AssetConfs[id] = uint32(confs)
+
+		case btcec.PubKeyBytesLenCompressed:
+			groupKey, err := btcec.ParsePubKey(keyBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid group key in "+
+					"override %q: %w", override, err)
+			}
+			policy.GroupConfs[asset.ToSerialized(groupKey)] =
+				uint32(confs)
+
+		default:
+			return nil, fmt.Errorf("override %q must be for an "+
+				"asset ID or a group key", override)
+		}
+	}
+
+	return policy, nil
+}
+
+// RequiredConfs returns the number of confirmations the anchor transaction of
+// the given asset needs before the asset can be spent.
+func (p *ConfirmationPolicy) RequiredConfs(a *asset.Asset) uint32 {
+	if p == nil {
+		return 0
+	}
+
+	if confs, ok := p.AssetConfs[a.ID()]; ok {
+		return confs
+	}
+
+	if a.GroupKey != nil {
+		groupKey := asset.ToSerialized(&a.GroupKey.GroupPubKey)
+		if confs, ok := p.GroupConfs[groupKey]; ok {
+			return confs
+		}
+	}
+
+	return p.DefaultConfs
+}
+
+// requiresConfs returns true if any asset needs at least one confirmation
+// before it can be spent.
+func (p *ConfirmationPolicy) requiresConfs() bool {
+	if p == nil {
+		return false
+	}
+
+	if p.DefaultConfs > 0 {
+		return true
+	}
+
+	for _, confs := range p.AssetConfs {
+		if confs > 0 {
+			return true
+		}
+	}
+	for _, confs := range p.GroupConfs {
+		if confs > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// numConfs returns the number of confirmations of a transaction mined at the
+// given height, zero if it isn't mined yet.
+func numConfs(blockHeight, currentHeight uint32) uint32 {
+	if blockHeight == 0 || blockHeight > currentHeight {
+		return 0
+	}
+
+	return currentHeight - blockHeight + 1
+}
@@ -0,0 +1,76 @@
+package tapfreighter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConfirmationPolicy tests that confirmation overrides are parsed
+// correctly, and that an asset override takes precedence over a group
+// override, which takes precedence over the default.
+func TestConfirmationPolicy(t *testing.T) {
+	t.Parallel()
+
+	groupKey := test.RandPubKey(t)
+	groupKeyHex := hex.EncodeToString(groupKey.SerializeCompressed())
+
+	grouped := asset.RandAsset(t, asset.Normal)
+	grouped.GroupKey = &asset.GroupKey{
+		GroupPubKey: *groupKey,
+	}
+	overridden := grouped.Copy()
+	overridden.Genesis = asset.RandGenesis(t, asset.Normal)
+	overriddenID := overridden.ID()
+
+	ungrouped := asset.RandAsset(t, asset.Normal)
+	ungrouped.GroupKey = nil
+
+	policy, err := ParseConfirmationPolicy(3, []string{
+		fmt.Sprintf("%s:6", groupKeyHex),
+		fmt.Sprintf("%x:1", overriddenID[:]),
+	})
+	require.NoError(t, err)
+
+	require.EqualValues(t, 3, policy.RequiredConfs(ungrouped))
+	require.EqualValues(t, 6, policy.RequiredConfs(grouped))
+	require.EqualValues(t, 1, policy.RequiredConfs(overridden))
+	require.True(t, policy.requiresConfs())
+
+	// A nil policy or one that requires no confirmations at all doesn't
+	// require looking up the current height.
+	var nilPolicy *ConfirmationPolicy
+	require.EqualValues(t, 0, nilPolicy.RequiredConfs(grouped))
+	require.False(t, nilPolicy.requiresConfs())
+
+	policy, err = ParseConfirmationPolicy(0, nil)
+	require.NoError(t, err)
+	require.False(t, policy.requiresConfs())
+
+	// Invalid overrides are rejected.
+	invalidOverrides := []string{
+		"6",
+		groupKeyHex + ":",
+		groupKeyHex + ":-1",
+		"zz:6",
+		"abcd:6",
+	}
+	for _, override := range invalidOverrides {
+		_, err := ParseConfirmationPolicy(1, []string{override})
+		require.Error(t, err, override)
+	}
+}
+
+// TestNumConfs tests the number of confirmations of a transaction.
+func TestNumConfs(t *testing.T) {
+	t.Parallel()
+
+	require.EqualValues(t, 0, numConfs(0, 100))
+	require.EqualValues(t, 0, numConfs(101, 100))
+	require.EqualValues(t, 1, numConfs(100, 100))
+	require.EqualValues(t, 6, numConfs(95, 100))
+}
@@ -0,0 +1,114 @@
+package tapfreighter
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeliveryCompletion describes when a transfer to multiple receivers is
+// considered complete with respect to the delivery of the receiver proofs.
+type DeliveryCompletion uint8
+
+const (
+	// DeliveryCompletionAll completes a transfer only once the proofs were
+	// delivered to all receivers.
+	DeliveryCompletionAll DeliveryCompletion = iota
+
+	// DeliveryCompletionQuorum completes a transfer once the proofs were
+	// delivered to a quorum of the receivers. The proofs of the remaining
+	// receivers continue to be delivered in the background.
+	DeliveryCompletionQuorum
+)
+
+// String returns a human-readable string for the delivery completion.
+func (d DeliveryCompletion) String() string {
+	switch d {
+	case DeliveryCompletionAll:
+		return "all"
+
+	case DeliveryCompletionQuorum:
+		return "quorum"
+
+	default:
+		return fmt.Sprintf("<unknown_delivery_completion(%d)>", d)
+	}
+}
+
+// ParseDeliveryCompletion parses a delivery completion string.
+func ParseDeliveryCompletion(completion string) (DeliveryCompletion, error) {
+	switch completion {
+	case "", DeliveryCompletionAll.String():
+		return DeliveryCompletionAll, nil
+
+	case DeliveryCompletionQuorum.String():
+		return DeliveryCompletionQuorum, nil
+
+	default:
+		return 0, fmt.Errorf("unknown delivery completion: %v",
+			completion)
+	}
+}
+
+// DeliveryCompletionPolicy describes how many receiver proofs of a transfer
+// need to be delivered before the transfer is marked as complete.
+type DeliveryCompletionPolicy struct {
+	// Completion is the completion mode of the policy.
+	Completion DeliveryCompletion
+
+	// QuorumPercent is the percentage of receivers the proofs need to be
+	// delivered to before a transfer is complete. This is only used with
+	// DeliveryCompletionQuorum.
+	QuorumPercent uint32
+}
+
+// threshold returns the number of receivers the proofs need to be delivered to
+// before a transfer with the given number of receivers is complete.
+func (d *DeliveryCompletionPolicy) threshold(numReceivers int) int {
+	if d == nil || d.Completion != DeliveryCompletionQuorum ||
+		d.QuorumPercent >= 100 {
+
+		return numReceivers
+	}
+
+	// We round up, so a quorum of 50% of three receivers requires two
+	// deliveries. A transfer with receivers always requires at least one
+	// delivery.
+	threshold := (numReceivers*int(d.QuorumPercent) + 99) / 100
+	if threshold == 0 && numReceivers > 0 {
+		threshold = 1
+	}
+
+	return threshold
+}
+
+// awaitDeliveries reads the results of the proof deliveries to the given number
+// of receivers until the given threshold of successful deliveries is reached.
+// The number of successful deliveries is returned. An error is returned as soon
+// as too many deliveries failed for the threshold to still be reached.
+func awaitDeliveries(ctx context.Context, results <-chan error,
+	numReceivers, threshold int) (int, error) {
+
+	var delivered, failed int
+	for delivered < threshold {
+		select {
+		case err := <-results:
+			if err == nil {
+				delivered++
+				continue
+			}
+
+			failed++
+			if numReceivers-failed < threshold {
+				return delivered, fmt.Errorf("%d of %d proof "+
+					"deliveries failed, unable to reach "+
+					"threshold of %d: %w", failed,
+					numReceivers, threshold, err)
+			}
+
+		case <-ctx.Done():
+			return delivered, ctx.Err()
+		}
+	}
+
+	return delivered, nil
+}
@@ -0,0 +1,186 @@
+package tapfreighter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errDeliveryFailed = errors.New("delivery failed")
+
+// TestDeliveryCompletionThreshold tests that the number of deliveries required
+// to complete a transfer is derived correctly from the policy.
+func TestDeliveryCompletionThreshold(t *testing.T) {
+	t.Parallel()
+
+	quorum := func(percent uint32) *DeliveryCompletionPolicy {
+		return &DeliveryCompletionPolicy{
+			Completion:    DeliveryCompletionQuorum,
+			QuorumPercent: percent,
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		policy       *DeliveryCompletionPolicy
+		numReceivers int
+		threshold    int
+	}{{
+		name:         "nil policy",
+		numReceivers: 5,
+		threshold:    5,
+	}, {
+		name: "all",
+		policy: &DeliveryCompletionPolicy{
+			Completion:    DeliveryCompletionAll,
+			QuorumPercent: 50,
+		},
+		numReceivers: 5,
+		threshold:    5,
+	}, {
+		name:         "majority rounded up",
+		policy:       quorum(51),
+		numReceivers: 5,
+		threshold:    3,
+	}, {
+		name:         "half of odd number",
+		policy:       quorum(50),
+		numReceivers: 3,
+		threshold:    2,
+	}, {
+		name:         "at least one",
+		policy:       quorum(1),
+		numReceivers: 3,
+		threshold:    1,
+	}, {
+		name:         "full quorum",
+		policy:       quorum(100),
+		numReceivers: 4,
+		threshold:    4,
+	}, {
+		name:         "no receivers",
+		policy:       quorum(51),
+		numReceivers: 0,
+		threshold:    0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(
+				t, tc.threshold, tc.policy.threshold(
+					tc.numReceivers,
+				),
+			)
+		})
+	}
+}
+
+// TestAwaitDeliveries tests that the wait for proof deliveries returns as soon
+// as the threshold is reached, or as soon as it can no longer be reached.
+func TestAwaitDeliveries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		results      []error
+		numReceivers int
+		threshold    int
+		delivered    int
+		expectErr    bool
+	}{{
+		name:         "all delivered",
+		results:      []error{nil, nil, nil},
+		numReceivers: 3,
+		threshold:    3,
+		delivered:    3,
+	}, {
+		name:         "quorum reached before slow receiver",
+		results:      []error{nil, nil},
+		numReceivers: 3,
+		threshold:    2,
+		delivered:    2,
+	}, {
+		name:         "quorum reached despite failure",
+		results:      []error{errDeliveryFailed, nil, nil},
+		numReceivers: 3,
+		threshold:    2,
+		delivered:    2,
+	}, {
+		name:         "all required but one failed",
+		results:      []error{nil, errDeliveryFailed},
+		numReceivers: 3,
+		threshold:    3,
+		delivered:    1,
+		expectErr:    true,
+	}, {
+		name: "quorum unreachable",
+		results: []error{
+			errDeliveryFailed, nil, errDeliveryFailed,
+		},
+		numReceivers: 4,
+		threshold:    3,
+		delivered:    1,
+		expectErr:    true,
+	}, {
+		name:         "no receivers",
+		numReceivers: 0,
+		threshold:    0,
+		delivered:    0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Only the given results are ever sent, so the wait
+			// would block if it didn't return in time.
+			results := make(chan error, len(tc.results))
+			for _, result := range tc.results {
+				results <- result
+			}
+
+			delivered, err := awaitDeliveries(
+				context.Background(), results, tc.numReceivers,
+				tc.threshold,
+			)
+			require.Equal(t, tc.delivered, delivered)
+			if tc.expectErr {
+				require.ErrorIs(t, err, errDeliveryFailed)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	// A cancelled context stops the wait.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := awaitDeliveries(ctx, make(chan error), 1, 1)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestParseDeliveryCompletion tests that all delivery completion modes can be
+// parsed from their string representation.
+func TestParseDeliveryCompletion(t *testing.T) {
+	t.Parallel()
+
+	for _, completion := range []DeliveryCompletion{
+		DeliveryCompletionAll, DeliveryCompletionQuorum,
+	} {
+		parsed, err := ParseDeliveryCompletion(completion.String())
+		require.NoError(t, err)
+		require.Equal(t, completion, parsed)
+	}
+
+	parsed, err := ParseDeliveryCompletion("")
+	require.NoError(t, err)
+	require.Equal(t, DeliveryCompletionAll, parsed)
+
+	_, err = ParseDeliveryCompletion("majority")
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package tapfreighter
+
+import (
+	"context"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// FeeEscalationPolicy describes how the fee rate of the anchor transaction of a
+// pending transfer that doesn't confirm is escalated automatically by replacing
+// the transaction.
+type FeeEscalationPolicy struct {
+	// After is how long a transfer needs to be pending before the fee rate
+	// of its anchor transaction is escalated for the first time.
+	After time.Duration
+
+	// Interval is the interval at which the fee rate of the anchor
+	// transactions of pending transfers is escalated.
+	Interval time.Duration
+
+	// Step is the fee rate the fee rate of an anchor transaction is
+	// increased by with every escalation.
+	Step chainfee.SatPerKWeight
+
+	// MaxFeeRate is the fee rate an anchor transaction is escalated to at
+	// most.
+	MaxFeeRate chainfee.SatPerKWeight
+}
+
+// nextFeeRate returns the fee rate the anchor transaction currently paying the
+// given fee rate is escalated to. False is returned if the fee rate already
+// reached the maximum fee rate.
+func (f *FeeEscalationPolicy) nextFeeRate(
+	feeRate chainfee.SatPerKWeight) (chainfee.SatPerKWeight, bool) {
+
+	if feeRate >= f.MaxFeeRate {
+		return 0, false
+	}
+
+	nextFeeRate := feeRate + f.Step
+	if nextFeeRate > f.MaxFeeRate {
+		nextFeeRate = f.MaxFeeRate
+	}
+
+	return nextFeeRate, true
+}
+
+// parcelFeeRate returns the fee rate the anchor transaction of the given parcel
+// pays.
+func parcelFeeRate(parcel *OutboundParcel) chainfee.SatPerKWeight {
+	weight := blockchain.GetTransactionWeight(
+		btcutil.NewTx(parcel.AnchorTx),
+	)
+	if weight == 0 {
+		return 0
+	}
+
+	return chainfee.SatPerKWeight(parcel.ChainFees * 1000 / weight)
+}
+
+// escalateFees periodically escalates the fee rate of the anchor transactions
+// of pending transfers according to the fee escalation policy.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ChainPorter) escalateFees() {
+	defer p.Wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FeeEscalation.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := p.WithCtxQuitNoTimeout()
+			p.escalatePendingFees(ctx, time.Now())
+			cancel()
+
+		case <-p.Quit:
+			return
+		}
+	}
+}
+
+// escalatePendingFees escalates the fee rate of the anchor transactions of all
+// transfers that were pending for longer than the fee escalation policy
+// requires. Only the anchor transactions of transfers we wait for to confirm
+// with a known funded anchor PSBT can be replaced, which isn't the case for
+// transfers that were handed off to this node by another one.
+func (p *ChainPorter) escalatePendingFees(ctx context.Context, now time.Time) {
+	policy := p.cfg.FeeEscalation
+
+	p.feeBumpMtx.Lock()
+	numHandlers := len(p.feeBumpHandlers)
+	p.feeBumpMtx.Unlock()
+
+	if numHandlers == 0 {
+		return
+	}
+
+	parcels, err := p.cfg.ExportLog.PendingParcels(ctx)
+	if err != nil {
+		log.Errorf("Unable to fetch pending parcels for fee "+
+			"escalation: %v", err)
+		return
+	}
+
+	for _, parcel := range parcels {
+		anchorTXID := parcel.AnchorTx.TxHash()
+		if _, ok := p.feeBumpHandler(anchorTXID); !ok {
+			continue
+		}
+		if now.Sub(parcel.TransferTime) < policy.After {
+			continue
+		}
+
+		feeRate := parcelFeeRate(parcel)
+		nextFeeRate, ok := policy.nextFeeRate(feeRate)
+		if !ok {
+			log.Debugf("Fee rate of transfer_txid=%v already at "+
+				"maximum escalation fee rate %v", anchorTXID,
+				policy.MaxFeeRate)
+			continue
+		}
+
+		log.Infof("Escalating fee rate of transfer_txid=%v from %v "+
+			"to %v", anchorTXID, feeRate, nextFeeRate)
+
+		_, err := p.requestFeeBump(anchorTXID, nextFeeRate, true)
+		if err != nil {
+			log.Warnf("Unable to escalate fee rate of "+
+				"transfer_txid=%v: %v", anchorTXID, err)
+		}
+	}
+}
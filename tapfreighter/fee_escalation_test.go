@@ -0,0 +1,274 @@
+package tapfreighter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeEscalationNextFeeRate tests that the fee rate is escalated by the
+// configured step until the maximum fee rate is reached.
+func TestFeeEscalationNextFeeRate(t *testing.T) {
+	t.Parallel()
+
+	policy := &FeeEscalationPolicy{
+		Step:       250,
+		MaxFeeRate: 3000,
+	}
+
+	testCases := []struct {
+		name        string
+		feeRate     chainfee.SatPerKWeight
+		nextFeeRate chainfee.SatPerKWeight
+		escalate    bool
+	}{{
+		name:        "below maximum",
+		feeRate:     2000,
+		nextFeeRate: 2250,
+		escalate:    true,
+	}, {
+		name:        "capped at maximum",
+		feeRate:     2900,
+		nextFeeRate: 3000,
+		escalate:    true,
+	}, {
+		name:     "at maximum",
+		feeRate:  3000,
+		escalate: false,
+	}, {
+		name:     "above maximum",
+		feeRate:  5000,
+		escalate: false,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			nextFeeRate, ok := policy.nextFeeRate(tc.feeRate)
+			require.Equal(t, tc.escalate, ok)
+			require.Equal(t, tc.nextFeeRate, nextFeeRate)
+		})
+	}
+}
+
+// TestEscalatePendingFees tests that only the anchor transactions of transfers
+// that were pending for long enough, that can be replaced and that didn't reach
+// the maximum fee rate yet are escalated.
+func TestEscalatePendingFees(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	policy := &FeeEscalationPolicy{
+		After:      time.Hour,
+		Interval:   time.Hour,
+		Step:       250,
+		MaxFeeRate: 3000,
+	}
+
+	newParcel := func(lockTime uint32, feeRate int64,
+		age time.Duration) *OutboundParcel {
+
+		anchorTx := wire.NewMsgTx(2)
+		anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+		anchorTx.AddTxOut(&wire.TxOut{
+			Value:    1000,
+			PkScript: []byte{0x51},
+		})
+		anchorTx.LockTime = lockTime
+
+		weight := blockchain.GetTransactionWeight(
+			btcutil.NewTx(anchorTx),
+		)
+
+		return &OutboundParcel{
+			AnchorTx:     anchorTx,
+			TransferTime: now.Add(-age),
+			ChainFees:    feeRate * weight / 1000,
+		}
+	}
+
+	var (
+		oldParcel       = newParcel(1, 2000, 2*time.Hour)
+		cappedParcel    = newParcel(2, 2900, 2*time.Hour)
+		maxedParcel     = newParcel(3, 3000, 2*time.Hour)
+		youngParcel     = newParcel(4, 2000, time.Minute)
+		untrackedParcel = newParcel(5, 2000, 2*time.Hour)
+	)
+
+	porter := NewChainPorter(&ChainPorterConfig{
+		ExportLog: &cancelExportLog{
+			parcels: []*OutboundParcel{
+				oldParcel, cappedParcel, maxedParcel,
+				youngParcel, untrackedParcel,
+			},
+		},
+		FeeEscalation: policy,
+	})
+
+	// Without any transfer waiting for a replaceable anchor transaction to
+	// confirm, there is nothing to escalate.
+	porter.escalatePendingFees(context.Background(), now)
+
+	// We register a handler for all parcels except one, which answers the
+	// fee bump requests it receives.
+	var (
+		mtx      sync.Mutex
+		wg       sync.WaitGroup
+		bumps    = make(map[chainhash.Hash]chainfee.SatPerKWeight)
+		cleanups []func()
+	)
+	for _, parcel := range []*OutboundParcel{
+		oldParcel, cappedParcel, maxedParcel, youngParcel,
+	} {
+		parcel := parcel
+		anchorTXID := parcel.AnchorTx.TxHash()
+		handler, cleanup := porter.registerFeeBumpHandler(anchorTXID)
+		cleanups = append(cleanups, cleanup)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case req := <-handler.feeBumpReqs:
+					require.True(t, req.escalation)
+
+					mtx.Lock()
+					bumps[anchorTXID] = req.feeRate
+					mtx.Unlock()
+
+					req.respChan <- parcel
+
+				case <-handler.done:
+					return
+				}
+			}
+		}()
+	}
+
+	porter.escalatePendingFees(context.Background(), now)
+
+	for _, cleanup := range cleanups {
+		cleanup()
+	}
+	wg.Wait()
+
+	require.Equal(t, map[chainhash.Hash]chainfee.SatPerKWeight{
+		oldParcel.AnchorTx.TxHash():    2250,
+		cappedParcel.AnchorTx.TxHash(): 3000,
+	}, bumps)
+}
+
+// TestResumedTransferFeeEscalation tests that the fee rate of the anchor
+// transaction of a transfer that was resumed after a restart is escalated as
+// well, continuing the fee escalation count stored with the transfer.
+func TestResumedTransferFeeEscalation(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 5 * time.Second
+
+	now := time.Now()
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 10_000, PkScript: []byte{0x51}})
+	anchorTXID := anchorTx.TxHash()
+
+	pkt, err := psbt.NewFromUnsignedTx(anchorTx.Copy())
+	require.NoError(t, err)
+
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(anchorTx))
+	parcel := &OutboundParcel{
+		AnchorTx:       anchorTx,
+		TransferTime:   now.Add(-2 * time.Hour),
+		ChainFees:      2000 * weight / 1000,
+		AnchorPsbt:     &tapgarden.FundedPsbt{Pkt: pkt},
+		FeeEscalations: 2,
+	}
+
+	bridge := &versionsChainBridge{
+		confs: make(map[chainhash.Hash]chan *chainntnfs.TxConfirmation),
+	}
+	exportLog := &replaceExportLog{
+		parcels:     []*OutboundParcel{parcel},
+		replaced:    make(map[chainhash.Hash]chainhash.Hash),
+		escalations: fn.NewSet[chainhash.Hash](),
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		ChainBridge: bridge,
+		Wallet:      &releaseWallet{},
+		AssetWallet: &bumpWallet{},
+		ExportLog:   exportLog,
+		FeeEscalation: &FeeEscalationPolicy{
+			After:      time.Hour,
+			Interval:   time.Hour,
+			Step:       250,
+			MaxFeeRate: 3000,
+		},
+	})
+	events := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	require.NoError(t, porter.RegisterSubscriber(events, false, false))
+
+	// The resumed transfer knows its funded anchor PSBT again, so it waits
+	// for a replaceable anchor transaction to confirm.
+	pkg := NewPendingParcel(parcel).pkg()
+	require.NotNil(t, pkg.AnchorTx)
+	pkg.SendState = SendStateWaitTxConf
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- porter.waitForTransferTxConf(pkg)
+	}()
+	require.Eventually(t, func() bool {
+		_, ok := porter.feeBumpHandler(anchorTXID)
+		return ok
+	}, timeout, 10*time.Millisecond)
+
+	porter.escalatePendingFees(context.Background(), now)
+
+	select {
+	case err := <-waitErr:
+		require.NoError(t, err)
+
+	case <-time.After(timeout):
+		t.Fatalf("fee bump not handled")
+	}
+
+	// The replacement was stored and published as the third fee
+	// escalation of the transfer.
+	newTXID := pkg.OutboundPkg.AnchorTx.TxHash()
+	require.NotEqual(t, anchorTXID, newTXID)
+	require.Equal(t, newTXID, exportLog.replaced[anchorTXID])
+	require.True(t, exportLog.escalations.Contains(newTXID))
+	require.Equal(t, []chainhash.Hash{newTXID}, bridge.publishedTxs())
+	require.EqualValues(t, 3, pkg.OutboundPkg.FeeEscalations)
+
+	select {
+	case e := <-events.NewItemCreated.ChanOut():
+		bumpEvent, ok := e.(*TransferFeeBumpedEvent)
+		require.True(t, ok)
+		require.Equal(t, anchorTXID, bumpEvent.OldAnchorTXID)
+		require.Equal(t, newTXID, bumpEvent.NewAnchorTXID)
+		require.EqualValues(t, 2250, bumpEvent.FeeRate)
+		require.EqualValues(t, 3, bumpEvent.Escalation)
+
+	case <-time.After(timeout):
+		t.Fatalf("event not received")
+	}
+
+	close(porter.Quit)
+	porter.Wg.Wait()
+}
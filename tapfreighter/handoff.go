@@ -0,0 +1,590 @@
+package tapfreighter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	handoffAnchorTxType     tlv.Type = 0
+	handoffHeightHintType   tlv.Type = 2
+	handoffTransferTimeType tlv.Type = 4
+	handoffChainFeesType    tlv.Type = 6
+	handoffStagedType       tlv.Type = 8
+	handoffInputsType       tlv.Type = 10
+	handoffOutputsType      tlv.Type = 12
+	handoffInputProofsType  tlv.Type = 14
+
+	handoffInputOutPointType  tlv.Type = 0
+	handoffInputAssetIDType   tlv.Type = 2
+	handoffInputScriptKeyType tlv.Type = 4
+	handoffInputAmountType    tlv.Type = 6
+
+	handoffOutputAnchorPointType      tlv.Type = 0
+	handoffOutputAnchorValueType      tlv.Type = 2
+	handoffOutputInternalKeyType      tlv.Type = 4
+	handoffOutputTaprootAssetRootType tlv.Type = 6
+	handoffOutputMerkleRootType       tlv.Type = 8
+	handoffOutputTapscriptSiblingType tlv.Type = 10
+	handoffOutputNumPassiveAssetsType tlv.Type = 12
+	handoffOutputTypeType             tlv.Type = 14
+	handoffOutputScriptKeyType        tlv.Type = 16
+	handoffOutputScriptKeyLocalType   tlv.Type = 18
+	handoffOutputAmountType           tlv.Type = 20
+	handoffOutputAssetVersionType     tlv.Type = 22
+	handoffOutputWitnessDataType      tlv.Type = 24
+	handoffOutputSplitRootType        tlv.Type = 26
+	handoffOutputProofSuffixType      tlv.Type = 28
+	handoffOutputCourierAddrType      tlv.Type = 30
+
+	// keyDescriptorSize is the size of an encoded key descriptor, which is
+	// the compressed public key followed by the key family and index.
+	keyDescriptorSize = btcec.PubKeyBytesLenCompressed + 4 + 4
+)
+
+// TransferHandoff is the resumable state of a pending transfer that is handed
+// off to another node, so it can complete the broadcast and the proof delivery
+// of the transfer. Both nodes need to be connected to the same lnd node.
+type TransferHandoff struct {
+	// Parcel is the pending parcel as it was logged to disk. Transfers
+	// that re-anchor passive assets can't be handed off.
+	Parcel *OutboundParcel
+
+	// InputProofs are the proof files of the assets spent by the transfer,
+	// one for each input of the parcel and in the same order.
+	InputProofs []proof.Blob
+}
+
+// Encode encodes the transfer handoff into the given writer.
+func (h *TransferHandoff) Encode(w io.Writer) error {
+	parcel := h.Parcel
+	if len(h.InputProofs) != len(parcel.Inputs) {
+		return fmt.Errorf("expected %d input proofs, got %d",
+			len(parcel.Inputs), len(h.InputProofs))
+	}
+
+	var txBuf bytes.Buffer
+	if err := parcel.AnchorTx.Serialize(&txBuf); err != nil {
+		return err
+	}
+	anchorTx := txBuf.Bytes()
+
+	inputs, err := encodeList(parcel.Inputs, encodeTransferInput)
+	if err != nil {
+		return err
+	}
+	outputs, err := encodeList(parcel.Outputs, encodeTransferOutput)
+	if err != nil {
+		return err
+	}
+	inputProofs, err := encodeList(
+		h.InputProofs, func(b *proof.Blob) ([]byte, error) {
+			return *b, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	var (
+		heightHint   = parcel.AnchorTxHeightHint
+		transferTime = uint64(parcel.TransferTime.UnixNano())
+		chainFees    = uint64(parcel.ChainFees)
+		staged       = parcel.Staged
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(handoffAnchorTxType, &anchorTx),
+		tlv.MakePrimitiveRecord(handoffHeightHintType, &heightHint),
+		tlv.MakePrimitiveRecord(
+			handoffTransferTimeType, &transferTime,
+		),
+		tlv.MakePrimitiveRecord(handoffChainFeesType, &chainFees),
+		tlv.MakeStaticRecord(
+			handoffStagedType, &staged, 1, proof.BoolEncoder,
+			proof.BoolDecoder,
+		),
+		tlv.MakePrimitiveRecord(handoffInputsType, &inputs),
+		tlv.MakePrimitiveRecord(handoffOutputsType, &outputs),
+		tlv.MakePrimitiveRecord(handoffInputProofsType, &inputProofs),
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode decodes a transfer handoff from the given reader.
+func (h *TransferHandoff) Decode(r io.Reader) error {
+	var (
+		anchorTx, inputs, outputs, inputProofs []byte
+		heightHint                             uint32
+		transferTime, chainFees                uint64
+		staged                                 bool
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(handoffAnchorTxType, &anchorTx),
+		tlv.MakePrimitiveRecord(handoffHeightHintType, &heightHint),
+		tlv.MakePrimitiveRecord(
+			handoffTransferTimeType, &transferTime,
+		),
+		tlv.MakePrimitiveRecord(handoffChainFeesType, &chainFees),
+		tlv.MakeStaticRecord(
+			handoffStagedType, &staged, 1, proof.BoolEncoder,
+			proof.BoolDecoder,
+		),
+		tlv.MakePrimitiveRecord(handoffInputsType, &inputs),
+		tlv.MakePrimitiveRecord(handoffOutputsType, &outputs),
+		tlv.MakePrimitiveRecord(handoffInputProofsType, &inputProofs),
+	)
+	if err != nil {
+		return err
+	}
+	if err := stream.Decode(r); err != nil {
+		return err
+	}
+
+	tx := wire.NewMsgTx(2)
+	if err := tx.Deserialize(bytes.NewReader(anchorTx)); err != nil {
+		return fmt.Errorf("unable to decode anchor tx: %w", err)
+	}
+
+	parcel := &OutboundParcel{
+		AnchorTx:           tx,
+		AnchorTxHeightHint: heightHint,
+		TransferTime:       time.Unix(0, int64(transferTime)).UTC(),
+		ChainFees:          int64(chainFees),
+		Staged:             staged,
+	}
+	parcel.Inputs, err = decodeList(inputs, decodeTransferInput)
+	if err != nil {
+		return fmt.Errorf("unable to decode inputs: %w", err)
+	}
+	parcel.Outputs, err = decodeList(outputs, decodeTransferOutput)
+	if err != nil {
+		return fmt.Errorf("unable to decode outputs: %w", err)
+	}
+
+	h.Parcel = parcel
+	h.InputProofs, err = decodeList(
+		inputProofs, func(b []byte) (proof.Blob, error) {
+			return b, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to decode input proofs: %w", err)
+	}
+	if len(h.InputProofs) != len(parcel.Inputs) {
+		return fmt.Errorf("expected %d input proofs, got %d",
+			len(parcel.Inputs), len(h.InputProofs))
+	}
+
+	return nil
+}
+
+// encodeList encodes the given items as a variable length list of byte
+// slices, using the given function to encode each item.
+func encodeList[T any](items []T, encode func(*T) ([]byte, error)) ([]byte,
+	error) {
+
+	var (
+		buf     bytes.Buffer
+		scratch [8]byte
+	)
+	err := tlv.WriteVarInt(&buf, uint64(len(items)), &scratch)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range items {
+		item, err := encode(&items[idx])
+		if err != nil {
+			return nil, err
+		}
+
+		err = asset.InlineVarBytesEncoder(&buf, &item, &scratch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeList decodes a variable length list of byte slices, using the given
+// function to decode each item.
+func decodeList[T any](b []byte, decode func([]byte) (T, error)) ([]T,
+	error) {
+
+	var (
+		r       = bytes.NewReader(b)
+		scratch [8]byte
+	)
+	numItems, err := tlv.ReadVarInt(r, &scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each item takes up at least one byte for its length, which gives us
+	// an upper bound for the number of items.
+	if numItems > uint64(r.Len()) {
+		return nil, tlv.ErrRecordTooLarge
+	}
+
+	items := make([]T, 0, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		var itemBytes []byte
+		err := asset.InlineVarBytesDecoder(
+			r, &itemBytes, &scratch, math.MaxUint32,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		item, err := decode(itemBytes)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// encodeTransferInput encodes a single transfer input.
+func encodeTransferInput(in *TransferInput) ([]byte, error) {
+	var (
+		assetID   = [32]byte(in.ID)
+		scriptKey = [33]byte(in.ScriptKey)
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakeStaticRecord(
+			handoffInputOutPointType, &in.OutPoint, 32+4,
+			asset.OutPointEncoder, asset.OutPointDecoder,
+		),
+		tlv.MakePrimitiveRecord(handoffInputAssetIDType, &assetID),
+		tlv.MakePrimitiveRecord(handoffInputScriptKeyType, &scriptKey),
+		tlv.MakePrimitiveRecord(handoffInputAmountType, &in.Amount),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := stream.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeTransferInput decodes a single transfer input.
+func decodeTransferInput(b []byte) (TransferInput, error) {
+	var (
+		in        TransferInput
+		assetID   [32]byte
+		scriptKey [33]byte
+	)
+	stream, err := tlv.NewStream(
+		tlv.MakeStaticRecord(
+			handoffInputOutPointType, &in.OutPoint, 32+4,
+			asset.OutPointEncoder, asset.OutPointDecoder,
+		),
+		tlv.MakePrimitiveRecord(handoffInputAssetIDType, &assetID),
+		tlv.MakePrimitiveRecord(handoffInputScriptKeyType, &scriptKey),
+		tlv.MakePrimitiveRecord(handoffInputAmountType, &in.Amount),
+	)
+	if err != nil {
+		return in, err
+	}
+	if err := stream.Decode(bytes.NewReader(b)); err != nil {
+		return in, err
+	}
+
+	in.ID = assetID
+	in.ScriptKey = scriptKey
+
+	return in, nil
+}
+
+// encodeTransferOutput encodes a single transfer output.
+func encodeTransferOutput(out *TransferOutput) ([]byte, error) {
+	internalKey, err := encodeKeyDescriptor(out.Anchor.InternalKey)
+	if err != nil {
+		return nil, err
+	}
+	scriptKey, err := encodeScriptKey(out.ScriptKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		witnessBuf bytes.Buffer
+		scratch    [8]byte
+	)
+	err = asset.WitnessEncoder(&witnessBuf, &out.WitnessData, &scratch)
+	if err != nil {
+		return nil, err
+	}
+	witnessData := witnessBuf.Bytes()
+
+	var splitRoot []byte
+	if out.SplitCommitmentRoot != nil {
+		var splitRootBuf bytes.Buffer
+		err := asset.SplitCommitmentRootEncoder(
+			&splitRootBuf, &out.SplitCommitmentRoot, &scratch,
+		)
+		if err != nil {
+			return nil, err
+		}
+		splitRoot = splitRootBuf.Bytes()
+	}
+
+	var (
+		anchorValue  = uint64(out.Anchor.Value)
+		outputType   = uint8(out.Type)
+		assetVersion = uint8(out.AssetVersion)
+	)
+	stream, err := tlv.NewStream(
+		outputRecords(
+			&out.Anchor, &anchorValue, &internalKey, &outputType,
+			&scriptKey, out, &assetVersion, &witnessData,
+			&splitRoot,
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := stream.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeTransferOutput decodes a single transfer output.
+func decodeTransferOutput(b []byte) (TransferOutput, error) {
+	var (
+		out                                 TransferOutput
+		anchorValue                         uint64
+		outputType, assetVersion            uint8
+		internalKey, scriptKey, witnessData []byte
+		splitRoot                           []byte
+	)
+	stream, err := tlv.NewStream(
+		outputRecords(
+			&out.Anchor, &anchorValue, &internalKey, &outputType,
+			&scriptKey, &out, &assetVersion, &witnessData,
+			&splitRoot,
+		)...,
+	)
+	if err != nil {
+		return out, err
+	}
+	if err := stream.Decode(bytes.NewReader(b)); err != nil {
+		return out, err
+	}
+
+	out.Anchor.Value = btcutil.Amount(anchorValue)
+	out.Type = tappsbt.VOutputType(outputType)
+	out.AssetVersion = asset.Version(assetVersion)
+
+	out.Anchor.InternalKey, err = decodeKeyDescriptor(internalKey)
+	if err != nil {
+		return out, fmt.Errorf("unable to decode internal key: %w", err)
+	}
+	out.ScriptKey, err = decodeScriptKey(scriptKey)
+	if err != nil {
+		return out, fmt.Errorf("unable to decode script key: %w", err)
+	}
+
+	var scratch [8]byte
+	err = asset.WitnessDecoder(
+		bytes.NewReader(witnessData), &out.WitnessData, &scratch,
+		uint64(len(witnessData)),
+	)
+	if err != nil {
+		return out, fmt.Errorf("unable to decode witness: %w", err)
+	}
+
+	if len(splitRoot) > 0 {
+		var root mssmt.Node
+		err := asset.SplitCommitmentRootDecoder(
+			bytes.NewReader(splitRoot), &root, &scratch,
+			uint64(len(splitRoot)),
+		)
+		if err != nil {
+			return out, fmt.Errorf("unable to decode split root: "+
+				"%w", err)
+		}
+		out.SplitCommitmentRoot = root
+	}
+
+	return out, nil
+}
+
+// outputRecords returns the TLV records of an encoded transfer output. The
+// fields that aren't stored in the output directly are passed in separately.
+func outputRecords(anchor *Anchor, anchorValue *uint64, internalKey *[]byte,
+	outputType *uint8, scriptKey *[]byte, out *TransferOutput,
+	assetVersion *uint8, witnessData, splitRoot *[]byte) []tlv.Record {
+
+	return []tlv.Record{
+		tlv.MakeStaticRecord(
+			handoffOutputAnchorPointType, &anchor.OutPoint, 32+4,
+			asset.OutPointEncoder, asset.OutPointDecoder,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputAnchorValueType, anchorValue,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputInternalKeyType, internalKey,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputTaprootAssetRootType,
+			&anchor.TaprootAssetRoot,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputMerkleRootType, &anchor.MerkleRoot,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputTapscriptSiblingType,
+			&anchor.TapscriptSibling,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputNumPassiveAssetsType,
+			&anchor.NumPassiveAssets,
+		),
+		tlv.MakePrimitiveRecord(handoffOutputTypeType, outputType),
+		tlv.MakePrimitiveRecord(handoffOutputScriptKeyType, scriptKey),
+		tlv.MakeStaticRecord(
+			handoffOutputScriptKeyLocalType, &out.ScriptKeyLocal, 1,
+			proof.BoolEncoder, proof.BoolDecoder,
+		),
+		tlv.MakePrimitiveRecord(handoffOutputAmountType, &out.Amount),
+		tlv.MakePrimitiveRecord(
+			handoffOutputAssetVersionType, assetVersion,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputWitnessDataType, witnessData,
+		),
+		tlv.MakePrimitiveRecord(handoffOutputSplitRootType, splitRoot),
+		tlv.MakePrimitiveRecord(
+			handoffOutputProofSuffixType, &out.ProofSuffix,
+		),
+		tlv.MakePrimitiveRecord(
+			handoffOutputCourierAddrType, &out.ProofCourierAddr,
+		),
+	}
+}
+
+// encodeKeyDescriptor encodes a key descriptor as its compressed public key,
+// followed by the key family and index.
+func encodeKeyDescriptor(desc keychain.KeyDescriptor) ([]byte, error) {
+	if desc.PubKey == nil {
+		return nil, fmt.Errorf("key descriptor is missing public key")
+	}
+
+	b := make([]byte, 0, keyDescriptorSize)
+	b = append(b, desc.PubKey.SerializeCompressed()...)
+	b = binary.BigEndian.AppendUint32(b, uint32(desc.Family))
+	b = binary.BigEndian.AppendUint32(b, desc.Index)
+
+	return b, nil
+}
+
+// decodeKeyDescriptor decodes a key descriptor encoded with
+// encodeKeyDescriptor.
+func decodeKeyDescriptor(b []byte) (keychain.KeyDescriptor, error) {
+	var desc keychain.KeyDescriptor
+	if len(b) != keyDescriptorSize {
+		return desc, fmt.Errorf("invalid key descriptor length %d",
+			len(b))
+	}
+
+	pubKey, err := btcec.ParsePubKey(b[:btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return desc, err
+	}
+
+	b = b[btcec.PubKeyBytesLenCompressed:]
+	desc.PubKey = pubKey
+	desc.Family = keychain.KeyFamily(binary.BigEndian.Uint32(b[:4]))
+	desc.Index = binary.BigEndian.Uint32(b[4:])
+
+	return desc, nil
+}
+
+// encodeScriptKey encodes a script key as its compressed public key. If the
+// tweaked script key is known, the key descriptor of its raw key and its tweak
+// follow.
+func encodeScriptKey(scriptKey asset.ScriptKey) ([]byte, error) {
+	if scriptKey.PubKey == nil {
+		return nil, fmt.Errorf("script key is missing public key")
+	}
+
+	b := scriptKey.PubKey.SerializeCompressed()
+	if scriptKey.TweakedScriptKey == nil {
+		return b, nil
+	}
+
+	rawKey, err := encodeKeyDescriptor(scriptKey.RawKey)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, rawKey...)
+
+	return append(b, scriptKey.Tweak...), nil
+}
+
+// decodeScriptKey decodes a script key encoded with encodeScriptKey.
+func decodeScriptKey(b []byte) (asset.ScriptKey, error) {
+	var scriptKey asset.ScriptKey
+	if len(b) < btcec.PubKeyBytesLenCompressed {
+		return scriptKey, fmt.Errorf("invalid script key length %d",
+			len(b))
+	}
+
+	pubKey, err := btcec.ParsePubKey(b[:btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return scriptKey, err
+	}
+	scriptKey.PubKey = pubKey
+
+	b = b[btcec.PubKeyBytesLenCompressed:]
+	if len(b) == 0 {
+		return scriptKey, nil
+	}
+	if len(b) < keyDescriptorSize {
+		return scriptKey, fmt.Errorf("invalid tweaked script key "+
+			"length %d", len(b))
+	}
+
+	rawKey, err := decodeKeyDescriptor(b[:keyDescriptorSize])
+	if err != nil {
+		return scriptKey, err
+	}
+	scriptKey.TweakedScriptKey = &asset.TweakedScriptKey{
+		RawKey: rawKey,
+	}
+	if tweak := b[keyDescriptorSize:]; len(tweak) > 0 {
+		scriptKey.Tweak = tweak
+	}
+
+	return scriptKey, nil
+}
@@ -0,0 +1,163 @@
+package tapfreighter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/mssmt"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransferHandoffEncoding tests that a transfer handoff survives an
+// encoding round trip.
+func TestTransferHandoffEncoding(t *testing.T) {
+	t.Parallel()
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+
+	newOutput := func(tweaked bool) TransferOutput {
+		value := btcutil.Amount(test.RandInt[uint32]())
+		keyFamily := keychain.KeyFamily(asset.TaprootAssetsKeyFamily)
+		scriptKey := asset.RandScriptKey(t)
+		if !tweaked {
+			scriptKey.TweakedScriptKey = nil
+		}
+
+		return TransferOutput{
+			Anchor: Anchor{
+				OutPoint: test.RandOp(t),
+				Value:    value,
+				InternalKey: keychain.KeyDescriptor{
+					KeyLocator: keychain.KeyLocator{
+						Family: keyFamily,
+						Index:  test.RandInt[uint32](),
+					},
+					PubKey: test.RandPubKey(t),
+				},
+				TaprootAssetRoot: test.RandBytes(32),
+				MerkleRoot:       test.RandBytes(32),
+				TapscriptSibling: test.RandBytes(33),
+			},
+			Type:           tappsbt.TypeSplitRoot,
+			ScriptKey:      scriptKey,
+			ScriptKeyLocal: tweaked,
+			Amount:         test.RandInt[uint64](),
+			AssetVersion:   asset.V1,
+			WitnessData: []asset.Witness{{
+				PrevID: &asset.PrevID{
+					OutPoint:  test.RandOp(t),
+					ID:        asset.RandID(t),
+					ScriptKey: asset.RandSerializedKey(t),
+				},
+				TxWitness: test.RandTxWitnesses(t),
+			}},
+			SplitCommitmentRoot: mssmt.NewComputedNode(
+				mssmt.NodeHash(test.RandHash()),
+				test.RandInt[uint64](),
+			),
+			ProofSuffix:      test.RandBytes(100),
+			ProofCourierAddr: test.RandBytes(30),
+		}
+	}
+
+	transferTime := time.Unix(0, test.RandInt[int64]()).UTC()
+	handoff := &TransferHandoff{
+		Parcel: &OutboundParcel{
+			AnchorTx:           anchorTx,
+			AnchorTxHeightHint: test.RandInt[uint32](),
+			TransferTime:       transferTime,
+			ChainFees:          int64(test.RandInt[uint32]()),
+			Staged:             true,
+			Inputs: []TransferInput{{
+				PrevID: asset.PrevID{
+					OutPoint:  test.RandOp(t),
+					ID:        asset.RandID(t),
+					ScriptKey: asset.RandSerializedKey(t),
+				},
+				Amount: test.RandInt[uint64](),
+			}},
+			Outputs: []TransferOutput{
+				newOutput(true), newOutput(false),
+			},
+		},
+		InputProofs: []proof.Blob{test.RandBytes(200)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, handoff.Encode(&buf))
+
+	var decoded TransferHandoff
+	require.NoError(t, decoded.Decode(&buf))
+
+	// Empty signature scripts aren't nil after decoding the anchor
+	// transaction, so we compare it by its hash.
+	require.Equal(t, anchorTx.TxHash(), decoded.Parcel.AnchorTx.TxHash())
+	decoded.Parcel.AnchorTx = anchorTx
+	require.Equal(t, handoff, &decoded)
+
+	// The proofs of all inputs need to be handed off.
+	handoff.InputProofs = nil
+	require.ErrorContains(t, handoff.Encode(&buf), "input proofs")
+}
+
+// TestClaimedParcel tests that a pending parcel that was claimed by another
+// node is neither released nor exported, while a parcel claimed by us is.
+func TestClaimedParcel(t *testing.T) {
+	t.Parallel()
+
+	assetInput, walletInput := test.RandOp(t), test.RandOp(t)
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: assetInput})
+	anchorTx.AddTxIn(&wire.TxIn{PreviousOutPoint: walletInput})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0x51}})
+	anchorTXID := anchorTx.TxHash()
+
+	parcel := &OutboundParcel{
+		AnchorTx: anchorTx,
+		Staged:   true,
+		Inputs: []TransferInput{{
+			PrevID: asset.PrevID{OutPoint: assetInput},
+		}},
+	}
+	exportLog := &stagedExportLog{
+		parcels: []*OutboundParcel{parcel},
+	}
+
+	var claimID [32]byte
+	test.RandRead(t, claimID[:])
+	wallet := &releaseWallet{
+		claims: map[wire.OutPoint][32]byte{
+			walletInput: claimID,
+		},
+	}
+	porter := NewChainPorter(&ChainPorterConfig{
+		Wallet:    wallet,
+		ExportLog: exportLog,
+	})
+
+	_, err := porter.ReleaseStagedParcel(anchorTXID)
+	require.ErrorIs(t, err, ErrTransferClaimed)
+	require.Empty(t, exportLog.unstaged)
+	require.Empty(t, wallet.released)
+
+	_, err = porter.ExportPendingParcel(anchorTXID)
+	require.ErrorIs(t, err, ErrTransferClaimed)
+
+	// Once the parcel carries the claim ID, it's ours to act on.
+	parcel.ClaimID = &claimID
+	claimed, err := porter.claimedElsewhere(context.Background(), parcel)
+	require.NoError(t, err)
+	require.False(t, claimed)
+}
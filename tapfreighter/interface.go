@@ -19,6 +19,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
 // CommitmentConstraints conveys the constraints on the type of Taproot asset
@@ -39,6 +40,155 @@ type CommitmentConstraints struct {
 	// MinAmt is the minimum amount that an asset commitment needs to hold
 	// to satisfy the constraints.
 	MinAmt uint64
+
+	// Tranche describes the order in which the tranches (asset IDs within
+	// the same group) of a grouped asset are considered. The commitments
+	// of the first tranche that can satisfy the constraints on its own are
+	// selected. Only if no single tranche can, commitments of multiple
+	// tranches are selected in that order. This only has an effect if the
+	// selection is constrained by the group key alone.
+	Tranche TrancheSelection
+
+	// MaxInputs is the maximum number of commitments that may be selected
+	// to satisfy the constraints. A value of zero means no limit.
+	MaxInputs uint32
+
+	// StrictMaxInputs indicates that the maximum number of inputs was
+	// requested explicitly for this selection, so it is never relaxed,
+	// regardless of the relaxation policy.
+	StrictMaxInputs bool
+
+	// NoLease indicates that the selected commitments shouldn't be leased,
+	// because they're only selected to preview a transfer.
+	NoLease bool
+
+	// Outpoints, if set, restricts the selection to the commitments
+	// anchored at these outpoints. Each of the outpoints must hold a
+	// commitment that satisfies the asset constraints above.
+	Outpoints []wire.OutPoint
+
+	// SharedOutpoints are the anchor outputs that were already selected
+	// for another virtual packet of the same transfer. Their commitments
+	// may be selected even though they're leased, so multiple assets
+	// anchored in the same output can be sent together.
+	SharedOutpoints []wire.OutPoint
+}
+
+// assetDesc returns a human-readable description of the asset the constraints
+// select, for logging purposes.
+func (c *CommitmentConstraints) assetDesc() string {
+	switch {
+	case c.AssetID != nil:
+		return fmt.Sprintf("asset_id=%v", c.AssetID.String())
+
+	case c.GroupKey != nil:
+		return fmt.Sprintf("group_key=%x",
+			c.GroupKey.SerializeCompressed())
+
+	default:
+		return "any asset"
+	}
+}
+
+// TranchePreference is an enum that describes which tranche of a grouped
+// asset should be preferred when selecting inputs for a transfer.
+type TranchePreference uint8
+
+const (
+	// TranchePreferNone means no tranche is preferred, only commitments
+	// of the exact asset ID of the transfer are selected.
+	TranchePreferNone TranchePreference = iota
+
+	// TranchePreferOldest prefers commitments of the tranche that was
+	// minted first, meaning the one with the lowest genesis block height.
+	TranchePreferOldest
+
+	// TranchePreferNewest prefers commitments of the tranche that was
+	// minted last, meaning the one with the highest genesis block height.
+	TranchePreferNewest
+
+	// TranchePreferGenesisPoint prefers commitments of the tranche that was
+	// minted with a specific genesis point.
+	TranchePreferGenesisPoint
+)
+
+// String returns a human-readable string for the tranche preference.
+func (t TranchePreference) String() string {
+	switch t {
+	case TranchePreferNone:
+		return "none"
+
+	case TranchePreferOldest:
+		return "oldest"
+
+	case TranchePreferNewest:
+		return "newest"
+
+	case TranchePreferGenesisPoint:
+		return "genesispoint"
+
+	default:
+		return fmt.Sprintf("<unknown_tranche_preference(%d)>", t)
+	}
+}
+
+// TrancheSelection describes the preferred tranche of a grouped asset when
+// selecting inputs for a transfer. If the preferred tranche can't satisfy the
+// amount, the next tranche in the order of the preference that can is used. If
+// none of them can on its own, inputs of multiple tranches are selected. The
+// preference only matters if the tranches carry a different meaning for the
+// application, for example different metadata versions.
+type TrancheSelection struct {
+	// Preference is the tranche preference.
+	Preference TranchePreference
+
+	// GenesisPoint is the genesis point of the preferred tranche. This is
+	// only used if Preference is TranchePreferGenesisPoint.
+	GenesisPoint wire.OutPoint
+}
+
+// ParseTrancheSelection parses a tranche preference string and an optional
+// genesis point string (in the txid:index format) into a tranche selection.
+func ParseTrancheSelection(preference,
+	genesisPoint string) (TrancheSelection, error) {
+
+	var sel TrancheSelection
+	switch preference {
+	case "", TranchePreferNone.String():
+		sel.Preference = TranchePreferNone
+
+	case TranchePreferOldest.String():
+		sel.Preference = TranchePreferOldest
+
+	case TranchePreferNewest.String():
+		sel.Preference = TranchePreferNewest
+
+	case TranchePreferGenesisPoint.String():
+		sel.Preference = TranchePreferGenesisPoint
+
+	default:
+		return sel, fmt.Errorf("unknown tranche preference: %v",
+			preference)
+	}
+
+	switch {
+	case sel.Preference == TranchePreferGenesisPoint && genesisPoint == "":
+		return sel, fmt.Errorf("genesis point must be set for tranche "+
+			"preference %v", sel.Preference)
+
+	case sel.Preference != TranchePreferGenesisPoint && genesisPoint != "":
+		return sel, fmt.Errorf("genesis point can only be set for "+
+			"tranche preference %v", TranchePreferGenesisPoint)
+
+	case genesisPoint != "":
+		op, err := wire.NewOutPointFromString(genesisPoint)
+		if err != nil {
+			return sel, fmt.Errorf("invalid genesis point: %w", err)
+		}
+		sel.GenesisPoint = *op
+	}
+
+	return sel, nil
 }
 
 // AnchoredCommitment is the response to satisfying the set of
@@ -60,6 +210,16 @@ type AnchoredCommitment struct {
 	// This will usually be nil.
 	TapscriptSibling *commitment.TapscriptPreimage
 
+	// GenesisBlockHeight is the height of the block that mined the genesis
+	// transaction of the asset, or zero if it isn't known. This is used to
+	// order the tranches of a grouped asset.
+	GenesisBlockHeight uint32
+
+	// AnchorBlockHeight is the height of the block that mined the anchor
+	// transaction, or zero if it isn't mined yet. This is used to enforce
+	// the number of confirmations required to spend the asset.
+	AnchorBlockHeight uint32
+
 	// Commitment is the full Taproot Asset commitment anchored at the above
 	// outpoint. This includes both the asset to be used as an input, along
 	// with any other assets that might be collocated in this commitment.
@@ -77,8 +237,87 @@ var (
 	ErrMatchingAssetsNotFound = fmt.Errorf("failed to find coin(s) that " +
 		"satisfy given constraints; if previous transfers are un-" +
 		"confirmed, wait for them to confirm before trying again")
+
+	// ErrIneligibleInput is returned when an outpoint the coin selection
+	// was restricted to doesn't hold a spendable coin of the asset that
+	// should be sent.
+	ErrIneligibleInput = fmt.Errorf("input doesn't hold a spendable coin " +
+		"of the asset")
+
+	// ErrMultipleTranches is returned when the inputs selected for a
+	// transfer of a grouped asset belong to multiple tranches. A virtual
+	// transaction can only spend a single asset ID, so such a transfer
+	// needs to be split into one transfer per tranche.
+	ErrMultipleTranches = fmt.Errorf("selected inputs belong to multiple " +
+		"tranches of the asset group")
+
+	// ErrTransferAbandoned is returned when the anchor transaction of a
+	// transfer can no longer confirm because one of its inputs was double
+	// spent by another transaction.
+	ErrTransferAbandoned = fmt.Errorf("transfer abandoned")
+
+	// ErrTransferConfirmed is returned when a transfer can't be cancelled
+	// because its anchor transaction already confirmed.
+	ErrTransferConfirmed = fmt.Errorf("transfer already confirmed")
+
+	// ErrTransferRollbackUnsupported is returned when the confirmation of
+	// a transfer can't be rolled back because it re-anchored passive
+	// assets, whose previous anchor isn't known anymore.
+	ErrTransferRollbackUnsupported = fmt.Errorf("transfer rollback not " +
+		"supported")
+
+	// ErrTransferClaimed is returned when a pending transfer can't be acted
+	// on because another node took it over.
+	ErrTransferClaimed = fmt.Errorf("transfer claimed by another node")
+
+	// ErrFeeRateTooLow is returned when the fee rate requested for the
+	// anchor transaction of a transfer is too low, for example because it
+	// doesn't pay enough to replace the current anchor transaction.
+	ErrFeeRateTooLow = fmt.Errorf("fee rate too low")
+
+	// ErrMaxInputsExceeded is returned when the amount of a transfer can
+	// only be satisfied by selecting more than the maximum number of
+	// inputs allowed.
+	ErrMaxInputsExceeded = fmt.Errorf("amount can only be satisfied by " +
+		"exceeding the maximum number of inputs; consider " +
+		"consolidating assets into fewer outputs first, for example " +
+		"by sending them to an address of the same node")
+
+	// ErrInsufficientConfirmations is returned when the amount of a
+	// transfer could be satisfied by the assets that are available, but
+	// not by the ones with enough confirmations to be spent.
+	ErrInsufficientConfirmations = fmt.Errorf("not enough assets have " +
+		"the required number of confirmations to be spent; wait for " +
+		"more confirmations before trying again")
+
+	// ErrLeaseNotFound is returned when a lease that should be released
+	// doesn't exist or is held by a different lease owner.
+	ErrLeaseNotFound = fmt.Errorf("no lease with the given owner found " +
+		"for outpoint")
 )
 
+// UTXOLease describes an active lease/lock/reservation of a managed asset
+// anchor UTXO.
+type UTXOLease struct {
+	// OutPoint is the anchor outpoint that is leased.
+	OutPoint wire.OutPoint
+
+	// AmtSats is the amount of satoshis carried by the anchor output.
+	AmtSats btcutil.Amount
+
+	// LeaseOwner is the owner of the lease.
+	LeaseOwner [32]byte
+
+	// Expiry is the time the lease expires and the UTXO is released
+	// automatically.
+	Expiry time.Time
+
+	// TransferAnchorTxID is the hash of the anchor transaction of the
+	// transfer that spends the UTXO, or nil if the lease isn't held by a
+	// transfer that was already logged.
+	TransferAnchorTxID *chainhash.Hash
+}
+
 // CoinLister attracts over the coin selection process needed to be
 // able to execute moving taproot assets on chain.
 type CoinLister interface {
@@ -103,6 +342,14 @@ type CoinLister interface {
 
 	// DeleteExpiredLeases deletes all expired leases from the database.
 	DeleteExpiredLeases(ctx context.Context) error
+
+	// ListLeases returns all leases that haven't expired yet.
+	ListLeases(ctx context.Context) ([]UTXOLease, error)
+
+	// ReleaseLease releases the lease of the given UTXO if it is held by
+	// the given lease owner. ErrLeaseNotFound is returned otherwise.
+	ReleaseLease(ctx context.Context, leaseOwner [32]byte,
+		utxoOutpoint wire.OutPoint) error
 }
 
 // MultiCommitmentSelectStrategy is an enum that describes the strategy that
@@ -120,11 +367,13 @@ const (
 // selecting coins during the asset send process.
 type CoinSelector interface {
 	// SelectCoins returns a set of not yet leased coins that satisfy the
-	// given constraints and strategy. The coins returned are leased for the
-	// default lease duration.
+	// given constraints and strategy. Unless the constraints say otherwise,
+	// the coins returned are leased for the default lease duration. If the
+	// constraints had to be relaxed to be satisfied, the relaxations are
+	// returned as well.
 	SelectCoins(ctx context.Context, constraints CommitmentConstraints,
 		strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment,
-		error)
+		[]CoinRelaxation, error)
 
 	// ReleaseCoins releases/unlocks coins that were previously leased and
 	// makes them available for coin selection again.
@@ -191,6 +440,11 @@ type TransferOutput struct {
 	// outbound transfer.
 	ScriptKeyLocal bool
 
+	// AssetID is the ID of the asset that is transferred to this output.
+	// A transfer can move multiple assets in a single anchor transaction,
+	// so this identifies the virtual packet the output belongs to.
+	AssetID asset.ID
+
 	// Amount is the new amount for the asset.
 	Amount uint64
 
@@ -212,6 +466,83 @@ type TransferOutput struct {
 	// ProofCourierAddr is the bytes encoded proof courier service address
 	// associated with this output.
 	ProofCourierAddr []byte
+
+	// ProofDelivery is the record of the delivery of the output's proof to
+	// its receiver. This is only populated when parcels are read from
+	// disk.
+	ProofDelivery ProofDelivery
+}
+
+// ProofDeliveryStatus is the outcome of the delivery of a transfer output's
+// proof to its receiver.
+type ProofDeliveryStatus uint8
+
+const (
+	// ProofDeliveryStatusNone indicates that no delivery outcome was
+	// recorded for the output. Either the output doesn't require a proof
+	// delivery, or the delivery didn't finish yet.
+	ProofDeliveryStatusNone ProofDeliveryStatus = iota
+
+	// ProofDeliveryStatusDelivered indicates that the receiver
+	// acknowledged the proof.
+	ProofDeliveryStatusDelivered
+
+	// ProofDeliveryStatusFailed indicates that the courier gave up on
+	// delivering the proof.
+	ProofDeliveryStatusFailed
+
+	// ProofDeliveryStatusSkipped indicates that no proof courier was
+	// available for the receiver, which needs to obtain the proof out of
+	// band.
+	ProofDeliveryStatusSkipped
+
+	// ProofDeliveryStatusPending indicates that the transfer was completed
+	// according to the delivery completion policy while the delivery of
+	// the proof was still in progress. Pending deliveries are resumed
+	// after a restart.
+	ProofDeliveryStatusPending
+)
+
+// String returns a human-readable string for the proof delivery status.
+func (s ProofDeliveryStatus) String() string {
+	switch s {
+	case ProofDeliveryStatusNone:
+		return "none"
+
+	case ProofDeliveryStatusDelivered:
+		return "delivered"
+
+	case ProofDeliveryStatusFailed:
+		return "failed"
+
+	case ProofDeliveryStatusSkipped:
+		return "skipped"
+
+	case ProofDeliveryStatusPending:
+		return "pending"
+
+	default:
+		return fmt.Sprintf("<unknown_delivery_status(%d)>", s)
+	}
+}
+
+// ProofDelivery is the historical record of the delivery of a transfer
+// output's proof to its receiver.
+type ProofDelivery struct {
+	// Status is the outcome of the delivery.
+	Status ProofDeliveryStatus
+
+	// CourierAddr is the address of the proof courier that delivered the
+	// proof. This is empty if the delivery was skipped.
+	CourierAddr []byte
+
+	// NumAttempts is the number of attempts the courier made to deliver
+	// the proof.
+	NumAttempts uint32
+
+	// ReceiptReceived indicates whether the receiver returned a signed
+	// delivery receipt for the proof.
+	ReceiptReceived bool
 }
 
 // OutboundParcel represents the database level delta of an outbound Taproot
@@ -232,10 +563,69 @@ type OutboundParcel struct {
 	// TransferTime holds the timestamp of the outbound spend.
 	TransferTime time.Time
 
+	// ConfirmationTime is the timestamp of the block that confirmed the
+	// anchor transaction. This is the zero time if the transfer isn't
+	// confirmed yet or the time wasn't recorded.
+	ConfirmationTime time.Time
+
+	// DeliveryCompleteTime is the time the transfer was marked as complete
+	// after all proofs were delivered. This is the zero time if the
+	// transfer isn't complete yet or the time wasn't recorded.
+	DeliveryCompleteTime time.Time
+
 	// ChainFees is the amount in sats paid in on-chain fees for the
 	// anchor transaction.
 	ChainFees int64
 
+	// Staged indicates that the anchor transaction was fully signed and
+	// logged, but must not be broadcast until the transfer is explicitly
+	// released.
+	Staged bool
+
+	// Cancelled indicates that the transfer was cancelled before its
+	// anchor transaction confirmed and its inputs were released.
+	Cancelled bool
+
+	// ClaimID is the ID the wallet inputs of the anchor transaction are
+	// leased with after the pending transfer was handed off to this node
+	// by another one. This is nil for transfers that were created by this
+	// node.
+	ClaimID *[32]byte
+
+	// Label is the optional human-readable label the transfer was
+	// requested with.
+	Label string
+
+	// IdempotencyKey is the optional client-supplied key the transfer was
+	// requested with. Repeated send requests with the same key return
+	// this transfer instead of creating a new one.
+	IdempotencyKey string
+
+	// AnchorPsbt is the funded PSBT the anchor transaction was signed
+	// from. It is needed to replace the anchor transaction with one that
+	// pays a higher fee rate. This is nil if it isn't known, for example
+	// for transfers that were handed off to this node by another one.
+	AnchorPsbt *tapgarden.FundedPsbt
+
+	// ReplacedAnchorTxs are the earlier versions of the anchor transaction
+	// that were replaced with ones paying a higher fee rate, oldest first.
+	// Each of them can still confirm instead of the current version.
+	ReplacedAnchorTxs []*AnchorTransaction
+
+	// FeeBumpAnchor is the output of the anchor transaction that is
+	// reserved for bumping its fee with a child transaction. This is nil
+	// if no such output was reserved.
+	FeeBumpAnchor *FeeBumpAnchor
+
+	// FeeBumpChildTXID is the ID of the child transaction that spends the
+	// fee bump anchor output of the current anchor transaction, if one was
+	// published.
+	FeeBumpChildTXID *chainhash.Hash
+
+	// FeeEscalations is the number of times the fee rate of the anchor
+	// transaction was escalated automatically.
+	FeeEscalations uint32
+
 	// PassiveAssets is the set of passive assets that are re-anchored
 	// during the parcel confirmation process.
 	PassiveAssets []*PassiveAssetReAnchor
@@ -247,6 +637,11 @@ type OutboundParcel struct {
 	// Outputs represents the list of new assets that were created with this
 	// transfer.
 	Outputs []TransferOutput
+
+	// CoinRelaxations are the coin selection constraints that had to be
+	// relaxed to fund the transfer. This is only known to the node that
+	// created the transfer and isn't persisted.
+	CoinRelaxations []CoinRelaxation
 }
 
 // AssetConfirmEvent is used to mark a batched spend as confirmed on disk.
@@ -265,6 +660,10 @@ type AssetConfirmEvent struct {
 	// point.
 	TxIndex int32
 
+	// ConfirmationTime is the timestamp of the block that confirmed the
+	// anchor point.
+	ConfirmationTime time.Time
+
 	// FinalProofs is the set of final full proof chain files that are going
 	// to be stored on disk, one for each output in the outbound parcel.
 	FinalProofs map[asset.SerializedKey]*proof.AnnotatedProof
@@ -272,6 +671,51 @@ type AssetConfirmEvent struct {
 	// PassiveAssetProofFiles is the set of passive asset proof files that
 	// are re-anchored during the parcel confirmation process.
 	PassiveAssetProofFiles map[[32]byte]proof.Blob
+
+	// ReOrgWatch is the optional re-org watch of the anchor transaction
+	// that is stored together with the confirmation, so the watch can be
+	// resumed after a restart.
+	ReOrgWatch *ReOrgWatch
+}
+
+// AnchorInput is a BTC level input of an anchor transaction.
+type AnchorInput struct {
+	// OutPoint is the outpoint spent by the anchor transaction.
+	OutPoint wire.OutPoint
+
+	// PkScript is the pk script of the spent output.
+	PkScript []byte
+}
+
+// ReOrgWatch is a confirmed parcel whose anchor transaction is watched for
+// re-orgs until it is buried under the re-org safe depth.
+type ReOrgWatch struct {
+	// Parcel is the watched parcel.
+	Parcel *OutboundParcel
+
+	// ConfHeight is the height at which the anchor transaction confirmed.
+	ConfHeight uint32
+
+	// Inputs are the BTC level inputs of the anchor transaction. They are
+	// watched for a double spend before an abandoned parcel is rolled
+	// back.
+	Inputs []AnchorInput
+
+	// AbandonPending is true if the anchor transaction was re-organized
+	// out and the parcel is abandoned once one of its inputs is spent by
+	// a different transaction.
+	AbandonPending bool
+}
+
+// ProofGapServe is a proof delivered to a universe courier whose receiver might
+// request the proofs missing from its proof chain from us.
+type ProofGapServe struct {
+	// Locator identifies the delivered proof in the proof archive.
+	Locator proof.Locator
+
+	// StartTime is the time the proof was delivered. We stop answering
+	// requests once the maximum serve duration passed since then.
+	StartTime time.Time
 }
 
 // PassiveAssetReAnchor includes the information needed to re-anchor a passive
@@ -321,10 +765,116 @@ type ExportLog interface {
 	// transactions for re-broadcast.
 	PendingParcels(context.Context) ([]*OutboundParcel, error)
 
+	// QueryParcelByIdempotencyKey returns the most recent parcel that
+	// wasn't cancelled and was requested with the given idempotency key at
+	// or after the given time. Nil is returned if there is no such parcel.
+	QueryParcelByIdempotencyKey(ctx context.Context, key string,
+		since time.Time) (*OutboundParcel, error)
+
 	// ConfirmParcelDelivery marks a spend event on disk as confirmed. This
 	// updates the on-chain reference information on disk to point to this
 	// new spend.
 	ConfirmParcelDelivery(context.Context, *AssetConfirmEvent) error
+
+	// UnstageParcel clears the staged flag of the pending parcel with the
+	// given anchor transaction ID, releasing it for broadcast. An error is
+	// returned if no such staged parcel exists.
+	UnstageParcel(context.Context, chainhash.Hash) error
+
+	// CancelParcel marks the unconfirmed parcel with the given anchor
+	// transaction ID as cancelled and releases the leases on its inputs.
+	// ErrTransferConfirmed is returned if the anchor transaction of the
+	// parcel already confirmed.
+	CancelParcel(context.Context, chainhash.Hash) error
+
+	// ReplaceParcelAnchorTx replaces the unconfirmed anchor transaction of
+	// the parcel with the given anchor transaction ID with the given
+	// version, which must create the same outputs at the same indexes.
+	// The replaced version is kept, as it can still confirm instead. If
+	// the given version is one that was replaced before, the parcel goes
+	// back to it and all versions created after it are discarded. The
+	// escalation flag marks the new version as created by an automatic
+	// fee escalation. ErrTransferConfirmed is returned if the anchor
+	// transaction of the parcel already confirmed.
+	ReplaceParcelAnchorTx(ctx context.Context, oldTXID chainhash.Hash,
+		anchorTx *AnchorTransaction, escalation bool) error
+
+	// LogFeeBumpChild records the given child transaction as spending the
+	// fee bump anchor output of the unconfirmed anchor transaction with
+	// the given ID. A nil child transaction ID removes the record again.
+	// ErrTransferConfirmed is returned if the anchor transaction already
+	// confirmed.
+	LogFeeBumpChild(ctx context.Context, anchorTXID chainhash.Hash,
+		childTXID *chainhash.Hash) error
+
+	// AbandonParcel rolls back the confirmation of the parcel with the
+	// given anchor transaction ID, after its anchor transaction was
+	// re-organized out of the best chain. The parcel is marked as
+	// cancelled, its inputs are spendable again and the assets it created
+	// are removed. ErrTransferRollbackUnsupported is returned if the parcel
+	// re-anchored passive assets. The re-org watch of the parcel is
+	// removed.
+	AbandonParcel(context.Context, chainhash.Hash) error
+
+	// QuarantineParcel leases the anchor outputs of the parcel with the
+	// given anchor transaction ID, so the assets it created can't be spent
+	// until the leases are released manually. The re-org watch of the
+	// parcel is removed.
+	QuarantineParcel(context.Context, chainhash.Hash) error
+
+	// ReOrgWatches returns the parcels whose anchor transactions are still
+	// watched for re-orgs.
+	ReOrgWatches(ctx context.Context) ([]*ReOrgWatch, error)
+
+	// UpdateReOrgWatch updates the confirmation height and the pending
+	// abandon flag of the re-org watch of the parcel with the given anchor
+	// transaction ID.
+	UpdateReOrgWatch(ctx context.Context, anchorTXID chainhash.Hash,
+		confHeight uint32, abandonPending bool) error
+
+	// RemoveReOrgWatch removes the re-org watch of the parcel with the
+	// given anchor transaction ID, once its anchor transaction is buried
+	// under the re-org safe depth.
+	RemoveReOrgWatch(ctx context.Context, anchorTXID chainhash.Hash) error
+
+	// LogProofDelivery records the outcome of the delivery of the proof of
+	// the transfer output with the given anchor outpoint and script key.
+	// The receipt flag of the given record is ignored, as receipts are
+	// tracked by the delivery log of the proof courier.
+	LogProofDelivery(ctx context.Context, anchorPoint wire.OutPoint,
+		scriptKey *btcec.PublicKey, delivery ProofDelivery) error
+
+	// PendingProofDeliveries returns the confirmed parcels that have at
+	// least one output with a pending proof delivery.
+	PendingProofDeliveries(ctx context.Context) ([]*OutboundParcel, error)
+
+	// LogProofGapServe records that we answer the requests of the receiver
+	// of the given proof for the proofs missing from its proof chain, so
+	// serving is resumed after a restart.
+	LogProofGapServe(ctx context.Context, serve ProofGapServe) error
+
+	// ProofGapServes returns the proofs whose receivers might still
+	// request the proofs missing from their proof chain.
+	ProofGapServes(ctx context.Context) ([]ProofGapServe, error)
+
+	// RemoveProofGapServe removes the proof gap serve of the proof with
+	// the given locator.
+	RemoveProofGapServe(ctx context.Context, locator proof.Locator) error
+
+	// SweepableAnchors returns the confirmed anchor outputs of the node
+	// with a value of at most the given value that only anchor spent
+	// assets and weren't spent or swept yet.
+	SweepableAnchors(ctx context.Context,
+		maxValue btcutil.Amount) ([]*SweepableAnchor, error)
+
+	// LogAnchorSweep records that the anchor outputs with the given
+	// outpoints were swept by the transaction with the given ID at the
+	// given time.
+	LogAnchorSweep(ctx context.Context, sweepTXID chainhash.Hash,
+		anchorPoints []wire.OutPoint, sweptAt time.Time) error
+
+	// AnchorSweeps returns all anchor outputs that were swept.
+	AnchorSweeps(ctx context.Context) ([]*AnchorSweep, error)
 }
 
 // ChainBridge aliases into the ChainBridge of the tapgarden package.
@@ -337,6 +887,32 @@ type WalletAnchor interface {
 	// SignPsbt signs all the inputs it can in the passed-in PSBT packet,
 	// returning a new one with updated signature/witness data.
 	SignPsbt(ctx context.Context, packet *psbt.Packet) (*psbt.Packet, error)
+
+	// LeaseOutput leases the given output of the wallet for the given
+	// duration, so it isn't selected to fund another transaction. An
+	// existing lease acquired when funding a PSBT with it is extended.
+	LeaseOutput(ctx context.Context, op wire.OutPoint,
+		duration time.Duration) error
+
+	// ReleaseOutput releases the lease the wallet acquired on the given
+	// output when funding a PSBT with it, so it can be selected again.
+	ReleaseOutput(ctx context.Context, op wire.OutPoint) error
+
+	// ClaimOutputs moves the leases the wallet holds on the given outputs
+	// to the given claim ID for the given duration. Either all outputs are
+	// claimed or none, an error is returned if any of them is already
+	// leased with another claim ID.
+	ClaimOutputs(ctx context.Context, claimID [32]byte,
+		ops []wire.OutPoint, duration time.Duration) error
+
+	// OutputClaims returns the claim IDs of all outputs of the wallet that
+	// are leased with an ID other than the one the wallet uses when
+	// funding a PSBT, keyed by their outpoint.
+	OutputClaims(ctx context.Context) (map[wire.OutPoint][32]byte, error)
+
+	// NextAddr returns a new Taproot address of the default account of
+	// the wallet.
+	NextAddr(ctx context.Context) (btcutil.Address, error)
 }
 
 // KeyRing aliases into the KeyRing of the tapgarden package.
@@ -353,6 +929,50 @@ type Porter interface {
 	// returned with the pending transfer information.
 	RequestShipment(req Parcel) (*OutboundParcel, error)
 
+	// EstimateShipment previews the send of the given address parcel
+	// without signing, storing or broadcasting it and without leasing any
+	// of its inputs.
+	EstimateShipment(req *AddressParcel) (*SendEstimate, error)
+
+	// ReleaseStagedParcel releases the staged parcel with the given anchor
+	// transaction ID for broadcast.
+	ReleaseStagedParcel(anchorTXID chainhash.Hash) (*OutboundParcel,
+		error)
+
+	// BumpParcelFee replaces the unconfirmed anchor transaction of the
+	// pending parcel with the given anchor transaction ID with one that
+	// pays the given fee rate.
+	BumpParcelFee(anchorTXID chainhash.Hash,
+		feeRate chainfee.SatPerKWeight) (*OutboundParcel, error)
+
+	// BumpParcelFeeCPFP bumps the fee of the unconfirmed anchor
+	// transaction of the pending parcel with the given anchor transaction
+	// ID to the given fee rate by publishing a child transaction that
+	// spends its fee bump anchor output. The returned parcel references
+	// the child transaction.
+	BumpParcelFeeCPFP(anchorTXID chainhash.Hash,
+		feeRate chainfee.SatPerKWeight) (*OutboundParcel, error)
+
+	// CancelPendingParcel cancels the pending parcel with the given anchor
+	// transaction ID and releases its inputs. ErrTransferConfirmed is
+	// returned if the anchor transaction of the parcel already confirmed.
+	CancelPendingParcel(anchorTXID chainhash.Hash) (*OutboundParcel,
+		error)
+
+	// ExportPendingParcel exports the pending parcel with the given anchor
+	// transaction ID, so another node can take it over.
+	ExportPendingParcel(anchorTXID chainhash.Hash) (*TransferHandoff,
+		error)
+
+	// ImportPendingParcel takes over a pending parcel exported by another
+	// node and resumes its delivery.
+	ImportPendingParcel(handoff *TransferHandoff) (*OutboundParcel,
+		error)
+
+	// AnchorSweepStatus returns the status of the background sweeper of
+	// anchor outputs that only anchor spent assets.
+	AnchorSweepStatus(ctx context.Context) (*AnchorSweepStatus, error)
+
 	// Start signals that the asset minter should being operations.
 	Start() error
 
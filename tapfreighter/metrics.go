@@ -0,0 +1,145 @@
+package tapfreighter
+
+import (
+	"sort"
+	"time"
+)
+
+// LatencyPercentiles describes the distribution of a set of latency samples.
+type LatencyPercentiles struct {
+	// NumSamples is the number of samples the percentiles were computed
+	// from.
+	NumSamples int
+
+	// P50 is the median latency.
+	P50 time.Duration
+
+	// P90 is the 90th percentile latency.
+	P90 time.Duration
+
+	// P99 is the 99th percentile latency.
+	P99 time.Duration
+}
+
+// TransferMetrics holds latency and throughput figures for the outbound
+// transfers that were initiated within a given time window.
+type TransferMetrics struct {
+	// Start is the start of the evaluated time window.
+	Start time.Time
+
+	// End is the end of the evaluated time window.
+	End time.Time
+
+	// NumTransfers is the number of transfers initiated within the time
+	// window.
+	NumTransfers int
+
+	// NumCompleted is the number of transfers within the time window that
+	// completed proof delivery.
+	NumCompleted int
+
+	// CompletedPerHour is the number of completed transfers per hour over
+	// the time window.
+	CompletedPerHour float64
+
+	// ConfirmationLatency is the latency between initiating a transfer and
+	// the anchor transaction confirming on chain.
+	ConfirmationLatency LatencyPercentiles
+
+	// DeliveryLatency is the latency between initiating a transfer and
+	// completing the proof delivery to all receivers.
+	DeliveryLatency LatencyPercentiles
+}
+
+// CalcTransferMetrics computes the transfer metrics for all parcels that were
+// initiated within the given time window. If the start time is zero, the
+// window starts at the earliest transfer found.
+func CalcTransferMetrics(parcels []*OutboundParcel, start,
+	end time.Time) *TransferMetrics {
+
+	var (
+		confLatencies     []time.Duration
+		deliveryLatencies []time.Duration
+		metrics           = &TransferMetrics{
+			Start: start,
+			End:   end,
+		}
+	)
+	for _, parcel := range parcels {
+		transferTime := parcel.TransferTime
+		if transferTime.Before(start) || transferTime.After(end) {
+			continue
+		}
+
+		if metrics.Start.IsZero() || transferTime.Before(metrics.Start) {
+			metrics.Start = transferTime
+		}
+
+		metrics.NumTransfers++
+
+		// The block timestamp is only accurate to within a couple of
+		// hours, so it can end up being before the transfer time. We
+		// count those as zero latency.
+		if !parcel.ConfirmationTime.IsZero() {
+			confLatencies = append(confLatencies, nonNegative(
+				parcel.ConfirmationTime.Sub(transferTime),
+			))
+		}
+
+		if !parcel.DeliveryCompleteTime.IsZero() {
+			metrics.NumCompleted++
+			deliveryLatencies = append(deliveryLatencies, nonNegative(
+				parcel.DeliveryCompleteTime.Sub(transferTime),
+			))
+		}
+	}
+
+	window := metrics.End.Sub(metrics.Start)
+	if window > 0 {
+		metrics.CompletedPerHour = float64(metrics.NumCompleted) /
+			window.Hours()
+	}
+
+	metrics.ConfirmationLatency = calcPercentiles(confLatencies)
+	metrics.DeliveryLatency = calcPercentiles(deliveryLatencies)
+
+	return metrics
+}
+
+// calcPercentiles computes the p50, p90 and p99 percentiles of the given
+// samples using the nearest-rank method.
+func calcPercentiles(samples []time.Duration) LatencyPercentiles {
+	if len(samples) == 0 {
+		return LatencyPercentiles{}
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+
+	rank := func(percentile int) time.Duration {
+		// The nearest rank is ceil(p/100 * n), which is 1-based.
+		idx := (percentile*len(samples) + 99) / 100
+		if idx < 1 {
+			idx = 1
+		}
+
+		return samples[idx-1]
+	}
+
+	return LatencyPercentiles{
+		NumSamples: len(samples),
+		P50:        rank(50),
+		P90:        rank(90),
+		P99:        rank(99),
+	}
+}
+
+// nonNegative returns the given duration or zero if it is negative.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
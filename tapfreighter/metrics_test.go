@@ -0,0 +1,75 @@
+package tapfreighter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalcTransferMetrics tests that the transfer metrics are computed
+// correctly from a set of parcels.
+func TestCalcTransferMetrics(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	end := start.Add(2 * time.Hour)
+
+	var parcels []*OutboundParcel
+	for i := 1; i <= 10; i++ {
+		transferTime := start.Add(time.Duration(i) * time.Minute)
+		parcels = append(parcels, &OutboundParcel{
+			TransferTime: transferTime,
+			ConfirmationTime: transferTime.Add(
+				time.Duration(i) * time.Second,
+			),
+			DeliveryCompleteTime: transferTime.Add(
+				time.Duration(i) * 10 * time.Second,
+			),
+		})
+	}
+
+	// A pending transfer only counts towards the number of transfers, and
+	// a block timestamp before the transfer time counts as zero latency.
+	pendingTime := start.Add(time.Hour)
+	parcels = append(parcels, &OutboundParcel{
+		TransferTime:     pendingTime,
+		ConfirmationTime: pendingTime.Add(-time.Minute),
+	})
+
+	// Transfers outside the window are ignored.
+	parcels = append(parcels, &OutboundParcel{
+		TransferTime:         end.Add(time.Minute),
+		ConfirmationTime:     end.Add(time.Hour),
+		DeliveryCompleteTime: end.Add(time.Hour),
+	})
+
+	metrics := CalcTransferMetrics(parcels, start, end)
+	require.Equal(t, 11, metrics.NumTransfers)
+	require.Equal(t, 10, metrics.NumCompleted)
+	require.InDelta(t, 5.0, metrics.CompletedPerHour, 0.0001)
+
+	require.Equal(t, LatencyPercentiles{
+		NumSamples: 11,
+		P50:        5 * time.Second,
+		P90:        9 * time.Second,
+		P99:        10 * time.Second,
+	}, metrics.ConfirmationLatency)
+	require.Equal(t, LatencyPercentiles{
+		NumSamples: 10,
+		P50:        50 * time.Second,
+		P90:        90 * time.Second,
+		P99:        100 * time.Second,
+	}, metrics.DeliveryLatency)
+
+	// Without a start time, the window starts at the earliest transfer.
+	metrics = CalcTransferMetrics(parcels, time.Time{}, end)
+	require.Equal(t, start.Add(time.Minute), metrics.Start)
+	require.Equal(t, 11, metrics.NumTransfers)
+
+	// An empty set results in empty metrics.
+	metrics = CalcTransferMetrics(nil, start, end)
+	require.Zero(t, metrics.NumTransfers)
+	require.Zero(t, metrics.CompletedPerHour)
+	require.Equal(t, LatencyPercentiles{}, metrics.DeliveryLatency)
+}
@@ -3,10 +3,14 @@ package tapfreighter
 import (
 	"bytes"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
@@ -17,6 +21,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tappsbt"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
 // SendState is an enum that describes the current state of a pending outbound
@@ -63,6 +68,10 @@ const (
 	// SendStateComplete is the state which is reached once entire asset
 	// transfer process is complete.
 	SendStateComplete
+
+	// SendStateCancelled is the terminal state of a transfer that was
+	// cancelled while waiting for its anchor transaction to confirm.
+	SendStateCancelled
 )
 
 // String returns a human-readable version of SendState.
@@ -95,11 +104,75 @@ func (s SendState) String() string {
 	case SendStateComplete:
 		return "SendStateComplete"
 
+	case SendStateCancelled:
+		return "SendStateCancelled"
+
 	default:
 		return fmt.Sprintf("<unknown_state(%d)>", s)
 	}
 }
 
+// sendStateSuccessors maps each send state to the states a parcel can move to
+// once the state was executed.
+var sendStateSuccessors = map[SendState][]SendState{
+	SendStateVirtualCommitmentSelect: {SendStateVirtualSign},
+	SendStateVirtualSign:             {SendStateAnchorSign},
+	SendStateAnchorSign:              {SendStateLogCommit},
+
+	// A staged parcel is complete once it was logged, it is only
+	// broadcast after it was released.
+	SendStateLogCommit: {SendStateBroadcast, SendStateComplete},
+
+	SendStateBroadcast: {SendStateWaitTxConf},
+
+	// A transfer can be cancelled as long as its anchor transaction
+	// didn't confirm.
+	SendStateWaitTxConf: {SendStateStoreProofs, SendStateCancelled},
+
+	SendStateStoreProofs:           {SendStateReceiverProofTransfer},
+	SendStateReceiverProofTransfer: {SendStateComplete},
+	SendStateComplete:              nil,
+	SendStateCancelled:             nil,
+}
+
+// SendStates returns all send states in the order a parcel moves through them,
+// followed by the cancelled state.
+func SendStates() []SendState {
+	states := make([]SendState, 0, SendStateCancelled+1)
+	for state := SendState(0); state <= SendStateCancelled; state++ {
+		states = append(states, state)
+	}
+
+	return states
+}
+
+// Successors returns the states a parcel can move to from this state. The
+// terminal states have no successors.
+func (s SendState) Successors() []SendState {
+	return sendStateSuccessors[s]
+}
+
+// IsTerminal returns true if a parcel doesn't move on from this state, which
+// is the case once it completed or was cancelled.
+func (s SendState) IsTerminal() bool {
+	return s == SendStateComplete || s == SendStateCancelled
+}
+
+// IsInitial returns true if a parcel can start out in this state. Address
+// parcels start with the coin selection, pre-signed parcels with the
+// anchor signing and pending parcels that are resumed with the broadcast.
+func (s SendState) IsInitial() bool {
+	switch s {
+	case SendStateVirtualCommitmentSelect, SendStateAnchorSign,
+		SendStateBroadcast:
+
+		return true
+
+	default:
+		return false
+	}
+}
+
 // Parcel is an interface that each parcel type must implement.
 type Parcel interface {
 	// pkg returns the send package that should be delivered.
@@ -122,6 +195,14 @@ type parcelKit struct {
 	errChan chan error
 }
 
+// MaxTransferLabelLen is the maximum length in bytes of the label an outbound
+// transfer can be stored with.
+const MaxTransferLabelLen = 256
+
+// MaxIdempotencyKeyLen is the maximum length in bytes of the idempotency key
+// an outbound transfer can be requested with.
+const MaxIdempotencyKeyLen = 128
+
 // AddressParcel is the main request to issue an asset transfer. This packages a
 // destination address, and also response context.
 type AddressParcel struct {
@@ -130,20 +211,71 @@ type AddressParcel struct {
 	// destAddrs is the list of address that should be used to satisfy the
 	// transfer.
 	destAddrs []*address.Tap
+
+	// maxInputs is the maximum number of asset inputs the transfer may
+	// select. If zero, the wallet's configured maximum is used.
+	maxInputs uint32
+
+	// inputs are the anchor outpoints of the assets the transfer may
+	// spend. If empty, the inputs are selected freely.
+	inputs []wire.OutPoint
+
+	// feeBumpAnchor indicates that an output for bumping the fee of the
+	// anchor transaction should be reserved, even if the porter isn't
+	// configured to always do so.
+	feeBumpAnchor bool
+
+	// feeRate is the fee rate the anchor transaction should be funded
+	// with. If zero, the fee rate is estimated by the chain bridge.
+	feeRate chainfee.SatPerKWeight
+
+	// proofCourierAddr is the optional proof courier the proofs of the
+	// transfer are delivered through, instead of the ones of the
+	// destination addresses.
+	proofCourierAddr *url.URL
+
+	// label is the optional human-readable label the transfer is stored
+	// with.
+	label string
+
+	// idempotencyKey is the optional client-supplied key of the request.
+	// If a transfer was already requested with the same key, it is
+	// returned instead of creating a new one.
+	idempotencyKey string
 }
 
 // A compile-time assertion to ensure AddressParcel implements the parcel
 // interface.
 var _ Parcel = (*AddressParcel)(nil)
 
-// NewAddressParcel creates a new AddressParcel.
-func NewAddressParcel(destAddrs ...*address.Tap) *AddressParcel {
+// NewAddressParcel creates a new AddressParcel. If maxInputs is non-zero, it
+// overrides the wallet's configured maximum number of asset inputs. If inputs
+// is non-empty, only the assets anchored at these outpoints may be spent. If
+// feeBumpAnchor is true, an output for bumping the fee of the anchor
+// transaction is reserved. If feeRate is non-zero, the anchor transaction is
+// funded with it instead of an estimated fee rate. If proofCourierAddr is
+// non-nil, the proofs are delivered through it instead of the proof couriers
+// of the destination addresses. The label is stored with the transfer. If
+// idempotencyKey is non-empty, a transfer that was already requested with the
+// same key is returned instead of creating a new one.
+func NewAddressParcel(maxInputs uint32, inputs []wire.OutPoint,
+	feeBumpAnchor bool, feeRate chainfee.SatPerKWeight,
+	proofCourierAddr *url.URL, label, idempotencyKey string,
+	destAddrs ...*address.Tap) *AddressParcel {
+
 	return &AddressParcel{
 		parcelKit: &parcelKit{
 			respChan: make(chan *OutboundParcel, 1),
 			errChan:  make(chan error, 1),
 		},
-		destAddrs: destAddrs,
+		destAddrs:        destAddrs,
+		maxInputs:        maxInputs,
+		inputs:           inputs,
+		feeBumpAnchor:    feeBumpAnchor,
+		feeRate:          feeRate,
+		proofCourierAddr: proofCourierAddr,
+		label:            label,
+		idempotencyKey:   idempotencyKey,
 	}
 }
 
@@ -154,7 +286,9 @@ func (p *AddressParcel) pkg() *sendPackage {
 
 	// Initialize a package with the destination address.
 	return &sendPackage{
-		Parcel: p,
+		Parcel:         p,
+		Label:          p.label,
+		IdempotencyKey: p.idempotencyKey,
 	}
 }
 
@@ -163,6 +297,32 @@ func (p *AddressParcel) kit() *parcelKit {
 	return p.parcelKit
 }
 
+// sendAddrs returns the destination addresses of the parcel as they should be
+// funded. If the parcel overrides the proof courier, the addresses are copied
+// with the proof courier replaced, so the courier is used for the delivery and
+// recorded with the transfer outputs.
+func (p *AddressParcel) sendAddrs() []*address.Tap {
+	if p.proofCourierAddr == nil {
+		return p.destAddrs
+	}
+
+	addrs := make([]*address.Tap, len(p.destAddrs))
+	for idx := range p.destAddrs {
+		addrs[idx] = p.destAddrs[idx].Copy()
+		addrs[idx].ProofCourierAddr = *p.proofCourierAddr
+	}
+
+	return addrs
+}
+
+// sameCourier returns true if both proof courier addresses point to the same
+// courier service, which is identified by the protocol, host and port.
+func sameCourier(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) &&
+		strings.EqualFold(a.Hostname(), b.Hostname()) &&
+		a.Port() == b.Port()
+}
+
 // Validate validates the parcel.
 func (p *AddressParcel) Validate() error {
 	// We need at least one address to send to in an address parcel.
@@ -171,6 +331,14 @@ func (p *AddressParcel) Validate() error {
 			"specified in address parcel")
 	}
 
+	if p.proofCourierAddr != nil {
+		_, err := proof.ParseCourierAddrUrl(*p.proofCourierAddr)
+		if err != nil {
+			return fmt.Errorf("invalid override proof courier "+
+				"address: %w", err)
+		}
+	}
+
 	for idx := range p.destAddrs {
 		tapAddr := p.destAddrs[idx]
 
@@ -180,6 +348,126 @@ func (p *AddressParcel) Validate() error {
 			return fmt.Errorf("invalid proof courier address: %w",
 				err)
 		}
+
+		// The receiver only listens for the proof on the courier of
+		// its address, so the override can't point to any other
+		// courier service. Only the remaining parts of the URL, like
+		// the path or credentials, may differ.
+		override := p.proofCourierAddr
+		if override != nil && !sameCourier(
+			override, &tapAddr.ProofCourierAddr,
+		) {
+
+			return fmt.Errorf("proof courier %v isn't shared with "+
+				"the receiver of address %d, which expects "+
+				"the courier %v", override.Redacted(), idx,
+				tapAddr.ProofCourierAddr.Redacted())
+		}
+	}
+
+	// A fee rate below the relay minimum would result in an anchor
+	// transaction that isn't relayed by the network.
+	if p.feeRate != 0 && p.feeRate < chainfee.FeePerKwFloor {
+		return fmt.Errorf("fee rate %v is below the minimum relay "+
+			"fee rate of %v", p.feeRate, chainfee.FeePerKwFloor)
+	}
+
+	if len(p.label) > MaxTransferLabelLen {
+		return fmt.Errorf("transfer label of %d bytes exceeds the "+
+			"maximum of %d bytes", len(p.label),
+			MaxTransferLabelLen)
+	}
+
+	if len(p.idempotencyKey) > MaxIdempotencyKeyLen {
+		return fmt.Errorf("idempotency key of %d bytes exceeds the "+
+			"maximum of %d bytes", len(p.idempotencyKey),
+			MaxIdempotencyKeyLen)
+	}
+
+	return nil
+}
+
+// matchesTransfer returns an error if the given transfer doesn't pay the
+// destination addresses of the parcel. It makes sure a send request that
+// re-uses an idempotency key is the request the transfer was created for.
+func (p *AddressParcel) matchesTransfer(transfer *OutboundParcel) error {
+	// destination identifies an asset output paid to an address.
+	type destination struct {
+		assetID   asset.ID
+		scriptKey asset.SerializedKey
+		amount    uint64
+	}
+
+	dests := make(map[destination]int, len(p.destAddrs))
+	for _, tapAddr := range p.destAddrs {
+		dests[destination{
+			assetID:   tapAddr.AssetID,
+			scriptKey: asset.ToSerialized(&tapAddr.ScriptKey),
+			amount:    tapAddr.Amount,
+		}]++
+	}
+
+	// Every output that isn't ours must pay one of the addresses, and
+	// every address must be paid by exactly one output.
+	for _, out := range transfer.Outputs {
+		if out.ScriptKeyLocal || out.ScriptKey.PubKey == nil {
+			continue
+		}
+
+		dest := destination{
+			assetID:   out.AssetID,
+			scriptKey: asset.ToSerialized(out.ScriptKey.PubKey),
+			amount:    out.Amount,
+		}
+		if dests[dest] == 0 {
+			return fmt.Errorf("output paying %d of asset %v to "+
+				"script key %x isn't part of the request",
+				out.Amount, out.AssetID, dest.scriptKey[:])
+		}
+		dests[dest]--
+	}
+
+	for dest, numUnpaid := range dests {
+		if numUnpaid != 0 {
+			return fmt.Errorf("address paying %d of asset %v to "+
+				"script key %x isn't part of the transfer",
+				dest.amount, dest.assetID, dest.scriptKey[:])
+		}
+	}
+
+	return nil
+}
+
+// checkCouriers makes sure the proofs can be delivered to the proof couriers of
+// all destination addresses with the given courier config. Currently this
+// only checks that email receivers support one of our encryption schemes, as
+// the email courier is the only one that negotiates an encryption scheme.
+func (p *AddressParcel) checkCouriers(cfg *proof.CourierCfg) error {
+	var emailCfg *proof.EmailCourierCfg
+	if cfg != nil {
+		emailCfg = cfg.EmailCfg
+	}
+
+	destAddrs := p.sendAddrs()
+	for idx := range destAddrs {
+		courierAddr, err := proof.ParseCourierAddrUrl(
+			destAddrs[idx].ProofCourierAddr,
+		)
+		if err != nil {
+			return fmt.Errorf("invalid proof courier address: %w",
+				err)
+		}
+
+		emailAddr, ok := courierAddr.(*proof.EmailCourierAddr)
+		if !ok {
+			continue
+		}
+
+		_, err = emailAddr.NegotiateEncScheme(emailCfg)
+		if err != nil {
+			return fmt.Errorf("unable to send to address %d: %w",
+				idx, err)
+		}
 	}
 
 	return nil
@@ -205,12 +493,28 @@ func NewPendingParcel(outboundPkg *OutboundParcel) *PendingParcel {
 
 // pkg returns the send package that should be delivered.
 func (p *PendingParcel) pkg() *sendPackage {
+	// The funded anchor PSBT is stored with the transfer, so the anchor
+	// transaction of a resumed transfer can still be replaced.
+	var anchorTx *AnchorTransaction
+	if p.outboundPkg.AnchorPsbt != nil {
+		anchorTx = &AnchorTransaction{
+			FundedPsbt:    p.outboundPkg.AnchorPsbt,
+			FinalTx:       p.outboundPkg.AnchorTx,
+			ChainFees:     p.outboundPkg.ChainFees,
+			FeeBumpAnchor: p.outboundPkg.FeeBumpAnchor,
+		}
+	}
+
 	// A pending parcel has already had its transfer transaction broadcast.
 	// We set the send package state such that the send process will
 	// rebroadcast and then wait for the transfer to confirm.
 	return &sendPackage{
-		OutboundPkg: p.outboundPkg,
-		SendState:   SendStateBroadcast,
+		OutboundPkg:    p.outboundPkg,
+		SendState:      SendStateBroadcast,
+		Parcel:         p,
+		AnchorTx:       anchorTx,
+		Label:          p.outboundPkg.Label,
+		IdempotencyKey: p.outboundPkg.IdempotencyKey,
 	}
 }
 
@@ -225,6 +529,33 @@ func (p *PendingParcel) Validate() error {
 	return nil
 }
 
+// StagedParcel wraps another parcel and stops its delivery right after the
+// fully signed anchor transaction was committed to disk. The transfer is only
+// broadcast once it is released through ReleaseStagedParcel. Until then, the
+// inputs of the transfer stay leased.
+type StagedParcel struct {
+	Parcel
+}
+
+// A compile-time assertion to ensure StagedParcel implements the parcel
+// interface.
+var _ Parcel = (*StagedParcel)(nil)
+
+// NewStagedParcel creates a new StagedParcel that wraps the given parcel.
+func NewStagedParcel(parcel Parcel) *StagedParcel {
+	return &StagedParcel{
+		Parcel: parcel,
+	}
+}
+
+// pkg returns the send package that should be delivered.
+func (p *StagedParcel) pkg() *sendPackage {
+	sendPkg := p.Parcel.pkg()
+	sendPkg.Staged = true
+
+	return sendPkg
+}
+
 // PreSignedParcel is a request to issue an asset transfer of a pre-signed
 // parcel. This packages a virtual transaction, the input commitment, and also
 // the response context.
@@ -265,10 +596,12 @@ func (p *PreSignedParcel) pkg() *sendPackage {
 	// Initialize a package the signed virtual transaction and input
 	// commitment.
 	return &sendPackage{
-		Parcel:           p,
-		SendState:        SendStateAnchorSign,
-		VirtualPacket:    p.vPkt,
-		InputCommitments: p.inputCommitments,
+		Parcel:         p,
+		SendState:      SendStateAnchorSign,
+		VirtualPackets: []*tappsbt.VPacket{p.vPkt},
+		InputCommitments: []tappsbt.InputCommitments{
+			p.inputCommitments,
+		},
 	}
 }
 
@@ -283,22 +616,150 @@ func (p *PreSignedParcel) Validate() error {
 	return nil
 }
 
+// PreAnchoredParcel is a request to issue an asset transfer of a pre-signed
+// virtual transaction that is anchored by an externally signed anchor
+// transaction. The anchor transaction is verified to commit to the virtual
+// transactions before the transfer is logged and broadcast.
+type PreAnchoredParcel struct {
+	*parcelKit
+
+	// vPkt is the virtual transaction that should be delivered.
+	vPkt *tappsbt.VPacket
+
+	// inputCommitments are the commitments for the input that are being
+	// spent in the virtual transaction.
+	inputCommitments tappsbt.InputCommitments
+
+	// passiveAssets are the signed re-anchors of the passive assets the
+	// anchor transaction commits to.
+	passiveAssets []*PassiveAssetReAnchor
+
+	// anchorPkt is the externally signed anchor transaction packet.
+	anchorPkt *psbt.Packet
+
+	// changeOutputIndex is the index of the change output the anchor
+	// transaction was funded with, or -1 if there is none.
+	changeOutputIndex int32
+
+	// lockedUTXOs are the wallet UTXOs that were locked to fund the
+	// anchor transaction.
+	lockedUTXOs []wire.OutPoint
+
+	// label is the optional human-readable label the transfer is stored
+	// with.
+	label string
+}
+
+// A compile-time assertion to ensure PreAnchoredParcel implements the parcel
+// interface.
+var _ Parcel = (*PreAnchoredParcel)(nil)
+
+// NewPreAnchoredParcel creates a new PreAnchoredParcel.
+func NewPreAnchoredParcel(vPkt *tappsbt.VPacket,
+	inputCommitments tappsbt.InputCommitments,
+	passiveAssets []*PassiveAssetReAnchor, anchorPkt *psbt.Packet,
+	changeOutputIndex int32, lockedUTXOs []wire.OutPoint,
+	label string) *PreAnchoredParcel {
+
+	return &PreAnchoredParcel{
+		parcelKit: &parcelKit{
+			respChan: make(chan *OutboundParcel, 1),
+			errChan:  make(chan error, 1),
+		},
+		vPkt:              vPkt,
+		inputCommitments:  inputCommitments,
+		passiveAssets:     passiveAssets,
+		anchorPkt:         anchorPkt,
+		changeOutputIndex: changeOutputIndex,
+		lockedUTXOs:       lockedUTXOs,
+		label:             label,
+	}
+}
+
+// pkg returns the send package that should be delivered.
+func (p *PreAnchoredParcel) pkg() *sendPackage {
+	log.Infof("New pre-anchored delivery request with %d outputs",
+		len(p.vPkt.Outputs))
+
+	// The anchor transaction was already signed, so it only needs to be
+	// verified and finalized in the anchor sign state.
+	return &sendPackage{
+		Parcel:         p,
+		SendState:      SendStateAnchorSign,
+		VirtualPackets: []*tappsbt.VPacket{p.vPkt},
+		InputCommitments: []tappsbt.InputCommitments{
+			p.inputCommitments,
+		},
+		PassiveAssets: p.passiveAssets,
+		Label:         p.label,
+	}
+}
+
+// kit returns the parcel kit used for delivery.
+func (p *PreAnchoredParcel) kit() *parcelKit {
+	return p.parcelKit
+}
+
+// Validate validates the parcel.
+func (p *PreAnchoredParcel) Validate() error {
+	if p.anchorPkt == nil {
+		return fmt.Errorf("missing anchor transaction packet")
+	}
+
+	if len(p.label) > MaxTransferLabelLen {
+		return fmt.Errorf("transfer label of %d bytes exceeds the "+
+			"maximum of %d bytes", len(p.label),
+			MaxTransferLabelLen)
+	}
+
+	return nil
+}
+
+// NewPassiveAssetReAnchor creates the re-anchor of a passive asset from the
+// signed virtual packet that re-anchors it.
+func NewPassiveAssetReAnchor(
+	vPkt *tappsbt.VPacket) (*PassiveAssetReAnchor, error) {
+
+	if len(vPkt.Inputs) != 1 || len(vPkt.Outputs) != 1 {
+		return nil, fmt.Errorf("passive asset packet must have " +
+			"exactly one input and one output")
+	}
+
+	vIn := vPkt.Inputs[0]
+	if vIn.Asset() == nil {
+		return nil, fmt.Errorf("passive asset packet input is " +
+			"missing its asset")
+	}
+
+	return &PassiveAssetReAnchor{
+		VPacket:         vPkt,
+		GenesisID:       vIn.Asset().ID(),
+		PrevAnchorPoint: vIn.PrevID.OutPoint,
+		AssetVersion:    vIn.Asset().Version,
+		ScriptKey:       vIn.Asset().ScriptKey,
+	}, nil
+}
+
 // sendPackage houses the information we need to complete a package transfer.
 type sendPackage struct {
 	// SendState is the current send state of this parcel.
 	SendState SendState
 
-	// VirtualPacket is the virtual packet that we'll use to construct the
-	// virtual asset transition transaction.
-	VirtualPacket *tappsbt.VPacket
+	// VirtualPackets are the virtual packets that we'll use to construct
+	// the virtual asset transition transactions, one for each asset ID
+	// that is transferred. All of them are anchored in the same anchor
+	// transaction.
+	VirtualPackets []*tappsbt.VPacket
 
-	// OutputIdxToAddr is a map from a VPacket's VOutput index to its
-	// associated Tap address.
-	OutputIdxToAddr tappsbt.OutputIdxToAddr
+	// OutputIdxToAddr holds a map from a VPacket's VOutput index to its
+	// associated Tap address for each of the virtual packets, in the same
+	// order as VirtualPackets.
+	OutputIdxToAddr []tappsbt.OutputIdxToAddr
 
-	// InputCommitments is a map from virtual package input index to its
-	// associated Taproot Asset commitment.
-	InputCommitments tappsbt.InputCommitments
+	// InputCommitments holds a map from virtual package input index to its
+	// associated Taproot Asset commitment for each of the virtual packets,
+	// in the same order as VirtualPackets.
+	InputCommitments []tappsbt.InputCommitments
 
 	// PassiveAssets is the data used in re-anchoring passive assets.
 	PassiveAssets []*PassiveAssetReAnchor
@@ -322,6 +783,33 @@ type sendPackage struct {
 	// TransferTxConfEvent contains transfer transaction on-chain
 	// confirmation data.
 	TransferTxConfEvent *chainntnfs.TxConfirmation
+
+	// Staged indicates that the package should not be broadcast after it
+	// was committed to disk.
+	Staged bool
+
+	// Label is the optional human-readable label the transfer is stored
+	// with.
+	Label string
+
+	// IdempotencyKey is the optional client-supplied key the transfer was
+	// requested with.
+	IdempotencyKey string
+
+	// CoinRelaxations are the coin selection constraints that had to be
+	// relaxed to fund the virtual packet.
+	CoinRelaxations []CoinRelaxation
+}
+
+// anchorTxIDs returns the transaction IDs of the current and of all replaced
+// versions of the package's anchor transaction.
+func (s *sendPackage) anchorTxIDs() fn.Set[chainhash.Hash] {
+	txids := fn.NewSet(s.OutboundPkg.AnchorTx.TxHash())
+	for _, replaced := range s.OutboundPkg.ReplacedAnchorTxs {
+		txids.Add(replaced.FinalTx.TxHash())
+	}
+
+	return txids
 }
 
 // prepareForStorage prepares the send package for storing to the database.
@@ -344,19 +832,55 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		passiveAsset.NewWitnessData = signedAsset.PrevWitnesses
 	}
 
-	vPkt := s.VirtualPacket
 	anchorTXID := s.AnchorTx.FinalTx.TxHash()
 	parcel := &OutboundParcel{
 		AnchorTx:           s.AnchorTx.FinalTx,
 		AnchorTxHeightHint: currentHeight,
 		// TODO(bhandras): use clock.Clock instead.
-		TransferTime:  time.Now(),
-		ChainFees:     s.AnchorTx.ChainFees,
-		Inputs:        make([]TransferInput, len(vPkt.Inputs)),
-		Outputs:       make([]TransferOutput, len(vPkt.Outputs)),
-		PassiveAssets: s.PassiveAssets,
+		TransferTime:   time.Now(),
+		ChainFees:      s.AnchorTx.ChainFees,
+		Staged:         s.Staged,
+		Label:          s.Label,
+		IdempotencyKey: s.IdempotencyKey,
+		AnchorPsbt:     s.AnchorTx.FundedPsbt,
+		FeeBumpAnchor:  s.AnchorTx.FeeBumpAnchor,
+		PassiveAssets:  s.PassiveAssets,
+
+		CoinRelaxations: s.CoinRelaxations,
 	}
 
+	// The inputs and outputs of all virtual packets are stored with the
+	// same transfer, grouped by the packet, and therefore the asset ID,
+	// they belong to.
+	for pktIdx, vPkt := range s.VirtualPackets {
+		inputs, err := s.transferInputs(vPkt)
+		if err != nil {
+			return nil, err
+		}
+		parcel.Inputs = append(parcel.Inputs, inputs...)
+
+		var outputIdxToAddr tappsbt.OutputIdxToAddr
+		if pktIdx < len(s.OutputIdxToAddr) {
+			outputIdxToAddr = s.OutputIdxToAddr[pktIdx]
+		}
+		outputs, err := s.transferOutputs(
+			vPkt, outputIdxToAddr, anchorTXID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		parcel.Outputs = append(parcel.Outputs, outputs...)
+	}
+
+	return parcel, nil
+}
+
+// transferInputs creates the transfer inputs for the inputs of the given
+// virtual packet.
+func (s *sendPackage) transferInputs(
+	vPkt *tappsbt.VPacket) ([]TransferInput, error) {
+
+	inputs := make([]TransferInput, len(vPkt.Inputs))
 	for idx := range vPkt.Inputs {
 		vIn := vPkt.Inputs[idx]
 
@@ -381,7 +905,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 				"outpoint for input %d", idx)
 		}
 
-		parcel.Inputs[idx] = TransferInput{
+		inputs[idx] = TransferInput{
 			PrevID: asset.PrevID{
 				OutPoint: *anchorOutPoint,
 				ID:       vIn.Asset().ID(),
@@ -393,6 +917,17 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		}
 	}
 
+	return inputs, nil
+}
+
+// transferOutputs creates the transfer outputs for the outputs of the given
+// virtual packet. The given map is used to look up the address of each output,
+// if it pays one.
+func (s *sendPackage) transferOutputs(vPkt *tappsbt.VPacket,
+	outputIdxToAddr tappsbt.OutputIdxToAddr,
+	anchorTXID chainhash.Hash) ([]TransferOutput, error) {
+
+	outputs := make([]TransferOutput, len(vPkt.Outputs))
 	outputCommitments := s.AnchorTx.OutputCommitments
 	for idx := range vPkt.Outputs {
 		vOut := vPkt.Outputs[idx]
@@ -400,12 +935,10 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		// Convert any proof courier address associated with this output
 		// to bytes for db storage.
 		var proofCourierAddrBytes []byte
-		if s.OutputIdxToAddr != nil {
-			if addr, ok := s.OutputIdxToAddr[idx]; ok {
-				proofCourierAddrBytes = []byte(
-					addr.ProofCourierAddr.String(),
-				)
-			}
+		if addr, ok := outputIdxToAddr[idx]; ok {
+			proofCourierAddrBytes = []byte(
+				addr.ProofCourierAddr.String(),
+			)
 		}
 
 		anchorInternalKey := keychain.KeyDescriptor{
@@ -440,9 +973,12 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		)
 
 		// If there are passive assets, they are always committed to the
-		// output that is marked as the split root.
+		// output that is marked as the split root of the packet that
+		// spends their previous anchor.
 		if vOut.Type.CanCarryPassive() {
-			numPassiveAssets = uint32(len(s.PassiveAssets))
+			numPassiveAssets = s.numPassiveAssets(
+				vOut.AnchorOutputIndex,
+			)
 		}
 
 		// Either we have an asset that we commit to or we have an
@@ -458,7 +994,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		// In any other case we expect an active asset transfer to be
 		// committed to.
 		case vOut.Asset != nil:
-			proofSuffix, err := s.createProofSuffix(idx)
+			proofSuffix, err := s.createProofSuffix(vPkt, idx)
 			if err != nil {
 				return nil, fmt.Errorf("unable to create "+
 					"proof %d: %w", idx, err)
@@ -477,8 +1013,15 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 				idx)
 		}
 
+		// Outputs that only carry passive assets don't have an asset
+		// of their own, so we use the asset ID of the packet's inputs.
+		assetID := vPkt.Inputs[0].PrevID.ID
+		if vOut.Asset != nil {
+			assetID = vOut.Asset.ID()
+		}
+
 		txOut := s.AnchorTx.FinalTx.TxOut[vOut.AnchorOutputIndex]
-		parcel.Outputs[idx] = TransferOutput{
+		outputs[idx] = TransferOutput{
 			Anchor: Anchor{
 				OutPoint: wire.OutPoint{
 					Hash:  anchorTXID,
@@ -493,6 +1036,7 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 			},
 			Type:                vOut.Type,
 			ScriptKey:           vOut.ScriptKey,
+			AssetID:             assetID,
 			Amount:              vOut.Amount,
 			AssetVersion:        vOut.AssetVersion,
 			WitnessData:         witness,
@@ -502,37 +1046,97 @@ func (s *sendPackage) prepareForStorage(currentHeight uint32) (*OutboundParcel,
 		}
 	}
 
-	return parcel, nil
+	return outputs, nil
+}
+
+// allOutputs returns the virtual outputs of all the package's virtual packets.
+func (s *sendPackage) allOutputs() []*tappsbt.VOutput {
+	var outputs []*tappsbt.VOutput
+	for _, vPkt := range s.VirtualPackets {
+		outputs = append(outputs, vPkt.Outputs...)
+	}
+
+	return outputs
 }
 
-// createProofSuffix creates the new proof for the given output. This is the
-// final state transition that will be added to the proofs of the receiver. The
-// proof returned will have all the Taproot Asset level proof information, but
-// contains dummy data for the on-chain part.
-func (s *sendPackage) createProofSuffix(outIndex int) (*proof.Proof, error) {
-	inputPrevID := s.VirtualPacket.Inputs[0].PrevID
+// isAssetAnchor returns true if any of the virtual outputs of the package is
+// committed to the anchor output with the given index.
+func (s *sendPackage) isAssetAnchor(idx uint32) bool {
+	for _, vOut := range s.allOutputs() {
+		if vOut.AnchorOutputIndex == idx {
+			return true
+		}
+	}
 
-	params, err := proofParams(s.AnchorTx, s.VirtualPacket, outIndex)
+	return false
+}
+
+// numPassiveAssets returns the number of passive assets that are re-anchored
+// in the anchor output with the given index.
+func (s *sendPackage) numPassiveAssets(anchorOutputIndex uint32) uint32 {
+	var numPassive uint32
+	for _, passiveAsset := range s.PassiveAssets {
+		passiveOut := passiveAsset.VPacket.Outputs[0]
+		if passiveOut.AnchorOutputIndex == anchorOutputIndex {
+			numPassive++
+		}
+	}
+
+	return numPassive
+}
+
+// passiveAssetsOutput returns the output that carries the passive assets of
+// the virtual packet that spends the anchor of the given passive input.
+func (s *sendPackage) passiveAssetsOutput(
+	passiveIn *tappsbt.VInput) (*tappsbt.VOutput, error) {
+
+	anchorOutPoint := passiveIn.PrevID.OutPoint
+	for _, vPkt := range s.VirtualPackets {
+		for _, vIn := range vPkt.Inputs {
+			if vIn.PrevID.OutPoint == anchorOutPoint {
+				return vPkt.PassiveAssetsOutput()
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no virtual packet spends anchor %v",
+		anchorOutPoint)
+}
+
+// createProofSuffix creates the new proof for the given output of the given
+// virtual packet. This is the final state transition that will be added to the
+// proofs of the receiver. The proof returned will have all the Taproot Asset
+// level proof information, but contains dummy data for the on-chain part.
+func (s *sendPackage) createProofSuffix(vPkt *tappsbt.VPacket,
+	outIndex int) (*proof.Proof, error) {
+
+	inputPrevID := vPkt.Inputs[0].PrevID
+
+	params, err := proofParams(s.AnchorTx, vPkt, outIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// The anchor outputs of the other virtual packets of the transfer
+	// commit to different assets, so we need an exclusion proof for each
+	// of them as well.
+	err = addOtherOutputExclusionProofs(
+		s.allOutputs(), params.NewAsset, params,
+		s.AnchorTx.OutputCommitments,
+		func(_ int, vOut *tappsbt.VOutput) bool {
+			anchorIdx := uint32(params.OutputIndex)
+			return vOut.AnchorOutputIndex == anchorIdx
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	// We also need to account for any P2TR change outputs.
 	if len(s.AnchorTx.FundedPsbt.Pkt.UnsignedTx.TxOut) > 1 {
-		isAnchor := func(idx uint32) bool {
-			for outIdx := range s.VirtualPacket.Outputs {
-				vOut := s.VirtualPacket.Outputs[outIdx]
-				if vOut.AnchorOutputIndex == idx {
-					return true
-				}
-			}
-
-			return false
-		}
-
 		err := proof.AddExclusionProofs(
 			&params.BaseProofParams, s.AnchorTx.FundedPsbt.Pkt,
-			isAnchor,
+			s.isAssetAnchor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error adding exclusion "+
@@ -741,7 +1345,7 @@ func (s *sendPackage) createReAnchorProof(
 	// normally contains asset change. But it can also be that the split
 	// root output was just created for the passive assets, if there is no
 	// active transfer or no change.
-	passiveCarrierOut, err := s.VirtualPacket.PassiveAssetsOutput()
+	passiveCarrierOut, err := s.passiveAssetsOutput(passiveIn)
 	if err != nil {
 		return nil, fmt.Errorf("anchor output for passive assets not "+
 			"found: %w", err)
@@ -763,7 +1367,7 @@ func (s *sendPackage) createReAnchorProof(
 	// provide an exclusion proof of the passive asset for each of the other
 	// BTC level outputs.
 	err = addOtherOutputExclusionProofs(
-		s.VirtualPacket.Outputs, passiveOut.Asset, passiveParams,
+		s.allOutputs(), passiveOut.Asset, passiveParams,
 		outputCommitments, func(i int, vOut *tappsbt.VOutput) bool {
 			return vOut.AnchorOutputIndex == passiveOutputIndex
 		},
@@ -775,20 +1379,9 @@ func (s *sendPackage) createReAnchorProof(
 	// Add exclusion proof(s) for any P2TR (=BIP-0086, not carrying any
 	// assets) change outputs.
 	if len(s.AnchorTx.FundedPsbt.Pkt.UnsignedTx.TxOut) > 1 {
-		isAnchor := func(idx uint32) bool {
-			for outIdx := range s.VirtualPacket.Outputs {
-				vOut := s.VirtualPacket.Outputs[outIdx]
-				if vOut.AnchorOutputIndex == idx {
-					return true
-				}
-			}
-
-			return false
-		}
-
 		err := proof.AddExclusionProofs(
 			&passiveParams.BaseProofParams,
-			s.AnchorTx.FundedPsbt.Pkt, isAnchor,
+			s.AnchorTx.FundedPsbt.Pkt, s.isAssetAnchor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("error adding exclusion "+
@@ -0,0 +1,409 @@
+package tapfreighter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+)
+
+// ReOrgPolicy describes how a transfer is handled if its confirmed anchor
+// transaction is re-organized out of the best chain.
+type ReOrgPolicy uint8
+
+const (
+	// ReOrgPolicyRebroadcast re-broadcasts the anchor transaction of the
+	// transfer, so it can confirm again in the new best chain.
+	ReOrgPolicyRebroadcast ReOrgPolicy = iota
+
+	// ReOrgPolicyAbandon rolls back the confirmation of the transfer,
+	// cancels it and frees its inputs, so they can be spent again.
+	ReOrgPolicyAbandon
+
+	// ReOrgPolicyQuarantine leases the anchor outputs of the transfer, so
+	// the assets it created can't be spent until they were reviewed and
+	// their leases were released manually.
+	ReOrgPolicyQuarantine
+)
+
+// String returns a human-readable string for the re-org policy.
+func (r ReOrgPolicy) String() string {
+	switch r {
+	case ReOrgPolicyRebroadcast:
+		return "rebroadcast"
+
+	case ReOrgPolicyAbandon:
+		return "abandon"
+
+	case ReOrgPolicyQuarantine:
+		return "quarantine"
+
+	default:
+		return fmt.Sprintf("<unknown_reorg_policy(%d)>", r)
+	}
+}
+
+// ParseReOrgPolicy parses a re-org policy string.
+func ParseReOrgPolicy(policy string) (ReOrgPolicy, error) {
+	switch policy {
+	case "", ReOrgPolicyRebroadcast.String():
+		return ReOrgPolicyRebroadcast, nil
+
+	case ReOrgPolicyAbandon.String():
+		return ReOrgPolicyAbandon, nil
+
+	case ReOrgPolicyQuarantine.String():
+		return ReOrgPolicyQuarantine, nil
+
+	default:
+		return 0, fmt.Errorf("unknown reorg policy: %v", policy)
+	}
+}
+
+// newReOrgWatch creates the re-org watch of the package's confirmed anchor
+// transaction. The inputs of the anchor transaction are only known if the
+// funded anchor PSBT is known, which isn't the case for parcels that were
+// handed off to this node by another one.
+func newReOrgWatch(pkg *sendPackage) *ReOrgWatch {
+	watch := &ReOrgWatch{
+		Parcel:     pkg.OutboundPkg,
+		ConfHeight: uint32(pkg.TransferTxConfEvent.BlockHeight),
+	}
+
+	anchorTx := pkg.AnchorTx
+	if anchorTx == nil || anchorTx.FundedPsbt == nil ||
+		anchorTx.FundedPsbt.Pkt == nil {
+
+		return watch
+	}
+
+	finalTx := pkg.OutboundPkg.AnchorTx
+	pktInputs := anchorTx.FundedPsbt.Pkt.Inputs
+	for idx := range finalTx.TxIn {
+		if idx >= len(pktInputs) || pktInputs[idx].WitnessUtxo == nil {
+			continue
+		}
+
+		watch.Inputs = append(watch.Inputs, AnchorInput{
+			OutPoint: finalTx.TxIn[idx].PreviousOutPoint,
+			PkScript: pktInputs[idx].WitnessUtxo.PkScript,
+		})
+	}
+
+	return watch
+}
+
+// resumeReOrgWatches resumes watching the anchor transactions of the confirmed
+// parcels that weren't buried under the re-org safe depth yet when the daemon
+// was shut down.
+func (p *ChainPorter) resumeReOrgWatches(ctx context.Context) error {
+	if p.cfg.ReOrgSafeDepth == 0 {
+		return nil
+	}
+
+	watches, err := p.cfg.ExportLog.ReOrgWatches(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch re-org watches: %w", err)
+	}
+
+	for _, watch := range watches {
+		log.Infof("Resuming re-org watch of transfer_txid=%v",
+			watch.Parcel.AnchorTx.TxHash())
+
+		p.Wg.Add(1)
+		go p.watchTransferReOrg(watch)
+	}
+
+	return nil
+}
+
+// watchTransferReOrg watches the confirmed anchor transaction of the given
+// parcel until it is buried under the re-org safe depth. If the transaction is
+// re-organized out of the best chain before that, the re-org policy is
+// applied.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *ChainPorter) watchTransferReOrg(watch *ReOrgWatch) {
+	defer p.Wg.Done()
+
+	ctx, cancel := p.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	for {
+		var done bool
+		if watch.AbandonPending {
+			done = p.awaitDoubleSpend(ctx, watch)
+		} else {
+			done = p.awaitReOrgSafeDepth(ctx, watch)
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// awaitReOrgSafeDepth waits until the anchor transaction of the watched parcel
+// is buried under the re-org safe depth or re-organized out of the best chain.
+// True is returned if the parcel no longer needs to be watched.
+func (p *ChainPorter) awaitReOrgSafeDepth(ctx context.Context,
+	watch *ReOrgWatch) bool {
+
+	anchorTx := watch.Parcel.AnchorTx
+	txHash := anchorTx.TxHash()
+	reOrgChan := make(chan struct{}, 1)
+	confNtfn, errChan, err := p.cfg.ChainBridge.RegisterConfirmationsNtfn(
+		ctx, &txHash, anchorTx.TxOut[0].PkScript, p.cfg.ReOrgSafeDepth,
+		watch.ConfHeight, false, reOrgChan,
+	)
+	if err != nil {
+		log.Errorf("Unable to watch transfer_txid=%v for re-orgs: %v",
+			txHash, err)
+		return true
+	}
+	defer confNtfn.Cancel()
+
+	for {
+		select {
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok {
+				return true
+			}
+
+			log.Debugf("Anchor tx of transfer_txid=%v reached "+
+				"safe depth", txHash)
+
+			err := p.cfg.ExportLog.RemoveReOrgWatch(ctx, txHash)
+			if err != nil {
+				log.Errorf("Unable to remove re-org watch of "+
+					"transfer_txid=%v: %v", txHash, err)
+			}
+			return true
+
+		case <-reOrgChan:
+			policy := p.cfg.ReOrgPolicy
+			abandon := policy == ReOrgPolicyAbandon
+
+			// A re-organized transfer is only abandoned once one
+			// of its inputs is spent by another transaction, as
+			// the anchor transaction can still confirm again
+			// otherwise.
+			if abandon && len(watch.Inputs) > 0 {
+				p.markAbandonPending(ctx, watch)
+				return false
+			}
+
+			// Without the inputs of the anchor transaction, we
+			// can't detect a double spend, so we quarantine the
+			// transfer instead.
+			if abandon {
+				log.Warnf("Inputs of transfer_txid=%v "+
+					"unknown, quarantining it instead of "+
+					"abandoning it", txHash)
+
+				policy = ReOrgPolicyQuarantine
+			}
+
+			if p.handleTransferReOrg(ctx, watch.Parcel, policy) {
+				return true
+			}
+
+		case err := <-errChan:
+			if !fn.IsCanceled(err) {
+				log.Errorf("Error while watching "+
+					"transfer_txid=%v for re-orgs: %v",
+					txHash, err)
+			}
+			return true
+
+		case <-p.Quit:
+			return true
+		}
+	}
+}
+
+// markAbandonPending persists that the watched parcel is abandoned once one of
+// the inputs of its re-organized anchor transaction is double spent, so the
+// pending abandon is resumed after a restart.
+func (p *ChainPorter) markAbandonPending(ctx context.Context,
+	watch *ReOrgWatch) {
+
+	txHash := watch.Parcel.AnchorTx.TxHash()
+	log.Warnf("Anchor tx of transfer_txid=%v was re-organized out of "+
+		"the best chain, abandoning transfer once one of its inputs "+
+		"is double spent", txHash)
+
+	watch.AbandonPending = true
+	err := p.cfg.ExportLog.UpdateReOrgWatch(
+		ctx, txHash, watch.ConfHeight, true,
+	)
+	if err != nil {
+		log.Errorf("Unable to persist pending abandon of "+
+			"transfer_txid=%v: %v", txHash, err)
+	}
+}
+
+// awaitDoubleSpend waits until one of the inputs of the re-organized anchor
+// transaction of the watched parcel is spent. If the anchor transaction itself
+// confirmed again, the parcel is watched for re-orgs again. If the input was
+// double spent, the parcel is abandoned. True is returned if the parcel no
+// longer needs to be watched.
+func (p *ChainPorter) awaitDoubleSpend(ctx context.Context,
+	watch *ReOrgWatch) bool {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	txHash := watch.Parcel.AnchorTx.TxHash()
+	heightHint := watch.Parcel.AnchorTxHeightHint
+	spendChan := make(chan *chainntnfs.SpendDetail, len(watch.Inputs))
+	for _, input := range watch.Inputs {
+		outpoint := input.OutPoint
+		spendEvent, errChan, err := p.cfg.ChainBridge.RegisterSpendNtfn(
+			ctx, &outpoint, input.PkScript, heightHint,
+		)
+		if err != nil {
+			log.Errorf("Unable to watch input %v of "+
+				"transfer_txid=%v: %v", outpoint, txHash, err)
+			return true
+		}
+
+		go func() {
+			defer spendEvent.Cancel()
+
+			select {
+			case spend, ok := <-spendEvent.Spend:
+				if ok {
+					spendChan <- spend
+				}
+
+			case err := <-errChan:
+				if !fn.IsCanceled(err) {
+					log.Warnf("Error watching input %v "+
+						"of transfer_txid=%v: %v",
+						outpoint, txHash, err)
+				}
+
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	select {
+	case spend := <-spendChan:
+		if *spend.SpenderTxHash == txHash {
+			log.Infof("Anchor tx of transfer_txid=%v confirmed "+
+				"again at height %d", txHash,
+				spend.SpendingHeight)
+
+			watch.AbandonPending = false
+			watch.ConfHeight = uint32(spend.SpendingHeight)
+			err := p.cfg.ExportLog.UpdateReOrgWatch(
+				ctx, txHash, watch.ConfHeight, false,
+			)
+			if err != nil {
+				log.Errorf("Unable to update re-org watch of "+
+					"transfer_txid=%v: %v", txHash, err)
+			}
+
+			return false
+		}
+
+		log.Warnf("Anchor input %v of re-organized transfer_txid=%v "+
+			"was double spent by txid=%v", spend.SpentOutPoint,
+			txHash, spend.SpenderTxHash)
+
+		p.handleTransferReOrg(ctx, watch.Parcel, ReOrgPolicyAbandon)
+		return true
+
+	case <-p.Quit:
+		return true
+	}
+}
+
+// handleTransferReOrg applies the given re-org policy to the given parcel,
+// whose anchor transaction was re-organized out of the best chain. True is
+// returned if the parcel no longer needs to be watched.
+func (p *ChainPorter) handleTransferReOrg(ctx context.Context,
+	parcel *OutboundParcel, policy ReOrgPolicy) bool {
+
+	txHash := parcel.AnchorTx.TxHash()
+
+	log.Warnf("Applying reorg policy %v to re-organized "+
+		"transfer_txid=%v", policy, txHash)
+
+	var err error
+	switch policy {
+	case ReOrgPolicyAbandon:
+		err = p.cfg.ExportLog.AbandonParcel(ctx, txHash)
+
+		// A transfer that re-anchored passive assets can't be rolled
+		// back, so we quarantine it instead.
+		if errors.Is(err, ErrTransferRollbackUnsupported) {
+			log.Warnf("Unable to abandon transfer_txid=%v, "+
+				"quarantining it instead: %v", txHash, err)
+
+			policy = ReOrgPolicyQuarantine
+			err = p.cfg.ExportLog.QuarantineParcel(ctx, txHash)
+			break
+		}
+		if err == nil {
+			p.releaseWalletInputs(ctx, parcel)
+		}
+
+	case ReOrgPolicyQuarantine:
+		err = p.cfg.ExportLog.QuarantineParcel(ctx, txHash)
+
+	default:
+		err = p.cfg.ChainBridge.PublishTransaction(ctx, parcel.AnchorTx)
+	}
+	if err != nil {
+		log.Errorf("Unable to apply reorg policy %v to "+
+			"transfer_txid=%v: %v", policy, txHash, err)
+	}
+
+	p.publishSubscriberEvent(NewTransferReOrgEvent(txHash, policy, err))
+
+	// A re-broadcast transaction can be re-organized out again, so we
+	// continue to watch it.
+	return policy != ReOrgPolicyRebroadcast
+}
+
+// TransferReOrgEvent is an event which is sent to the ChainPorter's event
+// subscribers when the confirmed anchor transaction of a transfer was
+// re-organized out of the best chain and the re-org policy was applied.
+type TransferReOrgEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// AnchorTXID is the ID of the re-organized anchor transaction.
+	AnchorTXID chainhash.Hash
+
+	// Policy is the re-org policy that was applied to the transfer.
+	Policy ReOrgPolicy
+
+	// Err is the error that prevented the policy from being applied, if
+	// any.
+	Err error
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *TransferReOrgEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewTransferReOrgEvent creates a new TransferReOrgEvent.
+func NewTransferReOrgEvent(anchorTXID chainhash.Hash, policy ReOrgPolicy,
+	err error) *TransferReOrgEvent {
+
+	return &TransferReOrgEvent{
+		timestamp:  time.Now().UTC(),
+		AnchorTXID: anchorTXID,
+		Policy:     policy,
+		Err:        err,
+	}
+}
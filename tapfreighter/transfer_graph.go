@@ -0,0 +1,106 @@
+package tapfreighter
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+)
+
+// TransferEdge describes the flow of an amount of an asset from an input of a
+// transfer to one of its outputs.
+type TransferEdge struct {
+	// InputIndex is the index of the input within the transfer.
+	InputIndex int
+
+	// OutputIndex is the index of the output within the transfer.
+	OutputIndex int
+
+	// Amount is the amount of the input that went into the output. This is
+	// zero for outputs that don't carry any value, like tombstones.
+	Amount uint64
+}
+
+// TransferGraph returns the edges between the inputs of the given transfer and
+// the outputs they contributed to. An input can only contribute to outputs of
+// the same asset ID. Because the units of a fungible asset are
+// interchangeable, the amounts are attributed in order: the inputs of an asset
+// ID fill its outputs one after the other, in the order both appear in the
+// transfer. Outputs that don't carry any value are linked to every input of
+// their asset ID with a zero amount. Outputs that only carry passive assets
+// aren't part of the graph.
+func TransferGraph(parcel *OutboundParcel) ([]TransferEdge, error) {
+	outputIDs := make([]*asset.ID, len(parcel.Outputs))
+	for idx := range parcel.Outputs {
+		out := parcel.Outputs[idx]
+		if out.Type == tappsbt.TypePassiveAssetsOnly {
+			continue
+		}
+
+		var proofSuffix proof.Proof
+		err := proofSuffix.Decode(bytes.NewReader(out.ProofSuffix))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding proof suffix "+
+				"%d: %w", idx, err)
+		}
+
+		id := proofSuffix.Asset.ID()
+		outputIDs[idx] = &id
+	}
+
+	return transferEdges(parcel.Inputs, parcel.Outputs, outputIDs), nil
+}
+
+// transferEdges attributes the amounts of the given inputs to the outputs of
+// the same asset ID, as described in TransferGraph. The asset ID of each
+// output is given by outputIDs, a nil ID excludes the output from the graph.
+func transferEdges(inputs []TransferInput, outputs []TransferOutput,
+	outputIDs []*asset.ID) []TransferEdge {
+
+	// We track the amount that's left to attribute for each input, so an
+	// input can be split over multiple outputs.
+	remaining := make([]uint64, len(inputs))
+	for idx := range inputs {
+		remaining[idx] = inputs[idx].Amount
+	}
+
+	var edges []TransferEdge
+	for outIdx := range outputs {
+		id := outputIDs[outIdx]
+		if id == nil {
+			continue
+		}
+
+		amount := outputs[outIdx].Amount
+		for inIdx := range inputs {
+			if inputs[inIdx].ID != *id {
+				continue
+			}
+
+			switch {
+			// Outputs without a value were still created by
+			// spending all inputs of their asset ID.
+			case amount == 0 && outputs[outIdx].Amount == 0:
+				edges = append(edges, TransferEdge{
+					InputIndex:  inIdx,
+					OutputIndex: outIdx,
+				})
+
+			case amount > 0 && remaining[inIdx] > 0:
+				contribution := min(amount, remaining[inIdx])
+				edges = append(edges, TransferEdge{
+					InputIndex:  inIdx,
+					OutputIndex: outIdx,
+					Amount:      contribution,
+				})
+
+				amount -= contribution
+				remaining[inIdx] -= contribution
+			}
+		}
+	}
+
+	return edges
+}
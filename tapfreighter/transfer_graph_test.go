@@ -0,0 +1,148 @@
+package tapfreighter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransferEdges tests that the amounts of the inputs of a transfer are
+// attributed to the outputs of the same asset ID.
+func TestTransferEdges(t *testing.T) {
+	t.Parallel()
+
+	id1 := asset.RandID(t)
+	id2 := asset.RandID(t)
+
+	input := func(id asset.ID, amount uint64) TransferInput {
+		return TransferInput{
+			PrevID: asset.PrevID{ID: id},
+			Amount: amount,
+		}
+	}
+	output := func(amount uint64) TransferOutput {
+		return TransferOutput{Amount: amount}
+	}
+
+	testCases := []struct {
+		name      string
+		inputs    []TransferInput
+		outputs   []TransferOutput
+		outputIDs []*asset.ID
+		edges     []TransferEdge
+	}{{
+		name:      "split of single input",
+		inputs:    []TransferInput{input(id1, 100)},
+		outputs:   []TransferOutput{output(60), output(40)},
+		outputIDs: []*asset.ID{&id1, &id1},
+		edges: []TransferEdge{
+			{InputIndex: 0, OutputIndex: 0, Amount: 60},
+			{InputIndex: 0, OutputIndex: 1, Amount: 40},
+		},
+	}, {
+		name:      "merge of two inputs",
+		inputs:    []TransferInput{input(id1, 30), input(id1, 70)},
+		outputs:   []TransferOutput{output(100)},
+		outputIDs: []*asset.ID{&id1},
+		edges: []TransferEdge{
+			{InputIndex: 0, OutputIndex: 0, Amount: 30},
+			{InputIndex: 1, OutputIndex: 0, Amount: 70},
+		},
+	}, {
+		name:      "inputs split over outputs",
+		inputs:    []TransferInput{input(id1, 50), input(id1, 50)},
+		outputs:   []TransferOutput{output(20), output(80)},
+		outputIDs: []*asset.ID{&id1, &id1},
+		edges: []TransferEdge{
+			{InputIndex: 0, OutputIndex: 0, Amount: 20},
+			{InputIndex: 0, OutputIndex: 1, Amount: 30},
+			{InputIndex: 1, OutputIndex: 1, Amount: 50},
+		},
+	}, {
+		name:    "tombstone and passive output",
+		inputs:  []TransferInput{input(id1, 10), input(id2, 5)},
+		outputs: []TransferOutput{output(0), output(10), output(5)},
+		outputIDs: []*asset.ID{
+			&id1, &id1, nil,
+		},
+		edges: []TransferEdge{
+			{InputIndex: 0, OutputIndex: 0},
+			{InputIndex: 0, OutputIndex: 1, Amount: 10},
+		},
+	}, {
+		name:      "multiple asset IDs",
+		inputs:    []TransferInput{input(id1, 10), input(id2, 5)},
+		outputs:   []TransferOutput{output(5), output(10)},
+		outputIDs: []*asset.ID{&id2, &id1},
+		edges: []TransferEdge{
+			{InputIndex: 1, OutputIndex: 0, Amount: 5},
+			{InputIndex: 0, OutputIndex: 1, Amount: 10},
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			edges := transferEdges(
+				tc.inputs, tc.outputs, tc.outputIDs,
+			)
+			require.Equal(t, tc.edges, edges)
+		})
+	}
+}
+
+// TestTransferGraph tests that the asset IDs of the outputs of a transfer are
+// taken from their proof suffix.
+func TestTransferGraph(t *testing.T) {
+	t.Parallel()
+
+	a := asset.RandAsset(t, asset.Normal)
+	a.Amount = 100
+
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{})
+	anchorTx.AddTxOut(&wire.TxOut{})
+
+	var proofSuffix bytes.Buffer
+	p := proof.Proof{
+		AnchorTx: *anchorTx,
+		Asset:    *a,
+		InclusionProof: proof.TaprootProof{
+			InternalKey: test.RandPubKey(t),
+		},
+	}
+	require.NoError(t, p.Encode(&proofSuffix))
+
+	parcel := &OutboundParcel{
+		Inputs: []TransferInput{{
+			PrevID: asset.PrevID{ID: a.ID()},
+			Amount: 100,
+		}},
+		Outputs: []TransferOutput{{
+			Type: tappsbt.TypePassiveAssetsOnly,
+		}, {
+			Amount:      100,
+			ProofSuffix: proofSuffix.Bytes(),
+		}},
+	}
+
+	edges, err := TransferGraph(parcel)
+	require.NoError(t, err)
+	require.Equal(t, []TransferEdge{
+		{InputIndex: 0, OutputIndex: 1, Amount: 100},
+	}, edges)
+
+	// A proof suffix that can't be decoded is reported.
+	parcel.Outputs[1].ProofSuffix = []byte{0x01}
+	_, err = TransferGraph(parcel)
+	require.ErrorContains(t, err, "error decoding proof suffix 1")
+}
@@ -5,7 +5,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
 
@@ -44,6 +47,12 @@ const (
 	// and avoids the same UTXO being used in another transaction if the
 	// confirmation of the first transaction takes a long time.
 	defaultBroadcastCoinLeaseDuration = 365 * 24 * time.Hour
+
+	// FeeBumpAnchorValue is the value in sats of the output that is
+	// reserved for bumping the fee of an anchor transaction with a child
+	// transaction. Like the anchor outputs of lnd's anchor channels, it is
+	// just large enough to not be considered dust.
+	FeeBumpAnchorValue = 330
 )
 
 var (
@@ -60,6 +69,19 @@ var (
 		0x4f, 0xb7, 0x4e, 0xc2, 0xad, 0x6e, 0x11, 0xd7,
 	}
 
+	// QuarantineLeaseIdentifier is the binary representation of the
+	// SHA256 hash of the string "tapd-reorg-quarantine-lock-id" and is
+	// used for the UTXO leases of transfers that were quarantined after
+	// their anchor transaction was re-organized out of the chain. The ID
+	// corresponds to the hex value of
+	// 83dde285836b70e009be3bbaf90c0d82acaf7b599d663ea7e5248ce7bca4689d.
+	QuarantineLeaseIdentifier = [32]byte{
+		0x83, 0xdd, 0xe2, 0x85, 0x83, 0x6b, 0x70, 0xe0,
+		0x09, 0xbe, 0x3b, 0xba, 0xf9, 0x0c, 0x0d, 0x82,
+		0xac, 0xaf, 0x7b, 0x59, 0x9d, 0x66, 0x3e, 0xa7,
+		0xe5, 0x24, 0x8c, 0xe7, 0xbc, 0xa4, 0x68, 0x9d,
+	}
+
 	// ErrFullBurnNotSupported is returned when we attempt to burn all
 	// assets of an anchor output, which is not supported.
 	ErrFullBurnNotSupported = errors.New("burning all assets of an " +
@@ -90,6 +112,27 @@ type AnchorTransaction struct {
 	// Taproot Asset tree of all the virtual asset transfer transactions
 	// that are within a single BTC level anchor output.
 	OutputCommitments map[uint32]*commitment.TapCommitment
+
+	// FeeBumpAnchor is the output reserved for bumping the fee of the
+	// anchor TX with a child transaction (CPFP). This is nil if no such
+	// output was reserved.
+	FeeBumpAnchor *FeeBumpAnchor
+}
+
+// FeeBumpAnchor is a wallet owned BIP-0086 output of an anchor transaction
+// that doesn't carry any assets and is reserved for bumping the fee of the
+// anchor transaction with a child transaction (CPFP). Because it is created
+// independently of the asset outputs and the change, there always is an
+// output we can spend to accelerate the transfer.
+type FeeBumpAnchor struct {
+	// OutputIndex is the index of the output within the anchor TX.
+	OutputIndex uint32
+
+	// InternalKey is the BIP-0086 internal key of the output.
+	InternalKey keychain.KeyDescriptor
+
+	// PkScript is the pk script of the output.
+	PkScript []byte
 }
 
 // Wallet is an interface for funding and signing asset transfers.
@@ -98,21 +141,43 @@ type Wallet interface {
 	// spend in order to pay the given address. It also returns supporting
 	// data which assists in processing the virtual transaction: passive
 	// asset re-anchors and the Taproot Asset level commitment of the
-	// selected assets.
-	FundAddressSend(ctx context.Context,
-		receiverAddrs ...*address.Tap) (*FundedVPacket,
-		tappsbt.OutputIdxToAddr, error)
+	// selected assets. If maxInputs is non-zero, it overrides the
+	// configured maximum number of inputs. If inputs is non-empty, only
+	// the assets anchored at these outpoints may be selected.
+	FundAddressSend(ctx context.Context, maxInputs uint32,
+		inputs []wire.OutPoint, receiverAddrs ...*address.Tap) (
+		*FundedVPacket, tappsbt.OutputIdxToAddr, error)
+
+	// FundMultiAssetSend funds one virtual transaction for each distinct
+	// asset ID of the given addresses, so that multiple assets can be
+	// paid out with a single anchor transaction. The anchor output
+	// indexes of the returned packets don't overlap. The address maps are
+	// returned in the same order as the packets.
+	FundMultiAssetSend(ctx context.Context, maxInputs uint32,
+		inputs []wire.OutPoint, receiverAddrs ...*address.Tap) (
+		[]*FundedVPacket, []tappsbt.OutputIdxToAddr, error)
+
+	// EstimateAddressSend previews a send to the given addresses. It
+	// selects and funds the assets to spend like FundMultiAssetSend, but
+	// doesn't lease them or derive any keys from the wallet, and estimates
+	// the chain fee of the anchor transaction at the given fee rate
+	// without funding or signing it.
+	EstimateAddressSend(ctx context.Context, maxInputs uint32,
+		inputs []wire.OutPoint, feeRate chainfee.SatPerKWeight,
+		feeBumpAnchor bool, receiverAddrs ...*address.Tap) (
+		*SendEstimate, error)
 
 	// FundPacket funds a virtual transaction, selecting assets to spend
 	// in order to pay the given recipient. The selected input is then added
 	// to the given virtual transaction.
 	FundPacket(ctx context.Context, fundDesc *tapscript.FundingDescriptor,
-		vPkt *tappsbt.VPacket) (*FundedVPacket, error)
+		vPkt *tappsbt.VPacket,
+		optFuncs ...FundingOption) (*FundedVPacket, error)
 
 	// FundBurn funds a virtual transaction for burning the given amount of
 	// units of the given asset.
-	FundBurn(ctx context.Context,
-		fundDesc *tapscript.FundingDescriptor) (*FundedVPacket, error)
+	FundBurn(ctx context.Context, fundDesc *tapscript.FundingDescriptor,
+		optFuncs ...FundingOption) (*FundedVPacket, error)
 
 	// SignVirtualPacket signs the virtual transaction of the given packet
 	// and returns the input indexes that were signed.
@@ -137,10 +202,41 @@ type Wallet interface {
 	AnchorVirtualTransactions(ctx context.Context,
 		params *AnchorVTxnsParams) (*AnchorTransaction, error)
 
+	// CommitVirtualTransactions creates and funds a BTC level anchor
+	// transaction that anchors all the virtual transactions of the given
+	// packets, but doesn't sign it. This allows the anchor TX to be signed
+	// externally.
+	CommitVirtualTransactions(ctx context.Context,
+		params *AnchorVTxnsParams) (*AnchorTransaction, error)
+
+	// CompleteAnchorTx verifies that the given externally signed anchor
+	// packet commits to the virtual transactions of the given packets and
+	// finalizes it. The change output index is -1 if the packet doesn't
+	// have a change output.
+	CompleteAnchorTx(params *AnchorVTxnsParams, signedPkt *psbt.Packet,
+		changeOutputIndex int32,
+		lockedUTXOs []wire.OutPoint) (*AnchorTransaction, error)
+
+	// BumpAnchorTxFee creates a replacement for the given anchor TX that
+	// pays the given fee rate. The additional fee is deducted from the
+	// wallet's change output, all inputs and other outputs are kept.
+	BumpAnchorTxFee(ctx context.Context, anchorTx *AnchorTransaction,
+		feeRate chainfee.SatPerKWeight) (*AnchorTransaction, error)
+
+	// CreateFeeBumpChild creates and signs a child transaction that spends
+	// the given fee bump anchor output of the given unconfirmed anchor TX,
+	// which pays the given amount of chain fees. The child pays enough
+	// fees for the package to reach the given fee rate (CPFP).
+	CreateFeeBumpChild(ctx context.Context, anchorTx *wire.MsgTx,
+		chainFees int64, feeBumpAnchor *FeeBumpAnchor,
+		feeRate chainfee.SatPerKWeight) (*wire.MsgTx, error)
+
 	// SignOwnershipProof creates and signs an ownership proof for the given
 	// owned asset. The ownership proof consists of a valid witness of a
 	// signed virtual packet that spends the asset fully to the NUMS key.
-	SignOwnershipProof(ownedAsset *asset.Asset) (wire.TxWitness, error)
+	// If a challenge is given, the witness commits to it.
+	SignOwnershipProof(ownedAsset *asset.Asset,
+		challenge *[32]byte) (wire.TxWitness, error)
 }
 
 // AddrBook is an interface that provides access to the address book.
@@ -151,6 +247,11 @@ type AddrBook interface {
 	FetchScriptKey(ctx context.Context,
 		tweakedScriptKey *btcec.PublicKey) (*asset.TweakedScriptKey,
 		error)
+
+	// FetchTapscriptLeaves fetches the leaves of the tapscript tree with
+	// the given root hash. No leaves are returned if the tree isn't known.
+	FetchTapscriptLeaves(ctx context.Context,
+		rootHash []byte) ([]txscript.TapLeaf, error)
 }
 
 // AnchorVTxnsParams holds all the parameters needed to create a BTC level
@@ -164,19 +265,33 @@ type AnchorVTxnsParams struct {
 	// anchored by the anchor transaction.
 	VPkts []*tappsbt.VPacket
 
-	// InputCommitments is a map from virtual package input index to its
-	// associated Taproot Assets commitment.
-	InputCommitments tappsbt.InputCommitments
+	// InputCommitments holds the input commitments of each of the virtual
+	// transactions, in the same order as VPkts. Each entry is a map from
+	// the virtual package input index to its associated Taproot Assets
+	// commitment.
+	InputCommitments []tappsbt.InputCommitments
 
 	// PassiveAssetsVPkts is a list of all the virtual transactions which
 	// re-anchor passive assets.
 	PassiveAssetsVPkts []*tappsbt.VPacket
+
+	// FeeBumpAnchor indicates that an additional output should be reserved
+	// in the anchor transaction for bumping its fee with a child
+	// transaction.
+	FeeBumpAnchor bool
 }
 
-// NewCoinSelect creates a new CoinSelect.
-func NewCoinSelect(coinLister CoinLister) *CoinSelect {
+// NewCoinSelect creates a new CoinSelect. The chain bridge is only used to
+// look up the current height if the confirmation policy requires any
+// confirmations. If the relaxation policy is nil, coin selection is strict.
+func NewCoinSelect(coinLister CoinLister, confPolicy *ConfirmationPolicy,
+	relaxPolicy *CoinRelaxationPolicy, chainBridge ChainBridge) *CoinSelect {
+
 	return &CoinSelect{
-		coinLister: coinLister,
+		coinLister:  coinLister,
+		confPolicy:  confPolicy,
+		relaxPolicy: relaxPolicy,
+		chainBridge: chainBridge,
 	}
 }
 
@@ -185,6 +300,17 @@ func NewCoinSelect(coinLister CoinLister) *CoinSelect {
 type CoinSelect struct {
 	coinLister CoinLister
 
+	// confPolicy is the number of confirmations an asset needs before it
+	// can be selected.
+	confPolicy *ConfirmationPolicy
+
+	// relaxPolicy describes how the constraints are relaxed if they can't
+	// be satisfied. If this is nil, coin selection is strict.
+	relaxPolicy *CoinRelaxationPolicy
+
+	// chainBridge is used to look up the current height of the chain.
+	chainBridge ChainBridge
+
 	// coinLock is a read/write mutex that is used to ensure that only one
 	// goroutine is attempting to call any coin selection related methods at
 	// any time. This is necessary as some of the calls to the store (e.g.
@@ -195,42 +321,109 @@ type CoinSelect struct {
 }
 
 // SelectCoins returns a set of not yet leased coins that satisfy the given
-// constraints and strategy. The coins returned are leased for the default lease
-// duration.
+// constraints and strategy. Unless the constraints say otherwise, the coins
+// returned are leased for the default lease duration. If the constraints can
+// only be satisfied by relaxing them according to the relaxation policy, the
+// relaxations that were applied are returned as well.
 func (s *CoinSelect) SelectCoins(ctx context.Context,
 	constraints CommitmentConstraints,
-	strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment, error) {
+	strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment,
+	[]CoinRelaxation, error) {
 
 	s.coinLock.Lock()
 	defer s.coinLock.Unlock()
 
 	// Before we select any coins, let's do some cleanup of expired leases.
 	if err := s.coinLister.DeleteExpiredLeases(ctx); err != nil {
-		return nil, fmt.Errorf("unable to delete expired leases: %w",
-			err)
+		return nil, nil, fmt.Errorf("unable to delete expired "+
+			"leases: %w", err)
 	}
 
 	listConstraints := CommitmentConstraints{
-		GroupKey: constraints.GroupKey,
-		AssetID:  constraints.AssetID,
-		MinAmt:   1,
+		GroupKey:        constraints.GroupKey,
+		AssetID:         constraints.AssetID,
+		MinAmt:          1,
+		SharedOutpoints: constraints.SharedOutpoints,
 	}
-	eligibleCommitments, err := s.coinLister.ListEligibleCoins(
+	listedCommitments, err := s.coinLister.ListEligibleCoins(
 		ctx, listConstraints,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to list eligible coins: %w", err)
+		return nil, nil, fmt.Errorf("unable to list eligible coins: %w",
+			err)
+	}
+
+	// If the caller chose the inputs to spend, only those may be
+	// selected.
+	if len(constraints.Outpoints) > 0 {
+		listedCommitments, err = filterOutpoints(
+			listedCommitments, constraints,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Assets that don't have the required number of confirmations yet
+	// can't be selected.
+	eligibleCommitments, shallowCommitments, err := s.filterConfirmed(
+		ctx, listedCommitments, nil,
+	)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	log.Infof("Identified %v eligible asset inputs for send of %d to %x",
-		len(eligibleCommitments), constraints.MinAmt,
-		constraints.AssetID[:])
+	log.Infof("Identified %v eligible asset inputs for send of %d of %v "+
+		"(%d more lack confirmations)", len(eligibleCommitments),
+		constraints.MinAmt, constraints.assetDesc(),
+		len(shallowCommitments))
 
 	selectedCoins, err := s.selectForAmount(
 		constraints.MinAmt, eligibleCommitments, strategy,
+		constraints.Tranche, constraints.MaxInputs,
 	)
+
+	// If the amount can't be satisfied, we check whether it could with
+	// the assets that lack confirmations, so we can tell the user to wait
+	// instead of reporting a missing balance.
+	if errors.Is(err, ErrMatchingAssetsNotFound) &&
+		len(shallowCommitments) > 0 {
+
+		allCommitments := append(
+			eligibleCommitments, shallowCommitments...,
+		)
+		_, allErr := s.selectForAmount(
+			constraints.MinAmt, allCommitments, strategy,
+			constraints.Tranche, constraints.MaxInputs,
+		)
+		if allErr == nil {
+			err = fmt.Errorf("%w: %d inputs of %v lack "+
+				"confirmations", ErrInsufficientConfirmations,
+				len(shallowCommitments),
+				constraints.assetDesc())
+		}
+	}
+
+	// If the strict constraints can't be satisfied, we try to relax them
+	// if we're allowed to.
+	var relaxations []CoinRelaxation
+	if err != nil && s.relaxPolicy != nil && isRelaxableErr(err) {
+		var relaxedErr error
+		selectedCoins, relaxations, relaxedErr = s.selectRelaxed(
+			ctx, constraints, strategy, listedCommitments,
+		)
+		if relaxedErr == nil {
+			err = nil
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("unable to select coins: %w", err)
+		return nil, nil, fmt.Errorf("unable to select coins: %w", err)
+	}
+
+	// A selection that only previews a transfer leaves the coins
+	// available to other processes.
+	if constraints.NoLease {
+		return selectedCoins, relaxations, nil
 	}
 
 	// We now need to lock/lease/reserve those selected coins so
@@ -245,10 +438,184 @@ func (s *CoinSelect) SelectCoins(ctx context.Context,
 		ctx, defaultWalletLeaseIdentifier, expiry, coinOutPoints...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to lease coin: %w", err)
+		return nil, nil, fmt.Errorf("unable to lease coin: %w", err)
+	}
+
+	return selectedCoins, relaxations, nil
+}
+
+// filterOutpoints returns the given commitments that are anchored at one of the
+// outpoints of the constraints. An error is returned if any of the outpoints
+// doesn't anchor one of the commitments, which means it doesn't hold an
+// unleased coin of the asset described by the constraints.
+func filterOutpoints(commitments []*AnchoredCommitment,
+	constraints CommitmentConstraints) ([]*AnchoredCommitment, error) {
+
+	allowed := fn.NewSet(constraints.Outpoints...)
+	found := fn.NewSet[wire.OutPoint]()
+
+	var filtered []*AnchoredCommitment
+	for _, c := range commitments {
+		if !allowed.Contains(c.AnchorPoint) {
+			continue
+		}
+
+		found.Add(c.AnchorPoint)
+		filtered = append(filtered, c)
+	}
+
+	for _, op := range constraints.Outpoints {
+		if !found.Contains(op) {
+			return nil, fmt.Errorf("%w: outpoint %v, %v",
+				ErrIneligibleInput, op, constraints.assetDesc())
+		}
+	}
+
+	return filtered, nil
+}
+
+// isRelaxableErr returns true if the given coin selection error might be
+// resolved by relaxing the constraints.
+func isRelaxableErr(err error) bool {
+	return errors.Is(err, ErrMatchingAssetsNotFound) ||
+		errors.Is(err, ErrInsufficientConfirmations) ||
+		errors.Is(err, ErrMaxInputsExceeded)
+}
+
+// selectRelaxed applies the steps of the relaxation policy one after the other
+// and returns the first selection that satisfies the relaxed constraints,
+// together with the relaxations that were needed for it.
+func (s *CoinSelect) selectRelaxed(ctx context.Context,
+	constraints CommitmentConstraints,
+	strategy MultiCommitmentSelectStrategy,
+	listedCommitments []*AnchoredCommitment) ([]*AnchoredCommitment,
+	[]CoinRelaxation, error) {
+
+	var (
+		relaxed = relaxedConstraints{
+			maxInputs: constraints.MaxInputs,
+		}
+		relaxations []CoinRelaxation
+		lastErr     error
+	)
+	for _, step := range s.relaxPolicy.Steps {
+		var relaxation CoinRelaxation
+		switch step {
+		case RelaxConfirmations:
+			var required uint32
+			for _, c := range listedCommitments {
+				confs := s.confPolicy.RequiredConfs(c.Asset)
+				if confs > required {
+					required = confs
+				}
+			}
+
+			// Nothing to relax if no asset requires more than
+			// the relaxed number of confirmations.
+			minConfs := s.relaxPolicy.MinConfs
+			if required <= minConfs {
+				continue
+			}
+
+			relaxed.minConfs = &minConfs
+			relaxation = CoinRelaxation{
+				Step:      step,
+				Required:  required,
+				RelaxedTo: minConfs,
+			}
+
+		case RelaxMaxInputs:
+			// Nothing to relax if there is no limit, the limit was
+			// requested explicitly, or the relaxed limit isn't
+			// higher.
+			maxInputs := s.relaxPolicy.MaxInputs
+			tooLow := maxInputs != 0 &&
+				maxInputs <= constraints.MaxInputs
+			if constraints.MaxInputs == 0 ||
+				constraints.StrictMaxInputs || tooLow {
+
+				continue
+			}
+
+			relaxed.maxInputs = maxInputs
+			relaxation = CoinRelaxation{
+				Step:      step,
+				Required:  constraints.MaxInputs,
+				RelaxedTo: maxInputs,
+			}
+
+		default:
+			return nil, nil, fmt.Errorf("unknown relaxation "+
+				"step %v", step)
+		}
+		relaxations = append(relaxations, relaxation)
+
+		eligibleCommitments, _, err := s.filterConfirmed(
+			ctx, listedCommitments, relaxed.minConfs,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selected, err := s.selectForAmount(
+			constraints.MinAmt, eligibleCommitments, strategy,
+			constraints.Tranche, relaxed.maxInputs,
+		)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		log.Infof("Selected %d inputs for send of %d of %v with "+
+			"relaxed constraints: %v", len(selected),
+			constraints.MinAmt, constraints.assetDesc(),
+			relaxations)
+
+		return selected, relaxations, nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no constraint can be relaxed")
+	}
+
+	return nil, nil, lastErr
+}
+
+// filterConfirmed splits the given commitments into the ones that have the
+// number of confirmations required by the confirmation policy, and the ones
+// that don't. If maxConfs is set, no asset requires more confirmations than
+// that.
+func (s *CoinSelect) filterConfirmed(ctx context.Context,
+	commitments []*AnchoredCommitment,
+	maxConfs *uint32) ([]*AnchoredCommitment, []*AnchoredCommitment,
+	error) {
+
+	if !s.confPolicy.requiresConfs() {
+		return commitments, nil, nil
+	}
+
+	currentHeight, err := s.chainBridge.CurrentHeight(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to fetch current "+
+			"height: %w", err)
+	}
+
+	var confirmed, shallow []*AnchoredCommitment
+	for _, c := range commitments {
+		confs := numConfs(c.AnchorBlockHeight, currentHeight)
+		required := s.confPolicy.RequiredConfs(c.Asset)
+		if maxConfs != nil && required > *maxConfs {
+			required = *maxConfs
+		}
+		if confs < required {
+			shallow = append(shallow, c)
+			continue
+		}
+
+		confirmed = append(confirmed, c)
 	}
 
-	return selectedCoins, nil
+	return confirmed, shallow, nil
 }
 
 // LeaseCoins leases/locks/reserves coins for the given lease owner until the
@@ -276,159 +643,978 @@ func (s *CoinSelect) ReleaseCoins(ctx context.Context,
 	return s.coinLister.ReleaseCoins(ctx, utxoOutpoints...)
 }
 
+// DeleteExpiredLeases deletes all expired leases, which makes the UTXOs they
+// locked available for coin selection again.
+func (s *CoinSelect) DeleteExpiredLeases(ctx context.Context) error {
+	s.coinLock.Lock()
+	defer s.coinLock.Unlock()
+
+	return s.coinLister.DeleteExpiredLeases(ctx)
+}
+
+// ListLeases returns all leases that haven't expired yet.
+func (s *CoinSelect) ListLeases(ctx context.Context) ([]UTXOLease, error) {
+	s.coinLock.Lock()
+	defer s.coinLock.Unlock()
+
+	return s.coinLister.ListLeases(ctx)
+}
+
+// ReleaseLease releases the lease of the given UTXO if it is held by the given
+// lease owner, which makes the UTXO available for coin selection again.
+func (s *CoinSelect) ReleaseLease(ctx context.Context, leaseOwner [32]byte,
+	utxoOutpoint wire.OutPoint) error {
+
+	s.coinLock.Lock()
+	defer s.coinLock.Unlock()
+
+	return s.coinLister.ReleaseLease(ctx, leaseOwner, utxoOutpoint)
+}
+
 // selectForAmount selects a subset of the given eligible commitments which
 // cumulatively sum to at least the minimum required amount. The selection
-// strategy determines how the commitments are selected.
+// strategy determines how the commitments are selected. The tranche selection
+// determines the order in which the tranches of a grouped asset are
+// considered. The commitments of the first tranche that can satisfy the amount
+// on its own are selected, as a virtual transaction can only spend a single
+// asset ID. Only if no single tranche holds enough, the commitments of
+// multiple tranches are selected, in the order of the tranches. If more than
+// maxInputs commitments would be needed, ErrMaxInputsExceeded is returned. A
+// maxInputs value of zero means no limit.
 func (s *CoinSelect) selectForAmount(minTotalAmount uint64,
 	eligibleCommitments []*AnchoredCommitment,
-	strategy MultiCommitmentSelectStrategy) ([]*AnchoredCommitment,
-	error) {
+	strategy MultiCommitmentSelectStrategy,
+	tranche TrancheSelection,
+	maxInputs uint32) ([]*AnchoredCommitment, error) {
 
-	// Select the first subset of eligible commitments which cumulatively
-	// sum to at least the minimum required amount.
-	var selectedCommitments []*AnchoredCommitment
-	amountSum := uint64(0)
+	trancheRanks, err := rankTranches(eligibleCommitments, tranche)
+	if err != nil {
+		return nil, err
+	}
 
 	switch strategy {
 	case PreferMaxAmount:
-		// Sort eligible commitments from the largest amount to
-		// smallest.
+		// Sort eligible commitments by the rank of their tranche
+		// first, then from the largest amount to smallest.
 		sort.Slice(
 			eligibleCommitments, func(i, j int) bool {
-				isLess := eligibleCommitments[i].Asset.Amount <
-					eligibleCommitments[j].Asset.Amount
+				ci, cj := eligibleCommitments[i],
+					eligibleCommitments[j]
+
+				rankI := trancheRanks[ci.Asset.ID()]
+				rankJ := trancheRanks[cj.Asset.ID()]
+				if rankI != rankJ {
+					return rankI < rankJ
+				}
 
-				// Negate the result to sort in descending
-				// order.
-				return !isLess
+				// Sort in descending order of amounts.
+				return ci.Asset.Amount > cj.Asset.Amount
 			},
 		)
 
-		// Select the first subset of eligible commitments which
-		// cumulatively sum to at least the minimum required amount.
-		for _, anchoredCommitment := range eligibleCommitments {
-			selectedCommitments = append(
-				selectedCommitments, anchoredCommitment,
-			)
+	default:
+		return nil, fmt.Errorf("unknown multi coin selection "+
+			"strategy: %v", strategy)
+	}
+
+	// We group the sorted commitments by their tranche, keeping the order
+	// in which the tranches should be considered.
+	var (
+		trancheIDs []asset.ID
+		byTranche  = make(map[asset.ID][]*AnchoredCommitment)
+	)
+	for _, c := range eligibleCommitments {
+		id := c.Asset.ID()
+		if _, ok := byTranche[id]; !ok {
+			trancheIDs = append(trancheIDs, id)
+		}
+		byTranche[id] = append(byTranche[id], c)
+	}
+
+	// A single tranche is the common case, which also covers ungrouped
+	// assets.
+	for _, id := range trancheIDs {
+		selected, err := selectFromSorted(
+			byTranche[id], minTotalAmount, maxInputs,
+		)
+		if err == nil {
+			return selected, nil
+		}
+	}
+
+	// If no single tranche holds enough, we fall back to selecting the
+	// commitments of multiple tranches, in the order of the tranches.
+	return selectFromSorted(eligibleCommitments, minTotalAmount, maxInputs)
+}
+
+// selectFromSorted selects the first subset of the given sorted commitments
+// which cumulatively sum to at least the minimum required amount, using at most
+// maxInputs commitments unless maxInputs is zero.
+func selectFromSorted(commitments []*AnchoredCommitment, minTotalAmount uint64,
+	maxInputs uint32) ([]*AnchoredCommitment, error) {
+
+	var selectedCommitments []*AnchoredCommitment
+	amountSum := uint64(0)
+	for _, anchoredCommitment := range commitments {
+		selectedCommitments = append(
+			selectedCommitments, anchoredCommitment,
+		)
+
+		// Keep track of the total amount of assets we've seen so far.
+		err := mssmt.CheckSumOverflowUint64(
+			amountSum, anchoredCommitment.Asset.Amount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("total selected amount: %w",
+				err)
+		}
+		amountSum += anchoredCommitment.Asset.Amount
+		if amountSum >= minTotalAmount {
+			// At this point a target min amount was specified and
+			// has been reached.
+			break
+		}
+	}
+
+	// Having examined all the eligible commitments, return an error if the
+	// minimal funding amount was not reached.
+	if amountSum < minTotalAmount {
+		return nil, fmt.Errorf("%w: %d units short of the required %d",
+			ErrMatchingAssetsNotFound, minTotalAmount-amountSum,
+			minTotalAmount)
+	}
+
+	if maxInputs > 0 && len(selectedCommitments) > int(maxInputs) {
+		return nil, fmt.Errorf("%w: %d inputs needed, maximum is %d",
+			ErrMaxInputsExceeded, len(selectedCommitments),
+			maxInputs)
+	}
+
+	return selectedCommitments, nil
+}
+
+// rankTranches assigns a rank to each tranche (asset ID) found in the given
+// eligible commitments according to the tranche selection. Commitments of
+// tranches with a lower rank should be preferred. Tranches are ordered by the
+// height of their genesis block, tranches with an unknown genesis height are
+// considered the newest. Tranches minted in the same block keep the order in
+// which they appear in the eligible commitments.
+func rankTranches(eligibleCommitments []*AnchoredCommitment,
+	tranche TrancheSelection) (map[asset.ID]int, error) {
+
+	ranks := make(map[asset.ID]int)
+	if tranche.Preference == TranchePreferNone {
+		return ranks, nil
+	}
+
+	type trancheInfo struct {
+		id            asset.ID
+		genesisPoint  wire.OutPoint
+		genesisHeight uint32
+	}
+
+	var tranches []trancheInfo
+	for _, c := range eligibleCommitments {
+		id := c.Asset.ID()
+		if _, ok := ranks[id]; ok {
+			continue
+		}
+		ranks[id] = 0
+
+		genesisHeight := c.GenesisBlockHeight
+		if genesisHeight == 0 {
+			genesisHeight = math.MaxUint32
+		}
+		tranches = append(tranches, trancheInfo{
+			id:            id,
+			genesisPoint:  c.Asset.Genesis.FirstPrevOut,
+			genesisHeight: genesisHeight,
+		})
+	}
+
+	sort.SliceStable(tranches, func(i, j int) bool {
+		return tranches[i].genesisHeight < tranches[j].genesisHeight
+	})
+
+	for idx, t := range tranches {
+		switch tranche.Preference {
+		case TranchePreferOldest:
+			ranks[t.id] = idx
+
+		case TranchePreferNewest:
+			ranks[t.id] = len(tranches) - idx
+
+		case TranchePreferGenesisPoint:
+			ranks[t.id] = 1
+			if t.genesisPoint == tranche.GenesisPoint {
+				ranks[t.id] = 0
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown tranche preference: %v",
+				tranche.Preference)
+		}
+	}
+
+	return ranks, nil
+}
+
+var _ CoinSelector = (*CoinSelect)(nil)
+
+// ZeroChangePolicy describes how the wallet handles a change output that ends
+// up with a zero amount because a transfer consumes its inputs exactly.
+type ZeroChangePolicy uint8
+
+const (
+	// ZeroChangeTombstone keeps a zero-amount change output as an explicit
+	// tombstone output with an un-spendable script key.
+	ZeroChangeTombstone ZeroChangePolicy = iota
+
+	// ZeroChangeOmit removes a zero-amount change output from the transfer
+	// if all recipient outputs are interactive. A tombstone is still
+	// created for non-interactive recipients, since the receiver of an
+	// address can only derive the expected on-chain output from a split
+	// asset.
+	ZeroChangeOmit
+)
+
+// String returns a human-readable string for the zero change policy.
+func (z ZeroChangePolicy) String() string {
+	switch z {
+	case ZeroChangeTombstone:
+		return "tombstone"
+
+	case ZeroChangeOmit:
+		return "omit"
+
+	default:
+		return fmt.Sprintf("<unknown_zero_change_policy(%d)>", z)
+	}
+}
+
+// ParseZeroChangePolicy parses a zero change policy string.
+func ParseZeroChangePolicy(policy string) (ZeroChangePolicy, error) {
+	switch policy {
+	case "", ZeroChangeTombstone.String():
+		return ZeroChangeTombstone, nil
+
+	case ZeroChangeOmit.String():
+		return ZeroChangeOmit, nil
+
+	default:
+		return 0, fmt.Errorf("unknown zero change policy: %v", policy)
+	}
+}
+
+// applyZeroChangePolicy applies the given policy to the zero-amount change
+// output of the virtual packet, if there is one. A change output that carries
+// passive assets is always kept, since the passive assets need to be anchored
+// somewhere.
+func applyZeroChangePolicy(vPkt *tappsbt.VPacket,
+	policy ZeroChangePolicy) error {
+
+	if policy != ZeroChangeOmit || !vPkt.HasSplitRootOutput() {
+		return nil
+	}
+
+	changeOut, err := vPkt.SplitRootOutput()
+	if err != nil {
+		return err
+	}
+
+	if changeOut.Amount != 0 || changeOut.Type.CanCarryPassive() {
+		return nil
+	}
+
+	recipients := fn.Filter(vPkt.Outputs, tappsbt.VOutIsNotSplitRoot)
+	if len(recipients) == 0 {
+		return fmt.Errorf("zero-amount change output without recipient")
+	}
+
+	allInteractive := fn.All(recipients, func(vOut *tappsbt.VOutput) bool {
+		return vOut.Interactive
+	})
+	if !allInteractive {
+		log.Debugf("Keeping zero-amount change output as tombstone, " +
+			"transfer has non-interactive recipients")
+		return nil
+	}
+
+	// A single interactive recipient of the full value doesn't need a
+	// split at all. With multiple recipients we still need a split
+	// commitment, so the first recipient becomes the split root instead
+	// of the tombstone. That's only possible if it doesn't already have a
+	// special type.
+	if len(recipients) > 1 {
+		if recipients[0].Type != tappsbt.TypeSimple {
+			return nil
+		}
+
+		recipients[0].Type = tappsbt.TypeSplitRoot
+	}
+
+	isNotChange := func(vOut *tappsbt.VOutput) bool {
+		return vOut != changeOut
+	}
+	vPkt.Outputs = fn.Filter(vPkt.Outputs, isNotChange)
+
+	// If the tombstone had an anchor output of its own, we shift the
+	// anchor outputs after it, so we don't create an empty BTC output.
+	anchorIndex := changeOut.AnchorOutputIndex
+	anchorShared := fn.Any(vPkt.Outputs, func(vOut *tappsbt.VOutput) bool {
+		return vOut.AnchorOutputIndex == anchorIndex
+	})
+	if anchorShared {
+		return nil
+	}
+
+	for _, vOut := range vPkt.Outputs {
+		if vOut.AnchorOutputIndex > anchorIndex {
+			vOut.AnchorOutputIndex--
+		}
+	}
+
+	return nil
+}
+
+// AnchorOutputOrder is the order of the asset carrying outputs of the anchor
+// transaction of a send to addresses.
+type AnchorOutputOrder uint8
+
+const (
+	// AnchorOutputOrderNone keeps the change output first, followed by the
+	// recipients in the order of their addresses.
+	AnchorOutputOrderNone AnchorOutputOrder = iota
+
+	// AnchorOutputOrderBIP69 sorts the outputs by the rules of BIP-0069.
+	AnchorOutputOrderBIP69
+)
+
+// String returns a human-readable string for the anchor output order.
+func (o AnchorOutputOrder) String() string {
+	switch o {
+	case AnchorOutputOrderNone:
+		return "none"
+
+	case AnchorOutputOrderBIP69:
+		return "bip69"
+
+	default:
+		return fmt.Sprintf("<unknown_anchor_output_order(%d)>", o)
+	}
+}
+
+// Sorter returns the sorter that implements the anchor output order, or nil if
+// the outputs aren't re-ordered.
+func (o AnchorOutputOrder) Sorter() tapscript.AnchorOutputSorter {
+	switch o {
+	case AnchorOutputOrderBIP69:
+		return &tapscript.BIP69AnchorOutputSorter{}
+
+	default:
+		return nil
+	}
+}
+
+// ParseAnchorOutputOrder parses an anchor output order string.
+func ParseAnchorOutputOrder(order string) (AnchorOutputOrder, error) {
+	switch order {
+	case "", AnchorOutputOrderNone.String():
+		return AnchorOutputOrderNone, nil
+
+	case AnchorOutputOrderBIP69.String():
+		return AnchorOutputOrderBIP69, nil
+
+	default:
+		return 0, fmt.Errorf("unknown anchor output order: %v", order)
+	}
+}
+
+// WalletConfig holds the configuration for a new Wallet.
+type WalletConfig struct {
+	// CoinSelector is the interface used to select input coins (assets)
+	// for the transfer.
+	CoinSelector CoinSelector
+
+	// AssetProofs is used to write the proof files on disk for the
+	// receiver during a transfer.
+	//
+	// TODO(roasbeef): replace with proof.Courier in the future/
+	AssetProofs proof.Archiver
+
+	// AddrBook is used to fetch information about local address book
+	// related data in the database.
+	AddrBook AddrBook
+
+	// KeyRing is used to generate new keys throughout the transfer
+	// process.
+	KeyRing KeyRing
+
+	// Signer implements the Taproot Asset level signing we need to sign a
+	// virtual transaction.
+	Signer Signer
+
+	// TxValidator allows us to validate each Taproot Asset virtual
+	// transaction we create.
+	TxValidator tapscript.TxValidator
+
+	// Wallet is used to fund+sign PSBTs for the transfer transaction.
+	Wallet WalletAnchor
+
+	// ChainParams is the chain params of the chain we operate on.
+	ChainParams *address.ChainParams
+
+	// TrancheSelection is the tranche preference used when selecting the
+	// inputs of a grouped asset transfer.
+	TrancheSelection TrancheSelection
+
+	// MaxInputs is the default maximum number of asset inputs a transfer
+	// may select. A value of zero means no limit.
+	MaxInputs uint32
+
+	// ZeroChangePolicy is the policy for handling change outputs that end
+	// up with a zero amount.
+	ZeroChangePolicy ZeroChangePolicy
+
+	// AnchorOutputSorter decides the order of the asset carrying anchor
+	// outputs of a send to addresses. If this is nil, the change output
+	// comes first, followed by the recipients in the order of their
+	// addresses. The BTC change output is always the last output.
+	AnchorOutputSorter tapscript.AnchorOutputSorter
+}
+
+// AssetWallet is an implementation of the Wallet interface that can create
+// virtual transactions, sign them and commit them on-chain.
+type AssetWallet struct {
+	cfg *WalletConfig
+}
+
+// NewAssetWallet creates a new AssetWallet instance from the given
+// configuration.
+func NewAssetWallet(cfg *WalletConfig) *AssetWallet {
+	return &AssetWallet{
+		cfg: cfg,
+	}
+}
+
+// FundedVPacket is the result from an attempt to fund a given Taproot Asset
+// address send request via a call to FundAddressSend.
+type FundedVPacket struct {
+	// VPacket is the virtual transaction that was created to fund the
+	// transfer.
+	VPacket *tappsbt.VPacket
+
+	// InputCommitments is a map from virtual package input index to its
+	// associated Taproot Asset commitment.
+	InputCommitments tappsbt.InputCommitments
+
+	// CoinRelaxations are the coin selection constraints that had to be
+	// relaxed to fund the transfer.
+	CoinRelaxations []CoinRelaxation
+}
+
+// FundAddressSend funds a virtual transaction, selecting assets to spend in
+// order to pay the given address. It also returns supporting data which assists
+// in processing the virtual transaction: passive asset re-anchors and the
+// Taproot Asset level commitment of the selected assets. If maxInputs is
+// non-zero, it overrides the configured maximum number of inputs. If inputs is
+// non-empty, only the assets anchored at these outpoints may be selected.
+//
+// NOTE: This is part of the Wallet interface.
+func (f *AssetWallet) FundAddressSend(ctx context.Context, maxInputs uint32,
+	inputs []wire.OutPoint, receiverAddrs ...*address.Tap) (*FundedVPacket,
+	tappsbt.OutputIdxToAddr, error) {
+
+	return f.fundAddressSend(
+		ctx, maxInputs, inputs, false, nil, receiverAddrs...,
+	)
+}
+
+// fundAddressSend funds a virtual transaction that pays the given addresses.
+// If dryRun is true, the selected assets aren't leased and no keys are derived
+// from the wallet. The assets anchored at the shared inputs may be selected
+// even if they're leased.
+func (f *AssetWallet) fundAddressSend(ctx context.Context, maxInputs uint32,
+	inputs []wire.OutPoint, dryRun bool, sharedInputs []wire.OutPoint,
+	receiverAddrs ...*address.Tap) (*FundedVPacket,
+	tappsbt.OutputIdxToAddr, error) {
+
+	// We start by creating a new virtual transaction that will be used to
+	// hold the asset transfer. Because sending to an address is always a
+	// non-interactive process, we can use this function that always creates
+	// a change output.
+	vPkt, outputIdxToAddr, err := tappsbt.FromAddresses(receiverAddrs, 1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create virtual "+
+			"transaction from addresses: %w", err)
+	}
+
+	fundDesc, err := tapscript.DescribeAddrs(receiverAddrs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to describe recipients: "+
+			"%w", err)
+	}
+
+	// We chose the anchor output indexes of an address send ourselves, so
+	// we're free to re-order them.
+	fundedVPkt, err := f.fundPacket(
+		ctx, fundDesc, vPkt, maxInputs, inputs, dryRun, sharedInputs,
+		f.cfg.TrancheSelection, f.cfg.AnchorOutputSorter,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fundedVPkt, outputIdxToAddr, nil
+}
+
+// FundMultiAssetSend funds one virtual transaction for each distinct asset ID
+// of the given addresses, so that multiple assets can be paid out with a
+// single anchor transaction. The anchor output indexes of the returned packets
+// don't overlap. The address maps are returned in the same order as the
+// packets.
+//
+// NOTE: This is part of the Wallet interface.
+func (f *AssetWallet) FundMultiAssetSend(ctx context.Context,
+	maxInputs uint32, inputs []wire.OutPoint,
+	receiverAddrs ...*address.Tap) ([]*FundedVPacket,
+	[]tappsbt.OutputIdxToAddr, error) {
+
+	return f.fundMultiAssetSend(
+		ctx, maxInputs, inputs, false, receiverAddrs...,
+	)
+}
+
+// fundMultiAssetSend funds one virtual transaction for each distinct asset ID
+// of the given addresses. If dryRun is true, the selected assets aren't leased
+// and no keys are derived from the wallet. The packets may spend the same
+// anchor outputs, if multiple of the assets to send are anchored in them.
+func (f *AssetWallet) fundMultiAssetSend(ctx context.Context,
+	maxInputs uint32, inputs []wire.OutPoint, dryRun bool,
+	receiverAddrs ...*address.Tap) ([]*FundedVPacket,
+	[]tappsbt.OutputIdxToAddr, error) {
+
+	var (
+		fundedVPkts  []*FundedVPacket
+		addrMaps     []tappsbt.OutputIdxToAddr
+		sharedInputs []wire.OutPoint
+		anchorOffset uint32
+		success      bool
+	)
+
+	// If we return with an error, we want to release the coins we've
+	// already selected for the packets funded so far.
+	defer func() {
+		if success || dryRun {
+			return
+		}
+
+		for _, fundedVPkt := range fundedVPkts {
+			f.releaseInputs(ctx, fundedVPkt.VPacket)
+		}
+	}()
+
+	for _, addrs := range groupAddrsByAssetID(receiverAddrs) {
+		// The anchor outputs selected for the previous packets were
+		// leased for them, but the assets of this packet that are
+		// anchored in them can still be spent in the same transfer.
+		fundedVPkt, outputIdxToAddr, err := f.fundAddressSend(
+			ctx, maxInputs, inputs, dryRun, sharedInputs,
+			addrs...,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to fund send of "+
+				"asset_id=%v: %w", addrs[0].AssetID, err)
+		}
+		fundedVPkts = append(fundedVPkts, fundedVPkt)
+		addrMaps = append(addrMaps, outputIdxToAddr)
+
+		for _, vIn := range fundedVPkt.VPacket.Inputs {
+			sharedInputs = append(sharedInputs, vIn.PrevID.OutPoint)
+		}
+
+		// All packets are anchored in the same transaction, so the
+		// outputs of each packet are moved behind the anchor outputs of
+		// the packets before it.
+		vPkt := fundedVPkt.VPacket
+		var maxAnchorIndex uint32
+		for _, vOut := range vPkt.Outputs {
+			vOut.AnchorOutputIndex += anchorOffset
+			if vOut.AnchorOutputIndex > maxAnchorIndex {
+				maxAnchorIndex = vOut.AnchorOutputIndex
+			}
+		}
+		anchorOffset = maxAnchorIndex + 1
+	}
+
+	vPkts := fn.Map(fundedVPkts, func(p *FundedVPacket) *tappsbt.VPacket {
+		return p.VPacket
+	})
+	if err := checkDisjointPackets(vPkts); err != nil {
+		return nil, nil, err
+	}
+
+	// Each asset of an anchor output that is spent by multiple packets
+	// must only be committed to once in the anchor transaction.
+	if err := splitSharedInputCommitments(fundedVPkts); err != nil {
+		return nil, nil, err
+	}
+
+	success = true
+
+	return fundedVPkts, addrMaps, nil
+}
+
+// groupAddrsByAssetID groups the given addresses by their asset ID. The groups
+// and the addresses within them are kept in the order the addresses were given
+// in.
+func groupAddrsByAssetID(addrs []*address.Tap) [][]*address.Tap {
+	var (
+		groups   [][]*address.Tap
+		groupIdx = make(map[asset.ID]int)
+	)
+	for _, addr := range addrs {
+		idx, ok := groupIdx[addr.AssetID]
+		if !ok {
+			idx = len(groups)
+			groupIdx[addr.AssetID] = idx
+			groups = append(groups, nil)
+		}
+
+		groups[idx] = append(groups[idx], addr)
+	}
+
+	return groups
+}
+
+// checkDisjointPackets makes sure the given virtual packets, which are meant to
+// be anchored in the same anchor transaction, neither spend the same asset nor
+// commit to the same anchor output index. The packets may spend different
+// assets of the same anchor output.
+func checkDisjointPackets(vPkts []*tappsbt.VPacket) error {
+	if len(vPkts) < 2 {
+		return nil
+	}
+
+	var (
+		spentAssets   = make(map[asset.PrevID]int)
+		anchorOutputs = make(map[uint32]int)
+	)
+	for pktIdx, vPkt := range vPkts {
+		for _, vIn := range vPkt.Inputs {
+			prevID := vIn.PrevID
+			idx, ok := spentAssets[prevID]
+			if ok && idx != pktIdx {
+				return fmt.Errorf("virtual packets %d and %d "+
+					"both spend asset %v of anchor output "+
+					"%v", idx, pktIdx, prevID.ID,
+					prevID.OutPoint)
+			}
+			spentAssets[prevID] = pktIdx
+		}
+
+		for _, vOut := range vPkt.Outputs {
+			outIdx := vOut.AnchorOutputIndex
+			idx, ok := anchorOutputs[outIdx]
+			if ok && idx != pktIdx {
+				return fmt.Errorf("virtual packets %d and %d "+
+					"both commit to anchor output index %d",
+					idx, pktIdx, outIdx)
+			}
+			anchorOutputs[outIdx] = pktIdx
+		}
+	}
+
+	return nil
+}
+
+// anchorOwners returns the index of the first of the given virtual packets that
+// spends each of their anchor inputs. The passive assets of an anchor output
+// that is spent by multiple packets are re-anchored by that packet.
+func anchorOwners(vPkts []*tappsbt.VPacket) map[wire.OutPoint]int {
+	owners := make(map[wire.OutPoint]int)
+	for pktIdx, vPkt := range vPkts {
+		for _, vIn := range vPkt.Inputs {
+			op := vIn.PrevID.OutPoint
+			if _, ok := owners[op]; !ok {
+				owners[op] = pktIdx
+			}
+		}
+	}
+
+	return owners
+}
+
+// splitSharedInputCommitments updates the input commitments of the given
+// funded packets, which are meant to be anchored in the same anchor
+// transaction, so each asset of an anchor output that is spent by multiple
+// packets is only committed to once. The input commitment of such an anchor
+// output only keeps the assets the packet spends. The passive assets are only
+// kept by the first packet that spends the anchor output, which re-anchors
+// them in its change output.
+func splitSharedInputCommitments(fundedVPkts []*FundedVPacket) error {
+	vPkts := fn.Map(fundedVPkts, func(p *FundedVPacket) *tappsbt.VPacket {
+		return p.VPacket
+	})
+	owners := anchorOwners(vPkts)
+
+	spenders := make(map[asset.PrevID]int)
+	for pktIdx, vPkt := range vPkts {
+		for _, vIn := range vPkt.Inputs {
+			spenders[vIn.PrevID] = pktIdx
+		}
+	}
+
+	for pktIdx, fundedVPkt := range fundedVPkts {
+		for inputIdx, vIn := range fundedVPkt.VPacket.Inputs {
+			anchorPoint := vIn.PrevID.OutPoint
+			keep := func(a *asset.Asset) bool {
+				prevID := asset.PrevID{
+					OutPoint: anchorPoint,
+					ID:       a.ID(),
+					ScriptKey: asset.ToSerialized(
+						a.ScriptKey.PubKey,
+					),
+				}
+				spender, isSpent := spenders[prevID]
+				if isSpent {
+					return spender == pktIdx
+				}
+
+				return owners[anchorPoint] == pktIdx
+			}
+
+			inputCommitment, ok :=
+				fundedVPkt.InputCommitments[inputIdx]
+			if !ok {
+				return fmt.Errorf("missing input commitment "+
+					"for input %d", inputIdx)
+			}
+
+			pruned, err := filterCommittedAssets(
+				inputCommitment, keep,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to split input "+
+					"commitment of anchor %v: %w",
+					anchorPoint, err)
+			}
+			fundedVPkt.InputCommitments[inputIdx] = pruned
+		}
+
+		// A packet whose passive assets are re-anchored by another
+		// packet doesn't carry any in its change output anymore.
+		hasPassive, err := hasPassiveAssets(fundedVPkt)
+		if err != nil {
+			return err
+		}
+		for _, vOut := range fundedVPkt.VPacket.Outputs {
+			if !hasPassive &&
+				vOut.Type == tappsbt.TypePassiveSplitRoot {
 
-			// Keep track of the total amount of assets we've seen
-			// so far.
-			amountSum += uint64(anchoredCommitment.Asset.Amount)
-			if amountSum >= minTotalAmount {
-				// At this point a target min amount was
-				// specified and has been reached.
-				break
+				vOut.Type = tappsbt.TypeSplitRoot
 			}
 		}
-
-	default:
-		return nil, fmt.Errorf("unknown multi coin selection "+
-			"strategy: %v", strategy)
 	}
 
-	// Having examined all the eligible commitments, return an error if the
-	// minimal funding amount was not reached.
-	if amountSum < minTotalAmount {
-		return nil, ErrMatchingAssetsNotFound
-	}
-	return selectedCommitments, nil
+	return nil
 }
 
-var _ CoinSelector = (*CoinSelect)(nil)
+// hasPassiveAssets returns true if any of the input commitments of the given
+// funded packet commits to assets the packet doesn't spend.
+func hasPassiveAssets(fundedVPkt *FundedVPacket) (bool, error) {
+	for _, inputCommitment := range fundedVPkt.InputCommitments {
+		passiveCommitments, err := removeActiveCommitments(
+			inputCommitment, fundedVPkt.VPacket,
+		)
+		if err != nil {
+			return false, err
+		}
 
-// WalletConfig holds the configuration for a new Wallet.
-type WalletConfig struct {
-	// CoinSelector is the interface used to select input coins (assets)
-	// for the transfer.
-	CoinSelector CoinSelector
+		if len(passiveCommitments) > 0 {
+			return true, nil
+		}
+	}
 
-	// AssetProofs is used to write the proof files on disk for the
-	// receiver during a transfer.
-	//
-	// TODO(roasbeef): replace with proof.Courier in the future/
-	AssetProofs proof.Archiver
+	return false, nil
+}
 
-	// AddrBook is used to fetch information about local address book
-	// related data in the database.
-	AddrBook AddrBook
+// filterCommittedAssets returns a copy of the given Taproot Asset commitment
+// that only commits to the assets the keep function returns true for. The
+// original commitment is returned if all of its assets are kept.
+func filterCommittedAssets(tapCommitment *commitment.TapCommitment,
+	keep func(*asset.Asset) bool) (*commitment.TapCommitment, error) {
 
-	// KeyRing is used to generate new keys throughout the transfer
-	// process.
-	KeyRing KeyRing
+	var toRemove []*asset.Asset
+	for _, committedAsset := range tapCommitment.CommittedAssets() {
+		if !keep(committedAsset) {
+			toRemove = append(toRemove, committedAsset)
+		}
+	}
+	if len(toRemove) == 0 {
+		return tapCommitment, nil
+	}
 
-	// Signer implements the Taproot Asset level signing we need to sign a
-	// virtual transaction.
-	Signer Signer
+	filtered, err := tapCommitment.Copy()
+	if err != nil {
+		return nil, err
+	}
+	for _, removeAsset := range toRemove {
+		assetCommitment, ok := filtered.Commitment(removeAsset)
+		if !ok {
+			return nil, commitment.ErrMissingAssetCommitment
+		}
 
-	// TxValidator allows us to validate each Taproot Asset virtual
-	// transaction we create.
-	TxValidator tapscript.TxValidator
+		err := assetCommitment.Delete(removeAsset)
+		if err != nil {
+			return nil, err
+		}
 
-	// Wallet is used to fund+sign PSBTs for the transfer transaction.
-	Wallet WalletAnchor
+		// An asset commitment that is empty now is removed from the
+		// Taproot Asset tree.
+		if err := filtered.Upsert(assetCommitment); err != nil {
+			return nil, err
+		}
+	}
 
-	// ChainParams is the chain params of the chain we operate on.
-	ChainParams *address.ChainParams
+	return filtered, nil
 }
 
-// AssetWallet is an implementation of the Wallet interface that can create
-// virtual transactions, sign them and commit them on-chain.
-type AssetWallet struct {
-	cfg *WalletConfig
-}
+// releaseInputs releases the leases of the anchor outputs spent by the given
+// virtual packet, so they are available for coin selection again.
+func (f *AssetWallet) releaseInputs(ctx context.Context,
+	vPkt *tappsbt.VPacket) {
 
-// NewAssetWallet creates a new AssetWallet instance from the given
-// configuration.
-func NewAssetWallet(cfg *WalletConfig) *AssetWallet {
-	return &AssetWallet{
-		cfg: cfg,
+	outpoints := fn.Map(
+		vPkt.Inputs, func(vIn *tappsbt.VInput) wire.OutPoint {
+			return vIn.PrevID.OutPoint
+		},
+	)
+	err := f.cfg.CoinSelector.ReleaseCoins(ctx, outpoints...)
+	if err != nil {
+		log.Errorf("Unable to release coins: %v", err)
 	}
 }
 
-// FundedVPacket is the result from an attempt to fund a given Taproot Asset
-// address send request via a call to FundAddressSend.
-type FundedVPacket struct {
-	// VPacket is the virtual transaction that was created to fund the
-	// transfer.
-	VPacket *tappsbt.VPacket
+// SendEstimate is the preview of a send that was funded on the asset level,
+// but wasn't signed, stored or broadcast.
+type SendEstimate struct {
+	// VPackets are the funded virtual transactions of the send, one for
+	// each asset ID that is sent. Their inputs are the selected assets and
+	// their outputs are the projected recipient and change outputs.
+	VPackets []*tappsbt.VPacket
 
-	// InputCommitments is a map from virtual package input index to its
-	// associated Taproot Asset commitment.
-	InputCommitments tappsbt.InputCommitments
+	// NumPassiveAssets is the number of passive assets that would be
+	// re-anchored by the send.
+	NumPassiveAssets int
+
+	// CoinRelaxations are the coin selection constraints that had to be
+	// relaxed to fund the send.
+	CoinRelaxations []CoinRelaxation
+
+	// FeeRate is the fee rate the chain fee was estimated with.
+	FeeRate chainfee.SatPerKWeight
+
+	// ChainFees is the estimated amount of sats the anchor transaction
+	// would pay in chain fees.
+	ChainFees btcutil.Amount
 }
 
-// FundAddressSend funds a virtual transaction, selecting assets to spend in
-// order to pay the given address. It also returns supporting data which assists
-// in processing the virtual transaction: passive asset re-anchors and the
-// Taproot Asset level commitment of the selected assets.
+// EstimateAddressSend previews a send to the given addresses. It selects and
+// funds the assets to spend like FundMultiAssetSend, but doesn't lease them,
+// and estimates the chain fee of the anchor transaction at the given fee rate
+// without funding or signing it. The keys of the projected change outputs are
+// ephemeral placeholders, so the key index of the wallet isn't advanced.
 //
 // NOTE: This is part of the Wallet interface.
-func (f *AssetWallet) FundAddressSend(ctx context.Context,
-	receiverAddrs ...*address.Tap) (*FundedVPacket,
-	tappsbt.OutputIdxToAddr, error) {
+func (f *AssetWallet) EstimateAddressSend(ctx context.Context,
+	maxInputs uint32, inputs []wire.OutPoint,
+	feeRate chainfee.SatPerKWeight, feeBumpAnchor bool,
+	receiverAddrs ...*address.Tap) (*SendEstimate, error) {
 
-	// We start by creating a new virtual transaction that will be used to
-	// hold the asset transfer. Because sending to an address is always a
-	// non-interactive process, we can use this function that always creates
-	// a change output.
-	vPkt, outputIdxToAddr, err := tappsbt.FromAddresses(receiverAddrs, 1)
+	fundedVPkts, _, err := f.fundMultiAssetSend(
+		ctx, maxInputs, inputs, true, receiverAddrs...,
+	)
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to create virtual "+
-			"transaction from addresses: %w", err)
+		return nil, err
 	}
 
-	fundDesc, err := tapscript.DescribeAddrs(receiverAddrs)
-	if err != nil {
-		return nil, nil, fmt.Errorf("unable to describe recipients: "+
-			"%w", err)
+	var (
+		vPkts            []*tappsbt.VPacket
+		numPassiveAssets int
+		relaxations      []CoinRelaxation
+	)
+	for _, fundedVPkt := range fundedVPkts {
+		vPkt := fundedVPkt.VPacket
+		for inputIdx := range fundedVPkt.InputCommitments {
+			passiveCommitments, err := removeActiveCommitments(
+				fundedVPkt.InputCommitments[inputIdx], vPkt,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, passiveCommitment := range passiveCommitments {
+				numPassiveAssets += len(
+					passiveCommitment.Assets(),
+				)
+			}
+		}
+
+		vPkts = append(vPkts, vPkt)
+		relaxations = append(
+			relaxations, fundedVPkt.CoinRelaxations...,
+		)
 	}
 
-	fundedVPkt, err := f.FundPacket(ctx, fundDesc, vPkt)
-	if err != nil {
-		return nil, nil, err
+	return &SendEstimate{
+		VPackets:         vPkts,
+		NumPassiveAssets: numPassiveAssets,
+		CoinRelaxations:  relaxations,
+		FeeRate:          feeRate,
+		ChainFees: estimateAnchorTxFee(
+			vPkts, feeRate, feeBumpAnchor,
+		),
+	}, nil
+}
+
+// estimateAnchorTxFee estimates the chain fee of the anchor transaction of the
+// given funded virtual transactions at the given fee rate. The anchor
+// transaction spends one key spend input per distinct asset anchor and creates
+// one output per distinct anchor output index, plus the optional fee bump
+// anchor. We assume the wallet adds a single BIP-0086 input and change output
+// to pay for the fee, which is what happens unless the wallet is fragmented.
+func estimateAnchorTxFee(vPkts []*tappsbt.VPacket,
+	feeRate chainfee.SatPerKWeight, feeBumpAnchor bool) btcutil.Amount {
+
+	var weightEstimator input.TxWeightEstimator
+
+	anchorInputs := fn.NewSet[wire.OutPoint]()
+	anchorOutputs := fn.NewSet[uint32]()
+	for _, vPkt := range vPkts {
+		for _, vIn := range vPkt.Inputs {
+			anchorInputs.Add(vIn.PrevID.OutPoint)
+		}
+		for _, vOut := range vPkt.Outputs {
+			anchorOutputs.Add(vOut.AnchorOutputIndex)
+		}
+	}
+	for range anchorInputs {
+		weightEstimator.AddTaprootKeySpendInput(txscript.SigHashDefault)
+	}
+	for range anchorOutputs {
+		weightEstimator.AddP2TROutput()
 	}
 
-	return fundedVPkt, outputIdxToAddr, nil
+	if feeBumpAnchor {
+		weightEstimator.AddP2TROutput()
+	}
+
+	// The wallet's funding input and change output.
+	weightEstimator.AddTaprootKeySpendInput(txscript.SigHashDefault)
+	weightEstimator.AddP2TROutput()
+
+	return feeRate.FeeForWeight(int64(weightEstimator.Weight()))
 }
 
 // passiveAssetVPacket creates a virtual packet for the given passive asset.
@@ -494,12 +1680,77 @@ func (f *AssetWallet) passiveAssetVPacket(passiveAsset *asset.Asset,
 	return vPacket
 }
 
+// FundingOptions is a set of functional options that allow callers to modify
+// the coin selection of a funding request.
+type FundingOptions struct {
+	// TrancheSelection is the tranche preference used when selecting the
+	// inputs of a grouped asset.
+	TrancheSelection TrancheSelection
+}
+
+// FundingOption is a functional option that allows a caller to modify the coin
+// selection of a funding request.
+type FundingOption func(*FundingOptions)
+
+// WithTrancheSelection sets the tranche preference used when selecting the
+// inputs of a grouped asset, overriding the configured one.
+func WithTrancheSelection(tranche TrancheSelection) FundingOption {
+	return func(o *FundingOptions) {
+		o.TrancheSelection = tranche
+	}
+}
+
+// fundingOptions returns the funding options that result from applying the
+// given functional options to the configured defaults.
+func (f *AssetWallet) fundingOptions(
+	optFuncs []FundingOption) *FundingOptions {
+
+	opts := &FundingOptions{
+		TrancheSelection: f.cfg.TrancheSelection,
+	}
+	for _, optFunc := range optFuncs {
+		optFunc(opts)
+	}
+
+	return opts
+}
+
 // FundPacket funds a virtual transaction, selecting assets to spend in order to
 // pay the given recipient. The selected input is then added to the given
-// virtual transaction.
+// virtual transaction. The anchor output indexes of the given packet are kept
+// as requested.
 func (f *AssetWallet) FundPacket(ctx context.Context,
-	fundDesc *tapscript.FundingDescriptor,
-	vPkt *tappsbt.VPacket) (*FundedVPacket, error) {
+	fundDesc *tapscript.FundingDescriptor, vPkt *tappsbt.VPacket,
+	optFuncs ...FundingOption) (*FundedVPacket, error) {
+
+	opts := f.fundingOptions(optFuncs)
+
+	return f.fundPacket(
+		ctx, fundDesc, vPkt, 0, nil, false, nil, opts.TrancheSelection,
+		nil,
+	)
+}
+
+// fundPacket funds a virtual transaction with at most maxInputs inputs. If
+// maxInputs is zero, the configured maximum number of inputs is used, which
+// may be relaxed by the coin selection. A non-zero maxInputs is never relaxed.
+// If inputs is non-empty, only the assets anchored at these outpoints are
+// selected. If dryRun is true, the selected inputs aren't leased and the keys
+// of the outputs are ephemeral instead of derived from the wallet. The assets
+// anchored at the shared inputs, which were selected for another packet of the
+// same transfer, may be selected even if they're leased. The inputs of a
+// grouped asset are selected according to the given tranche preference. If the
+// sorter is non-nil, the anchor outputs are re-ordered with it.
+func (f *AssetWallet) fundPacket(ctx context.Context,
+	fundDesc *tapscript.FundingDescriptor, vPkt *tappsbt.VPacket,
+	maxInputs uint32, inputs []wire.OutPoint, dryRun bool,
+	sharedInputs []wire.OutPoint, tranche TrancheSelection,
+	sorter tapscript.AnchorOutputSorter) (*FundedVPacket, error) {
+
+	strictMaxInputs := maxInputs != 0
+	if !strictMaxInputs {
+		maxInputs = f.cfg.MaxInputs
+	}
 
 	// The input and address networks must match.
 	if !address.IsForNet(vPkt.ChainParams.TapHRP, f.cfg.ChainParams) {
@@ -510,34 +1761,166 @@ func (f *AssetWallet) FundPacket(ctx context.Context,
 	// send request. We'll map the address to a set of constraints, so we
 	// can use that to do Taproot asset coin selection.
 	constraints := CommitmentConstraints{
-		GroupKey: fundDesc.GroupKey,
-		AssetID:  &fundDesc.ID,
-		MinAmt:   fundDesc.Amount,
-	}
-	selectedCommitments, err := f.cfg.CoinSelector.SelectCoins(
+		GroupKey:        fundDesc.GroupKey,
+		AssetID:         selectionAssetID(fundDesc, vPkt, tranche),
+		MinAmt:          fundDesc.Amount,
+		Tranche:         tranche,
+		MaxInputs:       maxInputs,
+		StrictMaxInputs: strictMaxInputs,
+		NoLease:         dryRun,
+		Outpoints:       inputs,
+		SharedOutpoints: sharedInputs,
+	}
+	coinSelector := f.cfg.CoinSelector
+	selectedCommitments, relaxations, err := coinSelector.SelectCoins(
 		ctx, constraints, PreferMaxAmount,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return f.fundPacketWithInputs(ctx, fundDesc, vPkt, selectedCommitments)
+	fundDesc, err = selectedTranche(fundDesc, selectedCommitments)
+	if err != nil {
+		f.releaseCoins(ctx, selectedCommitments)
+		return nil, err
+	}
+
+	keyRing := f.cfg.KeyRing
+	if dryRun {
+		keyRing = &dryRunKeyRing{KeyRing: keyRing}
+	}
+
+	fundedPkt, err := f.fundPacketWithInputs(
+		ctx, keyRing, fundDesc, vPkt, selectedCommitments,
+		f.cfg.ZeroChangePolicy, sorter,
+	)
+	if err != nil {
+		return nil, err
+	}
+	fundedPkt.CoinRelaxations = relaxations
+
+	return fundedPkt, nil
+}
+
+// selectionAssetID returns the asset ID coin selection for the given funding
+// descriptor is constrained to. Without a tranche preference, only the inputs
+// of the exact asset ID of the descriptor are selected. With a preference, the
+// inputs of a grouped asset are selected by the group key alone, so the
+// preference decides which tranche is spent. A non-interactive recipient (an
+// address) derives its output key from the exact asset ID however, so if the
+// virtual packet pays one, only the tranche of the descriptor can be spent.
+func selectionAssetID(fundDesc *tapscript.FundingDescriptor,
+	vPkt *tappsbt.VPacket, tranche TrancheSelection) *asset.ID {
+
+	if fundDesc.GroupKey == nil ||
+		tranche.Preference == TranchePreferNone {
+
+		return &fundDesc.ID
+	}
+
+	if vPkt != nil {
+		for _, vOut := range vPkt.Outputs {
+			// Our own change output isn't derived from an address.
+			if vOut.Type.IsSplitRoot() {
+				continue
+			}
+
+			if !vOut.Interactive {
+				return &fundDesc.ID
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectedTranche returns a copy of the given funding descriptor with the
+// asset ID of the tranche the given commitments were selected from. If the
+// commitments belong to multiple tranches, because none of the tranches holds
+// the amount on its own, ErrMultipleTranches is returned together with the
+// amount selected from each tranche, as a virtual transaction can only spend a
+// single asset ID.
+func selectedTranche(fundDesc *tapscript.FundingDescriptor,
+	selected []*AnchoredCommitment) (*tapscript.FundingDescriptor, error) {
+
+	if len(selected) == 0 {
+		return fundDesc, nil
+	}
+
+	var (
+		trancheIDs []asset.ID
+		amounts    = make(map[asset.ID]uint64)
+	)
+	for _, c := range selected {
+		id := c.Asset.ID()
+		if _, ok := amounts[id]; !ok {
+			trancheIDs = append(trancheIDs, id)
+		}
+		amounts[id] += c.Asset.Amount
+	}
+
+	if len(trancheIDs) > 1 {
+		parts := fn.Map(trancheIDs, func(id asset.ID) string {
+			return fmt.Sprintf("%d of asset_id=%v", amounts[id], id)
+		})
+
+		return nil, fmt.Errorf("%w: %v", ErrMultipleTranches,
+			strings.Join(parts, ", "))
+	}
+
+	trancheID := trancheIDs[0]
+	if trancheID == fundDesc.ID {
+		return fundDesc, nil
+	}
+
+	log.Debugf("Funding send of asset_id=%v from tranche asset_id=%v "+
+		"of the same group", fundDesc.ID, trancheID)
+
+	trancheDesc := *fundDesc
+	trancheDesc.ID = trancheID
+
+	return &trancheDesc, nil
+}
+
+// releaseCoins releases the leases of the given selected commitments, so they
+// are available for coin selection again.
+func (f *AssetWallet) releaseCoins(ctx context.Context,
+	selectedCommitments []*AnchoredCommitment) {
+
+	outpoints := fn.Map(
+		selectedCommitments, func(c *AnchoredCommitment) wire.OutPoint {
+			return c.AnchorPoint
+		},
+	)
+	err := f.cfg.CoinSelector.ReleaseCoins(ctx, outpoints...)
+	if err != nil {
+		log.Errorf("Unable to release coins: %v", err)
+	}
 }
 
 // FundBurn funds a virtual transaction for burning the given amount of units of
 // the given asset.
 func (f *AssetWallet) FundBurn(ctx context.Context,
-	fundDesc *tapscript.FundingDescriptor) (*FundedVPacket, error) {
+	fundDesc *tapscript.FundingDescriptor,
+	optFuncs ...FundingOption) (*FundedVPacket, error) {
+
+	opts := f.fundingOptions(optFuncs)
 
 	// We need to find a commitment that has enough assets to satisfy this
 	// send request. We'll map the address to a set of constraints, so we
-	// can use that to do Taproot asset coin selection.
+	// can use that to do Taproot asset coin selection. A burn is an
+	// interactive transfer, so with a tranche preference the inputs of a
+	// grouped asset can be selected from any of its tranches.
 	constraints := CommitmentConstraints{
 		GroupKey: fundDesc.GroupKey,
-		AssetID:  &fundDesc.ID,
-		MinAmt:   fundDesc.Amount,
+		AssetID: selectionAssetID(
+			fundDesc, nil, opts.TrancheSelection,
+		),
+		MinAmt:    fundDesc.Amount,
+		Tranche:   opts.TrancheSelection,
+		MaxInputs: f.cfg.MaxInputs,
 	}
-	selectedCommitments, err := f.cfg.CoinSelector.SelectCoins(
+	selectedCommitments, _, err := f.cfg.CoinSelector.SelectCoins(
 		ctx, constraints, PreferMaxAmount,
 	)
 	if err != nil {
@@ -549,21 +1932,15 @@ func (f *AssetWallet) FundBurn(ctx context.Context,
 	success := false
 	defer func() {
 		if !success {
-			outpoints := fn.Map(
-				selectedCommitments,
-				func(c *AnchoredCommitment) wire.OutPoint {
-					return c.AnchorPoint
-				},
-			)
-			err := f.cfg.CoinSelector.ReleaseCoins(
-				ctx, outpoints...,
-			)
-			if err != nil {
-				log.Errorf("Unable to release coins: %v", err)
-			}
+			f.releaseCoins(ctx, selectedCommitments)
 		}
 	}()
 
+	fundDesc, err = selectedTranche(fundDesc, selectedCommitments)
+	if err != nil {
+		return nil, err
+	}
+
 	activeAssets := fn.Filter(
 		selectedCommitments, func(c *AnchoredCommitment) bool {
 			return c.Asset.ID() == fundDesc.ID
@@ -626,9 +2003,11 @@ func (f *AssetWallet) FundBurn(ctx context.Context,
 	)
 
 	// The virtual transaction is now ready to be further enriched with the
-	// split commitment and other data.
+	// split commitment and other data. We handle the zero-amount change
+	// output of a burn ourselves below, so we always want it to be kept.
 	fundedPkt, err := f.fundPacketWithInputs(
-		ctx, fundDesc, vPkt, selectedCommitments,
+		ctx, f.cfg.KeyRing, fundDesc, vPkt, selectedCommitments,
+		ZeroChangeTombstone, nil,
 	)
 	if err != nil {
 		return nil, err
@@ -667,10 +2046,39 @@ func (f *AssetWallet) FundBurn(ctx context.Context,
 	return fundedPkt, nil
 }
 
-// fundPacketWithInputs funds a virtual transaction with the given inputs.
+// dryRunKeyRing is a key ring that hands out ephemeral keys instead of the next
+// keys of the wallet, so a send can be previewed without advancing the key
+// index of the wallet. All other calls are passed to the wallet's key ring.
+type dryRunKeyRing struct {
+	KeyRing
+}
+
+// DeriveNextKey returns a new ephemeral key in the given key family. The key
+// isn't known to the wallet and can't be signed with.
+func (k *dryRunKeyRing) DeriveNextKey(_ context.Context,
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	privKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return keychain.KeyDescriptor{}, err
+	}
+
+	return keychain.KeyDescriptor{
+		KeyLocator: keychain.KeyLocator{
+			Family: keyFam,
+		},
+		PubKey: privKey.PubKey(),
+	}, nil
+}
+
+// fundPacketWithInputs funds a virtual transaction with the given inputs. The
+// given zero change policy is applied if the inputs are consumed exactly. The
+// keys of the local outputs are derived from the given key ring.
 func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
-	fundDesc *tapscript.FundingDescriptor, vPkt *tappsbt.VPacket,
-	selectedCommitments []*AnchoredCommitment) (*FundedVPacket, error) {
+	keyRing KeyRing, fundDesc *tapscript.FundingDescriptor,
+	vPkt *tappsbt.VPacket, selectedCommitments []*AnchoredCommitment,
+	zeroChange ZeroChangePolicy,
+	sorter tapscript.AnchorOutputSorter) (*FundedVPacket, error) {
 
 	log.Infof("Selected %v asset inputs for send of %d to %x",
 		len(selectedCommitments), fundDesc.Amount, fundDesc.ID[:])
@@ -821,7 +2229,7 @@ func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
 				"key is spendable: %w", err)
 		}
 		if unSpendable && !fullValue {
-			changeScriptKey, err := f.cfg.KeyRing.DeriveNextKey(
+			changeScriptKey, err := keyRing.DeriveNextKey(
 				ctx, asset.TaprootAssetsKeyFamily,
 			)
 			if err != nil {
@@ -860,6 +2268,16 @@ func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
 		changeOut.AssetVersion = fn.Reduce(vPkt.Inputs, maxVersion)
 	}
 
+	// If the inputs are consumed exactly, there might be a zero-amount
+	// change output that we need to handle according to the policy.
+	if fullValue {
+		err = applyZeroChangePolicy(vPkt, zeroChange)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply zero change "+
+				"policy: %w", err)
+		}
+	}
+
 	// Before we can prepare output assets for our send, we need to generate
 	// a new internal key for the anchor outputs. We assume any output that
 	// hasn't got an internal key set is going to a local anchor, and we
@@ -870,7 +2288,7 @@ func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
 			continue
 		}
 
-		newInternalKey, err := f.cfg.KeyRing.DeriveNextKey(
+		newInternalKey, err := keyRing.DeriveNextKey(
 			ctx, asset.TaprootAssetsKeyFamily,
 		)
 		if err != nil {
@@ -881,6 +2299,15 @@ func (f *AssetWallet) fundPacketWithInputs(ctx context.Context,
 		)
 	}
 
+	// The split commitments created below commit to the anchor output
+	// index of each output, so this is the last chance to re-order them.
+	if sorter != nil {
+		if err := tapscript.SortAnchorOutputs(vPkt, sorter); err != nil {
+			return nil, fmt.Errorf("unable to sort anchor outputs: "+
+				"%w", err)
+		}
+	}
+
 	if err := tapscript.PrepareOutputAssets(ctx, vPkt); err != nil {
 		return nil, fmt.Errorf("unable to create split commit: %w", err)
 	}
@@ -1005,10 +2432,49 @@ func (f *AssetWallet) setVPacketInputs(ctx context.Context,
 		}
 		vPkt.SetInputAsset(idx, assetInput.Asset, inputProof)
 
-		inputCommitments[idx] = assetInput.Commitment
+		err = f.maybePrepareScriptSpend(ctx, vPkt.Inputs[idx])
+		if err != nil {
+			return nil, fmt.Errorf("cannot prepare script "+
+				"spend: %w", err)
+		}
+
+		inputCommitments[idx] = assetInput.Commitment
+	}
+
+	return inputCommitments, nil
+}
+
+// maybePrepareScriptSpend instructs the signer to spend the given input through
+// the script path if its script key commits to a known tapscript tree with a
+// leaf that can be satisfied with a signature of the script key's internal key
+// alone. All other inputs are spent through the key spend path, unless the
+// caller prepares them for a spend through a different leaf with
+// tapscript.PrepareLeafSpend.
+func (f *AssetWallet) maybePrepareScriptSpend(ctx context.Context,
+	vIn *tappsbt.VInput) error {
+
+	scriptKey := vIn.Asset().ScriptKey
+	if scriptKey.TweakedScriptKey == nil ||
+		len(scriptKey.Tweak) != chainhash.HashSize {
+
+		return nil
+	}
+
+	leaves, err := f.cfg.AddrBook.FetchTapscriptLeaves(
+		ctx, scriptKey.Tweak,
+	)
+	if err != nil {
+		return err
 	}
 
-	return inputCommitments, nil
+	leafIdx, ok := tapscript.SignatureLeafIndex(
+		leaves, scriptKey.RawKey.PubKey,
+	)
+	if !ok {
+		return nil
+	}
+
+	return tapscript.PrepareScriptSpend(vIn, leaves, leafIdx)
 }
 
 // SignVirtualPacketOptions is a set of functional options that allow callers to
@@ -1077,10 +2543,15 @@ func (f *AssetWallet) SignVirtualPacket(vPkt *tappsbt.VPacket,
 			"witness data: %w", err)
 	}
 
-	// Mark all inputs as signed.
-	signedInputs := make([]uint32, len(vPkt.Inputs))
+	// Mark all inputs as signed, except the ones that already carried a
+	// final witness supplied by the caller.
+	signedInputs := make([]uint32, 0, len(vPkt.Inputs))
 	for idx := range vPkt.Inputs {
-		signedInputs[idx] = uint32(idx)
+		if len(vPkt.Inputs[idx].FinalScriptWitness) > 0 {
+			continue
+		}
+
+		signedInputs = append(signedInputs, uint32(idx))
 	}
 
 	return signedInputs, nil
@@ -1335,31 +2806,67 @@ func (f *AssetWallet) SignPassiveAssets(vPkt *tappsbt.VPacket,
 func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	params *AnchorVTxnsParams) (*AnchorTransaction, error) {
 
-	// We currently only support anchoring a single virtual transaction.
-	//
-	// TODO(guggero): Support merging and anchoring multiple virtual
-	// transactions.
-	if len(params.VPkts) != 1 {
-		return nil, fmt.Errorf("only a single virtual transaction is " +
-			"supported for now")
+	anchorTx, err := f.CommitVirtualTransactions(ctx, params)
+	if err != nil {
+		return nil, err
 	}
-	vPacket := params.VPkts[0]
 
-	outputCommitments, err := tapscript.CreateOutputCommitments(
-		params.InputCommitments, vPacket, params.PassiveAssetsVPkts,
-	)
+	// With all the input and output information in the packet, we
+	// can now ask lnd to sign it, and then extract the final
+	// version ourselves.
+	signAnchorPkt := anchorTx.FundedPsbt.Pkt
+	log.Debugf("Signing PSBT")
+	log.Tracef("PSBT: %s", spew.Sdump(signAnchorPkt))
+	signedPsbt, err := f.cfg.Wallet.SignPsbt(ctx, signAnchorPkt)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create new output "+
-			"commitments: %w", err)
+		return nil, fmt.Errorf("unable to sign psbt: %w", err)
+	}
+	log.Debugf("Got signed PSBT")
+	log.Tracef("PSBT: %s", spew.Sdump(signedPsbt))
+
+	if err := finalizeAnchorTx(anchorTx, signedPsbt); err != nil {
+		return nil, err
+	}
+
+	return anchorTx, nil
+}
+
+// CommitVirtualTransactions creates and funds a BTC level anchor transaction
+// that anchors all the virtual transactions of the given packets (for both
+// sending and passive asset re-anchoring), but doesn't sign it. The returned
+// anchor TX only carries the funded PSBT, which contains all the information
+// needed to sign it.
+func (f *AssetWallet) CommitVirtualTransactions(ctx context.Context,
+	params *AnchorVTxnsParams) (*AnchorTransaction, error) {
+
+	if err := validateAnchorParams(params); err != nil {
+		return nil, err
 	}
 
 	// Construct our template PSBT to commits to the set of dummy locators
-	// we use to make fee estimation work.
-	sendPacket, err := tapscript.CreateAnchorTx(vPacket.Outputs)
+	// we use to make fee estimation work. The outputs of all virtual
+	// packets are anchored in the same transaction.
+	var allOutputs []*tappsbt.VOutput
+	for _, vPkt := range params.VPkts {
+		allOutputs = append(allOutputs, vPkt.Outputs...)
+	}
+	sendPacket, err := tapscript.CreateAnchorTx(allOutputs)
 	if err != nil {
 		return nil, fmt.Errorf("error creating anchor TX: %w", err)
 	}
 
+	// If requested, we reserve an output for bumping the fee of the
+	// anchor TX later on. We add it before funding, so the wallet pays
+	// for it and accounts for its weight.
+	var feeBumpAnchor *FeeBumpAnchor
+	if params.FeeBumpAnchor {
+		feeBumpAnchor, err = f.addFeeBumpAnchor(ctx, sendPacket)
+		if err != nil {
+			return nil, fmt.Errorf("unable to add fee bump "+
+				"anchor: %w", err)
+		}
+	}
+
 	anchorPkt, err := f.cfg.Wallet.FundPsbt(
 		ctx, sendPacket, 1, params.FeeRate,
 	)
@@ -1376,7 +2883,22 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	// TODO(jhb): Do we need richer handling for the change output?
 	// We could reassign the change value to our Taproot Asset change output
 	// and remove the change output entirely.
-	adjustFundedPsbt(&anchorPkt, int64(vPacket.Inputs[0].Anchor.Value))
+	adjustFundedPsbt(&anchorPkt, anchorInputValue(params.VPkts))
+
+	// The wallet appends the change output, so the reserved fee bump
+	// anchor should still be where we put it. We make sure it wasn't
+	// overwritten by the change.
+	if feeBumpAnchor != nil {
+		txOuts := anchorPkt.Pkt.UnsignedTx.TxOut
+		idx := feeBumpAnchor.OutputIndex
+		if int(idx) >= len(txOuts) || !bytes.Equal(
+			txOuts[idx].PkScript, feeBumpAnchor.PkScript,
+		) {
+
+			return nil, fmt.Errorf("fee bump anchor output %d was "+
+				"moved by the wallet", idx)
+		}
+	}
 
 	log.Infof("Received funded PSBT packet")
 	log.Tracef("Packet: %v", spew.Sdump(anchorPkt.Pkt))
@@ -1391,19 +2913,16 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 
 	// First, we'll update the PSBT packets to insert the _real_ outputs we
 	// need to commit to the asset transfer.
-	mergedCommitments, err := tapscript.UpdateTaprootOutputKeys(
-		signAnchorPkt, vPacket, outputCommitments,
-	)
+	mergedCommitments, err := commitVirtualPackets(signAnchorPkt, params)
 	if err != nil {
-		return nil, fmt.Errorf("error updating taproot output keys: %w",
-			err)
+		return nil, err
 	}
 
 	// Now that all the real outputs are in the PSBT, we'll also
 	// add our anchor inputs as well, since the wallet can sign for
 	// it itself.
 	err = addAnchorPsbtInputs(
-		signAnchorPkt, vPacket, params.FeeRate,
+		signAnchorPkt, params.VPkts, params.FeeRate,
 		f.cfg.ChainParams.Params,
 	)
 	if err != nil {
@@ -1411,20 +2930,329 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 	}
 	anchorPkt.Pkt = signAnchorPkt
 
-	// With all the input and output information in the packet, we
-	// can now ask lnd to sign it, and then extract the final
-	// version ourselves.
-	log.Debugf("Signing PSBT")
-	log.Tracef("PSBT: %s", spew.Sdump(signAnchorPkt))
-	signedPsbt, err := f.cfg.Wallet.SignPsbt(ctx, signAnchorPkt)
+	return &AnchorTransaction{
+		FundedPsbt:        &anchorPkt,
+		TargetFeeRate:     params.FeeRate,
+		OutputCommitments: mergedCommitments,
+		FeeBumpAnchor:     feeBumpAnchor,
+	}, nil
+}
+
+// CompleteAnchorTx verifies that the given externally signed anchor packet
+// commits to the virtual transactions of the given packets and finalizes it.
+// The change output index and the locked UTXOs are the ones the packet was
+// funded with.
+func (f *AssetWallet) CompleteAnchorTx(params *AnchorVTxnsParams,
+	signedPkt *psbt.Packet, changeOutputIndex int32,
+	lockedUTXOs []wire.OutPoint) (*AnchorTransaction, error) {
+
+	if err := validateAnchorParams(params); err != nil {
+		return nil, err
+	}
+
+	// We commit to the virtual transactions on a copy of the packet. If
+	// the signed packet was committed to the same transactions, this
+	// doesn't change any of its outputs.
+	fundedPkt, err := copyPsbt(signedPkt)
 	if err != nil {
-		return nil, fmt.Errorf("unable to sign psbt: %w", err)
+		return nil, fmt.Errorf("unable to copy PSBT: %w", err)
 	}
-	log.Debugf("Got signed PSBT")
-	log.Tracef("PSBT: %s", spew.Sdump(signedPsbt))
+	mergedCommitments, err := commitVirtualPackets(fundedPkt, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if fundedPkt.UnsignedTx.TxHash() != signedPkt.UnsignedTx.TxHash() {
+		return nil, fmt.Errorf("anchor transaction doesn't commit to " +
+			"the virtual transactions")
+	}
+
+	// A change output index of -1 means the packet has no change output,
+	// any other index must refer to one of its outputs.
+	numOutputs := int32(len(signedPkt.UnsignedTx.TxOut))
+	if changeOutputIndex < -1 || changeOutputIndex >= numOutputs {
+		return nil, fmt.Errorf("invalid change output index %d",
+			changeOutputIndex)
+	}
+
+	anchorTx := &AnchorTransaction{
+		FundedPsbt: &tapgarden.FundedPsbt{
+			Pkt:               fundedPkt,
+			ChangeOutputIndex: changeOutputIndex,
+			LockedUTXOs:       lockedUTXOs,
+		},
+		OutputCommitments: mergedCommitments,
+	}
+	if err := finalizeAnchorTx(anchorTx, signedPkt); err != nil {
+		return nil, err
+	}
+
+	// The packet was funded externally, so we only learn about the fee
+	// rate it pays from the final transaction.
+	weight := blockchain.GetTransactionWeight(
+		btcutil.NewTx(anchorTx.FinalTx),
+	)
+	if weight > 0 {
+		anchorTx.TargetFeeRate = chainfee.SatPerKWeight(
+			anchorTx.ChainFees * 1000 / weight,
+		)
+	}
+
+	return anchorTx, nil
+}
+
+// validateAnchorParams makes sure the given parameters describe a set of
+// virtual packets that can be anchored in a single anchor transaction.
+func validateAnchorParams(params *AnchorVTxnsParams) error {
+	if len(params.VPkts) == 0 {
+		return fmt.Errorf("no virtual transactions to anchor")
+	}
+
+	if len(params.InputCommitments) != len(params.VPkts) {
+		return fmt.Errorf("got input commitments for %d virtual "+
+			"transactions, expected %d",
+			len(params.InputCommitments), len(params.VPkts))
+	}
+
+	return checkDisjointPackets(params.VPkts)
+}
+
+// anchorInputValue returns the total value of the distinct anchor outputs the
+// given virtual packets spend.
+func anchorInputValue(vPkts []*tappsbt.VPacket) int64 {
+	var (
+		value        int64
+		spentAnchors = fn.NewSet[wire.OutPoint]()
+	)
+	for _, vPkt := range vPkts {
+		for _, vIn := range vPkt.Inputs {
+			if spentAnchors.Contains(vIn.PrevID.OutPoint) {
+				continue
+			}
+			spentAnchors.Add(vIn.PrevID.OutPoint)
+
+			value += int64(vIn.Anchor.Value)
+		}
+	}
+
+	return value
+}
+
+// commitVirtualPackets creates the output commitments of all the virtual
+// packets of the given parameters and updates the outputs of the given anchor
+// packet to commit to them. The passive assets are anchored in the change
+// output of the first virtual packet that spends their previous anchor output.
+// The merged commitments are returned by their anchor output index.
+func commitVirtualPackets(btcPkt *psbt.Packet,
+	params *AnchorVTxnsParams) (map[uint32]*commitment.TapCommitment,
+	error) {
+
+	owners := anchorOwners(params.VPkts)
+
+	mergedCommitments := make(map[uint32]*commitment.TapCommitment)
+	for pktIdx, vPkt := range params.VPkts {
+		passivePkts := fn.Filter(
+			params.PassiveAssetsVPkts,
+			func(passivePkt *tappsbt.VPacket) bool {
+				prevID := passivePkt.Inputs[0].PrevID
+				owner, ok := owners[prevID.OutPoint]
+				return ok && owner == pktIdx
+			},
+		)
+
+		outputCommitments, err := tapscript.CreateOutputCommitments(
+			params.InputCommitments[pktIdx], vPkt, passivePkts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create new output "+
+				"commitments: %w", err)
+		}
+
+		anchorCommitments, err := tapscript.UpdateTaprootOutputKeys(
+			btcPkt, vPkt, outputCommitments,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error updating taproot output "+
+				"keys: %w", err)
+		}
+
+		// The packets don't share any anchor outputs, so their
+		// commitments don't need to be merged.
+		for anchorIdx, anchorCommitment := range anchorCommitments {
+			mergedCommitments[anchorIdx] = anchorCommitment
+		}
+	}
+
+	return mergedCommitments, nil
+}
+
+// finalizeAnchorTx finalizes the given signed anchor packet and adds the final
+// transaction and the chain fees it pays to the anchor TX.
+func finalizeAnchorTx(anchorTx *AnchorTransaction,
+	signedPsbt *psbt.Packet) error {
 
 	// Before we finalize, we need to calculate the actual, final fees that
 	// we pay.
+	chainFees, err := tapgarden.GetTxFee(signedPsbt)
+	if err != nil {
+		return fmt.Errorf("unable to get on-chain fees for psbt: %w",
+			err)
+	}
+
+	err = psbt.MaybeFinalizeAll(signedPsbt)
+	if err != nil {
+		return fmt.Errorf("unable to finalize psbt: %w", err)
+	}
+
+	// Extract the final packet from the PSBT transaction (has all sigs
+	// included).
+	finalTx, err := psbt.Extract(signedPsbt)
+	if err != nil {
+		return fmt.Errorf("unable to extract psbt: %w", err)
+	}
+
+	// Final TX sanity check.
+	err = blockchain.CheckTransactionSanity(btcutil.NewTx(finalTx))
+	if err != nil {
+		return fmt.Errorf("anchor TX failed final checks: %w", err)
+	}
+
+	anchorTx.FinalTx = finalTx
+	anchorTx.ChainFees = chainFees
+
+	return nil
+}
+
+// addFeeBumpAnchor appends a wallet owned BIP-0086 output to the given
+// template anchor packet that is reserved for bumping the fee of the anchor
+// transaction with a child transaction. The output is imported into the
+// wallet, so it is watched and accounted for, like the other anchor outputs.
+// It is spent by CreateFeeBumpChild, which signs for it with the derivation
+// path of its internal key.
+func (f *AssetWallet) addFeeBumpAnchor(ctx context.Context,
+	pkt *psbt.Packet) (*FeeBumpAnchor, error) {
+
+	internalKey, err := f.cfg.KeyRing.DeriveNextKey(
+		ctx, asset.TaprootAssetsKeyFamily,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	outputKey := txscript.ComputeTaprootKeyNoScript(internalKey.PubKey)
+	pkScript, err := tapscript.PayToTaprootScript(outputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The wallet needs to know about the output, otherwise it wouldn't be
+	// able to find it again when we want to spend it.
+	_, err = f.cfg.Wallet.ImportTaprootOutput(ctx, outputKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to import output: %w", err)
+	}
+
+	bip32Derivation, trBip32Derivation :=
+		tappsbt.Bip32DerivationFromKeyDesc(
+			internalKey, f.cfg.ChainParams.HDCoinType,
+		)
+
+	pkt.UnsignedTx.AddTxOut(&wire.TxOut{
+		Value:    FeeBumpAnchorValue,
+		PkScript: pkScript,
+	})
+	pkt.Outputs = append(pkt.Outputs, psbt.POutput{
+		TaprootInternalKey: schnorr.SerializePubKey(
+			internalKey.PubKey,
+		),
+		Bip32Derivation: []*psbt.Bip32Derivation{
+			bip32Derivation,
+		},
+		TaprootBip32Derivation: []*psbt.TaprootBip32Derivation{
+			trBip32Derivation,
+		},
+	})
+
+	return &FeeBumpAnchor{
+		OutputIndex: uint32(len(pkt.UnsignedTx.TxOut) - 1),
+		InternalKey: internalKey,
+		PkScript:    pkScript,
+	}, nil
+}
+
+// BumpAnchorTxFee creates a replacement for the given anchor TX that pays the
+// given fee rate, following the rules of BIP-0125. The additional fee is
+// deducted from the wallet's change output, while all inputs and all other
+// outputs are kept as they are. That way the asset level witnesses and
+// commitments created for the original anchor TX remain valid for the
+// replacement.
+func (f *AssetWallet) BumpAnchorTxFee(ctx context.Context,
+	anchorTx *AnchorTransaction,
+	feeRate chainfee.SatPerKWeight) (*AnchorTransaction, error) {
+
+	if anchorTx.FundedPsbt == nil || anchorTx.FundedPsbt.Pkt == nil {
+		return nil, fmt.Errorf("funded anchor PSBT unknown")
+	}
+
+	changeIdx := anchorTx.FundedPsbt.ChangeOutputIndex
+	if changeIdx < 0 {
+		return nil, fmt.Errorf("anchor TX has no change output to " +
+			"pay for the fee bump")
+	}
+
+	// The replacement has the same weight as the original TX, as only the
+	// value of the change output changes. Besides paying for the target
+	// fee rate, it also needs to pay at least the absolute fee of the
+	// original TX plus the incremental relay fee for its own size.
+	weight := blockchain.GetTransactionWeight(
+		btcutil.NewTx(anchorTx.FinalTx),
+	)
+	oldFee := btcutil.Amount(anchorTx.ChainFees)
+	newFee := feeRate.FeeForWeight(weight)
+	minFee := oldFee + chainfee.FeePerKwFloor.FeeForWeight(weight)
+	if newFee < minFee {
+		return nil, fmt.Errorf("%w: fee of %d sats at %v doesn't "+
+			"replace fee of %d sats, need at least %d sats",
+			ErrFeeRateTooLow, newFee, feeRate, oldFee, minFee)
+	}
+
+	bumpPkt, err := copyPsbt(anchorTx.FundedPsbt.Pkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy PSBT: %w", err)
+	}
+
+	// We sign all inputs again, so we remove any signature that might be
+	// left over from signing the original TX.
+	for idx := range bumpPkt.Inputs {
+		pIn := &bumpPkt.Inputs[idx]
+		pIn.PartialSigs = nil
+		pIn.TaprootKeySpendSig = nil
+		pIn.TaprootScriptSpendSig = nil
+		pIn.FinalScriptSig = nil
+		pIn.FinalScriptWitness = nil
+	}
+
+	changeOut := bumpPkt.UnsignedTx.TxOut[changeIdx]
+	feeDelta := int64(newFee - oldFee)
+	dustLimit := lnwallet.DustLimitForSize(len(changeOut.PkScript))
+	if changeOut.Value-feeDelta < int64(dustLimit) {
+		return nil, fmt.Errorf("fee increase of %d sats exceeds "+
+			"change amount of %d sats", feeDelta, changeOut.Value)
+	}
+	changeOut.Value -= feeDelta
+
+	// We keep the unsigned replacement around, so it can be bumped again.
+	signAnchorPkt, err := copyPsbt(bumpPkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy PSBT: %w", err)
+	}
+
+	log.Debugf("Signing replacement PSBT")
+	signedPsbt, err := f.cfg.Wallet.SignPsbt(ctx, signAnchorPkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign psbt: %w", err)
+	}
+
 	chainFees, err := tapgarden.GetTxFee(signedPsbt)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get on-chain fees for psbt: "+
@@ -1436,39 +3264,185 @@ func (f *AssetWallet) AnchorVirtualTransactions(ctx context.Context,
 		return nil, fmt.Errorf("unable to finalize psbt: %w", err)
 	}
 
-	// Extract the final packet from the PSBT transaction (has all sigs
-	// included).
 	finalTx, err := psbt.Extract(signedPsbt)
 	if err != nil {
 		return nil, fmt.Errorf("unable to extract psbt: %w", err)
 	}
 
-	// Final TX sanity check.
 	err = blockchain.CheckTransactionSanity(btcutil.NewTx(finalTx))
 	if err != nil {
 		return nil, fmt.Errorf("anchor TX failed final checks: %w", err)
 	}
 
+	fundedPsbt := *anchorTx.FundedPsbt
+	fundedPsbt.Pkt = bumpPkt
+
 	return &AnchorTransaction{
-		FundedPsbt:        &anchorPkt,
+		FundedPsbt:        &fundedPsbt,
 		FinalTx:           finalTx,
-		TargetFeeRate:     params.FeeRate,
+		TargetFeeRate:     feeRate,
 		ChainFees:         chainFees,
-		OutputCommitments: mergedCommitments,
+		OutputCommitments: anchorTx.OutputCommitments,
+		FeeBumpAnchor:     anchorTx.FeeBumpAnchor,
 	}, nil
 }
 
+// CreateFeeBumpChild creates and signs a child transaction that spends the
+// given fee bump anchor output of the given unconfirmed anchor TX, which pays
+// the given amount of chain fees. The child pays enough fees for the package
+// of both transactions to reach the given fee rate. Additional wallet inputs
+// are selected to pay for the fees, and the remaining value is sent back to
+// the wallet.
+func (f *AssetWallet) CreateFeeBumpChild(ctx context.Context,
+	anchorTx *wire.MsgTx, chainFees int64, feeBumpAnchor *FeeBumpAnchor,
+	feeRate chainfee.SatPerKWeight) (*wire.MsgTx, error) {
+
+	idx := feeBumpAnchor.OutputIndex
+	if int(idx) >= len(anchorTx.TxOut) || !bytes.Equal(
+		anchorTx.TxOut[idx].PkScript, feeBumpAnchor.PkScript,
+	) {
+
+		return nil, fmt.Errorf("anchor TX has no fee bump anchor "+
+			"output at index %d", idx)
+	}
+	anchorOut := anchorTx.TxOut[idx]
+
+	// The child needs to make up for the fees the anchor TX is missing to
+	// reach the target fee rate on its own.
+	parentWeight := blockchain.GetTransactionWeight(btcutil.NewTx(anchorTx))
+	deficit := int64(feeRate.FeeForWeight(parentWeight)) - chainFees
+	if deficit <= 0 {
+		return nil, fmt.Errorf("%w: anchor TX already pays %d sats at "+
+			"%v", ErrFeeRateTooLow, chainFees, feeRate)
+	}
+
+	// The wallet funds the child without the fee bump anchor input, as
+	// it doesn't know about it. So we need to pay for the input ourselves.
+	anchorInputWeight := int64(
+		input.InputSize*blockchain.WitnessScaleFactor +
+			input.TaprootKeyPathWitnessSize,
+	)
+	anchorInputFee := int64(feeRate.FeeForWeight(anchorInputWeight))
+
+	addr, err := f.cfg.Wallet.NextAddr(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive address: %w", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// We let the wallet fund an output that is large enough to cover the
+	// additional fees and still not be dust afterward.
+	dustLimit := int64(lnwallet.DustLimitForSize(len(pkScript)))
+	extraFee := deficit + anchorInputFee
+	tx := wire.NewMsgTx(2)
+	tx.AddTxOut(&wire.TxOut{
+		Value:    extraFee + dustLimit,
+		PkScript: pkScript,
+	})
+	pkt, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	funded, err := f.cfg.Wallet.FundPsbt(ctx, pkt, 1, feeRate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fund psbt: %w", err)
+	}
+
+	childTx, err := f.signFeeBumpChild(
+		ctx, funded.Pkt, anchorTx.TxHash(), anchorOut, feeBumpAnchor,
+		pkScript, extraFee,
+	)
+	if err != nil {
+		for _, op := range funded.LockedUTXOs {
+			releaseErr := f.cfg.Wallet.ReleaseOutput(ctx, op)
+			if releaseErr != nil {
+				log.Warnf("Unable to release wallet input %v: "+
+					"%v", op, releaseErr)
+			}
+		}
+
+		return nil, err
+	}
+
+	return childTx, nil
+}
+
+// signFeeBumpChild adds the fee bump anchor input to the given funded child
+// packet, deducts the given extra fee minus the value of the anchor output
+// from the output with the given pk script and signs the packet.
+func (f *AssetWallet) signFeeBumpChild(ctx context.Context, pkt *psbt.Packet,
+	anchorTXID chainhash.Hash, anchorOut *wire.TxOut,
+	feeBumpAnchor *FeeBumpAnchor, pkScript []byte,
+	extraFee int64) (*wire.MsgTx, error) {
+
+	outIdx := -1
+	for idx, txOut := range pkt.UnsignedTx.TxOut {
+		if bytes.Equal(txOut.PkScript, pkScript) {
+			outIdx = idx
+			break
+		}
+	}
+	if outIdx < 0 {
+		return nil, fmt.Errorf("funded child TX is missing its output")
+	}
+	pkt.UnsignedTx.TxOut[outIdx].Value -= extraFee - anchorOut.Value
+
+	_, trDerivation := tappsbt.Bip32DerivationFromKeyDesc(
+		feeBumpAnchor.InternalKey, f.cfg.ChainParams.HDCoinType,
+	)
+	pkt.UnsignedTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  anchorTXID,
+			Index: feeBumpAnchor.OutputIndex,
+		},
+	})
+	pkt.Inputs = append(pkt.Inputs, psbt.PInput{
+		WitnessUtxo: &wire.TxOut{
+			Value:    anchorOut.Value,
+			PkScript: anchorOut.PkScript,
+		},
+		SighashType: txscript.SigHashDefault,
+		TaprootBip32Derivation: []*psbt.TaprootBip32Derivation{
+			trDerivation,
+		},
+		TaprootInternalKey: trDerivation.XOnlyPubKey,
+	})
+
+	signedPkt, err := f.cfg.Wallet.SignPsbt(ctx, pkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign child TX: %w", err)
+	}
+	if err := psbt.MaybeFinalizeAll(signedPkt); err != nil {
+		return nil, fmt.Errorf("unable to finalize child TX: %w", err)
+	}
+	childTx, err := psbt.Extract(signedPkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract child TX: %w", err)
+	}
+	err = blockchain.CheckTransactionSanity(btcutil.NewTx(childTx))
+	if err != nil {
+		return nil, fmt.Errorf("child TX failed final checks: %w", err)
+	}
+
+	return childTx, nil
+}
+
 // SignOwnershipProof creates and signs an ownership proof for the given owned
 // asset. The ownership proof consists of a signed virtual packet that spends
-// the asset fully to the NUMS key.
-func (f *AssetWallet) SignOwnershipProof(
-	ownedAsset *asset.Asset) (wire.TxWitness, error) {
+// the asset fully to the NUMS key. If a challenge is given, the witness commits
+// to it.
+func (f *AssetWallet) SignOwnershipProof(ownedAsset *asset.Asset,
+	challenge *[32]byte) (wire.TxWitness, error) {
 
 	outputAsset := ownedAsset.Copy()
 	log.Infof("Generating ownership proof for asset %v", outputAsset.ID())
 
 	vPkt := tappsbt.OwnershipProofPacket(
-		ownedAsset.Copy(), f.cfg.ChainParams,
+		ownedAsset.Copy(), challenge, f.cfg.ChainParams,
 	)
 	err := tapscript.SignVirtualTransaction(
 		vPkt, f.cfg.Signer, f.cfg.TxValidator,
@@ -1610,16 +3584,29 @@ func adjustFundedPsbt(fPkt *tapgarden.FundedPsbt, anchorInputValue int64) {
 	fPkt.ChangeOutputIndex = int32(maxOutputIndex)
 }
 
-// addAnchorPsbtInputs adds anchor information from all inputs to the PSBT
-// packet. This is called after the PSBT has been funded, but before signing.
-func addAnchorPsbtInputs(btcPkt *psbt.Packet, vPkt *tappsbt.VPacket,
+// addAnchorPsbtInputs adds anchor information from all inputs of the given
+// virtual packets to the PSBT packet. Each anchor output is only spent once,
+// even if multiple virtual inputs are anchored in it. This is called after the
+// PSBT has been funded, but before signing.
+func addAnchorPsbtInputs(btcPkt *psbt.Packet, vPkts []*tappsbt.VPacket,
 	feeRate chainfee.SatPerKWeight, params *chaincfg.Params) error {
 
-	for idx := range vPkt.Inputs {
+	var vInputs []*tappsbt.VInput
+	for _, vPkt := range vPkts {
+		vInputs = append(vInputs, vPkt.Inputs...)
+	}
+
+	spentAnchors := fn.NewSet[wire.OutPoint]()
+	for idx := range vInputs {
+		vIn := vInputs[idx]
+		if spentAnchors.Contains(vIn.PrevID.OutPoint) {
+			continue
+		}
+		spentAnchors.Add(vIn.PrevID.OutPoint)
+
 		// With the BIP-0032 information completed, we'll now add the
 		// information as a partial input and also add the input to the
 		// unsigned transaction.
-		vIn := vPkt.Inputs[idx]
 		btcPkt.Inputs = append(btcPkt.Inputs, psbt.PInput{
 			WitnessUtxo: &wire.TxOut{
 				Value:    int64(vIn.Anchor.Value),
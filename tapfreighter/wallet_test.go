@@ -2,17 +2,39 @@ package tapfreighter
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightninglabs/taproot-assets/tapgarden"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/stretchr/testify/require"
 )
 
 // mockCoinLister is a mock implementation of the CoinLister interface.
 type mockCoinLister struct {
 	eligibleCommitments []*AnchoredCommitment
+
+	leased []wire.OutPoint
 }
 
 func (m *mockCoinLister) ListEligibleCoins(
@@ -22,9 +44,10 @@ func (m *mockCoinLister) ListEligibleCoins(
 	return m.eligibleCommitments, nil
 }
 
-func (m *mockCoinLister) LeaseCoins(context.Context, [32]byte, time.Time,
-	...wire.OutPoint) error {
+func (m *mockCoinLister) LeaseCoins(_ context.Context, _ [32]byte,
+	_ time.Time, outpoints ...wire.OutPoint) error {
 
+	m.leased = append(m.leased, outpoints...)
 	return nil
 }
 
@@ -36,6 +59,16 @@ func (m *mockCoinLister) DeleteExpiredLeases(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockCoinLister) ListLeases(context.Context) ([]UTXOLease, error) {
+	return nil, nil
+}
+
+func (m *mockCoinLister) ReleaseLease(context.Context, [32]byte,
+	wire.OutPoint) error {
+
+	return nil
+}
+
 // TestCoinSelection tests that the coin selection logic behaves as expected.
 func TestCoinSelection(t *testing.T) {
 	t.Parallel()
@@ -44,6 +77,8 @@ func TestCoinSelection(t *testing.T) {
 		minTotalAmount      uint64
 		eligibleCommitments []*AnchoredCommitment
 		strategy            MultiCommitmentSelectStrategy
+		tranche             TrancheSelection
+		maxInputs           uint32
 
 		// Result analysis parameters.
 		//
@@ -55,6 +90,42 @@ func TestCoinSelection(t *testing.T) {
 		expectedSomeErr bool
 	}
 
+	// We create two tranches of the same asset group, with the first one
+	// being the older one. The commitments of the newer tranche are listed
+	// first, as the tranches are ordered by their genesis height only.
+	oldGen := asset.Genesis{
+		FirstPrevOut: wire.OutPoint{Index: 1},
+		Tag:          "tranche",
+	}
+	newGen := asset.Genesis{
+		FirstPrevOut: wire.OutPoint{Index: 2},
+		Tag:          "tranche",
+	}
+	oldSmall := &AnchoredCommitment{
+		GenesisBlockHeight: 100,
+		Asset:              &asset.Asset{Genesis: oldGen, Amount: 300},
+	}
+	oldLarge := &AnchoredCommitment{
+		GenesisBlockHeight: 100,
+		Asset:              &asset.Asset{Genesis: oldGen, Amount: 600},
+	}
+	newLarge := &AnchoredCommitment{
+		GenesisBlockHeight: 200,
+		Asset:              &asset.Asset{Genesis: newGen, Amount: 2000},
+	}
+	tranches := func() []*AnchoredCommitment {
+		return []*AnchoredCommitment{newLarge, oldSmall, oldLarge}
+	}
+
+	// A tranche with an unknown genesis height is considered the newest.
+	unknownGen := asset.Genesis{
+		FirstPrevOut: wire.OutPoint{Index: 3},
+		Tag:          "tranche",
+	}
+	unknownSmall := &AnchoredCommitment{
+		Asset: &asset.Asset{Genesis: unknownGen, Amount: 50},
+	}
+
 	testCases := []testCase{
 		// Test that an unknown strategy returns an error.
 		{
@@ -130,6 +201,184 @@ func TestCoinSelection(t *testing.T) {
 				},
 			},
 		},
+
+		// Test that without a tranche preference, the max amount
+		// commitment is selected, regardless of its tranche.
+		{
+			minTotalAmount:           500,
+			eligibleCommitments:      tranches(),
+			strategy:                 PreferMaxAmount,
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				newLarge,
+			},
+		},
+
+		// Test that the oldest tranche is preferred, even if the
+		// newer tranche holds a larger amount.
+		{
+			minTotalAmount:      500,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments:      []*AnchoredCommitment{oldLarge},
+		},
+
+		// Test that the next tranche is used if the preferred tranche
+		// doesn't hold enough, without mixing tranches.
+		{
+			minTotalAmount:      1000,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				newLarge,
+			},
+		},
+
+		// Test that commitments of multiple tranches are selected in
+		// the order of the preference if only their sum holds enough.
+		{
+			minTotalAmount:      2500,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				oldLarge, oldSmall, newLarge,
+			},
+		},
+
+		// Test that the selection fails if all tranches together don't
+		// hold enough.
+		{
+			minTotalAmount:      3000,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			expectedSomeErr: true,
+		},
+
+		// Test that the newest tranche is preferred.
+		{
+			minTotalAmount:      100,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferNewest,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				newLarge,
+			},
+		},
+
+		// Test that a tranche with an unknown genesis height is
+		// considered the newest.
+		{
+			minTotalAmount: 10,
+			eligibleCommitments: append(
+				[]*AnchoredCommitment{unknownSmall},
+				tranches()...,
+			),
+			strategy: PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferNewest,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				unknownSmall,
+			},
+		},
+
+		// Test that the tranche with the given genesis point is
+		// preferred.
+		{
+			minTotalAmount:      100,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference:   TranchePreferGenesisPoint,
+				GenesisPoint: oldGen.FirstPrevOut,
+			},
+			checkSelectedCommitments: true,
+			expectedCommitments:      []*AnchoredCommitment{oldLarge},
+		},
+
+		// Test that a tranche that needs more inputs than allowed is
+		// skipped in favor of the next one.
+		{
+			minTotalAmount:      800,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			maxInputs:                1,
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				newLarge,
+			},
+		},
+
+		// Test that the selection fails if more inputs than allowed
+		// are needed to satisfy the amount.
+		{
+			minTotalAmount:      2500,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			maxInputs:       2,
+			expectedSomeErr: true,
+		},
+
+		// Test that the selection succeeds if the number of needed
+		// inputs is exactly the maximum.
+		{
+			minTotalAmount:      2500,
+			eligibleCommitments: tranches(),
+			strategy:            PreferMaxAmount,
+			tranche: TrancheSelection{
+				Preference: TranchePreferOldest,
+			},
+			maxInputs:                3,
+			checkSelectedCommitments: true,
+			expectedCommitments: []*AnchoredCommitment{
+				oldLarge, oldSmall, newLarge,
+			},
+		},
+
+		// Test that commitments whose amounts overflow when summed up
+		// are rejected instead of wrapping around.
+		{
+			minTotalAmount: math.MaxUint64,
+			eligibleCommitments: []*AnchoredCommitment{
+				{
+					Asset: &asset.Asset{
+						Amount: math.MaxUint64 - 1,
+					},
+				},
+				{
+					Asset: &asset.Asset{
+						Amount: 2,
+					},
+				},
+			},
+			strategy:        PreferMaxAmount,
+			expectedSomeErr: true,
+		},
 	}
 
 	// Execute test cases.
@@ -137,11 +386,11 @@ func TestCoinSelection(t *testing.T) {
 		coinLister := &mockCoinLister{
 			eligibleCommitments: testCase.eligibleCommitments,
 		}
-		coinSelect := NewCoinSelect(coinLister)
+		coinSelect := NewCoinSelect(coinLister, nil, nil, nil)
 
 		resultCommitments, err := coinSelect.selectForAmount(
 			testCase.minTotalAmount, testCase.eligibleCommitments,
-			testCase.strategy,
+			testCase.strategy, testCase.tranche, testCase.maxInputs,
 		)
 
 		// Analyse results.
@@ -163,3 +412,1420 @@ func TestCoinSelection(t *testing.T) {
 		_ = idx
 	}
 }
+
+// heightChainBridge is a ChainBridge that only reports a fixed current height.
+type heightChainBridge struct {
+	ChainBridge
+
+	height uint32
+}
+
+func (h *heightChainBridge) CurrentHeight(context.Context) (uint32, error) {
+	return h.height, nil
+}
+
+// TestCoinSelectionConfirmations tests that assets without the number of
+// confirmations required by the confirmation policy aren't selected, and that
+// a distinct error is returned if only those could satisfy the amount.
+func TestCoinSelectionConfirmations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newCommitment := func(amt uint64, height uint32) *AnchoredCommitment {
+		return &AnchoredCommitment{
+			AnchorPoint:       test.RandOp(t),
+			AnchorBlockHeight: height,
+			Asset: &asset.Asset{
+				Amount: amt,
+			},
+		}
+	}
+
+	// At a height of 100, the first commitment has 6 confirmations, the
+	// second one 2 and the last one is unconfirmed.
+	deep := newCommitment(500, 95)
+	shallow := newCommitment(1000, 99)
+	unconfirmed := newCommitment(2000, 0)
+
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{
+			deep, shallow, unconfirmed,
+		},
+	}
+	policy, err := ParseConfirmationPolicy(6, nil)
+	require.NoError(t, err)
+
+	coinSelect := NewCoinSelect(
+		coinLister, policy, nil, &heightChainBridge{height: 100},
+	)
+
+	// The deep commitment alone can satisfy a small amount.
+	selected, _, err := coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 500,
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{deep}, selected)
+
+	// A larger amount is covered by the balance, but not by the assets
+	// with enough confirmations.
+	_, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 1500,
+	}, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrInsufficientConfirmations)
+
+	// An amount that exceeds the balance is still reported as such.
+	_, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 5000,
+	}, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+
+	// Without a confirmation requirement, unconfirmed assets can be
+	// selected as well.
+	coinSelect = NewCoinSelect(coinLister, nil, nil, nil)
+	selected, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 1500,
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{unconfirmed}, selected)
+}
+
+// TestCoinSelectionNoLease tests that selected coins are only leased if the
+// constraints don't ask otherwise.
+func TestCoinSelectionNoLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	coin := &AnchoredCommitment{
+		AnchorPoint: test.RandOp(t),
+		Asset: &asset.Asset{
+			Amount: 500,
+		},
+	}
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{coin},
+	}
+	coinSelect := NewCoinSelect(coinLister, nil, nil, nil)
+
+	selected, _, err := coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt:  500,
+		NoLease: true,
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{coin}, selected)
+	require.Empty(t, coinLister.leased)
+
+	selected, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 500,
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{coin}, selected)
+	require.Equal(t, []wire.OutPoint{coin.AnchorPoint}, coinLister.leased)
+}
+
+// TestCoinSelectionOutpoints tests that the coin selection can be restricted to
+// explicitly chosen inputs.
+func TestCoinSelectionOutpoints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newCoin := func(amt uint64) *AnchoredCommitment {
+		return &AnchoredCommitment{
+			AnchorPoint: test.RandOp(t),
+			Asset: &asset.Asset{
+				Amount: amt,
+			},
+		}
+	}
+	small, medium, large := newCoin(100), newCoin(300), newCoin(2000)
+
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{
+			small, medium, large,
+		},
+	}
+	coinSelect := NewCoinSelect(coinLister, nil, nil, nil)
+
+	// Without a restriction, the largest coin is selected.
+	selected, _, err := coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt:  350,
+		NoLease: true,
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{large}, selected)
+
+	// With a restriction, only the chosen coins are selected.
+	selected, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt:  350,
+		NoLease: true,
+		Outpoints: []wire.OutPoint{
+			small.AnchorPoint, medium.AnchorPoint,
+		},
+	}, PreferMaxAmount)
+	require.NoError(t, err)
+	require.Equal(t, []*AnchoredCommitment{medium, small}, selected)
+
+	// If the chosen coins don't hold enough, the shortfall is reported.
+	_, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt:    500,
+		NoLease:   true,
+		Outpoints: []wire.OutPoint{small.AnchorPoint},
+	}, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+	require.ErrorContains(t, err, "400 units short of the required 500")
+
+	// An outpoint that doesn't hold an eligible coin is rejected.
+	_, _, err = coinSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt:  50,
+		NoLease: true,
+		Outpoints: []wire.OutPoint{
+			small.AnchorPoint, test.RandOp(t),
+		},
+	}, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrIneligibleInput)
+}
+
+// TestCoinSelectionRelaxation tests that the coin selection constraints are
+// only relaxed if configured, in the configured order, and that the applied
+// relaxations are reported.
+func TestCoinSelectionRelaxation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newCommitment := func(amt uint64, height uint32) *AnchoredCommitment {
+		return &AnchoredCommitment{
+			AnchorPoint:       test.RandOp(t),
+			AnchorBlockHeight: height,
+			Asset: &asset.Asset{
+				Amount: amt,
+			},
+		}
+	}
+
+	// At a height of 100, the first three commitments have 6
+	// confirmations and the last one 2.
+	coinLister := &mockCoinLister{
+		eligibleCommitments: []*AnchoredCommitment{
+			newCommitment(500, 95), newCommitment(400, 95),
+			newCommitment(300, 95), newCommitment(1000, 99),
+		},
+	}
+	confPolicy, err := ParseConfirmationPolicy(6, nil)
+	require.NoError(t, err)
+	chainBridge := &heightChainBridge{height: 100}
+
+	_, err = ParseCoinRelaxationPolicy([]string{"fees"}, 1, 0)
+	require.ErrorContains(t, err, "unknown coin selection relaxation")
+	_, err = ParseCoinRelaxationPolicy(
+		[]string{"maxinputs", "maxinputs"}, 1, 0,
+	)
+	require.ErrorContains(t, err, "duplicate")
+
+	// Without a relaxation policy, coin selection stays strict.
+	noRelaxPolicy, err := ParseCoinRelaxationPolicy(nil, 1, 0)
+	require.NoError(t, err)
+	require.Nil(t, noRelaxPolicy)
+
+	strictSelect := NewCoinSelect(
+		coinLister, confPolicy, noRelaxPolicy, chainBridge,
+	)
+	constraints := CommitmentConstraints{
+		MinAmt:    1100,
+		MaxInputs: 2,
+	}
+	_, _, err = strictSelect.SelectCoins(ctx, constraints, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrMaxInputsExceeded)
+
+	// Relaxing the maximum number of inputs is enough to select the deep
+	// commitments, so the confirmations don't need to be relaxed.
+	relaxPolicy, err := ParseCoinRelaxationPolicy(
+		[]string{"maxinputs", "confirmations"}, 1, 3,
+	)
+	require.NoError(t, err)
+
+	relaxedSelect := NewCoinSelect(
+		coinLister, confPolicy, relaxPolicy, chainBridge,
+	)
+	selected, relaxations, err := relaxedSelect.SelectCoins(
+		ctx, constraints, PreferMaxAmount,
+	)
+	require.NoError(t, err)
+	require.Len(t, selected, 3)
+	require.Equal(t, []CoinRelaxation{{
+		Step:      RelaxMaxInputs,
+		Required:  2,
+		RelaxedTo: 3,
+	}}, relaxations)
+
+	// With the confirmations relaxed first, the shallow commitment can be
+	// selected within the strict maximum number of inputs.
+	relaxPolicy, err = ParseCoinRelaxationPolicy(
+		[]string{"confirmations", "maxinputs"}, 1, 3,
+	)
+	require.NoError(t, err)
+
+	relaxedSelect = NewCoinSelect(
+		coinLister, confPolicy, relaxPolicy, chainBridge,
+	)
+	selected, relaxations, err = relaxedSelect.SelectCoins(
+		ctx, constraints, PreferMaxAmount,
+	)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	require.Equal(t, []CoinRelaxation{{
+		Step:      RelaxConfirmations,
+		Required:  6,
+		RelaxedTo: 1,
+	}}, relaxations)
+
+	// A maximum number of inputs that was requested explicitly is never
+	// relaxed, so only the confirmations can be.
+	strictConstraints := constraints
+	strictConstraints.StrictMaxInputs = true
+
+	relaxPolicy, err = ParseCoinRelaxationPolicy(
+		[]string{"maxinputs", "confirmations"}, 1, 3,
+	)
+	require.NoError(t, err)
+
+	relaxedSelect = NewCoinSelect(
+		coinLister, confPolicy, relaxPolicy, chainBridge,
+	)
+	selected, relaxations, err = relaxedSelect.SelectCoins(
+		ctx, strictConstraints, PreferMaxAmount,
+	)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	require.Equal(t, []CoinRelaxation{{
+		Step:      RelaxConfirmations,
+		Required:  6,
+		RelaxedTo: 1,
+	}}, relaxations)
+
+	relaxPolicy, err = ParseCoinRelaxationPolicy(
+		[]string{"maxinputs"}, 1, 3,
+	)
+	require.NoError(t, err)
+
+	relaxedSelect = NewCoinSelect(
+		coinLister, confPolicy, relaxPolicy, chainBridge,
+	)
+	_, _, err = relaxedSelect.SelectCoins(
+		ctx, strictConstraints, PreferMaxAmount,
+	)
+	require.ErrorIs(t, err, ErrMaxInputsExceeded)
+
+	// An amount that exceeds the balance can't be satisfied by relaxing
+	// any constraint.
+	_, _, err = relaxedSelect.SelectCoins(ctx, CommitmentConstraints{
+		MinAmt: 5000,
+	}, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+
+	// The same goes for relaxing a constraint too little.
+	relaxPolicy, err = ParseCoinRelaxationPolicy(
+		[]string{"confirmations"}, 3, 0,
+	)
+	require.NoError(t, err)
+
+	relaxedSelect = NewCoinSelect(
+		coinLister, confPolicy, relaxPolicy, chainBridge,
+	)
+	_, _, err = relaxedSelect.SelectCoins(ctx, constraints, PreferMaxAmount)
+	require.ErrorIs(t, err, ErrMaxInputsExceeded)
+}
+
+// TestParseTrancheSelection tests that tranche selections are parsed
+// correctly.
+func TestParseTrancheSelection(t *testing.T) {
+	t.Parallel()
+
+	sel, err := ParseTrancheSelection("", "")
+	require.NoError(t, err)
+	require.Equal(t, TranchePreferNone, sel.Preference)
+
+	sel, err = ParseTrancheSelection("newest", "")
+	require.NoError(t, err)
+	require.Equal(t, TranchePreferNewest, sel.Preference)
+
+	op := wire.OutPoint{Index: 3}
+	sel, err = ParseTrancheSelection("genesispoint", op.String())
+	require.NoError(t, err)
+	require.Equal(t, TranchePreferGenesisPoint, sel.Preference)
+	require.Equal(t, op, sel.GenesisPoint)
+
+	_, err = ParseTrancheSelection("genesispoint", "")
+	require.Error(t, err)
+
+	_, err = ParseTrancheSelection("oldest", op.String())
+	require.Error(t, err)
+
+	_, err = ParseTrancheSelection("random", "")
+	require.Error(t, err)
+}
+
+// TestSelectionAssetID tests that grouped assets are only selected by their
+// group key alone if a tranche preference is set and no recipient expects the
+// exact asset ID, and that the funding descriptor is updated to the selected
+// tranche.
+func TestSelectionAssetID(t *testing.T) {
+	t.Parallel()
+
+	gen := asset.RandGenesis(t, asset.Normal)
+	otherGen := asset.RandGenesis(t, asset.Normal)
+	groupKey := test.RandPubKey(t)
+
+	ungrouped := &tapscript.FundingDescriptor{ID: gen.ID(), Amount: 1}
+	grouped := &tapscript.FundingDescriptor{
+		ID:       gen.ID(),
+		GroupKey: groupKey,
+		Amount:   1,
+	}
+
+	interactive := &tappsbt.VPacket{
+		Outputs: []*tappsbt.VOutput{{
+			Type: tappsbt.TypeSplitRoot,
+		}, {
+			Type:        tappsbt.TypeSimple,
+			Interactive: true,
+		}},
+	}
+	toAddress := &tappsbt.VPacket{
+		Outputs: []*tappsbt.VOutput{{
+			Type: tappsbt.TypeSplitRoot,
+		}, {
+			Type: tappsbt.TypeSimple,
+		}},
+	}
+
+	none := TrancheSelection{}
+	oldest := TrancheSelection{Preference: TranchePreferOldest}
+
+	require.Equal(
+		t, &ungrouped.ID, selectionAssetID(ungrouped, nil, oldest),
+	)
+	require.Equal(
+		t, &ungrouped.ID,
+		selectionAssetID(ungrouped, interactive, oldest),
+	)
+	require.Nil(t, selectionAssetID(grouped, nil, oldest))
+	require.Nil(t, selectionAssetID(grouped, interactive, oldest))
+	require.Equal(
+		t, &grouped.ID, selectionAssetID(grouped, toAddress, oldest),
+	)
+
+	// Without a tranche preference, the exact asset ID is selected.
+	require.Equal(t, &grouped.ID, selectionAssetID(grouped, nil, none))
+	require.Equal(
+		t, &grouped.ID, selectionAssetID(grouped, interactive, none),
+	)
+
+	// The descriptor is only replaced if another tranche was selected.
+	sameTranche := []*AnchoredCommitment{{
+		Asset: &asset.Asset{Genesis: gen, Amount: 1},
+	}}
+	trancheDesc, err := selectedTranche(grouped, sameTranche)
+	require.NoError(t, err)
+	require.Same(t, grouped, trancheDesc)
+
+	otherTranche := []*AnchoredCommitment{{
+		Asset: &asset.Asset{Genesis: otherGen, Amount: 1},
+	}}
+	trancheDesc, err = selectedTranche(grouped, otherTranche)
+	require.NoError(t, err)
+	require.Equal(t, otherGen.ID(), trancheDesc.ID)
+	require.Equal(t, grouped.GroupKey, trancheDesc.GroupKey)
+	require.Equal(t, grouped.Amount, trancheDesc.Amount)
+	require.Equal(t, gen.ID(), grouped.ID)
+
+	// Inputs of multiple tranches can't be spent in a single virtual
+	// transaction.
+	_, err = selectedTranche(
+		grouped, append(sameTranche, otherTranche...),
+	)
+	require.ErrorIs(t, err, ErrMultipleTranches)
+	require.ErrorContains(t, err, otherGen.ID().String())
+}
+
+// recordingCoinSelector is a coin selector that records the constraints it is
+// called with, without selecting any coins.
+type recordingCoinSelector struct {
+	constraints []CommitmentConstraints
+}
+
+func (r *recordingCoinSelector) SelectCoins(_ context.Context,
+	constraints CommitmentConstraints,
+	_ MultiCommitmentSelectStrategy) ([]*AnchoredCommitment,
+	[]CoinRelaxation, error) {
+
+	r.constraints = append(r.constraints, constraints)
+
+	return nil, nil, ErrMatchingAssetsNotFound
+}
+
+func (r *recordingCoinSelector) ReleaseCoins(context.Context,
+	...wire.OutPoint) error {
+
+	return nil
+}
+
+// TestFundingTrancheSelection tests that the inputs of a grouped asset are
+// only selected from its exact asset ID by default, and that the tranche
+// preference can be set per request.
+func TestFundingTrancheSelection(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	gen := asset.RandGenesis(t, asset.Normal)
+	grouped := &tapscript.FundingDescriptor{
+		ID:       gen.ID(),
+		GroupKey: test.RandPubKey(t),
+		Amount:   1,
+	}
+
+	none := TrancheSelection{}
+	oldest := TrancheSelection{Preference: TranchePreferOldest}
+
+	fundBurn := func(cfgTranche TrancheSelection,
+		optFuncs ...FundingOption) CommitmentConstraints {
+
+		selector := &recordingCoinSelector{}
+		wallet := NewAssetWallet(&WalletConfig{
+			CoinSelector:     selector,
+			TrancheSelection: cfgTranche,
+		})
+
+		_, err := wallet.FundBurn(ctx, grouped, optFuncs...)
+		require.ErrorIs(t, err, ErrMatchingAssetsNotFound)
+		require.Len(t, selector.constraints, 1)
+
+		return selector.constraints[0]
+	}
+
+	// With the default config, only the exact asset ID of the grouped
+	// asset is selected.
+	constraints := fundBurn(none)
+	require.Equal(t, &grouped.ID, constraints.AssetID)
+	require.Equal(t, grouped.GroupKey, constraints.GroupKey)
+	require.Equal(t, none, constraints.Tranche)
+
+	// A configured preference selects the inputs by group key.
+	constraints = fundBurn(oldest)
+	require.Nil(t, constraints.AssetID)
+	require.Equal(t, oldest, constraints.Tranche)
+
+	// The preference of the request overrides the configured one.
+	constraints = fundBurn(none, WithTrancheSelection(oldest))
+	require.Nil(t, constraints.AssetID)
+	require.Equal(t, oldest, constraints.Tranche)
+
+	constraints = fundBurn(oldest, WithTrancheSelection(none))
+	require.Equal(t, &grouped.ID, constraints.AssetID)
+	require.Equal(t, none, constraints.Tranche)
+}
+
+// keySpendSignWallet is a wallet anchor that adds a dummy key spend signature
+// to all inputs of the PSBTs it signs.
+type keySpendSignWallet struct {
+	WalletAnchor
+}
+
+func (w *keySpendSignWallet) SignPsbt(_ context.Context,
+	packet *psbt.Packet) (*psbt.Packet, error) {
+
+	for idx := range packet.Inputs {
+		packet.Inputs[idx].TaprootKeySpendSig = make(
+			[]byte, schnorr.SignatureSize,
+		)
+	}
+
+	return packet, nil
+}
+
+// TestBumpAnchorTxFee tests that the replacement of an anchor TX keeps all
+// inputs and outputs, and only deducts the additional fee from the change
+// output.
+func TestBumpAnchorTxFee(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	signer := &keySpendSignWallet{}
+	wallet := NewAssetWallet(&WalletConfig{
+		Wallet: signer,
+	})
+
+	p2trScript := func() []byte {
+		script, err := tapscript.PayToTaprootScript(test.RandPubKey(t))
+		require.NoError(t, err)
+
+		return script
+	}
+
+	newAnchorTx := func(changeIdx int32) *AnchorTransaction {
+		tx := wire.NewMsgTx(2)
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: test.RandOp(t)})
+		tx.AddTxOut(&wire.TxOut{Value: 1_000, PkScript: p2trScript()})
+		tx.AddTxOut(&wire.TxOut{Value: 8_000, PkScript: p2trScript()})
+
+		pkt, err := psbt.NewFromUnsignedTx(tx)
+		require.NoError(t, err)
+		pkt.Inputs[0].WitnessUtxo = &wire.TxOut{
+			Value:    10_000,
+			PkScript: p2trScript(),
+		}
+
+		signPkt, err := copyPsbt(pkt)
+		require.NoError(t, err)
+		signPkt, err = signer.SignPsbt(ctx, signPkt)
+		require.NoError(t, err)
+		require.NoError(t, psbt.MaybeFinalizeAll(signPkt))
+		finalTx, err := psbt.Extract(signPkt)
+		require.NoError(t, err)
+
+		return &AnchorTransaction{
+			FundedPsbt: &tapgarden.FundedPsbt{
+				Pkt:               pkt,
+				ChangeOutputIndex: changeIdx,
+			},
+			FinalTx:   finalTx,
+			ChainFees: 1_000,
+		}
+	}
+
+	anchorTx := newAnchorTx(1)
+	weight := blockchain.GetTransactionWeight(
+		btcutil.NewTx(anchorTx.FinalTx),
+	)
+
+	// The replacement needs to pay more than the original.
+	_, err := wallet.BumpAnchorTxFee(
+		ctx, anchorTx, chainfee.FeePerKwFloor,
+	)
+	require.ErrorIs(t, err, ErrFeeRateTooLow)
+
+	feeRate := chainfee.SatPerKWeight(10_000)
+	newFee := int64(feeRate.FeeForWeight(weight))
+	bumped, err := wallet.BumpAnchorTxFee(ctx, anchorTx, feeRate)
+	require.NoError(t, err)
+
+	require.Equal(t, newFee, bumped.ChainFees)
+	require.Equal(t, feeRate, bumped.TargetFeeRate)
+	require.NotEqual(t, anchorTx.FinalTx.TxHash(), bumped.FinalTx.TxHash())
+
+	oldTx, newTx := anchorTx.FinalTx, bumped.FinalTx
+	require.Equal(
+		t, oldTx.TxIn[0].PreviousOutPoint,
+		newTx.TxIn[0].PreviousOutPoint,
+	)
+	require.NotEmpty(t, newTx.TxIn[0].Witness)
+	require.Equal(t, oldTx.TxOut[0], newTx.TxOut[0])
+	require.Equal(t, oldTx.TxOut[1].PkScript, newTx.TxOut[1].PkScript)
+	require.Equal(
+		t, oldTx.TxOut[1].Value-(newFee-1_000), newTx.TxOut[1].Value,
+	)
+
+	// The replacement can be bumped again.
+	_, err = wallet.BumpAnchorTxFee(ctx, bumped, feeRate+1_000)
+	require.NoError(t, err)
+
+	// The additional fee can't exceed the change output.
+	_, err = wallet.BumpAnchorTxFee(ctx, anchorTx, feeRate*100)
+	require.ErrorContains(t, err, "exceeds change amount")
+
+	// Without a change output, there's nothing to pay the fee from.
+	_, err = wallet.BumpAnchorTxFee(ctx, newAnchorTx(-1), feeRate)
+	require.ErrorContains(t, err, "no change output")
+}
+
+// cpfpWallet is a wallet anchor that funds PSBTs with a single input and a
+// change output that pays the fee for the funded TX at the requested fee rate.
+type cpfpWallet struct {
+	keySpendSignWallet
+
+	addr btcutil.Address
+
+	fundInput wire.OutPoint
+
+	signErr error
+
+	released []wire.OutPoint
+}
+
+func (w *cpfpWallet) NextAddr(context.Context) (btcutil.Address, error) {
+	return w.addr, nil
+}
+
+func (w *cpfpWallet) FundPsbt(_ context.Context, pkt *psbt.Packet, _ uint32,
+	feeRate chainfee.SatPerKWeight) (tapgarden.FundedPsbt, error) {
+
+	const inputValue = 100_000
+
+	var weightEstimator input.TxWeightEstimator
+	weightEstimator.AddTaprootKeySpendInput(txscript.SigHashDefault)
+	weightEstimator.AddP2TROutput()
+	weightEstimator.AddP2TROutput()
+	fee := feeRate.FeeForWeight(int64(weightEstimator.Weight()))
+
+	pkScript, err := txscript.PayToAddrScript(w.addr)
+	if err != nil {
+		return tapgarden.FundedPsbt{}, err
+	}
+
+	pkt.UnsignedTx.AddTxIn(&wire.TxIn{PreviousOutPoint: w.fundInput})
+	pkt.Inputs = append(pkt.Inputs, psbt.PInput{
+		WitnessUtxo: &wire.TxOut{
+			Value:    inputValue,
+			PkScript: pkScript,
+		},
+	})
+	pkt.UnsignedTx.AddTxOut(&wire.TxOut{
+		Value: inputValue - pkt.UnsignedTx.TxOut[0].Value -
+			int64(fee),
+		PkScript: pkScript,
+	})
+	pkt.Outputs = append(pkt.Outputs, psbt.POutput{})
+
+	return tapgarden.FundedPsbt{
+		Pkt:               pkt,
+		ChangeOutputIndex: 1,
+		ChainFees:         int64(fee),
+		LockedUTXOs:       []wire.OutPoint{w.fundInput},
+	}, nil
+}
+
+func (w *cpfpWallet) SignPsbt(ctx context.Context,
+	pkt *psbt.Packet) (*psbt.Packet, error) {
+
+	if w.signErr != nil {
+		return nil, w.signErr
+	}
+
+	return w.keySpendSignWallet.SignPsbt(ctx, pkt)
+}
+
+func (w *cpfpWallet) ReleaseOutput(_ context.Context, op wire.OutPoint) error {
+	w.released = append(w.released, op)
+	return nil
+}
+
+// TestCreateFeeBumpChild tests that the child TX spends the fee bump anchor
+// output of the anchor TX and pays enough fees for the package of both TXs to
+// reach the target fee rate.
+func TestCreateFeeBumpChild(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	addr, err := btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(test.RandPubKey(t)),
+		&chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+
+	anchorWallet := &cpfpWallet{
+		addr:      addr,
+		fundInput: test.RandOp(t),
+	}
+	wallet := NewAssetWallet(&WalletConfig{
+		Wallet:      anchorWallet,
+		ChainParams: &address.RegressionNetTap,
+	})
+
+	internalKey := keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+		KeyLocator: keychain.KeyLocator{
+			Family: asset.TaprootAssetsKeyFamily,
+			Index:  3,
+		},
+	}
+	anchorPkScript, err := tapscript.PayToTaprootScript(
+		txscript.ComputeTaprootKeyNoScript(internalKey.PubKey),
+	)
+	require.NoError(t, err)
+	feeBumpAnchor := &FeeBumpAnchor{
+		OutputIndex: 1,
+		InternalKey: internalKey,
+		PkScript:    anchorPkScript,
+	}
+
+	assetPkScript, err := tapscript.PayToTaprootScript(test.RandPubKey(t))
+	require.NoError(t, err)
+	anchorTx := wire.NewMsgTx(2)
+	anchorTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: test.RandOp(t),
+		Witness:          wire.TxWitness{make([]byte, 64)},
+	})
+	anchorTx.AddTxOut(&wire.TxOut{Value: 1_000, PkScript: assetPkScript})
+	anchorTx.AddTxOut(&wire.TxOut{
+		Value:    FeeBumpAnchorValue,
+		PkScript: anchorPkScript,
+	})
+	const anchorTxFee = 150
+
+	// An output that isn't the fee bump anchor can't be spent.
+	_, err = wallet.CreateFeeBumpChild(
+		ctx, anchorTx, anchorTxFee, &FeeBumpAnchor{
+			OutputIndex: 0,
+			PkScript:    anchorPkScript,
+		}, chainfee.FeePerKwFloor,
+	)
+	require.ErrorContains(t, err, "no fee bump anchor output")
+
+	// If the anchor TX already pays the fee rate, there's nothing to do.
+	_, err = wallet.CreateFeeBumpChild(
+		ctx, anchorTx, anchorTxFee, feeBumpAnchor,
+		chainfee.FeePerKwFloor,
+	)
+	require.ErrorIs(t, err, ErrFeeRateTooLow)
+
+	feeRate := chainfee.SatPerKWeight(5_000)
+	childTx, err := wallet.CreateFeeBumpChild(
+		ctx, anchorTx, anchorTxFee, feeBumpAnchor, feeRate,
+	)
+	require.NoError(t, err)
+
+	// The fee bump anchor output is spent in addition to the wallet
+	// input.
+	require.Len(t, childTx.TxIn, 2)
+	require.Equal(
+		t, anchorWallet.fundInput, childTx.TxIn[0].PreviousOutPoint,
+	)
+	require.Equal(t, wire.OutPoint{
+		Hash:  anchorTx.TxHash(),
+		Index: 1,
+	}, childTx.TxIn[1].PreviousOutPoint)
+	require.NotEmpty(t, childTx.TxIn[1].Witness)
+
+	// Together, both TXs pay at least the target fee rate. We allow for
+	// rounding of the individual fees.
+	var childOutputValue int64
+	for _, txOut := range childTx.TxOut {
+		childOutputValue += txOut.Value
+	}
+	childFee := 100_000 + FeeBumpAnchorValue - childOutputValue
+	packageWeight := blockchain.GetTransactionWeight(
+		btcutil.NewTx(anchorTx),
+	) + blockchain.GetTransactionWeight(btcutil.NewTx(childTx))
+	require.GreaterOrEqual(
+		t, anchorTxFee+childFee,
+		int64(feeRate.FeeForWeight(packageWeight))-2,
+	)
+	require.Less(
+		t, anchorTxFee+childFee,
+		int64(feeRate.FeeForWeight(packageWeight))+10,
+	)
+	require.Empty(t, anchorWallet.released)
+
+	// If the child can't be signed, the wallet inputs are released again.
+	anchorWallet.signErr = fmt.Errorf("unable to sign")
+	_, err = wallet.CreateFeeBumpChild(
+		ctx, anchorTx, anchorTxFee, feeBumpAnchor, feeRate,
+	)
+	require.ErrorContains(t, err, "unable to sign")
+	require.Equal(
+		t, []wire.OutPoint{anchorWallet.fundInput},
+		anchorWallet.released,
+	)
+}
+
+// TestCompleteAnchorTx tests that an externally signed anchor packet is only
+// completed if it commits to the virtual transactions and the change output
+// index is either -1 or refers to one of its outputs.
+func TestCompleteAnchorTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	signer := &keySpendSignWallet{}
+	wallet := NewAssetWallet(&WalletConfig{
+		Wallet: signer,
+	})
+
+	p2trScript := func() []byte {
+		script, err := tapscript.PayToTaprootScript(test.RandPubKey(t))
+		require.NoError(t, err)
+
+		return script
+	}
+
+	// We'll spend a single asset fully back to ourselves, so the virtual
+	// packet only has a split root output.
+	inputAsset := asset.RandAsset(t, asset.Normal)
+	inputCommitment, err := commitment.FromAssets(inputAsset)
+	require.NoError(t, err)
+
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: asset.PrevID{
+				OutPoint: test.RandOp(t),
+				ID:       inputAsset.ID(),
+				ScriptKey: asset.ToSerialized(
+					inputAsset.ScriptKey.PubKey,
+				),
+			},
+		}},
+		Outputs: []*tappsbt.VOutput{{
+			Type:                    tappsbt.TypeSplitRoot,
+			Interactive:             true,
+			AnchorOutputIndex:       0,
+			AnchorOutputInternalKey: test.RandPubKey(t),
+			Asset:                   inputAsset.Copy(),
+		}},
+		ChainParams: &address.RegressionNetTap,
+	}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+
+	params := &AnchorVTxnsParams{
+		VPkts: []*tappsbt.VPacket{vPkt},
+		InputCommitments: []tappsbt.InputCommitments{{
+			0: inputCommitment,
+		}},
+	}
+
+	// The anchor packet is funded by a single wallet input and commits to
+	// the virtual transaction in its only output.
+	anchorPkt, err := tapscript.CreateAnchorTx(vPkt.Outputs)
+	require.NoError(t, err)
+	anchorPkt.UnsignedTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: test.RandOp(t),
+	})
+	anchorPkt.Inputs = append(anchorPkt.Inputs, psbt.PInput{
+		WitnessUtxo: &wire.TxOut{
+			Value:    10_000,
+			PkScript: p2trScript(),
+		},
+	})
+	_, err = commitVirtualPackets(anchorPkt, params)
+	require.NoError(t, err)
+
+	// Completing the anchor TX finalizes the signed packet, so each
+	// attempt needs its own copy.
+	signedPkt := func() *psbt.Packet {
+		pkt, err := copyPsbt(anchorPkt)
+		require.NoError(t, err)
+		pkt, err = signer.SignPsbt(ctx, pkt)
+		require.NoError(t, err)
+
+		return pkt
+	}
+
+	anchorTx, err := wallet.CompleteAnchorTx(params, signedPkt(), 0, nil)
+	require.NoError(t, err)
+	require.Equal(
+		t, anchorPkt.UnsignedTx.TxHash(), anchorTx.FinalTx.TxHash(),
+	)
+	require.EqualValues(
+		t, 10_000-tapscript.DummyAmtSats, anchorTx.ChainFees,
+	)
+	require.Contains(t, anchorTx.OutputCommitments, uint32(0))
+
+	// A packet without a change output is valid too.
+	anchorTx, err = wallet.CompleteAnchorTx(params, signedPkt(), -1, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, -1, anchorTx.FundedPsbt.ChangeOutputIndex)
+
+	// Any other change output index must refer to one of the outputs.
+	numOutputs := int32(len(anchorPkt.UnsignedTx.TxOut))
+	for _, changeIdx := range []int32{-2, math.MinInt32, numOutputs} {
+		_, err := wallet.CompleteAnchorTx(
+			params, signedPkt(), changeIdx, nil,
+		)
+		require.ErrorContains(t, err, "invalid change output index")
+	}
+
+	// A signed packet with an output that doesn't commit to the virtual
+	// transaction is rejected.
+	tamperedPkt := signedPkt()
+	tamperedPkt.UnsignedTx.TxOut[0].PkScript = p2trScript()
+	_, err = wallet.CompleteAnchorTx(params, tamperedPkt, 0, nil)
+	require.ErrorContains(
+		t, err, "anchor transaction doesn't commit to the virtual "+
+			"transactions",
+	)
+}
+
+// TestEstimateAnchorTxFee tests that the estimated anchor transaction spends
+// each asset anchor once and creates each anchor output once, plus the wallet's
+// funding input and change output and the optional fee bump anchor.
+func TestEstimateAnchorTxFee(t *testing.T) {
+	t.Parallel()
+
+	sharedAnchor := test.RandOp(t)
+	vPkt := &tappsbt.VPacket{
+		Inputs: []*tappsbt.VInput{{
+			PrevID: asset.PrevID{OutPoint: sharedAnchor},
+		}, {
+			PrevID: asset.PrevID{OutPoint: sharedAnchor},
+		}, {
+			PrevID: asset.PrevID{OutPoint: test.RandOp(t)},
+		}},
+		Outputs: []*tappsbt.VOutput{{
+			AnchorOutputIndex: 0,
+		}, {
+			AnchorOutputIndex: 1,
+		}, {
+			AnchorOutputIndex: 1,
+		}},
+	}
+	feeRate := chainfee.SatPerKWeight(2_500)
+
+	expectedFee := func(numOutputs int) btcutil.Amount {
+		var weightEstimator input.TxWeightEstimator
+		for i := 0; i < 3; i++ {
+			weightEstimator.AddTaprootKeySpendInput(
+				txscript.SigHashDefault,
+			)
+		}
+		for i := 0; i < numOutputs; i++ {
+			weightEstimator.AddP2TROutput()
+		}
+
+		return feeRate.FeeForWeight(int64(weightEstimator.Weight()))
+	}
+
+	vPkts := []*tappsbt.VPacket{vPkt}
+	require.Equal(
+		t, expectedFee(3), estimateAnchorTxFee(vPkts, feeRate, false),
+	)
+	require.Equal(
+		t, expectedFee(4), estimateAnchorTxFee(vPkts, feeRate, true),
+	)
+}
+
+// importWallet is a wallet anchor that records the keys of all Taproot outputs
+// imported into it.
+type importWallet struct {
+	WalletAnchor
+
+	imported []*btcec.PublicKey
+}
+
+func (w *importWallet) ImportTaprootOutput(_ context.Context,
+	pub *btcec.PublicKey) (btcutil.Address, error) {
+
+	w.imported = append(w.imported, pub)
+
+	return btcutil.NewAddressTaproot(
+		schnorr.SerializePubKey(pub), &chaincfg.RegressionNetParams,
+	)
+}
+
+// staticKeyRing is a key ring that always derives the same key.
+type staticKeyRing struct {
+	KeyRing
+
+	keyDesc keychain.KeyDescriptor
+}
+
+func (k *staticKeyRing) DeriveNextKey(context.Context,
+	keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	return k.keyDesc, nil
+}
+
+// TestAddFeeBumpAnchor tests that the reserved fee bump anchor output is a
+// wallet owned BIP-0086 output that is appended to the template anchor packet,
+// and that it is covered by the exclusion proofs of the transfer.
+func TestAddFeeBumpAnchor(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	anchorWallet := &importWallet{}
+	keyRing := &staticKeyRing{
+		keyDesc: keychain.KeyDescriptor{
+			PubKey: test.RandPubKey(t),
+			KeyLocator: keychain.KeyLocator{
+				Family: asset.TaprootAssetsKeyFamily,
+				Index:  7,
+			},
+		},
+	}
+	wallet := NewAssetWallet(&WalletConfig{
+		Wallet:      anchorWallet,
+		KeyRing:     keyRing,
+		ChainParams: &address.RegressionNetTap,
+	})
+
+	internalKey := test.RandPubKey(t)
+	pkt, err := tapscript.CreateAnchorTx([]*tappsbt.VOutput{{
+		AnchorOutputIndex:       0,
+		AnchorOutputInternalKey: internalKey,
+	}})
+	require.NoError(t, err)
+
+	feeBumpAnchor, err := wallet.addFeeBumpAnchor(ctx, pkt)
+	require.NoError(t, err)
+
+	// The fee bump anchor is added after the asset carrying output.
+	require.EqualValues(t, 1, feeBumpAnchor.OutputIndex)
+	require.Len(t, pkt.UnsignedTx.TxOut, 2)
+	require.Len(t, pkt.Outputs, 2)
+
+	txOut := pkt.UnsignedTx.TxOut[1]
+	require.EqualValues(t, FeeBumpAnchorValue, txOut.Value)
+	require.Equal(t, feeBumpAnchor.PkScript, txOut.PkScript)
+
+	// The output must be a BIP-0086 output of the derived key that was
+	// imported into the wallet.
+	outputKey := txscript.ComputeTaprootKeyNoScript(
+		feeBumpAnchor.InternalKey.PubKey,
+	)
+	pkScript, err := tapscript.PayToTaprootScript(outputKey)
+	require.NoError(t, err)
+	require.Equal(t, pkScript, txOut.PkScript)
+	require.Len(t, anchorWallet.imported, 1)
+	require.True(t, anchorWallet.imported[0].IsEqual(outputKey))
+
+	pOut := pkt.Outputs[1]
+	require.Equal(
+		t, schnorr.SerializePubKey(feeBumpAnchor.InternalKey.PubKey),
+		pOut.TaprootInternalKey,
+	)
+	require.Equal(t, keyRing.keyDesc, feeBumpAnchor.InternalKey)
+	require.Len(t, pOut.Bip32Derivation, 1)
+	require.Len(t, pOut.TaprootBip32Derivation, 1)
+
+	// Because the internal key is declared, the output gets a BIP-0086
+	// exclusion proof like any other P2TR output of the anchor TX.
+	var baseProof proof.BaseProofParams
+	err = proof.AddExclusionProofs(&baseProof, pkt, func(idx uint32) bool {
+		return idx == 0
+	})
+	require.NoError(t, err)
+	require.Len(t, baseProof.ExclusionProofs, 1)
+	require.EqualValues(t, 1, baseProof.ExclusionProofs[0].OutputIndex)
+	require.True(t, baseProof.ExclusionProofs[0].TapscriptProof.Bip86)
+}
+
+// TestParseZeroChangePolicy tests that zero change policies are parsed
+// correctly.
+func TestParseZeroChangePolicy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := ParseZeroChangePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, ZeroChangeTombstone, policy)
+
+	policy, err = ParseZeroChangePolicy("omit")
+	require.NoError(t, err)
+	require.Equal(t, ZeroChangeOmit, policy)
+
+	_, err = ParseZeroChangePolicy("burn")
+	require.Error(t, err)
+}
+
+// TestParseAnchorOutputOrder tests that anchor output orders are parsed
+// correctly and map to the expected sorter.
+func TestParseAnchorOutputOrder(t *testing.T) {
+	t.Parallel()
+
+	order, err := ParseAnchorOutputOrder("")
+	require.NoError(t, err)
+	require.Equal(t, AnchorOutputOrderNone, order)
+	require.Nil(t, order.Sorter())
+
+	order, err = ParseAnchorOutputOrder("bip69")
+	require.NoError(t, err)
+	require.Equal(t, AnchorOutputOrderBIP69, order)
+	require.NotNil(t, order.Sorter())
+
+	_, err = ParseAnchorOutputOrder("random")
+	require.Error(t, err)
+}
+
+// TestApplyZeroChangePolicy tests that a zero-amount change output of a
+// transfer that consumes its inputs exactly is handled according to the zero
+// change policy.
+func TestApplyZeroChangePolicy(t *testing.T) {
+	t.Parallel()
+
+	tombstone := func(anchorIndex uint32) *tappsbt.VOutput {
+		return &tappsbt.VOutput{
+			Type:              tappsbt.TypeSplitRoot,
+			AnchorOutputIndex: anchorIndex,
+			ScriptKey:         asset.NUMSScriptKey,
+		}
+	}
+	recipient := func(amt uint64, anchorIndex uint32,
+		interactive bool) *tappsbt.VOutput {
+
+		return &tappsbt.VOutput{
+			Amount:            amt,
+			Type:              tappsbt.TypeSimple,
+			Interactive:       interactive,
+			AnchorOutputIndex: anchorIndex,
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		policy  ZeroChangePolicy
+		outputs []*tappsbt.VOutput
+
+		// expectedTypes are the types of the outputs after applying the
+		// policy, expectedAnchors the anchor output indexes.
+		expectedTypes   []tappsbt.VOutputType
+		expectedAnchors []uint32
+	}{{
+		name:   "tombstone policy keeps tombstone",
+		policy: ZeroChangeTombstone,
+		outputs: []*tappsbt.VOutput{
+			tombstone(0), recipient(4000, 1, true),
+		},
+		expectedTypes: []tappsbt.VOutputType{
+			tappsbt.TypeSplitRoot, tappsbt.TypeSimple,
+		},
+		expectedAnchors: []uint32{0, 1},
+	}, {
+		name:   "omit keeps tombstone for address send",
+		policy: ZeroChangeOmit,
+		outputs: []*tappsbt.VOutput{
+			tombstone(0), recipient(4000, 1, false),
+		},
+		expectedTypes: []tappsbt.VOutputType{
+			tappsbt.TypeSplitRoot, tappsbt.TypeSimple,
+		},
+		expectedAnchors: []uint32{0, 1},
+	}, {
+		name:   "omit removes tombstone for interactive send",
+		policy: ZeroChangeOmit,
+		outputs: []*tappsbt.VOutput{
+			tombstone(0), recipient(4000, 1, true),
+		},
+		expectedTypes:   []tappsbt.VOutputType{tappsbt.TypeSimple},
+		expectedAnchors: []uint32{0},
+	}, {
+		name:   "omit promotes first of multiple recipients",
+		policy: ZeroChangeOmit,
+		outputs: []*tappsbt.VOutput{
+			recipient(1000, 0, true), tombstone(1),
+			recipient(3000, 2, true),
+		},
+		expectedTypes: []tappsbt.VOutputType{
+			tappsbt.TypeSplitRoot, tappsbt.TypeSimple,
+		},
+		expectedAnchors: []uint32{0, 1},
+	}, {
+		name:   "omit with shared anchor output",
+		policy: ZeroChangeOmit,
+		outputs: []*tappsbt.VOutput{
+			tombstone(0), recipient(4000, 0, true),
+		},
+		expectedTypes:   []tappsbt.VOutputType{tappsbt.TypeSimple},
+		expectedAnchors: []uint32{0},
+	}, {
+		name:   "omit keeps passive assets carrier",
+		policy: ZeroChangeOmit,
+		outputs: []*tappsbt.VOutput{
+			{
+				Type:      tappsbt.TypePassiveSplitRoot,
+				ScriptKey: asset.NUMSScriptKey,
+			},
+			recipient(4000, 1, true),
+		},
+		expectedTypes: []tappsbt.VOutputType{
+			tappsbt.TypePassiveSplitRoot, tappsbt.TypeSimple,
+		},
+		expectedAnchors: []uint32{0, 1},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			vPkt := &tappsbt.VPacket{
+				Outputs: tc.outputs,
+			}
+			err := applyZeroChangePolicy(vPkt, tc.policy)
+			require.NoError(t, err)
+
+			require.Len(t, vPkt.Outputs, len(tc.expectedTypes))
+			for idx, vOut := range vPkt.Outputs {
+				require.Equal(
+					t, tc.expectedTypes[idx], vOut.Type,
+				)
+				require.Equal(
+					t, tc.expectedAnchors[idx],
+					vOut.AnchorOutputIndex,
+				)
+			}
+
+			// After applying the policy, there should never be a
+			// zero-amount output that isn't a split root.
+			for _, vOut := range vPkt.Outputs {
+				if vOut.Amount == 0 {
+					require.True(t, vOut.Type.IsSplitRoot())
+				}
+			}
+		})
+	}
+}
+
+// TestMultiAssetPackets tests that addresses are grouped by their asset ID in
+// the order they were given and that virtual packets that share an anchor input
+// or output are rejected.
+func TestMultiAssetPackets(t *testing.T) {
+	t.Parallel()
+
+	idA := asset.RandID(t)
+	idB := asset.RandID(t)
+
+	addrA1 := &address.Tap{AssetID: idA, Amount: 1}
+	addrB := &address.Tap{AssetID: idB, Amount: 2}
+	addrA2 := &address.Tap{AssetID: idA, Amount: 3}
+
+	groups := groupAddrsByAssetID(
+		[]*address.Tap{addrA1, addrB, addrA2},
+	)
+	require.Equal(t, [][]*address.Tap{
+		{addrA1, addrA2},
+		{addrB},
+	}, groups)
+
+	newPkt := func(op wire.OutPoint, id asset.ID,
+		anchorIndexes ...uint32) *tappsbt.VPacket {
+
+		vPkt := &tappsbt.VPacket{
+			Inputs: []*tappsbt.VInput{{
+				PrevID: asset.PrevID{OutPoint: op, ID: id},
+			}},
+		}
+		for _, anchorIdx := range anchorIndexes {
+			vPkt.Outputs = append(vPkt.Outputs, &tappsbt.VOutput{
+				AnchorOutputIndex: anchorIdx,
+			})
+		}
+
+		return vPkt
+	}
+
+	opA := wire.OutPoint{Index: 1}
+	opB := wire.OutPoint{Index: 2}
+
+	// A single packet may commit multiple outputs to the same anchor.
+	require.NoError(t, checkDisjointPackets([]*tappsbt.VPacket{
+		newPkt(opA, idA, 0, 0, 1),
+	}))
+
+	require.NoError(t, checkDisjointPackets([]*tappsbt.VPacket{
+		newPkt(opA, idA, 0, 1), newPkt(opB, idB, 2, 3),
+	}))
+
+	// Different assets anchored in the same output can be spent by
+	// different packets, but the same asset can't.
+	require.NoError(t, checkDisjointPackets([]*tappsbt.VPacket{
+		newPkt(opA, idA, 0, 1), newPkt(opA, idB, 2, 3),
+	}))
+
+	err := checkDisjointPackets([]*tappsbt.VPacket{
+		newPkt(opA, idA, 0, 1), newPkt(opA, idA, 2, 3),
+	})
+	require.ErrorContains(t, err, "both spend asset")
+
+	err = checkDisjointPackets([]*tappsbt.VPacket{
+		newPkt(opA, idA, 0, 1), newPkt(opB, idB, 1, 2),
+	})
+	require.ErrorContains(t, err, "both commit to anchor output index")
+}
+
+// TestSplitSharedInputCommitments tests that the assets of an anchor output
+// that is spent by multiple packets are only committed to once: each packet
+// keeps the assets it spends, and the first packet also keeps the passive
+// assets.
+func TestSplitSharedInputCommitments(t *testing.T) {
+	t.Parallel()
+
+	assetA := asset.RandAsset(t, asset.Normal)
+	assetB := asset.RandAsset(t, asset.Normal)
+	passiveAsset := asset.RandAsset(t, asset.Normal)
+	ownAsset := asset.RandAsset(t, asset.Normal)
+
+	sharedCommitment, err := commitment.FromAssets(
+		assetA, assetB, passiveAsset,
+	)
+	require.NoError(t, err)
+	ownCommitment, err := commitment.FromAssets(ownAsset)
+	require.NoError(t, err)
+
+	sharedOp := test.RandOp(t)
+	ownOp := test.RandOp(t)
+
+	newInput := func(op wire.OutPoint, a *asset.Asset) *tappsbt.VInput {
+		return &tappsbt.VInput{
+			PrevID: asset.PrevID{
+				OutPoint: op,
+				ID:       a.ID(),
+				ScriptKey: asset.ToSerialized(
+					a.ScriptKey.PubKey,
+				),
+			},
+		}
+	}
+	newFundedPkt := func(inputs []*tappsbt.VInput, assets []*asset.Asset,
+		commitments ...*commitment.TapCommitment) *FundedVPacket {
+
+		vPkt := &tappsbt.VPacket{
+			Inputs: inputs,
+			Outputs: []*tappsbt.VOutput{{
+				Type: tappsbt.TypePassiveSplitRoot,
+			}},
+		}
+		inputCommitments := make(tappsbt.InputCommitments)
+		for idx := range inputs {
+			vPkt.SetInputAsset(idx, assets[idx], nil)
+			inputCommitments[idx] = commitments[idx]
+		}
+
+		return &FundedVPacket{
+			VPacket:          vPkt,
+			InputCommitments: inputCommitments,
+		}
+	}
+
+	// The first packet spends asset A of the shared anchor output, the
+	// second one spends asset B of it and another anchor output of its
+	// own.
+	pktA := newFundedPkt(
+		[]*tappsbt.VInput{newInput(sharedOp, assetA)},
+		[]*asset.Asset{assetA}, sharedCommitment,
+	)
+	pktB := newFundedPkt(
+		[]*tappsbt.VInput{
+			newInput(sharedOp, assetB), newInput(ownOp, ownAsset),
+		},
+		[]*asset.Asset{assetB, ownAsset}, sharedCommitment,
+		ownCommitment,
+	)
+	require.NoError(t, splitSharedInputCommitments(
+		[]*FundedVPacket{pktA, pktB},
+	))
+
+	committedIDs := func(c *commitment.TapCommitment) []asset.ID {
+		assets := c.CommittedAssets()
+		return fn.Map(assets, func(a *asset.Asset) asset.ID {
+			return a.ID()
+		})
+	}
+
+	// The first packet keeps its asset and the passive asset, which it
+	// re-anchors.
+	require.ElementsMatch(
+		t, []asset.ID{assetA.ID(), passiveAsset.ID()},
+		committedIDs(pktA.InputCommitments[0]),
+	)
+	require.Equal(
+		t, tappsbt.TypePassiveSplitRoot, pktA.VPacket.Outputs[0].Type,
+	)
+
+	// The second packet only keeps the asset it spends of the shared
+	// anchor output, so it doesn't carry passive assets anymore. Its own
+	// anchor output is left untouched.
+	require.ElementsMatch(
+		t, []asset.ID{assetB.ID()},
+		committedIDs(pktB.InputCommitments[0]),
+	)
+	require.Equal(t, ownCommitment, pktB.InputCommitments[1])
+	require.Equal(t, tappsbt.TypeSplitRoot, pktB.VPacket.Outputs[0].Type)
+
+	// The original commitment wasn't modified.
+	require.Len(t, sharedCommitment.CommittedAssets(), 3)
+}
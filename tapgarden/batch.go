@@ -2,6 +2,7 @@ package tapgarden
 
 import (
 	"fmt"
+	"math/bits"
 	"sync/atomic"
 	"time"
 
@@ -70,10 +71,61 @@ type MintingBatch struct {
 	// taprootAssetScriptRoot is the root hash of the Taproot Asset
 	// commitment. If this is nil, then the mintingPubKey will be as well.
 	taprootAssetScriptRoot []byte
+
+	// FinalizeSchedule is the optional schedule according to which the
+	// batch is finalized automatically.
+	//
+	// NOTE: This field is only set for the pending batch.
+	FinalizeSchedule *FinalizeSchedule
+}
+
+// FinalizeSchedule describes when a pending batch should be finalized
+// automatically. The batch is finalized as soon as any of the set conditions
+// is met.
+type FinalizeSchedule struct {
+	// FinalizeTime is the time at which the batch should be finalized. If
+	// this is the zero time, the batch isn't finalized based on time.
+	FinalizeTime time.Time
+
+	// MinSeedlings is the number of seedlings the batch should be
+	// finalized at. If this is zero, the batch isn't finalized based on
+	// its number of seedlings.
+	MinSeedlings int
+}
+
+// isDue returns true if the given batch should be finalized at the given time
+// according to the schedule.
+func (s *FinalizeSchedule) isDue(batch *MintingBatch, now time.Time) bool {
+	if !s.FinalizeTime.IsZero() && !now.Before(s.FinalizeTime) {
+		return true
+	}
+
+	return s.MinSeedlings > 0 && len(batch.Seedlings) >= s.MinSeedlings
+}
+
+// timer returns a channel that fires once the finalization time of the
+// schedule was reached. A nil channel is returned if the batch isn't finalized
+// based on time.
+func (s *FinalizeSchedule) timer() <-chan time.Time {
+	if s == nil || s.FinalizeTime.IsZero() {
+		return nil
+	}
+
+	return time.After(time.Until(s.FinalizeTime))
 }
 
 // TODO(roasbeef): add batch validate method re unique names?
 
+// numAssets returns the number of assets the batch creates, which are either
+// still seedlings or already sprouted into the root asset commitment.
+func (m *MintingBatch) numAssets() int {
+	if len(m.Seedlings) > 0 || m.RootAssetCommitment == nil {
+		return len(m.Seedlings)
+	}
+
+	return len(m.RootAssetCommitment.CommittedAssets())
+}
+
 // AddSeedling adds a new seedling to the batch.
 func (m *MintingBatch) addSeedling(s *Seedling) error {
 	if _, ok := m.Seedlings[s.AssetName]; ok {
@@ -99,6 +151,33 @@ func (m *MintingBatch) validateGroupAnchor(s *Seedling) error {
 			*s.GroupAnchor)
 	}
 
+	// If the anchor caps the supply of the group, then the anchor and all
+	// the seedlings grouped with it must stay within that cap.
+	supplyCap := anchor.SupplyCap()
+	if supplyCap == 0 {
+		return nil
+	}
+
+	total, carry := bits.Add64(anchor.Amount, s.Amount, 0)
+	for _, seedling := range m.Seedlings {
+		if seedling.GroupAnchor != nil &&
+			*seedling.GroupAnchor == anchor.AssetName {
+
+			var seedlingCarry uint64
+			total, seedlingCarry = bits.Add64(
+				total, seedling.Amount, 0,
+			)
+			carry |= seedlingCarry
+		}
+	}
+
+	// A total that doesn't fit into an uint64 exceeds any cap.
+	if carry != 0 || total > supplyCap {
+		return fmt.Errorf("%w: group %v would issue %d, cap %d",
+			proof.ErrSupplyCapExceeded, anchor.AssetName, total,
+			supplyCap)
+	}
+
 	return nil
 }
 
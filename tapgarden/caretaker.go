@@ -1,6 +1,7 @@
 package tapgarden
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -209,6 +210,8 @@ func (b *BatchCaretaker) Cancel() CancelResp {
 		if err != nil {
 			err = fmt.Errorf("BatchCaretaker(%x), batch state(%v), "+
 				"cancel failed: %w", batchKey, batchState, err)
+		} else {
+			b.releaseGenesisInputs(ctx)
 		}
 
 		b.cfg.BroadcastErrChan <- fmt.Errorf("caretaker canceled")
@@ -222,6 +225,28 @@ func (b *BatchCaretaker) Cancel() CancelResp {
 	}
 }
 
+// releaseGenesisInputs releases the wallet inputs that were leased when funding
+// the genesis transaction of a batch that won't be broadcast anymore, so they
+// can be used again right away instead of only once the lease expires.
+func (b *BatchCaretaker) releaseGenesisInputs(ctx context.Context) {
+	genesisPkt := b.cfg.Batch.GenesisPacket
+	if genesisPkt == nil || genesisPkt.Pkt == nil {
+		return
+	}
+
+	// All inputs of the genesis transaction were added by the wallet, so
+	// we don't need to rely on the set of locked UTXOs, which isn't known
+	// anymore after a restart.
+	for _, txIn := range genesisPkt.Pkt.UnsignedTx.TxIn {
+		err := b.cfg.Wallet.ReleaseOutput(ctx, txIn.PreviousOutPoint)
+		if err != nil {
+			log.Warnf("BatchCaretaker(%x): unable to release "+
+				"genesis input %v: %v", b.batchKey[:],
+				txIn.PreviousOutPoint, err)
+		}
+	}
+}
+
 // advanceStateUntil attempts to advance the internal state machine until the
 // target state has been reached.
 func (b *BatchCaretaker) advanceStateUntil(currentState,
@@ -457,6 +482,13 @@ func (b *BatchCaretaker) seedlingsToAssetSprouts(ctx context.Context,
 		}
 		tweakedScriptKey := asset.NewScriptKeyBip86(scriptKey)
 
+		// If the seedling commits to a tapscript tree, the script key
+		// is tweaked with the root of that tree instead.
+		if seedling.ScriptKeyTapscript != nil {
+			tree := seedling.ScriptKeyTapscript
+			tweakedScriptKey = tree.ScriptKey(scriptKey)
+		}
+
 		var (
 			amount         uint64
 			groupInfo      *asset.AssetGroup
@@ -933,6 +965,18 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 				"proofs: %w", err)
 		}
 
+		// Reissuances into asset groups whose issuance authority was
+		// rotated must also be authorized by the current authority key.
+		if b.cfg.IssuanceAuthorizer != nil {
+			err := authorizeReissuances(
+				ctx, b.cfg.IssuanceAuthorizer, mintingProofs,
+				confInfo.BlockHeight,
+			)
+			if err != nil {
+				return 0, err
+			}
+		}
+
 		var (
 			committedAssets   = batchCommitment.CommittedAssets()
 			numAssets         = len(committedAssets)
@@ -1032,7 +1076,7 @@ func (b *BatchCaretaker) stateStep(currentState BatchState) (BatchState, error)
 		err = b.cfg.Log.MarkBatchConfirmed(
 			ctx, b.cfg.Batch.BatchKey.PubKey, confInfo.BlockHash,
 			confInfo.BlockHeight, confInfo.TxIndex,
-			mintingProofBlobs,
+			confInfo.Block.Header.Timestamp, mintingProofBlobs,
 		)
 		if err != nil {
 			return 0, fmt.Errorf("unable to confirm batch: %w", err)
@@ -1349,6 +1393,65 @@ func GenGroupVerifier(ctx context.Context,
 	}
 }
 
+// GenUniverseVerifier generates a callback function that reports whether a
+// proof is already known to the given local multiverse. A proof is only
+// considered known if the universe leaf at its location holds the exact same
+// proof.
+func GenUniverseVerifier(
+	multiverse universe.MultiverseArchive) proof.UniverseVerifier {
+
+	return func(ctx context.Context, p *proof.Proof) (bool, error) {
+		proofType, err := universe.NewProofTypeFromAssetProof(p)
+		if err != nil {
+			return false, err
+		}
+
+		uniID := universe.Identifier{
+			AssetID:   p.Asset.ID(),
+			ProofType: proofType,
+		}
+		if p.Asset.GroupKey != nil {
+			uniID.GroupKey = &p.Asset.GroupKey.GroupPubKey
+		}
+
+		leafKey := universe.LeafKey{
+			OutPoint: wire.OutPoint{
+				Hash:  p.AnchorTx.TxHash(),
+				Index: p.InclusionProof.OutputIndex,
+			},
+			ScriptKey: &p.Asset.ScriptKey,
+		}
+
+		uniProofs, err := multiverse.FetchProofLeaf(ctx, uniID, leafKey)
+		switch {
+		case errors.Is(err, universe.ErrNoUniverseProofFound):
+			return false, nil
+
+		case err != nil:
+			return false, err
+		}
+
+		var proofBuf bytes.Buffer
+		if err := p.Encode(&proofBuf); err != nil {
+			return false, err
+		}
+
+		for _, uniProof := range uniProofs {
+			var leafBuf bytes.Buffer
+			err := uniProof.Leaf.Proof.Encode(&leafBuf)
+			if err != nil {
+				return false, err
+			}
+
+			if bytes.Equal(proofBuf.Bytes(), leafBuf.Bytes()) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
 // GenGroupAnchorVerifier generates a caching group anchor verification
 // callback function given a DB handle.
 func GenGroupAnchorVerifier(ctx context.Context,
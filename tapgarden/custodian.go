@@ -2,12 +2,15 @@ package tapgarden
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/lightninglabs/lndclient"
@@ -22,6 +25,67 @@ const (
 	defaultProofRetrievalDelay = 5 * time.Second
 )
 
+// AddrReusePolicy is an enum that describes how the custodian handles an
+// inbound transfer to an address that already received assets in a different
+// on-chain output.
+type AddrReusePolicy uint8
+
+const (
+	// AddrReuseAccept means a repeated inbound transfer to the same address
+	// is treated as a separate UTXO and taken custody of like any other.
+	AddrReuseAccept AddrReusePolicy = iota
+
+	// AddrReuseReject means a repeated inbound transfer to the same address
+	// is ignored. No event is created for it and its proof isn't fetched.
+	AddrReuseReject
+
+	// AddrReuseQuarantine means an event is created for a repeated inbound
+	// transfer to the same address, but its proof isn't fetched
+	// automatically. The transfer is only completed once the proof is
+	// imported manually.
+	AddrReuseQuarantine
+)
+
+// String returns a human-readable string for the address reuse policy.
+func (p AddrReusePolicy) String() string {
+	switch p {
+	case AddrReuseAccept:
+		return "accept"
+
+	case AddrReuseReject:
+		return "reject"
+
+	case AddrReuseQuarantine:
+		return "quarantine"
+
+	default:
+		return fmt.Sprintf("<unknown_addr_reuse_policy(%d)>", p)
+	}
+}
+
+// ParseAddrReusePolicy parses an address reuse policy string.
+func ParseAddrReusePolicy(policy string) (AddrReusePolicy, error) {
+	switch policy {
+	case "", AddrReuseAccept.String():
+		return AddrReuseAccept, nil
+
+	case AddrReuseReject.String():
+		return AddrReuseReject, nil
+
+	case AddrReuseQuarantine.String():
+		return AddrReuseQuarantine, nil
+
+	default:
+		return 0, fmt.Errorf("unknown address reuse policy: %v",
+			policy)
+	}
+}
+
+// ErrProofChainTooLong is the error of a dead letter proof that was rejected
+// because its proof file contains more state transitions than allowed by the
+// configured maximum proof chain depth.
+var ErrProofChainTooLong = errors.New("proof chain exceeds maximum depth")
+
 // CustodianConfig houses all the items that the Custodian needs to carry out
 // its duties.
 type CustodianConfig struct {
@@ -62,6 +126,30 @@ type CustodianConfig struct {
 	// to be confirmed safely with a minimum number of confirmations.
 	ProofWatcher proof.Watcher
 
+	// AddrReusePolicy determines how an inbound transfer to an address that
+	// already received assets in a different on-chain output is handled.
+	AddrReusePolicy AddrReusePolicy
+
+	// PartialReceivePolicy determines how an inbound transfer that carries
+	// fewer asset units than requested by the address is handled.
+	PartialReceivePolicy PartialReceivePolicy
+
+	// ProofImportRetry configures the retry of inbound proofs that failed
+	// to be imported because of a transient issue. If this is nil, a
+	// failed import isn't retried.
+	ProofImportRetry *ProofImportRetryCfg
+
+	// DeadLetterStore persists the inbound proofs that couldn't be
+	// imported. If this is nil, they're only kept in memory until the
+	// custodian is stopped.
+	DeadLetterStore DeadLetterStore
+
+	// MaxProofChainDepth is the maximum number of state transitions the
+	// proof file of an inbound transfer may contain. A proof with a longer
+	// chain isn't verified or imported but moved to the dead letters. A
+	// value of zero means no limit.
+	MaxProofChainDepth uint32
+
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
 	ErrChan chan<- error
@@ -90,6 +178,26 @@ type Custodian struct {
 	// address events of inbound assets.
 	events map[wire.OutPoint]*address.Event
 
+	// deadLetters are the inbound proofs that couldn't be imported, keyed
+	// by the outpoint of their inbound transfer.
+	deadLetters map[wire.OutPoint]*DeadLetterProof
+
+	// deadLettersMtx guards deadLetters, which is written by the
+	// goroutines that receive the inbound proofs.
+	deadLettersMtx sync.Mutex
+
+	// anyAmountProofs is the channel through which the goroutines that
+	// wait for the proofs of transfers to addresses that allow any amount
+	// hand them to the main event loop.
+	anyAmountProofs chan *anyAmountProof
+
+	// subscribers is a map of components that want to be notified about
+	// the progress of inbound asset transfers.
+	subscribers map[uint64]*fn.EventReceiver[fn.Event]
+
+	// subscriberMtx guards the subscribers map.
+	subscriberMtx sync.Mutex
+
 	// ContextGuard provides a wait group and main quit channel that can be
 	// used to create guarded contexts.
 	*fn.ContextGuard
@@ -107,6 +215,11 @@ func NewCustodian(cfg *CustodianConfig) *Custodian {
 		addrSubscription:  addrSub,
 		proofSubscription: proofSub,
 		events:            make(map[wire.OutPoint]*address.Event),
+		deadLetters:       make(map[wire.OutPoint]*DeadLetterProof),
+		anyAmountProofs:   make(chan *anyAmountProof),
+		subscribers: make(
+			map[uint64]*fn.EventReceiver[fn.Event],
+		),
 		ContextGuard: &fn.ContextGuard{
 			DefaultTimeout: DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -166,11 +279,73 @@ func (c *Custodian) Stop() error {
 		if err != nil {
 			stopErr = err
 		}
+
+		// Remove all receive event subscribers.
+		c.subscriberMtx.Lock()
+		for _, sub := range c.subscribers {
+			sub.Stop()
+		}
+		c.subscribers = make(map[uint64]*fn.EventReceiver[fn.Event])
+		c.subscriberMtx.Unlock()
 	})
 
 	return stopErr
 }
 
+// RegisterSubscriber adds a new subscriber to the set of subscribers that will
+// be notified about the progress of inbound asset transfers.
+func (c *Custodian) RegisterSubscriber(receiver *fn.EventReceiver[fn.Event],
+	_ bool, _ bool) error {
+
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	c.subscribers[receiver.ID()] = receiver
+
+	return nil
+}
+
+// RemoveSubscriber removes a subscriber from the set of subscribers that will
+// be notified about the progress of inbound asset transfers.
+func (c *Custodian) RemoveSubscriber(
+	subscriber *fn.EventReceiver[fn.Event]) error {
+
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	_, ok := c.subscribers[subscriber.ID()]
+	if !ok {
+		return fmt.Errorf("subscriber with ID %d not found",
+			subscriber.ID())
+	}
+
+	subscriber.Stop()
+	delete(c.subscribers, subscriber.ID())
+
+	return nil
+}
+
+// publishReceiveEvent notifies all subscribers that the inbound transfer to
+// the given address in the given outpoint progressed to the given status.
+func (c *Custodian) publishReceiveEvent(addr *address.Tap, op wire.OutPoint,
+	status ReceiveStatus, err error) {
+
+	event := NewAssetReceiveEvent(
+		addr.ScriptKey, addr.AssetID, op, status, err,
+	)
+
+	c.subscriberMtx.Lock()
+	defer c.subscriberMtx.Unlock()
+
+	for _, sub := range c.subscribers {
+		sub.NewItemCreated.ChanIn() <- event
+	}
+}
+
+// A compile-time assertion to make sure Custodian satisfies the
+// fn.EventPublisher interface.
+var _ fn.EventPublisher[fn.Event, bool] = (*Custodian)(nil)
+
 // watchInboundAssets processes new Taproot Asset addresses being created and
 // new transactions being received and attempts to match the two things into
 // inbound asset events.
@@ -197,6 +372,13 @@ func (c *Custodian) watchInboundAssets() {
 		return
 	}
 
+	// The proofs that couldn't be imported before the restart can still
+	// be imported manually.
+	if err := c.loadDeadLetters(); err != nil {
+		reportErr(err)
+		return
+	}
+
 	// Fetch all pending events that we wish to process.
 	log.Infof("Loading pending inbound asset events")
 	ctxt, cancel := c.WithCtxQuit()
@@ -268,6 +450,9 @@ func (c *Custodian) watchInboundAssets() {
 			log.Tracef("New proof received from notifier")
 			err = c.mapProofToEvent(newProof)
 
+		case p := <-c.anyAmountProofs:
+			err = c.handleAnyAmountProof(p)
+
 		case err = <-txErrChan:
 			break
 
@@ -333,6 +518,10 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 				}
 
 				c.events[op] = event
+				c.publishReceiveEvent(
+					event.Addr.Tap, op,
+					ReceiveStatusConfirmed, nil,
+				)
 			}
 
 			continue
@@ -340,7 +529,9 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 
 		// This is a new output, let's find out if it's for an address
 		// of ours.
-		addr, err := c.mapToTapAddr(walletTx, uint32(idx), op)
+		addr, quarantined, err := c.mapToTapAddr(
+			walletTx, uint32(idx), op,
+		)
 		if err != nil {
 			return err
 		}
@@ -351,6 +542,18 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 			continue
 		}
 
+		// A quarantined transfer is only completed once its proof is
+		// imported manually, so we don't fetch it ourselves.
+		if quarantined {
+			continue
+		}
+
+		// The proof of a transfer to an address that allows any amount
+		// is received by the goroutine that waits for it.
+		if addr.AllowAnyAmount {
+			continue
+		}
+
 		// TODO(ffranr): This proof courier disabled check should be
 		//  removed. It was implemented because some integration test do
 		//  not setup and use a proof courier.
@@ -361,6 +564,7 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 		// Now that we've seen this output on chain, we'll launch a
 		// goroutine to use the ProofCourier to import the proof into
 		// our local DB.
+		event = c.events[op]
 		c.Wg.Add(1)
 		go func() {
 			defer c.Wg.Done()
@@ -376,9 +580,10 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 			// Initiate proof courier service handle from the proof
 			// courier address found in the Tap address.
 			recipient := proof.Recipient{
-				ScriptKey: &addr.ScriptKey,
-				AssetID:   assetID,
-				Amount:    addr.Amount,
+				ScriptKey:   &addr.ScriptKey,
+				AssetID:     assetID,
+				Amount:      addr.Amount,
+				InternalKey: event.Addr.InternalKeyDesc,
 			}
 			courier, err := proof.NewCourier(
 				ctx, addr.ProofCourierAddr,
@@ -407,7 +612,9 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 				ScriptKey: addr.ScriptKey,
 				OutPoint:  &op,
 			}
-			addrProof, err := courier.ReceiveProof(ctx, loc)
+			addrProof, err := c.receiveProof(
+				ctx, courier, recipient, loc,
+			)
 			if err != nil {
 				log.Errorf("unable to recv proof: %v", err)
 				return
@@ -418,35 +625,243 @@ func (c *Custodian) inspectWalletTx(walletTx *lndclient.Transaction) error {
 				addr.ScriptKey.SerializeCompressed(),
 				assetID[:])
 
-			ctx, cancel = c.CtxBlocking()
-			defer cancel()
-
-			headerVerifier := GenHeaderVerifier(
-				ctx, c.cfg.ChainBridge,
-			)
-			err = c.cfg.ProofArchive.ImportProofs(
-				ctx, headerVerifier, c.cfg.GroupVerifier, false,
-				addrProof,
+			c.publishReceiveEvent(
+				addr, op, ReceiveStatusProofReceived, nil,
 			)
-			if err != nil {
-				log.Errorf("unable to import proofs: %v", err)
+
+			if !c.checkReceivedAmount(event, addrProof) {
 				return
 			}
+
+			c.importProof(addrProof, event)
 		}()
 	}
 
 	return nil
 }
 
+// receiveProof receives the proof of the given recipient through the given
+// courier. If that fails and a fallback courier is configured, the fallback
+// universe is polled for the proof instead, as the sender uploads it there if
+// it couldn't deliver it through the courier of the address.
+func (c *Custodian) receiveProof(ctx context.Context, courier proof.Courier,
+	recipient proof.Recipient,
+	loc proof.Locator) (*proof.AnnotatedProof, error) {
+
+	addrProof, err := courier.ReceiveProof(ctx, loc)
+
+	fallbackAddr := c.cfg.ProofCourierCfg.FallbackCourierAddr
+	if err == nil || ctx.Err() != nil || fallbackAddr == nil {
+		return addrProof, err
+	}
+
+	log.Warnf("Unable to recv proof for script key %x, trying fallback "+
+		"courier %v: %v", loc.ScriptKey.SerializeCompressed(),
+		fallbackAddr.String(), err)
+
+	fallbackCourier, err := proof.NewFallbackCourier(
+		ctx, c.cfg.ProofCourierCfg, recipient,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initiate fallback proof "+
+			"courier: %w", err)
+	}
+
+	return fallbackCourier.ReceiveProof(ctx, loc)
+}
+
+// importProof imports the proof of an inbound transfer into the proof
+// archive. An import that fails because of a transient issue is retried as
+// configured, a proof that can't be imported is moved to the dead letters.
+func (c *Custodian) importProof(p *proof.AnnotatedProof, event *address.Event) {
+	op := event.Outpoint
+
+	// deadLetter moves the proof to the dead letters and notifies the
+	// subscribers that the inbound transfer failed.
+	deadLetter := func(attempts uint32, err error) {
+		c.addDeadLetter(&DeadLetterProof{
+			OutPoint: op,
+			Proof:    p,
+			Attempts: attempts,
+			Err:      err,
+			Time:     time.Now(),
+		})
+
+		c.publishReceiveEvent(
+			event.Addr.Tap, op, ReceiveStatusFailed, err,
+		)
+	}
+
+	// We reject overly long proof chains before spending any effort on
+	// verifying them.
+	err := CheckProofChainDepth(p.Blob, c.cfg.MaxProofChainDepth)
+	if err != nil {
+		log.Errorf("Rejecting proof for %v, moving it to the dead "+
+			"letters: %v", op, err)
+
+		deadLetter(0, err)
+
+		return
+	}
+
+	retryCfg := c.cfg.ProofImportRetry
+	if retryCfg == nil {
+		retryCfg = &ProofImportRetryCfg{MaxAttempts: 1}
+	}
+
+	attempts, err := importWithRetry(c.Quit, retryCfg, func() error {
+		ctx, cancel := c.CtxBlocking()
+		defer cancel()
+
+		headerVerifier := transientHeaderVerifier(
+			GenHeaderVerifier(ctx, c.cfg.ChainBridge),
+			func() (uint32, error) {
+				return c.cfg.ChainBridge.CurrentHeight(ctx)
+			},
+		)
+		return c.cfg.ProofArchive.ImportProofs(
+			ctx, headerVerifier, c.cfg.GroupVerifier, false, p,
+		)
+	})
+	switch {
+	case err == nil:
+		// The archive verifies the proof before importing it.
+		c.publishReceiveEvent(
+			event.Addr.Tap, op, ReceiveStatusProofVerified, nil,
+		)
+
+		return
+
+	case errors.Is(err, errImportAborted):
+		log.Debugf("Aborted import of proof for %v: %v", op, err)
+		return
+	}
+
+	log.Errorf("Unable to import proof for %v after %d attempt(s), "+
+		"moving it to the dead letters: %v", op, attempts, err)
+
+	deadLetter(attempts, err)
+}
+
+// CheckProofChainDepth makes sure the given proof file doesn't contain more
+// state transitions than the given maximum depth. A maximum depth of zero
+// means no limit. A proof file that can't be decoded is not rejected here, as
+// its import fails anyway.
+func CheckProofChainDepth(blob proof.Blob, maxDepth uint32) error {
+	if maxDepth == 0 {
+		return nil
+	}
+
+	file := proof.NewEmptyFile(proof.V0)
+	if err := file.Decode(bytes.NewReader(blob)); err != nil {
+		return nil
+	}
+
+	depth := file.NumProofs()
+	if depth <= int(maxDepth) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: proof file contains %d state transitions, "+
+		"only %d are accepted, the sender should provide a shorter "+
+		"proof that starts at a state transition checkpointed in a "+
+		"universe instead", ErrProofChainTooLong, depth, maxDepth)
+}
+
+// addDeadLetter adds the given proof to the dead letters and persists it, so it
+// can still be listed after a restart.
+func (c *Custodian) addDeadLetter(deadLetter *DeadLetterProof) {
+	c.deadLettersMtx.Lock()
+	c.deadLetters[deadLetter.OutPoint] = deadLetter
+	c.deadLettersMtx.Unlock()
+
+	if c.cfg.DeadLetterStore == nil {
+		return
+	}
+
+	ctxt, cancel := c.CtxBlocking()
+	defer cancel()
+
+	err := c.cfg.DeadLetterStore.StoreDeadLetter(ctxt, deadLetter)
+	if err != nil {
+		log.Errorf("Unable to store dead letter proof for %v: %v",
+			deadLetter.OutPoint, err)
+	}
+}
+
+// removeDeadLetter removes the dead letter of the given outpoint, if there is
+// one.
+func (c *Custodian) removeDeadLetter(op wire.OutPoint) error {
+	c.deadLettersMtx.Lock()
+	delete(c.deadLetters, op)
+	c.deadLettersMtx.Unlock()
+
+	if c.cfg.DeadLetterStore == nil {
+		return nil
+	}
+
+	ctxt, cancel := c.CtxBlocking()
+	defer cancel()
+
+	return c.cfg.DeadLetterStore.DeleteDeadLetter(ctxt, op)
+}
+
+// loadDeadLetters loads the persisted dead letters into memory.
+func (c *Custodian) loadDeadLetters() error {
+	if c.cfg.DeadLetterStore == nil {
+		return nil
+	}
+
+	ctxt, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	deadLetters, err := c.cfg.DeadLetterStore.FetchDeadLetters(ctxt)
+	if err != nil {
+		return fmt.Errorf("unable to fetch dead letters: %w", err)
+	}
+
+	c.deadLettersMtx.Lock()
+	defer c.deadLettersMtx.Unlock()
+
+	for _, deadLetter := range deadLetters {
+		c.deadLetters[deadLetter.OutPoint] = deadLetter
+	}
+
+	return nil
+}
+
+// DeadLetters returns the inbound proofs that couldn't be imported. These
+// proofs need to be imported manually to complete their inbound transfer.
+func (c *Custodian) DeadLetters() []*DeadLetterProof {
+	c.deadLettersMtx.Lock()
+	defer c.deadLettersMtx.Unlock()
+
+	deadLetters := make([]*DeadLetterProof, 0, len(c.deadLetters))
+	for _, deadLetter := range c.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	// We return the dead letters in the order they were given up on.
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].Time.Before(deadLetters[j].Time)
+	})
+
+	return deadLetters
+}
+
 // mapToTapAddr attempts to match a transaction output to a Taproot Asset
 // address. If a matching address is found, an event is created for it. If an
 // event already exists, it is updated with the current transaction information.
+// If the address already received assets in a different confirmed output, the
+// configured address reuse policy is applied. The returned boolean is true if
+// the transfer was quarantined.
 func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
-	outputIdx uint32, op wire.OutPoint) (*address.Tap, error) {
+	outputIdx uint32, op wire.OutPoint) (*address.Tap, bool, error) {
 
 	taprootKey, err := proof.ExtractTaprootKey(walletTx.Tx, outputIdx)
 	if err != nil {
-		return nil, fmt.Errorf("error extracting taproot key: %w", err)
+		return nil, false, fmt.Errorf("error extracting taproot key: "+
+			"%w", err)
 	}
 
 	ctxt, cancel := c.WithCtxQuit()
@@ -457,16 +872,53 @@ func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
 	// on-chain output. This probably wasn't a Taproot Asset transaction at
 	// all then.
 	case errors.Is(err, address.ErrNoAddr):
-		return nil, nil
+		return nil, false, nil
 
 	case err != nil:
-		return nil, fmt.Errorf("error querying addresses by Taproot "+
-			"Asset key: %w", err)
+		return nil, false, fmt.Errorf("error querying addresses by "+
+			"Taproot Asset key: %w", err)
 	}
 
 	addrStr, err := addr.EncodeAddress()
 	if err != nil {
-		return nil, fmt.Errorf("unable to encode address: %v", err)
+		return nil, false, fmt.Errorf("unable to encode address: %v",
+			err)
+	}
+
+	var quarantined bool
+	if c.cfg.AddrReusePolicy != AddrReuseAccept {
+		reused, pending, err := c.isAddrReused(addr, op)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch {
+		// Another unconfirmed output to the same address might just be
+		// a transaction that was replaced (e.g. by RBF). We only decide
+		// about reuse once this output confirms, at which point the
+		// transaction is inspected again.
+		case pending && walletTx.Confirmations == 0:
+			log.Debugf("Deferring inbound asset transfer in %v "+
+				"until confirmation, Taproot Asset address %s "+
+				"has other unconfirmed outputs", op, addrStr)
+
+			return nil, false, nil
+
+		case reused && c.cfg.AddrReusePolicy == AddrReuseReject:
+			log.Warnf("Rejecting inbound asset transfer in %v, "+
+				"Taproot Asset address %s was already used",
+				op, addrStr)
+
+			return nil, false, nil
+
+		case reused && c.cfg.AddrReusePolicy == AddrReuseQuarantine:
+			log.Warnf("Quarantining inbound asset transfer in "+
+				"%v, Taproot Asset address %s was already "+
+				"used. Import the proof manually to complete "+
+				"the transfer", op, addrStr)
+
+			quarantined = true
+		}
 	}
 
 	// Make sure we have an event registered for the transaction, since it
@@ -475,8 +927,10 @@ func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
 	log.Infof("Found inbound asset transfer (asset_id=%x) for Taproot "+
 		"Asset address %s in %s", addr.AssetID[:], addrStr, op.String())
 	status := address.StatusTransactionDetected
+	receiveStatus := ReceiveStatusDetected
 	if walletTx.Confirmations > 0 {
 		status = address.StatusTransactionConfirmed
+		receiveStatus = ReceiveStatusConfirmed
 	}
 
 	// Block here, a shutdown can wait on this operation.
@@ -486,13 +940,69 @@ func (c *Custodian) mapToTapAddr(walletTx *lndclient.Transaction,
 	)
 	cancel()
 	if err != nil {
-		return nil, fmt.Errorf("error creating event: %w", err)
+		return nil, false, fmt.Errorf("error creating event: %w", err)
+	}
+
+	// We persist the quarantine so it survives restarts and can be
+	// queried through the address events.
+	if quarantined && !event.Quarantined {
+		ctxt, cancel = c.CtxBlocking()
+		err = c.cfg.AddrBook.SetEventQuarantined(ctxt, event, true)
+		cancel()
+		if err != nil {
+			return nil, false, fmt.Errorf("error quarantining "+
+				"event: %w", err)
+		}
+
+		event.Quarantined = true
 	}
 
 	// Let's update our cache of ongoing events.
 	c.events[op] = event
+	c.publishReceiveEvent(addr.Tap, op, receiveStatus, nil)
+
+	// An event can also have been quarantined by the partial receive
+	// policy before a restart.
+	return addr.Tap, event.Quarantined, nil
+}
+
+// isAddrReused returns true if the given address already has an event for a
+// confirmed on-chain output other than the given one. Outputs that aren't
+// confirmed yet don't count as reuse, since their transaction might still be
+// replaced. The second returned boolean is true if such an unconfirmed output
+// exists.
+func (c *Custodian) isAddrReused(addr *address.AddrWithKeyInfo,
+	op wire.OutPoint) (bool, bool, error) {
+
+	ctxt, cancel := c.WithCtxQuit()
+	defer cancel()
+
+	events, err := c.cfg.AddrBook.QueryEvents(
+		ctxt, address.EventQueryParams{
+			AddrTaprootOutputKey: schnorr.SerializePubKey(
+				&addr.TaprootOutputKey,
+			),
+		},
+	)
+	if err != nil {
+		return false, false, fmt.Errorf("error querying address "+
+			"events: %w", err)
+	}
+
+	var pending bool
+	for _, event := range events {
+		if event.Outpoint == op {
+			continue
+		}
 
-	return addr.Tap, nil
+		if event.ConfirmationHeight > 0 {
+			return true, false, nil
+		}
+
+		pending = true
+	}
+
+	return false, pending, nil
 }
 
 // importAddrToWallet imports the given Taproot Asset address into the
@@ -528,9 +1038,226 @@ func (c *Custodian) importAddrToWallet(addr *address.AddrWithKeyInfo) error {
 	log.Infof("Imported Taproot Asset address %v into wallet, watching "+
 		"p2tr address %v on chain", addrStr, p2trAddr.String())
 
+	// The output of a transfer to an address that allows any amount can
+	// only be found once we have its proof, so we wait for it. The address
+	// is only set as managed once the proof was received, so we resume
+	// waiting after a restart.
+	if addr.AllowAnyAmount {
+		if c.cfg.ProofCourierCfg != nil {
+			c.Wg.Add(1)
+			go c.receiveAnyAmountProof(addr)
+		}
+
+		return nil
+	}
+
 	return c.cfg.AddrBook.SetAddrManaged(ctxt, addr, time.Now())
 }
 
+// anyAmountProof is a proof of a transfer to an address that allows any
+// amount.
+type anyAmountProof struct {
+	// addr is the address that allows any amount.
+	addr *address.AddrWithKeyInfo
+
+	// proof is the received proof file.
+	proof *proof.AnnotatedProof
+
+	// lastProof is the last proof of the proof file, which proves the
+	// received asset.
+	lastProof *proof.Proof
+}
+
+// receiveAnyAmountProof waits for the proof of a transfer to the given address
+// that allows any amount. The on-chain output of such a transfer commits to
+// the amount chosen by the sender, so it can't be detected on chain before its
+// proof is received. The checked proof is handed to the main event loop.
+//
+// NOTE: This must be run as a goroutine.
+func (c *Custodian) receiveAnyAmountProof(addr *address.AddrWithKeyInfo) {
+	defer c.Wg.Done()
+
+	ctx, cancel := c.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	assetID := addr.AssetID
+	recipient := proof.Recipient{
+		ScriptKey:   &addr.ScriptKey,
+		AssetID:     assetID,
+		Amount:      addr.Amount,
+		InternalKey: addr.InternalKeyDesc,
+	}
+	courier, err := proof.NewCourier(
+		ctx, addr.ProofCourierAddr, c.cfg.ProofCourierCfg, recipient,
+	)
+	if err != nil {
+		log.Errorf("Unable to initiate proof courier service handle: "+
+			"%v", err)
+		return
+	}
+
+	log.Debugf("Waiting to receive proof of any amount for script key %x",
+		addr.ScriptKey.SerializeCompressed())
+
+	// The outpoint of the transfer isn't known yet, so we can only use a
+	// courier that delivers the proof to a mailbox keyed by the script
+	// key.
+	addrProof, err := courier.ReceiveProof(ctx, proof.Locator{
+		AssetID:   &assetID,
+		ScriptKey: addr.ScriptKey,
+	})
+	if err != nil {
+		if !fn.IsCanceled(err) {
+			log.Errorf("Unable to recv proof: %v", err)
+		}
+
+		return
+	}
+
+	lastProof, err := CheckAnyAmountProof(addr, addrProof.Blob)
+	if err != nil {
+		log.Errorf("Rejecting proof for script key %x: %v",
+			addr.ScriptKey.SerializeCompressed(), err)
+		return
+	}
+
+	op := lastProof.OutPoint()
+	addrProof.Locator.OutPoint = &op
+
+	received := &anyAmountProof{
+		addr:      addr,
+		proof:     addrProof,
+		lastProof: lastProof,
+	}
+	select {
+	case c.anyAmountProofs <- received:
+	case <-c.Quit:
+	}
+}
+
+// CheckAnyAmountProof makes sure the given proof file proves a transfer to the
+// given address that allows any amount and returns its last proof. The amount
+// must satisfy the minimum amount of the address, and the anchor output must
+// commit to the received asset the same way it would for an address that
+// requests exactly the received amount.
+func CheckAnyAmountProof(addr *address.AddrWithKeyInfo,
+	blob proof.Blob) (*proof.Proof, error) {
+
+	file := proof.NewEmptyFile(proof.V0)
+	if err := file.Decode(bytes.NewReader(blob)); err != nil {
+		return nil, fmt.Errorf("error decoding proof file: %w", err)
+	}
+
+	lastProof, err := file.LastProof()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching last proof: %w", err)
+	}
+
+	if !AddrMatchesAsset(addr, &lastProof.Asset) {
+		return nil, fmt.Errorf("proof doesn't match address asset")
+	}
+
+	amountAddr, err := addr.ForAmount(lastProof.Asset.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedKey, err := amountAddr.TaprootOutputKey()
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive Taproot output key: "+
+			"%w", err)
+	}
+
+	outputKey, err := proof.ExtractTaprootKey(
+		&lastProof.AnchorTx, lastProof.InclusionProof.OutputIndex,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting taproot key: %w", err)
+	}
+
+	expectedKeyBytes := schnorr.SerializePubKey(expectedKey)
+	if !bytes.Equal(expectedKeyBytes, schnorr.SerializePubKey(outputKey)) {
+		return nil, fmt.Errorf("anchor output doesn't commit to %d "+
+			"received asset units", lastProof.Asset.Amount)
+	}
+
+	return lastProof, nil
+}
+
+// handleAnyAmountProof creates the inbound transfer event for the proof of a
+// transfer to an address that allows any amount and imports the proof. The
+// event is tracked with the address that requests exactly the received
+// amount.
+func (c *Custodian) handleAnyAmountProof(p *anyAmountProof) error {
+	op := p.lastProof.OutPoint()
+	amount := p.lastProof.Asset.Amount
+
+	// Let's not be interrupted by a shutdown.
+	ctxt, cancel := c.CtxBlocking()
+	defer cancel()
+
+	amountAddr, err := c.cfg.AddrBook.AddrForAmount(ctxt, p.addr, amount)
+	if err != nil {
+		return fmt.Errorf("unable to store address for received "+
+			"amount: %w", err)
+	}
+
+	log.Infof("Found inbound asset transfer (asset_id=%x) of %d units "+
+		"for any amount address in %v", p.addr.AssetID[:], amount, op)
+
+	event, err := c.cfg.AddrBook.GetOrCreateEvent(
+		ctxt, address.StatusTransactionConfirmed, amountAddr,
+		proofWalletTx(p.lastProof), op.Index,
+	)
+	if err != nil {
+		return fmt.Errorf("error creating event: %w", err)
+	}
+
+	c.events[op] = event
+	c.publishReceiveEvent(amountAddr.Tap, op, ReceiveStatusConfirmed, nil)
+	c.publishReceiveEvent(
+		amountAddr.Tap, op, ReceiveStatusProofReceived, nil,
+	)
+
+	// Now that the address received its transfer, we no longer wait for
+	// its proof after a restart.
+	err = c.cfg.AddrBook.SetAddrManaged(ctxt, p.addr, time.Now())
+	if err != nil {
+		return fmt.Errorf("unable to set address managed: %w", err)
+	}
+
+	c.Wg.Add(1)
+	go func() {
+		defer c.Wg.Done()
+
+		c.importProof(p.proof, event)
+	}()
+
+	return nil
+}
+
+// proofWalletTx returns the anchor transaction of the given proof in the form
+// the wallet reports confirmed transactions in.
+func proofWalletTx(p *proof.Proof) *lndclient.Transaction {
+	outputDetails := make([]*lnrpc.OutputDetail, len(p.AnchorTx.TxOut))
+	for idx, txOut := range p.AnchorTx.TxOut {
+		outputDetails[idx] = &lnrpc.OutputDetail{
+			OutputIndex: int64(idx),
+			Amount:      txOut.Value,
+		}
+	}
+
+	blockHash := p.BlockHeader.BlockHash()
+	return &lndclient.Transaction{
+		Tx:            &p.AnchorTx,
+		TxHash:        p.AnchorTx.TxHash().String(),
+		Confirmations: 1,
+		BlockHash:     blockHash.String(),
+		BlockHeight:   int32(p.BlockHeight),
+		OutputDetails: outputDetails,
+	}
+}
+
 // checkProofAvailable checks the proof storage if a proof for the given event
 // is already available. If it is, and it checks out, the event is updated.
 func (c *Custodian) checkProofAvailable(event *address.Event) error {
@@ -620,6 +1347,14 @@ func (c *Custodian) mapProofToEvent(p proof.Blob) error {
 			}
 
 			delete(c.events, event.Outpoint)
+
+			// The proof might have been imported manually after
+			// its automatic import failed.
+			err = c.removeDeadLetter(event.Outpoint)
+			if err != nil {
+				return fmt.Errorf("error removing dead "+
+					"letter: %w", err)
+			}
 		}
 	}
 
@@ -653,9 +1388,28 @@ func (c *Custodian) setReceiveCompleted(event *address.Event,
 		Index: lastProof.InclusionProof.OutputIndex,
 	}
 
-	return c.cfg.AddrBook.CompleteEvent(
+	// We record the amount that was actually received, which can be less
+	// than requested if a partial receive was accepted or its proof was
+	// imported manually.
+	event.AssetAmt = lastProof.Asset.Amount
+	if shortfall := event.Shortfall(); shortfall != 0 {
+		log.Warnf("Completing inbound asset transfer in %v with a "+
+			"shortfall of %d asset units", event.Outpoint,
+			shortfall)
+	}
+
+	err = c.cfg.AddrBook.CompleteEvent(
 		ctxt, event, address.StatusCompleted, anchorPoint,
 	)
+	if err != nil {
+		return err
+	}
+
+	c.publishReceiveEvent(
+		event.Addr.Tap, event.Outpoint, ReceiveStatusCompleted, nil,
+	)
+
+	return nil
 }
 
 // hasWalletTaprootOutput returns true if one of the outputs of the given
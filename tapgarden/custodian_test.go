@@ -1,14 +1,18 @@
 package tapgarden_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"math/rand"
+	"net"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/address"
@@ -312,6 +316,304 @@ func TestTransactionHandling(t *testing.T) {
 	})
 }
 
+// TestReceiveEvents makes sure the subscribers of the custodian are notified
+// about a detected inbound transfer.
+func TestReceiveEvents(t *testing.T) {
+	h := newHarness(t, nil)
+
+	ctx := context.Background()
+	addr := randAddr(h)
+	err := h.tapdbBook.InsertAddrs(ctx, *addr)
+	require.NoError(t, err)
+
+	outputIdx, tx := randWalletTx(addr)
+	h.walletAnchor.Transactions = append(h.walletAnchor.Transactions, *tx)
+
+	sub := fn.NewEventReceiver[fn.Event](fn.DefaultQueueSize)
+	require.NoError(t, h.c.RegisterSubscriber(sub, false, false))
+
+	require.NoError(t, h.c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.c.Stop())
+	})
+	h.assertStartup()
+
+	event, err := fn.RecvOrTimeout(
+		sub.NewItemCreated.ChanOut(), testTimeout,
+	)
+	require.NoError(t, err)
+
+	receiveEvent, ok := (*event).(*tapgarden.AssetReceiveEvent)
+	require.True(t, ok)
+	require.Equal(t, tapgarden.ReceiveStatusDetected, receiveEvent.Status)
+	require.True(t, addr.ScriptKey.IsEqual(&receiveEvent.ScriptKey))
+	require.Equal(t, wire.OutPoint{
+		Hash:  tx.Tx.TxHash(),
+		Index: uint32(outputIdx),
+	}, receiveEvent.OutPoint)
+
+	require.NoError(t, h.c.RemoveSubscriber(sub))
+}
+
+// TestAddrReusePolicy makes sure a second inbound transfer to the same address
+// is handled according to the configured address reuse policy.
+func TestAddrReusePolicy(t *testing.T) {
+	testCases := []struct {
+		name             string
+		policy           tapgarden.AddrReusePolicy
+		firstConfirmed   bool
+		expectEvent      bool
+		expectFetch      bool
+		expectQuarantine bool
+	}{{
+		name:           "accept",
+		policy:         tapgarden.AddrReuseAccept,
+		firstConfirmed: true,
+		expectEvent:    true,
+		expectFetch:    true,
+	}, {
+		name:           "reject",
+		policy:         tapgarden.AddrReuseReject,
+		firstConfirmed: true,
+	}, {
+		name:             "quarantine",
+		policy:           tapgarden.AddrReuseQuarantine,
+		firstConfirmed:   true,
+		expectEvent:      true,
+		expectQuarantine: true,
+	}, {
+		// A first transfer that never confirmed (e.g. because it was
+		// replaced by RBF) doesn't count as reuse.
+		name:        "reject replaced first transfer",
+		policy:      tapgarden.AddrReuseReject,
+		expectEvent: true,
+		expectFetch: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHarness(t, nil)
+			h.cfg.AddrReusePolicy = tc.policy
+			h.cfg.ProofCourierCfg = &proof.CourierCfg{}
+
+			// We count the connections to the proof courier of the
+			// address, to find out whether a proof fetch was
+			// started.
+			courierConns := listenCourier(t)
+
+			ctx := context.Background()
+			addr := randAddrWithCourier(h, courierConns.addr)
+			err := h.tapdbBook.InsertAddrs(ctx, *addr)
+			require.NoError(t, err)
+
+			// The address already received assets in a first
+			// transfer, which was completed before the custodian
+			// started.
+			idx1, tx1 := randWalletTx(addr)
+			status := address.StatusTransactionDetected
+			if tc.firstConfirmed {
+				confirmWalletTx(tx1, 100)
+				status = address.StatusCompleted
+			}
+			_, err = h.tapdbBook.GetOrCreateEvent(
+				ctx, status, addr, tx1, uint32(idx1),
+			)
+			require.NoError(t, err)
+
+			// We now send to the same address again, in a different
+			// transaction that confirms.
+			idx2, tx2 := randWalletTx(addr)
+			confirmWalletTx(tx2, 101)
+			h.walletAnchor.Transactions = append(
+				h.walletAnchor.Transactions, *tx2,
+			)
+
+			require.NoError(t, h.c.Start())
+			t.Cleanup(func() {
+				require.NoError(t, h.c.Stop())
+			})
+			h.assertStartup()
+			h.assertAddrsRegistered(addr)
+
+			op2 := wire.OutPoint{
+				Hash:  tx2.Tx.TxHash(),
+				Index: uint32(idx2),
+			}
+			fetchEvent2 := func() *address.Event {
+				events, err := h.tapdbBook.QueryAddrEvents(
+					ctx, address.EventQueryParams{},
+				)
+				require.NoError(t, err)
+
+				for _, event := range events {
+					if event.Outpoint == op2 {
+						return event
+					}
+				}
+
+				return nil
+			}
+
+			if !tc.expectEvent {
+				require.Never(t, func() bool {
+					return fetchEvent2() != nil ||
+						courierConns.connected()
+				}, testTimeout, testPollInterval)
+
+				return
+			}
+
+			h.eventually(func() bool {
+				return fetchEvent2() != nil
+			})
+
+			// The quarantine must be persisted with the event.
+			event2 := fetchEvent2()
+			require.Equal(
+				t, tc.expectQuarantine, event2.Quarantined,
+			)
+
+			if tc.expectFetch {
+				h.eventually(courierConns.connected)
+
+				return
+			}
+
+			require.Never(
+				t, courierConns.connected, testTimeout,
+				testPollInterval,
+			)
+		})
+	}
+}
+
+// TestAddrReuseDeferredUntilConfirmed makes sure that an unconfirmed transfer
+// to an address that has other unconfirmed transfers is only evaluated against
+// the address reuse policy once it confirms.
+func TestAddrReuseDeferredUntilConfirmed(t *testing.T) {
+	h := newHarness(t, nil)
+	h.cfg.AddrReusePolicy = tapgarden.AddrReuseReject
+
+	ctx := context.Background()
+	addr := randAddr(h)
+	err := h.tapdbBook.InsertAddrs(ctx, *addr)
+	require.NoError(t, err)
+
+	// We send to the same address twice, in two unconfirmed transactions.
+	// One of them might just replace the other one.
+	_, tx1 := randWalletTx(addr)
+	_, tx2 := randWalletTx(addr)
+	h.walletAnchor.Transactions = append(
+		h.walletAnchor.Transactions, *tx1, *tx2,
+	)
+
+	require.NoError(t, h.c.Start())
+	t.Cleanup(func() {
+		require.NoError(t, h.c.Stop())
+	})
+	h.assertStartup()
+	h.assertAddrsRegistered(addr)
+
+	numEvents := func() int {
+		events, err := h.tapdbBook.QueryAddrEvents(
+			ctx, address.EventQueryParams{},
+		)
+		require.NoError(t, err)
+
+		return len(events)
+	}
+
+	// Only the first transfer is known until the second one confirms.
+	h.eventually(func() bool {
+		return numEvents() == 1
+	})
+	require.Never(t, func() bool {
+		return numEvents() != 1
+	}, testTimeout, testPollInterval)
+
+	// The first transaction was replaced by the second one, which now
+	// confirms. Since the first one never confirmed, this isn't reuse.
+	confirmWalletTx(tx2, 100)
+	select {
+	case h.walletAnchor.SubscribeTx <- *tx2:
+	case <-time.After(testTimeout):
+		t.Fatalf("timeout sending confirmed transaction")
+	}
+
+	h.eventually(func() bool {
+		return numEvents() == 2
+	})
+}
+
+// confirmWalletTx marks the given wallet transaction as confirmed at the given
+// height.
+func confirmWalletTx(tx *lndclient.Transaction, height int32) {
+	tx.Confirmations = 1
+	tx.BlockHeight = height
+	tx.BlockHash = chainhash.Hash{byte(height)}.String()
+}
+
+// randAddrWithCourier creates a random address that uses the given proof
+// courier.
+func randAddrWithCourier(h *custodianHarness,
+	courierAddr url.URL) *address.AddrWithKeyInfo {
+
+	addr, genesis, group := address.RandAddr(
+		h.t, &address.RegressionNetTap, courierAddr,
+	)
+
+	err := h.tapdbBook.InsertAssetGen(context.Background(), genesis, group)
+	require.NoError(h.t, err)
+
+	return addr
+}
+
+// courierListener accepts connections to a hashmail proof courier and records
+// whether anyone connected.
+type courierListener struct {
+	addr  url.URL
+	conns chan struct{}
+}
+
+// listenCourier starts a courierListener on a random local port.
+func listenCourier(t *testing.T) *courierListener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	l := &courierListener{
+		addr: url.URL{
+			Scheme: proof.HashmailCourierType,
+			Host:   listener.Addr().String(),
+		},
+		conns: make(chan struct{}, 1),
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+
+			select {
+			case l.conns <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return l
+}
+
+// connected returns true if anyone connected to the courier.
+func (l *courierListener) connected() bool {
+	return len(l.conns) > 0
+}
+
 func mustMakeAddr(t *testing.T,
 	gen asset.Genesis, groupKey *btcec.PublicKey,
 	groupWitness wire.TxWitness, scriptKey btcec.PublicKey) *address.Tap {
@@ -469,3 +771,110 @@ func TestAddrMatchesAsset(t *testing.T) {
 		})
 	}
 }
+
+// TestCheckProofChainDepth makes sure proof files with more state transitions
+// than the maximum proof chain depth are rejected.
+func TestCheckProofChainDepth(t *testing.T) {
+	t.Parallel()
+
+	const numProofs = 3
+	proofs := make([]proof.Proof, numProofs)
+	for idx := range proofs {
+		proofs[idx] = proof.Proof{
+			AnchorTx: wire.MsgTx{
+				Version: 2,
+				TxIn:    []*wire.TxIn{{}},
+			},
+			Asset: *asset.RandAsset(t, asset.Normal),
+			InclusionProof: proof.TaprootProof{
+				InternalKey: test.RandPubKey(t),
+			},
+		}
+	}
+
+	file, err := proof.NewFile(proof.V0, proofs...)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Encode(&buf))
+	blob := proof.Blob(buf.Bytes())
+
+	// No limit and limits that aren't exceeded accept the proof.
+	require.NoError(t, tapgarden.CheckProofChainDepth(blob, 0))
+	require.NoError(t, tapgarden.CheckProofChainDepth(blob, numProofs))
+	require.NoError(t, tapgarden.CheckProofChainDepth(blob, numProofs+1))
+
+	err = tapgarden.CheckProofChainDepth(blob, numProofs-1)
+	require.ErrorIs(t, err, tapgarden.ErrProofChainTooLong)
+
+	// A proof that can't be decoded is left to the import to reject.
+	require.NoError(t, tapgarden.CheckProofChainDepth(blob[:10], 1))
+}
+
+// TestCheckAnyAmountProof makes sure proofs of transfers to an address that
+// allows any amount are only accepted if the amount satisfies the minimum
+// amount and the anchor output commits to it.
+func TestCheckAnyAmountProof(t *testing.T) {
+	t.Parallel()
+
+	gen := asset.RandGenesis(t, asset.Normal)
+	scriptKey, internalKey := test.RandPubKey(t), test.RandPubKey(t)
+	tapAddr, err := address.New(
+		address.V0, gen, nil, nil, *scriptKey, *internalKey, 0, nil,
+		chainParams, address.RandProofCourierAddr(t),
+		address.WithAnyAmount(10),
+	)
+	require.NoError(t, err)
+	addr := &address.AddrWithKeyInfo{
+		Tap: tapAddr,
+	}
+
+	// makeProof creates a proof file that sends the given amount to the
+	// address, anchored in an output that commits to the given amount.
+	makeProof := func(amount, committedAmount uint64) proof.Blob {
+		amountAddr := tapAddr.Copy()
+		amountAddr.Amount = committedAmount
+		outputKey, err := amountAddr.TaprootOutputKey()
+		require.NoError(t, err)
+		pkScript, err := tapscript.PayToTaprootScript(outputKey)
+		require.NoError(t, err)
+
+		newAsset, err := asset.New(
+			gen, amount, 0, 0, asset.NewScriptKey(scriptKey), nil,
+		)
+		require.NoError(t, err)
+
+		file, err := proof.NewFile(proof.V0, proof.Proof{
+			AnchorTx: wire.MsgTx{
+				Version: 2,
+				TxIn:    []*wire.TxIn{{}},
+				TxOut: []*wire.TxOut{{
+					PkScript: pkScript,
+					Value:    1000,
+				}},
+			},
+			Asset: *newAsset,
+			InclusionProof: proof.TaprootProof{
+				InternalKey: internalKey,
+			},
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, file.Encode(&buf))
+
+		return buf.Bytes()
+	}
+
+	lastProof, err := tapgarden.CheckAnyAmountProof(
+		addr, makeProof(25, 25),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 25, lastProof.Asset.Amount)
+
+	_, err = tapgarden.CheckAnyAmountProof(addr, makeProof(5, 5))
+	require.ErrorIs(t, err, address.ErrAmountBelowMinimum)
+
+	_, err = tapgarden.CheckAnyAmountProof(addr, makeProof(25, 30))
+	require.ErrorContains(t, err, "doesn't commit")
+}
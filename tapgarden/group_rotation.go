@@ -0,0 +1,369 @@
+package tapgarden
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// ErrGroupAuthorityNotLocal is returned if the key that holds the issuance
+// authority of an asset group isn't controlled by the local wallet.
+var ErrGroupAuthorityNotLocal = errors.New("issuance authority of group " +
+	"not held by a local key")
+
+// GroupAuthority is the issuance authority of an asset group.
+type GroupAuthority struct {
+	// Group is the asset group.
+	Group *asset.AssetGroup
+
+	// Rotations is the chain of confirmed rotations of the issuance
+	// authority of the group, ordered by their sequence.
+	Rotations []*proof.GroupKeyRotation
+
+	// AuthorityKey is the key that currently holds the issuance authority
+	// of the group. This is the raw group key if the authority was never
+	// rotated. The key locator is only set for local keys.
+	AuthorityKey keychain.KeyDescriptor
+}
+
+// MessageSigner signs arbitrary messages with local keys.
+type MessageSigner interface {
+	// SignMessage creates a Schnorr signature over the SHA256 hash of the
+	// given message with the key at the given locator.
+	SignMessage(ctx context.Context, keyLoc keychain.KeyLocator,
+		msg []byte) (*schnorr.Signature, error)
+}
+
+// IssuanceAuthorizer creates the issuance authority for reissuances into
+// asset groups whose issuance authority was rotated.
+type IssuanceAuthorizer interface {
+	// AuthorizeIssuance returns the issuance authority for the issuance
+	// of the asset with the given ID into the group with the given key at
+	// the given height. Nil is returned if the issuance authority of the
+	// group wasn't rotated before that height.
+	AuthorizeIssuance(ctx context.Context, groupKey *btcec.PublicKey,
+		assetID asset.ID,
+		issuanceHeight uint32) (*proof.IssuanceAuthority, error)
+}
+
+// GroupKeyRotatorConfig is the config for the GroupKeyRotator.
+type GroupKeyRotatorConfig struct {
+	// Wallet is used to fund and sign the rotation transactions.
+	Wallet WalletAnchor
+
+	// ChainBridge is used to publish the rotation transactions and wait
+	// for their confirmation.
+	ChainBridge ChainBridge
+
+	// Log stores the rotations of the issuance authority of asset groups.
+	Log MintingStore
+
+	// KeyRing is used to derive the new authority keys.
+	KeyRing KeyRing
+
+	// Signer is used to sign rotations and issuances with the current
+	// authority key.
+	Signer MessageSigner
+}
+
+// GroupKeyRotator rotates the key that authorizes the issuance of assets into
+// an asset group, and authorizes reissuances with the current key.
+type GroupKeyRotator struct {
+	cfg *GroupKeyRotatorConfig
+
+	// rotationMtx makes sure only a single rotation is in flight at a
+	// time, so no two rotations are created with the same sequence.
+	rotationMtx sync.Mutex
+}
+
+// NewGroupKeyRotator creates a new group key rotator from the given config.
+func NewGroupKeyRotator(cfg *GroupKeyRotatorConfig) *GroupKeyRotator {
+	return &GroupKeyRotator{
+		cfg: cfg,
+	}
+}
+
+// RotateGroupKey hands the issuance authority of the asset group with the
+// given key over to a newly derived key. The rotation is signed by the current
+// authority key and anchored on-chain, and this method blocks until the
+// anchor transaction is confirmed. Only once the rotation is confirmed, future
+// reissuances into the group are authorized by the new key.
+//
+// NOTE: If the rotation is interrupted before its anchor transaction is
+// confirmed, the rotation is not recorded and the current key stays in charge.
+// The rotation can then simply be attempted again.
+func (r *GroupKeyRotator) RotateGroupKey(ctx context.Context,
+	groupKey *btcec.PublicKey,
+	feeRate chainfee.SatPerKWeight) (*proof.GroupKeyRotation, error) {
+
+	r.rotationMtx.Lock()
+	defer r.rotationMtx.Unlock()
+
+	authority, err := r.cfg.Log.FetchGroupAuthority(ctx, groupKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch group authority: %w",
+			err)
+	}
+
+	prevKey := authority.AuthorityKey
+	if !r.cfg.KeyRing.IsLocalKey(ctx, prevKey) {
+		return nil, ErrGroupAuthorityNotLocal
+	}
+
+	newKey, err := r.cfg.KeyRing.DeriveNextKey(
+		ctx, asset.TaprootAssetsKeyFamily,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive new authority key: "+
+			"%w", err)
+	}
+
+	sequence := uint32(len(authority.Rotations))
+	sig, err := r.cfg.Signer.SignMessage(
+		ctx, prevKey.KeyLocator, proof.RotationMessage(
+			groupKey, prevKey.PubKey, newKey.PubKey, sequence,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign rotation: %w", err)
+	}
+
+	rotationScript, err := proof.RotationScript(
+		groupKey, prevKey.PubKey, newKey.PubKey, sequence,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	anchorTx, err := r.publishRotation(ctx, rotationScript, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	outputIndex := -1
+	for idx, txOut := range anchorTx.TxOut {
+		if bytes.Equal(txOut.PkScript, rotationScript) {
+			outputIndex = idx
+			break
+		}
+	}
+	if outputIndex < 0 {
+		return nil, fmt.Errorf("rotation output missing from anchor " +
+			"transaction")
+	}
+
+	log.Infof("Rotating issuance authority of group %x to key %x with "+
+		"txid=%v", groupKey.SerializeCompressed(),
+		newKey.PubKey.SerializeCompressed(), anchorTx.TxHash())
+
+	rotation := &proof.GroupKeyRotation{
+		GroupKey:    groupKey,
+		Sequence:    sequence,
+		PrevKey:     prevKey.PubKey,
+		NewKey:      newKey.PubKey,
+		Signature:   sig,
+		AnchorTx:    *anchorTx,
+		OutputIndex: uint32(outputIndex),
+	}
+	if err := r.waitForConfirmation(ctx, rotation); err != nil {
+		return nil, err
+	}
+
+	err = r.cfg.Log.AddGroupKeyRotation(ctx, rotation, newKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to store rotation: %w", err)
+	}
+
+	return rotation, nil
+}
+
+// publishRotation funds, signs and publishes a transaction with an OP_RETURN
+// output that carries the given rotation script.
+func (r *GroupKeyRotator) publishRotation(ctx context.Context,
+	rotationScript []byte,
+	feeRate chainfee.SatPerKWeight) (*wire.MsgTx, error) {
+
+	txTemplate := wire.NewMsgTx(2)
+	txTemplate.AddTxOut(&wire.TxOut{
+		PkScript: rotationScript,
+	})
+	rotationPkt, err := psbt.NewFromUnsignedTx(txTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make psbt packet: %w", err)
+	}
+
+	if feeRate == 0 {
+		feeRate, err = r.cfg.ChainBridge.EstimateFee(
+			ctx, GenesisConfTarget,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to estimate fee: %w",
+				err)
+		}
+	}
+
+	fundedPkt, err := r.cfg.Wallet.FundPsbt(ctx, rotationPkt, 1, feeRate)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fund rotation: %w", err)
+	}
+
+	signedPkt, err := r.cfg.Wallet.SignAndFinalizePsbt(ctx, fundedPkt.Pkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign rotation: %w", err)
+	}
+
+	anchorTx, err := psbt.Extract(signedPkt)
+	if err != nil {
+		return nil, fmt.Errorf("unable to extract rotation "+
+			"transaction: %w", err)
+	}
+
+	err = r.cfg.ChainBridge.PublishTransaction(ctx, anchorTx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to publish rotation: %w", err)
+	}
+
+	return anchorTx, nil
+}
+
+// waitForConfirmation waits for the anchor transaction of the rotation to
+// confirm and adds the block location to the rotation.
+func (r *GroupKeyRotator) waitForConfirmation(ctx context.Context,
+	rotation *proof.GroupKeyRotation) error {
+
+	heightHint, err := r.cfg.ChainBridge.CurrentHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch current height: %w", err)
+	}
+
+	txHash := rotation.AnchorTx.TxHash()
+	pkScript := rotation.AnchorTx.TxOut[rotation.OutputIndex].PkScript
+	confNtfn, errChan, err := r.cfg.ChainBridge.RegisterConfirmationsNtfn(
+		ctx, &txHash, pkScript, 1, heightHint, true, nil,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register for rotation conf: %w",
+			err)
+	}
+
+	select {
+	case confEvent := <-confNtfn.Confirmed:
+		merkleProof, err := proof.NewTxMerkleProof(
+			confEvent.Block.Transactions, int(confEvent.TxIndex),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to create merkle proof: %w",
+				err)
+		}
+
+		rotation.TxMerkleProof = *merkleProof
+		rotation.BlockHeader = confEvent.Block.Header
+		rotation.BlockHeight = confEvent.BlockHeight
+
+		return nil
+
+	case err := <-errChan:
+		return fmt.Errorf("error getting rotation confirmation: %w",
+			err)
+
+	case <-ctx.Done():
+		return fmt.Errorf("rotation not confirmed: %w", ctx.Err())
+	}
+}
+
+// AuthorizeIssuance returns the issuance authority for the issuance of the
+// asset with the given ID into the group with the given key at the given
+// height. Nil is returned if the issuance authority of the group wasn't
+// rotated before that height.
+func (r *GroupKeyRotator) AuthorizeIssuance(ctx context.Context,
+	groupKey *btcec.PublicKey, assetID asset.ID,
+	issuanceHeight uint32) (*proof.IssuanceAuthority, error) {
+
+	authority, err := r.cfg.Log.FetchGroupAuthority(ctx, groupKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch group authority: %w",
+			err)
+	}
+
+	// Only the rotations that confirmed before the issuance are binding
+	// for it.
+	var numBinding int
+	for _, rotation := range authority.Rotations {
+		if rotation.BlockHeight > issuanceHeight {
+			break
+		}
+		numBinding++
+	}
+	if numBinding == 0 {
+		return nil, nil
+	}
+
+	// We only know the key locator of the latest authority key, so we
+	// can't authorize an issuance that confirmed in between two rotations.
+	if numBinding < len(authority.Rotations) {
+		return nil, fmt.Errorf("issuance at height %d was superseded "+
+			"by rotation %d of group authority", issuanceHeight,
+			numBinding)
+	}
+
+	authorityKey := authority.AuthorityKey
+	if !r.cfg.KeyRing.IsLocalKey(ctx, authorityKey) {
+		return nil, ErrGroupAuthorityNotLocal
+	}
+
+	sig, err := r.cfg.Signer.SignMessage(
+		ctx, authorityKey.KeyLocator,
+		proof.IssuanceMessage(groupKey, assetID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign issuance: %w", err)
+	}
+
+	return &proof.IssuanceAuthority{
+		GroupAnchorID:      authority.Group.Genesis.ID(),
+		GroupTapscriptRoot: authority.Group.TapscriptRoot,
+		Rotations:          authority.Rotations,
+		Signature:          sig,
+	}, nil
+}
+
+// authorizeReissuances adds the issuance authority to all reissuance proofs of
+// a batch that was confirmed at the given height.
+func authorizeReissuances(ctx context.Context, authorizer IssuanceAuthorizer,
+	mintingProofs proof.AssetProofs,
+	issuanceHeight uint32) error {
+
+	for _, mintingProof := range mintingProofs {
+		groupKey := mintingProof.Asset.GroupKey
+		if groupKey == nil || mintingProof.GroupKeyReveal != nil {
+			continue
+		}
+
+		authority, err := authorizer.AuthorizeIssuance(
+			ctx, &groupKey.GroupPubKey, mintingProof.Asset.ID(),
+			issuanceHeight,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to authorize reissuance: %w",
+				err)
+		}
+		mintingProof.IssuanceAuthority = authority
+	}
+
+	return nil
+}
+
+// A compile time assertion to ensure GroupKeyRotator meets the
+// IssuanceAuthorizer interface.
+var _ IssuanceAuthorizer = (*GroupKeyRotator)(nil)
@@ -3,6 +3,7 @@ package tapgarden
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -12,6 +13,7 @@ import (
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/fn"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -46,9 +48,22 @@ type Planter interface {
 	// the current batch, if one exists.
 	FinalizeBatch() (*MintingBatch, error)
 
+	// ScheduleFinalize signals that the asset minter should finalize the
+	// current batch automatically once the given schedule is due.
+	ScheduleFinalize(schedule FinalizeSchedule) (*MintingBatch, error)
+
 	// CancelBatch signals that the asset minter should cancel the
 	// current batch, if one exists.
-	CancelBatch() (*btcec.PublicKey, error)
+	CancelBatch() (*CancelledBatch, error)
+
+	// RegisterSubscriber adds a new subscriber that is notified with the
+	// key of each batch that is finalized from now on, which means that
+	// the assets of the batch were created.
+	RegisterSubscriber(receiver *fn.EventReceiver[BatchKey]) error
+
+	// RemoveSubscriber removes the given subscriber and also stops it
+	// from processing events.
+	RemoveSubscriber(subscriber *fn.EventReceiver[BatchKey]) error
 
 	// Start signals that the asset minter should being operations.
 	Start() error
@@ -178,6 +193,12 @@ type MintingStore interface {
 	UpdateBatchState(ctx context.Context, batchKey *btcec.PublicKey,
 		newState BatchState) error
 
+	// UpdateBatchFinalizeSchedule updates the finalization schedule of
+	// the batch on disk identified by the batch key. A nil schedule
+	// removes the schedule of the batch.
+	UpdateBatchFinalizeSchedule(ctx context.Context,
+		batchKey *btcec.PublicKey, schedule *FinalizeSchedule) error
+
 	// AddSeedlingsToBatch adds a new seedling to an existing batch. Once
 	// added this batch should remain in the BatchStatePending state.
 	//
@@ -219,13 +240,15 @@ type MintingStore interface {
 
 	// MarkBatchConfirmed marks the batch as confirmed on chain. The passed
 	// block location information determines where exactly in the chain the
-	// batch was confirmed.
+	// batch was confirmed. The block time is recorded as the acquisition
+	// time of the minted assets.
 	//
 	// NOTE: The BatchState should transition to the BatchStateConfirmed
 	// state upon a successful call.
 	MarkBatchConfirmed(ctx context.Context, batchKey *btcec.PublicKey,
 		blockHash *chainhash.Hash, blockHeight uint32,
-		txIndex uint32, mintingProofs proof.AssetBlobs) error
+		txIndex uint32, blockTime time.Time,
+		mintingProofs proof.AssetBlobs) error
 
 	// FetchGroupByGenesis fetches the asset group created by the genesis
 	// referenced by the given ID.
@@ -236,6 +259,26 @@ type MintingStore interface {
 	// key, including the genesis information used to create the group.
 	FetchGroupByGroupKey(ctx context.Context,
 		groupKey *btcec.PublicKey) (*asset.AssetGroup, error)
+
+	// FetchGroupAuthority fetches the asset group with a matching tweaked
+	// key, along with the current issuance authority of the group.
+	FetchGroupAuthority(ctx context.Context,
+		groupKey *btcec.PublicKey) (*GroupAuthority, error)
+
+	// AddGroupKeyRotation adds a confirmed rotation of the issuance
+	// authority of an asset group, which hands the authority to the given
+	// local key. An error is returned if the rotation doesn't continue the
+	// known rotation chain of the group, or if a competing rotation that
+	// was confirmed earlier is known.
+	AddGroupKeyRotation(ctx context.Context,
+		rotation *proof.GroupKeyRotation,
+		newKey keychain.KeyDescriptor) error
+
+	// GroupKeyRotations returns the known rotations of the issuance
+	// authority of the asset group with the given tweaked key, ordered by
+	// their sequence.
+	GroupKeyRotations(ctx context.Context,
+		groupKey *btcec.PublicKey) ([]*proof.GroupKeyRotation, error)
 }
 
 // ChainBridge is our bridge to the target chain. It's used to get confirmation
@@ -250,6 +293,12 @@ type ChainBridge interface {
 		reOrgChan chan struct{}) (*chainntnfs.ConfirmationEvent,
 		chan error, error)
 
+	// RegisterSpendNtfn registers an intent to be notified once the given
+	// outpoint is spent on chain.
+	RegisterSpendNtfn(ctx context.Context, outpoint *wire.OutPoint,
+		pkScript []byte, heightHint uint32) (*chainntnfs.SpendEvent,
+		chan error, error)
+
 	// RegisterBlockEpochNtfn registers an intent to be notified of each
 	// new block connected to the main chain.
 	RegisterBlockEpochNtfn(ctx context.Context) (chan int32, chan error,
@@ -315,9 +364,9 @@ type WalletAnchor interface {
 	// P2TR output.
 	ImportTaprootOutput(context.Context, *btcec.PublicKey) (btcutil.Address, error)
 
-	// UnlockInput unlocks the set of target inputs after a batch is
-	// abandoned.
-	UnlockInput(context.Context) error
+	// ReleaseOutput releases the lease the wallet acquired on the given
+	// output when funding a PSBT with it, so it can be selected again.
+	ReleaseOutput(ctx context.Context, op wire.OutPoint) error
 
 	// ListUnspentImportScripts lists all UTXOs of the imported Taproot
 	// scripts.
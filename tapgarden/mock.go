@@ -163,7 +163,7 @@ func (m *MockWalletAnchor) ImportTaprootOutput(ctx context.Context,
 	)
 }
 
-func (m *MockWalletAnchor) UnlockInput(_ context.Context) error {
+func (m *MockWalletAnchor) ReleaseOutput(context.Context, wire.OutPoint) error {
 	return nil
 }
 
@@ -299,6 +299,24 @@ func (m *MockChainBridge) RegisterConfirmationsNtfn(ctx context.Context,
 	return req, errChan, nil
 }
 
+// RegisterSpendNtfn registers an intent to be notified once the given
+// outpoint is spent on chain.
+func (m *MockChainBridge) RegisterSpendNtfn(ctx context.Context,
+	_ *wire.OutPoint, _ []byte, _ uint32) (*chainntnfs.SpendEvent,
+	chan error, error) {
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, fmt.Errorf("shutting down")
+	default:
+	}
+
+	return &chainntnfs.SpendEvent{
+		Spend:  make(chan *chainntnfs.SpendDetail, 1),
+		Cancel: func() {},
+	}, make(chan error), nil
+}
+
 func (m *MockChainBridge) RegisterBlockEpochNtfn(
 	ctx context.Context) (chan int32, chan error, error) {
 
@@ -0,0 +1,158 @@
+package tapgarden
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+// PartialReceivePolicy is an enum that describes how the custodian handles an
+// inbound transfer that carries fewer asset units than requested by the
+// address it was sent to.
+type PartialReceivePolicy uint8
+
+const (
+	// PartialReceiveAccept means a partial receive is taken custody of
+	// like any other. The actual amount is recorded on the address event.
+	PartialReceiveAccept PartialReceivePolicy = iota
+
+	// PartialReceiveReject means the proof of a partial receive is not
+	// imported. The address event is never completed.
+	PartialReceiveReject
+
+	// PartialReceiveQuarantine means the proof of a partial receive is
+	// moved to the dead letters instead of being imported and its address
+	// event is marked as quarantined. The transfer is only completed once
+	// the proof is imported manually.
+	PartialReceiveQuarantine
+)
+
+// String returns a human-readable string for the partial receive policy.
+func (p PartialReceivePolicy) String() string {
+	switch p {
+	case PartialReceiveAccept:
+		return "accept"
+
+	case PartialReceiveReject:
+		return "reject"
+
+	case PartialReceiveQuarantine:
+		return "quarantine"
+
+	default:
+		return fmt.Sprintf("<unknown_partial_receive_policy(%d)>", p)
+	}
+}
+
+// ParsePartialReceivePolicy parses a partial receive policy string.
+func ParsePartialReceivePolicy(policy string) (PartialReceivePolicy, error) {
+	switch policy {
+	case "", PartialReceiveAccept.String():
+		return PartialReceiveAccept, nil
+
+	case PartialReceiveReject.String():
+		return PartialReceiveReject, nil
+
+	case PartialReceiveQuarantine.String():
+		return PartialReceiveQuarantine, nil
+
+	default:
+		return 0, fmt.Errorf("unknown partial receive policy: %v",
+			policy)
+	}
+}
+
+// ErrPartialReceive is the error of a dead letter proof that was quarantined
+// because it carries fewer asset units than requested by the address.
+var ErrPartialReceive = errors.New("received fewer asset units than " +
+	"requested by the address")
+
+// checkReceivedAmount records the number of asset units the given proof of an
+// inbound transfer carries on its address event and applies the configured
+// partial receive policy if that is less than requested by the address. False
+// is returned if the proof should not be imported.
+func (c *Custodian) checkReceivedAmount(event *address.Event,
+	p *proof.AnnotatedProof) bool {
+
+	file := proof.NewEmptyFile(proof.V0)
+	if err := file.Decode(bytes.NewReader(p.Blob)); err != nil {
+		// The import will fail for the same reason and move the proof
+		// to the dead letters.
+		log.Errorf("Unable to decode proof for %v: %v", event.Outpoint,
+			err)
+		return true
+	}
+	lastProof, err := file.LastProof()
+	if err != nil {
+		log.Errorf("Unable to fetch last proof for %v: %v",
+			event.Outpoint, err)
+		return true
+	}
+
+	receivedAmt := lastProof.Asset.Amount
+	if receivedAmt >= event.Addr.Amount {
+		return true
+	}
+
+	// Record the actual amount, so the shortfall shows up on the address
+	// event and the receiver can follow up with the sender.
+	ctxt, cancel := c.CtxBlocking()
+	err = c.cfg.AddrBook.SetEventAssetAmt(ctxt, event, receivedAmt)
+	cancel()
+	if err != nil {
+		log.Errorf("Unable to record received amount for %v: %v",
+			event.Outpoint, err)
+	}
+
+	partialErr := fmt.Errorf("%w: received %d of %d", ErrPartialReceive,
+		receivedAmt, event.Addr.Amount)
+
+	shortfall := event.Addr.Amount - receivedAmt
+	log.Warnf("Inbound asset transfer in %v received %d of %d requested "+
+		"asset units (shortfall %d), applying partial receive policy "+
+		"'%v'", event.Outpoint, receivedAmt, event.Addr.Amount,
+		shortfall, c.cfg.PartialReceivePolicy)
+
+	switch c.cfg.PartialReceivePolicy {
+	case PartialReceiveReject:
+		c.publishReceiveEvent(
+			event.Addr.Tap, event.Outpoint, ReceiveStatusFailed,
+			partialErr,
+		)
+
+		return false
+
+	case PartialReceiveQuarantine:
+		// The quarantine is persisted on the address event, so the
+		// proof isn't fetched again after a restart.
+		ctxt, cancel := c.CtxBlocking()
+		err := c.cfg.AddrBook.SetEventQuarantined(ctxt, event, true)
+		cancel()
+		if err != nil {
+			log.Errorf("Unable to quarantine event for %v: %v",
+				event.Outpoint, err)
+		}
+		event.Quarantined = true
+
+		c.addDeadLetter(&DeadLetterProof{
+			OutPoint: event.Outpoint,
+			Proof:    p,
+			Err:      partialErr,
+			Time:     time.Now(),
+		})
+
+		c.publishReceiveEvent(
+			event.Addr.Tap, event.Outpoint, ReceiveStatusFailed,
+			partialErr,
+		)
+
+		return false
+
+	default:
+		return true
+	}
+}
@@ -0,0 +1,225 @@
+package tapgarden
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/stretchr/testify/require"
+)
+
+// partialReceiveStore is an address event store that records the received
+// amounts and quarantines of address events.
+type partialReceiveStore struct {
+	address.Storage
+
+	assetAmts map[int64]uint64
+
+	quarantined map[int64]bool
+}
+
+func (s *partialReceiveStore) SetEventAssetAmt(_ context.Context,
+	event *address.Event, assetAmt uint64) error {
+
+	s.assetAmts[event.ID] = assetAmt
+	return nil
+}
+
+func (s *partialReceiveStore) SetEventQuarantined(_ context.Context,
+	event *address.Event, quarantined bool) error {
+
+	s.quarantined[event.ID] = quarantined
+	return nil
+}
+
+// mockDeadLetterStore is a dead letter store that keeps the dead letters in
+// memory.
+type mockDeadLetterStore struct {
+	deadLetters map[wire.OutPoint]*DeadLetterProof
+}
+
+func (s *mockDeadLetterStore) StoreDeadLetter(_ context.Context,
+	deadLetter *DeadLetterProof) error {
+
+	s.deadLetters[deadLetter.OutPoint] = deadLetter
+	return nil
+}
+
+func (s *mockDeadLetterStore) FetchDeadLetters(
+	context.Context) ([]*DeadLetterProof, error) {
+
+	deadLetters := make([]*DeadLetterProof, 0, len(s.deadLetters))
+	for _, deadLetter := range s.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+func (s *mockDeadLetterStore) DeleteDeadLetter(_ context.Context,
+	op wire.OutPoint) error {
+
+	delete(s.deadLetters, op)
+	return nil
+}
+
+// TestCheckReceivedAmount tests that the partial receive policy is applied to
+// inbound transfers that carry fewer asset units than requested by the
+// address, and that the received amount is recorded on the address event.
+func TestCheckReceivedAmount(t *testing.T) {
+	t.Parallel()
+
+	// makeProof creates a proof file of an asset with the given amount.
+	makeProof := func(amount uint64) *proof.AnnotatedProof {
+		newAsset := asset.RandAsset(t, asset.Normal)
+		newAsset.Amount = amount
+
+		file, err := proof.NewFile(proof.V0, proof.Proof{
+			AnchorTx: wire.MsgTx{
+				Version: 2,
+				TxIn:    []*wire.TxIn{{}},
+			},
+			Asset: *newAsset,
+			InclusionProof: proof.TaprootProof{
+				InternalKey: test.RandPubKey(t),
+			},
+		})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, file.Encode(&buf))
+
+		return &proof.AnnotatedProof{
+			Blob: buf.Bytes(),
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		policy      PartialReceivePolicy
+		receivedAmt uint64
+		imported    bool
+		deadLetter  bool
+	}{{
+		name:        "full amount",
+		policy:      PartialReceiveReject,
+		receivedAmt: 10,
+		imported:    true,
+	}, {
+		name:        "accept",
+		policy:      PartialReceiveAccept,
+		receivedAmt: 7,
+		imported:    true,
+	}, {
+		name:        "reject",
+		policy:      PartialReceiveReject,
+		receivedAmt: 7,
+	}, {
+		name:        "quarantine",
+		policy:      PartialReceiveQuarantine,
+		receivedAmt: 7,
+		deadLetter:  true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &partialReceiveStore{
+				assetAmts:   make(map[int64]uint64),
+				quarantined: make(map[int64]bool),
+			}
+			deadLetterStore := &mockDeadLetterStore{
+				deadLetters: make(
+					map[wire.OutPoint]*DeadLetterProof,
+				),
+			}
+			c := NewCustodian(&CustodianConfig{
+				AddrBook: address.NewBook(address.BookConfig{
+					Store:        store,
+					StoreTimeout: time.Second,
+				}),
+				DeadLetterStore:      deadLetterStore,
+				PartialReceivePolicy: tc.policy,
+			})
+
+			events := fn.NewEventReceiver[fn.Event](
+				fn.DefaultQueueSize,
+			)
+			require.NoError(t, c.RegisterSubscriber(
+				events, false, false,
+			))
+
+			event := &address.Event{
+				ID: 1,
+				Addr: &address.AddrWithKeyInfo{
+					Tap: &address.Tap{
+						Amount: 10,
+					},
+				},
+				Outpoint: test.RandOp(t),
+			}
+			imported := c.checkReceivedAmount(
+				event, makeProof(tc.receivedAmt),
+			)
+			require.Equal(t, tc.imported, imported)
+
+			// The received amount is only recorded if it falls
+			// short of the requested amount.
+			if tc.receivedAmt < event.Addr.Amount {
+				require.Equal(
+					t, tc.receivedAmt, store.assetAmts[1],
+				)
+			} else {
+				require.Empty(t, store.assetAmts)
+			}
+
+			// A quarantined transfer is persisted on the address
+			// event and in the dead letters.
+			require.Equal(t, tc.deadLetter, event.Quarantined)
+			require.Equal(t, tc.deadLetter, store.quarantined[1])
+			require.Len(t, c.DeadLetters(), len(
+				deadLetterStore.deadLetters,
+			))
+			if tc.deadLetter {
+				deadLetters := deadLetterStore.deadLetters
+				deadLetter := deadLetters[event.Outpoint]
+				require.NotNil(t, deadLetter)
+				require.ErrorIs(
+					t, deadLetter.Err, ErrPartialReceive,
+				)
+			} else {
+				require.Empty(t, deadLetterStore.deadLetters)
+			}
+
+			// A transfer that isn't imported is reported as
+			// failed.
+			if tc.imported {
+				require.Empty(
+					t, events.NewItemCreated.ChanOut(),
+				)
+				return
+			}
+
+			e, err := fn.RecvOrTimeout(
+				events.NewItemCreated.ChanOut(), time.Second,
+			)
+			require.NoError(t, err)
+			receiveEvent, ok := (*e).(*AssetReceiveEvent)
+			require.True(t, ok)
+			require.Equal(
+				t, ReceiveStatusFailed, receiveEvent.Status,
+			)
+			require.ErrorIs(t, receiveEvent.Err, ErrPartialReceive)
+		})
+	}
+}
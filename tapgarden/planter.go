@@ -3,6 +3,7 @@ package tapgarden
 import (
 	"context"
 	"fmt"
+	"math/bits"
 	"sync"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/lightninglabs/taproot-assets/tapscript"
 	"github.com/lightninglabs/taproot-assets/universe"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/xeipuuv/gojsonschema"
 	"golang.org/x/exp/maps"
 )
 
@@ -64,6 +66,11 @@ type GardenKit struct {
 	// UniversePushBatchSize is the number of minted items to push to the
 	// local universe in a single batch.
 	UniversePushBatchSize int
+
+	// IssuanceAuthorizer is used to authorize reissuances into asset
+	// groups whose issuance authority was rotated. If it is nil, no
+	// issuance authority is added to reissuance proofs.
+	IssuanceAuthorizer IssuanceAuthorizer
 }
 
 // PlanterConfig is the main config for the ChainPlanter.
@@ -77,6 +84,11 @@ type PlanterConfig struct {
 	// ProofUpdates is the storage backend for updated proofs.
 	ProofUpdates proof.Archiver
 
+	// MetaJSONSchema is an optional JSON schema the meta data of seedlings
+	// with the JSON meta type must adhere to. If nil, JSON meta data only
+	// needs to be valid JSON.
+	MetaJSONSchema *gojsonschema.Schema
+
 	// ErrChan is the main error channel the planter will report back
 	// critical errors to the main server.
 	ErrChan chan<- error
@@ -93,6 +105,16 @@ type CancelResp struct {
 	err        error
 }
 
+// CancelledBatch describes a minting batch that was cancelled.
+type CancelledBatch struct {
+	// BatchKey is the internal key of the cancelled batch.
+	BatchKey *btcec.PublicKey
+
+	// NumSeedlings is the number of asset seedlings that were discarded
+	// with the batch.
+	NumSeedlings int
+}
+
 type stateRequest interface {
 	Resolve(any)
 	Error(error)
@@ -171,6 +193,7 @@ const (
 	reqTypeListBatches
 	reqTypeFinalizeBatch
 	reqTypeCancelBatch
+	reqTypeScheduleFinalize
 )
 
 // ChainPlanter is responsible for accepting new incoming requests to create
@@ -201,6 +224,10 @@ type ChainPlanter struct {
 	// any relevant resources.
 	completionSignals chan BatchKey
 
+	// batchEvents notifies subscribers with the key of each batch that was
+	// finalized, which means that the assets of the batch were created.
+	batchEvents *fn.EventDistributor[BatchKey]
+
 	// stateReqs is the channel that any outside requests for the state of
 	// the planter will come across.
 	stateReqs chan stateRequest
@@ -216,6 +243,7 @@ func NewChainPlanter(cfg PlanterConfig) *ChainPlanter {
 		cfg:               cfg,
 		caretakers:        make(map[BatchKey]*BatchCaretaker),
 		completionSignals: make(chan BatchKey),
+		batchEvents:       fn.NewEventDistributor[BatchKey](),
 		seedlingReqs:      make(chan *Seedling),
 		stateReqs:         make(chan stateRequest),
 		ContextGuard: &fn.ContextGuard{
@@ -286,9 +314,6 @@ func (c *ChainPlanter) Start() error {
 				continue
 			}
 
-			log.Infof("Launching ChainCaretaker(%x)",
-				batch.BatchKey.PubKey.SerializeCompressed())
-
 			// For batches before the actual assets have been
 			// committed, we'll need to populate this field
 			// manually.
@@ -296,6 +321,24 @@ func (c *ChainPlanter) Start() error {
 				batch.AssetMetas = make(AssetMetas)
 			}
 
+			// A pending batch that is scheduled to be finalized
+			// later remains the pending batch until its schedule
+			// is due.
+			batchKey := batch.BatchKey.PubKey.SerializeCompressed()
+			schedule := batch.FinalizeSchedule
+			if batchState == BatchStatePending &&
+				c.pendingBatch == nil && schedule != nil &&
+				!schedule.isDue(batch, time.Now()) {
+
+				log.Infof("Resuming scheduled pending batch "+
+					"%x", batchKey)
+
+				c.pendingBatch = batch
+				continue
+			}
+
+			log.Infof("Launching ChainCaretaker(%x)", batchKey)
+
 			caretaker := c.newCaretakerForBatch(batch)
 			if err := caretaker.Start(); err != nil {
 				startErr = err
@@ -413,18 +456,20 @@ func (c *ChainPlanter) canCancelBatch() (*btcec.PublicKey, error) {
 	return nil, fmt.Errorf("multiple caretakers not supported")
 }
 
-// cancelMintingBatch attempts to cancel a target minting batch. This can fail
-// if the batch is managed by a caretaker and has already been broadcast.
+// cancelMintingBatch attempts to cancel a target minting batch and returns the
+// number of assets that were discarded with it. This can fail if the batch is
+// managed by a caretaker and has already been broadcast.
 func (c *ChainPlanter) cancelMintingBatch(ctx context.Context,
-	batchKey *btcec.PublicKey) error {
+	batchKey *btcec.PublicKey) (int, error) {
 
 	// The target batch may have already been assigned a caretaker. If so,
 	// we need to signal to the caretaker to cancel the batch.
 	batchKeySerialized := asset.ToSerialized(batchKey)
 	caretaker, ok := c.caretakers[batchKeySerialized]
 	if ok {
+		numAssets := caretaker.cfg.Batch.numAssets()
 		log.Infof("Cancelling MintingBatch(key=%x, num_assets=%v)",
-			batchKeySerialized, len(caretaker.cfg.Batch.Seedlings))
+			batchKeySerialized, numAssets)
 
 		caretaker.cfg.CancelReqChan <- struct{}{}
 
@@ -441,26 +486,28 @@ func (c *ChainPlanter) cancelMintingBatch(ctx context.Context,
 				delete(c.caretakers, batchKeySerialized)
 			}
 
-			return cancelResp.err
+			return numAssets, cancelResp.err
 
 		case <-c.Quit:
-			return nil
+			return 0, nil
 		}
 	}
 
+	numAssets := c.pendingBatch.numAssets()
 	log.Infof("Cancelling MintingBatch(key=%x, num_assets=%v)",
-		batchKeySerialized, len(c.pendingBatch.Seedlings))
+		batchKeySerialized, numAssets)
 
 	// If the target batch was not assigned a caretaker, we only need to
-	// update the batch state on disk to cancel it.
+	// update the batch state on disk to cancel it. The batch internal key
+	// and the keys derived for its seedlings are never used then.
 	err := c.cfg.Log.UpdateBatchState(
 		ctx, batchKey, BatchStateSeedlingCancelled,
 	)
 	if err != nil {
-		return fmt.Errorf("unable to cancel minting batch: %w", err)
+		return 0, fmt.Errorf("unable to cancel minting batch: %w", err)
 	}
 
-	return nil
+	return numAssets, nil
 }
 
 // gardener is responsible for collecting new potential taproot asset
@@ -476,8 +523,20 @@ func (c *ChainPlanter) gardener() {
 
 	log.Infof("Gardener for ChainPlanter now active!")
 
+	// finalizeTimer fires once the finalization time of the pending batch
+	// was reached, if its finalization was scheduled for a specific time.
+	// The pending batch might have been scheduled before a restart.
+	var finalizeTimer <-chan time.Time
+	if c.pendingBatch != nil {
+		finalizeTimer = c.pendingBatch.FinalizeSchedule.timer()
+	}
+
 	for {
 		select {
+		case <-finalizeTimer:
+			finalizeTimer = nil
+			c.finalizeIfScheduled()
+
 		case <-c.cfg.BatchTicker.Ticks():
 			// There is no pending batch, so we can just abort.
 			if c.pendingBatch == nil {
@@ -528,6 +587,10 @@ func (c *ChainPlanter) gardener() {
 				NewState:     MintingStateSeed,
 			}
 
+			// The new seedling might be the one the batch was
+			// scheduled to be finalized at.
+			c.finalizeIfScheduled()
+
 		// A caretaker has finished processing their batch to full
 		// Taproot Asset maturity. We'll clean up our local state, and
 		// signal that it can exit.
@@ -549,7 +612,7 @@ func (c *ChainPlanter) gardener() {
 
 			delete(c.caretakers, batchKey)
 
-			// TODO(roasbeef): send completion signal?
+			c.batchEvents.NotifySubscribers(batchKey)
 
 		// A new request just came along to query our internal state.
 		case req := <-c.stateReqs:
@@ -580,6 +643,26 @@ func (c *ChainPlanter) gardener() {
 
 				req.Resolve(batches)
 
+			case reqTypeScheduleFinalize:
+				schedule, err := typedParam[FinalizeSchedule](
+					req,
+				)
+				if err != nil {
+					req.Error(fmt.Errorf("bad schedule: %w",
+						err))
+					break
+				}
+
+				err = c.scheduleFinalize(schedule)
+				if err != nil {
+					req.Error(err)
+					break
+				}
+
+				finalizeTimer = schedule.timer()
+
+				req.Resolve(c.pendingBatch)
+
 			case reqTypeFinalizeBatch:
 				if c.pendingBatch == nil {
 					req.Error(fmt.Errorf("no pending batch"))
@@ -627,13 +710,18 @@ func (c *ChainPlanter) gardener() {
 				// Attempt to cancel the current batch, and then
 				// clear the pending batch in the planter.
 				ctx, cancel := c.WithCtxQuit()
-				err = c.cancelMintingBatch(ctx, batchKey)
+				numSeedlings, err := c.cancelMintingBatch(
+					ctx, batchKey,
+				)
 				cancel()
 				c.pendingBatch = nil
 
 				// Always return the key of the batch we tried
 				// to cancel.
-				req.Return(batchKey, err)
+				req.Return(&CancelledBatch{
+					BatchKey:     batchKey,
+					NumSeedlings: numSeedlings,
+				}, err)
 			}
 
 		case <-c.Quit:
@@ -642,6 +730,67 @@ func (c *ChainPlanter) gardener() {
 	}
 }
 
+// scheduleFinalize schedules the pending batch to be finalized automatically
+// according to the given schedule. A schedule that is already due is rejected,
+// in that case the batch should be finalized directly.
+func (c *ChainPlanter) scheduleFinalize(schedule *FinalizeSchedule) error {
+	if c.pendingBatch == nil {
+		return fmt.Errorf("no pending batch")
+	}
+
+	if schedule.FinalizeTime.IsZero() && schedule.MinSeedlings <= 0 {
+		return fmt.Errorf("finalize schedule must specify a time " +
+			"or a minimum number of seedlings")
+	}
+
+	if schedule.isDue(c.pendingBatch, time.Now()) {
+		return fmt.Errorf("finalize schedule is already due, " +
+			"finalize the batch directly instead")
+	}
+
+	batchKey := c.pendingBatch.BatchKey.PubKey
+	log.Infof("Scheduling finalization of batch %x (time=%v, "+
+		"min_seedlings=%v)", batchKey.SerializeCompressed(),
+		schedule.FinalizeTime, schedule.MinSeedlings)
+
+	// The schedule is persisted with the batch, so it is resumed after a
+	// restart.
+	ctx, cancel := c.WithCtxQuit()
+	defer cancel()
+	err := c.cfg.Log.UpdateBatchFinalizeSchedule(ctx, batchKey, schedule)
+	if err != nil {
+		return fmt.Errorf("unable to store finalize schedule: %w", err)
+	}
+
+	c.pendingBatch.FinalizeSchedule = schedule
+
+	return nil
+}
+
+// finalizeIfScheduled finalizes the pending batch if its finalization was
+// scheduled and the schedule is due.
+func (c *ChainPlanter) finalizeIfScheduled() {
+	batch := c.pendingBatch
+	if batch == nil || batch.FinalizeSchedule == nil ||
+		!batch.FinalizeSchedule.isDue(batch, time.Now()) {
+
+		return
+	}
+
+	log.Infof("Finalizing scheduled batch %x",
+		batch.BatchKey.PubKey.SerializeCompressed())
+
+	if _, err := c.finalizeBatch(); err != nil {
+		c.cfg.ErrChan <- fmt.Errorf("unable to freeze minting batch: "+
+			"%w", err)
+		return
+	}
+
+	// Now that we have a caretaker launched for this batch, we'll set the
+	// pending batch to nil.
+	c.pendingBatch = nil
+}
+
 // finalizeBatch creates a new caretaker for the batch and starts it.
 func (c *ChainPlanter) finalizeBatch() (*BatchCaretaker, error) {
 	// Prep the new care taker that'll be launched assuming the call below
@@ -717,9 +866,27 @@ func (c *ChainPlanter) FinalizeBatch() (*MintingBatch, error) {
 	return <-req.resp, <-req.err
 }
 
-// CancelBatch sends a signal to the planter to cancel the current batch.
-func (c *ChainPlanter) CancelBatch() (*btcec.PublicKey, error) {
-	req := newStateReq[*btcec.PublicKey](reqTypeCancelBatch)
+// ScheduleFinalize sends a signal to the planter to finalize the current batch
+// automatically once the given schedule is due. The pending batch is returned.
+func (c *ChainPlanter) ScheduleFinalize(
+	schedule FinalizeSchedule) (*MintingBatch, error) {
+
+	req := newStateParamReq[*MintingBatch](
+		reqTypeScheduleFinalize, schedule,
+	)
+
+	if !fn.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
+		return nil, fmt.Errorf("chain planter shutting down")
+	}
+
+	return <-req.resp, <-req.err
+}
+
+// CancelBatch sends a signal to the planter to cancel the current batch. The
+// key of the batch that was attempted to be cancelled is also returned if the
+// cancellation failed.
+func (c *ChainPlanter) CancelBatch() (*CancelledBatch, error) {
+	req := newStateReq[*CancelledBatch](reqTypeCancelBatch)
 
 	if !fn.SendOrQuit[stateRequest](c.stateReqs, req, c.Quit) {
 		return nil, fmt.Errorf("chain planter shutting down")
@@ -728,6 +895,94 @@ func (c *ChainPlanter) CancelBatch() (*btcec.PublicKey, error) {
 	return <-req.resp, <-req.err
 }
 
+// validateSupplyCap checks that issuing the given seedling into an existing
+// asset group doesn't exceed the supply cap declared by the group anchor, if
+// the anchor declared one. All batches that weren't cancelled count towards
+// the issued supply.
+func (c *ChainPlanter) validateSupplyCap(ctx context.Context,
+	req *Seedling) error {
+
+	groupKey := &req.GroupInfo.GroupPubKey
+	anchorID := req.GroupInfo.Genesis.ID()
+
+	batches, err := c.cfg.Log.FetchAllBatches(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to fetch batches: %w", err)
+	}
+
+	var (
+		supplyCap uint64
+		issued    uint64
+		overflow  bool
+	)
+
+	// addIssued adds the given amount to the issued supply, remembering
+	// whether the sum no longer fits into an uint64.
+	addIssued := func(amt uint64) {
+		var carry uint64
+		issued, carry = bits.Add64(issued, amt, 0)
+		overflow = overflow || carry != 0
+	}
+
+	for _, batch := range batches {
+		switch batch.State() {
+		case BatchStateSeedlingCancelled, BatchStateSproutCancelled:
+			continue
+
+		// Batches that haven't been sprouted yet only hold seedlings,
+		// so we count the seedlings that join the group.
+		case BatchStatePending, BatchStateFrozen:
+			for _, seedling := range batch.Seedlings {
+				if !seedling.HasGroupKey() {
+					continue
+				}
+
+				seedlingKey := &seedling.GroupInfo.GroupPubKey
+				if seedlingKey.IsEqual(groupKey) {
+					addIssued(seedling.Amount)
+				}
+			}
+
+			continue
+		}
+
+		if batch.RootAssetCommitment == nil {
+			continue
+		}
+
+		for _, a := range batch.RootAssetCommitment.CommittedAssets() {
+			if a.GroupKey == nil ||
+				!a.GroupKey.GroupPubKey.IsEqual(groupKey) {
+
+				continue
+			}
+
+			addIssued(a.Amount)
+
+			if a.ID() != anchorID {
+				continue
+			}
+
+			scriptKey := asset.ToSerialized(a.ScriptKey.PubKey)
+			meta, ok := batch.AssetMetas[scriptKey]
+			if ok && meta != nil {
+				supplyCap = meta.SupplyCap
+			}
+		}
+	}
+
+	// A total that doesn't fit into an uint64 exceeds any cap.
+	total, carry := bits.Add64(issued, req.Amount, 0)
+	if supplyCap != 0 && (overflow || carry != 0 || total > supplyCap) {
+		return fmt.Errorf("%w: group %x has issued %d, cap %d, "+
+			"requested %d", proof.ErrSupplyCapExceeded,
+			groupKey.SerializeCompressed(), issued, supplyCap,
+			req.Amount)
+	}
+
+	return nil
+}
+
 // prepAssetSeedling performs some basic validation for the Seedling, then
 // either adds it to an existing pending batch or creates a new batch for it. A
 // bool indicating if a new batch should immediately be created is returned.
@@ -739,25 +994,52 @@ func (c *ChainPlanter) prepAssetSeedling(ctx context.Context,
 		return err
 	}
 
+	// JSON meta data must also adhere to the configured schema, if any.
+	if c.cfg.MetaJSONSchema != nil {
+		err := req.Meta.ValidateJSONSchema(c.cfg.MetaJSONSchema)
+		if err != nil {
+			return err
+		}
+	}
+
 	// If emission is enabled and a group key is specified, we need to
 	// make sure the asset types match and that we can sign with that key.
 	if req.HasGroupKey() {
-		groupInfo, err := c.cfg.Log.FetchGroupByGroupKey(
+		groupKeyBytes := req.GroupInfo.GroupPubKey.SerializeCompressed()
+		authority, err := c.cfg.Log.FetchGroupAuthority(
 			ctx, &req.GroupInfo.GroupPubKey,
 		)
 		if err != nil {
-			groupKeyBytes := req.GroupInfo.GroupPubKey.
-				SerializeCompressed()
 			return fmt.Errorf("group key %x not found: %w",
 				groupKeyBytes, err,
 			)
 		}
+		groupInfo := authority.Group
 
 		if err := req.validateGroupKey(*groupInfo); err != nil {
 			return err
 		}
 
+		// The group witness of the new asset is created with the raw
+		// group key, so the wallet must actually control that key. If
+		// the issuance authority of the group was rotated, the current
+		// authority key additionally needs to authorize the issuance.
+		if !c.cfg.KeyRing.IsLocalKey(ctx, groupInfo.RawKey) {
+			return fmt.Errorf("can't sign with group key %x, raw "+
+				"key not controlled by wallet", groupKeyBytes)
+		}
+		if len(authority.Rotations) > 0 &&
+			!c.cfg.KeyRing.IsLocalKey(ctx, authority.AuthorityKey) {
+
+			return fmt.Errorf("group %x: %w", groupKeyBytes,
+				ErrGroupAuthorityNotLocal)
+		}
+
 		req.GroupInfo = groupInfo
+
+		if err := c.validateSupplyCap(ctx, req); err != nil {
+			return err
+		}
 	}
 
 	// If a group anchor is specified, we need to ensure that the anchor
@@ -951,6 +1233,28 @@ func (c *ChainPlanter) CancelSeedling() error {
 	return nil
 }
 
+// RegisterSubscriber adds a new subscriber that is notified with the key of
+// each batch that is finalized from now on.
+//
+// NOTE: This is part of the Planter interface.
+func (c *ChainPlanter) RegisterSubscriber(
+	receiver *fn.EventReceiver[BatchKey]) error {
+
+	c.batchEvents.RegisterSubscriber(receiver)
+
+	return nil
+}
+
+// RemoveSubscriber removes the given subscriber and also stops it from
+// processing events.
+//
+// NOTE: This is part of the Planter interface.
+func (c *ChainPlanter) RemoveSubscriber(
+	subscriber *fn.EventReceiver[BatchKey]) error {
+
+	return c.batchEvents.RemoveSubscriber(subscriber)
+}
+
 // A compile-time assertion to make sure that ChainPlanter implements the
 // tapgarden.Planter interface.
 var _ Planter = (*ChainPlanter)(nil)
@@ -305,18 +305,20 @@ func (t *mintingTestHarness) assertNewBatchFrozen(
 func (t *mintingTestHarness) cancelMintingBatch(noBatch bool) *btcec.PublicKey {
 	t.Helper()
 
-	batchKey, err := t.planter.CancelBatch()
+	cancelled, err := t.planter.CancelBatch()
 	if noBatch {
 		require.ErrorContains(t, err, "no pending batch")
-		require.Nil(t, batchKey)
+		require.Nil(t, cancelled)
 		return nil
 	}
 
-	if batchKey == nil {
+	if cancelled == nil {
 		require.NotNil(t, err)
 		return nil
 	}
 
+	batchKey := cancelled.BatchKey
+
 	if err != nil {
 		require.ErrorContains(t, err, "batch not cancellable")
 		require.NotNil(t, batchKey)
@@ -697,6 +699,12 @@ func testBasicAssetCreation(t *mintingTestHarness) {
 	}
 	sendConfNtfn := t.assertConfReqSent(tx, block)
 
+	// Subscribers should be notified once the batch is finalized.
+	batchEvents := fn.NewEventReceiver[tapgarden.BatchKey](
+		fn.DefaultQueueSize,
+	)
+	require.NoError(t, t.planter.RegisterSubscriber(batchEvents))
+
 	// We'll now send the confirmation notification which should result in
 	// the batch being finalized, and the caretaker being cleaned up.
 	sendConfNtfn()
@@ -707,6 +715,13 @@ func testBasicAssetCreation(t *mintingTestHarness) {
 
 	// At this point there should be no active caretakers.
 	t.assertNumCaretakersActive(0)
+
+	batchKey, err := fn.RecvOrTimeout(
+		batchEvents.NewItemCreated.ChanOut(), defaultTimeout,
+	)
+	require.NoError(t, err)
+	require.Equal(t, asset.ToSerialized(t.batchKey.PubKey), *batchKey)
+	require.NoError(t, t.planter.RemoveSubscriber(batchEvents))
 }
 
 func testMintingTicker(t *mintingTestHarness) {
@@ -823,8 +838,12 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 	t.assertSeedlingsExist(seedlings, nil)
 
 	// If we cancel the current batch, the pending batch should be cleared,
-	// but the seedlings should still exist on disk.
-	firstBatchKey := t.cancelMintingBatch(false)
+	// but the seedlings should still exist on disk. All seedlings of the
+	// batch should be reported as discarded.
+	cancelled, err := t.planter.CancelBatch()
+	require.NoError(t, err)
+	require.Equal(t, numSeedlings, cancelled.NumSeedlings)
+	firstBatchKey := cancelled.BatchKey
 	t.assertNoPendingBatch()
 	t.assertBatchState(firstBatchKey, tapgarden.BatchStateSeedlingCancelled)
 	t.assertSeedlingsExist(seedlings, firstBatchKey)
@@ -960,6 +979,270 @@ func testMintingCancelFinalize(t *mintingTestHarness) {
 	t.assertNumCaretakersActive(0)
 }
 
+// assertSeedlingRejected queues the seedling and asserts that the planter
+// rejects it with the target error.
+func (t *mintingTestHarness) assertSeedlingRejected(
+	seedling *tapgarden.Seedling, targetErr error) {
+
+	t.Helper()
+
+	updates, err := t.planter.QueueNewSeedling(seedling)
+	require.NoError(t, err)
+
+	update, err := fn.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorIs(t, update.Error, targetErr)
+}
+
+// testMintingSupplyCap tests that the planter enforces the supply cap declared
+// by a group anchor for the seedlings grouped with it.
+func testMintingSupplyCap(t *mintingTestHarness) {
+	t.refreshChainPlanter()
+
+	// A seedling can only declare a supply cap if it anchors a new group.
+	t.assertSeedlingRejected(&tapgarden.Seedling{
+		AssetType: asset.Normal,
+		AssetName: "no-emission",
+		Amount:    10,
+		Meta: &proof.MetaReveal{
+			SupplyCap: 100,
+		},
+	}, tapgarden.ErrSupplyCapNotGroupAnchor)
+
+	// The anchor itself can't issue more than the cap.
+	t.assertSeedlingRejected(&tapgarden.Seedling{
+		AssetType:      asset.Normal,
+		AssetName:      "too-large",
+		Amount:         101,
+		EnableEmission: true,
+		Meta: &proof.MetaReveal{
+			SupplyCap: 100,
+		},
+	}, proof.ErrSupplyCapExceeded)
+
+	anchor := &tapgarden.Seedling{
+		AssetType:      asset.Normal,
+		AssetName:      "capped",
+		Amount:         60,
+		EnableEmission: true,
+		Meta: &proof.MetaReveal{
+			Data:      []byte("regulated"),
+			SupplyCap: 100,
+		},
+	}
+	grouped := &tapgarden.Seedling{
+		AssetType:   asset.Normal,
+		AssetName:   "capped-tranche",
+		Amount:      40,
+		GroupAnchor: &anchor.AssetName,
+	}
+	t.queueSeedlingsInBatch(anchor, grouped)
+
+	// With the anchor and the tranche filling up the cap, any further
+	// seedling for the group must be rejected.
+	t.assertSeedlingRejected(&tapgarden.Seedling{
+		AssetType:   asset.Normal,
+		AssetName:   "capped-overflow",
+		Amount:      1,
+		GroupAnchor: &anchor.AssetName,
+	}, proof.ErrSupplyCapExceeded)
+
+	// A seedling joining a capped group can't declare a cap of its own.
+	t.assertSeedlingRejected(&tapgarden.Seedling{
+		AssetType:   asset.Normal,
+		AssetName:   "capped-override",
+		Amount:      1,
+		GroupAnchor: &anchor.AssetName,
+		Meta: &proof.MetaReveal{
+			SupplyCap: 1000,
+		},
+	}, tapgarden.ErrSupplyCapNotGroupAnchor)
+
+	t.assertPendingBatchExists(2)
+}
+
+// groupAuthorityStore is a minting store that reports a fixed issuance
+// authority for any asset group.
+type groupAuthorityStore struct {
+	tapgarden.MintingStore
+
+	authority *tapgarden.GroupAuthority
+}
+
+func (s *groupAuthorityStore) FetchGroupAuthority(context.Context,
+	*btcec.PublicKey) (*tapgarden.GroupAuthority, error) {
+
+	return s.authority, nil
+}
+
+// localKeyRing is a key ring that only reports the given keys as controlled
+// by the wallet.
+type localKeyRing struct {
+	*tapgarden.MockKeyRing
+
+	localKeys map[asset.SerializedKey]bool
+}
+
+func (k *localKeyRing) IsLocalKey(_ context.Context,
+	desc keychain.KeyDescriptor) bool {
+
+	return k.localKeys[asset.ToSerialized(desc.PubKey)]
+}
+
+// testMintingGroupKeyNotLocal tests that the planter rejects a reissuance into
+// a group if the wallet doesn't control the keys needed to sign for it.
+func testMintingGroupKeyNotLocal(t *mintingTestHarness) {
+	genesis := asset.RandGenesis(t, asset.Normal)
+	groupKey := asset.RandGroupKey(
+		t, genesis, asset.RandAsset(t, asset.Normal),
+	)
+	groupKey.RawKey.Family = asset.TaprootAssetsKeyFamily
+
+	store := &groupAuthorityStore{
+		MintingStore: t.store,
+		authority: &tapgarden.GroupAuthority{
+			Group: &asset.AssetGroup{
+				Genesis:  &genesis,
+				GroupKey: groupKey,
+			},
+			AuthorityKey: groupKey.RawKey,
+		},
+	}
+	keyRing := &localKeyRing{
+		MockKeyRing: t.keyRing,
+		localKeys:   make(map[asset.SerializedKey]bool),
+	}
+
+	t.planter = tapgarden.NewChainPlanter(tapgarden.PlanterConfig{
+		GardenKit: tapgarden.GardenKit{
+			Wallet:       t.wallet,
+			ChainBridge:  t.chain,
+			Log:          store,
+			KeyRing:      keyRing,
+			GenSigner:    t.genSigner,
+			GenTxBuilder: t.genTxBuilder,
+			TxValidator:  t.txValidator,
+			ProofFiles:   t.proofFiles,
+			ProofWatcher: t.proofWatcher,
+		},
+		BatchTicker:  t.ticker,
+		ProofUpdates: t.proofFiles,
+		ErrChan:      t.errChan,
+	})
+	require.NoError(t, t.planter.Start())
+	defer func() {
+		require.NoError(t, t.planter.Stop())
+	}()
+
+	reissuance := &tapgarden.Seedling{
+		AssetType: asset.Normal,
+		AssetName: "reissuance",
+		Amount:    10,
+		GroupInfo: &asset.AssetGroup{
+			GroupKey: groupKey,
+		},
+	}
+
+	// The key locator of the group's raw key claims it's a local key, but
+	// the wallet doesn't actually control it.
+	updates, err := t.planter.QueueNewSeedling(reissuance)
+	require.NoError(t, err)
+
+	update, err := fn.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.ErrorContains(
+		t, update.Error, "raw key not controlled by wallet",
+	)
+
+	// If the issuance authority of the group was rotated, the wallet must
+	// also control the current authority key, even if it controls the raw
+	// group key.
+	rawKey := asset.ToSerialized(groupKey.RawKey.PubKey)
+	keyRing.localKeys[rawKey] = true
+	store.authority.Rotations = []*proof.GroupKeyRotation{{}}
+	store.authority.AuthorityKey = keychain.KeyDescriptor{
+		PubKey: test.RandPubKey(t),
+	}
+	t.assertSeedlingRejected(
+		reissuance, tapgarden.ErrGroupAuthorityNotLocal,
+	)
+	t.assertNoPendingBatch()
+}
+
+// testMintingScheduledFinalize tests that a pending batch with a finalization
+// schedule is finalized automatically once the schedule is due.
+func testMintingScheduledFinalize(t *mintingTestHarness) {
+	// First, create a new chain planter instance using the supplied test
+	// harness.
+	t.refreshChainPlanter()
+
+	// Without a pending batch, there is nothing to schedule.
+	_, err := t.planter.ScheduleFinalize(tapgarden.FinalizeSchedule{
+		MinSeedlings: 1,
+	})
+	require.ErrorContains(t, err, "no pending batch")
+
+	// We'll now queue two seedlings and schedule the batch to be finalized
+	// once a third one was added.
+	const numSeedlings = 3
+	seedlings := t.newRandSeedlings(numSeedlings)
+	t.queueSeedlingsInBatch(seedlings[:numSeedlings-1]...)
+	t.assertPendingBatchExists(numSeedlings - 1)
+
+	// A schedule that is already due must be rejected, as should an empty
+	// one.
+	_, err = t.planter.ScheduleFinalize(tapgarden.FinalizeSchedule{
+		MinSeedlings: numSeedlings - 1,
+	})
+	require.ErrorContains(t, err, "already due")
+	_, err = t.planter.ScheduleFinalize(tapgarden.FinalizeSchedule{})
+	require.Error(t, err)
+
+	schedule := tapgarden.FinalizeSchedule{
+		MinSeedlings: numSeedlings,
+	}
+	batch, err := t.planter.ScheduleFinalize(schedule)
+	require.NoError(t, err)
+	require.Equal(t, &schedule, batch.FinalizeSchedule)
+
+	// The schedule should be reported when listing the pending batch.
+	batches, err := t.planter.ListBatches(batch.BatchKey.PubKey)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Equal(t, &schedule, batches[0].FinalizeSchedule)
+
+	// The schedule is persisted with the batch, so after a restart the
+	// batch remains pending instead of being finalized right away.
+	t.refreshChainPlanter()
+	t.assertNumCaretakersActive(0)
+
+	batch, err = t.planter.PendingBatch()
+	require.NoError(t, err)
+	require.NotNil(t, batch)
+	require.Len(t, batch.Seedlings, numSeedlings-1)
+	require.Equal(t, &schedule, batch.FinalizeSchedule)
+
+	// Adding the last seedling should trigger the finalization of the
+	// batch, without the ticker being involved.
+	updates, err := t.planter.QueueNewSeedling(seedlings[numSeedlings-1])
+	require.NoError(t, err)
+	update, err := fn.RecvOrTimeout(updates, defaultTimeout)
+	require.NoError(t, err)
+	require.NoError(t, update.Error)
+
+	_ = t.assertGenesisTxFunded()
+	t.assertNumCaretakersActive(1)
+	t.assertNoPendingBatch()
+
+	for i := 0; i < numSeedlings; i++ {
+		t.assertKeyDerived()
+
+		if seedlings[i].EnableEmission {
+			t.assertKeyDerived()
+		}
+	}
+}
+
 // mintingStoreTestCase is used to programmatically run a series of test cases
 // that are parametrized based on a fresh minting store.
 type mintingStoreTestCase struct {
@@ -985,6 +1268,21 @@ var testCases = []mintingStoreTestCase{
 		interval: minterInterval,
 		testFunc: testMintingCancelFinalize,
 	},
+	{
+		name:     "minting_with_supply_cap",
+		interval: defaultInterval,
+		testFunc: testMintingSupplyCap,
+	},
+	{
+		name:     "minting_with_scheduled_finalize",
+		interval: defaultInterval,
+		testFunc: testMintingScheduledFinalize,
+	},
+	{
+		name:     "minting_group_key_not_local",
+		interval: defaultInterval,
+		testFunc: testMintingGroupKeyNotLocal,
+	},
 }
 
 // TestBatchedAssetIssuance runs a test of tests to ensure that the set of
@@ -0,0 +1,171 @@
+package tapgarden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/proof"
+)
+
+var (
+	// errImportAborted is returned when the retry of a proof import is
+	// aborted because the custodian is shutting down.
+	errImportAborted = errors.New("proof import aborted")
+)
+
+// ProofImportRetryCfg is the config for the automatic retry of inbound proofs
+// that failed to be imported because of a transient issue.
+type ProofImportRetryCfg struct {
+	MaxAttempts uint32 `long:"maxattempts" description:"The maximum number of attempts to import an inbound proof that fails because of a transient issue, like a chain backend that is behind and can't verify the proof's block yet. A proof that still fails after the last attempt, or fails because it is invalid, is moved to the dead letters and needs to be imported manually. A value of 1 disables the retry."`
+
+	InitialBackoff time.Duration `long:"initialbackoff" description:"The time to wait before the first retry of a failed proof import. The time is doubled for every further retry."`
+
+	MaxBackoff time.Duration `long:"maxbackoff" description:"The maximum time to wait between two retries of a failed proof import."`
+}
+
+// Validate returns an error if the config can't be used to retry proof
+// imports.
+func (c *ProofImportRetryCfg) Validate() error {
+	switch {
+	case c.MaxAttempts == 0:
+		return fmt.Errorf("proof import max attempts must be positive")
+
+	case c.InitialBackoff <= 0:
+		return fmt.Errorf("proof import initial backoff must be " +
+			"positive")
+
+	case c.MaxBackoff < c.InitialBackoff:
+		return fmt.Errorf("proof import max backoff must not be " +
+			"smaller than the initial backoff")
+	}
+
+	return nil
+}
+
+// TransientImportError is an error of a proof import that might succeed if
+// the import is attempted again later.
+type TransientImportError struct {
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns a human readable description of the error.
+func (e *TransientImportError) Error() string {
+	return fmt.Sprintf("transient import error: %v", e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TransientImportError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransientImportErr returns true if the given proof import error might not
+// occur again if the import is retried later.
+func IsTransientImportErr(err error) bool {
+	var transientErr *TransientImportError
+	return errors.As(err, &transientErr) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// transientHeaderVerifier wraps the given header verifier to mark its errors
+// as transient if the block header might still be verified later. That is the
+// case if the chain backend hasn't caught up to the height of the block yet or
+// can't be reached. A block that can't be verified at a height the chain
+// backend already knows about isn't part of the chain, which doesn't change by
+// retrying.
+func transientHeaderVerifier(headerVerifier proof.HeaderVerifier,
+	currentHeight func() (uint32, error)) proof.HeaderVerifier {
+
+	return func(header wire.BlockHeader, height uint32) error {
+		err := headerVerifier(header, height)
+		if err == nil {
+			return nil
+		}
+
+		bestHeight, heightErr := currentHeight()
+		switch {
+		case heightErr != nil:
+			log.Debugf("Unable to query chain height: %v",
+				heightErr)
+
+			return &TransientImportError{Err: err}
+
+		// Old proofs don't carry the height of their block, so we
+		// can't tell whether the chain backend is just behind.
+		case height == 0 || height > bestHeight:
+			return &TransientImportError{Err: err}
+
+		default:
+			return err
+		}
+	}
+}
+
+// DeadLetterProof is an inbound proof that couldn't be imported, either
+// because it failed permanently or because it still failed after the maximum
+// number of attempts.
+type DeadLetterProof struct {
+	// OutPoint is the on-chain output of the inbound transfer the proof
+	// is for.
+	OutPoint wire.OutPoint
+
+	// Proof is the proof that couldn't be imported.
+	Proof *proof.AnnotatedProof
+
+	// Attempts is the number of times the import was attempted.
+	Attempts uint32
+
+	// Err is the error of the last import attempt.
+	Err error
+
+	// Time is the time the proof was given up on.
+	Time time.Time
+}
+
+// DeadLetterStore persists the inbound proofs that couldn't be imported, so
+// they survive a restart and can be listed until they're imported manually.
+type DeadLetterStore interface {
+	// StoreDeadLetter stores the given dead letter, replacing an existing
+	// one for the same outpoint.
+	StoreDeadLetter(ctx context.Context, deadLetter *DeadLetterProof) error
+
+	// FetchDeadLetters returns all stored dead letters.
+	FetchDeadLetters(ctx context.Context) ([]*DeadLetterProof, error)
+
+	// DeleteDeadLetter deletes the dead letter of the given outpoint, if
+	// there is one.
+	DeleteDeadLetter(ctx context.Context, op wire.OutPoint) error
+}
+
+// importWithRetry calls importProof until it succeeds, fails with an error
+// that isn't transient or the maximum number of attempts is reached. The wait
+// between two attempts starts at the initial backoff and doubles with every
+// attempt, up to the maximum backoff. The number of attempts and the error of
+// the last attempt are returned.
+func importWithRetry(quit <-chan struct{}, cfg *ProofImportRetryCfg,
+	importProof func() error) (uint32, error) {
+
+	backoff := cfg.InitialBackoff
+	for attempt := uint32(1); ; attempt++ {
+		err := importProof()
+		if err == nil || !IsTransientImportErr(err) ||
+			attempt >= cfg.MaxAttempts {
+
+			return attempt, err
+		}
+
+		log.Warnf("Proof import attempt %d of %d failed, retrying in "+
+			"%v: %v", attempt, cfg.MaxAttempts, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-quit:
+			return attempt, errImportAborted
+		}
+
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
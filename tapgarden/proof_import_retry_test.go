@@ -0,0 +1,188 @@
+package tapgarden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestIsTransientImportErr tests that proof import errors are classified
+// correctly.
+func TestIsTransientImportErr(t *testing.T) {
+	t.Parallel()
+
+	errBlockNotFound := errors.New("block not found")
+	errNoBackend := errors.New("chain backend unreachable")
+	failingVerifier := func(wire.BlockHeader, uint32) error {
+		return errBlockNotFound
+	}
+	chainHeight := func(height uint32, err error) func() (uint32, error) {
+		return func() (uint32, error) {
+			return height, err
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		blockHeight uint32
+		bestHeight  uint32
+		heightErr   error
+		transient   bool
+	}{{
+		name:        "chain backend behind",
+		blockHeight: 100,
+		bestHeight:  99,
+		transient:   true,
+	}, {
+		name:        "chain backend unreachable",
+		blockHeight: 100,
+		heightErr:   errNoBackend,
+		transient:   true,
+	}, {
+		name:       "unknown block height",
+		bestHeight: 99,
+		transient:  true,
+	}, {
+		name:        "block not in chain",
+		blockHeight: 100,
+		bestHeight:  100,
+	}, {
+		name:        "block not in chain below tip",
+		blockHeight: 100,
+		bestHeight:  150,
+	}}
+
+	for _, tc := range testCases {
+		currentHeight := chainHeight(tc.bestHeight, tc.heightErr)
+		headerVerifier := transientHeaderVerifier(
+			failingVerifier, currentHeight,
+		)
+
+		err := fmt.Errorf("unable to verify proof: %w",
+			headerVerifier(wire.BlockHeader{}, tc.blockHeight))
+		require.Equal(t, tc.transient, IsTransientImportErr(err),
+			tc.name)
+		require.ErrorIs(t, err, errBlockNotFound, tc.name)
+	}
+
+	err := fmt.Errorf("timeout: %w", context.DeadlineExceeded)
+	require.True(t, IsTransientImportErr(err))
+
+	require.False(t, IsTransientImportErr(errors.New("invalid witness")))
+
+	// A successful header verification stays successful.
+	headerVerifier := transientHeaderVerifier(
+		func(wire.BlockHeader, uint32) error {
+			return nil
+		}, chainHeight(0, errNoBackend),
+	)
+	require.NoError(t, headerVerifier(wire.BlockHeader{}, 100))
+}
+
+// TestImportWithRetry tests that only transient proof import errors are
+// retried, up to the maximum number of attempts.
+func TestImportWithRetry(t *testing.T) {
+	t.Parallel()
+
+	cfg := &ProofImportRetryCfg{
+		MaxAttempts:    4,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+	transientErr := &TransientImportError{Err: errors.New("behind")}
+	permanentErr := errors.New("invalid proof")
+
+	testCases := []struct {
+		name     string
+		errs     []error
+		attempts uint32
+		err      error
+	}{{
+		name:     "success",
+		attempts: 1,
+	}, {
+		name:     "success after transient errors",
+		errs:     []error{transientErr, transientErr},
+		attempts: 3,
+	}, {
+		name:     "permanent error",
+		errs:     []error{transientErr, permanentErr},
+		attempts: 2,
+		err:      permanentErr,
+	}, {
+		name: "max attempts reached",
+		errs: []error{
+			transientErr, transientErr, transientErr, transientErr,
+			transientErr,
+		},
+		attempts: 4,
+		err:      transientErr,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int
+			attempts, err := importWithRetry(
+				make(chan struct{}), cfg, func() error {
+					defer func() { calls++ }()
+
+					if calls < len(tc.errs) {
+						return tc.errs[calls]
+					}
+
+					return nil
+				},
+			)
+			require.Equal(t, tc.attempts, attempts)
+			require.Equal(t, int(tc.attempts), calls)
+			require.Equal(t, tc.err, err)
+		})
+	}
+
+	// A shutdown aborts the retry.
+	quit := make(chan struct{})
+	close(quit)
+	attempts, err := importWithRetry(quit, &ProofImportRetryCfg{
+		MaxAttempts:    10,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     time.Hour,
+	}, func() error {
+		return transientErr
+	})
+	require.EqualValues(t, 1, attempts)
+	require.ErrorIs(t, err, errImportAborted)
+}
+
+// TestProofImportRetryCfgValidate tests the validation of the proof import
+// retry config.
+func TestProofImportRetryCfgValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := ProofImportRetryCfg{
+		MaxAttempts:    1,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+	}
+	require.NoError(t, cfg.Validate())
+
+	invalid := cfg
+	invalid.MaxAttempts = 0
+	require.Error(t, invalid.Validate())
+
+	invalid = cfg
+	invalid.InitialBackoff = 0
+	require.Error(t, invalid.Validate())
+
+	invalid = cfg
+	invalid.MaxBackoff = time.Millisecond
+	require.Error(t, invalid.Validate())
+}
@@ -0,0 +1,113 @@
+package tapgarden
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+)
+
+// ReceiveStatus denotes the progress of an inbound asset transfer to one of
+// our addresses.
+type ReceiveStatus uint8
+
+const (
+	// ReceiveStatusDetected denotes that the anchor transaction of an
+	// inbound transfer was detected, but isn't confirmed yet.
+	ReceiveStatusDetected ReceiveStatus = iota
+
+	// ReceiveStatusConfirmed denotes that the anchor transaction of an
+	// inbound transfer was confirmed. The transfer now requires the proof
+	// to proceed.
+	ReceiveStatusConfirmed
+
+	// ReceiveStatusProofReceived denotes that the proof of an inbound
+	// transfer was fetched from the proof courier.
+	ReceiveStatusProofReceived
+
+	// ReceiveStatusProofVerified denotes that the proof of an inbound
+	// transfer was verified and imported into the proof archive.
+	ReceiveStatusProofVerified
+
+	// ReceiveStatusCompleted denotes that an inbound transfer was completed
+	// and we've taken custody of the received assets.
+	ReceiveStatusCompleted
+
+	// ReceiveStatusFailed denotes that the proof of an inbound transfer
+	// was rejected or couldn't be imported. The transfer can only be
+	// completed by importing its proof manually.
+	ReceiveStatusFailed
+)
+
+// String returns a human-readable version of the receive status.
+func (s ReceiveStatus) String() string {
+	switch s {
+	case ReceiveStatusDetected:
+		return "ReceiveStatusDetected"
+
+	case ReceiveStatusConfirmed:
+		return "ReceiveStatusConfirmed"
+
+	case ReceiveStatusProofReceived:
+		return "ReceiveStatusProofReceived"
+
+	case ReceiveStatusProofVerified:
+		return "ReceiveStatusProofVerified"
+
+	case ReceiveStatusCompleted:
+		return "ReceiveStatusCompleted"
+
+	case ReceiveStatusFailed:
+		return "ReceiveStatusFailed"
+
+	default:
+		return fmt.Sprintf("<unknown_receive_status(%d)>", s)
+	}
+}
+
+// AssetReceiveEvent is an event which is sent to the custodian's event
+// subscribers whenever an inbound asset transfer to one of our addresses
+// progresses.
+type AssetReceiveEvent struct {
+	// timestamp is the time the event was created.
+	timestamp time.Time
+
+	// ScriptKey is the script key of the address the assets are received
+	// with.
+	ScriptKey btcec.PublicKey
+
+	// AssetID is the ID of the asset that is received.
+	AssetID asset.ID
+
+	// OutPoint is the on-chain outpoint that carries the inbound transfer.
+	OutPoint wire.OutPoint
+
+	// Status is the status the inbound transfer progressed to.
+	Status ReceiveStatus
+
+	// Err is the reason the inbound transfer failed, if the status is
+	// ReceiveStatusFailed.
+	Err error
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *AssetReceiveEvent) Timestamp() time.Time {
+	return e.timestamp
+}
+
+// NewAssetReceiveEvent creates a new AssetReceiveEvent.
+func NewAssetReceiveEvent(scriptKey btcec.PublicKey, assetID asset.ID,
+	op wire.OutPoint, status ReceiveStatus,
+	err error) *AssetReceiveEvent {
+
+	return &AssetReceiveEvent{
+		timestamp: time.Now().UTC(),
+		ScriptKey: scriptKey,
+		AssetID:   assetID,
+		OutPoint:  op,
+		Status:    status,
+		Err:       err,
+	}
+}
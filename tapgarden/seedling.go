@@ -17,6 +17,11 @@ var (
 	// ErrInvalidAssetAmt is returned in an asset request has an invalid
 	// amount.
 	ErrInvalidAssetAmt = fmt.Errorf("asset amt cannot be zero")
+
+	// ErrSupplyCapNotGroupAnchor is returned if a seedling declares a
+	// supply cap but doesn't anchor a new asset group.
+	ErrSupplyCapNotGroupAnchor = fmt.Errorf("supply cap can only be set " +
+		"on the anchor of a new asset group")
 )
 
 // MintingState is an enum that tracks an asset through the various minting
@@ -93,6 +98,12 @@ type Seedling struct {
 	// same group key as the anchor asset.
 	GroupAnchor *string
 
+	// ScriptKeyTapscript is an optional tapscript tree the script key of
+	// the minted asset commits to, in addition to the key spend path of
+	// the script key's internal key. If nil, a BIP-0086 script key is
+	// used.
+	ScriptKeyTapscript *asset.TapscriptTree
+
 	// update is used to send updates w.r.t the state of the batch.
 	updates SeedlingUpdates
 }
@@ -122,6 +133,31 @@ func (c Seedling) validateFields() error {
 		return ErrInvalidAssetAmt
 	}
 
+	if c.ScriptKeyTapscript != nil {
+		err := c.ScriptKeyTapscript.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid script key tapscript: %w",
+				err)
+		}
+	}
+
+	// A supply cap is committed to in the meta of the group anchor, so it
+	// can only be declared by a seedling that starts a new group.
+	supplyCap := c.SupplyCap()
+	if supplyCap != 0 {
+		if !c.EnableEmission || c.HasGroupKey() ||
+			c.GroupAnchor != nil {
+
+			return ErrSupplyCapNotGroupAnchor
+		}
+
+		if c.Amount > supplyCap {
+			return fmt.Errorf("%w: amount %d, cap %d",
+				proof.ErrSupplyCapExceeded, c.Amount,
+				supplyCap)
+		}
+	}
+
 	return nil
 }
 
@@ -148,6 +184,16 @@ func (c Seedling) HasGroupKey() bool {
 	return c.GroupInfo != nil && c.GroupInfo.GroupKey != nil
 }
 
+// SupplyCap returns the supply cap declared in the seedling's metadata, or
+// zero if the seedling doesn't declare one.
+func (c Seedling) SupplyCap() uint64 {
+	if c.Meta == nil {
+		return 0
+	}
+
+	return c.Meta.SupplyCap
+}
+
 // String returns a human-readable representation for the AssetSeedling.
 func (c Seedling) String() string {
 	return fmt.Sprintf("AssetSeedling(name=%v, type=%v, amt=%v, "+
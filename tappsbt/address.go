@@ -2,13 +2,21 @@ package tappsbt
 
 import (
 	"fmt"
+	"math"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightninglabs/taproot-assets/address"
 	"github.com/lightninglabs/taproot-assets/asset"
 	"github.com/lightningnetwork/lnd/keychain"
 )
 
+var (
+	// ownershipChallengeTag is the tag used to hash an ownership proof
+	// challenge into the previous outpoint of the ownership proof packet.
+	ownershipChallengeTag = []byte("taproot-assets/ownership-challenge")
+)
+
 // OutputIdxToAddr is a map from a VPacket's VOutput index to its associated
 // Tap address.
 type OutputIdxToAddr map[int]address.Tap
@@ -131,8 +139,10 @@ func AddOutput(pkt *VPacket, amount uint64, scriptAddr asset.ScriptKey,
 // OwnershipProofPacket creates a virtual transaction packet that is used to
 // prove ownership of an asset. It creates a 1-in-1-out transaction that spends
 // the owned asset to the NUMS key. The witness is created over an empty
-// previous outpoint, so it can never be used in an actual state transition.
-func OwnershipProofPacket(ownedAsset *asset.Asset,
+// previous outpoint, so it can never be used in an actual state transition. If
+// a challenge is given, it is committed to in the previous outpoint instead,
+// so the witness is only valid for that challenge.
+func OwnershipProofPacket(ownedAsset *asset.Asset, challenge *[32]byte,
 	chainParams *address.ChainParams) *VPacket {
 
 	// We create the ownership proof by creating a virtual packet that
@@ -144,10 +154,13 @@ func OwnershipProofPacket(ownedAsset *asset.Asset,
 	// actually goes on chain.
 	//
 	// TODO(guggero): Revisit this proof once we support pocket universes.
-	emptyOutPoint := wire.OutPoint{}
+	prevOutPoint := wire.OutPoint{}
+	if challenge != nil {
+		prevOutPoint = ownershipChallengeOutPoint(*challenge)
+	}
 	prevId := asset.PrevID{
 		ID:       ownedAsset.ID(),
-		OutPoint: emptyOutPoint,
+		OutPoint: prevOutPoint,
 		ScriptKey: asset.ToSerialized(
 			ownedAsset.ScriptKey.PubKey,
 		),
@@ -177,3 +190,17 @@ func OwnershipProofPacket(ownedAsset *asset.Asset,
 
 	return vPkt
 }
+
+// ownershipChallengeOutPoint returns the previous outpoint an ownership proof
+// for the given challenge is signed over. The challenge is hashed and the
+// maximum output index is used, so a verifier can't pick a challenge that
+// results in the outpoint of an actual asset output. Otherwise the witness
+// could be used to spend the asset to the NUMS key.
+func ownershipChallengeOutPoint(challenge [32]byte) wire.OutPoint {
+	return wire.OutPoint{
+		Hash: *chainhash.TaggedHash(
+			ownershipChallengeTag, challenge[:],
+		),
+		Index: math.MaxUint32,
+	}
+}
@@ -31,6 +31,15 @@ type FundVirtualPsbtRequest struct {
 	//	*FundVirtualPsbtRequest_Psbt
 	//	*FundVirtualPsbtRequest_Raw
 	Template isFundVirtualPsbtRequest_Template `protobuf_oneof:"template"`
+	// The order in which the tranches of a grouped asset are considered when
+	// selecting the inputs, oldest and newest refer to the genesis block height
+	// of a tranche. Valid options are none, oldest, newest and genesispoint. If
+	// none, only inputs of the asset ID of the template are selected. If empty,
+	// the tranchepreference of the daemon config is used.
+	TranchePreference string `protobuf:"bytes,3,opt,name=tranche_preference,json=tranchePreference,proto3" json:"tranche_preference,omitempty"`
+	// The genesis point (txid:index) of the tranche to prefer, only used if
+	// tranche_preference is genesispoint.
+	TrancheGenesisPoint string `protobuf:"bytes,4,opt,name=tranche_genesis_point,json=trancheGenesisPoint,proto3" json:"tranche_genesis_point,omitempty"`
 }
 
 func (x *FundVirtualPsbtRequest) Reset() {
@@ -86,6 +95,20 @@ func (x *FundVirtualPsbtRequest) GetRaw() *TxTemplate {
 	return nil
 }
 
+func (x *FundVirtualPsbtRequest) GetTranchePreference() string {
+	if x != nil {
+		return x.TranchePreference
+	}
+	return ""
+}
+
+func (x *FundVirtualPsbtRequest) GetTrancheGenesisPoint() string {
+	if x != nil {
+		return x.TrancheGenesisPoint
+	}
+	return ""
+}
+
 type isFundVirtualPsbtRequest_Template interface {
 	isFundVirtualPsbtRequest_Template()
 }
@@ -459,18 +482,32 @@ func (x *SignVirtualPsbtResponse) GetSignedInputs() []uint32 {
 	return nil
 }
 
-type AnchorVirtualPsbtsRequest struct {
+type PrepareScriptSpendRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The list of virtual transactions that should be merged and committed to in
-	// the BTC level anchor transaction.
-	VirtualPsbts [][]byte `protobuf:"bytes,1,rep,name=virtual_psbts,json=virtualPsbts,proto3" json:"virtual_psbts,omitempty"`
-}
-
-func (x *AnchorVirtualPsbtsRequest) Reset() {
-	*x = AnchorVirtualPsbtsRequest{}
+	// The PSBT of the funded virtual transaction that contains the input that
+	// should be spent through the script path.
+	FundedPsbt []byte `protobuf:"bytes,1,opt,name=funded_psbt,json=fundedPsbt,proto3" json:"funded_psbt,omitempty"`
+	// The index of the input that should be spent through the script path.
+	InputIndex uint32 `protobuf:"varint,2,opt,name=input_index,json=inputIndex,proto3" json:"input_index,omitempty"`
+	// The script of the leaf the input should be spent through.
+	LeafScript []byte `protobuf:"bytes,3,opt,name=leaf_script,json=leafScript,proto3" json:"leaf_script,omitempty"`
+	// The version of the leaf the input should be spent through. If this is zero,
+	// the base leaf version 0xc0 is used.
+	LeafVersion uint32 `protobuf:"varint,4,opt,name=leaf_version,json=leafVersion,proto3" json:"leaf_version,omitempty"`
+	// The control block that proves that the leaf is committed to by the script
+	// key of the input asset.
+	ControlBlock []byte `protobuf:"bytes,5,opt,name=control_block,json=controlBlock,proto3" json:"control_block,omitempty"`
+	// The witness stack that satisfies the leaf script, without the leaf script
+	// and the control block, which are appended. If this is empty, the input is
+	// signed with the internal key of its script key by SignVirtualPsbt instead.
+	Witness [][]byte `protobuf:"bytes,6,rep,name=witness,proto3" json:"witness,omitempty"`
+}
+
+func (x *PrepareScriptSpendRequest) Reset() {
+	*x = PrepareScriptSpendRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -478,13 +515,13 @@ func (x *AnchorVirtualPsbtsRequest) Reset() {
 	}
 }
 
-func (x *AnchorVirtualPsbtsRequest) String() string {
+func (x *PrepareScriptSpendRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AnchorVirtualPsbtsRequest) ProtoMessage() {}
+func (*PrepareScriptSpendRequest) ProtoMessage() {}
 
-func (x *AnchorVirtualPsbtsRequest) ProtoReflect() protoreflect.Message {
+func (x *PrepareScriptSpendRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -496,28 +533,64 @@ func (x *AnchorVirtualPsbtsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AnchorVirtualPsbtsRequest.ProtoReflect.Descriptor instead.
-func (*AnchorVirtualPsbtsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrepareScriptSpendRequest.ProtoReflect.Descriptor instead.
+func (*PrepareScriptSpendRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *AnchorVirtualPsbtsRequest) GetVirtualPsbts() [][]byte {
+func (x *PrepareScriptSpendRequest) GetFundedPsbt() []byte {
 	if x != nil {
-		return x.VirtualPsbts
+		return x.FundedPsbt
 	}
 	return nil
 }
 
-type NextInternalKeyRequest struct {
+func (x *PrepareScriptSpendRequest) GetInputIndex() uint32 {
+	if x != nil {
+		return x.InputIndex
+	}
+	return 0
+}
+
+func (x *PrepareScriptSpendRequest) GetLeafScript() []byte {
+	if x != nil {
+		return x.LeafScript
+	}
+	return nil
+}
+
+func (x *PrepareScriptSpendRequest) GetLeafVersion() uint32 {
+	if x != nil {
+		return x.LeafVersion
+	}
+	return 0
+}
+
+func (x *PrepareScriptSpendRequest) GetControlBlock() []byte {
+	if x != nil {
+		return x.ControlBlock
+	}
+	return nil
+}
+
+func (x *PrepareScriptSpendRequest) GetWitness() [][]byte {
+	if x != nil {
+		return x.Witness
+	}
+	return nil
+}
+
+type PrepareScriptSpendResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The virtual transaction in PSBT format with the prepared input.
+	PreparedPsbt []byte `protobuf:"bytes,1,opt,name=prepared_psbt,json=preparedPsbt,proto3" json:"prepared_psbt,omitempty"`
 }
 
-func (x *NextInternalKeyRequest) Reset() {
-	*x = NextInternalKeyRequest{}
+func (x *PrepareScriptSpendResponse) Reset() {
+	*x = PrepareScriptSpendResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -525,13 +598,13 @@ func (x *NextInternalKeyRequest) Reset() {
 	}
 }
 
-func (x *NextInternalKeyRequest) String() string {
+func (x *PrepareScriptSpendResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NextInternalKeyRequest) ProtoMessage() {}
+func (*PrepareScriptSpendResponse) ProtoMessage() {}
 
-func (x *NextInternalKeyRequest) ProtoReflect() protoreflect.Message {
+func (x *PrepareScriptSpendResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -543,28 +616,30 @@ func (x *NextInternalKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NextInternalKeyRequest.ProtoReflect.Descriptor instead.
-func (*NextInternalKeyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PrepareScriptSpendResponse.ProtoReflect.Descriptor instead.
+func (*PrepareScriptSpendResponse) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *NextInternalKeyRequest) GetKeyFamily() uint32 {
+func (x *PrepareScriptSpendResponse) GetPreparedPsbt() []byte {
 	if x != nil {
-		return x.KeyFamily
+		return x.PreparedPsbt
 	}
-	return 0
+	return nil
 }
 
-type NextInternalKeyResponse struct {
+type AnchorVirtualPsbtsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	InternalKey *taprpc.KeyDescriptor `protobuf:"bytes,1,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	// The list of virtual transactions that should be merged and committed to in
+	// the BTC level anchor transaction.
+	VirtualPsbts [][]byte `protobuf:"bytes,1,rep,name=virtual_psbts,json=virtualPsbts,proto3" json:"virtual_psbts,omitempty"`
 }
 
-func (x *NextInternalKeyResponse) Reset() {
-	*x = NextInternalKeyResponse{}
+func (x *AnchorVirtualPsbtsRequest) Reset() {
+	*x = AnchorVirtualPsbtsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -572,13 +647,13 @@ func (x *NextInternalKeyResponse) Reset() {
 	}
 }
 
-func (x *NextInternalKeyResponse) String() string {
+func (x *AnchorVirtualPsbtsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NextInternalKeyResponse) ProtoMessage() {}
+func (*AnchorVirtualPsbtsRequest) ProtoMessage() {}
 
-func (x *NextInternalKeyResponse) ProtoReflect() protoreflect.Message {
+func (x *AnchorVirtualPsbtsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -590,28 +665,33 @@ func (x *NextInternalKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NextInternalKeyResponse.ProtoReflect.Descriptor instead.
-func (*NextInternalKeyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use AnchorVirtualPsbtsRequest.ProtoReflect.Descriptor instead.
+func (*AnchorVirtualPsbtsRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *NextInternalKeyResponse) GetInternalKey() *taprpc.KeyDescriptor {
+func (x *AnchorVirtualPsbtsRequest) GetVirtualPsbts() [][]byte {
 	if x != nil {
-		return x.InternalKey
+		return x.VirtualPsbts
 	}
 	return nil
 }
 
-type NextScriptKeyRequest struct {
+type CommitVirtualPsbtsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The signed virtual transactions the anchor transaction should commit to.
+	// Only a single virtual transaction is currently supported.
+	VirtualPsbts [][]byte `protobuf:"bytes,1,rep,name=virtual_psbts,json=virtualPsbts,proto3" json:"virtual_psbts,omitempty"`
+	// The fee rate in sat/kw the anchor transaction is funded with. If zero, the
+	// fee rate is estimated.
+	FeeRate uint32 `protobuf:"varint,2,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
 }
 
-func (x *NextScriptKeyRequest) Reset() {
-	*x = NextScriptKeyRequest{}
+func (x *CommitVirtualPsbtsRequest) Reset() {
+	*x = CommitVirtualPsbtsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -619,13 +699,13 @@ func (x *NextScriptKeyRequest) Reset() {
 	}
 }
 
-func (x *NextScriptKeyRequest) String() string {
+func (x *CommitVirtualPsbtsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NextScriptKeyRequest) ProtoMessage() {}
+func (*CommitVirtualPsbtsRequest) ProtoMessage() {}
 
-func (x *NextScriptKeyRequest) ProtoReflect() protoreflect.Message {
+func (x *CommitVirtualPsbtsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -637,28 +717,50 @@ func (x *NextScriptKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NextScriptKeyRequest.ProtoReflect.Descriptor instead.
-func (*NextScriptKeyRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CommitVirtualPsbtsRequest.ProtoReflect.Descriptor instead.
+func (*CommitVirtualPsbtsRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *NextScriptKeyRequest) GetKeyFamily() uint32 {
+func (x *CommitVirtualPsbtsRequest) GetVirtualPsbts() [][]byte {
 	if x != nil {
-		return x.KeyFamily
+		return x.VirtualPsbts
+	}
+	return nil
+}
+
+func (x *CommitVirtualPsbtsRequest) GetFeeRate() uint32 {
+	if x != nil {
+		return x.FeeRate
 	}
 	return 0
 }
 
-type NextScriptKeyResponse struct {
+type CommitVirtualPsbtsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ScriptKey *taprpc.ScriptKey `protobuf:"bytes,1,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-}
-
-func (x *NextScriptKeyResponse) Reset() {
-	*x = NextScriptKeyResponse{}
+	// The funded but not yet signed anchor transaction in PSBT format. It
+	// contains the asset inputs as well as the BTC inputs the wallet funded it
+	// with, all of which must be signed.
+	AnchorPsbt []byte `protobuf:"bytes,1,opt,name=anchor_psbt,json=anchorPsbt,proto3" json:"anchor_psbt,omitempty"`
+	// The virtual transactions the anchor transaction commits to.
+	VirtualPsbts [][]byte `protobuf:"bytes,2,rep,name=virtual_psbts,json=virtualPsbts,proto3" json:"virtual_psbts,omitempty"`
+	// The signed virtual transactions that re-anchor the passive assets of the
+	// spent inputs. These must be handed to PublishAndLogTransfer together with
+	// the signed anchor transaction.
+	PassiveAssetPsbts [][]byte `protobuf:"bytes,3,rep,name=passive_asset_psbts,json=passiveAssetPsbts,proto3" json:"passive_asset_psbts,omitempty"`
+	// The index of the change output of the anchor transaction or -1 if there is
+	// no change output.
+	ChangeOutputIndex int32 `protobuf:"varint,4,opt,name=change_output_index,json=changeOutputIndex,proto3" json:"change_output_index,omitempty"`
+	// The wallet UTXOs that were leased to fund the anchor transaction, in the
+	// form of "txid:index".
+	LndLockedUtxos []string `protobuf:"bytes,5,rep,name=lnd_locked_utxos,json=lndLockedUtxos,proto3" json:"lnd_locked_utxos,omitempty"`
+}
+
+func (x *CommitVirtualPsbtsResponse) Reset() {
+	*x = CommitVirtualPsbtsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -666,13 +768,13 @@ func (x *NextScriptKeyResponse) Reset() {
 	}
 }
 
-func (x *NextScriptKeyResponse) String() string {
+func (x *CommitVirtualPsbtsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NextScriptKeyResponse) ProtoMessage() {}
+func (*CommitVirtualPsbtsResponse) ProtoMessage() {}
 
-func (x *NextScriptKeyResponse) ProtoReflect() protoreflect.Message {
+func (x *CommitVirtualPsbtsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -684,29 +786,72 @@ func (x *NextScriptKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NextScriptKeyResponse.ProtoReflect.Descriptor instead.
-func (*NextScriptKeyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use CommitVirtualPsbtsResponse.ProtoReflect.Descriptor instead.
+func (*CommitVirtualPsbtsResponse) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NextScriptKeyResponse) GetScriptKey() *taprpc.ScriptKey {
+func (x *CommitVirtualPsbtsResponse) GetAnchorPsbt() []byte {
 	if x != nil {
-		return x.ScriptKey
+		return x.AnchorPsbt
 	}
 	return nil
 }
 
-type ProveAssetOwnershipRequest struct {
+func (x *CommitVirtualPsbtsResponse) GetVirtualPsbts() [][]byte {
+	if x != nil {
+		return x.VirtualPsbts
+	}
+	return nil
+}
+
+func (x *CommitVirtualPsbtsResponse) GetPassiveAssetPsbts() [][]byte {
+	if x != nil {
+		return x.PassiveAssetPsbts
+	}
+	return nil
+}
+
+func (x *CommitVirtualPsbtsResponse) GetChangeOutputIndex() int32 {
+	if x != nil {
+		return x.ChangeOutputIndex
+	}
+	return 0
+}
+
+func (x *CommitVirtualPsbtsResponse) GetLndLockedUtxos() []string {
+	if x != nil {
+		return x.LndLockedUtxos
+	}
+	return nil
+}
+
+type PublishAndLogRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AssetId   []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-}
-
-func (x *ProveAssetOwnershipRequest) Reset() {
-	*x = ProveAssetOwnershipRequest{}
+	// The externally signed anchor transaction in PSBT format, as returned by
+	// CommitVirtualPsbts with all inputs signed.
+	AnchorPsbt []byte `protobuf:"bytes,1,opt,name=anchor_psbt,json=anchorPsbt,proto3" json:"anchor_psbt,omitempty"`
+	// The virtual transactions the anchor transaction commits to, as returned by
+	// CommitVirtualPsbts.
+	VirtualPsbts [][]byte `protobuf:"bytes,2,rep,name=virtual_psbts,json=virtualPsbts,proto3" json:"virtual_psbts,omitempty"`
+	// The passive asset re-anchoring virtual transactions, as returned by
+	// CommitVirtualPsbts.
+	PassiveAssetPsbts [][]byte `protobuf:"bytes,3,rep,name=passive_asset_psbts,json=passiveAssetPsbts,proto3" json:"passive_asset_psbts,omitempty"`
+	// The index of the change output of the anchor transaction or -1 if there is
+	// no change output, as returned by CommitVirtualPsbts.
+	ChangeOutputIndex int32 `protobuf:"varint,4,opt,name=change_output_index,json=changeOutputIndex,proto3" json:"change_output_index,omitempty"`
+	// The wallet UTXOs that were leased to fund the anchor transaction, as
+	// returned by CommitVirtualPsbts.
+	LndLockedUtxos []string `protobuf:"bytes,5,rep,name=lnd_locked_utxos,json=lndLockedUtxos,proto3" json:"lnd_locked_utxos,omitempty"`
+	// An optional human-readable label the transfer is stored with.
+	Label string `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
+}
+
+func (x *PublishAndLogRequest) Reset() {
+	*x = PublishAndLogRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -714,13 +859,13 @@ func (x *ProveAssetOwnershipRequest) Reset() {
 	}
 }
 
-func (x *ProveAssetOwnershipRequest) String() string {
+func (x *PublishAndLogRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProveAssetOwnershipRequest) ProtoMessage() {}
+func (*PublishAndLogRequest) ProtoMessage() {}
 
-func (x *ProveAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
+func (x *PublishAndLogRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -732,35 +877,63 @@ func (x *ProveAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProveAssetOwnershipRequest.ProtoReflect.Descriptor instead.
-func (*ProveAssetOwnershipRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PublishAndLogRequest.ProtoReflect.Descriptor instead.
+func (*PublishAndLogRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ProveAssetOwnershipRequest) GetAssetId() []byte {
+func (x *PublishAndLogRequest) GetAnchorPsbt() []byte {
 	if x != nil {
-		return x.AssetId
+		return x.AnchorPsbt
 	}
 	return nil
 }
 
-func (x *ProveAssetOwnershipRequest) GetScriptKey() []byte {
+func (x *PublishAndLogRequest) GetVirtualPsbts() [][]byte {
 	if x != nil {
-		return x.ScriptKey
+		return x.VirtualPsbts
 	}
 	return nil
 }
 
-type ProveAssetOwnershipResponse struct {
+func (x *PublishAndLogRequest) GetPassiveAssetPsbts() [][]byte {
+	if x != nil {
+		return x.PassiveAssetPsbts
+	}
+	return nil
+}
+
+func (x *PublishAndLogRequest) GetChangeOutputIndex() int32 {
+	if x != nil {
+		return x.ChangeOutputIndex
+	}
+	return 0
+}
+
+func (x *PublishAndLogRequest) GetLndLockedUtxos() []string {
+	if x != nil {
+		return x.LndLockedUtxos
+	}
+	return nil
+}
+
+func (x *PublishAndLogRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type NextInternalKeyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProofWithWitness []byte `protobuf:"bytes,1,opt,name=proof_with_witness,json=proofWithWitness,proto3" json:"proof_with_witness,omitempty"`
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
 }
 
-func (x *ProveAssetOwnershipResponse) Reset() {
-	*x = ProveAssetOwnershipResponse{}
+func (x *NextInternalKeyRequest) Reset() {
+	*x = NextInternalKeyRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -768,13 +941,13 @@ func (x *ProveAssetOwnershipResponse) Reset() {
 	}
 }
 
-func (x *ProveAssetOwnershipResponse) String() string {
+func (x *NextInternalKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProveAssetOwnershipResponse) ProtoMessage() {}
+func (*NextInternalKeyRequest) ProtoMessage() {}
 
-func (x *ProveAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
+func (x *NextInternalKeyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -786,28 +959,28 @@ func (x *ProveAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProveAssetOwnershipResponse.ProtoReflect.Descriptor instead.
-func (*ProveAssetOwnershipResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use NextInternalKeyRequest.ProtoReflect.Descriptor instead.
+func (*NextInternalKeyRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ProveAssetOwnershipResponse) GetProofWithWitness() []byte {
+func (x *NextInternalKeyRequest) GetKeyFamily() uint32 {
 	if x != nil {
-		return x.ProofWithWitness
+		return x.KeyFamily
 	}
-	return nil
+	return 0
 }
 
-type VerifyAssetOwnershipRequest struct {
+type NextInternalKeyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ProofWithWitness []byte `protobuf:"bytes,1,opt,name=proof_with_witness,json=proofWithWitness,proto3" json:"proof_with_witness,omitempty"`
+	InternalKey *taprpc.KeyDescriptor `protobuf:"bytes,1,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
 }
 
-func (x *VerifyAssetOwnershipRequest) Reset() {
-	*x = VerifyAssetOwnershipRequest{}
+func (x *NextInternalKeyResponse) Reset() {
+	*x = NextInternalKeyResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -815,13 +988,13 @@ func (x *VerifyAssetOwnershipRequest) Reset() {
 	}
 }
 
-func (x *VerifyAssetOwnershipRequest) String() string {
+func (x *NextInternalKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyAssetOwnershipRequest) ProtoMessage() {}
+func (*NextInternalKeyResponse) ProtoMessage() {}
 
-func (x *VerifyAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
+func (x *NextInternalKeyResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -833,28 +1006,28 @@ func (x *VerifyAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyAssetOwnershipRequest.ProtoReflect.Descriptor instead.
-func (*VerifyAssetOwnershipRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use NextInternalKeyResponse.ProtoReflect.Descriptor instead.
+func (*NextInternalKeyResponse) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *VerifyAssetOwnershipRequest) GetProofWithWitness() []byte {
+func (x *NextInternalKeyResponse) GetInternalKey() *taprpc.KeyDescriptor {
 	if x != nil {
-		return x.ProofWithWitness
+		return x.InternalKey
 	}
 	return nil
 }
 
-type VerifyAssetOwnershipResponse struct {
+type NextScriptKeyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ValidProof bool `protobuf:"varint,1,opt,name=valid_proof,json=validProof,proto3" json:"valid_proof,omitempty"`
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
 }
 
-func (x *VerifyAssetOwnershipResponse) Reset() {
-	*x = VerifyAssetOwnershipResponse{}
+func (x *NextScriptKeyRequest) Reset() {
+	*x = NextScriptKeyRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -862,13 +1035,13 @@ func (x *VerifyAssetOwnershipResponse) Reset() {
 	}
 }
 
-func (x *VerifyAssetOwnershipResponse) String() string {
+func (x *NextScriptKeyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyAssetOwnershipResponse) ProtoMessage() {}
+func (*NextScriptKeyRequest) ProtoMessage() {}
 
-func (x *VerifyAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
+func (x *NextScriptKeyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -880,29 +1053,28 @@ func (x *VerifyAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyAssetOwnershipResponse.ProtoReflect.Descriptor instead.
-func (*VerifyAssetOwnershipResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use NextScriptKeyRequest.ProtoReflect.Descriptor instead.
+func (*NextScriptKeyRequest) Descriptor() ([]byte, []int) {
 	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *VerifyAssetOwnershipResponse) GetValidProof() bool {
+func (x *NextScriptKeyRequest) GetKeyFamily() uint32 {
 	if x != nil {
-		return x.ValidProof
+		return x.KeyFamily
 	}
-	return false
+	return 0
 }
 
-type RemoveUTXOLeaseRequest struct {
+type NextScriptKeyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The outpoint of the UTXO to remove the lease for.
-	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	ScriptKey *taprpc.ScriptKey `protobuf:"bytes,1,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
 }
 
-func (x *RemoveUTXOLeaseRequest) Reset() {
-	*x = RemoveUTXOLeaseRequest{}
+func (x *NextScriptKeyResponse) Reset() {
+	*x = NextScriptKeyResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -910,14 +1082,1148 @@ func (x *RemoveUTXOLeaseRequest) Reset() {
 	}
 }
 
+func (x *NextScriptKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextScriptKeyResponse) ProtoMessage() {}
+
+func (x *NextScriptKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextScriptKeyResponse.ProtoReflect.Descriptor instead.
+func (*NextScriptKeyResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *NextScriptKeyResponse) GetScriptKey() *taprpc.ScriptKey {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type GetKeyDerivationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset ID of the owned asset.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key of the owned asset.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *GetKeyDerivationRequest) Reset() {
+	*x = GetKeyDerivationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetKeyDerivationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyDerivationRequest) ProtoMessage() {}
+
+func (x *GetKeyDerivationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyDerivationRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyDerivationRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetKeyDerivationRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *GetKeyDerivationRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type KeyDerivation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key descriptor of the derived key.
+	KeyDesc *taprpc.KeyDescriptor `protobuf:"bytes,1,opt,name=key_desc,json=keyDesc,proto3" json:"key_desc,omitempty"`
+	// The full BIP-0032 derivation path of the key, in the form
+	// m/1017'/coin_type'/key_family'/0/key_index.
+	DerivationPath string `protobuf:"bytes,2,opt,name=derivation_path,json=derivationPath,proto3" json:"derivation_path,omitempty"`
+}
+
+func (x *KeyDerivation) Reset() {
+	*x = KeyDerivation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyDerivation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyDerivation) ProtoMessage() {}
+
+func (x *KeyDerivation) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyDerivation.ProtoReflect.Descriptor instead.
+func (*KeyDerivation) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *KeyDerivation) GetKeyDesc() *taprpc.KeyDescriptor {
+	if x != nil {
+		return x.KeyDesc
+	}
+	return nil
+}
+
+func (x *KeyDerivation) GetDerivationPath() string {
+	if x != nil {
+		return x.DerivationPath
+	}
+	return ""
+}
+
+type GetKeyDerivationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The derivation information of the internal key of the script key.
+	ScriptKeyDerivation *KeyDerivation `protobuf:"bytes,1,opt,name=script_key_derivation,json=scriptKeyDerivation,proto3" json:"script_key_derivation,omitempty"`
+	// The optional Taproot tweak applied to the internal key of the script
+	// key. If this is empty then a BIP-86 style tweak is applied.
+	ScriptKeyTapTweak []byte `protobuf:"bytes,2,opt,name=script_key_tap_tweak,json=scriptKeyTapTweak,proto3" json:"script_key_tap_tweak,omitempty"`
+	// The derivation information of the internal key of the anchor output.
+	AnchorInternalKeyDerivation *KeyDerivation `protobuf:"bytes,3,opt,name=anchor_internal_key_derivation,json=anchorInternalKeyDerivation,proto3" json:"anchor_internal_key_derivation,omitempty"`
+	// The anchor outpoint of the asset, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,4,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+}
+
+func (x *GetKeyDerivationResponse) Reset() {
+	*x = GetKeyDerivationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetKeyDerivationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyDerivationResponse) ProtoMessage() {}
+
+func (x *GetKeyDerivationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyDerivationResponse.ProtoReflect.Descriptor instead.
+func (*GetKeyDerivationResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetKeyDerivationResponse) GetScriptKeyDerivation() *KeyDerivation {
+	if x != nil {
+		return x.ScriptKeyDerivation
+	}
+	return nil
+}
+
+func (x *GetKeyDerivationResponse) GetScriptKeyTapTweak() []byte {
+	if x != nil {
+		return x.ScriptKeyTapTweak
+	}
+	return nil
+}
+
+func (x *GetKeyDerivationResponse) GetAnchorInternalKeyDerivation() *KeyDerivation {
+	if x != nil {
+		return x.AnchorInternalKeyDerivation
+	}
+	return nil
+}
+
+func (x *GetKeyDerivationResponse) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+type ProveAssetOwnershipRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AssetId   []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// An optional 32-byte challenge chosen by the verifier, usually a random
+	// nonce. The ownership proof witness commits to the challenge, so the
+	// proof can't be replayed to a verifier that uses a different challenge.
+	Challenge []byte `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+}
+
+func (x *ProveAssetOwnershipRequest) Reset() {
+	*x = ProveAssetOwnershipRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProveAssetOwnershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProveAssetOwnershipRequest) ProtoMessage() {}
+
+func (x *ProveAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProveAssetOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*ProveAssetOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ProveAssetOwnershipRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *ProveAssetOwnershipRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *ProveAssetOwnershipRequest) GetChallenge() []byte {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+type ProveAssetOwnershipResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProofWithWitness []byte `protobuf:"bytes,1,opt,name=proof_with_witness,json=proofWithWitness,proto3" json:"proof_with_witness,omitempty"`
+}
+
+func (x *ProveAssetOwnershipResponse) Reset() {
+	*x = ProveAssetOwnershipResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProveAssetOwnershipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProveAssetOwnershipResponse) ProtoMessage() {}
+
+func (x *ProveAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProveAssetOwnershipResponse.ProtoReflect.Descriptor instead.
+func (*ProveAssetOwnershipResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProveAssetOwnershipResponse) GetProofWithWitness() []byte {
+	if x != nil {
+		return x.ProofWithWitness
+	}
+	return nil
+}
+
+type VerifyAssetOwnershipRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ProofWithWitness []byte `protobuf:"bytes,1,opt,name=proof_with_witness,json=proofWithWitness,proto3" json:"proof_with_witness,omitempty"`
+	// The optional 32-byte challenge the ownership proof was requested with.
+	// If set, the proof is only valid if its witness commits to exactly this
+	// challenge.
+	Challenge []byte `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+}
+
+func (x *VerifyAssetOwnershipRequest) Reset() {
+	*x = VerifyAssetOwnershipRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyAssetOwnershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAssetOwnershipRequest) ProtoMessage() {}
+
+func (x *VerifyAssetOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAssetOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*VerifyAssetOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *VerifyAssetOwnershipRequest) GetProofWithWitness() []byte {
+	if x != nil {
+		return x.ProofWithWitness
+	}
+	return nil
+}
+
+func (x *VerifyAssetOwnershipRequest) GetChallenge() []byte {
+	if x != nil {
+		return x.Challenge
+	}
+	return nil
+}
+
+type VerifyAssetOwnershipResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ValidProof bool `protobuf:"varint,1,opt,name=valid_proof,json=validProof,proto3" json:"valid_proof,omitempty"`
+}
+
+func (x *VerifyAssetOwnershipResponse) Reset() {
+	*x = VerifyAssetOwnershipResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyAssetOwnershipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyAssetOwnershipResponse) ProtoMessage() {}
+
+func (x *VerifyAssetOwnershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyAssetOwnershipResponse.ProtoReflect.Descriptor instead.
+func (*VerifyAssetOwnershipResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *VerifyAssetOwnershipResponse) GetValidProof() bool {
+	if x != nil {
+		return x.ValidProof
+	}
+	return false
+}
+
+type RemoveUTXOLeaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the UTXO to remove the lease for.
+	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+}
+
+func (x *RemoveUTXOLeaseRequest) Reset() {
+	*x = RemoveUTXOLeaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
 func (x *RemoveUTXOLeaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveUTXOLeaseRequest) ProtoMessage() {}
+func (*RemoveUTXOLeaseRequest) ProtoMessage() {}
+
+func (x *RemoveUTXOLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveUTXOLeaseRequest.ProtoReflect.Descriptor instead.
+func (*RemoveUTXOLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RemoveUTXOLeaseRequest) GetOutpoint() *OutPoint {
+	if x != nil {
+		return x.Outpoint
+	}
+	return nil
+}
+
+type RemoveUTXOLeaseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RemoveUTXOLeaseResponse) Reset() {
+	*x = RemoveUTXOLeaseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RemoveUTXOLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveUTXOLeaseResponse) ProtoMessage() {}
+
+func (x *RemoveUTXOLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveUTXOLeaseResponse.ProtoReflect.Descriptor instead.
+func (*RemoveUTXOLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{25}
+}
+
+type ListLeasesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListLeasesRequest) Reset() {
+	*x = ListLeasesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLeasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeasesRequest) ProtoMessage() {}
+
+func (x *ListLeasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeasesRequest.ProtoReflect.Descriptor instead.
+func (*ListLeasesRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{26}
+}
+
+type UTXOLease struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the leased UTXO.
+	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The amount of satoshis carried by the leased UTXO.
+	AmtSats int64 `protobuf:"varint,2,opt,name=amt_sats,json=amtSats,proto3" json:"amt_sats,omitempty"`
+	// The owner of the lease.
+	LeaseOwner []byte `protobuf:"bytes,3,opt,name=lease_owner,json=leaseOwner,proto3" json:"lease_owner,omitempty"`
+	// The Unix timestamp in seconds at which the lease expires.
+	LeaseExpiry int64 `protobuf:"varint,4,opt,name=lease_expiry,json=leaseExpiry,proto3" json:"lease_expiry,omitempty"`
+	// The hash of the anchor transaction of the transfer that spends the UTXO
+	// and therefore holds the lease. This is empty if the lease isn't held by a
+	// logged transfer.
+	TransferAnchorTxHash []byte `protobuf:"bytes,5,opt,name=transfer_anchor_tx_hash,json=transferAnchorTxHash,proto3" json:"transfer_anchor_tx_hash,omitempty"`
+}
+
+func (x *UTXOLease) Reset() {
+	*x = UTXOLease{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UTXOLease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UTXOLease) ProtoMessage() {}
+
+func (x *UTXOLease) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UTXOLease.ProtoReflect.Descriptor instead.
+func (*UTXOLease) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UTXOLease) GetOutpoint() *OutPoint {
+	if x != nil {
+		return x.Outpoint
+	}
+	return nil
+}
+
+func (x *UTXOLease) GetAmtSats() int64 {
+	if x != nil {
+		return x.AmtSats
+	}
+	return 0
+}
+
+func (x *UTXOLease) GetLeaseOwner() []byte {
+	if x != nil {
+		return x.LeaseOwner
+	}
+	return nil
+}
+
+func (x *UTXOLease) GetLeaseExpiry() int64 {
+	if x != nil {
+		return x.LeaseExpiry
+	}
+	return 0
+}
+
+func (x *UTXOLease) GetTransferAnchorTxHash() []byte {
+	if x != nil {
+		return x.TransferAnchorTxHash
+	}
+	return nil
+}
+
+type ListLeasesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The leases that haven't expired yet, ordered by their expiry.
+	Leases []*UTXOLease `protobuf:"bytes,1,rep,name=leases,proto3" json:"leases,omitempty"`
+}
+
+func (x *ListLeasesResponse) Reset() {
+	*x = ListLeasesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLeasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeasesResponse) ProtoMessage() {}
+
+func (x *ListLeasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeasesResponse.ProtoReflect.Descriptor instead.
+func (*ListLeasesResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListLeasesResponse) GetLeases() []*UTXOLease {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+type ReleaseLeaseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the UTXO to release the lease for.
+	Outpoint *OutPoint `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The owner of the lease that should be released.
+	LeaseOwner []byte `protobuf:"bytes,2,opt,name=lease_owner,json=leaseOwner,proto3" json:"lease_owner,omitempty"`
+}
+
+func (x *ReleaseLeaseRequest) Reset() {
+	*x = ReleaseLeaseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseLeaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLeaseRequest) ProtoMessage() {}
+
+func (x *ReleaseLeaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLeaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseLeaseRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReleaseLeaseRequest) GetOutpoint() *OutPoint {
+	if x != nil {
+		return x.Outpoint
+	}
+	return nil
+}
+
+func (x *ReleaseLeaseRequest) GetLeaseOwner() []byte {
+	if x != nil {
+		return x.LeaseOwner
+	}
+	return nil
+}
+
+type ReleaseLeaseResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReleaseLeaseResponse) Reset() {
+	*x = ReleaseLeaseResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReleaseLeaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseLeaseResponse) ProtoMessage() {}
+
+func (x *ReleaseLeaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseLeaseResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseLeaseResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{30}
+}
+
+type KeyFamilyIndex struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key family.
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The highest key index used in the key family.
+	KeyIndex uint32 `protobuf:"varint,2,opt,name=key_index,json=keyIndex,proto3" json:"key_index,omitempty"`
+}
+
+func (x *KeyFamilyIndex) Reset() {
+	*x = KeyFamilyIndex{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyFamilyIndex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyFamilyIndex) ProtoMessage() {}
+
+func (x *KeyFamilyIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyFamilyIndex.ProtoReflect.Descriptor instead.
+func (*KeyFamilyIndex) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *KeyFamilyIndex) GetKeyFamily() uint32 {
+	if x != nil {
+		return x.KeyFamily
+	}
+	return 0
+}
+
+func (x *KeyFamilyIndex) GetKeyIndex() uint32 {
+	if x != nil {
+		return x.KeyIndex
+	}
+	return 0
+}
+
+type SigningState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The internal keys derived by the wallet, used as the internal keys of
+	// anchor outputs and script keys.
+	InternalKeys []*taprpc.KeyDescriptor `protobuf:"bytes,1,rep,name=internal_keys,json=internalKeys,proto3" json:"internal_keys,omitempty"`
+	// The script keys derived by the wallet, including their internal key and
+	// tweak.
+	ScriptKeys []*taprpc.ScriptKey `protobuf:"bytes,2,rep,name=script_keys,json=scriptKeys,proto3" json:"script_keys,omitempty"`
+	// The highest key index used in each key family.
+	KeyIndexes []*KeyFamilyIndex `protobuf:"bytes,3,rep,name=key_indexes,json=keyIndexes,proto3" json:"key_indexes,omitempty"`
+}
+
+func (x *SigningState) Reset() {
+	*x = SigningState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SigningState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningState) ProtoMessage() {}
+
+func (x *SigningState) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningState.ProtoReflect.Descriptor instead.
+func (*SigningState) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SigningState) GetInternalKeys() []*taprpc.KeyDescriptor {
+	if x != nil {
+		return x.InternalKeys
+	}
+	return nil
+}
+
+func (x *SigningState) GetScriptKeys() []*taprpc.ScriptKey {
+	if x != nil {
+		return x.ScriptKeys
+	}
+	return nil
+}
+
+func (x *SigningState) GetKeyIndexes() []*KeyFamilyIndex {
+	if x != nil {
+		return x.KeyIndexes
+	}
+	return nil
+}
+
+type ExportSigningStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ExportSigningStateRequest) Reset() {
+	*x = ExportSigningStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportSigningStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSigningStateRequest) ProtoMessage() {}
+
+func (x *ExportSigningStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSigningStateRequest.ProtoReflect.Descriptor instead.
+func (*ExportSigningStateRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{33}
+}
+
+type ExportSigningStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The signing state of the wallet.
+	SigningState *SigningState `protobuf:"bytes,1,opt,name=signing_state,json=signingState,proto3" json:"signing_state,omitempty"`
+}
+
+func (x *ExportSigningStateResponse) Reset() {
+	*x = ExportSigningStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportSigningStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportSigningStateResponse) ProtoMessage() {}
+
+func (x *ExportSigningStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportSigningStateResponse.ProtoReflect.Descriptor instead.
+func (*ExportSigningStateResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ExportSigningStateResponse) GetSigningState() *SigningState {
+	if x != nil {
+		return x.SigningState
+	}
+	return nil
+}
+
+type ImportSigningStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The signing state to import, as returned by ExportSigningState.
+	SigningState *SigningState `protobuf:"bytes,1,opt,name=signing_state,json=signingState,proto3" json:"signing_state,omitempty"`
+}
+
+func (x *ImportSigningStateRequest) Reset() {
+	*x = ImportSigningStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportSigningStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSigningStateRequest) ProtoMessage() {}
+
+func (x *ImportSigningStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSigningStateRequest.ProtoReflect.Descriptor instead.
+func (*ImportSigningStateRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ImportSigningStateRequest) GetSigningState() *SigningState {
+	if x != nil {
+		return x.SigningState
+	}
+	return nil
+}
+
+type ImportSigningStateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of imported internal keys.
+	NumInternalKeys uint32 `protobuf:"varint,1,opt,name=num_internal_keys,json=numInternalKeys,proto3" json:"num_internal_keys,omitempty"`
+	// The number of imported script keys.
+	NumScriptKeys uint32 `protobuf:"varint,2,opt,name=num_script_keys,json=numScriptKeys,proto3" json:"num_script_keys,omitempty"`
+}
+
+func (x *ImportSigningStateResponse) Reset() {
+	*x = ImportSigningStateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportSigningStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSigningStateResponse) ProtoMessage() {}
+
+func (x *ImportSigningStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSigningStateResponse.ProtoReflect.Descriptor instead.
+func (*ImportSigningStateResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ImportSigningStateResponse) GetNumInternalKeys() uint32 {
+	if x != nil {
+		return x.NumInternalKeys
+	}
+	return 0
+}
+
+func (x *ImportSigningStateResponse) GetNumScriptKeys() uint32 {
+	if x != nil {
+		return x.NumScriptKeys
+	}
+	return 0
+}
+
+type KeyRange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key family the range belongs to.
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The first key index of the range.
+	FirstIndex uint32 `protobuf:"varint,2,opt,name=first_index,json=firstIndex,proto3" json:"first_index,omitempty"`
+	// The last key index of the range, inclusive.
+	LastIndex uint32 `protobuf:"varint,3,opt,name=last_index,json=lastIndex,proto3" json:"last_index,omitempty"`
+	// The purpose the range was reserved for.
+	Purpose string `protobuf:"bytes,4,opt,name=purpose,proto3" json:"purpose,omitempty"`
+	// The time the range was reserved as a Unix timestamp in seconds.
+	CreationTimeUnix int64 `protobuf:"varint,5,opt,name=creation_time_unix,json=creationTimeUnix,proto3" json:"creation_time_unix,omitempty"`
+}
+
+func (x *KeyRange) Reset() {
+	*x = KeyRange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRange) ProtoMessage() {}
 
-func (x *RemoveUTXOLeaseRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[16]
+func (x *KeyRange) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -928,41 +2234,146 @@ func (x *RemoveUTXOLeaseRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveUTXOLeaseRequest.ProtoReflect.Descriptor instead.
-func (*RemoveUTXOLeaseRequest) Descriptor() ([]byte, []int) {
-	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use KeyRange.ProtoReflect.Descriptor instead.
+func (*KeyRange) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *RemoveUTXOLeaseRequest) GetOutpoint() *OutPoint {
+func (x *KeyRange) GetKeyFamily() uint32 {
 	if x != nil {
-		return x.Outpoint
+		return x.KeyFamily
+	}
+	return 0
+}
+
+func (x *KeyRange) GetFirstIndex() uint32 {
+	if x != nil {
+		return x.FirstIndex
+	}
+	return 0
+}
+
+func (x *KeyRange) GetLastIndex() uint32 {
+	if x != nil {
+		return x.LastIndex
+	}
+	return 0
+}
+
+func (x *KeyRange) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+func (x *KeyRange) GetCreationTimeUnix() int64 {
+	if x != nil {
+		return x.CreationTimeUnix
+	}
+	return 0
+}
+
+type KeyFamilyUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key family.
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The lowest key index above all keys the daemon used or reserved in the
+	// family. The lnd node might already be past this index if other software
+	// derives keys of the same family.
+	NextIndex uint32 `protobuf:"varint,2,opt,name=next_index,json=nextIndex,proto3" json:"next_index,omitempty"`
+	// The number of keys of the family the daemon derived and stored.
+	UsedKeys uint32 `protobuf:"varint,3,opt,name=used_keys,json=usedKeys,proto3" json:"used_keys,omitempty"`
+	// The key ranges that were reserved in the family.
+	ReservedRanges []*KeyRange `protobuf:"bytes,4,rep,name=reserved_ranges,json=reservedRanges,proto3" json:"reserved_ranges,omitempty"`
+}
+
+func (x *KeyFamilyUsage) Reset() {
+	*x = KeyFamilyUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyFamilyUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyFamilyUsage) ProtoMessage() {}
+
+func (x *KeyFamilyUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyFamilyUsage.ProtoReflect.Descriptor instead.
+func (*KeyFamilyUsage) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *KeyFamilyUsage) GetKeyFamily() uint32 {
+	if x != nil {
+		return x.KeyFamily
+	}
+	return 0
+}
+
+func (x *KeyFamilyUsage) GetNextIndex() uint32 {
+	if x != nil {
+		return x.NextIndex
+	}
+	return 0
+}
+
+func (x *KeyFamilyUsage) GetUsedKeys() uint32 {
+	if x != nil {
+		return x.UsedKeys
+	}
+	return 0
+}
+
+func (x *KeyFamilyUsage) GetReservedRanges() []*KeyRange {
+	if x != nil {
+		return x.ReservedRanges
 	}
 	return nil
 }
 
-type RemoveUTXOLeaseResponse struct {
+type ListKeyRangesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *RemoveUTXOLeaseResponse) Reset() {
-	*x = RemoveUTXOLeaseResponse{}
+func (x *ListKeyRangesRequest) Reset() {
+	*x = ListKeyRangesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[17]
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *RemoveUTXOLeaseResponse) String() string {
+func (x *ListKeyRangesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RemoveUTXOLeaseResponse) ProtoMessage() {}
+func (*ListKeyRangesRequest) ProtoMessage() {}
 
-func (x *RemoveUTXOLeaseResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[17]
+func (x *ListKeyRangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -973,9 +2384,172 @@ func (x *RemoveUTXOLeaseResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RemoveUTXOLeaseResponse.ProtoReflect.Descriptor instead.
-func (*RemoveUTXOLeaseResponse) Descriptor() ([]byte, []int) {
-	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ListKeyRangesRequest.ProtoReflect.Descriptor instead.
+func (*ListKeyRangesRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{39}
+}
+
+type ListKeyRangesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The usage of each key family, ordered by key family.
+	KeyFamilies []*KeyFamilyUsage `protobuf:"bytes,1,rep,name=key_families,json=keyFamilies,proto3" json:"key_families,omitempty"`
+}
+
+func (x *ListKeyRangesResponse) Reset() {
+	*x = ListKeyRangesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKeyRangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeyRangesResponse) ProtoMessage() {}
+
+func (x *ListKeyRangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeyRangesResponse.ProtoReflect.Descriptor instead.
+func (*ListKeyRangesResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListKeyRangesResponse) GetKeyFamilies() []*KeyFamilyUsage {
+	if x != nil {
+		return x.KeyFamilies
+	}
+	return nil
+}
+
+type ReserveKeyRangeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key family to reserve the keys of.
+	KeyFamily uint32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The number of keys to reserve.
+	NumKeys uint32 `protobuf:"varint,2,opt,name=num_keys,json=numKeys,proto3" json:"num_keys,omitempty"`
+	// A description of what the keys are reserved for.
+	Purpose string `protobuf:"bytes,3,opt,name=purpose,proto3" json:"purpose,omitempty"`
+}
+
+func (x *ReserveKeyRangeRequest) Reset() {
+	*x = ReserveKeyRangeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveKeyRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveKeyRangeRequest) ProtoMessage() {}
+
+func (x *ReserveKeyRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveKeyRangeRequest.ProtoReflect.Descriptor instead.
+func (*ReserveKeyRangeRequest) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ReserveKeyRangeRequest) GetKeyFamily() uint32 {
+	if x != nil {
+		return x.KeyFamily
+	}
+	return 0
+}
+
+func (x *ReserveKeyRangeRequest) GetNumKeys() uint32 {
+	if x != nil {
+		return x.NumKeys
+	}
+	return 0
+}
+
+func (x *ReserveKeyRangeRequest) GetPurpose() string {
+	if x != nil {
+		return x.Purpose
+	}
+	return ""
+}
+
+type ReserveKeyRangeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The reserved key range. The range can contain keys that were derived
+	// concurrently by other software using the same lnd node.
+	KeyRange *KeyRange `protobuf:"bytes,1,opt,name=key_range,json=keyRange,proto3" json:"key_range,omitempty"`
+}
+
+func (x *ReserveKeyRangeResponse) Reset() {
+	*x = ReserveKeyRangeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReserveKeyRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveKeyRangeResponse) ProtoMessage() {}
+
+func (x *ReserveKeyRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_assetwalletrpc_assetwallet_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveKeyRangeResponse.ProtoReflect.Descriptor instead.
+func (*ReserveKeyRangeResponse) Descriptor() ([]byte, []int) {
+	return file_assetwalletrpc_assetwallet_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ReserveKeyRangeResponse) GetKeyRange() *KeyRange {
+	if x != nil {
+		return x.KeyRange
+	}
+	return nil
 }
 
 var File_assetwalletrpc_assetwallet_proto protoreflect.FileDescriptor
@@ -985,90 +2559,186 @@ var file_assetwalletrpc_assetwallet_proto_rawDesc = []byte{
 	0x2f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x0e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
 	0x70, 0x63, 0x1a, 0x13, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x6a, 0x0a, 0x16, 0x46, 0x75, 0x6e, 0x64, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x14, 0x0a, 0x04, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48,
-	0x00, 0x52, 0x04, 0x70, 0x73, 0x62, 0x74, 0x12, 0x2e, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
-	0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65,
-	0x48, 0x00, 0x52, 0x03, 0x72, 0x61, 0x77, 0x42, 0x0a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c,
-	0x61, 0x74, 0x65, 0x22, 0x6a, 0x0a, 0x17, 0x46, 0x75, 0x6e, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75,
-	0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f,
-	0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x50, 0x73, 0x62, 0x74, 0x12,
-	0x2e, 0x0a, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x63, 0x68,
-	0x61, 0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22,
-	0xc7, 0x01, 0x0a, 0x0a, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x2e,
-	0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
-	0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e,
-	0x50, 0x72, 0x65, 0x76, 0x49, 0x64, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x4a,
-	0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x2e, 0x52,
-	0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a,
-	0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x52, 0x65,
-	0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x6d, 0x0a, 0x06, 0x50, 0x72, 0x65,
-	0x76, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52,
-	0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x41, 0x0a, 0x08, 0x4f, 0x75, 0x74, 0x50,
-	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x04, 0x74, 0x78, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70,
-	0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
-	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x39, 0x0a, 0x16, 0x53,
-	0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x5f,
-	0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x75, 0x6e, 0x64,
-	0x65, 0x64, 0x50, 0x73, 0x62, 0x74, 0x22, 0x5f, 0x0a, 0x17, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x69,
-	0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x50, 0x73,
-	0x62, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x69, 0x6e, 0x70,
-	0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x65,
-	0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x22, 0x40, 0x0a, 0x19, 0x41, 0x6e, 0x63, 0x68, 0x6f,
-	0x72, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f,
-	0x70, 0x73, 0x62, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x69, 0x72,
-	0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x22, 0x37, 0x0a, 0x16, 0x4e, 0x65, 0x78,
-	0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69,
-	0x6c, 0x79, 0x22, 0x53, 0x0a, 0x17, 0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e,
-	0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a,
-	0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79,
-	0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x22, 0x35, 0x0a, 0x14, 0x4e, 0x65, 0x78, 0x74, 0x53,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x22, 0x49,
-	0x0a, 0x15, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x09,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x56, 0x0a, 0x1a, 0x50, 0x72, 0x6f,
-	0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65,
-	0x79, 0x22, 0x4b, 0x0a, 0x1b, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f,
-	0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x77,
-	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x57, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x22, 0x4b,
-	0x0a, 0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e,
-	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a,
-	0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x77, 0x69, 0x74, 0x6e,
-	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6f, 0x66,
-	0x57, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x22, 0x3f, 0x0a, 0x1c, 0x56,
+	0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcd, 0x01, 0x0a, 0x16, 0x46, 0x75, 0x6e, 0x64,
+	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x04, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x00, 0x52, 0x04, 0x70, 0x73, 0x62, 0x74, 0x12, 0x2e, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x61, 0x77, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x72, 0x61, 0x6e,
+	0x63, 0x68, 0x65, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x50, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x74, 0x72, 0x61, 0x6e, 0x63,
+	0x68, 0x65, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x13, 0x74, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x65, 0x47,
+	0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x0a, 0x0a, 0x08, 0x74,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0x6a, 0x0a, 0x17, 0x46, 0x75, 0x6e, 0x64, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x50,
+	0x73, 0x62, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x11, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x22, 0xc7, 0x01, 0x0a, 0x0a, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
+	0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x49, 0x64, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75,
+	0x74, 0x73, 0x12, 0x4a, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61,
+	0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x78, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x2e, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x1a, 0x3d,
+	0x0a, 0x0f, 0x52, 0x65, 0x63, 0x69, 0x70, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x6d, 0x0a,
+	0x06, 0x50, 0x72, 0x65, 0x76, 0x49, 0x64, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75, 0x74, 0x50, 0x6f,
+	0x69, 0x6e, 0x74, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x41, 0x0a, 0x08,
+	0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x78, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x74, 0x78, 0x69, 0x64, 0x12, 0x21, 0x0a, 0x0c,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22,
+	0x39, 0x0a, 0x16, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73,
+	0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e,
+	0x64, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x50, 0x73, 0x62, 0x74, 0x22, 0x5f, 0x0a, 0x17, 0x53, 0x69,
+	0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f,
+	0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x64, 0x50, 0x73, 0x62, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64,
+	0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0c, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x22, 0xe0, 0x01, 0x0a, 0x19,
+	0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x70, 0x65,
+	0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x75, 0x6e,
+	0x64, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x66, 0x75, 0x6e, 0x64, 0x65, 0x64, 0x50, 0x73, 0x62, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
+	0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x6c,
+	0x65, 0x61, 0x66, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6c, 0x65, 0x61, 0x66, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x6c, 0x65, 0x61, 0x66, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x66, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x23, 0x0a, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x22, 0x41,
+	0x0a, 0x1a, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53,
+	0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d,
+	0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x64, 0x50, 0x73, 0x62,
+	0x74, 0x22, 0x40, 0x0a, 0x19, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23,
+	0x0a, 0x0d, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73,
+	0x62, 0x74, 0x73, 0x22, 0x5b, 0x0a, 0x19, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x73, 0x62, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x66, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65,
+	0x22, 0xec, 0x01, 0x0a, 0x1a, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x50, 0x73, 0x62, 0x74,
+	0x12, 0x23, 0x0a, 0x0d, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x73, 0x62, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65,
+	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0c, 0x52, 0x11, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x11, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x28, 0x0a, 0x10, 0x6c, 0x6e, 0x64, 0x5f, 0x6c, 0x6f, 0x63,
+	0x6b, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x6c, 0x6e, 0x64, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x22,
+	0xfc, 0x01, 0x0a, 0x14, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x6e, 0x64, 0x4c, 0x6f,
+	0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x50, 0x73, 0x62, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x76, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x5f, 0x70, 0x73, 0x62, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c,
+	0x52, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x2e,
+	0x0a, 0x13, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
+	0x70, 0x73, 0x62, 0x74, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x11, 0x70, 0x61, 0x73,
+	0x73, 0x69, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x2e,
+	0x0a, 0x13, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x28,
+	0x0a, 0x10, 0x6c, 0x6e, 0x64, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x78,
+	0x6f, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6c, 0x6e, 0x64, 0x4c, 0x6f, 0x63,
+	0x6b, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x37,
+	0x0a, 0x16, 0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65,
+	0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x22, 0x53, 0x0a, 0x17, 0x4e, 0x65, 0x78, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x52,
+	0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x22, 0x35, 0x0a, 0x14,
+	0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x22, 0x49, 0x0a, 0x15, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x0a,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x4b, 0x65, 0x79, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x53,
+	0x0a, 0x17, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x4b, 0x65, 0x79, 0x22, 0x6a, 0x0a, 0x0d, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x73, 0x63,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x52, 0x07, 0x6b,
+	0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x65, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x64, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x61, 0x74, 0x68, 0x22,
+	0xab, 0x02, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x15,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x72, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79,
+	0x44, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x13, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x2f, 0x0a, 0x14, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x61,
+	0x70, 0x5f, 0x74, 0x77, 0x65, 0x61, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x11, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x61, 0x70, 0x54, 0x77, 0x65, 0x61, 0x6b,
+	0x12, 0x62, 0x0a, 0x1e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x74, 0x0a,
+	0x1a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65,
+	0x6e, 0x67, 0x65, 0x22, 0x4b, 0x0a, 0x1b, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x77, 0x69, 0x74, 0x68,
+	0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x57, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73,
+	0x22, 0x69, 0x0a, 0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f,
+	0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x77, 0x69,
+	0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x57, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x22, 0x3f, 0x0a, 0x1c, 0x56,
 	0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73,
 	0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x76,
 	0x61, 0x6c, 0x69, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
@@ -1079,63 +2749,233 @@ var file_assetwalletrpc_assetwallet_proto_rawDesc = []byte{
 	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69,
 	0x6e, 0x74, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x19, 0x0a, 0x17,
 	0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xba, 0x06, 0x0a, 0x0b, 0x41, 0x73, 0x73, 0x65,
-	0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x62, 0x0a, 0x0f, 0x46, 0x75, 0x6e, 0x64, 0x56,
-	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73,
-	0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x75, 0x6e, 0x64,
-	0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x46, 0x75, 0x6e, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50,
-	0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x53,
-	0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x12, 0x26,
-	0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e,
-	0x53, 0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61,
-	0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74,
-	0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x5a, 0x0a, 0x12, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x4c,
+	0x65, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xd7, 0x01, 0x0a,
+	0x09, 0x55, 0x54, 0x58, 0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x75,
+	0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75,
+	0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0a, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12,
+	0x35, 0x0a, 0x17, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x22, 0x47, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65,
+	0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x06,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x54,
+	0x58, 0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x22,
+	0x6c, 0x0a, 0x13, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x34, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75, 0x74, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0a, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x22, 0x16, 0x0a,
+	0x14, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4c, 0x0a, 0x0e, 0x4b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66,
+	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65, 0x79,
+	0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x22, 0xbf, 0x01, 0x0a, 0x0c, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
+	0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x52, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x73,
+	0x12, 0x32, 0x0a, 0x0b, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x4b, 0x65, 0x79, 0x73, 0x12, 0x3f, 0x0a, 0x0b, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x46, 0x61,
+	0x6d, 0x69, 0x6c, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x52, 0x0a, 0x6b, 0x65, 0x79, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x65, 0x73, 0x22, 0x1b, 0x0a, 0x19, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x5f, 0x0a, 0x1a, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x41, 0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x22, 0x5e, 0x0a, 0x19, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x41, 0x0a, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x0c, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x22, 0x70, 0x0a, 0x1a, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x75, 0x6d, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x6e, 0x75,
+	0x6d, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x26, 0x0a,
+	0x0f, 0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x6e, 0x75, 0x6d, 0x53, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x4b, 0x65, 0x79, 0x73, 0x22, 0xb1, 0x01, 0x0a, 0x08, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74, 0x49, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69,
+	0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x22, 0xae, 0x01, 0x0a, 0x0e, 0x4b, 0x65,
+	0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x6e,
+	0x65, 0x78, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x6e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1b, 0x0a, 0x09, 0x75, 0x73,
+	0x65, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x75,
+	0x73, 0x65, 0x64, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x41, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x18, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70,
+	0x63, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x64, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x5a, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0c, 0x6b,
+	0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1e, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x0b, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x69, 0x65, 0x73, 0x22, 0x6c,
+	0x0a, 0x16, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f,
+	0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6b, 0x65,
+	0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x75, 0x6d, 0x5f, 0x6b,
+	0x65, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x4b, 0x65,
+	0x79, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x75, 0x72, 0x70, 0x6f, 0x73, 0x65, 0x22, 0x50, 0x0a, 0x17,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x32, 0xa1,
+	0x0e, 0x0a, 0x0b, 0x41, 0x73, 0x73, 0x65, 0x74, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x62,
+	0x0a, 0x0f, 0x46, 0x75, 0x6e, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62,
+	0x74, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x46, 0x75, 0x6e, 0x64, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73,
+	0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x75, 0x6e, 0x64, 0x56,
+	0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61,
+	0x6c, 0x50, 0x73, 0x62, 0x74, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75,
+	0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x69, 0x67, 0x6e, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6b, 0x0a, 0x12, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72,
+	0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x12, 0x29, 0x2e, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72,
+	0x65, 0x70, 0x61, 0x72, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x70, 0x65, 0x6e, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65,
+	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x70, 0x65, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x12, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x56, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x6b, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c,
 	0x50, 0x73, 0x62, 0x74, 0x73, 0x12, 0x29, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x56, 0x69, 0x72,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x56, 0x69, 0x72,
 	0x74, 0x75, 0x61, 0x6c, 0x50, 0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73,
-	0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x4e,
-	0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x26,
-	0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e,
-	0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61,
-	0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x5c, 0x0a, 0x0d, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79,
-	0x12, 0x24, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70,
-	0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61,
-	0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e, 0x0a,
-	0x13, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x73, 0x68, 0x69, 0x70, 0x12, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
-	0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74,
-	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x2b, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70,
-	0x63, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x71, 0x0a,
-	0x14, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65,
-	0x72, 0x73, 0x68, 0x69, 0x70, 0x12, 0x2b, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73,
+	0x1a, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x56, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x50,
+	0x73, 0x62, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x15,
+	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x6e, 0x64, 0x4c, 0x6f, 0x67, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x24, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x41, 0x6e,
+	0x64, 0x4c, 0x6f, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x0d, 0x4e, 0x65,
+	0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x2e, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78,
+	0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x25, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4b,
+	0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x2e, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x72,
+	0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x6e, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e,
+	0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x12, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61,
+	0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73,
 	0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f,
-	0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x62, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f, 0x4c, 0x65,
-	0x61, 0x73, 0x65, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65,
-	0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f, 0x4c,
-	0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
-	0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61, 0x62, 0x73,
-	0x2f, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2f,
-	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
-	0x65, 0x74, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
+	0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77,
+	0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x71, 0x0a, 0x14, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4f, 0x77,
+	0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x12, 0x2b, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x62, 0x0a, 0x0f, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f,
+	0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58,
+	0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x55, 0x54, 0x58, 0x4f, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65,
+	0x61, 0x73, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61,
+	0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x0c, 0x52,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x23, 0x2e, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x24, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70,
+	0x63, 0x2e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6b, 0x0a, 0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x2e, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x53,
+	0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x6b, 0x0a, 0x12, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x29, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x5c, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x73, 0x12, 0x24, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x62,
+	0x0a, 0x0f, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x26, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x4b, 0x65, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x3f, 0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x74,
+	0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2f, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74,
+	0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1150,7 +2990,7 @@ func file_assetwalletrpc_assetwallet_proto_rawDescGZIP() []byte {
 	return file_assetwalletrpc_assetwallet_proto_rawDescData
 }
 
-var file_assetwalletrpc_assetwallet_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_assetwalletrpc_assetwallet_proto_msgTypes = make([]protoimpl.MessageInfo, 44)
 var file_assetwalletrpc_assetwallet_proto_goTypes = []interface{}{
 	(*FundVirtualPsbtRequest)(nil),       // 0: assetwalletrpc.FundVirtualPsbtRequest
 	(*FundVirtualPsbtResponse)(nil),      // 1: assetwalletrpc.FundVirtualPsbtResponse
@@ -1159,51 +2999,110 @@ var file_assetwalletrpc_assetwallet_proto_goTypes = []interface{}{
 	(*OutPoint)(nil),                     // 4: assetwalletrpc.OutPoint
 	(*SignVirtualPsbtRequest)(nil),       // 5: assetwalletrpc.SignVirtualPsbtRequest
 	(*SignVirtualPsbtResponse)(nil),      // 6: assetwalletrpc.SignVirtualPsbtResponse
-	(*AnchorVirtualPsbtsRequest)(nil),    // 7: assetwalletrpc.AnchorVirtualPsbtsRequest
-	(*NextInternalKeyRequest)(nil),       // 8: assetwalletrpc.NextInternalKeyRequest
-	(*NextInternalKeyResponse)(nil),      // 9: assetwalletrpc.NextInternalKeyResponse
-	(*NextScriptKeyRequest)(nil),         // 10: assetwalletrpc.NextScriptKeyRequest
-	(*NextScriptKeyResponse)(nil),        // 11: assetwalletrpc.NextScriptKeyResponse
-	(*ProveAssetOwnershipRequest)(nil),   // 12: assetwalletrpc.ProveAssetOwnershipRequest
-	(*ProveAssetOwnershipResponse)(nil),  // 13: assetwalletrpc.ProveAssetOwnershipResponse
-	(*VerifyAssetOwnershipRequest)(nil),  // 14: assetwalletrpc.VerifyAssetOwnershipRequest
-	(*VerifyAssetOwnershipResponse)(nil), // 15: assetwalletrpc.VerifyAssetOwnershipResponse
-	(*RemoveUTXOLeaseRequest)(nil),       // 16: assetwalletrpc.RemoveUTXOLeaseRequest
-	(*RemoveUTXOLeaseResponse)(nil),      // 17: assetwalletrpc.RemoveUTXOLeaseResponse
-	nil,                                  // 18: assetwalletrpc.TxTemplate.RecipientsEntry
-	(*taprpc.KeyDescriptor)(nil),         // 19: taprpc.KeyDescriptor
-	(*taprpc.ScriptKey)(nil),             // 20: taprpc.ScriptKey
-	(*taprpc.SendAssetResponse)(nil),     // 21: taprpc.SendAssetResponse
+	(*PrepareScriptSpendRequest)(nil),    // 7: assetwalletrpc.PrepareScriptSpendRequest
+	(*PrepareScriptSpendResponse)(nil),   // 8: assetwalletrpc.PrepareScriptSpendResponse
+	(*AnchorVirtualPsbtsRequest)(nil),    // 9: assetwalletrpc.AnchorVirtualPsbtsRequest
+	(*CommitVirtualPsbtsRequest)(nil),    // 10: assetwalletrpc.CommitVirtualPsbtsRequest
+	(*CommitVirtualPsbtsResponse)(nil),   // 11: assetwalletrpc.CommitVirtualPsbtsResponse
+	(*PublishAndLogRequest)(nil),         // 12: assetwalletrpc.PublishAndLogRequest
+	(*NextInternalKeyRequest)(nil),       // 13: assetwalletrpc.NextInternalKeyRequest
+	(*NextInternalKeyResponse)(nil),      // 14: assetwalletrpc.NextInternalKeyResponse
+	(*NextScriptKeyRequest)(nil),         // 15: assetwalletrpc.NextScriptKeyRequest
+	(*NextScriptKeyResponse)(nil),        // 16: assetwalletrpc.NextScriptKeyResponse
+	(*GetKeyDerivationRequest)(nil),      // 17: assetwalletrpc.GetKeyDerivationRequest
+	(*KeyDerivation)(nil),                // 18: assetwalletrpc.KeyDerivation
+	(*GetKeyDerivationResponse)(nil),     // 19: assetwalletrpc.GetKeyDerivationResponse
+	(*ProveAssetOwnershipRequest)(nil),   // 20: assetwalletrpc.ProveAssetOwnershipRequest
+	(*ProveAssetOwnershipResponse)(nil),  // 21: assetwalletrpc.ProveAssetOwnershipResponse
+	(*VerifyAssetOwnershipRequest)(nil),  // 22: assetwalletrpc.VerifyAssetOwnershipRequest
+	(*VerifyAssetOwnershipResponse)(nil), // 23: assetwalletrpc.VerifyAssetOwnershipResponse
+	(*RemoveUTXOLeaseRequest)(nil),       // 24: assetwalletrpc.RemoveUTXOLeaseRequest
+	(*RemoveUTXOLeaseResponse)(nil),      // 25: assetwalletrpc.RemoveUTXOLeaseResponse
+	(*ListLeasesRequest)(nil),            // 26: assetwalletrpc.ListLeasesRequest
+	(*UTXOLease)(nil),                    // 27: assetwalletrpc.UTXOLease
+	(*ListLeasesResponse)(nil),           // 28: assetwalletrpc.ListLeasesResponse
+	(*ReleaseLeaseRequest)(nil),          // 29: assetwalletrpc.ReleaseLeaseRequest
+	(*ReleaseLeaseResponse)(nil),         // 30: assetwalletrpc.ReleaseLeaseResponse
+	(*KeyFamilyIndex)(nil),               // 31: assetwalletrpc.KeyFamilyIndex
+	(*SigningState)(nil),                 // 32: assetwalletrpc.SigningState
+	(*ExportSigningStateRequest)(nil),    // 33: assetwalletrpc.ExportSigningStateRequest
+	(*ExportSigningStateResponse)(nil),   // 34: assetwalletrpc.ExportSigningStateResponse
+	(*ImportSigningStateRequest)(nil),    // 35: assetwalletrpc.ImportSigningStateRequest
+	(*ImportSigningStateResponse)(nil),   // 36: assetwalletrpc.ImportSigningStateResponse
+	(*KeyRange)(nil),                     // 37: assetwalletrpc.KeyRange
+	(*KeyFamilyUsage)(nil),               // 38: assetwalletrpc.KeyFamilyUsage
+	(*ListKeyRangesRequest)(nil),         // 39: assetwalletrpc.ListKeyRangesRequest
+	(*ListKeyRangesResponse)(nil),        // 40: assetwalletrpc.ListKeyRangesResponse
+	(*ReserveKeyRangeRequest)(nil),       // 41: assetwalletrpc.ReserveKeyRangeRequest
+	(*ReserveKeyRangeResponse)(nil),      // 42: assetwalletrpc.ReserveKeyRangeResponse
+	nil,                                  // 43: assetwalletrpc.TxTemplate.RecipientsEntry
+	(*taprpc.KeyDescriptor)(nil),         // 44: taprpc.KeyDescriptor
+	(*taprpc.ScriptKey)(nil),             // 45: taprpc.ScriptKey
+	(*taprpc.SendAssetResponse)(nil),     // 46: taprpc.SendAssetResponse
 }
 var file_assetwalletrpc_assetwallet_proto_depIdxs = []int32{
 	2,  // 0: assetwalletrpc.FundVirtualPsbtRequest.raw:type_name -> assetwalletrpc.TxTemplate
 	3,  // 1: assetwalletrpc.TxTemplate.inputs:type_name -> assetwalletrpc.PrevId
-	18, // 2: assetwalletrpc.TxTemplate.recipients:type_name -> assetwalletrpc.TxTemplate.RecipientsEntry
+	43, // 2: assetwalletrpc.TxTemplate.recipients:type_name -> assetwalletrpc.TxTemplate.RecipientsEntry
 	4,  // 3: assetwalletrpc.PrevId.outpoint:type_name -> assetwalletrpc.OutPoint
-	19, // 4: assetwalletrpc.NextInternalKeyResponse.internal_key:type_name -> taprpc.KeyDescriptor
-	20, // 5: assetwalletrpc.NextScriptKeyResponse.script_key:type_name -> taprpc.ScriptKey
-	4,  // 6: assetwalletrpc.RemoveUTXOLeaseRequest.outpoint:type_name -> assetwalletrpc.OutPoint
-	0,  // 7: assetwalletrpc.AssetWallet.FundVirtualPsbt:input_type -> assetwalletrpc.FundVirtualPsbtRequest
-	5,  // 8: assetwalletrpc.AssetWallet.SignVirtualPsbt:input_type -> assetwalletrpc.SignVirtualPsbtRequest
-	7,  // 9: assetwalletrpc.AssetWallet.AnchorVirtualPsbts:input_type -> assetwalletrpc.AnchorVirtualPsbtsRequest
-	8,  // 10: assetwalletrpc.AssetWallet.NextInternalKey:input_type -> assetwalletrpc.NextInternalKeyRequest
-	10, // 11: assetwalletrpc.AssetWallet.NextScriptKey:input_type -> assetwalletrpc.NextScriptKeyRequest
-	12, // 12: assetwalletrpc.AssetWallet.ProveAssetOwnership:input_type -> assetwalletrpc.ProveAssetOwnershipRequest
-	14, // 13: assetwalletrpc.AssetWallet.VerifyAssetOwnership:input_type -> assetwalletrpc.VerifyAssetOwnershipRequest
-	16, // 14: assetwalletrpc.AssetWallet.RemoveUTXOLease:input_type -> assetwalletrpc.RemoveUTXOLeaseRequest
-	1,  // 15: assetwalletrpc.AssetWallet.FundVirtualPsbt:output_type -> assetwalletrpc.FundVirtualPsbtResponse
-	6,  // 16: assetwalletrpc.AssetWallet.SignVirtualPsbt:output_type -> assetwalletrpc.SignVirtualPsbtResponse
-	21, // 17: assetwalletrpc.AssetWallet.AnchorVirtualPsbts:output_type -> taprpc.SendAssetResponse
-	9,  // 18: assetwalletrpc.AssetWallet.NextInternalKey:output_type -> assetwalletrpc.NextInternalKeyResponse
-	11, // 19: assetwalletrpc.AssetWallet.NextScriptKey:output_type -> assetwalletrpc.NextScriptKeyResponse
-	13, // 20: assetwalletrpc.AssetWallet.ProveAssetOwnership:output_type -> assetwalletrpc.ProveAssetOwnershipResponse
-	15, // 21: assetwalletrpc.AssetWallet.VerifyAssetOwnership:output_type -> assetwalletrpc.VerifyAssetOwnershipResponse
-	17, // 22: assetwalletrpc.AssetWallet.RemoveUTXOLease:output_type -> assetwalletrpc.RemoveUTXOLeaseResponse
-	15, // [15:23] is the sub-list for method output_type
-	7,  // [7:15] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	44, // 4: assetwalletrpc.NextInternalKeyResponse.internal_key:type_name -> taprpc.KeyDescriptor
+	45, // 5: assetwalletrpc.NextScriptKeyResponse.script_key:type_name -> taprpc.ScriptKey
+	44, // 6: assetwalletrpc.KeyDerivation.key_desc:type_name -> taprpc.KeyDescriptor
+	18, // 7: assetwalletrpc.GetKeyDerivationResponse.script_key_derivation:type_name -> assetwalletrpc.KeyDerivation
+	18, // 8: assetwalletrpc.GetKeyDerivationResponse.anchor_internal_key_derivation:type_name -> assetwalletrpc.KeyDerivation
+	4,  // 9: assetwalletrpc.RemoveUTXOLeaseRequest.outpoint:type_name -> assetwalletrpc.OutPoint
+	4,  // 10: assetwalletrpc.UTXOLease.outpoint:type_name -> assetwalletrpc.OutPoint
+	27, // 11: assetwalletrpc.ListLeasesResponse.leases:type_name -> assetwalletrpc.UTXOLease
+	4,  // 12: assetwalletrpc.ReleaseLeaseRequest.outpoint:type_name -> assetwalletrpc.OutPoint
+	44, // 13: assetwalletrpc.SigningState.internal_keys:type_name -> taprpc.KeyDescriptor
+	45, // 14: assetwalletrpc.SigningState.script_keys:type_name -> taprpc.ScriptKey
+	31, // 15: assetwalletrpc.SigningState.key_indexes:type_name -> assetwalletrpc.KeyFamilyIndex
+	32, // 16: assetwalletrpc.ExportSigningStateResponse.signing_state:type_name -> assetwalletrpc.SigningState
+	32, // 17: assetwalletrpc.ImportSigningStateRequest.signing_state:type_name -> assetwalletrpc.SigningState
+	37, // 18: assetwalletrpc.KeyFamilyUsage.reserved_ranges:type_name -> assetwalletrpc.KeyRange
+	38, // 19: assetwalletrpc.ListKeyRangesResponse.key_families:type_name -> assetwalletrpc.KeyFamilyUsage
+	37, // 20: assetwalletrpc.ReserveKeyRangeResponse.key_range:type_name -> assetwalletrpc.KeyRange
+	0,  // 21: assetwalletrpc.AssetWallet.FundVirtualPsbt:input_type -> assetwalletrpc.FundVirtualPsbtRequest
+	5,  // 22: assetwalletrpc.AssetWallet.SignVirtualPsbt:input_type -> assetwalletrpc.SignVirtualPsbtRequest
+	7,  // 23: assetwalletrpc.AssetWallet.PrepareScriptSpend:input_type -> assetwalletrpc.PrepareScriptSpendRequest
+	9,  // 24: assetwalletrpc.AssetWallet.AnchorVirtualPsbts:input_type -> assetwalletrpc.AnchorVirtualPsbtsRequest
+	10, // 25: assetwalletrpc.AssetWallet.CommitVirtualPsbts:input_type -> assetwalletrpc.CommitVirtualPsbtsRequest
+	12, // 26: assetwalletrpc.AssetWallet.PublishAndLogTransfer:input_type -> assetwalletrpc.PublishAndLogRequest
+	13, // 27: assetwalletrpc.AssetWallet.NextInternalKey:input_type -> assetwalletrpc.NextInternalKeyRequest
+	15, // 28: assetwalletrpc.AssetWallet.NextScriptKey:input_type -> assetwalletrpc.NextScriptKeyRequest
+	17, // 29: assetwalletrpc.AssetWallet.GetKeyDerivation:input_type -> assetwalletrpc.GetKeyDerivationRequest
+	20, // 30: assetwalletrpc.AssetWallet.ProveAssetOwnership:input_type -> assetwalletrpc.ProveAssetOwnershipRequest
+	22, // 31: assetwalletrpc.AssetWallet.VerifyAssetOwnership:input_type -> assetwalletrpc.VerifyAssetOwnershipRequest
+	24, // 32: assetwalletrpc.AssetWallet.RemoveUTXOLease:input_type -> assetwalletrpc.RemoveUTXOLeaseRequest
+	26, // 33: assetwalletrpc.AssetWallet.ListLeases:input_type -> assetwalletrpc.ListLeasesRequest
+	29, // 34: assetwalletrpc.AssetWallet.ReleaseLease:input_type -> assetwalletrpc.ReleaseLeaseRequest
+	33, // 35: assetwalletrpc.AssetWallet.ExportSigningState:input_type -> assetwalletrpc.ExportSigningStateRequest
+	35, // 36: assetwalletrpc.AssetWallet.ImportSigningState:input_type -> assetwalletrpc.ImportSigningStateRequest
+	39, // 37: assetwalletrpc.AssetWallet.ListKeyRanges:input_type -> assetwalletrpc.ListKeyRangesRequest
+	41, // 38: assetwalletrpc.AssetWallet.ReserveKeyRange:input_type -> assetwalletrpc.ReserveKeyRangeRequest
+	1,  // 39: assetwalletrpc.AssetWallet.FundVirtualPsbt:output_type -> assetwalletrpc.FundVirtualPsbtResponse
+	6,  // 40: assetwalletrpc.AssetWallet.SignVirtualPsbt:output_type -> assetwalletrpc.SignVirtualPsbtResponse
+	8,  // 41: assetwalletrpc.AssetWallet.PrepareScriptSpend:output_type -> assetwalletrpc.PrepareScriptSpendResponse
+	46, // 42: assetwalletrpc.AssetWallet.AnchorVirtualPsbts:output_type -> taprpc.SendAssetResponse
+	11, // 43: assetwalletrpc.AssetWallet.CommitVirtualPsbts:output_type -> assetwalletrpc.CommitVirtualPsbtsResponse
+	46, // 44: assetwalletrpc.AssetWallet.PublishAndLogTransfer:output_type -> taprpc.SendAssetResponse
+	14, // 45: assetwalletrpc.AssetWallet.NextInternalKey:output_type -> assetwalletrpc.NextInternalKeyResponse
+	16, // 46: assetwalletrpc.AssetWallet.NextScriptKey:output_type -> assetwalletrpc.NextScriptKeyResponse
+	19, // 47: assetwalletrpc.AssetWallet.GetKeyDerivation:output_type -> assetwalletrpc.GetKeyDerivationResponse
+	21, // 48: assetwalletrpc.AssetWallet.ProveAssetOwnership:output_type -> assetwalletrpc.ProveAssetOwnershipResponse
+	23, // 49: assetwalletrpc.AssetWallet.VerifyAssetOwnership:output_type -> assetwalletrpc.VerifyAssetOwnershipResponse
+	25, // 50: assetwalletrpc.AssetWallet.RemoveUTXOLease:output_type -> assetwalletrpc.RemoveUTXOLeaseResponse
+	28, // 51: assetwalletrpc.AssetWallet.ListLeases:output_type -> assetwalletrpc.ListLeasesResponse
+	30, // 52: assetwalletrpc.AssetWallet.ReleaseLease:output_type -> assetwalletrpc.ReleaseLeaseResponse
+	34, // 53: assetwalletrpc.AssetWallet.ExportSigningState:output_type -> assetwalletrpc.ExportSigningStateResponse
+	36, // 54: assetwalletrpc.AssetWallet.ImportSigningState:output_type -> assetwalletrpc.ImportSigningStateResponse
+	40, // 55: assetwalletrpc.AssetWallet.ListKeyRanges:output_type -> assetwalletrpc.ListKeyRangesResponse
+	42, // 56: assetwalletrpc.AssetWallet.ReserveKeyRange:output_type -> assetwalletrpc.ReserveKeyRangeResponse
+	39, // [39:57] is the sub-list for method output_type
+	21, // [21:39] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_assetwalletrpc_assetwallet_proto_init() }
@@ -1224,8 +3123,56 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FundVirtualPsbtResponse); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FundVirtualPsbtResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TxTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrevId); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignVirtualPsbtRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1236,8 +3183,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TxTemplate); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignVirtualPsbtResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1248,8 +3195,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrevId); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareScriptSpendRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1260,8 +3207,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OutPoint); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareScriptSpendResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1272,8 +3219,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SignVirtualPsbtRequest); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnchorVirtualPsbtsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1284,8 +3231,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SignVirtualPsbtResponse); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitVirtualPsbtsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1296,8 +3243,8 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AnchorVirtualPsbtsRequest); i {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitVirtualPsbtsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1308,7 +3255,19 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PublishAndLogRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NextInternalKeyRequest); i {
 			case 0:
 				return &v.state
@@ -1320,7 +3279,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NextInternalKeyResponse); i {
 			case 0:
 				return &v.state
@@ -1332,7 +3291,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NextScriptKeyRequest); i {
 			case 0:
 				return &v.state
@@ -1344,7 +3303,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NextScriptKeyResponse); i {
 			case 0:
 				return &v.state
@@ -1356,7 +3315,43 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetKeyDerivationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyDerivation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetKeyDerivationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProveAssetOwnershipRequest); i {
 			case 0:
 				return &v.state
@@ -1368,7 +3363,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProveAssetOwnershipResponse); i {
 			case 0:
 				return &v.state
@@ -1380,7 +3375,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VerifyAssetOwnershipRequest); i {
 			case 0:
 				return &v.state
@@ -1392,7 +3387,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*VerifyAssetOwnershipResponse); i {
 			case 0:
 				return &v.state
@@ -1404,7 +3399,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveUTXOLeaseRequest); i {
 			case 0:
 				return &v.state
@@ -1416,7 +3411,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
-		file_assetwalletrpc_assetwallet_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+		file_assetwalletrpc_assetwallet_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*RemoveUTXOLeaseResponse); i {
 			case 0:
 				return &v.state
@@ -1428,6 +3423,210 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 				return nil
 			}
 		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLeasesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UTXOLease); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLeasesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReleaseLeaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReleaseLeaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyFamilyIndex); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SigningState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportSigningStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportSigningStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportSigningStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportSigningStateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyRange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyFamilyUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeyRangesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeyRangesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveKeyRangeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_assetwalletrpc_assetwallet_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReserveKeyRangeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_assetwalletrpc_assetwallet_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*FundVirtualPsbtRequest_Psbt)(nil),
@@ -1439,7 +3638,7 @@ func file_assetwalletrpc_assetwallet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_assetwalletrpc_assetwallet_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   19,
+			NumMessages:   44,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
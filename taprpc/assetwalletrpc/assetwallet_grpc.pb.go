@@ -25,12 +25,35 @@ type AssetWalletClient interface {
 	// SignVirtualPsbt signs the inputs of a virtual transaction and prepares the
 	// commitments of the inputs and outputs.
 	SignVirtualPsbt(ctx context.Context, in *SignVirtualPsbtRequest, opts ...grpc.CallOption) (*SignVirtualPsbtResponse, error)
+	// PrepareScriptSpend prepares an input of a funded virtual transaction to be
+	// spent through the script path of any leaf of the tapscript tree its script
+	// key commits to. If a witness is given, it becomes the final witness of the
+	// input and SignVirtualPsbt doesn't sign the input. Otherwise SignVirtualPsbt
+	// signs the input with the internal key of its script key.
+	PrepareScriptSpend(ctx context.Context, in *PrepareScriptSpendRequest, opts ...grpc.CallOption) (*PrepareScriptSpendResponse, error)
 	// AnchorVirtualPsbts merges and then commits multiple virtual transactions in
 	// a single BTC level anchor transaction.
 	//
 	// TODO(guggero): Actually implement accepting and merging multiple
 	// transactions.
 	AnchorVirtualPsbts(ctx context.Context, in *AnchorVirtualPsbtsRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error)
+	// CommitVirtualPsbts creates the BTC level anchor transaction that commits to
+	// the given signed virtual transaction and funds it with the wallet, but
+	// doesn't sign it. This is the first step of sending assets with an anchor
+	// transaction that is signed externally, for example by a hardware wallet or
+	// the participants of a multisig setup. The asset inputs stay leased for the
+	// 10 minutes FundVirtualPsbt leased them for, the BTC inputs the wallet funds
+	// the anchor transaction with are leased by lnd for 10 minutes as well. The
+	// signed anchor transaction must be handed to PublishAndLogTransfer before
+	// these leases expire.
+	CommitVirtualPsbts(ctx context.Context, in *CommitVirtualPsbtsRequest, opts ...grpc.CallOption) (*CommitVirtualPsbtsResponse, error)
+	// PublishAndLogTransfer completes a transfer with an anchor transaction that
+	// was created by CommitVirtualPsbts and signed externally. The anchor
+	// transaction is verified to commit to the virtual transactions, then the
+	// transfer is logged, which leases its asset inputs until the anchor
+	// transaction confirms, and the anchor transaction is broadcast and the
+	// proofs are delivered like for any other transfer.
+	PublishAndLogTransfer(ctx context.Context, in *PublishAndLogRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error)
 	// NextInternalKey derives the next internal key for the given key family and
 	// stores it as an internal key in the database to make sure it is identified
 	// as a local key later on when importing proofs. While an internal key can
@@ -42,17 +65,55 @@ type AssetWalletClient interface {
 	// key) and stores them both in the database to make sure they are identified
 	// as local keys later on when importing proofs.
 	NextScriptKey(ctx context.Context, in *NextScriptKeyRequest, opts ...grpc.CallOption) (*NextScriptKeyResponse, error)
+	// GetKeyDerivation returns the key derivation information of the script key
+	// and the anchor internal key of an owned asset. Besides the key family and
+	// index, the full BIP-0032 derivation path of each key is returned as it is
+	// used by lnd, which allows external signers to locate the keys.
+	GetKeyDerivation(ctx context.Context, in *GetKeyDerivationRequest, opts ...grpc.CallOption) (*GetKeyDerivationResponse, error)
 	// ProveAssetOwnership creates an ownership proof embedded in an asset
 	// transition proof. That ownership proof is a signed virtual transaction
 	// spending the asset with a valid witness to prove the prover owns the keys
-	// that can spend the asset.
+	// that can spend the asset. If a challenge is given, the witness commits to
+	// it, so the proof can only be verified with the same challenge.
 	ProveAssetOwnership(ctx context.Context, in *ProveAssetOwnershipRequest, opts ...grpc.CallOption) (*ProveAssetOwnershipResponse, error)
 	// VerifyAssetOwnership verifies the asset ownership proof embedded in the
 	// given transition proof of an asset and returns true if the proof is valid.
+	// If a challenge is given, the proof must have been created for exactly that
+	// challenge.
 	VerifyAssetOwnership(ctx context.Context, in *VerifyAssetOwnershipRequest, opts ...grpc.CallOption) (*VerifyAssetOwnershipResponse, error)
 	// RemoveUTXOLease removes the lease/lock/reservation of the given managed
 	// UTXO.
 	RemoveUTXOLease(ctx context.Context, in *RemoveUTXOLeaseRequest, opts ...grpc.CallOption) (*RemoveUTXOLeaseResponse, error)
+	// ListLeases lists the leases/locks/reservations of managed asset anchor
+	// UTXOs that haven't expired yet. This includes the UTXOs that are locked by
+	// pending transfers.
+	ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error)
+	// ReleaseLease releases the lease/lock/reservation of the given managed
+	// asset anchor UTXO, but only if it is held by the given lease owner. This
+	// can be used to release stale leases manually, expired leases are released
+	// automatically.
+	ReleaseLease(ctx context.Context, in *ReleaseLeaseRequest, opts ...grpc.CallOption) (*ReleaseLeaseResponse, error)
+	// ExportSigningState exports the inventory of all internal and script keys
+	// the wallet derived, along with the highest key index used in each key
+	// family. Together with the proofs of its assets, this allows a node that was
+	// rebuilt with the same lnd seed to recognize and spend the assets it
+	// previously owned.
+	ExportSigningState(ctx context.Context, in *ExportSigningStateRequest, opts ...grpc.CallOption) (*ExportSigningStateResponse, error)
+	// ImportSigningState imports a signing state that was exported by a node with
+	// the same lnd seed. All keys must be derivable by the connected lnd node.
+	// The key derivation of lnd is advanced past the highest imported key index of
+	// each key family, so previously used keys are never derived again. The import
+	// is rejected if a key index is more than 100000 keys ahead of the key index
+	// of lnd, as lnd can only be advanced by deriving one key after another.
+	ImportSigningState(ctx context.Context, in *ImportSigningStateRequest, opts ...grpc.CallOption) (*ImportSigningStateResponse, error)
+	// ListKeyRanges lists the key families the daemon derived keys in or reserved
+	// key ranges of, together with their usage. This makes the key allocation of
+	// the daemon observable when the same lnd node is used by other software.
+	ListKeyRanges(ctx context.Context, in *ListKeyRangesRequest, opts ...grpc.CallOption) (*ListKeyRangesResponse, error)
+	// ReserveKeyRange reserves a range of keys of a key family for a purpose
+	// outside of the daemon by deriving them from the connected lnd node, which
+	// then never hands them out again.
+	ReserveKeyRange(ctx context.Context, in *ReserveKeyRangeRequest, opts ...grpc.CallOption) (*ReserveKeyRangeResponse, error)
 }
 
 type assetWalletClient struct {
@@ -81,6 +142,15 @@ func (c *assetWalletClient) SignVirtualPsbt(ctx context.Context, in *SignVirtual
 	return out, nil
 }
 
+func (c *assetWalletClient) PrepareScriptSpend(ctx context.Context, in *PrepareScriptSpendRequest, opts ...grpc.CallOption) (*PrepareScriptSpendResponse, error) {
+	out := new(PrepareScriptSpendResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/PrepareScriptSpend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *assetWalletClient) AnchorVirtualPsbts(ctx context.Context, in *AnchorVirtualPsbtsRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error) {
 	out := new(taprpc.SendAssetResponse)
 	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/AnchorVirtualPsbts", in, out, opts...)
@@ -90,6 +160,24 @@ func (c *assetWalletClient) AnchorVirtualPsbts(ctx context.Context, in *AnchorVi
 	return out, nil
 }
 
+func (c *assetWalletClient) CommitVirtualPsbts(ctx context.Context, in *CommitVirtualPsbtsRequest, opts ...grpc.CallOption) (*CommitVirtualPsbtsResponse, error) {
+	out := new(CommitVirtualPsbtsResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/CommitVirtualPsbts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) PublishAndLogTransfer(ctx context.Context, in *PublishAndLogRequest, opts ...grpc.CallOption) (*taprpc.SendAssetResponse, error) {
+	out := new(taprpc.SendAssetResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/PublishAndLogTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *assetWalletClient) NextInternalKey(ctx context.Context, in *NextInternalKeyRequest, opts ...grpc.CallOption) (*NextInternalKeyResponse, error) {
 	out := new(NextInternalKeyResponse)
 	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/NextInternalKey", in, out, opts...)
@@ -108,6 +196,15 @@ func (c *assetWalletClient) NextScriptKey(ctx context.Context, in *NextScriptKey
 	return out, nil
 }
 
+func (c *assetWalletClient) GetKeyDerivation(ctx context.Context, in *GetKeyDerivationRequest, opts ...grpc.CallOption) (*GetKeyDerivationResponse, error) {
+	out := new(GetKeyDerivationResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/GetKeyDerivation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *assetWalletClient) ProveAssetOwnership(ctx context.Context, in *ProveAssetOwnershipRequest, opts ...grpc.CallOption) (*ProveAssetOwnershipResponse, error) {
 	out := new(ProveAssetOwnershipResponse)
 	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ProveAssetOwnership", in, out, opts...)
@@ -135,6 +232,60 @@ func (c *assetWalletClient) RemoveUTXOLease(ctx context.Context, in *RemoveUTXOL
 	return out, nil
 }
 
+func (c *assetWalletClient) ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error) {
+	out := new(ListLeasesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ListLeases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ReleaseLease(ctx context.Context, in *ReleaseLeaseRequest, opts ...grpc.CallOption) (*ReleaseLeaseResponse, error) {
+	out := new(ReleaseLeaseResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ReleaseLease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ExportSigningState(ctx context.Context, in *ExportSigningStateRequest, opts ...grpc.CallOption) (*ExportSigningStateResponse, error) {
+	out := new(ExportSigningStateResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ExportSigningState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ImportSigningState(ctx context.Context, in *ImportSigningStateRequest, opts ...grpc.CallOption) (*ImportSigningStateResponse, error) {
+	out := new(ImportSigningStateResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ImportSigningState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ListKeyRanges(ctx context.Context, in *ListKeyRangesRequest, opts ...grpc.CallOption) (*ListKeyRangesResponse, error) {
+	out := new(ListKeyRangesResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ListKeyRanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetWalletClient) ReserveKeyRange(ctx context.Context, in *ReserveKeyRangeRequest, opts ...grpc.CallOption) (*ReserveKeyRangeResponse, error) {
+	out := new(ReserveKeyRangeResponse)
+	err := c.cc.Invoke(ctx, "/assetwalletrpc.AssetWallet/ReserveKeyRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AssetWalletServer is the server API for AssetWallet service.
 // All implementations must embed UnimplementedAssetWalletServer
 // for forward compatibility
@@ -145,12 +296,35 @@ type AssetWalletServer interface {
 	// SignVirtualPsbt signs the inputs of a virtual transaction and prepares the
 	// commitments of the inputs and outputs.
 	SignVirtualPsbt(context.Context, *SignVirtualPsbtRequest) (*SignVirtualPsbtResponse, error)
+	// PrepareScriptSpend prepares an input of a funded virtual transaction to be
+	// spent through the script path of any leaf of the tapscript tree its script
+	// key commits to. If a witness is given, it becomes the final witness of the
+	// input and SignVirtualPsbt doesn't sign the input. Otherwise SignVirtualPsbt
+	// signs the input with the internal key of its script key.
+	PrepareScriptSpend(context.Context, *PrepareScriptSpendRequest) (*PrepareScriptSpendResponse, error)
 	// AnchorVirtualPsbts merges and then commits multiple virtual transactions in
 	// a single BTC level anchor transaction.
 	//
 	// TODO(guggero): Actually implement accepting and merging multiple
 	// transactions.
 	AnchorVirtualPsbts(context.Context, *AnchorVirtualPsbtsRequest) (*taprpc.SendAssetResponse, error)
+	// CommitVirtualPsbts creates the BTC level anchor transaction that commits to
+	// the given signed virtual transaction and funds it with the wallet, but
+	// doesn't sign it. This is the first step of sending assets with an anchor
+	// transaction that is signed externally, for example by a hardware wallet or
+	// the participants of a multisig setup. The asset inputs stay leased for the
+	// 10 minutes FundVirtualPsbt leased them for, the BTC inputs the wallet funds
+	// the anchor transaction with are leased by lnd for 10 minutes as well. The
+	// signed anchor transaction must be handed to PublishAndLogTransfer before
+	// these leases expire.
+	CommitVirtualPsbts(context.Context, *CommitVirtualPsbtsRequest) (*CommitVirtualPsbtsResponse, error)
+	// PublishAndLogTransfer completes a transfer with an anchor transaction that
+	// was created by CommitVirtualPsbts and signed externally. The anchor
+	// transaction is verified to commit to the virtual transactions, then the
+	// transfer is logged, which leases its asset inputs until the anchor
+	// transaction confirms, and the anchor transaction is broadcast and the
+	// proofs are delivered like for any other transfer.
+	PublishAndLogTransfer(context.Context, *PublishAndLogRequest) (*taprpc.SendAssetResponse, error)
 	// NextInternalKey derives the next internal key for the given key family and
 	// stores it as an internal key in the database to make sure it is identified
 	// as a local key later on when importing proofs. While an internal key can
@@ -162,17 +336,55 @@ type AssetWalletServer interface {
 	// key) and stores them both in the database to make sure they are identified
 	// as local keys later on when importing proofs.
 	NextScriptKey(context.Context, *NextScriptKeyRequest) (*NextScriptKeyResponse, error)
+	// GetKeyDerivation returns the key derivation information of the script key
+	// and the anchor internal key of an owned asset. Besides the key family and
+	// index, the full BIP-0032 derivation path of each key is returned as it is
+	// used by lnd, which allows external signers to locate the keys.
+	GetKeyDerivation(context.Context, *GetKeyDerivationRequest) (*GetKeyDerivationResponse, error)
 	// ProveAssetOwnership creates an ownership proof embedded in an asset
 	// transition proof. That ownership proof is a signed virtual transaction
 	// spending the asset with a valid witness to prove the prover owns the keys
-	// that can spend the asset.
+	// that can spend the asset. If a challenge is given, the witness commits to
+	// it, so the proof can only be verified with the same challenge.
 	ProveAssetOwnership(context.Context, *ProveAssetOwnershipRequest) (*ProveAssetOwnershipResponse, error)
 	// VerifyAssetOwnership verifies the asset ownership proof embedded in the
 	// given transition proof of an asset and returns true if the proof is valid.
+	// If a challenge is given, the proof must have been created for exactly that
+	// challenge.
 	VerifyAssetOwnership(context.Context, *VerifyAssetOwnershipRequest) (*VerifyAssetOwnershipResponse, error)
 	// RemoveUTXOLease removes the lease/lock/reservation of the given managed
 	// UTXO.
 	RemoveUTXOLease(context.Context, *RemoveUTXOLeaseRequest) (*RemoveUTXOLeaseResponse, error)
+	// ListLeases lists the leases/locks/reservations of managed asset anchor
+	// UTXOs that haven't expired yet. This includes the UTXOs that are locked by
+	// pending transfers.
+	ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error)
+	// ReleaseLease releases the lease/lock/reservation of the given managed
+	// asset anchor UTXO, but only if it is held by the given lease owner. This
+	// can be used to release stale leases manually, expired leases are released
+	// automatically.
+	ReleaseLease(context.Context, *ReleaseLeaseRequest) (*ReleaseLeaseResponse, error)
+	// ExportSigningState exports the inventory of all internal and script keys
+	// the wallet derived, along with the highest key index used in each key
+	// family. Together with the proofs of its assets, this allows a node that was
+	// rebuilt with the same lnd seed to recognize and spend the assets it
+	// previously owned.
+	ExportSigningState(context.Context, *ExportSigningStateRequest) (*ExportSigningStateResponse, error)
+	// ImportSigningState imports a signing state that was exported by a node with
+	// the same lnd seed. All keys must be derivable by the connected lnd node.
+	// The key derivation of lnd is advanced past the highest imported key index of
+	// each key family, so previously used keys are never derived again. The import
+	// is rejected if a key index is more than 100000 keys ahead of the key index
+	// of lnd, as lnd can only be advanced by deriving one key after another.
+	ImportSigningState(context.Context, *ImportSigningStateRequest) (*ImportSigningStateResponse, error)
+	// ListKeyRanges lists the key families the daemon derived keys in or reserved
+	// key ranges of, together with their usage. This makes the key allocation of
+	// the daemon observable when the same lnd node is used by other software.
+	ListKeyRanges(context.Context, *ListKeyRangesRequest) (*ListKeyRangesResponse, error)
+	// ReserveKeyRange reserves a range of keys of a key family for a purpose
+	// outside of the daemon by deriving them from the connected lnd node, which
+	// then never hands them out again.
+	ReserveKeyRange(context.Context, *ReserveKeyRangeRequest) (*ReserveKeyRangeResponse, error)
 	mustEmbedUnimplementedAssetWalletServer()
 }
 
@@ -186,15 +398,27 @@ func (UnimplementedAssetWalletServer) FundVirtualPsbt(context.Context, *FundVirt
 func (UnimplementedAssetWalletServer) SignVirtualPsbt(context.Context, *SignVirtualPsbtRequest) (*SignVirtualPsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignVirtualPsbt not implemented")
 }
+func (UnimplementedAssetWalletServer) PrepareScriptSpend(context.Context, *PrepareScriptSpendRequest) (*PrepareScriptSpendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareScriptSpend not implemented")
+}
 func (UnimplementedAssetWalletServer) AnchorVirtualPsbts(context.Context, *AnchorVirtualPsbtsRequest) (*taprpc.SendAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AnchorVirtualPsbts not implemented")
 }
+func (UnimplementedAssetWalletServer) CommitVirtualPsbts(context.Context, *CommitVirtualPsbtsRequest) (*CommitVirtualPsbtsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitVirtualPsbts not implemented")
+}
+func (UnimplementedAssetWalletServer) PublishAndLogTransfer(context.Context, *PublishAndLogRequest) (*taprpc.SendAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishAndLogTransfer not implemented")
+}
 func (UnimplementedAssetWalletServer) NextInternalKey(context.Context, *NextInternalKeyRequest) (*NextInternalKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NextInternalKey not implemented")
 }
 func (UnimplementedAssetWalletServer) NextScriptKey(context.Context, *NextScriptKeyRequest) (*NextScriptKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NextScriptKey not implemented")
 }
+func (UnimplementedAssetWalletServer) GetKeyDerivation(context.Context, *GetKeyDerivationRequest) (*GetKeyDerivationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyDerivation not implemented")
+}
 func (UnimplementedAssetWalletServer) ProveAssetOwnership(context.Context, *ProveAssetOwnershipRequest) (*ProveAssetOwnershipResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProveAssetOwnership not implemented")
 }
@@ -204,6 +428,24 @@ func (UnimplementedAssetWalletServer) VerifyAssetOwnership(context.Context, *Ver
 func (UnimplementedAssetWalletServer) RemoveUTXOLease(context.Context, *RemoveUTXOLeaseRequest) (*RemoveUTXOLeaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveUTXOLease not implemented")
 }
+func (UnimplementedAssetWalletServer) ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeases not implemented")
+}
+func (UnimplementedAssetWalletServer) ReleaseLease(context.Context, *ReleaseLeaseRequest) (*ReleaseLeaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLease not implemented")
+}
+func (UnimplementedAssetWalletServer) ExportSigningState(context.Context, *ExportSigningStateRequest) (*ExportSigningStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSigningState not implemented")
+}
+func (UnimplementedAssetWalletServer) ImportSigningState(context.Context, *ImportSigningStateRequest) (*ImportSigningStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportSigningState not implemented")
+}
+func (UnimplementedAssetWalletServer) ListKeyRanges(context.Context, *ListKeyRangesRequest) (*ListKeyRangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeyRanges not implemented")
+}
+func (UnimplementedAssetWalletServer) ReserveKeyRange(context.Context, *ReserveKeyRangeRequest) (*ReserveKeyRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveKeyRange not implemented")
+}
 func (UnimplementedAssetWalletServer) mustEmbedUnimplementedAssetWalletServer() {}
 
 // UnsafeAssetWalletServer may be embedded to opt out of forward compatibility for this service.
@@ -253,6 +495,24 @@ func _AssetWallet_SignVirtualPsbt_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetWallet_PrepareScriptSpend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareScriptSpendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).PrepareScriptSpend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/PrepareScriptSpend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).PrepareScriptSpend(ctx, req.(*PrepareScriptSpendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AssetWallet_AnchorVirtualPsbts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AnchorVirtualPsbtsRequest)
 	if err := dec(in); err != nil {
@@ -271,6 +531,42 @@ func _AssetWallet_AnchorVirtualPsbts_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetWallet_CommitVirtualPsbts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitVirtualPsbtsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).CommitVirtualPsbts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/CommitVirtualPsbts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).CommitVirtualPsbts(ctx, req.(*CommitVirtualPsbtsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_PublishAndLogTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishAndLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).PublishAndLogTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/PublishAndLogTransfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).PublishAndLogTransfer(ctx, req.(*PublishAndLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AssetWallet_NextInternalKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NextInternalKeyRequest)
 	if err := dec(in); err != nil {
@@ -307,6 +603,24 @@ func _AssetWallet_NextScriptKey_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetWallet_GetKeyDerivation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyDerivationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).GetKeyDerivation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/GetKeyDerivation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).GetKeyDerivation(ctx, req.(*GetKeyDerivationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AssetWallet_ProveAssetOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProveAssetOwnershipRequest)
 	if err := dec(in); err != nil {
@@ -361,6 +675,114 @@ func _AssetWallet_RemoveUTXOLease_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetWallet_ListLeases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLeasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ListLeases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ListLeases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ListLeases(ctx, req.(*ListLeasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ReleaseLease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseLeaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ReleaseLease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ReleaseLease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ReleaseLease(ctx, req.(*ReleaseLeaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ExportSigningState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSigningStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ExportSigningState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ExportSigningState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ExportSigningState(ctx, req.(*ExportSigningStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ImportSigningState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSigningStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ImportSigningState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ImportSigningState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ImportSigningState(ctx, req.(*ImportSigningStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ListKeyRanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeyRangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ListKeyRanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ListKeyRanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ListKeyRanges(ctx, req.(*ListKeyRangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetWallet_ReserveKeyRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveKeyRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetWalletServer).ReserveKeyRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/assetwalletrpc.AssetWallet/ReserveKeyRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetWalletServer).ReserveKeyRange(ctx, req.(*ReserveKeyRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AssetWallet_ServiceDesc is the grpc.ServiceDesc for AssetWallet service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -376,10 +798,22 @@ var AssetWallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignVirtualPsbt",
 			Handler:    _AssetWallet_SignVirtualPsbt_Handler,
 		},
+		{
+			MethodName: "PrepareScriptSpend",
+			Handler:    _AssetWallet_PrepareScriptSpend_Handler,
+		},
 		{
 			MethodName: "AnchorVirtualPsbts",
 			Handler:    _AssetWallet_AnchorVirtualPsbts_Handler,
 		},
+		{
+			MethodName: "CommitVirtualPsbts",
+			Handler:    _AssetWallet_CommitVirtualPsbts_Handler,
+		},
+		{
+			MethodName: "PublishAndLogTransfer",
+			Handler:    _AssetWallet_PublishAndLogTransfer_Handler,
+		},
 		{
 			MethodName: "NextInternalKey",
 			Handler:    _AssetWallet_NextInternalKey_Handler,
@@ -388,6 +822,10 @@ var AssetWallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NextScriptKey",
 			Handler:    _AssetWallet_NextScriptKey_Handler,
 		},
+		{
+			MethodName: "GetKeyDerivation",
+			Handler:    _AssetWallet_GetKeyDerivation_Handler,
+		},
 		{
 			MethodName: "ProveAssetOwnership",
 			Handler:    _AssetWallet_ProveAssetOwnership_Handler,
@@ -400,6 +838,30 @@ var AssetWallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveUTXOLease",
 			Handler:    _AssetWallet_RemoveUTXOLease_Handler,
 		},
+		{
+			MethodName: "ListLeases",
+			Handler:    _AssetWallet_ListLeases_Handler,
+		},
+		{
+			MethodName: "ReleaseLease",
+			Handler:    _AssetWallet_ReleaseLease_Handler,
+		},
+		{
+			MethodName: "ExportSigningState",
+			Handler:    _AssetWallet_ExportSigningState_Handler,
+		},
+		{
+			MethodName: "ImportSigningState",
+			Handler:    _AssetWallet_ImportSigningState_Handler,
+		},
+		{
+			MethodName: "ListKeyRanges",
+			Handler:    _AssetWallet_ListKeyRanges_Handler,
+		},
+		{
+			MethodName: "ReserveKeyRange",
+			Handler:    _AssetWallet_ReserveKeyRange_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "assetwalletrpc/assetwallet.proto",
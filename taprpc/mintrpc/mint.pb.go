@@ -110,6 +110,16 @@ type MintAsset struct {
 	GroupAnchor string `protobuf:"bytes,6,opt,name=group_anchor,json=groupAnchor,proto3" json:"group_anchor,omitempty"`
 	// The version of asset to mint.
 	AssetVersion taprpc.AssetVersion `protobuf:"varint,7,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// The optional tapscript tree the script key of the minted asset should commit
+	// to. The asset can then be spent through one of the tree's leaves, in
+	// addition to the key spend path of the script key's internal key. If not
+	// set, a BIP-86 script key is used.
+	ScriptKeyTapscript *taprpc.ScriptKeyTapscript `protobuf:"bytes,8,opt,name=script_key_tapscript,json=scriptKeyTapscript,proto3" json:"script_key_tapscript,omitempty"`
+	// The ID of a previously issued grouped asset. If set, the new asset is
+	// minted into the existing group of that asset, which requires this node to
+	// control the group's issuance key. Cannot be combined with group_key or
+	// group_anchor.
+	GroupAssetId []byte `protobuf:"bytes,9,opt,name=group_asset_id,json=groupAssetId,proto3" json:"group_asset_id,omitempty"`
 }
 
 func (x *MintAsset) Reset() {
@@ -193,6 +203,20 @@ func (x *MintAsset) GetAssetVersion() taprpc.AssetVersion {
 	return taprpc.AssetVersion(0)
 }
 
+func (x *MintAsset) GetScriptKeyTapscript() *taprpc.ScriptKeyTapscript {
+	if x != nil {
+		return x.ScriptKeyTapscript
+	}
+	return nil
+}
+
+func (x *MintAsset) GetGroupAssetId() []byte {
+	if x != nil {
+		return x.GroupAssetId
+	}
+	return nil
+}
+
 type MintAssetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -323,6 +347,13 @@ type MintingBatch struct {
 	Assets []*MintAsset `protobuf:"bytes,2,rep,name=assets,proto3" json:"assets,omitempty"`
 	// The state of the batch.
 	State BatchState `protobuf:"varint,3,opt,name=state,proto3,enum=mintrpc.BatchState" json:"state,omitempty"`
+	// The transaction ID of the genesis transaction that mints the assets of the
+	// batch. This is only set once the batch was committed and its genesis
+	// transaction was funded.
+	BatchTxid string `protobuf:"bytes,4,opt,name=batch_txid,json=batchTxid,proto3" json:"batch_txid,omitempty"`
+	// The schedule according to which the batch is finalized automatically. This
+	// is only set for the pending batch, if its finalization was scheduled.
+	FinalizeSchedule *BatchFinalizeSchedule `protobuf:"bytes,5,opt,name=finalize_schedule,json=finalizeSchedule,proto3" json:"finalize_schedule,omitempty"`
 }
 
 func (x *MintingBatch) Reset() {
@@ -378,6 +409,79 @@ func (x *MintingBatch) GetState() BatchState {
 	return BatchState_BATCH_STATE_UNKNOWN
 }
 
+func (x *MintingBatch) GetBatchTxid() string {
+	if x != nil {
+		return x.BatchTxid
+	}
+	return ""
+}
+
+func (x *MintingBatch) GetFinalizeSchedule() *BatchFinalizeSchedule {
+	if x != nil {
+		return x.FinalizeSchedule
+	}
+	return nil
+}
+
+type BatchFinalizeSchedule struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unix timestamp in seconds at which the batch is finalized. If zero, the
+	// batch isn't finalized based on time.
+	FinalizeTime int64 `protobuf:"varint,1,opt,name=finalize_time,json=finalizeTime,proto3" json:"finalize_time,omitempty"`
+	// The number of seedlings at which the batch is finalized. If zero, the batch
+	// isn't finalized based on its number of seedlings.
+	MinSeedlings uint32 `protobuf:"varint,2,opt,name=min_seedlings,json=minSeedlings,proto3" json:"min_seedlings,omitempty"`
+}
+
+func (x *BatchFinalizeSchedule) Reset() {
+	*x = BatchFinalizeSchedule{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mintrpc_mint_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchFinalizeSchedule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFinalizeSchedule) ProtoMessage() {}
+
+func (x *BatchFinalizeSchedule) ProtoReflect() protoreflect.Message {
+	mi := &file_mintrpc_mint_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFinalizeSchedule.ProtoReflect.Descriptor instead.
+func (*BatchFinalizeSchedule) Descriptor() ([]byte, []int) {
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchFinalizeSchedule) GetFinalizeTime() int64 {
+	if x != nil {
+		return x.FinalizeTime
+	}
+	return 0
+}
+
+func (x *BatchFinalizeSchedule) GetMinSeedlings() uint32 {
+	if x != nil {
+		return x.MinSeedlings
+	}
+	return 0
+}
+
 type FinalizeBatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -387,12 +491,20 @@ type FinalizeBatchRequest struct {
 	// response. This is mainly to avoid a lot of data being transmitted and
 	// possibly printed on the command line in the case of a very large batch.
 	ShortResponse bool `protobuf:"varint,1,opt,name=short_response,json=shortResponse,proto3" json:"short_response,omitempty"`
+	// The optional unix timestamp in seconds at which the pending batch should
+	// be finalized automatically. The schedule is persisted with the batch, so it
+	// is resumed if the daemon restarts before the batch is finalized.
+	FinalizeTime int64 `protobuf:"varint,2,opt,name=finalize_time,json=finalizeTime,proto3" json:"finalize_time,omitempty"`
+	// The optional number of seedlings at which the pending batch should be
+	// finalized automatically. The schedule is persisted with the batch, so it is
+	// resumed if the daemon restarts before the batch is finalized.
+	MinSeedlings uint32 `protobuf:"varint,3,opt,name=min_seedlings,json=minSeedlings,proto3" json:"min_seedlings,omitempty"`
 }
 
 func (x *FinalizeBatchRequest) Reset() {
 	*x = FinalizeBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[4]
+		mi := &file_mintrpc_mint_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -405,7 +517,7 @@ func (x *FinalizeBatchRequest) String() string {
 func (*FinalizeBatchRequest) ProtoMessage() {}
 
 func (x *FinalizeBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[4]
+	mi := &file_mintrpc_mint_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -418,7 +530,7 @@ func (x *FinalizeBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FinalizeBatchRequest.ProtoReflect.Descriptor instead.
 func (*FinalizeBatchRequest) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{4}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *FinalizeBatchRequest) GetShortResponse() bool {
@@ -428,6 +540,20 @@ func (x *FinalizeBatchRequest) GetShortResponse() bool {
 	return false
 }
 
+func (x *FinalizeBatchRequest) GetFinalizeTime() int64 {
+	if x != nil {
+		return x.FinalizeTime
+	}
+	return 0
+}
+
+func (x *FinalizeBatchRequest) GetMinSeedlings() uint32 {
+	if x != nil {
+		return x.MinSeedlings
+	}
+	return 0
+}
+
 type FinalizeBatchResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -440,7 +566,7 @@ type FinalizeBatchResponse struct {
 func (x *FinalizeBatchResponse) Reset() {
 	*x = FinalizeBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[5]
+		mi := &file_mintrpc_mint_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -453,7 +579,7 @@ func (x *FinalizeBatchResponse) String() string {
 func (*FinalizeBatchResponse) ProtoMessage() {}
 
 func (x *FinalizeBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[5]
+	mi := &file_mintrpc_mint_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +592,7 @@ func (x *FinalizeBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FinalizeBatchResponse.ProtoReflect.Descriptor instead.
 func (*FinalizeBatchResponse) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{5}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *FinalizeBatchResponse) GetBatch() *MintingBatch {
@@ -485,7 +611,7 @@ type CancelBatchRequest struct {
 func (x *CancelBatchRequest) Reset() {
 	*x = CancelBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[6]
+		mi := &file_mintrpc_mint_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -498,7 +624,7 @@ func (x *CancelBatchRequest) String() string {
 func (*CancelBatchRequest) ProtoMessage() {}
 
 func (x *CancelBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[6]
+	mi := &file_mintrpc_mint_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +637,7 @@ func (x *CancelBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelBatchRequest.ProtoReflect.Descriptor instead.
 func (*CancelBatchRequest) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{6}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{7}
 }
 
 type CancelBatchResponse struct {
@@ -521,12 +647,14 @@ type CancelBatchResponse struct {
 
 	// The internal public key of the batch.
 	BatchKey []byte `protobuf:"bytes,1,opt,name=batch_key,json=batchKey,proto3" json:"batch_key,omitempty"`
+	// The number of asset seedlings that were discarded with the batch.
+	NumSeedlings uint32 `protobuf:"varint,2,opt,name=num_seedlings,json=numSeedlings,proto3" json:"num_seedlings,omitempty"`
 }
 
 func (x *CancelBatchResponse) Reset() {
 	*x = CancelBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[7]
+		mi := &file_mintrpc_mint_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -539,7 +667,7 @@ func (x *CancelBatchResponse) String() string {
 func (*CancelBatchResponse) ProtoMessage() {}
 
 func (x *CancelBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[7]
+	mi := &file_mintrpc_mint_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -552,7 +680,7 @@ func (x *CancelBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CancelBatchResponse.ProtoReflect.Descriptor instead.
 func (*CancelBatchResponse) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{7}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CancelBatchResponse) GetBatchKey() []byte {
@@ -562,6 +690,13 @@ func (x *CancelBatchResponse) GetBatchKey() []byte {
 	return nil
 }
 
+func (x *CancelBatchResponse) GetNumSeedlings() uint32 {
+	if x != nil {
+		return x.NumSeedlings
+	}
+	return 0
+}
+
 type ListBatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -579,7 +714,7 @@ type ListBatchRequest struct {
 func (x *ListBatchRequest) Reset() {
 	*x = ListBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[8]
+		mi := &file_mintrpc_mint_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -592,7 +727,7 @@ func (x *ListBatchRequest) String() string {
 func (*ListBatchRequest) ProtoMessage() {}
 
 func (x *ListBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[8]
+	mi := &file_mintrpc_mint_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -605,7 +740,7 @@ func (x *ListBatchRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBatchRequest.ProtoReflect.Descriptor instead.
 func (*ListBatchRequest) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{8}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{9}
 }
 
 func (m *ListBatchRequest) GetFilter() isListBatchRequest_Filter {
@@ -660,7 +795,7 @@ type ListBatchResponse struct {
 func (x *ListBatchResponse) Reset() {
 	*x = ListBatchResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_mintrpc_mint_proto_msgTypes[9]
+		mi := &file_mintrpc_mint_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -673,7 +808,7 @@ func (x *ListBatchResponse) String() string {
 func (*ListBatchResponse) ProtoMessage() {}
 
 func (x *ListBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mintrpc_mint_proto_msgTypes[9]
+	mi := &file_mintrpc_mint_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -686,7 +821,7 @@ func (x *ListBatchResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListBatchResponse.ProtoReflect.Descriptor instead.
 func (*ListBatchResponse) Descriptor() ([]byte, []int) {
-	return file_mintrpc_mint_proto_rawDescGZIP(), []int{9}
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListBatchResponse) GetBatches() []*MintingBatch {
@@ -696,13 +831,157 @@ func (x *ListBatchResponse) GetBatches() []*MintingBatch {
 	return nil
 }
 
+type RotateGroupKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The tweaked group key of the asset group to rotate the issuance
+	// authority of.
+	GroupKey []byte `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	// The optional fee rate to use for the rotation transaction, in sat/kw. If
+	// not set, the fee rate is estimated.
+	SatPerKw uint32 `protobuf:"varint,2,opt,name=sat_per_kw,json=satPerKw,proto3" json:"sat_per_kw,omitempty"`
+}
+
+func (x *RotateGroupKeyRequest) Reset() {
+	*x = RotateGroupKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mintrpc_mint_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateGroupKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateGroupKeyRequest) ProtoMessage() {}
+
+func (x *RotateGroupKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mintrpc_mint_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateGroupKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateGroupKeyRequest) Descriptor() ([]byte, []int) {
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RotateGroupKeyRequest) GetGroupKey() []byte {
+	if x != nil {
+		return x.GroupKey
+	}
+	return nil
+}
+
+func (x *RotateGroupKeyRequest) GetSatPerKw() uint32 {
+	if x != nil {
+		return x.SatPerKw
+	}
+	return 0
+}
+
+type RotateGroupKeyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The position of the rotation in the rotation chain of the group,
+	// starting at zero.
+	Sequence uint32 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// The key that held the issuance authority before the rotation.
+	PrevKey []byte `protobuf:"bytes,2,opt,name=prev_key,json=prevKey,proto3" json:"prev_key,omitempty"`
+	// The key that holds the issuance authority after the rotation.
+	NewKey []byte `protobuf:"bytes,3,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+	// The outpoint of the OP_RETURN output that anchors the rotation.
+	AnchorOutpoint string `protobuf:"bytes,4,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The height of the block that confirmed the rotation.
+	BlockHeight uint32 `protobuf:"varint,5,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+}
+
+func (x *RotateGroupKeyResponse) Reset() {
+	*x = RotateGroupKeyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_mintrpc_mint_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RotateGroupKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateGroupKeyResponse) ProtoMessage() {}
+
+func (x *RotateGroupKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mintrpc_mint_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateGroupKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateGroupKeyResponse) Descriptor() ([]byte, []int) {
+	return file_mintrpc_mint_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RotateGroupKeyResponse) GetSequence() uint32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RotateGroupKeyResponse) GetPrevKey() []byte {
+	if x != nil {
+		return x.PrevKey
+	}
+	return nil
+}
+
+func (x *RotateGroupKeyResponse) GetNewKey() []byte {
+	if x != nil {
+		return x.NewKey
+	}
+	return nil
+}
+
+func (x *RotateGroupKeyResponse) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *RotateGroupKeyResponse) GetBlockHeight() uint32 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
 var File_mintrpc_mint_proto protoreflect.FileDescriptor
 
 var file_mintrpc_mint_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2f, 0x6d, 0x69, 0x6e, 0x74, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x1a, 0x13, 0x74,
 	0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0x96, 0x02, 0x0a, 0x09, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x74, 0x6f, 0x22, 0x8a, 0x03, 0x0a, 0x09, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
 	0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73,
 	0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79,
@@ -719,93 +998,142 @@ var file_mintrpc_mint_proto_rawDesc = []byte{
 	0x12, 0x39, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
 	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
 	0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61,
-	0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x10,
-	0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x28, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73,
-	0x73, 0x65, 0x74, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x65, 0x6e,
-	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x65, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0e, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x6d, 0x69, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x68, 0x6f,
-	0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f, 0x0a, 0x11, 0x4d, 0x69,
-	0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3a, 0x0a, 0x0d, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x62, 0x61, 0x74, 0x63, 0x68,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63,
-	0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x0c, 0x70,
-	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x22, 0x82, 0x01, 0x0a, 0x0c,
-	0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x0a, 0x09,
-	0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x06, 0x61, 0x73, 0x73,
-	0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x6e, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x06, 0x61,
-	0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x42,
-	0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
-	0x22, 0x3d, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x68, 0x6f, 0x72,
-	0x74, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0d, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x44, 0x0a, 0x15, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x62, 0x61, 0x74, 0x63,
-	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05,
-	0x62, 0x61, 0x74, 0x63, 0x68, 0x22, 0x14, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42,
-	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x32, 0x0a, 0x13, 0x43,
-	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x22,
-	0x61, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4b,
-	0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
-	0x73, 0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x62, 0x61, 0x74,
-	0x63, 0x68, 0x4b, 0x65, 0x79, 0x53, 0x74, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74,
-	0x65, 0x72, 0x22, 0x44, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72,
-	0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
-	0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x2a, 0x88, 0x02, 0x0a, 0x0a, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x42, 0x41, 0x54, 0x43, 0x48,
-	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00,
-	0x12, 0x17, 0x0a, 0x13, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
-	0x50, 0x45, 0x44, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x42, 0x41, 0x54,
-	0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x4f, 0x5a, 0x45, 0x4e, 0x10,
-	0x02, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
-	0x5f, 0x43, 0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15,
-	0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x42, 0x52, 0x4f, 0x41,
-	0x44, 0x43, 0x41, 0x53, 0x54, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48,
-	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44,
-	0x10, 0x05, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54,
-	0x45, 0x5f, 0x46, 0x49, 0x4e, 0x41, 0x4c, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x06, 0x12, 0x22, 0x0a,
-	0x1e, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x45, 0x45,
-	0x44, 0x4c, 0x49, 0x4e, 0x47, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10,
-	0x07, 0x12, 0x20, 0x0a, 0x1c, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
-	0x5f, 0x53, 0x50, 0x52, 0x4f, 0x55, 0x54, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45,
-	0x44, 0x10, 0x08, 0x32, 0xaa, 0x02, 0x0a, 0x04, 0x4d, 0x69, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x09,
-	0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6e, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d,
-	0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x4e, 0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63,
-	0x68, 0x12, 0x1d, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1e, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c,
-	0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x48, 0x0a, 0x0b, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12,
-	0x1b, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
-	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d,
-	0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74,
-	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x4c, 0x69,
-	0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6e, 0x74,
-	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c,
-	0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x74, 0x61, 0x70,
-	0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2f, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2f, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x33,
+	0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4c, 0x0a, 0x14, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x61, 0x70, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x12, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79,
+	0x54, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x41, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x22,
+	0x8c, 0x01, 0x0a, 0x10, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69,
+	0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x27,
+	0x0a, 0x0f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x65, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x45,
+	0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x68, 0x6f, 0x72, 0x74,
+	0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0d, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x4f,
+	0x0a, 0x11, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x62,
+	0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x6e,
+	0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x0c, 0x70, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x22,
+	0xee, 0x01, 0x0a, 0x0c, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a,
+	0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x74, 0x78,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x54,
+	0x78, 0x69, 0x64, 0x12, 0x4b, 0x0a, 0x11, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e,
+	0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69,
+	0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x10,
+	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65,
+	0x22, 0x61, 0x0a, 0x15, 0x42, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x53, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69, 0x6e,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0c, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23,
+	0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x65, 0x64, 0x6c, 0x69,
+	0x6e, 0x67, 0x73, 0x22, 0x87, 0x01, 0x0a, 0x14, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x73, 0x68, 0x6f, 0x72, 0x74, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x69, 0x7a, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f,
+	0x73, 0x65, 0x65, 0x64, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0c, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x65, 0x64, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x44, 0x0a,
+	0x15, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x62, 0x61, 0x74, 0x63, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e,
+	0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x22, 0x14, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x57, 0x0a, 0x13, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x08, 0x62, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x0a,
+	0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x53, 0x65, 0x65, 0x64, 0x6c, 0x69, 0x6e,
+	0x67, 0x73, 0x22, 0x61, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x62, 0x61, 0x74,
+	0x63, 0x68, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b,
+	0x62, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x53, 0x74, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x44, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x62, 0x61,
+	0x74, 0x63, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x69,
+	0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x69, 0x6e, 0x67, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x07, 0x62, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0x52, 0x0a, 0x15, 0x52,
+	0x6f, 0x74, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65,
+	0x79, 0x12, 0x1c, 0x0a, 0x0a, 0x73, 0x61, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x6b, 0x77, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x61, 0x74, 0x50, 0x65, 0x72, 0x4b, 0x77, 0x22,
+	0xb4, 0x01, 0x0a, 0x16, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x72, 0x65, 0x76, 0x4b, 0x65,
+	0x79, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x65, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x6e, 0x65, 0x77, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x2a, 0x88, 0x02, 0x0a, 0x0a, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x13, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x17,
+	0x0a, 0x13, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x50, 0x45,
+	0x44, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x42, 0x41, 0x54, 0x43, 0x48,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x52, 0x4f, 0x5a, 0x45, 0x4e, 0x10, 0x02, 0x12,
+	0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43,
+	0x4f, 0x4d, 0x4d, 0x49, 0x54, 0x54, 0x45, 0x44, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41,
+	0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x42, 0x52, 0x4f, 0x41, 0x44, 0x43,
+	0x41, 0x53, 0x54, 0x10, 0x04, 0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x05,
+	0x12, 0x19, 0x0a, 0x15, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x46, 0x49, 0x4e, 0x41, 0x4c, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x06, 0x12, 0x22, 0x0a, 0x1e, 0x42,
+	0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x45, 0x45, 0x44, 0x4c,
+	0x49, 0x4e, 0x47, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10, 0x07, 0x12,
+	0x20, 0x0a, 0x1c, 0x42, 0x41, 0x54, 0x43, 0x48, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53,
+	0x50, 0x52, 0x4f, 0x55, 0x54, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x4c, 0x45, 0x44, 0x10,
+	0x08, 0x32, 0xfd, 0x02, 0x0a, 0x04, 0x4d, 0x69, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x09, 0x4d, 0x69,
+	0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x6e,
+	0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e,
+	0x0a, 0x0d, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x1d, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48,
+	0x0a, 0x0b, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e,
+	0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x69, 0x6e,
+	0x74, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70,
+	0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51,
+	0x0a, 0x0e, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79,
+	0x12, 0x1e, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1f, 0x2e, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x74, 0x61, 0x74,
+	0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x38, 0x5a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61, 0x62, 0x73, 0x2f, 0x74, 0x61,
+	0x70, 0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2f, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2f, 0x6d, 0x69, 0x6e, 0x74, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -821,46 +1149,54 @@ func file_mintrpc_mint_proto_rawDescGZIP() []byte {
 }
 
 var file_mintrpc_mint_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_mintrpc_mint_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_mintrpc_mint_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_mintrpc_mint_proto_goTypes = []interface{}{
-	(BatchState)(0),               // 0: mintrpc.BatchState
-	(*MintAsset)(nil),             // 1: mintrpc.MintAsset
-	(*MintAssetRequest)(nil),      // 2: mintrpc.MintAssetRequest
-	(*MintAssetResponse)(nil),     // 3: mintrpc.MintAssetResponse
-	(*MintingBatch)(nil),          // 4: mintrpc.MintingBatch
-	(*FinalizeBatchRequest)(nil),  // 5: mintrpc.FinalizeBatchRequest
-	(*FinalizeBatchResponse)(nil), // 6: mintrpc.FinalizeBatchResponse
-	(*CancelBatchRequest)(nil),    // 7: mintrpc.CancelBatchRequest
-	(*CancelBatchResponse)(nil),   // 8: mintrpc.CancelBatchResponse
-	(*ListBatchRequest)(nil),      // 9: mintrpc.ListBatchRequest
-	(*ListBatchResponse)(nil),     // 10: mintrpc.ListBatchResponse
-	(taprpc.AssetType)(0),         // 11: taprpc.AssetType
-	(*taprpc.AssetMeta)(nil),      // 12: taprpc.AssetMeta
-	(taprpc.AssetVersion)(0),      // 13: taprpc.AssetVersion
+	(BatchState)(0),                   // 0: mintrpc.BatchState
+	(*MintAsset)(nil),                 // 1: mintrpc.MintAsset
+	(*MintAssetRequest)(nil),          // 2: mintrpc.MintAssetRequest
+	(*MintAssetResponse)(nil),         // 3: mintrpc.MintAssetResponse
+	(*MintingBatch)(nil),              // 4: mintrpc.MintingBatch
+	(*BatchFinalizeSchedule)(nil),     // 5: mintrpc.BatchFinalizeSchedule
+	(*FinalizeBatchRequest)(nil),      // 6: mintrpc.FinalizeBatchRequest
+	(*FinalizeBatchResponse)(nil),     // 7: mintrpc.FinalizeBatchResponse
+	(*CancelBatchRequest)(nil),        // 8: mintrpc.CancelBatchRequest
+	(*CancelBatchResponse)(nil),       // 9: mintrpc.CancelBatchResponse
+	(*ListBatchRequest)(nil),          // 10: mintrpc.ListBatchRequest
+	(*ListBatchResponse)(nil),         // 11: mintrpc.ListBatchResponse
+	(*RotateGroupKeyRequest)(nil),     // 12: mintrpc.RotateGroupKeyRequest
+	(*RotateGroupKeyResponse)(nil),    // 13: mintrpc.RotateGroupKeyResponse
+	(taprpc.AssetType)(0),             // 14: taprpc.AssetType
+	(*taprpc.AssetMeta)(nil),          // 15: taprpc.AssetMeta
+	(taprpc.AssetVersion)(0),          // 16: taprpc.AssetVersion
+	(*taprpc.ScriptKeyTapscript)(nil), // 17: taprpc.ScriptKeyTapscript
 }
 var file_mintrpc_mint_proto_depIdxs = []int32{
-	11, // 0: mintrpc.MintAsset.asset_type:type_name -> taprpc.AssetType
-	12, // 1: mintrpc.MintAsset.asset_meta:type_name -> taprpc.AssetMeta
-	13, // 2: mintrpc.MintAsset.asset_version:type_name -> taprpc.AssetVersion
-	1,  // 3: mintrpc.MintAssetRequest.asset:type_name -> mintrpc.MintAsset
-	4,  // 4: mintrpc.MintAssetResponse.pending_batch:type_name -> mintrpc.MintingBatch
-	1,  // 5: mintrpc.MintingBatch.assets:type_name -> mintrpc.MintAsset
-	0,  // 6: mintrpc.MintingBatch.state:type_name -> mintrpc.BatchState
-	4,  // 7: mintrpc.FinalizeBatchResponse.batch:type_name -> mintrpc.MintingBatch
-	4,  // 8: mintrpc.ListBatchResponse.batches:type_name -> mintrpc.MintingBatch
-	2,  // 9: mintrpc.Mint.MintAsset:input_type -> mintrpc.MintAssetRequest
-	5,  // 10: mintrpc.Mint.FinalizeBatch:input_type -> mintrpc.FinalizeBatchRequest
-	7,  // 11: mintrpc.Mint.CancelBatch:input_type -> mintrpc.CancelBatchRequest
-	9,  // 12: mintrpc.Mint.ListBatches:input_type -> mintrpc.ListBatchRequest
-	3,  // 13: mintrpc.Mint.MintAsset:output_type -> mintrpc.MintAssetResponse
-	6,  // 14: mintrpc.Mint.FinalizeBatch:output_type -> mintrpc.FinalizeBatchResponse
-	8,  // 15: mintrpc.Mint.CancelBatch:output_type -> mintrpc.CancelBatchResponse
-	10, // 16: mintrpc.Mint.ListBatches:output_type -> mintrpc.ListBatchResponse
-	13, // [13:17] is the sub-list for method output_type
-	9,  // [9:13] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	14, // 0: mintrpc.MintAsset.asset_type:type_name -> taprpc.AssetType
+	15, // 1: mintrpc.MintAsset.asset_meta:type_name -> taprpc.AssetMeta
+	16, // 2: mintrpc.MintAsset.asset_version:type_name -> taprpc.AssetVersion
+	17, // 3: mintrpc.MintAsset.script_key_tapscript:type_name -> taprpc.ScriptKeyTapscript
+	1,  // 4: mintrpc.MintAssetRequest.asset:type_name -> mintrpc.MintAsset
+	4,  // 5: mintrpc.MintAssetResponse.pending_batch:type_name -> mintrpc.MintingBatch
+	1,  // 6: mintrpc.MintingBatch.assets:type_name -> mintrpc.MintAsset
+	0,  // 7: mintrpc.MintingBatch.state:type_name -> mintrpc.BatchState
+	5,  // 8: mintrpc.MintingBatch.finalize_schedule:type_name -> mintrpc.BatchFinalizeSchedule
+	4,  // 9: mintrpc.FinalizeBatchResponse.batch:type_name -> mintrpc.MintingBatch
+	4,  // 10: mintrpc.ListBatchResponse.batches:type_name -> mintrpc.MintingBatch
+	2,  // 11: mintrpc.Mint.MintAsset:input_type -> mintrpc.MintAssetRequest
+	6,  // 12: mintrpc.Mint.FinalizeBatch:input_type -> mintrpc.FinalizeBatchRequest
+	8,  // 13: mintrpc.Mint.CancelBatch:input_type -> mintrpc.CancelBatchRequest
+	10, // 14: mintrpc.Mint.ListBatches:input_type -> mintrpc.ListBatchRequest
+	12, // 15: mintrpc.Mint.RotateGroupKey:input_type -> mintrpc.RotateGroupKeyRequest
+	3,  // 16: mintrpc.Mint.MintAsset:output_type -> mintrpc.MintAssetResponse
+	7,  // 17: mintrpc.Mint.FinalizeBatch:output_type -> mintrpc.FinalizeBatchResponse
+	9,  // 18: mintrpc.Mint.CancelBatch:output_type -> mintrpc.CancelBatchResponse
+	11, // 19: mintrpc.Mint.ListBatches:output_type -> mintrpc.ListBatchResponse
+	13, // 20: mintrpc.Mint.RotateGroupKey:output_type -> mintrpc.RotateGroupKeyResponse
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_mintrpc_mint_proto_init() }
@@ -918,7 +1254,7 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalizeBatchRequest); i {
+			switch v := v.(*BatchFinalizeSchedule); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -930,7 +1266,7 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalizeBatchResponse); i {
+			switch v := v.(*FinalizeBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -942,7 +1278,7 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelBatchRequest); i {
+			switch v := v.(*FinalizeBatchResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -954,7 +1290,7 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CancelBatchResponse); i {
+			switch v := v.(*CancelBatchRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -966,7 +1302,7 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListBatchRequest); i {
+			switch v := v.(*CancelBatchResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -978,6 +1314,18 @@ func file_mintrpc_mint_proto_init() {
 			}
 		}
 		file_mintrpc_mint_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mintrpc_mint_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ListBatchResponse); i {
 			case 0:
 				return &v.state
@@ -989,8 +1337,32 @@ func file_mintrpc_mint_proto_init() {
 				return nil
 			}
 		}
+		file_mintrpc_mint_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateGroupKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_mintrpc_mint_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RotateGroupKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_mintrpc_mint_proto_msgTypes[8].OneofWrappers = []interface{}{
+	file_mintrpc_mint_proto_msgTypes[9].OneofWrappers = []interface{}{
 		(*ListBatchRequest_BatchKey)(nil),
 		(*ListBatchRequest_BatchKeyStr)(nil),
 	}
@@ -1000,7 +1372,7 @@ func file_mintrpc_mint_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_mintrpc_mint_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   10,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
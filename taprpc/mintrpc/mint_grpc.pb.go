@@ -26,15 +26,29 @@ type MintClient interface {
 	// in the batch) or fails.
 	MintAsset(ctx context.Context, in *MintAssetRequest, opts ...grpc.CallOption) (*MintAssetResponse, error)
 	// tapcli: `assets mint finalize`
-	// FinalizeBatch will attempt to finalize the current pending batch.
+	// FinalizeBatch will attempt to finalize the current pending batch. If a
+	// finalize time or a minimum number of seedlings is specified, the batch is
+	// instead scheduled to be finalized automatically once any of the conditions
+	// is met.
 	FinalizeBatch(ctx context.Context, in *FinalizeBatchRequest, opts ...grpc.CallOption) (*FinalizeBatchResponse, error)
 	// tapcli: `assets mint cancel`
-	// CancelBatch will attempt to cancel the current pending batch.
+	// CancelBatch will attempt to cancel the current pending batch. The batch
+	// and its asset seedlings are discarded and the wallet inputs that were
+	// reserved to fund its genesis transaction are released. A batch can no
+	// longer be cancelled once its genesis transaction was broadcast.
 	CancelBatch(ctx context.Context, in *CancelBatchRequest, opts ...grpc.CallOption) (*CancelBatchResponse, error)
 	// tapcli: `assets mint batches`
 	// ListBatches lists the set of batches submitted to the daemon, including
-	// pending and cancelled batches.
+	// pending and cancelled batches. For batches that were already committed,
+	// the genesis transaction ID is returned as well.
 	ListBatches(ctx context.Context, in *ListBatchRequest, opts ...grpc.CallOption) (*ListBatchResponse, error)
+	// tapcli: `assets mint rotategroupkey`
+	// RotateGroupKey hands the authority to issue assets into an asset group
+	// over to a newly derived key. The rotation is signed by the current
+	// authority key and anchored on-chain in an OP_RETURN output. The call blocks
+	// until the anchor transaction is confirmed. From then on, reissuances into
+	// the group must be authorized by the new key.
+	RotateGroupKey(ctx context.Context, in *RotateGroupKeyRequest, opts ...grpc.CallOption) (*RotateGroupKeyResponse, error)
 }
 
 type mintClient struct {
@@ -81,6 +95,15 @@ func (c *mintClient) ListBatches(ctx context.Context, in *ListBatchRequest, opts
 	return out, nil
 }
 
+func (c *mintClient) RotateGroupKey(ctx context.Context, in *RotateGroupKeyRequest, opts ...grpc.CallOption) (*RotateGroupKeyResponse, error) {
+	out := new(RotateGroupKeyResponse)
+	err := c.cc.Invoke(ctx, "/mintrpc.Mint/RotateGroupKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MintServer is the server API for Mint service.
 // All implementations must embed UnimplementedMintServer
 // for forward compatibility
@@ -93,15 +116,29 @@ type MintServer interface {
 	// in the batch) or fails.
 	MintAsset(context.Context, *MintAssetRequest) (*MintAssetResponse, error)
 	// tapcli: `assets mint finalize`
-	// FinalizeBatch will attempt to finalize the current pending batch.
+	// FinalizeBatch will attempt to finalize the current pending batch. If a
+	// finalize time or a minimum number of seedlings is specified, the batch is
+	// instead scheduled to be finalized automatically once any of the conditions
+	// is met.
 	FinalizeBatch(context.Context, *FinalizeBatchRequest) (*FinalizeBatchResponse, error)
 	// tapcli: `assets mint cancel`
-	// CancelBatch will attempt to cancel the current pending batch.
+	// CancelBatch will attempt to cancel the current pending batch. The batch
+	// and its asset seedlings are discarded and the wallet inputs that were
+	// reserved to fund its genesis transaction are released. A batch can no
+	// longer be cancelled once its genesis transaction was broadcast.
 	CancelBatch(context.Context, *CancelBatchRequest) (*CancelBatchResponse, error)
 	// tapcli: `assets mint batches`
 	// ListBatches lists the set of batches submitted to the daemon, including
-	// pending and cancelled batches.
+	// pending and cancelled batches. For batches that were already committed,
+	// the genesis transaction ID is returned as well.
 	ListBatches(context.Context, *ListBatchRequest) (*ListBatchResponse, error)
+	// tapcli: `assets mint rotategroupkey`
+	// RotateGroupKey hands the authority to issue assets into an asset group
+	// over to a newly derived key. The rotation is signed by the current
+	// authority key and anchored on-chain in an OP_RETURN output. The call blocks
+	// until the anchor transaction is confirmed. From then on, reissuances into
+	// the group must be authorized by the new key.
+	RotateGroupKey(context.Context, *RotateGroupKeyRequest) (*RotateGroupKeyResponse, error)
 	mustEmbedUnimplementedMintServer()
 }
 
@@ -121,6 +158,9 @@ func (UnimplementedMintServer) CancelBatch(context.Context, *CancelBatchRequest)
 func (UnimplementedMintServer) ListBatches(context.Context, *ListBatchRequest) (*ListBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListBatches not implemented")
 }
+func (UnimplementedMintServer) RotateGroupKey(context.Context, *RotateGroupKeyRequest) (*RotateGroupKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateGroupKey not implemented")
+}
 func (UnimplementedMintServer) mustEmbedUnimplementedMintServer() {}
 
 // UnsafeMintServer may be embedded to opt out of forward compatibility for this service.
@@ -206,6 +246,24 @@ func _Mint_ListBatches_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Mint_RotateGroupKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateGroupKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MintServer).RotateGroupKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mintrpc.Mint/RotateGroupKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MintServer).RotateGroupKey(ctx, req.(*RotateGroupKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Mint_ServiceDesc is the grpc.ServiceDesc for Mint service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,6 +287,10 @@ var Mint_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListBatches",
 			Handler:    _Mint_ListBatches_Handler,
 		},
+		{
+			MethodName: "RotateGroupKey",
+			Handler:    _Mint_RotateGroupKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "mintrpc/mint.proto",
@@ -78,15 +78,21 @@ const (
 	// Opaque is used for asset meta blobs that have no true structure and instead
 	// should be interpreted as opaque blobs.
 	AssetMetaType_META_TYPE_OPAQUE AssetMetaType = 0
+	// JSON is used for asset meta blobs that are JSON documents. Minting an asset
+	// with this type fails if the meta data doesn't parse as JSON or, if the
+	// daemon was configured with a JSON schema, doesn't adhere to that schema.
+	AssetMetaType_META_TYPE_JSON AssetMetaType = 1
 )
 
 // Enum value maps for AssetMetaType.
 var (
 	AssetMetaType_name = map[int32]string{
 		0: "META_TYPE_OPAQUE",
+		1: "META_TYPE_JSON",
 	}
 	AssetMetaType_value = map[string]int32{
 		"META_TYPE_OPAQUE": 0,
+		"META_TYPE_JSON":   1,
 	}
 )
 
@@ -194,6 +200,12 @@ const (
 	// key rotation (send-to-self) scenarios or asset burns where we burn the
 	// full supply of a single asset within a commitment.
 	OutputType_OUTPUT_TYPE_SIMPLE_PASSIVE_ASSETS OutputType = 4
+	// OUTPUT_TYPE_BURN is an output that provably burns the asset units it
+	// carries, by assigning them to a script key that is derived from the NUMS
+	// point and the first input of the transfer and therefore can't be spent.
+	// This type is only reported in the transfer history, the output is
+	// created as one of the other types.
+	OutputType_OUTPUT_TYPE_BURN OutputType = 5
 )
 
 // Enum value maps for OutputType.
@@ -204,6 +216,7 @@ var (
 		2: "OUTPUT_TYPE_PASSIVE_ASSETS_ONLY",
 		3: "OUTPUT_TYPE_PASSIVE_SPLIT_ROOT",
 		4: "OUTPUT_TYPE_SIMPLE_PASSIVE_ASSETS",
+		5: "OUTPUT_TYPE_BURN",
 	}
 	OutputType_value = map[string]int32{
 		"OUTPUT_TYPE_SIMPLE":                0,
@@ -211,6 +224,7 @@ var (
 		"OUTPUT_TYPE_PASSIVE_ASSETS_ONLY":   2,
 		"OUTPUT_TYPE_PASSIVE_SPLIT_ROOT":    3,
 		"OUTPUT_TYPE_SIMPLE_PASSIVE_ASSETS": 4,
+		"OUTPUT_TYPE_BURN":                  5,
 	}
 )
 
@@ -241,6 +255,74 @@ func (OutputType) EnumDescriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{3}
 }
 
+type ProofDeliveryStatus int32
+
+const (
+	// PROOF_DELIVERY_STATUS_NONE indicates that no delivery outcome was
+	// recorded for the output. Either the output doesn't require a proof
+	// delivery, or the delivery didn't finish yet.
+	ProofDeliveryStatus_PROOF_DELIVERY_STATUS_NONE ProofDeliveryStatus = 0
+	// PROOF_DELIVERY_STATUS_DELIVERED indicates that the receiver acknowledged
+	// the proof.
+	ProofDeliveryStatus_PROOF_DELIVERY_STATUS_DELIVERED ProofDeliveryStatus = 1
+	// PROOF_DELIVERY_STATUS_FAILED indicates that the proof courier gave up on
+	// delivering the proof.
+	ProofDeliveryStatus_PROOF_DELIVERY_STATUS_FAILED ProofDeliveryStatus = 2
+	// PROOF_DELIVERY_STATUS_SKIPPED indicates that no proof courier was
+	// available for the receiver, which needs to obtain the proof out of band.
+	ProofDeliveryStatus_PROOF_DELIVERY_STATUS_SKIPPED ProofDeliveryStatus = 3
+	// PROOF_DELIVERY_STATUS_PENDING indicates that the transfer was completed
+	// according to the delivery completion policy while the delivery of the
+	// proof was still in progress. Pending deliveries are resumed after a
+	// restart.
+	ProofDeliveryStatus_PROOF_DELIVERY_STATUS_PENDING ProofDeliveryStatus = 4
+)
+
+// Enum value maps for ProofDeliveryStatus.
+var (
+	ProofDeliveryStatus_name = map[int32]string{
+		0: "PROOF_DELIVERY_STATUS_NONE",
+		1: "PROOF_DELIVERY_STATUS_DELIVERED",
+		2: "PROOF_DELIVERY_STATUS_FAILED",
+		3: "PROOF_DELIVERY_STATUS_SKIPPED",
+		4: "PROOF_DELIVERY_STATUS_PENDING",
+	}
+	ProofDeliveryStatus_value = map[string]int32{
+		"PROOF_DELIVERY_STATUS_NONE":      0,
+		"PROOF_DELIVERY_STATUS_DELIVERED": 1,
+		"PROOF_DELIVERY_STATUS_FAILED":    2,
+		"PROOF_DELIVERY_STATUS_SKIPPED":   3,
+		"PROOF_DELIVERY_STATUS_PENDING":   4,
+	}
+)
+
+func (x ProofDeliveryStatus) Enum() *ProofDeliveryStatus {
+	p := new(ProofDeliveryStatus)
+	*p = x
+	return p
+}
+
+func (x ProofDeliveryStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProofDeliveryStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_taprootassets_proto_enumTypes[4].Descriptor()
+}
+
+func (ProofDeliveryStatus) Type() protoreflect.EnumType {
+	return &file_taprootassets_proto_enumTypes[4]
+}
+
+func (x ProofDeliveryStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProofDeliveryStatus.Descriptor instead.
+func (ProofDeliveryStatus) EnumDescriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{4}
+}
+
 type AddrEventStatus int32
 
 const (
@@ -280,11 +362,11 @@ func (x AddrEventStatus) String() string {
 }
 
 func (AddrEventStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_taprootassets_proto_enumTypes[4].Descriptor()
+	return file_taprootassets_proto_enumTypes[5].Descriptor()
 }
 
 func (AddrEventStatus) Type() protoreflect.EnumType {
-	return &file_taprootassets_proto_enumTypes[4]
+	return &file_taprootassets_proto_enumTypes[5]
 }
 
 func (x AddrEventStatus) Number() protoreflect.EnumNumber {
@@ -293,7 +375,74 @@ func (x AddrEventStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use AddrEventStatus.Descriptor instead.
 func (AddrEventStatus) EnumDescriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{4}
+	return file_taprootassets_proto_rawDescGZIP(), []int{5}
+}
+
+type ReceiveAssetStatus int32
+
+const (
+	// The anchor transaction of the inbound transfer was detected, but isn't
+	// confirmed yet.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_DETECTED ReceiveAssetStatus = 0
+	// The anchor transaction of the inbound transfer was confirmed.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_CONFIRMED ReceiveAssetStatus = 1
+	// The proof of the inbound transfer was fetched from the proof courier.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_PROOF_RECEIVED ReceiveAssetStatus = 2
+	// The proof of the inbound transfer was verified and imported.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_PROOF_VERIFIED ReceiveAssetStatus = 3
+	// The inbound transfer was completed and the assets are now in custody
+	// of the daemon.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_COMPLETED ReceiveAssetStatus = 4
+	// The proof of the inbound transfer was rejected or couldn't be imported.
+	// The transfer can only be completed by importing its proof manually.
+	ReceiveAssetStatus_RECEIVE_ASSET_STATUS_FAILED ReceiveAssetStatus = 5
+)
+
+// Enum value maps for ReceiveAssetStatus.
+var (
+	ReceiveAssetStatus_name = map[int32]string{
+		0: "RECEIVE_ASSET_STATUS_DETECTED",
+		1: "RECEIVE_ASSET_STATUS_CONFIRMED",
+		2: "RECEIVE_ASSET_STATUS_PROOF_RECEIVED",
+		3: "RECEIVE_ASSET_STATUS_PROOF_VERIFIED",
+		4: "RECEIVE_ASSET_STATUS_COMPLETED",
+		5: "RECEIVE_ASSET_STATUS_FAILED",
+	}
+	ReceiveAssetStatus_value = map[string]int32{
+		"RECEIVE_ASSET_STATUS_DETECTED":       0,
+		"RECEIVE_ASSET_STATUS_CONFIRMED":      1,
+		"RECEIVE_ASSET_STATUS_PROOF_RECEIVED": 2,
+		"RECEIVE_ASSET_STATUS_PROOF_VERIFIED": 3,
+		"RECEIVE_ASSET_STATUS_COMPLETED":      4,
+		"RECEIVE_ASSET_STATUS_FAILED":         5,
+	}
+)
+
+func (x ReceiveAssetStatus) Enum() *ReceiveAssetStatus {
+	p := new(ReceiveAssetStatus)
+	*p = x
+	return p
+}
+
+func (x ReceiveAssetStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReceiveAssetStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_taprootassets_proto_enumTypes[6].Descriptor()
+}
+
+func (ReceiveAssetStatus) Type() protoreflect.EnumType {
+	return &file_taprootassets_proto_enumTypes[6]
+}
+
+func (x ReceiveAssetStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReceiveAssetStatus.Descriptor instead.
+func (ReceiveAssetStatus) EnumDescriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{6}
 }
 
 type AssetMeta struct {
@@ -310,6 +459,10 @@ type AssetMeta struct {
 	// The hash of the meta. This is the hash of the TLV serialization of the meta
 	// itself.
 	MetaHash []byte `protobuf:"bytes,3,opt,name=meta_hash,json=metaHash,proto3" json:"meta_hash,omitempty"`
+	// The maximum total supply that can ever be issued for the asset group
+	// anchored by this asset. Can only be set when minting the anchor of a new
+	// asset group. Zero means the supply of the group isn't capped.
+	SupplyCap uint64 `protobuf:"varint,4,opt,name=supply_cap,json=supplyCap,proto3" json:"supply_cap,omitempty"`
 }
 
 func (x *AssetMeta) Reset() {
@@ -365,6 +518,13 @@ func (x *AssetMeta) GetMetaHash() []byte {
 	return nil
 }
 
+func (x *AssetMeta) GetSupplyCap() uint64 {
+	if x != nil {
+		return x.SupplyCap
+	}
+	return 0
+}
+
 type ListAssetRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -373,6 +533,14 @@ type ListAssetRequest struct {
 	WithWitness   bool `protobuf:"varint,1,opt,name=with_witness,json=withWitness,proto3" json:"with_witness,omitempty"`
 	IncludeSpent  bool `protobuf:"varint,2,opt,name=include_spent,json=includeSpent,proto3" json:"include_spent,omitempty"`
 	IncludeLeased bool `protobuf:"varint,3,opt,name=include_leased,json=includeLeased,proto3" json:"include_leased,omitempty"`
+	// If set, only assets that were acquired at or after this Unix timestamp (in
+	// seconds) are returned. Assets without a known acquisition time never match
+	// this filter.
+	AcquiredAfter int64 `protobuf:"varint,4,opt,name=acquired_after,json=acquiredAfter,proto3" json:"acquired_after,omitempty"`
+	// If set, only assets that were acquired before this Unix timestamp (in
+	// seconds) are returned. Assets without a known acquisition time never match
+	// this filter.
+	AcquiredBefore int64 `protobuf:"varint,5,opt,name=acquired_before,json=acquiredBefore,proto3" json:"acquired_before,omitempty"`
 }
 
 func (x *ListAssetRequest) Reset() {
@@ -428,6 +596,20 @@ func (x *ListAssetRequest) GetIncludeLeased() bool {
 	return false
 }
 
+func (x *ListAssetRequest) GetAcquiredAfter() int64 {
+	if x != nil {
+		return x.AcquiredAfter
+	}
+	return 0
+}
+
+func (x *ListAssetRequest) GetAcquiredBefore() int64 {
+	if x != nil {
+		return x.AcquiredBefore
+	}
+	return 0
+}
+
 type AnchorInfo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -861,6 +1043,12 @@ type Asset struct {
 	// Indicates whether this transfer was an asset burn. If true, the number of
 	// assets in this output are destroyed and can no longer be spent.
 	IsBurn bool `protobuf:"varint,17,opt,name=is_burn,json=isBurn,proto3" json:"is_burn,omitempty"`
+	// The time the asset was minted or received by this node as a Unix timestamp
+	// in seconds. This is the time of the block that confirmed the genesis
+	// transaction for minted assets and the time of the block that confirmed the
+	// anchor transaction for received assets. Zero if the acquisition time isn't
+	// known, which is the case for assets that were stored by an older version.
+	AcquiredAt int64 `protobuf:"varint,18,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"`
 }
 
 func (x *Asset) Reset() {
@@ -1007,6 +1195,13 @@ func (x *Asset) GetIsBurn() bool {
 	return false
 }
 
+func (x *Asset) GetAcquiredAt() int64 {
+	if x != nil {
+		return x.AcquiredAt
+	}
+	return 0
+}
+
 type PrevWitness struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1164,6 +1359,149 @@ func (x *ListAssetResponse) GetAssets() []*Asset {
 	return nil
 }
 
+type SubscribeAssetsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	WithWitness   bool `protobuf:"varint,1,opt,name=with_witness,json=withWitness,proto3" json:"with_witness,omitempty"`
+	IncludeLeased bool `protobuf:"varint,2,opt,name=include_leased,json=includeLeased,proto3" json:"include_leased,omitempty"`
+}
+
+func (x *SubscribeAssetsRequest) Reset() {
+	*x = SubscribeAssetsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeAssetsRequest) ProtoMessage() {}
+
+func (x *SubscribeAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeAssetsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SubscribeAssetsRequest) GetWithWitness() bool {
+	if x != nil {
+		return x.WithWitness
+	}
+	return false
+}
+
+func (x *SubscribeAssetsRequest) GetIncludeLeased() bool {
+	if x != nil {
+		return x.IncludeLeased
+	}
+	return false
+}
+
+type AssetUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Update timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Whether this update is the initial snapshot of the asset set, which is
+	// always the first update sent on the stream. All assets owned at the time of
+	// the subscription are listed as added assets of the snapshot.
+	IsSnapshot bool `protobuf:"varint,2,opt,name=is_snapshot,json=isSnapshot,proto3" json:"is_snapshot,omitempty"`
+	// The assets that were added to the set, for example by receiving them.
+	Added []*Asset `protobuf:"bytes,3,rep,name=added,proto3" json:"added,omitempty"`
+	// The assets that were removed from the set, for example by spending them. If
+	// leased assets aren't included, assets that are leased are removed as well.
+	Removed []*Asset `protobuf:"bytes,4,rep,name=removed,proto3" json:"removed,omitempty"`
+	// The assets that are still in the set but changed, for example because their
+	// anchor transaction confirmed.
+	Changed []*Asset `protobuf:"bytes,5,rep,name=changed,proto3" json:"changed,omitempty"`
+}
+
+func (x *AssetUpdate) Reset() {
+	*x = AssetUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssetUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetUpdate) ProtoMessage() {}
+
+func (x *AssetUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetUpdate.ProtoReflect.Descriptor instead.
+func (*AssetUpdate) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AssetUpdate) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AssetUpdate) GetIsSnapshot() bool {
+	if x != nil {
+		return x.IsSnapshot
+	}
+	return false
+}
+
+func (x *AssetUpdate) GetAdded() []*Asset {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *AssetUpdate) GetRemoved() []*Asset {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *AssetUpdate) GetChanged() []*Asset {
+	if x != nil {
+		return x.Changed
+	}
+	return nil
+}
+
 type ListUtxosRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1175,7 +1513,7 @@ type ListUtxosRequest struct {
 func (x *ListUtxosRequest) Reset() {
 	*x = ListUtxosRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[11]
+		mi := &file_taprootassets_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1188,7 +1526,7 @@ func (x *ListUtxosRequest) String() string {
 func (*ListUtxosRequest) ProtoMessage() {}
 
 func (x *ListUtxosRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[11]
+	mi := &file_taprootassets_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1201,7 +1539,7 @@ func (x *ListUtxosRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUtxosRequest.ProtoReflect.Descriptor instead.
 func (*ListUtxosRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{11}
+	return file_taprootassets_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListUtxosRequest) GetIncludeLeased() bool {
@@ -1235,7 +1573,7 @@ type ManagedUtxo struct {
 func (x *ManagedUtxo) Reset() {
 	*x = ManagedUtxo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[12]
+		mi := &file_taprootassets_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1248,7 +1586,7 @@ func (x *ManagedUtxo) String() string {
 func (*ManagedUtxo) ProtoMessage() {}
 
 func (x *ManagedUtxo) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[12]
+	mi := &file_taprootassets_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1261,7 +1599,7 @@ func (x *ManagedUtxo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ManagedUtxo.ProtoReflect.Descriptor instead.
 func (*ManagedUtxo) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{12}
+	return file_taprootassets_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ManagedUtxo) GetOutPoint() string {
@@ -1318,7 +1656,7 @@ type ListUtxosResponse struct {
 func (x *ListUtxosResponse) Reset() {
 	*x = ListUtxosResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[13]
+		mi := &file_taprootassets_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1331,7 +1669,7 @@ func (x *ListUtxosResponse) String() string {
 func (*ListUtxosResponse) ProtoMessage() {}
 
 func (x *ListUtxosResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[13]
+	mi := &file_taprootassets_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1344,7 +1682,7 @@ func (x *ListUtxosResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUtxosResponse.ProtoReflect.Descriptor instead.
 func (*ListUtxosResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{13}
+	return file_taprootassets_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ListUtxosResponse) GetManagedUtxos() map[string]*ManagedUtxo {
@@ -1354,29 +1692,29 @@ func (x *ListUtxosResponse) GetManagedUtxos() map[string]*ManagedUtxo {
 	return nil
 }
 
-type ListGroupsRequest struct {
+type ListOnChainFootprintRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *ListGroupsRequest) Reset() {
-	*x = ListGroupsRequest{}
+func (x *ListOnChainFootprintRequest) Reset() {
+	*x = ListOnChainFootprintRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[14]
+		mi := &file_taprootassets_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ListGroupsRequest) String() string {
+func (x *ListOnChainFootprintRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListGroupsRequest) ProtoMessage() {}
+func (*ListOnChainFootprintRequest) ProtoMessage() {}
 
-func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[14]
+func (x *ListOnChainFootprintRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1387,51 +1725,44 @@ func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListGroupsRequest.ProtoReflect.Descriptor instead.
-func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListOnChainFootprintRequest.ProtoReflect.Descriptor instead.
+func (*ListOnChainFootprintRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{16}
 }
 
-type AssetHumanReadable struct {
+type FootprintAsset struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// The ID of the asset.
-	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// The amount of the asset.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The number of units of the asset.
 	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
-	// An optional locktime, as with Bitcoin transactions.
-	LockTime int32 `protobuf:"varint,3,opt,name=lock_time,json=lockTime,proto3" json:"lock_time,omitempty"`
-	// An optional relative locktime, as with Bitcoin transactions.
-	RelativeLockTime int32 `protobuf:"varint,4,opt,name=relative_lock_time,json=relativeLockTime,proto3" json:"relative_lock_time,omitempty"`
-	// The name of the asset.
-	Tag string `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
-	// The metadata hash of the asset.
-	MetaHash []byte `protobuf:"bytes,6,opt,name=meta_hash,json=metaHash,proto3" json:"meta_hash,omitempty"`
-	// The type of the asset.
-	Type AssetType `protobuf:"varint,7,opt,name=type,proto3,enum=taprpc.AssetType" json:"type,omitempty"`
-	// The version of the asset.
-	Version AssetVersion `protobuf:"varint,8,opt,name=version,proto3,enum=taprpc.AssetVersion" json:"version,omitempty"`
+	// The tweaked script key of the asset.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// Whether the asset was spent, which is the case for the tombstones and burns
+	// left behind by transfers.
+	Spent bool `protobuf:"varint,4,opt,name=spent,proto3" json:"spent,omitempty"`
 }
 
-func (x *AssetHumanReadable) Reset() {
-	*x = AssetHumanReadable{}
+func (x *FootprintAsset) Reset() {
+	*x = FootprintAsset{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[15]
+		mi := &file_taprootassets_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AssetHumanReadable) String() string {
+func (x *FootprintAsset) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssetHumanReadable) ProtoMessage() {}
+func (*FootprintAsset) ProtoMessage() {}
 
-func (x *AssetHumanReadable) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[15]
+func (x *FootprintAsset) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1442,93 +1773,87 @@ func (x *AssetHumanReadable) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssetHumanReadable.ProtoReflect.Descriptor instead.
-func (*AssetHumanReadable) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use FootprintAsset.ProtoReflect.Descriptor instead.
+func (*FootprintAsset) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *AssetHumanReadable) GetId() []byte {
+func (x *FootprintAsset) GetAssetId() []byte {
 	if x != nil {
-		return x.Id
+		return x.AssetId
 	}
 	return nil
 }
 
-func (x *AssetHumanReadable) GetAmount() uint64 {
+func (x *FootprintAsset) GetAmount() uint64 {
 	if x != nil {
 		return x.Amount
 	}
 	return 0
 }
 
-func (x *AssetHumanReadable) GetLockTime() int32 {
+func (x *FootprintAsset) GetScriptKey() []byte {
 	if x != nil {
-		return x.LockTime
+		return x.ScriptKey
 	}
-	return 0
+	return nil
 }
 
-func (x *AssetHumanReadable) GetRelativeLockTime() int32 {
+func (x *FootprintAsset) GetSpent() bool {
 	if x != nil {
-		return x.RelativeLockTime
+		return x.Spent
 	}
-	return 0
+	return false
 }
 
-func (x *AssetHumanReadable) GetTag() string {
-	if x != nil {
-		return x.Tag
-	}
-	return ""
-}
-
-func (x *AssetHumanReadable) GetMetaHash() []byte {
-	if x != nil {
-		return x.MetaHash
-	}
-	return nil
-}
-
-func (x *AssetHumanReadable) GetType() AssetType {
-	if x != nil {
-		return x.Type
-	}
-	return AssetType_NORMAL
-}
-
-func (x *AssetHumanReadable) GetVersion() AssetVersion {
-	if x != nil {
-		return x.Version
-	}
-	return AssetVersion_ASSET_VERSION_V0
-}
-
-type GroupedAssets struct {
+type FootprintUtxo struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A list of assets with the same group key.
-	Assets []*AssetHumanReadable `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
-}
-
-func (x *GroupedAssets) Reset() {
-	*x = GroupedAssets{}
+	// The outpoint of the UTXO, in the form of "txid:index".
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The value of the UTXO in satoshis.
+	AmtSats int64 `protobuf:"varint,2,opt,name=amt_sats,json=amtSats,proto3" json:"amt_sats,omitempty"`
+	// The internal key of the output, including its key derivation.
+	InternalKey *KeyDescriptor `protobuf:"bytes,3,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	// The Taproot Asset root commitment hash committed to by the output.
+	TaprootAssetRoot []byte `protobuf:"bytes,4,opt,name=taproot_asset_root,json=taprootAssetRoot,proto3" json:"taproot_asset_root,omitempty"`
+	// The Taproot merkle root hash committed to by the output.
+	MerkleRoot []byte `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	// The height of the block the UTXO was confirmed in, or zero if it isn't
+	// confirmed yet.
+	BlockHeight uint32 `protobuf:"varint,6,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	// The owner of the lease on the UTXO, if it is leased.
+	LeaseOwner []byte `protobuf:"bytes,7,opt,name=lease_owner,json=leaseOwner,proto3" json:"lease_owner,omitempty"`
+	// The Unix timestamp in seconds the lease on the UTXO expires at, if it is
+	// leased.
+	LeaseExpiry int64 `protobuf:"varint,8,opt,name=lease_expiry,json=leaseExpiry,proto3" json:"lease_expiry,omitempty"`
+	// The assets anchored in the UTXO.
+	Assets []*FootprintAsset `protobuf:"bytes,9,rep,name=assets,proto3" json:"assets,omitempty"`
+	// Whether the UTXO is the output of a transfer's anchor transaction that is
+	// reserved for bumping its fee with a child transaction. Such a UTXO never
+	// anchors any assets.
+	FeeBumpAnchor bool `protobuf:"varint,10,opt,name=fee_bump_anchor,json=feeBumpAnchor,proto3" json:"fee_bump_anchor,omitempty"`
+}
+
+func (x *FootprintUtxo) Reset() {
+	*x = FootprintUtxo{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[16]
+		mi := &file_taprootassets_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupedAssets) String() string {
+func (x *FootprintUtxo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupedAssets) ProtoMessage() {}
+func (*FootprintUtxo) ProtoMessage() {}
 
-func (x *GroupedAssets) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[16]
+func (x *FootprintUtxo) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1539,185 +1864,94 @@ func (x *GroupedAssets) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupedAssets.ProtoReflect.Descriptor instead.
-func (*GroupedAssets) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use FootprintUtxo.ProtoReflect.Descriptor instead.
+func (*FootprintUtxo) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *GroupedAssets) GetAssets() []*AssetHumanReadable {
+func (x *FootprintUtxo) GetOutpoint() string {
 	if x != nil {
-		return x.Assets
+		return x.Outpoint
 	}
-	return nil
-}
-
-type ListGroupsResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// The set of assets with a group key.
-	Groups map[string]*GroupedAssets `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	return ""
 }
 
-func (x *ListGroupsResponse) Reset() {
-	*x = ListGroupsResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[17]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *FootprintUtxo) GetAmtSats() int64 {
+	if x != nil {
+		return x.AmtSats
 	}
+	return 0
 }
 
-func (x *ListGroupsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListGroupsResponse) ProtoMessage() {}
-
-func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[17]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *FootprintUtxo) GetInternalKey() *KeyDescriptor {
+	if x != nil {
+		return x.InternalKey
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
-func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{17}
+	return nil
 }
 
-func (x *ListGroupsResponse) GetGroups() map[string]*GroupedAssets {
+func (x *FootprintUtxo) GetTaprootAssetRoot() []byte {
 	if x != nil {
-		return x.Groups
+		return x.TaprootAssetRoot
 	}
 	return nil
 }
 
-type ListBalancesRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to GroupBy:
-	//
-	//	*ListBalancesRequest_AssetId
-	//	*ListBalancesRequest_GroupKey
-	GroupBy isListBalancesRequest_GroupBy `protobuf_oneof:"group_by"`
-	// If the query results should grouped by asset ids, then an optional asset
-	// filter may be provided to query balance of a specific asset.
-	AssetFilter []byte `protobuf:"bytes,3,opt,name=asset_filter,json=assetFilter,proto3" json:"asset_filter,omitempty"`
-	// If the query results should be grouped by group keys, then an optional
-	// group key filter may be provided to query the balance of a specific
-	// asset group.
-	GroupKeyFilter []byte `protobuf:"bytes,4,opt,name=group_key_filter,json=groupKeyFilter,proto3" json:"group_key_filter,omitempty"`
-}
-
-func (x *ListBalancesRequest) Reset() {
-	*x = ListBalancesRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[18]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *FootprintUtxo) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
 	}
+	return nil
 }
 
-func (x *ListBalancesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListBalancesRequest) ProtoMessage() {}
-
-func (x *ListBalancesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[18]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *FootprintUtxo) GetBlockHeight() uint32 {
+	if x != nil {
+		return x.BlockHeight
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListBalancesRequest.ProtoReflect.Descriptor instead.
-func (*ListBalancesRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{18}
+	return 0
 }
 
-func (m *ListBalancesRequest) GetGroupBy() isListBalancesRequest_GroupBy {
-	if m != nil {
-		return m.GroupBy
+func (x *FootprintUtxo) GetLeaseOwner() []byte {
+	if x != nil {
+		return x.LeaseOwner
 	}
 	return nil
 }
 
-func (x *ListBalancesRequest) GetAssetId() bool {
-	if x, ok := x.GetGroupBy().(*ListBalancesRequest_AssetId); ok {
-		return x.AssetId
-	}
-	return false
-}
-
-func (x *ListBalancesRequest) GetGroupKey() bool {
-	if x, ok := x.GetGroupBy().(*ListBalancesRequest_GroupKey); ok {
-		return x.GroupKey
+func (x *FootprintUtxo) GetLeaseExpiry() int64 {
+	if x != nil {
+		return x.LeaseExpiry
 	}
-	return false
+	return 0
 }
 
-func (x *ListBalancesRequest) GetAssetFilter() []byte {
+func (x *FootprintUtxo) GetAssets() []*FootprintAsset {
 	if x != nil {
-		return x.AssetFilter
+		return x.Assets
 	}
 	return nil
 }
 
-func (x *ListBalancesRequest) GetGroupKeyFilter() []byte {
+func (x *FootprintUtxo) GetFeeBumpAnchor() bool {
 	if x != nil {
-		return x.GroupKeyFilter
+		return x.FeeBumpAnchor
 	}
-	return nil
-}
-
-type isListBalancesRequest_GroupBy interface {
-	isListBalancesRequest_GroupBy()
-}
-
-type ListBalancesRequest_AssetId struct {
-	// Group results by asset IDs.
-	AssetId bool `protobuf:"varint,1,opt,name=asset_id,json=assetId,proto3,oneof"`
-}
-
-type ListBalancesRequest_GroupKey struct {
-	// Group results by group keys.
-	GroupKey bool `protobuf:"varint,2,opt,name=group_key,json=groupKey,proto3,oneof"`
+	return false
 }
 
-func (*ListBalancesRequest_AssetId) isListBalancesRequest_GroupBy() {}
-
-func (*ListBalancesRequest_GroupKey) isListBalancesRequest_GroupBy() {}
-
-type AssetBalance struct {
+type ListOnChainFootprintResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The base genesis information of an asset. This information never changes.
-	AssetGenesis *GenesisInfo `protobuf:"bytes,1,opt,name=asset_genesis,json=assetGenesis,proto3" json:"asset_genesis,omitempty"`
-	// The type of the asset.
-	AssetType AssetType `protobuf:"varint,2,opt,name=asset_type,json=assetType,proto3,enum=taprpc.AssetType" json:"asset_type,omitempty"`
-	// The balance of the asset owned by the target daemon.
-	Balance uint64 `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	// The UTXOs the daemon considers its own.
+	Utxos []*FootprintUtxo `protobuf:"bytes,1,rep,name=utxos,proto3" json:"utxos,omitempty"`
+	// The total value of all UTXOs in satoshis.
+	TotalAmtSats int64 `protobuf:"varint,2,opt,name=total_amt_sats,json=totalAmtSats,proto3" json:"total_amt_sats,omitempty"`
 }
 
-func (x *AssetBalance) Reset() {
-	*x = AssetBalance{}
+func (x *ListOnChainFootprintResponse) Reset() {
+	*x = ListOnChainFootprintResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1725,13 +1959,13 @@ func (x *AssetBalance) Reset() {
 	}
 }
 
-func (x *AssetBalance) String() string {
+func (x *ListOnChainFootprintResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssetBalance) ProtoMessage() {}
+func (*ListOnChainFootprintResponse) ProtoMessage() {}
 
-func (x *AssetBalance) ProtoReflect() protoreflect.Message {
+func (x *ListOnChainFootprintResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1743,45 +1977,33 @@ func (x *AssetBalance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssetBalance.ProtoReflect.Descriptor instead.
-func (*AssetBalance) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListOnChainFootprintResponse.ProtoReflect.Descriptor instead.
+func (*ListOnChainFootprintResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *AssetBalance) GetAssetGenesis() *GenesisInfo {
+func (x *ListOnChainFootprintResponse) GetUtxos() []*FootprintUtxo {
 	if x != nil {
-		return x.AssetGenesis
+		return x.Utxos
 	}
 	return nil
 }
 
-func (x *AssetBalance) GetAssetType() AssetType {
-	if x != nil {
-		return x.AssetType
-	}
-	return AssetType_NORMAL
-}
-
-func (x *AssetBalance) GetBalance() uint64 {
+func (x *ListOnChainFootprintResponse) GetTotalAmtSats() int64 {
 	if x != nil {
-		return x.Balance
+		return x.TotalAmtSats
 	}
 	return 0
 }
 
-type AssetGroupBalance struct {
+type ListGroupsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The group key or nil aggregating assets that don't have a group.
-	GroupKey []byte `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
-	// The total balance of the assets in the group.
-	Balance uint64 `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
 }
 
-func (x *AssetGroupBalance) Reset() {
-	*x = AssetGroupBalance{}
+func (x *ListGroupsRequest) Reset() {
+	*x = ListGroupsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1789,13 +2011,13 @@ func (x *AssetGroupBalance) Reset() {
 	}
 }
 
-func (x *AssetGroupBalance) String() string {
+func (x *ListGroupsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssetGroupBalance) ProtoMessage() {}
+func (*ListGroupsRequest) ProtoMessage() {}
 
-func (x *AssetGroupBalance) ProtoReflect() protoreflect.Message {
+func (x *ListGroupsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1807,36 +2029,22 @@ func (x *AssetGroupBalance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssetGroupBalance.ProtoReflect.Descriptor instead.
-func (*AssetGroupBalance) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListGroupsRequest.ProtoReflect.Descriptor instead.
+func (*ListGroupsRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *AssetGroupBalance) GetGroupKey() []byte {
-	if x != nil {
-		return x.GroupKey
-	}
-	return nil
-}
-
-func (x *AssetGroupBalance) GetBalance() uint64 {
-	if x != nil {
-		return x.Balance
-	}
-	return 0
-}
-
-type ListBalancesResponse struct {
+type VerifySupplyConsistencyRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AssetBalances      map[string]*AssetBalance      `protobuf:"bytes,1,rep,name=asset_balances,json=assetBalances,proto3" json:"asset_balances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	AssetGroupBalances map[string]*AssetGroupBalance `protobuf:"bytes,2,rep,name=asset_group_balances,json=assetGroupBalances,proto3" json:"asset_group_balances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The key of the asset group to verify the supply of.
+	GroupKey []byte `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
 }
 
-func (x *ListBalancesResponse) Reset() {
-	*x = ListBalancesResponse{}
+func (x *VerifySupplyConsistencyRequest) Reset() {
+	*x = VerifySupplyConsistencyRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1844,13 +2052,13 @@ func (x *ListBalancesResponse) Reset() {
 	}
 }
 
-func (x *ListBalancesResponse) String() string {
+func (x *VerifySupplyConsistencyRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListBalancesResponse) ProtoMessage() {}
+func (*VerifySupplyConsistencyRequest) ProtoMessage() {}
 
-func (x *ListBalancesResponse) ProtoReflect() protoreflect.Message {
+func (x *VerifySupplyConsistencyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1862,33 +2070,55 @@ func (x *ListBalancesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListBalancesResponse.ProtoReflect.Descriptor instead.
-func (*ListBalancesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifySupplyConsistencyRequest.ProtoReflect.Descriptor instead.
+func (*VerifySupplyConsistencyRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *ListBalancesResponse) GetAssetBalances() map[string]*AssetBalance {
+func (x *VerifySupplyConsistencyRequest) GetGroupKey() []byte {
 	if x != nil {
-		return x.AssetBalances
-	}
-	return nil
-}
-
-func (x *ListBalancesResponse) GetAssetGroupBalances() map[string]*AssetGroupBalance {
-	if x != nil {
-		return x.AssetGroupBalances
+		return x.GroupKey
 	}
 	return nil
 }
 
-type ListTransfersRequest struct {
+type VerifySupplyConsistencyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *ListTransfersRequest) Reset() {
-	*x = ListTransfersRequest{}
+	// The key of the asset group.
+	GroupKey []byte `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	// The sum of the issuance universe root of the group.
+	IssuanceRootSum uint64 `protobuf:"varint,2,opt,name=issuance_root_sum,json=issuanceRootSum,proto3" json:"issuance_root_sum,omitempty"`
+	// The sum of the amounts of all issuance proofs of the group.
+	IssuedAmt uint64 `protobuf:"varint,3,opt,name=issued_amt,json=issuedAmt,proto3" json:"issued_amt,omitempty"`
+	// The number of issuance proofs of the group.
+	NumIssuances uint64 `protobuf:"varint,4,opt,name=num_issuances,json=numIssuances,proto3" json:"num_issuances,omitempty"`
+	// The sum of the amounts of all known burns of the group.
+	BurnedAmt uint64 `protobuf:"varint,5,opt,name=burned_amt,json=burnedAmt,proto3" json:"burned_amt,omitempty"`
+	// The number of known burns of the group.
+	NumBurns uint64 `protobuf:"varint,6,opt,name=num_burns,json=numBurns,proto3" json:"num_burns,omitempty"`
+	// The number of units of the group held by this node.
+	LocalBalance uint64 `protobuf:"varint,7,opt,name=local_balance,json=localBalance,proto3" json:"local_balance,omitempty"`
+	// The number of units that were issued and not burned.
+	OutstandingAmt uint64 `protobuf:"varint,8,opt,name=outstanding_amt,json=outstandingAmt,proto3" json:"outstanding_amt,omitempty"`
+	// Whether the supply is consistent, meaning no discrepancy was found.
+	Consistent bool `protobuf:"varint,9,opt,name=consistent,proto3" json:"consistent,omitempty"`
+	// A description of every discrepancy that was found.
+	Discrepancies []string `protobuf:"bytes,10,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
+	// The number of units held in the unspent outputs of the group that are
+	// known to the universe or this node. An output is unspent if no known
+	// transfer spends it. The supply is only consistent if this matches the
+	// outstanding units.
+	UnspentAmt uint64 `protobuf:"varint,11,opt,name=unspent_amt,json=unspentAmt,proto3" json:"unspent_amt,omitempty"`
+	// The number of unspent outputs of the group that are known to the
+	// universe or this node.
+	NumUnspentOutputs uint64 `protobuf:"varint,12,opt,name=num_unspent_outputs,json=numUnspentOutputs,proto3" json:"num_unspent_outputs,omitempty"`
+}
+
+func (x *VerifySupplyConsistencyResponse) Reset() {
+	*x = VerifySupplyConsistencyResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1896,13 +2126,13 @@ func (x *ListTransfersRequest) Reset() {
 	}
 }
 
-func (x *ListTransfersRequest) String() string {
+func (x *VerifySupplyConsistencyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTransfersRequest) ProtoMessage() {}
+func (*VerifySupplyConsistencyResponse) ProtoMessage() {}
 
-func (x *ListTransfersRequest) ProtoReflect() protoreflect.Message {
+func (x *VerifySupplyConsistencyResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1914,183 +2144,135 @@ func (x *ListTransfersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTransfersRequest.ProtoReflect.Descriptor instead.
-func (*ListTransfersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use VerifySupplyConsistencyResponse.ProtoReflect.Descriptor instead.
+func (*VerifySupplyConsistencyResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{22}
 }
 
-type ListTransfersResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// The unordered list of outgoing asset transfers.
-	Transfers []*AssetTransfer `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
-}
-
-func (x *ListTransfersResponse) Reset() {
-	*x = ListTransfersResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[23]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *VerifySupplyConsistencyResponse) GetGroupKey() []byte {
+	if x != nil {
+		return x.GroupKey
 	}
+	return nil
 }
 
-func (x *ListTransfersResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ListTransfersResponse) ProtoMessage() {}
-
-func (x *ListTransfersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[23]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *VerifySupplyConsistencyResponse) GetIssuanceRootSum() uint64 {
+	if x != nil {
+		return x.IssuanceRootSum
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListTransfersResponse.ProtoReflect.Descriptor instead.
-func (*ListTransfersResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{23}
+	return 0
 }
 
-func (x *ListTransfersResponse) GetTransfers() []*AssetTransfer {
+func (x *VerifySupplyConsistencyResponse) GetIssuedAmt() uint64 {
 	if x != nil {
-		return x.Transfers
+		return x.IssuedAmt
 	}
-	return nil
-}
-
-type AssetTransfer struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	TransferTimestamp int64 `protobuf:"varint,1,opt,name=transfer_timestamp,json=transferTimestamp,proto3" json:"transfer_timestamp,omitempty"`
-	// The new transaction that commits to the set of Taproot Assets found
-	// at the above new anchor point.
-	AnchorTxHash       []byte `protobuf:"bytes,2,opt,name=anchor_tx_hash,json=anchorTxHash,proto3" json:"anchor_tx_hash,omitempty"`
-	AnchorTxHeightHint uint32 `protobuf:"varint,3,opt,name=anchor_tx_height_hint,json=anchorTxHeightHint,proto3" json:"anchor_tx_height_hint,omitempty"`
-	AnchorTxChainFees  int64  `protobuf:"varint,4,opt,name=anchor_tx_chain_fees,json=anchorTxChainFees,proto3" json:"anchor_tx_chain_fees,omitempty"`
-	// Describes the set of spent assets.
-	Inputs []*TransferInput `protobuf:"bytes,5,rep,name=inputs,proto3" json:"inputs,omitempty"`
-	// Describes the set of newly created asset outputs.
-	Outputs []*TransferOutput `protobuf:"bytes,6,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	return 0
 }
 
-func (x *AssetTransfer) Reset() {
-	*x = AssetTransfer{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[24]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *VerifySupplyConsistencyResponse) GetNumIssuances() uint64 {
+	if x != nil {
+		return x.NumIssuances
 	}
+	return 0
 }
 
-func (x *AssetTransfer) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*AssetTransfer) ProtoMessage() {}
-
-func (x *AssetTransfer) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[24]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *VerifySupplyConsistencyResponse) GetBurnedAmt() uint64 {
+	if x != nil {
+		return x.BurnedAmt
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use AssetTransfer.ProtoReflect.Descriptor instead.
-func (*AssetTransfer) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{24}
+	return 0
 }
 
-func (x *AssetTransfer) GetTransferTimestamp() int64 {
+func (x *VerifySupplyConsistencyResponse) GetNumBurns() uint64 {
 	if x != nil {
-		return x.TransferTimestamp
+		return x.NumBurns
 	}
 	return 0
 }
 
-func (x *AssetTransfer) GetAnchorTxHash() []byte {
+func (x *VerifySupplyConsistencyResponse) GetLocalBalance() uint64 {
 	if x != nil {
-		return x.AnchorTxHash
+		return x.LocalBalance
 	}
-	return nil
+	return 0
 }
 
-func (x *AssetTransfer) GetAnchorTxHeightHint() uint32 {
+func (x *VerifySupplyConsistencyResponse) GetOutstandingAmt() uint64 {
 	if x != nil {
-		return x.AnchorTxHeightHint
+		return x.OutstandingAmt
 	}
 	return 0
 }
 
-func (x *AssetTransfer) GetAnchorTxChainFees() int64 {
+func (x *VerifySupplyConsistencyResponse) GetConsistent() bool {
 	if x != nil {
-		return x.AnchorTxChainFees
+		return x.Consistent
 	}
-	return 0
+	return false
 }
 
-func (x *AssetTransfer) GetInputs() []*TransferInput {
+func (x *VerifySupplyConsistencyResponse) GetDiscrepancies() []string {
 	if x != nil {
-		return x.Inputs
+		return x.Discrepancies
 	}
 	return nil
 }
 
-func (x *AssetTransfer) GetOutputs() []*TransferOutput {
+func (x *VerifySupplyConsistencyResponse) GetUnspentAmt() uint64 {
 	if x != nil {
-		return x.Outputs
+		return x.UnspentAmt
 	}
-	return nil
+	return 0
 }
 
-type TransferInput struct {
+func (x *VerifySupplyConsistencyResponse) GetNumUnspentOutputs() uint64 {
+	if x != nil {
+		return x.NumUnspentOutputs
+	}
+	return 0
+}
+
+type AssetHumanReadable struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The old/current location of the Taproot Asset commitment that was spent
-	// as an input.
-	AnchorPoint string `protobuf:"bytes,1,opt,name=anchor_point,json=anchorPoint,proto3" json:"anchor_point,omitempty"`
-	// The ID of the asset that was spent.
-	AssetId []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	// The script key of the asset that was spent.
-	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-	// The amount of the asset that was spent.
-	Amount uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The ID of the asset.
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The amount of the asset.
+	Amount uint64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// An optional locktime, as with Bitcoin transactions.
+	LockTime int32 `protobuf:"varint,3,opt,name=lock_time,json=lockTime,proto3" json:"lock_time,omitempty"`
+	// An optional relative locktime, as with Bitcoin transactions.
+	RelativeLockTime int32 `protobuf:"varint,4,opt,name=relative_lock_time,json=relativeLockTime,proto3" json:"relative_lock_time,omitempty"`
+	// The name of the asset.
+	Tag string `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	// The metadata hash of the asset.
+	MetaHash []byte `protobuf:"bytes,6,opt,name=meta_hash,json=metaHash,proto3" json:"meta_hash,omitempty"`
+	// The type of the asset.
+	Type AssetType `protobuf:"varint,7,opt,name=type,proto3,enum=taprpc.AssetType" json:"type,omitempty"`
+	// The version of the asset.
+	Version AssetVersion `protobuf:"varint,8,opt,name=version,proto3,enum=taprpc.AssetVersion" json:"version,omitempty"`
 }
 
-func (x *TransferInput) Reset() {
-	*x = TransferInput{}
+func (x *AssetHumanReadable) Reset() {
+	*x = AssetHumanReadable{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[25]
+		mi := &file_taprootassets_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TransferInput) String() string {
+func (x *AssetHumanReadable) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferInput) ProtoMessage() {}
+func (*AssetHumanReadable) ProtoMessage() {}
 
-func (x *TransferInput) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[25]
+func (x *AssetHumanReadable) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2101,72 +2283,93 @@ func (x *TransferInput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferInput.ProtoReflect.Descriptor instead.
-func (*TransferInput) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use AssetHumanReadable.ProtoReflect.Descriptor instead.
+func (*AssetHumanReadable) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *TransferInput) GetAnchorPoint() string {
+func (x *AssetHumanReadable) GetId() []byte {
 	if x != nil {
-		return x.AnchorPoint
+		return x.Id
+	}
+	return nil
+}
+
+func (x *AssetHumanReadable) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AssetHumanReadable) GetLockTime() int32 {
+	if x != nil {
+		return x.LockTime
+	}
+	return 0
+}
+
+func (x *AssetHumanReadable) GetRelativeLockTime() int32 {
+	if x != nil {
+		return x.RelativeLockTime
+	}
+	return 0
+}
+
+func (x *AssetHumanReadable) GetTag() string {
+	if x != nil {
+		return x.Tag
 	}
 	return ""
 }
 
-func (x *TransferInput) GetAssetId() []byte {
+func (x *AssetHumanReadable) GetMetaHash() []byte {
 	if x != nil {
-		return x.AssetId
+		return x.MetaHash
 	}
 	return nil
 }
 
-func (x *TransferInput) GetScriptKey() []byte {
+func (x *AssetHumanReadable) GetType() AssetType {
 	if x != nil {
-		return x.ScriptKey
+		return x.Type
 	}
-	return nil
+	return AssetType_NORMAL
 }
 
-func (x *TransferInput) GetAmount() uint64 {
+func (x *AssetHumanReadable) GetVersion() AssetVersion {
 	if x != nil {
-		return x.Amount
+		return x.Version
 	}
-	return 0
+	return AssetVersion_ASSET_VERSION_V0
 }
 
-type TransferOutputAnchor struct {
+type GroupedAssets struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The new location of the Taproot Asset commitment that was created on
-	// chain.
-	Outpoint         string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
-	Value            int64  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
-	InternalKey      []byte `protobuf:"bytes,3,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
-	TaprootAssetRoot []byte `protobuf:"bytes,4,opt,name=taproot_asset_root,json=taprootAssetRoot,proto3" json:"taproot_asset_root,omitempty"`
-	MerkleRoot       []byte `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
-	TapscriptSibling []byte `protobuf:"bytes,6,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
-	NumPassiveAssets uint32 `protobuf:"varint,7,opt,name=num_passive_assets,json=numPassiveAssets,proto3" json:"num_passive_assets,omitempty"`
+	// A list of assets with the same group key.
+	Assets []*AssetHumanReadable `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
 }
 
-func (x *TransferOutputAnchor) Reset() {
-	*x = TransferOutputAnchor{}
+func (x *GroupedAssets) Reset() {
+	*x = GroupedAssets{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[26]
+		mi := &file_taprootassets_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TransferOutputAnchor) String() string {
+func (x *GroupedAssets) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferOutputAnchor) ProtoMessage() {}
+func (*GroupedAssets) ProtoMessage() {}
 
-func (x *TransferOutputAnchor) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[26]
+func (x *GroupedAssets) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2177,94 +2380,102 @@ func (x *TransferOutputAnchor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferOutputAnchor.ProtoReflect.Descriptor instead.
-func (*TransferOutputAnchor) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use GroupedAssets.ProtoReflect.Descriptor instead.
+func (*GroupedAssets) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *TransferOutputAnchor) GetOutpoint() string {
+func (x *GroupedAssets) GetAssets() []*AssetHumanReadable {
 	if x != nil {
-		return x.Outpoint
+		return x.Assets
 	}
-	return ""
+	return nil
 }
 
-func (x *TransferOutputAnchor) GetValue() int64 {
-	if x != nil {
-		return x.Value
-	}
-	return 0
+type ListGroupsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The set of assets with a group key.
+	Groups map[string]*GroupedAssets `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *TransferOutputAnchor) GetInternalKey() []byte {
-	if x != nil {
-		return x.InternalKey
+func (x *ListGroupsResponse) Reset() {
+	*x = ListGroupsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *TransferOutputAnchor) GetTaprootAssetRoot() []byte {
-	if x != nil {
-		return x.TaprootAssetRoot
-	}
-	return nil
+func (x *ListGroupsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *TransferOutputAnchor) GetMerkleRoot() []byte {
-	if x != nil {
-		return x.MerkleRoot
+func (*ListGroupsResponse) ProtoMessage() {}
+
+func (x *ListGroupsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *TransferOutputAnchor) GetTapscriptSibling() []byte {
-	if x != nil {
-		return x.TapscriptSibling
-	}
-	return nil
+// Deprecated: Use ListGroupsResponse.ProtoReflect.Descriptor instead.
+func (*ListGroupsResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *TransferOutputAnchor) GetNumPassiveAssets() uint32 {
+func (x *ListGroupsResponse) GetGroups() map[string]*GroupedAssets {
 	if x != nil {
-		return x.NumPassiveAssets
+		return x.Groups
 	}
-	return 0
+	return nil
 }
 
-type TransferOutput struct {
+type ListBalancesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Anchor           *TransferOutputAnchor `protobuf:"bytes,1,opt,name=anchor,proto3" json:"anchor,omitempty"`
-	ScriptKey        []byte                `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-	ScriptKeyIsLocal bool                  `protobuf:"varint,3,opt,name=script_key_is_local,json=scriptKeyIsLocal,proto3" json:"script_key_is_local,omitempty"`
-	Amount           uint64                `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	// The new individual transition proof (not a full proof file) that proves
-	// the inclusion of the new asset within the new AnchorTx.
-	NewProofBlob        []byte       `protobuf:"bytes,5,opt,name=new_proof_blob,json=newProofBlob,proto3" json:"new_proof_blob,omitempty"`
-	SplitCommitRootHash []byte       `protobuf:"bytes,6,opt,name=split_commit_root_hash,json=splitCommitRootHash,proto3" json:"split_commit_root_hash,omitempty"`
-	OutputType          OutputType   `protobuf:"varint,7,opt,name=output_type,json=outputType,proto3,enum=taprpc.OutputType" json:"output_type,omitempty"`
-	AssetVersion        AssetVersion `protobuf:"varint,8,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// Types that are assignable to GroupBy:
+	//
+	//	*ListBalancesRequest_AssetId
+	//	*ListBalancesRequest_GroupKey
+	GroupBy isListBalancesRequest_GroupBy `protobuf_oneof:"group_by"`
+	// If the query results should grouped by asset ids, then an optional asset
+	// filter may be provided to query balance of a specific asset.
+	AssetFilter []byte `protobuf:"bytes,3,opt,name=asset_filter,json=assetFilter,proto3" json:"asset_filter,omitempty"`
+	// If the query results should be grouped by group keys, then an optional
+	// group key filter may be provided to query the balance of a specific
+	// asset group.
+	GroupKeyFilter []byte `protobuf:"bytes,4,opt,name=group_key_filter,json=groupKeyFilter,proto3" json:"group_key_filter,omitempty"`
 }
 
-func (x *TransferOutput) Reset() {
-	*x = TransferOutput{}
+func (x *ListBalancesRequest) Reset() {
+	*x = ListBalancesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[27]
+		mi := &file_taprootassets_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *TransferOutput) String() string {
+func (x *ListBalancesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TransferOutput) ProtoMessage() {}
+func (*ListBalancesRequest) ProtoMessage() {}
 
-func (x *TransferOutput) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[27]
+func (x *ListBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2275,90 +2486,98 @@ func (x *TransferOutput) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TransferOutput.ProtoReflect.Descriptor instead.
-func (*TransferOutput) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use ListBalancesRequest.ProtoReflect.Descriptor instead.
+func (*ListBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *TransferOutput) GetAnchor() *TransferOutputAnchor {
-	if x != nil {
-		return x.Anchor
+func (m *ListBalancesRequest) GetGroupBy() isListBalancesRequest_GroupBy {
+	if m != nil {
+		return m.GroupBy
 	}
 	return nil
 }
 
-func (x *TransferOutput) GetScriptKey() []byte {
-	if x != nil {
-		return x.ScriptKey
+func (x *ListBalancesRequest) GetAssetId() bool {
+	if x, ok := x.GetGroupBy().(*ListBalancesRequest_AssetId); ok {
+		return x.AssetId
 	}
-	return nil
+	return false
 }
 
-func (x *TransferOutput) GetScriptKeyIsLocal() bool {
-	if x != nil {
-		return x.ScriptKeyIsLocal
+func (x *ListBalancesRequest) GetGroupKey() bool {
+	if x, ok := x.GetGroupBy().(*ListBalancesRequest_GroupKey); ok {
+		return x.GroupKey
 	}
 	return false
 }
 
-func (x *TransferOutput) GetAmount() uint64 {
+func (x *ListBalancesRequest) GetAssetFilter() []byte {
 	if x != nil {
-		return x.Amount
+		return x.AssetFilter
 	}
-	return 0
+	return nil
 }
 
-func (x *TransferOutput) GetNewProofBlob() []byte {
+func (x *ListBalancesRequest) GetGroupKeyFilter() []byte {
 	if x != nil {
-		return x.NewProofBlob
+		return x.GroupKeyFilter
 	}
 	return nil
 }
 
-func (x *TransferOutput) GetSplitCommitRootHash() []byte {
-	if x != nil {
-		return x.SplitCommitRootHash
-	}
-	return nil
+type isListBalancesRequest_GroupBy interface {
+	isListBalancesRequest_GroupBy()
 }
 
-func (x *TransferOutput) GetOutputType() OutputType {
-	if x != nil {
-		return x.OutputType
-	}
-	return OutputType_OUTPUT_TYPE_SIMPLE
+type ListBalancesRequest_AssetId struct {
+	// Group results by asset IDs.
+	AssetId bool `protobuf:"varint,1,opt,name=asset_id,json=assetId,proto3,oneof"`
 }
 
-func (x *TransferOutput) GetAssetVersion() AssetVersion {
-	if x != nil {
-		return x.AssetVersion
-	}
-	return AssetVersion_ASSET_VERSION_V0
+type ListBalancesRequest_GroupKey struct {
+	// Group results by group keys. The balance of each group is the
+	// total over all the asset IDs (tranches) of the group, with a
+	// breakdown by asset ID. Assets that aren't part of a group are
+	// reported individually in asset_balances, unless a group key filter
+	// is set.
+	GroupKey bool `protobuf:"varint,2,opt,name=group_key,json=groupKey,proto3,oneof"`
 }
 
-type StopRequest struct {
+func (*ListBalancesRequest_AssetId) isListBalancesRequest_GroupBy() {}
+
+func (*ListBalancesRequest_GroupKey) isListBalancesRequest_GroupBy() {}
+
+type AssetBalance struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The base genesis information of an asset. This information never changes.
+	AssetGenesis *GenesisInfo `protobuf:"bytes,1,opt,name=asset_genesis,json=assetGenesis,proto3" json:"asset_genesis,omitempty"`
+	// The type of the asset.
+	AssetType AssetType `protobuf:"varint,2,opt,name=asset_type,json=assetType,proto3,enum=taprpc.AssetType" json:"asset_type,omitempty"`
+	// The balance of the asset owned by the target daemon.
+	Balance uint64 `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"`
 }
 
-func (x *StopRequest) Reset() {
-	*x = StopRequest{}
+func (x *AssetBalance) Reset() {
+	*x = AssetBalance{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[28]
+		mi := &file_taprootassets_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StopRequest) String() string {
+func (x *AssetBalance) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopRequest) ProtoMessage() {}
+func (*AssetBalance) ProtoMessage() {}
 
-func (x *StopRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[28]
+func (x *AssetBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2369,34 +2588,63 @@ func (x *StopRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
-func (*StopRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use AssetBalance.ProtoReflect.Descriptor instead.
+func (*AssetBalance) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{27}
 }
 
-type StopResponse struct {
+func (x *AssetBalance) GetAssetGenesis() *GenesisInfo {
+	if x != nil {
+		return x.AssetGenesis
+	}
+	return nil
+}
+
+func (x *AssetBalance) GetAssetType() AssetType {
+	if x != nil {
+		return x.AssetType
+	}
+	return AssetType_NORMAL
+}
+
+func (x *AssetBalance) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type AssetGroupBalance struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The group key of the asset group.
+	GroupKey []byte `protobuf:"bytes,1,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	// The total balance of the assets in the group.
+	Balance uint64 `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// The balances of the individual asset IDs of the group, keyed by the
+	// hex encoded asset ID.
+	AssetBalances map[string]*AssetBalance `protobuf:"bytes,3,rep,name=asset_balances,json=assetBalances,proto3" json:"asset_balances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *StopResponse) Reset() {
-	*x = StopResponse{}
+func (x *AssetGroupBalance) Reset() {
+	*x = AssetGroupBalance{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[29]
+		mi := &file_taprootassets_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *StopResponse) String() string {
+func (x *AssetGroupBalance) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StopResponse) ProtoMessage() {}
+func (*AssetGroupBalance) ProtoMessage() {}
 
-func (x *StopResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[29]
+func (x *AssetGroupBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2407,38 +2655,60 @@ func (x *StopResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
-func (*StopResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use AssetGroupBalance.ProtoReflect.Descriptor instead.
+func (*AssetGroupBalance) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{28}
 }
 
-type DebugLevelRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *AssetGroupBalance) GetGroupKey() []byte {
+	if x != nil {
+		return x.GroupKey
+	}
+	return nil
+}
 
-	// If true, all the valid debug sub-systems will be returned.
-	Show      bool   `protobuf:"varint,1,opt,name=show,proto3" json:"show,omitempty"`
-	LevelSpec string `protobuf:"bytes,2,opt,name=level_spec,json=levelSpec,proto3" json:"level_spec,omitempty"`
+func (x *AssetGroupBalance) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
 }
 
-func (x *DebugLevelRequest) Reset() {
-	*x = DebugLevelRequest{}
+func (x *AssetGroupBalance) GetAssetBalances() map[string]*AssetBalance {
+	if x != nil {
+		return x.AssetBalances
+	}
+	return nil
+}
+
+type ListBalancesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The balances by asset ID. If the results are grouped by group keys,
+	// this only contains the assets that aren't part of a group.
+	AssetBalances      map[string]*AssetBalance      `protobuf:"bytes,1,rep,name=asset_balances,json=assetBalances,proto3" json:"asset_balances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	AssetGroupBalances map[string]*AssetGroupBalance `protobuf:"bytes,2,rep,name=asset_group_balances,json=assetGroupBalances,proto3" json:"asset_group_balances,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ListBalancesResponse) Reset() {
+	*x = ListBalancesResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[30]
+		mi := &file_taprootassets_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DebugLevelRequest) String() string {
+func (x *ListBalancesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebugLevelRequest) ProtoMessage() {}
+func (*ListBalancesResponse) ProtoMessage() {}
 
-func (x *DebugLevelRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[30]
+func (x *ListBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2449,50 +2719,52 @@ func (x *DebugLevelRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebugLevelRequest.ProtoReflect.Descriptor instead.
-func (*DebugLevelRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use ListBalancesResponse.ProtoReflect.Descriptor instead.
+func (*ListBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *DebugLevelRequest) GetShow() bool {
+func (x *ListBalancesResponse) GetAssetBalances() map[string]*AssetBalance {
 	if x != nil {
-		return x.Show
+		return x.AssetBalances
 	}
-	return false
+	return nil
 }
 
-func (x *DebugLevelRequest) GetLevelSpec() string {
+func (x *ListBalancesResponse) GetAssetGroupBalances() map[string]*AssetGroupBalance {
 	if x != nil {
-		return x.LevelSpec
+		return x.AssetGroupBalances
 	}
-	return ""
+	return nil
 }
 
-type DebugLevelResponse struct {
+type RebuildBalancesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SubSystems string `protobuf:"bytes,1,opt,name=sub_systems,json=subSystems,proto3" json:"sub_systems,omitempty"`
+	// If set, the discrepancies are only reported, but the recorded assets
+	// aren't changed.
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 }
 
-func (x *DebugLevelResponse) Reset() {
-	*x = DebugLevelResponse{}
+func (x *RebuildBalancesRequest) Reset() {
+	*x = RebuildBalancesRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[31]
+		mi := &file_taprootassets_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DebugLevelResponse) String() string {
+func (x *RebuildBalancesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebugLevelResponse) ProtoMessage() {}
+func (*RebuildBalancesRequest) ProtoMessage() {}
 
-func (x *DebugLevelResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[31]
+func (x *RebuildBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2503,70 +2775,60 @@ func (x *DebugLevelResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebugLevelResponse.ProtoReflect.Descriptor instead.
-func (*DebugLevelResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use RebuildBalancesRequest.ProtoReflect.Descriptor instead.
+func (*RebuildBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *DebugLevelResponse) GetSubSystems() string {
+func (x *RebuildBalancesRequest) GetDryRun() bool {
 	if x != nil {
-		return x.SubSystems
+		return x.DryRun
 	}
-	return ""
+	return false
 }
 
-type Addr struct {
+type AssetBalanceDiscrepancy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The bech32 encoded Taproot Asset address.
-	Encoded string `protobuf:"bytes,1,opt,name=encoded,proto3" json:"encoded,omitempty"`
-	// The asset ID that uniquely identifies the asset.
+	// The type of the discrepancy. One of amount_mismatch, missing_proof,
+	// invalid_proof, anchor_mismatch or not_in_chain.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// The ID of the asset.
 	AssetId []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	// The type of the asset.
-	AssetType AssetType `protobuf:"varint,3,opt,name=asset_type,json=assetType,proto3,enum=taprpc.AssetType" json:"asset_type,omitempty"`
-	// The total amount of the asset stored in this Taproot Asset UTXO.
-	Amount uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
-	// The group key of the asset (if it exists)
-	GroupKey []byte `protobuf:"bytes,5,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
-	// The specific script key the asset must commit to in order to transfer
-	// ownership to the creator of the address.
-	ScriptKey []byte `protobuf:"bytes,6,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-	// The internal key used for the on-chain output.
-	InternalKey []byte `protobuf:"bytes,7,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
-	// The optional serialized tapscript sibling preimage to use for the receiving
-	// asset. This is usually empty as it is only needed when there should be an
-	// additional script path in the Taproot tree alongside the Taproot Asset
-	// commitment of the asset.
-	TapscriptSibling []byte `protobuf:"bytes,8,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
-	// The tweaked internal key that commits to the asset and represents the
-	// on-chain output key the Bitcoin transaction must send to in order to
-	// transfer assets described in this address.
-	TaprootOutputKey []byte `protobuf:"bytes,9,opt,name=taproot_output_key,json=taprootOutputKey,proto3" json:"taproot_output_key,omitempty"`
-	// The address of the proof courier service used in proof transfer.
-	ProofCourierAddr string `protobuf:"bytes,10,opt,name=proof_courier_addr,json=proofCourierAddr,proto3" json:"proof_courier_addr,omitempty"`
-	// The asset version of the address.
-	AssetVersion AssetVersion `protobuf:"varint,11,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
-}
-
-func (x *Addr) Reset() {
-	*x = Addr{}
+	// The script key of the asset.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The recorded anchor outpoint of the asset, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,4,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The amount of the asset that is recorded in the database.
+	IndexedAmount uint64 `protobuf:"varint,5,opt,name=indexed_amount,json=indexedAmount,proto3" json:"indexed_amount,omitempty"`
+	// The amount of the asset according to its proof. Only set if the proof
+	// could be decoded.
+	ProofAmount uint64 `protobuf:"varint,6,opt,name=proof_amount,json=proofAmount,proto3" json:"proof_amount,omitempty"`
+	// Whether the recorded asset was updated to match its proof.
+	Repaired bool `protobuf:"varint,7,opt,name=repaired,proto3" json:"repaired,omitempty"`
+	// A description of the discrepancy.
+	Details string `protobuf:"bytes,8,opt,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *AssetBalanceDiscrepancy) Reset() {
+	*x = AssetBalanceDiscrepancy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[32]
+		mi := &file_taprootassets_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Addr) String() string {
+func (x *AssetBalanceDiscrepancy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Addr) ProtoMessage() {}
+func (*AssetBalanceDiscrepancy) ProtoMessage() {}
 
-func (x *Addr) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[32]
+func (x *AssetBalanceDiscrepancy) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2577,107 +2839,148 @@ func (x *Addr) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Addr.ProtoReflect.Descriptor instead.
-func (*Addr) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use AssetBalanceDiscrepancy.ProtoReflect.Descriptor instead.
+func (*AssetBalanceDiscrepancy) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *Addr) GetEncoded() string {
+func (x *AssetBalanceDiscrepancy) GetType() string {
 	if x != nil {
-		return x.Encoded
+		return x.Type
 	}
 	return ""
 }
 
-func (x *Addr) GetAssetId() []byte {
+func (x *AssetBalanceDiscrepancy) GetAssetId() []byte {
 	if x != nil {
 		return x.AssetId
 	}
 	return nil
 }
 
-func (x *Addr) GetAssetType() AssetType {
+func (x *AssetBalanceDiscrepancy) GetScriptKey() []byte {
 	if x != nil {
-		return x.AssetType
+		return x.ScriptKey
 	}
-	return AssetType_NORMAL
+	return nil
 }
 
-func (x *Addr) GetAmount() uint64 {
+func (x *AssetBalanceDiscrepancy) GetAnchorOutpoint() string {
 	if x != nil {
-		return x.Amount
+		return x.AnchorOutpoint
 	}
-	return 0
+	return ""
 }
 
-func (x *Addr) GetGroupKey() []byte {
+func (x *AssetBalanceDiscrepancy) GetIndexedAmount() uint64 {
 	if x != nil {
-		return x.GroupKey
+		return x.IndexedAmount
 	}
-	return nil
+	return 0
 }
 
-func (x *Addr) GetScriptKey() []byte {
+func (x *AssetBalanceDiscrepancy) GetProofAmount() uint64 {
 	if x != nil {
-		return x.ScriptKey
+		return x.ProofAmount
 	}
-	return nil
+	return 0
 }
 
-func (x *Addr) GetInternalKey() []byte {
+func (x *AssetBalanceDiscrepancy) GetRepaired() bool {
 	if x != nil {
-		return x.InternalKey
+		return x.Repaired
 	}
-	return nil
+	return false
 }
 
-func (x *Addr) GetTapscriptSibling() []byte {
+func (x *AssetBalanceDiscrepancy) GetDetails() string {
 	if x != nil {
-		return x.TapscriptSibling
+		return x.Details
 	}
-	return nil
+	return ""
 }
 
-func (x *Addr) GetTaprootOutputKey() []byte {
+type BalanceChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the asset.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The balance of the asset ID before the rebuild.
+	OldBalance uint64 `protobuf:"varint,2,opt,name=old_balance,json=oldBalance,proto3" json:"old_balance,omitempty"`
+	// The balance of the asset ID after the rebuild.
+	NewBalance uint64 `protobuf:"varint,3,opt,name=new_balance,json=newBalance,proto3" json:"new_balance,omitempty"`
+}
+
+func (x *BalanceChange) Reset() {
+	*x = BalanceChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BalanceChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BalanceChange) ProtoMessage() {}
+
+func (x *BalanceChange) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BalanceChange.ProtoReflect.Descriptor instead.
+func (*BalanceChange) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *BalanceChange) GetAssetId() []byte {
 	if x != nil {
-		return x.TaprootOutputKey
+		return x.AssetId
 	}
 	return nil
 }
 
-func (x *Addr) GetProofCourierAddr() string {
+func (x *BalanceChange) GetOldBalance() uint64 {
 	if x != nil {
-		return x.ProofCourierAddr
+		return x.OldBalance
 	}
-	return ""
+	return 0
 }
 
-func (x *Addr) GetAssetVersion() AssetVersion {
+func (x *BalanceChange) GetNewBalance() uint64 {
 	if x != nil {
-		return x.AssetVersion
+		return x.NewBalance
 	}
-	return AssetVersion_ASSET_VERSION_V0
+	return 0
 }
 
-type QueryAddrRequest struct {
+type RebuildBalancesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// If set, then only addresses created after this Unix timestamp will be
-	// returned.
-	CreatedAfter int64 `protobuf:"varint,1,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
-	// If set, then only addresses created before this Unix timestamp will be
-	// returned.
-	CreatedBefore int64 `protobuf:"varint,2,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
-	// The max number of addresses that should be returned.
-	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	// The offset from the addresses that should be returned.
-	Offset int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// The number of owned, unspent assets that were checked.
+	NumAssets uint32 `protobuf:"varint,1,opt,name=num_assets,json=numAssets,proto3" json:"num_assets,omitempty"`
+	// The discrepancies found between the recorded assets and their proofs.
+	Discrepancies []*AssetBalanceDiscrepancy `protobuf:"bytes,2,rep,name=discrepancies,proto3" json:"discrepancies,omitempty"`
+	// The asset IDs whose balance changed by the rebuild.
+	BalanceChanges []*BalanceChange `protobuf:"bytes,3,rep,name=balance_changes,json=balanceChanges,proto3" json:"balance_changes,omitempty"`
 }
 
-func (x *QueryAddrRequest) Reset() {
-	*x = QueryAddrRequest{}
+func (x *RebuildBalancesResponse) Reset() {
+	*x = RebuildBalancesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2685,13 +2988,13 @@ func (x *QueryAddrRequest) Reset() {
 	}
 }
 
-func (x *QueryAddrRequest) String() string {
+func (x *RebuildBalancesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryAddrRequest) ProtoMessage() {}
+func (*RebuildBalancesResponse) ProtoMessage() {}
 
-func (x *QueryAddrRequest) ProtoReflect() protoreflect.Message {
+func (x *RebuildBalancesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2703,49 +3006,44 @@ func (x *QueryAddrRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryAddrRequest.ProtoReflect.Descriptor instead.
-func (*QueryAddrRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RebuildBalancesResponse.ProtoReflect.Descriptor instead.
+func (*RebuildBalancesResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *QueryAddrRequest) GetCreatedAfter() int64 {
-	if x != nil {
-		return x.CreatedAfter
-	}
-	return 0
-}
-
-func (x *QueryAddrRequest) GetCreatedBefore() int64 {
+func (x *RebuildBalancesResponse) GetNumAssets() uint32 {
 	if x != nil {
-		return x.CreatedBefore
+		return x.NumAssets
 	}
 	return 0
 }
 
-func (x *QueryAddrRequest) GetLimit() int32 {
+func (x *RebuildBalancesResponse) GetDiscrepancies() []*AssetBalanceDiscrepancy {
 	if x != nil {
-		return x.Limit
+		return x.Discrepancies
 	}
-	return 0
+	return nil
 }
 
-func (x *QueryAddrRequest) GetOffset() int32 {
+func (x *RebuildBalancesResponse) GetBalanceChanges() []*BalanceChange {
 	if x != nil {
-		return x.Offset
+		return x.BalanceChanges
 	}
-	return 0
+	return nil
 }
 
-type QueryAddrResponse struct {
+type ListTransfersRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Addrs []*Addr `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs,omitempty"`
+	// If set, only the transfers whose label contains this substring,
+	// ignoring case, are listed.
+	LabelFilter string `protobuf:"bytes,1,opt,name=label_filter,json=labelFilter,proto3" json:"label_filter,omitempty"`
 }
 
-func (x *QueryAddrResponse) Reset() {
-	*x = QueryAddrResponse{}
+func (x *ListTransfersRequest) Reset() {
+	*x = ListTransfersRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2753,13 +3051,13 @@ func (x *QueryAddrResponse) Reset() {
 	}
 }
 
-func (x *QueryAddrResponse) String() string {
+func (x *ListTransfersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryAddrResponse) ProtoMessage() {}
+func (*ListTransfersRequest) ProtoMessage() {}
 
-func (x *QueryAddrResponse) ProtoReflect() protoreflect.Message {
+func (x *ListTransfersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2771,53 +3069,29 @@ func (x *QueryAddrResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryAddrResponse.ProtoReflect.Descriptor instead.
-func (*QueryAddrResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListTransfersRequest.ProtoReflect.Descriptor instead.
+func (*ListTransfersRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *QueryAddrResponse) GetAddrs() []*Addr {
+func (x *ListTransfersRequest) GetLabelFilter() string {
 	if x != nil {
-		return x.Addrs
+		return x.LabelFilter
 	}
-	return nil
+	return ""
 }
 
-type NewAddrRequest struct {
+type ListTransfersResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	Amt     uint64 `protobuf:"varint,2,opt,name=amt,proto3" json:"amt,omitempty"`
-	// The optional script key that the receiving asset should be locked to. If no
-	// script key is provided, a normal BIP-86 key will be derived from the
-	// underlying wallet.
-	//
-	// NOTE: The script_key and internal_key fields should either both be set or
-	// both be empty.
-	ScriptKey *ScriptKey `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-	// The optional internal key of the receiving BTC level transaction output on
-	// which the receiving asset transfers will be committed to. If no internal key
-	// is provided, a key will be derived from the underlying wallet.
-	//
-	// NOTE: The script_key and internal_key fields should either both be set or
-	// both be empty.
-	InternalKey *KeyDescriptor `protobuf:"bytes,4,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
-	// The optional serialized tapscript sibling preimage to use for the receiving
-	// asset. This is usually empty as it is only needed when there should be an
-	// additional script path in the Taproot tree alongside the Taproot Asset
-	// commitment of the asset.
-	TapscriptSibling []byte `protobuf:"bytes,5,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
-	// An optional proof courier address for use in proof transfer. If unspecified,
-	// the daemon configured default address will be used.
-	ProofCourierAddr string `protobuf:"bytes,6,opt,name=proof_courier_addr,json=proofCourierAddr,proto3" json:"proof_courier_addr,omitempty"`
-	// The asset version to use when sending/receiving to/from this address.
-	AssetVersion AssetVersion `protobuf:"varint,7,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// The unordered list of outgoing asset transfers.
+	Transfers []*AssetTransfer `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
 }
 
-func (x *NewAddrRequest) Reset() {
-	*x = NewAddrRequest{}
+func (x *ListTransfersResponse) Reset() {
+	*x = ListTransfersResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2825,13 +3099,13 @@ func (x *NewAddrRequest) Reset() {
 	}
 }
 
-func (x *NewAddrRequest) String() string {
+func (x *ListTransfersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewAddrRequest) ProtoMessage() {}
+func (*ListTransfersResponse) ProtoMessage() {}
 
-func (x *NewAddrRequest) ProtoReflect() protoreflect.Message {
+func (x *ListTransfersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2843,93 +3117,97 @@ func (x *NewAddrRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewAddrRequest.ProtoReflect.Descriptor instead.
-func (*NewAddrRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListTransfersResponse.ProtoReflect.Descriptor instead.
+func (*ListTransfersResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *NewAddrRequest) GetAssetId() []byte {
+func (x *ListTransfersResponse) GetTransfers() []*AssetTransfer {
 	if x != nil {
-		return x.AssetId
+		return x.Transfers
 	}
 	return nil
 }
 
-func (x *NewAddrRequest) GetAmt() uint64 {
-	if x != nil {
-		return x.Amt
-	}
-	return 0
+type ListTransfersByScriptKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The 33-byte compressed or 32-byte x-only script key to list the
+	// transfers for.
+	ScriptKey []byte `protobuf:"bytes,1,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
 }
 
-func (x *NewAddrRequest) GetScriptKey() *ScriptKey {
-	if x != nil {
-		return x.ScriptKey
+func (x *ListTransfersByScriptKeyRequest) Reset() {
+	*x = ListTransfersByScriptKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *NewAddrRequest) GetInternalKey() *KeyDescriptor {
-	if x != nil {
-		return x.InternalKey
-	}
-	return nil
+func (x *ListTransfersByScriptKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *NewAddrRequest) GetTapscriptSibling() []byte {
-	if x != nil {
-		return x.TapscriptSibling
+func (*ListTransfersByScriptKeyRequest) ProtoMessage() {}
+
+func (x *ListTransfersByScriptKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *NewAddrRequest) GetProofCourierAddr() string {
-	if x != nil {
-		return x.ProofCourierAddr
-	}
-	return ""
+// Deprecated: Use ListTransfersByScriptKeyRequest.ProtoReflect.Descriptor instead.
+func (*ListTransfersByScriptKeyRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *NewAddrRequest) GetAssetVersion() AssetVersion {
+func (x *ListTransfersByScriptKeyRequest) GetScriptKey() []byte {
 	if x != nil {
-		return x.AssetVersion
+		return x.ScriptKey
 	}
-	return AssetVersion_ASSET_VERSION_V0
+	return nil
 }
 
-type ScriptKey struct {
+type ScriptKeyTransfer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The full Taproot output key the asset is locked to. This is either a BIP-86
-	// key if the tap_tweak below is empty, or a key with the tap tweak applied to
-	// it.
-	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
-	// The key descriptor describing the internal key of the above Taproot key.
-	KeyDesc *KeyDescriptor `protobuf:"bytes,2,opt,name=key_desc,json=keyDesc,proto3" json:"key_desc,omitempty"`
-	// The optional Taproot tweak to apply to the above internal key. If this is
-	// empty then a BIP-86 style tweak is applied to the internal key.
-	TapTweak []byte `protobuf:"bytes,3,opt,name=tap_tweak,json=tapTweak,proto3" json:"tap_tweak,omitempty"`
+	// Types that are assignable to Transfer:
+	//
+	//	*ScriptKeyTransfer_Outbound
+	//	*ScriptKeyTransfer_Inbound
+	//	*ScriptKeyTransfer_Received
+	Transfer isScriptKeyTransfer_Transfer `protobuf_oneof:"transfer"`
 }
 
-func (x *ScriptKey) Reset() {
-	*x = ScriptKey{}
+func (x *ScriptKeyTransfer) Reset() {
+	*x = ScriptKeyTransfer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[36]
+		mi := &file_taprootassets_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ScriptKey) String() string {
+func (x *ScriptKeyTransfer) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ScriptKey) ProtoMessage() {}
+func (*ScriptKeyTransfer) ProtoMessage() {}
 
-func (x *ScriptKey) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[36]
+func (x *ScriptKeyTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2940,60 +3218,94 @@ func (x *ScriptKey) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ScriptKey.ProtoReflect.Descriptor instead.
-func (*ScriptKey) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use ScriptKeyTransfer.ProtoReflect.Descriptor instead.
+func (*ScriptKeyTransfer) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ScriptKey) GetPubKey() []byte {
-	if x != nil {
-		return x.PubKey
+func (m *ScriptKeyTransfer) GetTransfer() isScriptKeyTransfer_Transfer {
+	if m != nil {
+		return m.Transfer
 	}
 	return nil
 }
 
-func (x *ScriptKey) GetKeyDesc() *KeyDescriptor {
-	if x != nil {
-		return x.KeyDesc
+func (x *ScriptKeyTransfer) GetOutbound() *AssetTransfer {
+	if x, ok := x.GetTransfer().(*ScriptKeyTransfer_Outbound); ok {
+		return x.Outbound
 	}
 	return nil
 }
 
-func (x *ScriptKey) GetTapTweak() []byte {
-	if x != nil {
-		return x.TapTweak
+func (x *ScriptKeyTransfer) GetInbound() *AddrEvent {
+	if x, ok := x.GetTransfer().(*ScriptKeyTransfer_Inbound); ok {
+		return x.Inbound
 	}
 	return nil
 }
 
-type KeyLocator struct {
+func (x *ScriptKeyTransfer) GetReceived() *Asset {
+	if x, ok := x.GetTransfer().(*ScriptKeyTransfer_Received); ok {
+		return x.Received
+	}
+	return nil
+}
+
+type isScriptKeyTransfer_Transfer interface {
+	isScriptKeyTransfer_Transfer()
+}
+
+type ScriptKeyTransfer_Outbound struct {
+	// An outbound transfer that spent an input with the script key or
+	// created an output with the script key.
+	Outbound *AssetTransfer `protobuf:"bytes,1,opt,name=outbound,proto3,oneof"`
+}
+
+type ScriptKeyTransfer_Inbound struct {
+	// An inbound transfer to an address that uses the script key.
+	Inbound *AddrEvent `protobuf:"bytes,2,opt,name=inbound,proto3,oneof"`
+}
+
+type ScriptKeyTransfer_Received struct {
+	// An asset locked to the script key that was received without an
+	// address, for example through an interactive transfer. The transfer
+	// is dated by the acquisition time of the asset.
+	Received *Asset `protobuf:"bytes,3,opt,name=received,proto3,oneof"`
+}
+
+func (*ScriptKeyTransfer_Outbound) isScriptKeyTransfer_Transfer() {}
+
+func (*ScriptKeyTransfer_Inbound) isScriptKeyTransfer_Transfer() {}
+
+func (*ScriptKeyTransfer_Received) isScriptKeyTransfer_Transfer() {}
+
+type ListTransfersByScriptKeyResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The family of key being identified.
-	KeyFamily int32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
-	// The precise index of the key being identified.
-	KeyIndex int32 `protobuf:"varint,2,opt,name=key_index,json=keyIndex,proto3" json:"key_index,omitempty"`
+	// The list of transfers involving the script key, ordered by the time
+	// they were first seen, oldest first.
+	Transfers []*ScriptKeyTransfer `protobuf:"bytes,1,rep,name=transfers,proto3" json:"transfers,omitempty"`
 }
 
-func (x *KeyLocator) Reset() {
-	*x = KeyLocator{}
+func (x *ListTransfersByScriptKeyResponse) Reset() {
+	*x = ListTransfersByScriptKeyResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[37]
+		mi := &file_taprootassets_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KeyLocator) String() string {
+func (x *ListTransfersByScriptKeyResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeyLocator) ProtoMessage() {}
+func (*ListTransfersByScriptKeyResponse) ProtoMessage() {}
 
-func (x *KeyLocator) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[37]
+func (x *ListTransfersByScriptKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3004,53 +3316,44 @@ func (x *KeyLocator) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeyLocator.ProtoReflect.Descriptor instead.
-func (*KeyLocator) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{37}
-}
-
-func (x *KeyLocator) GetKeyFamily() int32 {
-	if x != nil {
-		return x.KeyFamily
-	}
-	return 0
+// Deprecated: Use ListTransfersByScriptKeyResponse.ProtoReflect.Descriptor instead.
+func (*ListTransfersByScriptKeyResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *KeyLocator) GetKeyIndex() int32 {
+func (x *ListTransfersByScriptKeyResponse) GetTransfers() []*ScriptKeyTransfer {
 	if x != nil {
-		return x.KeyIndex
+		return x.Transfers
 	}
-	return 0
+	return nil
 }
 
-type KeyDescriptor struct {
+type GetTransferGraphRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The raw bytes of the key being identified.
-	RawKeyBytes []byte `protobuf:"bytes,1,opt,name=raw_key_bytes,json=rawKeyBytes,proto3" json:"raw_key_bytes,omitempty"`
-	// The key locator that identifies which key to use for signing.
-	KeyLoc *KeyLocator `protobuf:"bytes,2,opt,name=key_loc,json=keyLoc,proto3" json:"key_loc,omitempty"`
+	// The hash of the anchor transaction of the transfer.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
 }
 
-func (x *KeyDescriptor) Reset() {
-	*x = KeyDescriptor{}
+func (x *GetTransferGraphRequest) Reset() {
+	*x = GetTransferGraphRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[38]
+		mi := &file_taprootassets_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *KeyDescriptor) String() string {
+func (x *GetTransferGraphRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeyDescriptor) ProtoMessage() {}
+func (*GetTransferGraphRequest) ProtoMessage() {}
 
-func (x *KeyDescriptor) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[38]
+func (x *GetTransferGraphRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3061,50 +3364,50 @@ func (x *KeyDescriptor) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeyDescriptor.ProtoReflect.Descriptor instead.
-func (*KeyDescriptor) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{38}
-}
-
-func (x *KeyDescriptor) GetRawKeyBytes() []byte {
-	if x != nil {
-		return x.RawKeyBytes
-	}
-	return nil
+// Deprecated: Use GetTransferGraphRequest.ProtoReflect.Descriptor instead.
+func (*GetTransferGraphRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *KeyDescriptor) GetKeyLoc() *KeyLocator {
+func (x *GetTransferGraphRequest) GetAnchorTxid() string {
 	if x != nil {
-		return x.KeyLoc
+		return x.AnchorTxid
 	}
-	return nil
+	return ""
 }
 
-type DecodeAddrRequest struct {
+type TransferEdge struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Addr string `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	// The index of the input within the inputs of the transfer.
+	InputIndex uint32 `protobuf:"varint,1,opt,name=input_index,json=inputIndex,proto3" json:"input_index,omitempty"`
+	// The index of the output within the outputs of the transfer.
+	OutputIndex uint32 `protobuf:"varint,2,opt,name=output_index,json=outputIndex,proto3" json:"output_index,omitempty"`
+	// The amount of the input that went into the output. This is zero for
+	// outputs that don't carry any value, like tombstones, which are linked
+	// to every input of their asset ID.
+	Amount uint64 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
 }
 
-func (x *DecodeAddrRequest) Reset() {
-	*x = DecodeAddrRequest{}
+func (x *TransferEdge) Reset() {
+	*x = TransferEdge{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[39]
+		mi := &file_taprootassets_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DecodeAddrRequest) String() string {
+func (x *TransferEdge) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DecodeAddrRequest) ProtoMessage() {}
+func (*TransferEdge) ProtoMessage() {}
 
-func (x *DecodeAddrRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[39]
+func (x *TransferEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3115,46 +3418,61 @@ func (x *DecodeAddrRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DecodeAddrRequest.ProtoReflect.Descriptor instead.
-func (*DecodeAddrRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use TransferEdge.ProtoReflect.Descriptor instead.
+func (*TransferEdge) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *DecodeAddrRequest) GetAddr() string {
+func (x *TransferEdge) GetInputIndex() uint32 {
 	if x != nil {
-		return x.Addr
+		return x.InputIndex
 	}
-	return ""
+	return 0
 }
 
-type ProofFile struct {
+func (x *TransferEdge) GetOutputIndex() uint32 {
+	if x != nil {
+		return x.OutputIndex
+	}
+	return 0
+}
+
+func (x *TransferEdge) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type GetTransferGraphResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The raw proof file encoded as bytes. Must be a file and not just an
-	// individual mint/transfer proof.
-	RawProofFile []byte `protobuf:"bytes,1,opt,name=raw_proof_file,json=rawProofFile,proto3" json:"raw_proof_file,omitempty"`
-	GenesisPoint string `protobuf:"bytes,2,opt,name=genesis_point,json=genesisPoint,proto3" json:"genesis_point,omitempty"`
+	// The transfer the graph was built for.
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// The edges from the inputs of the transfer to the outputs they
+	// contributed to. Outputs that only carry passive assets have no edges.
+	Edges []*TransferEdge `protobuf:"bytes,2,rep,name=edges,proto3" json:"edges,omitempty"`
 }
 
-func (x *ProofFile) Reset() {
-	*x = ProofFile{}
+func (x *GetTransferGraphResponse) Reset() {
+	*x = GetTransferGraphResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[40]
+		mi := &file_taprootassets_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ProofFile) String() string {
+func (x *GetTransferGraphResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProofFile) ProtoMessage() {}
+func (*GetTransferGraphResponse) ProtoMessage() {}
 
-func (x *ProofFile) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[40]
+func (x *GetTransferGraphResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3165,92 +3483,65 @@ func (x *ProofFile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProofFile.ProtoReflect.Descriptor instead.
-func (*ProofFile) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use GetTransferGraphResponse.ProtoReflect.Descriptor instead.
+func (*GetTransferGraphResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *ProofFile) GetRawProofFile() []byte {
+func (x *GetTransferGraphResponse) GetTransfer() *AssetTransfer {
 	if x != nil {
-		return x.RawProofFile
+		return x.Transfer
 	}
 	return nil
 }
 
-func (x *ProofFile) GetGenesisPoint() string {
+func (x *GetTransferGraphResponse) GetEdges() []*TransferEdge {
 	if x != nil {
-		return x.GenesisPoint
+		return x.Edges
 	}
-	return ""
+	return nil
 }
 
-type DecodedProof struct {
+type AssetTransfer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The index depth of the decoded proof, with 0 being the latest proof.
-	ProofAtDepth uint32 `protobuf:"varint,1,opt,name=proof_at_depth,json=proofAtDepth,proto3" json:"proof_at_depth,omitempty"`
-	// The total number of proofs contained in the decoded proof file (this will
-	// always be 1 if a single mint/transition proof was given as the raw_proof
-	// instead of a file).
-	NumberOfProofs uint32 `protobuf:"varint,2,opt,name=number_of_proofs,json=numberOfProofs,proto3" json:"number_of_proofs,omitempty"`
-	// The asset referenced in the proof.
-	Asset *Asset `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
-	// The reveal meta data associated with the proof, if available.
-	MetaReveal *AssetMeta `protobuf:"bytes,4,opt,name=meta_reveal,json=metaReveal,proto3" json:"meta_reveal,omitempty"`
-	// The merkle proof for AnchorTx used to prove its
-	// inclusion within BlockHeader.
-	TxMerkleProof []byte `protobuf:"bytes,5,opt,name=tx_merkle_proof,json=txMerkleProof,proto3" json:"tx_merkle_proof,omitempty"`
-	// The TaprootProof proving the new inclusion of the
-	// resulting asset within AnchorTx.
-	InclusionProof []byte `protobuf:"bytes,6,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
-	// The set of TaprootProofs proving the exclusion of
-	// the resulting asset from all other Taproot outputs within AnchorTx.
-	ExclusionProofs [][]byte `protobuf:"bytes,7,rep,name=exclusion_proofs,json=exclusionProofs,proto3" json:"exclusion_proofs,omitempty"`
-	// An optional TaprootProof needed if this asset is
-	// the result of a split. SplitRootProof proves inclusion of the root
-	// asset of the split.
-	SplitRootProof []byte `protobuf:"bytes,8,opt,name=split_root_proof,json=splitRootProof,proto3" json:"split_root_proof,omitempty"`
-	// The number of additional nested full proofs for any inputs found within
-	// the resulting asset.
-	NumAdditionalInputs uint32 `protobuf:"varint,9,opt,name=num_additional_inputs,json=numAdditionalInputs,proto3" json:"num_additional_inputs,omitempty"`
-	// ChallengeWitness is an optional virtual transaction witness that serves
-	// as an ownership proof for the asset. If this is non-nil, then it is a
-	// valid transfer witness for a 1-input, 1-output virtual transaction that
-	// spends the asset in this proof and sends it to the NUMS key, to prove
-	// that the creator of the proof is able to produce a valid signature to
-	// spend the asset.
-	ChallengeWitness [][]byte `protobuf:"bytes,10,rep,name=challenge_witness,json=challengeWitness,proto3" json:"challenge_witness,omitempty"`
-	// Indicates whether the state transition this proof represents is a burn,
-	// meaning that the assets were provably destroyed and can no longer be
-	// spent.
-	IsBurn bool `protobuf:"varint,11,opt,name=is_burn,json=isBurn,proto3" json:"is_burn,omitempty"`
-	// GenesisReveal is an optional field that is the Genesis information for
-	// the asset. This is required for minting proofs.
-	GenesisReveal *GenesisReveal `protobuf:"bytes,12,opt,name=genesis_reveal,json=genesisReveal,proto3" json:"genesis_reveal,omitempty"`
-	// GroupKeyReveal is an optional field that includes the information needed
-	// to derive the tweaked group key.
-	GroupKeyReveal *GroupKeyReveal `protobuf:"bytes,13,opt,name=group_key_reveal,json=groupKeyReveal,proto3" json:"group_key_reveal,omitempty"`
+	TransferTimestamp int64 `protobuf:"varint,1,opt,name=transfer_timestamp,json=transferTimestamp,proto3" json:"transfer_timestamp,omitempty"`
+	// The new transaction that commits to the set of Taproot Assets found
+	// at the above new anchor point.
+	AnchorTxHash       []byte `protobuf:"bytes,2,opt,name=anchor_tx_hash,json=anchorTxHash,proto3" json:"anchor_tx_hash,omitempty"`
+	AnchorTxHeightHint uint32 `protobuf:"varint,3,opt,name=anchor_tx_height_hint,json=anchorTxHeightHint,proto3" json:"anchor_tx_height_hint,omitempty"`
+	AnchorTxChainFees  int64  `protobuf:"varint,4,opt,name=anchor_tx_chain_fees,json=anchorTxChainFees,proto3" json:"anchor_tx_chain_fees,omitempty"`
+	// Describes the set of spent assets.
+	Inputs []*TransferInput `protobuf:"bytes,5,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// Describes the set of newly created asset outputs.
+	Outputs []*TransferOutput `protobuf:"bytes,6,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	// Indicates that the anchor transaction was signed and persisted but
+	// not yet broadcast, because the transfer is waiting to be released with
+	// BroadcastTransfer.
+	Staged bool `protobuf:"varint,7,opt,name=staged,proto3" json:"staged,omitempty"`
+	// The human-readable label the transfer was requested with, if any.
+	Label string `protobuf:"bytes,8,opt,name=label,proto3" json:"label,omitempty"`
 }
 
-func (x *DecodedProof) Reset() {
-	*x = DecodedProof{}
+func (x *AssetTransfer) Reset() {
+	*x = AssetTransfer{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[41]
+		mi := &file_taprootassets_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DecodedProof) String() string {
+func (x *AssetTransfer) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DecodedProof) ProtoMessage() {}
+func (*AssetTransfer) ProtoMessage() {}
 
-func (x *DecodedProof) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[41]
+func (x *AssetTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3261,129 +3552,100 @@ func (x *DecodedProof) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DecodedProof.ProtoReflect.Descriptor instead.
-func (*DecodedProof) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{41}
-}
-
-func (x *DecodedProof) GetProofAtDepth() uint32 {
-	if x != nil {
-		return x.ProofAtDepth
-	}
-	return 0
+// Deprecated: Use AssetTransfer.ProtoReflect.Descriptor instead.
+func (*AssetTransfer) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *DecodedProof) GetNumberOfProofs() uint32 {
+func (x *AssetTransfer) GetTransferTimestamp() int64 {
 	if x != nil {
-		return x.NumberOfProofs
+		return x.TransferTimestamp
 	}
 	return 0
 }
 
-func (x *DecodedProof) GetAsset() *Asset {
-	if x != nil {
-		return x.Asset
-	}
-	return nil
-}
-
-func (x *DecodedProof) GetMetaReveal() *AssetMeta {
-	if x != nil {
-		return x.MetaReveal
-	}
-	return nil
-}
-
-func (x *DecodedProof) GetTxMerkleProof() []byte {
+func (x *AssetTransfer) GetAnchorTxHash() []byte {
 	if x != nil {
-		return x.TxMerkleProof
+		return x.AnchorTxHash
 	}
 	return nil
 }
 
-func (x *DecodedProof) GetInclusionProof() []byte {
+func (x *AssetTransfer) GetAnchorTxHeightHint() uint32 {
 	if x != nil {
-		return x.InclusionProof
+		return x.AnchorTxHeightHint
 	}
-	return nil
+	return 0
 }
 
-func (x *DecodedProof) GetExclusionProofs() [][]byte {
+func (x *AssetTransfer) GetAnchorTxChainFees() int64 {
 	if x != nil {
-		return x.ExclusionProofs
+		return x.AnchorTxChainFees
 	}
-	return nil
+	return 0
 }
 
-func (x *DecodedProof) GetSplitRootProof() []byte {
+func (x *AssetTransfer) GetInputs() []*TransferInput {
 	if x != nil {
-		return x.SplitRootProof
+		return x.Inputs
 	}
 	return nil
 }
 
-func (x *DecodedProof) GetNumAdditionalInputs() uint32 {
-	if x != nil {
-		return x.NumAdditionalInputs
-	}
-	return 0
-}
-
-func (x *DecodedProof) GetChallengeWitness() [][]byte {
+func (x *AssetTransfer) GetOutputs() []*TransferOutput {
 	if x != nil {
-		return x.ChallengeWitness
+		return x.Outputs
 	}
 	return nil
 }
 
-func (x *DecodedProof) GetIsBurn() bool {
+func (x *AssetTransfer) GetStaged() bool {
 	if x != nil {
-		return x.IsBurn
+		return x.Staged
 	}
 	return false
 }
 
-func (x *DecodedProof) GetGenesisReveal() *GenesisReveal {
-	if x != nil {
-		return x.GenesisReveal
-	}
-	return nil
-}
-
-func (x *DecodedProof) GetGroupKeyReveal() *GroupKeyReveal {
+func (x *AssetTransfer) GetLabel() string {
 	if x != nil {
-		return x.GroupKeyReveal
+		return x.Label
 	}
-	return nil
+	return ""
 }
 
-type VerifyProofResponse struct {
+type TransferInput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	// The decoded last proof in the file if the proof file was valid.
-	DecodedProof *DecodedProof `protobuf:"bytes,2,opt,name=decoded_proof,json=decodedProof,proto3" json:"decoded_proof,omitempty"`
+	// The old/current location of the Taproot Asset commitment that was spent
+	// as an input.
+	AnchorPoint string `protobuf:"bytes,1,opt,name=anchor_point,json=anchorPoint,proto3" json:"anchor_point,omitempty"`
+	// The ID of the asset that was spent.
+	AssetId []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key of the asset that was spent.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The amount of the asset that was spent.
+	Amount uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
 }
 
-func (x *VerifyProofResponse) Reset() {
-	*x = VerifyProofResponse{}
+func (x *TransferInput) Reset() {
+	*x = TransferInput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[42]
+		mi := &file_taprootassets_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *VerifyProofResponse) String() string {
+func (x *TransferInput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyProofResponse) ProtoMessage() {}
+func (*TransferInput) ProtoMessage() {}
 
-func (x *VerifyProofResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[42]
+func (x *TransferInput) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3394,62 +3656,72 @@ func (x *VerifyProofResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyProofResponse.ProtoReflect.Descriptor instead.
-func (*VerifyProofResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use TransferInput.ProtoReflect.Descriptor instead.
+func (*TransferInput) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *VerifyProofResponse) GetValid() bool {
+func (x *TransferInput) GetAnchorPoint() string {
 	if x != nil {
-		return x.Valid
+		return x.AnchorPoint
 	}
-	return false
+	return ""
 }
 
-func (x *VerifyProofResponse) GetDecodedProof() *DecodedProof {
+func (x *TransferInput) GetAssetId() []byte {
 	if x != nil {
-		return x.DecodedProof
+		return x.AssetId
 	}
 	return nil
 }
 
-type DecodeProofRequest struct {
+func (x *TransferInput) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *TransferInput) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type TransferOutputAnchor struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The raw proof bytes to decode. This can be a full proof file or a single
-	// mint/transition proof. If it is a full proof file, the proof_at_depth
-	// field will be used to determine which individual proof within the file to
-	// decode.
-	RawProof []byte `protobuf:"bytes,1,opt,name=raw_proof,json=rawProof,proto3" json:"raw_proof,omitempty"`
-	// The index depth of the decoded proof, with 0 being the latest proof. This
-	// is ignored if the raw_proof is a single mint/transition proof and not a
-	// proof file.
-	ProofAtDepth uint32 `protobuf:"varint,2,opt,name=proof_at_depth,json=proofAtDepth,proto3" json:"proof_at_depth,omitempty"`
-	// An option to include previous witnesses in decoding.
-	WithPrevWitnesses bool `protobuf:"varint,3,opt,name=with_prev_witnesses,json=withPrevWitnesses,proto3" json:"with_prev_witnesses,omitempty"`
-	// An option to attempt to retrieve the meta data associated with the proof.
-	WithMetaReveal bool `protobuf:"varint,4,opt,name=with_meta_reveal,json=withMetaReveal,proto3" json:"with_meta_reveal,omitempty"`
+	// The new location of the Taproot Asset commitment that was created on
+	// chain.
+	Outpoint         string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	Value            int64  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	InternalKey      []byte `protobuf:"bytes,3,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	TaprootAssetRoot []byte `protobuf:"bytes,4,opt,name=taproot_asset_root,json=taprootAssetRoot,proto3" json:"taproot_asset_root,omitempty"`
+	MerkleRoot       []byte `protobuf:"bytes,5,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	TapscriptSibling []byte `protobuf:"bytes,6,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
+	NumPassiveAssets uint32 `protobuf:"varint,7,opt,name=num_passive_assets,json=numPassiveAssets,proto3" json:"num_passive_assets,omitempty"`
 }
 
-func (x *DecodeProofRequest) Reset() {
-	*x = DecodeProofRequest{}
+func (x *TransferOutputAnchor) Reset() {
+	*x = TransferOutputAnchor{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[43]
+		mi := &file_taprootassets_proto_msgTypes[44]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DecodeProofRequest) String() string {
+func (x *TransferOutputAnchor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DecodeProofRequest) ProtoMessage() {}
+func (*TransferOutputAnchor) ProtoMessage() {}
 
-func (x *DecodeProofRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[43]
+func (x *TransferOutputAnchor) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[44]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3460,64 +3732,100 @@ func (x *DecodeProofRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DecodeProofRequest.ProtoReflect.Descriptor instead.
-func (*DecodeProofRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use TransferOutputAnchor.ProtoReflect.Descriptor instead.
+func (*TransferOutputAnchor) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *DecodeProofRequest) GetRawProof() []byte {
+func (x *TransferOutputAnchor) GetOutpoint() string {
 	if x != nil {
-		return x.RawProof
+		return x.Outpoint
 	}
-	return nil
+	return ""
 }
 
-func (x *DecodeProofRequest) GetProofAtDepth() uint32 {
+func (x *TransferOutputAnchor) GetValue() int64 {
 	if x != nil {
-		return x.ProofAtDepth
+		return x.Value
 	}
 	return 0
 }
 
-func (x *DecodeProofRequest) GetWithPrevWitnesses() bool {
+func (x *TransferOutputAnchor) GetInternalKey() []byte {
 	if x != nil {
-		return x.WithPrevWitnesses
+		return x.InternalKey
 	}
-	return false
+	return nil
 }
 
-func (x *DecodeProofRequest) GetWithMetaReveal() bool {
+func (x *TransferOutputAnchor) GetTaprootAssetRoot() []byte {
 	if x != nil {
-		return x.WithMetaReveal
+		return x.TaprootAssetRoot
 	}
-	return false
+	return nil
 }
 
-type DecodeProofResponse struct {
+func (x *TransferOutputAnchor) GetMerkleRoot() []byte {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return nil
+}
+
+func (x *TransferOutputAnchor) GetTapscriptSibling() []byte {
+	if x != nil {
+		return x.TapscriptSibling
+	}
+	return nil
+}
+
+func (x *TransferOutputAnchor) GetNumPassiveAssets() uint32 {
+	if x != nil {
+		return x.NumPassiveAssets
+	}
+	return 0
+}
+
+type TransferOutput struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	DecodedProof *DecodedProof `protobuf:"bytes,1,opt,name=decoded_proof,json=decodedProof,proto3" json:"decoded_proof,omitempty"`
+	Anchor           *TransferOutputAnchor `protobuf:"bytes,1,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	ScriptKey        []byte                `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	ScriptKeyIsLocal bool                  `protobuf:"varint,3,opt,name=script_key_is_local,json=scriptKeyIsLocal,proto3" json:"script_key_is_local,omitempty"`
+	Amount           uint64                `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The new individual transition proof (not a full proof file) that proves
+	// the inclusion of the new asset within the new AnchorTx.
+	NewProofBlob        []byte       `protobuf:"bytes,5,opt,name=new_proof_blob,json=newProofBlob,proto3" json:"new_proof_blob,omitempty"`
+	SplitCommitRootHash []byte       `protobuf:"bytes,6,opt,name=split_commit_root_hash,json=splitCommitRootHash,proto3" json:"split_commit_root_hash,omitempty"`
+	OutputType          OutputType   `protobuf:"varint,7,opt,name=output_type,json=outputType,proto3,enum=taprpc.OutputType" json:"output_type,omitempty"`
+	AssetVersion        AssetVersion `protobuf:"varint,8,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// The record of the delivery of the output's proof to its receiver.
+	ProofDelivery *ProofDelivery `protobuf:"bytes,9,opt,name=proof_delivery,json=proofDelivery,proto3" json:"proof_delivery,omitempty"`
+	// The ID of the asset transferred to this output. A single transfer can
+	// send multiple assets in the same anchor transaction, in which case the
+	// outputs can be grouped by this ID.
+	AssetId []byte `protobuf:"bytes,10,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
 }
 
-func (x *DecodeProofResponse) Reset() {
-	*x = DecodeProofResponse{}
+func (x *TransferOutput) Reset() {
+	*x = TransferOutput{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[44]
+		mi := &file_taprootassets_proto_msgTypes[45]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DecodeProofResponse) String() string {
+func (x *TransferOutput) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DecodeProofResponse) ProtoMessage() {}
+func (*TransferOutput) ProtoMessage() {}
 
-func (x *DecodeProofResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[44]
+func (x *TransferOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[45]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3528,102 +3836,102 @@ func (x *DecodeProofResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DecodeProofResponse.ProtoReflect.Descriptor instead.
-func (*DecodeProofResponse) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use TransferOutput.ProtoReflect.Descriptor instead.
+func (*TransferOutput) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *DecodeProofResponse) GetDecodedProof() *DecodedProof {
+func (x *TransferOutput) GetAnchor() *TransferOutputAnchor {
 	if x != nil {
-		return x.DecodedProof
+		return x.Anchor
 	}
 	return nil
 }
 
-type ExportProofRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *TransferOutput) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
 
-	AssetId   []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+func (x *TransferOutput) GetScriptKeyIsLocal() bool {
+	if x != nil {
+		return x.ScriptKeyIsLocal
+	}
+	return false
 }
 
-func (x *ExportProofRequest) Reset() {
-	*x = ExportProofRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[45]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *TransferOutput) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
 	}
+	return 0
 }
 
-func (x *ExportProofRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *TransferOutput) GetNewProofBlob() []byte {
+	if x != nil {
+		return x.NewProofBlob
+	}
+	return nil
 }
 
-func (*ExportProofRequest) ProtoMessage() {}
+func (x *TransferOutput) GetSplitCommitRootHash() []byte {
+	if x != nil {
+		return x.SplitCommitRootHash
+	}
+	return nil
+}
 
-func (x *ExportProofRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[45]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *TransferOutput) GetOutputType() OutputType {
+	if x != nil {
+		return x.OutputType
 	}
-	return mi.MessageOf(x)
+	return OutputType_OUTPUT_TYPE_SIMPLE
 }
 
-// Deprecated: Use ExportProofRequest.ProtoReflect.Descriptor instead.
-func (*ExportProofRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{45}
+func (x *TransferOutput) GetAssetVersion() AssetVersion {
+	if x != nil {
+		return x.AssetVersion
+	}
+	return AssetVersion_ASSET_VERSION_V0
 }
 
-func (x *ExportProofRequest) GetAssetId() []byte {
+func (x *TransferOutput) GetProofDelivery() *ProofDelivery {
 	if x != nil {
-		return x.AssetId
+		return x.ProofDelivery
 	}
 	return nil
 }
 
-func (x *ExportProofRequest) GetScriptKey() []byte {
+func (x *TransferOutput) GetAssetId() []byte {
 	if x != nil {
-		return x.ScriptKey
+		return x.AssetId
 	}
 	return nil
 }
 
-type AddrEvent struct {
+type ProofDelivery struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The time the event was created in unix timestamp seconds.
-	CreationTimeUnixSeconds uint64 `protobuf:"varint,1,opt,name=creation_time_unix_seconds,json=creationTimeUnixSeconds,proto3" json:"creation_time_unix_seconds,omitempty"`
-	// The address the event was created for.
-	Addr *Addr `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	// The current status of the event.
-	Status AddrEventStatus `protobuf:"varint,3,opt,name=status,proto3,enum=taprpc.AddrEventStatus" json:"status,omitempty"`
-	// The outpoint that contains the inbound asset transfer.
-	Outpoint string `protobuf:"bytes,4,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
-	// The amount in satoshis that were transferred on chain along with the asset.
-	// This amount is independent of the requested asset amount, which can be
-	// looked up on the address.
-	UtxoAmtSat uint64 `protobuf:"varint,5,opt,name=utxo_amt_sat,json=utxoAmtSat,proto3" json:"utxo_amt_sat,omitempty"`
-	// The taproot sibling hash that was used to send to the Taproot output.
-	TaprootSibling []byte `protobuf:"bytes,6,opt,name=taproot_sibling,json=taprootSibling,proto3" json:"taproot_sibling,omitempty"`
-	// The height at which the on-chain output was confirmed. If this is zero, it
-	// means the output is unconfirmed.
-	ConfirmationHeight uint32 `protobuf:"varint,7,opt,name=confirmation_height,json=confirmationHeight,proto3" json:"confirmation_height,omitempty"`
-	// Indicates whether a proof file can be found for the address' asset ID and
-	// script key.
-	HasProof bool `protobuf:"varint,8,opt,name=has_proof,json=hasProof,proto3" json:"has_proof,omitempty"`
-}
-
-func (x *AddrEvent) Reset() {
-	*x = AddrEvent{}
+	// The outcome of the delivery.
+	Status ProofDeliveryStatus `protobuf:"varint,1,opt,name=status,proto3,enum=taprpc.ProofDeliveryStatus" json:"status,omitempty"`
+	// The type of the proof courier that delivered the proof, for example
+	// hashmail or universerpc. Empty if no courier was used.
+	CourierType string `protobuf:"bytes,2,opt,name=courier_type,json=courierType,proto3" json:"courier_type,omitempty"`
+	// The address of the proof courier that delivered the proof. Empty if no
+	// courier was used.
+	CourierAddr string `protobuf:"bytes,3,opt,name=courier_addr,json=courierAddr,proto3" json:"courier_addr,omitempty"`
+	// The number of attempts the proof courier made to deliver the proof.
+	NumAttempts uint32 `protobuf:"varint,4,opt,name=num_attempts,json=numAttempts,proto3" json:"num_attempts,omitempty"`
+	// Whether the receiver returned a signed delivery receipt for the proof.
+	ReceiptReceived bool `protobuf:"varint,5,opt,name=receipt_received,json=receiptReceived,proto3" json:"receipt_received,omitempty"`
+}
+
+func (x *ProofDelivery) Reset() {
+	*x = ProofDelivery{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[46]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3631,13 +3939,13 @@ func (x *AddrEvent) Reset() {
 	}
 }
 
-func (x *AddrEvent) String() string {
+func (x *ProofDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddrEvent) ProtoMessage() {}
+func (*ProofDelivery) ProtoMessage() {}
 
-func (x *AddrEvent) ProtoReflect() protoreflect.Message {
+func (x *ProofDelivery) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[46]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3649,80 +3957,54 @@ func (x *AddrEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddrEvent.ProtoReflect.Descriptor instead.
-func (*AddrEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProofDelivery.ProtoReflect.Descriptor instead.
+func (*ProofDelivery) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *AddrEvent) GetCreationTimeUnixSeconds() uint64 {
-	if x != nil {
-		return x.CreationTimeUnixSeconds
-	}
-	return 0
-}
-
-func (x *AddrEvent) GetAddr() *Addr {
-	if x != nil {
-		return x.Addr
-	}
-	return nil
-}
-
-func (x *AddrEvent) GetStatus() AddrEventStatus {
+func (x *ProofDelivery) GetStatus() ProofDeliveryStatus {
 	if x != nil {
 		return x.Status
 	}
-	return AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN
+	return ProofDeliveryStatus_PROOF_DELIVERY_STATUS_NONE
 }
 
-func (x *AddrEvent) GetOutpoint() string {
+func (x *ProofDelivery) GetCourierType() string {
 	if x != nil {
-		return x.Outpoint
+		return x.CourierType
 	}
 	return ""
 }
 
-func (x *AddrEvent) GetUtxoAmtSat() uint64 {
-	if x != nil {
-		return x.UtxoAmtSat
-	}
-	return 0
-}
-
-func (x *AddrEvent) GetTaprootSibling() []byte {
+func (x *ProofDelivery) GetCourierAddr() string {
 	if x != nil {
-		return x.TaprootSibling
+		return x.CourierAddr
 	}
-	return nil
+	return ""
 }
 
-func (x *AddrEvent) GetConfirmationHeight() uint32 {
+func (x *ProofDelivery) GetNumAttempts() uint32 {
 	if x != nil {
-		return x.ConfirmationHeight
+		return x.NumAttempts
 	}
 	return 0
 }
 
-func (x *AddrEvent) GetHasProof() bool {
+func (x *ProofDelivery) GetReceiptReceived() bool {
 	if x != nil {
-		return x.HasProof
+		return x.ReceiptReceived
 	}
 	return false
 }
 
-type AddrReceivesRequest struct {
+type StopRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// Filter receives by a specific address. Leave empty to get all receives.
-	FilterAddr string `protobuf:"bytes,1,opt,name=filter_addr,json=filterAddr,proto3" json:"filter_addr,omitempty"`
-	// Filter receives by a specific status. Leave empty to get all receives.
-	FilterStatus AddrEventStatus `protobuf:"varint,2,opt,name=filter_status,json=filterStatus,proto3,enum=taprpc.AddrEventStatus" json:"filter_status,omitempty"`
 }
 
-func (x *AddrReceivesRequest) Reset() {
-	*x = AddrReceivesRequest{}
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[47]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3730,13 +4012,13 @@ func (x *AddrReceivesRequest) Reset() {
 	}
 }
 
-func (x *AddrReceivesRequest) String() string {
+func (x *StopRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddrReceivesRequest) ProtoMessage() {}
+func (*StopRequest) ProtoMessage() {}
 
-func (x *AddrReceivesRequest) ProtoReflect() protoreflect.Message {
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[47]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3748,36 +4030,19 @@ func (x *AddrReceivesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddrReceivesRequest.ProtoReflect.Descriptor instead.
-func (*AddrReceivesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *AddrReceivesRequest) GetFilterAddr() string {
-	if x != nil {
-		return x.FilterAddr
-	}
-	return ""
-}
-
-func (x *AddrReceivesRequest) GetFilterStatus() AddrEventStatus {
-	if x != nil {
-		return x.FilterStatus
-	}
-	return AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN
-}
-
-type AddrReceivesResponse struct {
+type StopResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The events that match the filter criteria.
-	Events []*AddrEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
 }
 
-func (x *AddrReceivesResponse) Reset() {
-	*x = AddrReceivesResponse{}
+func (x *StopResponse) Reset() {
+	*x = StopResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[48]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3785,13 +4050,13 @@ func (x *AddrReceivesResponse) Reset() {
 	}
 }
 
-func (x *AddrReceivesResponse) String() string {
+func (x *StopResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddrReceivesResponse) ProtoMessage() {}
+func (*StopResponse) ProtoMessage() {}
 
-func (x *AddrReceivesResponse) ProtoReflect() protoreflect.Message {
+func (x *StopResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[48]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3803,28 +4068,23 @@ func (x *AddrReceivesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddrReceivesResponse.ProtoReflect.Descriptor instead.
-func (*AddrReceivesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
+func (*StopResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *AddrReceivesResponse) GetEvents() []*AddrEvent {
-	if x != nil {
-		return x.Events
-	}
-	return nil
-}
-
-type SendAssetRequest struct {
+type DebugLevelRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TapAddrs []string `protobuf:"bytes,1,rep,name=tap_addrs,json=tapAddrs,proto3" json:"tap_addrs,omitempty"`
+	// If true, all the valid debug sub-systems will be returned.
+	Show      bool   `protobuf:"varint,1,opt,name=show,proto3" json:"show,omitempty"`
+	LevelSpec string `protobuf:"bytes,2,opt,name=level_spec,json=levelSpec,proto3" json:"level_spec,omitempty"`
 }
 
-func (x *SendAssetRequest) Reset() {
-	*x = SendAssetRequest{}
+func (x *DebugLevelRequest) Reset() {
+	*x = DebugLevelRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[49]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3832,13 +4092,13 @@ func (x *SendAssetRequest) Reset() {
 	}
 }
 
-func (x *SendAssetRequest) String() string {
+func (x *DebugLevelRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendAssetRequest) ProtoMessage() {}
+func (*DebugLevelRequest) ProtoMessage() {}
 
-func (x *SendAssetRequest) ProtoReflect() protoreflect.Message {
+func (x *DebugLevelRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[49]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3850,31 +4110,35 @@ func (x *SendAssetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendAssetRequest.ProtoReflect.Descriptor instead.
-func (*SendAssetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DebugLevelRequest.ProtoReflect.Descriptor instead.
+func (*DebugLevelRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *SendAssetRequest) GetTapAddrs() []string {
+func (x *DebugLevelRequest) GetShow() bool {
 	if x != nil {
-		return x.TapAddrs
+		return x.Show
 	}
-	return nil
+	return false
 }
 
-type PrevInputAsset struct {
+func (x *DebugLevelRequest) GetLevelSpec() string {
+	if x != nil {
+		return x.LevelSpec
+	}
+	return ""
+}
+
+type DebugLevelResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AnchorPoint string `protobuf:"bytes,1,opt,name=anchor_point,json=anchorPoint,proto3" json:"anchor_point,omitempty"`
-	AssetId     []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	ScriptKey   []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
-	Amount      uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	SubSystems string `protobuf:"bytes,1,opt,name=sub_systems,json=subSystems,proto3" json:"sub_systems,omitempty"`
 }
 
-func (x *PrevInputAsset) Reset() {
-	*x = PrevInputAsset{}
+func (x *DebugLevelResponse) Reset() {
+	*x = DebugLevelResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[50]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3882,13 +4146,13 @@ func (x *PrevInputAsset) Reset() {
 	}
 }
 
-func (x *PrevInputAsset) String() string {
+func (x *DebugLevelResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PrevInputAsset) ProtoMessage() {}
+func (*DebugLevelResponse) ProtoMessage() {}
 
-func (x *PrevInputAsset) ProtoReflect() protoreflect.Message {
+func (x *DebugLevelResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[50]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3900,49 +4164,30 @@ func (x *PrevInputAsset) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PrevInputAsset.ProtoReflect.Descriptor instead.
-func (*PrevInputAsset) Descriptor() ([]byte, []int) {
+// Deprecated: Use DebugLevelResponse.ProtoReflect.Descriptor instead.
+func (*DebugLevelResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *PrevInputAsset) GetAnchorPoint() string {
+func (x *DebugLevelResponse) GetSubSystems() string {
 	if x != nil {
-		return x.AnchorPoint
+		return x.SubSystems
 	}
 	return ""
 }
 
-func (x *PrevInputAsset) GetAssetId() []byte {
-	if x != nil {
-		return x.AssetId
-	}
-	return nil
-}
-
-func (x *PrevInputAsset) GetScriptKey() []byte {
-	if x != nil {
-		return x.ScriptKey
-	}
-	return nil
-}
-
-func (x *PrevInputAsset) GetAmount() uint64 {
-	if x != nil {
-		return x.Amount
-	}
-	return 0
-}
-
-type SendAssetResponse struct {
+type BackupDatabaseRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// The path on the machine running the daemon to write the backup to. The
+	// file must not exist yet.
+	FilePath string `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
 }
 
-func (x *SendAssetResponse) Reset() {
-	*x = SendAssetResponse{}
+func (x *BackupDatabaseRequest) Reset() {
+	*x = BackupDatabaseRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[51]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3950,13 +4195,13 @@ func (x *SendAssetResponse) Reset() {
 	}
 }
 
-func (x *SendAssetResponse) String() string {
+func (x *BackupDatabaseRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendAssetResponse) ProtoMessage() {}
+func (*BackupDatabaseRequest) ProtoMessage() {}
 
-func (x *SendAssetResponse) ProtoReflect() protoreflect.Message {
+func (x *BackupDatabaseRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[51]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3968,26 +4213,33 @@ func (x *SendAssetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendAssetResponse.ProtoReflect.Descriptor instead.
-func (*SendAssetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BackupDatabaseRequest.ProtoReflect.Descriptor instead.
+func (*BackupDatabaseRequest) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *SendAssetResponse) GetTransfer() *AssetTransfer {
+func (x *BackupDatabaseRequest) GetFilePath() string {
 	if x != nil {
-		return x.Transfer
+		return x.FilePath
 	}
-	return nil
+	return ""
 }
 
-type GetInfoRequest struct {
+type BackupDatabaseResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The path the backup was written to.
+	FilePath string `protobuf:"bytes,1,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	// The size of the backup in bytes.
+	SizeBytes uint64 `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// The hex encoded SHA-256 checksum of the backup.
+	Sha256 string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
 }
 
-func (x *GetInfoRequest) Reset() {
-	*x = GetInfoRequest{}
+func (x *BackupDatabaseResponse) Reset() {
+	*x = BackupDatabaseResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[52]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3995,13 +4247,13 @@ func (x *GetInfoRequest) Reset() {
 	}
 }
 
-func (x *GetInfoRequest) String() string {
+func (x *BackupDatabaseResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoRequest) ProtoMessage() {}
+func (*BackupDatabaseResponse) ProtoMessage() {}
 
-func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+func (x *BackupDatabaseResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[52]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4013,23 +4265,74 @@ func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BackupDatabaseResponse.ProtoReflect.Descriptor instead.
+func (*BackupDatabaseResponse) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{52}
 }
 
-type GetInfoResponse struct {
+func (x *BackupDatabaseResponse) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *BackupDatabaseResponse) GetSizeBytes() uint64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *BackupDatabaseResponse) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type Addr struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Version    string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
-	LndVersion string `protobuf:"bytes,2,opt,name=lnd_version,json=lndVersion,proto3" json:"lnd_version,omitempty"`
-	Network    string `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	// The bech32 encoded Taproot Asset address.
+	Encoded string `protobuf:"bytes,1,opt,name=encoded,proto3" json:"encoded,omitempty"`
+	// The asset ID that uniquely identifies the asset.
+	AssetId []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The type of the asset.
+	AssetType AssetType `protobuf:"varint,3,opt,name=asset_type,json=assetType,proto3,enum=taprpc.AssetType" json:"asset_type,omitempty"`
+	// The total amount of the asset stored in this Taproot Asset UTXO.
+	Amount uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The group key of the asset (if it exists)
+	GroupKey []byte `protobuf:"bytes,5,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	// The specific script key the asset must commit to in order to transfer
+	// ownership to the creator of the address.
+	ScriptKey []byte `protobuf:"bytes,6,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The internal key used for the on-chain output.
+	InternalKey []byte `protobuf:"bytes,7,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	// The optional serialized tapscript sibling preimage to use for the receiving
+	// asset. This is usually empty as it is only needed when there should be an
+	// additional script path in the Taproot tree alongside the Taproot Asset
+	// commitment of the asset.
+	TapscriptSibling []byte `protobuf:"bytes,8,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
+	// The tweaked internal key that commits to the asset and represents the
+	// on-chain output key the Bitcoin transaction must send to in order to
+	// transfer assets described in this address.
+	TaprootOutputKey []byte `protobuf:"bytes,9,opt,name=taproot_output_key,json=taprootOutputKey,proto3" json:"taproot_output_key,omitempty"`
+	// The address of the proof courier service used in proof transfer.
+	ProofCourierAddr string `protobuf:"bytes,10,opt,name=proof_courier_addr,json=proofCourierAddr,proto3" json:"proof_courier_addr,omitempty"`
+	// The asset version of the address.
+	AssetVersion AssetVersion `protobuf:"varint,11,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// If set, the sender chooses the amount sent to the address, which must be
+	// at least min_amount. The amount field is then only the suggested amount.
+	AllowAnyAmount bool `protobuf:"varint,12,opt,name=allow_any_amount,json=allowAnyAmount,proto3" json:"allow_any_amount,omitempty"`
+	// The minimum amount the address accepts if allow_any_amount is set.
+	MinAmount uint64 `protobuf:"varint,13,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
 }
 
-func (x *GetInfoResponse) Reset() {
-	*x = GetInfoResponse{}
+func (x *Addr) Reset() {
+	*x = Addr{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[53]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4037,13 +4340,13 @@ func (x *GetInfoResponse) Reset() {
 	}
 }
 
-func (x *GetInfoResponse) String() string {
+func (x *Addr) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInfoResponse) ProtoMessage() {}
+func (*Addr) ProtoMessage() {}
 
-func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+func (x *Addr) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[53]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4055,182 +4358,136 @@ func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Addr.ProtoReflect.Descriptor instead.
+func (*Addr) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GetInfoResponse) GetVersion() string {
+func (x *Addr) GetEncoded() string {
 	if x != nil {
-		return x.Version
+		return x.Encoded
 	}
 	return ""
 }
 
-func (x *GetInfoResponse) GetLndVersion() string {
+func (x *Addr) GetAssetId() []byte {
 	if x != nil {
-		return x.LndVersion
+		return x.AssetId
 	}
-	return ""
+	return nil
 }
 
-func (x *GetInfoResponse) GetNetwork() string {
+func (x *Addr) GetAssetType() AssetType {
 	if x != nil {
-		return x.Network
+		return x.AssetType
 	}
-	return ""
+	return AssetType_NORMAL
 }
 
-type SubscribeSendAssetEventNtfnsRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *Addr) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
 }
 
-func (x *SubscribeSendAssetEventNtfnsRequest) Reset() {
-	*x = SubscribeSendAssetEventNtfnsRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[54]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Addr) GetGroupKey() []byte {
+	if x != nil {
+		return x.GroupKey
 	}
+	return nil
 }
 
-func (x *SubscribeSendAssetEventNtfnsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Addr) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
 }
 
-func (*SubscribeSendAssetEventNtfnsRequest) ProtoMessage() {}
-
-func (x *SubscribeSendAssetEventNtfnsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[54]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *Addr) GetInternalKey() []byte {
+	if x != nil {
+		return x.InternalKey
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use SubscribeSendAssetEventNtfnsRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeSendAssetEventNtfnsRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{54}
+func (x *Addr) GetTapscriptSibling() []byte {
+	if x != nil {
+		return x.TapscriptSibling
+	}
+	return nil
 }
 
-type SendAssetEvent struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Types that are assignable to Event:
-	//
-	//	*SendAssetEvent_ExecuteSendStateEvent
-	//	*SendAssetEvent_ReceiverProofBackoffWaitEvent
-	Event isSendAssetEvent_Event `protobuf_oneof:"event"`
+func (x *Addr) GetTaprootOutputKey() []byte {
+	if x != nil {
+		return x.TaprootOutputKey
+	}
+	return nil
 }
 
-func (x *SendAssetEvent) Reset() {
-	*x = SendAssetEvent{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[55]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *Addr) GetProofCourierAddr() string {
+	if x != nil {
+		return x.ProofCourierAddr
 	}
+	return ""
 }
 
-func (x *SendAssetEvent) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*SendAssetEvent) ProtoMessage() {}
-
-func (x *SendAssetEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[55]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use SendAssetEvent.ProtoReflect.Descriptor instead.
-func (*SendAssetEvent) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{55}
-}
-
-func (m *SendAssetEvent) GetEvent() isSendAssetEvent_Event {
-	if m != nil {
-		return m.Event
+func (x *Addr) GetAssetVersion() AssetVersion {
+	if x != nil {
+		return x.AssetVersion
 	}
-	return nil
+	return AssetVersion_ASSET_VERSION_V0
 }
 
-func (x *SendAssetEvent) GetExecuteSendStateEvent() *ExecuteSendStateEvent {
-	if x, ok := x.GetEvent().(*SendAssetEvent_ExecuteSendStateEvent); ok {
-		return x.ExecuteSendStateEvent
+func (x *Addr) GetAllowAnyAmount() bool {
+	if x != nil {
+		return x.AllowAnyAmount
 	}
-	return nil
+	return false
 }
 
-func (x *SendAssetEvent) GetReceiverProofBackoffWaitEvent() *ReceiverProofBackoffWaitEvent {
-	if x, ok := x.GetEvent().(*SendAssetEvent_ReceiverProofBackoffWaitEvent); ok {
-		return x.ReceiverProofBackoffWaitEvent
+func (x *Addr) GetMinAmount() uint64 {
+	if x != nil {
+		return x.MinAmount
 	}
-	return nil
-}
-
-type isSendAssetEvent_Event interface {
-	isSendAssetEvent_Event()
-}
-
-type SendAssetEvent_ExecuteSendStateEvent struct {
-	// An event which indicates that a send state is about to be executed.
-	ExecuteSendStateEvent *ExecuteSendStateEvent `protobuf:"bytes,1,opt,name=execute_send_state_event,json=executeSendStateEvent,proto3,oneof"`
-}
-
-type SendAssetEvent_ReceiverProofBackoffWaitEvent struct {
-	// An event which indicates that the proof send backoff wait period will
-	// start imminently.
-	ReceiverProofBackoffWaitEvent *ReceiverProofBackoffWaitEvent `protobuf:"bytes,2,opt,name=receiver_proof_backoff_wait_event,json=receiverProofBackoffWaitEvent,proto3,oneof"`
+	return 0
 }
 
-func (*SendAssetEvent_ExecuteSendStateEvent) isSendAssetEvent_Event() {}
-
-func (*SendAssetEvent_ReceiverProofBackoffWaitEvent) isSendAssetEvent_Event() {}
-
-type ExecuteSendStateEvent struct {
+type QueryAddrRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Execute timestamp (microseconds).
-	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// The send state that is about to be executed.
-	SendState string `protobuf:"bytes,2,opt,name=send_state,json=sendState,proto3" json:"send_state,omitempty"`
+	// If set, then only addresses created after this Unix timestamp will be
+	// returned.
+	CreatedAfter int64 `protobuf:"varint,1,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	// If set, then only addresses created before this Unix timestamp will be
+	// returned.
+	CreatedBefore int64 `protobuf:"varint,2,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	// The max number of addresses that should be returned.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The offset from the addresses that should be returned.
+	Offset int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
-func (x *ExecuteSendStateEvent) Reset() {
-	*x = ExecuteSendStateEvent{}
+func (x *QueryAddrRequest) Reset() {
+	*x = QueryAddrRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[56]
+		mi := &file_taprootassets_proto_msgTypes[54]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ExecuteSendStateEvent) String() string {
+func (x *QueryAddrRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecuteSendStateEvent) ProtoMessage() {}
+func (*QueryAddrRequest) ProtoMessage() {}
 
-func (x *ExecuteSendStateEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[56]
+func (x *QueryAddrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[54]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4241,57 +4498,64 @@ func (x *ExecuteSendStateEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecuteSendStateEvent.ProtoReflect.Descriptor instead.
-func (*ExecuteSendStateEvent) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{56}
+// Deprecated: Use QueryAddrRequest.ProtoReflect.Descriptor instead.
+func (*QueryAddrRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *ExecuteSendStateEvent) GetTimestamp() int64 {
+func (x *QueryAddrRequest) GetCreatedAfter() int64 {
 	if x != nil {
-		return x.Timestamp
+		return x.CreatedAfter
 	}
 	return 0
 }
 
-func (x *ExecuteSendStateEvent) GetSendState() string {
+func (x *QueryAddrRequest) GetCreatedBefore() int64 {
 	if x != nil {
-		return x.SendState
+		return x.CreatedBefore
 	}
-	return ""
+	return 0
 }
 
-type ReceiverProofBackoffWaitEvent struct {
+func (x *QueryAddrRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *QueryAddrRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type QueryAddrResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Transfer attempt timestamp (microseconds).
-	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	// Backoff is the active backoff wait duration.
-	Backoff int64 `protobuf:"varint,2,opt,name=backoff,proto3" json:"backoff,omitempty"`
-	// Tries counter is the number of tries we've made so far during the
-	// course of the current backoff procedure to deliver the proof to the
-	// receiver.
-	TriesCounter int64 `protobuf:"varint,3,opt,name=tries_counter,json=triesCounter,proto3" json:"tries_counter,omitempty"`
+	Addrs []*Addr `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs,omitempty"`
 }
 
-func (x *ReceiverProofBackoffWaitEvent) Reset() {
-	*x = ReceiverProofBackoffWaitEvent{}
+func (x *QueryAddrResponse) Reset() {
+	*x = QueryAddrResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[57]
+		mi := &file_taprootassets_proto_msgTypes[55]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ReceiverProofBackoffWaitEvent) String() string {
+func (x *QueryAddrResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReceiverProofBackoffWaitEvent) ProtoMessage() {}
+func (*QueryAddrResponse) ProtoMessage() {}
 
-func (x *ReceiverProofBackoffWaitEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[57]
+func (x *QueryAddrResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[55]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4302,63 +4566,91 @@ func (x *ReceiverProofBackoffWaitEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReceiverProofBackoffWaitEvent.ProtoReflect.Descriptor instead.
-func (*ReceiverProofBackoffWaitEvent) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{57}
-}
-
-func (x *ReceiverProofBackoffWaitEvent) GetTimestamp() int64 {
-	if x != nil {
-		return x.Timestamp
-	}
-	return 0
-}
-
-func (x *ReceiverProofBackoffWaitEvent) GetBackoff() int64 {
-	if x != nil {
-		return x.Backoff
-	}
-	return 0
+// Deprecated: Use QueryAddrResponse.ProtoReflect.Descriptor instead.
+func (*QueryAddrResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *ReceiverProofBackoffWaitEvent) GetTriesCounter() int64 {
+func (x *QueryAddrResponse) GetAddrs() []*Addr {
 	if x != nil {
-		return x.TriesCounter
+		return x.Addrs
 	}
-	return 0
+	return nil
 }
 
-type FetchAssetMetaRequest struct {
+type NewAddrRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Asset:
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Amt     uint64 `protobuf:"varint,2,opt,name=amt,proto3" json:"amt,omitempty"`
+	// The optional script key that the receiving asset should be locked to. If no
+	// script key is provided, a normal BIP-86 key will be derived from the
+	// underlying wallet.
 	//
-	//	*FetchAssetMetaRequest_AssetId
-	//	*FetchAssetMetaRequest_MetaHash
-	//	*FetchAssetMetaRequest_AssetIdStr
-	//	*FetchAssetMetaRequest_MetaHashStr
-	Asset isFetchAssetMetaRequest_Asset `protobuf_oneof:"asset"`
+	// NOTE: The script_key and internal_key fields should either both be set or
+	// both be empty.
+	ScriptKey *ScriptKey `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The optional internal key of the receiving BTC level transaction output on
+	// which the receiving asset transfers will be committed to. If no internal key
+	// is provided, a key will be derived from the underlying wallet.
+	//
+	// NOTE: The script_key and internal_key fields should either both be set or
+	// both be empty.
+	InternalKey *KeyDescriptor `protobuf:"bytes,4,opt,name=internal_key,json=internalKey,proto3" json:"internal_key,omitempty"`
+	// The optional serialized tapscript sibling preimage to use for the receiving
+	// asset. This is usually empty as it is only needed when there should be an
+	// additional script path in the Taproot tree alongside the Taproot Asset
+	// commitment of the asset.
+	TapscriptSibling []byte `protobuf:"bytes,5,opt,name=tapscript_sibling,json=tapscriptSibling,proto3" json:"tapscript_sibling,omitempty"`
+	// An optional proof courier address for use in proof transfer. If unspecified,
+	// the daemon configured default address will be used.
+	ProofCourierAddr string `protobuf:"bytes,6,opt,name=proof_courier_addr,json=proofCourierAddr,proto3" json:"proof_courier_addr,omitempty"`
+	// The asset version to use when sending/receiving to/from this address.
+	AssetVersion AssetVersion `protobuf:"varint,7,opt,name=asset_version,json=assetVersion,proto3,enum=taprpc.AssetVersion" json:"asset_version,omitempty"`
+	// The optional tapscript tree the script key of the receiving asset should
+	// commit to. The asset can then be spent through one of the tree's leaves, in
+	// addition to the key spend path of the script key's internal key, which is
+	// derived from the underlying wallet.
+	//
+	// NOTE: This field can't be combined with the script_key and internal_key
+	// fields.
+	ScriptKeyTapscript *ScriptKeyTapscript `protobuf:"bytes,8,opt,name=script_key_tapscript,json=scriptKeyTapscript,proto3" json:"script_key_tapscript,omitempty"`
+	// An optional key chosen by the client that makes the address creation
+	// idempotent. If an address was already created with the same key and the
+	// same parameters, that address is returned instead of deriving a new one.
+	// Re-using a key with different parameters results in an error. If no key is
+	// set, a new address is created on every call.
+	IdempotencyKey string `protobuf:"bytes,9,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// If set, the address accepts a transfer of any amount of at least
+	// min_amount, chosen by the sender. The amt field is then only the suggested
+	// amount and may be zero, in which case min_amount (or a single unit) is
+	// suggested. The address must use a mailbox based proof courier (hashmail,
+	// email or http(s)), as the receiver only learns about the transfer from its
+	// proof. Collectibles can't be received this way.
+	AllowAnyAmount bool `protobuf:"varint,10,opt,name=allow_any_amount,json=allowAnyAmount,proto3" json:"allow_any_amount,omitempty"`
+	// The minimum amount the address accepts if allow_any_amount is set.
+	MinAmount uint64 `protobuf:"varint,11,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
 }
 
-func (x *FetchAssetMetaRequest) Reset() {
-	*x = FetchAssetMetaRequest{}
+func (x *NewAddrRequest) Reset() {
+	*x = NewAddrRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[58]
+		mi := &file_taprootassets_proto_msgTypes[56]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *FetchAssetMetaRequest) String() string {
+func (x *NewAddrRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FetchAssetMetaRequest) ProtoMessage() {}
+func (*NewAddrRequest) ProtoMessage() {}
 
-func (x *FetchAssetMetaRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[58]
+func (x *NewAddrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[56]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4369,112 +4661,115 @@ func (x *FetchAssetMetaRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FetchAssetMetaRequest.ProtoReflect.Descriptor instead.
-func (*FetchAssetMetaRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{58}
+// Deprecated: Use NewAddrRequest.ProtoReflect.Descriptor instead.
+func (*NewAddrRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{56}
 }
 
-func (m *FetchAssetMetaRequest) GetAsset() isFetchAssetMetaRequest_Asset {
-	if m != nil {
-		return m.Asset
+func (x *NewAddrRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
 	}
 	return nil
 }
 
-func (x *FetchAssetMetaRequest) GetAssetId() []byte {
-	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_AssetId); ok {
-		return x.AssetId
+func (x *NewAddrRequest) GetAmt() uint64 {
+	if x != nil {
+		return x.Amt
 	}
-	return nil
+	return 0
 }
 
-func (x *FetchAssetMetaRequest) GetMetaHash() []byte {
-	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_MetaHash); ok {
-		return x.MetaHash
+func (x *NewAddrRequest) GetScriptKey() *ScriptKey {
+	if x != nil {
+		return x.ScriptKey
 	}
 	return nil
 }
 
-func (x *FetchAssetMetaRequest) GetAssetIdStr() string {
-	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_AssetIdStr); ok {
-		return x.AssetIdStr
+func (x *NewAddrRequest) GetInternalKey() *KeyDescriptor {
+	if x != nil {
+		return x.InternalKey
 	}
-	return ""
+	return nil
 }
 
-func (x *FetchAssetMetaRequest) GetMetaHashStr() string {
-	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_MetaHashStr); ok {
-		return x.MetaHashStr
+func (x *NewAddrRequest) GetTapscriptSibling() []byte {
+	if x != nil {
+		return x.TapscriptSibling
 	}
-	return ""
+	return nil
 }
 
-type isFetchAssetMetaRequest_Asset interface {
-	isFetchAssetMetaRequest_Asset()
+func (x *NewAddrRequest) GetProofCourierAddr() string {
+	if x != nil {
+		return x.ProofCourierAddr
+	}
+	return ""
 }
 
-type FetchAssetMetaRequest_AssetId struct {
-	// The asset ID of the asset to fetch the meta for.
-	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3,oneof"`
+func (x *NewAddrRequest) GetAssetVersion() AssetVersion {
+	if x != nil {
+		return x.AssetVersion
+	}
+	return AssetVersion_ASSET_VERSION_V0
 }
 
-type FetchAssetMetaRequest_MetaHash struct {
-	// The 32-byte meta hash of the asset meta.
-	MetaHash []byte `protobuf:"bytes,2,opt,name=meta_hash,json=metaHash,proto3,oneof"`
+func (x *NewAddrRequest) GetScriptKeyTapscript() *ScriptKeyTapscript {
+	if x != nil {
+		return x.ScriptKeyTapscript
+	}
+	return nil
 }
 
-type FetchAssetMetaRequest_AssetIdStr struct {
-	// The hex encoded asset ID of the asset to fetch the meta for.
-	AssetIdStr string `protobuf:"bytes,3,opt,name=asset_id_str,json=assetIdStr,proto3,oneof"`
+func (x *NewAddrRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
 }
 
-type FetchAssetMetaRequest_MetaHashStr struct {
-	// The hex encoded meta hash of the asset meta.
-	MetaHashStr string `protobuf:"bytes,4,opt,name=meta_hash_str,json=metaHashStr,proto3,oneof"`
+func (x *NewAddrRequest) GetAllowAnyAmount() bool {
+	if x != nil {
+		return x.AllowAnyAmount
+	}
+	return false
 }
 
-func (*FetchAssetMetaRequest_AssetId) isFetchAssetMetaRequest_Asset() {}
-
-func (*FetchAssetMetaRequest_MetaHash) isFetchAssetMetaRequest_Asset() {}
-
-func (*FetchAssetMetaRequest_AssetIdStr) isFetchAssetMetaRequest_Asset() {}
-
-func (*FetchAssetMetaRequest_MetaHashStr) isFetchAssetMetaRequest_Asset() {}
+func (x *NewAddrRequest) GetMinAmount() uint64 {
+	if x != nil {
+		return x.MinAmount
+	}
+	return 0
+}
 
-type BurnAssetRequest struct {
+type NewAddrBatchRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Asset:
-	//
-	//	*BurnAssetRequest_AssetId
-	//	*BurnAssetRequest_AssetIdStr
-	Asset        isBurnAssetRequest_Asset `protobuf_oneof:"asset"`
-	AmountToBurn uint64                   `protobuf:"varint,3,opt,name=amount_to_burn,json=amountToBurn,proto3" json:"amount_to_burn,omitempty"`
-	// A safety check to ensure the user is aware of the destructive nature of
-	// the burn. This needs to be set to the value "assets will be destroyed"
-	// for the burn to succeed.
-	ConfirmationText string `protobuf:"bytes,4,opt,name=confirmation_text,json=confirmationText,proto3" json:"confirmation_text,omitempty"`
+	// The params of the addresses to create. Idempotency keys are not supported
+	// within a batch.
+	Addrs []*NewAddrRequest `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs,omitempty"`
 }
 
-func (x *BurnAssetRequest) Reset() {
-	*x = BurnAssetRequest{}
+func (x *NewAddrBatchRequest) Reset() {
+	*x = NewAddrBatchRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_taprootassets_proto_msgTypes[59]
+		mi := &file_taprootassets_proto_msgTypes[57]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BurnAssetRequest) String() string {
+func (x *NewAddrBatchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BurnAssetRequest) ProtoMessage() {}
+func (*NewAddrBatchRequest) ProtoMessage() {}
 
-func (x *BurnAssetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_taprootassets_proto_msgTypes[59]
+func (x *NewAddrBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[57]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4485,77 +4780,149 @@ func (x *BurnAssetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BurnAssetRequest.ProtoReflect.Descriptor instead.
-func (*BurnAssetRequest) Descriptor() ([]byte, []int) {
-	return file_taprootassets_proto_rawDescGZIP(), []int{59}
+// Deprecated: Use NewAddrBatchRequest.ProtoReflect.Descriptor instead.
+func (*NewAddrBatchRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{57}
 }
 
-func (m *BurnAssetRequest) GetAsset() isBurnAssetRequest_Asset {
-	if m != nil {
-		return m.Asset
+func (x *NewAddrBatchRequest) GetAddrs() []*NewAddrRequest {
+	if x != nil {
+		return x.Addrs
 	}
 	return nil
 }
 
-func (x *BurnAssetRequest) GetAssetId() []byte {
-	if x, ok := x.GetAsset().(*BurnAssetRequest_AssetId); ok {
-		return x.AssetId
+type NewAddrBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The created addresses, in the order of the requests.
+	Addrs []*Addr `protobuf:"bytes,1,rep,name=addrs,proto3" json:"addrs,omitempty"`
+}
+
+func (x *NewAddrBatchResponse) Reset() {
+	*x = NewAddrBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *BurnAssetRequest) GetAssetIdStr() string {
-	if x, ok := x.GetAsset().(*BurnAssetRequest_AssetIdStr); ok {
-		return x.AssetIdStr
+func (x *NewAddrBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewAddrBatchResponse) ProtoMessage() {}
+
+func (x *NewAddrBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *BurnAssetRequest) GetAmountToBurn() uint64 {
+// Deprecated: Use NewAddrBatchResponse.ProtoReflect.Descriptor instead.
+func (*NewAddrBatchResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *NewAddrBatchResponse) GetAddrs() []*Addr {
 	if x != nil {
-		return x.AmountToBurn
+		return x.Addrs
 	}
-	return 0
+	return nil
 }
 
-func (x *BurnAssetRequest) GetConfirmationText() string {
-	if x != nil {
-		return x.ConfirmationText
+type ScriptKeyTapscript struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The scripts of the leaves of the tapscript tree, in the order they are
+	// assembled into the tree. All leaves use the base tapscript leaf version.
+	// Only the tree of a script key with known leaves can be spent through a
+	// script path by this daemon.
+	//
+	// NOTE: Exactly one of leaf_scripts and root_hash must be set.
+	LeafScripts [][]byte `protobuf:"bytes,1,rep,name=leaf_scripts,json=leafScripts,proto3" json:"leaf_scripts,omitempty"`
+	// The root hash of the tapscript tree, if its leaves should not be revealed
+	// to this daemon.
+	//
+	// NOTE: Exactly one of leaf_scripts and root_hash must be set.
+	RootHash []byte `protobuf:"bytes,2,opt,name=root_hash,json=rootHash,proto3" json:"root_hash,omitempty"`
+}
+
+func (x *ScriptKeyTapscript) Reset() {
+	*x = ScriptKeyTapscript{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-type isBurnAssetRequest_Asset interface {
-	isBurnAssetRequest_Asset()
+func (x *ScriptKeyTapscript) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type BurnAssetRequest_AssetId struct {
-	// The asset ID of the asset to burn units of.
-	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3,oneof"`
+func (*ScriptKeyTapscript) ProtoMessage() {}
+
+func (x *ScriptKeyTapscript) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type BurnAssetRequest_AssetIdStr struct {
-	// The hex encoded asset ID of the asset to burn units of.
-	AssetIdStr string `protobuf:"bytes,2,opt,name=asset_id_str,json=assetIdStr,proto3,oneof"`
+// Deprecated: Use ScriptKeyTapscript.ProtoReflect.Descriptor instead.
+func (*ScriptKeyTapscript) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{59}
 }
 
-func (*BurnAssetRequest_AssetId) isBurnAssetRequest_Asset() {}
+func (x *ScriptKeyTapscript) GetLeafScripts() [][]byte {
+	if x != nil {
+		return x.LeafScripts
+	}
+	return nil
+}
 
-func (*BurnAssetRequest_AssetIdStr) isBurnAssetRequest_Asset() {}
+func (x *ScriptKeyTapscript) GetRootHash() []byte {
+	if x != nil {
+		return x.RootHash
+	}
+	return nil
+}
 
-type BurnAssetResponse struct {
+type ScriptKey struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The asset transfer that contains the asset burn as an output.
-	BurnTransfer *AssetTransfer `protobuf:"bytes,1,opt,name=burn_transfer,json=burnTransfer,proto3" json:"burn_transfer,omitempty"`
-	// The burn transition proof for the asset burn output.
-	BurnProof *DecodedProof `protobuf:"bytes,2,opt,name=burn_proof,json=burnProof,proto3" json:"burn_proof,omitempty"`
+	// The full Taproot output key the asset is locked to. This is either a BIP-86
+	// key if the tap_tweak below is empty, or a key with the tap tweak applied to
+	// it.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// The key descriptor describing the internal key of the above Taproot key.
+	KeyDesc *KeyDescriptor `protobuf:"bytes,2,opt,name=key_desc,json=keyDesc,proto3" json:"key_desc,omitempty"`
+	// The optional Taproot tweak to apply to the above internal key. If this is
+	// empty then a BIP-86 style tweak is applied to the internal key.
+	TapTweak []byte `protobuf:"bytes,3,opt,name=tap_tweak,json=tapTweak,proto3" json:"tap_tweak,omitempty"`
 }
 
-func (x *BurnAssetResponse) Reset() {
-	*x = BurnAssetResponse{}
+func (x *ScriptKey) Reset() {
+	*x = ScriptKey{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_taprootassets_proto_msgTypes[60]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4563,13 +4930,13 @@ func (x *BurnAssetResponse) Reset() {
 	}
 }
 
-func (x *BurnAssetResponse) String() string {
+func (x *ScriptKey) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BurnAssetResponse) ProtoMessage() {}
+func (*ScriptKey) ProtoMessage() {}
 
-func (x *BurnAssetResponse) ProtoReflect() protoreflect.Message {
+func (x *ScriptKey) ProtoReflect() protoreflect.Message {
 	mi := &file_taprootassets_proto_msgTypes[60]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -4581,940 +4948,9385 @@ func (x *BurnAssetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BurnAssetResponse.ProtoReflect.Descriptor instead.
-func (*BurnAssetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ScriptKey.ProtoReflect.Descriptor instead.
+func (*ScriptKey) Descriptor() ([]byte, []int) {
 	return file_taprootassets_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *BurnAssetResponse) GetBurnTransfer() *AssetTransfer {
+func (x *ScriptKey) GetPubKey() []byte {
 	if x != nil {
-		return x.BurnTransfer
+		return x.PubKey
 	}
 	return nil
 }
 
-func (x *BurnAssetResponse) GetBurnProof() *DecodedProof {
+func (x *ScriptKey) GetKeyDesc() *KeyDescriptor {
 	if x != nil {
-		return x.BurnProof
+		return x.KeyDesc
 	}
 	return nil
 }
 
-var File_taprootassets_proto protoreflect.FileDescriptor
+func (x *ScriptKey) GetTapTweak() []byte {
+	if x != nil {
+		return x.TapTweak
+	}
+	return nil
+}
 
-var file_taprootassets_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x22, 0x67, 0x0a,
-	0x09, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x29,
-	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74,
-	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74,
-	0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65,
-	0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x22, 0x81, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77,
-	0x69, 0x74, 0x68, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x23,
-	0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x53, 0x70,
-	0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6c,
-	0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63,
-	0x6c, 0x75, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x22, 0xb3, 0x02, 0x0a, 0x0a, 0x41,
-	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6e, 0x63,
-	0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x6e,
-	0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72,
-	0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63,
-	0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x6e, 0x63, 0x68, 0x6f,
-	0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48,
-	0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75,
-	0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e,
-	0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12,
-	0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74,
-	0x12, 0x2b, 0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69,
-	0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a,
-	0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
-	0x22, 0xbb, 0x01, 0x0a, 0x0b, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x23, 0x0a, 0x0d, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73,
-	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74,
-	0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65,
-	0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
-	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49,
-	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65,
-	0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49,
-	0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x81,
-	0x01, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x22, 0x0a,
-	0x0d, 0x72, 0x61, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65,
-	0x79, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x77, 0x65, 0x61, 0x6b, 0x65, 0x64, 0x5f, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x74, 0x77,
-	0x65, 0x61, 0x6b, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x0a,
-	0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x57, 0x69, 0x74, 0x6e, 0x65,
-	0x73, 0x73, 0x22, 0x5b, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65,
-	0x76, 0x65, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75,
-	0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x70, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0d, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x22,
-	0x86, 0x01, 0x0a, 0x0d, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61,
-	0x6c, 0x12, 0x43, 0x0a, 0x13, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x62, 0x61, 0x73,
-	0x65, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x11, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x61, 0x73, 0x65,
-	0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x61,
-	0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x9b, 0x05, 0x0a, 0x05, 0x41, 0x73, 0x73,
-	0x65, 0x74, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73,
-	0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65,
-	0x73, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x12, 0x30, 0x0a, 0x0a,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
-	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
-	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74,
-	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x54,
-	0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f,
-	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x10, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d,
-	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x13, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x73, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x49,
-	0x73, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x33, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
-	0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x35, 0x0a, 0x0c, 0x63,
-	0x68, 0x61, 0x69, 0x6e, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f,
-	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x6e, 0x63, 0x68,
-	0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65,
-	0x73, 0x73, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x52,
-	0x0d, 0x70, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x19,
-	0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x07, 0x69, 0x73, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x65, 0x61,
-	0x73, 0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
-	0x6c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x65,
-	0x61, 0x73, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x0b, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x17, 0x0a,
-	0x07, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
-	0x69, 0x73, 0x42, 0x75, 0x72, 0x6e, 0x22, 0xa1, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x76, 0x57,
-	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
-	0x2e, 0x50, 0x72, 0x65, 0x76, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52,
-	0x06, 0x70, 0x72, 0x65, 0x76, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x78, 0x5f, 0x77, 0x69,
-	0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x74, 0x78, 0x57,
-	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x42, 0x0a, 0x10, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f,
-	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43,
-	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0f, 0x73, 0x70, 0x6c, 0x69, 0x74,
-	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x3f, 0x0a, 0x0f, 0x53, 0x70,
-	0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a,
-	0x0a, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74,
-	0x52, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x22, 0x3a, 0x0a, 0x11, 0x4c,
-	0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x25, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52,
-	0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0x39, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x55,
-	0x74, 0x78, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69,
-	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73,
-	0x65, 0x64, 0x22, 0xdc, 0x01, 0x0a, 0x0b, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55, 0x74,
-	0x78, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12,
-	0x17, 0x0a, 0x07, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x06, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x74,
-	0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x6f, 0x6f,
-	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74,
-	0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72,
-	0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
-	0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x73, 0x22, 0xbb, 0x01, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x6d, 0x61, 0x6e, 0x61, 0x67,
-	0x65, 0x64, 0x5f, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65,
-	0x64, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x6d, 0x61, 0x6e,
-	0x61, 0x67, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x1a, 0x54, 0x0a, 0x11, 0x4d, 0x61, 0x6e,
-	0x61, 0x67, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x29, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64,
-	0x55, 0x74, 0x78, 0x6f, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
-	0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x22, 0x8d, 0x02, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x74, 0x48, 0x75,
-	0x6d, 0x61, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f,
-	0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65,
-	0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6c, 0x6f, 0x63,
-	0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x72, 0x65,
-	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x10,
-	0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67,
-	0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x25, 0x0a,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x43, 0x0a, 0x0d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x65, 0x64, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c,
-	0x65, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x12, 0x4c, 0x69,
-	0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x3e, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x26, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73,
-	0x1a, 0x50, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x65,
-	0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
-	0x38, 0x01, 0x22, 0xaa, 0x01, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x08, 0x67, 0x72,
-	0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
-	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x0e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c,
-	0x74, 0x65, 0x72, 0x42, 0x0a, 0x0a, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x62, 0x79, 0x22,
-	0x94, 0x01, 0x0a, 0x0c, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
-	0x12, 0x38, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
-	0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70,
-	0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62,
-	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x4a, 0x0a, 0x11, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x67,
-	0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61,
-	0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x22, 0x90, 0x03, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x0e, 0x61,
-	0x73, 0x73, 0x65, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73,
-	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45,
-	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
-	0x63, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x14, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x67, 0x72, 0x6f,
-	0x75, 0x70, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x34, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42,
-	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
-	0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
-	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12, 0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x1a, 0x56, 0x0a, 0x12, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
-	0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65,
-	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x1a, 0x60, 0x0a, 0x17, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61,
-	0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4c, 0x0a,
-	0x15, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
-	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
-	0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x22, 0xa9, 0x02, 0x0a, 0x0d,
-	0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x2d, 0x0a,
-	0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x74, 0x72, 0x61, 0x6e, 0x73,
-	0x66, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x24, 0x0a, 0x0e,
-	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x48, 0x61,
-	0x73, 0x68, 0x12, 0x31, 0x0a, 0x15, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x5f,
-	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0d, 0x52, 0x12, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x48, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x48, 0x69, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
-	0x74, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x73, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x11, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x43, 0x68, 0x61,
-	0x69, 0x6e, 0x46, 0x65, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73,
-	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x06, 0x69,
-	0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73,
-	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
-	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x07,
-	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6e, 0x63,
-	0x68, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x95,
-	0x02, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f,
-	0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f,
-	0x69, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12,
-	0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x6f,
-	0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f,
-	0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65,
-	0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x74,
-	0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x75, 0x6d, 0x5f,
-	0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x50, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65,
-	0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xf7, 0x02, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e, 0x73,
-	0x66, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x61, 0x6e, 0x63,
-	0x68, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x52, 0x06, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12,
-	0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x2d,
-	0x0a, 0x13, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x73, 0x5f,
-	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x49, 0x73, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x16, 0x0a,
-	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x65, 0x77, 0x5f, 0x70, 0x72, 0x6f,
-	0x6f, 0x66, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x6e,
-	0x65, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x6c, 0x6f, 0x62, 0x12, 0x33, 0x0a, 0x16, 0x73,
-	0x70, 0x6c, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74,
-	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x73, 0x70, 0x6c,
-	0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68,
-	0x12, 0x33, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74,
-	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
-	0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x22, 0x0d, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
-	0x0e, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x46, 0x0a, 0x11, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x68, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x04, 0x73, 0x68, 0x6f, 0x77, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x76, 0x65,
-	0x6c, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x65,
-	0x76, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x22, 0x35, 0x0a, 0x12, 0x44, 0x65, 0x62, 0x75, 0x67,
-	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a,
-	0x0b, 0x73, 0x75, 0x62, 0x5f, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x73, 0x22, 0xa8,
-	0x03, 0x0a, 0x04, 0x41, 0x64, 0x64, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64,
-	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65,
-	0x64, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x0a,
-	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54,
-	0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
-	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
-	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
-	0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70,
-	0x4b, 0x65, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65,
-	0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b,
-	0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b,
-	0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
-	0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x10, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69,
-	0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x6f, 0x75,
-	0x74, 0x70, 0x75, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10,
-	0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4b, 0x65, 0x79,
-	0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65,
-	0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x39,
-	0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73,
-	0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x10, 0x51, 0x75,
-	0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23,
-	0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x66,
-	0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62,
-	0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x63, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69,
-	0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74,
-	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x37, 0x0a, 0x11, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a,
-	0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74,
-	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72,
-	0x73, 0x22, 0xbf, 0x02, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12,
-	0x10, 0x0a, 0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x61, 0x6d,
-	0x74, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x4b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f,
-	0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72,
-	0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2b, 0x0a,
-	0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69,
-	0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x75,
-	0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x39, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73,
-	0x69, 0x6f, 0x6e, 0x22, 0x73, 0x0a, 0x09, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79,
-	0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x08, 0x6b, 0x65, 0x79,
-	0x5f, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x6f, 0x72, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x74,
-	0x61, 0x70, 0x5f, 0x74, 0x77, 0x65, 0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08,
-	0x74, 0x61, 0x70, 0x54, 0x77, 0x65, 0x61, 0x6b, 0x22, 0x48, 0x0a, 0x0a, 0x4b, 0x65, 0x79, 0x4c,
-	0x6f, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61,
-	0x6d, 0x69, 0x6c, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46,
-	0x61, 0x6d, 0x69, 0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x6e, 0x64,
-	0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x49, 0x6e, 0x64,
-	0x65, 0x78, 0x22, 0x60, 0x0a, 0x0d, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x62,
-	0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77, 0x4b,
-	0x65, 0x79, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x6c,
-	0x6f, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x4b, 0x65, 0x79, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x06, 0x6b, 0x65,
-	0x79, 0x4c, 0x6f, 0x63, 0x22, 0x27, 0x0a, 0x11, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x41, 0x64,
-	0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x22, 0x56, 0x0a,
-	0x09, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x61,
-	0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0c, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65,
-	0x12, 0x23, 0x0a, 0x0d, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73,
-	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0xd7, 0x04, 0x0a, 0x0c, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65,
-	0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f,
-	0x61, 0x74, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
-	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x74, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x28, 0x0a, 0x10,
-	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66,
-	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x23, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x52, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x6d,
-	0x65, 0x74, 0x61, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d,
-	0x65, 0x74, 0x61, 0x52, 0x0a, 0x6d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12,
-	0x26, 0x0a, 0x0f, 0x74, 0x78, 0x5f, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f,
-	0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x74, 0x78, 0x4d, 0x65, 0x72, 0x6b,
-	0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75,
-	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c,
-	0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66,
-	0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0f, 0x65, 0x78, 0x63, 0x6c,
-	0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73,
-	0x70, 0x6c, 0x69, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74,
-	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x32, 0x0a, 0x15, 0x6e, 0x75, 0x6d, 0x5f, 0x61, 0x64, 0x64,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x6e, 0x75, 0x6d, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x61, 0x6c, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x68, 0x61,
-	0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x0a,
-	0x20, 0x03, 0x28, 0x0c, 0x52, 0x10, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x57,
-	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x72,
-	0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x42, 0x75, 0x72, 0x6e, 0x12,
-	0x3c, 0x0a, 0x0e, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61,
-	0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
-	0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x52, 0x0d,
-	0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12, 0x40, 0x0a,
-	0x10, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61,
-	0x6c, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
-	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x52,
-	0x0e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x22,
-	0x66, 0x0a, 0x13, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x0d,
-	0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63,
-	0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6f, 0x64,
-	0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xb1, 0x01, 0x0a, 0x12, 0x44, 0x65, 0x63, 0x6f,
-	0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
-	0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x08, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x24, 0x0a, 0x0e, 0x70,
-	0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x61, 0x74, 0x5f, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x74, 0x44, 0x65, 0x70, 0x74,
-	0x68, 0x12, 0x2e, 0x0a, 0x13, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x77,
-	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
-	0x77, 0x69, 0x74, 0x68, 0x50, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x65,
-	0x73, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x72,
-	0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x77, 0x69, 0x74,
-	0x68, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x22, 0x50, 0x0a, 0x13, 0x44,
-	0x65, 0x63, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x39, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x5f, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52,
-	0x0c, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x4e, 0x0a,
-	0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d,
-	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0xd0, 0x02,
-	0x0a, 0x09, 0x41, 0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3b, 0x0a, 0x1a, 0x63,
-	0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69,
-	0x78, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x17, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69,
-	0x78, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x20, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
-	0x41, 0x64, 0x64, 0x72, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6f,
-	0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f,
-	0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x75, 0x74, 0x78, 0x6f, 0x5f,
-	0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x75,
-	0x74, 0x78, 0x6f, 0x41, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x61, 0x70,
-	0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0e, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69,
-	0x6e, 0x67, 0x12, 0x2f, 0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x12, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66,
-	0x22, 0x74, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6c, 0x74, 0x65,
-	0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x69,
-	0x6c, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x3c, 0x0a, 0x0d, 0x66, 0x69, 0x6c, 0x74,
-	0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x41, 0x0a, 0x14, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29,
-	0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x2f, 0x0a, 0x10, 0x53, 0x65, 0x6e,
-	0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
-	0x09, 0x74, 0x61, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
-	0x52, 0x08, 0x74, 0x61, 0x70, 0x41, 0x64, 0x64, 0x72, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x0e, 0x50,
-	0x72, 0x65, 0x76, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x21, 0x0a,
-	0x0c, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x50, 0x6f, 0x69, 0x6e, 0x74,
-	0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d,
-	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75,
-	0x6e, 0x74, 0x22, 0x46, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73,
-	0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
-	0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x65,
-	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x0f,
-	0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6e, 0x64,
-	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x6c, 0x6e, 0x64, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65,
-	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74,
-	0x77, 0x6f, 0x72, 0x6b, 0x22, 0x25, 0x0a, 0x23, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
-	0x65, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e,
-	0x74, 0x66, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xe6, 0x01, 0x0a, 0x0e,
-	0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x58,
-	0x0a, 0x18, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
-	0x65, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
-	0x00, 0x52, 0x15, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74,
-	0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x71, 0x0a, 0x21, 0x72, 0x65, 0x63, 0x65,
-	0x69, 0x76, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f,
-	0x66, 0x66, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63,
-	0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66,
-	0x66, 0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x1d, 0x72, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f,
-	0x66, 0x66, 0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x22, 0x54, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x53,
-	0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a,
-	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
-	0x65, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x73, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x7c, 0x0a, 0x1d, 0x52, 0x65,
-	0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f,
-	0x66, 0x66, 0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63,
-	0x6b, 0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b,
-	0x6f, 0x66, 0x66, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x72, 0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75,
-	0x6e, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x72, 0x69, 0x65,
-	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x22, 0xa6, 0x01, 0x0a, 0x15, 0x46, 0x65, 0x74,
-	0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12,
-	0x1d, 0x0a, 0x09, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x22,
-	0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x72, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x53,
-	0x74, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f,
-	0x73, 0x74, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x6d, 0x65, 0x74,
-	0x61, 0x48, 0x61, 0x73, 0x68, 0x53, 0x74, 0x72, 0x42, 0x07, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x22, 0xaf, 0x01, 0x0a, 0x10, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x5f,
-	0x73, 0x74, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x73, 0x73,
-	0x65, 0x74, 0x49, 0x64, 0x53, 0x74, 0x72, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x5f, 0x74, 0x6f, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x0c, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x42, 0x75, 0x72, 0x6e, 0x12, 0x2b, 0x0a,
-	0x11, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x65,
-	0x78, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72,
-	0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x65, 0x78, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x61, 0x73,
-	0x73, 0x65, 0x74, 0x22, 0x84, 0x01, 0x0a, 0x11, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x62, 0x75, 0x72,
-	0x6e, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54,
-	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x0c, 0x62, 0x75, 0x72, 0x6e, 0x54, 0x72, 0x61,
-	0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x0a, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x70, 0x72,
-	0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52,
-	0x09, 0x62, 0x75, 0x72, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x2a, 0x28, 0x0a, 0x09, 0x41, 0x73,
-	0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41,
-	0x4c, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x49, 0x42,
-	0x4c, 0x45, 0x10, 0x01, 0x2a, 0x25, 0x0a, 0x0d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74,
-	0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x54, 0x41, 0x5f, 0x54, 0x59,
-	0x50, 0x45, 0x5f, 0x4f, 0x50, 0x41, 0x51, 0x55, 0x45, 0x10, 0x00, 0x2a, 0x3a, 0x0a, 0x0c, 0x41,
-	0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x10, 0x41,
-	0x53, 0x53, 0x45, 0x54, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x56, 0x30, 0x10,
-	0x00, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49,
-	0x4f, 0x4e, 0x5f, 0x56, 0x31, 0x10, 0x01, 0x2a, 0xb0, 0x01, 0x0a, 0x0a, 0x4f, 0x75, 0x74, 0x70,
-	0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54,
-	0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x1a,
-	0x0a, 0x16, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x50,
-	0x4c, 0x49, 0x54, 0x5f, 0x52, 0x4f, 0x4f, 0x54, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f, 0x4f, 0x55,
-	0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x49, 0x56,
-	0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x53, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x12,
-	0x22, 0x0a, 0x1e, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50,
-	0x41, 0x53, 0x53, 0x49, 0x56, 0x45, 0x5f, 0x53, 0x50, 0x4c, 0x49, 0x54, 0x5f, 0x52, 0x4f, 0x4f,
-	0x54, 0x10, 0x03, 0x12, 0x25, 0x0a, 0x21, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59,
-	0x50, 0x45, 0x5f, 0x53, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x5f, 0x50, 0x41, 0x53, 0x53, 0x49, 0x56,
-	0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x53, 0x10, 0x04, 0x2a, 0xd0, 0x01, 0x0a, 0x0f, 0x41,
-	0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d,
-	0x0a, 0x19, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41,
-	0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x2a, 0x0a,
-	0x26, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54,
-	0x55, 0x53, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44,
-	0x45, 0x54, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x2b, 0x0a, 0x27, 0x41, 0x44, 0x44,
-	0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54,
-	0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49,
-	0x52, 0x4d, 0x45, 0x44, 0x10, 0x02, 0x12, 0x24, 0x0a, 0x20, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x45,
-	0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x4f,
-	0x46, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1f, 0x0a, 0x1b,
-	0x41, 0x44, 0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
-	0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x04, 0x32, 0x96, 0x0a,
-	0x0a, 0x0d, 0x54, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12,
-	0x41, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x18, 0x2e,
-	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x12,
-	0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78,
-	0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x73, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
-	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
-	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
-	0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e,
-	0x73, 0x66, 0x65, 0x72, 0x73, 0x12, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c,
-	0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73,
-	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x44, 0x61, 0x65, 0x6d, 0x6f, 0x6e,
-	0x12, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
-	0x74, 0x6f, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x44,
-	0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72,
-	0x70, 0x63, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65,
-	0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x41, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x18,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64,
-	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x12, 0x16,
-	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
-	0x41, 0x64, 0x64, 0x72, 0x12, 0x35, 0x0a, 0x0a, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x41, 0x64,
-	0x64, 0x72, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f,
-	0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
-	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x12, 0x49, 0x0a, 0x0c, 0x41,
-	0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
-	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50,
-	0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x1a, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x50,
-	0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65,
-	0x63, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65,
-	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a,
-	0x0b, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x2e, 0x74,
-	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f,
-	0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x53,
-	0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64,
-	0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a,
-	0x09, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75,
-	0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70,
-	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x49,
-	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x1c, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65,
-	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e, 0x73, 0x12, 0x2b, 0x2e, 0x74, 0x61,
-	0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x53, 0x65,
-	0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
-	0x30, 0x01, 0x12, 0x42, 0x0a, 0x0e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74,
-	0x4d, 0x65, 0x74, 0x61, 0x12, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x65,
-	0x74, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73,
-	0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
-	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61,
-	0x62, 0x73, 0x2f, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x73, 0x2f, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+type KeyLocator struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The family of key being identified.
+	KeyFamily int32 `protobuf:"varint,1,opt,name=key_family,json=keyFamily,proto3" json:"key_family,omitempty"`
+	// The precise index of the key being identified.
+	KeyIndex int32 `protobuf:"varint,2,opt,name=key_index,json=keyIndex,proto3" json:"key_index,omitempty"`
 }
 
-var (
-	file_taprootassets_proto_rawDescOnce sync.Once
-	file_taprootassets_proto_rawDescData = file_taprootassets_proto_rawDesc
-)
+func (x *KeyLocator) Reset() {
+	*x = KeyLocator{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_taprootassets_proto_rawDescGZIP() []byte {
-	file_taprootassets_proto_rawDescOnce.Do(func() {
-		file_taprootassets_proto_rawDescData = protoimpl.X.CompressGZIP(file_taprootassets_proto_rawDescData)
-	})
-	return file_taprootassets_proto_rawDescData
+func (x *KeyLocator) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_taprootassets_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_taprootassets_proto_msgTypes = make([]protoimpl.MessageInfo, 65)
-var file_taprootassets_proto_goTypes = []interface{}{
-	(AssetType)(0),                              // 0: taprpc.AssetType
-	(AssetMetaType)(0),                          // 1: taprpc.AssetMetaType
-	(AssetVersion)(0),                           // 2: taprpc.AssetVersion
-	(OutputType)(0),                             // 3: taprpc.OutputType
-	(AddrEventStatus)(0),                        // 4: taprpc.AddrEventStatus
-	(*AssetMeta)(nil),                           // 5: taprpc.AssetMeta
-	(*ListAssetRequest)(nil),                    // 6: taprpc.ListAssetRequest
-	(*AnchorInfo)(nil),                          // 7: taprpc.AnchorInfo
-	(*GenesisInfo)(nil),                         // 8: taprpc.GenesisInfo
-	(*AssetGroup)(nil),                          // 9: taprpc.AssetGroup
-	(*GroupKeyReveal)(nil),                      // 10: taprpc.GroupKeyReveal
-	(*GenesisReveal)(nil),                       // 11: taprpc.GenesisReveal
-	(*Asset)(nil),                               // 12: taprpc.Asset
-	(*PrevWitness)(nil),                         // 13: taprpc.PrevWitness
-	(*SplitCommitment)(nil),                     // 14: taprpc.SplitCommitment
-	(*ListAssetResponse)(nil),                   // 15: taprpc.ListAssetResponse
-	(*ListUtxosRequest)(nil),                    // 16: taprpc.ListUtxosRequest
-	(*ManagedUtxo)(nil),                         // 17: taprpc.ManagedUtxo
-	(*ListUtxosResponse)(nil),                   // 18: taprpc.ListUtxosResponse
-	(*ListGroupsRequest)(nil),                   // 19: taprpc.ListGroupsRequest
-	(*AssetHumanReadable)(nil),                  // 20: taprpc.AssetHumanReadable
-	(*GroupedAssets)(nil),                       // 21: taprpc.GroupedAssets
-	(*ListGroupsResponse)(nil),                  // 22: taprpc.ListGroupsResponse
-	(*ListBalancesRequest)(nil),                 // 23: taprpc.ListBalancesRequest
-	(*AssetBalance)(nil),                        // 24: taprpc.AssetBalance
-	(*AssetGroupBalance)(nil),                   // 25: taprpc.AssetGroupBalance
-	(*ListBalancesResponse)(nil),                // 26: taprpc.ListBalancesResponse
-	(*ListTransfersRequest)(nil),                // 27: taprpc.ListTransfersRequest
-	(*ListTransfersResponse)(nil),               // 28: taprpc.ListTransfersResponse
-	(*AssetTransfer)(nil),                       // 29: taprpc.AssetTransfer
-	(*TransferInput)(nil),                       // 30: taprpc.TransferInput
-	(*TransferOutputAnchor)(nil),                // 31: taprpc.TransferOutputAnchor
-	(*TransferOutput)(nil),                      // 32: taprpc.TransferOutput
-	(*StopRequest)(nil),                         // 33: taprpc.StopRequest
-	(*StopResponse)(nil),                        // 34: taprpc.StopResponse
-	(*DebugLevelRequest)(nil),                   // 35: taprpc.DebugLevelRequest
-	(*DebugLevelResponse)(nil),                  // 36: taprpc.DebugLevelResponse
-	(*Addr)(nil),                                // 37: taprpc.Addr
-	(*QueryAddrRequest)(nil),                    // 38: taprpc.QueryAddrRequest
-	(*QueryAddrResponse)(nil),                   // 39: taprpc.QueryAddrResponse
-	(*NewAddrRequest)(nil),                      // 40: taprpc.NewAddrRequest
-	(*ScriptKey)(nil),                           // 41: taprpc.ScriptKey
-	(*KeyLocator)(nil),                          // 42: taprpc.KeyLocator
-	(*KeyDescriptor)(nil),                       // 43: taprpc.KeyDescriptor
-	(*DecodeAddrRequest)(nil),                   // 44: taprpc.DecodeAddrRequest
-	(*ProofFile)(nil),                           // 45: taprpc.ProofFile
-	(*DecodedProof)(nil),                        // 46: taprpc.DecodedProof
-	(*VerifyProofResponse)(nil),                 // 47: taprpc.VerifyProofResponse
-	(*DecodeProofRequest)(nil),                  // 48: taprpc.DecodeProofRequest
-	(*DecodeProofResponse)(nil),                 // 49: taprpc.DecodeProofResponse
-	(*ExportProofRequest)(nil),                  // 50: taprpc.ExportProofRequest
-	(*AddrEvent)(nil),                           // 51: taprpc.AddrEvent
-	(*AddrReceivesRequest)(nil),                 // 52: taprpc.AddrReceivesRequest
-	(*AddrReceivesResponse)(nil),                // 53: taprpc.AddrReceivesResponse
-	(*SendAssetRequest)(nil),                    // 54: taprpc.SendAssetRequest
-	(*PrevInputAsset)(nil),                      // 55: taprpc.PrevInputAsset
-	(*SendAssetResponse)(nil),                   // 56: taprpc.SendAssetResponse
-	(*GetInfoRequest)(nil),                      // 57: taprpc.GetInfoRequest
-	(*GetInfoResponse)(nil),                     // 58: taprpc.GetInfoResponse
-	(*SubscribeSendAssetEventNtfnsRequest)(nil), // 59: taprpc.SubscribeSendAssetEventNtfnsRequest
-	(*SendAssetEvent)(nil),                      // 60: taprpc.SendAssetEvent
-	(*ExecuteSendStateEvent)(nil),               // 61: taprpc.ExecuteSendStateEvent
-	(*ReceiverProofBackoffWaitEvent)(nil),       // 62: taprpc.ReceiverProofBackoffWaitEvent
-	(*FetchAssetMetaRequest)(nil),               // 63: taprpc.FetchAssetMetaRequest
-	(*BurnAssetRequest)(nil),                    // 64: taprpc.BurnAssetRequest
-	(*BurnAssetResponse)(nil),                   // 65: taprpc.BurnAssetResponse
-	nil,                                         // 66: taprpc.ListUtxosResponse.ManagedUtxosEntry
-	nil,                                         // 67: taprpc.ListGroupsResponse.GroupsEntry
-	nil,                                         // 68: taprpc.ListBalancesResponse.AssetBalancesEntry
-	nil,                                         // 69: taprpc.ListBalancesResponse.AssetGroupBalancesEntry
+func (*KeyLocator) ProtoMessage() {}
+
+func (x *KeyLocator) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_taprootassets_proto_depIdxs = []int32{
-	1,  // 0: taprpc.AssetMeta.type:type_name -> taprpc.AssetMetaType
-	8,  // 1: taprpc.GenesisReveal.genesis_base_reveal:type_name -> taprpc.GenesisInfo
-	0,  // 2: taprpc.GenesisReveal.asset_type:type_name -> taprpc.AssetType
-	2,  // 3: taprpc.Asset.version:type_name -> taprpc.AssetVersion
-	8,  // 4: taprpc.Asset.asset_genesis:type_name -> taprpc.GenesisInfo
-	0,  // 5: taprpc.Asset.asset_type:type_name -> taprpc.AssetType
-	9,  // 6: taprpc.Asset.asset_group:type_name -> taprpc.AssetGroup
-	7,  // 7: taprpc.Asset.chain_anchor:type_name -> taprpc.AnchorInfo
-	13, // 8: taprpc.Asset.prev_witnesses:type_name -> taprpc.PrevWitness
-	55, // 9: taprpc.PrevWitness.prev_id:type_name -> taprpc.PrevInputAsset
-	14, // 10: taprpc.PrevWitness.split_commitment:type_name -> taprpc.SplitCommitment
-	12, // 11: taprpc.SplitCommitment.root_asset:type_name -> taprpc.Asset
-	12, // 12: taprpc.ListAssetResponse.assets:type_name -> taprpc.Asset
-	12, // 13: taprpc.ManagedUtxo.assets:type_name -> taprpc.Asset
-	66, // 14: taprpc.ListUtxosResponse.managed_utxos:type_name -> taprpc.ListUtxosResponse.ManagedUtxosEntry
-	0,  // 15: taprpc.AssetHumanReadable.type:type_name -> taprpc.AssetType
-	2,  // 16: taprpc.AssetHumanReadable.version:type_name -> taprpc.AssetVersion
-	20, // 17: taprpc.GroupedAssets.assets:type_name -> taprpc.AssetHumanReadable
-	67, // 18: taprpc.ListGroupsResponse.groups:type_name -> taprpc.ListGroupsResponse.GroupsEntry
-	8,  // 19: taprpc.AssetBalance.asset_genesis:type_name -> taprpc.GenesisInfo
-	0,  // 20: taprpc.AssetBalance.asset_type:type_name -> taprpc.AssetType
-	68, // 21: taprpc.ListBalancesResponse.asset_balances:type_name -> taprpc.ListBalancesResponse.AssetBalancesEntry
-	69, // 22: taprpc.ListBalancesResponse.asset_group_balances:type_name -> taprpc.ListBalancesResponse.AssetGroupBalancesEntry
-	29, // 23: taprpc.ListTransfersResponse.transfers:type_name -> taprpc.AssetTransfer
-	30, // 24: taprpc.AssetTransfer.inputs:type_name -> taprpc.TransferInput
-	32, // 25: taprpc.AssetTransfer.outputs:type_name -> taprpc.TransferOutput
-	31, // 26: taprpc.TransferOutput.anchor:type_name -> taprpc.TransferOutputAnchor
-	3,  // 27: taprpc.TransferOutput.output_type:type_name -> taprpc.OutputType
-	2,  // 28: taprpc.TransferOutput.asset_version:type_name -> taprpc.AssetVersion
-	0,  // 29: taprpc.Addr.asset_type:type_name -> taprpc.AssetType
-	2,  // 30: taprpc.Addr.asset_version:type_name -> taprpc.AssetVersion
-	37, // 31: taprpc.QueryAddrResponse.addrs:type_name -> taprpc.Addr
-	41, // 32: taprpc.NewAddrRequest.script_key:type_name -> taprpc.ScriptKey
-	43, // 33: taprpc.NewAddrRequest.internal_key:type_name -> taprpc.KeyDescriptor
-	2,  // 34: taprpc.NewAddrRequest.asset_version:type_name -> taprpc.AssetVersion
-	43, // 35: taprpc.ScriptKey.key_desc:type_name -> taprpc.KeyDescriptor
-	42, // 36: taprpc.KeyDescriptor.key_loc:type_name -> taprpc.KeyLocator
-	12, // 37: taprpc.DecodedProof.asset:type_name -> taprpc.Asset
-	5,  // 38: taprpc.DecodedProof.meta_reveal:type_name -> taprpc.AssetMeta
-	11, // 39: taprpc.DecodedProof.genesis_reveal:type_name -> taprpc.GenesisReveal
-	10, // 40: taprpc.DecodedProof.group_key_reveal:type_name -> taprpc.GroupKeyReveal
-	46, // 41: taprpc.VerifyProofResponse.decoded_proof:type_name -> taprpc.DecodedProof
-	46, // 42: taprpc.DecodeProofResponse.decoded_proof:type_name -> taprpc.DecodedProof
-	37, // 43: taprpc.AddrEvent.addr:type_name -> taprpc.Addr
-	4,  // 44: taprpc.AddrEvent.status:type_name -> taprpc.AddrEventStatus
-	4,  // 45: taprpc.AddrReceivesRequest.filter_status:type_name -> taprpc.AddrEventStatus
-	51, // 46: taprpc.AddrReceivesResponse.events:type_name -> taprpc.AddrEvent
-	29, // 47: taprpc.SendAssetResponse.transfer:type_name -> taprpc.AssetTransfer
-	61, // 48: taprpc.SendAssetEvent.execute_send_state_event:type_name -> taprpc.ExecuteSendStateEvent
-	62, // 49: taprpc.SendAssetEvent.receiver_proof_backoff_wait_event:type_name -> taprpc.ReceiverProofBackoffWaitEvent
-	29, // 50: taprpc.BurnAssetResponse.burn_transfer:type_name -> taprpc.AssetTransfer
-	46, // 51: taprpc.BurnAssetResponse.burn_proof:type_name -> taprpc.DecodedProof
-	17, // 52: taprpc.ListUtxosResponse.ManagedUtxosEntry.value:type_name -> taprpc.ManagedUtxo
-	21, // 53: taprpc.ListGroupsResponse.GroupsEntry.value:type_name -> taprpc.GroupedAssets
-	24, // 54: taprpc.ListBalancesResponse.AssetBalancesEntry.value:type_name -> taprpc.AssetBalance
-	25, // 55: taprpc.ListBalancesResponse.AssetGroupBalancesEntry.value:type_name -> taprpc.AssetGroupBalance
-	6,  // 56: taprpc.TaprootAssets.ListAssets:input_type -> taprpc.ListAssetRequest
-	16, // 57: taprpc.TaprootAssets.ListUtxos:input_type -> taprpc.ListUtxosRequest
-	19, // 58: taprpc.TaprootAssets.ListGroups:input_type -> taprpc.ListGroupsRequest
-	23, // 59: taprpc.TaprootAssets.ListBalances:input_type -> taprpc.ListBalancesRequest
-	27, // 60: taprpc.TaprootAssets.ListTransfers:input_type -> taprpc.ListTransfersRequest
-	33, // 61: taprpc.TaprootAssets.StopDaemon:input_type -> taprpc.StopRequest
-	35, // 62: taprpc.TaprootAssets.DebugLevel:input_type -> taprpc.DebugLevelRequest
-	38, // 63: taprpc.TaprootAssets.QueryAddrs:input_type -> taprpc.QueryAddrRequest
-	40, // 64: taprpc.TaprootAssets.NewAddr:input_type -> taprpc.NewAddrRequest
-	44, // 65: taprpc.TaprootAssets.DecodeAddr:input_type -> taprpc.DecodeAddrRequest
-	52, // 66: taprpc.TaprootAssets.AddrReceives:input_type -> taprpc.AddrReceivesRequest
-	45, // 67: taprpc.TaprootAssets.VerifyProof:input_type -> taprpc.ProofFile
-	48, // 68: taprpc.TaprootAssets.DecodeProof:input_type -> taprpc.DecodeProofRequest
-	50, // 69: taprpc.TaprootAssets.ExportProof:input_type -> taprpc.ExportProofRequest
-	54, // 70: taprpc.TaprootAssets.SendAsset:input_type -> taprpc.SendAssetRequest
-	64, // 71: taprpc.TaprootAssets.BurnAsset:input_type -> taprpc.BurnAssetRequest
-	57, // 72: taprpc.TaprootAssets.GetInfo:input_type -> taprpc.GetInfoRequest
-	59, // 73: taprpc.TaprootAssets.SubscribeSendAssetEventNtfns:input_type -> taprpc.SubscribeSendAssetEventNtfnsRequest
-	63, // 74: taprpc.TaprootAssets.FetchAssetMeta:input_type -> taprpc.FetchAssetMetaRequest
-	15, // 75: taprpc.TaprootAssets.ListAssets:output_type -> taprpc.ListAssetResponse
-	18, // 76: taprpc.TaprootAssets.ListUtxos:output_type -> taprpc.ListUtxosResponse
-	22, // 77: taprpc.TaprootAssets.ListGroups:output_type -> taprpc.ListGroupsResponse
-	26, // 78: taprpc.TaprootAssets.ListBalances:output_type -> taprpc.ListBalancesResponse
-	28, // 79: taprpc.TaprootAssets.ListTransfers:output_type -> taprpc.ListTransfersResponse
-	34, // 80: taprpc.TaprootAssets.StopDaemon:output_type -> taprpc.StopResponse
-	36, // 81: taprpc.TaprootAssets.DebugLevel:output_type -> taprpc.DebugLevelResponse
-	39, // 82: taprpc.TaprootAssets.QueryAddrs:output_type -> taprpc.QueryAddrResponse
-	37, // 83: taprpc.TaprootAssets.NewAddr:output_type -> taprpc.Addr
-	37, // 84: taprpc.TaprootAssets.DecodeAddr:output_type -> taprpc.Addr
-	53, // 85: taprpc.TaprootAssets.AddrReceives:output_type -> taprpc.AddrReceivesResponse
-	47, // 86: taprpc.TaprootAssets.VerifyProof:output_type -> taprpc.VerifyProofResponse
-	49, // 87: taprpc.TaprootAssets.DecodeProof:output_type -> taprpc.DecodeProofResponse
-	45, // 88: taprpc.TaprootAssets.ExportProof:output_type -> taprpc.ProofFile
-	56, // 89: taprpc.TaprootAssets.SendAsset:output_type -> taprpc.SendAssetResponse
-	65, // 90: taprpc.TaprootAssets.BurnAsset:output_type -> taprpc.BurnAssetResponse
-	58, // 91: taprpc.TaprootAssets.GetInfo:output_type -> taprpc.GetInfoResponse
-	60, // 92: taprpc.TaprootAssets.SubscribeSendAssetEventNtfns:output_type -> taprpc.SendAssetEvent
-	5,  // 93: taprpc.TaprootAssets.FetchAssetMeta:output_type -> taprpc.AssetMeta
-	75, // [75:94] is the sub-list for method output_type
-	56, // [56:75] is the sub-list for method input_type
-	56, // [56:56] is the sub-list for extension type_name
-	56, // [56:56] is the sub-list for extension extendee
-	0,  // [0:56] is the sub-list for field type_name
+
+// Deprecated: Use KeyLocator.ProtoReflect.Descriptor instead.
+func (*KeyLocator) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{61}
 }
 
-func init() { file_taprootassets_proto_init() }
-func file_taprootassets_proto_init() {
-	if File_taprootassets_proto != nil {
-		return
+func (x *KeyLocator) GetKeyFamily() int32 {
+	if x != nil {
+		return x.KeyFamily
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_taprootassets_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetMeta); i {
+	return 0
+}
+
+func (x *KeyLocator) GetKeyIndex() int32 {
+	if x != nil {
+		return x.KeyIndex
+	}
+	return 0
+}
+
+type KeyDescriptor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw bytes of the key being identified.
+	RawKeyBytes []byte `protobuf:"bytes,1,opt,name=raw_key_bytes,json=rawKeyBytes,proto3" json:"raw_key_bytes,omitempty"`
+	// The key locator that identifies which key to use for signing.
+	KeyLoc *KeyLocator `protobuf:"bytes,2,opt,name=key_loc,json=keyLoc,proto3" json:"key_loc,omitempty"`
+}
+
+func (x *KeyDescriptor) Reset() {
+	*x = KeyDescriptor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyDescriptor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyDescriptor) ProtoMessage() {}
+
+func (x *KeyDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyDescriptor.ProtoReflect.Descriptor instead.
+func (*KeyDescriptor) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *KeyDescriptor) GetRawKeyBytes() []byte {
+	if x != nil {
+		return x.RawKeyBytes
+	}
+	return nil
+}
+
+func (x *KeyDescriptor) GetKeyLoc() *KeyLocator {
+	if x != nil {
+		return x.KeyLoc
+	}
+	return nil
+}
+
+type DecodeAddrRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addr string `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+}
+
+func (x *DecodeAddrRequest) Reset() {
+	*x = DecodeAddrRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeAddrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeAddrRequest) ProtoMessage() {}
+
+func (x *DecodeAddrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeAddrRequest.ProtoReflect.Descriptor instead.
+func (*DecodeAddrRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DecodeAddrRequest) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+type ProofFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw proof file encoded as bytes. Must be a file and not just an
+	// individual mint/transfer proof, except when used as the request of
+	// VerifyProof, which also accepts a single proof.
+	RawProofFile []byte `protobuf:"bytes,1,opt,name=raw_proof_file,json=rawProofFile,proto3" json:"raw_proof_file,omitempty"`
+	GenesisPoint string `protobuf:"bytes,2,opt,name=genesis_point,json=genesisPoint,proto3" json:"genesis_point,omitempty"`
+}
+
+func (x *ProofFile) Reset() {
+	*x = ProofFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProofFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofFile) ProtoMessage() {}
+
+func (x *ProofFile) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofFile.ProtoReflect.Descriptor instead.
+func (*ProofFile) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ProofFile) GetRawProofFile() []byte {
+	if x != nil {
+		return x.RawProofFile
+	}
+	return nil
+}
+
+func (x *ProofFile) GetGenesisPoint() string {
+	if x != nil {
+		return x.GenesisPoint
+	}
+	return ""
+}
+
+type DecodedProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The index depth of the decoded proof, with 0 being the latest proof.
+	ProofAtDepth uint32 `protobuf:"varint,1,opt,name=proof_at_depth,json=proofAtDepth,proto3" json:"proof_at_depth,omitempty"`
+	// The total number of proofs contained in the decoded proof file (this will
+	// always be 1 if a single mint/transition proof was given as the raw_proof
+	// instead of a file).
+	NumberOfProofs uint32 `protobuf:"varint,2,opt,name=number_of_proofs,json=numberOfProofs,proto3" json:"number_of_proofs,omitempty"`
+	// The asset referenced in the proof.
+	Asset *Asset `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
+	// The reveal meta data associated with the proof, if available.
+	MetaReveal *AssetMeta `protobuf:"bytes,4,opt,name=meta_reveal,json=metaReveal,proto3" json:"meta_reveal,omitempty"`
+	// The merkle proof for AnchorTx used to prove its
+	// inclusion within BlockHeader.
+	TxMerkleProof []byte `protobuf:"bytes,5,opt,name=tx_merkle_proof,json=txMerkleProof,proto3" json:"tx_merkle_proof,omitempty"`
+	// The TaprootProof proving the new inclusion of the
+	// resulting asset within AnchorTx.
+	InclusionProof []byte `protobuf:"bytes,6,opt,name=inclusion_proof,json=inclusionProof,proto3" json:"inclusion_proof,omitempty"`
+	// The set of TaprootProofs proving the exclusion of
+	// the resulting asset from all other Taproot outputs within AnchorTx.
+	ExclusionProofs [][]byte `protobuf:"bytes,7,rep,name=exclusion_proofs,json=exclusionProofs,proto3" json:"exclusion_proofs,omitempty"`
+	// An optional TaprootProof needed if this asset is
+	// the result of a split. SplitRootProof proves inclusion of the root
+	// asset of the split.
+	SplitRootProof []byte `protobuf:"bytes,8,opt,name=split_root_proof,json=splitRootProof,proto3" json:"split_root_proof,omitempty"`
+	// The number of additional nested full proofs for any inputs found within
+	// the resulting asset.
+	NumAdditionalInputs uint32 `protobuf:"varint,9,opt,name=num_additional_inputs,json=numAdditionalInputs,proto3" json:"num_additional_inputs,omitempty"`
+	// ChallengeWitness is an optional virtual transaction witness that serves
+	// as an ownership proof for the asset. If this is non-nil, then it is a
+	// valid transfer witness for a 1-input, 1-output virtual transaction that
+	// spends the asset in this proof and sends it to the NUMS key, to prove
+	// that the creator of the proof is able to produce a valid signature to
+	// spend the asset.
+	ChallengeWitness [][]byte `protobuf:"bytes,10,rep,name=challenge_witness,json=challengeWitness,proto3" json:"challenge_witness,omitempty"`
+	// Indicates whether the state transition this proof represents is a burn,
+	// meaning that the assets were provably destroyed and can no longer be
+	// spent.
+	IsBurn bool `protobuf:"varint,11,opt,name=is_burn,json=isBurn,proto3" json:"is_burn,omitempty"`
+	// GenesisReveal is an optional field that is the Genesis information for
+	// the asset. This is required for minting proofs.
+	GenesisReveal *GenesisReveal `protobuf:"bytes,12,opt,name=genesis_reveal,json=genesisReveal,proto3" json:"genesis_reveal,omitempty"`
+	// GroupKeyReveal is an optional field that includes the information needed
+	// to derive the tweaked group key.
+	GroupKeyReveal *GroupKeyReveal `protobuf:"bytes,13,opt,name=group_key_reveal,json=groupKeyReveal,proto3" json:"group_key_reveal,omitempty"`
+}
+
+func (x *DecodedProof) Reset() {
+	*x = DecodedProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodedProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodedProof) ProtoMessage() {}
+
+func (x *DecodedProof) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodedProof.ProtoReflect.Descriptor instead.
+func (*DecodedProof) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *DecodedProof) GetProofAtDepth() uint32 {
+	if x != nil {
+		return x.ProofAtDepth
+	}
+	return 0
+}
+
+func (x *DecodedProof) GetNumberOfProofs() uint32 {
+	if x != nil {
+		return x.NumberOfProofs
+	}
+	return 0
+}
+
+func (x *DecodedProof) GetAsset() *Asset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetMetaReveal() *AssetMeta {
+	if x != nil {
+		return x.MetaReveal
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetTxMerkleProof() []byte {
+	if x != nil {
+		return x.TxMerkleProof
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetInclusionProof() []byte {
+	if x != nil {
+		return x.InclusionProof
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetExclusionProofs() [][]byte {
+	if x != nil {
+		return x.ExclusionProofs
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetSplitRootProof() []byte {
+	if x != nil {
+		return x.SplitRootProof
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetNumAdditionalInputs() uint32 {
+	if x != nil {
+		return x.NumAdditionalInputs
+	}
+	return 0
+}
+
+func (x *DecodedProof) GetChallengeWitness() [][]byte {
+	if x != nil {
+		return x.ChallengeWitness
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetIsBurn() bool {
+	if x != nil {
+		return x.IsBurn
+	}
+	return false
+}
+
+func (x *DecodedProof) GetGenesisReveal() *GenesisReveal {
+	if x != nil {
+		return x.GenesisReveal
+	}
+	return nil
+}
+
+func (x *DecodedProof) GetGroupKeyReveal() *GroupKeyReveal {
+	if x != nil {
+		return x.GroupKeyReveal
+	}
+	return nil
+}
+
+type VerifyProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the proof passed the full verification.
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// The decoded last proof in the file or the decoded single proof. This
+	// is also set if the verification failed.
+	DecodedProof *DecodedProof `protobuf:"bytes,2,opt,name=decoded_proof,json=decodedProof,proto3" json:"decoded_proof,omitempty"`
+	// The reason the verification failed. Empty if the proof is valid.
+	FailureReason string `protobuf:"bytes,3,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	// The ID of the asset the proof is for.
+	AssetId []byte `protobuf:"bytes,4,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The amount of the asset the proof is for.
+	Amount uint64 `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The script key of the asset the proof is for.
+	ScriptKey []byte `protobuf:"bytes,6,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *VerifyProofResponse) Reset() {
+	*x = VerifyProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyProofResponse) ProtoMessage() {}
+
+func (x *VerifyProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyProofResponse.ProtoReflect.Descriptor instead.
+func (*VerifyProofResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *VerifyProofResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyProofResponse) GetDecodedProof() *DecodedProof {
+	if x != nil {
+		return x.DecodedProof
+	}
+	return nil
+}
+
+func (x *VerifyProofResponse) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *VerifyProofResponse) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *VerifyProofResponse) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *VerifyProofResponse) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type DecodeProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw proof bytes to decode. This can be a full proof file or a single
+	// mint/transition proof. If it is a full proof file, the proof_at_depth
+	// field will be used to determine which individual proof within the file to
+	// decode.
+	RawProof []byte `protobuf:"bytes,1,opt,name=raw_proof,json=rawProof,proto3" json:"raw_proof,omitempty"`
+	// The index depth of the decoded proof, with 0 being the latest proof. This
+	// is ignored if the raw_proof is a single mint/transition proof and not a
+	// proof file.
+	ProofAtDepth uint32 `protobuf:"varint,2,opt,name=proof_at_depth,json=proofAtDepth,proto3" json:"proof_at_depth,omitempty"`
+	// An option to include previous witnesses in decoding.
+	WithPrevWitnesses bool `protobuf:"varint,3,opt,name=with_prev_witnesses,json=withPrevWitnesses,proto3" json:"with_prev_witnesses,omitempty"`
+	// An option to attempt to retrieve the meta data associated with the proof.
+	WithMetaReveal bool `protobuf:"varint,4,opt,name=with_meta_reveal,json=withMetaReveal,proto3" json:"with_meta_reveal,omitempty"`
+}
+
+func (x *DecodeProofRequest) Reset() {
+	*x = DecodeProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeProofRequest) ProtoMessage() {}
+
+func (x *DecodeProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeProofRequest.ProtoReflect.Descriptor instead.
+func (*DecodeProofRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *DecodeProofRequest) GetRawProof() []byte {
+	if x != nil {
+		return x.RawProof
+	}
+	return nil
+}
+
+func (x *DecodeProofRequest) GetProofAtDepth() uint32 {
+	if x != nil {
+		return x.ProofAtDepth
+	}
+	return 0
+}
+
+func (x *DecodeProofRequest) GetWithPrevWitnesses() bool {
+	if x != nil {
+		return x.WithPrevWitnesses
+	}
+	return false
+}
+
+func (x *DecodeProofRequest) GetWithMetaReveal() bool {
+	if x != nil {
+		return x.WithMetaReveal
+	}
+	return false
+}
+
+type DecodeProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DecodedProof *DecodedProof `protobuf:"bytes,1,opt,name=decoded_proof,json=decodedProof,proto3" json:"decoded_proof,omitempty"`
+}
+
+func (x *DecodeProofResponse) Reset() {
+	*x = DecodeProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeProofResponse) ProtoMessage() {}
+
+func (x *DecodeProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeProofResponse.ProtoReflect.Descriptor instead.
+func (*DecodeProofResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *DecodeProofResponse) GetDecodedProof() *DecodedProof {
+	if x != nil {
+		return x.DecodedProof
+	}
+	return nil
+}
+
+type CheckProofCompatibilityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw proof or proof file to check.
+	RawProof []byte `protobuf:"bytes,1,opt,name=raw_proof,json=rawProof,proto3" json:"raw_proof,omitempty"`
+	// The name of the implementation that created the proof. This is only
+	// used to attribute the result and doesn't influence the checks.
+	Implementation string `protobuf:"bytes,2,opt,name=implementation,proto3" json:"implementation,omitempty"`
+	// The version of the implementation that created the proof. This is only
+	// used to attribute the result and doesn't influence the checks.
+	ImplementationVersion string `protobuf:"bytes,3,opt,name=implementation_version,json=implementationVersion,proto3" json:"implementation_version,omitempty"`
+}
+
+func (x *CheckProofCompatibilityRequest) Reset() {
+	*x = CheckProofCompatibilityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckProofCompatibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckProofCompatibilityRequest) ProtoMessage() {}
+
+func (x *CheckProofCompatibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckProofCompatibilityRequest.ProtoReflect.Descriptor instead.
+func (*CheckProofCompatibilityRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *CheckProofCompatibilityRequest) GetRawProof() []byte {
+	if x != nil {
+		return x.RawProof
+	}
+	return nil
+}
+
+func (x *CheckProofCompatibilityRequest) GetImplementation() string {
+	if x != nil {
+		return x.Implementation
+	}
+	return ""
+}
+
+func (x *CheckProofCompatibilityRequest) GetImplementationVersion() string {
+	if x != nil {
+		return x.ImplementationVersion
+	}
+	return ""
+}
+
+type ProofConformanceCheck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the check.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The index of the checked proof within the proof file. This is zero for
+	// a single proof and -1 for the checks of the proof file itself.
+	ProofIndex int32 `protobuf:"varint,2,opt,name=proof_index,json=proofIndex,proto3" json:"proof_index,omitempty"`
+	// Whether the proof passed the check.
+	Passed bool `protobuf:"varint,3,opt,name=passed,proto3" json:"passed,omitempty"`
+	// The reason the check failed, or additional information about a passed
+	// check.
+	Details string `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *ProofConformanceCheck) Reset() {
+	*x = ProofConformanceCheck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProofConformanceCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofConformanceCheck) ProtoMessage() {}
+
+func (x *ProofConformanceCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofConformanceCheck.ProtoReflect.Descriptor instead.
+func (*ProofConformanceCheck) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ProofConformanceCheck) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProofConformanceCheck) GetProofIndex() int32 {
+	if x != nil {
+		return x.ProofIndex
+	}
+	return 0
+}
+
+func (x *ProofConformanceCheck) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *ProofConformanceCheck) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+type CheckProofCompatibilityResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the proof passed all checks.
+	Compatible bool `protobuf:"varint,1,opt,name=compatible,proto3" json:"compatible,omitempty"`
+	// Whether the checked blob is a proof file instead of a single proof.
+	IsFile bool `protobuf:"varint,2,opt,name=is_file,json=isFile,proto3" json:"is_file,omitempty"`
+	// The results of the individual checks.
+	Checks []*ProofConformanceCheck `protobuf:"bytes,3,rep,name=checks,proto3" json:"checks,omitempty"`
+	// The implementation that created the proof, as declared in the request.
+	Implementation string `protobuf:"bytes,4,opt,name=implementation,proto3" json:"implementation,omitempty"`
+	// The version of the implementation that created the proof, as declared
+	// in the request.
+	ImplementationVersion string `protobuf:"bytes,5,opt,name=implementation_version,json=implementationVersion,proto3" json:"implementation_version,omitempty"`
+	// The version of the tapd instance that ran the checks.
+	CheckerVersion string `protobuf:"bytes,6,opt,name=checker_version,json=checkerVersion,proto3" json:"checker_version,omitempty"`
+}
+
+func (x *CheckProofCompatibilityResponse) Reset() {
+	*x = CheckProofCompatibilityResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CheckProofCompatibilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckProofCompatibilityResponse) ProtoMessage() {}
+
+func (x *CheckProofCompatibilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckProofCompatibilityResponse.ProtoReflect.Descriptor instead.
+func (*CheckProofCompatibilityResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *CheckProofCompatibilityResponse) GetCompatible() bool {
+	if x != nil {
+		return x.Compatible
+	}
+	return false
+}
+
+func (x *CheckProofCompatibilityResponse) GetIsFile() bool {
+	if x != nil {
+		return x.IsFile
+	}
+	return false
+}
+
+func (x *CheckProofCompatibilityResponse) GetChecks() []*ProofConformanceCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+func (x *CheckProofCompatibilityResponse) GetImplementation() string {
+	if x != nil {
+		return x.Implementation
+	}
+	return ""
+}
+
+func (x *CheckProofCompatibilityResponse) GetImplementationVersion() string {
+	if x != nil {
+		return x.ImplementationVersion
+	}
+	return ""
+}
+
+func (x *CheckProofCompatibilityResponse) GetCheckerVersion() string {
+	if x != nil {
+		return x.CheckerVersion
+	}
+	return ""
+}
+
+type ExportProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AssetId   []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *ExportProofRequest) Reset() {
+	*x = ExportProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProofRequest) ProtoMessage() {}
+
+func (x *ExportProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProofRequest.ProtoReflect.Descriptor instead.
+func (*ExportProofRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ExportProofRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *ExportProofRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type ExportProofsBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset IDs of the assets to export the proofs of.
+	AssetIds [][]byte `protobuf:"bytes,1,rep,name=asset_ids,json=assetIds,proto3" json:"asset_ids,omitempty"`
+	// The group keys of the grouped assets to export the proofs of.
+	GroupKeys [][]byte `protobuf:"bytes,2,rep,name=group_keys,json=groupKeys,proto3" json:"group_keys,omitempty"`
+}
+
+func (x *ExportProofsBatchRequest) Reset() {
+	*x = ExportProofsBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportProofsBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProofsBatchRequest) ProtoMessage() {}
+
+func (x *ExportProofsBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProofsBatchRequest.ProtoReflect.Descriptor instead.
+func (*ExportProofsBatchRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *ExportProofsBatchRequest) GetAssetIds() [][]byte {
+	if x != nil {
+		return x.AssetIds
+	}
+	return nil
+}
+
+func (x *ExportProofsBatchRequest) GetGroupKeys() [][]byte {
+	if x != nil {
+		return x.GroupKeys
+	}
+	return nil
+}
+
+type ExportedProofFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset ID of the exported asset.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key of the exported asset.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The anchor outpoint of the exported asset, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,3,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The raw proof file of the exported asset.
+	RawProofFile []byte `protobuf:"bytes,4,opt,name=raw_proof_file,json=rawProofFile,proto3" json:"raw_proof_file,omitempty"`
+}
+
+func (x *ExportedProofFile) Reset() {
+	*x = ExportedProofFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportedProofFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedProofFile) ProtoMessage() {}
+
+func (x *ExportedProofFile) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedProofFile.ProtoReflect.Descriptor instead.
+func (*ExportedProofFile) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ExportedProofFile) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *ExportedProofFile) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *ExportedProofFile) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *ExportedProofFile) GetRawProofFile() []byte {
+	if x != nil {
+		return x.RawProofFile
+	}
+	return nil
+}
+
+type ExportProofStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset ID of the asset to export the proof file of.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key of the asset to export the proof file of.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The index of the first proof to export, with 0 being the genesis proof.
+	StartIndex uint32 `protobuf:"varint,3,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	// The index after the last proof to export. If zero, all proofs up to and
+	// including the latest one are exported.
+	EndIndex uint32 `protobuf:"varint,4,opt,name=end_index,json=endIndex,proto3" json:"end_index,omitempty"`
+}
+
+func (x *ExportProofStreamRequest) Reset() {
+	*x = ExportProofStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportProofStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportProofStreamRequest) ProtoMessage() {}
+
+func (x *ExportProofStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportProofStreamRequest.ProtoReflect.Descriptor instead.
+func (*ExportProofStreamRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ExportProofStreamRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *ExportProofStreamRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *ExportProofStreamRequest) GetStartIndex() uint32 {
+	if x != nil {
+		return x.StartIndex
+	}
+	return 0
+}
+
+func (x *ExportProofStreamRequest) GetEndIndex() uint32 {
+	if x != nil {
+		return x.EndIndex
+	}
+	return 0
+}
+
+type ProofFileChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The version of the proof file the proof is exported from.
+	FileVersion uint32 `protobuf:"varint,1,opt,name=file_version,json=fileVersion,proto3" json:"file_version,omitempty"`
+	// The total number of proofs in the proof file.
+	NumProofs uint32 `protobuf:"varint,2,opt,name=num_proofs,json=numProofs,proto3" json:"num_proofs,omitempty"`
+	// The index of the proof in the proof file, with 0 being the genesis
+	// proof.
+	ProofIndex uint32 `protobuf:"varint,3,opt,name=proof_index,json=proofIndex,proto3" json:"proof_index,omitempty"`
+	// The raw proof at the index. This is an individual mint/transfer proof
+	// and not a proof file.
+	RawProof []byte `protobuf:"bytes,4,opt,name=raw_proof,json=rawProof,proto3" json:"raw_proof,omitempty"`
+}
+
+func (x *ProofFileChunk) Reset() {
+	*x = ProofFileChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProofFileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProofFileChunk) ProtoMessage() {}
+
+func (x *ProofFileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProofFileChunk.ProtoReflect.Descriptor instead.
+func (*ProofFileChunk) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ProofFileChunk) GetFileVersion() uint32 {
+	if x != nil {
+		return x.FileVersion
+	}
+	return 0
+}
+
+func (x *ProofFileChunk) GetNumProofs() uint32 {
+	if x != nil {
+		return x.NumProofs
+	}
+	return 0
+}
+
+func (x *ProofFileChunk) GetProofIndex() uint32 {
+	if x != nil {
+		return x.ProofIndex
+	}
+	return 0
+}
+
+func (x *ProofFileChunk) GetRawProof() []byte {
+	if x != nil {
+		return x.RawProof
+	}
+	return nil
+}
+
+type GetSplitCommitmentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The anchor outpoint of the transfer output, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,1,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the asset in the transfer output.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The optional asset ID of the asset in the transfer output.
+	AssetId []byte `protobuf:"bytes,3,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+}
+
+func (x *GetSplitCommitmentRequest) Reset() {
+	*x = GetSplitCommitmentRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSplitCommitmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSplitCommitmentRequest) ProtoMessage() {}
+
+func (x *GetSplitCommitmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSplitCommitmentRequest.ProtoReflect.Descriptor instead.
+func (*GetSplitCommitmentRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *GetSplitCommitmentRequest) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *GetSplitCommitmentRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *GetSplitCommitmentRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+type GetSplitCommitmentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether the asset in the transfer output is the root asset of the split
+	// that carries the split commitment root. If false, the asset is a split
+	// that commits to the split root through the inclusion proof.
+	IsSplitRoot bool `protobuf:"varint,1,opt,name=is_split_root,json=isSplitRoot,proto3" json:"is_split_root,omitempty"`
+	// The root hash of the split commitment tree.
+	SplitRootHash []byte `protobuf:"bytes,2,opt,name=split_root_hash,json=splitRootHash,proto3" json:"split_root_hash,omitempty"`
+	// The sum of all asset amounts committed to in the split commitment tree.
+	SplitRootSum uint64 `protobuf:"varint,3,opt,name=split_root_sum,json=splitRootSum,proto3" json:"split_root_sum,omitempty"`
+	// The index of the anchor output that holds the root asset of the split.
+	SplitRootOutputIndex uint32 `protobuf:"varint,4,opt,name=split_root_output_index,json=splitRootOutputIndex,proto3" json:"split_root_output_index,omitempty"`
+	// The script key of the root asset of the split.
+	RootAssetScriptKey []byte `protobuf:"bytes,5,opt,name=root_asset_script_key,json=rootAssetScriptKey,proto3" json:"root_asset_script_key,omitempty"`
+	// The amount of the root asset of the split.
+	RootAssetAmount uint64 `protobuf:"varint,6,opt,name=root_asset_amount,json=rootAssetAmount,proto3" json:"root_asset_amount,omitempty"`
+	// The key of the split's leaf within the split commitment tree. Only set
+	// for a split asset.
+	SplitLeafKey []byte `protobuf:"bytes,7,opt,name=split_leaf_key,json=splitLeafKey,proto3" json:"split_leaf_key,omitempty"`
+	// The compressed MS-SMT inclusion proof of the split's leaf within the
+	// split commitment tree. Only set for a split asset.
+	SplitInclusionProof []byte `protobuf:"bytes,8,opt,name=split_inclusion_proof,json=splitInclusionProof,proto3" json:"split_inclusion_proof,omitempty"`
+	// Whether the inclusion proof resolves to the split commitment root. Only
+	// set for a split asset.
+	InclusionProofValid bool `protobuf:"varint,9,opt,name=inclusion_proof_valid,json=inclusionProofValid,proto3" json:"inclusion_proof_valid,omitempty"`
+}
+
+func (x *GetSplitCommitmentResponse) Reset() {
+	*x = GetSplitCommitmentResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSplitCommitmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSplitCommitmentResponse) ProtoMessage() {}
+
+func (x *GetSplitCommitmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSplitCommitmentResponse.ProtoReflect.Descriptor instead.
+func (*GetSplitCommitmentResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *GetSplitCommitmentResponse) GetIsSplitRoot() bool {
+	if x != nil {
+		return x.IsSplitRoot
+	}
+	return false
+}
+
+func (x *GetSplitCommitmentResponse) GetSplitRootHash() []byte {
+	if x != nil {
+		return x.SplitRootHash
+	}
+	return nil
+}
+
+func (x *GetSplitCommitmentResponse) GetSplitRootSum() uint64 {
+	if x != nil {
+		return x.SplitRootSum
+	}
+	return 0
+}
+
+func (x *GetSplitCommitmentResponse) GetSplitRootOutputIndex() uint32 {
+	if x != nil {
+		return x.SplitRootOutputIndex
+	}
+	return 0
+}
+
+func (x *GetSplitCommitmentResponse) GetRootAssetScriptKey() []byte {
+	if x != nil {
+		return x.RootAssetScriptKey
+	}
+	return nil
+}
+
+func (x *GetSplitCommitmentResponse) GetRootAssetAmount() uint64 {
+	if x != nil {
+		return x.RootAssetAmount
+	}
+	return 0
+}
+
+func (x *GetSplitCommitmentResponse) GetSplitLeafKey() []byte {
+	if x != nil {
+		return x.SplitLeafKey
+	}
+	return nil
+}
+
+func (x *GetSplitCommitmentResponse) GetSplitInclusionProof() []byte {
+	if x != nil {
+		return x.SplitInclusionProof
+	}
+	return nil
+}
+
+func (x *GetSplitCommitmentResponse) GetInclusionProofValid() bool {
+	if x != nil {
+		return x.InclusionProofValid
+	}
+	return false
+}
+
+type GetAnchorMerkleProofRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The anchor outpoint of the transfer output, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,1,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the asset in the transfer output.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The optional asset ID of the asset in the transfer output.
+	AssetId []byte `protobuf:"bytes,3,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+}
+
+func (x *GetAnchorMerkleProofRequest) Reset() {
+	*x = GetAnchorMerkleProofRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[79]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAnchorMerkleProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnchorMerkleProofRequest) ProtoMessage() {}
+
+func (x *GetAnchorMerkleProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[79]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnchorMerkleProofRequest.ProtoReflect.Descriptor instead.
+func (*GetAnchorMerkleProofRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetAnchorMerkleProofRequest) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *GetAnchorMerkleProofRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *GetAnchorMerkleProofRequest) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+type GetAnchorMerkleProofResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the anchor transaction.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The serialized anchor transaction.
+	RawAnchorTx []byte `protobuf:"bytes,2,opt,name=raw_anchor_tx,json=rawAnchorTx,proto3" json:"raw_anchor_tx,omitempty"`
+	// The serialized 80 byte header of the block that confirmed the anchor
+	// transaction.
+	BlockHeader []byte `protobuf:"bytes,3,opt,name=block_header,json=blockHeader,proto3" json:"block_header,omitempty"`
+	// The hash of the block that confirmed the anchor transaction.
+	BlockHash string `protobuf:"bytes,4,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	// The height of the block that confirmed the anchor transaction.
+	BlockHeight uint32 `protobuf:"varint,5,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	// The position of the anchor transaction within the block.
+	TxIndex uint32 `protobuf:"varint,6,opt,name=tx_index,json=txIndex,proto3" json:"tx_index,omitempty"`
+	// The hashes to combine with the anchor transaction's ID to arrive at the
+	// block's merkle root, starting at the leaf level. The hashes are in
+	// internal byte order.
+	MerkleBranch [][]byte `protobuf:"bytes,7,rep,name=merkle_branch,json=merkleBranch,proto3" json:"merkle_branch,omitempty"`
+	// The merkle proof as encoded in the Taproot Assets proof.
+	MerkleProof []byte `protobuf:"bytes,8,opt,name=merkle_proof,json=merkleProof,proto3" json:"merkle_proof,omitempty"`
+	// Whether the merkle branch resolves to the merkle root of the block
+	// header.
+	MerkleProofValid bool `protobuf:"varint,9,opt,name=merkle_proof_valid,json=merkleProofValid,proto3" json:"merkle_proof_valid,omitempty"`
+}
+
+func (x *GetAnchorMerkleProofResponse) Reset() {
+	*x = GetAnchorMerkleProofResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[80]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetAnchorMerkleProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnchorMerkleProofResponse) ProtoMessage() {}
+
+func (x *GetAnchorMerkleProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[80]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnchorMerkleProofResponse.ProtoReflect.Descriptor instead.
+func (*GetAnchorMerkleProofResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetAnchorMerkleProofResponse) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *GetAnchorMerkleProofResponse) GetRawAnchorTx() []byte {
+	if x != nil {
+		return x.RawAnchorTx
+	}
+	return nil
+}
+
+func (x *GetAnchorMerkleProofResponse) GetBlockHeader() []byte {
+	if x != nil {
+		return x.BlockHeader
+	}
+	return nil
+}
+
+func (x *GetAnchorMerkleProofResponse) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *GetAnchorMerkleProofResponse) GetBlockHeight() uint32 {
+	if x != nil {
+		return x.BlockHeight
+	}
+	return 0
+}
+
+func (x *GetAnchorMerkleProofResponse) GetTxIndex() uint32 {
+	if x != nil {
+		return x.TxIndex
+	}
+	return 0
+}
+
+func (x *GetAnchorMerkleProofResponse) GetMerkleBranch() [][]byte {
+	if x != nil {
+		return x.MerkleBranch
+	}
+	return nil
+}
+
+func (x *GetAnchorMerkleProofResponse) GetMerkleProof() []byte {
+	if x != nil {
+		return x.MerkleProof
+	}
+	return nil
+}
+
+func (x *GetAnchorMerkleProofResponse) GetMerkleProofValid() bool {
+	if x != nil {
+		return x.MerkleProofValid
+	}
+	return false
+}
+
+type ListDeliveryReceiptsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The script key the proofs were sent to.
+	ScriptKey []byte `protobuf:"bytes,1,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *ListDeliveryReceiptsRequest) Reset() {
+	*x = ListDeliveryReceiptsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[81]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeliveryReceiptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryReceiptsRequest) ProtoMessage() {}
+
+func (x *ListDeliveryReceiptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[81]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryReceiptsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeliveryReceiptsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ListDeliveryReceiptsRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type DeliveryReceipt struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset ID of the asset the proof was sent for.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key the proof was sent to.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The anchor outpoint of the asset the proof was sent for, in the form
+	// txid:index.
+	AnchorOutpoint string `protobuf:"bytes,3,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The SHA256 hash of the raw proof file the receipt was signed for.
+	ProofHash []byte `protobuf:"bytes,4,opt,name=proof_hash,json=proofHash,proto3" json:"proof_hash,omitempty"`
+	// The internal key of the script key that created the signature.
+	RawScriptKey []byte `protobuf:"bytes,5,opt,name=raw_script_key,json=rawScriptKey,proto3" json:"raw_script_key,omitempty"`
+	// The optional Taproot tweak that is applied to the raw script key to
+	// arrive at the script key. If this is empty then a BIP-86 style tweak is
+	// applied.
+	TapTweak []byte `protobuf:"bytes,6,opt,name=tap_tweak,json=tapTweak,proto3" json:"tap_tweak,omitempty"`
+	// The Schnorr signature over the SHA256 hash of the receipt message.
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The time the receipt was received in unix timestamp seconds.
+	ReceiveTimeUnixSeconds int64 `protobuf:"varint,8,opt,name=receive_time_unix_seconds,json=receiveTimeUnixSeconds,proto3" json:"receive_time_unix_seconds,omitempty"`
+}
+
+func (x *DeliveryReceipt) Reset() {
+	*x = DeliveryReceipt{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[82]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeliveryReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryReceipt) ProtoMessage() {}
+
+func (x *DeliveryReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[82]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryReceipt.ProtoReflect.Descriptor instead.
+func (*DeliveryReceipt) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *DeliveryReceipt) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *DeliveryReceipt) GetProofHash() []byte {
+	if x != nil {
+		return x.ProofHash
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetRawScriptKey() []byte {
+	if x != nil {
+		return x.RawScriptKey
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetTapTweak() []byte {
+	if x != nil {
+		return x.TapTweak
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *DeliveryReceipt) GetReceiveTimeUnixSeconds() int64 {
+	if x != nil {
+		return x.ReceiveTimeUnixSeconds
+	}
+	return 0
+}
+
+type ListDeliveryReceiptsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The delivery receipts, most recent first.
+	Receipts []*DeliveryReceipt `protobuf:"bytes,1,rep,name=receipts,proto3" json:"receipts,omitempty"`
+}
+
+func (x *ListDeliveryReceiptsResponse) Reset() {
+	*x = ListDeliveryReceiptsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[83]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeliveryReceiptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryReceiptsResponse) ProtoMessage() {}
+
+func (x *ListDeliveryReceiptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[83]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryReceiptsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeliveryReceiptsResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *ListDeliveryReceiptsResponse) GetReceipts() []*DeliveryReceipt {
+	if x != nil {
+		return x.Receipts
+	}
+	return nil
+}
+
+type PauseProofDeliveryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the anchor output of the transfer output, in the form
+	// txid:index.
+	AnchorOutpoint string `protobuf:"bytes,1,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the transfer output.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *PauseProofDeliveryRequest) Reset() {
+	*x = PauseProofDeliveryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[84]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseProofDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseProofDeliveryRequest) ProtoMessage() {}
+
+func (x *PauseProofDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[84]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseProofDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*PauseProofDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *PauseProofDeliveryRequest) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *PauseProofDeliveryRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type PauseProofDeliveryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PauseProofDeliveryResponse) Reset() {
+	*x = PauseProofDeliveryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[85]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PauseProofDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseProofDeliveryResponse) ProtoMessage() {}
+
+func (x *PauseProofDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[85]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseProofDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*PauseProofDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{85}
+}
+
+type ResumeProofDeliveryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the anchor output of the transfer output, in the form
+	// txid:index.
+	AnchorOutpoint string `protobuf:"bytes,1,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the transfer output.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *ResumeProofDeliveryRequest) Reset() {
+	*x = ResumeProofDeliveryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[86]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeProofDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeProofDeliveryRequest) ProtoMessage() {}
+
+func (x *ResumeProofDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[86]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeProofDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ResumeProofDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ResumeProofDeliveryRequest) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *ResumeProofDeliveryRequest) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type ResumeProofDeliveryResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ResumeProofDeliveryResponse) Reset() {
+	*x = ResumeProofDeliveryResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[87]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ResumeProofDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeProofDeliveryResponse) ProtoMessage() {}
+
+func (x *ResumeProofDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[87]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeProofDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*ResumeProofDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{87}
+}
+
+type ListDeadLetterProofsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDeadLetterProofsRequest) Reset() {
+	*x = ListDeadLetterProofsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[88]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeadLetterProofsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLetterProofsRequest) ProtoMessage() {}
+
+func (x *ListDeadLetterProofsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[88]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLetterProofsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLetterProofsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{88}
+}
+
+type DeadLetterProof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the on-chain output of the inbound transfer, in the
+	// form txid:index.
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The asset ID of the asset the proof is for, if known.
+	AssetId []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The script key of the asset the proof is for.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The raw proof file that couldn't be imported.
+	RawProofFile []byte `protobuf:"bytes,4,opt,name=raw_proof_file,json=rawProofFile,proto3" json:"raw_proof_file,omitempty"`
+	// The number of times the import was attempted.
+	Attempts uint32 `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// The error of the last import attempt.
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// The time the proof was given up on in unix timestamp seconds.
+	TimeUnixSeconds int64 `protobuf:"varint,7,opt,name=time_unix_seconds,json=timeUnixSeconds,proto3" json:"time_unix_seconds,omitempty"`
+}
+
+func (x *DeadLetterProof) Reset() {
+	*x = DeadLetterProof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[89]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeadLetterProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetterProof) ProtoMessage() {}
+
+func (x *DeadLetterProof) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[89]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetterProof.ProtoReflect.Descriptor instead.
+func (*DeadLetterProof) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *DeadLetterProof) GetOutpoint() string {
+	if x != nil {
+		return x.Outpoint
+	}
+	return ""
+}
+
+func (x *DeadLetterProof) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *DeadLetterProof) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *DeadLetterProof) GetRawProofFile() []byte {
+	if x != nil {
+		return x.RawProofFile
+	}
+	return nil
+}
+
+func (x *DeadLetterProof) GetAttempts() uint32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadLetterProof) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DeadLetterProof) GetTimeUnixSeconds() int64 {
+	if x != nil {
+		return x.TimeUnixSeconds
+	}
+	return 0
+}
+
+type ListDeadLetterProofsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The dead letter proofs, oldest first.
+	DeadLetters []*DeadLetterProof `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+}
+
+func (x *ListDeadLetterProofsResponse) Reset() {
+	*x = ListDeadLetterProofsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[90]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeadLetterProofsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLetterProofsResponse) ProtoMessage() {}
+
+func (x *ListDeadLetterProofsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[90]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLetterProofsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLetterProofsResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ListDeadLetterProofsResponse) GetDeadLetters() []*DeadLetterProof {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+type MergeProofFilesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The raw proof file that contains the beginning of the proof chain,
+	// starting at the genesis proof.
+	PrefixProofFile []byte `protobuf:"bytes,1,opt,name=prefix_proof_file,json=prefixProofFile,proto3" json:"prefix_proof_file,omitempty"`
+	// The raw proof file that continues the proof chain of the prefix. Its
+	// first proof must spend the asset output of the last prefix proof.
+	SuffixProofFile []byte `protobuf:"bytes,2,opt,name=suffix_proof_file,json=suffixProofFile,proto3" json:"suffix_proof_file,omitempty"`
+}
+
+func (x *MergeProofFilesRequest) Reset() {
+	*x = MergeProofFilesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[91]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MergeProofFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeProofFilesRequest) ProtoMessage() {}
+
+func (x *MergeProofFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[91]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeProofFilesRequest.ProtoReflect.Descriptor instead.
+func (*MergeProofFilesRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *MergeProofFilesRequest) GetPrefixProofFile() []byte {
+	if x != nil {
+		return x.PrefixProofFile
+	}
+	return nil
+}
+
+func (x *MergeProofFilesRequest) GetSuffixProofFile() []byte {
+	if x != nil {
+		return x.SuffixProofFile
+	}
+	return nil
+}
+
+type AddrEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The time the event was created in unix timestamp seconds.
+	CreationTimeUnixSeconds uint64 `protobuf:"varint,1,opt,name=creation_time_unix_seconds,json=creationTimeUnixSeconds,proto3" json:"creation_time_unix_seconds,omitempty"`
+	// The address the event was created for.
+	Addr *Addr `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	// The current status of the event.
+	Status AddrEventStatus `protobuf:"varint,3,opt,name=status,proto3,enum=taprpc.AddrEventStatus" json:"status,omitempty"`
+	// The outpoint that contains the inbound asset transfer.
+	Outpoint string `protobuf:"bytes,4,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The amount in satoshis that were transferred on chain along with the asset.
+	// This amount is independent of the requested asset amount, which can be
+	// looked up on the address.
+	UtxoAmtSat uint64 `protobuf:"varint,5,opt,name=utxo_amt_sat,json=utxoAmtSat,proto3" json:"utxo_amt_sat,omitempty"`
+	// The taproot sibling hash that was used to send to the Taproot output.
+	TaprootSibling []byte `protobuf:"bytes,6,opt,name=taproot_sibling,json=taprootSibling,proto3" json:"taproot_sibling,omitempty"`
+	// The height at which the on-chain output was confirmed. If this is zero, it
+	// means the output is unconfirmed.
+	ConfirmationHeight uint32 `protobuf:"varint,7,opt,name=confirmation_height,json=confirmationHeight,proto3" json:"confirmation_height,omitempty"`
+	// Indicates whether a proof file can be found for the address' asset ID and
+	// script key.
+	HasProof bool `protobuf:"varint,8,opt,name=has_proof,json=hasProof,proto3" json:"has_proof,omitempty"`
+	// The number of asset units that were actually received, as seen in the proof
+	// of the transfer. This is zero if no proof was seen yet.
+	AssetAmt uint64 `protobuf:"varint,9,opt,name=asset_amt,json=assetAmt,proto3" json:"asset_amt,omitempty"`
+	// The number of asset units that were received less than requested by the
+	// address. If this is non-zero, the transfer was handled according to the
+	// configured partial receive policy and the receiver might want to follow up
+	// with the sender.
+	AssetShortfall uint64 `protobuf:"varint,10,opt,name=asset_shortfall,json=assetShortfall,proto3" json:"asset_shortfall,omitempty"`
+	// Indicates whether the transfer was quarantined, either by the configured
+	// address reuse policy because it re-used an address that already received
+	// assets or by the configured partial receive policy because it carries fewer
+	// asset units than requested. A quarantined transfer is only completed once
+	// its proof is imported manually.
+	Quarantined bool `protobuf:"varint,11,opt,name=quarantined,proto3" json:"quarantined,omitempty"`
+}
+
+func (x *AddrEvent) Reset() {
+	*x = AddrEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[92]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddrEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddrEvent) ProtoMessage() {}
+
+func (x *AddrEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[92]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddrEvent.ProtoReflect.Descriptor instead.
+func (*AddrEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *AddrEvent) GetCreationTimeUnixSeconds() uint64 {
+	if x != nil {
+		return x.CreationTimeUnixSeconds
+	}
+	return 0
+}
+
+func (x *AddrEvent) GetAddr() *Addr {
+	if x != nil {
+		return x.Addr
+	}
+	return nil
+}
+
+func (x *AddrEvent) GetStatus() AddrEventStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN
+}
+
+func (x *AddrEvent) GetOutpoint() string {
+	if x != nil {
+		return x.Outpoint
+	}
+	return ""
+}
+
+func (x *AddrEvent) GetUtxoAmtSat() uint64 {
+	if x != nil {
+		return x.UtxoAmtSat
+	}
+	return 0
+}
+
+func (x *AddrEvent) GetTaprootSibling() []byte {
+	if x != nil {
+		return x.TaprootSibling
+	}
+	return nil
+}
+
+func (x *AddrEvent) GetConfirmationHeight() uint32 {
+	if x != nil {
+		return x.ConfirmationHeight
+	}
+	return 0
+}
+
+func (x *AddrEvent) GetHasProof() bool {
+	if x != nil {
+		return x.HasProof
+	}
+	return false
+}
+
+func (x *AddrEvent) GetAssetAmt() uint64 {
+	if x != nil {
+		return x.AssetAmt
+	}
+	return 0
+}
+
+func (x *AddrEvent) GetAssetShortfall() uint64 {
+	if x != nil {
+		return x.AssetShortfall
+	}
+	return 0
+}
+
+func (x *AddrEvent) GetQuarantined() bool {
+	if x != nil {
+		return x.Quarantined
+	}
+	return false
+}
+
+type AddrReceivesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Filter receives by a specific address. Leave empty to get all receives.
+	FilterAddr string `protobuf:"bytes,1,opt,name=filter_addr,json=filterAddr,proto3" json:"filter_addr,omitempty"`
+	// Filter receives by a specific status. Leave empty to get all receives.
+	FilterStatus AddrEventStatus `protobuf:"varint,2,opt,name=filter_status,json=filterStatus,proto3,enum=taprpc.AddrEventStatus" json:"filter_status,omitempty"`
+}
+
+func (x *AddrReceivesRequest) Reset() {
+	*x = AddrReceivesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[93]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddrReceivesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddrReceivesRequest) ProtoMessage() {}
+
+func (x *AddrReceivesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[93]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddrReceivesRequest.ProtoReflect.Descriptor instead.
+func (*AddrReceivesRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *AddrReceivesRequest) GetFilterAddr() string {
+	if x != nil {
+		return x.FilterAddr
+	}
+	return ""
+}
+
+func (x *AddrReceivesRequest) GetFilterStatus() AddrEventStatus {
+	if x != nil {
+		return x.FilterStatus
+	}
+	return AddrEventStatus_ADDR_EVENT_STATUS_UNKNOWN
+}
+
+type AddrReceivesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The events that match the filter criteria.
+	Events []*AddrEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *AddrReceivesResponse) Reset() {
+	*x = AddrReceivesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[94]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddrReceivesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddrReceivesResponse) ProtoMessage() {}
+
+func (x *AddrReceivesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[94]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddrReceivesResponse.ProtoReflect.Descriptor instead.
+func (*AddrReceivesResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *AddrReceivesResponse) GetEvents() []*AddrEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type SubscribeReceiveAssetEventNtfnsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The optional script key of the address to receive events for. If not set,
+	// events for all addresses are sent.
+	FilterScriptKey []byte `protobuf:"bytes,1,opt,name=filter_script_key,json=filterScriptKey,proto3" json:"filter_script_key,omitempty"`
+}
+
+func (x *SubscribeReceiveAssetEventNtfnsRequest) Reset() {
+	*x = SubscribeReceiveAssetEventNtfnsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[95]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeReceiveAssetEventNtfnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeReceiveAssetEventNtfnsRequest) ProtoMessage() {}
+
+func (x *SubscribeReceiveAssetEventNtfnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[95]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeReceiveAssetEventNtfnsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeReceiveAssetEventNtfnsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *SubscribeReceiveAssetEventNtfnsRequest) GetFilterScriptKey() []byte {
+	if x != nil {
+		return x.FilterScriptKey
+	}
+	return nil
+}
+
+type ReceiveAssetEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The time the event was created (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The script key of the address the assets are received with.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The ID of the asset that is received.
+	AssetId []byte `protobuf:"bytes,3,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	// The outpoint of the inbound transfer, in the form of "txid:index".
+	Outpoint string `protobuf:"bytes,4,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The status the inbound transfer progressed to.
+	Status ReceiveAssetStatus `protobuf:"varint,5,opt,name=status,proto3,enum=taprpc.ReceiveAssetStatus" json:"status,omitempty"`
+	// The reason the inbound transfer failed, if the status is
+	// RECEIVE_ASSET_STATUS_FAILED.
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ReceiveAssetEvent) Reset() {
+	*x = ReceiveAssetEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[96]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReceiveAssetEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveAssetEvent) ProtoMessage() {}
+
+func (x *ReceiveAssetEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[96]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveAssetEvent.ProtoReflect.Descriptor instead.
+func (*ReceiveAssetEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ReceiveAssetEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReceiveAssetEvent) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *ReceiveAssetEvent) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *ReceiveAssetEvent) GetOutpoint() string {
+	if x != nil {
+		return x.Outpoint
+	}
+	return ""
+}
+
+func (x *ReceiveAssetEvent) GetStatus() ReceiveAssetStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReceiveAssetStatus_RECEIVE_ASSET_STATUS_DETECTED
+}
+
+func (x *ReceiveAssetEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SendAssetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The addresses to send to. Addresses of different asset IDs are funded
+	// separately, but all of them are paid out in the same anchor transaction.
+	TapAddrs []string `protobuf:"bytes,1,rep,name=tap_addrs,json=tapAddrs,proto3" json:"tap_addrs,omitempty"`
+	// The maximum number of asset inputs the transfer may spend. If zero,
+	// the daemon's configured maximum is used, which may be relaxed if the
+	// daemon is configured to relax coin selection. A non-zero value is never
+	// relaxed.
+	MaxInputs uint32 `protobuf:"varint,2,opt,name=max_inputs,json=maxInputs,proto3" json:"max_inputs,omitempty"`
+	// If set, the anchor transaction reserves an additional small wallet
+	// owned output that can be spent by a child transaction to bump its fee
+	// (CPFP). This is always done if the daemon is configured with
+	// fee-bump-anchor.
+	ReserveFeeBumpAnchor bool `protobuf:"varint,3,opt,name=reserve_fee_bump_anchor,json=reserveFeeBumpAnchor,proto3" json:"reserve_fee_bump_anchor,omitempty"`
+	// The fee rate in sat/kw the anchor transaction should be funded with. If
+	// zero, the fee rate is estimated. Fee rates below the minimum relay fee
+	// rate are rejected.
+	FeeRate uint32 `protobuf:"varint,4,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	// If set, the send is only previewed. The assets to spend are selected
+	// and the chain fee is estimated, but nothing is signed, stored or
+	// broadcast and no inputs are locked. The preview is returned as the
+	// estimate of the response.
+	DryRun bool `protobuf:"varint,5,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The anchor outpoints of the assets the send may spend, in the form of
+	// "txid:index". If set, the coin selection is restricted to the assets
+	// anchored at these outpoints, each of which must hold a spendable coin of
+	// the asset the addresses request. If not set, the inputs are selected
+	// automatically.
+	InputOutpoints []string `protobuf:"bytes,6,rep,name=input_outpoints,json=inputOutpoints,proto3" json:"input_outpoints,omitempty"`
+	// The amounts to send to the addresses, in the same order as tap_addrs. An
+	// amount other than the one requested by an address can only be sent to an
+	// address that allows any amount. If an entry is zero or missing, the amount
+	// requested by the address is sent.
+	Amounts []uint64 `protobuf:"varint,7,rep,packed,name=amounts,proto3" json:"amounts,omitempty"`
+	// The optional proof courier the proofs of the transfer are delivered
+	// through, instead of the proof couriers of the addresses. The courier must
+	// use the same protocol, host and port as the proof couriers of the
+	// addresses, as the receivers only listen for their proofs there. Only the
+	// remaining parts of the URL, like the path, may differ. The courier used is
+	// recorded with the transfer outputs.
+	ProofCourierAddr string `protobuf:"bytes,8,opt,name=proof_courier_addr,json=proofCourierAddr,proto3" json:"proof_courier_addr,omitempty"`
+	// An optional human-readable label the transfer is stored with, of at
+	// most 256 bytes.
+	Label string `protobuf:"bytes,9,opt,name=label,proto3" json:"label,omitempty"`
+	// An optional client-supplied key of at most 128 bytes that makes the request
+	// idempotent. If a transfer was already requested with the same key within
+	// the retention window of the node, that transfer is returned instead of
+	// creating a new one. Ignored in dry-run mode.
+	IdempotencyKey string `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+}
+
+func (x *SendAssetRequest) Reset() {
+	*x = SendAssetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[97]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAssetRequest) ProtoMessage() {}
+
+func (x *SendAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[97]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAssetRequest.ProtoReflect.Descriptor instead.
+func (*SendAssetRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *SendAssetRequest) GetTapAddrs() []string {
+	if x != nil {
+		return x.TapAddrs
+	}
+	return nil
+}
+
+func (x *SendAssetRequest) GetMaxInputs() uint32 {
+	if x != nil {
+		return x.MaxInputs
+	}
+	return 0
+}
+
+func (x *SendAssetRequest) GetReserveFeeBumpAnchor() bool {
+	if x != nil {
+		return x.ReserveFeeBumpAnchor
+	}
+	return false
+}
+
+func (x *SendAssetRequest) GetFeeRate() uint32 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *SendAssetRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *SendAssetRequest) GetInputOutpoints() []string {
+	if x != nil {
+		return x.InputOutpoints
+	}
+	return nil
+}
+
+func (x *SendAssetRequest) GetAmounts() []uint64 {
+	if x != nil {
+		return x.Amounts
+	}
+	return nil
+}
+
+func (x *SendAssetRequest) GetProofCourierAddr() string {
+	if x != nil {
+		return x.ProofCourierAddr
+	}
+	return ""
+}
+
+func (x *SendAssetRequest) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *SendAssetRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type PrevInputAsset struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AnchorPoint string `protobuf:"bytes,1,opt,name=anchor_point,json=anchorPoint,proto3" json:"anchor_point,omitempty"`
+	AssetId     []byte `protobuf:"bytes,2,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	ScriptKey   []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	Amount      uint64 `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *PrevInputAsset) Reset() {
+	*x = PrevInputAsset{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[98]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrevInputAsset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrevInputAsset) ProtoMessage() {}
+
+func (x *PrevInputAsset) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[98]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrevInputAsset.ProtoReflect.Descriptor instead.
+func (*PrevInputAsset) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *PrevInputAsset) GetAnchorPoint() string {
+	if x != nil {
+		return x.AnchorPoint
+	}
+	return ""
+}
+
+func (x *PrevInputAsset) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *PrevInputAsset) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *PrevInputAsset) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CoinRelaxation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The coin selection constraint that was relaxed, either confirmations or
+	// maxinputs.
+	Constraint string `protobuf:"bytes,1,opt,name=constraint,proto3" json:"constraint,omitempty"`
+	// The value of the constraint before it was relaxed. For confirmations, this
+	// is the highest number of confirmations required for any of the candidate
+	// inputs.
+	Required uint32 `protobuf:"varint,2,opt,name=required,proto3" json:"required,omitempty"`
+	// The value of the constraint after it was relaxed. A maximum number of
+	// inputs of zero means no limit.
+	RelaxedTo uint32 `protobuf:"varint,3,opt,name=relaxed_to,json=relaxedTo,proto3" json:"relaxed_to,omitempty"`
+}
+
+func (x *CoinRelaxation) Reset() {
+	*x = CoinRelaxation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[99]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CoinRelaxation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoinRelaxation) ProtoMessage() {}
+
+func (x *CoinRelaxation) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[99]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoinRelaxation.ProtoReflect.Descriptor instead.
+func (*CoinRelaxation) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *CoinRelaxation) GetConstraint() string {
+	if x != nil {
+		return x.Constraint
+	}
+	return ""
+}
+
+func (x *CoinRelaxation) GetRequired() uint32 {
+	if x != nil {
+		return x.Required
+	}
+	return 0
+}
+
+func (x *CoinRelaxation) GetRelaxedTo() uint32 {
+	if x != nil {
+		return x.RelaxedTo
+	}
+	return 0
+}
+
+type SendAssetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// The coin selection constraints that had to be relaxed to fund the
+	// transfer. This is empty unless the daemon is configured to relax coin
+	// selection constraints.
+	CoinRelaxations []*CoinRelaxation `protobuf:"bytes,2,rep,name=coin_relaxations,json=coinRelaxations,proto3" json:"coin_relaxations,omitempty"`
+	// The preview of the send if dry_run was set in the request. The transfer
+	// is empty in that case.
+	Estimate *SendAssetEstimate `protobuf:"bytes,3,opt,name=estimate,proto3" json:"estimate,omitempty"`
+}
+
+func (x *SendAssetResponse) Reset() {
+	*x = SendAssetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[100]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAssetResponse) ProtoMessage() {}
+
+func (x *SendAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[100]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAssetResponse.ProtoReflect.Descriptor instead.
+func (*SendAssetResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *SendAssetResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *SendAssetResponse) GetCoinRelaxations() []*CoinRelaxation {
+	if x != nil {
+		return x.CoinRelaxations
+	}
+	return nil
+}
+
+func (x *SendAssetResponse) GetEstimate() *SendAssetEstimate {
+	if x != nil {
+		return x.Estimate
+	}
+	return nil
+}
+
+type SendAssetEstimate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset inputs the send would spend.
+	Inputs []*TransferInput `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// The projected asset outputs of the send, including the change output.
+	Outputs []*SendAssetEstimateOutput `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	// The number of passive assets that would be re-anchored by the send.
+	NumPassiveAssets uint32 `protobuf:"varint,3,opt,name=num_passive_assets,json=numPassiveAssets,proto3" json:"num_passive_assets,omitempty"`
+	// The fee rate in sat/kw the chain fee was estimated with.
+	FeeRate uint32 `protobuf:"varint,4,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	// The estimated amount of sats the anchor transaction would pay in chain
+	// fees. This assumes the wallet funds the anchor transaction with a single
+	// input and a change output.
+	ChainFees int64 `protobuf:"varint,5,opt,name=chain_fees,json=chainFees,proto3" json:"chain_fees,omitempty"`
+}
+
+func (x *SendAssetEstimate) Reset() {
+	*x = SendAssetEstimate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[101]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAssetEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAssetEstimate) ProtoMessage() {}
+
+func (x *SendAssetEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[101]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAssetEstimate.ProtoReflect.Descriptor instead.
+func (*SendAssetEstimate) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *SendAssetEstimate) GetInputs() []*TransferInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *SendAssetEstimate) GetOutputs() []*SendAssetEstimateOutput {
+	if x != nil {
+		return x.Outputs
+	}
+	return nil
+}
+
+func (x *SendAssetEstimate) GetNumPassiveAssets() uint32 {
+	if x != nil {
+		return x.NumPassiveAssets
+	}
+	return 0
+}
+
+func (x *SendAssetEstimate) GetFeeRate() uint32 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *SendAssetEstimate) GetChainFees() int64 {
+	if x != nil {
+		return x.ChainFees
+	}
+	return 0
+}
+
+type SendAssetEstimateOutput struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The index of the anchor transaction output the asset output would be
+	// committed to.
+	AnchorOutputIndex uint32 `protobuf:"varint,1,opt,name=anchor_output_index,json=anchorOutputIndex,proto3" json:"anchor_output_index,omitempty"`
+	// The script key of the asset output.
+	ScriptKey []byte `protobuf:"bytes,2,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The amount of the asset output.
+	Amount uint64 `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The type of the asset output.
+	OutputType OutputType `protobuf:"varint,4,opt,name=output_type,json=outputType,proto3,enum=taprpc.OutputType" json:"output_type,omitempty"`
+	// Whether the output returns the change of the send to this daemon.
+	IsChange bool `protobuf:"varint,5,opt,name=is_change,json=isChange,proto3" json:"is_change,omitempty"`
+	// The ID of the asset of the output.
+	AssetId []byte `protobuf:"bytes,6,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+}
+
+func (x *SendAssetEstimateOutput) Reset() {
+	*x = SendAssetEstimateOutput{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[102]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAssetEstimateOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAssetEstimateOutput) ProtoMessage() {}
+
+func (x *SendAssetEstimateOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[102]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAssetEstimateOutput.ProtoReflect.Descriptor instead.
+func (*SendAssetEstimateOutput) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *SendAssetEstimateOutput) GetAnchorOutputIndex() uint32 {
+	if x != nil {
+		return x.AnchorOutputIndex
+	}
+	return 0
+}
+
+func (x *SendAssetEstimateOutput) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *SendAssetEstimateOutput) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *SendAssetEstimateOutput) GetOutputType() OutputType {
+	if x != nil {
+		return x.OutputType
+	}
+	return OutputType_OUTPUT_TYPE_SIMPLE
+}
+
+func (x *SendAssetEstimateOutput) GetIsChange() bool {
+	if x != nil {
+		return x.IsChange
+	}
+	return false
+}
+
+func (x *SendAssetEstimateOutput) GetAssetId() []byte {
+	if x != nil {
+		return x.AssetId
+	}
+	return nil
+}
+
+type PrepareTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TapAddrs []string `protobuf:"bytes,1,rep,name=tap_addrs,json=tapAddrs,proto3" json:"tap_addrs,omitempty"`
+	// The maximum number of asset inputs the transfer may spend. If zero,
+	// the daemon's configured maximum is used, which may be relaxed if the
+	// daemon is configured to relax coin selection. A non-zero value is never
+	// relaxed.
+	MaxInputs uint32 `protobuf:"varint,2,opt,name=max_inputs,json=maxInputs,proto3" json:"max_inputs,omitempty"`
+	// If set, the anchor transaction reserves an additional small wallet
+	// owned output that can be spent by a child transaction to bump its fee
+	// (CPFP). This is always done if the daemon is configured with
+	// fee-bump-anchor.
+	ReserveFeeBumpAnchor bool `protobuf:"varint,3,opt,name=reserve_fee_bump_anchor,json=reserveFeeBumpAnchor,proto3" json:"reserve_fee_bump_anchor,omitempty"`
+}
+
+func (x *PrepareTransferRequest) Reset() {
+	*x = PrepareTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[103]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrepareTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareTransferRequest) ProtoMessage() {}
+
+func (x *PrepareTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[103]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareTransferRequest.ProtoReflect.Descriptor instead.
+func (*PrepareTransferRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *PrepareTransferRequest) GetTapAddrs() []string {
+	if x != nil {
+		return x.TapAddrs
+	}
+	return nil
+}
+
+func (x *PrepareTransferRequest) GetMaxInputs() uint32 {
+	if x != nil {
+		return x.MaxInputs
+	}
+	return 0
+}
+
+func (x *PrepareTransferRequest) GetReserveFeeBumpAnchor() bool {
+	if x != nil {
+		return x.ReserveFeeBumpAnchor
+	}
+	return false
+}
+
+type PrepareTransferResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The staged transfer.
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// The hash of the anchor transaction of the staged transfer, which is the
+	// handle used to release the transfer with BroadcastTransfer.
+	AnchorTxid string `protobuf:"bytes,2,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The coin selection constraints that had to be relaxed to fund the
+	// transfer. This is empty unless the daemon is configured to relax coin
+	// selection constraints.
+	CoinRelaxations []*CoinRelaxation `protobuf:"bytes,3,rep,name=coin_relaxations,json=coinRelaxations,proto3" json:"coin_relaxations,omitempty"`
+}
+
+func (x *PrepareTransferResponse) Reset() {
+	*x = PrepareTransferResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[104]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PrepareTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrepareTransferResponse) ProtoMessage() {}
+
+func (x *PrepareTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[104]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrepareTransferResponse.ProtoReflect.Descriptor instead.
+func (*PrepareTransferResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *PrepareTransferResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *PrepareTransferResponse) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *PrepareTransferResponse) GetCoinRelaxations() []*CoinRelaxation {
+	if x != nil {
+		return x.CoinRelaxations
+	}
+	return nil
+}
+
+type BroadcastTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hash of the anchor transaction of the staged transfer, as returned
+	// by PrepareTransfer.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+}
+
+func (x *BroadcastTransferRequest) Reset() {
+	*x = BroadcastTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[105]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTransferRequest) ProtoMessage() {}
+
+func (x *BroadcastTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[105]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTransferRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastTransferRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *BroadcastTransferRequest) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+type BroadcastTransferResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+}
+
+func (x *BroadcastTransferResponse) Reset() {
+	*x = BroadcastTransferResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[106]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BroadcastTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTransferResponse) ProtoMessage() {}
+
+func (x *BroadcastTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[106]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTransferResponse.ProtoReflect.Descriptor instead.
+func (*BroadcastTransferResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *BroadcastTransferResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type ExportPendingTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hash of the anchor transaction of the pending transfer to export.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+}
+
+func (x *ExportPendingTransferRequest) Reset() {
+	*x = ExportPendingTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[107]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportPendingTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPendingTransferRequest) ProtoMessage() {}
+
+func (x *ExportPendingTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[107]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPendingTransferRequest.ProtoReflect.Descriptor instead.
+func (*ExportPendingTransferRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *ExportPendingTransferRequest) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+type ExportPendingTransferResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The serialized state of the pending transfer, including the proofs of
+	// its inputs.
+	TransferPackage []byte `protobuf:"bytes,1,opt,name=transfer_package,json=transferPackage,proto3" json:"transfer_package,omitempty"`
+}
+
+func (x *ExportPendingTransferResponse) Reset() {
+	*x = ExportPendingTransferResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[108]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExportPendingTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportPendingTransferResponse) ProtoMessage() {}
+
+func (x *ExportPendingTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[108]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportPendingTransferResponse.ProtoReflect.Descriptor instead.
+func (*ExportPendingTransferResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{108}
+}
+
+func (x *ExportPendingTransferResponse) GetTransferPackage() []byte {
+	if x != nil {
+		return x.TransferPackage
+	}
+	return nil
+}
+
+type ImportPendingTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The serialized state of the pending transfer, as returned by
+	// ExportPendingTransfer.
+	TransferPackage []byte `protobuf:"bytes,1,opt,name=transfer_package,json=transferPackage,proto3" json:"transfer_package,omitempty"`
+}
+
+func (x *ImportPendingTransferRequest) Reset() {
+	*x = ImportPendingTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[109]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportPendingTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportPendingTransferRequest) ProtoMessage() {}
+
+func (x *ImportPendingTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[109]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportPendingTransferRequest.ProtoReflect.Descriptor instead.
+func (*ImportPendingTransferRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *ImportPendingTransferRequest) GetTransferPackage() []byte {
+	if x != nil {
+		return x.TransferPackage
+	}
+	return nil
+}
+
+type ImportPendingTransferResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The imported transfer.
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+}
+
+func (x *ImportPendingTransferResponse) Reset() {
+	*x = ImportPendingTransferResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[110]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportPendingTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportPendingTransferResponse) ProtoMessage() {}
+
+func (x *ImportPendingTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[110]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportPendingTransferResponse.ProtoReflect.Descriptor instead.
+func (*ImportPendingTransferResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *ImportPendingTransferResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type BumpTransferFeeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hash of the unconfirmed anchor transaction of the pending transfer
+	// to bump the fee of.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The fee rate in sat/kw the replacement anchor transaction should pay.
+	// The fee of the replacement must exceed the fee of the anchor transaction
+	// it replaces by at least the minimum relay fee.
+	FeeRate uint32 `protobuf:"varint,2,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	// If set, the anchor transaction isn't replaced. Instead, a child
+	// transaction spending the fee bump anchor output of the anchor transaction
+	// is published, paying enough fees for both transactions together to reach
+	// the given fee rate. This requires the transfer to have been sent with a
+	// fee bump anchor output.
+	UseFeeBumpAnchor bool `protobuf:"varint,3,opt,name=use_fee_bump_anchor,json=useFeeBumpAnchor,proto3" json:"use_fee_bump_anchor,omitempty"`
+}
+
+func (x *BumpTransferFeeRequest) Reset() {
+	*x = BumpTransferFeeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[111]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BumpTransferFeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BumpTransferFeeRequest) ProtoMessage() {}
+
+func (x *BumpTransferFeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[111]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BumpTransferFeeRequest.ProtoReflect.Descriptor instead.
+func (*BumpTransferFeeRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *BumpTransferFeeRequest) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *BumpTransferFeeRequest) GetFeeRate() uint32 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *BumpTransferFeeRequest) GetUseFeeBumpAnchor() bool {
+	if x != nil {
+		return x.UseFeeBumpAnchor
+	}
+	return false
+}
+
+type BumpTransferFeeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The transfer, referencing the replacement anchor transaction.
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	// The hash of the published child transaction, if the fee was bumped by
+	// spending the fee bump anchor output.
+	ChildTxid string `protobuf:"bytes,2,opt,name=child_txid,json=childTxid,proto3" json:"child_txid,omitempty"`
+}
+
+func (x *BumpTransferFeeResponse) Reset() {
+	*x = BumpTransferFeeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[112]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BumpTransferFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BumpTransferFeeResponse) ProtoMessage() {}
+
+func (x *BumpTransferFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[112]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BumpTransferFeeResponse.ProtoReflect.Descriptor instead.
+func (*BumpTransferFeeResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *BumpTransferFeeResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+func (x *BumpTransferFeeResponse) GetChildTxid() string {
+	if x != nil {
+		return x.ChildTxid
+	}
+	return ""
+}
+
+type CancelTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The hash of the anchor transaction of the pending transfer to cancel.
+	AnchorTxid string `protobuf:"bytes,1,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+}
+
+func (x *CancelTransferRequest) Reset() {
+	*x = CancelTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[113]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransferRequest) ProtoMessage() {}
+
+func (x *CancelTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[113]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransferRequest.ProtoReflect.Descriptor instead.
+func (*CancelTransferRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *CancelTransferRequest) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+type CancelTransferResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The cancelled transfer.
+	Transfer *AssetTransfer `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+}
+
+func (x *CancelTransferResponse) Reset() {
+	*x = CancelTransferResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[114]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CancelTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTransferResponse) ProtoMessage() {}
+
+func (x *CancelTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[114]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTransferResponse.ProtoReflect.Descriptor instead.
+func (*CancelTransferResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *CancelTransferResponse) GetTransfer() *AssetTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type GetInfoRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[115]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[115]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{115}
+}
+
+type GetInfoResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version    string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	LndVersion string `protobuf:"bytes,2,opt,name=lnd_version,json=lndVersion,proto3" json:"lnd_version,omitempty"`
+	Network    string `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[116]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[116]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *GetInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetLndVersion() string {
+	if x != nil {
+		return x.LndVersion
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[117]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[117]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{117}
+}
+
+type GetConfigResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The network the daemon is running on.
+	Network string `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	// The host:port of the servers that are currently members of the local
+	// universe federation.
+	FederationServers []string `protobuf:"bytes,2,rep,name=federation_servers,json=federationServers,proto3" json:"federation_servers,omitempty"`
+	// All config options keyed by their long option name, prefixed by the
+	// namespace of their group (for example "universe.public-access").
+	// Sensitive values are redacted.
+	Config map[string]string `protobuf:"bytes,3,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *GetConfigResponse) Reset() {
+	*x = GetConfigResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[118]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigResponse) ProtoMessage() {}
+
+func (x *GetConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[118]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *GetConfigResponse) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *GetConfigResponse) GetFederationServers() []string {
+	if x != nil {
+		return x.FederationServers
+	}
+	return nil
+}
+
+func (x *GetConfigResponse) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type GetSendStateMachineRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetSendStateMachineRequest) Reset() {
+	*x = GetSendStateMachineRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[119]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSendStateMachineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSendStateMachineRequest) ProtoMessage() {}
+
+func (x *GetSendStateMachineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[119]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSendStateMachineRequest.ProtoReflect.Descriptor instead.
+func (*GetSendStateMachineRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{119}
+}
+
+type SendStateDefinition struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the send state, as used in the execute send state event.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Whether a transfer can start out in this state. Transfers to addresses
+	// start with the coin selection, pre-signed transfers with the anchor
+	// signing and pending transfers that are resumed with the broadcast.
+	Initial bool `protobuf:"varint,2,opt,name=initial,proto3" json:"initial,omitempty"`
+	// The names of the states a transfer can move to from this state. This is
+	// empty for the final state.
+	Successors []string `protobuf:"bytes,3,rep,name=successors,proto3" json:"successors,omitempty"`
+}
+
+func (x *SendStateDefinition) Reset() {
+	*x = SendStateDefinition{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[120]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendStateDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendStateDefinition) ProtoMessage() {}
+
+func (x *SendStateDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[120]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendStateDefinition.ProtoReflect.Descriptor instead.
+func (*SendStateDefinition) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *SendStateDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SendStateDefinition) GetInitial() bool {
+	if x != nil {
+		return x.Initial
+	}
+	return false
+}
+
+func (x *SendStateDefinition) GetSuccessors() []string {
+	if x != nil {
+		return x.Successors
+	}
+	return nil
+}
+
+type GetSendStateMachineResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// All send states in the order a transfer moves through them.
+	States []*SendStateDefinition `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
+}
+
+func (x *GetSendStateMachineResponse) Reset() {
+	*x = GetSendStateMachineResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[121]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSendStateMachineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSendStateMachineResponse) ProtoMessage() {}
+
+func (x *GetSendStateMachineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[121]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSendStateMachineResponse.ProtoReflect.Descriptor instead.
+func (*GetSendStateMachineResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *GetSendStateMachineResponse) GetStates() []*SendStateDefinition {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type SubscribeSendAssetEventNtfnsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set, the persisted events that were emitted at or after this
+	// timestamp (in microseconds) are replayed before the live events are
+	// streamed. Events older than the configured send event retention period
+	// can't be replayed.
+	StartTimestamp int64 `protobuf:"varint,1,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	// If set, the persisted events with an ID greater than this one are
+	// replayed before the live events are streamed. This is usually the
+	// event_id of the last event received before the client reconnects.
+	AfterEventId uint64 `protobuf:"varint,2,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"`
+}
+
+func (x *SubscribeSendAssetEventNtfnsRequest) Reset() {
+	*x = SubscribeSendAssetEventNtfnsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[122]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeSendAssetEventNtfnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeSendAssetEventNtfnsRequest) ProtoMessage() {}
+
+func (x *SubscribeSendAssetEventNtfnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[122]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeSendAssetEventNtfnsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeSendAssetEventNtfnsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{122}
+}
+
+func (x *SubscribeSendAssetEventNtfnsRequest) GetStartTimestamp() int64 {
+	if x != nil {
+		return x.StartTimestamp
+	}
+	return 0
+}
+
+func (x *SubscribeSendAssetEventNtfnsRequest) GetAfterEventId() uint64 {
+	if x != nil {
+		return x.AfterEventId
+	}
+	return 0
+}
+
+type SendAssetEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Event:
+	//
+	//	*SendAssetEvent_ExecuteSendStateEvent
+	//	*SendAssetEvent_ReceiverProofBackoffWaitEvent
+	//	*SendAssetEvent_TransferAbandonedEvent
+	//	*SendAssetEvent_TransferRetryEvent
+	//	*SendAssetEvent_TransferFeeBumpedEvent
+	//	*SendAssetEvent_ReceiverProofDeliveryPausedEvent
+	//	*SendAssetEvent_ReceiverProofDeliveredEvent
+	//	*SendAssetEvent_TransferCompleteEvent
+	//	*SendAssetEvent_TransferReorgEvent
+	//	*SendAssetEvent_TransferCancelledEvent
+	Event isSendAssetEvent_Event `protobuf_oneof:"event"`
+	// The ID of the event. The IDs of the events increase monotonically in
+	// the order the events were emitted in. Zero if the event couldn't be
+	// persisted, in which case it can't be replayed.
+	EventId uint64 `protobuf:"varint,11,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+}
+
+func (x *SendAssetEvent) Reset() {
+	*x = SendAssetEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[123]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SendAssetEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendAssetEvent) ProtoMessage() {}
+
+func (x *SendAssetEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[123]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendAssetEvent.ProtoReflect.Descriptor instead.
+func (*SendAssetEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{123}
+}
+
+func (m *SendAssetEvent) GetEvent() isSendAssetEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetExecuteSendStateEvent() *ExecuteSendStateEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_ExecuteSendStateEvent); ok {
+		return x.ExecuteSendStateEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetReceiverProofBackoffWaitEvent() *ReceiverProofBackoffWaitEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_ReceiverProofBackoffWaitEvent); ok {
+		return x.ReceiverProofBackoffWaitEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferAbandonedEvent() *TransferAbandonedEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferAbandonedEvent); ok {
+		return x.TransferAbandonedEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferRetryEvent() *TransferRetryEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferRetryEvent); ok {
+		return x.TransferRetryEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferFeeBumpedEvent() *TransferFeeBumpedEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferFeeBumpedEvent); ok {
+		return x.TransferFeeBumpedEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetReceiverProofDeliveryPausedEvent() *ReceiverProofDeliveryPausedEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_ReceiverProofDeliveryPausedEvent); ok {
+		return x.ReceiverProofDeliveryPausedEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetReceiverProofDeliveredEvent() *ReceiverProofDeliveredEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_ReceiverProofDeliveredEvent); ok {
+		return x.ReceiverProofDeliveredEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferCompleteEvent() *TransferCompleteEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferCompleteEvent); ok {
+		return x.TransferCompleteEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferReorgEvent() *TransferReOrgEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferReorgEvent); ok {
+		return x.TransferReorgEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetTransferCancelledEvent() *TransferCancelledEvent {
+	if x, ok := x.GetEvent().(*SendAssetEvent_TransferCancelledEvent); ok {
+		return x.TransferCancelledEvent
+	}
+	return nil
+}
+
+func (x *SendAssetEvent) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+type isSendAssetEvent_Event interface {
+	isSendAssetEvent_Event()
+}
+
+type SendAssetEvent_ExecuteSendStateEvent struct {
+	// An event which indicates that a send state is about to be executed.
+	ExecuteSendStateEvent *ExecuteSendStateEvent `protobuf:"bytes,1,opt,name=execute_send_state_event,json=executeSendStateEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_ReceiverProofBackoffWaitEvent struct {
+	// An event which indicates that the proof send backoff wait period will
+	// start imminently.
+	ReceiverProofBackoffWaitEvent *ReceiverProofBackoffWaitEvent `protobuf:"bytes,2,opt,name=receiver_proof_backoff_wait_event,json=receiverProofBackoffWaitEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferAbandonedEvent struct {
+	// An event which indicates that the anchor transaction of a transfer
+	// can no longer confirm because one of its inputs was double spent.
+	TransferAbandonedEvent *TransferAbandonedEvent `protobuf:"bytes,3,opt,name=transfer_abandoned_event,json=transferAbandonedEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferRetryEvent struct {
+	// An event which indicates that an abandoned transfer was re-attempted
+	// with a new anchor transaction and freshly selected coins.
+	TransferRetryEvent *TransferRetryEvent `protobuf:"bytes,4,opt,name=transfer_retry_event,json=transferRetryEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferFeeBumpedEvent struct {
+	// An event which indicates that the anchor transaction of a pending
+	// transfer was replaced with one that pays a higher fee rate.
+	TransferFeeBumpedEvent *TransferFeeBumpedEvent `protobuf:"bytes,5,opt,name=transfer_fee_bumped_event,json=transferFeeBumpedEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_ReceiverProofDeliveryPausedEvent struct {
+	// An event which indicates that the delivery of a proof is held back
+	// because it was paused by the user.
+	ReceiverProofDeliveryPausedEvent *ReceiverProofDeliveryPausedEvent `protobuf:"bytes,6,opt,name=receiver_proof_delivery_paused_event,json=receiverProofDeliveryPausedEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_ReceiverProofDeliveredEvent struct {
+	// An event which indicates that the delivery of a proof to one of the
+	// receivers of a transfer finished, either successfully or not.
+	ReceiverProofDeliveredEvent *ReceiverProofDeliveredEvent `protobuf:"bytes,7,opt,name=receiver_proof_delivered_event,json=receiverProofDeliveredEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferCompleteEvent struct {
+	// An event which indicates that a transfer is complete according to
+	// the configured proof delivery completion policy.
+	TransferCompleteEvent *TransferCompleteEvent `protobuf:"bytes,8,opt,name=transfer_complete_event,json=transferCompleteEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferReorgEvent struct {
+	// An event which indicates that the confirmed anchor transaction of a
+	// transfer was re-organized out of the best chain and the configured
+	// reorg policy was applied.
+	TransferReorgEvent *TransferReOrgEvent `protobuf:"bytes,9,opt,name=transfer_reorg_event,json=transferReorgEvent,proto3,oneof"`
+}
+
+type SendAssetEvent_TransferCancelledEvent struct {
+	// An event which indicates that a pending transfer was cancelled and
+	// its asset inputs were released.
+	TransferCancelledEvent *TransferCancelledEvent `protobuf:"bytes,10,opt,name=transfer_cancelled_event,json=transferCancelledEvent,proto3,oneof"`
+}
+
+func (*SendAssetEvent_ExecuteSendStateEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_ReceiverProofBackoffWaitEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferAbandonedEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferRetryEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferFeeBumpedEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_ReceiverProofDeliveryPausedEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_ReceiverProofDeliveredEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferCompleteEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferReorgEvent) isSendAssetEvent_Event() {}
+
+func (*SendAssetEvent_TransferCancelledEvent) isSendAssetEvent_Event() {}
+
+type ExecuteSendStateEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Execute timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The send state that is about to be executed.
+	SendState string `protobuf:"bytes,2,opt,name=send_state,json=sendState,proto3" json:"send_state,omitempty"`
+	// The label of the transfer the send state is executed for, if any.
+	Label string `protobuf:"bytes,3,opt,name=label,proto3" json:"label,omitempty"`
+}
+
+func (x *ExecuteSendStateEvent) Reset() {
+	*x = ExecuteSendStateEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[124]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExecuteSendStateEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteSendStateEvent) ProtoMessage() {}
+
+func (x *ExecuteSendStateEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[124]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteSendStateEvent.ProtoReflect.Descriptor instead.
+func (*ExecuteSendStateEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *ExecuteSendStateEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ExecuteSendStateEvent) GetSendState() string {
+	if x != nil {
+		return x.SendState
+	}
+	return ""
+}
+
+func (x *ExecuteSendStateEvent) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+type ReceiverProofBackoffWaitEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Transfer attempt timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Backoff is the active backoff wait duration.
+	Backoff int64 `protobuf:"varint,2,opt,name=backoff,proto3" json:"backoff,omitempty"`
+	// Tries counter is the number of tries we've made so far during the
+	// course of the current backoff procedure to deliver the proof to the
+	// receiver.
+	TriesCounter int64 `protobuf:"varint,3,opt,name=tries_counter,json=triesCounter,proto3" json:"tries_counter,omitempty"`
+	// The configured number of tries of the current backoff procedure.
+	NumTries int64 `protobuf:"varint,4,opt,name=num_tries,json=numTries,proto3" json:"num_tries,omitempty"`
+	// The timestamp (microseconds) at which the next delivery attempt is
+	// made, once the backoff wait is over.
+	NextAttemptTimestamp int64 `protobuf:"varint,5,opt,name=next_attempt_timestamp,json=nextAttemptTimestamp,proto3" json:"next_attempt_timestamp,omitempty"`
+}
+
+func (x *ReceiverProofBackoffWaitEvent) Reset() {
+	*x = ReceiverProofBackoffWaitEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[125]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReceiverProofBackoffWaitEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiverProofBackoffWaitEvent) ProtoMessage() {}
+
+func (x *ReceiverProofBackoffWaitEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[125]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiverProofBackoffWaitEvent.ProtoReflect.Descriptor instead.
+func (*ReceiverProofBackoffWaitEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{125}
+}
+
+func (x *ReceiverProofBackoffWaitEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReceiverProofBackoffWaitEvent) GetBackoff() int64 {
+	if x != nil {
+		return x.Backoff
+	}
+	return 0
+}
+
+func (x *ReceiverProofBackoffWaitEvent) GetTriesCounter() int64 {
+	if x != nil {
+		return x.TriesCounter
+	}
+	return 0
+}
+
+func (x *ReceiverProofBackoffWaitEvent) GetNumTries() int64 {
+	if x != nil {
+		return x.NumTries
+	}
+	return 0
+}
+
+func (x *ReceiverProofBackoffWaitEvent) GetNextAttemptTimestamp() int64 {
+	if x != nil {
+		return x.NextAttemptTimestamp
+	}
+	return 0
+}
+
+type ReceiverProofDeliveryPausedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Pause timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The outpoint of the anchor output of the transfer output the proof is
+	// delivered for, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,2,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the transfer output the proof is delivered for.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+}
+
+func (x *ReceiverProofDeliveryPausedEvent) Reset() {
+	*x = ReceiverProofDeliveryPausedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[126]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReceiverProofDeliveryPausedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiverProofDeliveryPausedEvent) ProtoMessage() {}
+
+func (x *ReceiverProofDeliveryPausedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[126]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiverProofDeliveryPausedEvent.ProtoReflect.Descriptor instead.
+func (*ReceiverProofDeliveryPausedEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{126}
+}
+
+func (x *ReceiverProofDeliveryPausedEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReceiverProofDeliveryPausedEvent) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *ReceiverProofDeliveryPausedEvent) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+type ReceiverProofDeliveredEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Delivery timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The outpoint of the anchor output of the transfer output the proof was
+	// delivered for, in the form txid:index.
+	AnchorOutpoint string `protobuf:"bytes,2,opt,name=anchor_outpoint,json=anchorOutpoint,proto3" json:"anchor_outpoint,omitempty"`
+	// The script key of the transfer output the proof was delivered for.
+	ScriptKey []byte `protobuf:"bytes,3,opt,name=script_key,json=scriptKey,proto3" json:"script_key,omitempty"`
+	// The error that prevented the delivery of the proof, if any.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// The address of the proof courier the delivery was attempted through.
+	// This is empty if the delivery was skipped.
+	CourierAddr string `protobuf:"bytes,5,opt,name=courier_addr,json=courierAddr,proto3" json:"courier_addr,omitempty"`
+	// Whether the proof was delivered through the fallback courier, as the
+	// courier of the receiver failed all delivery attempts.
+	Fallback bool `protobuf:"varint,6,opt,name=fallback,proto3" json:"fallback,omitempty"`
+}
+
+func (x *ReceiverProofDeliveredEvent) Reset() {
+	*x = ReceiverProofDeliveredEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[127]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReceiverProofDeliveredEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiverProofDeliveredEvent) ProtoMessage() {}
+
+func (x *ReceiverProofDeliveredEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[127]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiverProofDeliveredEvent.ProtoReflect.Descriptor instead.
+func (*ReceiverProofDeliveredEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{127}
+}
+
+func (x *ReceiverProofDeliveredEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReceiverProofDeliveredEvent) GetAnchorOutpoint() string {
+	if x != nil {
+		return x.AnchorOutpoint
+	}
+	return ""
+}
+
+func (x *ReceiverProofDeliveredEvent) GetScriptKey() []byte {
+	if x != nil {
+		return x.ScriptKey
+	}
+	return nil
+}
+
+func (x *ReceiverProofDeliveredEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReceiverProofDeliveredEvent) GetCourierAddr() string {
+	if x != nil {
+		return x.CourierAddr
+	}
+	return ""
+}
+
+func (x *ReceiverProofDeliveredEvent) GetFallback() bool {
+	if x != nil {
+		return x.Fallback
+	}
+	return false
+}
+
+type TransferCompleteEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Completion timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the anchor transaction of the transfer.
+	AnchorTxid string `protobuf:"bytes,2,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The number of receivers the proof needs to be delivered to.
+	NumReceivers uint32 `protobuf:"varint,3,opt,name=num_receivers,json=numReceivers,proto3" json:"num_receivers,omitempty"`
+	// The number of receivers the proof was delivered to at the time the
+	// transfer was completed. The proofs of the remaining receivers continue
+	// to be delivered in the background.
+	NumDelivered uint32 `protobuf:"varint,4,opt,name=num_delivered,json=numDelivered,proto3" json:"num_delivered,omitempty"`
+}
+
+func (x *TransferCompleteEvent) Reset() {
+	*x = TransferCompleteEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[128]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferCompleteEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferCompleteEvent) ProtoMessage() {}
+
+func (x *TransferCompleteEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[128]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferCompleteEvent.ProtoReflect.Descriptor instead.
+func (*TransferCompleteEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{128}
+}
+
+func (x *TransferCompleteEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferCompleteEvent) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferCompleteEvent) GetNumReceivers() uint32 {
+	if x != nil {
+		return x.NumReceivers
+	}
+	return 0
+}
+
+func (x *TransferCompleteEvent) GetNumDelivered() uint32 {
+	if x != nil {
+		return x.NumDelivered
+	}
+	return 0
+}
+
+type TransferReOrgEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Event timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the anchor transaction that was re-organized out
+	// of the best chain.
+	AnchorTxid string `protobuf:"bytes,2,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The reorg policy that was applied to the transfer, which is one of
+	// "rebroadcast", "abandon" or "quarantine". A transfer that can't be
+	// abandoned is quarantined instead.
+	Policy string `protobuf:"bytes,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// The error that prevented the policy from being applied, if any.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *TransferReOrgEvent) Reset() {
+	*x = TransferReOrgEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[129]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferReOrgEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferReOrgEvent) ProtoMessage() {}
+
+func (x *TransferReOrgEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[129]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferReOrgEvent.ProtoReflect.Descriptor instead.
+func (*TransferReOrgEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{129}
+}
+
+func (x *TransferReOrgEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferReOrgEvent) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferReOrgEvent) GetPolicy() string {
+	if x != nil {
+		return x.Policy
+	}
+	return ""
+}
+
+func (x *TransferReOrgEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TransferAbandonedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Abandon timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the abandoned anchor transaction.
+	AnchorTxid string `protobuf:"bytes,2,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+	// The transaction ID of the confirmed transaction that double spent one of
+	// the inputs of the abandoned anchor transaction.
+	ConflictingTxid string `protobuf:"bytes,3,opt,name=conflicting_txid,json=conflictingTxid,proto3" json:"conflicting_txid,omitempty"`
+	// Whether the transfer will automatically be re-attempted with a new
+	// anchor transaction.
+	WillRetry bool `protobuf:"varint,4,opt,name=will_retry,json=willRetry,proto3" json:"will_retry,omitempty"`
+}
+
+func (x *TransferAbandonedEvent) Reset() {
+	*x = TransferAbandonedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[130]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferAbandonedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferAbandonedEvent) ProtoMessage() {}
+
+func (x *TransferAbandonedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[130]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferAbandonedEvent.ProtoReflect.Descriptor instead.
+func (*TransferAbandonedEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{130}
+}
+
+func (x *TransferAbandonedEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferAbandonedEvent) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferAbandonedEvent) GetConflictingTxid() string {
+	if x != nil {
+		return x.ConflictingTxid
+	}
+	return ""
+}
+
+func (x *TransferAbandonedEvent) GetWillRetry() bool {
+	if x != nil {
+		return x.WillRetry
+	}
+	return false
+}
+
+type TransferRetryEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Retry timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the abandoned anchor transaction.
+	AbandonedTxid string `protobuf:"bytes,2,opt,name=abandoned_txid,json=abandonedTxid,proto3" json:"abandoned_txid,omitempty"`
+	// The transaction ID of the new anchor transaction. Only set if the new
+	// transfer was broadcast successfully.
+	NewAnchorTxid string `protobuf:"bytes,3,opt,name=new_anchor_txid,json=newAnchorTxid,proto3" json:"new_anchor_txid,omitempty"`
+	// The error that prevented the new transfer, if any.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *TransferRetryEvent) Reset() {
+	*x = TransferRetryEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[131]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferRetryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferRetryEvent) ProtoMessage() {}
+
+func (x *TransferRetryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[131]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferRetryEvent.ProtoReflect.Descriptor instead.
+func (*TransferRetryEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{131}
+}
+
+func (x *TransferRetryEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferRetryEvent) GetAbandonedTxid() string {
+	if x != nil {
+		return x.AbandonedTxid
+	}
+	return ""
+}
+
+func (x *TransferRetryEvent) GetNewAnchorTxid() string {
+	if x != nil {
+		return x.NewAnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferRetryEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type TransferCancelledEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Cancel timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the anchor transaction of the cancelled transfer.
+	AnchorTxid string `protobuf:"bytes,2,opt,name=anchor_txid,json=anchorTxid,proto3" json:"anchor_txid,omitempty"`
+}
+
+func (x *TransferCancelledEvent) Reset() {
+	*x = TransferCancelledEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[132]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferCancelledEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferCancelledEvent) ProtoMessage() {}
+
+func (x *TransferCancelledEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[132]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferCancelledEvent.ProtoReflect.Descriptor instead.
+func (*TransferCancelledEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{132}
+}
+
+func (x *TransferCancelledEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferCancelledEvent) GetAnchorTxid() string {
+	if x != nil {
+		return x.AnchorTxid
+	}
+	return ""
+}
+
+type TransferFeeBumpedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Event timestamp (microseconds).
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The transaction ID of the anchor transaction that was replaced.
+	OldAnchorTxid string `protobuf:"bytes,2,opt,name=old_anchor_txid,json=oldAnchorTxid,proto3" json:"old_anchor_txid,omitempty"`
+	// The transaction ID of the replacement anchor transaction.
+	NewAnchorTxid string `protobuf:"bytes,3,opt,name=new_anchor_txid,json=newAnchorTxid,proto3" json:"new_anchor_txid,omitempty"`
+	// The fee rate in sat/kw the replacement was created for.
+	FeeRate uint32 `protobuf:"varint,4,opt,name=fee_rate,json=feeRate,proto3" json:"fee_rate,omitempty"`
+	// The total amount of sats paid in chain fees by the replacement.
+	ChainFees int64 `protobuf:"varint,5,opt,name=chain_fees,json=chainFees,proto3" json:"chain_fees,omitempty"`
+	// The number of the automatic fee escalation of the transfer the
+	// replacement was created for, or zero if the fee bump was requested
+	// manually.
+	Escalation uint32 `protobuf:"varint,6,opt,name=escalation,proto3" json:"escalation,omitempty"`
+}
+
+func (x *TransferFeeBumpedEvent) Reset() {
+	*x = TransferFeeBumpedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[133]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TransferFeeBumpedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferFeeBumpedEvent) ProtoMessage() {}
+
+func (x *TransferFeeBumpedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[133]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferFeeBumpedEvent.ProtoReflect.Descriptor instead.
+func (*TransferFeeBumpedEvent) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{133}
+}
+
+func (x *TransferFeeBumpedEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *TransferFeeBumpedEvent) GetOldAnchorTxid() string {
+	if x != nil {
+		return x.OldAnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferFeeBumpedEvent) GetNewAnchorTxid() string {
+	if x != nil {
+		return x.NewAnchorTxid
+	}
+	return ""
+}
+
+func (x *TransferFeeBumpedEvent) GetFeeRate() uint32 {
+	if x != nil {
+		return x.FeeRate
+	}
+	return 0
+}
+
+func (x *TransferFeeBumpedEvent) GetChainFees() int64 {
+	if x != nil {
+		return x.ChainFees
+	}
+	return 0
+}
+
+func (x *TransferFeeBumpedEvent) GetEscalation() uint32 {
+	if x != nil {
+		return x.Escalation
+	}
+	return 0
+}
+
+type FetchAssetMetaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Asset:
+	//
+	//	*FetchAssetMetaRequest_AssetId
+	//	*FetchAssetMetaRequest_MetaHash
+	//	*FetchAssetMetaRequest_AssetIdStr
+	//	*FetchAssetMetaRequest_MetaHashStr
+	Asset isFetchAssetMetaRequest_Asset `protobuf_oneof:"asset"`
+}
+
+func (x *FetchAssetMetaRequest) Reset() {
+	*x = FetchAssetMetaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[134]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchAssetMetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchAssetMetaRequest) ProtoMessage() {}
+
+func (x *FetchAssetMetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[134]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchAssetMetaRequest.ProtoReflect.Descriptor instead.
+func (*FetchAssetMetaRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{134}
+}
+
+func (m *FetchAssetMetaRequest) GetAsset() isFetchAssetMetaRequest_Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return nil
+}
+
+func (x *FetchAssetMetaRequest) GetAssetId() []byte {
+	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_AssetId); ok {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *FetchAssetMetaRequest) GetMetaHash() []byte {
+	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_MetaHash); ok {
+		return x.MetaHash
+	}
+	return nil
+}
+
+func (x *FetchAssetMetaRequest) GetAssetIdStr() string {
+	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_AssetIdStr); ok {
+		return x.AssetIdStr
+	}
+	return ""
+}
+
+func (x *FetchAssetMetaRequest) GetMetaHashStr() string {
+	if x, ok := x.GetAsset().(*FetchAssetMetaRequest_MetaHashStr); ok {
+		return x.MetaHashStr
+	}
+	return ""
+}
+
+type isFetchAssetMetaRequest_Asset interface {
+	isFetchAssetMetaRequest_Asset()
+}
+
+type FetchAssetMetaRequest_AssetId struct {
+	// The asset ID of the asset to fetch the meta for.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3,oneof"`
+}
+
+type FetchAssetMetaRequest_MetaHash struct {
+	// The 32-byte meta hash of the asset meta.
+	MetaHash []byte `protobuf:"bytes,2,opt,name=meta_hash,json=metaHash,proto3,oneof"`
+}
+
+type FetchAssetMetaRequest_AssetIdStr struct {
+	// The hex encoded asset ID of the asset to fetch the meta for.
+	AssetIdStr string `protobuf:"bytes,3,opt,name=asset_id_str,json=assetIdStr,proto3,oneof"`
+}
+
+type FetchAssetMetaRequest_MetaHashStr struct {
+	// The hex encoded meta hash of the asset meta.
+	MetaHashStr string `protobuf:"bytes,4,opt,name=meta_hash_str,json=metaHashStr,proto3,oneof"`
+}
+
+func (*FetchAssetMetaRequest_AssetId) isFetchAssetMetaRequest_Asset() {}
+
+func (*FetchAssetMetaRequest_MetaHash) isFetchAssetMetaRequest_Asset() {}
+
+func (*FetchAssetMetaRequest_AssetIdStr) isFetchAssetMetaRequest_Asset() {}
+
+func (*FetchAssetMetaRequest_MetaHashStr) isFetchAssetMetaRequest_Asset() {}
+
+type SearchAssetsByMetaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The query expression. It consists of one or more terms separated by "&&",
+	// all of which need to match. A term of the form path=value matches JSON
+	// meta data that has the given value at the dot separated key path, e.g.
+	// "artist.name=satoshi". A term that only consists of a path matches JSON
+	// meta data that has any value at the path. Meta data that isn't a JSON
+	// object matches if it contains the complete query as a substring.
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// If true, assets that are only known from the universe and aren't owned by
+	// the daemon are searched as well.
+	IncludeUniverse bool `protobuf:"varint,2,opt,name=include_universe,json=includeUniverse,proto3" json:"include_universe,omitempty"`
+}
+
+func (x *SearchAssetsByMetaRequest) Reset() {
+	*x = SearchAssetsByMetaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[135]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchAssetsByMetaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAssetsByMetaRequest) ProtoMessage() {}
+
+func (x *SearchAssetsByMetaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[135]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAssetsByMetaRequest.ProtoReflect.Descriptor instead.
+func (*SearchAssetsByMetaRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{135}
+}
+
+func (x *SearchAssetsByMetaRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchAssetsByMetaRequest) GetIncludeUniverse() bool {
+	if x != nil {
+		return x.IncludeUniverse
+	}
+	return false
+}
+
+type AssetMetaMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The base genesis information of the asset.
+	AssetGenesis *GenesisInfo `protobuf:"bytes,1,opt,name=asset_genesis,json=assetGenesis,proto3" json:"asset_genesis,omitempty"`
+	// The type of the asset.
+	AssetType AssetType `protobuf:"varint,2,opt,name=asset_type,json=assetType,proto3,enum=taprpc.AssetType" json:"asset_type,omitempty"`
+	// The balance of the asset owned by the daemon. This is zero for assets
+	// that are only known from the universe.
+	Balance uint64 `protobuf:"varint,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	// The meta data of the asset.
+	Meta *AssetMeta `protobuf:"bytes,4,opt,name=meta,proto3" json:"meta,omitempty"`
+}
+
+func (x *AssetMetaMatch) Reset() {
+	*x = AssetMetaMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[136]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssetMetaMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetMetaMatch) ProtoMessage() {}
+
+func (x *AssetMetaMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[136]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetMetaMatch.ProtoReflect.Descriptor instead.
+func (*AssetMetaMatch) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{136}
+}
+
+func (x *AssetMetaMatch) GetAssetGenesis() *GenesisInfo {
+	if x != nil {
+		return x.AssetGenesis
+	}
+	return nil
+}
+
+func (x *AssetMetaMatch) GetAssetType() AssetType {
+	if x != nil {
+		return x.AssetType
+	}
+	return AssetType_NORMAL
+}
+
+func (x *AssetMetaMatch) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *AssetMetaMatch) GetMeta() *AssetMeta {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type SearchAssetsByMetaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The assets whose meta data matches the query.
+	Assets []*AssetMetaMatch `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+}
+
+func (x *SearchAssetsByMetaResponse) Reset() {
+	*x = SearchAssetsByMetaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[137]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchAssetsByMetaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchAssetsByMetaResponse) ProtoMessage() {}
+
+func (x *SearchAssetsByMetaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[137]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchAssetsByMetaResponse.ProtoReflect.Descriptor instead.
+func (*SearchAssetsByMetaResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{137}
+}
+
+func (x *SearchAssetsByMetaResponse) GetAssets() []*AssetMetaMatch {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type BurnAssetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Asset:
+	//
+	//	*BurnAssetRequest_AssetId
+	//	*BurnAssetRequest_AssetIdStr
+	Asset        isBurnAssetRequest_Asset `protobuf_oneof:"asset"`
+	AmountToBurn uint64                   `protobuf:"varint,3,opt,name=amount_to_burn,json=amountToBurn,proto3" json:"amount_to_burn,omitempty"`
+	// A safety check to ensure the user is aware of the destructive nature of
+	// the burn. This needs to be set to the value "assets will be destroyed"
+	// for the burn to succeed.
+	ConfirmationText string `protobuf:"bytes,4,opt,name=confirmation_text,json=confirmationText,proto3" json:"confirmation_text,omitempty"`
+}
+
+func (x *BurnAssetRequest) Reset() {
+	*x = BurnAssetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[138]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BurnAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnAssetRequest) ProtoMessage() {}
+
+func (x *BurnAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[138]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BurnAssetRequest.ProtoReflect.Descriptor instead.
+func (*BurnAssetRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{138}
+}
+
+func (m *BurnAssetRequest) GetAsset() isBurnAssetRequest_Asset {
+	if m != nil {
+		return m.Asset
+	}
+	return nil
+}
+
+func (x *BurnAssetRequest) GetAssetId() []byte {
+	if x, ok := x.GetAsset().(*BurnAssetRequest_AssetId); ok {
+		return x.AssetId
+	}
+	return nil
+}
+
+func (x *BurnAssetRequest) GetAssetIdStr() string {
+	if x, ok := x.GetAsset().(*BurnAssetRequest_AssetIdStr); ok {
+		return x.AssetIdStr
+	}
+	return ""
+}
+
+func (x *BurnAssetRequest) GetAmountToBurn() uint64 {
+	if x != nil {
+		return x.AmountToBurn
+	}
+	return 0
+}
+
+func (x *BurnAssetRequest) GetConfirmationText() string {
+	if x != nil {
+		return x.ConfirmationText
+	}
+	return ""
+}
+
+type isBurnAssetRequest_Asset interface {
+	isBurnAssetRequest_Asset()
+}
+
+type BurnAssetRequest_AssetId struct {
+	// The asset ID of the asset to burn units of.
+	AssetId []byte `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3,oneof"`
+}
+
+type BurnAssetRequest_AssetIdStr struct {
+	// The hex encoded asset ID of the asset to burn units of.
+	AssetIdStr string `protobuf:"bytes,2,opt,name=asset_id_str,json=assetIdStr,proto3,oneof"`
+}
+
+func (*BurnAssetRequest_AssetId) isBurnAssetRequest_Asset() {}
+
+func (*BurnAssetRequest_AssetIdStr) isBurnAssetRequest_Asset() {}
+
+type BurnAssetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The asset transfer that contains the asset burn as an output.
+	BurnTransfer *AssetTransfer `protobuf:"bytes,1,opt,name=burn_transfer,json=burnTransfer,proto3" json:"burn_transfer,omitempty"`
+	// The burn transition proof for the asset burn output.
+	BurnProof *DecodedProof `protobuf:"bytes,2,opt,name=burn_proof,json=burnProof,proto3" json:"burn_proof,omitempty"`
+}
+
+func (x *BurnAssetResponse) Reset() {
+	*x = BurnAssetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[139]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BurnAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnAssetResponse) ProtoMessage() {}
+
+func (x *BurnAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[139]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BurnAssetResponse.ProtoReflect.Descriptor instead.
+func (*BurnAssetResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{139}
+}
+
+func (x *BurnAssetResponse) GetBurnTransfer() *AssetTransfer {
+	if x != nil {
+		return x.BurnTransfer
+	}
+	return nil
+}
+
+func (x *BurnAssetResponse) GetBurnProof() *DecodedProof {
+	if x != nil {
+		return x.BurnProof
+	}
+	return nil
+}
+
+type GetTransferMetricsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If set, then only transfers initiated at or after this Unix timestamp
+	// (seconds) will be taken into account.
+	StartTimestamp int64 `protobuf:"varint,1,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	// If set, then only transfers initiated at or before this Unix timestamp
+	// (seconds) will be taken into account. Defaults to the current time.
+	EndTimestamp int64 `protobuf:"varint,2,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+}
+
+func (x *GetTransferMetricsRequest) Reset() {
+	*x = GetTransferMetricsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[140]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTransferMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransferMetricsRequest) ProtoMessage() {}
+
+func (x *GetTransferMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[140]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransferMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetTransferMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{140}
+}
+
+func (x *GetTransferMetricsRequest) GetStartTimestamp() int64 {
+	if x != nil {
+		return x.StartTimestamp
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsRequest) GetEndTimestamp() int64 {
+	if x != nil {
+		return x.EndTimestamp
+	}
+	return 0
+}
+
+type LatencyPercentiles struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of transfers the percentiles were computed from.
+	NumSamples uint32 `protobuf:"varint,1,opt,name=num_samples,json=numSamples,proto3" json:"num_samples,omitempty"`
+	// The median latency in milliseconds.
+	P50Ms int64 `protobuf:"varint,2,opt,name=p50_ms,json=p50Ms,proto3" json:"p50_ms,omitempty"`
+	// The 90th percentile latency in milliseconds.
+	P90Ms int64 `protobuf:"varint,3,opt,name=p90_ms,json=p90Ms,proto3" json:"p90_ms,omitempty"`
+	// The 99th percentile latency in milliseconds.
+	P99Ms int64 `protobuf:"varint,4,opt,name=p99_ms,json=p99Ms,proto3" json:"p99_ms,omitempty"`
+}
+
+func (x *LatencyPercentiles) Reset() {
+	*x = LatencyPercentiles{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[141]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LatencyPercentiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LatencyPercentiles) ProtoMessage() {}
+
+func (x *LatencyPercentiles) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[141]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LatencyPercentiles.ProtoReflect.Descriptor instead.
+func (*LatencyPercentiles) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{141}
+}
+
+func (x *LatencyPercentiles) GetNumSamples() uint32 {
+	if x != nil {
+		return x.NumSamples
+	}
+	return 0
+}
+
+func (x *LatencyPercentiles) GetP50Ms() int64 {
+	if x != nil {
+		return x.P50Ms
+	}
+	return 0
+}
+
+func (x *LatencyPercentiles) GetP90Ms() int64 {
+	if x != nil {
+		return x.P90Ms
+	}
+	return 0
+}
+
+func (x *LatencyPercentiles) GetP99Ms() int64 {
+	if x != nil {
+		return x.P99Ms
+	}
+	return 0
+}
+
+type GetTransferMetricsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The Unix timestamp (seconds) of the start of the evaluated time window.
+	StartTimestamp int64 `protobuf:"varint,1,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	// The Unix timestamp (seconds) of the end of the evaluated time window.
+	EndTimestamp int64 `protobuf:"varint,2,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	// The number of transfers initiated within the time window.
+	NumTransfers uint32 `protobuf:"varint,3,opt,name=num_transfers,json=numTransfers,proto3" json:"num_transfers,omitempty"`
+	// The number of transfers within the time window that are confirmed and
+	// have completed proof delivery.
+	NumCompleted uint32 `protobuf:"varint,4,opt,name=num_completed,json=numCompleted,proto3" json:"num_completed,omitempty"`
+	// The number of completed transfers per hour within the time window.
+	CompletedPerHour float64 `protobuf:"fixed64,5,opt,name=completed_per_hour,json=completedPerHour,proto3" json:"completed_per_hour,omitempty"`
+	// The latency between initiating a transfer and the anchor transaction
+	// being confirmed on chain.
+	ConfirmationLatency *LatencyPercentiles `protobuf:"bytes,6,opt,name=confirmation_latency,json=confirmationLatency,proto3" json:"confirmation_latency,omitempty"`
+	// The latency between initiating a transfer and the completion of the proof
+	// delivery to all receivers.
+	DeliveryLatency *LatencyPercentiles `protobuf:"bytes,7,opt,name=delivery_latency,json=deliveryLatency,proto3" json:"delivery_latency,omitempty"`
+}
+
+func (x *GetTransferMetricsResponse) Reset() {
+	*x = GetTransferMetricsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[142]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTransferMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransferMetricsResponse) ProtoMessage() {}
+
+func (x *GetTransferMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[142]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransferMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetTransferMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{142}
+}
+
+func (x *GetTransferMetricsResponse) GetStartTimestamp() int64 {
+	if x != nil {
+		return x.StartTimestamp
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsResponse) GetEndTimestamp() int64 {
+	if x != nil {
+		return x.EndTimestamp
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsResponse) GetNumTransfers() uint32 {
+	if x != nil {
+		return x.NumTransfers
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsResponse) GetNumCompleted() uint32 {
+	if x != nil {
+		return x.NumCompleted
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsResponse) GetCompletedPerHour() float64 {
+	if x != nil {
+		return x.CompletedPerHour
+	}
+	return 0
+}
+
+func (x *GetTransferMetricsResponse) GetConfirmationLatency() *LatencyPercentiles {
+	if x != nil {
+		return x.ConfirmationLatency
+	}
+	return nil
+}
+
+func (x *GetTransferMetricsResponse) GetDeliveryLatency() *LatencyPercentiles {
+	if x != nil {
+		return x.DeliveryLatency
+	}
+	return nil
+}
+
+type AnchorSweepStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *AnchorSweepStatusRequest) Reset() {
+	*x = AnchorSweepStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[143]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnchorSweepStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorSweepStatusRequest) ProtoMessage() {}
+
+func (x *AnchorSweepStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[143]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorSweepStatusRequest.ProtoReflect.Descriptor instead.
+func (*AnchorSweepStatusRequest) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{143}
+}
+
+type SweepableAnchor struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the anchor output, in the form of "txid:index".
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The value of the anchor output in satoshis.
+	AmtSats int64 `protobuf:"varint,2,opt,name=amt_sats,json=amtSats,proto3" json:"amt_sats,omitempty"`
+}
+
+func (x *SweepableAnchor) Reset() {
+	*x = SweepableAnchor{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[144]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SweepableAnchor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SweepableAnchor) ProtoMessage() {}
+
+func (x *SweepableAnchor) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[144]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SweepableAnchor.ProtoReflect.Descriptor instead.
+func (*SweepableAnchor) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{144}
+}
+
+func (x *SweepableAnchor) GetOutpoint() string {
+	if x != nil {
+		return x.Outpoint
+	}
+	return ""
+}
+
+func (x *SweepableAnchor) GetAmtSats() int64 {
+	if x != nil {
+		return x.AmtSats
+	}
+	return 0
+}
+
+type AnchorSweep struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The outpoint of the swept anchor output, in the form of "txid:index".
+	Outpoint string `protobuf:"bytes,1,opt,name=outpoint,proto3" json:"outpoint,omitempty"`
+	// The value of the swept anchor output in satoshis.
+	AmtSats int64 `protobuf:"varint,2,opt,name=amt_sats,json=amtSats,proto3" json:"amt_sats,omitempty"`
+	// The ID of the transaction that swept the anchor output.
+	SweepTxid string `protobuf:"bytes,3,opt,name=sweep_txid,json=sweepTxid,proto3" json:"sweep_txid,omitempty"`
+	// The Unix timestamp (seconds) at which the sweep transaction was
+	// published.
+	SweptAt int64 `protobuf:"varint,4,opt,name=swept_at,json=sweptAt,proto3" json:"swept_at,omitempty"`
+}
+
+func (x *AnchorSweep) Reset() {
+	*x = AnchorSweep{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[145]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnchorSweep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorSweep) ProtoMessage() {}
+
+func (x *AnchorSweep) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[145]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorSweep.ProtoReflect.Descriptor instead.
+func (*AnchorSweep) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{145}
+}
+
+func (x *AnchorSweep) GetOutpoint() string {
+	if x != nil {
+		return x.Outpoint
+	}
+	return ""
+}
+
+func (x *AnchorSweep) GetAmtSats() int64 {
+	if x != nil {
+		return x.AmtSats
+	}
+	return 0
+}
+
+func (x *AnchorSweep) GetSweepTxid() string {
+	if x != nil {
+		return x.SweepTxid
+	}
+	return ""
+}
+
+func (x *AnchorSweep) GetSweptAt() int64 {
+	if x != nil {
+		return x.SweptAt
+	}
+	return 0
+}
+
+type AnchorSweepStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Whether anchor outputs are swept in the background.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// The value in satoshis an anchor output has at most to be swept.
+	MaxOutputValueSats int64 `protobuf:"varint,2,opt,name=max_output_value_sats,json=maxOutputValueSats,proto3" json:"max_output_value_sats,omitempty"`
+	// The fee rate in sat/vB above which no sweep is attempted.
+	MaxFeeRateSatPerVbyte uint64 `protobuf:"varint,3,opt,name=max_fee_rate_sat_per_vbyte,json=maxFeeRateSatPerVbyte,proto3" json:"max_fee_rate_sat_per_vbyte,omitempty"`
+	// The percentage of the value of the swept anchor outputs that can be spent
+	// on the fees of a sweep transaction at most.
+	FeeBudgetPercent uint32 `protobuf:"varint,4,opt,name=fee_budget_percent,json=feeBudgetPercent,proto3" json:"fee_budget_percent,omitempty"`
+	// The Unix timestamp (seconds) of the last sweep attempt, or zero if no
+	// sweep was attempted yet.
+	LastAttempt int64 `protobuf:"varint,5,opt,name=last_attempt,json=lastAttempt,proto3" json:"last_attempt,omitempty"`
+	// The error the last sweep attempt failed with, if any.
+	LastError string `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// The anchor outputs that are waiting to be swept. This is only populated if
+	// sweeping is enabled.
+	Backlog []*SweepableAnchor `protobuf:"bytes,7,rep,name=backlog,proto3" json:"backlog,omitempty"`
+	// The total value in satoshis of the anchor outputs waiting to be swept.
+	BacklogSats int64 `protobuf:"varint,8,opt,name=backlog_sats,json=backlogSats,proto3" json:"backlog_sats,omitempty"`
+	// The anchor outputs that were already swept.
+	Sweeps []*AnchorSweep `protobuf:"bytes,9,rep,name=sweeps,proto3" json:"sweeps,omitempty"`
+}
+
+func (x *AnchorSweepStatusResponse) Reset() {
+	*x = AnchorSweepStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_taprootassets_proto_msgTypes[146]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnchorSweepStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnchorSweepStatusResponse) ProtoMessage() {}
+
+func (x *AnchorSweepStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_taprootassets_proto_msgTypes[146]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnchorSweepStatusResponse.ProtoReflect.Descriptor instead.
+func (*AnchorSweepStatusResponse) Descriptor() ([]byte, []int) {
+	return file_taprootassets_proto_rawDescGZIP(), []int{146}
+}
+
+func (x *AnchorSweepStatusResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AnchorSweepStatusResponse) GetMaxOutputValueSats() int64 {
+	if x != nil {
+		return x.MaxOutputValueSats
+	}
+	return 0
+}
+
+func (x *AnchorSweepStatusResponse) GetMaxFeeRateSatPerVbyte() uint64 {
+	if x != nil {
+		return x.MaxFeeRateSatPerVbyte
+	}
+	return 0
+}
+
+func (x *AnchorSweepStatusResponse) GetFeeBudgetPercent() uint32 {
+	if x != nil {
+		return x.FeeBudgetPercent
+	}
+	return 0
+}
+
+func (x *AnchorSweepStatusResponse) GetLastAttempt() int64 {
+	if x != nil {
+		return x.LastAttempt
+	}
+	return 0
+}
+
+func (x *AnchorSweepStatusResponse) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *AnchorSweepStatusResponse) GetBacklog() []*SweepableAnchor {
+	if x != nil {
+		return x.Backlog
+	}
+	return nil
+}
+
+func (x *AnchorSweepStatusResponse) GetBacklogSats() int64 {
+	if x != nil {
+		return x.BacklogSats
+	}
+	return 0
+}
+
+func (x *AnchorSweepStatusResponse) GetSweeps() []*AnchorSweep {
+	if x != nil {
+		return x.Sweeps
+	}
+	return nil
+}
+
+var File_taprootassets_proto protoreflect.FileDescriptor
+
+var file_taprootassets_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x22, 0x86, 0x01,
+	0x0a, 0x09, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65,
+	0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x75, 0x70, 0x70, 0x6c,
+	0x79, 0x5f, 0x63, 0x61, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x75, 0x70,
+	0x70, 0x6c, 0x79, 0x43, 0x61, 0x70, 0x22, 0xd1, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77,
+	0x69, 0x74, 0x68, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x23,
+	0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x53, 0x70,
+	0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x63,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0d, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65,
+	0x72, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x61, 0x63, 0x71, 0x75,
+	0x69, 0x72, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x22, 0xb3, 0x02, 0x0a, 0x0a, 0x41,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75,
+	0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74,
+	0x12, 0x2b, 0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69,
+	0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a,
+	0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x22, 0xbb, 0x01, 0x0a, 0x0b, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x23, 0x0a, 0x0d, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74,
+	0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49,
+	0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x81,
+	0x01, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x22, 0x0a,
+	0x0d, 0x72, 0x61, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65,
+	0x79, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x77, 0x65, 0x61, 0x6b, 0x65, 0x64, 0x5f, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x74, 0x77,
+	0x65, 0x61, 0x6b, 0x65, 0x64, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x23, 0x0a,
+	0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x57, 0x69, 0x74, 0x6e, 0x65,
+	0x73, 0x73, 0x22, 0x5b, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x52, 0x65,
+	0x76, 0x65, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x61, 0x70, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0d, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x22,
+	0x86, 0x01, 0x0a, 0x0d, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61,
+	0x6c, 0x12, 0x43, 0x0a, 0x13, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x62, 0x61, 0x73,
+	0x65, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x11, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x61, 0x73, 0x65,
+	0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0xbc, 0x05, 0x0a, 0x05, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x12, 0x2e, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65,
+	0x73, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x12, 0x30, 0x0a, 0x0a,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x10, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x2d, 0x0a, 0x13, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x73, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x49,
+	0x73, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x33, 0x0a, 0x0b, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52,
+	0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x35, 0x0a, 0x0c, 0x63,
+	0x68, 0x61, 0x69, 0x6e, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x41, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x12, 0x3a, 0x0a, 0x0e, 0x70, 0x72, 0x65, 0x76, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65,
+	0x73, 0x73, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x52,
+	0x0d, 0x70, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x19,
+	0x0a, 0x08, 0x69, 0x73, 0x5f, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x07, 0x69, 0x73, 0x53, 0x70, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x6c, 0x65, 0x61, 0x73, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x17, 0x0a,
+	0x07, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x69, 0x73, 0x42, 0x75, 0x72, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72,
+	0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x61, 0x63, 0x71,
+	0x75, 0x69, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0xa1, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x76,
+	0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x2f, 0x0a, 0x07, 0x70, 0x72, 0x65, 0x76, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x06, 0x70, 0x72, 0x65, 0x76, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x78, 0x5f, 0x77,
+	0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x74, 0x78,
+	0x57, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x42, 0x0a, 0x10, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0f, 0x73, 0x70, 0x6c, 0x69,
+	0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x3f, 0x0a, 0x0f, 0x53,
+	0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2c,
+	0x0a, 0x0a, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x52, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x22, 0x3a, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x25, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0x62, 0x0a, 0x16, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x77, 0x69, 0x74, 0x68, 0x57, 0x69,
+	0x74, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x5f, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x22, 0xc3, 0x01, 0x0a,
+	0x0b, 0x41, 0x73, 0x73, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73,
+	0x5f, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x69, 0x73, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x23, 0x0a, 0x05, 0x61,
+	0x64, 0x64, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64,
+	0x12, 0x27, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x27, 0x0a, 0x07, 0x63, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x22, 0x39, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x5f, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x64, 0x22, 0xdc, 0x01,
+	0x0a, 0x0b, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x12, 0x1b, 0x0a,
+	0x09, 0x6f, 0x75, 0x74, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6f, 0x75, 0x74, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x6d,
+	0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x74,
+	0x53, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f,
+	0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72,
+	0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18,
+	0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xbb, 0x01, 0x0a,
+	0x11, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x5f, 0x75, 0x74,
+	0x78, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55,
+	0x74, 0x78, 0x6f, 0x73, 0x1a, 0x54, 0x0a, 0x11, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55,
+	0x74, 0x78, 0x6f, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x55, 0x74, 0x78, 0x6f, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1d, 0x0a, 0x1b, 0x4c, 0x69,
+	0x73, 0x74, 0x4f, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x78, 0x0a, 0x0e, 0x46, 0x6f, 0x6f,
+	0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x73, 0x70,
+	0x65, 0x6e, 0x74, 0x22, 0x8e, 0x03, 0x0a, 0x0d, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e,
+	0x74, 0x55, 0x74, 0x78, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x0c,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f,
+	0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72,
+	0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x65, 0x61, 0x73,
+	0x65, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x65, 0x61,
+	0x73, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0b, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x2e, 0x0a, 0x06,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x26, 0x0a, 0x0f,
+	0x66, 0x65, 0x65, 0x5f, 0x62, 0x75, 0x6d, 0x70, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x66, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x41, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x22, 0x71, 0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x6e, 0x43, 0x68,
+	0x61, 0x69, 0x6e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x75, 0x74, 0x78, 0x6f, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x6f,
+	0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x52, 0x05, 0x75, 0x74, 0x78, 0x6f,
+	0x73, 0x12, 0x24, 0x0a, 0x0e, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73,
+	0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x41, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x73, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3d, 0x0a, 0x1e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x6f, 0x6e, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x22, 0xcf, 0x03, 0x0a, 0x1f,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x6f, 0x6e, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x11,
+	0x69, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x73, 0x75,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x69, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x75, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x64, 0x5f, 0x61, 0x6d, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x64, 0x41, 0x6d, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x69,
+	0x73, 0x73, 0x75, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x6e, 0x75, 0x6d, 0x49, 0x73, 0x73, 0x75, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a,
+	0x62, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x5f, 0x61, 0x6d, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x62, 0x75, 0x72, 0x6e, 0x65, 0x64, 0x41, 0x6d, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x6e,
+	0x75, 0x6d, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x6e, 0x75, 0x6d, 0x42, 0x75, 0x72, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x27, 0x0a,
+	0x0f, 0x6f, 0x75, 0x74, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x73, 0x74, 0x61, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73,
+	0x69, 0x73, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65,
+	0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x64,
+	0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x75, 0x6e, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x6d, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x75, 0x6e, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x41, 0x6d, 0x74, 0x12, 0x2e, 0x0a,
+	0x13, 0x6e, 0x75, 0x6d, 0x5f, 0x75, 0x6e, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x04, 0x52, 0x11, 0x6e, 0x75, 0x6d, 0x55,
+	0x6e, 0x73, 0x70, 0x65, 0x6e, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x22, 0x8d, 0x02,
+	0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x74, 0x48, 0x75, 0x6d, 0x61, 0x6e, 0x52, 0x65, 0x61, 0x64,
+	0x61, 0x62, 0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x09,
+	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x4c,
+	0x6f, 0x63, 0x6b, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x65, 0x74,
+	0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2e, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x43, 0x0a,
+	0x0d, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x32,
+	0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x48, 0x75, 0x6d,
+	0x61, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x61, 0x62, 0x6c, 0x65, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x1a, 0x50, 0x0a, 0x0b, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x65, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xaa, 0x01, 0x0a, 0x13,
+	0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12,
+	0x21, 0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x61, 0x73, 0x73, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x42, 0x0a, 0x0a, 0x08,
+	0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x62, 0x79, 0x22, 0x94, 0x01, 0x0a, 0x0c, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x38, 0x0a, 0x0d, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69,
+	0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x65, 0x6e, 0x65,
+	0x73, 0x69, 0x73, 0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x22,
+	0xf7, 0x01, 0x0a, 0x11, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b,
+	0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x53, 0x0a, 0x0e,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x2e,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x73, 0x1a, 0x56, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x90, 0x03, 0x0a, 0x14, 0x4c, 0x69,
+	0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x56, 0x0a, 0x0e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x62, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x66, 0x0a, 0x14, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x12,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x1a, 0x56, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x60, 0x0a, 0x17, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x31, 0x0a, 0x16,
+	0x52, 0x65, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x22,
+	0x90, 0x02, 0x0a, 0x17, 0x41, 0x73, 0x73, 0x65, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x64, 0x5f, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x65, 0x64, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x72, 0x65, 0x70, 0x61, 0x69, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61,
+	0x69, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x22, 0x6c, 0x0a, 0x0d, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1f,
+	0x0a, 0x0b, 0x6f, 0x6c, 0x64, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0a, 0x6f, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x77, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x6e, 0x65, 0x77, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x22, 0xbf, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6e, 0x75, 0x6d, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x09, 0x6e, 0x75, 0x6d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x45, 0x0a, 0x0d, 0x64,
+	0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x44, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61,
+	0x6e, 0x63, 0x79, 0x52, 0x0d, 0x64, 0x69, 0x73, 0x63, 0x72, 0x65, 0x70, 0x61, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x63, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x0e, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x73, 0x22, 0x39, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x22, 0x4c, 0x0a,
+	0x15, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x22, 0x40, 0x0a, 0x1f, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x42, 0x79, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0xb0, 0x01,
+	0x0a, 0x11, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x12, 0x33, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x48, 0x00, 0x52, 0x08,
+	0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x2d, 0x0a, 0x07, 0x69, 0x6e, 0x62, 0x6f,
+	0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07,
+	0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x2b, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x42, 0x0a, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x22, 0x5b, 0x0a, 0x20, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x73, 0x42, 0x79, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x22, 0x3a, 0x0a,
+	0x17, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x47, 0x72, 0x61, 0x70,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x22, 0x6a, 0x0a, 0x0c, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x45, 0x64, 0x67, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x70,
+	0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x79, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x31, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x45, 0x64, 0x67, 0x65, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73,
+	0x22, 0xd7, 0x02, 0x0a, 0x0d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x12, 0x2d, 0x0a, 0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x61, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x54, 0x78, 0x48, 0x61, 0x73, 0x68, 0x12, 0x31, 0x0a, 0x15, 0x61, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x68, 0x69, 0x6e, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x48, 0x69, 0x6e, 0x74, 0x12, 0x2f, 0x0a, 0x14, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x54, 0x78, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x65, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x49, 0x6e, 0x70,
+	0x75, 0x74, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x67, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x67, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x22, 0x84, 0x01, 0x0a, 0x0d, 0x54,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x22, 0x95, 0x02, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75,
+	0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75,
+	0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79, 0x12,
+	0x2c, 0x0a, 0x12, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70,
+	0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x2b,
+	0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69, 0x62, 0x6c,
+	0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a, 0x12, 0x6e,
+	0x75, 0x6d, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x50, 0x61, 0x73, 0x73,
+	0x69, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xd0, 0x03, 0x0a, 0x0e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x34, 0x0a, 0x06,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x52, 0x06, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65,
+	0x79, 0x12, 0x2d, 0x0a, 0x13, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x69, 0x73, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x49, 0x73, 0x4c, 0x6f, 0x63, 0x61, 0x6c,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x65, 0x77, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0c, 0x6e, 0x65, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x6c, 0x6f, 0x62, 0x12, 0x33,
+	0x0a, 0x16, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x72,
+	0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13,
+	0x73, 0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x48,
+	0x61, 0x73, 0x68, 0x12, 0x33, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x22, 0xd8, 0x01, 0x0a,
+	0x0d, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x33,
+	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x75, 0x72, 0x69,
+	0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65,
+	0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f,
+	0x75, 0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x6e, 0x75, 0x6d,
+	0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0b, 0x6e, 0x75, 0x6d, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x29, 0x0a, 0x10,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x22, 0x0d, 0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x0e, 0x0a, 0x0c, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x46, 0x0a, 0x11, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x68, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x73, 0x68, 0x6f, 0x77, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x53, 0x70, 0x65, 0x63, 0x22, 0x35,
+	0x0a, 0x12, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x75, 0x62, 0x5f, 0x73, 0x79, 0x73, 0x74,
+	0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x62, 0x53, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x34, 0x0a, 0x15, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x68, 0x22, 0x6c, 0x0a, 0x16, 0x42,
+	0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x50, 0x61,
+	0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0xf1, 0x03, 0x0a, 0x04, 0x41, 0x64,
+	0x64, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x65, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
+	0x74, 0x12, 0x1b, 0x0a, 0x09, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a,
+	0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65, 0x79,
+	0x12, 0x2b, 0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73, 0x69,
+	0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2c, 0x0a,
+	0x12, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61, 0x70, 0x72, 0x6f,
+	0x6f, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f,
+	0x75, 0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x39, 0x0a, 0x0d, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x61, 0x6e,
+	0x79, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e,
+	0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x6e, 0x79, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x8c, 0x01,
+	0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x37, 0x0a, 0x11,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x22, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x05,
+	0x61, 0x64, 0x64, 0x72, 0x73, 0x22, 0xff, 0x03, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x03, 0x61, 0x6d, 0x74, 0x12, 0x30, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x09, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x38, 0x0a, 0x0c, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x6f, 0x72, 0x52, 0x0b, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4b, 0x65,
+	0x79, 0x12, 0x2b, 0x0a, 0x11, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x73,
+	0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x74, 0x61,
+	0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2c,
+	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x5f,
+	0x61, 0x64, 0x64, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x43, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x39, 0x0a, 0x0d,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x4c, 0x0a, 0x14, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x52, 0x12, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x61, 0x70, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b, 0x65, 0x79, 0x12, 0x28,
+	0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x61, 0x6e, 0x79, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x41,
+	0x6e, 0x79, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x6d, 0x69,
+	0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x43, 0x0a, 0x13, 0x4e, 0x65, 0x77, 0x41, 0x64,
+	0x64, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c,
+	0x0a, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x22, 0x3a, 0x0a, 0x14,
+	0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64,
+	0x72, 0x52, 0x05, 0x61, 0x64, 0x64, 0x72, 0x73, 0x22, 0x54, 0x0a, 0x12, 0x53, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x4b, 0x65, 0x79, 0x54, 0x61, 0x70, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x6c, 0x65, 0x61, 0x66, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x0b, 0x6c, 0x65, 0x61, 0x66, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x22, 0x73,
+	0x0a, 0x09, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x70,
+	0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75,
+	0x62, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x64, 0x65, 0x73, 0x63,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x52, 0x07, 0x6b,
+	0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x70, 0x5f, 0x74, 0x77,
+	0x65, 0x61, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x74, 0x61, 0x70, 0x54, 0x77,
+	0x65, 0x61, 0x6b, 0x22, 0x48, 0x0a, 0x0a, 0x4b, 0x65, 0x79, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x6f,
+	0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x46, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x60, 0x0a,
+	0x0d, 0x4b, 0x65, 0x79, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x22,
+	0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72, 0x61, 0x77, 0x4b, 0x65, 0x79, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x6b, 0x65, 0x79, 0x5f, 0x6c, 0x6f, 0x63, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x65, 0x79,
+	0x4c, 0x6f, 0x63, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x06, 0x6b, 0x65, 0x79, 0x4c, 0x6f, 0x63, 0x22,
+	0x27, 0x0a, 0x11, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x61, 0x64, 0x64, 0x72, 0x22, 0x56, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x72,
+	0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x67,
+	0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x67, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x22, 0xd7, 0x04, 0x0a, 0x0c, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x61, 0x74, 0x5f, 0x64, 0x65,
+	0x70, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x41, 0x74, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x5f, 0x6f, 0x66, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0e, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x4f, 0x66, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x12, 0x23, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52,
+	0x05, 0x61, 0x73, 0x73, 0x65, 0x74, 0x12, 0x32, 0x0a, 0x0b, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x72,
+	0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x0a,
+	0x6d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x78,
+	0x5f, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x74, 0x78, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x69, 0x6e, 0x63,
+	0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x29, 0x0a, 0x10, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f,
+	0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x12, 0x32, 0x0a, 0x15, 0x6e, 0x75, 0x6d, 0x5f, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x13, 0x6e, 0x75, 0x6d, 0x41, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67,
+	0x65, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0c, 0x52,
+	0x10, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x57, 0x69, 0x74, 0x6e, 0x65, 0x73,
+	0x73, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x42, 0x75, 0x72, 0x6e, 0x12, 0x3c, 0x0a, 0x0e, 0x67, 0x65,
+	0x6e, 0x65, 0x73, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65,
+	0x73, 0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x52, 0x0d, 0x67, 0x65, 0x6e, 0x65, 0x73,
+	0x69, 0x73, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x12, 0x40, 0x0a, 0x10, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x52, 0x0e, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x22, 0xdf, 0x01, 0x0a, 0x13, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6f,
+	0x64, 0x65, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0xb1, 0x01, 0x0a,
+	0x12, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x12, 0x24, 0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x61, 0x74, 0x5f, 0x64, 0x65, 0x70,
+	0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x41,
+	0x74, 0x44, 0x65, 0x70, 0x74, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x70,
+	0x72, 0x65, 0x76, 0x5f, 0x77, 0x69, 0x74, 0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x11, 0x77, 0x69, 0x74, 0x68, 0x50, 0x72, 0x65, 0x76, 0x57, 0x69, 0x74,
+	0x6e, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x28, 0x0a, 0x10, 0x77, 0x69, 0x74, 0x68, 0x5f, 0x6d,
+	0x65, 0x74, 0x61, 0x5f, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x77, 0x69, 0x74, 0x68, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c,
+	0x22, 0x50, 0x0a, 0x13, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x39, 0x0a, 0x0d, 0x64, 0x65, 0x63, 0x6f, 0x64,
+	0x65, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0c, 0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x22, 0x9c, 0x01, 0x0a, 0x1e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6d, 0x70, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x16, 0x69, 0x6d,
+	0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x69, 0x6d, 0x70, 0x6c,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x22, 0x7e, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x6e, 0x66, 0x6f, 0x72,
+	0x6d, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x06, 0x70, 0x61, 0x73, 0x73, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69,
+	0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c,
+	0x73, 0x22, 0x99, 0x02, 0x0a, 0x1f, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x43, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69,
+	0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x61,
+	0x74, 0x69, 0x62, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x35,
+	0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x6e,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x06, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65,
+	0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69,
+	0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a,
+	0x16, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x69,
+	0x6d, 0x70, 0x6c, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x4e, 0x0a,
+	0x12, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x56, 0x0a,
+	0x18, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x49, 0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f,
+	0x6b, 0x65, 0x79, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x09, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x4b, 0x65, 0x79, 0x73, 0x22, 0x9c, 0x01, 0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x24,
+	0x0a, 0x0e, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x46, 0x69, 0x6c, 0x65, 0x22, 0x92, 0x01, 0x0a, 0x18, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1b, 0x0a, 0x09,
+	0x65, 0x6e, 0x64, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x65, 0x6e, 0x64, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0x90, 0x01, 0x0a, 0x0e, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x21, 0x0a, 0x0c,
+	0x66, 0x69, 0x6c, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x66, 0x69, 0x6c, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6e, 0x75, 0x6d, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x1b, 0x0a, 0x09, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x08, 0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x7e, 0x0a, 0x19,
+	0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65,
+	0x79, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x22, 0xb2, 0x03, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x69,
+	0x73, 0x5f, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x12,
+	0x26, 0x0a, 0x0f, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x52,
+	0x6f, 0x6f, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0c, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x53, 0x75, 0x6d, 0x12, 0x35, 0x0a,
+	0x17, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x14,
+	0x73, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x6f, 0x6f, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x31, 0x0a, 0x15, 0x72, 0x6f, 0x6f, 0x74, 0x5f, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x12, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0f, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x41, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f, 0x6c, 0x65, 0x61,
+	0x66, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x73, 0x70, 0x6c,
+	0x69, 0x74, 0x4c, 0x65, 0x61, 0x66, 0x4b, 0x65, 0x79, 0x12, 0x32, 0x0a, 0x15, 0x73, 0x70, 0x6c,
+	0x69, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x13, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x49,
+	0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x32, 0x0a,
+	0x15, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x69, 0x6e,
+	0x63, 0x6c, 0x75, 0x73, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x56, 0x61, 0x6c, 0x69,
+	0x64, 0x22, 0x80, 0x01, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4d,
+	0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x49, 0x64, 0x22, 0xd9, 0x02, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
+	0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x72, 0x61, 0x77, 0x5f, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x72,
+	0x61, 0x77, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1d, 0x0a,
+	0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x12, 0x21, 0x0a, 0x0c,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x74, 0x78, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x74, 0x78, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x65,
+	0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x62, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0c, 0x52, 0x0c, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x42, 0x72, 0x61, 0x6e, 0x63, 0x68, 0x12,
+	0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x12, 0x2c, 0x0a, 0x12, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10,
+	0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x56, 0x61, 0x6c, 0x69, 0x64,
+	0x22, 0x3c, 0x0a, 0x1b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0xaf,
+	0x02, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x70, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x68,
+	0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x61, 0x77, 0x5f, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x72, 0x61,
+	0x77, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61,
+	0x70, 0x5f, 0x74, 0x77, 0x65, 0x61, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x74,
+	0x61, 0x70, 0x54, 0x77, 0x65, 0x61, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x39, 0x0a, 0x19, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x16, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x22, 0x53, 0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x33, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x52, 0x08, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x70, 0x74, 0x73, 0x22, 0x63, 0x0a, 0x19, 0x50, 0x61, 0x75, 0x73, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x1c, 0x0a, 0x1a, 0x50, 0x61,
+	0x75, 0x73, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x64, 0x0a, 0x1a, 0x52, 0x65, 0x73, 0x75,
+	0x6d, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0x1d,
+	0x0a, 0x1b, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x0a,
+	0x1b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xeb, 0x01, 0x0a,
+	0x0f, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70,
+	0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0e, 0x72, 0x61, 0x77, 0x5f, 0x70, 0x72,
+	0x6f, 0x6f, 0x66, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c,
+	0x72, 0x61, 0x77, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2a,
+	0x0a, 0x11, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x55,
+	0x6e, 0x69, 0x78, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x5a, 0x0a, 0x1c, 0x4c, 0x69,
+	0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0c, 0x64, 0x65,
+	0x61, 0x64, 0x5f, 0x6c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65,
+	0x74, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0b, 0x64, 0x65, 0x61, 0x64, 0x4c,
+	0x65, 0x74, 0x74, 0x65, 0x72, 0x73, 0x22, 0x70, 0x0a, 0x16, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2a, 0x0a, 0x11, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x70, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x11,
+	0x73, 0x75, 0x66, 0x66, 0x69, 0x78, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x73, 0x75, 0x66, 0x66, 0x69, 0x78, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x22, 0xb8, 0x03, 0x0a, 0x09, 0x41, 0x64, 0x64,
+	0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3b, 0x0a, 0x1a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x75, 0x6e, 0x69, 0x78, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x55, 0x6e, 0x69, 0x78, 0x53, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x12, 0x20, 0x0a, 0x04, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52,
+	0x04, 0x61, 0x64, 0x64, 0x72, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x64, 0x64, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x75, 0x74, 0x78, 0x6f, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73,
+	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x75, 0x74, 0x78, 0x6f, 0x41, 0x6d,
+	0x74, 0x53, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x74,
+	0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2f, 0x0a,
+	0x13, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1b,
+	0x0a, 0x09, 0x68, 0x61, 0x73, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1b, 0x0a, 0x09, 0x61,
+	0x73, 0x73, 0x65, 0x74, 0x5f, 0x61, 0x6d, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x41, 0x6d, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x5f, 0x73, 0x68, 0x6f, 0x72, 0x74, 0x66, 0x61, 0x6c, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x53, 0x68, 0x6f, 0x72, 0x74, 0x66, 0x61, 0x6c,
+	0x6c, 0x12, 0x20, 0x0a, 0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x64,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69,
+	0x6e, 0x65, 0x64, 0x22, 0x74, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x3c, 0x0a, 0x0d, 0x66,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0c, 0x66, 0x69, 0x6c,
+	0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x41, 0x0a, 0x14, 0x41, 0x64, 0x64,
+	0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x29, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x54, 0x0a, 0x26,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b,
+	0x65, 0x79, 0x22, 0xd1, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64,
+	0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xe9, 0x02, 0x0a, 0x10, 0x53, 0x65, 0x6e, 0x64, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x74,
+	0x61, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x74, 0x61, 0x70, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x6d, 0x61,
+	0x78, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x72, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x62, 0x75, 0x6d, 0x70, 0x5f, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x46, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12, 0x19,
+	0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x07, 0x66, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79,
+	0x5f, 0x72, 0x75, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x6f, 0x75, 0x74, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x6e, 0x70,
+	0x75, 0x74, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x04, 0x52, 0x07, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x63,
+	0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x41,
+	0x64, 0x64, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x64, 0x65,
+	0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x69, 0x64, 0x65, 0x6d, 0x70, 0x6f, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4b,
+	0x65, 0x79, 0x22, 0x85, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x65, 0x76, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x6b, 0x0a, 0x0e, 0x43, 0x6f,
+	0x69, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x6c, 0x61,
+	0x78, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65,
+	0x6c, 0x61, 0x78, 0x65, 0x64, 0x54, 0x6f, 0x22, 0xc0, 0x01, 0x0a, 0x11, 0x53, 0x65, 0x6e, 0x64,
+	0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
+	0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x12, 0x41, 0x0a, 0x10, 0x63, 0x6f, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0f, 0x63, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x35, 0x0a, 0x08, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65,
+	0x52, 0x08, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x22, 0xe5, 0x01, 0x0a, 0x11, 0x53,
+	0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65,
+	0x12, 0x2d, 0x0a, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12,
+	0x39, 0x0a, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x52, 0x07, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x75,
+	0x6d, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x50, 0x61, 0x73, 0x73, 0x69,
+	0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x66, 0x65, 0x65, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x65,
+	0x65, 0x73, 0x22, 0xed, 0x01, 0x0a, 0x17, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x2e,
+	0x0a, 0x13, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x11, 0x61, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x61,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a,
+	0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69,
+	0x73, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x49, 0x64, 0x22, 0x8b, 0x01, 0x0a, 0x16, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x74, 0x61, 0x70, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x74, 0x61, 0x70, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x61,
+	0x78, 0x5f, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6d, 0x61, 0x78, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x72, 0x65, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x62, 0x75, 0x6d, 0x70, 0x5f, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x46, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x22, 0xb0, 0x01, 0x0a, 0x17, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64,
+	0x12, 0x41, 0x0a, 0x10, 0x63, 0x6f, 0x69, 0x6e, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x0f, 0x63, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x3b, 0x0a, 0x18, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64,
+	0x22, 0x4e, 0x0a, 0x19, 0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
+	0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x22, 0x3f, 0x0a, 0x1c, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69,
+	0x64, 0x22, 0x4a, 0x0a, 0x1d, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x22, 0x49, 0x0a,
+	0x1c, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a,
+	0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x22, 0x52, 0x0a, 0x1d, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x22, 0x83, 0x01, 0x0a,
+	0x16, 0x42, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e,
+	0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x66, 0x65, 0x65, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x75, 0x73, 0x65, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x62,
+	0x75, 0x6d, 0x70, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x75, 0x73, 0x65, 0x46, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x41, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x22, 0x6b, 0x0a, 0x17, 0x42, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a,
+	0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x54, 0x78, 0x69, 0x64, 0x22,
+	0x38, 0x0a, 0x15, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x16, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x08, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x22, 0x10, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x66, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x6e, 0x64, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6c, 0x6e, 0x64, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x22, 0x12, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0xd6, 0x01, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x12, 0x2d, 0x0a, 0x12, 0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x11, 0x66, 0x65, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x73, 0x12, 0x3d, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x1c, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x63,
+	0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x63, 0x0a, 0x13, 0x53,
+	0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c,
+	0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x6f, 0x72, 0x73,
+	0x22, 0x52, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x73, 0x22, 0x74, 0x0a, 0x23, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e,
+	0x74, 0x66, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x61, 0x66,
+	0x74, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xf7, 0x07, 0x0a, 0x0e, 0x53,
+	0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x58, 0x0a,
+	0x18, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65,
+	0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x15, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x71, 0x0a, 0x21, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x25, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66,
+	0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x1d, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x5a, 0x0a, 0x18, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x61, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65, 0x64,
+	0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x62,
+	0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x16,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65,
+	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4e, 0x0a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x74, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x74, 0x72,
+	0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x5b, 0x0a, 0x19, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x62, 0x75, 0x6d, 0x70, 0x65, 0x64, 0x5f, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65, 0x42, 0x75,
+	0x6d, 0x70, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x16, 0x74, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x7a, 0x0a, 0x24, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x5f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79,
+	0x50, 0x61, 0x75, 0x73, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x20, 0x72,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x50, 0x61, 0x75, 0x73, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x6a, 0x0a, 0x1e, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x5f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x1b,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x57, 0x0a, 0x17, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x15, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x4e, 0x0a, 0x14, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x5f, 0x72, 0x65, 0x6f, 0x72, 0x67, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x4f, 0x72, 0x67, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x12, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x6f, 0x72, 0x67, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x5a, 0x0a, 0x18, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x5f, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65,
+	0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x16, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x65,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x6a, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x53,
+	0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x6e, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x22, 0xcf, 0x01, 0x0a, 0x1d, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x57, 0x61, 0x69, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x18, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x74, 0x72, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x12,
+	0x1b, 0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x5f, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x6e, 0x75, 0x6d, 0x54, 0x72, 0x69, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x16,
+	0x6e, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f, 0x74, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x14, 0x6e, 0x65,
+	0x78, 0x74, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x22, 0x88, 0x01, 0x0a, 0x20, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
+	0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x22, 0xd8, 0x01,
+	0x0a, 0x1b, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x27, 0x0a, 0x0f, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4f, 0x75, 0x74, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x75,
+	0x72, 0x69, 0x65, 0x72, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x6f, 0x75, 0x72, 0x69, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x22, 0xa0, 0x01, 0x0a, 0x15, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69,
+	0x64, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x52, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x72, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x64, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e,
+	0x75, 0x6d, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x22, 0x81, 0x01, 0x0a, 0x12,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x4f, 0x72, 0x67, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0xa1, 0x01, 0x0a, 0x16, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x41, 0x62, 0x61, 0x6e,
+	0x64, 0x6f, 0x6e, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x6f, 0x6e,
+	0x66, 0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x69, 0x6e, 0x67,
+	0x54, 0x78, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6c, 0x6c, 0x5f, 0x72, 0x65, 0x74,
+	0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x77, 0x69, 0x6c, 0x6c, 0x52, 0x65,
+	0x74, 0x72, 0x79, 0x22, 0x97, 0x01, 0x0a, 0x12, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x52, 0x65, 0x74, 0x72, 0x79, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x62, 0x61, 0x6e,
+	0x64, 0x6f, 0x6e, 0x65, 0x64, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x61, 0x62, 0x61, 0x6e, 0x64, 0x6f, 0x6e, 0x65, 0x64, 0x54, 0x78, 0x69, 0x64, 0x12,
+	0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x77, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x77, 0x41, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x57, 0x0a,
+	0x16, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x6c,
+	0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f,
+	0x74, 0x78, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x6e, 0x63, 0x68,
+	0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x22, 0xe0, 0x01, 0x0a, 0x16, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x46, 0x65, 0x65, 0x42, 0x75, 0x6d, 0x70, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12,
+	0x26, 0x0a, 0x0f, 0x6f, 0x6c, 0x64, 0x5f, 0x61, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6f, 0x6c, 0x64, 0x41, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x77, 0x5f, 0x61,
+	0x6e, 0x63, 0x68, 0x6f, 0x72, 0x5f, 0x74, 0x78, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x77, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x54, 0x78, 0x69, 0x64, 0x12,
+	0x19, 0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x66, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x5f, 0x66, 0x65, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x63, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x65, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x73, 0x63,
+	0x61, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x65,
+	0x73, 0x63, 0x61, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xa6, 0x01, 0x0a, 0x15, 0x46, 0x65,
+	0x74, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x09, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x12,
+	0x22, 0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64,
+	0x53, 0x74, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x65, 0x74, 0x61, 0x5f, 0x68, 0x61, 0x73, 0x68,
+	0x5f, 0x73, 0x74, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x6d, 0x65,
+	0x74, 0x61, 0x48, 0x61, 0x73, 0x68, 0x53, 0x74, 0x72, 0x42, 0x07, 0x0a, 0x05, 0x61, 0x73, 0x73,
+	0x65, 0x74, 0x22, 0x5c, 0x0a, 0x19, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x42, 0x79, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65,
+	0x5f, 0x75, 0x6e, 0x69, 0x76, 0x65, 0x72, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x55, 0x6e, 0x69, 0x76, 0x65, 0x72, 0x73, 0x65,
+	0x22, 0xbd, 0x01, 0x0a, 0x0e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x38, 0x0a, 0x0d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x67, 0x65, 0x6e,
+	0x65, 0x73, 0x69, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x12, 0x30, 0x0a,
+	0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x61, 0x73, 0x73, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x6d, 0x65, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x04, 0x6d, 0x65, 0x74, 0x61,
+	0x22, 0x4c, 0x0a, 0x1a, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x42, 0x79, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e,
+	0x0a, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74,
+	0x61, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x06, 0x61, 0x73, 0x73, 0x65, 0x74, 0x73, 0x22, 0xaf,
+	0x01, 0x0a, 0x10, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x08, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x07, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x61, 0x73, 0x73, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x5f, 0x73, 0x74, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x61, 0x73, 0x73, 0x65, 0x74, 0x49,
+	0x64, 0x53, 0x74, 0x72, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x74,
+	0x6f, 0x5f, 0x62, 0x75, 0x72, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x54, 0x6f, 0x42, 0x75, 0x72, 0x6e, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x65, 0x78, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x22, 0x84, 0x01, 0x0a, 0x11, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x0d, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x0c, 0x62, 0x75, 0x72, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x12, 0x33, 0x0a, 0x0a, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x09, 0x62, 0x75,
+	0x72, 0x6e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x69, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a,
+	0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x22, 0x7a, 0x0a, 0x12, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x75, 0x6d, 0x5f,
+	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x6e,
+	0x75, 0x6d, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x70, 0x35, 0x30,
+	0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x35, 0x30, 0x4d, 0x73,
+	0x12, 0x15, 0x0a, 0x06, 0x70, 0x39, 0x30, 0x5f, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x05, 0x70, 0x39, 0x30, 0x4d, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x70, 0x39, 0x39, 0x5f, 0x6d,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x70, 0x39, 0x39, 0x4d, 0x73, 0x22, 0xf8,
+	0x02, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a,
+	0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x65,
+	0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x23, 0x0a, 0x0d, 0x6e,
+	0x75, 0x6d, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73,
+	0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x43, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x2c, 0x0a, 0x12, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x50, 0x65, 0x72, 0x48,
+	0x6f, 0x75, 0x72, 0x12, 0x4d, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x13, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x72, 0x6d, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x12, 0x45, 0x0a, 0x10, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6c,
+	0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x79, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x1a, 0x0a, 0x18, 0x41, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x48, 0x0a, 0x0f, 0x53, 0x77, 0x65, 0x65, 0x70, 0x61, 0x62,
+	0x6c, 0x65, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x75, 0x74, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x73, 0x22,
+	0x7e, 0x0a, 0x0b, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x12, 0x1a,
+	0x0a, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x6f, 0x75, 0x74, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d,
+	0x74, 0x5f, 0x73, 0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d,
+	0x74, 0x53, 0x61, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x77, 0x65, 0x65, 0x70, 0x5f, 0x74,
+	0x78, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x77, 0x65, 0x65, 0x70,
+	0x54, 0x78, 0x69, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x77, 0x65, 0x70, 0x74, 0x5f, 0x61, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73, 0x77, 0x65, 0x70, 0x74, 0x41, 0x74, 0x22,
+	0x96, 0x03, 0x0a, 0x19, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x31, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x53, 0x61, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x1a, 0x6d, 0x61,
+	0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x73, 0x61, 0x74, 0x5f, 0x70,
+	0x65, 0x72, 0x5f, 0x76, 0x62, 0x79, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15,
+	0x6d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x52, 0x61, 0x74, 0x65, 0x53, 0x61, 0x74, 0x50, 0x65, 0x72,
+	0x56, 0x62, 0x79, 0x74, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x66, 0x65, 0x65, 0x5f, 0x62, 0x75, 0x64,
+	0x67, 0x65, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x10, 0x66, 0x65, 0x65, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x50, 0x65, 0x72, 0x63,
+	0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x41,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6c, 0x61, 0x73, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x6c, 0x6f, 0x67,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x77, 0x65, 0x65, 0x70, 0x61, 0x62, 0x6c, 0x65, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x52,
+	0x07, 0x62, 0x61, 0x63, 0x6b, 0x6c, 0x6f, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x61, 0x63, 0x6b,
+	0x6c, 0x6f, 0x67, 0x5f, 0x73, 0x61, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x62, 0x61, 0x63, 0x6b, 0x6c, 0x6f, 0x67, 0x53, 0x61, 0x74, 0x73, 0x12, 0x2b, 0x0a, 0x06, 0x73,
+	0x77, 0x65, 0x65, 0x70, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70,
+	0x52, 0x06, 0x73, 0x77, 0x65, 0x65, 0x70, 0x73, 0x2a, 0x28, 0x0a, 0x09, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x4e, 0x4f, 0x52, 0x4d, 0x41, 0x4c, 0x10,
+	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x4f, 0x4c, 0x4c, 0x45, 0x43, 0x54, 0x49, 0x42, 0x4c, 0x45,
+	0x10, 0x01, 0x2a, 0x39, 0x0a, 0x0d, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x10, 0x4d, 0x45, 0x54, 0x41, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x4f, 0x50, 0x41, 0x51, 0x55, 0x45, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x4d, 0x45, 0x54,
+	0x41, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x4a, 0x53, 0x4f, 0x4e, 0x10, 0x01, 0x2a, 0x3a, 0x0a,
+	0x0c, 0x41, 0x73, 0x73, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
+	0x10, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x56,
+	0x30, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x56, 0x45, 0x52,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x56, 0x31, 0x10, 0x01, 0x2a, 0xc6, 0x01, 0x0a, 0x0a, 0x4f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x12, 0x4f, 0x55, 0x54, 0x50,
+	0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x10, 0x00,
+	0x12, 0x1a, 0x0a, 0x16, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x53, 0x50, 0x4c, 0x49, 0x54, 0x5f, 0x52, 0x4f, 0x4f, 0x54, 0x10, 0x01, 0x12, 0x23, 0x0a, 0x1f,
+	0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x50, 0x41, 0x53, 0x53,
+	0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x53, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10,
+	0x02, 0x12, 0x22, 0x0a, 0x1e, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45,
+	0x5f, 0x50, 0x41, 0x53, 0x53, 0x49, 0x56, 0x45, 0x5f, 0x53, 0x50, 0x4c, 0x49, 0x54, 0x5f, 0x52,
+	0x4f, 0x4f, 0x54, 0x10, 0x03, 0x12, 0x25, 0x0a, 0x21, 0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f,
+	0x54, 0x59, 0x50, 0x45, 0x5f, 0x53, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x5f, 0x50, 0x41, 0x53, 0x53,
+	0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x53, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10,
+	0x4f, 0x55, 0x54, 0x50, 0x55, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x42, 0x55, 0x52, 0x4e,
+	0x10, 0x05, 0x2a, 0xc2, 0x01, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69,
+	0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x1a, 0x50, 0x52,
+	0x4f, 0x4f, 0x46, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x50, 0x52,
+	0x4f, 0x4f, 0x46, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x20, 0x0a, 0x1c, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56, 0x45, 0x52,
+	0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x21, 0x0a, 0x1d, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f, 0x44, 0x45, 0x4c, 0x49, 0x56,
+	0x45, 0x52, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x4b, 0x49, 0x50, 0x50,
+	0x45, 0x44, 0x10, 0x03, 0x12, 0x21, 0x0a, 0x1d, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f, 0x44, 0x45,
+	0x4c, 0x49, 0x56, 0x45, 0x52, 0x59, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x45,
+	0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x2a, 0xd0, 0x01, 0x0a, 0x0f, 0x41, 0x64, 0x64, 0x72,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x19, 0x41,
+	0x44, 0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x2a, 0x0a, 0x26, 0x41, 0x44,
+	0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x54, 0x52, 0x41, 0x4e, 0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x54, 0x45,
+	0x43, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x2b, 0x0a, 0x27, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x45,
+	0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x52, 0x41, 0x4e,
+	0x53, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x52, 0x4d, 0x45,
+	0x44, 0x10, 0x02, 0x12, 0x24, 0x0a, 0x20, 0x41, 0x44, 0x44, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e,
+	0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f, 0x52,
+	0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10, 0x03, 0x12, 0x1f, 0x0a, 0x1b, 0x41, 0x44, 0x44,
+	0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43,
+	0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x04, 0x2a, 0xf2, 0x01, 0x0a, 0x12, 0x52,
+	0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53,
+	0x45, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x44, 0x45, 0x54, 0x45, 0x43, 0x54,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f,
+	0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4e,
+	0x46, 0x49, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x01, 0x12, 0x27, 0x0a, 0x23, 0x52, 0x45, 0x43, 0x45,
+	0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x27, 0x0a, 0x23, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53,
+	0x45, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x4f, 0x46, 0x5f,
+	0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x03, 0x12, 0x22, 0x0a, 0x1e, 0x52, 0x45,
+	0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x04, 0x12, 0x1f,
+	0x0a, 0x1b, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x41, 0x53, 0x53, 0x45, 0x54, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x05, 0x32,
+	0xc4, 0x1f, 0x0a, 0x0d, 0x54, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x73, 0x12, 0x41, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12,
+	0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73,
+	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x12, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x40,
+	0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x12, 0x18, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x55, 0x74, 0x78, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x61, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x46,
+	0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x23, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x6e, 0x43, 0x68, 0x61, 0x69, 0x6e, 0x46, 0x6f, 0x6f,
+	0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x6e, 0x43, 0x68, 0x61,
+	0x69, 0x6e, 0x46, 0x6f, 0x6f, 0x74, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x73, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6a, 0x0a, 0x17, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x12, 0x26, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x53, 0x75, 0x70, 0x70, 0x6c, 0x79, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74,
+	0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x75, 0x70, 0x70, 0x6c,
+	0x79, 0x43, 0x6f, 0x6e, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x42,
+	0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x52, 0x0a, 0x0f, 0x52, 0x65, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x12, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x73, 0x12, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x6d, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x73, 0x42, 0x79, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x73, 0x42, 0x79, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x73, 0x42, 0x79, 0x53,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x55, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x12, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x47, 0x72, 0x61, 0x70, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70, 0x44,
+	0x61, 0x65, 0x6d, 0x6f, 0x6e, 0x12, 0x13, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x6f, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x43, 0x0a, 0x0a, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x19,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44,
+	0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x12, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x44, 0x61, 0x74, 0x61, 0x62, 0x61, 0x73, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41,
+	0x64, 0x64, 0x72, 0x73, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x41, 0x64, 0x64,
+	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x4e, 0x65, 0x77,
+	0x41, 0x64, 0x64, 0x72, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65,
+	0x77, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x12, 0x49, 0x0a, 0x0c, 0x4e, 0x65,
+	0x77, 0x41, 0x64, 0x64, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x4e, 0x65, 0x77, 0x41, 0x64, 0x64, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x0a, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x41,
+	0x64, 0x64, 0x72, 0x12, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63,
+	0x6f, 0x64, 0x65, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x12, 0x49, 0x0a, 0x0c,
+	0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x12, 0x1b, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
+	0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0b, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x1a, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44,
+	0x65, 0x63, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6a,
+	0x0a, 0x17, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x6d, 0x70,
+	0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x26, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x65, 0x63, 0x6b,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x74, 0x69, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x0b, 0x45, 0x78,
+	0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x1a, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x52, 0x0a, 0x11, 0x45, 0x78, 0x70, 0x6f,
+	0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x20, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x73, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x65,
+	0x64, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x30, 0x01, 0x12, 0x4f, 0x0a, 0x11,
+	0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72,
+	0x74, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x44, 0x0a,
+	0x0f, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73,
+	0x12, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x5b, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x43, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x61, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4d, 0x65, 0x72,
+	0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x23, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x4d, 0x65, 0x72, 0x6b, 0x6c,
+	0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72,
+	0x4d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x79, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x12, 0x23, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x24, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65,
+	0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x63, 0x65, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x12, 0x50, 0x61, 0x75, 0x73, 0x65, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x21, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x12, 0x22, 0x2e, 0x74, 0x61, 0x70,
+	0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c,
+	0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x23, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x61, 0x64, 0x4c, 0x65, 0x74,
+	0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x24, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65,
+	0x61, 0x64, 0x4c, 0x65, 0x74, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x50, 0x72, 0x65, 0x70,
+	0x61, 0x72, 0x65, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x11,
+	0x42, 0x72, 0x6f, 0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65,
+	0x72, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x72, 0x6f, 0x61, 0x64,
+	0x63, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x72, 0x6f,
+	0x61, 0x64, 0x63, 0x61, 0x73, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x15, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
+	0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12,
+	0x24, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x50,
+	0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x45,
+	0x78, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x15,
+	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x24, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x50, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x52, 0x0a, 0x0f, 0x42, 0x75, 0x6d, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66,
+	0x65, 0x72, 0x46, 0x65, 0x65, 0x12, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42,
+	0x75, 0x6d, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42,
+	0x75, 0x6d, 0x70, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x46, 0x65, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x12, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x42, 0x75, 0x72, 0x6e, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75,
+	0x72, 0x6e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x72, 0x6e, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x18, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x74,
+	0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x1c, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e, 0x73, 0x12, 0x2b, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x53, 0x65, 0x6e, 0x64, 0x41, 0x73,
+	0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x6e, 0x64, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x5e,
+	0x0a, 0x13, 0x47, 0x65, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x61,
+	0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x22, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x63, 0x68, 0x69,
+	0x6e, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x74, 0x61, 0x70, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x65, 0x6e, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x4d,
+	0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6e,
+	0x0a, 0x1f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e,
+	0x73, 0x12, 0x2e, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x4e, 0x74, 0x66, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x41, 0x73, 0x73, 0x65, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x42,
+	0x0a, 0x0e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61,
+	0x12, 0x1d, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x41,
+	0x73, 0x73, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x11, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x4d, 0x65,
+	0x74, 0x61, 0x12, 0x5b, 0x0a, 0x12, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65,
+	0x74, 0x73, 0x42, 0x79, 0x4d, 0x65, 0x74, 0x61, 0x12, 0x21, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74, 0x73, 0x42, 0x79,
+	0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x61,
+	0x70, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x73, 0x42, 0x79, 0x4d, 0x65, 0x74, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5b, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x21, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70,
+	0x63, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x11,
+	0x41, 0x6e, 0x63, 0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x20, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63, 0x68, 0x6f,
+	0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e, 0x63,
+	0x68, 0x6f, 0x72, 0x53, 0x77, 0x65, 0x65, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6c, 0x61,
+	0x62, 0x73, 0x2f, 0x74, 0x61, 0x70, 0x72, 0x6f, 0x6f, 0x74, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x73, 0x2f, 0x74, 0x61, 0x70, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_taprootassets_proto_rawDescOnce sync.Once
+	file_taprootassets_proto_rawDescData = file_taprootassets_proto_rawDesc
+)
+
+func file_taprootassets_proto_rawDescGZIP() []byte {
+	file_taprootassets_proto_rawDescOnce.Do(func() {
+		file_taprootassets_proto_rawDescData = protoimpl.X.CompressGZIP(file_taprootassets_proto_rawDescData)
+	})
+	return file_taprootassets_proto_rawDescData
+}
+
+var file_taprootassets_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_taprootassets_proto_msgTypes = make([]protoimpl.MessageInfo, 153)
+var file_taprootassets_proto_goTypes = []interface{}{
+	(AssetType)(0),                                 // 0: taprpc.AssetType
+	(AssetMetaType)(0),                             // 1: taprpc.AssetMetaType
+	(AssetVersion)(0),                              // 2: taprpc.AssetVersion
+	(OutputType)(0),                                // 3: taprpc.OutputType
+	(ProofDeliveryStatus)(0),                       // 4: taprpc.ProofDeliveryStatus
+	(AddrEventStatus)(0),                           // 5: taprpc.AddrEventStatus
+	(ReceiveAssetStatus)(0),                        // 6: taprpc.ReceiveAssetStatus
+	(*AssetMeta)(nil),                              // 7: taprpc.AssetMeta
+	(*ListAssetRequest)(nil),                       // 8: taprpc.ListAssetRequest
+	(*AnchorInfo)(nil),                             // 9: taprpc.AnchorInfo
+	(*GenesisInfo)(nil),                            // 10: taprpc.GenesisInfo
+	(*AssetGroup)(nil),                             // 11: taprpc.AssetGroup
+	(*GroupKeyReveal)(nil),                         // 12: taprpc.GroupKeyReveal
+	(*GenesisReveal)(nil),                          // 13: taprpc.GenesisReveal
+	(*Asset)(nil),                                  // 14: taprpc.Asset
+	(*PrevWitness)(nil),                            // 15: taprpc.PrevWitness
+	(*SplitCommitment)(nil),                        // 16: taprpc.SplitCommitment
+	(*ListAssetResponse)(nil),                      // 17: taprpc.ListAssetResponse
+	(*SubscribeAssetsRequest)(nil),                 // 18: taprpc.SubscribeAssetsRequest
+	(*AssetUpdate)(nil),                            // 19: taprpc.AssetUpdate
+	(*ListUtxosRequest)(nil),                       // 20: taprpc.ListUtxosRequest
+	(*ManagedUtxo)(nil),                            // 21: taprpc.ManagedUtxo
+	(*ListUtxosResponse)(nil),                      // 22: taprpc.ListUtxosResponse
+	(*ListOnChainFootprintRequest)(nil),            // 23: taprpc.ListOnChainFootprintRequest
+	(*FootprintAsset)(nil),                         // 24: taprpc.FootprintAsset
+	(*FootprintUtxo)(nil),                          // 25: taprpc.FootprintUtxo
+	(*ListOnChainFootprintResponse)(nil),           // 26: taprpc.ListOnChainFootprintResponse
+	(*ListGroupsRequest)(nil),                      // 27: taprpc.ListGroupsRequest
+	(*VerifySupplyConsistencyRequest)(nil),         // 28: taprpc.VerifySupplyConsistencyRequest
+	(*VerifySupplyConsistencyResponse)(nil),        // 29: taprpc.VerifySupplyConsistencyResponse
+	(*AssetHumanReadable)(nil),                     // 30: taprpc.AssetHumanReadable
+	(*GroupedAssets)(nil),                          // 31: taprpc.GroupedAssets
+	(*ListGroupsResponse)(nil),                     // 32: taprpc.ListGroupsResponse
+	(*ListBalancesRequest)(nil),                    // 33: taprpc.ListBalancesRequest
+	(*AssetBalance)(nil),                           // 34: taprpc.AssetBalance
+	(*AssetGroupBalance)(nil),                      // 35: taprpc.AssetGroupBalance
+	(*ListBalancesResponse)(nil),                   // 36: taprpc.ListBalancesResponse
+	(*RebuildBalancesRequest)(nil),                 // 37: taprpc.RebuildBalancesRequest
+	(*AssetBalanceDiscrepancy)(nil),                // 38: taprpc.AssetBalanceDiscrepancy
+	(*BalanceChange)(nil),                          // 39: taprpc.BalanceChange
+	(*RebuildBalancesResponse)(nil),                // 40: taprpc.RebuildBalancesResponse
+	(*ListTransfersRequest)(nil),                   // 41: taprpc.ListTransfersRequest
+	(*ListTransfersResponse)(nil),                  // 42: taprpc.ListTransfersResponse
+	(*ListTransfersByScriptKeyRequest)(nil),        // 43: taprpc.ListTransfersByScriptKeyRequest
+	(*ScriptKeyTransfer)(nil),                      // 44: taprpc.ScriptKeyTransfer
+	(*ListTransfersByScriptKeyResponse)(nil),       // 45: taprpc.ListTransfersByScriptKeyResponse
+	(*GetTransferGraphRequest)(nil),                // 46: taprpc.GetTransferGraphRequest
+	(*TransferEdge)(nil),                           // 47: taprpc.TransferEdge
+	(*GetTransferGraphResponse)(nil),               // 48: taprpc.GetTransferGraphResponse
+	(*AssetTransfer)(nil),                          // 49: taprpc.AssetTransfer
+	(*TransferInput)(nil),                          // 50: taprpc.TransferInput
+	(*TransferOutputAnchor)(nil),                   // 51: taprpc.TransferOutputAnchor
+	(*TransferOutput)(nil),                         // 52: taprpc.TransferOutput
+	(*ProofDelivery)(nil),                          // 53: taprpc.ProofDelivery
+	(*StopRequest)(nil),                            // 54: taprpc.StopRequest
+	(*StopResponse)(nil),                           // 55: taprpc.StopResponse
+	(*DebugLevelRequest)(nil),                      // 56: taprpc.DebugLevelRequest
+	(*DebugLevelResponse)(nil),                     // 57: taprpc.DebugLevelResponse
+	(*BackupDatabaseRequest)(nil),                  // 58: taprpc.BackupDatabaseRequest
+	(*BackupDatabaseResponse)(nil),                 // 59: taprpc.BackupDatabaseResponse
+	(*Addr)(nil),                                   // 60: taprpc.Addr
+	(*QueryAddrRequest)(nil),                       // 61: taprpc.QueryAddrRequest
+	(*QueryAddrResponse)(nil),                      // 62: taprpc.QueryAddrResponse
+	(*NewAddrRequest)(nil),                         // 63: taprpc.NewAddrRequest
+	(*NewAddrBatchRequest)(nil),                    // 64: taprpc.NewAddrBatchRequest
+	(*NewAddrBatchResponse)(nil),                   // 65: taprpc.NewAddrBatchResponse
+	(*ScriptKeyTapscript)(nil),                     // 66: taprpc.ScriptKeyTapscript
+	(*ScriptKey)(nil),                              // 67: taprpc.ScriptKey
+	(*KeyLocator)(nil),                             // 68: taprpc.KeyLocator
+	(*KeyDescriptor)(nil),                          // 69: taprpc.KeyDescriptor
+	(*DecodeAddrRequest)(nil),                      // 70: taprpc.DecodeAddrRequest
+	(*ProofFile)(nil),                              // 71: taprpc.ProofFile
+	(*DecodedProof)(nil),                           // 72: taprpc.DecodedProof
+	(*VerifyProofResponse)(nil),                    // 73: taprpc.VerifyProofResponse
+	(*DecodeProofRequest)(nil),                     // 74: taprpc.DecodeProofRequest
+	(*DecodeProofResponse)(nil),                    // 75: taprpc.DecodeProofResponse
+	(*CheckProofCompatibilityRequest)(nil),         // 76: taprpc.CheckProofCompatibilityRequest
+	(*ProofConformanceCheck)(nil),                  // 77: taprpc.ProofConformanceCheck
+	(*CheckProofCompatibilityResponse)(nil),        // 78: taprpc.CheckProofCompatibilityResponse
+	(*ExportProofRequest)(nil),                     // 79: taprpc.ExportProofRequest
+	(*ExportProofsBatchRequest)(nil),               // 80: taprpc.ExportProofsBatchRequest
+	(*ExportedProofFile)(nil),                      // 81: taprpc.ExportedProofFile
+	(*ExportProofStreamRequest)(nil),               // 82: taprpc.ExportProofStreamRequest
+	(*ProofFileChunk)(nil),                         // 83: taprpc.ProofFileChunk
+	(*GetSplitCommitmentRequest)(nil),              // 84: taprpc.GetSplitCommitmentRequest
+	(*GetSplitCommitmentResponse)(nil),             // 85: taprpc.GetSplitCommitmentResponse
+	(*GetAnchorMerkleProofRequest)(nil),            // 86: taprpc.GetAnchorMerkleProofRequest
+	(*GetAnchorMerkleProofResponse)(nil),           // 87: taprpc.GetAnchorMerkleProofResponse
+	(*ListDeliveryReceiptsRequest)(nil),            // 88: taprpc.ListDeliveryReceiptsRequest
+	(*DeliveryReceipt)(nil),                        // 89: taprpc.DeliveryReceipt
+	(*ListDeliveryReceiptsResponse)(nil),           // 90: taprpc.ListDeliveryReceiptsResponse
+	(*PauseProofDeliveryRequest)(nil),              // 91: taprpc.PauseProofDeliveryRequest
+	(*PauseProofDeliveryResponse)(nil),             // 92: taprpc.PauseProofDeliveryResponse
+	(*ResumeProofDeliveryRequest)(nil),             // 93: taprpc.ResumeProofDeliveryRequest
+	(*ResumeProofDeliveryResponse)(nil),            // 94: taprpc.ResumeProofDeliveryResponse
+	(*ListDeadLetterProofsRequest)(nil),            // 95: taprpc.ListDeadLetterProofsRequest
+	(*DeadLetterProof)(nil),                        // 96: taprpc.DeadLetterProof
+	(*ListDeadLetterProofsResponse)(nil),           // 97: taprpc.ListDeadLetterProofsResponse
+	(*MergeProofFilesRequest)(nil),                 // 98: taprpc.MergeProofFilesRequest
+	(*AddrEvent)(nil),                              // 99: taprpc.AddrEvent
+	(*AddrReceivesRequest)(nil),                    // 100: taprpc.AddrReceivesRequest
+	(*AddrReceivesResponse)(nil),                   // 101: taprpc.AddrReceivesResponse
+	(*SubscribeReceiveAssetEventNtfnsRequest)(nil), // 102: taprpc.SubscribeReceiveAssetEventNtfnsRequest
+	(*ReceiveAssetEvent)(nil),                      // 103: taprpc.ReceiveAssetEvent
+	(*SendAssetRequest)(nil),                       // 104: taprpc.SendAssetRequest
+	(*PrevInputAsset)(nil),                         // 105: taprpc.PrevInputAsset
+	(*CoinRelaxation)(nil),                         // 106: taprpc.CoinRelaxation
+	(*SendAssetResponse)(nil),                      // 107: taprpc.SendAssetResponse
+	(*SendAssetEstimate)(nil),                      // 108: taprpc.SendAssetEstimate
+	(*SendAssetEstimateOutput)(nil),                // 109: taprpc.SendAssetEstimateOutput
+	(*PrepareTransferRequest)(nil),                 // 110: taprpc.PrepareTransferRequest
+	(*PrepareTransferResponse)(nil),                // 111: taprpc.PrepareTransferResponse
+	(*BroadcastTransferRequest)(nil),               // 112: taprpc.BroadcastTransferRequest
+	(*BroadcastTransferResponse)(nil),              // 113: taprpc.BroadcastTransferResponse
+	(*ExportPendingTransferRequest)(nil),           // 114: taprpc.ExportPendingTransferRequest
+	(*ExportPendingTransferResponse)(nil),          // 115: taprpc.ExportPendingTransferResponse
+	(*ImportPendingTransferRequest)(nil),           // 116: taprpc.ImportPendingTransferRequest
+	(*ImportPendingTransferResponse)(nil),          // 117: taprpc.ImportPendingTransferResponse
+	(*BumpTransferFeeRequest)(nil),                 // 118: taprpc.BumpTransferFeeRequest
+	(*BumpTransferFeeResponse)(nil),                // 119: taprpc.BumpTransferFeeResponse
+	(*CancelTransferRequest)(nil),                  // 120: taprpc.CancelTransferRequest
+	(*CancelTransferResponse)(nil),                 // 121: taprpc.CancelTransferResponse
+	(*GetInfoRequest)(nil),                         // 122: taprpc.GetInfoRequest
+	(*GetInfoResponse)(nil),                        // 123: taprpc.GetInfoResponse
+	(*GetConfigRequest)(nil),                       // 124: taprpc.GetConfigRequest
+	(*GetConfigResponse)(nil),                      // 125: taprpc.GetConfigResponse
+	(*GetSendStateMachineRequest)(nil),             // 126: taprpc.GetSendStateMachineRequest
+	(*SendStateDefinition)(nil),                    // 127: taprpc.SendStateDefinition
+	(*GetSendStateMachineResponse)(nil),            // 128: taprpc.GetSendStateMachineResponse
+	(*SubscribeSendAssetEventNtfnsRequest)(nil),    // 129: taprpc.SubscribeSendAssetEventNtfnsRequest
+	(*SendAssetEvent)(nil),                         // 130: taprpc.SendAssetEvent
+	(*ExecuteSendStateEvent)(nil),                  // 131: taprpc.ExecuteSendStateEvent
+	(*ReceiverProofBackoffWaitEvent)(nil),          // 132: taprpc.ReceiverProofBackoffWaitEvent
+	(*ReceiverProofDeliveryPausedEvent)(nil),       // 133: taprpc.ReceiverProofDeliveryPausedEvent
+	(*ReceiverProofDeliveredEvent)(nil),            // 134: taprpc.ReceiverProofDeliveredEvent
+	(*TransferCompleteEvent)(nil),                  // 135: taprpc.TransferCompleteEvent
+	(*TransferReOrgEvent)(nil),                     // 136: taprpc.TransferReOrgEvent
+	(*TransferAbandonedEvent)(nil),                 // 137: taprpc.TransferAbandonedEvent
+	(*TransferRetryEvent)(nil),                     // 138: taprpc.TransferRetryEvent
+	(*TransferCancelledEvent)(nil),                 // 139: taprpc.TransferCancelledEvent
+	(*TransferFeeBumpedEvent)(nil),                 // 140: taprpc.TransferFeeBumpedEvent
+	(*FetchAssetMetaRequest)(nil),                  // 141: taprpc.FetchAssetMetaRequest
+	(*SearchAssetsByMetaRequest)(nil),              // 142: taprpc.SearchAssetsByMetaRequest
+	(*AssetMetaMatch)(nil),                         // 143: taprpc.AssetMetaMatch
+	(*SearchAssetsByMetaResponse)(nil),             // 144: taprpc.SearchAssetsByMetaResponse
+	(*BurnAssetRequest)(nil),                       // 145: taprpc.BurnAssetRequest
+	(*BurnAssetResponse)(nil),                      // 146: taprpc.BurnAssetResponse
+	(*GetTransferMetricsRequest)(nil),              // 147: taprpc.GetTransferMetricsRequest
+	(*LatencyPercentiles)(nil),                     // 148: taprpc.LatencyPercentiles
+	(*GetTransferMetricsResponse)(nil),             // 149: taprpc.GetTransferMetricsResponse
+	(*AnchorSweepStatusRequest)(nil),               // 150: taprpc.AnchorSweepStatusRequest
+	(*SweepableAnchor)(nil),                        // 151: taprpc.SweepableAnchor
+	(*AnchorSweep)(nil),                            // 152: taprpc.AnchorSweep
+	(*AnchorSweepStatusResponse)(nil),              // 153: taprpc.AnchorSweepStatusResponse
+	nil,                                            // 154: taprpc.ListUtxosResponse.ManagedUtxosEntry
+	nil,                                            // 155: taprpc.ListGroupsResponse.GroupsEntry
+	nil,                                            // 156: taprpc.AssetGroupBalance.AssetBalancesEntry
+	nil,                                            // 157: taprpc.ListBalancesResponse.AssetBalancesEntry
+	nil,                                            // 158: taprpc.ListBalancesResponse.AssetGroupBalancesEntry
+	nil,                                            // 159: taprpc.GetConfigResponse.ConfigEntry
+}
+var file_taprootassets_proto_depIdxs = []int32{
+	1,   // 0: taprpc.AssetMeta.type:type_name -> taprpc.AssetMetaType
+	10,  // 1: taprpc.GenesisReveal.genesis_base_reveal:type_name -> taprpc.GenesisInfo
+	0,   // 2: taprpc.GenesisReveal.asset_type:type_name -> taprpc.AssetType
+	2,   // 3: taprpc.Asset.version:type_name -> taprpc.AssetVersion
+	10,  // 4: taprpc.Asset.asset_genesis:type_name -> taprpc.GenesisInfo
+	0,   // 5: taprpc.Asset.asset_type:type_name -> taprpc.AssetType
+	11,  // 6: taprpc.Asset.asset_group:type_name -> taprpc.AssetGroup
+	9,   // 7: taprpc.Asset.chain_anchor:type_name -> taprpc.AnchorInfo
+	15,  // 8: taprpc.Asset.prev_witnesses:type_name -> taprpc.PrevWitness
+	105, // 9: taprpc.PrevWitness.prev_id:type_name -> taprpc.PrevInputAsset
+	16,  // 10: taprpc.PrevWitness.split_commitment:type_name -> taprpc.SplitCommitment
+	14,  // 11: taprpc.SplitCommitment.root_asset:type_name -> taprpc.Asset
+	14,  // 12: taprpc.ListAssetResponse.assets:type_name -> taprpc.Asset
+	14,  // 13: taprpc.AssetUpdate.added:type_name -> taprpc.Asset
+	14,  // 14: taprpc.AssetUpdate.removed:type_name -> taprpc.Asset
+	14,  // 15: taprpc.AssetUpdate.changed:type_name -> taprpc.Asset
+	14,  // 16: taprpc.ManagedUtxo.assets:type_name -> taprpc.Asset
+	154, // 17: taprpc.ListUtxosResponse.managed_utxos:type_name -> taprpc.ListUtxosResponse.ManagedUtxosEntry
+	69,  // 18: taprpc.FootprintUtxo.internal_key:type_name -> taprpc.KeyDescriptor
+	24,  // 19: taprpc.FootprintUtxo.assets:type_name -> taprpc.FootprintAsset
+	25,  // 20: taprpc.ListOnChainFootprintResponse.utxos:type_name -> taprpc.FootprintUtxo
+	0,   // 21: taprpc.AssetHumanReadable.type:type_name -> taprpc.AssetType
+	2,   // 22: taprpc.AssetHumanReadable.version:type_name -> taprpc.AssetVersion
+	30,  // 23: taprpc.GroupedAssets.assets:type_name -> taprpc.AssetHumanReadable
+	155, // 24: taprpc.ListGroupsResponse.groups:type_name -> taprpc.ListGroupsResponse.GroupsEntry
+	10,  // 25: taprpc.AssetBalance.asset_genesis:type_name -> taprpc.GenesisInfo
+	0,   // 26: taprpc.AssetBalance.asset_type:type_name -> taprpc.AssetType
+	156, // 27: taprpc.AssetGroupBalance.asset_balances:type_name -> taprpc.AssetGroupBalance.AssetBalancesEntry
+	157, // 28: taprpc.ListBalancesResponse.asset_balances:type_name -> taprpc.ListBalancesResponse.AssetBalancesEntry
+	158, // 29: taprpc.ListBalancesResponse.asset_group_balances:type_name -> taprpc.ListBalancesResponse.AssetGroupBalancesEntry
+	38,  // 30: taprpc.RebuildBalancesResponse.discrepancies:type_name -> taprpc.AssetBalanceDiscrepancy
+	39,  // 31: taprpc.RebuildBalancesResponse.balance_changes:type_name -> taprpc.BalanceChange
+	49,  // 32: taprpc.ListTransfersResponse.transfers:type_name -> taprpc.AssetTransfer
+	49,  // 33: taprpc.ScriptKeyTransfer.outbound:type_name -> taprpc.AssetTransfer
+	99,  // 34: taprpc.ScriptKeyTransfer.inbound:type_name -> taprpc.AddrEvent
+	14,  // 35: taprpc.ScriptKeyTransfer.received:type_name -> taprpc.Asset
+	44,  // 36: taprpc.ListTransfersByScriptKeyResponse.transfers:type_name -> taprpc.ScriptKeyTransfer
+	49,  // 37: taprpc.GetTransferGraphResponse.transfer:type_name -> taprpc.AssetTransfer
+	47,  // 38: taprpc.GetTransferGraphResponse.edges:type_name -> taprpc.TransferEdge
+	50,  // 39: taprpc.AssetTransfer.inputs:type_name -> taprpc.TransferInput
+	52,  // 40: taprpc.AssetTransfer.outputs:type_name -> taprpc.TransferOutput
+	51,  // 41: taprpc.TransferOutput.anchor:type_name -> taprpc.TransferOutputAnchor
+	3,   // 42: taprpc.TransferOutput.output_type:type_name -> taprpc.OutputType
+	2,   // 43: taprpc.TransferOutput.asset_version:type_name -> taprpc.AssetVersion
+	53,  // 44: taprpc.TransferOutput.proof_delivery:type_name -> taprpc.ProofDelivery
+	4,   // 45: taprpc.ProofDelivery.status:type_name -> taprpc.ProofDeliveryStatus
+	0,   // 46: taprpc.Addr.asset_type:type_name -> taprpc.AssetType
+	2,   // 47: taprpc.Addr.asset_version:type_name -> taprpc.AssetVersion
+	60,  // 48: taprpc.QueryAddrResponse.addrs:type_name -> taprpc.Addr
+	67,  // 49: taprpc.NewAddrRequest.script_key:type_name -> taprpc.ScriptKey
+	69,  // 50: taprpc.NewAddrRequest.internal_key:type_name -> taprpc.KeyDescriptor
+	2,   // 51: taprpc.NewAddrRequest.asset_version:type_name -> taprpc.AssetVersion
+	66,  // 52: taprpc.NewAddrRequest.script_key_tapscript:type_name -> taprpc.ScriptKeyTapscript
+	63,  // 53: taprpc.NewAddrBatchRequest.addrs:type_name -> taprpc.NewAddrRequest
+	60,  // 54: taprpc.NewAddrBatchResponse.addrs:type_name -> taprpc.Addr
+	69,  // 55: taprpc.ScriptKey.key_desc:type_name -> taprpc.KeyDescriptor
+	68,  // 56: taprpc.KeyDescriptor.key_loc:type_name -> taprpc.KeyLocator
+	14,  // 57: taprpc.DecodedProof.asset:type_name -> taprpc.Asset
+	7,   // 58: taprpc.DecodedProof.meta_reveal:type_name -> taprpc.AssetMeta
+	13,  // 59: taprpc.DecodedProof.genesis_reveal:type_name -> taprpc.GenesisReveal
+	12,  // 60: taprpc.DecodedProof.group_key_reveal:type_name -> taprpc.GroupKeyReveal
+	72,  // 61: taprpc.VerifyProofResponse.decoded_proof:type_name -> taprpc.DecodedProof
+	72,  // 62: taprpc.DecodeProofResponse.decoded_proof:type_name -> taprpc.DecodedProof
+	77,  // 63: taprpc.CheckProofCompatibilityResponse.checks:type_name -> taprpc.ProofConformanceCheck
+	89,  // 64: taprpc.ListDeliveryReceiptsResponse.receipts:type_name -> taprpc.DeliveryReceipt
+	96,  // 65: taprpc.ListDeadLetterProofsResponse.dead_letters:type_name -> taprpc.DeadLetterProof
+	60,  // 66: taprpc.AddrEvent.addr:type_name -> taprpc.Addr
+	5,   // 67: taprpc.AddrEvent.status:type_name -> taprpc.AddrEventStatus
+	5,   // 68: taprpc.AddrReceivesRequest.filter_status:type_name -> taprpc.AddrEventStatus
+	99,  // 69: taprpc.AddrReceivesResponse.events:type_name -> taprpc.AddrEvent
+	6,   // 70: taprpc.ReceiveAssetEvent.status:type_name -> taprpc.ReceiveAssetStatus
+	49,  // 71: taprpc.SendAssetResponse.transfer:type_name -> taprpc.AssetTransfer
+	106, // 72: taprpc.SendAssetResponse.coin_relaxations:type_name -> taprpc.CoinRelaxation
+	108, // 73: taprpc.SendAssetResponse.estimate:type_name -> taprpc.SendAssetEstimate
+	50,  // 74: taprpc.SendAssetEstimate.inputs:type_name -> taprpc.TransferInput
+	109, // 75: taprpc.SendAssetEstimate.outputs:type_name -> taprpc.SendAssetEstimateOutput
+	3,   // 76: taprpc.SendAssetEstimateOutput.output_type:type_name -> taprpc.OutputType
+	49,  // 77: taprpc.PrepareTransferResponse.transfer:type_name -> taprpc.AssetTransfer
+	106, // 78: taprpc.PrepareTransferResponse.coin_relaxations:type_name -> taprpc.CoinRelaxation
+	49,  // 79: taprpc.BroadcastTransferResponse.transfer:type_name -> taprpc.AssetTransfer
+	49,  // 80: taprpc.ImportPendingTransferResponse.transfer:type_name -> taprpc.AssetTransfer
+	49,  // 81: taprpc.BumpTransferFeeResponse.transfer:type_name -> taprpc.AssetTransfer
+	49,  // 82: taprpc.CancelTransferResponse.transfer:type_name -> taprpc.AssetTransfer
+	159, // 83: taprpc.GetConfigResponse.config:type_name -> taprpc.GetConfigResponse.ConfigEntry
+	127, // 84: taprpc.GetSendStateMachineResponse.states:type_name -> taprpc.SendStateDefinition
+	131, // 85: taprpc.SendAssetEvent.execute_send_state_event:type_name -> taprpc.ExecuteSendStateEvent
+	132, // 86: taprpc.SendAssetEvent.receiver_proof_backoff_wait_event:type_name -> taprpc.ReceiverProofBackoffWaitEvent
+	137, // 87: taprpc.SendAssetEvent.transfer_abandoned_event:type_name -> taprpc.TransferAbandonedEvent
+	138, // 88: taprpc.SendAssetEvent.transfer_retry_event:type_name -> taprpc.TransferRetryEvent
+	140, // 89: taprpc.SendAssetEvent.transfer_fee_bumped_event:type_name -> taprpc.TransferFeeBumpedEvent
+	133, // 90: taprpc.SendAssetEvent.receiver_proof_delivery_paused_event:type_name -> taprpc.ReceiverProofDeliveryPausedEvent
+	134, // 91: taprpc.SendAssetEvent.receiver_proof_delivered_event:type_name -> taprpc.ReceiverProofDeliveredEvent
+	135, // 92: taprpc.SendAssetEvent.transfer_complete_event:type_name -> taprpc.TransferCompleteEvent
+	136, // 93: taprpc.SendAssetEvent.transfer_reorg_event:type_name -> taprpc.TransferReOrgEvent
+	139, // 94: taprpc.SendAssetEvent.transfer_cancelled_event:type_name -> taprpc.TransferCancelledEvent
+	10,  // 95: taprpc.AssetMetaMatch.asset_genesis:type_name -> taprpc.GenesisInfo
+	0,   // 96: taprpc.AssetMetaMatch.asset_type:type_name -> taprpc.AssetType
+	7,   // 97: taprpc.AssetMetaMatch.meta:type_name -> taprpc.AssetMeta
+	143, // 98: taprpc.SearchAssetsByMetaResponse.assets:type_name -> taprpc.AssetMetaMatch
+	49,  // 99: taprpc.BurnAssetResponse.burn_transfer:type_name -> taprpc.AssetTransfer
+	72,  // 100: taprpc.BurnAssetResponse.burn_proof:type_name -> taprpc.DecodedProof
+	148, // 101: taprpc.GetTransferMetricsResponse.confirmation_latency:type_name -> taprpc.LatencyPercentiles
+	148, // 102: taprpc.GetTransferMetricsResponse.delivery_latency:type_name -> taprpc.LatencyPercentiles
+	151, // 103: taprpc.AnchorSweepStatusResponse.backlog:type_name -> taprpc.SweepableAnchor
+	152, // 104: taprpc.AnchorSweepStatusResponse.sweeps:type_name -> taprpc.AnchorSweep
+	21,  // 105: taprpc.ListUtxosResponse.ManagedUtxosEntry.value:type_name -> taprpc.ManagedUtxo
+	31,  // 106: taprpc.ListGroupsResponse.GroupsEntry.value:type_name -> taprpc.GroupedAssets
+	34,  // 107: taprpc.AssetGroupBalance.AssetBalancesEntry.value:type_name -> taprpc.AssetBalance
+	34,  // 108: taprpc.ListBalancesResponse.AssetBalancesEntry.value:type_name -> taprpc.AssetBalance
+	35,  // 109: taprpc.ListBalancesResponse.AssetGroupBalancesEntry.value:type_name -> taprpc.AssetGroupBalance
+	8,   // 110: taprpc.TaprootAssets.ListAssets:input_type -> taprpc.ListAssetRequest
+	18,  // 111: taprpc.TaprootAssets.SubscribeAssets:input_type -> taprpc.SubscribeAssetsRequest
+	20,  // 112: taprpc.TaprootAssets.ListUtxos:input_type -> taprpc.ListUtxosRequest
+	23,  // 113: taprpc.TaprootAssets.ListOnChainFootprint:input_type -> taprpc.ListOnChainFootprintRequest
+	27,  // 114: taprpc.TaprootAssets.ListGroups:input_type -> taprpc.ListGroupsRequest
+	28,  // 115: taprpc.TaprootAssets.VerifySupplyConsistency:input_type -> taprpc.VerifySupplyConsistencyRequest
+	33,  // 116: taprpc.TaprootAssets.ListBalances:input_type -> taprpc.ListBalancesRequest
+	37,  // 117: taprpc.TaprootAssets.RebuildBalances:input_type -> taprpc.RebuildBalancesRequest
+	41,  // 118: taprpc.TaprootAssets.ListTransfers:input_type -> taprpc.ListTransfersRequest
+	43,  // 119: taprpc.TaprootAssets.ListTransfersByScriptKey:input_type -> taprpc.ListTransfersByScriptKeyRequest
+	46,  // 120: taprpc.TaprootAssets.GetTransferGraph:input_type -> taprpc.GetTransferGraphRequest
+	54,  // 121: taprpc.TaprootAssets.StopDaemon:input_type -> taprpc.StopRequest
+	56,  // 122: taprpc.TaprootAssets.DebugLevel:input_type -> taprpc.DebugLevelRequest
+	58,  // 123: taprpc.TaprootAssets.BackupDatabase:input_type -> taprpc.BackupDatabaseRequest
+	61,  // 124: taprpc.TaprootAssets.QueryAddrs:input_type -> taprpc.QueryAddrRequest
+	63,  // 125: taprpc.TaprootAssets.NewAddr:input_type -> taprpc.NewAddrRequest
+	64,  // 126: taprpc.TaprootAssets.NewAddrBatch:input_type -> taprpc.NewAddrBatchRequest
+	70,  // 127: taprpc.TaprootAssets.DecodeAddr:input_type -> taprpc.DecodeAddrRequest
+	100, // 128: taprpc.TaprootAssets.AddrReceives:input_type -> taprpc.AddrReceivesRequest
+	71,  // 129: taprpc.TaprootAssets.VerifyProof:input_type -> taprpc.ProofFile
+	74,  // 130: taprpc.TaprootAssets.DecodeProof:input_type -> taprpc.DecodeProofRequest
+	76,  // 131: taprpc.TaprootAssets.CheckProofCompatibility:input_type -> taprpc.CheckProofCompatibilityRequest
+	79,  // 132: taprpc.TaprootAssets.ExportProof:input_type -> taprpc.ExportProofRequest
+	80,  // 133: taprpc.TaprootAssets.ExportProofsBatch:input_type -> taprpc.ExportProofsBatchRequest
+	82,  // 134: taprpc.TaprootAssets.ExportProofStream:input_type -> taprpc.ExportProofStreamRequest
+	98,  // 135: taprpc.TaprootAssets.MergeProofFiles:input_type -> taprpc.MergeProofFilesRequest
+	84,  // 136: taprpc.TaprootAssets.GetSplitCommitment:input_type -> taprpc.GetSplitCommitmentRequest
+	86,  // 137: taprpc.TaprootAssets.GetAnchorMerkleProof:input_type -> taprpc.GetAnchorMerkleProofRequest
+	88,  // 138: taprpc.TaprootAssets.ListDeliveryReceipts:input_type -> taprpc.ListDeliveryReceiptsRequest
+	91,  // 139: taprpc.TaprootAssets.PauseProofDelivery:input_type -> taprpc.PauseProofDeliveryRequest
+	93,  // 140: taprpc.TaprootAssets.ResumeProofDelivery:input_type -> taprpc.ResumeProofDeliveryRequest
+	95,  // 141: taprpc.TaprootAssets.ListDeadLetterProofs:input_type -> taprpc.ListDeadLetterProofsRequest
+	104, // 142: taprpc.TaprootAssets.SendAsset:input_type -> taprpc.SendAssetRequest
+	110, // 143: taprpc.TaprootAssets.PrepareTransfer:input_type -> taprpc.PrepareTransferRequest
+	112, // 144: taprpc.TaprootAssets.BroadcastTransfer:input_type -> taprpc.BroadcastTransferRequest
+	114, // 145: taprpc.TaprootAssets.ExportPendingTransfer:input_type -> taprpc.ExportPendingTransferRequest
+	116, // 146: taprpc.TaprootAssets.ImportPendingTransfer:input_type -> taprpc.ImportPendingTransferRequest
+	118, // 147: taprpc.TaprootAssets.BumpTransferFee:input_type -> taprpc.BumpTransferFeeRequest
+	120, // 148: taprpc.TaprootAssets.CancelTransfer:input_type -> taprpc.CancelTransferRequest
+	145, // 149: taprpc.TaprootAssets.BurnAsset:input_type -> taprpc.BurnAssetRequest
+	122, // 150: taprpc.TaprootAssets.GetInfo:input_type -> taprpc.GetInfoRequest
+	124, // 151: taprpc.TaprootAssets.GetConfig:input_type -> taprpc.GetConfigRequest
+	129, // 152: taprpc.TaprootAssets.SubscribeSendAssetEventNtfns:input_type -> taprpc.SubscribeSendAssetEventNtfnsRequest
+	126, // 153: taprpc.TaprootAssets.GetSendStateMachine:input_type -> taprpc.GetSendStateMachineRequest
+	102, // 154: taprpc.TaprootAssets.SubscribeReceiveAssetEventNtfns:input_type -> taprpc.SubscribeReceiveAssetEventNtfnsRequest
+	141, // 155: taprpc.TaprootAssets.FetchAssetMeta:input_type -> taprpc.FetchAssetMetaRequest
+	142, // 156: taprpc.TaprootAssets.SearchAssetsByMeta:input_type -> taprpc.SearchAssetsByMetaRequest
+	147, // 157: taprpc.TaprootAssets.GetTransferMetrics:input_type -> taprpc.GetTransferMetricsRequest
+	150, // 158: taprpc.TaprootAssets.AnchorSweepStatus:input_type -> taprpc.AnchorSweepStatusRequest
+	17,  // 159: taprpc.TaprootAssets.ListAssets:output_type -> taprpc.ListAssetResponse
+	19,  // 160: taprpc.TaprootAssets.SubscribeAssets:output_type -> taprpc.AssetUpdate
+	22,  // 161: taprpc.TaprootAssets.ListUtxos:output_type -> taprpc.ListUtxosResponse
+	26,  // 162: taprpc.TaprootAssets.ListOnChainFootprint:output_type -> taprpc.ListOnChainFootprintResponse
+	32,  // 163: taprpc.TaprootAssets.ListGroups:output_type -> taprpc.ListGroupsResponse
+	29,  // 164: taprpc.TaprootAssets.VerifySupplyConsistency:output_type -> taprpc.VerifySupplyConsistencyResponse
+	36,  // 165: taprpc.TaprootAssets.ListBalances:output_type -> taprpc.ListBalancesResponse
+	40,  // 166: taprpc.TaprootAssets.RebuildBalances:output_type -> taprpc.RebuildBalancesResponse
+	42,  // 167: taprpc.TaprootAssets.ListTransfers:output_type -> taprpc.ListTransfersResponse
+	45,  // 168: taprpc.TaprootAssets.ListTransfersByScriptKey:output_type -> taprpc.ListTransfersByScriptKeyResponse
+	48,  // 169: taprpc.TaprootAssets.GetTransferGraph:output_type -> taprpc.GetTransferGraphResponse
+	55,  // 170: taprpc.TaprootAssets.StopDaemon:output_type -> taprpc.StopResponse
+	57,  // 171: taprpc.TaprootAssets.DebugLevel:output_type -> taprpc.DebugLevelResponse
+	59,  // 172: taprpc.TaprootAssets.BackupDatabase:output_type -> taprpc.BackupDatabaseResponse
+	62,  // 173: taprpc.TaprootAssets.QueryAddrs:output_type -> taprpc.QueryAddrResponse
+	60,  // 174: taprpc.TaprootAssets.NewAddr:output_type -> taprpc.Addr
+	65,  // 175: taprpc.TaprootAssets.NewAddrBatch:output_type -> taprpc.NewAddrBatchResponse
+	60,  // 176: taprpc.TaprootAssets.DecodeAddr:output_type -> taprpc.Addr
+	101, // 177: taprpc.TaprootAssets.AddrReceives:output_type -> taprpc.AddrReceivesResponse
+	73,  // 178: taprpc.TaprootAssets.VerifyProof:output_type -> taprpc.VerifyProofResponse
+	75,  // 179: taprpc.TaprootAssets.DecodeProof:output_type -> taprpc.DecodeProofResponse
+	78,  // 180: taprpc.TaprootAssets.CheckProofCompatibility:output_type -> taprpc.CheckProofCompatibilityResponse
+	71,  // 181: taprpc.TaprootAssets.ExportProof:output_type -> taprpc.ProofFile
+	81,  // 182: taprpc.TaprootAssets.ExportProofsBatch:output_type -> taprpc.ExportedProofFile
+	83,  // 183: taprpc.TaprootAssets.ExportProofStream:output_type -> taprpc.ProofFileChunk
+	71,  // 184: taprpc.TaprootAssets.MergeProofFiles:output_type -> taprpc.ProofFile
+	85,  // 185: taprpc.TaprootAssets.GetSplitCommitment:output_type -> taprpc.GetSplitCommitmentResponse
+	87,  // 186: taprpc.TaprootAssets.GetAnchorMerkleProof:output_type -> taprpc.GetAnchorMerkleProofResponse
+	90,  // 187: taprpc.TaprootAssets.ListDeliveryReceipts:output_type -> taprpc.ListDeliveryReceiptsResponse
+	92,  // 188: taprpc.TaprootAssets.PauseProofDelivery:output_type -> taprpc.PauseProofDeliveryResponse
+	94,  // 189: taprpc.TaprootAssets.ResumeProofDelivery:output_type -> taprpc.ResumeProofDeliveryResponse
+	97,  // 190: taprpc.TaprootAssets.ListDeadLetterProofs:output_type -> taprpc.ListDeadLetterProofsResponse
+	107, // 191: taprpc.TaprootAssets.SendAsset:output_type -> taprpc.SendAssetResponse
+	111, // 192: taprpc.TaprootAssets.PrepareTransfer:output_type -> taprpc.PrepareTransferResponse
+	113, // 193: taprpc.TaprootAssets.BroadcastTransfer:output_type -> taprpc.BroadcastTransferResponse
+	115, // 194: taprpc.TaprootAssets.ExportPendingTransfer:output_type -> taprpc.ExportPendingTransferResponse
+	117, // 195: taprpc.TaprootAssets.ImportPendingTransfer:output_type -> taprpc.ImportPendingTransferResponse
+	119, // 196: taprpc.TaprootAssets.BumpTransferFee:output_type -> taprpc.BumpTransferFeeResponse
+	121, // 197: taprpc.TaprootAssets.CancelTransfer:output_type -> taprpc.CancelTransferResponse
+	146, // 198: taprpc.TaprootAssets.BurnAsset:output_type -> taprpc.BurnAssetResponse
+	123, // 199: taprpc.TaprootAssets.GetInfo:output_type -> taprpc.GetInfoResponse
+	125, // 200: taprpc.TaprootAssets.GetConfig:output_type -> taprpc.GetConfigResponse
+	130, // 201: taprpc.TaprootAssets.SubscribeSendAssetEventNtfns:output_type -> taprpc.SendAssetEvent
+	128, // 202: taprpc.TaprootAssets.GetSendStateMachine:output_type -> taprpc.GetSendStateMachineResponse
+	103, // 203: taprpc.TaprootAssets.SubscribeReceiveAssetEventNtfns:output_type -> taprpc.ReceiveAssetEvent
+	7,   // 204: taprpc.TaprootAssets.FetchAssetMeta:output_type -> taprpc.AssetMeta
+	144, // 205: taprpc.TaprootAssets.SearchAssetsByMeta:output_type -> taprpc.SearchAssetsByMetaResponse
+	149, // 206: taprpc.TaprootAssets.GetTransferMetrics:output_type -> taprpc.GetTransferMetricsResponse
+	153, // 207: taprpc.TaprootAssets.AnchorSweepStatus:output_type -> taprpc.AnchorSweepStatusResponse
+	159, // [159:208] is the sub-list for method output_type
+	110, // [110:159] is the sub-list for method input_type
+	110, // [110:110] is the sub-list for extension type_name
+	110, // [110:110] is the sub-list for extension extendee
+	0,   // [0:110] is the sub-list for field type_name
+}
+
+func init() { file_taprootassets_proto_init() }
+func file_taprootassets_proto_init() {
+	if File_taprootassets_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_taprootassets_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetMeta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAssetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnchorInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenesisInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetGroup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupKeyReveal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GenesisReveal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Asset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrevWitness); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SplitCommitment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAssetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeAssetsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetUpdate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListUtxosRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ManagedUtxo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListUtxosResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOnChainFootprintRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FootprintAsset); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FootprintUtxo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListOnChainFootprintResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGroupsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifySupplyConsistencyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifySupplyConsistencyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetHumanReadable); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupedAssets); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListGroupsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBalancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetBalance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetGroupBalance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListBalancesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebuildBalancesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetBalanceDiscrepancy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BalanceChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebuildBalancesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransfersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransfersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransfersByScriptKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScriptKeyTransfer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTransfersByScriptKeyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransferGraphRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferEdge); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransferGraphResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetTransfer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferInput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferOutputAnchor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferOutput); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProofDelivery); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebugLevelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebugLevelResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupDatabaseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BackupDatabaseResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Addr); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryAddrRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryAddrResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewAddrRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewAddrBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewAddrBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScriptKeyTapscript); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ScriptKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyLocator); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyDescriptor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeAddrRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProofFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodedProof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeProofResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckProofCompatibilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProofConformanceCheck); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CheckProofCompatibilityResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportProofRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportProofsBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportedProofFile); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5525,8 +14337,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListAssetRequest); i {
+		file_taprootassets_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportProofStreamRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5537,8 +14349,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AnchorInfo); i {
+		file_taprootassets_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProofFileChunk); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5549,8 +14361,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GenesisInfo); i {
+		file_taprootassets_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSplitCommitmentRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5561,8 +14373,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetGroup); i {
+		file_taprootassets_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSplitCommitmentResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5573,8 +14385,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupKeyReveal); i {
+		file_taprootassets_proto_msgTypes[79].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAnchorMerkleProofRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5585,8 +14397,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GenesisReveal); i {
+		file_taprootassets_proto_msgTypes[80].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetAnchorMerkleProofResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5597,8 +14409,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Asset); i {
+		file_taprootassets_proto_msgTypes[81].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeliveryReceiptsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5609,8 +14421,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrevWitness); i {
+		file_taprootassets_proto_msgTypes[82].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeliveryReceipt); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5621,8 +14433,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SplitCommitment); i {
+		file_taprootassets_proto_msgTypes[83].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeliveryReceiptsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5633,8 +14445,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListAssetResponse); i {
+		file_taprootassets_proto_msgTypes[84].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseProofDeliveryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5645,8 +14457,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListUtxosRequest); i {
+		file_taprootassets_proto_msgTypes[85].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseProofDeliveryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5657,8 +14469,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ManagedUtxo); i {
+		file_taprootassets_proto_msgTypes[86].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeProofDeliveryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5669,8 +14481,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListUtxosResponse); i {
+		file_taprootassets_proto_msgTypes[87].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeProofDeliveryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5681,8 +14493,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListGroupsRequest); i {
+		file_taprootassets_proto_msgTypes[88].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeadLetterProofsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5693,8 +14505,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetHumanReadable); i {
+		file_taprootassets_proto_msgTypes[89].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeadLetterProof); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5705,8 +14517,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupedAssets); i {
+		file_taprootassets_proto_msgTypes[90].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeadLetterProofsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5717,8 +14529,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListGroupsResponse); i {
+		file_taprootassets_proto_msgTypes[91].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MergeProofFilesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5729,8 +14541,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListBalancesRequest); i {
+		file_taprootassets_proto_msgTypes[92].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddrEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5741,8 +14553,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetBalance); i {
+		file_taprootassets_proto_msgTypes[93].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddrReceivesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5753,8 +14565,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetGroupBalance); i {
+		file_taprootassets_proto_msgTypes[94].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddrReceivesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5765,8 +14577,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListBalancesResponse); i {
+		file_taprootassets_proto_msgTypes[95].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeReceiveAssetEventNtfnsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5777,8 +14589,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListTransfersRequest); i {
+		file_taprootassets_proto_msgTypes[96].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceiveAssetEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5789,8 +14601,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ListTransfersResponse); i {
+		file_taprootassets_proto_msgTypes[97].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAssetRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5801,8 +14613,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AssetTransfer); i {
+		file_taprootassets_proto_msgTypes[98].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrevInputAsset); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5813,8 +14625,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TransferInput); i {
+		file_taprootassets_proto_msgTypes[99].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CoinRelaxation); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5825,8 +14637,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TransferOutputAnchor); i {
+		file_taprootassets_proto_msgTypes[100].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAssetResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5837,8 +14649,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TransferOutput); i {
+		file_taprootassets_proto_msgTypes[101].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAssetEstimate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5849,8 +14661,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopRequest); i {
+		file_taprootassets_proto_msgTypes[102].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAssetEstimateOutput); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5861,8 +14673,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopResponse); i {
+		file_taprootassets_proto_msgTypes[103].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5873,8 +14685,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DebugLevelRequest); i {
+		file_taprootassets_proto_msgTypes[104].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PrepareTransferResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5885,8 +14697,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DebugLevelResponse); i {
+		file_taprootassets_proto_msgTypes[105].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5897,8 +14709,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Addr); i {
+		file_taprootassets_proto_msgTypes[106].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BroadcastTransferResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5909,8 +14721,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryAddrRequest); i {
+		file_taprootassets_proto_msgTypes[107].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportPendingTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5921,8 +14733,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryAddrResponse); i {
+		file_taprootassets_proto_msgTypes[108].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExportPendingTransferResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5933,8 +14745,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewAddrRequest); i {
+		file_taprootassets_proto_msgTypes[109].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportPendingTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5945,8 +14757,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ScriptKey); i {
+		file_taprootassets_proto_msgTypes[110].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportPendingTransferResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5957,8 +14769,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeyLocator); i {
+		file_taprootassets_proto_msgTypes[111].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BumpTransferFeeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5969,8 +14781,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeyDescriptor); i {
+		file_taprootassets_proto_msgTypes[112].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BumpTransferFeeResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5981,8 +14793,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DecodeAddrRequest); i {
+		file_taprootassets_proto_msgTypes[113].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTransferRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -5993,8 +14805,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProofFile); i {
+		file_taprootassets_proto_msgTypes[114].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelTransferResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6005,8 +14817,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DecodedProof); i {
+		file_taprootassets_proto_msgTypes[115].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6017,8 +14829,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*VerifyProofResponse); i {
+		file_taprootassets_proto_msgTypes[116].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetInfoResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6029,8 +14841,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DecodeProofRequest); i {
+		file_taprootassets_proto_msgTypes[117].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6041,8 +14853,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DecodeProofResponse); i {
+		file_taprootassets_proto_msgTypes[118].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6053,8 +14865,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExportProofRequest); i {
+		file_taprootassets_proto_msgTypes[119].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSendStateMachineRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6065,8 +14877,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddrEvent); i {
+		file_taprootassets_proto_msgTypes[120].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendStateDefinition); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6077,8 +14889,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddrReceivesRequest); i {
+		file_taprootassets_proto_msgTypes[121].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetSendStateMachineResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6089,8 +14901,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddrReceivesResponse); i {
+		file_taprootassets_proto_msgTypes[122].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeSendAssetEventNtfnsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6101,8 +14913,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendAssetRequest); i {
+		file_taprootassets_proto_msgTypes[123].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendAssetEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6113,8 +14925,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PrevInputAsset); i {
+		file_taprootassets_proto_msgTypes[124].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecuteSendStateEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6125,8 +14937,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendAssetResponse); i {
+		file_taprootassets_proto_msgTypes[125].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceiverProofBackoffWaitEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6137,8 +14949,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoRequest); i {
+		file_taprootassets_proto_msgTypes[126].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceiverProofDeliveryPausedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6149,8 +14961,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetInfoResponse); i {
+		file_taprootassets_proto_msgTypes[127].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReceiverProofDeliveredEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6161,8 +14973,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribeSendAssetEventNtfnsRequest); i {
+		file_taprootassets_proto_msgTypes[128].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferCompleteEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6173,8 +14985,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendAssetEvent); i {
+		file_taprootassets_proto_msgTypes[129].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferReOrgEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6185,8 +14997,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecuteSendStateEvent); i {
+		file_taprootassets_proto_msgTypes[130].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferAbandonedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6197,8 +15009,8 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReceiverProofBackoffWaitEvent); i {
+		file_taprootassets_proto_msgTypes[131].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferRetryEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -6209,7 +15021,31 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+		file_taprootassets_proto_msgTypes[132].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferCancelledEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[133].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TransferFeeBumpedEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[134].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FetchAssetMetaRequest); i {
 			case 0:
 				return &v.state
@@ -6221,7 +15057,43 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+		file_taprootassets_proto_msgTypes[135].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchAssetsByMetaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[136].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetMetaMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[137].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchAssetsByMetaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[138].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BurnAssetRequest); i {
 			case 0:
 				return &v.state
@@ -6233,7 +15105,7 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
-		file_taprootassets_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+		file_taprootassets_proto_msgTypes[139].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*BurnAssetResponse); i {
 			case 0:
 				return &v.state
@@ -6245,22 +15117,119 @@ func file_taprootassets_proto_init() {
 				return nil
 			}
 		}
+		file_taprootassets_proto_msgTypes[140].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransferMetricsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[141].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LatencyPercentiles); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[142].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTransferMetricsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[143].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnchorSweepStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[144].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SweepableAnchor); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[145].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnchorSweep); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_taprootassets_proto_msgTypes[146].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnchorSweepStatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_taprootassets_proto_msgTypes[18].OneofWrappers = []interface{}{
+	file_taprootassets_proto_msgTypes[26].OneofWrappers = []interface{}{
 		(*ListBalancesRequest_AssetId)(nil),
 		(*ListBalancesRequest_GroupKey)(nil),
 	}
-	file_taprootassets_proto_msgTypes[55].OneofWrappers = []interface{}{
+	file_taprootassets_proto_msgTypes[37].OneofWrappers = []interface{}{
+		(*ScriptKeyTransfer_Outbound)(nil),
+		(*ScriptKeyTransfer_Inbound)(nil),
+		(*ScriptKeyTransfer_Received)(nil),
+	}
+	file_taprootassets_proto_msgTypes[123].OneofWrappers = []interface{}{
 		(*SendAssetEvent_ExecuteSendStateEvent)(nil),
 		(*SendAssetEvent_ReceiverProofBackoffWaitEvent)(nil),
-	}
-	file_taprootassets_proto_msgTypes[58].OneofWrappers = []interface{}{
+		(*SendAssetEvent_TransferAbandonedEvent)(nil),
+		(*SendAssetEvent_TransferRetryEvent)(nil),
+		(*SendAssetEvent_TransferFeeBumpedEvent)(nil),
+		(*SendAssetEvent_ReceiverProofDeliveryPausedEvent)(nil),
+		(*SendAssetEvent_ReceiverProofDeliveredEvent)(nil),
+		(*SendAssetEvent_TransferCompleteEvent)(nil),
+		(*SendAssetEvent_TransferReorgEvent)(nil),
+		(*SendAssetEvent_TransferCancelledEvent)(nil),
+	}
+	file_taprootassets_proto_msgTypes[134].OneofWrappers = []interface{}{
 		(*FetchAssetMetaRequest_AssetId)(nil),
 		(*FetchAssetMetaRequest_MetaHash)(nil),
 		(*FetchAssetMetaRequest_AssetIdStr)(nil),
 		(*FetchAssetMetaRequest_MetaHashStr)(nil),
 	}
-	file_taprootassets_proto_msgTypes[59].OneofWrappers = []interface{}{
+	file_taprootassets_proto_msgTypes[138].OneofWrappers = []interface{}{
 		(*BurnAssetRequest_AssetId)(nil),
 		(*BurnAssetRequest_AssetIdStr)(nil),
 	}
@@ -6269,8 +15238,8 @@ func file_taprootassets_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_taprootassets_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   65,
+			NumEnums:      7,
+			NumMessages:   153,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
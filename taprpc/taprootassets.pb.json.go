@@ -46,6 +46,48 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
+	registry["taprpc.TaprootAssets.SubscribeAssets"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &SubscribeAssetsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		stream, err := client.SubscribeAssets(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		go func() {
+			for {
+				select {
+				case <-stream.Context().Done():
+					callback("", stream.Context().Err())
+					return
+				default:
+				}
+
+				resp, err := stream.Recv()
+				if err != nil {
+					callback("", err)
+					return
+				}
+
+				respBytes, err := marshaler.Marshal(resp)
+				if err != nil {
+					callback("", err)
+					return
+				}
+				callback(string(respBytes), nil)
+			}
+		}()
+	}
+
 	registry["taprpc.TaprootAssets.ListUtxos"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
@@ -71,6 +113,31 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
+	registry["taprpc.TaprootAssets.ListOnChainFootprint"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ListOnChainFootprintRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ListOnChainFootprint(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
 	registry["taprpc.TaprootAssets.ListGroups"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
@@ -96,6 +163,31 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
+	registry["taprpc.TaprootAssets.VerifySupplyConsistency"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &VerifySupplyConsistencyRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.VerifySupplyConsistency(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
 	registry["taprpc.TaprootAssets.ListBalances"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
@@ -121,6 +213,31 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
+	registry["taprpc.TaprootAssets.RebuildBalances"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &RebuildBalancesRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.RebuildBalances(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
 	registry["taprpc.TaprootAssets.ListTransfers"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
@@ -146,6 +263,56 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
+	registry["taprpc.TaprootAssets.ListTransfersByScriptKey"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ListTransfersByScriptKeyRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ListTransfersByScriptKey(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetTransferGraph"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetTransferGraphRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetTransferGraph(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
 	registry["taprpc.TaprootAssets.StopDaemon"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
@@ -196,10 +363,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.QueryAddrs"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.BackupDatabase"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &QueryAddrRequest{}
+		req := &BackupDatabaseRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -207,7 +374,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.QueryAddrs(ctx, req)
+		resp, err := client.BackupDatabase(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -221,10 +388,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.NewAddr"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.QueryAddrs"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &NewAddrRequest{}
+		req := &QueryAddrRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -232,7 +399,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.NewAddr(ctx, req)
+		resp, err := client.QueryAddrs(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -246,10 +413,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.DecodeAddr"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.NewAddr"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &DecodeAddrRequest{}
+		req := &NewAddrRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -257,7 +424,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.DecodeAddr(ctx, req)
+		resp, err := client.NewAddr(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -271,10 +438,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.AddrReceives"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.NewAddrBatch"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &AddrReceivesRequest{}
+		req := &NewAddrBatchRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -282,7 +449,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.AddrReceives(ctx, req)
+		resp, err := client.NewAddrBatch(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -296,10 +463,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.VerifyProof"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.DecodeAddr"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &ProofFile{}
+		req := &DecodeAddrRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -307,7 +474,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.VerifyProof(ctx, req)
+		resp, err := client.DecodeAddr(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -321,10 +488,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.DecodeProof"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.AddrReceives"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &DecodeProofRequest{}
+		req := &AddrReceivesRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -332,7 +499,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.DecodeProof(ctx, req)
+		resp, err := client.AddrReceives(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -346,10 +513,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.ExportProof"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.VerifyProof"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &ExportProofRequest{}
+		req := &ProofFile{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -357,7 +524,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.ExportProof(ctx, req)
+		resp, err := client.VerifyProof(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -371,10 +538,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.SendAsset"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.DecodeProof"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &SendAssetRequest{}
+		req := &DecodeProofRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -382,7 +549,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.SendAsset(ctx, req)
+		resp, err := client.DecodeProof(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -396,10 +563,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.BurnAsset"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.CheckProofCompatibility"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &BurnAssetRequest{}
+		req := &CheckProofCompatibilityRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -407,7 +574,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.BurnAsset(ctx, req)
+		resp, err := client.CheckProofCompatibility(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -421,10 +588,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.GetInfo"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.ExportProof"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &GetInfoRequest{}
+		req := &ExportProofRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -432,7 +599,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.GetInfo(ctx, req)
+		resp, err := client.ExportProof(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -446,10 +613,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		callback(string(respBytes), nil)
 	}
 
-	registry["taprpc.TaprootAssets.SubscribeSendAssetEventNtfns"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.ExportProofsBatch"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &SubscribeSendAssetEventNtfnsRequest{}
+		req := &ExportProofsBatchRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -457,7 +624,7 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		stream, err := client.SubscribeSendAssetEventNtfns(ctx, req)
+		stream, err := client.ExportProofsBatch(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -488,10 +655,10 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}()
 	}
 
-	registry["taprpc.TaprootAssets.FetchAssetMeta"] = func(ctx context.Context,
+	registry["taprpc.TaprootAssets.ExportProofStream"] = func(ctx context.Context,
 		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
 
-		req := &FetchAssetMetaRequest{}
+		req := &ExportProofStreamRequest{}
 		err := marshaler.Unmarshal([]byte(reqJSON), req)
 		if err != nil {
 			callback("", err)
@@ -499,7 +666,658 @@ func RegisterTaprootAssetsJSONCallbacks(registry map[string]func(ctx context.Con
 		}
 
 		client := NewTaprootAssetsClient(conn)
-		resp, err := client.FetchAssetMeta(ctx, req)
+		stream, err := client.ExportProofStream(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		go func() {
+			for {
+				select {
+				case <-stream.Context().Done():
+					callback("", stream.Context().Err())
+					return
+				default:
+				}
+
+				resp, err := stream.Recv()
+				if err != nil {
+					callback("", err)
+					return
+				}
+
+				respBytes, err := marshaler.Marshal(resp)
+				if err != nil {
+					callback("", err)
+					return
+				}
+				callback(string(respBytes), nil)
+			}
+		}()
+	}
+
+	registry["taprpc.TaprootAssets.MergeProofFiles"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &MergeProofFilesRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.MergeProofFiles(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetSplitCommitment"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetSplitCommitmentRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetSplitCommitment(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetAnchorMerkleProof"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetAnchorMerkleProofRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetAnchorMerkleProof(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.ListDeliveryReceipts"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ListDeliveryReceiptsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ListDeliveryReceipts(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.PauseProofDelivery"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &PauseProofDeliveryRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.PauseProofDelivery(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.ResumeProofDelivery"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ResumeProofDeliveryRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ResumeProofDelivery(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.ListDeadLetterProofs"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ListDeadLetterProofsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ListDeadLetterProofs(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.SendAsset"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &SendAssetRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.SendAsset(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.PrepareTransfer"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &PrepareTransferRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.PrepareTransfer(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.BroadcastTransfer"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &BroadcastTransferRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.BroadcastTransfer(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.ExportPendingTransfer"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ExportPendingTransferRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ExportPendingTransfer(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.ImportPendingTransfer"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &ImportPendingTransferRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.ImportPendingTransfer(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.BumpTransferFee"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &BumpTransferFeeRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.BumpTransferFee(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.CancelTransfer"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &CancelTransferRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.CancelTransfer(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.BurnAsset"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &BurnAssetRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.BurnAsset(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetInfo"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetInfoRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetInfo(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetConfig"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetConfigRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetConfig(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.SubscribeSendAssetEventNtfns"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &SubscribeSendAssetEventNtfnsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		stream, err := client.SubscribeSendAssetEventNtfns(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		go func() {
+			for {
+				select {
+				case <-stream.Context().Done():
+					callback("", stream.Context().Err())
+					return
+				default:
+				}
+
+				resp, err := stream.Recv()
+				if err != nil {
+					callback("", err)
+					return
+				}
+
+				respBytes, err := marshaler.Marshal(resp)
+				if err != nil {
+					callback("", err)
+					return
+				}
+				callback(string(respBytes), nil)
+			}
+		}()
+	}
+
+	registry["taprpc.TaprootAssets.GetSendStateMachine"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetSendStateMachineRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetSendStateMachine(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.SubscribeReceiveAssetEventNtfns"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &SubscribeReceiveAssetEventNtfnsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		stream, err := client.SubscribeReceiveAssetEventNtfns(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		go func() {
+			for {
+				select {
+				case <-stream.Context().Done():
+					callback("", stream.Context().Err())
+					return
+				default:
+				}
+
+				resp, err := stream.Recv()
+				if err != nil {
+					callback("", err)
+					return
+				}
+
+				respBytes, err := marshaler.Marshal(resp)
+				if err != nil {
+					callback("", err)
+					return
+				}
+				callback(string(respBytes), nil)
+			}
+		}()
+	}
+
+	registry["taprpc.TaprootAssets.FetchAssetMeta"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &FetchAssetMetaRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.FetchAssetMeta(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.SearchAssetsByMeta"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &SearchAssetsByMetaRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.SearchAssetsByMeta(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.GetTransferMetrics"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &GetTransferMetricsRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.GetTransferMetrics(ctx, req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		respBytes, err := marshaler.Marshal(resp)
+		if err != nil {
+			callback("", err)
+			return
+		}
+		callback(string(respBytes), nil)
+	}
+
+	registry["taprpc.TaprootAssets.AnchorSweepStatus"] = func(ctx context.Context,
+		conn *grpc.ClientConn, reqJSON string, callback func(string, error)) {
+
+		req := &AnchorSweepStatusRequest{}
+		err := marshaler.Unmarshal([]byte(reqJSON), req)
+		if err != nil {
+			callback("", err)
+			return
+		}
+
+		client := NewTaprootAssetsClient(conn)
+		resp, err := client.AnchorSweepStatus(ctx, req)
 		if err != nil {
 			callback("", err)
 			return
@@ -21,20 +21,60 @@ type TaprootAssetsClient interface {
 	// tapcli: `assets list`
 	// ListAssets lists the set of assets owned by the target daemon.
 	ListAssets(ctx context.Context, in *ListAssetRequest, opts ...grpc.CallOption) (*ListAssetResponse, error)
+	// tapcli: `assets subscribe`
+	// SubscribeAssets sends the current set of assets owned by the target daemon
+	// and then streams incremental updates to that set as assets are minted,
+	// received, sent, change their confirmation status or their lease expires.
+	SubscribeAssets(ctx context.Context, in *SubscribeAssetsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeAssetsClient, error)
 	// tapcli: `assets utxos`
 	// ListUtxos lists the UTXOs managed by the target daemon, and the assets they
 	// hold.
 	ListUtxos(ctx context.Context, in *ListUtxosRequest, opts ...grpc.CallOption) (*ListUtxosResponse, error)
+	// tapcli: `assets footprint`
+	// ListOnChainFootprint lists all on-chain UTXOs the target daemon considers
+	// its own, including anchor outputs that only hold spent assets, such as
+	// tombstones and burns, together with their key derivation and the assets
+	// they anchor. This allows the outputs managed by the daemon to be told apart
+	// from the other UTXOs of the backing wallet.
+	ListOnChainFootprint(ctx context.Context, in *ListOnChainFootprintRequest, opts ...grpc.CallOption) (*ListOnChainFootprintResponse, error)
 	// tapcli: `assets groups`
 	// ListGroups lists the asset groups known to the target daemon, and the assets
 	// held in each group.
 	ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error)
+	// tapcli: `assets verifysupply`
+	// VerifySupplyConsistency reconciles the supply of an asset group. The
+	// issuance proofs of the local universe are checked against the universe
+	// root, the burns known to the transfer universe and the local node are
+	// subtracted from the issued amount, and the balance held by the node is
+	// checked against the remaining outstanding units. Any discrepancy found is
+	// reported.
+	VerifySupplyConsistency(ctx context.Context, in *VerifySupplyConsistencyRequest, opts ...grpc.CallOption) (*VerifySupplyConsistencyResponse, error)
 	// tapcli: `assets balance`
 	// ListBalances lists asset balances
 	ListBalances(ctx context.Context, in *ListBalancesRequest, opts ...grpc.CallOption) (*ListBalancesResponse, error)
+	// tapcli: `assets rebuildbalances`
+	// RebuildBalances re-derives the amounts of all owned, unspent assets from
+	// their stored proofs, checks the proofs against the current chain and
+	// replaces the recorded amounts that differ in a single database transaction.
+	// All discrepancies found between the recorded assets and their proofs are
+	// reported. Assets whose proof can't be used to repair them are reported but
+	// left unchanged.
+	RebuildBalances(ctx context.Context, in *RebuildBalancesRequest, opts ...grpc.CallOption) (*RebuildBalancesResponse, error)
 	// tapcli: `assets transfers`
 	// ListTransfers lists outbound asset transfers tracked by the target daemon.
 	ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error)
+	// tapcli: `assets transfersbykey`
+	// ListTransfersByScriptKey lists all inbound and outbound asset transfers
+	// that created, spent or changed an output with the given script key,
+	// ordered by the time they were first seen.
+	ListTransfersByScriptKey(ctx context.Context, in *ListTransfersByScriptKeyRequest, opts ...grpc.CallOption) (*ListTransfersByScriptKeyResponse, error)
+	// tapcli: `assets transfergraph`
+	// GetTransferGraph returns an outbound transfer together with the flow of
+	// asset amounts from each of its inputs to the outputs they contributed to.
+	// Because the units of a fungible asset are interchangeable, the amounts of
+	// the inputs of an asset ID are attributed to its outputs in the order both
+	// appear in the transfer.
+	GetTransferGraph(ctx context.Context, in *GetTransferGraphRequest, opts ...grpc.CallOption) (*GetTransferGraphResponse, error)
 	// tapcli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -45,6 +85,15 @@ type TaprootAssetsClient interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	// tapcli: `backup`
+	// BackupDatabase creates a consistent backup of the database while the daemon
+	// keeps running, and writes it to the given path on the machine running the
+	// daemon. Transfers that are in flight while the backup is created are
+	// either fully included or not at all. For SQLite, the backup is a regular
+	// database file. For Postgres, the backup is created by pg_dump, which must
+	// be installed, in its custom archive format that can be restored with
+	// pg_restore. The proof files stored on disk aren't part of the backup.
+	BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error)
 	// tapcli: `addrs query`
 	// QueryAddrs queries the set of Taproot Asset addresses stored in the
 	// database.
@@ -52,6 +101,10 @@ type TaprootAssetsClient interface {
 	// tapcli: `addrs new`
 	// NewAddr makes a new address from the set of request params.
 	NewAddr(ctx context.Context, in *NewAddrRequest, opts ...grpc.CallOption) (*Addr, error)
+	// NewAddrBatch makes a new address for each of the given sets of request
+	// params. The addresses are created atomically, so if any of the requests is
+	// invalid, none of the addresses are created.
+	NewAddrBatch(ctx context.Context, in *NewAddrBatchRequest, opts ...grpc.CallOption) (*NewAddrBatchResponse, error)
 	// tapcli: `addrs decode`
 	// DecodeAddr decode a Taproot Asset address into a partial asset message that
 	// represents the asset it wants to receive.
@@ -61,23 +114,128 @@ type TaprootAssetsClient interface {
 	// created previously.
 	AddrReceives(ctx context.Context, in *AddrReceivesRequest, opts ...grpc.CallOption) (*AddrReceivesResponse, error)
 	// tapcli: `proofs verify`
-	// VerifyProof attempts to verify a given proof file that claims to be anchored
-	// at the specified genesis point.
+	// VerifyProof attempts to verify a given proof file or single mint/transition
+	// proof, including the anchor transaction's block header and merkle
+	// inclusion. The asset does not need to be owned by the node. A verification
+	// failure does not fail the call but is reported in the response instead.
+	// Single transfer proofs can only be verified fully as part of their file,
+	// since the witnesses are checked against the previous asset state.
 	VerifyProof(ctx context.Context, in *ProofFile, opts ...grpc.CallOption) (*VerifyProofResponse, error)
 	// tapcli: `proofs decode`
 	// DecodeProof attempts to decode a given proof file into human readable
-	// format.
+	// format. The proof is neither verified nor imported. If the proof can't be
+	// decoded, the error names the record that couldn't be decoded and its byte
+	// offset within the proof.
 	DecodeProof(ctx context.Context, in *DecodeProofRequest, opts ...grpc.CallOption) (*DecodeProofResponse, error)
+	// tapcli: `proofs checkcompat`
+	// CheckProofCompatibility runs a strict conformance check of the encoding of
+	// a given proof or proof file, for example one created by another
+	// implementation. In contrast to VerifyProof, only the encoding is checked
+	// and not the validity of the proof. The results of all individual checks are
+	// returned.
+	CheckProofCompatibility(ctx context.Context, in *CheckProofCompatibilityRequest, opts ...grpc.CallOption) (*CheckProofCompatibilityResponse, error)
 	// tapcli: `proofs export`
 	// ExportProof exports the latest raw proof file anchored at the specified
 	// script_key.
 	ExportProof(ctx context.Context, in *ExportProofRequest, opts ...grpc.CallOption) (*ProofFile, error)
+	// tapcli: `proofs exportbatch`
+	// ExportProofsBatch exports the latest raw proof files of all owned assets
+	// that match the given filter. A proof file is streamed for each matching
+	// asset, together with its asset ID and script key. If no filter is
+	// specified, the proof files of all owned assets are exported.
+	ExportProofsBatch(ctx context.Context, in *ExportProofsBatchRequest, opts ...grpc.CallOption) (TaprootAssets_ExportProofsBatchClient, error)
+	// tapcli: `proofs exportstream`
+	// ExportProofStream exports the latest proof file anchored at the specified
+	// script_key one proof at a time, instead of returning the whole file in a
+	// single response. An optional range of proof indexes can be given to only
+	// export a part of the proof chain. The streamed proofs can be appended to
+	// an empty proof file of the reported version to reassemble the file.
+	ExportProofStream(ctx context.Context, in *ExportProofStreamRequest, opts ...grpc.CallOption) (TaprootAssets_ExportProofStreamClient, error)
+	// tapcli: `proofs merge`
+	// MergeProofFiles merges two proof files into a single proof file. The first
+	// proof of the suffix file must spend the asset output of the last proof of
+	// the prefix file, otherwise the request is rejected.
+	MergeProofFiles(ctx context.Context, in *MergeProofFilesRequest, opts ...grpc.CallOption) (*ProofFile, error)
+	// tapcli: `proofs splitcommitment`
+	// GetSplitCommitment decodes the split commitment of the asset in the given
+	// transfer output from the stored proof. For a split asset, the inclusion
+	// proof of the split in the split commitment tree is returned together with
+	// the split root it resolves to. For the root asset of a split, only the split
+	// root is returned.
+	GetSplitCommitment(ctx context.Context, in *GetSplitCommitmentRequest, opts ...grpc.CallOption) (*GetSplitCommitmentResponse, error)
+	// tapcli: `proofs merkleproof`
+	// GetAnchorMerkleProof returns the merkle proof of the anchor transaction of
+	// the given transfer output in its confirming block, as embedded in the
+	// stored proof, together with the block header. This allows a verifier that
+	// follows the header chain to confirm the inclusion of the anchor
+	// transaction independently.
+	GetAnchorMerkleProof(ctx context.Context, in *GetAnchorMerkleProofRequest, opts ...grpc.CallOption) (*GetAnchorMerkleProofResponse, error)
+	// tapcli: `proofs receipts`
+	// ListDeliveryReceipts lists the delivery receipts that receivers returned
+	// for proofs sent to the given script key through a proof courier. A receipt
+	// is signed by the key that controls the script key and proves the receiver
+	// got the exact proof that was sent.
+	ListDeliveryReceipts(ctx context.Context, in *ListDeliveryReceiptsRequest, opts ...grpc.CallOption) (*ListDeliveryReceiptsResponse, error)
+	// tapcli: `proofs pausedelivery`
+	// PauseProofDelivery pauses the delivery of the proof for the given transfer
+	// output. The courier stops its delivery attempts until the delivery is
+	// resumed, without abandoning the delivery. Time spent paused doesn't count
+	// against the retry limit. Paused deliveries are resumed when the daemon
+	// restarts.
+	PauseProofDelivery(ctx context.Context, in *PauseProofDeliveryRequest, opts ...grpc.CallOption) (*PauseProofDeliveryResponse, error)
+	// tapcli: `proofs resumedelivery`
+	// ResumeProofDelivery resumes the paused delivery of the proof for the given
+	// transfer output. The courier starts over with a fresh set of delivery
+	// attempts.
+	ResumeProofDelivery(ctx context.Context, in *ResumeProofDeliveryRequest, opts ...grpc.CallOption) (*ResumeProofDeliveryResponse, error)
+	// tapcli: `proofs deadletters`
+	// ListDeadLetterProofs lists the inbound proofs that couldn't be imported
+	// after all retries. Dead letters are persisted and stay listed until the
+	// proof is imported, for example by importing it manually.
+	ListDeadLetterProofs(ctx context.Context, in *ListDeadLetterProofsRequest, opts ...grpc.CallOption) (*ListDeadLetterProofsResponse, error)
 	// tapcli: `assets send`
 	// SendAsset uses one or multiple passed Taproot Asset address(es) to attempt
 	// to complete an asset send. The method returns information w.r.t the on chain
 	// send, as well as the proof file information the receiver needs to fully
 	// receive the asset.
 	SendAsset(ctx context.Context, in *SendAssetRequest, opts ...grpc.CallOption) (*SendAssetResponse, error)
+	// tapcli: `assets prepare`
+	// PrepareTransfer funds, signs and persists the anchor transaction of a
+	// transfer to one or multiple Taproot Asset address(es) without broadcasting
+	// it. The inputs of the staged transfer stay locked until the transfer is
+	// released with BroadcastTransfer, also across restarts.
+	PrepareTransfer(ctx context.Context, in *PrepareTransferRequest, opts ...grpc.CallOption) (*PrepareTransferResponse, error)
+	// tapcli: `assets broadcast`
+	// BroadcastTransfer releases a transfer staged with PrepareTransfer, which
+	// broadcasts its anchor transaction and continues the regular send process.
+	BroadcastTransfer(ctx context.Context, in *BroadcastTransferRequest, opts ...grpc.CallOption) (*BroadcastTransferResponse, error)
+	// tapcli: `assets exportpending`
+	// ExportPendingTransfer exports the state of a pending transfer, so another
+	// daemon connected to the same lnd node can take it over with
+	// ImportPendingTransfer, for example if this daemon becomes unavailable.
+	ExportPendingTransfer(ctx context.Context, in *ExportPendingTransferRequest, opts ...grpc.CallOption) (*ExportPendingTransferResponse, error)
+	// tapcli: `assets importpending`
+	// ImportPendingTransfer takes over a pending transfer exported by another
+	// daemon connected to the same lnd node and completes its broadcast and proof
+	// delivery. The transfer is claimed by leasing the wallet inputs of its anchor
+	// transaction, so only one daemon can take it over and the exporting daemon
+	// no longer acts on it.
+	ImportPendingTransfer(ctx context.Context, in *ImportPendingTransferRequest, opts ...grpc.CallOption) (*ImportPendingTransferResponse, error)
+	// tapcli: `assets bumpfee`
+	// BumpTransferFee replaces the unconfirmed anchor transaction of a pending
+	// transfer with one that pays a higher fee rate (BIP-125 RBF). The
+	// replacement spends the same inputs and creates the same outputs, including
+	// the ones carrying passive assets, with the additional fee being deducted
+	// from the wallet's change output. Proof delivery continues against whichever
+	// version of the anchor transaction confirms.
+	BumpTransferFee(ctx context.Context, in *BumpTransferFeeRequest, opts ...grpc.CallOption) (*BumpTransferFeeResponse, error)
+	// tapcli: `assets cancel`
+	// CancelTransfer cancels a pending transfer whose anchor transaction has not
+	// yet confirmed, for example because it pays a fee too low to ever confirm.
+	// The transfer is moved into a terminal cancelled state and its asset inputs
+	// are released so they can be spent again. Transfers that were already
+	// confirmed on chain can't be cancelled.
+	CancelTransfer(ctx context.Context, in *CancelTransferRequest, opts ...grpc.CallOption) (*CancelTransferResponse, error)
 	// tapcli: `assets burn`
 	// BurnAsset burns the given number of units of a given asset by sending them
 	// to a provably un-spendable script key. Burning means irrevocably destroying
@@ -88,12 +246,41 @@ type TaprootAssetsClient interface {
 	// tapcli: `getinfo`
 	// GetInfo returns the information for the node.
 	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// tapcli: `getconfig`
+	// GetConfig returns the effective configuration the daemon was started with,
+	// after validation. Sensitive values such as passwords are redacted.
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
 	// SubscribeSendAssetEventNtfns registers a subscription to the event
 	// notification stream which relates to the asset sending process.
 	SubscribeSendAssetEventNtfns(ctx context.Context, in *SubscribeSendAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeSendAssetEventNtfnsClient, error)
+	// tapcli: `assets sendstates`
+	// GetSendStateMachine returns the definition of the state machine that
+	// outbound transfers move through, as reported by the execute send state
+	// events of SubscribeSendAssetEventNtfns.
+	GetSendStateMachine(ctx context.Context, in *GetSendStateMachineRequest, opts ...grpc.CallOption) (*GetSendStateMachineResponse, error)
+	// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+	// notification stream which relates to the process of receiving assets with
+	// an address.
+	SubscribeReceiveAssetEventNtfns(ctx context.Context, in *SubscribeReceiveAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeReceiveAssetEventNtfnsClient, error)
 	// FetchAssetMeta allows a caller to fetch the reveal meta data for an asset
 	// either by the asset ID for that asset, or a meta hash.
 	FetchAssetMeta(ctx context.Context, in *FetchAssetMetaRequest, opts ...grpc.CallOption) (*AssetMeta, error)
+	// tapcli: `assets searchmeta`
+	// SearchAssetsByMeta searches the assets known to the daemon by the content
+	// of their meta data. Meta data that is a JSON object is matched by its
+	// fields, any other meta data by substring.
+	SearchAssetsByMeta(ctx context.Context, in *SearchAssetsByMetaRequest, opts ...grpc.CallOption) (*SearchAssetsByMetaResponse, error)
+	// tapcli: `assets transfermetrics`
+	// GetTransferMetrics returns latency percentiles and throughput figures for
+	// the outbound asset transfers that were initiated within the given time
+	// window.
+	GetTransferMetrics(ctx context.Context, in *GetTransferMetricsRequest, opts ...grpc.CallOption) (*GetTransferMetricsResponse, error)
+	// tapcli: `assets sweepstatus`
+	// AnchorSweepStatus returns the status of the background sweeper of anchor
+	// outputs that only anchor spent assets, such as the tombstones and burns
+	// left behind by completed transfers, together with the anchor outputs that
+	// are waiting to be swept and the ones that were already swept.
+	AnchorSweepStatus(ctx context.Context, in *AnchorSweepStatusRequest, opts ...grpc.CallOption) (*AnchorSweepStatusResponse, error)
 }
 
 type taprootAssetsClient struct {
@@ -113,6 +300,38 @@ func (c *taprootAssetsClient) ListAssets(ctx context.Context, in *ListAssetReque
 	return out, nil
 }
 
+func (c *taprootAssetsClient) SubscribeAssets(ctx context.Context, in *SubscribeAssetsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeAssetsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[0], "/taprpc.TaprootAssets/SubscribeAssets", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taprootAssetsSubscribeAssetsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaprootAssets_SubscribeAssetsClient interface {
+	Recv() (*AssetUpdate, error)
+	grpc.ClientStream
+}
+
+type taprootAssetsSubscribeAssetsClient struct {
+	grpc.ClientStream
+}
+
+func (x *taprootAssetsSubscribeAssetsClient) Recv() (*AssetUpdate, error) {
+	m := new(AssetUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *taprootAssetsClient) ListUtxos(ctx context.Context, in *ListUtxosRequest, opts ...grpc.CallOption) (*ListUtxosResponse, error) {
 	out := new(ListUtxosResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListUtxos", in, out, opts...)
@@ -122,6 +341,15 @@ func (c *taprootAssetsClient) ListUtxos(ctx context.Context, in *ListUtxosReques
 	return out, nil
 }
 
+func (c *taprootAssetsClient) ListOnChainFootprint(ctx context.Context, in *ListOnChainFootprintRequest, opts ...grpc.CallOption) (*ListOnChainFootprintResponse, error) {
+	out := new(ListOnChainFootprintResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListOnChainFootprint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) ListGroups(ctx context.Context, in *ListGroupsRequest, opts ...grpc.CallOption) (*ListGroupsResponse, error) {
 	out := new(ListGroupsResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListGroups", in, out, opts...)
@@ -131,6 +359,15 @@ func (c *taprootAssetsClient) ListGroups(ctx context.Context, in *ListGroupsRequ
 	return out, nil
 }
 
+func (c *taprootAssetsClient) VerifySupplyConsistency(ctx context.Context, in *VerifySupplyConsistencyRequest, opts ...grpc.CallOption) (*VerifySupplyConsistencyResponse, error) {
+	out := new(VerifySupplyConsistencyResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/VerifySupplyConsistency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) ListBalances(ctx context.Context, in *ListBalancesRequest, opts ...grpc.CallOption) (*ListBalancesResponse, error) {
 	out := new(ListBalancesResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListBalances", in, out, opts...)
@@ -140,6 +377,15 @@ func (c *taprootAssetsClient) ListBalances(ctx context.Context, in *ListBalances
 	return out, nil
 }
 
+func (c *taprootAssetsClient) RebuildBalances(ctx context.Context, in *RebuildBalancesRequest, opts ...grpc.CallOption) (*RebuildBalancesResponse, error) {
+	out := new(RebuildBalancesResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/RebuildBalances", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) ListTransfers(ctx context.Context, in *ListTransfersRequest, opts ...grpc.CallOption) (*ListTransfersResponse, error) {
 	out := new(ListTransfersResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListTransfers", in, out, opts...)
@@ -149,6 +395,24 @@ func (c *taprootAssetsClient) ListTransfers(ctx context.Context, in *ListTransfe
 	return out, nil
 }
 
+func (c *taprootAssetsClient) ListTransfersByScriptKey(ctx context.Context, in *ListTransfersByScriptKeyRequest, opts ...grpc.CallOption) (*ListTransfersByScriptKeyResponse, error) {
+	out := new(ListTransfersByScriptKeyResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListTransfersByScriptKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) GetTransferGraph(ctx context.Context, in *GetTransferGraphRequest, opts ...grpc.CallOption) (*GetTransferGraphResponse, error) {
+	out := new(GetTransferGraphResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetTransferGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) StopDaemon(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
 	out := new(StopResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/StopDaemon", in, out, opts...)
@@ -167,6 +431,15 @@ func (c *taprootAssetsClient) DebugLevel(ctx context.Context, in *DebugLevelRequ
 	return out, nil
 }
 
+func (c *taprootAssetsClient) BackupDatabase(ctx context.Context, in *BackupDatabaseRequest, opts ...grpc.CallOption) (*BackupDatabaseResponse, error) {
+	out := new(BackupDatabaseResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BackupDatabase", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) QueryAddrs(ctx context.Context, in *QueryAddrRequest, opts ...grpc.CallOption) (*QueryAddrResponse, error) {
 	out := new(QueryAddrResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/QueryAddrs", in, out, opts...)
@@ -185,6 +458,15 @@ func (c *taprootAssetsClient) NewAddr(ctx context.Context, in *NewAddrRequest, o
 	return out, nil
 }
 
+func (c *taprootAssetsClient) NewAddrBatch(ctx context.Context, in *NewAddrBatchRequest, opts ...grpc.CallOption) (*NewAddrBatchResponse, error) {
+	out := new(NewAddrBatchResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/NewAddrBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) DecodeAddr(ctx context.Context, in *DecodeAddrRequest, opts ...grpc.CallOption) (*Addr, error) {
 	out := new(Addr)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/DecodeAddr", in, out, opts...)
@@ -221,6 +503,15 @@ func (c *taprootAssetsClient) DecodeProof(ctx context.Context, in *DecodeProofRe
 	return out, nil
 }
 
+func (c *taprootAssetsClient) CheckProofCompatibility(ctx context.Context, in *CheckProofCompatibilityRequest, opts ...grpc.CallOption) (*CheckProofCompatibilityResponse, error) {
+	out := new(CheckProofCompatibilityResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/CheckProofCompatibility", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) ExportProof(ctx context.Context, in *ExportProofRequest, opts ...grpc.CallOption) (*ProofFile, error) {
 	out := new(ProofFile)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ExportProof", in, out, opts...)
@@ -230,6 +521,133 @@ func (c *taprootAssetsClient) ExportProof(ctx context.Context, in *ExportProofRe
 	return out, nil
 }
 
+func (c *taprootAssetsClient) ExportProofsBatch(ctx context.Context, in *ExportProofsBatchRequest, opts ...grpc.CallOption) (TaprootAssets_ExportProofsBatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[1], "/taprpc.TaprootAssets/ExportProofsBatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taprootAssetsExportProofsBatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaprootAssets_ExportProofsBatchClient interface {
+	Recv() (*ExportedProofFile, error)
+	grpc.ClientStream
+}
+
+type taprootAssetsExportProofsBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *taprootAssetsExportProofsBatchClient) Recv() (*ExportedProofFile, error) {
+	m := new(ExportedProofFile)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taprootAssetsClient) ExportProofStream(ctx context.Context, in *ExportProofStreamRequest, opts ...grpc.CallOption) (TaprootAssets_ExportProofStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[2], "/taprpc.TaprootAssets/ExportProofStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taprootAssetsExportProofStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaprootAssets_ExportProofStreamClient interface {
+	Recv() (*ProofFileChunk, error)
+	grpc.ClientStream
+}
+
+type taprootAssetsExportProofStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *taprootAssetsExportProofStreamClient) Recv() (*ProofFileChunk, error) {
+	m := new(ProofFileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taprootAssetsClient) MergeProofFiles(ctx context.Context, in *MergeProofFilesRequest, opts ...grpc.CallOption) (*ProofFile, error) {
+	out := new(ProofFile)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/MergeProofFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) GetSplitCommitment(ctx context.Context, in *GetSplitCommitmentRequest, opts ...grpc.CallOption) (*GetSplitCommitmentResponse, error) {
+	out := new(GetSplitCommitmentResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetSplitCommitment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) GetAnchorMerkleProof(ctx context.Context, in *GetAnchorMerkleProofRequest, opts ...grpc.CallOption) (*GetAnchorMerkleProofResponse, error) {
+	out := new(GetAnchorMerkleProofResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetAnchorMerkleProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ListDeliveryReceipts(ctx context.Context, in *ListDeliveryReceiptsRequest, opts ...grpc.CallOption) (*ListDeliveryReceiptsResponse, error) {
+	out := new(ListDeliveryReceiptsResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListDeliveryReceipts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) PauseProofDelivery(ctx context.Context, in *PauseProofDeliveryRequest, opts ...grpc.CallOption) (*PauseProofDeliveryResponse, error) {
+	out := new(PauseProofDeliveryResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/PauseProofDelivery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ResumeProofDelivery(ctx context.Context, in *ResumeProofDeliveryRequest, opts ...grpc.CallOption) (*ResumeProofDeliveryResponse, error) {
+	out := new(ResumeProofDeliveryResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ResumeProofDelivery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ListDeadLetterProofs(ctx context.Context, in *ListDeadLetterProofsRequest, opts ...grpc.CallOption) (*ListDeadLetterProofsResponse, error) {
+	out := new(ListDeadLetterProofsResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ListDeadLetterProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) SendAsset(ctx context.Context, in *SendAssetRequest, opts ...grpc.CallOption) (*SendAssetResponse, error) {
 	out := new(SendAssetResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/SendAsset", in, out, opts...)
@@ -239,6 +657,60 @@ func (c *taprootAssetsClient) SendAsset(ctx context.Context, in *SendAssetReques
 	return out, nil
 }
 
+func (c *taprootAssetsClient) PrepareTransfer(ctx context.Context, in *PrepareTransferRequest, opts ...grpc.CallOption) (*PrepareTransferResponse, error) {
+	out := new(PrepareTransferResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/PrepareTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) BroadcastTransfer(ctx context.Context, in *BroadcastTransferRequest, opts ...grpc.CallOption) (*BroadcastTransferResponse, error) {
+	out := new(BroadcastTransferResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BroadcastTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ExportPendingTransfer(ctx context.Context, in *ExportPendingTransferRequest, opts ...grpc.CallOption) (*ExportPendingTransferResponse, error) {
+	out := new(ExportPendingTransferResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ExportPendingTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) ImportPendingTransfer(ctx context.Context, in *ImportPendingTransferRequest, opts ...grpc.CallOption) (*ImportPendingTransferResponse, error) {
+	out := new(ImportPendingTransferResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/ImportPendingTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) BumpTransferFee(ctx context.Context, in *BumpTransferFeeRequest, opts ...grpc.CallOption) (*BumpTransferFeeResponse, error) {
+	out := new(BumpTransferFeeResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BumpTransferFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) CancelTransfer(ctx context.Context, in *CancelTransferRequest, opts ...grpc.CallOption) (*CancelTransferResponse, error) {
+	out := new(CancelTransferResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/CancelTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) BurnAsset(ctx context.Context, in *BurnAssetRequest, opts ...grpc.CallOption) (*BurnAssetResponse, error) {
 	out := new(BurnAssetResponse)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/BurnAsset", in, out, opts...)
@@ -257,8 +729,17 @@ func (c *taprootAssetsClient) GetInfo(ctx context.Context, in *GetInfoRequest, o
 	return out, nil
 }
 
+func (c *taprootAssetsClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *taprootAssetsClient) SubscribeSendAssetEventNtfns(ctx context.Context, in *SubscribeSendAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeSendAssetEventNtfnsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[0], "/taprpc.TaprootAssets/SubscribeSendAssetEventNtfns", opts...)
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[3], "/taprpc.TaprootAssets/SubscribeSendAssetEventNtfns", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -289,6 +770,47 @@ func (x *taprootAssetsSubscribeSendAssetEventNtfnsClient) Recv() (*SendAssetEven
 	return m, nil
 }
 
+func (c *taprootAssetsClient) GetSendStateMachine(ctx context.Context, in *GetSendStateMachineRequest, opts ...grpc.CallOption) (*GetSendStateMachineResponse, error) {
+	out := new(GetSendStateMachineResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetSendStateMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) SubscribeReceiveAssetEventNtfns(ctx context.Context, in *SubscribeReceiveAssetEventNtfnsRequest, opts ...grpc.CallOption) (TaprootAssets_SubscribeReceiveAssetEventNtfnsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TaprootAssets_ServiceDesc.Streams[4], "/taprpc.TaprootAssets/SubscribeReceiveAssetEventNtfns", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taprootAssetsSubscribeReceiveAssetEventNtfnsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TaprootAssets_SubscribeReceiveAssetEventNtfnsClient interface {
+	Recv() (*ReceiveAssetEvent, error)
+	grpc.ClientStream
+}
+
+type taprootAssetsSubscribeReceiveAssetEventNtfnsClient struct {
+	grpc.ClientStream
+}
+
+func (x *taprootAssetsSubscribeReceiveAssetEventNtfnsClient) Recv() (*ReceiveAssetEvent, error) {
+	m := new(ReceiveAssetEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *taprootAssetsClient) FetchAssetMeta(ctx context.Context, in *FetchAssetMetaRequest, opts ...grpc.CallOption) (*AssetMeta, error) {
 	out := new(AssetMeta)
 	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/FetchAssetMeta", in, out, opts...)
@@ -298,6 +820,33 @@ func (c *taprootAssetsClient) FetchAssetMeta(ctx context.Context, in *FetchAsset
 	return out, nil
 }
 
+func (c *taprootAssetsClient) SearchAssetsByMeta(ctx context.Context, in *SearchAssetsByMetaRequest, opts ...grpc.CallOption) (*SearchAssetsByMetaResponse, error) {
+	out := new(SearchAssetsByMetaResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/SearchAssetsByMeta", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) GetTransferMetrics(ctx context.Context, in *GetTransferMetricsRequest, opts ...grpc.CallOption) (*GetTransferMetricsResponse, error) {
+	out := new(GetTransferMetricsResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/GetTransferMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taprootAssetsClient) AnchorSweepStatus(ctx context.Context, in *AnchorSweepStatusRequest, opts ...grpc.CallOption) (*AnchorSweepStatusResponse, error) {
+	out := new(AnchorSweepStatusResponse)
+	err := c.cc.Invoke(ctx, "/taprpc.TaprootAssets/AnchorSweepStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TaprootAssetsServer is the server API for TaprootAssets service.
 // All implementations must embed UnimplementedTaprootAssetsServer
 // for forward compatibility
@@ -305,20 +854,60 @@ type TaprootAssetsServer interface {
 	// tapcli: `assets list`
 	// ListAssets lists the set of assets owned by the target daemon.
 	ListAssets(context.Context, *ListAssetRequest) (*ListAssetResponse, error)
+	// tapcli: `assets subscribe`
+	// SubscribeAssets sends the current set of assets owned by the target daemon
+	// and then streams incremental updates to that set as assets are minted,
+	// received, sent, change their confirmation status or their lease expires.
+	SubscribeAssets(*SubscribeAssetsRequest, TaprootAssets_SubscribeAssetsServer) error
 	// tapcli: `assets utxos`
 	// ListUtxos lists the UTXOs managed by the target daemon, and the assets they
 	// hold.
 	ListUtxos(context.Context, *ListUtxosRequest) (*ListUtxosResponse, error)
+	// tapcli: `assets footprint`
+	// ListOnChainFootprint lists all on-chain UTXOs the target daemon considers
+	// its own, including anchor outputs that only hold spent assets, such as
+	// tombstones and burns, together with their key derivation and the assets
+	// they anchor. This allows the outputs managed by the daemon to be told apart
+	// from the other UTXOs of the backing wallet.
+	ListOnChainFootprint(context.Context, *ListOnChainFootprintRequest) (*ListOnChainFootprintResponse, error)
 	// tapcli: `assets groups`
 	// ListGroups lists the asset groups known to the target daemon, and the assets
 	// held in each group.
 	ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error)
+	// tapcli: `assets verifysupply`
+	// VerifySupplyConsistency reconciles the supply of an asset group. The
+	// issuance proofs of the local universe are checked against the universe
+	// root, the burns known to the transfer universe and the local node are
+	// subtracted from the issued amount, and the balance held by the node is
+	// checked against the remaining outstanding units. Any discrepancy found is
+	// reported.
+	VerifySupplyConsistency(context.Context, *VerifySupplyConsistencyRequest) (*VerifySupplyConsistencyResponse, error)
 	// tapcli: `assets balance`
 	// ListBalances lists asset balances
 	ListBalances(context.Context, *ListBalancesRequest) (*ListBalancesResponse, error)
+	// tapcli: `assets rebuildbalances`
+	// RebuildBalances re-derives the amounts of all owned, unspent assets from
+	// their stored proofs, checks the proofs against the current chain and
+	// replaces the recorded amounts that differ in a single database transaction.
+	// All discrepancies found between the recorded assets and their proofs are
+	// reported. Assets whose proof can't be used to repair them are reported but
+	// left unchanged.
+	RebuildBalances(context.Context, *RebuildBalancesRequest) (*RebuildBalancesResponse, error)
 	// tapcli: `assets transfers`
 	// ListTransfers lists outbound asset transfers tracked by the target daemon.
 	ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error)
+	// tapcli: `assets transfersbykey`
+	// ListTransfersByScriptKey lists all inbound and outbound asset transfers
+	// that created, spent or changed an output with the given script key,
+	// ordered by the time they were first seen.
+	ListTransfersByScriptKey(context.Context, *ListTransfersByScriptKeyRequest) (*ListTransfersByScriptKeyResponse, error)
+	// tapcli: `assets transfergraph`
+	// GetTransferGraph returns an outbound transfer together with the flow of
+	// asset amounts from each of its inputs to the outputs they contributed to.
+	// Because the units of a fungible asset are interchangeable, the amounts of
+	// the inputs of an asset ID are attributed to its outputs in the order both
+	// appear in the transfer.
+	GetTransferGraph(context.Context, *GetTransferGraphRequest) (*GetTransferGraphResponse, error)
 	// tapcli: `stop`
 	// StopDaemon will send a shutdown request to the interrupt handler, triggering
 	// a graceful shutdown of the daemon.
@@ -329,6 +918,15 @@ type TaprootAssetsServer interface {
 	// level, or in a granular fashion to specify the logging for a target
 	// sub-system.
 	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	// tapcli: `backup`
+	// BackupDatabase creates a consistent backup of the database while the daemon
+	// keeps running, and writes it to the given path on the machine running the
+	// daemon. Transfers that are in flight while the backup is created are
+	// either fully included or not at all. For SQLite, the backup is a regular
+	// database file. For Postgres, the backup is created by pg_dump, which must
+	// be installed, in its custom archive format that can be restored with
+	// pg_restore. The proof files stored on disk aren't part of the backup.
+	BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error)
 	// tapcli: `addrs query`
 	// QueryAddrs queries the set of Taproot Asset addresses stored in the
 	// database.
@@ -336,6 +934,10 @@ type TaprootAssetsServer interface {
 	// tapcli: `addrs new`
 	// NewAddr makes a new address from the set of request params.
 	NewAddr(context.Context, *NewAddrRequest) (*Addr, error)
+	// NewAddrBatch makes a new address for each of the given sets of request
+	// params. The addresses are created atomically, so if any of the requests is
+	// invalid, none of the addresses are created.
+	NewAddrBatch(context.Context, *NewAddrBatchRequest) (*NewAddrBatchResponse, error)
 	// tapcli: `addrs decode`
 	// DecodeAddr decode a Taproot Asset address into a partial asset message that
 	// represents the asset it wants to receive.
@@ -345,23 +947,128 @@ type TaprootAssetsServer interface {
 	// created previously.
 	AddrReceives(context.Context, *AddrReceivesRequest) (*AddrReceivesResponse, error)
 	// tapcli: `proofs verify`
-	// VerifyProof attempts to verify a given proof file that claims to be anchored
-	// at the specified genesis point.
+	// VerifyProof attempts to verify a given proof file or single mint/transition
+	// proof, including the anchor transaction's block header and merkle
+	// inclusion. The asset does not need to be owned by the node. A verification
+	// failure does not fail the call but is reported in the response instead.
+	// Single transfer proofs can only be verified fully as part of their file,
+	// since the witnesses are checked against the previous asset state.
 	VerifyProof(context.Context, *ProofFile) (*VerifyProofResponse, error)
 	// tapcli: `proofs decode`
 	// DecodeProof attempts to decode a given proof file into human readable
-	// format.
+	// format. The proof is neither verified nor imported. If the proof can't be
+	// decoded, the error names the record that couldn't be decoded and its byte
+	// offset within the proof.
 	DecodeProof(context.Context, *DecodeProofRequest) (*DecodeProofResponse, error)
+	// tapcli: `proofs checkcompat`
+	// CheckProofCompatibility runs a strict conformance check of the encoding of
+	// a given proof or proof file, for example one created by another
+	// implementation. In contrast to VerifyProof, only the encoding is checked
+	// and not the validity of the proof. The results of all individual checks are
+	// returned.
+	CheckProofCompatibility(context.Context, *CheckProofCompatibilityRequest) (*CheckProofCompatibilityResponse, error)
 	// tapcli: `proofs export`
 	// ExportProof exports the latest raw proof file anchored at the specified
 	// script_key.
 	ExportProof(context.Context, *ExportProofRequest) (*ProofFile, error)
+	// tapcli: `proofs exportbatch`
+	// ExportProofsBatch exports the latest raw proof files of all owned assets
+	// that match the given filter. A proof file is streamed for each matching
+	// asset, together with its asset ID and script key. If no filter is
+	// specified, the proof files of all owned assets are exported.
+	ExportProofsBatch(*ExportProofsBatchRequest, TaprootAssets_ExportProofsBatchServer) error
+	// tapcli: `proofs exportstream`
+	// ExportProofStream exports the latest proof file anchored at the specified
+	// script_key one proof at a time, instead of returning the whole file in a
+	// single response. An optional range of proof indexes can be given to only
+	// export a part of the proof chain. The streamed proofs can be appended to
+	// an empty proof file of the reported version to reassemble the file.
+	ExportProofStream(*ExportProofStreamRequest, TaprootAssets_ExportProofStreamServer) error
+	// tapcli: `proofs merge`
+	// MergeProofFiles merges two proof files into a single proof file. The first
+	// proof of the suffix file must spend the asset output of the last proof of
+	// the prefix file, otherwise the request is rejected.
+	MergeProofFiles(context.Context, *MergeProofFilesRequest) (*ProofFile, error)
+	// tapcli: `proofs splitcommitment`
+	// GetSplitCommitment decodes the split commitment of the asset in the given
+	// transfer output from the stored proof. For a split asset, the inclusion
+	// proof of the split in the split commitment tree is returned together with
+	// the split root it resolves to. For the root asset of a split, only the split
+	// root is returned.
+	GetSplitCommitment(context.Context, *GetSplitCommitmentRequest) (*GetSplitCommitmentResponse, error)
+	// tapcli: `proofs merkleproof`
+	// GetAnchorMerkleProof returns the merkle proof of the anchor transaction of
+	// the given transfer output in its confirming block, as embedded in the
+	// stored proof, together with the block header. This allows a verifier that
+	// follows the header chain to confirm the inclusion of the anchor
+	// transaction independently.
+	GetAnchorMerkleProof(context.Context, *GetAnchorMerkleProofRequest) (*GetAnchorMerkleProofResponse, error)
+	// tapcli: `proofs receipts`
+	// ListDeliveryReceipts lists the delivery receipts that receivers returned
+	// for proofs sent to the given script key through a proof courier. A receipt
+	// is signed by the key that controls the script key and proves the receiver
+	// got the exact proof that was sent.
+	ListDeliveryReceipts(context.Context, *ListDeliveryReceiptsRequest) (*ListDeliveryReceiptsResponse, error)
+	// tapcli: `proofs pausedelivery`
+	// PauseProofDelivery pauses the delivery of the proof for the given transfer
+	// output. The courier stops its delivery attempts until the delivery is
+	// resumed, without abandoning the delivery. Time spent paused doesn't count
+	// against the retry limit. Paused deliveries are resumed when the daemon
+	// restarts.
+	PauseProofDelivery(context.Context, *PauseProofDeliveryRequest) (*PauseProofDeliveryResponse, error)
+	// tapcli: `proofs resumedelivery`
+	// ResumeProofDelivery resumes the paused delivery of the proof for the given
+	// transfer output. The courier starts over with a fresh set of delivery
+	// attempts.
+	ResumeProofDelivery(context.Context, *ResumeProofDeliveryRequest) (*ResumeProofDeliveryResponse, error)
+	// tapcli: `proofs deadletters`
+	// ListDeadLetterProofs lists the inbound proofs that couldn't be imported
+	// after all retries. Dead letters are persisted and stay listed until the
+	// proof is imported, for example by importing it manually.
+	ListDeadLetterProofs(context.Context, *ListDeadLetterProofsRequest) (*ListDeadLetterProofsResponse, error)
 	// tapcli: `assets send`
 	// SendAsset uses one or multiple passed Taproot Asset address(es) to attempt
 	// to complete an asset send. The method returns information w.r.t the on chain
 	// send, as well as the proof file information the receiver needs to fully
 	// receive the asset.
 	SendAsset(context.Context, *SendAssetRequest) (*SendAssetResponse, error)
+	// tapcli: `assets prepare`
+	// PrepareTransfer funds, signs and persists the anchor transaction of a
+	// transfer to one or multiple Taproot Asset address(es) without broadcasting
+	// it. The inputs of the staged transfer stay locked until the transfer is
+	// released with BroadcastTransfer, also across restarts.
+	PrepareTransfer(context.Context, *PrepareTransferRequest) (*PrepareTransferResponse, error)
+	// tapcli: `assets broadcast`
+	// BroadcastTransfer releases a transfer staged with PrepareTransfer, which
+	// broadcasts its anchor transaction and continues the regular send process.
+	BroadcastTransfer(context.Context, *BroadcastTransferRequest) (*BroadcastTransferResponse, error)
+	// tapcli: `assets exportpending`
+	// ExportPendingTransfer exports the state of a pending transfer, so another
+	// daemon connected to the same lnd node can take it over with
+	// ImportPendingTransfer, for example if this daemon becomes unavailable.
+	ExportPendingTransfer(context.Context, *ExportPendingTransferRequest) (*ExportPendingTransferResponse, error)
+	// tapcli: `assets importpending`
+	// ImportPendingTransfer takes over a pending transfer exported by another
+	// daemon connected to the same lnd node and completes its broadcast and proof
+	// delivery. The transfer is claimed by leasing the wallet inputs of its anchor
+	// transaction, so only one daemon can take it over and the exporting daemon
+	// no longer acts on it.
+	ImportPendingTransfer(context.Context, *ImportPendingTransferRequest) (*ImportPendingTransferResponse, error)
+	// tapcli: `assets bumpfee`
+	// BumpTransferFee replaces the unconfirmed anchor transaction of a pending
+	// transfer with one that pays a higher fee rate (BIP-125 RBF). The
+	// replacement spends the same inputs and creates the same outputs, including
+	// the ones carrying passive assets, with the additional fee being deducted
+	// from the wallet's change output. Proof delivery continues against whichever
+	// version of the anchor transaction confirms.
+	BumpTransferFee(context.Context, *BumpTransferFeeRequest) (*BumpTransferFeeResponse, error)
+	// tapcli: `assets cancel`
+	// CancelTransfer cancels a pending transfer whose anchor transaction has not
+	// yet confirmed, for example because it pays a fee too low to ever confirm.
+	// The transfer is moved into a terminal cancelled state and its asset inputs
+	// are released so they can be spent again. Transfers that were already
+	// confirmed on chain can't be cancelled.
+	CancelTransfer(context.Context, *CancelTransferRequest) (*CancelTransferResponse, error)
 	// tapcli: `assets burn`
 	// BurnAsset burns the given number of units of a given asset by sending them
 	// to a provably un-spendable script key. Burning means irrevocably destroying
@@ -372,12 +1079,41 @@ type TaprootAssetsServer interface {
 	// tapcli: `getinfo`
 	// GetInfo returns the information for the node.
 	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	// tapcli: `getconfig`
+	// GetConfig returns the effective configuration the daemon was started with,
+	// after validation. Sensitive values such as passwords are redacted.
+	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
 	// SubscribeSendAssetEventNtfns registers a subscription to the event
 	// notification stream which relates to the asset sending process.
 	SubscribeSendAssetEventNtfns(*SubscribeSendAssetEventNtfnsRequest, TaprootAssets_SubscribeSendAssetEventNtfnsServer) error
+	// tapcli: `assets sendstates`
+	// GetSendStateMachine returns the definition of the state machine that
+	// outbound transfers move through, as reported by the execute send state
+	// events of SubscribeSendAssetEventNtfns.
+	GetSendStateMachine(context.Context, *GetSendStateMachineRequest) (*GetSendStateMachineResponse, error)
+	// SubscribeReceiveAssetEventNtfns registers a subscription to the event
+	// notification stream which relates to the process of receiving assets with
+	// an address.
+	SubscribeReceiveAssetEventNtfns(*SubscribeReceiveAssetEventNtfnsRequest, TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error
 	// FetchAssetMeta allows a caller to fetch the reveal meta data for an asset
 	// either by the asset ID for that asset, or a meta hash.
 	FetchAssetMeta(context.Context, *FetchAssetMetaRequest) (*AssetMeta, error)
+	// tapcli: `assets searchmeta`
+	// SearchAssetsByMeta searches the assets known to the daemon by the content
+	// of their meta data. Meta data that is a JSON object is matched by its
+	// fields, any other meta data by substring.
+	SearchAssetsByMeta(context.Context, *SearchAssetsByMetaRequest) (*SearchAssetsByMetaResponse, error)
+	// tapcli: `assets transfermetrics`
+	// GetTransferMetrics returns latency percentiles and throughput figures for
+	// the outbound asset transfers that were initiated within the given time
+	// window.
+	GetTransferMetrics(context.Context, *GetTransferMetricsRequest) (*GetTransferMetricsResponse, error)
+	// tapcli: `assets sweepstatus`
+	// AnchorSweepStatus returns the status of the background sweeper of anchor
+	// outputs that only anchor spent assets, such as the tombstones and burns
+	// left behind by completed transfers, together with the anchor outputs that
+	// are waiting to be swept and the ones that were already swept.
+	AnchorSweepStatus(context.Context, *AnchorSweepStatusRequest) (*AnchorSweepStatusResponse, error)
 	mustEmbedUnimplementedTaprootAssetsServer()
 }
 
@@ -388,30 +1124,54 @@ type UnimplementedTaprootAssetsServer struct {
 func (UnimplementedTaprootAssetsServer) ListAssets(context.Context, *ListAssetRequest) (*ListAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListAssets not implemented")
 }
+func (UnimplementedTaprootAssetsServer) SubscribeAssets(*SubscribeAssetsRequest, TaprootAssets_SubscribeAssetsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAssets not implemented")
+}
 func (UnimplementedTaprootAssetsServer) ListUtxos(context.Context, *ListUtxosRequest) (*ListUtxosResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUtxos not implemented")
 }
+func (UnimplementedTaprootAssetsServer) ListOnChainFootprint(context.Context, *ListOnChainFootprintRequest) (*ListOnChainFootprintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOnChainFootprint not implemented")
+}
 func (UnimplementedTaprootAssetsServer) ListGroups(context.Context, *ListGroupsRequest) (*ListGroupsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListGroups not implemented")
 }
+func (UnimplementedTaprootAssetsServer) VerifySupplyConsistency(context.Context, *VerifySupplyConsistencyRequest) (*VerifySupplyConsistencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifySupplyConsistency not implemented")
+}
 func (UnimplementedTaprootAssetsServer) ListBalances(context.Context, *ListBalancesRequest) (*ListBalancesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListBalances not implemented")
 }
+func (UnimplementedTaprootAssetsServer) RebuildBalances(context.Context, *RebuildBalancesRequest) (*RebuildBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebuildBalances not implemented")
+}
 func (UnimplementedTaprootAssetsServer) ListTransfers(context.Context, *ListTransfersRequest) (*ListTransfersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListTransfers not implemented")
 }
+func (UnimplementedTaprootAssetsServer) ListTransfersByScriptKey(context.Context, *ListTransfersByScriptKeyRequest) (*ListTransfersByScriptKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransfersByScriptKey not implemented")
+}
+func (UnimplementedTaprootAssetsServer) GetTransferGraph(context.Context, *GetTransferGraphRequest) (*GetTransferGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransferGraph not implemented")
+}
 func (UnimplementedTaprootAssetsServer) StopDaemon(context.Context, *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopDaemon not implemented")
 }
 func (UnimplementedTaprootAssetsServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
 }
+func (UnimplementedTaprootAssetsServer) BackupDatabase(context.Context, *BackupDatabaseRequest) (*BackupDatabaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackupDatabase not implemented")
+}
 func (UnimplementedTaprootAssetsServer) QueryAddrs(context.Context, *QueryAddrRequest) (*QueryAddrResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryAddrs not implemented")
 }
 func (UnimplementedTaprootAssetsServer) NewAddr(context.Context, *NewAddrRequest) (*Addr, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NewAddr not implemented")
 }
+func (UnimplementedTaprootAssetsServer) NewAddrBatch(context.Context, *NewAddrBatchRequest) (*NewAddrBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewAddrBatch not implemented")
+}
 func (UnimplementedTaprootAssetsServer) DecodeAddr(context.Context, *DecodeAddrRequest) (*Addr, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DecodeAddr not implemented")
 }
@@ -424,24 +1184,90 @@ func (UnimplementedTaprootAssetsServer) VerifyProof(context.Context, *ProofFile)
 func (UnimplementedTaprootAssetsServer) DecodeProof(context.Context, *DecodeProofRequest) (*DecodeProofResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DecodeProof not implemented")
 }
+func (UnimplementedTaprootAssetsServer) CheckProofCompatibility(context.Context, *CheckProofCompatibilityRequest) (*CheckProofCompatibilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckProofCompatibility not implemented")
+}
 func (UnimplementedTaprootAssetsServer) ExportProof(context.Context, *ExportProofRequest) (*ProofFile, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExportProof not implemented")
 }
+func (UnimplementedTaprootAssetsServer) ExportProofsBatch(*ExportProofsBatchRequest, TaprootAssets_ExportProofsBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportProofsBatch not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ExportProofStream(*ExportProofStreamRequest, TaprootAssets_ExportProofStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportProofStream not implemented")
+}
+func (UnimplementedTaprootAssetsServer) MergeProofFiles(context.Context, *MergeProofFilesRequest) (*ProofFile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeProofFiles not implemented")
+}
+func (UnimplementedTaprootAssetsServer) GetSplitCommitment(context.Context, *GetSplitCommitmentRequest) (*GetSplitCommitmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSplitCommitment not implemented")
+}
+func (UnimplementedTaprootAssetsServer) GetAnchorMerkleProof(context.Context, *GetAnchorMerkleProofRequest) (*GetAnchorMerkleProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAnchorMerkleProof not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ListDeliveryReceipts(context.Context, *ListDeliveryReceiptsRequest) (*ListDeliveryReceiptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveryReceipts not implemented")
+}
+func (UnimplementedTaprootAssetsServer) PauseProofDelivery(context.Context, *PauseProofDeliveryRequest) (*PauseProofDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseProofDelivery not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ResumeProofDelivery(context.Context, *ResumeProofDeliveryRequest) (*ResumeProofDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeProofDelivery not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ListDeadLetterProofs(context.Context, *ListDeadLetterProofsRequest) (*ListDeadLetterProofsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetterProofs not implemented")
+}
 func (UnimplementedTaprootAssetsServer) SendAsset(context.Context, *SendAssetRequest) (*SendAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendAsset not implemented")
 }
+func (UnimplementedTaprootAssetsServer) PrepareTransfer(context.Context, *PrepareTransferRequest) (*PrepareTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareTransfer not implemented")
+}
+func (UnimplementedTaprootAssetsServer) BroadcastTransfer(context.Context, *BroadcastTransferRequest) (*BroadcastTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BroadcastTransfer not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ExportPendingTransfer(context.Context, *ExportPendingTransferRequest) (*ExportPendingTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportPendingTransfer not implemented")
+}
+func (UnimplementedTaprootAssetsServer) ImportPendingTransfer(context.Context, *ImportPendingTransferRequest) (*ImportPendingTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportPendingTransfer not implemented")
+}
+func (UnimplementedTaprootAssetsServer) BumpTransferFee(context.Context, *BumpTransferFeeRequest) (*BumpTransferFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BumpTransferFee not implemented")
+}
+func (UnimplementedTaprootAssetsServer) CancelTransfer(context.Context, *CancelTransferRequest) (*CancelTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTransfer not implemented")
+}
 func (UnimplementedTaprootAssetsServer) BurnAsset(context.Context, *BurnAssetRequest) (*BurnAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BurnAsset not implemented")
 }
 func (UnimplementedTaprootAssetsServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
 }
+func (UnimplementedTaprootAssetsServer) GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
 func (UnimplementedTaprootAssetsServer) SubscribeSendAssetEventNtfns(*SubscribeSendAssetEventNtfnsRequest, TaprootAssets_SubscribeSendAssetEventNtfnsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeSendAssetEventNtfns not implemented")
 }
+func (UnimplementedTaprootAssetsServer) GetSendStateMachine(context.Context, *GetSendStateMachineRequest) (*GetSendStateMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSendStateMachine not implemented")
+}
+func (UnimplementedTaprootAssetsServer) SubscribeReceiveAssetEventNtfns(*SubscribeReceiveAssetEventNtfnsRequest, TaprootAssets_SubscribeReceiveAssetEventNtfnsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeReceiveAssetEventNtfns not implemented")
+}
 func (UnimplementedTaprootAssetsServer) FetchAssetMeta(context.Context, *FetchAssetMetaRequest) (*AssetMeta, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchAssetMeta not implemented")
 }
+func (UnimplementedTaprootAssetsServer) SearchAssetsByMeta(context.Context, *SearchAssetsByMetaRequest) (*SearchAssetsByMetaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAssetsByMeta not implemented")
+}
+func (UnimplementedTaprootAssetsServer) GetTransferMetrics(context.Context, *GetTransferMetricsRequest) (*GetTransferMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransferMetrics not implemented")
+}
+func (UnimplementedTaprootAssetsServer) AnchorSweepStatus(context.Context, *AnchorSweepStatusRequest) (*AnchorSweepStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnchorSweepStatus not implemented")
+}
 func (UnimplementedTaprootAssetsServer) mustEmbedUnimplementedTaprootAssetsServer() {}
 
 // UnsafeTaprootAssetsServer may be embedded to opt out of forward compatibility for this service.
@@ -455,272 +1281,713 @@ func RegisterTaprootAssetsServer(s grpc.ServiceRegistrar, srv TaprootAssetsServe
 	s.RegisterService(&TaprootAssets_ServiceDesc, srv)
 }
 
-func _TaprootAssets_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAssetRequest)
+func _TaprootAssets_ListAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListAssets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListAssets(ctx, req.(*ListAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_SubscribeAssets_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAssetsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaprootAssetsServer).SubscribeAssets(m, &taprootAssetsSubscribeAssetsServer{stream})
+}
+
+type TaprootAssets_SubscribeAssetsServer interface {
+	Send(*AssetUpdate) error
+	grpc.ServerStream
+}
+
+type taprootAssetsSubscribeAssetsServer struct {
+	grpc.ServerStream
+}
+
+func (x *taprootAssetsSubscribeAssetsServer) Send(m *AssetUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaprootAssets_ListUtxos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUtxosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListUtxos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListUtxos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListUtxos(ctx, req.(*ListUtxosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ListOnChainFootprint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOnChainFootprintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListOnChainFootprint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListOnChainFootprint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListOnChainFootprint(ctx, req.(*ListOnChainFootprintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGroupsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListGroups(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListGroups",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListGroups(ctx, req.(*ListGroupsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_VerifySupplyConsistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifySupplyConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).VerifySupplyConsistency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/VerifySupplyConsistency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).VerifySupplyConsistency(ctx, req.(*VerifySupplyConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ListBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListBalances(ctx, req.(*ListBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_RebuildBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebuildBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).RebuildBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/RebuildBalances",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).RebuildBalances(ctx, req.(*RebuildBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ListTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransfersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListTransfers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListTransfers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListTransfers(ctx, req.(*ListTransfersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ListTransfersByScriptKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransfersByScriptKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).ListTransfersByScriptKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/ListTransfersByScriptKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).ListTransfersByScriptKey(ctx, req.(*ListTransfersByScriptKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_GetTransferGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransferGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).GetTransferGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/GetTransferGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).GetTransferGraph(ctx, req.(*GetTransferGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).StopDaemon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/StopDaemon",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).StopDaemon(ctx, req.(*StopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).DebugLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/DebugLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_BackupDatabase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupDatabaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).BackupDatabase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/BackupDatabase",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).BackupDatabase(ctx, req.(*BackupDatabaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_QueryAddrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAddrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).QueryAddrs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/QueryAddrs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).QueryAddrs(ctx, req.(*QueryAddrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_NewAddr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).NewAddr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/NewAddr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).NewAddr(ctx, req.(*NewAddrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_NewAddrBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddrBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).NewAddrBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/NewAddrBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).NewAddrBatch(ctx, req.(*NewAddrBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_DecodeAddr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeAddrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).DecodeAddr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/DecodeAddr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).DecodeAddr(ctx, req.(*DecodeAddrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_AddrReceives_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddrReceivesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).AddrReceives(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/AddrReceives",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).AddrReceives(ctx, req.(*AddrReceivesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_VerifyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProofFile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).VerifyProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/VerifyProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).VerifyProof(ctx, req.(*ProofFile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_DecodeProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).DecodeProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/DecodeProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).DecodeProof(ctx, req.(*DecodeProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_CheckProofCompatibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckProofCompatibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).CheckProofCompatibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/CheckProofCompatibility",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).CheckProofCompatibility(ctx, req.(*CheckProofCompatibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_ExportProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportProofRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ListAssets(ctx, in)
+		return srv.(TaprootAssetsServer).ExportProof(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ListAssets",
+		FullMethod: "/taprpc.TaprootAssets/ExportProof",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ListAssets(ctx, req.(*ListAssetRequest))
+		return srv.(TaprootAssetsServer).ExportProof(ctx, req.(*ExportProofRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_ListUtxos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListUtxosRequest)
+func _TaprootAssets_ExportProofsBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportProofsBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaprootAssetsServer).ExportProofsBatch(m, &taprootAssetsExportProofsBatchServer{stream})
+}
+
+type TaprootAssets_ExportProofsBatchServer interface {
+	Send(*ExportedProofFile) error
+	grpc.ServerStream
+}
+
+type taprootAssetsExportProofsBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *taprootAssetsExportProofsBatchServer) Send(m *ExportedProofFile) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaprootAssets_ExportProofStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportProofStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaprootAssetsServer).ExportProofStream(m, &taprootAssetsExportProofStreamServer{stream})
+}
+
+type TaprootAssets_ExportProofStreamServer interface {
+	Send(*ProofFileChunk) error
+	grpc.ServerStream
+}
+
+type taprootAssetsExportProofStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *taprootAssetsExportProofStreamServer) Send(m *ProofFileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TaprootAssets_MergeProofFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeProofFilesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ListUtxos(ctx, in)
+		return srv.(TaprootAssetsServer).MergeProofFiles(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ListUtxos",
+		FullMethod: "/taprpc.TaprootAssets/MergeProofFiles",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ListUtxos(ctx, req.(*ListUtxosRequest))
+		return srv.(TaprootAssetsServer).MergeProofFiles(ctx, req.(*MergeProofFilesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_ListGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListGroupsRequest)
+func _TaprootAssets_GetSplitCommitment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSplitCommitmentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ListGroups(ctx, in)
+		return srv.(TaprootAssetsServer).GetSplitCommitment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ListGroups",
+		FullMethod: "/taprpc.TaprootAssets/GetSplitCommitment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ListGroups(ctx, req.(*ListGroupsRequest))
+		return srv.(TaprootAssetsServer).GetSplitCommitment(ctx, req.(*GetSplitCommitmentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_ListBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListBalancesRequest)
+func _TaprootAssets_GetAnchorMerkleProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAnchorMerkleProofRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ListBalances(ctx, in)
+		return srv.(TaprootAssetsServer).GetAnchorMerkleProof(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ListBalances",
+		FullMethod: "/taprpc.TaprootAssets/GetAnchorMerkleProof",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ListBalances(ctx, req.(*ListBalancesRequest))
+		return srv.(TaprootAssetsServer).GetAnchorMerkleProof(ctx, req.(*GetAnchorMerkleProofRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_ListTransfers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListTransfersRequest)
+func _TaprootAssets_ListDeliveryReceipts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeliveryReceiptsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ListTransfers(ctx, in)
+		return srv.(TaprootAssetsServer).ListDeliveryReceipts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ListTransfers",
+		FullMethod: "/taprpc.TaprootAssets/ListDeliveryReceipts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ListTransfers(ctx, req.(*ListTransfersRequest))
+		return srv.(TaprootAssetsServer).ListDeliveryReceipts(ctx, req.(*ListDeliveryReceiptsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _TaprootAssets_PauseProofDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseProofDeliveryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).StopDaemon(ctx, in)
+		return srv.(TaprootAssetsServer).PauseProofDelivery(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/StopDaemon",
+		FullMethod: "/taprpc.TaprootAssets/PauseProofDelivery",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).StopDaemon(ctx, req.(*StopRequest))
+		return srv.(TaprootAssetsServer).PauseProofDelivery(ctx, req.(*PauseProofDeliveryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _TaprootAssets_ResumeProofDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeProofDeliveryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).DebugLevel(ctx, in)
+		return srv.(TaprootAssetsServer).ResumeProofDelivery(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/DebugLevel",
+		FullMethod: "/taprpc.TaprootAssets/ResumeProofDelivery",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(TaprootAssetsServer).ResumeProofDelivery(ctx, req.(*ResumeProofDeliveryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_QueryAddrs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryAddrRequest)
+func _TaprootAssets_ListDeadLetterProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLetterProofsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).QueryAddrs(ctx, in)
+		return srv.(TaprootAssetsServer).ListDeadLetterProofs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/QueryAddrs",
+		FullMethod: "/taprpc.TaprootAssets/ListDeadLetterProofs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).QueryAddrs(ctx, req.(*QueryAddrRequest))
+		return srv.(TaprootAssetsServer).ListDeadLetterProofs(ctx, req.(*ListDeadLetterProofsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_NewAddr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewAddrRequest)
+func _TaprootAssets_SendAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAssetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).NewAddr(ctx, in)
+		return srv.(TaprootAssetsServer).SendAsset(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/NewAddr",
+		FullMethod: "/taprpc.TaprootAssets/SendAsset",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).NewAddr(ctx, req.(*NewAddrRequest))
+		return srv.(TaprootAssetsServer).SendAsset(ctx, req.(*SendAssetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_DecodeAddr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DecodeAddrRequest)
+func _TaprootAssets_PrepareTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrepareTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).DecodeAddr(ctx, in)
+		return srv.(TaprootAssetsServer).PrepareTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/DecodeAddr",
+		FullMethod: "/taprpc.TaprootAssets/PrepareTransfer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).DecodeAddr(ctx, req.(*DecodeAddrRequest))
+		return srv.(TaprootAssetsServer).PrepareTransfer(ctx, req.(*PrepareTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_AddrReceives_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddrReceivesRequest)
+func _TaprootAssets_BroadcastTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).AddrReceives(ctx, in)
+		return srv.(TaprootAssetsServer).BroadcastTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/AddrReceives",
+		FullMethod: "/taprpc.TaprootAssets/BroadcastTransfer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).AddrReceives(ctx, req.(*AddrReceivesRequest))
+		return srv.(TaprootAssetsServer).BroadcastTransfer(ctx, req.(*BroadcastTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_VerifyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProofFile)
+func _TaprootAssets_ExportPendingTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportPendingTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).VerifyProof(ctx, in)
+		return srv.(TaprootAssetsServer).ExportPendingTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/VerifyProof",
+		FullMethod: "/taprpc.TaprootAssets/ExportPendingTransfer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).VerifyProof(ctx, req.(*ProofFile))
+		return srv.(TaprootAssetsServer).ExportPendingTransfer(ctx, req.(*ExportPendingTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_DecodeProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DecodeProofRequest)
+func _TaprootAssets_ImportPendingTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportPendingTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).DecodeProof(ctx, in)
+		return srv.(TaprootAssetsServer).ImportPendingTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/DecodeProof",
+		FullMethod: "/taprpc.TaprootAssets/ImportPendingTransfer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).DecodeProof(ctx, req.(*DecodeProofRequest))
+		return srv.(TaprootAssetsServer).ImportPendingTransfer(ctx, req.(*ImportPendingTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_ExportProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExportProofRequest)
+func _TaprootAssets_BumpTransferFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BumpTransferFeeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).ExportProof(ctx, in)
+		return srv.(TaprootAssetsServer).BumpTransferFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/ExportProof",
+		FullMethod: "/taprpc.TaprootAssets/BumpTransferFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).ExportProof(ctx, req.(*ExportProofRequest))
+		return srv.(TaprootAssetsServer).BumpTransferFee(ctx, req.(*BumpTransferFeeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _TaprootAssets_SendAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendAssetRequest)
+func _TaprootAssets_CancelTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTransferRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(TaprootAssetsServer).SendAsset(ctx, in)
+		return srv.(TaprootAssetsServer).CancelTransfer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/taprpc.TaprootAssets/SendAsset",
+		FullMethod: "/taprpc.TaprootAssets/CancelTransfer",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TaprootAssetsServer).SendAsset(ctx, req.(*SendAssetRequest))
+		return srv.(TaprootAssetsServer).CancelTransfer(ctx, req.(*CancelTransferRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -761,6 +2028,24 @@ func _TaprootAssets_GetInfo_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaprootAssets_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TaprootAssets_SubscribeSendAssetEventNtfns_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SubscribeSendAssetEventNtfnsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -782,6 +2067,45 @@ func (x *taprootAssetsSubscribeSendAssetEventNtfnsServer) Send(m *SendAssetEvent
 	return x.ServerStream.SendMsg(m)
 }
 
+func _TaprootAssets_GetSendStateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSendStateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).GetSendStateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/GetSendStateMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).GetSendStateMachine(ctx, req.(*GetSendStateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_SubscribeReceiveAssetEventNtfns_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeReceiveAssetEventNtfnsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaprootAssetsServer).SubscribeReceiveAssetEventNtfns(m, &taprootAssetsSubscribeReceiveAssetEventNtfnsServer{stream})
+}
+
+type TaprootAssets_SubscribeReceiveAssetEventNtfnsServer interface {
+	Send(*ReceiveAssetEvent) error
+	grpc.ServerStream
+}
+
+type taprootAssetsSubscribeReceiveAssetEventNtfnsServer struct {
+	grpc.ServerStream
+}
+
+func (x *taprootAssetsSubscribeReceiveAssetEventNtfnsServer) Send(m *ReceiveAssetEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _TaprootAssets_FetchAssetMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FetchAssetMetaRequest)
 	if err := dec(in); err != nil {
@@ -800,6 +2124,60 @@ func _TaprootAssets_FetchAssetMeta_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TaprootAssets_SearchAssetsByMeta_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAssetsByMetaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).SearchAssetsByMeta(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/SearchAssetsByMeta",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).SearchAssetsByMeta(ctx, req.(*SearchAssetsByMetaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_GetTransferMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransferMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).GetTransferMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/GetTransferMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).GetTransferMetrics(ctx, req.(*GetTransferMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaprootAssets_AnchorSweepStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnchorSweepStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaprootAssetsServer).AnchorSweepStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/taprpc.TaprootAssets/AnchorSweepStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaprootAssetsServer).AnchorSweepStatus(ctx, req.(*AnchorSweepStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TaprootAssets_ServiceDesc is the grpc.ServiceDesc for TaprootAssets service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -815,18 +2193,38 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListUtxos",
 			Handler:    _TaprootAssets_ListUtxos_Handler,
 		},
+		{
+			MethodName: "ListOnChainFootprint",
+			Handler:    _TaprootAssets_ListOnChainFootprint_Handler,
+		},
 		{
 			MethodName: "ListGroups",
 			Handler:    _TaprootAssets_ListGroups_Handler,
 		},
+		{
+			MethodName: "VerifySupplyConsistency",
+			Handler:    _TaprootAssets_VerifySupplyConsistency_Handler,
+		},
 		{
 			MethodName: "ListBalances",
 			Handler:    _TaprootAssets_ListBalances_Handler,
 		},
+		{
+			MethodName: "RebuildBalances",
+			Handler:    _TaprootAssets_RebuildBalances_Handler,
+		},
 		{
 			MethodName: "ListTransfers",
 			Handler:    _TaprootAssets_ListTransfers_Handler,
 		},
+		{
+			MethodName: "ListTransfersByScriptKey",
+			Handler:    _TaprootAssets_ListTransfersByScriptKey_Handler,
+		},
+		{
+			MethodName: "GetTransferGraph",
+			Handler:    _TaprootAssets_GetTransferGraph_Handler,
+		},
 		{
 			MethodName: "StopDaemon",
 			Handler:    _TaprootAssets_StopDaemon_Handler,
@@ -835,6 +2233,10 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DebugLevel",
 			Handler:    _TaprootAssets_DebugLevel_Handler,
 		},
+		{
+			MethodName: "BackupDatabase",
+			Handler:    _TaprootAssets_BackupDatabase_Handler,
+		},
 		{
 			MethodName: "QueryAddrs",
 			Handler:    _TaprootAssets_QueryAddrs_Handler,
@@ -843,6 +2245,10 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NewAddr",
 			Handler:    _TaprootAssets_NewAddr_Handler,
 		},
+		{
+			MethodName: "NewAddrBatch",
+			Handler:    _TaprootAssets_NewAddrBatch_Handler,
+		},
 		{
 			MethodName: "DecodeAddr",
 			Handler:    _TaprootAssets_DecodeAddr_Handler,
@@ -859,14 +2265,70 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DecodeProof",
 			Handler:    _TaprootAssets_DecodeProof_Handler,
 		},
+		{
+			MethodName: "CheckProofCompatibility",
+			Handler:    _TaprootAssets_CheckProofCompatibility_Handler,
+		},
 		{
 			MethodName: "ExportProof",
 			Handler:    _TaprootAssets_ExportProof_Handler,
 		},
+		{
+			MethodName: "MergeProofFiles",
+			Handler:    _TaprootAssets_MergeProofFiles_Handler,
+		},
+		{
+			MethodName: "GetSplitCommitment",
+			Handler:    _TaprootAssets_GetSplitCommitment_Handler,
+		},
+		{
+			MethodName: "GetAnchorMerkleProof",
+			Handler:    _TaprootAssets_GetAnchorMerkleProof_Handler,
+		},
+		{
+			MethodName: "ListDeliveryReceipts",
+			Handler:    _TaprootAssets_ListDeliveryReceipts_Handler,
+		},
+		{
+			MethodName: "PauseProofDelivery",
+			Handler:    _TaprootAssets_PauseProofDelivery_Handler,
+		},
+		{
+			MethodName: "ResumeProofDelivery",
+			Handler:    _TaprootAssets_ResumeProofDelivery_Handler,
+		},
+		{
+			MethodName: "ListDeadLetterProofs",
+			Handler:    _TaprootAssets_ListDeadLetterProofs_Handler,
+		},
 		{
 			MethodName: "SendAsset",
 			Handler:    _TaprootAssets_SendAsset_Handler,
 		},
+		{
+			MethodName: "PrepareTransfer",
+			Handler:    _TaprootAssets_PrepareTransfer_Handler,
+		},
+		{
+			MethodName: "BroadcastTransfer",
+			Handler:    _TaprootAssets_BroadcastTransfer_Handler,
+		},
+		{
+			MethodName: "ExportPendingTransfer",
+			Handler:    _TaprootAssets_ExportPendingTransfer_Handler,
+		},
+		{
+			MethodName: "ImportPendingTransfer",
+			Handler:    _TaprootAssets_ImportPendingTransfer_Handler,
+		},
+		{
+			MethodName: "BumpTransferFee",
+			Handler:    _TaprootAssets_BumpTransferFee_Handler,
+		},
+		{
+			MethodName: "CancelTransfer",
+			Handler:    _TaprootAssets_CancelTransfer_Handler,
+		},
 		{
 			MethodName: "BurnAsset",
 			Handler:    _TaprootAssets_BurnAsset_Handler,
@@ -875,17 +2337,57 @@ var TaprootAssets_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetInfo",
 			Handler:    _TaprootAssets_GetInfo_Handler,
 		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _TaprootAssets_GetConfig_Handler,
+		},
+		{
+			MethodName: "GetSendStateMachine",
+			Handler:    _TaprootAssets_GetSendStateMachine_Handler,
+		},
 		{
 			MethodName: "FetchAssetMeta",
 			Handler:    _TaprootAssets_FetchAssetMeta_Handler,
 		},
+		{
+			MethodName: "SearchAssetsByMeta",
+			Handler:    _TaprootAssets_SearchAssetsByMeta_Handler,
+		},
+		{
+			MethodName: "GetTransferMetrics",
+			Handler:    _TaprootAssets_GetTransferMetrics_Handler,
+		},
+		{
+			MethodName: "AnchorSweepStatus",
+			Handler:    _TaprootAssets_AnchorSweepStatus_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeAssets",
+			Handler:       _TaprootAssets_SubscribeAssets_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportProofsBatch",
+			Handler:       _TaprootAssets_ExportProofsBatch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportProofStream",
+			Handler:       _TaprootAssets_ExportProofStream_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeSendAssetEventNtfns",
 			Handler:       _TaprootAssets_SubscribeSendAssetEventNtfns_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeReceiveAssetEventNtfns",
+			Handler:       _TaprootAssets_SubscribeReceiveAssetEventNtfns_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "taprootassets.proto",
 }
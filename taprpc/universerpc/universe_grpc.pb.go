@@ -26,10 +26,23 @@ type UniverseClient interface {
 	// QueryAssetRoots attempts to locate the current Universe root for a specific
 	// asset. This asset can be identified by its asset ID or group key.
 	QueryAssetRoots(ctx context.Context, in *AssetRootQuery, opts ...grpc.CallOption) (*QueryRootResponse, error)
+	// tapcli: `universe roots --authoritative`
+	// ListAuthoritativeAssets lists the Universe roots this server is the
+	// issuance origin of, meaning the assets of the universe were minted by
+	// this server. These universes are served authoritatively, while all other
+	// roots are mirrored from other servers.
+	ListAuthoritativeAssets(ctx context.Context, in *ListAuthoritativeAssetsRequest, opts ...grpc.CallOption) (*ListAuthoritativeAssetsResponse, error)
 	// tapcli: `universe delete`
 	// DeleteAssetRoot deletes the Universe root for a specific asset, including
 	// all asoociated universe keys, leaves, and events.
 	DeleteAssetRoot(ctx context.Context, in *DeleteRootQuery, opts ...grpc.CallOption) (*DeleteRootResponse, error)
+	// tapcli: `universe prune`
+	// PruneUniverse removes the proofs of a given universe, or of all known
+	// universes, that were confirmed in a block below the given height. The
+	// universe and multiverse roots are updated accordingly, so the pruned
+	// leaves are no longer advertised to syncing peers. The number of removed
+	// proofs is returned.
+	PruneUniverse(ctx context.Context, in *PruneUniverseRequest, opts ...grpc.CallOption) (*PruneUniverseResponse, error)
 	// tapcli: `universe keys`
 	// AssetLeafKeys queries for the set of Universe keys associated with a given
 	// asset_id or group_key. Each key takes the form: (outpoint, script_key),
@@ -53,6 +66,11 @@ type UniverseClient interface {
 	// to the known Universe root, as well as a Taproot Asset state transition or
 	// issuance proof for the said asset.
 	QueryProof(ctx context.Context, in *UniverseKey, opts ...grpc.CallOption) (*AssetProofResponse, error)
+	// QueryProofs attempts to query for the issuance or transfer proofs of a set
+	// of leaves of a single Universe at once. All leaves must exist in the
+	// Universe, otherwise an error is returned. This is used by the syncer to
+	// efficiently fetch the leaves that are missing locally.
+	QueryProofs(ctx context.Context, in *QueryProofsRequest, opts ...grpc.CallOption) (*QueryProofsResponse, error)
 	// tapcli: `universe proofs insert`
 	// InsertProof attempts to insert a new issuance or transfer proof into the
 	// Universe tree specified by the UniverseKey. If valid, then the proof is
@@ -67,12 +85,33 @@ type UniverseClient interface {
 	// attempts to synchronize either only the set of specified asset_ids, or all
 	// assets if none are specified. The sync process will attempt to query for
 	// the latest known root for each asset, performing tree based reconciliation
-	// to arrive at a new shared root.
+	// to arrive at a new shared root. If no host is specified, the default
+	// Universe server is used.
 	SyncUniverse(ctx context.Context, in *SyncRequest, opts ...grpc.CallOption) (*SyncResponse, error)
+	// tapcli: `universe sync estimate`
+	// EstimateSync estimates how much data a sync with a remote Universe server
+	// would transfer and how long it would take, without actually syncing. The
+	// universes are diffed just like in a sync, and a small sample of the
+	// missing leaf proofs is fetched to estimate their size and fetch time. If
+	// no host is specified, the default Universe server is used.
+	EstimateSync(ctx context.Context, in *EstimateSyncRequest, opts ...grpc.CallOption) (*EstimateSyncResponse, error)
+	// tapcli: `universe default set`
+	// SetDefaultUniverse sets the Universe server that is used as the sync target
+	// if a sync request doesn't specify one. The server must be reachable to be
+	// accepted. An empty host clears the default. The default set through this
+	// call is persisted across restarts and takes precedence over the configured
+	// default server.
+	SetDefaultUniverse(ctx context.Context, in *SetDefaultUniverseRequest, opts ...grpc.CallOption) (*SetDefaultUniverseResponse, error)
+	// tapcli: `universe default get`
+	// GetDefaultUniverse returns the Universe server that is used as the sync
+	// target if a sync request doesn't specify one.
+	GetDefaultUniverse(ctx context.Context, in *GetDefaultUniverseRequest, opts ...grpc.CallOption) (*GetDefaultUniverseResponse, error)
 	// tapcli: `universe federation list`
 	// ListFederationServers lists the set of servers that make up the federation
 	// of the local Universe server. This servers are used to push out new proofs,
-	// and also periodically call sync new proofs from the remote server.
+	// and also periodically call sync new proofs from the remote server. The
+	// time of the last successful sync and the error of the last failed sync are
+	// reported for each server.
 	ListFederationServers(ctx context.Context, in *ListFederationServersRequest, opts ...grpc.CallOption) (*ListFederationServersResponse, error)
 	// tapcli: `universe federation add`
 	// AddFederationServer adds a new server to the federation of the local
@@ -83,10 +122,28 @@ type UniverseClient interface {
 	// DeleteFederationServer removes a server from the federation of the local
 	// Universe server.
 	DeleteFederationServer(ctx context.Context, in *DeleteFederationServerRequest, opts ...grpc.CallOption) (*DeleteFederationServerResponse, error)
+	// tapcli: `universe federation backlog`
+	// ListFederationPushBacklog lists the proof pushes to each member of the
+	// federation that failed and are queued to be retried. The failed pushes of
+	// minted assets to the default Universe server are listed as well.
+	ListFederationPushBacklog(ctx context.Context, in *ListFederationPushBacklogRequest, opts ...grpc.CallOption) (*ListFederationPushBacklogResponse, error)
+	// SubscribeIssuancePushEvents subscribes to the results of pushing the
+	// issuance proofs of minted assets to the federation and the default
+	// Universe server. Events are only published if minted assets are
+	// registered automatically with the default Universe server.
+	SubscribeIssuancePushEvents(ctx context.Context, in *SubscribeIssuancePushEventsRequest, opts ...grpc.CallOption) (Universe_SubscribeIssuancePushEventsClient, error)
+	// tapcli: `universe federation syncstatus`
+	// GetFederationSyncStatus reports, for each member of the federation, the
+	// last successful sync, the number of universe roots the member has that the
+	// local Universe server doesn't and an estimate of how far the local
+	// Universe server lags behind the member. It also reports the push
+	// concurrency limit and the number of proof pushes currently in flight.
+	GetFederationSyncStatus(ctx context.Context, in *GetFederationSyncStatusRequest, opts ...grpc.CallOption) (*GetFederationSyncStatusResponse, error)
 	// tapcli: `universe stats`
 	// UniverseStats returns a set of aggregate statistics for the current state
 	// of the Universe. Stats returned include: total number of syncs, total
-	// number of proofs, and total number of known assets.
+	// number of proofs, total number of known assets and groups, and the number
+	// of syncs and proofs of the last 24 hours.
 	UniverseStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	// tapcli `universe stats assets`
 	// QueryAssetStats returns a set of statistics for a given set of assets.
@@ -132,6 +189,15 @@ func (c *universeClient) QueryAssetRoots(ctx context.Context, in *AssetRootQuery
 	return out, nil
 }
 
+func (c *universeClient) ListAuthoritativeAssets(ctx context.Context, in *ListAuthoritativeAssetsRequest, opts ...grpc.CallOption) (*ListAuthoritativeAssetsResponse, error) {
+	out := new(ListAuthoritativeAssetsResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/ListAuthoritativeAssets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *universeClient) DeleteAssetRoot(ctx context.Context, in *DeleteRootQuery, opts ...grpc.CallOption) (*DeleteRootResponse, error) {
 	out := new(DeleteRootResponse)
 	err := c.cc.Invoke(ctx, "/universerpc.Universe/DeleteAssetRoot", in, out, opts...)
@@ -141,6 +207,15 @@ func (c *universeClient) DeleteAssetRoot(ctx context.Context, in *DeleteRootQuer
 	return out, nil
 }
 
+func (c *universeClient) PruneUniverse(ctx context.Context, in *PruneUniverseRequest, opts ...grpc.CallOption) (*PruneUniverseResponse, error) {
+	out := new(PruneUniverseResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/PruneUniverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *universeClient) AssetLeafKeys(ctx context.Context, in *ID, opts ...grpc.CallOption) (*AssetLeafKeyResponse, error) {
 	out := new(AssetLeafKeyResponse)
 	err := c.cc.Invoke(ctx, "/universerpc.Universe/AssetLeafKeys", in, out, opts...)
@@ -168,6 +243,15 @@ func (c *universeClient) QueryProof(ctx context.Context, in *UniverseKey, opts .
 	return out, nil
 }
 
+func (c *universeClient) QueryProofs(ctx context.Context, in *QueryProofsRequest, opts ...grpc.CallOption) (*QueryProofsResponse, error) {
+	out := new(QueryProofsResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/QueryProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *universeClient) InsertProof(ctx context.Context, in *AssetProof, opts ...grpc.CallOption) (*AssetProofResponse, error) {
 	out := new(AssetProofResponse)
 	err := c.cc.Invoke(ctx, "/universerpc.Universe/InsertProof", in, out, opts...)
@@ -195,6 +279,33 @@ func (c *universeClient) SyncUniverse(ctx context.Context, in *SyncRequest, opts
 	return out, nil
 }
 
+func (c *universeClient) EstimateSync(ctx context.Context, in *EstimateSyncRequest, opts ...grpc.CallOption) (*EstimateSyncResponse, error) {
+	out := new(EstimateSyncResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/EstimateSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *universeClient) SetDefaultUniverse(ctx context.Context, in *SetDefaultUniverseRequest, opts ...grpc.CallOption) (*SetDefaultUniverseResponse, error) {
+	out := new(SetDefaultUniverseResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/SetDefaultUniverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *universeClient) GetDefaultUniverse(ctx context.Context, in *GetDefaultUniverseRequest, opts ...grpc.CallOption) (*GetDefaultUniverseResponse, error) {
+	out := new(GetDefaultUniverseResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/GetDefaultUniverse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *universeClient) ListFederationServers(ctx context.Context, in *ListFederationServersRequest, opts ...grpc.CallOption) (*ListFederationServersResponse, error) {
 	out := new(ListFederationServersResponse)
 	err := c.cc.Invoke(ctx, "/universerpc.Universe/ListFederationServers", in, out, opts...)
@@ -222,6 +333,56 @@ func (c *universeClient) DeleteFederationServer(ctx context.Context, in *DeleteF
 	return out, nil
 }
 
+func (c *universeClient) ListFederationPushBacklog(ctx context.Context, in *ListFederationPushBacklogRequest, opts ...grpc.CallOption) (*ListFederationPushBacklogResponse, error) {
+	out := new(ListFederationPushBacklogResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/ListFederationPushBacklog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *universeClient) SubscribeIssuancePushEvents(ctx context.Context, in *SubscribeIssuancePushEventsRequest, opts ...grpc.CallOption) (Universe_SubscribeIssuancePushEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Universe_ServiceDesc.Streams[0], "/universerpc.Universe/SubscribeIssuancePushEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &universeSubscribeIssuancePushEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Universe_SubscribeIssuancePushEventsClient interface {
+	Recv() (*IssuancePushEvent, error)
+	grpc.ClientStream
+}
+
+type universeSubscribeIssuancePushEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *universeSubscribeIssuancePushEventsClient) Recv() (*IssuancePushEvent, error) {
+	m := new(IssuancePushEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *universeClient) GetFederationSyncStatus(ctx context.Context, in *GetFederationSyncStatusRequest, opts ...grpc.CallOption) (*GetFederationSyncStatusResponse, error) {
+	out := new(GetFederationSyncStatusResponse)
+	err := c.cc.Invoke(ctx, "/universerpc.Universe/GetFederationSyncStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *universeClient) UniverseStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
 	out := new(StatsResponse)
 	err := c.cc.Invoke(ctx, "/universerpc.Universe/UniverseStats", in, out, opts...)
@@ -279,10 +440,23 @@ type UniverseServer interface {
 	// QueryAssetRoots attempts to locate the current Universe root for a specific
 	// asset. This asset can be identified by its asset ID or group key.
 	QueryAssetRoots(context.Context, *AssetRootQuery) (*QueryRootResponse, error)
+	// tapcli: `universe roots --authoritative`
+	// ListAuthoritativeAssets lists the Universe roots this server is the
+	// issuance origin of, meaning the assets of the universe were minted by
+	// this server. These universes are served authoritatively, while all other
+	// roots are mirrored from other servers.
+	ListAuthoritativeAssets(context.Context, *ListAuthoritativeAssetsRequest) (*ListAuthoritativeAssetsResponse, error)
 	// tapcli: `universe delete`
 	// DeleteAssetRoot deletes the Universe root for a specific asset, including
 	// all asoociated universe keys, leaves, and events.
 	DeleteAssetRoot(context.Context, *DeleteRootQuery) (*DeleteRootResponse, error)
+	// tapcli: `universe prune`
+	// PruneUniverse removes the proofs of a given universe, or of all known
+	// universes, that were confirmed in a block below the given height. The
+	// universe and multiverse roots are updated accordingly, so the pruned
+	// leaves are no longer advertised to syncing peers. The number of removed
+	// proofs is returned.
+	PruneUniverse(context.Context, *PruneUniverseRequest) (*PruneUniverseResponse, error)
 	// tapcli: `universe keys`
 	// AssetLeafKeys queries for the set of Universe keys associated with a given
 	// asset_id or group_key. Each key takes the form: (outpoint, script_key),
@@ -306,6 +480,11 @@ type UniverseServer interface {
 	// to the known Universe root, as well as a Taproot Asset state transition or
 	// issuance proof for the said asset.
 	QueryProof(context.Context, *UniverseKey) (*AssetProofResponse, error)
+	// QueryProofs attempts to query for the issuance or transfer proofs of a set
+	// of leaves of a single Universe at once. All leaves must exist in the
+	// Universe, otherwise an error is returned. This is used by the syncer to
+	// efficiently fetch the leaves that are missing locally.
+	QueryProofs(context.Context, *QueryProofsRequest) (*QueryProofsResponse, error)
 	// tapcli: `universe proofs insert`
 	// InsertProof attempts to insert a new issuance or transfer proof into the
 	// Universe tree specified by the UniverseKey. If valid, then the proof is
@@ -320,12 +499,33 @@ type UniverseServer interface {
 	// attempts to synchronize either only the set of specified asset_ids, or all
 	// assets if none are specified. The sync process will attempt to query for
 	// the latest known root for each asset, performing tree based reconciliation
-	// to arrive at a new shared root.
+	// to arrive at a new shared root. If no host is specified, the default
+	// Universe server is used.
 	SyncUniverse(context.Context, *SyncRequest) (*SyncResponse, error)
+	// tapcli: `universe sync estimate`
+	// EstimateSync estimates how much data a sync with a remote Universe server
+	// would transfer and how long it would take, without actually syncing. The
+	// universes are diffed just like in a sync, and a small sample of the
+	// missing leaf proofs is fetched to estimate their size and fetch time. If
+	// no host is specified, the default Universe server is used.
+	EstimateSync(context.Context, *EstimateSyncRequest) (*EstimateSyncResponse, error)
+	// tapcli: `universe default set`
+	// SetDefaultUniverse sets the Universe server that is used as the sync target
+	// if a sync request doesn't specify one. The server must be reachable to be
+	// accepted. An empty host clears the default. The default set through this
+	// call is persisted across restarts and takes precedence over the configured
+	// default server.
+	SetDefaultUniverse(context.Context, *SetDefaultUniverseRequest) (*SetDefaultUniverseResponse, error)
+	// tapcli: `universe default get`
+	// GetDefaultUniverse returns the Universe server that is used as the sync
+	// target if a sync request doesn't specify one.
+	GetDefaultUniverse(context.Context, *GetDefaultUniverseRequest) (*GetDefaultUniverseResponse, error)
 	// tapcli: `universe federation list`
 	// ListFederationServers lists the set of servers that make up the federation
 	// of the local Universe server. This servers are used to push out new proofs,
-	// and also periodically call sync new proofs from the remote server.
+	// and also periodically call sync new proofs from the remote server. The
+	// time of the last successful sync and the error of the last failed sync are
+	// reported for each server.
 	ListFederationServers(context.Context, *ListFederationServersRequest) (*ListFederationServersResponse, error)
 	// tapcli: `universe federation add`
 	// AddFederationServer adds a new server to the federation of the local
@@ -336,10 +536,28 @@ type UniverseServer interface {
 	// DeleteFederationServer removes a server from the federation of the local
 	// Universe server.
 	DeleteFederationServer(context.Context, *DeleteFederationServerRequest) (*DeleteFederationServerResponse, error)
+	// tapcli: `universe federation backlog`
+	// ListFederationPushBacklog lists the proof pushes to each member of the
+	// federation that failed and are queued to be retried. The failed pushes of
+	// minted assets to the default Universe server are listed as well.
+	ListFederationPushBacklog(context.Context, *ListFederationPushBacklogRequest) (*ListFederationPushBacklogResponse, error)
+	// SubscribeIssuancePushEvents subscribes to the results of pushing the
+	// issuance proofs of minted assets to the federation and the default
+	// Universe server. Events are only published if minted assets are
+	// registered automatically with the default Universe server.
+	SubscribeIssuancePushEvents(*SubscribeIssuancePushEventsRequest, Universe_SubscribeIssuancePushEventsServer) error
+	// tapcli: `universe federation syncstatus`
+	// GetFederationSyncStatus reports, for each member of the federation, the
+	// last successful sync, the number of universe roots the member has that the
+	// local Universe server doesn't and an estimate of how far the local
+	// Universe server lags behind the member. It also reports the push
+	// concurrency limit and the number of proof pushes currently in flight.
+	GetFederationSyncStatus(context.Context, *GetFederationSyncStatusRequest) (*GetFederationSyncStatusResponse, error)
 	// tapcli: `universe stats`
 	// UniverseStats returns a set of aggregate statistics for the current state
 	// of the Universe. Stats returned include: total number of syncs, total
-	// number of proofs, and total number of known assets.
+	// number of proofs, total number of known assets and groups, and the number
+	// of syncs and proofs of the last 24 hours.
 	UniverseStats(context.Context, *StatsRequest) (*StatsResponse, error)
 	// tapcli `universe stats assets`
 	// QueryAssetStats returns a set of statistics for a given set of assets.
@@ -370,9 +588,15 @@ func (UnimplementedUniverseServer) AssetRoots(context.Context, *AssetRootRequest
 func (UnimplementedUniverseServer) QueryAssetRoots(context.Context, *AssetRootQuery) (*QueryRootResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryAssetRoots not implemented")
 }
+func (UnimplementedUniverseServer) ListAuthoritativeAssets(context.Context, *ListAuthoritativeAssetsRequest) (*ListAuthoritativeAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuthoritativeAssets not implemented")
+}
 func (UnimplementedUniverseServer) DeleteAssetRoot(context.Context, *DeleteRootQuery) (*DeleteRootResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAssetRoot not implemented")
 }
+func (UnimplementedUniverseServer) PruneUniverse(context.Context, *PruneUniverseRequest) (*PruneUniverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneUniverse not implemented")
+}
 func (UnimplementedUniverseServer) AssetLeafKeys(context.Context, *ID) (*AssetLeafKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AssetLeafKeys not implemented")
 }
@@ -382,6 +606,9 @@ func (UnimplementedUniverseServer) AssetLeaves(context.Context, *ID) (*AssetLeaf
 func (UnimplementedUniverseServer) QueryProof(context.Context, *UniverseKey) (*AssetProofResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryProof not implemented")
 }
+func (UnimplementedUniverseServer) QueryProofs(context.Context, *QueryProofsRequest) (*QueryProofsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryProofs not implemented")
+}
 func (UnimplementedUniverseServer) InsertProof(context.Context, *AssetProof) (*AssetProofResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method InsertProof not implemented")
 }
@@ -391,6 +618,15 @@ func (UnimplementedUniverseServer) Info(context.Context, *InfoRequest) (*InfoRes
 func (UnimplementedUniverseServer) SyncUniverse(context.Context, *SyncRequest) (*SyncResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SyncUniverse not implemented")
 }
+func (UnimplementedUniverseServer) EstimateSync(context.Context, *EstimateSyncRequest) (*EstimateSyncResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateSync not implemented")
+}
+func (UnimplementedUniverseServer) SetDefaultUniverse(context.Context, *SetDefaultUniverseRequest) (*SetDefaultUniverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultUniverse not implemented")
+}
+func (UnimplementedUniverseServer) GetDefaultUniverse(context.Context, *GetDefaultUniverseRequest) (*GetDefaultUniverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDefaultUniverse not implemented")
+}
 func (UnimplementedUniverseServer) ListFederationServers(context.Context, *ListFederationServersRequest) (*ListFederationServersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListFederationServers not implemented")
 }
@@ -400,6 +636,15 @@ func (UnimplementedUniverseServer) AddFederationServer(context.Context, *AddFede
 func (UnimplementedUniverseServer) DeleteFederationServer(context.Context, *DeleteFederationServerRequest) (*DeleteFederationServerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteFederationServer not implemented")
 }
+func (UnimplementedUniverseServer) ListFederationPushBacklog(context.Context, *ListFederationPushBacklogRequest) (*ListFederationPushBacklogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFederationPushBacklog not implemented")
+}
+func (UnimplementedUniverseServer) SubscribeIssuancePushEvents(*SubscribeIssuancePushEventsRequest, Universe_SubscribeIssuancePushEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeIssuancePushEvents not implemented")
+}
+func (UnimplementedUniverseServer) GetFederationSyncStatus(context.Context, *GetFederationSyncStatusRequest) (*GetFederationSyncStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFederationSyncStatus not implemented")
+}
 func (UnimplementedUniverseServer) UniverseStats(context.Context, *StatsRequest) (*StatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UniverseStats not implemented")
 }
@@ -464,6 +709,24 @@ func _Universe_QueryAssetRoots_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Universe_ListAuthoritativeAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuthoritativeAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).ListAuthoritativeAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/ListAuthoritativeAssets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).ListAuthoritativeAssets(ctx, req.(*ListAuthoritativeAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Universe_DeleteAssetRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteRootQuery)
 	if err := dec(in); err != nil {
@@ -482,6 +745,24 @@ func _Universe_DeleteAssetRoot_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Universe_PruneUniverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneUniverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).PruneUniverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/PruneUniverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).PruneUniverse(ctx, req.(*PruneUniverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Universe_AssetLeafKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ID)
 	if err := dec(in); err != nil {
@@ -536,6 +817,24 @@ func _Universe_QueryProof_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Universe_QueryProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryProofsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).QueryProofs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/QueryProofs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).QueryProofs(ctx, req.(*QueryProofsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Universe_InsertProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AssetProof)
 	if err := dec(in); err != nil {
@@ -590,6 +889,60 @@ func _Universe_SyncUniverse_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Universe_EstimateSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).EstimateSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/EstimateSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).EstimateSync(ctx, req.(*EstimateSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Universe_SetDefaultUniverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultUniverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).SetDefaultUniverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/SetDefaultUniverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).SetDefaultUniverse(ctx, req.(*SetDefaultUniverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Universe_GetDefaultUniverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDefaultUniverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).GetDefaultUniverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/GetDefaultUniverse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).GetDefaultUniverse(ctx, req.(*GetDefaultUniverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Universe_ListFederationServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListFederationServersRequest)
 	if err := dec(in); err != nil {
@@ -644,6 +997,63 @@ func _Universe_DeleteFederationServer_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Universe_ListFederationPushBacklog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFederationPushBacklogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).ListFederationPushBacklog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/ListFederationPushBacklog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).ListFederationPushBacklog(ctx, req.(*ListFederationPushBacklogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Universe_SubscribeIssuancePushEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeIssuancePushEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UniverseServer).SubscribeIssuancePushEvents(m, &universeSubscribeIssuancePushEventsServer{stream})
+}
+
+type Universe_SubscribeIssuancePushEventsServer interface {
+	Send(*IssuancePushEvent) error
+	grpc.ServerStream
+}
+
+type universeSubscribeIssuancePushEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *universeSubscribeIssuancePushEventsServer) Send(m *IssuancePushEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Universe_GetFederationSyncStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFederationSyncStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UniverseServer).GetFederationSyncStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/universerpc.Universe/GetFederationSyncStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UniverseServer).GetFederationSyncStatus(ctx, req.(*GetFederationSyncStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Universe_UniverseStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StatsRequest)
 	if err := dec(in); err != nil {
@@ -749,10 +1159,18 @@ var Universe_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryAssetRoots",
 			Handler:    _Universe_QueryAssetRoots_Handler,
 		},
+		{
+			MethodName: "ListAuthoritativeAssets",
+			Handler:    _Universe_ListAuthoritativeAssets_Handler,
+		},
 		{
 			MethodName: "DeleteAssetRoot",
 			Handler:    _Universe_DeleteAssetRoot_Handler,
 		},
+		{
+			MethodName: "PruneUniverse",
+			Handler:    _Universe_PruneUniverse_Handler,
+		},
 		{
 			MethodName: "AssetLeafKeys",
 			Handler:    _Universe_AssetLeafKeys_Handler,
@@ -765,6 +1183,10 @@ var Universe_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryProof",
 			Handler:    _Universe_QueryProof_Handler,
 		},
+		{
+			MethodName: "QueryProofs",
+			Handler:    _Universe_QueryProofs_Handler,
+		},
 		{
 			MethodName: "InsertProof",
 			Handler:    _Universe_InsertProof_Handler,
@@ -777,6 +1199,18 @@ var Universe_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SyncUniverse",
 			Handler:    _Universe_SyncUniverse_Handler,
 		},
+		{
+			MethodName: "EstimateSync",
+			Handler:    _Universe_EstimateSync_Handler,
+		},
+		{
+			MethodName: "SetDefaultUniverse",
+			Handler:    _Universe_SetDefaultUniverse_Handler,
+		},
+		{
+			MethodName: "GetDefaultUniverse",
+			Handler:    _Universe_GetDefaultUniverse_Handler,
+		},
 		{
 			MethodName: "ListFederationServers",
 			Handler:    _Universe_ListFederationServers_Handler,
@@ -789,6 +1223,14 @@ var Universe_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFederationServer",
 			Handler:    _Universe_DeleteFederationServer_Handler,
 		},
+		{
+			MethodName: "ListFederationPushBacklog",
+			Handler:    _Universe_ListFederationPushBacklog_Handler,
+		},
+		{
+			MethodName: "GetFederationSyncStatus",
+			Handler:    _Universe_GetFederationSyncStatus_Handler,
+		},
 		{
 			MethodName: "UniverseStats",
 			Handler:    _Universe_UniverseStats_Handler,
@@ -810,6 +1252,12 @@ var Universe_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _Universe_QueryFederationSyncConfig_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeIssuancePushEvents",
+			Handler:       _Universe_SubscribeIssuancePushEvents_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "universerpc/universe.proto",
 }
@@ -0,0 +1,111 @@
+package tapscript
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+)
+
+// AnchorOutput describes an output of an anchor transaction that carries
+// assets, before the asset level commitments of the output are created.
+type AnchorOutput struct {
+	// Value is the value of the output.
+	Value btcutil.Amount
+
+	// InternalKey is the Taproot internal key of the output.
+	InternalKey *btcec.PublicKey
+
+	// VOutputs are the virtual outputs that are anchored in the output.
+	VOutputs []*tappsbt.VOutput
+}
+
+// AnchorOutputSorter decides the order of the asset carrying outputs of an
+// anchor transaction.
+type AnchorOutputSorter interface {
+	// SortAnchorOutputs sorts the given anchor outputs in place, into the
+	// order they should have in the anchor transaction.
+	SortAnchorOutputs(outputs []*AnchorOutput)
+}
+
+// BIP69AnchorOutputSorter sorts anchor outputs by the rules of BIP-0069, by
+// value first and then by output script. The output key of an asset carrying
+// output commits to the index of the output, since the split commitments of
+// the assets include it. So the final output script can't be known before the
+// order is decided, and the P2TR script of the internal key is used instead.
+type BIP69AnchorOutputSorter struct{}
+
+// SortAnchorOutputs sorts the given anchor outputs in place.
+//
+// NOTE: This is part of the AnchorOutputSorter interface.
+func (BIP69AnchorOutputSorter) SortAnchorOutputs(outputs []*AnchorOutput) {
+	sort.SliceStable(outputs, func(i, j int) bool {
+		if outputs[i].Value != outputs[j].Value {
+			return outputs[i].Value < outputs[j].Value
+		}
+
+		// All P2TR scripts share the same prefix, so comparing the
+		// x-only keys is equivalent to comparing the scripts.
+		return bytes.Compare(
+			schnorr.SerializePubKey(outputs[i].InternalKey),
+			schnorr.SerializePubKey(outputs[j].InternalKey),
+		) < 0
+	})
+}
+
+// A compile-time assertion to ensure BIP69AnchorOutputSorter meets the
+// AnchorOutputSorter interface.
+var _ AnchorOutputSorter = (*BIP69AnchorOutputSorter)(nil)
+
+// SortAnchorOutputs re-assigns the anchor output indexes of the virtual outputs
+// of the given packet in the order decided by the sorter. Virtual outputs that
+// share an anchor output keep sharing it. This must be called before
+// PrepareOutputAssets, as the split commitments created there commit to the
+// anchor output index of each split. The anchor output indexes of the packet
+// must start at zero and be continuous, and all outputs must have an internal
+// key set.
+func SortAnchorOutputs(vPkt *tappsbt.VPacket, sorter AnchorOutputSorter) error {
+	anchors := make(map[uint32]*AnchorOutput)
+	for _, vOut := range vPkt.Outputs {
+		if vOut.AnchorOutputInternalKey == nil {
+			return fmt.Errorf("output with anchor index %d has no "+
+				"internal key", vOut.AnchorOutputIndex)
+		}
+
+		anchor, ok := anchors[vOut.AnchorOutputIndex]
+		if !ok {
+			anchor = &AnchorOutput{
+				Value:       DummyAmtSats,
+				InternalKey: vOut.AnchorOutputInternalKey,
+			}
+			anchors[vOut.AnchorOutputIndex] = anchor
+		}
+
+		anchor.VOutputs = append(anchor.VOutputs, vOut)
+	}
+
+	// We only re-order outputs that are all created by us, so there can't
+	// be any gaps for outputs that are added by someone else.
+	sorted := make([]*AnchorOutput, len(anchors))
+	for idx, anchor := range anchors {
+		if idx >= uint32(len(sorted)) {
+			return ErrInvalidOutputIndexes
+		}
+
+		sorted[idx] = anchor
+	}
+
+	sorter.SortAnchorOutputs(sorted)
+
+	for idx, anchor := range sorted {
+		for _, vOut := range anchor.VOutputs {
+			vOut.AnchorOutputIndex = uint32(idx)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package tapscript
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+)
+
+// SignatureLeaf returns the tapscript leaf that can be satisfied with a
+// signature of the given key alone: <key> OP_CHECKSIG.
+func SignatureLeaf(key *btcec.PublicKey) (txscript.TapLeaf, error) {
+	script, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(key)).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	if err != nil {
+		return txscript.TapLeaf{}, err
+	}
+
+	return txscript.NewBaseTapLeaf(script), nil
+}
+
+// SignatureLeafIndex returns the index of the leaf of the given tapscript tree
+// that can be satisfied with a signature of the given key alone. False is
+// returned if there is no such leaf.
+func SignatureLeafIndex(leaves []txscript.TapLeaf,
+	key *btcec.PublicKey) (int, bool) {
+
+	sigLeaf, err := SignatureLeaf(key)
+	if err != nil {
+		return 0, false
+	}
+
+	for idx, leaf := range leaves {
+		if leaf.LeafVersion == sigLeaf.LeafVersion &&
+			bytes.Equal(leaf.Script, sigLeaf.Script) {
+
+			return idx, true
+		}
+	}
+
+	return 0, false
+}
+
+// PrepareScriptSpend sets the fields of the given virtual input that instruct
+// the signer to spend the input asset through the script path of the leaf
+// with the given index, instead of the key spend path. The leaves must be the
+// full tapscript tree the script key of the input asset commits to.
+func PrepareScriptSpend(vIn *tappsbt.VInput, leaves []txscript.TapLeaf,
+	leafIdx int) error {
+
+	scriptKey := vIn.Asset().ScriptKey
+	if scriptKey.TweakedScriptKey == nil {
+		return fmt.Errorf("input script key has no internal key")
+	}
+
+	if leafIdx < 0 || leafIdx >= len(leaves) {
+		return fmt.Errorf("invalid leaf index %d for tree with %d "+
+			"leaves", leafIdx, len(leaves))
+	}
+
+	tree := txscript.AssembleTaprootScriptTree(leaves...)
+	root := tree.RootNode.TapHash()
+	if !bytes.Equal(root[:], scriptKey.Tweak) {
+		return fmt.Errorf("tapscript tree root %x doesn't match "+
+			"script key tweak %x", root[:], scriptKey.Tweak)
+	}
+
+	leaf := leaves[leafIdx]
+	leafHash := leaf.TapHash()
+	merkleProof := tree.LeafMerkleProofs[tree.LeafProofIndex[leafHash]]
+	controlBlock := merkleProof.ToControlBlock(scriptKey.RawKey.PubKey)
+	controlBlockBytes, err := controlBlock.ToBytes()
+	if err != nil {
+		return fmt.Errorf("unable to serialize control block: %w", err)
+	}
+
+	return PrepareLeafSpend(vIn, leaf, controlBlockBytes)
+}
+
+// PrepareLeafSpend sets the fields of the given virtual input that instruct
+// the signer to spend the input asset through the script path of the given
+// leaf, which can be any script. The control block must prove that the leaf is
+// committed to by the script key of the input asset. Only the leaf is known,
+// so the rest of the tapscript tree doesn't need to be known by the wallet.
+func PrepareLeafSpend(vIn *tappsbt.VInput, leaf txscript.TapLeaf,
+	controlBlockBytes []byte) error {
+
+	scriptKey := vIn.Asset().ScriptKey
+	if scriptKey.TweakedScriptKey == nil {
+		return fmt.Errorf("input script key has no internal key")
+	}
+
+	controlBlock, err := txscript.ParseControlBlock(controlBlockBytes)
+	if err != nil {
+		return fmt.Errorf("invalid control block: %w", err)
+	}
+
+	if controlBlock.LeafVersion != leaf.LeafVersion {
+		return fmt.Errorf("control block leaf version %v doesn't "+
+			"match leaf version %v", controlBlock.LeafVersion,
+			leaf.LeafVersion)
+	}
+
+	internalKey := schnorr.SerializePubKey(controlBlock.InternalKey)
+	rawKey := schnorr.SerializePubKey(scriptKey.RawKey.PubKey)
+	if !bytes.Equal(internalKey, rawKey) {
+		return fmt.Errorf("control block internal key %x doesn't "+
+			"match script key internal key %x", internalKey, rawKey)
+	}
+
+	root := controlBlock.RootHash(leaf.Script)
+	if !bytes.Equal(root, scriptKey.Tweak) {
+		return fmt.Errorf("tapscript tree root %x doesn't match "+
+			"script key tweak %x", root, scriptKey.Tweak)
+	}
+
+	leafHash := leaf.TapHash()
+	vIn.TaprootMerkleRoot = root
+	vIn.TaprootLeafScript = []*psbt.TaprootTapLeafScript{{
+		ControlBlock: controlBlockBytes,
+		Script:       leaf.Script,
+		LeafVersion:  leaf.LeafVersion,
+	}}
+	for _, derivation := range vIn.TaprootBip32Derivation {
+		derivation.LeafHashes = [][]byte{leafHash[:]}
+	}
+
+	return nil
+}
+
+// SetScriptSpendWitness sets the witness stack that satisfies the leaf script
+// of a virtual input that was prepared for a script path spend. The stack
+// excludes the leaf script and the control block, which are appended to form
+// the final witness of the input asset. An input with a final witness isn't
+// signed by the wallet, which allows spending leaves that require something
+// else than a signature of the script key's internal key.
+func SetScriptSpendWitness(vIn *tappsbt.VInput, stack wire.TxWitness) error {
+	if len(vIn.TaprootLeafScript) != 1 {
+		return fmt.Errorf("input isn't prepared for a script path " +
+			"spend")
+	}
+
+	leafScript := vIn.TaprootLeafScript[0]
+	witness := make(wire.TxWitness, 0, len(stack)+2)
+	witness = append(witness, stack...)
+	witness = append(witness, leafScript.Script, leafScript.ControlBlock)
+
+	var b bytes.Buffer
+	if err := psbt.WriteTxWitness(&b, witness); err != nil {
+		return fmt.Errorf("unable to encode witness: %w", err)
+	}
+	vIn.FinalScriptWitness = b.Bytes()
+
+	return nil
+}
+
+// FinalWitness returns the final witness of the given virtual input that was
+// set by SetScriptSpendWitness. False is returned if the input has no final
+// witness and must be signed.
+func FinalWitness(vIn *tappsbt.VInput) (wire.TxWitness, bool, error) {
+	if len(vIn.FinalScriptWitness) == 0 {
+		return nil, false, nil
+	}
+
+	r := bytes.NewReader(vIn.FinalScriptWitness)
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode witness: %w",
+			err)
+	}
+
+	// Every item takes at least one byte, which bounds the number of items
+	// we need to allocate for.
+	if numItems > uint64(len(vIn.FinalScriptWitness)) {
+		return nil, false, fmt.Errorf("invalid witness item count %d",
+			numItems)
+	}
+
+	witness := make(wire.TxWitness, 0, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		item, err := wire.ReadVarBytes(
+			r, 0, uint32(len(vIn.FinalScriptWitness)),
+			"witness item",
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to decode "+
+				"witness: %w", err)
+		}
+		witness = append(witness, item)
+	}
+
+	return witness, true, nil
+}
@@ -0,0 +1,280 @@
+package tapscript_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	tap "github.com/lightninglabs/taproot-assets"
+	"github.com/lightninglabs/taproot-assets/address"
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/commitment"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/tappsbt"
+	"github.com/lightninglabs/taproot-assets/tapscript"
+	"github.com/lightninglabs/taproot-assets/vm"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScriptPathSpend tests that an asset locked to a script key that
+// commits to a tapscript tree can be spent through one of its leaves, and that
+// the VM enforces the committed tapscript.
+func TestScriptPathSpend(t *testing.T) {
+	t.Parallel()
+
+	privKey := test.RandPrivKey(t)
+	rawKey := keychain.KeyDescriptor{PubKey: privKey.PubKey()}
+
+	sigLeaf, err := tapscript.SignatureLeaf(rawKey.PubKey)
+	require.NoError(t, err)
+	otherLeaf, err := tapscript.SignatureLeaf(test.RandPubKey(t))
+	require.NoError(t, err)
+
+	tree := asset.NewTapscriptTreeFromLeaves([][]byte{
+		otherLeaf.Script, sigLeaf.Script,
+	})
+	require.NoError(t, tree.Validate())
+
+	leafIdx, ok := tapscript.SignatureLeafIndex(tree.Leaves, rawKey.PubKey)
+	require.True(t, ok)
+	require.Equal(t, 1, leafIdx)
+
+	scriptKey := tree.ScriptKey(rawKey)
+	root := tree.Root()
+	require.Equal(t, root[:], scriptKey.Tweak)
+
+	// A tree that only carries its root commits to the same script key.
+	rootOnly := &asset.TapscriptTree{RootHash: &root}
+	require.NoError(t, rootOnly.Validate())
+	require.Equal(t, scriptKey.PubKey, rootOnly.ScriptKey(rawKey).PubKey)
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	inputAsset := asset.NewAssetNoErr(
+		t, genesis, 10, 0, 0, scriptKey, nil,
+	)
+	prevID := asset.PrevID{
+		ID:        inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(scriptKey.PubKey),
+	}
+	prevAssets := commitment.InputSet{prevID: inputAsset}
+
+	// newPacket creates a packet that sends the full value of the input
+	// asset to a random key, spending it through the given leaf.
+	newPacket := func(leafIdx int) *tappsbt.VPacket {
+		receiverKey := test.RandPubKey(t)
+		receiverScriptKey := asset.NewScriptKey(receiverKey)
+		vPkt := &tappsbt.VPacket{
+			Inputs: []*tappsbt.VInput{{
+				PrevID: prevID,
+			}},
+			Outputs: []*tappsbt.VOutput{{
+				Interactive:             true,
+				Amount:                  inputAsset.Amount,
+				ScriptKey:               receiverScriptKey,
+				AnchorOutputInternalKey: receiverKey,
+			}},
+			ChainParams: &address.RegressionNetTap,
+		}
+		vPkt.SetInputAsset(0, inputAsset, nil)
+
+		err := tapscript.PrepareOutputAssets(
+			context.Background(), vPkt,
+		)
+		require.NoError(t, err)
+
+		err = tapscript.PrepareScriptSpend(
+			vPkt.Inputs[0], tree.Leaves, leafIdx,
+		)
+		require.NoError(t, err)
+
+		return vPkt
+	}
+
+	validator := &tap.ValidatorV0{}
+
+	// A spend with a valid script path witness is accepted.
+	vPkt := newPacket(leafIdx)
+	err = tapscript.SignVirtualTransaction(
+		vPkt, tapscript.NewMockSigner(privKey), validator,
+	)
+	require.NoError(t, err)
+
+	newAsset := vPkt.Outputs[0].Asset
+	witness := newAsset.PrevWitnesses[0].TxWitness
+	require.Len(t, witness, 3)
+	require.Equal(t, sigLeaf.Script, witness[1])
+	require.NoError(t, validator.Execute(newAsset, nil, prevAssets))
+
+	// Revealing a leaf that isn't committed to by the script key is
+	// rejected.
+	invalidAsset := newAsset.Copy()
+	uncommittedLeaf, err := tapscript.SignatureLeaf(test.RandPubKey(t))
+	require.NoError(t, err)
+	invalidAsset.PrevWitnesses[0].TxWitness = wire.TxWitness{
+		witness[0], uncommittedLeaf.Script, witness[2],
+	}
+	err = validator.Execute(invalidAsset, nil, prevAssets)
+	requireInvalidWitness(t, err)
+
+	// A leaf that requires a signature of a different key can't be
+	// satisfied by the script key's internal key.
+	vPkt = newPacket(0)
+	err = tapscript.SignVirtualTransaction(
+		vPkt, tapscript.NewMockSigner(privKey), validator,
+	)
+	requireInvalidWitness(t, err)
+
+	// Leaves that don't match the script key's tweak are refused.
+	vPkt = &tappsbt.VPacket{ChainParams: &address.RegressionNetTap}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+	err = tapscript.PrepareScriptSpend(
+		vPkt.Inputs[0], []txscript.TapLeaf{sigLeaf}, 0,
+	)
+	require.ErrorContains(t, err, "doesn't match script key tweak")
+}
+
+// TestLeafSpendWithWitness tests that an asset locked to a script key that
+// commits to a tapscript tree can be spent through a leaf that requires more
+// than a signature, with a witness supplied by the caller.
+func TestLeafSpendWithWitness(t *testing.T) {
+	t.Parallel()
+
+	privKey := test.RandPrivKey(t)
+	rawKey := keychain.KeyDescriptor{PubKey: privKey.PubKey()}
+
+	// The hash lock leaf can be spent by anyone who knows the preimage.
+	preimage := test.RandBytes(32)
+	hash := sha256.Sum256(preimage)
+	hashLockScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_SHA256).
+		AddData(hash[:]).
+		AddOp(txscript.OP_EQUAL).
+		Script()
+	require.NoError(t, err)
+
+	sigLeaf, err := tapscript.SignatureLeaf(rawKey.PubKey)
+	require.NoError(t, err)
+	hashLockLeaf := txscript.NewBaseTapLeaf(hashLockScript)
+
+	tree := asset.NewTapscriptTreeFromLeaves([][]byte{
+		sigLeaf.Script, hashLockScript,
+	})
+	require.NoError(t, tree.Validate())
+	scriptKey := tree.ScriptKey(rawKey)
+
+	// The wallet only needs to know the leaf and its control block, not
+	// the full tree.
+	scriptTree := txscript.AssembleTaprootScriptTree(tree.Leaves...)
+	hashLockHash := hashLockLeaf.TapHash()
+	proofIdx := scriptTree.LeafProofIndex[hashLockHash]
+	merkleProof := scriptTree.LeafMerkleProofs[proofIdx]
+	controlBlock, err := merkleProof.ToControlBlock(
+		rawKey.PubKey,
+	).ToBytes()
+	require.NoError(t, err)
+
+	genesis := asset.RandGenesis(t, asset.Normal)
+	inputAsset := asset.NewAssetNoErr(
+		t, genesis, 10, 0, 0, scriptKey, nil,
+	)
+	prevID := asset.PrevID{
+		ID:        inputAsset.ID(),
+		ScriptKey: asset.ToSerialized(scriptKey.PubKey),
+	}
+
+	newPacket := func() *tappsbt.VPacket {
+		receiverKey := test.RandPubKey(t)
+		vPkt := &tappsbt.VPacket{
+			Inputs: []*tappsbt.VInput{{
+				PrevID: prevID,
+			}},
+			Outputs: []*tappsbt.VOutput{{
+				Interactive: true,
+				Amount:      inputAsset.Amount,
+				ScriptKey: asset.NewScriptKey(
+					receiverKey,
+				),
+				AnchorOutputInternalKey: receiverKey,
+			}},
+			ChainParams: &address.RegressionNetTap,
+		}
+		vPkt.SetInputAsset(0, inputAsset, nil)
+
+		err := tapscript.PrepareOutputAssets(
+			context.Background(), vPkt,
+		)
+		require.NoError(t, err)
+
+		err = tapscript.PrepareLeafSpend(
+			vPkt.Inputs[0], hashLockLeaf, controlBlock,
+		)
+		require.NoError(t, err)
+
+		return vPkt
+	}
+
+	// The signer must not be asked for a signature, as the input carries
+	// a final witness.
+	signer := tapscript.NewMockSigner(test.RandPrivKey(t))
+	validator := &tap.ValidatorV0{}
+
+	// A spend with the correct preimage is accepted.
+	vPkt := newPacket()
+	err = tapscript.SetScriptSpendWitness(
+		vPkt.Inputs[0], wire.TxWitness{preimage},
+	)
+	require.NoError(t, err)
+
+	finalWitness, ok, err := tapscript.FinalWitness(vPkt.Inputs[0])
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, wire.TxWitness{
+		preimage, hashLockScript, controlBlock,
+	}, finalWitness)
+
+	err = tapscript.SignVirtualTransaction(vPkt, signer, validator)
+	require.NoError(t, err)
+	require.Equal(
+		t, finalWitness,
+		vPkt.Outputs[0].Asset.PrevWitnesses[0].TxWitness,
+	)
+
+	// A spend with a wrong preimage is rejected by the VM.
+	vPkt = newPacket()
+	err = tapscript.SetScriptSpendWitness(
+		vPkt.Inputs[0], wire.TxWitness{test.RandBytes(32)},
+	)
+	require.NoError(t, err)
+	err = tapscript.SignVirtualTransaction(vPkt, signer, validator)
+	requireInvalidWitness(t, err)
+
+	// A control block that doesn't commit the leaf to the script key is
+	// refused.
+	vPkt = &tappsbt.VPacket{ChainParams: &address.RegressionNetTap}
+	vPkt.SetInputAsset(0, inputAsset, nil)
+	otherLeaf := txscript.NewBaseTapLeaf([]byte{txscript.OP_TRUE})
+	err = tapscript.PrepareLeafSpend(
+		vPkt.Inputs[0], otherLeaf, controlBlock,
+	)
+	require.ErrorContains(t, err, "doesn't match script key tweak")
+
+	// A witness can only be set for an input prepared for a script path
+	// spend.
+	err = tapscript.SetScriptSpendWitness(
+		vPkt.Inputs[0], wire.TxWitness{preimage},
+	)
+	require.ErrorContains(t, err, "isn't prepared for a script path")
+}
+
+// requireInvalidWitness asserts that the given error is a VM error caused by an
+// invalid transfer witness.
+func requireInvalidWitness(t *testing.T, err error) {
+	t.Helper()
+
+	var vmErr vm.Error
+	require.ErrorAs(t, err, &vmErr)
+	require.Equal(t, vm.ErrInvalidTransferWitness, vmErr.Kind)
+}
@@ -170,7 +170,14 @@ func DescribeRecipients(ctx context.Context, vPkt *tappsbt.VPacket,
 		GroupKey: groupPubKey,
 	}
 	for idx := range vPkt.Outputs {
-		desc.Amount += vPkt.Outputs[idx].Amount
+		amount, carry := bits.Add64(
+			desc.Amount, vPkt.Outputs[idx].Amount, 0,
+		)
+		if carry != 0 {
+			return nil, fmt.Errorf("total output amount uint64 " +
+				"overflow")
+		}
+		desc.Amount = amount
 	}
 
 	return desc, nil
@@ -189,7 +196,12 @@ func DescribeAddrs(addrs []*address.Tap) (*FundingDescriptor, error) {
 		GroupKey: firstAddr.GroupKey,
 	}
 	for idx := range addrs {
-		desc.Amount += addrs[idx].Amount
+		amount, carry := bits.Add64(desc.Amount, addrs[idx].Amount, 0)
+		if carry != 0 {
+			return nil, fmt.Errorf("total address amount uint64 " +
+				"overflow")
+		}
+		desc.Amount = amount
 	}
 
 	return desc, nil
@@ -265,7 +277,14 @@ func ValidateInputs(inputCommitments tappsbt.InputCommitments,
 		// Sum the total amount of the input assets.
 		var totalInputsAmount uint64
 		for _, inputAsset := range inputAssets {
-			totalInputsAmount += inputAsset.Amount
+			amount, carry := bits.Add64(
+				totalInputsAmount, inputAsset.Amount, 0,
+			)
+			if carry != 0 {
+				return false, fmt.Errorf("total input amount " +
+					"uint64 overflow")
+			}
+			totalInputsAmount = amount
 		}
 
 		// Ensure that the input assets are sufficient to cover the amount
@@ -622,6 +641,18 @@ func SignVirtualTransaction(vPkt *tappsbt.VPacket, signer Signer,
 	for idx := range inputs {
 		input := inputs[idx]
 
+		// An input with a final witness was satisfied by the caller
+		// and doesn't need to be signed. The VM below makes sure the
+		// witness is valid.
+		finalWitness, ok, err := FinalWitness(input)
+		if err != nil {
+			return err
+		}
+		if ok {
+			newAsset.PrevWitnesses[idx].TxWitness = finalWitness
+			continue
+		}
+
 		// For each input asset leaf, we need to produce a witness.
 		// Update the input of the virtual TX, generate a witness, and
 		// attach it to the copy of the new Asset.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"math"
 	"testing"
 
 	"github.com/btcsuite/btcd/blockchain"
@@ -1588,8 +1589,8 @@ var updateTaprootOutputKeysTestCases = []testCase{{
 }}
 
 func createSpend(t *testing.T, state *spendData, inputSet commitment.InputSet,
-	full bool) (*psbt.Packet, *tappsbt.VPacket,
-	[]*commitment.TapCommitment) {
+	full bool, sorter tapscript.AnchorOutputSorter) (*psbt.Packet,
+	*tappsbt.VPacket, []*commitment.TapCommitment) {
 
 	spendAddress := state.address1
 
@@ -1608,6 +1609,11 @@ func createSpend(t *testing.T, state *spendData, inputSet commitment.InputSet,
 	// correct outputs.
 	pkt.Outputs[1].AnchorOutputIndex = 1
 
+	if sorter != nil {
+		err := tapscript.SortAnchorOutputs(pkt, sorter)
+		require.NoError(t, err)
+	}
+
 	err := tapscript.PrepareOutputAssets(context.Background(), pkt)
 	require.NoError(t, err)
 	err = tapscript.SignVirtualTransaction(
@@ -1649,6 +1655,8 @@ func createProofParams(t *testing.T, genesisTxIn wire.TxIn, state spendData,
 
 	senderAsset := pkt.Outputs[0].Asset
 	receiverAsset := pkt.Outputs[1].Asset
+	senderIdx := pkt.Outputs[0].AnchorOutputIndex
+	receiverIdx := pkt.Outputs[1].AnchorOutputIndex
 	senderTapTree := outputCommitments[0]
 	receiverTapTree := outputCommitments[1]
 
@@ -1671,11 +1679,11 @@ func createProofParams(t *testing.T, genesisTxIn wire.TxIn, state spendData,
 			},
 			Tx:               spendTx,
 			TxIndex:          0,
-			OutputIndex:      0,
+			OutputIndex:      int(senderIdx),
 			InternalKey:      &state.spenderPubKey,
 			TaprootAssetRoot: senderTapTree,
 			ExclusionProofs: []proof.TaprootProof{{
-				OutputIndex: 1,
+				OutputIndex: receiverIdx,
 				InternalKey: &state.receiverPubKey,
 				CommitmentProof: &proof.CommitmentProof{
 					Proof: *senderExclusionProof,
@@ -1693,11 +1701,11 @@ func createProofParams(t *testing.T, genesisTxIn wire.TxIn, state spendData,
 			},
 			Tx:               spendTx,
 			TxIndex:          0,
-			OutputIndex:      1,
+			OutputIndex:      int(receiverIdx),
 			InternalKey:      &state.receiverPubKey,
 			TaprootAssetRoot: receiverTapTree,
 			ExclusionProofs: []proof.TaprootProof{{
-				OutputIndex: 0,
+				OutputIndex: senderIdx,
 				InternalKey: &state.spenderPubKey,
 				CommitmentProof: &proof.CommitmentProof{
 					Proof: *receiverExclusionProof,
@@ -1705,7 +1713,7 @@ func createProofParams(t *testing.T, genesisTxIn wire.TxIn, state spendData,
 			}},
 		},
 		NewAsset:             receiverAsset,
-		RootOutputIndex:      0,
+		RootOutputIndex:      senderIdx,
 		RootInternalKey:      &state.spenderPubKey,
 		RootTaprootAssetTree: senderTapTree,
 	}
@@ -1713,11 +1721,109 @@ func createProofParams(t *testing.T, genesisTxIn wire.TxIn, state spendData,
 	return []proof.TransitionParams{senderParams, receiverParams}
 }
 
+// reverseAnchorOutputSorter is an anchor output sorter that reverses the order
+// of the outputs.
+type reverseAnchorOutputSorter struct{}
+
+// SortAnchorOutputs reverses the order of the given anchor outputs.
+func (reverseAnchorOutputSorter) SortAnchorOutputs(
+	outputs []*tapscript.AnchorOutput) {
+
+	for i, j := 0, len(outputs)-1; i < j; i, j = i+1, j-1 {
+		outputs[i], outputs[j] = outputs[j], outputs[i]
+	}
+}
+
+// TestSortAnchorOutputs tests that the anchor output indexes of a virtual
+// packet are re-assigned in the order decided by the sorter.
+func TestSortAnchorOutputs(t *testing.T) {
+	t.Parallel()
+
+	keyA := test.RandPubKey(t)
+	keyB := test.RandPubKey(t)
+	if bytes.Compare(
+		schnorr.SerializePubKey(keyA), schnorr.SerializePubKey(keyB),
+	) > 0 {
+
+		keyA, keyB = keyB, keyA
+	}
+
+	vOut := func(idx uint32, key *btcec.PublicKey) *tappsbt.VOutput {
+		return &tappsbt.VOutput{
+			AnchorOutputIndex:       idx,
+			AnchorOutputInternalKey: key,
+		}
+	}
+
+	// Outputs that share an anchor must keep sharing it after sorting.
+	pkt := &tappsbt.VPacket{
+		Outputs: []*tappsbt.VOutput{
+			vOut(0, keyB), vOut(1, keyA), vOut(0, keyB),
+		},
+	}
+	err := tapscript.SortAnchorOutputs(
+		pkt, &tapscript.BIP69AnchorOutputSorter{},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pkt.Outputs[0].AnchorOutputIndex)
+	require.EqualValues(t, 0, pkt.Outputs[1].AnchorOutputIndex)
+	require.EqualValues(t, 1, pkt.Outputs[2].AnchorOutputIndex)
+
+	err = tapscript.SortAnchorOutputs(pkt, &reverseAnchorOutputSorter{})
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pkt.Outputs[0].AnchorOutputIndex)
+	require.EqualValues(t, 1, pkt.Outputs[1].AnchorOutputIndex)
+	require.EqualValues(t, 0, pkt.Outputs[2].AnchorOutputIndex)
+
+	// Gaps in the anchor output indexes can't be re-ordered.
+	pkt.Outputs[1].AnchorOutputIndex = 2
+	err = tapscript.SortAnchorOutputs(
+		pkt, &tapscript.BIP69AnchorOutputSorter{},
+	)
+	require.ErrorIs(t, err, tapscript.ErrInvalidOutputIndexes)
+
+	// All outputs need an internal key.
+	pkt.Outputs[1].AnchorOutputIndex = 1
+	pkt.Outputs[1].AnchorOutputInternalKey = nil
+	err = tapscript.SortAnchorOutputs(
+		pkt, &tapscript.BIP69AnchorOutputSorter{},
+	)
+	require.ErrorContains(t, err, "no internal key")
+}
+
 // TestProofVerify tests that a split spend can be used to append to a
-// proof file and produce a valid updated proof file.
+// proof file and produce a valid updated proof file, regardless of the order of
+// the anchor outputs.
 func TestProofVerify(t *testing.T) {
 	t.Parallel()
 
+	testCases := []struct {
+		name   string
+		sorter tapscript.AnchorOutputSorter
+	}{{
+		name: "default order",
+	}, {
+		name:   "bip69 order",
+		sorter: &tapscript.BIP69AnchorOutputSorter{},
+	}, {
+		name:   "reverse order",
+		sorter: &reverseAnchorOutputSorter{},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testProofVerify(t, tc.sorter)
+		})
+	}
+}
+
+// testProofVerify creates a split spend with the anchor outputs in the order
+// decided by the given sorter and verifies the resulting proofs.
+func testProofVerify(t *testing.T, sorter tapscript.AnchorOutputSorter) {
 	state := initSpendScenario(t)
 
 	// Create a proof for the genesis of asset 2.
@@ -1749,9 +1855,15 @@ func TestProofVerify(t *testing.T) {
 
 	// Perform a split spend of asset 2.
 	btcPkt, pkt, outputCommitments := createSpend(
-		t, &state, state.asset2InputAssets, false,
+		t, &state, state.asset2InputAssets, false, sorter,
 	)
 
+	// A reversed order must move the receiver to the first output.
+	if _, ok := sorter.(*reverseAnchorOutputSorter); ok {
+		require.EqualValues(t, 0, pkt.Outputs[1].AnchorOutputIndex)
+		require.EqualValues(t, 1, pkt.Outputs[0].AnchorOutputIndex)
+	}
+
 	genesisTxIn := wire.TxIn{PreviousOutPoint: *genesisOutPoint}
 
 	proofParams := createProofParams(
@@ -1822,7 +1934,7 @@ func TestProofVerifyFullValueSplit(t *testing.T) {
 	state.spenderScriptKey = *asset.NUMSPubKey
 
 	btcPkt, pkt, outputCommitments := createSpend(
-		t, &state, state.asset2InputAssets, true,
+		t, &state, state.asset2InputAssets, true, nil,
 	)
 
 	genesisTxIn := wire.TxIn{PreviousOutPoint: *genesisOutPoint}
@@ -2113,6 +2225,31 @@ var addressValidInputTestCases = []addressValidInputTestCase{{
 
 // TestPayToAddrScript tests edge cases around creating a P2TR script with
 // PayToAddrScript.
+// TestDescribeAddrsOverflow tests that addresses whose amounts overflow when
+// summed up are rejected instead of wrapping around to a small amount.
+func TestDescribeAddrsOverflow(t *testing.T) {
+	t.Parallel()
+
+	assetID := asset.RandID(t)
+	newAddr := func(amt uint64) *address.Tap {
+		return &address.Tap{
+			AssetID: assetID,
+			Amount:  amt,
+		}
+	}
+
+	desc, err := tapscript.DescribeAddrs([]*address.Tap{
+		newAddr(math.MaxUint64 - 1), newAddr(1),
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, uint64(math.MaxUint64), desc.Amount)
+
+	_, err = tapscript.DescribeAddrs([]*address.Tap{
+		newAddr(math.MaxUint64), newAddr(1),
+	})
+	require.ErrorContains(t, err, "overflow")
+}
+
 func TestPayToAddrScript(t *testing.T) {
 	t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -15,6 +16,14 @@ const (
 	// DefaultTimeout is the default timeout we use for RPC and database
 	// operations.
 	DefaultTimeout = 30 * time.Second
+
+	// DefaultMaxConcurrentPushes is the default maximum number of proof
+	// pushes to federation members that are in flight at the same time.
+	DefaultMaxConcurrentPushes = 16
+
+	// DefaultServerSyncTimeout is the default maximum time a sync with a
+	// single Universe server may take.
+	DefaultServerSyncTimeout = 30 * time.Minute
 )
 
 // FederationConfig is a config that the FederationEnvoy will use to
@@ -38,10 +47,41 @@ type FederationConfig struct {
 	// to the federation.
 	LocalRegistrar BatchRegistrar
 
+	// LocalDiffEngine is the diff engine tied to the local Universe
+	// instance. This'll be used to fetch the leaves of failed pushes that
+	// are retried.
+	LocalDiffEngine DiffEngine
+
 	// SyncInterval is the period that we'll use to synchronize with the
 	// set of Universe servers.
 	SyncInterval time.Duration
 
+	// PushRetryInitialBackoff is the time we'll wait before retrying a
+	// failed proof push to a federation member for the first time. The
+	// wait time is doubled with each failed attempt. This is also the
+	// interval at which we check for pushes that are due to be retried.
+	PushRetryInitialBackoff time.Duration
+
+	// PushRetryMaxBackoff is the maximum time we'll wait between two
+	// attempts to push a proof to a federation member.
+	PushRetryMaxBackoff time.Duration
+
+	// MaxPendingPushes is the maximum number of failed proof pushes we'll
+	// queue for retry per federation member. If zero, failed pushes aren't
+	// retried.
+	MaxPendingPushes int
+
+	// MaxConcurrentPushes is the maximum number of proof pushes to
+	// federation members that are in flight at the same time, across all
+	// members. If zero, DefaultMaxConcurrentPushes is used.
+	MaxConcurrentPushes int
+
+	// ServerSyncTimeout is the maximum time a sync with a single Universe
+	// server may take. A sync that takes longer is aborted, so a server
+	// that hangs doesn't hold up the sync with the remaining servers. If
+	// zero, DefaultServerSyncTimeout is used.
+	ServerSyncTimeout time.Duration
+
 	// ErrChan is the main error channel the custodian will report back
 	// critical errors to the main server.
 	ErrChan chan<- error
@@ -51,11 +91,74 @@ type FederationConfig struct {
 	// with.
 	StaticFederationMembers []string
 
+	// DefaultSyncServer is the optional Universe server that is used as
+	// the sync target if none is specified. It is also used for the
+	// periodic sync if the federation has no members. The default can be
+	// changed at runtime with SetDefaultServer. A default set at runtime is
+	// persisted and takes precedence over this one.
+	DefaultSyncServer string
+
 	// ServerChecker is a function that can be used to check if a server is
 	// operational and not the local daemon.
 	ServerChecker func(ServerAddr) error
+
+	// AutoRegisterMints indicates that the issuance proofs of locally
+	// minted assets should be published to the current default sync
+	// server as well. The default server doesn't become a federation
+	// member for this, failed pushes to it are queued for retry
+	// separately. An IssuancePushEvent is published for every push of a
+	// minted asset.
+	AutoRegisterMints bool
+}
+
+// IssuancePushEvent is an event that is published after the issuance proof of
+// a locally minted asset was pushed to a federation member, or the push
+// failed.
+type IssuancePushEvent struct {
+	timestamp time.Time
+
+	// Server is the federation member the proof was pushed to.
+	Server ServerAddr
+
+	// ID identifies the universe the proof was pushed to.
+	ID Identifier
+
+	// Key is the key of the pushed leaf within the universe.
+	Key LeafKey
+
+	// Error is the error the push failed with, or nil if it succeeded. A
+	// failed push is queued for retry, if retries are enabled.
+	Error error
+
+	// Retry is true if the push was a retry of a previously failed push.
+	Retry bool
+}
+
+// NewIssuancePushEvent creates a new IssuancePushEvent.
+func NewIssuancePushEvent(server ServerAddr, id Identifier, key LeafKey,
+	err error, retry bool) *IssuancePushEvent {
+
+	return &IssuancePushEvent{
+		timestamp: time.Now().UTC(),
+		Server:    server,
+		ID:        id,
+		Key:       key,
+		Error:     err,
+		Retry:     retry,
+	}
+}
+
+// Timestamp returns the timestamp of the event.
+//
+// NOTE: This is part of the fn.Event interface.
+func (e *IssuancePushEvent) Timestamp() time.Time {
+	return e.timestamp
 }
 
+// A compile-time assertion to ensure IssuancePushEvent meets the fn.Event
+// interface.
+var _ fn.Event = (*IssuancePushEvent)(nil)
+
 // FederationPushReq is used to push out new updates to all or some members of
 // the federation.
 type FederationPushReq struct {
@@ -98,14 +201,52 @@ type FederationEnvoy struct {
 	pushRequests chan *FederationPushReq
 
 	batchPushRequests chan *FederationIssuanceBatchPushReq
+
+	// retryActive is set while the queued pushes are being retried, to
+	// make sure we only ever have a single retry round in flight.
+	retryActive atomic.Bool
+
+	// pushSlots limits the number of proof pushes that are in flight at
+	// the same time. A slot is taken by sending on the channel.
+	pushSlots chan struct{}
+
+	// inFlightPushes is the number of proof pushes currently in flight.
+	inFlightPushes atomic.Int32
+
+	// defaultServer is the Universe server that is used as the sync target
+	// if none is specified. This is nil if there is no default.
+	defaultServer *ServerAddr
+
+	// defaultServerMtx guards defaultServer.
+	defaultServerMtx sync.RWMutex
+
+	// pushEvents distributes the IssuancePushEvents of minted assets to
+	// subscribers.
+	pushEvents *fn.EventDistributor[*IssuancePushEvent]
 }
 
 // NewFederationEnvoy creates a new federation envoy from the passed config.
 func NewFederationEnvoy(cfg FederationConfig) *FederationEnvoy {
+	var defaultServer *ServerAddr
+	if cfg.DefaultSyncServer != "" {
+		addr := NewServerAddrFromStr(cfg.DefaultSyncServer)
+		defaultServer = &addr
+	}
+
+	if cfg.MaxConcurrentPushes == 0 {
+		cfg.MaxConcurrentPushes = DefaultMaxConcurrentPushes
+	}
+	if cfg.ServerSyncTimeout == 0 {
+		cfg.ServerSyncTimeout = DefaultServerSyncTimeout
+	}
+
 	return &FederationEnvoy{
 		cfg:               cfg,
 		pushRequests:      make(chan *FederationPushReq),
 		batchPushRequests: make(chan *FederationIssuanceBatchPushReq),
+		pushSlots:         make(chan struct{}, cfg.MaxConcurrentPushes),
+		defaultServer:     defaultServer,
+		pushEvents:        fn.NewEventDistributor[*IssuancePushEvent](),
 		ContextGuard: &fn.ContextGuard{
 			DefaultTimeout: DefaultTimeout,
 			Quit:           make(chan struct{}),
@@ -147,6 +288,10 @@ func (f *FederationEnvoy) Start() error {
 			log.Warnf("Unable to add universe servers: %v", err)
 		}
 
+		// A default server that was set at runtime takes precedence
+		// over the configured one.
+		f.loadDefaultServer()
+
 		f.Wg.Add(1)
 
 		go f.syncer()
@@ -197,16 +342,15 @@ func (f *FederationEnvoy) syncServerState(ctx context.Context,
 		return err
 	}
 
-	if len(diff) == 0 {
-		return nil
-	}
-
 	// If we synced anything from the server, then we'll log that here.
-	log.Infof("Synced new Universe leaves from server=%v, diff_size=%v",
-		spew.Sdump(addr), len(diff))
+	if len(diff) > 0 {
+		log.Infof("Synced new Universe leaves from server=%v, "+
+			"diff_size=%v", spew.Sdump(addr), len(diff))
+	}
 
 	// Log a new sync event in the background now that we know we were able
-	// to contract the remote server.
+	// to contract the remote server. We do this even if there was nothing
+	// new to sync, so the last sync time reflects the last successful sync.
 	f.Wg.Add(1)
 	go func() {
 		defer f.Wg.Done()
@@ -223,10 +367,26 @@ func (f *FederationEnvoy) syncServerState(ctx context.Context,
 	return nil
 }
 
-// pushProofToFederation attempts to push out a new proof to the current
-// federation in parallel.
-func (f *FederationEnvoy) pushProofToFederation(uniID Identifier, key LeafKey,
-	leaf *Leaf) {
+// pushTarget is a universe server proofs are pushed to.
+type pushTarget struct {
+	// addr is the address of the server.
+	addr ServerAddr
+
+	// registration is true if the server is the default universe server
+	// the proofs of minted assets are registered with, rather than a
+	// federation member.
+	registration bool
+}
+
+// pushProofsToFederation attempts to push out new proofs to the current
+// federation. The proofs are pushed to each member in order, while the members
+// are handled in parallel, so a slow member doesn't hold up the others. Once a
+// push to a member fails, the remaining proofs for that member are queued for
+// retry right away. If register is set, the proofs are the issuance proofs of
+// minted assets that are also pushed to the default universe server, and an
+// IssuancePushEvent is published for the push of each proof to each server.
+func (f *FederationEnvoy) pushProofsToFederation(items []*IssuanceItem,
+	register bool) {
 
 	ctx, cancel := f.WithCtxQuit()
 	defer cancel()
@@ -241,46 +401,296 @@ func (f *FederationEnvoy) pushProofToFederation(uniID Identifier, key LeafKey,
 		return
 	}
 
-	if len(fedServers) == 0 {
+	targets := fn.Map(fedServers, func(addr ServerAddr) pushTarget {
+		return pushTarget{
+			addr: addr,
+		}
+	})
+
+	// Minted assets are registered with the default universe server as
+	// well, without making it a federation member.
+	regServer := f.registrationServer(fedServers)
+	if register && regServer != nil {
+		targets = append(targets, pushTarget{
+			addr:         *regServer,
+			registration: true,
+		})
+	}
+
+	if len(targets) == 0 || len(items) == 0 {
 		return
 	}
 
-	log.Infof("Pushing new proof to %v federation members, proof_key=%v",
-		len(fedServers), spew.Sdump(key))
+	log.Infof("Pushing %v new proofs to %v universe servers",
+		len(items), len(targets))
 
 	ctx, cancel = f.WithCtxQuitNoTimeout()
 	defer cancel()
 
-	// To push a new proof out, we'll attempt to dial to the remote
-	// registrar, then will attempt to push the new proof directly to the
-	// register.
-	pushNewProof := func(ctx context.Context, addr ServerAddr) error {
-		remoteUniverseServer, err := f.cfg.NewRemoteRegistrar(addr)
-		if err != nil {
-			log.Warnf("cannot push proof unable to connect "+
-				"to remote server(%v): %v", addr.HostStr(),
-				err)
-			return nil
+	// To push the new proofs out, we'll attempt to dial to the remote
+	// registrar, then will attempt to push each proof directly to the
+	// register. If that fails, we'll queue the push to be retried later
+	// on.
+	pushNewProofs := func(ctx context.Context, target pushTarget) error {
+		addr := target.addr
+
+		var pushErr error
+		for _, item := range items {
+			if pushErr == nil {
+				pushErr = f.pushProof(
+					ctx, addr, item.ID, item.Key, item.Leaf,
+				)
+				if pushErr != nil {
+					log.Warnf("cannot push proof to "+
+						"remote server(%v): %v",
+						addr.HostStr(), pushErr)
+				}
+			}
+
+			if pushErr != nil {
+				f.queueFailedPush(
+					ctx, addr, target.registration,
+					item.ID, item.Key, pushErr,
+				)
+			}
+
+			if register {
+				f.pushEvents.NotifySubscribers(
+					NewIssuancePushEvent(
+						addr, item.ID, item.Key,
+						pushErr, false,
+					),
+				)
+			}
 		}
 
-		_, err = remoteUniverseServer.RegisterIssuance(
-			ctx, uniID, key, leaf,
-		)
-		if err != nil {
-			log.Warnf("cannot push proof to remote "+
-				"server(%v): %v", addr.HostStr(), err)
+		return nil
+	}
+
+	// To conclude, we'll push the new proofs to all the universe servers
+	// in parallel. The number of pushes in flight is limited by pushProof,
+	// so we don't need to limit the number of servers here.
+	err = fn.ParSliceLimit(ctx, 0, targets, pushNewProofs)
+	if err != nil {
+		log.Errorf("unable to push proofs to federation: %v", err)
+		return
+	}
+}
+
+// pushProof attempts to push a single proof to the given remote universe
+// server. The push waits for a free push slot first, and is aborted if it
+// doesn't complete within the default timeout, so a slow member can't hold on
+// to a slot for long.
+func (f *FederationEnvoy) pushProof(ctx context.Context, addr ServerAddr,
+	uniID Identifier, key LeafKey, leaf *Leaf) error {
+
+	select {
+	case f.pushSlots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	f.inFlightPushes.Add(1)
+	defer func() {
+		f.inFlightPushes.Add(-1)
+		<-f.pushSlots
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	remoteUniverseServer, err := f.cfg.NewRemoteRegistrar(addr)
+	if err != nil {
+		return fmt.Errorf("unable to connect to remote server: %w",
+			err)
+	}
+
+	_, err = remoteUniverseServer.RegisterIssuance(ctx, uniID, key, leaf)
+	return err
+}
+
+// pushBackoff returns the time to wait after the given number of failed
+// attempts to push a proof.
+func (f *FederationEnvoy) pushBackoff(numAttempts uint32) time.Duration {
+	backoff := f.cfg.PushRetryInitialBackoff
+	for i := uint32(1); i < numAttempts; i++ {
+		backoff *= 2
+		if backoff >= f.cfg.PushRetryMaxBackoff {
+			return f.cfg.PushRetryMaxBackoff
 		}
+	}
+
+	return backoff
+}
+
+// registrationServer returns the default universe server if the proofs of
+// minted assets are registered with it automatically and it isn't one of the
+// given federation members, which receive the proofs anyway.
+func (f *FederationEnvoy) registrationServer(
+	fedServers []ServerAddr) *ServerAddr {
+
+	defaultServer := f.DefaultServer()
+	if !f.cfg.AutoRegisterMints || defaultServer == nil {
 		return nil
 	}
 
-	// To conclude, we'll attempt to push the new proof to all the universe
-	// servers in parallel.
-	err = fn.ParSlice(ctx, fedServers, pushNewProof)
+	for idx := range fedServers {
+		if fedServers[idx].HostStr() == defaultServer.HostStr() {
+			return nil
+		}
+	}
+
+	return defaultServer
+}
+
+// queueFailedPush persists a failed proof push to the given server, so it can
+// be retried later on. If registration is set, the server is the default
+// universe server the proofs of minted assets are registered with.
+func (f *FederationEnvoy) queueFailedPush(ctx context.Context, addr ServerAddr,
+	registration bool, uniID Identifier, key LeafKey, pushErr error) {
+
+	if f.cfg.MaxPendingPushes == 0 {
+		return
+	}
+
+	err := f.cfg.FederationDB.QueuePendingPush(ctx, &PendingPush{
+		Server:       addr,
+		Registration: registration,
+		UniverseID:   uniID,
+		LeafKey:      key,
+		NumAttempts:  1,
+		NextAttempt:  time.Now().Add(f.pushBackoff(1)),
+		LastError:    pushErr.Error(),
+	}, f.cfg.MaxPendingPushes)
+	switch {
+	case errors.Is(err, ErrPushQueueFull):
+		log.Warnf("Push queue for server(%v) is full, not retrying "+
+			"push of proof_key=%v", addr.HostStr(), spew.Sdump(key))
+
+	case err != nil:
+		log.Errorf("Unable to queue failed push to server(%v): %v",
+			addr.HostStr(), err)
+	}
+}
+
+// retryPendingPushes retries all queued proof pushes that are due. The pushes
+// to each server are retried in order, while the different servers are
+// handled in parallel. Once a retry to a server fails, the remaining pushes to
+// that server are left for the next round, so a slow server doesn't hold up
+// the others.
+func (f *FederationEnvoy) retryPendingPushes() {
+	ctx, cancel := f.WithCtxQuitNoTimeout()
+	defer cancel()
+
+	pushes, err := f.cfg.FederationDB.PendingPushes(ctx, time.Now())
 	if err != nil {
-		// TODO(roasbeef): retry in the background until successful?
-		log.Errorf("unable to push proof to federation: %v", err)
+		log.Errorf("Unable to fetch pending pushes: %v", err)
 		return
 	}
+
+	if len(pushes) == 0 {
+		return
+	}
+
+	log.Infof("Retrying %v pending proof pushes to federation members",
+		len(pushes))
+
+	// We group the pushes by host rather than by federation member, as
+	// registration pushes are queued for servers that aren't members.
+	pushesByServer := make(map[string][]*PendingPush)
+	for _, push := range pushes {
+		host := push.Server.HostStr()
+		pushesByServer[host] = append(pushesByServer[host], push)
+	}
+
+	serverPushes := make([][]*PendingPush, 0, len(pushesByServer))
+	for _, pushes := range pushesByServer {
+		serverPushes = append(serverPushes, pushes)
+	}
+
+	err = fn.ParSliceLimit(
+		ctx, 0, serverPushes,
+		func(ctx context.Context, pushes []*PendingPush) error {
+			for _, push := range pushes {
+				if !f.retryPush(ctx, push) {
+					break
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		log.Errorf("Unable to retry pending pushes: %v", err)
+	}
+}
+
+// retryPush retries a single queued proof push. The push is removed from the
+// queue if it succeeds, otherwise its next attempt is scheduled. False is
+// returned if the proof couldn't be pushed to the server.
+func (f *FederationEnvoy) retryPush(ctx context.Context,
+	push *PendingPush) bool {
+
+	proofs, err := f.cfg.LocalDiffEngine.FetchIssuanceProof(
+		ctx, push.UniverseID, push.LeafKey,
+	)
+	switch {
+	// If the proof is no longer in our local universe, there's nothing
+	// left to push.
+	case errors.Is(err, ErrNoUniverseProofFound) || (err == nil &&
+		len(proofs) == 0):
+
+		log.Warnf("Proof of pending push to server(%v) not found, "+
+			"removing push", push.Server.HostStr())
+
+		err := f.cfg.FederationDB.RemovePendingPush(ctx, push)
+		if err != nil {
+			log.Errorf("Unable to remove pending push: %v", err)
+		}
+		return true
+
+	case err != nil:
+		log.Errorf("Unable to fetch proof of pending push: %v", err)
+		return true
+	}
+
+	err = f.pushProof(
+		ctx, push.Server, push.UniverseID, push.LeafKey, proofs[0].Leaf,
+	)
+
+	// Only registration pushes are known to be for minted assets, so we
+	// publish the results of their retries.
+	if push.Registration {
+		f.pushEvents.NotifySubscribers(NewIssuancePushEvent(
+			push.Server, push.UniverseID, push.LeafKey, err, true,
+		))
+	}
+
+	if err == nil {
+		log.Infof("Pushed proof to server(%v) after %v failed attempts",
+			push.Server.HostStr(), push.NumAttempts)
+
+		err := f.cfg.FederationDB.RemovePendingPush(ctx, push)
+		if err != nil {
+			log.Errorf("Unable to remove pending push: %v", err)
+		}
+		return true
+	}
+
+	push.NumAttempts++
+	push.NextAttempt = time.Now().Add(f.pushBackoff(push.NumAttempts))
+	push.LastError = err.Error()
+
+	log.Debugf("Retrying push to server(%v) failed (attempt %v), next "+
+		"attempt at %v: %v", push.Server.HostStr(), push.NumAttempts,
+		push.NextAttempt, err)
+
+	err = f.cfg.FederationDB.UpdatePendingPush(ctx, push)
+	if err != nil {
+		log.Errorf("Unable to update pending push: %v", err)
+	}
+
+	return false
 }
 
 // syncer is the main goroutine that's responsible for interacting with the
@@ -296,6 +706,15 @@ func (f *FederationEnvoy) syncer() {
 	syncTicker := time.NewTicker(f.cfg.SyncInterval)
 	defer syncTicker.Stop()
 
+	// The retry ticker is only used if failed pushes are queued at all.
+	var retryTick <-chan time.Time
+	if f.cfg.MaxPendingPushes > 0 {
+		retryTicker := time.NewTicker(f.cfg.PushRetryInitialBackoff)
+		defer retryTicker.Stop()
+
+		retryTick = retryTicker.C
+	}
+
 	for {
 		select {
 		// A new sync event has just been triggered, so we'll attempt
@@ -317,6 +736,13 @@ func (f *FederationEnvoy) syncer() {
 			}
 			cancel()
 
+			// Without any federation members, we fall back to the
+			// default sync server, if one is set.
+			defaultServer := f.DefaultServer()
+			if len(fedServers) == 0 && defaultServer != nil {
+				fedServers = []ServerAddr{*defaultServer}
+			}
+
 			log.Infof("Synchronizing with %v federation members",
 				len(fedServers))
 			err = f.SyncServers(fedServers)
@@ -356,9 +782,27 @@ func (f *FederationEnvoy) syncer() {
 
 			// With the response sent above, we'll push this out to
 			// all the Universe servers in the background.
-			go f.pushProofToFederation(
-				pushReq.ID, pushReq.Key, pushReq.Leaf,
-			)
+			go f.pushProofsToFederation([]*IssuanceItem{{
+				ID:   pushReq.ID,
+				Key:  pushReq.Key,
+				Leaf: pushReq.Leaf,
+			}}, false)
+
+		// It's time to check whether any of the failed pushes are due
+		// to be retried. We do that in the background, unless the
+		// previous round is still running.
+		case <-retryTick:
+			if !f.retryActive.CompareAndSwap(false, true) {
+				continue
+			}
+
+			f.Wg.Add(1)
+			go func() {
+				defer f.Wg.Done()
+				defer f.retryActive.Store(false)
+
+				f.retryPendingPushes()
+			}()
 
 		case pushReq := <-f.batchPushRequests:
 			ctx, cancel := f.WithCtxQuitNoTimeout()
@@ -384,15 +828,13 @@ func (f *FederationEnvoy) syncer() {
 			pushReq.resp <- struct{}{}
 
 			// With the response sent above, we'll push this out to
-			// all the Universe servers in the background.
-			go func() {
-				for idx := range pushReq.IssuanceBatch {
-					item := pushReq.IssuanceBatch[idx]
-					f.pushProofToFederation(
-						item.ID, item.Key, item.Leaf,
-					)
-				}
-			}()
+			// all the Universe servers in the background. Issuance
+			// batches are only created by the minter, so we publish
+			// the push results if minted assets are registered
+			// automatically.
+			go f.pushProofsToFederation(
+				pushReq.IssuanceBatch, f.cfg.AutoRegisterMints,
+			)
 
 		case <-f.Quit:
 			return
@@ -463,6 +905,96 @@ func (f *FederationEnvoy) AddServer(addrs ...ServerAddr) error {
 	return f.SyncServers(addrs)
 }
 
+// loadDefaultServer replaces the configured default server with the one that
+// was last set at runtime, if any.
+func (f *FederationEnvoy) loadDefaultServer() {
+	ctx, cancel := f.WithCtxQuit()
+	defer cancel()
+
+	host, err := f.cfg.FederationDB.DefaultServer(ctx)
+	switch {
+	case errors.Is(err, ErrNoDefaultServer):
+		return
+
+	case err != nil:
+		log.Warnf("Unable to load default universe server: %v", err)
+		return
+	}
+
+	var addr *ServerAddr
+	if host != "" {
+		serverAddr := NewServerAddrFromStr(host)
+		addr = &serverAddr
+	}
+
+	f.defaultServerMtx.Lock()
+	f.defaultServer = addr
+	f.defaultServerMtx.Unlock()
+}
+
+// DefaultServer returns the Universe server that is used as the sync target if
+// none is specified. This returns nil if no default is set.
+func (f *FederationEnvoy) DefaultServer() *ServerAddr {
+	f.defaultServerMtx.RLock()
+	defer f.defaultServerMtx.RUnlock()
+
+	return f.defaultServer
+}
+
+// SetDefaultServer sets the Universe server that is used as the sync target if
+// none is specified. Passing nil clears the default. The default is persisted
+// and takes precedence over the configured one after a restart.
+func (f *FederationEnvoy) SetDefaultServer(ctx context.Context,
+	addr *ServerAddr) error {
+
+	f.defaultServerMtx.Lock()
+	defer f.defaultServerMtx.Unlock()
+
+	var host string
+	if addr != nil {
+		host = addr.HostStr()
+	}
+
+	err := f.cfg.FederationDB.SetDefaultServer(ctx, host)
+	if err != nil {
+		return fmt.Errorf("unable to persist default server: %w", err)
+	}
+
+	f.defaultServer = addr
+
+	return nil
+}
+
+// RegisterPushSubscriber adds a new subscriber for the IssuancePushEvents of
+// minted assets.
+func (f *FederationEnvoy) RegisterPushSubscriber(
+	receiver *fn.EventReceiver[*IssuancePushEvent]) {
+
+	f.pushEvents.RegisterSubscriber(receiver)
+}
+
+// RemovePushSubscriber removes the given IssuancePushEvent subscriber and stops
+// it from processing events.
+func (f *FederationEnvoy) RemovePushSubscriber(
+	receiver *fn.EventReceiver[*IssuancePushEvent]) error {
+
+	return f.pushEvents.RemoveSubscriber(receiver)
+}
+
+// PendingPushes returns all failed proof pushes that are queued to be retried.
+func (f *FederationEnvoy) PendingPushes(
+	ctx context.Context) ([]*PendingPush, error) {
+
+	return f.cfg.FederationDB.PendingPushes(ctx, time.Time{})
+}
+
+// PushConcurrency returns the maximum number of proof pushes to federation
+// members that are in flight at the same time, and the number of pushes that
+// are currently in flight.
+func (f *FederationEnvoy) PushConcurrency() (int, int) {
+	return f.cfg.MaxConcurrentPushes, int(f.inFlightPushes.Load())
+}
+
 // QuerySyncConfigs returns the current sync configs for the federation.
 func (f *FederationEnvoy) QuerySyncConfigs(
 	ctx context.Context) (*SyncConfigs, error) {
@@ -481,8 +1013,14 @@ func (f *FederationEnvoy) QuerySyncConfigs(
 	}, nil
 }
 
+// SyncServers syncs the local Universe with each of the given servers. The
+// servers are synced one after another, so the leaves pulled from one server
+// are already part of the local Universe when the next server is diffed
+// against it, and aren't fetched a second time. Each sync is bounded by the
+// ServerSyncTimeout, so a server that hangs only delays the others by that
+// much. The error of each failed sync is logged in the federation DB, and
+// cleared again once a sync with the server succeeds.
 func (f *FederationEnvoy) SyncServers(serverAddrs []ServerAddr) error {
-	// Sync servers in parallel without context timeout.
 	ctx, cancel := f.WithCtxQuitNoTimeout()
 	defer cancel()
 
@@ -492,23 +1030,51 @@ func (f *FederationEnvoy) SyncServers(serverAddrs []ServerAddr) error {
 		return err
 	}
 
-	syncServer := func(ctx context.Context, serverAddr ServerAddr) error {
-		err := f.syncServerState(ctx, serverAddr, *syncConfigs)
+	for _, serverAddr := range serverAddrs {
+		// We don't start a new sync if we're shutting down.
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		err := f.syncServer(ctx, serverAddr, *syncConfigs)
 		if err != nil {
 			log.Warnf("encountered an error whilst syncing with "+
-				"server=%v: %w", spew.Sdump(serverAddr), err)
+				"server=%v: %v", serverAddr.HostStr(), err)
 		}
-		return nil
-	}
 
-	err = fn.ParSlice(ctx, serverAddrs, syncServer)
-	if err != nil {
-		log.Warnf("unable to sync with server: %w", err)
+		// If we were interrupted by a shutdown, the outcome doesn't
+		// tell us anything about the server.
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		logErr := f.cfg.FederationDB.LogSyncError(ctx, serverAddr, err)
+		if logErr != nil {
+			return fmt.Errorf("unable to log sync error: %w",
+				logErr)
+		}
 	}
 
 	return nil
 }
 
+// syncServer syncs the local Universe with the given server, aborting the sync
+// if it takes longer than the ServerSyncTimeout.
+func (f *FederationEnvoy) syncServer(ctx context.Context, addr ServerAddr,
+	syncConfigs SyncConfigs) error {
+
+	ctxt, cancel := context.WithTimeout(ctx, f.cfg.ServerSyncTimeout)
+	defer cancel()
+
+	err := f.syncServerState(ctxt, addr, syncConfigs)
+	if err != nil && errors.Is(ctxt.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("sync timed out after %v: %w",
+			f.cfg.ServerSyncTimeout, err)
+	}
+
+	return err
+}
+
 // SetAllowPublicAccess sets the global sync config to allow public access
 // for proof insert and export across all universes.
 func (f *FederationEnvoy) SetAllowPublicAccess() error {
@@ -0,0 +1,745 @@
+package universe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	memberServer  = "member.universe:10029"
+	defaultServer = "default.universe:10029"
+)
+
+var errPushFailed = errors.New("push failed")
+
+// mockFederationDB is a mock FederationDB that keeps the federation members,
+// sync errors, default server and queued pushes in memory. Only the methods
+// used by the envoy during a sync or push are implemented.
+type mockFederationDB struct {
+	FederationDB
+
+	mtx           sync.Mutex
+	servers       []ServerAddr
+	syncErrors    map[string]string
+	defaultServer *string
+	queued        []*PendingPush
+}
+
+func (m *mockFederationDB) UniverseServers(
+	context.Context) ([]ServerAddr, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return append([]ServerAddr(nil), m.servers...), nil
+}
+
+func (m *mockFederationDB) AddServers(_ context.Context,
+	addrs ...ServerAddr) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, addr := range addrs {
+		m.servers = append(m.servers, NewServerAddr(
+			int64(len(m.servers)+1), addr.HostStr(),
+		))
+	}
+
+	return nil
+}
+
+func (m *mockFederationDB) LogNewSyncs(context.Context, ...ServerAddr) error {
+	return nil
+}
+
+func (m *mockFederationDB) LogSyncError(_ context.Context, addr ServerAddr,
+	syncErr error) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.syncErrors == nil {
+		m.syncErrors = make(map[string]string)
+	}
+
+	if syncErr == nil {
+		delete(m.syncErrors, addr.HostStr())
+		return nil
+	}
+
+	m.syncErrors[addr.HostStr()] = syncErr.Error()
+
+	return nil
+}
+
+func (m *mockFederationDB) LastSyncErrors(
+	context.Context) (map[string]string, error) {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	syncErrors := make(map[string]string, len(m.syncErrors))
+	for host, syncErr := range m.syncErrors {
+		syncErrors[host] = syncErr
+	}
+
+	return syncErrors, nil
+}
+
+func (m *mockFederationDB) SetDefaultServer(_ context.Context,
+	host string) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.defaultServer = &host
+
+	return nil
+}
+
+func (m *mockFederationDB) DefaultServer(context.Context) (string, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.defaultServer == nil {
+		return "", ErrNoDefaultServer
+	}
+
+	return *m.defaultServer, nil
+}
+
+func (m *mockFederationDB) QueryFederationSyncConfigs(
+	context.Context) ([]*FedGlobalSyncConfig, []*FedUniSyncConfig, error) {
+
+	return nil, nil, nil
+}
+
+func (m *mockFederationDB) QueuePendingPush(_ context.Context,
+	push *PendingPush, _ int) error {
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.queued = append(m.queued, push)
+
+	return nil
+}
+
+func (m *mockFederationDB) queuedPushes() []*PendingPush {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return append([]*PendingPush(nil), m.queued...)
+}
+
+// mockSyncer is a Syncer that never finds anything to sync.
+type mockSyncer struct{}
+
+func (mockSyncer) SyncUniverse(context.Context, ServerAddr, SyncType,
+	SyncConfigs, ...Identifier) ([]AssetSyncDiff, error) {
+
+	return nil, nil
+}
+
+func (mockSyncer) EstimateSync(context.Context, ServerAddr, SyncType,
+	SyncConfigs, ...Identifier) (*SyncEstimate, error) {
+
+	return &SyncEstimate{}, nil
+}
+
+// mockRegistrar is a BatchRegistrar that accepts all proofs, unless an error
+// is set.
+type mockRegistrar struct {
+	err error
+}
+
+func (m *mockRegistrar) RegisterIssuance(context.Context, Identifier, LeafKey,
+	*Leaf) (*Proof, error) {
+
+	return nil, m.err
+}
+
+func (m *mockRegistrar) RegisterNewIssuanceBatch(context.Context,
+	[]*IssuanceItem) error {
+
+	return m.err
+}
+
+// newTestEnvoy creates a federation envoy with a single static member and a
+// default sync server. Pushes to the default sync server always fail.
+func newTestEnvoy(t *testing.T, autoRegister bool) (*FederationEnvoy,
+	*mockFederationDB) {
+
+	fedDB := &mockFederationDB{}
+	envoy := NewFederationEnvoy(FederationConfig{
+		FederationDB:   fedDB,
+		UniverseSyncer: mockSyncer{},
+		NewRemoteRegistrar: func(addr ServerAddr) (Registrar, error) {
+			if addr.HostStr() == defaultServer {
+				return &mockRegistrar{err: errPushFailed}, nil
+			}
+
+			return &mockRegistrar{}, nil
+		},
+		LocalRegistrar:          &mockRegistrar{},
+		SyncInterval:            time.Hour,
+		PushRetryInitialBackoff: time.Hour,
+		PushRetryMaxBackoff:     time.Hour,
+		MaxPendingPushes:        10,
+		ErrChan:                 make(chan error, 1),
+		StaticFederationMembers: []string{memberServer},
+		DefaultSyncServer:       defaultServer,
+		ServerChecker: func(ServerAddr) error {
+			return nil
+		},
+		AutoRegisterMints: autoRegister,
+	})
+
+	require.NoError(t, envoy.Start())
+	t.Cleanup(func() {
+		require.NoError(t, envoy.Stop())
+	})
+
+	return envoy, fedDB
+}
+
+// TestFederationEnvoyAutoRegisterMints tests that the issuance proofs of minted
+// assets are pushed to the default sync server and that the push results are
+// published if minted assets are registered automatically.
+func TestFederationEnvoyAutoRegisterMints(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	scriptKey := asset.NewScriptKey(test.RandPubKey(t))
+	item := &IssuanceItem{
+		ID: Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		},
+		Key: LeafKey{
+			ScriptKey: &scriptKey,
+		},
+		Leaf: &Leaf{},
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		envoy, fedDB := newTestEnvoy(t, true)
+
+		// The default sync server must not become a federation member.
+		servers, err := fedDB.UniverseServers(ctx)
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		require.Equal(t, memberServer, servers[0].HostStr())
+
+		receiver := fn.NewEventReceiver[*IssuancePushEvent](
+			fn.DefaultQueueSize,
+		)
+		envoy.RegisterPushSubscriber(receiver)
+		defer func() {
+			err := envoy.RemovePushSubscriber(receiver)
+			require.NoError(t, err)
+		}()
+
+		err = envoy.RegisterNewIssuanceBatch(
+			ctx, []*IssuanceItem{item},
+		)
+		require.NoError(t, err)
+
+		// We expect a successful push to the member and a failed push
+		// to the default sync server.
+		pushErrs := make(map[string]error)
+		for i := 0; i < 2; i++ {
+			select {
+			case event := <-receiver.NewItemCreated.ChanOut():
+				require.Equal(t, item.ID, event.ID)
+				require.False(t, event.Retry)
+				pushErrs[event.Server.HostStr()] = event.Error
+
+			case <-time.After(DefaultTimeout):
+				t.Fatalf("no push event received")
+			}
+		}
+		require.NoError(t, pushErrs[memberServer])
+		require.ErrorIs(t, pushErrs[defaultServer], errPushFailed)
+
+		// The failed push must be queued for retry as a registration
+		// push, as the default sync server isn't a member.
+		queued := fedDB.queuedPushes()
+		require.Len(t, queued, 1)
+		require.Equal(t, defaultServer, queued[0].Server.HostStr())
+		require.True(t, queued[0].Registration)
+	})
+
+	t.Run("default server is member", func(t *testing.T) {
+		t.Parallel()
+
+		envoy, fedDB := newTestEnvoy(t, true)
+
+		// If the default sync server is a federation member already,
+		// the proof is pushed to it only once, as a member.
+		defaultAddr := NewServerAddrFromStr(defaultServer)
+		require.NoError(t, envoy.AddServer(defaultAddr))
+
+		receiver := fn.NewEventReceiver[*IssuancePushEvent](
+			fn.DefaultQueueSize,
+		)
+		envoy.RegisterPushSubscriber(receiver)
+		defer func() {
+			err := envoy.RemovePushSubscriber(receiver)
+			require.NoError(t, err)
+		}()
+
+		err := envoy.RegisterNewIssuanceBatch(
+			ctx, []*IssuanceItem{item},
+		)
+		require.NoError(t, err)
+
+		numPushes := make(map[string]int)
+		for i := 0; i < 2; i++ {
+			select {
+			case event := <-receiver.NewItemCreated.ChanOut():
+				numPushes[event.Server.HostStr()]++
+
+			case <-time.After(DefaultTimeout):
+				t.Fatalf("no push event received")
+			}
+		}
+		require.Equal(t, 1, numPushes[memberServer])
+		require.Equal(t, 1, numPushes[defaultServer])
+
+		select {
+		case event := <-receiver.NewItemCreated.ChanOut():
+			t.Fatalf("unexpected push event: %v", event)
+
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		queued := fedDB.queuedPushes()
+		require.Len(t, queued, 1)
+		require.False(t, queued[0].Registration)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		envoy, fedDB := newTestEnvoy(t, false)
+
+		// Without auto registration, the default sync server isn't a
+		// federation member.
+		servers, err := fedDB.UniverseServers(ctx)
+		require.NoError(t, err)
+		require.Len(t, servers, 1)
+		require.Equal(t, memberServer, servers[0].HostStr())
+
+		receiver := fn.NewEventReceiver[*IssuancePushEvent](
+			fn.DefaultQueueSize,
+		)
+		envoy.RegisterPushSubscriber(receiver)
+		defer func() {
+			err := envoy.RemovePushSubscriber(receiver)
+			require.NoError(t, err)
+		}()
+
+		err = envoy.RegisterNewIssuanceBatch(
+			ctx, []*IssuanceItem{item},
+		)
+		require.NoError(t, err)
+
+		// The proof is still pushed to the member, but no events are
+		// published for it.
+		select {
+		case event := <-receiver.NewItemCreated.ChanOut():
+			t.Fatalf("unexpected push event: %v", event)
+
+		case <-time.After(100 * time.Millisecond):
+		}
+		require.Empty(t, fedDB.queuedPushes())
+	})
+}
+
+// pushTracker records the proof pushes to a set of mock federation members.
+type pushTracker struct {
+	mtx       sync.Mutex
+	pushes    map[string]int
+	active    int
+	maxActive int
+}
+
+func (p *pushTracker) numPushes(host string) int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.pushes[host]
+}
+
+// trackingRegistrar is a Registrar that records the pushes it receives with a
+// push tracker. If a release channel is set, pushes block until it's closed.
+type trackingRegistrar struct {
+	host    string
+	tracker *pushTracker
+	release chan struct{}
+}
+
+func (r *trackingRegistrar) RegisterIssuance(ctx context.Context, _ Identifier,
+	_ LeafKey, _ *Leaf) (*Proof, error) {
+
+	r.tracker.mtx.Lock()
+	r.tracker.active++
+	r.tracker.maxActive = max(r.tracker.maxActive, r.tracker.active)
+	r.tracker.mtx.Unlock()
+
+	defer func() {
+		r.tracker.mtx.Lock()
+		r.tracker.active--
+		r.tracker.pushes[r.host]++
+		r.tracker.mtx.Unlock()
+	}()
+
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, nil
+}
+
+// TestFederationEnvoyPushConcurrency tests that the number of proof pushes in
+// flight is limited, and that a slow federation member doesn't hold up the
+// pushes to the other members.
+func TestFederationEnvoyPushConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const (
+		slowServer    = "slow.universe:10029"
+		maxConcurrent = 2
+		numItems      = 3
+	)
+	fastServers := []string{
+		"fast1.universe:10029", "fast2.universe:10029",
+		"fast3.universe:10029",
+	}
+
+	tracker := &pushTracker{
+		pushes: make(map[string]int),
+	}
+	release := make(chan struct{})
+
+	fedDB := &mockFederationDB{}
+	envoy := NewFederationEnvoy(FederationConfig{
+		FederationDB:   fedDB,
+		UniverseSyncer: mockSyncer{},
+		NewRemoteRegistrar: func(addr ServerAddr) (Registrar, error) {
+			registrar := &trackingRegistrar{
+				host:    addr.HostStr(),
+				tracker: tracker,
+			}
+			if addr.HostStr() == slowServer {
+				registrar.release = release
+			}
+
+			return registrar, nil
+		},
+		LocalRegistrar:          &mockRegistrar{},
+		SyncInterval:            time.Hour,
+		PushRetryInitialBackoff: time.Hour,
+		PushRetryMaxBackoff:     time.Hour,
+		MaxPendingPushes:        10,
+		MaxConcurrentPushes:     maxConcurrent,
+		ErrChan:                 make(chan error, 1),
+		StaticFederationMembers: append(fastServers, slowServer),
+		ServerChecker: func(ServerAddr) error {
+			return nil
+		},
+	})
+	require.NoError(t, envoy.Start())
+	t.Cleanup(func() {
+		require.NoError(t, envoy.Stop())
+	})
+
+	items := make([]*IssuanceItem, numItems)
+	for i := range items {
+		scriptKey := asset.NewScriptKey(test.RandPubKey(t))
+		items[i] = &IssuanceItem{
+			ID: Identifier{
+				AssetID:   asset.RandID(t),
+				ProofType: ProofTypeIssuance,
+			},
+			Key: LeafKey{
+				ScriptKey: &scriptKey,
+			},
+			Leaf: &Leaf{},
+		}
+	}
+
+	err := envoy.RegisterNewIssuanceBatch(context.Background(), items)
+	require.NoError(t, err)
+
+	// All proofs should reach the fast members, even though the slow
+	// member holds on to one of the push slots. Only the push to the slow
+	// member should be left in flight then.
+	require.Eventually(t, func() bool {
+		for _, server := range fastServers {
+			if tracker.numPushes(server) != numItems {
+				return false
+			}
+		}
+
+		_, inFlight := envoy.PushConcurrency()
+		return inFlight == 1
+	}, DefaultTimeout, 10*time.Millisecond)
+
+	limit, _ := envoy.PushConcurrency()
+	require.Equal(t, maxConcurrent, limit)
+
+	// Once the slow member accepts the proofs, it receives all of them and
+	// no pushes are left in flight.
+	close(release)
+	require.Eventually(t, func() bool {
+		_, inFlight := envoy.PushConcurrency()
+		return tracker.numPushes(slowServer) == numItems &&
+			inFlight == 0
+	}, DefaultTimeout, 10*time.Millisecond)
+
+	tracker.mtx.Lock()
+	require.LessOrEqual(t, tracker.maxActive, maxConcurrent)
+	tracker.mtx.Unlock()
+
+	require.Empty(t, fedDB.queuedPushes())
+}
+
+var errSyncFailed = errors.New("sync failed")
+
+// syncTracker is a Syncer that records the servers it syncs with. Syncs with
+// the servers that have an error set fail with that error, syncs with the
+// servers that are set to hang block until the sync is aborted.
+type syncTracker struct {
+	mtx       sync.Mutex
+	active    int
+	maxActive int
+	synced    []string
+	errs      map[string]error
+	hanging   map[string]bool
+}
+
+func (s *syncTracker) SyncUniverse(ctx context.Context, host ServerAddr,
+	_ SyncType, _ SyncConfigs, _ ...Identifier) ([]AssetSyncDiff, error) {
+
+	s.mtx.Lock()
+	s.active++
+	if s.active > s.maxActive {
+		s.maxActive = s.active
+	}
+	s.synced = append(s.synced, host.HostStr())
+	err := s.errs[host.HostStr()]
+	hanging := s.hanging[host.HostStr()]
+	s.mtx.Unlock()
+
+	// Give any concurrent sync the chance to overlap with this one. A
+	// hanging server only returns once the sync is aborted.
+	if hanging {
+		<-ctx.Done()
+		err = ctx.Err()
+	} else {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.mtx.Lock()
+	s.active--
+	s.mtx.Unlock()
+
+	return nil, err
+}
+
+func (s *syncTracker) EstimateSync(context.Context, ServerAddr, SyncType,
+	SyncConfigs, ...Identifier) (*SyncEstimate, error) {
+
+	return &SyncEstimate{}, nil
+}
+
+func (s *syncTracker) setErr(host string, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.errs[host] = err
+}
+
+// TestFederationEnvoySyncServers tests that the federation members are synced
+// one after another, that a hanging member doesn't stall the sync with the
+// others, and that the error of the last sync with each member is logged.
+func TestFederationEnvoySyncServers(t *testing.T) {
+	t.Parallel()
+
+	const (
+		failingServer = "failing.universe:10029"
+		hangingServer = "hanging.universe:10029"
+	)
+	servers := []ServerAddr{
+		NewServerAddrFromStr("sync1.universe:10029"),
+		NewServerAddrFromStr(failingServer),
+		NewServerAddrFromStr(hangingServer),
+		NewServerAddrFromStr("sync2.universe:10029"),
+	}
+
+	syncer := &syncTracker{
+		errs: map[string]error{
+			failingServer: errSyncFailed,
+		},
+		hanging: map[string]bool{
+			hangingServer: true,
+		},
+	}
+	fedDB := &mockFederationDB{}
+	envoy := NewFederationEnvoy(FederationConfig{
+		FederationDB:            fedDB,
+		UniverseSyncer:          syncer,
+		ServerSyncTimeout:       50 * time.Millisecond,
+		LocalRegistrar:          &mockRegistrar{},
+		SyncInterval:            time.Hour,
+		PushRetryInitialBackoff: time.Hour,
+		PushRetryMaxBackoff:     time.Hour,
+		ErrChan:                 make(chan error, 1),
+		ServerChecker: func(ServerAddr) error {
+			return nil
+		},
+	})
+	require.NoError(t, envoy.Start())
+	t.Cleanup(func() {
+		require.NoError(t, envoy.Stop())
+	})
+
+	// Neither a failing nor a hanging member should prevent the sync with
+	// the other members.
+	require.NoError(t, envoy.SyncServers(servers))
+
+	syncer.mtx.Lock()
+	require.Equal(t, 1, syncer.maxActive)
+	require.Equal(
+		t, fn.Map(servers, func(s ServerAddr) string {
+			return s.HostStr()
+		}), syncer.synced,
+	)
+	syncer.mtx.Unlock()
+
+	syncErrors, err := fedDB.LastSyncErrors(context.Background())
+	require.NoError(t, err)
+	require.Len(t, syncErrors, 2)
+	require.Equal(t, errSyncFailed.Error(), syncErrors[failingServer])
+	require.Contains(t, syncErrors[hangingServer], "sync timed out")
+
+	// Once the members can be synced with again, their errors are cleared.
+	syncer.setErr(failingServer, nil)
+	syncer.mtx.Lock()
+	delete(syncer.hanging, hangingServer)
+	syncer.mtx.Unlock()
+
+	require.NoError(t, envoy.SyncServers(servers))
+	syncErrors, err = fedDB.LastSyncErrors(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, syncErrors)
+}
+
+// TestFederationEnvoyDefaultServer tests that the default sync server can be
+// changed at runtime, and that a default set at runtime is persisted and takes
+// precedence over the configured one after a restart.
+func TestFederationEnvoyDefaultServer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	fedDB := &mockFederationDB{}
+
+	// startEnvoy starts a new envoy with the configured default server
+	// on top of the shared federation DB.
+	startEnvoy := func(defaultServer string) *FederationEnvoy {
+		envoy := NewFederationEnvoy(FederationConfig{
+			FederationDB:            fedDB,
+			UniverseSyncer:          mockSyncer{},
+			LocalRegistrar:          &mockRegistrar{},
+			SyncInterval:            time.Hour,
+			PushRetryInitialBackoff: time.Hour,
+			PushRetryMaxBackoff:     time.Hour,
+			ErrChan:                 make(chan error, 1),
+			DefaultSyncServer:       defaultServer,
+			ServerChecker: func(ServerAddr) error {
+				return nil
+			},
+		})
+		require.NoError(t, envoy.Start())
+		t.Cleanup(func() {
+			require.NoError(t, envoy.Stop())
+		})
+
+		return envoy
+	}
+
+	// Without a default set at runtime, the configured one is used.
+	envoy := startEnvoy(defaultServer)
+	require.NotNil(t, envoy.DefaultServer())
+	require.Equal(t, defaultServer, envoy.DefaultServer().HostStr())
+
+	// We now change the default at runtime, which is persisted.
+	const runtimeServer = "runtime.universe:10029"
+	newDefault := NewServerAddrFromStr(runtimeServer)
+	require.NoError(t, envoy.SetDefaultServer(ctx, &newDefault))
+	require.Equal(t, runtimeServer, envoy.DefaultServer().HostStr())
+
+	host, err := fedDB.DefaultServer(ctx)
+	require.NoError(t, err)
+	require.Equal(t, runtimeServer, host)
+
+	// After a restart, the default set at runtime takes precedence over
+	// the configured one.
+	envoy = startEnvoy(defaultServer)
+	require.NotNil(t, envoy.DefaultServer())
+	require.Equal(t, runtimeServer, envoy.DefaultServer().HostStr())
+
+	// Clearing the default at runtime is persisted as well.
+	require.NoError(t, envoy.SetDefaultServer(ctx, nil))
+	require.Nil(t, envoy.DefaultServer())
+
+	envoy = startEnvoy(defaultServer)
+	require.Nil(t, envoy.DefaultServer())
+}
+
+// TestValidateServerAddrStr tests that malformed Universe server addresses are
+// rejected.
+func TestValidateServerAddrStr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		addr  string
+		valid bool
+	}{
+		{addr: "universe.example.com", valid: true},
+		{addr: "universe.example.com:10029", valid: true},
+		{addr: "127.0.0.1:8443", valid: true},
+		{addr: "[::1]:10029", valid: true},
+		{addr: "", valid: false},
+		{addr: ":10029", valid: false},
+		{addr: "universe.example.com:port", valid: false},
+		{addr: "universe.example.com:0", valid: false},
+		{addr: "universe.example.com:70000", valid: false},
+	}
+
+	for _, tc := range testCases {
+		err := ValidateServerAddrStr(tc.addr)
+		if tc.valid {
+			require.NoError(t, err, tc.addr)
+		} else {
+			require.Error(t, err, tc.addr)
+		}
+	}
+}
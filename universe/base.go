@@ -32,6 +32,11 @@ type MintingArchiveConfig struct {
 	// genesis proof.
 	GroupVerifier proof.GroupVerifier
 
+	// RotationLookup is used to look up the known rotations of the
+	// issuance authority of asset groups, so issuance proofs signed by a
+	// group key that was already rotated out are rejected.
+	RotationLookup proof.GroupRotationLookup
+
 	// Multiverse is used to interact with the set of known base
 	// universe trees, and also obtain associated metadata and statistics.
 	Multiverse MultiverseArchive
@@ -211,6 +216,11 @@ func (a *MintingArchive) RegisterIssuance(ctx context.Context, id Identifier,
 		return nil, err
 	}
 
+	err = a.verifyGroupSupplyCap(ctx, id, []*proof.Proof{newProof})
+	if err != nil {
+		return nil, err
+	}
+
 	// Now that we know the proof is valid, we'll insert it into the base
 	// multiverse backend, and return the new issuance proof.
 	issuanceProof, err := a.cfg.Multiverse.UpsertProofLeaf(
@@ -242,9 +252,9 @@ func (a *MintingArchive) verifyIssuanceProof(ctx context.Context, id Identifier,
 	key LeafKey, leaf *Leaf,
 	prevAssetSnapshot *proof.AssetSnapshot) (*proof.AssetSnapshot, error) {
 
-	assetSnapshot, err := leaf.Proof.Verify(
+	assetSnapshot, err := leaf.Proof.VerifyWithRotations(
 		ctx, prevAssetSnapshot, a.cfg.HeaderVerifier,
-		a.cfg.GroupVerifier,
+		a.cfg.GroupVerifier, a.cfg.RotationLookup,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to verify proof: %v", err)
@@ -280,6 +290,55 @@ func (a *MintingArchive) verifyIssuanceProof(ctx context.Context, id Identifier,
 	return assetSnapshot, nil
 }
 
+// verifyGroupSupplyCap verifies that adding the given issuance proofs to the
+// issuance universe of an asset group doesn't exceed the supply cap declared by
+// the group anchor. Proofs that replace a known leaf, for example after a
+// re-org, are only counted once.
+func (a *MintingArchive) verifyGroupSupplyCap(ctx context.Context,
+	id Identifier, newProofs []*proof.Proof) error {
+
+	if id.GroupKey == nil || id.ProofType != ProofTypeIssuance {
+		return nil
+	}
+
+	knownLeaves, err := a.MintingLeaves(ctx, id)
+	if err != nil {
+		return fmt.Errorf("unable to fetch group issuance leaves: %w",
+			err)
+	}
+
+	type issuanceKey struct {
+		assetID   asset.ID
+		scriptKey asset.SerializedKey
+	}
+	keyOf := func(p *proof.Proof) issuanceKey {
+		return issuanceKey{
+			assetID:   p.Asset.ID(),
+			scriptKey: asset.ToSerialized(p.Asset.ScriptKey.PubKey),
+		}
+	}
+
+	issuances := make(map[issuanceKey]*proof.Proof, len(knownLeaves))
+	for idx := range knownLeaves {
+		knownProof := knownLeaves[idx].Proof
+		issuances[keyOf(knownProof)] = knownProof
+	}
+	for _, newProof := range newProofs {
+		issuances[keyOf(newProof)] = newProof
+	}
+
+	allProofs := make([]*proof.Proof, 0, len(issuances))
+	for _, p := range issuances {
+		allProofs = append(allProofs, p)
+	}
+
+	if err := proof.VerifyGroupSupplyCap(allProofs); err != nil {
+		return fmt.Errorf("unable to verify group supply cap: %w", err)
+	}
+
+	return nil
+}
+
 // RegisterNewIssuanceBatch inserts a batch of new minting leaves within the
 // target universe tree (based on the ID), stored at the base key(s). We assume
 // the proofs within the batch have already been checked that they don't yet
@@ -372,6 +431,29 @@ func (a *MintingArchive) RegisterNewIssuanceBatch(ctx context.Context,
 		return err
 	}
 
+	// The group anchors are stored by now, so we can check that the
+	// re-issuances of this batch don't exceed the supply cap of their
+	// group, together with the ones that are already known.
+	groupIDs := make(map[string]Identifier)
+	groupProofs := make(map[string][]*proof.Proof)
+	for _, item := range nonAnchorItems {
+		if item.ID.GroupKey == nil {
+			continue
+		}
+
+		idStr := item.ID.String()
+		groupIDs[idStr] = item.ID
+		groupProofs[idStr] = append(
+			groupProofs[idStr], item.Leaf.Proof,
+		)
+	}
+	for idStr, id := range groupIDs {
+		err := a.verifyGroupSupplyCap(ctx, id, groupProofs[idStr])
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Infof("Inserting %d verified proofs into Universe",
 		len(nonAnchorItems))
 	err = a.cfg.Multiverse.RegisterBatchIssuance(ctx, nonAnchorItems)
@@ -489,6 +571,26 @@ func (a *MintingArchive) FetchIssuanceProof(ctx context.Context, id Identifier,
 	return a.cfg.Multiverse.FetchProofLeaf(ctx, id, key)
 }
 
+// FetchIssuanceProofs fetches the proofs of a set of leaves of the target
+// universe. The proofs are returned in the order of the given keys.
+func (a *MintingArchive) FetchIssuanceProofs(ctx context.Context,
+	id Identifier, keys []LeafKey) ([]*Proof, error) {
+
+	proofs := make([]*Proof, 0, len(keys))
+	for _, key := range keys {
+		leafProofs, err := a.FetchIssuanceProof(ctx, id, key)
+		if err != nil {
+			return nil, err
+		}
+
+		// As the keys are fully specified, there's exactly one proof
+		// per key.
+		proofs = append(proofs, leafProofs[0])
+	}
+
+	return proofs, nil
+}
+
 // UniverseLeafKeys returns the set of leaf keys known for the specified
 // universe identifier.
 func (a *MintingArchive) UniverseLeafKeys(ctx context.Context,
@@ -534,3 +636,16 @@ func (a *MintingArchive) DeleteRoot(ctx context.Context,
 	}
 	return uniStr, err
 }
+
+// PruneLeaves removes the proof leaves of the specified base universe that
+// were confirmed in a block below the given height. If the height is zero, then
+// all leaves of the universe are removed. The number of removed leaves is
+// returned.
+func (a *MintingArchive) PruneLeaves(ctx context.Context, id Identifier,
+	beforeHeight uint32) (uint64, error) {
+
+	log.Debugf("Pruning leaves of Universe: id=%v, before_height=%v",
+		id.StringForLog(), beforeHeight)
+
+	return a.cfg.Multiverse.PruneProofLeaves(ctx, id, beforeHeight)
+}
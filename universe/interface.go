@@ -6,6 +6,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"net"
 	"strconv"
 	"time"
@@ -34,6 +35,15 @@ var (
 	// ErrNoUniverseProofFound is returned when a user attempts to look up
 	// a key in the universe that actually points to the empty leaf.
 	ErrNoUniverseProofFound = fmt.Errorf("no universe proof found")
+
+	// ErrPushQueueFull is returned when a failed proof push can't be
+	// queued for a retry, because the push queue of the target federation
+	// member is already full.
+	ErrPushQueueFull = fmt.Errorf("federation push queue full")
+
+	// ErrNoDefaultServer is returned when the default Universe server was
+	// never set at runtime.
+	ErrNoDefaultServer = fmt.Errorf("no default universe server set")
 )
 
 // Identifier is the identifier for a universe.
@@ -249,6 +259,20 @@ type BaseBackend interface {
 	DeleteUniverse(ctx context.Context) (string, error)
 }
 
+// RootVersion is the version of the leaf encoding a universe root commits to.
+// Roots of different versions can't be compared, even if they commit to the
+// same set of leaves.
+type RootVersion uint32
+
+const (
+	// RootV0 is the initial version of universe roots. Servers that don't
+	// report a version of their roots use this version.
+	RootV0 RootVersion = 0
+
+	// LatestRootVersion is the version of the roots of the local universe.
+	LatestRootVersion = RootV0
+)
+
 // BaseRoot is the ms-smt root for a base universe. This root can be used to
 // compare against other trackers of a base universe to find discrepancies
 // (unknown issuance events, etc).
@@ -257,6 +281,9 @@ type BaseRoot struct {
 
 	mssmt.Node
 
+	// Version is the version of the leaf encoding the root commits to.
+	Version RootVersion
+
 	// AssetName is the name of the asset. This might not always be set for
 	// performance reasons.
 	AssetName string
@@ -264,6 +291,12 @@ type BaseRoot struct {
 	// GroupedAssets is an optional map of asset IDs to the minted amount.
 	// This is only set for grouped assets.
 	GroupedAssets map[asset.ID]uint64
+
+	// IssuanceOrigin is true if the asset of this universe was minted by
+	// the local node, which makes this node the authoritative source for
+	// the universe instead of a mirror of it. This might not always be
+	// set for performance reasons.
+	IssuanceOrigin bool
 }
 
 // MultiverseRoot is the ms-smt root for a multiverse. This root can be used to
@@ -303,6 +336,13 @@ type MultiverseArchive interface {
 	FetchProofLeaf(ctx context.Context, id Identifier,
 		key LeafKey) ([]*Proof, error)
 
+	// PruneProofLeaves removes the proof leaves of the given universe that
+	// were confirmed in a block below the given height, or all leaves if
+	// the height is zero. The universe and multiverse roots are updated
+	// accordingly. The number of removed leaves is returned.
+	PruneProofLeaves(ctx context.Context, id Identifier,
+		beforeHeight uint32) (uint64, error)
+
 	// TODO(roasbeef): other stats stuff here, like total number of assets, etc
 	//  * also eventually want pull/fetch stats, can be pulled out into another instance
 }
@@ -352,16 +392,17 @@ const (
 	DefaultUniverseRPCPort = 10029
 )
 
-// resolveUniverseAddr maps an RPC universe host (of the form 'host' or
-// 'host:port') into a net.Addr.
-func resolverUniverseAddr(uniAddr string) (net.Addr, error) {
+// parseUniverseAddr maps an RPC universe host (of the form 'host' or
+// 'host:port') into the 'host:port' form, using the default port if none is
+// given.
+func parseUniverseAddr(uniAddr string) (string, error) {
 	var (
 		host string
 		port int
 	)
 
 	if len(uniAddr) == 0 {
-		return nil, fmt.Errorf("universe host cannot be empty")
+		return "", fmt.Errorf("universe host cannot be empty")
 	}
 
 	// Split the address into its host and port components.
@@ -376,14 +417,38 @@ func resolverUniverseAddr(uniAddr string) (net.Addr, error) {
 		host = h
 		portNum, err := strconv.Atoi(p)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 		port = portNum
 	}
 
+	if host == "" {
+		return "", fmt.Errorf("universe host cannot be empty")
+	}
+	if port <= 0 || port > math.MaxUint16 {
+		return "", fmt.Errorf("invalid universe port: %d", port)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
+
+// ValidateServerAddrStr checks that the given RPC universe host (of the form
+// 'host' or 'host:port') is well-formed, without resolving it.
+func ValidateServerAddrStr(uniAddr string) error {
+	_, err := parseUniverseAddr(uniAddr)
+	return err
+}
+
+// resolveUniverseAddr maps an RPC universe host (of the form 'host' or
+// 'host:port') into a net.Addr.
+func resolverUniverseAddr(uniAddr string) (net.Addr, error) {
+	hostPort, err := parseUniverseAddr(uniAddr)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO(roasbeef): add tor support
 
-	hostPort := net.JoinHostPort(host, strconv.Itoa(port))
 	return net.ResolveTCPAddr("tcp", hostPort)
 }
 
@@ -492,6 +557,13 @@ type Syncer interface {
 	SyncUniverse(ctx context.Context, host ServerAddr,
 		syncType SyncType, syncConfigs SyncConfigs,
 		idsToSync ...Identifier) ([]AssetSyncDiff, error)
+
+	// EstimateSync estimates how much data a sync with the remote
+	// universe would transfer and how long it would take, governed by the
+	// sync type and the set of universe IDs to sync.
+	EstimateSync(ctx context.Context, host ServerAddr,
+		syncType SyncType, syncConfigs SyncConfigs,
+		idsToSync ...Identifier) (*SyncEstimate, error)
 }
 
 // DiffEngine is a Universe diff engine that can be used to compare the state
@@ -514,8 +586,19 @@ type DiffEngine interface {
 	// of diff
 	FetchIssuanceProof(ctx context.Context, id Identifier,
 		key LeafKey) ([]*Proof, error)
+
+	// FetchIssuanceProofs fetches the proofs of a set of leaves of the
+	// target universe at once. The proofs are returned in the order of
+	// the given keys. An error is returned if any of the leaves is
+	// unknown.
+	FetchIssuanceProofs(ctx context.Context, id Identifier,
+		keys []LeafKey) ([]*Proof, error)
 }
 
+// MaxQueryProofsKeys is the maximum number of leaf keys the proofs can be
+// queried for with a single call to a remote universe.
+const MaxQueryProofsKeys = 1000
+
 // Commitment is an on chain universe commitment. This includes the merkle
 // proof for a transaction which anchors the target universe root.
 type Commitment struct {
@@ -587,6 +670,25 @@ type FederationLog interface {
 	// LogNewSyncs logs a new sync event for each server. This can be used
 	// to keep track of the last time we synced with a remote server.
 	LogNewSyncs(ctx context.Context, addrs ...ServerAddr) error
+
+	// LogSyncError logs the error of the last sync attempt with a server.
+	// A nil error clears the logged error after a successful sync.
+	LogSyncError(ctx context.Context, addr ServerAddr, syncErr error) error
+
+	// LastSyncErrors returns the error of the last sync attempt with each
+	// server that failed to sync, keyed by the host string of the server.
+	LastSyncErrors(ctx context.Context) (map[string]string, error)
+
+	// SetDefaultServer persists the host of the Universe server that is
+	// used as the sync target if none is specified. An empty host records
+	// that the default was cleared.
+	SetDefaultServer(ctx context.Context, host string) error
+
+	// DefaultServer returns the host of the Universe server that was last
+	// set as the default sync target. An empty host means the default was
+	// cleared. ErrNoDefaultServer is returned if the default was never
+	// set.
+	DefaultServer(ctx context.Context) (string, error)
 }
 
 // ProofType is an enum that describes the type of proof which can be stored in
@@ -692,11 +794,72 @@ type FederationSyncConfigDB interface {
 		uniSyncConfigs []*FedUniSyncConfig) error
 }
 
+// PendingPush is a proof push to a federation member that failed and is
+// queued to be retried.
+type PendingPush struct {
+	// ID is the unique identifier of the queued push.
+	ID int64
+
+	// Server is the federation member the proof should be pushed to. For a
+	// registration push, only the host of the server is set.
+	Server ServerAddr
+
+	// Registration is true if the push registers the issuance proof of a
+	// minted asset with the default universe server. That server isn't
+	// necessarily a federation member.
+	Registration bool
+
+	// UniverseID identifies the universe the proof belongs to.
+	UniverseID Identifier
+
+	// LeafKey is the key of the proof leaf within the universe.
+	LeafKey LeafKey
+
+	// NumAttempts is the number of failed attempts to push the proof.
+	NumAttempts uint32
+
+	// NextAttempt is the earliest time the push should be retried.
+	NextAttempt time.Time
+
+	// LastError is the error returned by the last failed push attempt.
+	LastError string
+
+	// CreationTime is the time the push was first queued.
+	CreationTime time.Time
+}
+
+// FederationPushLog is used to persist the proof pushes to federation members
+// that failed, so they can be retried, even across restarts.
+type FederationPushLog interface {
+	// QueuePendingPush adds the given push to the retry queue of its
+	// target server. If the push is already queued, only its retry
+	// information is updated. ErrPushQueueFull is returned if the queue of
+	// the server already holds maxQueueSize pushes.
+	//
+	// NOTE: The ID of the given push is ignored.
+	QueuePendingPush(ctx context.Context, push *PendingPush,
+		maxQueueSize int) error
+
+	// PendingPushes returns the queued pushes that are due to be retried
+	// at the given time. If the time is zero, all queued pushes are
+	// returned.
+	PendingPushes(ctx context.Context,
+		dueBefore time.Time) ([]*PendingPush, error)
+
+	// UpdatePendingPush updates the number of attempts, next attempt time
+	// and last error of the given queued push.
+	UpdatePendingPush(ctx context.Context, push *PendingPush) error
+
+	// RemovePendingPush removes the given queued push.
+	RemovePendingPush(ctx context.Context, push *PendingPush) error
+}
+
 // FederationDB is used for CRUD operations related to federation sync config
 // and tracked servers.
 type FederationDB interface {
 	FederationLog
 	FederationSyncConfigDB
+	FederationPushLog
 }
 
 // SyncStatsSort is an enum used to specify the sort order of the returned sync
@@ -824,6 +987,10 @@ type AssetSyncStats struct {
 	SyncStats []AssetSyncSnapshot
 }
 
+// RecentStatsWindow is the time window over which the recent activity counts
+// of the AggregateStats are computed.
+const RecentStatsWindow = 24 * time.Hour
+
 // AggregateStats is a set of aggregate stats for a given Universe.
 type AggregateStats struct {
 	// NumTotalAssets is the total number of assets in the Universe.
@@ -839,6 +1006,14 @@ type AggregateStats struct {
 	// NumTotalProofs is the total number of proofs that have been inserted
 	// into the Universe.
 	NumTotalProofs uint64
+
+	// NumRecentSyncs is the number of syncs that have been performed in
+	// the Universe within the last RecentStatsWindow.
+	NumRecentSyncs uint64
+
+	// NumRecentProofs is the number of proofs that have been inserted into
+	// the Universe within the last RecentStatsWindow.
+	NumRecentProofs uint64
 }
 
 // GroupedStatsQuery packages a set of query parameters to retrieve event based
@@ -0,0 +1,318 @@
+package universe
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightninglabs/taproot-assets/asset"
+)
+
+// SupplyOutput is an asset output of an asset group.
+type SupplyOutput struct {
+	// OutPoint is the outpoint of the anchor output of the asset.
+	OutPoint wire.OutPoint
+
+	// Asset is the asset of the output.
+	Asset *asset.Asset
+}
+
+// SupplyBurn is a burn of units of an asset group.
+type SupplyBurn = SupplyOutput
+
+// SupplyReport is the result of the reconciliation of the supply of an asset
+// group. It compares the issuance and burn proofs known to the local universe
+// and node with each other, and the outstanding units with the units held in
+// the unspent outputs known to the universe and the node.
+type SupplyReport struct {
+	// GroupKey is the key of the asset group.
+	GroupKey *btcec.PublicKey
+
+	// IssuanceRootSum is the sum of the issuance universe root of the
+	// group.
+	IssuanceRootSum uint64
+
+	// IssuedAmt is the sum of the amounts of all issuance proofs of the
+	// group.
+	IssuedAmt uint64
+
+	// NumIssuances is the number of issuance proofs of the group.
+	NumIssuances uint64
+
+	// BurnedAmt is the sum of the amounts of all known burns of the group.
+	BurnedAmt uint64
+
+	// NumBurns is the number of known burns of the group.
+	NumBurns uint64
+
+	// LocalBalance is the number of units of the group held by the local
+	// node.
+	LocalBalance uint64
+
+	// UnspentAmt is the number of units held in the unspent outputs of
+	// the group that are known to the universe or the local node. An
+	// output is unspent if no known transfer spends it.
+	UnspentAmt uint64
+
+	// NumUnspentOutputs is the number of unspent outputs of the group that
+	// are known to the universe or the local node.
+	NumUnspentOutputs uint64
+
+	// Discrepancies describes every inconsistency that was found. The
+	// supply is consistent if there are none.
+	Discrepancies []string
+}
+
+// OutstandingAmt returns the number of units of the group that were issued
+// and not burned, which is the number of units that can be held at most.
+func (r *SupplyReport) OutstandingAmt() uint64 {
+	if r.BurnedAmt > r.IssuedAmt {
+		return 0
+	}
+
+	return r.IssuedAmt - r.BurnedAmt
+}
+
+// Consistent returns true if no discrepancy was found.
+func (r *SupplyReport) Consistent() bool {
+	return len(r.Discrepancies) == 0
+}
+
+// addDiscrepancy adds a discrepancy to the report.
+func (r *SupplyReport) addDiscrepancy(format string, args ...any) {
+	r.Discrepancies = append(r.Discrepancies, fmt.Sprintf(format, args...))
+}
+
+// outputID returns the ID of the given asset output, as it is referenced by the
+// inputs that spend it.
+func outputID(outPoint wire.OutPoint, a *asset.Asset) asset.PrevID {
+	return asset.PrevID{
+		OutPoint:  outPoint,
+		ID:        a.ID(),
+		ScriptKey: asset.ToSerialized(a.ScriptKey.PubKey),
+	}
+}
+
+// spentOutputs returns the IDs of the outputs spent by the transfer that
+// created the given asset. A split output refers to the inputs of its root
+// asset. Genesis assets don't spend any output.
+func spentOutputs(a *asset.Asset) []asset.PrevID {
+	witnesses := a.PrevWitnesses
+	if a.HasSplitCommitmentWitness() {
+		rootAsset := a.PrevWitnesses[0].SplitCommitment.RootAsset
+		witnesses = rootAsset.PrevWitnesses
+	}
+
+	var spent []asset.PrevID
+	for _, witness := range witnesses {
+		prevID := witness.PrevID
+		if prevID == nil || *prevID == asset.ZeroPrevID {
+			continue
+		}
+
+		spent = append(spent, *prevID)
+	}
+
+	return spent
+}
+
+// ReconcileSupply reconciles the supply of the asset group with the given key.
+// The issuance leaves must be the leaves of the issuance universe of the group
+// with the given root sum, the transfer leaves the ones of its transfer
+// universe. The burns are deduplicated by their anchor outpoint and script key,
+// so the burns known to the transfer universe and the ones known to the local
+// node can simply be combined. The local outputs are the unspent outputs of the
+// group held by the local node.
+//
+// The units that were issued and not burned must be held in the outputs that
+// aren't spent by any transfer known to the universe or the local node. An
+// output held by the local node that a known transfer spends is reported as
+// well.
+func ReconcileSupply(groupKey *btcec.PublicKey, issuanceRootSum uint64,
+	issuanceLeaves, transferLeaves []Leaf, burns []SupplyBurn,
+	localOutputs []SupplyOutput) *SupplyReport {
+
+	report := &SupplyReport{
+		GroupKey:        groupKey,
+		IssuanceRootSum: issuanceRootSum,
+	}
+
+	// inGroup returns true if the given asset belongs to the group.
+	inGroup := func(a *asset.Asset) bool {
+		return a.GroupKey != nil &&
+			a.GroupKey.GroupPubKey.IsEqual(groupKey)
+	}
+
+	// outputs holds the amounts of all known outputs of the group that
+	// can hold units, spent the IDs of the outputs spent by known
+	// transfers.
+	var (
+		outputs = make(map[asset.PrevID]uint64)
+		spent   = make(map[asset.PrevID]struct{})
+	)
+	addOutput := func(outPoint wire.OutPoint, a *asset.Asset) {
+		for _, prevID := range spentOutputs(a) {
+			spent[prevID] = struct{}{}
+		}
+
+		if !a.IsBurn() {
+			outputs[outputID(outPoint, a)] = a.Amount
+		}
+	}
+
+	for _, leaf := range issuanceLeaves {
+		if leaf.Proof == nil {
+			report.addDiscrepancy("issuance leaf of asset %v "+
+				"without proof", leaf.Genesis.ID())
+			continue
+		}
+
+		issued := &leaf.Proof.Asset
+		switch {
+		case !inGroup(issued):
+			report.addDiscrepancy("issuance proof of asset %v "+
+				"doesn't belong to the group", issued.ID())
+			continue
+
+		case !issued.IsGenesisAsset():
+			report.addDiscrepancy("issuance proof of asset %v "+
+				"isn't a genesis proof", issued.ID())
+			continue
+
+		case leaf.Amt != issued.Amount:
+			report.addDiscrepancy("issuance leaf of asset %v "+
+				"claims %d units, but its proof issues %d",
+				issued.ID(), leaf.Amt, issued.Amount)
+		}
+
+		report.IssuedAmt += issued.Amount
+		report.NumIssuances++
+
+		addOutput(leaf.Proof.OutPoint(), issued)
+	}
+
+	for _, leaf := range transferLeaves {
+		if leaf.Proof == nil {
+			report.addDiscrepancy("transfer leaf of asset %v "+
+				"without proof", leaf.Genesis.ID())
+			continue
+		}
+
+		transferred := &leaf.Proof.Asset
+		if !inGroup(transferred) {
+			report.addDiscrepancy("transfer proof of asset %v "+
+				"doesn't belong to the group",
+				transferred.ID())
+			continue
+		}
+
+		addOutput(leaf.Proof.OutPoint(), transferred)
+	}
+
+	if report.IssuedAmt != issuanceRootSum {
+		report.addDiscrepancy("issuance universe root sum %d doesn't "+
+			"match the %d units issued by the issuance proofs",
+			issuanceRootSum, report.IssuedAmt)
+	}
+
+	seenBurns := make(map[asset.PrevID]struct{}, len(burns))
+	for _, burn := range burns {
+		if burn.Asset.ScriptKey.PubKey == nil {
+			report.addDiscrepancy("burn of asset %v at %v without "+
+				"script key", burn.Asset.ID(), burn.OutPoint)
+			continue
+		}
+
+		burnID := asset.PrevID{
+			OutPoint: burn.OutPoint,
+			ID:       burn.Asset.ID(),
+			ScriptKey: asset.ToSerialized(
+				burn.Asset.ScriptKey.PubKey,
+			),
+		}
+		if _, ok := seenBurns[burnID]; ok {
+			continue
+		}
+		seenBurns[burnID] = struct{}{}
+
+		switch {
+		case !inGroup(burn.Asset):
+			report.addDiscrepancy("burn of asset %v at %v doesn't "+
+				"belong to the group", burnID.ID,
+				burn.OutPoint)
+			continue
+
+		case !burn.Asset.IsBurn():
+			report.addDiscrepancy("asset %v at %v isn't a valid "+
+				"burn", burnID.ID, burn.OutPoint)
+			continue
+		}
+
+		report.BurnedAmt += burn.Asset.Amount
+		report.NumBurns++
+
+		addOutput(burn.OutPoint, burn.Asset)
+	}
+
+	for _, local := range localOutputs {
+		if !inGroup(local.Asset) {
+			report.addDiscrepancy("local asset %v at %v doesn't "+
+				"belong to the group", local.Asset.ID(),
+				local.OutPoint)
+			continue
+		}
+
+		report.LocalBalance += local.Asset.Amount
+		addOutput(local.OutPoint, local.Asset)
+	}
+
+	// Now that all transfers are known, we can tell which outputs are
+	// still unspent.
+	for _, local := range localOutputs {
+		if !inGroup(local.Asset) {
+			continue
+		}
+
+		if _, ok := spent[outputID(local.OutPoint, local.Asset)]; ok {
+			report.addDiscrepancy("local asset %v at %v is spent "+
+				"by a known transfer", local.Asset.ID(),
+				local.OutPoint)
+		}
+	}
+	for id, amt := range outputs {
+		if _, ok := spent[id]; ok {
+			continue
+		}
+
+		report.UnspentAmt += amt
+		report.NumUnspentOutputs++
+	}
+
+	if report.BurnedAmt > report.IssuedAmt {
+		report.addDiscrepancy("%d units were burned, but only %d "+
+			"were issued", report.BurnedAmt, report.IssuedAmt)
+	}
+
+	switch {
+	case report.UnspentAmt > report.OutstandingAmt():
+		report.addDiscrepancy("%d units held in unspent outputs "+
+			"exceed the %d outstanding units", report.UnspentAmt,
+			report.OutstandingAmt())
+
+	// Units can only go missing if transfers that spent outputs are
+	// known, but not all of the outputs they created.
+	case report.UnspentAmt < report.OutstandingAmt():
+		report.addDiscrepancy("only %d of the %d outstanding units "+
+			"are held in known unspent outputs, transfer proofs "+
+			"are missing from the universe", report.UnspentAmt,
+			report.OutstandingAmt())
+	}
+
+	if report.LocalBalance > report.OutstandingAmt() {
+		report.addDiscrepancy("local balance of %d units exceeds the "+
+			"%d outstanding units", report.LocalBalance,
+			report.OutstandingAmt())
+	}
+
+	return report
+}
@@ -0,0 +1,171 @@
+package universe
+
+import (
+	"testing"
+
+	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/proof"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReconcileSupply tests that the supply of an asset group is reconciled
+// correctly and that discrepancies are reported.
+func TestReconcileSupply(t *testing.T) {
+	t.Parallel()
+
+	// We issue two tranches of the same group.
+	firstIssuance := asset.RandAsset(t, asset.Normal)
+	firstIssuance.Amount = 100
+	groupKey := &firstIssuance.GroupKey.GroupPubKey
+
+	secondIssuance := asset.RandAsset(t, asset.Normal)
+	secondIssuance.Amount = 50
+	secondIssuance.GroupKey = firstIssuance.GroupKey
+
+	// leafAt creates a universe leaf of the given asset, anchored at the
+	// output with the given index.
+	leafAt := func(a *asset.Asset, outputIndex uint32) Leaf {
+		return Leaf{
+			GenesisWithGroup: GenesisWithGroup{
+				Genesis:  a.Genesis,
+				GroupKey: a.GroupKey,
+			},
+			Proof: &proof.Proof{
+				Asset: *a,
+				InclusionProof: proof.TaprootProof{
+					OutputIndex: outputIndex,
+				},
+			},
+			Amt: a.Amount,
+		}
+	}
+	leaves := []Leaf{
+		leafAt(firstIssuance, 0), leafAt(secondIssuance, 1),
+	}
+
+	// Some units of the first tranche are burned, the rest is sent to a
+	// change output of the same transfer.
+	issuancePrevID := outputID(
+		leaves[0].Proof.OutPoint(), firstIssuance,
+	)
+	burned := firstIssuance.Copy()
+	burned.Amount = 30
+	burned.ScriptKey = asset.NewScriptKey(
+		asset.DeriveBurnKey(issuancePrevID),
+	)
+	burned.PrevWitnesses = []asset.Witness{{
+		PrevID: &issuancePrevID,
+	}}
+	burnLeaf := leafAt(burned, 2)
+	burn := SupplyBurn{
+		OutPoint: burnLeaf.Proof.OutPoint(),
+		Asset:    burned,
+	}
+
+	change := burned.Copy()
+	change.Amount = 70
+	change.ScriptKey = asset.RandScriptKey(t)
+	changeLeaf := leafAt(change, 3)
+	transferLeaves := []Leaf{burnLeaf, changeLeaf}
+
+	// The second tranche is held by the local node.
+	localOutputs := []SupplyOutput{{
+		OutPoint: leaves[1].Proof.OutPoint(),
+		Asset:    secondIssuance,
+	}}
+
+	// The same burn known to both the universe and the local node is only
+	// counted once. The outstanding units are held by the change output
+	// and the local output.
+	report := ReconcileSupply(
+		groupKey, 150, leaves, transferLeaves,
+		[]SupplyBurn{burn, burn}, localOutputs,
+	)
+	require.True(t, report.Consistent(), report.Discrepancies)
+	require.EqualValues(t, 150, report.IssuedAmt)
+	require.EqualValues(t, 2, report.NumIssuances)
+	require.EqualValues(t, 30, report.BurnedAmt)
+	require.EqualValues(t, 1, report.NumBurns)
+	require.EqualValues(t, 120, report.OutstandingAmt())
+	require.EqualValues(t, 120, report.UnspentAmt)
+	require.EqualValues(t, 2, report.NumUnspentOutputs)
+	require.EqualValues(t, 50, report.LocalBalance)
+
+	// A universe root that doesn't match its leaves is reported.
+	burns := []SupplyBurn{burn}
+	report = ReconcileSupply(
+		groupKey, 200, leaves, transferLeaves, burns, localOutputs,
+	)
+	require.False(t, report.Consistent())
+	require.Len(t, report.Discrepancies, 1)
+	require.Contains(t, report.Discrepancies[0], "root sum 200")
+
+	// Units held in an output that no known transfer created indicate
+	// inflation.
+	inflated := secondIssuance.Copy()
+	inflated.Amount = 1
+	inflated.ScriptKey = asset.RandScriptKey(t)
+	report = ReconcileSupply(
+		groupKey, 150, leaves, transferLeaves, burns,
+		append(localOutputs, SupplyOutput{
+			OutPoint: test.RandOp(t),
+			Asset:    inflated,
+		}),
+	)
+	require.False(t, report.Consistent())
+	require.Len(t, report.Discrepancies, 1)
+	require.Contains(t, report.Discrepancies[0], "exceed")
+	require.EqualValues(t, 121, report.UnspentAmt)
+
+	// If the universe knows the transfer that spent the first tranche,
+	// but not its change output, units are missing.
+	report = ReconcileSupply(
+		groupKey, 150, leaves, []Leaf{burnLeaf}, burns, localOutputs,
+	)
+	require.False(t, report.Consistent())
+	require.Len(t, report.Discrepancies, 1)
+	require.Contains(t, report.Discrepancies[0], "only 50 of the 120")
+
+	// A local output that a known transfer spent is reported.
+	report = ReconcileSupply(
+		groupKey, 150, leaves, transferLeaves, burns,
+		[]SupplyOutput{{
+			OutPoint: leaves[0].Proof.OutPoint(),
+			Asset:    firstIssuance,
+		}},
+	)
+	require.False(t, report.Consistent())
+	require.Len(t, report.Discrepancies, 1)
+	require.Contains(t, report.Discrepancies[0], "spent by a known")
+
+	// Burns of other groups and assets that aren't burns are reported and
+	// not counted.
+	otherGroup := asset.RandAsset(t, asset.Normal)
+	notBurned := firstIssuance.Copy()
+	report = ReconcileSupply(
+		groupKey, 150, leaves, nil, []SupplyBurn{{
+			OutPoint: test.RandOp(t),
+			Asset:    otherGroup,
+		}, {
+			OutPoint: test.RandOp(t),
+			Asset:    notBurned,
+		}}, nil,
+	)
+	require.Len(t, report.Discrepancies, 2)
+	require.Contains(t, report.Discrepancies[0], "doesn't belong")
+	require.Contains(t, report.Discrepancies[1], "isn't a valid burn")
+	require.Zero(t, report.BurnedAmt)
+
+	// Issuance and transfer proofs of another group are reported and not
+	// counted.
+	report = ReconcileSupply(
+		groupKey, 150, append(leaves, leafAt(otherGroup, 4)),
+		[]Leaf{leafAt(otherGroup, 5)}, nil, nil,
+	)
+	require.Len(t, report.Discrepancies, 2)
+	require.Contains(t, report.Discrepancies[0], "doesn't belong")
+	require.Contains(t, report.Discrepancies[1], "doesn't belong")
+	require.EqualValues(t, 150, report.IssuedAmt)
+	require.EqualValues(t, 150, report.UnspentAmt)
+}
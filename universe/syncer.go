@@ -1,10 +1,13 @@
 package universe
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"sync/atomic"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightninglabs/taproot-assets/fn"
@@ -18,6 +21,13 @@ var (
 	ErrUnsupportedSync = fmt.Errorf("unsupported sync type")
 )
 
+const (
+	// maxSyncEstimateSamples is the maximum number of leaf proofs that are
+	// fetched from the remote universe to estimate the size of a leaf
+	// proof and the time it takes to fetch it.
+	maxSyncEstimateSamples = 10
+)
+
 // SimpleSyncCfg contains all the configuration needed to create a new
 // SimpleSyncer.
 type SimpleSyncCfg struct {
@@ -36,6 +46,11 @@ type SimpleSyncCfg struct {
 
 	// SyncBatchSize is the number of items to sync in a single batch.
 	SyncBatchSize int
+
+	// SyncWorkers is the maximum number of universe roots that are synced
+	// in parallel. The leaf proofs of each root are also fetched by up to
+	// that many workers. If not positive, the number of CPUs is used.
+	SyncWorkers int
 }
 
 // SimpleSyncer is a simple implementation of the Syncer interface. It's based
@@ -56,6 +71,16 @@ func NewSimpleSyncer(cfg SimpleSyncCfg) *SimpleSyncer {
 	}
 }
 
+// numWorkers returns the maximum number of universe roots that are synced in
+// parallel.
+func (s *SimpleSyncer) numWorkers() int {
+	if s.cfg.SyncWorkers > 0 {
+		return s.cfg.SyncWorkers
+	}
+
+	return runtime.NumCPU()
+}
+
 // executeSync attempts to sync the local Universe with the remote diff engine.
 // A simple approach where a set difference is used to find the set of assets
 // that need to be synced is used.
@@ -73,6 +98,47 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 		s.isSyncing.Store(false)
 	}()
 
+	targetRoots, err := fetchTargetRoots(
+		ctx, diffEngine, syncType, syncConfigs, idsToSync,
+		s.numWorkers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Now that we know the set of Universes we need to sync, we'll execute
+	// the diff operation for each of them. The diffs are returned in the
+	// order of the target roots, no matter in which order the workers
+	// finish.
+	syncDiffs, err := fn.ParMapLimit(
+		ctx, s.numWorkers(), targetRoots,
+		func(ctx context.Context, r BaseRoot) (*AssetSyncDiff, error) {
+			return s.syncRoot(ctx, r, diffEngine)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, we'll collect the diffs of all roots that weren't in sync
+	// already and return them to the caller.
+	syncDiffs = fn.Filter(syncDiffs, func(d *AssetSyncDiff) bool {
+		return d != nil
+	})
+
+	return fn.Map(syncDiffs, func(d *AssetSyncDiff) AssetSyncDiff {
+		return *d
+	}), nil
+}
+
+// fetchTargetRoots fetches the roots of the remote universes that are synced
+// for the given sync type and sync configs. If no IDs are given, all remote
+// universes are considered. The roots of the given IDs are fetched by up to the
+// given number of workers and returned in the order of the IDs.
+func fetchTargetRoots(ctx context.Context, diffEngine DiffEngine,
+	syncType SyncType, syncConfigs SyncConfigs, idsToSync []Identifier,
+	numWorkers int) ([]BaseRoot, error) {
+
 	var (
 		targetRoots []BaseRoot
 		err         error
@@ -87,26 +153,14 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 
 		// We'll use an error group to fetch each Universe root we need
 		// as a series of parallel requests backed by a worker pool.
-		rootsToSync := make(chan BaseRoot, len(idsToSync))
-		err = fn.ParSlice(
-			ctx, idsToSync,
-			func(ctx context.Context, id Identifier) error {
-				root, err := diffEngine.RootNode(ctx, id)
-				if err != nil {
-					return err
-				}
-
-				rootsToSync <- root
-				return nil
-			},
+		targetRoots, err = fn.ParMapLimit(
+			ctx, numWorkers, idsToSync, diffEngine.RootNode,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("unable to fetch roots for "+
 				"universe sync: %w", err)
 		}
 
-		targetRoots = fn.Collect(rootsToSync)
-
 	// Otherwise, we'll just fetch all the roots from the remote universe.
 	default:
 		log.Infof("Fetching all roots for remote Universe server...")
@@ -135,30 +189,22 @@ func (s *SimpleSyncer) executeSync(ctx context.Context, diffEngine DiffEngine,
 	log.Tracef("Obtained %v roots from remote Universe server: %v",
 		len(targetRoots), spew.Sdump(targetRoots))
 
-	// Now that we know the set of Universes we need to sync, we'll execute
-	// the diff operation for each of them.
-	syncDiffs := make(chan AssetSyncDiff, len(targetRoots))
-	err = fn.ParSlice(
-		ctx, targetRoots, func(ctx context.Context, r BaseRoot) error {
-			return s.syncRoot(ctx, r, diffEngine, syncDiffs)
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	// Finally, we'll collect all the diffs and return them to the caller.
-	return fn.Collect(syncDiffs), nil
+	return targetRoots, nil
 }
 
-// syncRoot attempts to sync the local Universe with the remote diff engine for
-// a specific base root.
-func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
-	diffEngine DiffEngine, result chan<- AssetSyncDiff) error {
+// diffLeafKeys compares the remote root against the local root of the same
+// universe. It returns the local root and the keys of the remote leaves that
+// are missing locally. If both roots match, the returned boolean is true and no
+// keys are returned. If the roots are of different versions, the keys of all
+// remote leaves are returned, so the universe is fully synced.
+func (s *SimpleSyncer) diffLeafKeys(ctx context.Context, remoteRoot BaseRoot,
+	diffEngine DiffEngine) (BaseRoot, []LeafKey, bool, error) {
 
 	// First, we'll compare the remote root against the local root.
 	uniID := remoteRoot.ID
 	localRoot, err := s.cfg.LocalDiffEngine.RootNode(ctx, uniID)
+	haveLocalRoot := err == nil
+	fullSync := false
 	switch {
 	// If we don't have this root, then we don't have anything to compare
 	// to, so we'll proceed as normal.
@@ -166,15 +212,26 @@ func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
 		// TODO(roasbeef): abstraction leak, error should be in
 		// universe package
 
+	// If the roots are of different versions, then they can't be
+	// compared, and neither can the leaves with the same keys. We fall
+	// back to a full sync of this universe.
+	case err == nil && localRoot.Version != remoteRoot.Version:
+		log.Warnf("UniverseRoot(%v) has version %v, local version "+
+			"is %v, falling back to full sync", uniID.String(),
+			remoteRoot.Version, localRoot.Version)
+
+		fullSync = true
+
 	// If the local root matches the remote root, then we're done here.
 	case err == nil && mssmt.IsEqualNode(localRoot, remoteRoot):
 		log.Infof("Root for %v matches, no sync needed",
 			uniID.String())
 
-		return nil
+		return localRoot, nil, true, nil
 
 	case err != nil:
-		return fmt.Errorf("unable to fetch local root: %v", err)
+		return BaseRoot{}, nil, false, fmt.Errorf("unable to fetch "+
+			"local root: %v", err)
 	}
 
 	log.Infof("UniverseRoot(%v) diverges, performing leaf diff...",
@@ -184,86 +241,148 @@ func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
 	// keys we need to fetch.
 	remoteUniKeys, err := diffEngine.UniverseLeafKeys(ctx, uniID)
 	if err != nil {
-		return err
+		return BaseRoot{}, nil, false, err
 	}
 	localUniKeys, err := s.cfg.LocalDiffEngine.UniverseLeafKeys(ctx, uniID)
 	if err != nil {
-		return err
+		return BaseRoot{}, nil, false, err
 	}
 
 	// With the set of keys fetched, we can now find the set of keys that
 	// need to be synced.
 	keysToFetch := fn.SetDiff(remoteUniKeys, localUniKeys)
 
+	// If the roots differ even though we already have all the remote keys
+	// and the remote has all of ours, then the leaves themselves must
+	// differ. As the keys alone can't tell us which ones, we fall back to
+	// a full sync of this universe, which overwrites our local leaves with
+	// the remote ones.
+	if haveLocalRoot && !fullSync && len(keysToFetch) == 0 &&
+		len(localUniKeys) == len(remoteUniKeys) {
+
+		log.Warnf("UniverseRoot(%v) diverges without missing keys, "+
+			"falling back to full sync", uniID.String())
+
+		fullSync = true
+	}
+	if fullSync {
+		keysToFetch = remoteUniKeys
+	}
+
 	log.Infof("UniverseRoot(%v): diff_size=%v", uniID.String(),
 		len(keysToFetch))
 	log.Tracef("UniverseRoot(%v): diff_size=%v, diff=%v", uniID.String(),
 		len(keysToFetch), spew.Sdump(keysToFetch))
 
+	return localRoot, keysToFetch, false, nil
+}
+
+// syncRoot attempts to sync the local Universe with the remote diff engine for
+// a specific base root. If the local root is already in sync, no diff is
+// returned.
+func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
+	diffEngine DiffEngine) (*AssetSyncDiff, error) {
+
+	uniID := remoteRoot.ID
+	localRoot, keysToFetch, inSync, err := s.diffLeafKeys(
+		ctx, remoteRoot, diffEngine,
+	)
+	if err != nil || inSync {
+		return nil, err
+	}
+
 	// Before we start fetching leaves, we already start our batch stream
 	// for the new leaves. This allows us to stream the new leaves to the
-	// local registrar as they're fetched.
+	// local registrar as they're fetched. To bound the memory used by a
+	// sync, we only buffer a single batch of fetched leaves. The fetching
+	// workers block until the batch streamer catches up, or until it
+	// fails, which cancels the shared context.
 	var (
-		fetchedLeaves = make(chan *IssuanceItem, len(keysToFetch))
+		fetchedLeaves = make(chan *IssuanceItem, s.cfg.SyncBatchSize)
 		newLeafProofs []*Leaf
-		batchSyncEG   errgroup.Group
 	)
+	batchSyncEG, streamCtx := errgroup.WithContext(ctx)
 
 	// We use an error group to simply the error handling of a goroutine.
 	batchSyncEG.Go(func() error {
+		var err error
 		newLeafProofs, err = s.batchStreamNewItems(
-			ctx, uniID, fetchedLeaves, len(keysToFetch),
+			streamCtx, uniID, fetchedLeaves, len(keysToFetch),
 		)
 		return err
 	})
 
 	// Now that we know where the divergence is, we can fetch the issuance
-	// proofs from the remote party.
-	err = fn.ParSlice(
-		ctx, keysToFetch, func(ctx context.Context, key LeafKey) error {
-			newProof, err := diffEngine.FetchIssuanceProof(
-				ctx, uniID, key,
+	// proofs from the remote party. We fetch them in batches, so only a
+	// single round trip is needed per batch.
+	batchSize := max(s.cfg.SyncBatchSize, 1)
+	keyBatches := make([][]LeafKey, 0, len(keysToFetch)/batchSize+1)
+	for start := 0; start < len(keysToFetch); start += batchSize {
+		end := min(start+batchSize, len(keysToFetch))
+		keyBatches = append(keyBatches, keysToFetch[start:end])
+	}
+	fetchErr := fn.ParSliceLimit(
+		streamCtx, s.numWorkers(), keyBatches,
+		func(ctx context.Context, keys []LeafKey) error {
+			leafProofs, err := diffEngine.FetchIssuanceProofs(
+				ctx, uniID, keys,
 			)
 			if err != nil {
 				return err
 			}
 
-			leafProof := newProof[0]
-
-			// Now that we have this leaf proof, we want to ensure
-			// that it's actually part of the remote root we were
-			// given.
-			validRoot, err := leafProof.VerifyRoot(remoteRoot)
-			if err != nil {
-				return fmt.Errorf("unable to verify root: %w",
-					err)
-			}
-			if !validRoot {
-				return fmt.Errorf("proof for key=%v is "+
-					"invalid", spew.Sdump(key))
+			if len(leafProofs) != len(keys) {
+				return fmt.Errorf("expected %d proofs, got %d",
+					len(keys), len(leafProofs))
 			}
 
-			// TODO(roasbeef): inclusion w/ root here, also that
-			// it's the expected asset ID
+			for i, leafProof := range leafProofs {
+				key := keys[i]
+
+				// Now that we have this leaf proof, we want to
+				// ensure that it's actually part of the remote
+				// root we were given.
+				validRoot, err := leafProof.VerifyRoot(
+					remoteRoot,
+				)
+				if err != nil {
+					return fmt.Errorf("unable to verify "+
+						"root: %w", err)
+				}
+				if !validRoot {
+					return fmt.Errorf("proof for key=%v "+
+						"is invalid", spew.Sdump(key))
+				}
+
+				// TODO(roasbeef): inclusion w/ root here, also
+				// that it's the expected asset ID
+
+				item := &IssuanceItem{
+					ID:   uniID,
+					Key:  key,
+					Leaf: leafProof.Leaf,
+				}
+				select {
+				case fetchedLeaves <- item:
 
-			fetchedLeaves <- &IssuanceItem{
-				ID:   uniID,
-				Key:  key,
-				Leaf: leafProof.Leaf,
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
 
 			return nil
 		},
 	)
-	if err != nil {
-		return err
-	}
 
-	// And now we wait for the batch streamer to finish as well.
+	// And now we wait for the batch streamer to finish as well. We do so
+	// even if fetching failed, so the streamer doesn't leak. If the
+	// streamer failed, its error is the root cause of any fetch error.
 	close(fetchedLeaves)
-	err = batchSyncEG.Wait()
-	if err != nil {
-		return err
+	if err := batchSyncEG.Wait(); err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fetchErr
 	}
 
 	log.Infof("Universe sync for UniverseRoot(%v) complete, %d "+
@@ -273,7 +392,7 @@ func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
 
 	// To wrap up, we'll collect the set of leaves then convert them into a
 	// final sync diff.
-	result <- AssetSyncDiff{
+	syncDiff := &AssetSyncDiff{
 		OldUniverseRoot: localRoot,
 		NewUniverseRoot: remoteRoot,
 		NewLeafProofs:   newLeafProofs,
@@ -283,7 +402,7 @@ func (s *SimpleSyncer) syncRoot(ctx context.Context, remoteRoot BaseRoot,
 	log.Tracef("Sync for UniverseRoot(%v) complete! New "+
 		"universe_root=%v", uniID.String(), spew.Sdump(remoteRoot))
 
-	return nil
+	return syncDiff, nil
 }
 
 // batchStreamNewItems streams the set of new items to the local registrar in
@@ -355,3 +474,186 @@ func (s *SimpleSyncer) SyncUniverse(ctx context.Context, host ServerAddr,
 	// remote instance.
 	return s.executeSync(ctx, diffEngine, syncType, syncConfigs, idsToSync)
 }
+
+// SyncEstimate is an estimate of the amount of data transferred and the time
+// spent by a sync with a remote universe.
+type SyncEstimate struct {
+	// NumRoots is the number of remote universe roots that differ from the
+	// local ones.
+	NumRoots uint64
+
+	// NumLeaves is the number of remote leaves that are missing locally.
+	NumLeaves uint64
+
+	// BatchSize is the configured number of leaves that are inserted
+	// locally in a single batch.
+	BatchSize uint64
+
+	// NumBatches is the number of batches the missing leaves are inserted
+	// in locally, given the batch size.
+	NumBatches uint64
+
+	// NumSamples is the number of leaf proofs that were fetched from the
+	// remote universe to base the estimate on.
+	NumSamples uint64
+
+	// TotalBytes is the estimated size of all leaf proofs that are
+	// fetched.
+	TotalBytes uint64
+
+	// Duration is the estimated duration of the sync. It only accounts
+	// for the time spent on diffing the universes and fetching the leaf
+	// proofs, not for inserting them locally.
+	Duration time.Duration
+}
+
+// EstimateSync estimates how much data a sync with the remote universe would
+// transfer and how long it would take, governed by the sync type and the set of
+// universe IDs to sync. To do so, the universes are diffed just like in a sync,
+// and a small sample of the missing leaf proofs is fetched.
+func (s *SimpleSyncer) EstimateSync(ctx context.Context, host ServerAddr,
+	syncType SyncType, syncConfigs SyncConfigs,
+	idsToSync ...Identifier) (*SyncEstimate, error) {
+
+	diffEngine, err := s.cfg.NewRemoteDiffEngine(host)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create remote diff "+
+			"engine: %w", err)
+	}
+
+	// We diff the universes in parallel just like a sync does, so the
+	// time it takes is a good estimate for that part of the sync.
+	diffStart := time.Now()
+	targetRoots, err := fetchTargetRoots(
+		ctx, diffEngine, syncType, syncConfigs, idsToSync,
+		s.numWorkers(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	type rootDiff struct {
+		id          Identifier
+		keysToFetch []LeafKey
+	}
+	rootDiffs := make(chan rootDiff, len(targetRoots))
+	err = fn.ParSliceLimit(
+		ctx, s.numWorkers(), targetRoots,
+		func(ctx context.Context, r BaseRoot) error {
+			_, keysToFetch, inSync, err := s.diffLeafKeys(
+				ctx, r, diffEngine,
+			)
+			if err != nil || inSync || len(keysToFetch) == 0 {
+				return err
+			}
+
+			rootDiffs <- rootDiff{
+				id:          r.ID,
+				keysToFetch: keysToFetch,
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	diffTime := time.Since(diffStart)
+
+	var (
+		estimate = SyncEstimate{
+			BatchSize: uint64(s.cfg.SyncBatchSize),
+		}
+		sampleBytes uint64
+		sampleTime  time.Duration
+	)
+	for _, diff := range fn.Collect(rootDiffs) {
+		numLeaves := uint64(len(diff.keysToFetch))
+		estimate.NumRoots++
+		estimate.NumLeaves += numLeaves
+		estimate.NumBatches += numBatches(
+			numLeaves, estimate.BatchSize,
+		)
+
+		if estimate.NumSamples >= maxSyncEstimateSamples {
+			continue
+		}
+
+		// We sample the first missing leaf of each universe, until we
+		// have enough samples.
+		fetchStart := time.Now()
+		leafProofs, err := diffEngine.FetchIssuanceProof(
+			ctx, diff.id, diff.keysToFetch[0],
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch sample leaf "+
+				"proof: %w", err)
+		}
+		sampleTime += time.Since(fetchStart)
+
+		var buf bytes.Buffer
+		if err := leafProofs[0].Leaf.Proof.Encode(&buf); err != nil {
+			return nil, err
+		}
+		sampleBytes += uint64(buf.Len())
+		estimate.NumSamples++
+	}
+
+	estimate.Duration = diffTime
+	if estimate.NumSamples == 0 {
+		return &estimate, nil
+	}
+
+	// The leaf proofs are fetched by a worker pool, so we expect as many of
+	// them to be fetched at a time as there are workers.
+	avgBytes := sampleBytes / estimate.NumSamples
+	avgTime := sampleTime / time.Duration(estimate.NumSamples)
+	numRounds := numBatches(estimate.NumLeaves, uint64(s.numWorkers()))
+
+	estimate.TotalBytes = avgBytes * estimate.NumLeaves
+	estimate.Duration += avgTime * time.Duration(numRounds)
+
+	return &estimate, nil
+}
+
+// numBatches returns the number of batches of the given size the given number
+// of items are split into.
+func numBatches(numItems, batchSize uint64) uint64 {
+	if batchSize == 0 {
+		return numItems
+	}
+
+	return (numItems + batchSize - 1) / batchSize
+}
+
+// CountRootDiff compares the roots of a remote universe with the roots of the
+// local universe. It returns the number of remote roots that aren't known
+// locally at all and the number of remote roots that are known locally but
+// have a different root hash. Only roots that may be inserted through
+// federation sync according to the given sync configs are counted.
+func CountRootDiff(localRoots, remoteRoots []BaseRoot,
+	syncConfigs SyncConfigs) (uint64, uint64) {
+
+	localHashes := make(map[string]mssmt.NodeHash, len(localRoots))
+	for _, root := range localRoots {
+		localHashes[root.ID.String()] = root.NodeHash()
+	}
+
+	var numMissing, numDiverged uint64
+	for _, root := range remoteRoots {
+		if !syncConfigs.IsSyncInsertEnabled(root.ID) {
+			continue
+		}
+
+		localHash, ok := localHashes[root.ID.String()]
+		switch {
+		case !ok:
+			numMissing++
+
+		case localHash != root.NodeHash():
+			numDiverged++
+		}
+	}
+
+	return numMissing, numDiverged
+}
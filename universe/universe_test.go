@@ -1,9 +1,16 @@
 package universe
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/lightninglabs/taproot-assets/asset"
+	"github.com/lightninglabs/taproot-assets/internal/test"
+	"github.com/lightninglabs/taproot-assets/mssmt"
 	"github.com/lightninglabs/taproot-assets/proof"
 	"github.com/stretchr/testify/require"
 )
@@ -105,3 +112,533 @@ func TestValidateProofUniverseType(t *testing.T) {
 		})
 	}
 }
+
+// TestCountRootDiff tests that the roots a remote universe has that the local
+// universe doesn't have, or has with a different root hash, are counted
+// correctly.
+func TestCountRootDiff(t *testing.T) {
+	t.Parallel()
+
+	newRoot := func(id Identifier, hash mssmt.NodeHash) BaseRoot {
+		return BaseRoot{
+			ID:   id,
+			Node: mssmt.NewComputedBranch(hash, 1),
+		}
+	}
+	randHash := func() mssmt.NodeHash {
+		return mssmt.NodeHash(asset.RandID(t))
+	}
+
+	var (
+		syncedID = Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		}
+		divergedID = Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		}
+		missingID = Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		}
+		transferID = Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeTransfer,
+		}
+		syncedHash = randHash()
+	)
+
+	localRoots := []BaseRoot{
+		newRoot(syncedID, syncedHash),
+		newRoot(divergedID, randHash()),
+	}
+	remoteRoots := []BaseRoot{
+		newRoot(syncedID, syncedHash),
+		newRoot(divergedID, randHash()),
+		newRoot(missingID, randHash()),
+		newRoot(transferID, randHash()),
+	}
+
+	// With only issuance proofs allowed to be synced, the missing transfer
+	// root shouldn't be counted.
+	syncConfigs := SyncConfigs{
+		GlobalSyncConfigs: []*FedGlobalSyncConfig{{
+			ProofType:       ProofTypeIssuance,
+			AllowSyncInsert: true,
+		}},
+	}
+	numMissing, numDiverged := CountRootDiff(
+		localRoots, remoteRoots, syncConfigs,
+	)
+	require.EqualValues(t, 1, numMissing)
+	require.EqualValues(t, 1, numDiverged)
+
+	// Once transfer proofs are allowed to be synced as well, it should be.
+	syncConfigs.GlobalSyncConfigs = append(
+		syncConfigs.GlobalSyncConfigs, &FedGlobalSyncConfig{
+			ProofType:       ProofTypeTransfer,
+			AllowSyncInsert: true,
+		},
+	)
+	numMissing, numDiverged = CountRootDiff(
+		localRoots, remoteRoots, syncConfigs,
+	)
+	require.EqualValues(t, 2, numMissing)
+	require.EqualValues(t, 1, numDiverged)
+}
+
+// mockDiffEngine is a diff engine backed by a fixed set of roots and leaves.
+type mockDiffEngine struct {
+	roots  map[Identifier]BaseRoot
+	keys   map[Identifier][]LeafKey
+	leaves map[LeafKey]*Proof
+
+	// fetchedBatches records the keys of each batch of proofs fetched.
+	fetchedBatches [][]LeafKey
+	fetchMtx       sync.Mutex
+}
+
+func (m *mockDiffEngine) RootNode(_ context.Context,
+	id Identifier) (BaseRoot, error) {
+
+	root, ok := m.roots[id]
+	if !ok {
+		return BaseRoot{}, ErrNoUniverseRoot
+	}
+
+	return root, nil
+}
+
+func (m *mockDiffEngine) RootNodes(context.Context) ([]BaseRoot, error) {
+	roots := make([]BaseRoot, 0, len(m.roots))
+	for _, root := range m.roots {
+		roots = append(roots, root)
+	}
+
+	return roots, nil
+}
+
+func (m *mockDiffEngine) UniverseLeafKeys(_ context.Context,
+	id Identifier) ([]LeafKey, error) {
+
+	return m.keys[id], nil
+}
+
+func (m *mockDiffEngine) FetchIssuanceProof(_ context.Context, _ Identifier,
+	key LeafKey) ([]*Proof, error) {
+
+	return []*Proof{m.leaves[key]}, nil
+}
+
+func (m *mockDiffEngine) FetchIssuanceProofs(_ context.Context, _ Identifier,
+	keys []LeafKey) ([]*Proof, error) {
+
+	m.fetchMtx.Lock()
+	m.fetchedBatches = append(m.fetchedBatches, keys)
+	m.fetchMtx.Unlock()
+
+	proofs := make([]*Proof, 0, len(keys))
+	for _, key := range keys {
+		leafProof, ok := m.leaves[key]
+		if !ok {
+			return nil, ErrNoUniverseProofFound
+		}
+
+		proofs = append(proofs, leafProof)
+	}
+
+	return proofs, nil
+}
+
+// TestEstimateSync tests that a sync estimate accounts for all leaves that are
+// missing locally and scales the size of the sampled leaf proofs accordingly.
+func TestEstimateSync(t *testing.T) {
+	t.Parallel()
+
+	newID := func() Identifier {
+		return Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		}
+	}
+	newRoot := func(id Identifier) BaseRoot {
+		hash := mssmt.NodeHash(asset.RandID(t))
+		return BaseRoot{
+			ID:   id,
+			Node: mssmt.NewComputedBranch(hash, 1),
+		}
+	}
+
+	leafProof := &Proof{
+		Leaf: &Leaf{
+			Proof: &proof.Proof{
+				Asset: randGenesisAsset(t),
+				InclusionProof: proof.TaprootProof{
+					InternalKey: test.RandPubKey(t),
+				},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, leafProof.Leaf.Proof.Encode(&buf))
+	proofSize := uint64(buf.Len())
+
+	newKeys := func(n int) []LeafKey {
+		keys := make([]LeafKey, n)
+		for i := range keys {
+			keys[i] = LeafKey{
+				OutPoint: test.RandOp(t),
+			}
+		}
+
+		return keys
+	}
+
+	var (
+		syncedID   = newID()
+		divergedID = newID()
+		missingID  = newID()
+		syncedRoot = newRoot(syncedID)
+
+		syncedKeys   = newKeys(2)
+		divergedKeys = newKeys(3)
+		missingKeys  = newKeys(3)
+	)
+
+	local := &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{
+			syncedID:   syncedRoot,
+			divergedID: newRoot(divergedID),
+		},
+		keys: map[Identifier][]LeafKey{
+			syncedID:   syncedKeys,
+			divergedID: divergedKeys[:1],
+		},
+	}
+	remote := &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{
+			syncedID:   syncedRoot,
+			divergedID: newRoot(divergedID),
+			missingID:  newRoot(missingID),
+		},
+		keys: map[Identifier][]LeafKey{
+			syncedID:   syncedKeys,
+			divergedID: divergedKeys,
+			missingID:  missingKeys,
+		},
+		leaves: make(map[LeafKey]*Proof),
+	}
+	for _, key := range append(divergedKeys, missingKeys...) {
+		remote.leaves[key] = leafProof
+	}
+
+	syncer := NewSimpleSyncer(SimpleSyncCfg{
+		LocalDiffEngine: local,
+		NewRemoteDiffEngine: func(ServerAddr) (DiffEngine, error) {
+			return remote, nil
+		},
+		SyncBatchSize: 2,
+	})
+	syncConfigs := SyncConfigs{
+		GlobalSyncConfigs: []*FedGlobalSyncConfig{{
+			ProofType:       ProofTypeIssuance,
+			AllowSyncInsert: true,
+		}},
+	}
+
+	// Two leaves are missing from the diverged universe and three from the
+	// missing one, which are inserted in one and two batches respectively.
+	estimate, err := syncer.EstimateSync(
+		context.Background(), ServerAddr{}, SyncIssuance, syncConfigs,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, estimate.NumRoots)
+	require.EqualValues(t, 5, estimate.NumLeaves)
+	require.EqualValues(t, 2, estimate.BatchSize)
+	require.EqualValues(t, 3, estimate.NumBatches)
+	require.EqualValues(t, 2, estimate.NumSamples)
+	require.Equal(t, 5*proofSize, estimate.TotalBytes)
+
+	// Nothing needs to be synced for a universe that is in sync.
+	estimate, err = syncer.EstimateSync(
+		context.Background(), ServerAddr{}, SyncIssuance, syncConfigs,
+		syncedID,
+	)
+	require.NoError(t, err)
+	require.Zero(t, estimate.NumLeaves)
+	require.Zero(t, estimate.TotalBytes)
+}
+
+// mockBatchRegistrar is a batch registrar that records all registered items.
+type mockBatchRegistrar struct {
+	sync.Mutex
+
+	items []*IssuanceItem
+	err   error
+}
+
+func (m *mockBatchRegistrar) RegisterIssuance(context.Context, Identifier,
+	LeafKey, *Leaf) (*Proof, error) {
+
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockBatchRegistrar) RegisterNewIssuanceBatch(_ context.Context,
+	items []*IssuanceItem) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.items = append(m.items, items...)
+
+	return nil
+}
+
+// addRemoteUniverse adds a universe with the given number of leaves to the
+// mock diff engine. The leaf proofs carry valid inclusion proofs for the
+// universe root, so they can be synced.
+func addRemoteUniverse(t *testing.T, engine *mockDiffEngine, id Identifier,
+	numLeaves int) {
+
+	ctx := context.Background()
+	tree := mssmt.NewFullTree(mssmt.NewDefaultStore())
+
+	keys := make([]LeafKey, numLeaves)
+	leaves := make([]*Leaf, numLeaves)
+	for i := range keys {
+		genAsset := randGenesisAsset(t)
+		keys[i] = LeafKey{
+			OutPoint:  test.RandOp(t),
+			ScriptKey: &genAsset.ScriptKey,
+		}
+		leaves[i] = &Leaf{
+			Proof: &proof.Proof{
+				Asset: genAsset,
+				InclusionProof: proof.TaprootProof{
+					InternalKey: test.RandPubKey(t),
+				},
+			},
+			Amt: genAsset.Amount,
+		}
+
+		leafNode, err := leaves[i].SmtLeafNode()
+		require.NoError(t, err)
+
+		_, err = tree.Insert(ctx, keys[i].UniverseKey(), leafNode)
+		require.NoError(t, err)
+	}
+
+	root, err := tree.Root(ctx)
+	require.NoError(t, err)
+
+	for i, key := range keys {
+		inclusionProof, err := tree.MerkleProof(ctx, key.UniverseKey())
+		require.NoError(t, err)
+
+		engine.leaves[key] = &Proof{
+			Leaf:                   leaves[i],
+			LeafKey:                key,
+			UniverseRoot:           root,
+			UniverseInclusionProof: inclusionProof,
+		}
+	}
+
+	engine.roots[id] = BaseRoot{
+		ID:   id,
+		Node: root,
+	}
+	engine.keys[id] = keys
+}
+
+// TestSyncUniverseParallel tests that universes are synced by a bounded number
+// of workers, that the sync diffs are returned in a deterministic order and
+// that a failing registrar doesn't block the workers.
+func TestSyncUniverseParallel(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numUniverses = 10
+		numLeaves    = 5
+	)
+
+	remote := &mockDiffEngine{
+		roots:  make(map[Identifier]BaseRoot),
+		keys:   make(map[Identifier][]LeafKey),
+		leaves: make(map[LeafKey]*Proof),
+	}
+	ids := make([]Identifier, numUniverses)
+	for i := range ids {
+		ids[i] = Identifier{
+			AssetID:   asset.RandID(t),
+			ProofType: ProofTypeIssuance,
+		}
+		addRemoteUniverse(t, remote, ids[i], numLeaves)
+	}
+
+	registrar := &mockBatchRegistrar{}
+	syncer := NewSimpleSyncer(SimpleSyncCfg{
+		LocalDiffEngine: &mockDiffEngine{},
+		NewRemoteDiffEngine: func(ServerAddr) (DiffEngine, error) {
+			return remote, nil
+		},
+		LocalRegistrar: registrar,
+		SyncBatchSize:  2,
+		SyncWorkers:    3,
+	})
+	syncConfigs := SyncConfigs{
+		GlobalSyncConfigs: []*FedGlobalSyncConfig{{
+			ProofType:       ProofTypeIssuance,
+			AllowSyncInsert: true,
+		}},
+	}
+
+	// All universes are synced, with the diffs being returned in the order
+	// the universes were requested in.
+	syncDiffs, err := syncer.SyncUniverse(
+		context.Background(), ServerAddr{}, SyncIssuance, syncConfigs,
+		ids...,
+	)
+	require.NoError(t, err)
+	require.Len(t, syncDiffs, numUniverses)
+	for i, syncDiff := range syncDiffs {
+		require.Equal(t, ids[i], syncDiff.NewUniverseRoot.ID)
+		require.Len(t, syncDiff.NewLeafProofs, numLeaves)
+	}
+	require.Len(t, registrar.items, numUniverses*numLeaves)
+
+	// If the registrar fails, the sync must fail as well instead of the
+	// workers blocking on the full leaf buffer.
+	errRegister := errors.New("unable to register")
+	registrar.err = errRegister
+
+	_, err = syncer.SyncUniverse(
+		context.Background(), ServerAddr{}, SyncIssuance, syncConfigs,
+		ids...,
+	)
+	require.ErrorIs(t, err, errRegister)
+}
+
+// TestSyncUniverseIncremental tests that only the leaves missing locally are
+// fetched from the remote universe, in batches, and that a universe whose
+// leaves diverge without any missing keys or whose root is of another version
+// is fully synced instead.
+func TestSyncUniverseIncremental(t *testing.T) {
+	t.Parallel()
+
+	const numLeaves = 5
+
+	newRemote := func(id Identifier) *mockDiffEngine {
+		remote := &mockDiffEngine{
+			roots:  make(map[Identifier]BaseRoot),
+			keys:   make(map[Identifier][]LeafKey),
+			leaves: make(map[LeafKey]*Proof),
+		}
+		addRemoteUniverse(t, remote, id, numLeaves)
+
+		return remote
+	}
+	syncConfigs := SyncConfigs{
+		GlobalSyncConfigs: []*FedGlobalSyncConfig{{
+			ProofType:       ProofTypeIssuance,
+			AllowSyncInsert: true,
+		}},
+	}
+	syncUniverse := func(local, remote *mockDiffEngine,
+		id Identifier) ([]AssetSyncDiff, error) {
+
+		remoteEngine := func(ServerAddr) (DiffEngine, error) {
+			return remote, nil
+		}
+		syncer := NewSimpleSyncer(SimpleSyncCfg{
+			LocalDiffEngine:     local,
+			NewRemoteDiffEngine: remoteEngine,
+			LocalRegistrar:      &mockBatchRegistrar{},
+			SyncBatchSize:       2,
+			SyncWorkers:         1,
+		})
+
+		return syncer.SyncUniverse(
+			context.Background(), ServerAddr{}, SyncIssuance,
+			syncConfigs, id,
+		)
+	}
+
+	id := Identifier{
+		AssetID:   asset.RandID(t),
+		ProofType: ProofTypeIssuance,
+	}
+	divergedRoot := BaseRoot{
+		ID: id,
+		Node: mssmt.NewComputedBranch(
+			mssmt.NodeHash(asset.RandID(t)), 1,
+		),
+	}
+
+	// If the local universe already has some of the leaves, only the
+	// missing ones are fetched, in batches of the configured size.
+	remote := newRemote(id)
+	remoteKeys := remote.keys[id]
+	local := &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{id: divergedRoot},
+		keys:  map[Identifier][]LeafKey{id: remoteKeys[:2]},
+	}
+	syncDiffs, err := syncUniverse(local, remote, id)
+	require.NoError(t, err)
+	require.Len(t, syncDiffs, 1)
+	require.Len(t, syncDiffs[0].NewLeafProofs, numLeaves-2)
+	require.Len(t, remote.fetchedBatches, 2)
+	require.ElementsMatch(
+		t, remoteKeys[2:], append(
+			remote.fetchedBatches[0], remote.fetchedBatches[1]...,
+		),
+	)
+
+	// If the roots diverge although no keys are missing on either side, the
+	// leaves themselves differ, so all of them are fetched again.
+	remote = newRemote(id)
+	remoteKeys = remote.keys[id]
+	local = &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{id: divergedRoot},
+		keys:  map[Identifier][]LeafKey{id: remoteKeys},
+	}
+	syncDiffs, err = syncUniverse(local, remote, id)
+	require.NoError(t, err)
+	require.Len(t, syncDiffs, 1)
+	require.Len(t, syncDiffs[0].NewLeafProofs, numLeaves)
+	require.Len(t, remote.fetchedBatches, 3)
+
+	// If the remote root is of another version, the roots can't be
+	// compared, even if their hashes match. So all leaves are fetched,
+	// including those we already have.
+	remote = newRemote(id)
+	remoteKeys = remote.keys[id]
+	localRoot := remote.roots[id]
+	remoteRoot := localRoot
+	remoteRoot.Version = LatestRootVersion + 1
+	remote.roots[id] = remoteRoot
+	local = &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{id: localRoot},
+		keys:  map[Identifier][]LeafKey{id: remoteKeys[:2]},
+	}
+	syncDiffs, err = syncUniverse(local, remote, id)
+	require.NoError(t, err)
+	require.Len(t, syncDiffs, 1)
+	require.Len(t, syncDiffs[0].NewLeafProofs, numLeaves)
+	require.Len(t, remote.fetchedBatches, 3)
+
+	// If the roots match, nothing is fetched at all.
+	remote = newRemote(id)
+	local = &mockDiffEngine{
+		roots: map[Identifier]BaseRoot{id: remote.roots[id]},
+		keys:  map[Identifier][]LeafKey{id: remote.keys[id]},
+	}
+	syncDiffs, err = syncUniverse(local, remote, id)
+	require.NoError(t, err)
+	require.Empty(t, syncDiffs)
+	require.Empty(t, remote.fetchedBatches)
+}
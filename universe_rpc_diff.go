@@ -10,6 +10,8 @@ import (
 	unirpc "github.com/lightninglabs/taproot-assets/taprpc/universerpc"
 	"github.com/lightninglabs/taproot-assets/universe"
 	"golang.org/x/exp/maps"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // RpcUniverseDiff is an implementation of the universe.DiffEngine interface
@@ -50,8 +52,9 @@ func unmarshalUniverseRoot(
 	}
 
 	return universe.BaseRoot{
-		ID:   id,
-		Node: unmarshalMerkleSumNode(root.MssmtRoot),
+		ID:      id,
+		Node:    unmarshalMerkleSumNode(root.MssmtRoot),
+		Version: universe.RootVersion(root.RootVersion),
 	}, nil
 }
 
@@ -66,8 +69,9 @@ func unmarshalUniverseRoots(
 		}
 
 		baseRoots = append(baseRoots, universe.BaseRoot{
-			ID:   id,
-			Node: unmarshalMerkleSumNode(root.MssmtRoot),
+			ID:      id,
+			Node:    unmarshalMerkleSumNode(root.MssmtRoot),
+			Version: universe.RootVersion(root.RootVersion),
 		})
 	}
 
@@ -165,19 +169,105 @@ func (r *RpcUniverseDiff) FetchIssuanceProof(ctx context.Context,
 		return nil, err
 	}
 
-	uniRoot, err := unmarshalUniverseRoot(uProofs.UniverseRoot)
+	uniProof, err := unmarshalUniverseProof(key, uProofs)
 	if err != nil {
 		return nil, err
 	}
 
-	assetLeaf, err := unmarshalAssetLeaf(uProofs.AssetLeaf)
+	return []*universe.Proof{uniProof}, nil
+}
+
+// FetchIssuanceProofs fetches the proofs of a set of leaves of the target
+// universe. The keys are queried in batches of at most
+// universe.MaxQueryProofsKeys. If the remote server doesn't support batch
+// queries yet, we fall back to querying each leaf individually.
+func (r *RpcUniverseDiff) FetchIssuanceProofs(ctx context.Context,
+	id universe.Identifier,
+	keys []universe.LeafKey) ([]*universe.Proof, error) {
+
+	uniID, err := MarshalUniID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	proofs := make([]*universe.Proof, 0, len(keys))
+	for start := 0; start < len(keys); {
+		end := min(start+universe.MaxQueryProofsKeys, len(keys))
+		batch := keys[start:end]
+
+		rpcKeys := make([]*universerpc.AssetKey, 0, len(batch))
+		for _, key := range batch {
+			rpcKeys = append(rpcKeys, marshalLeafKey(key))
+		}
+
+		resp, err := r.conn.QueryProofs(
+			ctx, &universerpc.QueryProofsRequest{
+				Id:       uniID,
+				LeafKeys: rpcKeys,
+			},
+		)
+		statusCode, isStatusErr := status.FromError(err)
+		switch {
+		// Older servers don't know about the batch query, so we'll
+		// fetch the remaining proofs one by one.
+		case isStatusErr && statusCode.Code() == codes.Unimplemented:
+			for _, key := range keys[start:] {
+				leafProofs, err := r.FetchIssuanceProof(
+					ctx, id, key,
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				proofs = append(proofs, leafProofs[0])
+			}
+
+			return proofs, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		if len(resp.Proofs) != len(batch) {
+			return nil, fmt.Errorf("expected %d proofs, got %d",
+				len(batch), len(resp.Proofs))
+		}
+
+		for i, uProof := range resp.Proofs {
+			uniProof, err := unmarshalUniverseProof(
+				batch[i], uProof,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			proofs = append(proofs, uniProof)
+		}
+
+		start = end
+	}
+
+	return proofs, nil
+}
+
+// unmarshalUniverseProof unmarshals a universe proof for the given leaf key
+// from its RPC form.
+func unmarshalUniverseProof(key universe.LeafKey,
+	uProof *universerpc.AssetProofResponse) (*universe.Proof, error) {
+
+	uniRoot, err := unmarshalUniverseRoot(uProof.UniverseRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	assetLeaf, err := unmarshalAssetLeaf(uProof.AssetLeaf)
 	if err != nil {
 		return nil, err
 	}
 
 	var compressedProof mssmt.CompressedProof
 	err = compressedProof.Decode(
-		bytes.NewReader(uProofs.UniverseInclusionProof),
+		bytes.NewReader(uProof.UniverseInclusionProof),
 	)
 	if err != nil {
 		return nil, err
@@ -188,14 +278,12 @@ func (r *RpcUniverseDiff) FetchIssuanceProof(ctx context.Context,
 		return nil, err
 	}
 
-	uniProof := &universe.Proof{
+	return &universe.Proof{
 		LeafKey:                key,
 		UniverseRoot:           uniRoot,
 		UniverseInclusionProof: inclusionProof,
 		Leaf:                   assetLeaf,
-	}
-
-	return []*universe.Proof{uniProof}, nil
+	}, nil
 }
 
 // A compile time interface to ensure that RpcUniverseDiff implements the
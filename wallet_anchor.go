@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -12,6 +13,7 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightninglabs/lndclient"
 	"github.com/lightninglabs/taproot-assets/tapfreighter"
 	"github.com/lightninglabs/taproot-assets/tapgarden"
@@ -40,6 +42,15 @@ const (
 	defaultChangeType = walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
 )
 
+// lndInternalLockID is the ID lnd leases the inputs of the PSBTs it funds
+// with. It is the SHA256 hash of the string "lnd-internal-lock-id".
+var lndInternalLockID = wtxmgr.LockID{
+	0xed, 0xe1, 0x9a, 0x92, 0xed, 0x32, 0x1a, 0x47,
+	0x05, 0xf8, 0xa1, 0xcc, 0xcc, 0x1d, 0x4f, 0x61,
+	0x82, 0x54, 0x5d, 0x4b, 0xb4, 0xfa, 0xe0, 0x8b,
+	0xd5, 0x93, 0x78, 0x31, 0xb7, 0xe3, 0x8f, 0x98,
+}
+
 // FundPsbt attaches enough inputs to the target PSBT packet for it to be
 // valid.
 func (l *LndRpcWalletAnchor) FundPsbt(ctx context.Context, packet *psbt.Packet,
@@ -130,11 +141,93 @@ func (l *LndRpcWalletAnchor) ImportTaprootOutput(ctx context.Context,
 	return addr, nil
 }
 
-// UnlockInput unlocks the set of target inputs after a batch is abandoned.
-func (l *LndRpcWalletAnchor) UnlockInput(ctx context.Context) error {
+// LeaseOutput leases the given output of the wallet for the given duration, so
+// it isn't selected to fund another transaction. We use the same lock ID lnd
+// uses when funding a PSBT, so an existing lease is extended and can be
+// released with ReleaseOutput.
+func (l *LndRpcWalletAnchor) LeaseOutput(ctx context.Context, op wire.OutPoint,
+	duration time.Duration) error {
+
+	_, err := l.lnd.WalletKit.LeaseOutput(
+		ctx, lndInternalLockID, op, duration,
+	)
+	return err
+}
+
+// ReleaseOutput releases the lease the wallet acquired on the given output when
+// funding a PSBT with it, so it can be selected again.
+func (l *LndRpcWalletAnchor) ReleaseOutput(ctx context.Context,
+	op wire.OutPoint) error {
+
+	return l.lnd.WalletKit.ReleaseOutput(
+		ctx, lndInternalLockID, op,
+	)
+}
+
+// ClaimOutputs moves the leases the wallet holds on the given outputs to the
+// given claim ID for the given duration. lnd refuses to lease an output that is
+// already leased with another ID, so only one claim can succeed. If an output
+// can't be claimed, the outputs claimed before it are released again.
+func (l *LndRpcWalletAnchor) ClaimOutputs(ctx context.Context,
+	claimID [32]byte, ops []wire.OutPoint, duration time.Duration) error {
+
+	for idx, op := range ops {
+		// The output isn't necessarily leased by the wallet anymore, so
+		// we ignore a failure to release it. Leasing it with the claim
+		// ID below fails if someone else holds a lease on it.
+		_ = l.lnd.WalletKit.ReleaseOutput(ctx, lndInternalLockID, op)
+
+		_, err := l.lnd.WalletKit.LeaseOutput(
+			ctx, claimID, op, duration,
+		)
+		if err == nil {
+			continue
+		}
+
+		for _, claimedOp := range ops[:idx] {
+			_ = l.lnd.WalletKit.ReleaseOutput(
+				ctx, claimID, claimedOp,
+			)
+		}
+
+		return fmt.Errorf("unable to claim output %v: %w", op, err)
+	}
+
 	return nil
 }
 
+// OutputClaims returns the claim IDs of all outputs of the wallet that are
+// leased with an ID other than the one lnd uses when funding a PSBT, keyed by
+// their outpoint.
+func (l *LndRpcWalletAnchor) OutputClaims(
+	ctx context.Context) (map[wire.OutPoint][32]byte, error) {
+
+	leases, err := l.lnd.WalletKit.ListLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make(map[wire.OutPoint][32]byte)
+	for _, lease := range leases {
+		if lease.LockID == lndInternalLockID {
+			continue
+		}
+
+		claims[lease.Outpoint] = lease.LockID
+	}
+
+	return claims, nil
+}
+
+// NextAddr returns a new Taproot address of the default account of the wallet.
+func (l *LndRpcWalletAnchor) NextAddr(
+	ctx context.Context) (btcutil.Address, error) {
+
+	return l.lnd.WalletKit.NextAddr(
+		ctx, "", walletrpc.AddressType_TAPROOT_PUBKEY, false,
+	)
+}
+
 // ListUnspentImportScripts lists all UTXOs of the imported Taproot scripts.
 func (l *LndRpcWalletAnchor) ListUnspentImportScripts(
 	ctx context.Context) ([]*lnwallet.Utxo, error) {
@@ -0,0 +1,495 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// SignatureHeader is the header of a webhook request that holds the
+	// signature of the request. The signature is the hex encoded
+	// HMAC-SHA256 of the timestamp header, a dot and the request body,
+	// keyed with the configured secret and prefixed with "sha256=".
+	SignatureHeader = "X-Tapd-Signature"
+
+	// TimestampHeader is the header of a webhook request that holds the
+	// unix timestamp in seconds the request was signed at. Receivers
+	// should reject requests with an old timestamp to prevent replays.
+	TimestampHeader = "X-Tapd-Timestamp"
+
+	// DeliveryIDHeader is the header of a webhook request that holds the
+	// unique ID of the delivery. All attempts to deliver the same event
+	// carry the same ID, so receivers can skip duplicates.
+	DeliveryIDHeader = "X-Tapd-Delivery-Id"
+
+	// signaturePrefix is the prefix of the signature header value that
+	// identifies the signature algorithm.
+	signaturePrefix = "sha256="
+)
+
+// Config is the config of the webhook the send and receive asset events are
+// delivered to.
+type Config struct {
+	URL string `long:"url" description:"The http(s) URL the JSON encoded send and receive asset events are POSTed to. These are the same events that are streamed by SubscribeSendAssetEventNtfns and SubscribeReceiveAssetEventNtfns. If not set, no webhook is notified."`
+
+	Secret string `long:"secret" description:"The secret each webhook request is signed with. The X-Tapd-Signature header of a request holds 'sha256=' followed by the hex encoded HMAC-SHA256 of the X-Tapd-Timestamp header, a dot and the request body."`
+
+	RequestTimeout time.Duration `long:"requesttimeout" description:"The timeout of a single webhook request."`
+
+	MaxAttempts uint32 `long:"maxattempts" description:"The maximum number of attempts to deliver an event to the webhook. An event that still can't be delivered after the last attempt, or is rejected by the webhook with a client error, is written to the dead letter file."`
+
+	InitialBackoff time.Duration `long:"initialbackoff" description:"The time to wait before the first retry of a failed webhook delivery. The time is doubled for every further retry."`
+
+	MaxBackoff time.Duration `long:"maxbackoff" description:"The maximum time to wait between two retries of a failed webhook delivery."`
+
+	DeadLetterFile string `long:"deadletterfile" description:"The file the events that couldn't be delivered to the webhook are appended to, one JSON object per line. Defaults to webhook_dead_letters.log in the network data directory."`
+}
+
+// Validate returns an error if the config can't be used to deliver events to
+// a webhook.
+func (c *Config) Validate() error {
+	webhookURL, err := url.ParseRequestURI(c.URL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+
+	switch {
+	case webhookURL.Scheme != "http" && webhookURL.Scheme != "https":
+		return fmt.Errorf("webhook url must use http(s), got %v",
+			webhookURL.Scheme)
+
+	case c.Secret == "":
+		return fmt.Errorf("webhook secret must be set")
+
+	case c.RequestTimeout <= 0:
+		return fmt.Errorf("webhook request timeout must be positive")
+
+	case c.MaxAttempts == 0:
+		return fmt.Errorf("webhook max attempts must be positive")
+
+	case c.InitialBackoff <= 0:
+		return fmt.Errorf("webhook initial backoff must be positive")
+
+	case c.MaxBackoff < c.InitialBackoff:
+		return fmt.Errorf("webhook max backoff must not be smaller " +
+			"than the initial backoff")
+	}
+
+	return nil
+}
+
+// EventSource is a publisher of events that are delivered to the webhook.
+type EventSource struct {
+	// Type is the type of the events of the source, which is reported in
+	// the type field of the payload.
+	Type string
+
+	// Publisher is the publisher of the events.
+	Publisher fn.EventPublisher[fn.Event, bool]
+
+	// Marshal maps an event of the publisher to its RPC counterpart. If
+	// nil is returned without an error, the event isn't delivered.
+	Marshal func(fn.Event) (proto.Message, error)
+}
+
+// Payload is the JSON encoded body of a webhook request.
+type Payload struct {
+	// DeliveryID is the unique ID of the delivery, which is also sent in
+	// the DeliveryIDHeader.
+	DeliveryID string `json:"delivery_id"`
+
+	// Type is the type of the event source.
+	Type string `json:"type"`
+
+	// Event is the JSON encoded RPC event.
+	Event json.RawMessage `json:"event"`
+}
+
+// DeadLetter is an event that couldn't be delivered to the webhook. Dead
+// letters are appended to the dead letter file as JSON objects, one per line.
+type DeadLetter struct {
+	// Time is the time the delivery was given up on.
+	Time time.Time `json:"time"`
+
+	// URL is the URL of the webhook the event couldn't be delivered to.
+	URL string `json:"url"`
+
+	// Attempts is the number of times the delivery was attempted.
+	Attempts uint32 `json:"attempts"`
+
+	// Error is the error of the last delivery attempt.
+	Error string `json:"error"`
+
+	// Payload is the body of the webhook request.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// delivery is an event that is delivered to the webhook.
+type delivery struct {
+	// id is the unique ID of the delivery.
+	id string
+
+	// body is the JSON encoded payload of the delivery.
+	body []byte
+}
+
+// StatusError is the error of a webhook request that was answered with a
+// status code other than 2xx.
+type StatusError struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+}
+
+// Error returns a human readable description of the error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d %s",
+		e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// isPermanentErr returns true if the given delivery error won't go away if
+// the delivery is retried. Client errors are permanent, except for timeouts
+// and rate limiting.
+func isPermanentErr(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+
+	default:
+		return statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+	}
+}
+
+// Sign returns the value of the signature header of a webhook request with
+// the given timestamp header and body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte{'.'})
+	_, _ = mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NotifierConfig is the config of the webhook notifier.
+type NotifierConfig struct {
+	// Cfg is the config of the webhook.
+	Cfg *Config
+
+	// Sources are the publishers of the events that are delivered to the
+	// webhook.
+	Sources []EventSource
+}
+
+// Notifier delivers the events of a set of publishers to a webhook. The events
+// of each publisher are delivered in order, a delivery that fails is retried
+// with an exponential backoff. Events that can't be delivered are written to
+// the dead letter file.
+type Notifier struct {
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	cfg *NotifierConfig
+
+	client *http.Client
+
+	// receivers are the event receivers registered with the publishers of
+	// the sources, in the same order as the sources.
+	receivers []*fn.EventReceiver[fn.Event]
+
+	// deadLetterMtx serializes writes to the dead letter file.
+	deadLetterMtx sync.Mutex
+
+	*fn.ContextGuard
+}
+
+// NewNotifier creates a new webhook notifier.
+func NewNotifier(cfg *NotifierConfig) *Notifier {
+	return &Notifier{
+		cfg:    cfg,
+		client: &http.Client{},
+		ContextGuard: &fn.ContextGuard{
+			DefaultTimeout: cfg.Cfg.RequestTimeout,
+			Quit:           make(chan struct{}),
+		},
+	}
+}
+
+// Start subscribes to the events of all sources and starts delivering them to
+// the webhook.
+func (n *Notifier) Start() error {
+	var startErr error
+	n.startOnce.Do(func() {
+		log.Infof("Starting webhook notifier for %v", n.cfg.Cfg.URL)
+
+		for idx := range n.cfg.Sources {
+			source := n.cfg.Sources[idx]
+
+			receiver := fn.NewEventReceiver[fn.Event](
+				fn.DefaultQueueSize,
+			)
+			err := source.Publisher.RegisterSubscriber(
+				receiver, false, false,
+			)
+			if err != nil {
+				startErr = fmt.Errorf("unable to subscribe to "+
+					"%v events: %w", source.Type, err)
+				return
+			}
+			n.receivers = append(n.receivers, receiver)
+
+			n.Wg.Add(1)
+			go n.deliverEvents(source, receiver)
+		}
+	})
+
+	return startErr
+}
+
+// Stop stops delivering events and removes the subscriptions of the notifier.
+// Events that weren't delivered yet are dropped.
+func (n *Notifier) Stop() error {
+	n.stopOnce.Do(func() {
+		log.Info("Stopping webhook notifier")
+
+		close(n.Quit)
+		n.Wg.Wait()
+
+		for idx, receiver := range n.receivers {
+			publisher := n.cfg.Sources[idx].Publisher
+			err := publisher.RemoveSubscriber(receiver)
+			if err != nil {
+				log.Warnf("Unable to remove webhook "+
+					"subscriber: %v", err)
+			}
+		}
+	})
+
+	return nil
+}
+
+// deliverEvents delivers the events of the given source to the webhook, one
+// at a time.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (n *Notifier) deliverEvents(source EventSource,
+	receiver *fn.EventReceiver[fn.Event]) {
+
+	defer n.Wg.Done()
+
+	for {
+		select {
+		case event := <-receiver.NewItemCreated.ChanOut():
+			d, err := n.newDelivery(source, event)
+			if err != nil {
+				log.Errorf("Unable to encode %v event %T for "+
+					"webhook: %v", source.Type, event, err)
+				continue
+			}
+
+			// The source isn't interested in delivering this
+			// event.
+			if d == nil {
+				continue
+			}
+
+			n.deliverWithRetry(d)
+
+		case <-n.Quit:
+			return
+		}
+	}
+}
+
+// newDelivery creates the delivery of the given event of the given source to
+// the webhook. Nil is returned if the event shouldn't be delivered.
+func (n *Notifier) newDelivery(source EventSource,
+	event fn.Event) (*delivery, error) {
+
+	rpcEvent, err := source.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if rpcEvent == nil {
+		return nil, nil
+	}
+
+	eventJSON, err := taprpc.RESTJsonMarshalOpts.Marshal(rpcEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	var deliveryID [16]byte
+	if _, err := rand.Read(deliveryID[:]); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(deliveryID[:])
+
+	body, err := json.Marshal(&Payload{
+		DeliveryID: id,
+		Type:       source.Type,
+		Event:      eventJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &delivery{
+		id:   id,
+		body: body,
+	}, nil
+}
+
+// deliverWithRetry delivers the given event to the webhook until it succeeds,
+// fails with a permanent error or the maximum number of attempts is reached.
+// The wait between two attempts starts at the initial backoff and doubles with
+// every attempt, up to the maximum backoff. An event that can't be delivered
+// is written to the dead letter file.
+func (n *Notifier) deliverWithRetry(d *delivery) {
+	cfg := n.cfg.Cfg
+
+	backoff := cfg.InitialBackoff
+	for attempt := uint32(1); ; attempt++ {
+		err := n.deliver(d)
+		if err == nil {
+			return
+		}
+
+		if isPermanentErr(err) || attempt >= cfg.MaxAttempts {
+			log.Errorf("Unable to deliver event %v to webhook "+
+				"after %d attempt(s), writing it to the dead "+
+				"letters: %v", d.id, attempt, err)
+
+			n.deadLetter(d, attempt, err)
+
+			return
+		}
+
+		log.Warnf("Webhook delivery attempt %d of %d failed, "+
+			"retrying in %v: %v", attempt, cfg.MaxAttempts,
+			backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-n.Quit:
+			log.Debugf("Aborted webhook delivery of event %v",
+				d.id)
+			return
+		}
+
+		backoff = min(2*backoff, cfg.MaxBackoff)
+	}
+}
+
+// deliver makes a single signed request to deliver the given event to the
+// webhook.
+func (n *Notifier) deliver(d *delivery) error {
+	// The request times out after the default timeout of the context
+	// guard, which is the configured request timeout.
+	ctx, cancel := n.WithCtxQuit()
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, n.cfg.Cfg.URL, bytes.NewReader(d.body),
+	)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(DeliveryIDHeader, d.id)
+	req.Header.Set(
+		SignatureHeader,
+		Sign([]byte(n.cfg.Cfg.Secret), timestamp, d.body),
+	)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// We drain the body, so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// deadLetter appends the given event that couldn't be delivered to the dead
+// letter file. If no dead letter file is configured, the event is logged
+// instead.
+func (n *Notifier) deadLetter(d *delivery, attempts uint32, err error) {
+	deadLetter := DeadLetter{
+		Time:     time.Now().UTC(),
+		URL:      n.cfg.Cfg.URL,
+		Attempts: attempts,
+		Error:    err.Error(),
+		Payload:  d.body,
+	}
+
+	line, err := json.Marshal(&deadLetter)
+	if err != nil {
+		log.Errorf("Unable to encode webhook dead letter: %v", err)
+		return
+	}
+
+	fileName := n.cfg.Cfg.DeadLetterFile
+	if fileName == "" {
+		log.Errorf("Undelivered webhook event: %s", line)
+		return
+	}
+
+	n.deadLetterMtx.Lock()
+	defer n.deadLetterMtx.Unlock()
+
+	err = appendLine(fileName, line)
+	if err != nil {
+		log.Errorf("Unable to write webhook dead letter %s to %v: %v",
+			line, fileName, err)
+	}
+}
+
+// appendLine appends the given line to the file with the given name, creating
+// the file if it doesn't exist yet.
+func appendLine(fileName string, line []byte) error {
+	file, err := os.OpenFile(
+		fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
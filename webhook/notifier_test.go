@@ -0,0 +1,267 @@
+package webhook
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightninglabs/taproot-assets/fn"
+	"github.com/lightninglabs/taproot-assets/taprpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// testEvent is an event with an outpoint that identifies it.
+type testEvent struct {
+	outpoint string
+}
+
+// Timestamp returns the timestamp of the event.
+func (e *testEvent) Timestamp() time.Time {
+	return time.Unix(1700000000, 0)
+}
+
+// otherEvent is an event that isn't delivered to the webhook.
+type otherEvent struct{}
+
+// Timestamp returns the timestamp of the event.
+func (e *otherEvent) Timestamp() time.Time {
+	return time.Unix(1700000000, 0)
+}
+
+// testPublisher is an event publisher that hands out a single subscription.
+type testPublisher struct {
+	receiver chan *fn.EventReceiver[fn.Event]
+	removed  chan struct{}
+}
+
+func (p *testPublisher) RegisterSubscriber(
+	receiver *fn.EventReceiver[fn.Event], _, _ bool) error {
+
+	p.receiver <- receiver
+	return nil
+}
+
+func (p *testPublisher) RemoveSubscriber(
+	receiver *fn.EventReceiver[fn.Event]) error {
+
+	receiver.Stop()
+	close(p.removed)
+	return nil
+}
+
+// marshalTestEvent maps a test event to a receive event. Other events aren't
+// delivered.
+func marshalTestEvent(event fn.Event) (proto.Message, error) {
+	e, ok := event.(*testEvent)
+	if !ok {
+		return nil, nil
+	}
+
+	return &taprpc.ReceiveAssetEvent{
+		Timestamp: e.Timestamp().UnixMicro(),
+		Outpoint:  e.outpoint,
+	}, nil
+}
+
+// request is a webhook request received by the test server.
+type request struct {
+	header  http.Header
+	payload Payload
+	event   taprpc.ReceiveAssetEvent
+}
+
+// TestConfigValidate tests that only a config with a valid webhook URL and a
+// secret is accepted.
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	validCfg := func() *Config {
+		return &Config{
+			URL:            "https://example.com/tapd",
+			Secret:         "secret",
+			RequestTimeout: time.Second,
+			MaxAttempts:    3,
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+		}
+	}
+	require.NoError(t, validCfg().Validate())
+
+	cfg := validCfg()
+	cfg.URL = "ftp://example.com/tapd"
+	require.ErrorContains(t, cfg.Validate(), "must use http(s)")
+
+	cfg = validCfg()
+	cfg.URL = "example.com"
+	require.ErrorContains(t, cfg.Validate(), "invalid webhook url")
+
+	cfg = validCfg()
+	cfg.Secret = ""
+	require.ErrorContains(t, cfg.Validate(), "secret must be set")
+
+	cfg = validCfg()
+	cfg.MaxAttempts = 0
+	require.ErrorContains(t, cfg.Validate(), "max attempts")
+
+	cfg = validCfg()
+	cfg.MaxBackoff = time.Millisecond
+	require.ErrorContains(t, cfg.Validate(), "max backoff")
+}
+
+// TestNotifier tests that events are delivered to the webhook with a valid
+// signature, that failed deliveries are retried and that events that can't be
+// delivered are written to the dead letter file.
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	const (
+		secret  = "webhook-secret"
+		timeout = 5 * time.Second
+	)
+
+	// The server fails the first request, accepts the retry of the first
+	// event and permanently rejects the second event.
+	var (
+		requests   []request
+		requestsMu sync.Mutex
+		received   = make(chan struct{}, 10)
+	)
+	statusCodes := []int{
+		http.StatusServiceUnavailable, http.StatusOK,
+		http.StatusBadRequest,
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			require.Equal(
+				t, Sign(
+					[]byte(secret),
+					r.Header.Get(TimestampHeader), body,
+				), r.Header.Get(SignatureHeader),
+			)
+
+			var req request
+			req.header = r.Header
+			require.NoError(t, json.Unmarshal(body, &req.payload))
+
+			unmarshalOpts := taprpc.RESTJsonUnmarshalOpts
+			require.NoError(t, unmarshalOpts.Unmarshal(
+				req.payload.Event, &req.event,
+			))
+
+			requestsMu.Lock()
+			statusCode := statusCodes[len(requests)]
+			requests = append(requests, req)
+			requestsMu.Unlock()
+
+			w.WriteHeader(statusCode)
+			received <- struct{}{}
+		},
+	))
+	t.Cleanup(server.Close)
+
+	deadLetterFile := filepath.Join(t.TempDir(), "dead_letters.log")
+	publisher := &testPublisher{
+		receiver: make(chan *fn.EventReceiver[fn.Event], 1),
+		removed:  make(chan struct{}),
+	}
+	notifier := NewNotifier(&NotifierConfig{
+		Cfg: &Config{
+			URL:            server.URL,
+			Secret:         secret,
+			RequestTimeout: timeout,
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			DeadLetterFile: deadLetterFile,
+		},
+		Sources: []EventSource{{
+			Type:      "receive",
+			Publisher: publisher,
+			Marshal:   marshalTestEvent,
+		}},
+	})
+	require.NoError(t, notifier.Start())
+
+	receiver := <-publisher.receiver
+
+	// Events the source doesn't marshal aren't delivered.
+	receiver.NewItemCreated.ChanIn() <- &otherEvent{}
+	receiver.NewItemCreated.ChanIn() <- &testEvent{outpoint: "first"}
+	receiver.NewItemCreated.ChanIn() <- &testEvent{outpoint: "second"}
+
+	for range statusCodes {
+		select {
+		case <-received:
+		case <-time.After(timeout):
+			t.Fatalf("webhook request not received")
+		}
+	}
+
+	// The dead letter is written after the last request was answered.
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(deadLetterFile)
+		return err == nil
+	}, timeout, 10*time.Millisecond)
+
+	require.NoError(t, notifier.Stop())
+	<-publisher.removed
+
+	requestsMu.Lock()
+	defer requestsMu.Unlock()
+
+	// The first event was retried with the same delivery ID.
+	require.Len(t, requests, 3)
+	require.Equal(t, "first", requests[0].event.Outpoint)
+	require.Equal(t, "first", requests[1].event.Outpoint)
+	require.Equal(t, "second", requests[2].event.Outpoint)
+	require.Equal(t, "receive", requests[0].payload.Type)
+	require.Equal(
+		t, requests[0].payload.DeliveryID,
+		requests[1].payload.DeliveryID,
+	)
+	require.Equal(
+		t, requests[0].payload.DeliveryID,
+		requests[0].header.Get(DeliveryIDHeader),
+	)
+	require.NotEqual(
+		t, requests[0].payload.DeliveryID,
+		requests[2].payload.DeliveryID,
+	)
+
+	// The second event was rejected with a client error, so it was
+	// written to the dead letters without a retry.
+	file, err := os.Open(deadLetterFile)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var deadLetters []DeadLetter
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var deadLetter DeadLetter
+		err := json.Unmarshal(scanner.Bytes(), &deadLetter)
+		require.NoError(t, err)
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, deadLetters, 1)
+	require.Equal(t, uint32(1), deadLetters[0].Attempts)
+	require.Equal(t, server.URL, deadLetters[0].URL)
+	require.Contains(t, deadLetters[0].Error, "400")
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(deadLetters[0].Payload, &payload))
+	require.Equal(t, requests[2].payload.DeliveryID, payload.DeliveryID)
+}